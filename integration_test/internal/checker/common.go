@@ -2,7 +2,7 @@ package checker
 
 import (
 	"context"
-	"github.com/openimsdk/tools/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
 )
 
 const (
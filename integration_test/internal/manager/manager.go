@@ -43,10 +43,7 @@ func (m *MetaManager) BuildCtx(ctx context.Context) context.Context {
 	if ctx == nil {
 		ctx = context.Background()
 	}
-	ctx = ccontext.WithInfo(ctx, &ccontext.GlobalConfig{
-		Token:    m.token,
-		IMConfig: &m.IMConfig,
-	})
+	ctx = ccontext.WithInfo(ctx, ccontext.NewGlobalConfig("", m.token, &m.IMConfig))
 	ctx = ccontext.WithOperationID(ctx, utils.OperationIDGenerator())
 	ctx = mcontext.SetOpUserID(ctx, "admin")
 	return ctx
@@ -8,8 +8,8 @@ import (
 	"github.com/openimsdk/openim-sdk-core/v3/integration_test/internal/pkg/reerrgroup"
 	"github.com/openimsdk/openim-sdk-core/v3/integration_test/internal/vars"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/api"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
 	"github.com/openimsdk/protocol/relation"
-	"github.com/openimsdk/tools/log"
 )
 
 type TestRelationManager struct {
@@ -3,7 +3,7 @@ package decorator
 import (
 	"context"
 	"fmt"
-	"github.com/openimsdk/tools/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
 	"github.com/openimsdk/tools/utils/stringutil"
 	"time"
 )
@@ -3,10 +3,10 @@ package initialization
 import (
 	"fmt"
 
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
 	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
 	"github.com/openimsdk/openim-sdk-core/v3/version"
 	"github.com/openimsdk/protocol/constant"
-	"github.com/openimsdk/tools/log"
 )
 
 const (
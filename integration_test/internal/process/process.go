@@ -4,8 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
 	"github.com/openimsdk/tools/errs"
-	"github.com/openimsdk/tools/log"
 	"reflect"
 	"runtime"
 	"runtime/debug"
@@ -12,7 +12,7 @@ import (
 	"github.com/openimsdk/openim-sdk-core/v3/integration_test/internal/process"
 	"github.com/openimsdk/openim-sdk-core/v3/integration_test/internal/statistics"
 	"github.com/openimsdk/openim-sdk-core/v3/integration_test/internal/vars"
-	"github.com/openimsdk/tools/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
 	"github.com/openimsdk/tools/utils/formatutil"
 	"os"
 	"runtime/debug"
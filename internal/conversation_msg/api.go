@@ -11,10 +11,12 @@ import (
 	"sync"
 	"time"
 
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/audit"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/ccontext"
 	"github.com/openimsdk/protocol/msg"
 
 	"github.com/openimsdk/tools/errs"
+	"github.com/openimsdk/tools/utils/datautil"
 
 	"github.com/openimsdk/openim-sdk-core/v3/internal/third/file"
 	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
@@ -22,14 +24,15 @@ import (
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/content_type"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/page"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdk_params_callback"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/server_api_params"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/telemetry"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
 	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
 
-	"github.com/openimsdk/tools/log"
-
 	pbConversation "github.com/openimsdk/protocol/conversation"
 	"github.com/openimsdk/protocol/sdkws"
 
@@ -37,11 +40,60 @@ import (
 )
 
 func (c *Conversation) GetAllConversationList(ctx context.Context) ([]*model_struct.LocalConversation, error) {
-	return c.db.GetAllConversationListDB(ctx)
+	conversations, err := c.db.GetAllConversationListDB(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.hydrateFirstPage(ctx, conversations)
+	return conversations, nil
 }
 
 func (c *Conversation) GetConversationListSplit(ctx context.Context, offset, count int) ([]*model_struct.LocalConversation, error) {
-	return c.db.GetConversationListSplitDB(ctx, offset, count)
+	conversations, err := c.db.GetConversationListSplitDB(ctx, offset, count)
+	if err != nil {
+		return nil, err
+	}
+	c.hydrateFirstPage(ctx, conversations)
+	return conversations, nil
+}
+
+// GetConversationListByCursor gets the page of the conversation list following the cursor, the first page for the empty cursor.
+func (c *Conversation) GetConversationListByCursor(ctx context.Context, cursor string, count int) (*sdk_params_callback.GetConversationListByCursorCallback, error) {
+	if count <= 0 {
+		return nil, sdkerrs.ErrArgs.WrapMsg("count must be greater than 0")
+	}
+	after, err := page.DecodeCursor(page.CursorConversations, cursor)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		pinned         bool
+		sortTime       int64
+		conversationID string
+	)
+	if after != nil {
+		pinned, sortTime, conversationID = after.Rank == 0, after.Time, after.ID
+	}
+	conversations, err := c.db.GetConversationListAfterDB(ctx, pinned, sortTime, conversationID, count+1)
+	if err != nil {
+		return nil, err
+	}
+	res := &sdk_params_callback.GetConversationListByCursorCallback{ConversationList: conversations, HasMore: len(conversations) > count}
+	if res.HasMore {
+		res.ConversationList = conversations[:count]
+	}
+	c.hydrateFirstPage(ctx, res.ConversationList)
+	if n := len(res.ConversationList); n > 0 {
+		last := res.ConversationList[n-1]
+		next := &page.Cursor{List: page.CursorConversations, ID: last.ConversationID, Time: max(last.LatestMsgSendTime, last.DraftTextTime), Rank: 1}
+		if last.IsPinned {
+			next.Rank = 0
+		}
+		res.NextCursor = next.Encode()
+	} else {
+		res.NextCursor = cursor
+	}
+	return res, nil
 }
 
 func (c *Conversation) HideConversation(ctx context.Context, conversationID string) error {
@@ -49,6 +101,7 @@ func (c *Conversation) HideConversation(ctx context.Context, conversationID stri
 	if err != nil {
 		return err
 	}
+	c.unread.remove(conversationID)
 	return nil
 }
 
@@ -102,11 +155,36 @@ func (c *Conversation) GetMultipleConversation(ctx context.Context, conversation
 
 }
 
+// GetConversations returns the conversations in the order of the IDs, the ones not found are reported failed.
+func (c *Conversation) GetConversations(ctx context.Context, conversationIDs []string) (*sdk_params_callback.GetConversationsCallback, error) {
+	conversations, err := c.db.GetMultipleConversationDB(ctx, conversationIDs)
+	if err != nil {
+		return nil, err
+	}
+	conversationMap := datautil.SliceToMap(conversations, func(e *model_struct.LocalConversation) string {
+		return e.ConversationID
+	})
+	res := &sdk_params_callback.GetConversationsCallback{
+		ConversationList: make([]*model_struct.LocalConversation, 0, len(conversations)),
+		FailedList:       []*sdk_params_callback.BatchFailure{},
+	}
+	for _, conversationID := range datautil.Distinct(conversationIDs) {
+		if conversation, ok := conversationMap[conversationID]; ok {
+			res.ConversationList = append(res.ConversationList, conversation)
+		} else {
+			res.FailedList = append(res.FailedList, sdk_params_callback.NewBatchFailure(conversationID,
+				errs.ErrRecordNotFound.WrapMsg("conversation not found", "conversationID", conversationID)))
+		}
+	}
+	return res, nil
+}
+
 func (c *Conversation) HideAllConversations(ctx context.Context) error {
 	err := c.db.ResetAllConversation(ctx)
 	if err != nil {
 		return err
 	}
+	c.unread.invalidate()
 	return nil
 }
 
@@ -143,7 +221,7 @@ func (c *Conversation) SetConversation(ctx context.Context, conversationID strin
 }
 
 func (c *Conversation) GetTotalUnreadMsgCount(ctx context.Context) (totalUnreadCount int32, err error) {
-	return c.db.GetTotalUnreadMsgCountDB(ctx)
+	return c.totalUnread(ctx)
 }
 
 func (c *Conversation) SetConversationListener(listener func() open_im_sdk_callback.OnConversationListener) {
@@ -182,6 +260,9 @@ func (c *Conversation) checkID(ctx context.Context, s *sdk_struct.MsgStruct,
 	if recvID == "" && groupID == "" {
 		return nil, sdkerrs.ErrArgs
 	}
+	if err := c.checkGuestSend(recvID, groupID); err != nil {
+		return nil, err
+	}
 	s.SendID = c.loginUserID
 	s.SenderPlatformID = c.platform
 	lc := &model_struct.LocalConversation{LatestMsgSendTime: s.CreateTime}
@@ -205,17 +286,11 @@ func (c *Conversation) checkID(ctx context.Context, s *sdk_struct.MsgStruct,
 		}
 		s.GroupID = groupID
 		lc.GroupID = groupID
-		gm, err := c.db.GetGroupMemberInfoByGroupIDUserID(ctx, groupID, c.loginUserID)
-		if err == nil && gm != nil {
-			if gm.Nickname != "" {
-				s.SenderNickname = gm.Nickname
-			}
-		} else { //Maybe the group member information hasn't been pulled locally yet.
-			gm, err := c.group.GetSpecifiedGroupMembersInfo(ctx, groupID, []string{c.loginUserID})
-			if err == nil && gm != nil {
-				if gm[0].Nickname != "" {
-					s.SenderNickname = gm[0].Nickname
-				}
+		// the member info is loaded from the server when it hasn't been pulled locally yet
+		gm, err := c.group.GetGroupMembersInfo(ctx, groupID, []string{c.loginUserID})
+		if err == nil && gm[c.loginUserID] != nil {
+			if gm[c.loginUserID].Nickname != "" {
+				s.SenderNickname = gm[c.loginUserID].Nickname
 			}
 		}
 		var attachedInfo sdk_struct.AttachedInfoElem
@@ -285,6 +360,7 @@ func (c *Conversation) sendMessage(ctx context.Context, s *sdk_struct.MsgStruct,
 	if err != nil {
 		return nil, err
 	}
+	applyRestrictions(ctx, s)
 	callback, ok := ctx.Value(ccontext.CtxCallback).(open_im_sdk_callback.SendMsgCallBack)
 	if !ok {
 		return nil, sdkerrs.ErrSdkInternal.WrapMsg("context not found SendMsgCallBack")
@@ -341,6 +417,32 @@ func (c *Conversation) sendMessage(ctx context.Context, s *sdk_struct.MsgStruct,
 			delFile = append(delFile, sourcePath)
 		}
 		log.ZDebug(ctx, "send picture", "path", sourcePath)
+		if c.shouldStripImageMetadata(ctx) {
+			stripped, err := c.stripPictureMetadata(ctx, s.ClientMsgID, sourcePath)
+			if err != nil {
+				c.updateMsgStatusAndTriggerConversation(ctx, s.ClientMsgID, "", s.CreateTime, constant.MsgStatusSendFailed, s, lc, isOnlineOnly)
+				return nil, err
+			}
+			if stripped != "" {
+				defer os.Remove(stripped)
+				sourcePath = stripped
+			}
+		}
+		if c.shouldCompressImage(ctx) {
+			// the original is sent when it can't be compressed
+			compressed, info, err := c.compressPicture(ctx, s.ClientMsgID, sourcePath)
+			if err != nil {
+				log.ZWarn(ctx, "compress picture failed", err, "path", sourcePath)
+			} else if compressed != "" {
+				defer os.Remove(compressed)
+				sourcePath = compressed
+				s.PictureElem.SourcePicture.UUID = replaceExt(s.PictureElem.SourcePicture.UUID, filepath.Ext(compressed))
+				s.PictureElem.SourcePicture.Width = info.Width
+				s.PictureElem.SourcePicture.Height = info.Height
+				s.PictureElem.SourcePicture.Type = info.Type
+				s.PictureElem.SourcePicture.Size = info.Size
+			}
+		}
 
 		res, err := c.file.UploadFile(ctx, &file.UploadFileReq{
 			ContentType: s.PictureElem.SourcePicture.Type,
@@ -348,13 +450,24 @@ func (c *Conversation) sendMessage(ctx context.Context, s *sdk_struct.MsgStruct,
 			Uuid:        s.PictureElem.SourcePicture.UUID,
 			Name:        c.fileName("picture", s.ClientMsgID) + filepathExt(s.PictureElem.SourcePicture.UUID, sourcePath),
 			Cause:       "msg-picture",
-		}, NewUploadFileCallback(ctx, callback.OnProgress, s, lc.ConversationID, c.db))
+			Storage:     msgStorage(ctx),
+		}, NewUploadFileCallback(ctx, callback, s, lc.ConversationID, c.db))
 		if err != nil {
 			c.updateMsgStatusAndTriggerConversation(ctx, s.ClientMsgID, "", s.CreateTime, constant.MsgStatusSendFailed, s, lc, isOnlineOnly)
 			return nil, err
 		}
 		s.PictureElem.SourcePicture.Url = res.URL
+		s.PictureElem.SourcePicture.Md5 = res.Md5
 		s.PictureElem.BigPicture = s.PictureElem.SourcePicture
+		if c.imageCompression.ThumbnailMaxSide > 0 {
+			snapshot, err := c.uploadThumbnail(ctx, s.ClientMsgID, sourcePath)
+			if err == nil {
+				s.PictureElem.SnapshotPicture = snapshot
+				s.Content = utils.StructToJsonString(s.PictureElem)
+				break
+			}
+			log.ZWarn(ctx, "picture thumbnail failed, the server resizes it", err, "path", sourcePath)
+		}
 		u, err := url.Parse(res.URL)
 		if err == nil {
 			snapshot := u.Query()
@@ -393,12 +506,14 @@ func (c *Conversation) sendMessage(ctx context.Context, s *sdk_struct.MsgStruct,
 			Uuid:        s.SoundElem.UUID,
 			Name:        c.fileName("voice", s.ClientMsgID) + filepathExt(s.SoundElem.UUID, sourcePath),
 			Cause:       "msg-voice",
-		}, NewUploadFileCallback(ctx, callback.OnProgress, s, lc.ConversationID, c.db))
+			Storage:     msgStorage(ctx),
+		}, NewUploadFileCallback(ctx, callback, s, lc.ConversationID, c.db))
 		if err != nil {
 			c.updateMsgStatusAndTriggerConversation(ctx, s.ClientMsgID, "", s.CreateTime, constant.MsgStatusSendFailed, s, lc, isOnlineOnly)
 			return nil, err
 		}
 		s.SoundElem.SourceURL = res.URL
+		s.SoundElem.Md5 = res.Md5
 		s.Content = utils.StructToJsonString(s.SoundElem)
 	case constant.Video:
 		if s.Status == constant.MsgStatusSendSuccess {
@@ -419,6 +534,18 @@ func (c *Conversation) sendMessage(ctx context.Context, s *sdk_struct.MsgStruct,
 			delFile = append(delFile, snapPath)
 		}
 		log.ZDebug(ctx, "file", "videoPath", videoPath, "snapPath", snapPath, "delFile", delFile)
+		transcoded, err := c.transcodeVideo(ctx, s, videoPath, callback.OnProgress)
+		if err != nil {
+			c.updateMsgStatusAndTriggerConversation(ctx, s.ClientMsgID, "", s.CreateTime, constant.MsgStatusSendFailed, s, lc, isOnlineOnly)
+			return nil, err
+		}
+		if transcoded != "" {
+			// the transcoder may have written it elsewhere than asked, that file is not the sdk's to remove
+			if transcoded == c.transcodeOutputPath(s.ClientMsgID, videoPath) {
+				defer os.Remove(transcoded)
+			}
+			videoPath = transcoded
+		}
 
 		var wg sync.WaitGroup
 		wg.Add(2)
@@ -431,23 +558,26 @@ func (c *Conversation) sendMessage(ctx context.Context, s *sdk_struct.MsgStruct,
 				Uuid:        s.VideoElem.SnapshotUUID,
 				Name:        c.fileName("videoSnapshot", s.ClientMsgID) + filepathExt(s.VideoElem.SnapshotUUID, snapPath),
 				Cause:       "msg-video-snapshot",
+				Storage:     msgStorage(ctx),
 			}, nil)
 			if err != nil {
 				log.ZWarn(ctx, "upload video snapshot failed", err)
 				return
 			}
 			s.VideoElem.SnapshotURL = snapRes.URL
+			s.VideoElem.SnapshotMd5 = snapRes.Md5
 		}()
 
 		go func() {
 			defer wg.Done()
 			res, err := c.file.UploadFile(ctx, &file.UploadFileReq{
-				ContentType: content_type.GetType(s.VideoElem.VideoType, filepath.Ext(s.VideoElem.VideoPath)),
+				ContentType: content_type.GetType(s.VideoElem.VideoType, filepath.Ext(videoPath)),
 				Filepath:    videoPath,
 				Uuid:        s.VideoElem.VideoUUID,
 				Name:        c.fileName("video", s.ClientMsgID) + filepathExt(s.VideoElem.VideoUUID, videoPath),
 				Cause:       "msg-video",
-			}, NewUploadFileCallback(ctx, callback.OnProgress, s, lc.ConversationID, c.db))
+				Storage:     msgStorage(ctx),
+			}, NewUploadFileCallback(ctx, callback, s, lc.ConversationID, c.db))
 			if err != nil {
 				c.updateMsgStatusAndTriggerConversation(ctx, s.ClientMsgID, "", s.CreateTime, constant.MsgStatusSendFailed, s, lc, isOnlineOnly)
 				putErrs = err
@@ -455,6 +585,7 @@ func (c *Conversation) sendMessage(ctx context.Context, s *sdk_struct.MsgStruct,
 			}
 			if res != nil {
 				s.VideoElem.VideoURL = res.URL
+				s.VideoElem.VideoMd5 = res.Md5
 			}
 		}()
 		wg.Wait()
@@ -491,12 +622,14 @@ func (c *Conversation) sendMessage(ctx context.Context, s *sdk_struct.MsgStruct,
 			Uuid:        s.FileElem.UUID,
 			Name:        c.fileName("file", s.ClientMsgID) + "/" + filepath.Base(name),
 			Cause:       "msg-file",
-		}, NewUploadFileCallback(ctx, callback.OnProgress, s, lc.ConversationID, c.db))
+			Storage:     msgStorage(ctx),
+		}, NewUploadFileCallback(ctx, callback, s, lc.ConversationID, c.db))
 		if err != nil {
 			c.updateMsgStatusAndTriggerConversation(ctx, s.ClientMsgID, "", s.CreateTime, constant.MsgStatusSendFailed, s, lc, isOnlineOnly)
 			return nil, err
 		}
 		s.FileElem.SourceURL = res.URL
+		s.FileElem.FileMd5 = res.Md5
 		s.Content = utils.StructToJsonString(s.FileElem)
 	case constant.Text:
 		s.Content = utils.StructToJsonString(s.TextElem)
@@ -521,6 +654,8 @@ func (c *Conversation) sendMessage(ctx context.Context, s *sdk_struct.MsgStruct,
 		s.Content = utils.StructToJsonString(s.FaceElem)
 	case constant.AdvancedText:
 		s.Content = utils.StructToJsonString(s.AdvancedTextElem)
+	case constant.SatisfactionRating:
+		s.Content = utils.StructToJsonString(s.SatisfactionRatingElem)
 	default:
 		return nil, sdkerrs.ErrMsgContentTypeNotSupport
 	}
@@ -545,6 +680,7 @@ func (c *Conversation) sendMessageNotOss(ctx context.Context, s *sdk_struct.MsgS
 	if err != nil {
 		return nil, err
 	}
+	applyRestrictions(ctx, s)
 	callback, ok := ctx.Value(ccontext.CtxCallback).(open_im_sdk_callback.SendMsgCallBack)
 	if !ok {
 		return nil, sdkerrs.ErrSdkInternal.WrapMsg("context not found SendMsgCallBack")
@@ -608,6 +744,8 @@ func (c *Conversation) sendMessageNotOss(ctx context.Context, s *sdk_struct.MsgS
 		s.Content = utils.StructToJsonString(s.FaceElem)
 	case constant.AdvancedText:
 		s.Content = utils.StructToJsonString(s.AdvancedTextElem)
+	case constant.SatisfactionRating:
+		s.Content = utils.StructToJsonString(s.SatisfactionRatingElem)
 	default:
 		return nil, sdkerrs.ErrMsgContentTypeNotSupport
 	}
@@ -624,6 +762,15 @@ func (c *Conversation) sendMessageNotOss(ctx context.Context, s *sdk_struct.MsgS
 }
 
 func (c *Conversation) SendMessage(ctx context.Context, s *sdk_struct.MsgStruct, recvID, groupID string, p *sdkws.OfflinePushInfo, isOnlineOnly bool) (*sdk_struct.MsgStruct, error) {
+	if err := c.filterSensitiveWords(ctx, s); err != nil {
+		return nil, err
+	}
+	if err := c.moderateSend(ctx, s); err != nil {
+		return nil, err
+	}
+	if err := checkClientConfig(ctx, s); err != nil {
+		return nil, err
+	}
 	task := &sendTask{
 		ctx: ctx,
 		msg: s,
@@ -639,6 +786,15 @@ func (c *Conversation) SendMessage(ctx context.Context, s *sdk_struct.MsgStruct,
 
 func (c *Conversation) SendMessageNotOss(ctx context.Context, s *sdk_struct.MsgStruct, recvID, groupID string,
 	p *sdkws.OfflinePushInfo, isOnlineOnly bool) (*sdk_struct.MsgStruct, error) {
+	if err := c.filterSensitiveWords(ctx, s); err != nil {
+		return nil, err
+	}
+	if err := c.moderateSend(ctx, s); err != nil {
+		return nil, err
+	}
+	if err := checkClientConfig(ctx, s); err != nil {
+		return nil, err
+	}
 	task := &sendTask{
 		ctx: ctx,
 		msg: s,
@@ -663,22 +819,35 @@ func (c *Conversation) sendMessageToServer(ctx context.Context, s *sdk_struct.Ms
 		utils.SetSwitchFromOptions(options, constant.IsUnreadCount, false)
 		utils.SetSwitchFromOptions(options, constant.IsOfflinePush, false)
 	}
+	wire, err := c.beforeSend(ctx, s)
+	if err == nil {
+		wire, err = c.encrypt(ctx, wire)
+	}
+	if err == nil {
+		wire, err = c.sign(ctx, wire)
+	}
+	if err != nil {
+		log.ZError(ctx, "message rejected before send", err, "message", s)
+		c.updateMsgStatusAndTriggerConversation(ctx, s.ClientMsgID, "", s.CreateTime,
+			constant.MsgStatusSendFailed, s, lc, isOnlineOnly)
+		return s, err
+	}
 	//Protocol conversion
 	var wsMsgData sdkws.MsgData
-	copier.Copy(&wsMsgData, s)
-	wsMsgData.AttachedInfo = utils.StructToJsonString(s.AttachedInfoElem)
-	wsMsgData.Content = []byte(s.Content)
+	copier.Copy(&wsMsgData, wire)
+	wsMsgData.AttachedInfo = utils.StructToJsonString(wire.AttachedInfoElem)
+	wsMsgData.Content = []byte(wire.Content)
 	wsMsgData.CreateTime = s.CreateTime
 	wsMsgData.SendTime = 0
 	wsMsgData.Options = options
-	if wsMsgData.ContentType == constant.AtText {
-		wsMsgData.AtUserIDList = s.AtTextElem.AtUserList
+	if wsMsgData.ContentType == constant.AtText && wire.AtTextElem != nil {
+		wsMsgData.AtUserIDList = wire.AtTextElem.AtUserList
 	}
 	wsMsgData.OfflinePushInfo = offlinePushInfo
 	s.Content = ""
 	var sendMsgResp msg.SendMsgResp
 	//err := c.LongConnMgr.SendReqWaitResp(ctx, &wsMsgData, constant.SendMsg, &sendMsgResp)
-	err := c.sendMsg(ctx, s, &wsMsgData, &sendMsgResp)
+	err = c.sendMsg(ctx, s, &wsMsgData, &sendMsgResp)
 	if err != nil {
 		//if send message network timeout need to double-check message has received by db.
 		if sdkerrs.ErrNetworkTimeOut.Is(err) && !isOnlineOnly {
@@ -723,7 +892,13 @@ func (c *Conversation) sendMsg(ctx context.Context, s *sdk_struct.MsgStruct, wsM
 	if sendMsgResp == nil {
 		sendMsgResp = &msg.SendMsgResp{}
 	}
-	if err := c.LongConnMgr.SendReqWaitResp(ctx, wsMsgData, constant.SendMsg, sendMsgResp); err != nil {
+	attrs := []telemetry.Attr{telemetry.Int("content_type", int(wsMsgData.ContentType)), telemetry.Int("session_type", int(wsMsgData.SessionType))}
+	ctx, span := telemetry.StartSpan(ctx, "msg.send", attrs...)
+	start := time.Now()
+	err := c.LongConnMgr.SendReqWaitResp(ctx, wsMsgData, constant.SendMsg, sendMsgResp)
+	telemetry.Record(telemetry.MsgSendLatency, telemetry.Since(start), append(attrs, telemetry.Bool("error", err != nil))...)
+	span.End(err)
+	if err != nil {
 		return err
 	}
 	if sendMsgResp.Modify == nil {
@@ -743,6 +918,41 @@ func (c *Conversation) sendMsg(ctx context.Context, s *sdk_struct.MsgStruct, wsM
 	return nil
 }
 
+// GetMessages returns the messages of several conversations in the order asked, the ones not found or whose
+// conversation could not be read are reported failed by their clientMsgID.
+func (c *Conversation) GetMessages(ctx context.Context, req []*sdk_params_callback.ConversationArgs) (*sdk_params_callback.GetMessagesCallback, error) {
+	res := &sdk_params_callback.GetMessagesCallback{
+		MessageList: []*sdk_struct.MsgStruct{},
+		FailedList:  []*sdk_params_callback.BatchFailure{},
+	}
+	for _, args := range req {
+		clientMsgIDs := datautil.Distinct(args.ClientMsgIDList)
+		if len(clientMsgIDs) == 0 {
+			continue
+		}
+		messages, err := c.db.GetMessagesByClientMsgIDs(ctx, args.ConversationID, clientMsgIDs)
+		if err != nil {
+			log.ZWarn(ctx, "GetMessagesByClientMsgIDs err", err, "conversationID", args.ConversationID)
+			for _, clientMsgID := range clientMsgIDs {
+				res.FailedList = append(res.FailedList, sdk_params_callback.NewBatchFailure(clientMsgID, err))
+			}
+			continue
+		}
+		messageMap := datautil.SliceToMap(messages, func(e *model_struct.LocalChatLog) string {
+			return e.ClientMsgID
+		})
+		for _, clientMsgID := range clientMsgIDs {
+			if message, ok := messageMap[clientMsgID]; ok {
+				res.MessageList = append(res.MessageList, LocalChatLogToMsgStruct(message))
+			} else {
+				res.FailedList = append(res.FailedList, sdk_params_callback.NewBatchFailure(clientMsgID,
+					errs.ErrRecordNotFound.WrapMsg("message not found", "conversationID", args.ConversationID, "clientMsgID", clientMsgID)))
+			}
+		}
+	}
+	return res, nil
+}
+
 func (c *Conversation) FindMessageList(ctx context.Context, req []*sdk_params_callback.ConversationArgs) (*sdk_params_callback.FindMessageListCallback, error) {
 	var r sdk_params_callback.FindMessageListCallback
 	type tempConversationAndMessageList struct {
@@ -798,6 +1008,43 @@ func (c *Conversation) GetAdvancedHistoryMessageList(ctx context.Context, req sd
 	return result, nil
 }
 
+// GetMessageListByCursor gets the page of the history messages older than the cursor, the latest messages for the empty cursor.
+// A cursor whose message has been deleted resumes from the send time and seq it was taken at.
+func (c *Conversation) GetMessageListByCursor(ctx context.Context, conversationID, cursor string, count int) (*sdk_params_callback.GetMessageListByCursorCallback, error) {
+	if count <= 0 {
+		return nil, sdkerrs.ErrArgs.WrapMsg("count must be greater than 0")
+	}
+	after, err := page.DecodeCursor(page.CursorMessages, cursor)
+	if err != nil {
+		return nil, err
+	}
+	var startMessage *model_struct.LocalChatLog
+	if after != nil {
+		messages, err := c.db.GetMessagesByClientMsgIDs(ctx, conversationID, []string{after.ID})
+		if err != nil {
+			return nil, err
+		}
+		if len(messages) > 0 {
+			startMessage = messages[0]
+		} else {
+			startMessage = &model_struct.LocalChatLog{ClientMsgID: after.ID, SendTime: after.Time, Seq: after.Seq}
+		}
+	}
+	req := sdk_params_callback.GetAdvancedHistoryMessageListParams{ConversationID: conversationID, Count: count}
+	result, err := c.getAdvancedHistoryMessageListFrom(ctx, req, false, startMessage)
+	if err != nil {
+		return nil, err
+	}
+	res := &sdk_params_callback.GetMessageListByCursorCallback{MessageList: result.MessageList, HasMore: !result.IsEnd, NextCursor: cursor}
+	if len(res.MessageList) == 0 {
+		res.MessageList = make([]*sdk_struct.MsgStruct, 0)
+	} else {
+		oldest := res.MessageList[0]
+		res.NextCursor = (&page.Cursor{List: page.CursorMessages, ID: oldest.ClientMsgID, Time: oldest.SendTime, Seq: oldest.Seq}).Encode()
+	}
+	return res, nil
+}
+
 func (c *Conversation) GetAdvancedHistoryMessageListReverse(ctx context.Context, req sdk_params_callback.GetAdvancedHistoryMessageListParams) (*sdk_params_callback.GetAdvancedHistoryMessageListCallback, error) {
 	result, err := c.getAdvancedHistoryMessageList(ctx, req, true)
 	if err != nil {
@@ -849,19 +1096,35 @@ func (c *Conversation) DeleteMessage(ctx context.Context, conversationID string,
 }
 
 func (c *Conversation) DeleteAllMsgFromLocalAndServer(ctx context.Context) error {
-	return c.deleteAllMsgFromLocalAndServer(ctx)
+	if err := c.deleteAllMsgFromLocalAndServer(ctx); err != nil {
+		return err
+	}
+	audit.Record(ctx, audit.ActionBulkDelete, "DeleteAllMsgFromLocalAndServer", "")
+	return nil
 }
 
 func (c *Conversation) DeleteAllMessageFromLocalStorage(ctx context.Context) error {
-	return c.deleteAllMsgFromLocal(ctx, true)
+	if err := c.deleteAllMsgFromLocal(ctx, true); err != nil {
+		return err
+	}
+	audit.Record(ctx, audit.ActionBulkDelete, "DeleteAllMessageFromLocalStorage", "")
+	return nil
 }
 
 func (c *Conversation) ClearConversationAndDeleteAllMsg(ctx context.Context, conversationID string) error {
-	return c.clearConversationFromLocalAndServer(ctx, conversationID, c.db.ClearConversation)
+	if err := c.clearConversationFromLocalAndServer(ctx, conversationID, c.db.ClearConversation); err != nil {
+		return err
+	}
+	audit.Record(ctx, audit.ActionBulkDelete, "ClearConversationAndDeleteAllMsg", conversationID)
+	return nil
 }
 
 func (c *Conversation) DeleteConversationAndDeleteAllMsg(ctx context.Context, conversationID string) error {
-	return c.clearConversationFromLocalAndServer(ctx, conversationID, c.db.ResetConversation)
+	if err := c.clearConversationFromLocalAndServer(ctx, conversationID, c.db.ResetConversation); err != nil {
+		return err
+	}
+	audit.Record(ctx, audit.ActionBulkDelete, "DeleteConversationAndDeleteAllMsg", conversationID)
+	return nil
 }
 
 func (c *Conversation) InsertSingleMessageToLocalStorage(ctx context.Context, s *sdk_struct.MsgStruct, recvID, sendID string) (*sdk_struct.MsgStruct, error) {
@@ -898,7 +1161,7 @@ func (c *Conversation) InsertSingleMessageToLocalStorage(ctx context.Context, s
 	s.SendID = sendID
 	s.RecvID = recvID
 	s.ClientMsgID = utils.GetMsgID(s.SendID)
-	s.SendTime = utils.GetCurrentTimestampByMill()
+	s.SendTime = utils.GetServerTimestampByMill()
 	s.SessionType = constant.SingleChatType
 	s.Status = constant.MsgStatusSendSuccess
 	localMessage := MsgStructToLocalChatLog(s)
@@ -937,7 +1200,7 @@ func (c *Conversation) InsertGroupMessageToLocalStorage(ctx context.Context, s *
 	s.SendID = sendID
 	s.GroupID = groupID
 	s.ClientMsgID = utils.GetMsgID(s.SendID)
-	s.SendTime = utils.GetCurrentTimestampByMill()
+	s.SendTime = utils.GetServerTimestampByMill()
 	s.SessionType = conversation.ConversationType
 	s.Status = constant.MsgStatusSendSuccess
 	localMessage := MsgStructToLocalChatLog(s)
@@ -983,7 +1246,7 @@ func (c *Conversation) SetMessageLocalEx(ctx context.Context, conversationID str
 }
 
 func (c *Conversation) initBasicInfo(ctx context.Context, message *sdk_struct.MsgStruct, msgFrom, contentType int32) error {
-	message.CreateTime = utils.GetCurrentTimestampByMill()
+	message.CreateTime = utils.GetServerTimestampByMill()
 	message.SendTime = message.CreateTime
 	message.IsRead = false
 	message.Status = constant.MsgStatusSending
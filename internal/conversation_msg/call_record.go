@@ -0,0 +1,96 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !no_signaling
+// +build !no_signaling
+
+package conversation_msg
+
+import (
+	"context"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/page"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdk_params_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+// callOver records the call over then inserts its message in the chat, a missed call message for a call
+// the login user did not answer.
+func (s *signaling) callOver(ctx context.Context, invitation *sdk_struct.SignalingInvitation, callElem *sdk_struct.CallElem) {
+	if callElem == nil {
+		return
+	}
+	record := &model_struct.LocalCallRecord{
+		RoomID:         invitation.RoomID,
+		InviterUserID:  invitation.InviterUserID,
+		InviteeUserIDs: invitation.InviteeUserIDList,
+		GroupID:        invitation.GroupID,
+		SessionType:    invitation.SessionType,
+		MediaType:      invitation.MediaType,
+		State:          callElem.State,
+		Missed:         callElem.Missed,
+		Duration:       callElem.Duration,
+		InitiateTime:   invitation.InitiateTime,
+		EndTime:        utils.GetServerTimestampByMill(),
+	}
+	if invitation.GroupID == "" {
+		record.PeerUserID = s.peer(invitation)
+	}
+	if err := s.conv.db.SetCallRecord(ctx, record); err != nil {
+		log.ZWarn(ctx, "SetCallRecord failed", err, "roomID", invitation.RoomID)
+	}
+	s.insertCallMessage(ctx, invitation, callElem)
+}
+
+// GetCallRecords gets the page of the call records of the filter following the cursor, the latest calls
+// first, the first page for the empty cursor.
+func (c *Conversation) GetCallRecords(ctx context.Context, filter *sdk_params_callback.CallRecordFilter, cursor string, count int) (*sdk_params_callback.GetCallRecordsCallback, error) {
+	if count <= 0 {
+		return nil, sdkerrs.ErrArgs.WrapMsg("count must be greater than 0")
+	}
+	if filter == nil {
+		filter = &sdk_params_callback.CallRecordFilter{}
+	}
+	after, err := page.DecodeCursor(page.CursorCallRecords, cursor)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		initiateTime int64
+		roomID       string
+	)
+	if after != nil {
+		initiateTime, roomID = after.Time, after.ID
+	}
+	records, err := c.db.GetCallRecordsAfter(ctx, filter.UserID, filter.GroupID, filter.MediaType, filter.States, filter.Missed,
+		initiateTime, roomID, count+1)
+	if err != nil {
+		return nil, err
+	}
+	res := &sdk_params_callback.GetCallRecordsCallback{CallRecords: records, HasMore: len(records) > count}
+	if res.HasMore {
+		res.CallRecords = records[:count]
+	}
+	if n := len(res.CallRecords); n > 0 {
+		last := res.CallRecords[n-1]
+		res.NextCursor = (&page.Cursor{List: page.CursorCallRecords, ID: last.RoomID, Time: last.InitiateTime}).Encode()
+	} else {
+		res.NextCursor = cursor
+	}
+	return res, nil
+}
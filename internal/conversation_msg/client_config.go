@@ -0,0 +1,54 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversation_msg
+
+import (
+	"context"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/cliconf"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+// checkClientConfig fails the message the client config of the server does not allow, of a content type it did
+// not enable or with media over its size limit. The last config known is used, sending does not wait for the
+// server.
+func checkClientConfig(ctx context.Context, s *sdk_struct.MsgStruct) error {
+	config := cliconf.LatestClientConfig(ctx)
+	if !config.ContentTypeEnabled(s.ContentType) {
+		return sdkerrs.ErrMsgContentTypeNotSupport.WrapMsg("the content type is not enabled by the server", "contentType", s.ContentType)
+	}
+	if limit := config.MaxMediaSize(s.ContentType); limit > 0 {
+		if size := estimateMediaSize(s); size > limit {
+			return sdkerrs.ErrMsgTooLarge.WrapMsg("the media is over the size limit", "size", size, "limit", limit)
+		}
+	}
+	return nil
+}
+
+// checkRevokeWindow fails the revoke of a message of the login user sent longer ago than the revoke window of the
+// client config, the messages of the others revoked by a group admin are not limited.
+func checkRevokeWindow(ctx context.Context, message *model_struct.LocalChatLog, loginUserID string) error {
+	window := cliconf.LatestClientConfig(ctx).RevokeWindow
+	if window <= 0 || message.SendID != loginUserID {
+		return nil
+	}
+	if time.Since(time.UnixMilli(message.SendTime)) > time.Duration(window)*time.Second {
+		return sdkerrs.ErrArgs.WrapMsg("the message was sent before the revoke window", "revokeWindow", window)
+	}
+	return nil
+}
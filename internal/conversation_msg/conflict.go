@@ -0,0 +1,83 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversation_msg
+
+import (
+	"context"
+
+	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+// CheckConflictPolicies checks the kinds and the sides of the conflict policies of the config.
+func CheckConflictPolicies(policies map[string]string) error {
+	for kind, resolution := range policies {
+		switch kind {
+		case constant.ConflictKindMessageRevoke, constant.ConflictKindMessageSendState:
+		default:
+			return sdkerrs.ErrArgs.WrapMsg("unknown conflict kind " + kind)
+		}
+		if resolution != constant.ConflictKeepServer && resolution != constant.ConflictKeepLocal {
+			return sdkerrs.ErrArgs.WrapMsg("unknown conflict resolution " + resolution)
+		}
+	}
+	return nil
+}
+
+func (c *Conversation) SetConflictPolicies(policies map[string]string) {
+	c.conflictPolicies = policies
+}
+
+func (c *Conversation) SetConflictResolver(conflictResolver func() open_im_sdk_callback.ConflictResolver) {
+	c.conflictResolver = conflictResolver
+}
+
+func (c *Conversation) SetSyncConflictListener(conflictListener func() open_im_sdk_callback.OnSyncConflictListener) {
+	c.conflictListener = conflictListener
+}
+
+// resolveConflict decides which of the local and the server state of a message is kept, by the conflict
+// resolver of the app, else by the policy of the kind, else the server one, and reports the resolution.
+// Returns true when the local state is kept.
+func (c *Conversation) resolveConflict(ctx context.Context, kind, conversationID, clientMsgID string, local, server any) bool {
+	conflict := &sdk_struct.SyncConflict{
+		Kind:           kind,
+		ConversationID: conversationID,
+		ClientMsgID:    clientMsgID,
+		Local:          utils.StructToJsonString(local),
+		Server:         utils.StructToJsonString(server),
+	}
+	if c.conflictResolver != nil {
+		if resolver := c.conflictResolver(); resolver != nil {
+			conflict.Resolution = resolver.ResolveConflict(utils.StructToJsonString(conflict))
+		}
+	}
+	if conflict.Resolution != constant.ConflictKeepLocal && conflict.Resolution != constant.ConflictKeepServer {
+		conflict.Resolution = constant.ConflictKeepServer
+		if resolution, ok := c.conflictPolicies[kind]; ok {
+			conflict.Resolution = resolution
+		}
+	}
+	log.ZInfo(ctx, "sync conflict resolved", "kind", kind, "conversationID", conversationID,
+		"clientMsgID", clientMsgID, "resolution", conflict.Resolution)
+	if c.conflictListener != nil {
+		c.conflictListener().OnSyncConflict(utils.StructToJsonString(conflict))
+	}
+	return conflict.Resolution == constant.ConflictKeepLocal
+}
@@ -16,7 +16,6 @@ package conversation_msg
 
 import (
 	"context"
-	"errors"
 	"sort"
 	"sync"
 	"time"
@@ -28,13 +27,12 @@ import (
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/cache"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
 	sdk "github.com/openimsdk/openim-sdk-core/v3/pkg/sdk_params_callback"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
 	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
 
-	"github.com/openimsdk/tools/log"
-
 	"github.com/openimsdk/protocol/sdkws"
 
 	pbConversation "github.com/openimsdk/protocol/conversation"
@@ -51,6 +49,19 @@ func (c *Conversation) setConversation(ctx context.Context, apiReq *pbConversati
 }
 
 func (c *Conversation) getAdvancedHistoryMessageList(ctx context.Context, req sdk.GetAdvancedHistoryMessageListParams, isReverse bool) (*sdk.GetAdvancedHistoryMessageListCallback, error) {
+	var startMessage *model_struct.LocalChatLog
+	if len(req.StartClientMsgID) > 0 {
+		m, err := c.db.GetMessage(ctx, req.ConversationID, req.StartClientMsgID)
+		if err != nil {
+			return nil, err
+		}
+		startMessage = m
+	}
+	return c.getAdvancedHistoryMessageListFrom(ctx, req, isReverse, startMessage)
+}
+
+// getAdvancedHistoryMessageListFrom pulls the messages next to startMessage, from the latest ones when it is nil.
+func (c *Conversation) getAdvancedHistoryMessageListFrom(ctx context.Context, req sdk.GetAdvancedHistoryMessageListParams, isReverse bool, startMessage *model_struct.LocalChatLog) (*sdk.GetAdvancedHistoryMessageListCallback, error) {
 	t := time.Now()
 	var messageListCallback sdk.GetAdvancedHistoryMessageListCallback
 	var conversationID string
@@ -59,15 +70,11 @@ func (c *Conversation) getAdvancedHistoryMessageList(ctx context.Context, req sd
 	var err error
 	var messageList sdk_struct.NewMsgList
 	conversationID = req.ConversationID
-	if len(req.StartClientMsgID) > 0 {
-		m, err := c.db.GetMessage(ctx, conversationID, req.StartClientMsgID)
-		if err != nil {
-			return nil, err
-		}
-		startTime = m.SendTime
-		startClientMsgID = req.StartClientMsgID
-		startSeq = m.Seq
-		err = c.handleEndSeq(ctx, req, isReverse, m)
+	if startMessage != nil {
+		startTime = startMessage.SendTime
+		startClientMsgID = startMessage.ClientMsgID
+		startSeq = startMessage.Seq
+		err = c.handleEndSeq(ctx, req, isReverse, startMessage)
 		if err != nil {
 			return nil, err
 		}
@@ -94,6 +101,9 @@ func (c *Conversation) getAdvancedHistoryMessageList(ctx context.Context, req sd
 	}
 	log.ZDebug(ctx, "sort", "sort cost time", time.Since(t))
 	messageListCallback.MessageList = messageList
+	if !isReverse {
+		c.prefetchHistory(ctx, conversationID, &messageListCallback, req.Count)
+	}
 
 	return &messageListCallback, nil
 }
@@ -227,6 +237,10 @@ func (c *Conversation) fetchMessagesWithGapCheck(ctx context.Context, conversati
 	c.validateAndFillEndBlockContinuity(ctx, conversationID, isReverse, viewType,
 		count, startTime, &list, messageListCallback)
 	log.ZDebug(ctx, "end continuity check over", "cost time", time.Since(t))
+	// the gap fills swallow the errors of their pulls, a canceled pull neither returns the gaps nor fetches more
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	// If the number of valid messages retrieved is less than the count,
 	// continue fetching recursively until the valid messages are sufficient or all messages have been fetched.
 	missingCount := shouldFetchMoreMessagesNum(list)
@@ -353,14 +367,14 @@ func (c *Conversation) searchLocalMessages(ctx context.Context, searchParam *sdk
 
 	// Validate that either keyword list or message type list is provided
 	if len(searchParam.KeywordList) == 0 && len(searchParam.MessageTypeList) == 0 {
-		return nil, errors.New("keywordlist and messageTypelist all null")
+		return nil, sdkerrs.ErrArgs.WrapMsg("keywordlist and messageTypelist all null")
 	}
 
 	// Search in a specific conversation if ConversationID is provided
 	if searchParam.ConversationID != "" {
 		// Validate pagination parameters
 		if searchParam.PageIndex < 1 || searchParam.Count < 1 {
-			return nil, errors.New("page or count is null")
+			return nil, sdkerrs.ErrArgs.WrapMsg("page or count is null")
 		}
 		offset := (searchParam.PageIndex - 1) * searchParam.Count
 		_, err := c.db.GetConversation(ctx, searchParam.ConversationID)
@@ -490,8 +504,14 @@ func (c *Conversation) searchMessageByContentTypeAndKeyword(ctx context.Context,
 		conversationID := cID
 
 		eg.Go(func() error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
 			sList, err := c.db.SearchMessageByContentTypeAndKeyword(ctx, contentType, conversationID, senderUserIDList, keywordList, keywordListMatchType, startTime, endTime)
 			if err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
 				log.ZWarn(ctx, "search conversation message", err, "conversationID", conversationID)
 				return nil
 			}
@@ -536,6 +556,9 @@ func (c *Conversation) filterMsg(temp *sdk_struct.MsgStruct, searchParam *sdk.Se
 	case constant.Card:
 		return !c.judgeMultipleSubString(searchParam.KeywordList, temp.CardElem.Nickname,
 			searchParam.KeywordListMatchType)
+	case constant.InteractiveCard:
+		return !c.judgeMultipleSubString(searchParam.KeywordList, temp.InteractiveCardElem.Title+"\n"+temp.InteractiveCardElem.Content,
+			searchParam.KeywordListMatchType)
 	case constant.Location:
 		return !c.judgeMultipleSubString(searchParam.KeywordList, temp.LocationElem.Description,
 			searchParam.KeywordListMatchType)
@@ -2,12 +2,14 @@ package conversation_msg
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
 	"sync"
+	"sync/atomic"
 
+	"github.com/openimsdk/openim-sdk-core/v3/internal/customerservice"
+	"github.com/openimsdk/openim-sdk-core/v3/internal/e2ee"
 	"github.com/openimsdk/openim-sdk-core/v3/internal/group"
 	"github.com/openimsdk/openim-sdk-core/v3/internal/interaction"
 	"github.com/openimsdk/openim-sdk-core/v3/internal/relation"
@@ -21,13 +23,13 @@ import (
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/converter"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/db_interface"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/page"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/syncer"
 	pbConversation "github.com/openimsdk/protocol/conversation"
 	"github.com/openimsdk/protocol/msg"
 	"github.com/openimsdk/protocol/sdkws"
 	"github.com/openimsdk/tools/errs"
-	"github.com/openimsdk/tools/log"
 	"github.com/openimsdk/tools/utils/datautil"
 
 	"sort"
@@ -52,6 +54,14 @@ type Conversation struct {
 	msgListener                 func() open_im_sdk_callback.OnAdvancedMsgListener
 	msgKvListener               func() open_im_sdk_callback.OnMessageKvInfoListener
 	businessListener            func() open_im_sdk_callback.OnCustomBusinessListener
+	syncProgressListener        func() open_im_sdk_callback.OnSyncProgressListener
+	conflictListener            func() open_im_sdk_callback.OnSyncConflictListener
+	conflictResolver            func() open_im_sdk_callback.ConflictResolver
+	videoTranscoder             func() open_im_sdk_callback.VideoTranscoder
+	translator                  func() open_im_sdk_callback.Translator
+	moderation                  moderation
+	messagePlugins              func() []open_im_sdk_callback.MessagePlugin
+	conflictPolicies            map[string]string
 	msgSyncerCh                 chan common.Cmd2Value
 	conversationEventQueue      chan common.Cmd2Value
 	loginUserID                 string
@@ -62,6 +72,10 @@ type Conversation struct {
 	user                        *user.User
 	file                        *file.File
 	cache                       *cache.Cache[string, *model_struct.LocalConversation]
+	latestMsgs                  *cache.Cache[string, *parsedLatestMsg]
+	hydrating                   atomic.Bool
+	prefetcher                  *historyPrefetcher
+	unread                      unreadCounts
 	maxSeqRecorder              MaxSeqRecorder
 	messagePullForwardEndSeqMap *cache.ConversationSeqContextCache
 	messagePullReverseEndSeqMap *cache.ConversationSeqContextCache
@@ -74,9 +88,42 @@ type Conversation struct {
 	startTime time.Time
 
 	typing *typing
+	// interactiveCardMutex orders the edits of the interactive cards
+	interactiveCardMutex sync.Mutex
+	signaling            *signaling
+	// moments handles the moment notifications, nil when the module is left out of the build
+	moments NotificationModule
+	// organization handles the organization notifications and is synced with the other data, nil when the module
+	// is left out of the build
+	organization SyncedModule
+	// customerService handles the customer-service notifications and is synced with the other data
+	customerService *customerservice.CustomerService
 
 	sender     *messageSender
 	senderOnce sync.Once
+
+	guest *guestSession
+
+	// initSyncProgress tracks the phases of the initial sync, nil once it is finished
+	initSyncProgress *syncProgress
+
+	// badgeReporter reports the total unread count as the app badge, nil when the app reports it itself
+	badgeReporter func(ctx context.Context, totalUnreadCount int32)
+
+	// msgWriteBatchSize is the messages of the sync written per transaction
+	msgWriteBatchSize int
+	// stripImageMetadata removes the metadata of the pictures sent by default
+	stripImageMetadata bool
+	imageCompression   ImageCompression
+	videoConstraints   sdk_struct.VideoTranscodeConstraints
+	msgWriteStats      msgWriteStats
+
+	// e2ee encrypts the single chats end to end, nil without it
+	e2ee *e2ee.E2EE
+	// signMessages signs the messages sent with the key of the device
+	signMessages bool
+	// sensitiveWords are the word lists checked in the messages
+	sensitiveWords sensitiveWordFilter
 }
 
 func (c *Conversation) ConversationEventQueue() chan common.Cmd2Value {
@@ -84,7 +131,7 @@ func (c *Conversation) ConversationEventQueue() chan common.Cmd2Value {
 }
 
 func (c *Conversation) SetDataBase(db db_interface.DataBase) {
-	c.db = db
+	c.db = &pluginDataBase{DataBase: db, c: c}
 }
 
 func (c *Conversation) SetLoginUserID(loginUserID string) {
@@ -107,10 +154,50 @@ func (c *Conversation) SetMsgKvListener(msgKvListener func() open_im_sdk_callbac
 	c.msgKvListener = msgKvListener
 }
 
+// SetBadgeReporter makes the changes of the total unread count be reported to fn as well.
+func (c *Conversation) SetBadgeReporter(fn func(ctx context.Context, totalUnreadCount int32)) {
+	c.badgeReporter = fn
+}
+
+// NotificationModule is an optional module handling the notifications of its range, e.g. the moments.
+type NotificationModule interface {
+	DoNotification(ctx context.Context, msg *sdkws.MsgData)
+}
+
+// SyncedModule is an optional module synced with the other data as well, e.g. the organization.
+type SyncedModule interface {
+	NotificationModule
+	SyncIfUsed(ctx context.Context) error
+}
+
+func (c *Conversation) SetMoments(m NotificationModule) {
+	c.moments = m
+}
+
+func (c *Conversation) SetOrganization(o SyncedModule) {
+	c.organization = o
+}
+
+// syncModules syncs the optional modules built.
+func (c *Conversation) syncModules(ctx context.Context) error {
+	if c.organization == nil {
+		return nil
+	}
+	return c.organization.SyncIfUsed(ctx)
+}
+
+func (c *Conversation) SetCustomerService(s *customerservice.CustomerService) {
+	c.customerService = s
+}
+
 func (c *Conversation) SetBusinessListener(businessListener func() open_im_sdk_callback.OnCustomBusinessListener) {
 	c.businessListener = businessListener
 }
 
+func (c *Conversation) SetSyncProgressListener(syncProgressListener func() open_im_sdk_callback.OnSyncProgressListener) {
+	c.syncProgressListener = syncProgressListener
+}
+
 func NewConversation(
 	longConnMgr *interaction.LongConnMgr,
 	msgSyncerCh chan common.Cmd2Value, conversationEventQueue chan common.Cmd2Value,
@@ -132,8 +219,13 @@ func NewConversation(
 		progress:                    0,
 	}
 	n.typing = newTyping(n)
+	n.signaling = newSignaling(n)
 	n.initSyncer()
-	n.cache = cache.NewCache[string, *model_struct.LocalConversation]()
+	conversations := cache.NewTTLStore[string, *model_struct.LocalConversation](0, cache.DefaultInfoCapacity)
+	cache.Register("conversation", conversations)
+	n.cache = cache.NewCacheWithStore(conversations)
+	n.latestMsgs = newLatestMsgCache()
+	n.prefetcher = newHistoryPrefetcher()
 	return n
 }
 
@@ -191,15 +283,17 @@ func (c *Conversation) initSyncer() {
 			if state == syncer.Update || state == syncer.Insert {
 				c.doUpdateConversation(common.Cmd2Value{Value: common.UpdateConNode{ConID: server.ConversationID, Action: constant.ConChange, Args: []string{server.ConversationID}}})
 			}
+			if state == syncer.Delete {
+				c.unread.remove(local.ConversationID)
+			}
 			return nil
 		}),
+		// the conversations synced in bulk are hydrated when they are first shown, see hydrateFirstPage
 		syncer.WithBatchInsert[*model_struct.LocalConversation, pbConversation.GetOwnerConversationResp, string](func(ctx context.Context, values []*model_struct.LocalConversation) error {
-			if err := c.batchAddFaceURLAndName(ctx, values...); err != nil {
-				return err
-			}
 			return c.db.BatchInsertConversationList(ctx, values)
 		}),
 		syncer.WithDeleteAll[*model_struct.LocalConversation, pbConversation.GetOwnerConversationResp, string](func(ctx context.Context, _ string) error {
+			c.unread.invalidate()
 			return c.db.DeleteAllConversation(ctx)
 		}),
 		syncer.WithBatchPageReq[*model_struct.LocalConversation, pbConversation.GetOwnerConversationResp, string](func(entityID string) page.PageReq {
@@ -238,6 +332,7 @@ func (c *Conversation) doMsgNew(c2v common.Cmd2Value) {
 	conversationChangedSet := make(map[string]*model_struct.LocalConversation)
 	newConversationSet := make(map[string]*model_struct.LocalConversation)
 	conversationSet := make(map[string]*model_struct.LocalConversation)
+	latestMsgs := make(map[string]*sdk_struct.MsgStruct)
 	phConversationChangedSet := make(map[string]*model_struct.LocalConversation)
 	phNewConversationSet := make(map[string]*model_struct.LocalConversation)
 	conversationIDs := make([]string, 0, len(allMsg))
@@ -270,6 +365,14 @@ func (c *Conversation) doMsgNew(c2v common.Cmd2Value) {
 
 		for _, v := range msgs.Msgs {
 			log.ZDebug(ctx, "parse message ", "conversationID", conversationID, "msg", v)
+			if v.ContentType == constant.Signaling {
+				c.signaling.onNewMsg(ctx, v)
+				continue
+			}
+			if v.ContentType == constant.SignalingData {
+				c.signaling.onData(ctx, v)
+				continue
+			}
 			isHistory = utils.GetSwitchFromOptions(v.Options, constant.IsHistory)
 
 			isUnreadCount = utils.GetSwitchFromOptions(v.Options, constant.IsUnreadCount)
@@ -280,7 +383,7 @@ func (c *Conversation) doMsgNew(c2v common.Cmd2Value) {
 
 			isSenderConversationUpdate = utils.GetSwitchFromOptions(v.Options, constant.IsSenderConversationUpdate)
 
-			msg := converter.MsgDataToMsgStruct(v)
+			msg := c.onReceive(ctx, c.unseal(ctx, conversationID, converter.MsgDataToMsgStruct(v)))
 
 			//When the message has been marked and deleted by the cloud, it is directly inserted locally without any conversation and message update.
 			if msg.Status == constant.MsgStatusHasDeleted {
@@ -299,6 +402,9 @@ func (c *Conversation) doMsgNew(c2v common.Cmd2Value) {
 				log.ZError(ctx, "Parsing data error:", err, "type: ", msg.ContentType, "msg", msg)
 				continue
 			}
+			c.filterReceivedSensitiveWords(msg)
+			c.localizeTips(msg)
+			c.moderateReceived(ctx, msg)
 
 			if !isNotPrivate {
 				msg.AttachedInfoElem.IsPrivateChat = true
@@ -319,6 +425,11 @@ func (c *Conversation) doMsgNew(c2v common.Cmd2Value) {
 				if ok {
 					log.ZInfo(ctx, "have message", "msg", msg)
 					if existingMsg.Seq == 0 {
+						// failed here but sent on the server, e.g. the response was lost
+						if existingMsg.Status == constant.MsgStatusSendFailed &&
+							c.resolveConflict(ctx, constant.ConflictKindMessageSendState, conversationID, msg.ClientMsgID, existingMsg, msg) {
+							continue
+						}
 						if !isConversationUpdate {
 							msg.Status = constant.MsgStatusFiltered
 						}
@@ -333,7 +444,6 @@ func (c *Conversation) doMsgNew(c2v common.Cmd2Value) {
 					log.ZInfo(ctx, "sync message", "msg", msg)
 					lc := model_struct.LocalConversation{
 						ConversationType:  v.SessionType,
-						LatestMsg:         utils.StructToJsonString(msg),
 						LatestMsgSendTime: msg.SendTime,
 						ConversationID:    conversationID,
 					}
@@ -347,6 +457,7 @@ func (c *Conversation) doMsgNew(c2v common.Cmd2Value) {
 						if isSenderConversationUpdate {
 							log.ZDebug(ctx, "updateConversation msg", "message", v, "conversation", lc)
 							c.updateConversation(&lc, conversationSet)
+							updateLatestMsg(latestMsgs, conversationID, msg)
 						}
 						newMessages = append(newMessages, msg)
 					}
@@ -359,7 +470,6 @@ func (c *Conversation) doMsgNew(c2v common.Cmd2Value) {
 				if !ok {
 					lc := model_struct.LocalConversation{
 						ConversationType:  v.SessionType,
-						LatestMsg:         utils.StructToJsonString(msg),
 						LatestMsgSendTime: msg.SendTime,
 						ConversationID:    conversationID,
 					}
@@ -383,6 +493,7 @@ func (c *Conversation) doMsgNew(c2v common.Cmd2Value) {
 					}
 					if isConversationUpdate {
 						c.updateConversation(&lc, conversationSet)
+						updateLatestMsg(latestMsgs, conversationID, msg)
 						newMessages = append(newMessages, msg)
 					}
 					if isHistory {
@@ -404,6 +515,8 @@ func (c *Conversation) doMsgNew(c2v common.Cmd2Value) {
 		}
 	}
 
+	setLatestMsgs(conversationSet, latestMsgs)
+
 	//todo The lock granularity needs to be optimized to the conversation level.
 	c.conversationSyncMutex.Lock()
 	defer c.conversationSyncMutex.Unlock()
@@ -426,14 +539,11 @@ func (c *Conversation) doMsgNew(c2v common.Cmd2Value) {
 	c.diff(ctx, m, conversationSet, conversationChangedSet, newConversationSet)
 	log.ZInfo(ctx, "trigger map is :", "newConversations", newConversationSet, "changedConversations", conversationChangedSet)
 
-	//seq sync message update
+	//seq sync message update, not in the transactions of the storage as it calls the listener
 	if err := c.batchUpdateMessageList(ctx, updateMsg); err != nil {
 		log.ZError(ctx, "sync seq normal message err  :", err)
 	}
 
-	//Normal message storage
-	_ = c.batchInsertMessageList(ctx, insertMsg)
-
 	for _, v := range hList {
 		if nc, ok := newConversationSet[v.ConversationID]; ok {
 			phConversationChangedSet[v.ConversationID] = nc
@@ -461,17 +571,20 @@ func (c *Conversation) doMsgNew(c2v common.Cmd2Value) {
 		}
 	}
 
-	if err := c.db.BatchUpdateConversationList(ctx, append(datautil.Values(conversationChangedSet), datautil.Values(phConversationChangedSet)...)); err != nil {
-		log.ZError(ctx, "insert changed conversation err :", err)
-	}
-	//New conversation storage
+	c.storeSyncedMessages(ctx, insertMsg, func(ctx context.Context) {
+		if err := c.db.BatchUpdateConversationList(ctx, append(datautil.Values(conversationChangedSet), datautil.Values(phConversationChangedSet)...)); err != nil {
+			log.ZError(ctx, "insert changed conversation err :", err)
+		}
+		//New conversation storage
 
-	if err := c.db.BatchInsertConversationList(ctx, datautil.Values(phNewConversationSet)); err != nil {
-		log.ZError(ctx, "insert new conversation err:", err)
-	}
+		if err := c.db.BatchInsertConversationList(ctx, datautil.Values(phNewConversationSet)); err != nil {
+			log.ZError(ctx, "insert new conversation err:", err)
+		}
+	})
 	log.ZDebug(ctx, "before trigger msg", "cost time", time.Since(b).Seconds(), "len", len(allMsg))
 
 	c.newMessage(ctx, newMessages, conversationChangedSet, newConversationSet, onlineMap)
+	c.unread.set(append(datautil.Values(conversationChangedSet), datautil.Values(newConversationSet)...)...)
 
 	if len(newConversationSet) > 0 {
 		c.ConversationListener().OnNewConversation(utils.StructToJsonString(datautil.Values(newConversationSet)))
@@ -501,16 +614,23 @@ func (c *Conversation) doMsgNew(c2v common.Cmd2Value) {
 
 func (c *Conversation) OnTotalUnreadMessageCountChanged(ctx context.Context) error {
 	log.ZInfo(ctx, "OnTotalUnreadMessageCountChanged", "caller", common.GetCaller(2))
-	totalUnreadCount, err := c.db.GetTotalUnreadMsgCountDB(ctx)
+	totalUnreadCount, err := c.totalUnread(ctx)
 	if err != nil {
-		log.ZWarn(ctx, "TotalUnreadMessageChanged GetTotalUnreadMsgCountDB err", err)
+		log.ZWarn(ctx, "TotalUnreadMessageChanged totalUnread err", err)
 	} else {
 		log.ZDebug(ctx, "TotalUnreadMessageChanged", "totalUnreadCount", totalUnreadCount)
-		c.ConversationListener().OnTotalUnreadMessageCountChanged(totalUnreadCount)
+		c.totalUnreadMessageCountChanged(ctx, totalUnreadCount)
 	}
 	return nil
 }
 
+func (c *Conversation) totalUnreadMessageCountChanged(ctx context.Context, totalUnreadCount int32) {
+	c.ConversationListener().OnTotalUnreadMessageCountChanged(totalUnreadCount)
+	if c.badgeReporter != nil {
+		c.badgeReporter(ctx, totalUnreadCount)
+	}
+}
+
 func (c *Conversation) doMsgSyncByReinstalled(c2v common.Cmd2Value) {
 	allMsg := c2v.Value.(sdk_struct.CmdMsgSyncInReinstall).Msgs
 	ctx := c2v.Ctx
@@ -537,7 +657,7 @@ func (c *Conversation) doMsgSyncByReinstalled(c2v common.Cmd2Value) {
 		for _, v := range msgs.Msgs {
 
 			log.ZDebug(ctx, "parse message ", "conversationID", conversationID, "msg", v)
-			msg := converter.MsgDataToMsgStruct(v)
+			msg := c.onReceive(ctx, c.unseal(ctx, conversationID, converter.MsgDataToMsgStruct(v)))
 
 			//When the message has been marked and deleted by the cloud, it is directly inserted locally without any conversation and message update.
 			if msg.Status == constant.MsgStatusHasDeleted {
@@ -554,6 +674,9 @@ func (c *Conversation) doMsgSyncByReinstalled(c2v common.Cmd2Value) {
 				log.ZError(ctx, "Parsing data error:", err, "type: ", msg.ContentType, "msg", msg)
 				continue
 			}
+			c.filterReceivedSensitiveWords(msg)
+			c.localizeTips(msg)
+			c.moderateReceived(ctx, msg)
 
 			if conversationID == "" {
 				log.ZError(ctx, "conversationID is empty", errors.New("conversationID is empty"), "msg", msg)
@@ -588,17 +711,19 @@ func (c *Conversation) doMsgSyncByReinstalled(c2v common.Cmd2Value) {
 		insertMsg[conversationID] = append(insertMessage, c.faceURLAndNicknameHandle(ctx, selfInsertMessage, othersInsertMessage, conversationID)...)
 	}
 
-	// message storage
-	_ = c.batchInsertMessageList(ctx, insertMsg)
-
-	// conversation storage
-	if err := c.db.BatchUpdateConversationList(ctx, conversationList); err != nil {
-		log.ZError(ctx, "insert new conversation err:", err)
-	}
+	// message and conversation storage
+	c.storeSyncedMessages(ctx, insertMsg, func(ctx context.Context) {
+		if err := c.db.BatchUpdateConversationList(ctx, conversationList); err != nil {
+			log.ZError(ctx, "insert new conversation err:", err)
+		}
+	})
+	// the conversations shown now are counted once loaded again
+	c.unread.invalidate()
 	log.ZDebug(ctx, "before trigger msg", "cost time", time.Since(b).Seconds(), "len", len(allMsg))
 
 	// log.ZDebug(ctx, "progress is", "msgLen", msgLen, "msgOffset", c.msgOffset, "total", total, "now progress is", (c.msgOffset*(100-InitSyncProgress))/total + InitSyncProgress)
 	c.ConversationListener().OnSyncServerProgress((c.msgOffset*(100-InitSyncProgress))/total + InitSyncProgress)
+	c.initSyncProgress.Update(constant.SyncPhaseMessages, c.msgOffset, total)
 	//Exception message storage
 	for _, v := range exceptionMsg {
 		log.ZWarn(ctx, "exceptionMsg show: ", nil, "msg", *v)
@@ -674,8 +799,8 @@ func (c *Conversation) batchUpdateMessageList(ctx context.Context, updateMsg map
 			log.ZError(ctx, "GetConversation err", err, "conversationID", conversationID)
 			continue
 		}
-		latestMsg := &sdk_struct.MsgStruct{}
-		if err := json.Unmarshal([]byte(conversation.LatestMsg), latestMsg); err != nil {
+		latestMsg, err := c.parseLatestMsg(conversation)
+		if err != nil {
 			log.ZError(ctx, "Unmarshal err", err, "conversationID",
 				conversationID, "latestMsg", conversation.LatestMsg, "messages", messages)
 			continue
@@ -19,11 +19,11 @@ import (
 
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
 	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
 	"github.com/openimsdk/protocol/sdkws"
 	"github.com/openimsdk/tools/errs"
-	"github.com/openimsdk/tools/log"
 
 	pbConversation "github.com/openimsdk/protocol/conversation"
 )
@@ -196,6 +196,18 @@ func PopulateMsgStructByContentType(msg *sdk_struct.MsgStruct) (err error) {
 		elem := sdk_struct.MarkdownTextElem{}
 		err = utils.JsonStringToStruct(msg.Content, &elem)
 		msg.MarkdownTextElem = &elem
+	case constant.Call:
+		elem := sdk_struct.CallElem{}
+		err = utils.JsonStringToStruct(msg.Content, &elem)
+		msg.CallElem = &elem
+	case constant.InteractiveCard:
+		elem := sdk_struct.InteractiveCardElem{}
+		err = utils.JsonStringToStruct(msg.Content, &elem)
+		msg.InteractiveCardElem = &elem
+	case constant.SatisfactionRating:
+		elem := sdk_struct.SatisfactionRatingElem{}
+		err = utils.JsonStringToStruct(msg.Content, &elem)
+		msg.SatisfactionRatingElem = &elem
 	default:
 		elem := sdk_struct.NotificationElem{}
 		err = utils.JsonStringToStruct(msg.Content, &elem)
@@ -278,6 +290,18 @@ func msgHandleByContentType(msg *sdk_struct.MsgStruct) (err error) {
 		t := sdk_struct.MarkdownTextElem{}
 		err = utils.JsonStringToStruct(msg.Content, &t)
 		msg.MarkdownTextElem = &t
+	case constant.Call:
+		t := sdk_struct.CallElem{}
+		err = utils.JsonStringToStruct(msg.Content, &t)
+		msg.CallElem = &t
+	case constant.InteractiveCard:
+		t := sdk_struct.InteractiveCardElem{}
+		err = utils.JsonStringToStruct(msg.Content, &t)
+		msg.InteractiveCardElem = &t
+	case constant.SatisfactionRating:
+		t := sdk_struct.SatisfactionRatingElem{}
+		err = utils.JsonStringToStruct(msg.Content, &t)
+		msg.SatisfactionRatingElem = &t
 	default:
 		t := sdk_struct.NotificationElem{}
 		err = utils.JsonStringToStruct(msg.Content, &t)
@@ -338,6 +362,12 @@ func MsgStructToLocalChatLog(message *sdk_struct.MsgStruct) *model_struct.LocalC
 		localMessage.Content = utils.StructToJsonString(message.AdvancedTextElem)
 	case constant.MarkdownText:
 		localMessage.Content = utils.StructToJsonString(message.MarkdownTextElem)
+	case constant.Call:
+		localMessage.Content = utils.StructToJsonString(message.CallElem)
+	case constant.InteractiveCard:
+		localMessage.Content = utils.StructToJsonString(message.InteractiveCardElem)
+	case constant.SatisfactionRating:
+		localMessage.Content = utils.StructToJsonString(message.SatisfactionRatingElem)
 	default:
 		localMessage.Content = utils.StructToJsonString(message.NotificationElem)
 	}
@@ -5,7 +5,7 @@ import (
 	"errors"
 
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
-	"github.com/openimsdk/tools/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
 
 	"os"
 	"path/filepath"
@@ -40,7 +40,7 @@ func (c *Conversation) CreateAdvancedTextMessage(ctx context.Context, text strin
 
 func (c *Conversation) CreateTextAtMessage(ctx context.Context, text string, userIDList []string, usersInfo []*sdk_struct.AtInfo, qs *sdk_struct.MsgStruct) (*sdk_struct.MsgStruct, error) {
 	if text == "" {
-		return nil, errors.New("text can not be empty")
+		return nil, sdkerrs.ErrArgs.WrapMsg("text can not be empty")
 	}
 	if len(userIDList) > 10 {
 		return nil, sdkerrs.ErrArgs
@@ -142,6 +142,21 @@ func (c *Conversation) CreateCardMessage(ctx context.Context, card *sdk_struct.C
 	return &s, nil
 }
 
+// CreateSatisfactionRatingInviteMessage creates the message of an agent inviting the customer to rate the
+// customer-service session, sent in the conversation of the session.
+func (c *Conversation) CreateSatisfactionRatingInviteMessage(ctx context.Context, sessionID string) (*sdk_struct.MsgStruct, error) {
+	if sessionID == "" {
+		return nil, sdkerrs.ErrArgs.WrapMsg("sessionID can't be empty")
+	}
+	s := sdk_struct.MsgStruct{}
+	err := c.initBasicInfo(ctx, &s, constant.UserMsgType, constant.SatisfactionRating)
+	if err != nil {
+		return nil, err
+	}
+	s.SatisfactionRatingElem = &sdk_struct.SatisfactionRatingElem{SessionID: sessionID}
+	return &s, nil
+}
+
 func (c *Conversation) CreateVideoMessageFromFullPath(ctx context.Context, videoFullPath string, videoType string,
 	duration int64, snapshotFullPath string) (*sdk_struct.MsgStruct, error) {
 	dstFile := utils.FileTmpPath(videoFullPath, c.DataDir) //a->b
@@ -325,6 +340,7 @@ func (c *Conversation) CreateSoundMessageByURL(ctx context.Context, soundElem *s
 		DataSize:  soundElem.DataSize,
 		Duration:  soundElem.Duration,
 		SoundType: soundElem.SoundType,
+		Md5:       soundElem.Md5,
 	}
 	return &s, nil
 }
@@ -370,6 +386,8 @@ func (c *Conversation) CreateVideoMessageByURL(ctx context.Context, videoElem sd
 		SnapshotWidth:  videoElem.SnapshotWidth,
 		SnapshotHeight: videoElem.SnapshotHeight,
 		SnapshotType:   videoElem.SnapshotType,
+		VideoMd5:       videoElem.VideoMd5,
+		SnapshotMd5:    videoElem.SnapshotMd5,
 	}
 	return &s, nil
 }
@@ -419,6 +437,7 @@ func (c *Conversation) CreateFileMessageByURL(ctx context.Context, fileElem sdk_
 		FileName:  fileElem.FileName,
 		FileSize:  fileElem.FileSize,
 		FileType:  fileElem.FileType,
+		FileMd5:   fileElem.FileMd5,
 	}
 	return &s, nil
 }
@@ -440,6 +459,9 @@ func (c *Conversation) CreateFileMessage(ctx context.Context, filePath string, f
 	return &s, nil
 }
 func (c *Conversation) CreateMergerMessage(ctx context.Context, messages []*sdk_struct.MsgStruct, title string, summaries []string) (*sdk_struct.MsgStruct, error) {
+	if err := c.checkForwardable(ctx, messages...); err != nil {
+		return nil, err
+	}
 	s := sdk_struct.MsgStruct{MergeElem: &sdk_struct.MergeElem{}}
 	err := c.initBasicInfo(ctx, &s, constant.UserMsgType, constant.Merger)
 	if err != nil {
@@ -467,7 +489,10 @@ func (c *Conversation) CreateForwardMessage(ctx context.Context, s *sdk_struct.M
 	if s.Status != constant.MsgStatusSendSuccess {
 		log.ZError(ctx, "only send success message can be Forward",
 			errors.New("only send success message can be Forward"))
-		return nil, errors.New("only send success message can be Forward")
+		return nil, sdkerrs.ErrArgs.WrapMsg("only send success message can be Forward")
+	}
+	if err := c.checkForwardable(ctx, s); err != nil {
+		return nil, err
 	}
 	err := c.initBasicInfo(ctx, s, constant.UserMsgType, s.ContentType)
 	if err != nil {
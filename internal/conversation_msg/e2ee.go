@@ -0,0 +1,106 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversation_msg
+
+import (
+	"context"
+	"sort"
+
+	"github.com/openimsdk/openim-sdk-core/v3/internal/e2ee"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+func (c *Conversation) SetE2EE(e *e2ee.E2EE) {
+	c.e2ee = e
+}
+
+// encrypt returns the wire message with its content encrypted for the peer of the single chat, the message
+// itself when it is sent in plain. It never falls back to plain on an error, the send fails.
+func (c *Conversation) encrypt(ctx context.Context, wire *sdk_struct.MsgStruct) (*sdk_struct.MsgStruct, error) {
+	if c.e2ee == nil || wire.SessionType != constant.SingleChatType || wire.RecvID == c.loginUserID ||
+		wire.ContentType == constant.Typing || wire.ContentType >= constant.NotificationBegin {
+		return wire, nil
+	}
+	envelope, ok, err := c.e2ee.Encrypt(ctx, wire.RecvID, wire.ContentType, wire.Content)
+	if err != nil || !ok {
+		return wire, err
+	}
+	encrypted := *wire
+	encrypted.ContentType = constant.E2EEMessage
+	encrypted.Content = envelope
+	return &encrypted, nil
+}
+
+// decrypt restores the content of an encrypted message, the ones sent by this device from the local message.
+// A message that can not be decrypted is kept as it is, an E2EEMessage.
+func (c *Conversation) decrypt(ctx context.Context, conversationID string, msg *sdk_struct.MsgStruct) *sdk_struct.MsgStruct {
+	if msg.ContentType != constant.E2EEMessage || c.e2ee == nil {
+		return msg
+	}
+	if msg.SendID == c.loginUserID {
+		if local, err := c.db.GetMessage(ctx, conversationID, msg.ClientMsgID); err == nil {
+			msg.ContentType, msg.Content = local.ContentType, local.Content
+		}
+		return msg
+	}
+	contentType, content, err := c.e2ee.Decrypt(ctx, msg.SendID, msg.ClientMsgID, msg.Content)
+	if err != nil {
+		log.ZWarn(ctx, "decrypt e2ee message failed", err, "conversationID", conversationID, "clientMsgID", msg.ClientMsgID)
+		return msg
+	}
+	msg.ContentType, msg.Content = contentType, content
+	return msg
+}
+
+// DecryptStoredMessages decrypts again the messages stored as they could not be decrypted, after the keys were
+// restored, and returns how many were. The ones sent by another device of the login user stay encrypted.
+func (c *Conversation) DecryptStoredMessages(ctx context.Context) (int, error) {
+	conversations, err := c.db.GetAllConversations(ctx)
+	if err != nil {
+		return 0, err
+	}
+	var decrypted int
+	for _, conversation := range conversations {
+		if conversation.ConversationType != constant.SingleChatType {
+			continue
+		}
+		messages, err := c.db.SearchAllMessageByContentType(ctx, conversation.ConversationID, constant.E2EEMessage)
+		if err != nil {
+			return decrypted, err
+		}
+		// in the order they were sent, the sessions move forward with them
+		sort.Slice(messages, func(i, j int) bool { return messages[i].Seq < messages[j].Seq })
+		for _, message := range messages {
+			if message.SendID == c.loginUserID {
+				continue
+			}
+			contentType, content, err := c.e2ee.Decrypt(ctx, message.SendID, message.ClientMsgID, message.Content)
+			if err != nil {
+				log.ZWarn(ctx, "decrypt stored e2ee message failed", err, "conversationID", conversation.ConversationID,
+					"clientMsgID", message.ClientMsgID)
+				continue
+			}
+			message.ContentType, message.Content = contentType, content
+			if err := c.db.UpdateMessage(ctx, conversation.ConversationID, message); err != nil {
+				return decrypted, err
+			}
+			decrypted++
+		}
+	}
+	log.ZInfo(ctx, "stored e2ee messages decrypted", "count", decrypted)
+	return decrypted, nil
+}
@@ -0,0 +1,53 @@
+package conversation_msg
+
+import (
+	"sync"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/tools/utils/datautil"
+)
+
+const guestSendWindow = time.Minute
+
+// guestSession restricts a guest login to the designated group conversations with a send rate limit.
+type guestSession struct {
+	lock      sync.Mutex
+	groupIDs  map[string]struct{}
+	sendLimit int
+	sendTimes []time.Time
+}
+
+// SetGuest restricts sending to the designated groups and at most sendLimit messages per minute.
+func (c *Conversation) SetGuest(groupIDs []string, sendLimit int) {
+	c.guest = &guestSession{groupIDs: datautil.SliceSet(groupIDs), sendLimit: sendLimit}
+}
+
+func (c *Conversation) checkGuestSend(recvID, groupID string) error {
+	if c.guest == nil {
+		return nil
+	}
+	if recvID != "" {
+		return sdkerrs.ErrGuestNotAllowed.WrapMsg("guest can not send single chat messages")
+	}
+	if _, ok := c.guest.groupIDs[groupID]; !ok {
+		return sdkerrs.ErrGuestNotAllowed.WrapMsg("guest can not send messages to group " + groupID)
+	}
+	return c.guest.take(time.Now())
+}
+
+func (g *guestSession) take(now time.Time) error {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	start := now.Add(-guestSendWindow)
+	i := 0
+	for i < len(g.sendTimes) && !g.sendTimes[i].After(start) {
+		i++
+	}
+	g.sendTimes = g.sendTimes[i:]
+	if len(g.sendTimes) >= g.sendLimit {
+		return sdkerrs.ErrGuestSendLimit.Wrap()
+	}
+	g.sendTimes = append(g.sendTimes, now)
+	return nil
+}
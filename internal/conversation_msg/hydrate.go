@@ -0,0 +1,109 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversation_msg
+
+import (
+	"context"
+	"slices"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/common"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/tools/utils/datautil"
+)
+
+const (
+	// conversationFirstPageSize is the most conversations shown first, they are hydrated before the list is
+	// returned, the others in the background.
+	conversationFirstPageSize = 20
+	// conversationHydrateBatch is the most conversations hydrated together in the background.
+	conversationHydrateBatch = 100
+)
+
+// needsHydration tells whether the name and face url of the conversation are yet to be loaded, the
+// conversations synced in bulk are stored without them.
+func needsHydration(conversation *model_struct.LocalConversation) bool {
+	switch conversation.ConversationType {
+	case constant.SingleChatType, constant.NotificationChatType, constant.ReadGroupChatType:
+		return conversation.ShowName == "" && conversation.FaceURL == ""
+	}
+	return false
+}
+
+// hydrateConversations loads the names and face urls of the conversations missing them and stores them, it
+// returns the conversations hydrated.
+func (c *Conversation) hydrateConversations(ctx context.Context, conversations []*model_struct.LocalConversation) []*model_struct.LocalConversation {
+	missing := datautil.Filter(conversations, func(conversation *model_struct.LocalConversation) (*model_struct.LocalConversation, bool) {
+		return conversation, needsHydration(conversation)
+	})
+	if len(missing) == 0 {
+		return nil
+	}
+	if err := c.batchAddFaceURLAndName(ctx, missing...); err != nil {
+		log.ZWarn(ctx, "hydrate conversations failed", err, "count", len(missing))
+		return nil
+	}
+	for _, conversation := range missing {
+		if err := c.db.UpdateColumnsConversation(ctx, conversation.ConversationID,
+			map[string]any{"show_name": conversation.ShowName, "face_url": conversation.FaceURL}); err != nil {
+			log.ZWarn(ctx, "store hydrated conversation failed", err, "conversationID", conversation.ConversationID)
+		}
+	}
+	return missing
+}
+
+// hydrateFirstPage hydrates the first page of the conversation list before it is returned, and the rest of it
+// in the background.
+func (c *Conversation) hydrateFirstPage(ctx context.Context, conversations []*model_struct.LocalConversation) {
+	if len(conversations) <= conversationFirstPageSize {
+		c.hydrateConversations(ctx, conversations)
+		return
+	}
+	c.hydrateConversations(ctx, conversations[:conversationFirstPageSize])
+	if slices.ContainsFunc(conversations[conversationFirstPageSize:], needsHydration) {
+		c.hydrateLater(ctx)
+	}
+}
+
+// hydrateLater hydrates the conversations stored without their names and face urls in the background, and
+// reports them changed. It does nothing while it is already running.
+func (c *Conversation) hydrateLater(ctx context.Context) {
+	if !c.hydrating.CompareAndSwap(false, true) {
+		return
+	}
+	go func() {
+		defer c.hydrating.Store(false)
+		ctx := context.WithoutCancel(ctx)
+		conversations, err := c.db.GetAllConversations(ctx)
+		if err != nil {
+			log.ZWarn(ctx, "get conversations to hydrate failed", err)
+			return
+		}
+		conversations = datautil.Filter(conversations, func(conversation *model_struct.LocalConversation) (*model_struct.LocalConversation, bool) {
+			return conversation, needsHydration(conversation)
+		})
+		for start := 0; start < len(conversations); start += conversationHydrateBatch {
+			hydrated := c.hydrateConversations(ctx, conversations[start:min(start+conversationHydrateBatch, len(conversations))])
+			if len(hydrated) == 0 {
+				continue
+			}
+			conversationIDs := datautil.Slice(hydrated, func(conversation *model_struct.LocalConversation) string {
+				return conversation.ConversationID
+			})
+			_ = common.DispatchUpdateConversation(ctx, common.UpdateConNode{Action: constant.ConChange, Args: conversationIDs}, c.conversationEventQueue)
+		}
+	}()
+}
@@ -0,0 +1,306 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversation_msg
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/image/draw"
+
+	"github.com/openimsdk/openim-sdk-core/v3/internal/third/file"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/ccontext"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+	"github.com/openimsdk/tools/errs"
+)
+
+const (
+	DefaultImageQuality = 85
+	minImageQuality     = 40
+	// maxImageEncodes bounds the encodes tried to reach ImageCompression.MaxBytes
+	maxImageEncodes = 8
+)
+
+// ImageCompression is how the pictures sent are downscaled, compressed and thumbnailed before upload.
+type ImageCompression struct {
+	// MaxSide downscales the pictures with a longer side, 0 to keep the size
+	MaxSide int
+	// Quality is the jpeg quality of the pictures encoded again, 0 is the default
+	Quality int
+	// MaxBytes lowers the quality, then the size, until the picture fits in it, 0 for no target
+	MaxBytes int64
+	// ThumbnailMaxSide generates the snapshot of the pictures in the sdk, 0 leaves it to the server
+	ThumbnailMaxSide int
+}
+
+func CheckImageCompression(compression ImageCompression) error {
+	if compression.MaxSide < 0 || compression.MaxBytes < 0 || compression.ThumbnailMaxSide < 0 {
+		return sdkerrs.ErrArgs.WrapMsg("image compression limits are negative")
+	}
+	if compression.Quality < 0 || compression.Quality > 100 {
+		return sdkerrs.ErrArgs.WrapMsg("image quality is out of 1 to 100")
+	}
+	return nil
+}
+
+// SetImageCompression sets how the pictures sent are processed before upload.
+func (c *Conversation) SetImageCompression(compression ImageCompression) {
+	if compression.Quality == 0 {
+		compression.Quality = DefaultImageQuality
+	}
+	c.imageCompression = compression
+}
+
+func (c *Conversation) shouldCompressImage(ctx context.Context) bool {
+	if c.imageCompression.MaxSide == 0 && c.imageCompression.MaxBytes == 0 {
+		return false
+	}
+	if options, ok := ccontext.GetSendMsgOptions(ctx); ok && options.CompressImage != nil {
+		return *options.CompressImage
+	}
+	return true
+}
+
+// compressPicture writes a downscaled and compressed copy of the picture to DataDir, the caller uploads and then
+// removes it. Returns "" when the picture is already within the limits or the copy isn't smaller.
+func (c *Conversation) compressPicture(ctx context.Context, clientMsgID, sourcePath string) (string, *sdk_struct.ImageInfo, error) {
+	data, err := os.ReadFile(sourcePath)
+	if err != nil {
+		return "", nil, errs.WrapMsg(err, "image file open err")
+	}
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return "", nil, errs.WrapMsg(err, "image file decode err")
+	}
+	// an animation would be reduced to its first frame
+	if format == "gif" {
+		return "", nil, nil
+	}
+	compression := c.imageCompression
+	side := longerSide(img.Bounds())
+	if (compression.MaxSide == 0 || side <= compression.MaxSide) && (compression.MaxBytes == 0 || int64(len(data)) <= compression.MaxBytes) {
+		return "", nil, nil
+	}
+	if compression.MaxSide > 0 && side > compression.MaxSide {
+		img = fitImage(img, compression.MaxSide)
+	}
+	img = orientImage(img, imageOrientation(format, data))
+	quality := compression.Quality
+	var (
+		out []byte
+		ext string
+	)
+	for i := 0; ; i++ {
+		out, ext, err = encodeImage(img, quality)
+		if err != nil {
+			return "", nil, err
+		}
+		if compression.MaxBytes == 0 || int64(len(out)) <= compression.MaxBytes || i+1 == maxImageEncodes {
+			break
+		}
+		// the quality only applies to jpeg, a png is made smaller
+		if ext == ".jpg" && quality > minImageQuality {
+			quality = max(quality-10, minImageQuality)
+		} else {
+			img = fitImage(img, longerSide(img.Bounds())*3/4)
+		}
+	}
+	if len(out) >= len(data) && longerSide(img.Bounds()) == side {
+		log.ZDebug(ctx, "picture not compressed, the copy isn't smaller", "path", sourcePath, "size", len(data))
+		return "", nil, nil
+	}
+	dst := filepath.Join(c.DataDir, clientMsgID+".compress"+ext)
+	if err := os.WriteFile(dst, out, 0644); err != nil {
+		return "", nil, errs.Wrap(err)
+	}
+	return dst, newImageInfo(img, ext, len(out)), nil
+}
+
+// generateThumbnail writes the snapshot of the picture to DataDir, the caller uploads and then removes it.
+func (c *Conversation) generateThumbnail(clientMsgID, sourcePath string) (string, *sdk_struct.ImageInfo, error) {
+	data, err := os.ReadFile(sourcePath)
+	if err != nil {
+		return "", nil, errs.WrapMsg(err, "image file open err")
+	}
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return "", nil, errs.WrapMsg(err, "image file decode err")
+	}
+	if longerSide(img.Bounds()) > c.imageCompression.ThumbnailMaxSide {
+		img = fitImage(img, c.imageCompression.ThumbnailMaxSide)
+	}
+	img = orientImage(img, imageOrientation(format, data))
+	out, ext, err := encodeImage(img, c.imageCompression.Quality)
+	if err != nil {
+		return "", nil, err
+	}
+	dst := filepath.Join(c.DataDir, clientMsgID+".thumb"+ext)
+	if err := os.WriteFile(dst, out, 0644); err != nil {
+		return "", nil, errs.Wrap(err)
+	}
+	return dst, newImageInfo(img, ext, len(out)), nil
+}
+
+// uploadThumbnail generates and uploads the snapshot of a picture sent.
+func (c *Conversation) uploadThumbnail(ctx context.Context, clientMsgID, sourcePath string) (*sdk_struct.PictureBaseInfo, error) {
+	thumbnail, info, err := c.generateThumbnail(clientMsgID, sourcePath)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(thumbnail)
+	res, err := c.file.UploadFile(ctx, &file.UploadFileReq{
+		ContentType: info.Type,
+		Filepath:    thumbnail,
+		Name:        c.fileName("pictureSnapshot", clientMsgID) + filepath.Ext(thumbnail),
+		Cause:       "msg-picture-snapshot",
+		Storage:     msgStorage(ctx),
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &sdk_struct.PictureBaseInfo{
+		Type:   info.Type,
+		Size:   info.Size,
+		Width:  info.Width,
+		Height: info.Height,
+		Url:    res.URL,
+		Md5:    res.Md5,
+	}, nil
+}
+
+func newImageInfo(img image.Image, ext string, size int) *sdk_struct.ImageInfo {
+	typ := "image/jpeg"
+	if ext == ".png" {
+		typ = "image/png"
+	}
+	b := img.Bounds()
+	return &sdk_struct.ImageInfo{Width: int32(b.Dx()), Height: int32(b.Dy()), Type: typ, Size: int64(size)}
+}
+
+// replaceExt gives the uuid of a picture the extension of the file uploaded.
+func replaceExt(name, ext string) string {
+	if name == "" {
+		return name
+	}
+	return strings.TrimSuffix(name, filepath.Ext(name)) + ext
+}
+
+func longerSide(b image.Rectangle) int {
+	return max(b.Dx(), b.Dy())
+}
+
+// fitImage scales img down so that its longer side is side, keeping the ratio.
+func fitImage(img image.Image, side int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w >= h {
+		w, h = side, max(h*side/w, 1)
+	} else {
+		w, h = max(w*side/h, 1), side
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.BiLinear.Scale(dst, dst.Bounds(), img, b, draw.Src, nil)
+	return dst
+}
+
+// encodeImage encodes the pictures with transparency as png to keep it, the others as jpeg.
+func encodeImage(img image.Image, quality int) ([]byte, string, error) {
+	var buf bytes.Buffer
+	if !isOpaque(img) {
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, "", errs.WrapMsg(err, "image encode err")
+		}
+		return buf.Bytes(), ".png", nil
+	}
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+		return nil, "", errs.WrapMsg(err, "image encode err")
+	}
+	return buf.Bytes(), ".jpg", nil
+}
+
+func isOpaque(img image.Image) bool {
+	if o, ok := img.(interface{ Opaque() bool }); ok {
+		return o.Opaque()
+	}
+	return true
+}
+
+// imageOrientation is the exif orientation of a jpeg, the decoded pixels aren't rotated by it.
+func imageOrientation(format string, data []byte) uint16 {
+	if format != "jpeg" {
+		return 1
+	}
+	for i := len(jpegSOI); i+4 <= len(data) && data[i] == 0xFF; {
+		marker := data[i+1]
+		if marker == jpegSOS {
+			break
+		}
+		size := int(data[i+2])<<8 | int(data[i+3])
+		payload := data[min(i+4, len(data)):min(i+2+size, len(data))]
+		if marker == jpegAPP1 && bytes.HasPrefix(payload, exifHeader) {
+			if o := exifOrientation(payload[len(exifHeader):]); o != 0 {
+				return o
+			}
+		}
+		i += 2 + size
+	}
+	return 1
+}
+
+// orientImage turns the pixels upright according to the exif orientation, as the metadata is not written again.
+func orientImage(img image.Image, orient uint16) image.Image {
+	if orient < 2 || orient > 8 {
+		return img
+	}
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	dw, dh := w, h
+	if orient >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		for x := 0; x < dw; x++ {
+			var sx, sy int
+			switch orient {
+			case 2: // mirrored
+				sx, sy = w-1-x, y
+			case 3: // rotated 180
+				sx, sy = w-1-x, h-1-y
+			case 4: // flipped
+				sx, sy = x, h-1-y
+			case 5: // transposed
+				sx, sy = y, x
+			case 6: // rotated 90 clockwise
+				sx, sy = y, h-1-x
+			case 7: // transversed
+				sx, sy = w-1-y, h-1-x
+			case 8: // rotated 90 counterclockwise
+				sx, sy = w-1-y, x
+			}
+			dst.Set(x, y, color.RGBAModel.Convert(img.At(b.Min.X+sx, b.Min.Y+sy)))
+		}
+	}
+	return dst
+}
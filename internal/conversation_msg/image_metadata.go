@@ -0,0 +1,263 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversation_msg
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/ccontext"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/tools/errs"
+)
+
+var (
+	jpegSOI    = []byte{0xFF, 0xD8}
+	pngMagic   = []byte("\x89PNG\r\n\x1a\n")
+	exifHeader = []byte("Exif\x00\x00")
+)
+
+// jpeg markers of the segments carrying metadata
+const (
+	jpegAPP1    = 0xE1 // exif and xmp
+	jpegAPP13   = 0xED // iptc
+	jpegCOM     = 0xFE
+	jpegSOS     = 0xDA
+	orientation = 0x0112
+)
+
+// pngMetadataChunks are the png chunks carrying metadata, the others are kept.
+var pngMetadataChunks = map[string]bool{"eXIf": true, "tEXt": true, "zTXt": true, "iTXt": true, "tIME": true}
+
+// SetStripImageMetadata sets whether the metadata of the pictures sent is removed when the message doesn't say.
+func (c *Conversation) SetStripImageMetadata(strip bool) {
+	c.stripImageMetadata = strip
+}
+
+func (c *Conversation) shouldStripImageMetadata(ctx context.Context) bool {
+	if options, ok := ccontext.GetSendMsgOptions(ctx); ok && options.StripImageMetadata != nil {
+		return *options.StripImageMetadata
+	}
+	return c.stripImageMetadata
+}
+
+// stripPictureMetadata writes a copy of the picture without its metadata to DataDir, the caller uploads and then
+// removes it. Returns "" when the format isn't handled, the picture is sent as it is then.
+func (c *Conversation) stripPictureMetadata(ctx context.Context, clientMsgID, sourcePath string) (string, error) {
+	dst := filepath.Join(c.DataDir, clientMsgID+".strip"+filepath.Ext(sourcePath))
+	ok, err := stripImageMetadata(sourcePath, dst)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		log.ZDebug(ctx, "picture metadata not stripped, unsupported format", "path", sourcePath)
+		return "", nil
+	}
+	return dst, nil
+}
+
+// stripImageMetadata writes the image at src to dst without its exif, xmp, iptc and text metadata, e.g. the
+// GPS location. The pixels are copied as they are, only the orientation of a jpeg is kept so that it is still
+// displayed upright. Returns false when the format is neither jpeg nor png, dst is not written then.
+func stripImageMetadata(src, dst string) (bool, error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return false, errs.WrapMsg(err, "image file open err")
+	}
+	defer in.Close()
+	r := bufio.NewReader(in)
+	head, err := r.Peek(len(pngMagic))
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, errs.Wrap(err)
+	}
+	var strip func(w io.Writer, r *bufio.Reader) error
+	switch {
+	case bytes.HasPrefix(head, jpegSOI):
+		strip = stripJPEG
+	case bytes.HasPrefix(head, pngMagic):
+		strip = stripPNG
+	default:
+		return false, nil
+	}
+	out, err := os.Create(dst)
+	if err != nil {
+		return false, errs.Wrap(err)
+	}
+	w := bufio.NewWriter(out)
+	if err := strip(w, r); err != nil {
+		_ = out.Close()
+		_ = os.Remove(dst)
+		return false, errs.WrapMsg(err, "strip image metadata failed")
+	}
+	if err := w.Flush(); err != nil {
+		_ = out.Close()
+		_ = os.Remove(dst)
+		return false, errs.Wrap(err)
+	}
+	return true, errs.Wrap(out.Close())
+}
+
+// stripJPEG copies the segments up to the scan but the metadata ones, then the scan and the rest as they are.
+func stripJPEG(w io.Writer, r *bufio.Reader) error {
+	if _, err := io.CopyN(w, r, int64(len(jpegSOI))); err != nil {
+		return err
+	}
+	var orient uint16
+	for {
+		var marker [2]byte
+		if _, err := io.ReadFull(r, marker[:]); err != nil {
+			return err
+		}
+		if marker[0] != 0xFF {
+			return errs.New("invalid jpeg marker")
+		}
+		// fill bytes before a marker
+		for marker[1] == 0xFF {
+			b, err := r.ReadByte()
+			if err != nil {
+				return err
+			}
+			marker[1] = b
+		}
+		if marker[1] == jpegSOS {
+			if orient > 1 {
+				if _, err := w.Write(exifOrientationSegment(orient)); err != nil {
+					return err
+				}
+			}
+			if _, err := w.Write(marker[:]); err != nil {
+				return err
+			}
+			_, err := io.Copy(w, r)
+			return err
+		}
+		var length [2]byte
+		if _, err := io.ReadFull(r, length[:]); err != nil {
+			return err
+		}
+		size := int(binary.BigEndian.Uint16(length[:]))
+		if size < 2 {
+			return errs.New("invalid jpeg segment length")
+		}
+		payload := make([]byte, size-2)
+		if _, err := io.ReadFull(r, payload); err != nil {
+			return err
+		}
+		switch marker[1] {
+		case jpegAPP1:
+			if bytes.HasPrefix(payload, exifHeader) {
+				if o := exifOrientation(payload[len(exifHeader):]); o != 0 {
+					orient = o
+				}
+			}
+			continue
+		case jpegAPP13, jpegCOM:
+			continue
+		}
+		for _, b := range [][]byte{marker[:], length[:], payload} {
+			if _, err := w.Write(b); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// exifOrientation reads the orientation tag of the first ifd of the tiff data of an exif segment, 0 when it
+// has none.
+func exifOrientation(tiff []byte) uint16 {
+	if len(tiff) < 8 {
+		return 0
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0
+	}
+	offset := int(order.Uint32(tiff[4:8]))
+	if offset < 8 || offset+2 > len(tiff) {
+		return 0
+	}
+	count := int(order.Uint16(tiff[offset:]))
+	for i := 0; i < count; i++ {
+		entry := offset + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 0
+		}
+		if order.Uint16(tiff[entry:]) == orientation {
+			return order.Uint16(tiff[entry+8:])
+		}
+	}
+	return 0
+}
+
+// exifOrientationSegment is an exif segment holding only the orientation.
+func exifOrientationSegment(orient uint16) []byte {
+	var tiff bytes.Buffer
+	tiff.WriteString("II*\x00")
+	_ = binary.Write(&tiff, binary.LittleEndian, uint32(8)) // offset of the first ifd
+	_ = binary.Write(&tiff, binary.LittleEndian, uint16(1)) // one entry
+	_ = binary.Write(&tiff, binary.LittleEndian, []uint16{orientation, 3})
+	_ = binary.Write(&tiff, binary.LittleEndian, uint32(1))
+	_ = binary.Write(&tiff, binary.LittleEndian, []uint16{orient, 0})
+	_ = binary.Write(&tiff, binary.LittleEndian, uint32(0)) // no next ifd
+	segment := []byte{0xFF, jpegAPP1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(2+len(exifHeader)+tiff.Len()))
+	segment = append(segment, exifHeader...)
+	return append(segment, tiff.Bytes()...)
+}
+
+// stripPNG copies the chunks but the metadata ones.
+func stripPNG(w io.Writer, r *bufio.Reader) error {
+	if _, err := io.CopyN(w, r, int64(len(pngMagic))); err != nil {
+		return err
+	}
+	for {
+		var header [8]byte
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		length := int64(binary.BigEndian.Uint32(header[:4]))
+		typ := string(header[4:])
+		// the data and the crc
+		if pngMetadataChunks[typ] {
+			if _, err := io.CopyN(io.Discard, r, length+4); err != nil {
+				return err
+			}
+			continue
+		}
+		if _, err := w.Write(header[:]); err != nil {
+			return err
+		}
+		if _, err := io.CopyN(w, r, length+4); err != nil {
+			return err
+		}
+		if typ == "IEND" {
+			return nil
+		}
+	}
+}
@@ -15,7 +15,9 @@
 package conversation_msg
 
 import (
+	"cmp"
 	"context"
+	"slices"
 
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/syncer"
@@ -54,14 +56,14 @@ func (c *Conversation) IncrSyncConversations(ctx context.Context) error {
 			return datautil.Batch(ServerConversationToLocal, resp.Insert)
 		},
 		Syncer: func(server, local []*model_struct.LocalConversation) error {
-			return c.conversationSyncer.Sync(ctx, server, local, nil, true)
+			return c.conversationSyncer.Sync(ctx, prioritySyncOrder(server, local), local, nil, true)
 		},
 		FullSyncer: func(ctx context.Context) error {
 			conversationIDList, err := c.db.GetAllConversationIDList(ctx)
 			if err != nil {
 				return err
 			}
-			if len(conversationIDList) == 0 {
+			if len(conversationIDList) == 0 || syncer.FullSyncResumed(ctx) {
 				return c.conversationSyncer.FullSync(ctx, c.loginUserID)
 			} else {
 				local, err := c.db.GetAllConversations(ctx)
@@ -73,7 +75,7 @@ func (c *Conversation) IncrSyncConversations(ctx context.Context) error {
 					return err
 				}
 				server := datautil.Batch(ServerConversationToLocal, resp.Conversations)
-				return c.conversationSyncer.Sync(ctx, server, local, nil, true)
+				return c.conversationSyncer.Sync(ctx, prioritySyncOrder(server, local), local, nil, true)
 			}
 		},
 		FullID: func(ctx context.Context) ([]string, error) {
@@ -85,7 +87,14 @@ func (c *Conversation) IncrSyncConversations(ctx context.Context) error {
 		},
 	}
 
-	return conversationSyncer.IncrementalSync()
+	if err := conversationSyncer.IncrementalSync(); err != nil {
+		return err
+	}
+	// the conversations synced in bulk are counted once loaded again
+	c.unread.invalidate()
+	// the conversations synced in bulk are stored without their names and face urls
+	c.hydrateLater(ctx)
+	return nil
 }
 
 func (c *Conversation) IncrSyncConversationsWithLock(ctx context.Context) error {
@@ -94,6 +103,39 @@ func (c *Conversation) IncrSyncConversationsWithLock(ctx context.Context) error
 	return c.IncrSyncConversations(ctx)
 }
 
+// ForceResyncConversations drops the version of the conversation list, so that the server sends the full
+// list again, and pulls the read seqs of all the conversations.
+func (c *Conversation) ForceResyncConversations(ctx context.Context) error {
+	c.conversationSyncMutex.Lock()
+	defer c.conversationSyncMutex.Unlock()
+	if err := c.db.DeleteVersionSync(ctx, c.conversationTableName(), c.loginUserID); err != nil {
+		return err
+	}
+	if err := c.IncrSyncConversations(ctx); err != nil {
+		return err
+	}
+	return c.SyncAllConversationHashReadSeqs(ctx)
+}
+
+// prioritySyncOrder orders the server conversations to store, the pinned ones first, then the ones with the
+// latest messages locally, so that the top of the conversation list is right first.
+func prioritySyncOrder(server, local []*model_struct.LocalConversation) []*model_struct.LocalConversation {
+	latest := make(map[string]int64, len(local))
+	for _, conversation := range local {
+		latest[conversation.ConversationID] = max(conversation.LatestMsgSendTime, conversation.DraftTextTime)
+	}
+	slices.SortStableFunc(server, func(a, b *model_struct.LocalConversation) int {
+		if a.IsPinned != b.IsPinned {
+			if a.IsPinned {
+				return -1
+			}
+			return 1
+		}
+		return cmp.Compare(latest[b.ConversationID], latest[a.ConversationID])
+	})
+	return server
+}
+
 func (c *Conversation) conversationTableName() string {
 	return model_struct.LocalConversation{}.TableName()
 }
@@ -0,0 +1,144 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversation_msg
+
+import (
+	"context"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/api"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/common"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/server_api_params"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+	"github.com/openimsdk/protocol/sdkws"
+	"github.com/openimsdk/tools/errs"
+	"github.com/openimsdk/tools/utils/datautil"
+)
+
+// SubmitInteractiveAction routes the action of an element of the interactive card to the bot which sent it.
+// The payload of a menu is the value of the option chosen. The card edited by the bot replaces the local one
+// and is passed to OnMsgEdited, whether the bot answers right away or later.
+func (c *Conversation) SubmitInteractiveAction(ctx context.Context, conversationID, clientMsgID, actionID, payload string) error {
+	message, err := c.db.GetMessage(ctx, conversationID, clientMsgID)
+	if err != nil {
+		return err
+	}
+	if message.ContentType != constant.InteractiveCard {
+		return sdkerrs.ErrArgs.WrapMsg("the message is not an interactive card", "contentType", message.ContentType)
+	}
+	var card sdk_struct.InteractiveCardElem
+	if err := utils.JsonStringToStruct(message.Content, &card); err != nil {
+		return err
+	}
+	if err := checkInteractiveAction(&card, actionID, payload); err != nil {
+		return err
+	}
+	resp, err := api.SubmitInteractiveAction.Invoke(ctx, &server_api_params.SubmitInteractiveActionReq{
+		ConversationID: conversationID,
+		ClientMsgID:    clientMsgID,
+		ServerMsgID:    message.ServerMsgID,
+		BotUserID:      message.SendID,
+		ActionID:       actionID,
+		Payload:        payload,
+	})
+	if err != nil {
+		return err
+	}
+	if resp.Card != nil {
+		return c.updateInteractiveCard(ctx, conversationID, clientMsgID, resp.Card)
+	}
+	return nil
+}
+
+// checkInteractiveAction checks the card takes the action, and the payload of a menu is one of its options.
+func checkInteractiveAction(card *sdk_struct.InteractiveCardElem, actionID, payload string) error {
+	if card.Closed {
+		return sdkerrs.ErrArgs.WrapMsg("the interactive card is closed")
+	}
+	var element *sdk_struct.InteractiveElement
+	for _, e := range card.Elements {
+		if e.ActionID == actionID {
+			element = e
+			break
+		}
+	}
+	if element == nil {
+		return sdkerrs.ErrArgs.WrapMsg("the interactive card has no such action", "actionID", actionID)
+	}
+	if element.Disabled {
+		return sdkerrs.ErrArgs.WrapMsg("the action is disabled", "actionID", actionID)
+	}
+	if element.Type == constant.InteractiveElementMenu && !datautil.Contain(payload, datautil.Slice(element.Options, func(option *sdk_struct.InteractiveOption) string {
+		return option.Value
+	})...) {
+		return sdkerrs.ErrArgs.WrapMsg("the payload of a menu is the value of one of its options", "actionID", actionID)
+	}
+	return nil
+}
+
+func (c *Conversation) doInteractiveCardUpdated(ctx context.Context, msg *sdkws.MsgData) error {
+	var tips server_api_params.InteractiveCardUpdatedTips
+	if err := utils.UnmarshalNotificationElem(msg.Content, &tips); err != nil {
+		return err
+	}
+	if tips.Card == nil {
+		return errs.New("interactive card notification without the card", "clientMsgID", tips.ClientMsgID).Wrap()
+	}
+	return c.updateInteractiveCard(ctx, tips.ConversationID, tips.ClientMsgID, tips.Card)
+}
+
+// updateInteractiveCard edits the card in place unless the local one is as recent, then calls OnMsgEdited
+// and refreshes the conversation of which it is the latest message.
+func (c *Conversation) updateInteractiveCard(ctx context.Context, conversationID, clientMsgID string, card *sdk_struct.InteractiveCardElem) error {
+	c.interactiveCardMutex.Lock()
+	defer c.interactiveCardMutex.Unlock()
+	message, err := c.db.GetMessage(ctx, conversationID, clientMsgID)
+	if err != nil {
+		return err
+	}
+	if message.ContentType != constant.InteractiveCard {
+		return errs.New("the edited message is not an interactive card", "clientMsgID", clientMsgID, "contentType", message.ContentType).Wrap()
+	}
+	var local sdk_struct.InteractiveCardElem
+	if err := utils.JsonStringToStruct(message.Content, &local); err != nil {
+		return err
+	}
+	if card.Version <= local.Version {
+		log.ZDebug(ctx, "interactive card already up to date", "clientMsgID", clientMsgID, "version", card.Version, "localVersion", local.Version)
+		return nil
+	}
+	message.Content = utils.StructToJsonString(card)
+	if err := c.db.UpdateMessage(ctx, conversationID, &model_struct.LocalChatLog{ClientMsgID: clientMsgID, Content: message.Content}); err != nil {
+		return err
+	}
+	c.invalidateTranslations(ctx, clientMsgID)
+	edited := LocalChatLogToMsgStruct(message)
+	c.msgListener().OnMsgEdited(utils.StructToJsonString(edited))
+	conversation, err := c.db.GetConversation(ctx, conversationID)
+	if err != nil {
+		return err
+	}
+	if c.getConversationLatestMsgClientID(conversation.LatestMsg) == clientMsgID {
+		if err := c.db.UpdateColumnsConversation(ctx, conversationID, map[string]any{"latest_msg": utils.StructToJsonString(edited)}); err != nil {
+			return err
+		}
+		c.doUpdateConversation(common.Cmd2Value{Ctx: ctx, Value: common.UpdateConNode{Action: constant.ConChange, Args: []string{conversationID}}})
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package conversation_msg
+
+import (
+	"testing"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+func TestCheckInteractiveAction(t *testing.T) {
+	card := &sdk_struct.InteractiveCardElem{
+		Elements: []*sdk_struct.InteractiveElement{
+			{ActionID: "approve", Type: constant.InteractiveElementButton},
+			{ActionID: "archived", Type: constant.InteractiveElementButton, Disabled: true},
+			{ActionID: "priority", Type: constant.InteractiveElementMenu, Options: []*sdk_struct.InteractiveOption{
+				{Value: "high"}, {Value: "low"},
+			}},
+		},
+	}
+	tests := []struct {
+		actionID string
+		payload  string
+		ok       bool
+	}{
+		{"approve", "", true},
+		{"approve", "anything", true},
+		{"archived", "", false},
+		{"unknown", "", false},
+		{"priority", "low", true},
+		{"priority", "urgent", false},
+	}
+	for _, tt := range tests {
+		if err := checkInteractiveAction(card, tt.actionID, tt.payload); (err == nil) != tt.ok {
+			t.Errorf("checkInteractiveAction(%s, %s) = %v", tt.actionID, tt.payload, err)
+		}
+	}
+	card.Closed = true
+	if err := checkInteractiveAction(card, "approve", ""); err == nil {
+		t.Error("a closed card takes actions")
+	}
+}
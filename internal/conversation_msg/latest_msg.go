@@ -0,0 +1,73 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversation_msg
+
+import (
+	"encoding/json"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/cache"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+// latestMsgCacheSize is the most conversations whose latest message is kept parsed.
+const latestMsgCacheSize = 1000
+
+// parsedLatestMsg is the latest message of a conversation parsed from its json.
+type parsedLatestMsg struct {
+	raw string
+	msg *sdk_struct.MsgStruct
+}
+
+func newLatestMsgCache() *cache.Cache[string, *parsedLatestMsg] {
+	store := cache.NewTTLStore[string, *parsedLatestMsg](0, latestMsgCacheSize)
+	cache.Register("latestMsg", store)
+	return cache.NewCacheWithStore(store)
+}
+
+// parseLatestMsg parses the latest message of the conversation, the same json is parsed once. The message
+// returned is a copy of the one kept whose elements are shared: only its own fields are to be changed.
+func (c *Conversation) parseLatestMsg(conversation *model_struct.LocalConversation) (*sdk_struct.MsgStruct, error) {
+	parsed, ok := c.latestMsgs.Load(conversation.ConversationID)
+	if !ok || parsed.raw != conversation.LatestMsg {
+		msg := &sdk_struct.MsgStruct{}
+		if err := json.Unmarshal([]byte(conversation.LatestMsg), msg); err != nil {
+			return nil, err
+		}
+		parsed = &parsedLatestMsg{raw: conversation.LatestMsg, msg: msg}
+		c.latestMsgs.Store(conversation.ConversationID, parsed)
+	}
+	msg := *parsed.msg
+	return &msg, nil
+}
+
+// setLatestMsgs sets the latest messages of the conversations received together, each one is marshaled once
+// rather than once for each message received.
+func setLatestMsgs(conversations map[string]*model_struct.LocalConversation, latestMsgs map[string]*sdk_struct.MsgStruct) {
+	for conversationID, msg := range latestMsgs {
+		if conversation, ok := conversations[conversationID]; ok {
+			conversation.LatestMsg = utils.StructToJsonString(msg)
+		}
+	}
+}
+
+// updateLatestMsg keeps the message as the latest one of its conversation when it is newer, the first one
+// otherwise, the same as updateConversation does.
+func updateLatestMsg(latestMsgs map[string]*sdk_struct.MsgStruct, conversationID string, msg *sdk_struct.MsgStruct) {
+	if latest, ok := latestMsgs[conversationID]; !ok || msg.SendTime > latest.SendTime {
+		latestMsgs[conversationID] = msg
+	}
+}
@@ -0,0 +1,44 @@
+package conversation_msg
+
+import (
+	"testing"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+func TestParseLatestMsg(t *testing.T) {
+	c := &Conversation{latestMsgs: newLatestMsgCache()}
+	conversation := &model_struct.LocalConversation{ConversationID: "si_1_2",
+		LatestMsg: utils.StructToJsonString(&sdk_struct.MsgStruct{ClientMsgID: "a", Seq: 1})}
+	msg, err := c.parseLatestMsg(conversation)
+	if err != nil || msg.ClientMsgID != "a" {
+		t.Fatal(msg, err)
+	}
+	msg.IsRead = true
+	if msg, _ = c.parseLatestMsg(conversation); msg.IsRead {
+		t.Fatal("the message kept was changed")
+	}
+	conversation.LatestMsg = utils.StructToJsonString(&sdk_struct.MsgStruct{ClientMsgID: "b", Seq: 2})
+	if msg, _ = c.parseLatestMsg(conversation); msg.ClientMsgID != "b" {
+		t.Fatal("the changed json was not parsed again", msg)
+	}
+	conversation.LatestMsg = "{"
+	if _, err = c.parseLatestMsg(conversation); err == nil {
+		t.Fatal("invalid json parsed")
+	}
+}
+
+func TestSetLatestMsgs(t *testing.T) {
+	latestMsgs := make(map[string]*sdk_struct.MsgStruct)
+	for _, msg := range []*sdk_struct.MsgStruct{{ClientMsgID: "a", SendTime: 2}, {ClientMsgID: "b", SendTime: 1}, {ClientMsgID: "c", SendTime: 2}} {
+		updateLatestMsg(latestMsgs, "si_1_2", msg)
+	}
+	conversations := map[string]*model_struct.LocalConversation{"si_1_2": {ConversationID: "si_1_2"}}
+	setLatestMsgs(conversations, latestMsgs)
+	var msg sdk_struct.MsgStruct
+	if err := utils.JsonStringToStruct(conversations["si_1_2"].LatestMsg, &msg); err != nil || msg.ClientMsgID != "a" {
+		t.Fatal(conversations["si_1_2"].LatestMsg, err)
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversation_msg
+
+import (
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/i18n"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+	"github.com/openimsdk/protocol/sdkws"
+)
+
+// userName is the name of the user in the tips, You for the login user.
+func (c *Conversation) userName(userID, nickname string) string {
+	switch {
+	case userID == c.loginUserID:
+		return i18n.T(i18n.You)
+	case nickname != "":
+		return nickname
+	case userID != "":
+		return userID
+	default:
+		return i18n.T(i18n.UnknownUser)
+	}
+}
+
+func (c *Conversation) memberName(member *sdkws.GroupMemberFullInfo) string {
+	if member == nil {
+		return i18n.T(i18n.UnknownUser)
+	}
+	return c.userName(member.UserID, member.Nickname)
+}
+
+func (c *Conversation) memberNames(members []*sdkws.GroupMemberFullInfo) string {
+	names := make([]string, 0, len(members))
+	for _, member := range members {
+		names = append(names, c.memberName(member))
+	}
+	return i18n.List(names)
+}
+
+func groupName(group *sdkws.GroupInfo) string {
+	if group == nil {
+		return ""
+	}
+	return group.GroupName
+}
+
+// revokeTips is the text shown for the revoke.
+func (c *Conversation) revokeTips(m *sdk_struct.MessageRevoked) string {
+	revoker := c.userName(m.RevokerID, m.RevokerNickname)
+	if m.IsAdminRevoke && m.RevokerID != m.SourceMessageSendID {
+		return i18n.T(i18n.MessageRevokedByAdmin, "user", revoker, "sender", c.userName(m.SourceMessageSendID, m.SourceMessageSenderNickname))
+	}
+	return i18n.T(i18n.MessageRevoked, "user", revoker)
+}
+
+// localizeTips fills the tips of the group notification received, in the locale of the config.
+func (c *Conversation) localizeTips(msg *sdk_struct.MsgStruct) {
+	if msg.NotificationElem == nil || msg.ContentType <= constant.GroupNotificationBegin || msg.ContentType >= constant.GroupNotificationEnd {
+		return
+	}
+	if tips := c.groupTips(msg.ContentType, msg.NotificationElem.Detail); tips != "" {
+		msg.NotificationElem.Tips = tips
+	}
+}
+
+// parseTips renders the text of the detail of a notification, empty when it can not be parsed.
+func parseTips[T any](detail string, text func(tips *T) string) string {
+	var tips T
+	if utils.JsonStringToStruct(detail, &tips) != nil {
+		return ""
+	}
+	return text(&tips)
+}
+
+// groupTips is the text shown for the group notification, empty for the ones without a text.
+func (c *Conversation) groupTips(contentType int32, detail string) string {
+	switch contentType {
+	case constant.GroupCreatedNotification:
+		return parseTips(detail, func(tips *sdkws.GroupCreatedTips) string {
+			return i18n.T(i18n.GroupCreated, "user", c.memberName(tips.OpUser), "group", groupName(tips.Group))
+		})
+	case constant.GroupInfoSetNotification:
+		return parseTips(detail, func(tips *sdkws.GroupInfoSetTips) string {
+			return i18n.T(i18n.GroupInfoSet, "user", c.memberName(tips.OpUser))
+		})
+	case constant.GroupInfoSetNameNotification:
+		return parseTips(detail, func(tips *sdkws.GroupInfoSetNameTips) string {
+			return i18n.T(i18n.GroupNameSet, "user", c.memberName(tips.OpUser), "group", groupName(tips.Group))
+		})
+	case constant.GroupInfoSetAnnouncementNotification:
+		return parseTips(detail, func(tips *sdkws.GroupInfoSetAnnouncementTips) string {
+			return i18n.T(i18n.GroupAnnouncementSet, "user", c.memberName(tips.OpUser))
+		})
+	case constant.GroupOwnerTransferredNotification:
+		return parseTips(detail, func(tips *sdkws.GroupOwnerTransferredTips) string {
+			return i18n.T(i18n.GroupOwnerTransferred, "user", c.memberName(tips.OpUser), "member", c.memberName(tips.NewGroupOwner))
+		})
+	case constant.GroupDismissedNotification:
+		return parseTips(detail, func(tips *sdkws.GroupDismissedTips) string {
+			return i18n.T(i18n.GroupDismissed, "user", c.memberName(tips.OpUser))
+		})
+	case constant.GroupMutedNotification:
+		return parseTips(detail, func(tips *sdkws.GroupMutedTips) string {
+			return i18n.T(i18n.GroupMuted, "user", c.memberName(tips.OpUser))
+		})
+	case constant.GroupCancelMutedNotification:
+		return parseTips(detail, func(tips *sdkws.GroupCancelMutedTips) string {
+			return i18n.T(i18n.GroupCancelMuted, "user", c.memberName(tips.OpUser))
+		})
+	case constant.MemberInvitedNotification:
+		return parseTips(detail, func(tips *sdkws.MemberInvitedTips) string {
+			return i18n.T(i18n.MemberInvited, "user", c.memberName(tips.OpUser), "members", c.memberNames(tips.InvitedUserList))
+		})
+	case constant.MemberKickedNotification:
+		return parseTips(detail, func(tips *sdkws.MemberKickedTips) string {
+			return i18n.T(i18n.MemberKicked, "user", c.memberName(tips.OpUser), "members", c.memberNames(tips.KickedUserList))
+		})
+	case constant.MemberQuitNotification:
+		return parseTips(detail, func(tips *sdkws.MemberQuitTips) string {
+			return i18n.T(i18n.MemberQuit, "user", c.memberName(tips.QuitUser))
+		})
+	case constant.MemberEnterNotification:
+		return parseTips(detail, func(tips *sdkws.MemberEnterTips) string {
+			return i18n.T(i18n.MemberEnter, "user", c.memberName(tips.EntrantUser))
+		})
+	case constant.GroupMemberMutedNotification:
+		return parseTips(detail, func(tips *sdkws.GroupMemberMutedTips) string {
+			return i18n.T(i18n.MemberMuted, "user", c.memberName(tips.OpUser), "member", c.memberName(tips.MutedUser),
+				"duration", i18n.Duration(int64(tips.MutedSeconds)))
+		})
+	case constant.GroupMemberCancelMutedNotification:
+		return parseTips(detail, func(tips *sdkws.GroupMemberCancelMutedTips) string {
+			return i18n.T(i18n.MemberCancelMuted, "user", c.memberName(tips.OpUser), "member", c.memberName(tips.MutedUser))
+		})
+	case constant.GroupMemberSetToAdminNotification:
+		return parseTips(detail, func(tips *sdkws.GroupMemberInfoSetTips) string {
+			return i18n.T(i18n.MemberSetToAdmin, "user", c.memberName(tips.OpUser), "member", c.memberName(tips.ChangedUser))
+		})
+	case constant.GroupMemberSetToOrdinaryUserNotification:
+		return parseTips(detail, func(tips *sdkws.GroupMemberInfoSetTips) string {
+			return i18n.T(i18n.MemberSetToOrdinaryUser, "user", c.memberName(tips.OpUser), "member", c.memberName(tips.ChangedUser))
+		})
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,30 @@
+package conversation_msg
+
+import (
+	"testing"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/i18n"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+	"github.com/openimsdk/protocol/sdkws"
+)
+
+func TestLocalizeTips(t *testing.T) {
+	defer i18n.SetLocale("")
+	c := &Conversation{loginUserID: "me"}
+	detail := utils.StructToJsonString(&sdkws.MemberKickedTips{
+		OpUser:         &sdkws.GroupMemberFullInfo{UserID: "me"},
+		KickedUserList: []*sdkws.GroupMemberFullInfo{{UserID: "u1", Nickname: "Bob"}, {UserID: "u2"}},
+	})
+	msg := &sdk_struct.MsgStruct{ContentType: constant.MemberKickedNotification, NotificationElem: &sdk_struct.NotificationElem{Detail: detail}}
+	c.localizeTips(msg)
+	if msg.NotificationElem.Tips != "You removed Bob, u2 from the group" {
+		t.Fatal(msg.NotificationElem.Tips)
+	}
+	i18n.SetLocale("zh")
+	revoked := &sdk_struct.MessageRevoked{RevokerID: "admin", RevokerNickname: "Ann", IsAdminRevoke: true, SourceMessageSendID: "me"}
+	if tips := c.revokeTips(revoked); tips != "Ann撤回了你的一条消息" {
+		t.Fatal(tips)
+	}
+}
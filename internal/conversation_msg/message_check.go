@@ -8,13 +8,12 @@ import (
 
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
 	sdk "github.com/openimsdk/openim-sdk-core/v3/pkg/sdk_params_callback"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
 	"github.com/openimsdk/protocol/msg"
 	"github.com/openimsdk/tools/utils/datautil"
 
-	"github.com/openimsdk/tools/log"
-
 	"github.com/openimsdk/protocol/sdkws"
 )
 
@@ -436,6 +435,8 @@ func (c *Conversation) pullMessageIntoTable(ctx context.Context, pullMsgData map
 		localMessagesMap := datautil.SliceToMap(localMessages, func(msg *model_struct.LocalChatLog) string { return msg.ClientMsgID })
 		for _, v := range msgs.Msgs {
 			log.ZDebug(ctx, "msg detail", "msg", v, "conversationID", conversationID)
+			// in place, the messages pulled are merged into the ones returned as well
+			c.unsealMsgData(ctx, conversationID, v)
 			//When the message has been marked and deleted by the cloud, it is directly inserted locally
 			//without any conversation and message update.
 			msg := MsgDataToLocalChatLog(v)
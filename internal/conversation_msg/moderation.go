@@ -0,0 +1,172 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversation_msg
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+	"github.com/openimsdk/tools/errs"
+)
+
+// receivedModerationTimeout bounds the wait for the verdict on a message received, the message passes after it.
+const receivedModerationTimeout = 10 * time.Second
+
+type moderation struct {
+	moderator func() open_im_sdk_callback.Moderator
+	received  atomic.Bool
+}
+
+func (c *Conversation) SetModerator(moderator func() open_im_sdk_callback.Moderator) {
+	c.moderation.moderator = moderator
+}
+
+// SetModerateReceived sets whether the messages received from the other users are moderated too.
+func (c *Conversation) SetModerateReceived(received bool) {
+	c.moderation.received.Store(received)
+}
+
+func (c *Conversation) moderator() open_im_sdk_callback.Moderator {
+	if c.moderation.moderator == nil {
+		return nil
+	}
+	return c.moderation.moderator()
+}
+
+// moderate asks the moderator for its verdict on the message and waits for it when it is reported later.
+func moderate(ctx context.Context, moderator open_im_sdk_callback.Moderator, msg *sdk_struct.MsgStruct, received bool) (*sdk_struct.ModerationVerdict, error) {
+	cb := &moderateCallback{done: make(chan struct{})}
+	data := moderator.Moderate(utils.StructToJsonString(msg), received, cb)
+	if data == "" {
+		select {
+		case <-ctx.Done():
+			return nil, context.Cause(ctx)
+		case <-cb.done:
+		}
+		if cb.err != nil {
+			return nil, cb.err
+		}
+		data = cb.data
+	}
+	var verdict sdk_struct.ModerationVerdict
+	if err := utils.JsonStringToStruct(data, &verdict); err != nil {
+		return nil, sdkerrs.ErrArgs.WrapMsg("invalid moderation verdict " + err.Error())
+	}
+	switch verdict.Action {
+	case "":
+		verdict.Action = constant.ModerationPass
+	case constant.ModerationPass, constant.ModerationBlock, constant.ModerationFlag:
+	case constant.ModerationModify:
+		if verdict.Message == nil {
+			return nil, sdkerrs.ErrArgs.WrapMsg("moderation verdict modify without the message")
+		}
+		keepIdentity(verdict.Message, msg)
+	default:
+		return nil, sdkerrs.ErrArgs.WrapMsg("unknown moderation action " + verdict.Action)
+	}
+	return &verdict, nil
+}
+
+// applyVerdict replaces the message for the modify action and keeps the verdict on it unless it passed.
+func applyVerdict(msg *sdk_struct.MsgStruct, verdict *sdk_struct.ModerationVerdict) {
+	if verdict.Action == constant.ModerationPass {
+		return
+	}
+	if verdict.Action == constant.ModerationModify {
+		*msg = *verdict.Message
+	}
+	if msg.AttachedInfoElem == nil {
+		msg.AttachedInfoElem = &sdk_struct.AttachedInfoElem{}
+	}
+	msg.AttachedInfoElem.Moderation = &sdk_struct.MsgModeration{Action: verdict.Action, Reason: verdict.Reason, Labels: verdict.Labels}
+}
+
+// moderateSend applies the verdict of the moderator to the message sent before it is stored, it fails when the
+// message is blocked or the moderator fails.
+func (c *Conversation) moderateSend(ctx context.Context, s *sdk_struct.MsgStruct) error {
+	moderator := c.moderator()
+	if moderator == nil {
+		return nil
+	}
+	verdict, err := moderate(ctx, moderator, s, false)
+	if err != nil {
+		return sdkerrs.ErrMsgModerationBlocked.WrapMsg(err.Error(), "clientMsgID", s.ClientMsgID)
+	}
+	if verdict.Action == constant.ModerationBlock {
+		return sdkerrs.ErrMsgModerationBlocked.WrapMsg(verdict.Reason, "clientMsgID", s.ClientMsgID, "labels", strings.Join(verdict.Labels, ","))
+	}
+	applyVerdict(s, verdict)
+	log.ZDebug(ctx, "message sent moderated", "clientMsgID", s.ClientMsgID, "action", verdict.Action)
+	return nil
+}
+
+// moderateReceived applies the verdict of the moderator to the message received from another user before it is
+// stored, when it is asked. The message passes when the moderator fails.
+func (c *Conversation) moderateReceived(ctx context.Context, msg *sdk_struct.MsgStruct) {
+	if msg.SendID == c.loginUserID || !c.moderation.received.Load() {
+		return
+	}
+	moderator := c.moderator()
+	if moderator == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeoutCause(ctx, receivedModerationTimeout, errs.New("moderation timeout"))
+	defer cancel()
+	verdict, err := moderate(ctx, moderator, msg, true)
+	if err != nil {
+		log.ZWarn(ctx, "moderate the message received failed, it passes", err, "clientMsgID", msg.ClientMsgID)
+		return
+	}
+	applyVerdict(msg, verdict)
+	if verdict.Action == constant.ModerationBlock {
+		for _, text := range messageTexts(msg) {
+			*text = ""
+		}
+	}
+	if verdict.Action != constant.ModerationPass {
+		log.ZDebug(ctx, "message received moderated", "clientMsgID", msg.ClientMsgID, "action", verdict.Action)
+	}
+}
+
+// moderateCallback is handed to the moderator, the first verdict or error counts.
+type moderateCallback struct {
+	once sync.Once
+	done chan struct{}
+	data string
+	err  error
+}
+
+func (m *moderateCallback) OnError(errCode int32, errMsg string) {
+	m.once.Do(func() {
+		m.err = errs.NewCodeError(int(errCode), errMsg).Wrap()
+		close(m.done)
+	})
+}
+
+func (m *moderateCallback) OnSuccess(data string) {
+	m.once.Do(func() {
+		m.data = data
+		close(m.done)
+	})
+}
@@ -0,0 +1,69 @@
+package conversation_msg
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+type testModerator struct {
+	verdict sdk_struct.ModerationVerdict
+	async   bool
+}
+
+func (m *testModerator) Moderate(message string, received bool, callback open_im_sdk_callback.Base) string {
+	if m.async {
+		go callback.OnSuccess(utils.StructToJsonString(m.verdict))
+		return ""
+	}
+	return utils.StructToJsonString(m.verdict)
+}
+
+func TestModeration(t *testing.T) {
+	ctx := context.Background()
+	m := &testModerator{}
+	c := &Conversation{loginUserID: "me"}
+	c.SetModerator(func() open_im_sdk_callback.Moderator { return m })
+	text := func(content string) *sdk_struct.MsgStruct {
+		return &sdk_struct.MsgStruct{ClientMsgID: "1", SendID: "other", ContentType: constant.Text, TextElem: &sdk_struct.TextElem{Content: content}}
+	}
+	s := text("hi")
+	if err := c.moderateSend(ctx, s); err != nil || s.AttachedInfoElem != nil {
+		t.Fatal(s, err)
+	}
+	m.verdict = sdk_struct.ModerationVerdict{Action: constant.ModerationBlock, Reason: "spam"}
+	if err := c.moderateSend(ctx, text("buy")); !sdkerrs.ErrMsgModerationBlocked.Is(err) {
+		t.Fatal(err)
+	}
+	m.async = true
+	m.verdict = sdk_struct.ModerationVerdict{Action: constant.ModerationModify, Message: &sdk_struct.MsgStruct{ClientMsgID: "moved",
+		ContentType: constant.Text, TextElem: &sdk_struct.TextElem{Content: "hello"}}}
+	s = text("hi")
+	if err := c.moderateSend(ctx, s); err != nil || s.TextElem.Content != "hello" || s.ClientMsgID != "1" ||
+		s.AttachedInfoElem.Moderation.Action != constant.ModerationModify {
+		t.Fatal(s, err)
+	}
+	// the messages received are only moderated when it is asked
+	m.verdict = sdk_struct.ModerationVerdict{Action: constant.ModerationBlock}
+	received := text("bad")
+	c.moderateReceived(ctx, received)
+	if received.TextElem.Content != "bad" {
+		t.Fatal(received)
+	}
+	c.SetModerateReceived(true)
+	c.moderateReceived(ctx, received)
+	if received.TextElem.Content != "" || received.AttachedInfoElem.Moderation.Action != constant.ModerationBlock {
+		t.Fatal(received)
+	}
+	m.verdict = sdk_struct.ModerationVerdict{Action: constant.ModerationFlag, Labels: []string{"rude"}}
+	received = text("meh")
+	c.moderateReceived(ctx, received)
+	if received.TextElem.Content != "meh" || received.AttachedInfoElem.Moderation.Labels[0] != "rude" {
+		t.Fatal(received)
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversation_msg
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+const DefaultMsgWriteBatchSize = 500
+
+func CheckMsgWriteBatchSize(size int) error {
+	if size < 0 {
+		return sdkerrs.ErrArgs.WrapMsg("msg write batch size is negative")
+	}
+	return nil
+}
+
+// SetMsgWriteBatchSize sets the messages written per transaction while syncing, 0 is the default.
+func (c *Conversation) SetMsgWriteBatchSize(size int) {
+	if size == 0 {
+		size = DefaultMsgWriteBatchSize
+	}
+	c.msgWriteBatchSize = size
+}
+
+// msgWriteStats counts the writes of the synced messages since login.
+type msgWriteStats struct {
+	lock          sync.Mutex
+	messages      int64
+	conversations int64
+	transactions  int64
+	duration      time.Duration
+}
+
+func (s *msgWriteStats) add(messages, conversations, transactions int, duration time.Duration) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.messages += int64(messages)
+	s.conversations += int64(conversations)
+	s.transactions += int64(transactions)
+	s.duration += duration
+}
+
+// GetMsgWriteStats returns the messages of the sync written to the local database since login, in how many
+// transactions and how fast.
+func (c *Conversation) GetMsgWriteStats(_ context.Context) (*sdk_struct.MsgWriteStats, error) {
+	s := &c.msgWriteStats
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	stats := &sdk_struct.MsgWriteStats{
+		Messages:      s.messages,
+		Conversations: s.conversations,
+		Transactions:  s.transactions,
+		Duration:      s.duration.Milliseconds(),
+	}
+	if s.duration > 0 {
+		stats.MessagesPerSecond = float64(s.messages) / s.duration.Seconds()
+	}
+	return stats, nil
+}
+
+// storeSyncedMessages writes the new messages of the conversations in transactions of about
+// msgWriteBatchSize messages, the messages of a conversation are not split. storeConversations writes the
+// conversations in the last transaction, along with their latest messages. Nothing run in the transactions
+// calls the listeners, the calls of the app to the sdk would wait for the transaction.
+func (c *Conversation) storeSyncedMessages(ctx context.Context, insertMsg map[string][]*model_struct.LocalChatLog,
+	storeConversations func(ctx context.Context)) {
+	start := time.Now()
+	conversationIDs := make([]string, 0, len(insertMsg))
+	for conversationID, msgs := range insertMsg {
+		if len(msgs) > 0 {
+			conversationIDs = append(conversationIDs, conversationID)
+		}
+	}
+	sort.Strings(conversationIDs)
+	conversations := len(conversationIDs)
+	batchSize := c.msgWriteBatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultMsgWriteBatchSize
+	}
+	var messages, transactions int
+	for len(conversationIDs) > 0 || transactions == 0 {
+		batch := make(map[string][]*model_struct.LocalChatLog)
+		var n int
+		for len(conversationIDs) > 0 && (n == 0 || n+len(insertMsg[conversationIDs[0]]) <= batchSize) {
+			conversationID := conversationIDs[0]
+			conversationIDs = conversationIDs[1:]
+			batch[conversationID] = insertMsg[conversationID]
+			n += len(insertMsg[conversationID])
+		}
+		last := len(conversationIDs) == 0
+		err := c.db.Transaction(ctx, func(ctx context.Context) error {
+			if err := c.batchInsertMessageList(ctx, batch); err != nil {
+				return err
+			}
+			if last && storeConversations != nil {
+				storeConversations(ctx)
+			}
+			return nil
+		})
+		transactions++
+		if err != nil {
+			log.ZError(ctx, "store synced messages failed", err, "messages", n)
+			if last && storeConversations != nil {
+				// the messages of the batch are rolled back, the conversations are kept
+				_ = c.db.Transaction(ctx, func(ctx context.Context) error {
+					storeConversations(ctx)
+					return nil
+				})
+				transactions++
+			}
+			continue
+		}
+		messages += n
+	}
+	c.msgWriteStats.add(messages, conversations, transactions, time.Since(start))
+	log.ZDebug(ctx, "store synced messages", "messages", messages, "transactions", transactions, "cost", time.Since(start))
+}
@@ -23,15 +23,18 @@ import (
 	"sync"
 	"time"
 
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/audit"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/common"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/errreport"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/syncer"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
 	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
 
 	"github.com/openimsdk/protocol/sdkws"
 	"github.com/openimsdk/tools/errs"
-	"github.com/openimsdk/tools/log"
 )
 
 const (
@@ -73,16 +76,19 @@ func (c *Conversation) syncFlag(c2v common.Cmd2Value) {
 		c.startTime = time.Now()
 		c.ConversationListener().OnSyncServerStart(true)
 		c.ConversationListener().OnSyncServerProgress(1)
+		c.initSyncProgress = newSyncProgress(c.syncProgressListener)
+		ctx := syncer.WithProgress(ctx, c.initSyncProgress)
 		asyncWaitFunctions := []func(c context.Context) error{
 			c.group.SyncAllJoinedGroupsAndMembersWithLock,
-			c.relation.IncrSyncFriends,
+			c.syncPhase(constant.SyncPhaseFriends, c.relation.IncrSyncFriends),
 		}
 		runSyncFunctions(ctx, asyncWaitFunctions, asyncWait)
 		c.addInitProgress(InitSyncProgress * 4 / 10)              // add 40% of InitSyncProgress as progress
 		c.ConversationListener().OnSyncServerProgress(c.progress) // notify server current Progress
 
+		// the conversations sync after the groups and the friends they refer to
 		syncWaitFunctions := []func(c context.Context) error{
-			c.IncrSyncConversations,
+			c.syncPhase(constant.SyncPhaseConversations, c.IncrSyncConversations),
 			c.SyncAllConversationHashReadSeqs,
 		}
 		runSyncFunctions(ctx, syncWaitFunctions, syncWait)
@@ -93,6 +99,11 @@ func (c *Conversation) syncFlag(c2v common.Cmd2Value) {
 		asyncNoWaitFunctions := []func(c context.Context) error{
 			c.user.SyncLoginUserInfoWithoutNotice,
 			c.relation.SyncAllBlackListWithoutNotice,
+			c.user.SyncPrivacySettings,
+			c.user.SyncClientConfig,
+			c.syncModules,
+			c.customerService.SyncSessions,
+			audit.Upload,
 		}
 		runSyncFunctions(ctx, asyncNoWaitFunctions, asyncNoWait)
 
@@ -100,6 +111,8 @@ func (c *Conversation) syncFlag(c2v common.Cmd2Value) {
 		log.ZDebug(ctx, "AppDataSyncFinish", "time", time.Since(c.startTime).Milliseconds())
 		c.progress = 100
 		c.ConversationListener().OnSyncServerProgress(c.progress)
+		c.initSyncProgress.finish()
+		c.initSyncProgress = nil
 		c.ConversationListener().OnSyncServerFinish(true)
 	case constant.MsgSyncBegin:
 		log.ZDebug(ctx, "MsgSyncBegin")
@@ -117,6 +130,7 @@ func (c *Conversation) doNotificationManager(c2v common.Cmd2Value) {
 	ctx := c2v.Ctx
 	allMsg := c2v.Value.(sdk_struct.CmdNewMsgComeToConversation).Msgs
 
+	notificationSeqs := make(map[string]int64, len(allMsg))
 	for conversationID, msgs := range allMsg {
 		log.ZDebug(ctx, "notification handling", "conversationID", conversationID, "msgs", msgs)
 
@@ -128,6 +142,16 @@ func (c *Conversation) doNotificationManager(c2v common.Cmd2Value) {
 				c.user.DoNotification(ctx, msg)
 			} else if msg.ContentType > constant.GroupNotificationBegin && msg.ContentType < constant.GroupNotificationEnd {
 				c.group.DoNotification(ctx, msg)
+			} else if msg.ContentType > constant.MomentNotificationBegin && msg.ContentType < constant.MomentNotificationEnd {
+				if c.moments != nil {
+					c.moments.DoNotification(ctx, msg)
+				}
+			} else if msg.ContentType > constant.OrganizationNotificationBegin && msg.ContentType < constant.OrganizationNotificationEnd {
+				if c.organization != nil {
+					c.organization.DoNotification(ctx, msg)
+				}
+			} else if msg.ContentType > constant.CustomerServiceNotificationBegin && msg.ContentType < constant.CustomerServiceNotificationEnd {
+				c.customerService.DoNotification(ctx, msg)
 			} else {
 				c.DoNotification(ctx, msg)
 			}
@@ -138,19 +162,30 @@ func (c *Conversation) doNotificationManager(c2v common.Cmd2Value) {
 			lastMsg := msgs.Msgs[len(msgs.Msgs)-1]
 			log.ZDebug(ctx, "SetNotificationSeq", "conversationID", conversationID, "seq", lastMsg.Seq)
 			if lastMsg.Seq != 0 {
-				if err := c.db.SetNotificationSeq(ctx, conversationID, lastMsg.Seq); err != nil {
-					// Log an error if setting the sequence number fails
-					log.ZError(ctx, "SetNotificationSeq err", err, "conversationID", conversationID, "lastMsg", lastMsg)
-				}
+				notificationSeqs[conversationID] = lastMsg.Seq
 			}
 		}
 	}
 
+	// the seqs of all the conversations are committed at once
+	err := c.db.Transaction(ctx, func(ctx context.Context) error {
+		for conversationID, seq := range notificationSeqs {
+			if err := c.db.SetNotificationSeq(ctx, conversationID, seq); err != nil {
+				// Log an error if setting the sequence number fails
+				log.ZError(ctx, "SetNotificationSeq err", err, "conversationID", conversationID, "seq", seq)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		errreport.Report(ctx, errreport.SourceSync, "store notification seqs", err, "notificationSeqs", notificationSeqs)
+	}
+
 }
 
 func (c *Conversation) DoNotification(ctx context.Context, msg *sdkws.MsgData) {
 	if err := c.doNotification(ctx, msg); err != nil {
-		log.ZWarn(ctx, "DoConversationNotification failed", err)
+		errreport.Report(ctx, errreport.SourceNotification, "DoConversationNotification", err, "contentType", msg.ContentType)
 	}
 }
 
@@ -168,6 +203,8 @@ func (c *Conversation) doNotification(ctx context.Context, msg *sdkws.MsgData) e
 		return c.doClearConversations(ctx, msg)
 	case constant.DeleteMsgsNotification:
 		return c.doDeleteMsgs(ctx, msg)
+	case constant.InteractiveCardUpdatedNotification:
+		return c.doInteractiveCardUpdated(ctx, msg)
 	case constant.HasReadReceipt: // 2200
 		return c.doReadDrawing(ctx, msg)
 	}
@@ -198,10 +235,11 @@ func (c *Conversation) doUpdateConversation(c2v common.Cmd2Value) {
 			if lc.LatestMsgSendTime >= oc.LatestMsgSendTime || c.getConversationLatestMsgClientID(lc.LatestMsg) == c.getConversationLatestMsgClientID(oc.LatestMsg) { // The session update of asynchronous messages is subject to the latest sending time
 				err := c.db.UpdateColumnsConversation(ctx, node.ConID, map[string]interface{}{"latest_msg_send_time": lc.LatestMsgSendTime, "latest_msg": lc.LatestMsg})
 				if err != nil {
-					log.ZError(ctx, "updateConversationLatestMsgModel", err, "conversationID", node.ConID)
+					errreport.Report(ctx, errreport.SourceConversation, "update conversation latest message", err, "conversationID", node.ConID)
 				} else {
 					oc.LatestMsgSendTime = lc.LatestMsgSendTime
 					oc.LatestMsg = lc.LatestMsg
+					c.unread.set(oc)
 					list = append(list, oc)
 					data := utils.StructToJsonString(list)
 					log.ZInfo(ctx, "OnConversationChanged", "data", data)
@@ -212,19 +250,25 @@ func (c *Conversation) doUpdateConversation(c2v common.Cmd2Value) {
 			log.ZDebug(ctx, "new conversation", "lc", lc)
 			err4 := c.db.InsertConversation(ctx, &lc)
 			if err4 != nil {
-				log.ZWarn(ctx, "insert new conversation err", err4)
+				errreport.Report(ctx, errreport.SourceConversation, "insert new conversation", err4, "conversationID", lc.ConversationID)
 			} else {
+				c.unread.set(&lc)
 				list = append(list, &lc)
 				c.ConversationListener().OnNewConversation(utils.StructToJsonString(list))
 			}
 		}
 
 	case constant.TotalUnreadMessageChanged:
-		totalUnreadCount, err := c.db.GetTotalUnreadMsgCountDB(ctx)
+		// the conversations whose unread counts changed, all of them when none
+		conversationIDs, _ := node.Args.([]string)
+		if err := c.refreshUnread(ctx, conversationIDs); err != nil {
+			log.ZWarn(ctx, "refreshUnread err", err, "conversationIDs", conversationIDs)
+		}
+		totalUnreadCount, err := c.totalUnread(ctx)
 		if err != nil {
-			log.ZWarn(ctx, "GetTotalUnreadMsgCountDB err", err)
+			log.ZWarn(ctx, "totalUnread err", err)
 		} else {
-			c.ConversationListener().OnTotalUnreadMessageCountChanged(totalUnreadCount)
+			c.totalUnreadMessageCountChanged(ctx, totalUnreadCount)
 		}
 	case constant.UpdateConFaceUrlAndNickName:
 		var lc model_struct.LocalConversation
@@ -262,12 +306,11 @@ func (c *Conversation) doUpdateConversation(c2v common.Cmd2Value) {
 
 	case constant.UpdateLatestMessageReadState:
 		conversationID := node.ConID
-		var latestMsg sdk_struct.MsgStruct
 		l, err := c.db.GetConversation(ctx, conversationID)
 		if err != nil {
 			log.ZError(ctx, "getConversationLatestMsgModel err", err, "conversationID", conversationID)
 		} else {
-			err := json.Unmarshal([]byte(l.LatestMsg), &latestMsg)
+			latestMsg, err := c.parseLatestMsg(l)
 			if err != nil {
 				log.ZError(ctx, "latestMsg,Unmarshal err", err)
 			} else {
@@ -289,8 +332,7 @@ func (c *Conversation) doUpdateConversation(c2v common.Cmd2Value) {
 				log.ZWarn(ctx, "getConversation err", err)
 				return
 			}
-			var latestMsg sdk_struct.MsgStruct
-			err = json.Unmarshal([]byte(lc.LatestMsg), &latestMsg)
+			latestMsg, err := c.parseLatestMsg(lc)
 			if err != nil {
 				log.ZError(ctx, "latestMsg,Unmarshal err", err)
 			} else {
@@ -322,6 +364,7 @@ func (c *Conversation) doUpdateConversation(c2v common.Cmd2Value) {
 		if err != nil {
 			log.ZError(ctx, "getMultipleConversationModel err", err)
 		} else {
+			c.unread.set(conversations...)
 			var newCList []*model_struct.LocalConversation
 			for _, v := range conversations {
 				if v.LatestMsgSendTime != 0 {
@@ -425,10 +468,14 @@ func (c *Conversation) syncData(c2v common.Cmd2Value) {
 	// Asynchronous sync functions
 	asyncFuncs := []func(c context.Context) error{
 		c.user.SyncLoginUserInfo,
+		c.user.SyncClientConfig,
 		c.relation.SyncAllBlackList,
 		c.group.SyncAllJoinedGroupsAndMembersWithLock,
 		c.relation.IncrSyncFriendsWithLock,
 		c.IncrSyncConversationsWithLock,
+		c.syncModules,
+		c.customerService.SyncSessions,
+		audit.Upload,
 	}
 
 	runSyncFunctions(ctx, asyncFuncs, asyncNoWait)
@@ -464,7 +511,7 @@ func executeSyncFunction(ctx context.Context, fn func(c context.Context) error,
 	err := fn(ctx)
 	duration := time.Since(startTime)
 	if err != nil {
-		log.ZWarn(ctx, fmt.Sprintf("%s sync error", funcName), err, "duration", duration.Seconds())
+		errreport.Report(ctx, errreport.SourceSync, funcName, err, "duration", duration.Seconds())
 	} else {
 		log.ZDebug(ctx, fmt.Sprintf("%s completed successfully", funcName), "duration", duration.Seconds())
 	}
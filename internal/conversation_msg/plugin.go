@@ -0,0 +1,157 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversation_msg
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/db_interface"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+func (c *Conversation) SetMessagePlugins(messagePlugins func() []open_im_sdk_callback.MessagePlugin) {
+	c.messagePlugins = messagePlugins
+}
+
+func (c *Conversation) plugins() []open_im_sdk_callback.MessagePlugin {
+	if c.messagePlugins == nil {
+		return nil
+	}
+	return c.messagePlugins()
+}
+
+// rewrite passes the message in json through the hook of each plugin.
+func rewrite(plugins []open_im_sdk_callback.MessagePlugin, data string, hook func(p open_im_sdk_callback.MessagePlugin, data string) (string, error)) (string, error) {
+	for _, p := range plugins {
+		rewritten, err := hook(p, data)
+		if err != nil {
+			return "", err
+		}
+		if rewritten != "" {
+			data = rewritten
+		}
+	}
+	return data, nil
+}
+
+// keepIdentity restores the fields identifying the message and its conversation, the plugins can not move it.
+func keepIdentity(rewritten, s *sdk_struct.MsgStruct) {
+	rewritten.ClientMsgID, rewritten.ServerMsgID, rewritten.Seq = s.ClientMsgID, s.ServerMsgID, s.Seq
+	rewritten.SessionType, rewritten.SendID, rewritten.RecvID, rewritten.GroupID = s.SessionType, s.SendID, s.RecvID, s.GroupID
+}
+
+// beforeSend returns the message sent to the server as the plugins rewrote it, s itself without a plugin.
+func (c *Conversation) beforeSend(ctx context.Context, s *sdk_struct.MsgStruct) (*sdk_struct.MsgStruct, error) {
+	plugins := c.plugins()
+	if len(plugins) == 0 {
+		return s, nil
+	}
+	data, err := rewrite(plugins, utils.StructToJsonString(s), func(p open_im_sdk_callback.MessagePlugin, data string) (string, error) {
+		return p.BeforeSend(data)
+	})
+	if err != nil {
+		return nil, sdkerrs.ErrMsgPluginRejected.WrapMsg(err.Error(), "clientMsgID", s.ClientMsgID)
+	}
+	var wire sdk_struct.MsgStruct
+	if err := json.Unmarshal([]byte(data), &wire); err != nil {
+		return nil, sdkerrs.ErrSdkInternal.WrapMsg("message plugin returned an invalid message: " + err.Error())
+	}
+	keepIdentity(&wire, s)
+	log.ZDebug(ctx, "message rewritten before send", "clientMsgID", s.ClientMsgID)
+	return &wire, nil
+}
+
+// afterSend tells the plugins the result of the send of s.
+func (c *Conversation) afterSend(s *sdk_struct.MsgStruct, err error) {
+	plugins := c.plugins()
+	if len(plugins) == 0 || s == nil {
+		return
+	}
+	data := utils.StructToJsonString(s)
+	var errMsg string
+	if err != nil {
+		errMsg = err.Error()
+	}
+	for _, p := range plugins {
+		p.AfterSend(data, sdkerrs.Code(err), errMsg)
+	}
+}
+
+// onReceive returns the message received as the plugins rewrote it, msg itself without a plugin or when a
+// plugin returned an invalid message.
+func (c *Conversation) onReceive(ctx context.Context, msg *sdk_struct.MsgStruct) *sdk_struct.MsgStruct {
+	plugins := c.plugins()
+	if len(plugins) == 0 {
+		return msg
+	}
+	data, _ := rewrite(plugins, utils.StructToJsonString(msg), func(p open_im_sdk_callback.MessagePlugin, data string) (string, error) {
+		return p.OnReceive(data), nil
+	})
+	var received sdk_struct.MsgStruct
+	if err := json.Unmarshal([]byte(data), &received); err != nil {
+		log.ZWarn(ctx, "message plugin returned an invalid message, it is ignored", err, "clientMsgID", msg.ClientMsgID)
+		return msg
+	}
+	keepIdentity(&received, msg)
+	return &received
+}
+
+// beforeStore returns the messages written to the database as the plugins rewrote them, copies so that the
+// callers keep theirs.
+func (c *Conversation) beforeStore(ctx context.Context, messages []*model_struct.LocalChatLog) []*model_struct.LocalChatLog {
+	plugins := c.plugins()
+	if len(plugins) == 0 {
+		return messages
+	}
+	stored := make([]*model_struct.LocalChatLog, 0, len(messages))
+	for _, message := range messages {
+		data, _ := rewrite(plugins, utils.StructToJsonString(message), func(p open_im_sdk_callback.MessagePlugin, data string) (string, error) {
+			return p.BeforeStore(data), nil
+		})
+		var rewritten model_struct.LocalChatLog
+		if err := json.Unmarshal([]byte(data), &rewritten); err != nil {
+			log.ZWarn(ctx, "message plugin returned an invalid message, it is ignored", err, "clientMsgID", message.ClientMsgID)
+			stored = append(stored, message)
+			continue
+		}
+		rewritten.ClientMsgID, rewritten.ServerMsgID, rewritten.Seq = message.ClientMsgID, message.ServerMsgID, message.Seq
+		stored = append(stored, &rewritten)
+	}
+	return stored
+}
+
+// pluginDataBase has the messages written by the conversation pass through the BeforeStore of the plugins.
+type pluginDataBase struct {
+	db_interface.DataBase
+	c *Conversation
+}
+
+func (d *pluginDataBase) InsertMessage(ctx context.Context, conversationID string, message *model_struct.LocalChatLog) error {
+	return d.DataBase.InsertMessage(ctx, conversationID, d.c.beforeStore(ctx, []*model_struct.LocalChatLog{message})[0])
+}
+
+func (d *pluginDataBase) BatchInsertMessageList(ctx context.Context, conversationID string, messages []*model_struct.LocalChatLog) error {
+	return d.DataBase.BatchInsertMessageList(ctx, conversationID, d.c.beforeStore(ctx, messages))
+}
+
+func (d *pluginDataBase) UpdateMessage(ctx context.Context, conversationID string, message *model_struct.LocalChatLog) error {
+	return d.DataBase.UpdateMessage(ctx, conversationID, d.c.beforeStore(ctx, []*model_struct.LocalChatLog{message})[0])
+}
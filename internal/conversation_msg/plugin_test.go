@@ -0,0 +1,74 @@
+package conversation_msg
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+type testPlugin struct {
+	suffix string
+	reject bool
+	sent   []int32
+}
+
+func (p *testPlugin) rewrite(message string) string {
+	var m map[string]any
+	_ = json.Unmarshal([]byte(message), &m)
+	m["content"] = m["content"].(string) + p.suffix
+	m["clientMsgID"] = "moved"
+	data, _ := json.Marshal(m)
+	return string(data)
+}
+
+func (p *testPlugin) BeforeSend(message string) (string, error) {
+	if p.reject {
+		return "", errors.New("rejected")
+	}
+	return p.rewrite(message), nil
+}
+
+func (p *testPlugin) AfterSend(message string, errCode int32, errMsg string) {
+	p.sent = append(p.sent, errCode)
+}
+
+func (p *testPlugin) OnReceive(message string) string {
+	return p.rewrite(message)
+}
+
+func (p *testPlugin) BeforeStore(message string) string {
+	return ""
+}
+
+func TestMessagePlugins(t *testing.T) {
+	a, b := &testPlugin{suffix: "a"}, &testPlugin{suffix: "b"}
+	c := &Conversation{}
+	c.SetMessagePlugins(func() []open_im_sdk_callback.MessagePlugin { return []open_im_sdk_callback.MessagePlugin{a, b} })
+	s := &sdk_struct.MsgStruct{ClientMsgID: "1", Content: "x"}
+	wire, err := c.beforeSend(context.Background(), s)
+	if err != nil || wire.Content != "xab" || wire.ClientMsgID != "1" || s.Content != "x" {
+		t.Fatal(wire, err)
+	}
+	if received := c.onReceive(context.Background(), s); received.Content != "xab" || received.ClientMsgID != "1" {
+		t.Fatal(received)
+	}
+	// an empty result keeps the message
+	stored := []*model_struct.LocalChatLog{{ClientMsgID: "1", Content: "x"}}
+	if got := c.beforeStore(context.Background(), stored); got[0].Content != "x" {
+		t.Fatal(got[0])
+	}
+	c.afterSend(s, sdkerrs.ErrNetwork)
+	if len(a.sent) != 1 || a.sent[0] != sdkerrs.NetworkError {
+		t.Fatal(a.sent)
+	}
+	b.reject = true
+	if _, err := c.beforeSend(context.Background(), s); !sdkerrs.ErrMsgPluginRejected.Is(err) {
+		t.Fatal(err)
+	}
+}
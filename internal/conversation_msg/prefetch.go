@@ -0,0 +1,127 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversation_msg
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/network"
+	sdk "github.com/openimsdk/openim-sdk-core/v3/pkg/sdk_params_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+	"github.com/openimsdk/tools/utils/datautil"
+)
+
+const (
+	// historyPrefetchConcurrency is the most history pages prefetched at the same time, the others are skipped.
+	historyPrefetchConcurrency = 2
+	// historyPrefetchMaxCount is the most messages of a page prefetched, the pages asked for larger are
+	// prefetched in part.
+	historyPrefetchMaxCount = 100
+	// historyPrefetchTimeout bounds the pull of a page prefetched.
+	historyPrefetchTimeout = 30 * time.Second
+)
+
+// historyPrefetcher pulls the page of history older than the one returned to the app in the background, so
+// that the next page is read from the local database.
+type historyPrefetcher struct {
+	slots chan struct{}
+	lock  sync.Mutex
+	// running are the conversations with a page being prefetched
+	running map[string]struct{}
+}
+
+func newHistoryPrefetcher() *historyPrefetcher {
+	return &historyPrefetcher{slots: make(chan struct{}, historyPrefetchConcurrency), running: make(map[string]struct{})}
+}
+
+// acquire takes a slot for the conversation, false when none is free or the conversation has one already.
+func (p *historyPrefetcher) acquire(conversationID string) bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if _, ok := p.running[conversationID]; ok {
+		return false
+	}
+	select {
+	case p.slots <- struct{}{}:
+	default:
+		return false
+	}
+	p.running[conversationID] = struct{}{}
+	return true
+}
+
+func (p *historyPrefetcher) release(conversationID string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	delete(p.running, conversationID)
+	<-p.slots
+}
+
+// prefetchSeqs are the seqs of the page older than the messages returned, from the oldest seq among them down
+// to minSeq.
+func prefetchSeqs(messages []*sdk_struct.MsgStruct, count int, minSeq int64) []int64 {
+	var oldest int64
+	for _, message := range messages {
+		if message.Seq > 0 && (oldest == 0 || message.Seq < oldest) {
+			oldest = message.Seq
+		}
+	}
+	if oldest == 0 {
+		return nil
+	}
+	count = min(count, historyPrefetchMaxCount)
+	var seqs []int64
+	for seq := oldest - 1; seq >= max(minSeq, 1) && len(seqs) < count; seq-- {
+		seqs = append(seqs, seq)
+	}
+	return seqs
+}
+
+// prefetchHistory pulls the messages of the next page of history missing locally in the background, unless
+// the budget of the prefetches is spent or the network is metered.
+func (c *Conversation) prefetchHistory(ctx context.Context, conversationID string, result *sdk.GetAdvancedHistoryMessageListCallback, count int) {
+	if result.IsEnd || len(result.MessageList) == 0 || network.Deferred(constant.MeteredBackfill) || !c.LongConnMgr.IsConnected() {
+		return
+	}
+	seqs := prefetchSeqs(result.MessageList, count, c.getConversationMinSeq(ctx, conversationID))
+	if len(seqs) == 0 || !c.prefetcher.acquire(conversationID) {
+		return
+	}
+	go func() {
+		defer c.prefetcher.release(conversationID)
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), historyPrefetchTimeout)
+		defer cancel()
+		local, err := c.db.GetMessagesBySeqs(ctx, conversationID, seqs)
+		if err != nil {
+			log.ZWarn(ctx, "get messages to prefetch failed", err, "conversationID", conversationID)
+			return
+		}
+		missing := datautil.SliceSub(seqs, datautil.Slice(local, func(message *model_struct.LocalChatLog) int64 {
+			return message.Seq
+		}))
+		if len(missing) == 0 {
+			return
+		}
+		log.ZDebug(ctx, "prefetch history", "conversationID", conversationID, "seqs", missing)
+		// the messages pulled are stored, the list and the callback are not the ones of the app
+		var list []*model_struct.LocalChatLog
+		c.fetchAndMergeMissingMessages(ctx, conversationID, missing, false, len(missing), 0, &list, &sdk.GetAdvancedHistoryMessageListCallback{})
+	}()
+}
@@ -0,0 +1,39 @@
+package conversation_msg
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+func TestPrefetchSeqs(t *testing.T) {
+	messages := []*sdk_struct.MsgStruct{{Seq: 0}, {Seq: 12}, {Seq: 10}, {Seq: 11}}
+	if seqs := prefetchSeqs(messages, 3, 1); !slices.Equal(seqs, []int64{9, 8, 7}) {
+		t.Fatal(seqs)
+	}
+	// not below the min seq of the conversation
+	if seqs := prefetchSeqs(messages, 3, 9); !slices.Equal(seqs, []int64{9}) {
+		t.Fatal(seqs)
+	}
+	if seqs := prefetchSeqs([]*sdk_struct.MsgStruct{{Seq: 0}}, 3, 1); seqs != nil {
+		t.Fatal(seqs)
+	}
+	if seqs := prefetchSeqs([]*sdk_struct.MsgStruct{{Seq: 1000}}, 500, 1); len(seqs) != historyPrefetchMaxCount {
+		t.Fatal(len(seqs))
+	}
+}
+
+func TestHistoryPrefetcherBudget(t *testing.T) {
+	p := newHistoryPrefetcher()
+	if !p.acquire("a") || p.acquire("a") {
+		t.Fatal("a conversation prefetched twice at the same time")
+	}
+	if !p.acquire("b") || p.acquire("c") {
+		t.Fatal("the concurrency is not bounded")
+	}
+	p.release("a")
+	if !p.acquire("c") {
+		t.Fatal("the slot released is not reused")
+	}
+}
@@ -19,20 +19,20 @@ import (
 	"encoding/json"
 
 	"github.com/openimsdk/openim-sdk-core/v3/internal/third/file"
+	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/db_interface"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
 	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
-
-	"github.com/openimsdk/tools/log"
 )
 
-func NewUploadFileCallback(ctx context.Context, progress func(progress int), msg *sdk_struct.MsgStruct, conversationID string, db db_interface.DataBase) file.UploadFileCallback {
+func NewUploadFileCallback(ctx context.Context, callback open_im_sdk_callback.SendMsgCallBack, msg *sdk_struct.MsgStruct, conversationID string, db db_interface.DataBase) file.UploadFileCallback {
 	if msg.AttachedInfoElem == nil {
 		msg.AttachedInfoElem = &sdk_struct.AttachedInfoElem{}
 	}
 	if msg.AttachedInfoElem.Progress == nil {
 		msg.AttachedInfoElem.Progress = &sdk_struct.UploadProgress{}
 	}
-	return &msgUploadFileCallback{ctx: ctx, progress: progress, msg: msg, db: db, conversationID: conversationID}
+	return &msgUploadFileCallback{ctx: ctx, callback: callback, msg: msg, db: db, conversationID: conversationID}
 }
 
 type msgUploadFileCallback struct {
@@ -41,7 +41,7 @@ type msgUploadFileCallback struct {
 	msg            *sdk_struct.MsgStruct
 	conversationID string
 	value          int
-	progress       func(progress int)
+	callback       open_im_sdk_callback.SendMsgCallBack
 }
 
 func (c *msgUploadFileCallback) Open(size int64) {
@@ -70,6 +70,19 @@ func (c *msgUploadFileCallback) UploadID(uploadID string) {
 func (c *msgUploadFileCallback) UploadPartComplete(index int, partSize int64, partHash string) {
 }
 
+// OnUploadSpeed keeps the speed in the progress of the message, and passes it on to a send callback that
+// implements open_im_sdk_callback.UploadSpeedCallback.
+func (c *msgUploadFileCallback) OnUploadSpeed(speed string) {
+	var info sdk_struct.TransferSpeed
+	if err := json.Unmarshal([]byte(speed), &info); err == nil {
+		c.msg.AttachedInfoElem.Progress.Speed = info.Speed
+		c.msg.AttachedInfoElem.Progress.RemainingTime = info.RemainingTime
+	}
+	if speedCallback, ok := c.callback.(open_im_sdk_callback.UploadSpeedCallback); ok {
+		speedCallback.OnUploadSpeed(speed)
+	}
+}
+
 func (c *msgUploadFileCallback) UploadComplete(fileSize int64, streamSize int64, storageSize int64) {
 	c.msg.AttachedInfoElem.Progress.Save = storageSize
 	c.msg.AttachedInfoElem.Progress.Current = streamSize
@@ -84,13 +97,13 @@ func (c *msgUploadFileCallback) UploadComplete(fileSize int64, streamSize int64,
 	value := int(float64(streamSize) / float64(fileSize) * 100)
 	if c.value < value {
 		c.value = value
-		c.progress(value)
+		c.callback.OnProgress(value)
 	}
 }
 
 func (c *msgUploadFileCallback) Complete(size int64, url string, typ int) {
 	if c.value != 100 {
-		c.progress(100)
+		c.callback.OnProgress(100)
 	}
 	c.msg.AttachedInfoElem.Progress = nil
 	data, err := json.Marshal(c.msg.AttachedInfoElem)
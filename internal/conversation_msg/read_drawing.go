@@ -16,19 +16,18 @@ package conversation_msg
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/common"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
 	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
 	"github.com/openimsdk/tools/errs"
 	"github.com/openimsdk/tools/utils/datautil"
 
 	"github.com/openimsdk/protocol/sdkws"
-	"github.com/openimsdk/tools/log"
 )
 
 func (c *Conversation) getConversationMaxSeqAndSetHasRead(ctx context.Context, conversationID string) error {
@@ -74,13 +73,13 @@ func (c *Conversation) markConversationMessageAsRead(ctx context.Context, conver
 		msgIDs, seqs := c.getAsReadMsgMapAndList(ctx, msgs)
 		if len(seqs) == 0 {
 			log.ZWarn(ctx, "seqs is empty", nil, "conversationID", conversationID)
-			if err := c.markConversationAsReadServer(ctx, conversationID, maxSeq, seqs); err != nil {
+			if err := c.markSingleChatAsReadServer(ctx, conversationID, maxSeq, seqs); err != nil {
 				return err
 			}
 		} else {
 			log.ZDebug(ctx, "markConversationMessageAsRead", "conversationID", conversationID, "seqs",
 				seqs, "peerUserMaxSeq", peerUserMaxSeq, "maxSeq", maxSeq)
-			if err := c.markConversationAsReadServer(ctx, conversationID, maxSeq, seqs); err != nil {
+			if err := c.markSingleChatAsReadServer(ctx, conversationID, maxSeq, seqs); err != nil {
 				return err
 			}
 			_, err = c.db.MarkConversationMessageAsReadDB(ctx, conversationID, msgIDs)
@@ -105,7 +104,7 @@ func (c *Conversation) markConversationMessageAsRead(ctx context.Context, conver
 
 // mark a conversation's message as read by seqs
 func (c *Conversation) markMessagesAsReadByMsgID(ctx context.Context, conversationID string, msgIDs []string) error {
-	_, err := c.db.GetConversation(ctx, conversationID)
+	conversation, err := c.db.GetConversation(ctx, conversationID)
 	if err != nil {
 		return err
 	}
@@ -127,7 +126,9 @@ func (c *Conversation) markMessagesAsReadByMsgID(ctx context.Context, conversati
 		log.ZWarn(ctx, "seqs is empty", nil, "conversationID", conversationID)
 		return nil
 	}
-	if err := c.markMsgAsRead2Server(ctx, conversationID, seqs); err != nil {
+	if conversation.ConversationType == constant.SingleChatType && c.user.ReadReceiptDisabled(ctx) {
+		log.ZDebug(ctx, "read receipt disabled, only mark as read locally", "conversationID", conversationID, "seqs", seqs)
+	} else if err := c.markMsgAsRead2Server(ctx, conversationID, seqs); err != nil {
 		return err
 	}
 	decrCount, err := c.db.MarkConversationMessageAsReadDB(ctx, conversationID, markAsReadMsgIDs)
@@ -142,6 +143,15 @@ func (c *Conversation) markMessagesAsReadByMsgID(ctx context.Context, conversati
 	return nil
 }
 
+// markSingleChatAsReadServer does not send a read receipt to the peer when the login user has opted out of read receipts,
+// only the has read seq is synced to the other devices.
+func (c *Conversation) markSingleChatAsReadServer(ctx context.Context, conversationID string, hasReadSeq int64, seqs []int64) error {
+	if c.user.ReadReceiptDisabled(ctx) {
+		return c.setConversationHasReadSeq(ctx, conversationID, hasReadSeq)
+	}
+	return c.markConversationAsReadServer(ctx, conversationID, hasReadSeq, seqs)
+}
+
 func (c *Conversation) getAsReadMsgMapAndList(ctx context.Context,
 	msgs []*model_struct.LocalChatLog) (asReadMsgIDs []string, seqs []int64) {
 	for _, msg := range msgs {
@@ -166,7 +176,7 @@ func (c *Conversation) unreadChangeTrigger(ctx context.Context, conversationID s
 	}
 	c.doUpdateConversation(common.Cmd2Value{Value: common.UpdateConNode{ConID: conversationID,
 		Action: constant.ConChange, Args: []string{conversationID}}, Ctx: ctx})
-	c.doUpdateConversation(common.Cmd2Value{Value: common.UpdateConNode{Action: constant.TotalUnreadMessageChanged},
+	c.doUpdateConversation(common.Cmd2Value{Value: common.UpdateConNode{Action: constant.TotalUnreadMessageChanged, Args: []string{conversationID}},
 		Ctx: ctx})
 }
 
@@ -203,8 +213,8 @@ func (c *Conversation) doUnreadCount(ctx context.Context, conversation *model_st
 				return err
 			}
 		}
-		latestMsg := &sdk_struct.MsgStruct{}
-		if err := json.Unmarshal([]byte(conversation.LatestMsg), latestMsg); err != nil {
+		latestMsg, err := c.parseLatestMsg(conversation)
+		if err != nil {
 			log.ZError(ctx, "Unmarshal err", err, "conversationID", conversation.ConversationID, "latestMsg", conversation.LatestMsg)
 			return err
 		}
@@ -219,7 +229,7 @@ func (c *Conversation) doUnreadCount(ctx context.Context, conversation *model_st
 		}
 	}
 	c.doUpdateConversation(common.Cmd2Value{Value: common.UpdateConNode{ConID: conversation.ConversationID, Action: constant.ConChange, Args: []string{conversation.ConversationID}}})
-	c.doUpdateConversation(common.Cmd2Value{Value: common.UpdateConNode{Action: constant.TotalUnreadMessageChanged}})
+	c.doUpdateConversation(common.Cmd2Value{Value: common.UpdateConNode{Action: constant.TotalUnreadMessageChanged, Args: []string{conversation.ConversationID}}})
 
 	return nil
 }
@@ -249,8 +259,8 @@ func (c *Conversation) doReadDrawing(ctx context.Context, msg *sdkws.MsgData) er
 
 		}
 		if conversation.ConversationType == constant.SingleChatType {
-			latestMsg := &sdk_struct.MsgStruct{}
-			if err := json.Unmarshal([]byte(conversation.LatestMsg), latestMsg); err != nil {
+			latestMsg, err := c.parseLatestMsg(conversation)
+			if err != nil {
 				log.ZWarn(ctx, "Unmarshal err", err, "conversationID", tips.ConversationID, "latestMsg", conversation.LatestMsg)
 				return err
 			}
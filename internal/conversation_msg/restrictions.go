@@ -0,0 +1,108 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversation_msg
+
+import (
+	"context"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/ccontext"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+// checkRestrictions checks the restrictions asked for the message sent, only the pictures and the videos are
+// viewed once.
+func checkRestrictions(s *sdk_struct.MsgStruct, restrictions *sdk_struct.MsgRestrictions) error {
+	if restrictions != nil && restrictions.ViewOnce && s.ContentType != constant.Picture && s.ContentType != constant.Video {
+		return sdkerrs.ErrArgs.WrapMsg("only the pictures and the videos can be viewed once", "contentType", s.ContentType)
+	}
+	return nil
+}
+
+// applyRestrictions sets the restrictions of the send options on the attached info of the message sent, once
+// its conversation set the attached info.
+func applyRestrictions(ctx context.Context, s *sdk_struct.MsgStruct) {
+	options, ok := ccontext.GetSendMsgOptions(ctx)
+	if !ok || options.Restrictions == nil {
+		return
+	}
+	if s.AttachedInfoElem == nil {
+		s.AttachedInfoElem = &sdk_struct.AttachedInfoElem{}
+	}
+	restrictions := *options.Restrictions
+	s.AttachedInfoElem.Restrictions = &restrictions
+}
+
+// storedRestrictions returns the restrictions of the message as stored, the app can not drop them from the
+// message it passes. The ones of the message itself when it is not stored.
+func (c *Conversation) storedRestrictions(ctx context.Context, msg *sdk_struct.MsgStruct) *sdk_struct.MsgRestrictions {
+	if local, err := c.db.GetMessage(ctx, utils.GetConversationIDByMsg(msg), msg.ClientMsgID); err == nil {
+		if stored := LocalChatLogToMsgStruct(local); stored.AttachedInfoElem != nil {
+			return stored.AttachedInfoElem.Restrictions
+		}
+		return nil
+	}
+	if msg.AttachedInfoElem != nil {
+		return msg.AttachedInfoElem.Restrictions
+	}
+	return nil
+}
+
+// checkForwardable fails for the messages their senders did not let forward, the view-once ones included.
+func (c *Conversation) checkForwardable(ctx context.Context, messages ...*sdk_struct.MsgStruct) error {
+	for _, msg := range messages {
+		if r := c.storedRestrictions(ctx, msg); r != nil && (r.NoForward || r.ViewOnce) {
+			return sdkerrs.ErrMsgRestricted.WrapMsg("the message can not be forwarded", "clientMsgID", msg.ClientMsgID)
+		}
+	}
+	return nil
+}
+
+// OpenViewOnceMessage returns the view-once message received with its media, once: the urls of the media are
+// then removed from the stored message. The messages sent are returned as they are.
+func (c *Conversation) OpenViewOnceMessage(ctx context.Context, conversationID, clientMsgID string) (*sdk_struct.MsgStruct, error) {
+	local, err := c.db.GetMessage(ctx, conversationID, clientMsgID)
+	if err != nil {
+		return nil, err
+	}
+	msg := LocalChatLogToMsgStruct(local)
+	if msg.AttachedInfoElem == nil || msg.AttachedInfoElem.Restrictions == nil || !msg.AttachedInfoElem.Restrictions.ViewOnce {
+		return nil, sdkerrs.ErrArgs.WrapMsg("the message is not a view-once message", "clientMsgID", clientMsgID)
+	}
+	if msg.SendID == c.loginUserID {
+		return msg, nil
+	}
+	if msg.AttachedInfoElem.Viewed {
+		return nil, sdkerrs.ErrMsgRestricted.WrapMsg("the view-once message was viewed", "clientMsgID", clientMsgID)
+	}
+	viewed := *msg
+	attachedInfo := *msg.AttachedInfoElem
+	attachedInfo.Viewed = true
+	viewed.AttachedInfoElem = &attachedInfo
+	switch msg.ContentType {
+	case constant.Picture:
+		viewed.PictureElem = &sdk_struct.PictureElem{}
+	case constant.Video:
+		viewed.VideoElem = &sdk_struct.VideoElem{Duration: msg.VideoElem.Duration}
+	}
+	if err := c.db.UpdateMessage(ctx, conversationID, MsgStructToLocalChatLog(&viewed)); err != nil {
+		return nil, err
+	}
+	log.ZInfo(ctx, "view-once message opened", "conversationID", conversationID, "clientMsgID", clientMsgID)
+	return msg, nil
+}
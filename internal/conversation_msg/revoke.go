@@ -19,16 +19,18 @@ import (
 	"errors"
 	"time"
 
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/audit"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/common"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/i18n"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
 	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
 	"github.com/openimsdk/tools/errs"
 	"github.com/openimsdk/tools/utils/timeutil"
 
 	"github.com/openimsdk/protocol/sdkws"
-	"github.com/openimsdk/tools/log"
 )
 
 func (c *Conversation) doRevokeMsg(ctx context.Context, msg *sdkws.MsgData) error {
@@ -47,6 +49,10 @@ func (c *Conversation) revokeMessage(ctx context.Context, tips *sdkws.RevokeMsgT
 		log.ZError(ctx, "GetMessageBySeq failed", err, "tips", &tips)
 		return errs.Wrap(err)
 	}
+	if revokedMsg.LocalEx != "" &&
+		c.resolveConflict(ctx, constant.ConflictKindMessageRevoke, tips.ConversationID, revokedMsg.ClientMsgID, revokedMsg, tips) {
+		return nil
+	}
 
 	var revokerRole int32
 	var revokerNickname string
@@ -67,17 +73,17 @@ func (c *Conversation) revokeMessage(ctx context.Context, tips *sdkws.RevokeMsgT
 			return errs.Wrap(err)
 		}
 
-		groupMember, err := c.group.GetSpecifiedGroupMembersInfo(ctx, conversation.GroupID, []string{tips.RevokerUserID})
+		groupMembers, err := c.group.GetGroupMembersInfo(ctx, conversation.GroupID, []string{tips.RevokerUserID})
 		if err != nil {
-			log.ZError(ctx, "GetGroupMemberInfoByGroupIDUserID failed", err, "tips", &tips)
+			log.ZError(ctx, "GetGroupMembersInfo failed", err, "tips", &tips)
 			return errs.Wrap(err)
 		} else {
-			log.ZDebug(ctx, "revoker member name", "groupMember", groupMember)
-			if len(groupMember) == 0 {
-				revokerNickname = "unknown"
+			log.ZDebug(ctx, "revoker member name", "groupMembers", groupMembers)
+			if groupMember, ok := groupMembers[tips.RevokerUserID]; !ok {
+				revokerNickname = i18n.T(i18n.UnknownUser)
 			} else {
-				revokerRole = groupMember[0].RoleLevel
-				revokerNickname = groupMember[0].Nickname
+				revokerRole = groupMember.RoleLevel
+				revokerNickname = groupMember.Nickname
 			}
 		}
 	}
@@ -98,11 +104,13 @@ func (c *Conversation) revokeMessage(ctx context.Context, tips *sdkws.RevokeMsgT
 	// log.ZDebug(ctx, "callback revokeMessage", "m", m)
 	var n sdk_struct.NotificationElem
 	n.Detail = utils.StructToJsonString(m)
+	n.Tips = c.revokeTips(&m)
 	if err := c.db.UpdateMessageBySeq(ctx, tips.ConversationID, &model_struct.LocalChatLog{Seq: tips.Seq,
 		Content: utils.StructToJsonString(n), ContentType: constant.RevokeNotification}); err != nil {
 		log.ZError(ctx, "UpdateMessageBySeq failed", err, "tips", &tips)
 		return errs.Wrap(err)
 	}
+	c.invalidateTranslations(ctx, revokedMsg.ClientMsgID)
 	conversation, err := c.db.GetConversation(ctx, tips.ConversationID)
 	if err != nil {
 		log.ZError(ctx, "GetConversation failed", err, "tips", &tips)
@@ -206,6 +214,9 @@ func (c *Conversation) revokeOneMessage(ctx context.Context, conversationID, cli
 	if message.Status != constant.MsgStatusSendSuccess {
 		return errors.New("only send success message can be revoked")
 	}
+	if err := checkRevokeWindow(ctx, message, c.loginUserID); err != nil {
+		return err
+	}
 	switch conversation.ConversationType {
 	case constant.SingleChatType:
 		if message.SendID != c.loginUserID {
@@ -243,5 +254,8 @@ func (c *Conversation) revokeOneMessage(ctx context.Context, conversationID, cli
 		SesstionType:   conversation.ConversationType,
 		ClientMsgID:    clientMsgID,
 	})
+	if message.SendID != c.loginUserID {
+		audit.Record(ctx, audit.ActionAdminEdit, "RevokeMessage", conversationID, "clientMsgID", clientMsgID, "sendID", message.SendID)
+	}
 	return nil
 }
@@ -0,0 +1,45 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversation_msg
+
+import (
+	"context"
+
+	"github.com/openimsdk/openim-sdk-core/v3/internal/third/file"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/ccontext"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+	"github.com/openimsdk/protocol/sdkws"
+)
+
+// SendMessageWithOptions sends a message like SendMessage with the options of this message.
+func (c *Conversation) SendMessageWithOptions(ctx context.Context, s *sdk_struct.MsgStruct, recvID, groupID string, p *sdkws.OfflinePushInfo, isOnlineOnly bool, options *sdk_struct.SendMsgOptions) (*sdk_struct.MsgStruct, error) {
+	if options != nil {
+		if err := file.CheckObjectStorage(options.Storage); err != nil {
+			return nil, err
+		}
+		if err := checkRestrictions(s, options.Restrictions); err != nil {
+			return nil, err
+		}
+	}
+	return c.SendMessage(ccontext.WithSendMsgOptions(ctx, options), s, recvID, groupID, p, isOnlineOnly)
+}
+
+// msgStorage is the bucket the files of the message are put to, nil for the storage of the server.
+func msgStorage(ctx context.Context) *sdk_struct.ObjectStorage {
+	if options, ok := ccontext.GetSendMsgOptions(ctx); ok {
+		return options.Storage
+	}
+	return nil
+}
@@ -129,6 +129,7 @@ func (m *messageSender) runTask(task *sendTask) {
 	if task.lane == ccontext.SendOrderLaneMedia && task.mediaSize > 0 && err == nil && task.ordered {
 		m.estimator.Update(task.mediaSize, time.Since(task.enqueueAt))
 	}
+	m.conversation.afterSend(task.msg, err)
 	if err != nil {
 		notifySendError(task.ctx, err)
 		return
@@ -143,7 +144,7 @@ func notifySendSuccess(ctx context.Context, msg *sdk_struct.MsgStruct) {
 	}
 	data, err := json.Marshal(msg)
 	if err != nil {
-		callback.OnError(sdkerrs.UnknownCode, err.Error())
+		callback.OnError(sdkerrs.SdkInternalError, err.Error())
 		return
 	}
 	callback.OnSuccess(string(data))
@@ -158,7 +159,7 @@ func notifySendError(ctx context.Context, err error) {
 		callback.OnError(int32(code.Code()), code.Msg())
 		return
 	}
-	callback.OnError(sdkerrs.UnknownCode, err.Error())
+	callback.OnError(sdkerrs.Code(err), err.Error())
 }
 
 type thresholdEstimator struct {
@@ -237,3 +238,8 @@ func estimateMediaSize(msg *sdk_struct.MsgStruct) int64 {
 	}
 	return 0
 }
+
+// PendingSendTasks is the number of the messages waiting for a worker of the sender.
+func (c *Conversation) PendingSendTasks() int {
+	return len(c.getSender().queue)
+}
@@ -0,0 +1,171 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversation_msg
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/wordfilter"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+const DefaultSensitiveWordMask = "*"
+
+// sensitiveWordFilter is the matcher of the word lists set by the app and what is done with the messages
+// having one of the words.
+type sensitiveWordFilter struct {
+	lock     sync.RWMutex
+	lists    map[string][]string
+	matcher  *wordfilter.Matcher
+	mode     string
+	mask     rune
+	received bool
+}
+
+// CheckSensitiveWordMask checks the mask is one character, or empty for the default.
+func CheckSensitiveWordMask(mask string) error {
+	if mask != "" && utf8.RuneCountInString(mask) != 1 {
+		return sdkerrs.ErrArgs.WrapMsg("the mask must be one character")
+	}
+	return nil
+}
+
+// SetSensitiveWordFilter sets what is done with the messages sent having a sensitive word, see
+// constant.SensitiveWord*, and whether the words of the messages received are masked.
+func (c *Conversation) SetSensitiveWordFilter(mode, mask string, received bool) {
+	f := &c.sensitiveWords
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.mode, f.received = mode, received
+	if mask == "" {
+		mask = DefaultSensitiveWordMask
+	}
+	f.mask, _ = utf8.DecodeRuneInString(mask)
+}
+
+// SetSensitiveWordList sets the words of the list, replacing the ones it had. No word removes the list.
+func (c *Conversation) SetSensitiveWordList(ctx context.Context, name string, words []string) error {
+	if name == "" {
+		return sdkerrs.ErrArgs.WrapMsg("the name of the list is required")
+	}
+	f := &c.sensitiveWords
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if f.lists == nil {
+		f.lists = make(map[string][]string)
+	}
+	if len(words) == 0 {
+		delete(f.lists, name)
+	} else {
+		f.lists[name] = words
+	}
+	var all []string
+	for _, list := range f.lists {
+		all = append(all, list...)
+	}
+	f.matcher = wordfilter.New(all)
+	log.ZInfo(ctx, "sensitive word list set", "name", name, "words", len(words), "lists", len(f.lists))
+	return nil
+}
+
+// FindSensitiveWords returns the sensitive words in the text, e.g. to warn while it is typed.
+func (c *Conversation) FindSensitiveWords(_ context.Context, text string) ([]string, error) {
+	c.sensitiveWords.lock.RLock()
+	defer c.sensitiveWords.lock.RUnlock()
+	return c.sensitiveWords.matcher.Words(text), nil
+}
+
+// messageTexts returns the texts of the message the words are searched in.
+func messageTexts(msg *sdk_struct.MsgStruct) []*string {
+	var texts []*string
+	switch msg.ContentType {
+	case constant.Text:
+		if msg.TextElem != nil {
+			texts = append(texts, &msg.TextElem.Content)
+		}
+	case constant.AtText:
+		if msg.AtTextElem != nil {
+			texts = append(texts, &msg.AtTextElem.Text)
+		}
+	case constant.Quote:
+		if msg.QuoteElem != nil {
+			texts = append(texts, &msg.QuoteElem.Text)
+		}
+	case constant.AdvancedText:
+		if msg.AdvancedTextElem != nil {
+			texts = append(texts, &msg.AdvancedTextElem.Text)
+		}
+	case constant.MarkdownText:
+		if msg.MarkdownTextElem != nil {
+			texts = append(texts, &msg.MarkdownTextElem.Content)
+		}
+	}
+	return texts
+}
+
+// filterSensitiveWords applies the mode to the message sent before it is stored, it fails in the block mode.
+func (c *Conversation) filterSensitiveWords(ctx context.Context, s *sdk_struct.MsgStruct) error {
+	f := &c.sensitiveWords
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+	if f.mode == "" || f.matcher.Empty() {
+		return nil
+	}
+	var words []string
+	for _, text := range messageTexts(s) {
+		words = append(words, f.matcher.Words(*text)...)
+	}
+	if len(words) == 0 {
+		return nil
+	}
+	switch f.mode {
+	case constant.SensitiveWordBlock:
+		return sdkerrs.ErrMsgSensitiveWords.WrapMsg("message blocked", "clientMsgID", s.ClientMsgID, "words", strings.Join(words, ","))
+	case constant.SensitiveWordReplace:
+		for _, text := range messageTexts(s) {
+			*text, _ = f.matcher.Mask(*text, f.mask)
+		}
+	case constant.SensitiveWordWarn:
+		if s.AttachedInfoElem == nil {
+			s.AttachedInfoElem = &sdk_struct.AttachedInfoElem{}
+		}
+		s.AttachedInfoElem.SensitiveWords = words
+	}
+	log.ZDebug(ctx, "sensitive words in the message sent", "clientMsgID", s.ClientMsgID, "mode", f.mode, "words", words)
+	return nil
+}
+
+// filterReceivedSensitiveWords masks the sensitive words of the message received from another user when it is
+// asked.
+func (c *Conversation) filterReceivedSensitiveWords(msg *sdk_struct.MsgStruct) {
+	if msg.SendID == c.loginUserID {
+		return
+	}
+	f := &c.sensitiveWords
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+	if !f.received || f.matcher.Empty() {
+		return
+	}
+	for _, text := range messageTexts(msg) {
+		*text, _ = f.matcher.Mask(*text, f.mask)
+	}
+}
@@ -0,0 +1,55 @@
+package conversation_msg
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+func TestSensitiveWords(t *testing.T) {
+	ctx := context.Background()
+	c := &Conversation{loginUserID: "self"}
+	text := func(content string) *sdk_struct.MsgStruct {
+		return &sdk_struct.MsgStruct{SendID: "other", ContentType: constant.Text, TextElem: &sdk_struct.TextElem{Content: content}}
+	}
+	if err := c.SetSensitiveWordList(ctx, "a", []string{"bad"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SetSensitiveWordList(ctx, "b", []string{"worse"}); err != nil {
+		t.Fatal(err)
+	}
+	// no mode, the messages are sent unchecked
+	if err := c.filterSensitiveWords(ctx, text("bad")); err != nil {
+		t.Fatal(err)
+	}
+	c.SetSensitiveWordFilter(constant.SensitiveWordBlock, "", false)
+	if err := c.filterSensitiveWords(ctx, text("so bad")); !sdkerrs.ErrMsgSensitiveWords.Is(err) {
+		t.Fatal(err)
+	}
+	c.SetSensitiveWordFilter(constant.SensitiveWordReplace, "#", true)
+	s := text("bad and worse")
+	if err := c.filterSensitiveWords(ctx, s); err != nil || s.TextElem.Content != "### and #####" {
+		t.Fatal(s.TextElem.Content, err)
+	}
+	c.SetSensitiveWordFilter(constant.SensitiveWordWarn, "", true)
+	s = text("worse")
+	if err := c.filterSensitiveWords(ctx, s); err != nil || !reflect.DeepEqual(s.AttachedInfoElem.SensitiveWords, []string{"worse"}) {
+		t.Fatal(s.AttachedInfoElem, err)
+	}
+	received := text("bad")
+	c.filterReceivedSensitiveWords(received)
+	if received.TextElem.Content != "***" {
+		t.Fatal(received.TextElem.Content)
+	}
+	// removing a list removes its words
+	if err := c.SetSensitiveWordList(ctx, "a", nil); err != nil {
+		t.Fatal(err)
+	}
+	if words, _ := c.FindSensitiveWords(ctx, "bad or worse"); !reflect.DeepEqual(words, []string{"worse"}) {
+		t.Fatal(words)
+	}
+}
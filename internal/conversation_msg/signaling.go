@@ -0,0 +1,463 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !no_signaling
+// +build !no_signaling
+
+package conversation_msg
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/jinzhu/copier"
+	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+	"github.com/openimsdk/protocol/sdkws"
+)
+
+// defaultCallTimeout is the seconds the invitee has to answer when the invitation sets none
+const defaultCallTimeout = 30
+
+// callState is the state of a call on this device, a call over is no longer tracked.
+type callState int
+
+const (
+	callEnded   callState = iota
+	callCalling           // the inviter waits for the answer
+	callRinging           // the invitee has not answered
+	callInCall
+	callOngoing   // a group call goes on without the login user
+	callElsewhere // the login user takes the call on another device
+)
+
+// nextCallState returns the state of the call after the action of the inviter or of the invitee, false when
+// the action is not one of the state or of the user.
+func nextCallState(state callState, action string, byInviter bool) (callState, bool) {
+	if state == callElsewhere {
+		// the call goes on until it is over on the other device or on the other side
+		switch action {
+		case constant.SignalingAccept:
+			return callElsewhere, !byInviter
+		case constant.SignalingCancel, constant.SignalingReject, constant.SignalingTimeout, constant.SignalingHungUp, constant.SignalingBusy:
+			return callEnded, true
+		}
+		return state, false
+	}
+	waiting := state == callCalling || state == callRinging
+	switch action {
+	case constant.SignalingCancel:
+		return callEnded, waiting && byInviter
+	case constant.SignalingAccept:
+		return callInCall, waiting && !byInviter
+	case constant.SignalingReject:
+		return callEnded, waiting && !byInviter
+	case constant.SignalingBusy:
+		return callEnded, state == callCalling && !byInviter
+	case constant.SignalingTimeout:
+		return callEnded, waiting
+	case constant.SignalingHungUp:
+		return callEnded, state == callInCall
+	}
+	return state, false
+}
+
+type call struct {
+	invitation *sdk_struct.SignalingInvitation
+	state      callState
+	acceptTime int64
+	timer      *time.Timer
+	// participants are the users in a group call by the time they joined
+	participants map[string]int64
+}
+
+// signaling runs the calls of the login user, the signals go as online only messages to the other side and
+// the calls over are inserted in the conversation as call messages.
+type signaling struct {
+	conv     *Conversation
+	listener func() open_im_sdk_callback.OnSignalingListener
+
+	lock  sync.Mutex
+	calls map[string]*call // by room ID
+}
+
+func newSignaling(c *Conversation) *signaling {
+	return &signaling{conv: c, calls: make(map[string]*call)}
+}
+
+func (c *Conversation) SetSignalingListener(listener func() open_im_sdk_callback.OnSignalingListener) {
+	c.signaling.listener = listener
+}
+
+// SignalingInvite calls the invitee of the invitation, which is returned with its room ID. The call is over
+// when the invitee did not answer within the timeout of the invitation.
+func (c *Conversation) SignalingInvite(ctx context.Context, invitation *sdk_struct.SignalingInvitation) (*sdk_struct.SignalingInvitation, error) {
+	if len(invitation.InviteeUserIDList) != 1 || invitation.InviteeUserIDList[0] == "" || invitation.InviteeUserIDList[0] == c.loginUserID {
+		return nil, sdkerrs.ErrArgs.WrapMsg("a call has one invitee other than the login user")
+	}
+	if invitation.MediaType != constant.CallMediaAudio && invitation.MediaType != constant.CallMediaVideo {
+		return nil, sdkerrs.ErrArgs.WrapMsg("mediaType is audio or video", "mediaType", invitation.MediaType)
+	}
+	if invitation.Timeout < 0 {
+		return nil, sdkerrs.ErrArgs.WrapMsg("timeout can't be negative")
+	}
+	if invitation.Timeout == 0 {
+		invitation.Timeout = defaultCallTimeout
+	}
+	if invitation.RoomID == "" {
+		invitation.RoomID = utils.GetMsgID(c.loginUserID)
+	}
+	invitation.InviterUserID = c.loginUserID
+	invitation.GroupID = ""
+	invitation.SessionType = constant.SingleChatType
+	invitation.InitiateTime = utils.GetServerTimestampByMill()
+	invitation.PlatformID = c.platform
+	s := c.signaling
+	s.lock.Lock()
+	if _, ok := s.calls[invitation.RoomID]; ok {
+		s.lock.Unlock()
+		return nil, sdkerrs.ErrArgs.WrapMsg("the room already has a call", "roomID", invitation.RoomID)
+	}
+	cl := &call{invitation: invitation, state: callCalling}
+	s.calls[invitation.RoomID] = cl
+	s.lock.Unlock()
+	err := s.send(ctx, invitation, &sdk_struct.SignalingElem{
+		Action:     constant.SignalingInvite,
+		Invitation: invitation,
+		UserID:     c.loginUserID,
+		CustomData: invitation.CustomData,
+	})
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if err != nil {
+		if s.calls[invitation.RoomID] == cl {
+			delete(s.calls, invitation.RoomID)
+		}
+		return nil, err
+	}
+	if cl.state == callCalling {
+		s.startTimer(ctx, cl)
+	}
+	return invitation, nil
+}
+
+// SignalingAccept answers the call the login user is invited to, for a group call it joins the call.
+func (c *Conversation) SignalingAccept(ctx context.Context, roomID, customData string) error {
+	return c.signaling.act(ctx, roomID, constant.SignalingAccept, customData)
+}
+
+// SignalingReject declines the call the login user is invited to.
+func (c *Conversation) SignalingReject(ctx context.Context, roomID, customData string) error {
+	return c.signaling.act(ctx, roomID, constant.SignalingReject, customData)
+}
+
+// SignalingCancel calls off the call of the login user before it is answered.
+func (c *Conversation) SignalingCancel(ctx context.Context, roomID, customData string) error {
+	return c.signaling.act(ctx, roomID, constant.SignalingCancel, customData)
+}
+
+// SignalingHungUp ends the call in progress, for a group call it leaves the call which goes on for the others.
+func (c *Conversation) SignalingHungUp(ctx context.Context, roomID, customData string) error {
+	return c.signaling.act(ctx, roomID, constant.SignalingHungUp, customData)
+}
+
+// act sends the action of the login user to the other side of the call then applies it, the call must still
+// be in the same state once the signal is sent.
+func (s *signaling) act(ctx context.Context, roomID, action, customData string) error {
+	s.lock.Lock()
+	cl, ok := s.calls[roomID]
+	if !ok {
+		s.lock.Unlock()
+		return sdkerrs.ErrCallNotFound.WrapMsg("roomID", roomID)
+	}
+	state := cl.state
+	if state == callElsewhere {
+		s.lock.Unlock()
+		return sdkerrs.ErrCallState.WrapMsg("the call is taken on another device", "roomID", roomID)
+	}
+	if _, ok := s.next(cl, action, s.conv.loginUserID); !ok {
+		s.lock.Unlock()
+		return sdkerrs.ErrCallState.WrapMsg("the call can't be "+action, "roomID", roomID, "state", state)
+	}
+	s.lock.Unlock()
+	elem := &sdk_struct.SignalingElem{Action: action, Invitation: cl.invitation, UserID: s.conv.loginUserID, CustomData: customData}
+	if err := s.send(ctx, cl.invitation, elem); err != nil {
+		return err
+	}
+	s.lock.Lock()
+	if s.calls[roomID] != cl || cl.state != state {
+		s.lock.Unlock()
+		return sdkerrs.ErrCallState.WrapMsg("the call changed meanwhile", "roomID", roomID)
+	}
+	next, _ := s.next(cl, action, s.conv.loginUserID)
+	callElem := s.apply(cl, next, action)
+	s.lock.Unlock()
+	s.callOver(ctx, cl.invitation, callElem)
+	return nil
+}
+
+// next returns the state of the call after the action of the user, false when the user can't take it.
+func (s *signaling) next(cl *call, action, userID string) (callState, bool) {
+	byInviter := cl.invitation.InviterUserID == userID
+	if cl.invitation.GroupID != "" {
+		return nextGroupCallState(cl.state, action, byInviter, len(cl.participants) == 1)
+	}
+	return nextCallState(cl.state, action, byInviter)
+}
+
+// apply moves the call to the state after the action of the login user or of the peer, it returns the message
+// of the call when it is over. It is called with the lock held.
+func (s *signaling) apply(cl *call, next callState, action string) *sdk_struct.CallElem {
+	if cl.invitation.GroupID != "" {
+		return s.applyInGroup(cl, next, action)
+	}
+	if cl.timer != nil {
+		cl.timer.Stop()
+		cl.timer = nil
+	}
+	cl.state = next
+	switch next {
+	case callInCall, callElsewhere:
+		cl.acceptTime = utils.GetServerTimestampByMill()
+		return nil
+	case callEnded:
+		delete(s.calls, cl.invitation.RoomID)
+	default:
+		return nil
+	}
+	return s.callElem(cl, action)
+}
+
+// callElem returns the message of the call over after the action.
+func (s *signaling) callElem(cl *call, action string) *sdk_struct.CallElem {
+	callElem := &sdk_struct.CallElem{
+		RoomID:        cl.invitation.RoomID,
+		InviterUserID: cl.invitation.InviterUserID,
+		MediaType:     cl.invitation.MediaType,
+	}
+	switch action {
+	case constant.SignalingCancel:
+		callElem.State = constant.CallCancelled
+	case constant.SignalingReject:
+		callElem.State = constant.CallRejected
+	case constant.SignalingTimeout:
+		callElem.State = constant.CallTimeout
+	case constant.SignalingBusy:
+		callElem.State = constant.CallBusy
+	case constant.SignalingHungUp:
+		callElem.State = constant.CallCompleted
+		callElem.Duration = utils.GetServerTimestampByMill() - cl.acceptTime
+	}
+	// the invitee is called off or times out only while rung
+	callElem.Missed = cl.invitation.InviterUserID != s.conv.loginUserID &&
+		(action == constant.SignalingCancel || action == constant.SignalingTimeout)
+	return callElem
+}
+
+// startTimer ends the call when it is not answered in time. It is called with the lock held.
+func (s *signaling) startTimer(ctx context.Context, cl *call) {
+	ctx = context.WithoutCancel(ctx)
+	cl.timer = time.AfterFunc(time.Duration(cl.invitation.Timeout)*time.Second, func() { s.onTimeout(ctx, cl) })
+}
+
+// onTimeout ends the call not answered, the inviter tells the invitees so that a late answer finds no call.
+func (s *signaling) onTimeout(ctx context.Context, cl *call) {
+	s.lock.Lock()
+	if s.calls[cl.invitation.RoomID] != cl {
+		s.lock.Unlock()
+		return
+	}
+	state := cl.state
+	next, ok := s.next(cl, constant.SignalingTimeout, s.conv.loginUserID)
+	if !ok {
+		s.lock.Unlock()
+		return
+	}
+	callElem := s.apply(cl, next, constant.SignalingTimeout)
+	s.lock.Unlock()
+	elem := &sdk_struct.SignalingElem{Action: constant.SignalingTimeout, Invitation: cl.invitation, UserID: s.conv.loginUserID}
+	if cl.invitation.InviterUserID == s.conv.loginUserID && state != callElsewhere {
+		if err := s.send(ctx, cl.invitation, elem); err != nil {
+			log.ZWarn(ctx, "send call timeout failed", err, "roomID", cl.invitation.RoomID)
+		}
+	}
+	if state != callElsewhere {
+		s.listener().OnInvitationTimeout(utils.StructToJsonString(elem))
+	}
+	s.callOver(ctx, cl.invitation, callElem)
+}
+
+// peer returns the other side of the call.
+func (s *signaling) peer(invitation *sdk_struct.SignalingInvitation) string {
+	if invitation.InviterUserID == s.conv.loginUserID {
+		return invitation.InviteeUserIDList[0]
+	}
+	return invitation.InviterUserID
+}
+
+// send sends the signal to the other side of the call, the group of a group call, as a message that is
+// not stored. The other devices of the login user get it too to follow the call. Only the invitations are
+// pushed offline.
+func (s *signaling) send(ctx context.Context, invitation *sdk_struct.SignalingInvitation, elem *sdk_struct.SignalingElem) error {
+	m := sdk_struct.MsgStruct{}
+	if err := s.conv.initBasicInfo(ctx, &m, constant.UserMsgType, constant.Signaling); err != nil {
+		return err
+	}
+	if invitation.GroupID != "" {
+		m.GroupID = invitation.GroupID
+	} else {
+		m.RecvID = s.peer(invitation)
+	}
+	m.SessionType = invitation.SessionType
+	m.Content = utils.StructToJsonString(elem)
+	options := make(map[string]bool, 7)
+	utils.SetSwitchFromOptions(options, constant.IsHistory, false)
+	utils.SetSwitchFromOptions(options, constant.IsPersistent, false)
+	utils.SetSwitchFromOptions(options, constant.IsSenderSync, true)
+	utils.SetSwitchFromOptions(options, constant.IsConversationUpdate, false)
+	utils.SetSwitchFromOptions(options, constant.IsSenderConversationUpdate, false)
+	utils.SetSwitchFromOptions(options, constant.IsUnreadCount, false)
+	utils.SetSwitchFromOptions(options, constant.IsOfflinePush, elem.Action == constant.SignalingInvite)
+	var wsMsgData sdkws.MsgData
+	copier.Copy(&wsMsgData, m)
+	wsMsgData.Content = []byte(m.Content)
+	wsMsgData.CreateTime = m.CreateTime
+	wsMsgData.Options = options
+	if err := s.conv.sendMsg(ctx, &m, &wsMsgData, nil); err != nil {
+		log.ZError(ctx, "signaling msg to server failed", err, "action", elem.Action, "roomID", elem.Invitation.RoomID)
+		return err
+	}
+	return nil
+}
+
+// onNewMsg applies the signal of the other side of a call or of another device of the login user, the signals
+// of unknown calls are dropped.
+func (s *signaling) onNewMsg(ctx context.Context, msg *sdkws.MsgData) {
+	if msg.SendID == s.conv.loginUserID && msg.SenderPlatformID == s.conv.platform {
+		return
+	}
+	var elem sdk_struct.SignalingElem
+	if err := json.Unmarshal(msg.Content, &elem); err != nil || elem.Invitation == nil || elem.Invitation.RoomID == "" {
+		log.ZWarn(ctx, "signaling onNewMsg invalid signal", err, "message", msg)
+		return
+	}
+	elem.UserID = msg.SendID
+	if elem.Invitation.GroupID != "" {
+		if elem.Invitation.GroupID == msg.GroupID {
+			s.onGroupSignal(ctx, &elem)
+		}
+		return
+	}
+	if msg.SendID == s.conv.loginUserID {
+		s.onOtherDevice(ctx, &elem)
+		return
+	}
+	if elem.Action == constant.SignalingInvite {
+		s.onInvitation(ctx, &elem)
+		return
+	}
+	s.lock.Lock()
+	cl, ok := s.calls[elem.Invitation.RoomID]
+	if !ok || cl.invitation.GroupID != "" || s.peer(cl.invitation) != msg.SendID {
+		s.lock.Unlock()
+		log.ZDebug(ctx, "signal of an unknown call", "action", elem.Action, "roomID", elem.Invitation.RoomID)
+		return
+	}
+	state := cl.state
+	next, ok := s.next(cl, elem.Action, msg.SendID)
+	if !ok {
+		s.lock.Unlock()
+		log.ZWarn(ctx, "signal not allowed in the call state", nil, "action", elem.Action, "state", cl.state)
+		return
+	}
+	callElem := s.apply(cl, next, elem.Action)
+	s.lock.Unlock()
+	if state == callElsewhere {
+		s.callOver(ctx, cl.invitation, callElem)
+		return
+	}
+	elem.Invitation = cl.invitation
+	data := utils.StructToJsonString(elem)
+	switch elem.Action {
+	case constant.SignalingAccept:
+		s.listener().OnInviteeAccepted(data)
+	case constant.SignalingReject:
+		s.listener().OnInviteeRejected(data)
+	case constant.SignalingBusy:
+		s.listener().OnInviteeBusy(data)
+	case constant.SignalingCancel:
+		s.listener().OnInvitationCancelled(data)
+	case constant.SignalingTimeout:
+		s.listener().OnInvitationTimeout(data)
+	case constant.SignalingHungUp:
+		s.listener().OnHangUp(data)
+	}
+	s.callOver(ctx, cl.invitation, callElem)
+}
+
+func (s *signaling) onInvitation(ctx context.Context, elem *sdk_struct.SignalingElem) {
+	invitation := elem.Invitation
+	if invitation.InviterUserID != elem.UserID || len(invitation.InviteeUserIDList) != 1 ||
+		invitation.InviteeUserIDList[0] != s.conv.loginUserID {
+		log.ZWarn(ctx, "invalid call invitation", nil, "invitation", invitation)
+		return
+	}
+	if invitation.Timeout <= 0 {
+		invitation.Timeout = defaultCallTimeout
+	}
+	s.lock.Lock()
+	if _, ok := s.calls[invitation.RoomID]; ok {
+		s.lock.Unlock()
+		return
+	}
+	if here, elsewhere := s.busy(); here || elsewhere {
+		s.lock.Unlock()
+		s.onBusy(ctx, invitation, here)
+		return
+	}
+	cl := &call{invitation: invitation, state: callRinging}
+	s.calls[invitation.RoomID] = cl
+	s.startTimer(ctx, cl)
+	s.lock.Unlock()
+	s.listener().OnReceiveNewInvitation(utils.StructToJsonString(elem))
+}
+
+// insertCallMessage inserts the message of the call over in the chat, each side inserts its own.
+func (s *signaling) insertCallMessage(ctx context.Context, invitation *sdk_struct.SignalingInvitation, callElem *sdk_struct.CallElem) {
+	m := sdk_struct.MsgStruct{}
+	if err := s.conv.initBasicInfo(ctx, &m, constant.UserMsgType, constant.Call); err != nil {
+		log.ZWarn(ctx, "insert call message failed", err, "roomID", invitation.RoomID)
+		return
+	}
+	m.CallElem = callElem
+	m.AttachedInfoElem = &sdk_struct.AttachedInfoElem{}
+	var msg *sdk_struct.MsgStruct
+	var err error
+	if invitation.GroupID != "" {
+		msg, err = s.conv.InsertGroupMessageToLocalStorage(ctx, &m, invitation.GroupID, invitation.InviterUserID)
+	} else {
+		msg, err = s.conv.InsertSingleMessageToLocalStorage(ctx, &m, invitation.InviteeUserIDList[0], invitation.InviterUserID)
+	}
+	if err != nil {
+		log.ZWarn(ctx, "insert call message failed", err, "roomID", invitation.RoomID)
+		return
+	}
+	s.conv.msgListener().OnRecvNewMessage(utils.StructToJsonString(msg))
+}
@@ -0,0 +1,79 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !no_signaling
+// +build !no_signaling
+
+package conversation_msg
+
+import (
+	"context"
+
+	"github.com/jinzhu/copier"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+	"github.com/openimsdk/protocol/sdkws"
+)
+
+// maxSignalingDataSize is the bytes a payload of the custom signaling channel has at most
+const maxSignalingDataSize = 4 * 1024
+
+// SendSignalingData sends the payload to the online devices of the user over the long connection, it is
+// neither stored nor pushed offline, so a user offline never gets it.
+func (c *Conversation) SendSignalingData(ctx context.Context, toUserID, payload string) error {
+	if toUserID == "" {
+		return sdkerrs.ErrArgs.WrapMsg("toUserID can't be empty")
+	}
+	if payload == "" || len(payload) > maxSignalingDataSize {
+		return sdkerrs.ErrArgs.WrapMsg("the payload has from 1 to 4096 bytes", "size", len(payload))
+	}
+	m := sdk_struct.MsgStruct{}
+	if err := c.initBasicInfo(ctx, &m, constant.UserMsgType, constant.SignalingData); err != nil {
+		return err
+	}
+	m.RecvID = toUserID
+	m.SessionType = constant.SingleChatType
+	m.Content = payload
+	options := make(map[string]bool, 7)
+	utils.SetSwitchFromOptions(options, constant.IsHistory, false)
+	utils.SetSwitchFromOptions(options, constant.IsPersistent, false)
+	utils.SetSwitchFromOptions(options, constant.IsSenderSync, false)
+	utils.SetSwitchFromOptions(options, constant.IsConversationUpdate, false)
+	utils.SetSwitchFromOptions(options, constant.IsSenderConversationUpdate, false)
+	utils.SetSwitchFromOptions(options, constant.IsUnreadCount, false)
+	utils.SetSwitchFromOptions(options, constant.IsOfflinePush, false)
+	var wsMsgData sdkws.MsgData
+	copier.Copy(&wsMsgData, m)
+	wsMsgData.Content = []byte(m.Content)
+	wsMsgData.CreateTime = m.CreateTime
+	wsMsgData.Options = options
+	return c.sendMsg(ctx, &m, &wsMsgData, nil)
+}
+
+// onData hands the payload of the custom signaling channel to the app as is.
+func (s *signaling) onData(ctx context.Context, msg *sdkws.MsgData) {
+	if msg.SendID == s.conv.loginUserID && msg.SenderPlatformID == s.conv.platform {
+		return
+	}
+	log.ZDebug(ctx, "signaling data", "sendID", msg.SendID, "size", len(msg.Content))
+	s.listener().OnReceiveSignalingData(utils.StructToJsonString(&sdk_struct.SignalingData{
+		SendID:     msg.SendID,
+		PlatformID: msg.SenderPlatformID,
+		Payload:    string(msg.Content),
+		SendTime:   msg.SendTime,
+	}))
+}
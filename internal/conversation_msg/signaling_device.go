@@ -0,0 +1,117 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !no_signaling
+// +build !no_signaling
+
+package conversation_msg
+
+import (
+	"context"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+// busy tells whether the login user is in a call on this device, or on another one. It is called with the
+// lock held.
+func (s *signaling) busy() (here, elsewhere bool) {
+	for _, cl := range s.calls {
+		switch cl.state {
+		case callCalling, callInCall:
+			here = true
+		case callElsewhere:
+			elsewhere = true
+		case callOngoing:
+			if _, ok := cl.participants[s.conv.loginUserID]; ok {
+				elsewhere = true
+			}
+		}
+	}
+	return here, elsewhere
+}
+
+// onBusy turns down the invitation of the login user already in a call, the device in the call answers busy
+// and every device keeps the missed call.
+func (s *signaling) onBusy(ctx context.Context, invitation *sdk_struct.SignalingInvitation, here bool) {
+	if here {
+		elem := &sdk_struct.SignalingElem{Action: constant.SignalingBusy, Invitation: invitation, UserID: s.conv.loginUserID}
+		if err := s.send(ctx, invitation, elem); err != nil {
+			log.ZWarn(ctx, "send call busy failed", err, "roomID", invitation.RoomID)
+		}
+	}
+	s.callOver(ctx, invitation, &sdk_struct.CallElem{
+		RoomID:        invitation.RoomID,
+		InviterUserID: invitation.InviterUserID,
+		MediaType:     invitation.MediaType,
+		State:         constant.CallBusy,
+		Missed:        true,
+	})
+}
+
+// onOtherDevice follows the call the login user takes on another device, a call rung here stops ringing
+// once answered or declined there. A call on another device is over here when it is over there or on the
+// other side.
+func (s *signaling) onOtherDevice(ctx context.Context, elem *sdk_struct.SignalingElem) {
+	invitation := elem.Invitation
+	if elem.Action == constant.SignalingInvite {
+		if invitation.InviterUserID != s.conv.loginUserID || len(invitation.InviteeUserIDList) != 1 {
+			log.ZWarn(ctx, "invalid call invitation", nil, "invitation", invitation)
+			return
+		}
+		if invitation.Timeout <= 0 {
+			invitation.Timeout = defaultCallTimeout
+		}
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		if _, ok := s.calls[invitation.RoomID]; ok {
+			return
+		}
+		cl := &call{invitation: invitation, state: callElsewhere}
+		s.calls[invitation.RoomID] = cl
+		s.startTimer(ctx, cl)
+		return
+	}
+	s.lock.Lock()
+	cl, ok := s.calls[invitation.RoomID]
+	if !ok || cl.invitation.GroupID != "" || (cl.state != callRinging && cl.state != callElsewhere) {
+		s.lock.Unlock()
+		log.ZDebug(ctx, "signal of a call not on another device", "action", elem.Action, "roomID", invitation.RoomID)
+		return
+	}
+	state := cl.state
+	next, ok := s.next(cl, elem.Action, s.conv.loginUserID)
+	if !ok {
+		s.lock.Unlock()
+		log.ZWarn(ctx, "signal not allowed in the call state", nil, "action", elem.Action, "state", cl.state)
+		return
+	}
+	if next == callInCall {
+		next = callElsewhere
+	}
+	callElem := s.apply(cl, next, elem.Action)
+	s.lock.Unlock()
+	elem.Invitation = cl.invitation
+	if state == callRinging {
+		switch elem.Action {
+		case constant.SignalingAccept:
+			s.listener().OnInviteeAcceptedByOtherDevice(utils.StructToJsonString(elem))
+		case constant.SignalingReject:
+			s.listener().OnInviteeRejectedByOtherDevice(utils.StructToJsonString(elem))
+		}
+	}
+	s.callOver(ctx, cl.invitation, callElem)
+}
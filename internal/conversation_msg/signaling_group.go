@@ -0,0 +1,374 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !no_signaling
+// +build !no_signaling
+
+package conversation_msg
+
+import (
+	"context"
+	"sort"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+	"github.com/openimsdk/tools/utils/datautil"
+)
+
+// maxGroupCallInvitees is the members a group call rings at most
+const maxGroupCallInvitees = 32
+
+// nextGroupCallState is nextCallState for a group call, which goes on without the login user until nobody
+// is in it. The inviter cancels or times out the call only while alone in it.
+func nextGroupCallState(state callState, action string, byInviter, alone bool) (callState, bool) {
+	switch action {
+	case constant.SignalingAccept:
+		return callInCall, state == callRinging
+	case constant.SignalingJoin:
+		return callInCall, state == callRinging || state == callOngoing
+	case constant.SignalingReject:
+		return callOngoing, state == callRinging
+	case constant.SignalingTimeout:
+		if state == callInCall {
+			return callEnded, byInviter && alone
+		}
+		return callOngoing, state == callRinging
+	case constant.SignalingCancel:
+		return callEnded, state == callInCall && byInviter && alone
+	case constant.SignalingHungUp:
+		return callOngoing, state == callInCall
+	}
+	return state, false
+}
+
+// SignalingInviteInGroup starts a call in the group, the login user is in it at once. The invitees are rung,
+// all the members when there are none and the group is small enough, the other members can join the call.
+func (c *Conversation) SignalingInviteInGroup(ctx context.Context, invitation *sdk_struct.SignalingInvitation) (*sdk_struct.SignalingInvitation, error) {
+	if invitation.GroupID == "" {
+		return nil, sdkerrs.ErrArgs.WrapMsg("groupID can't be empty")
+	}
+	if invitation.MediaType != constant.CallMediaAudio && invitation.MediaType != constant.CallMediaVideo {
+		return nil, sdkerrs.ErrArgs.WrapMsg("mediaType is audio or video", "mediaType", invitation.MediaType)
+	}
+	if invitation.Timeout < 0 {
+		return nil, sdkerrs.ErrArgs.WrapMsg("timeout can't be negative")
+	}
+	if invitation.Timeout == 0 {
+		invitation.Timeout = defaultCallTimeout
+	}
+	invitees, err := c.groupCallInvitees(ctx, invitation.GroupID, invitation.InviteeUserIDList)
+	if err != nil {
+		return nil, err
+	}
+	_, sessionType, err := c.getConversationTypeByGroupID(ctx, invitation.GroupID)
+	if err != nil {
+		return nil, err
+	}
+	if invitation.RoomID == "" {
+		invitation.RoomID = utils.GetMsgID(c.loginUserID)
+	}
+	invitation.InviterUserID = c.loginUserID
+	invitation.InviteeUserIDList = invitees
+	invitation.SessionType = sessionType
+	invitation.InitiateTime = utils.GetServerTimestampByMill()
+	invitation.PlatformID = c.platform
+	s := c.signaling
+	s.lock.Lock()
+	if _, ok := s.calls[invitation.RoomID]; ok {
+		s.lock.Unlock()
+		return nil, sdkerrs.ErrArgs.WrapMsg("the room already has a call", "roomID", invitation.RoomID)
+	}
+	cl := &call{
+		invitation:   invitation,
+		state:        callInCall,
+		acceptTime:   invitation.InitiateTime,
+		participants: map[string]int64{c.loginUserID: invitation.InitiateTime},
+	}
+	s.calls[invitation.RoomID] = cl
+	s.lock.Unlock()
+	err = s.send(ctx, invitation, &sdk_struct.SignalingElem{
+		Action:     constant.SignalingInvite,
+		Invitation: invitation,
+		UserID:     c.loginUserID,
+		CustomData: invitation.CustomData,
+	})
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if err != nil {
+		if s.calls[invitation.RoomID] == cl {
+			delete(s.calls, invitation.RoomID)
+		}
+		return nil, err
+	}
+	if s.calls[invitation.RoomID] == cl {
+		s.startTimer(ctx, cl)
+	}
+	return invitation, nil
+}
+
+// groupCallInvitees returns the members the call rings. A muted member can't start a call, nor an ordinary
+// member of a muted group.
+func (c *Conversation) groupCallInvitees(ctx context.Context, groupID string, invitees []string) ([]string, error) {
+	g, err := c.group.FetchGroupOrError(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	if g.Status == constant.GroupStatusDismissed {
+		return nil, sdkerrs.ErrArgs.WrapMsg("the group is dismissed", "groupID", groupID)
+	}
+	members, err := c.db.GetGroupMemberListByGroupID(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	memberSet := make(map[string]struct{}, len(members))
+	others := make([]string, 0, len(members))
+	var self bool
+	for _, member := range members {
+		if member.UserID != c.loginUserID {
+			memberSet[member.UserID] = struct{}{}
+			others = append(others, member.UserID)
+			continue
+		}
+		self = true
+		if member.MuteEndTime > utils.GetServerTimestampByMill() {
+			return nil, sdkerrs.ErrArgs.WrapMsg("the login user is muted in the group", "groupID", groupID)
+		}
+		if g.Status == constant.GroupStatusMuted && member.RoleLevel < constant.GroupAdmin {
+			return nil, sdkerrs.ErrArgs.WrapMsg("the group is muted", "groupID", groupID)
+		}
+	}
+	if !self {
+		return nil, sdkerrs.ErrArgs.WrapMsg("the login user is not in the group", "groupID", groupID)
+	}
+	if len(invitees) == 0 {
+		invitees = others
+	}
+	invitees = datautil.Distinct(invitees)
+	if len(invitees) == 0 || len(invitees) > maxGroupCallInvitees {
+		return nil, sdkerrs.ErrArgs.WrapMsg("a group call rings from 1 to 32 members, list the invitees", "invitees", len(invitees))
+	}
+	for _, userID := range invitees {
+		if _, ok := memberSet[userID]; !ok {
+			return nil, sdkerrs.ErrArgs.WrapMsg("the invitee is not another member of the group", "userID", userID)
+		}
+	}
+	return invitees, nil
+}
+
+// SignalingJoin joins the call of the group, one the login user is rung for or not.
+func (c *Conversation) SignalingJoin(ctx context.Context, roomID, customData string) error {
+	return c.signaling.act(ctx, roomID, constant.SignalingJoin, customData)
+}
+
+// SignalingGetRoomByGroupID returns the call of the group with its roster.
+func (c *Conversation) SignalingGetRoomByGroupID(ctx context.Context, groupID string) (*sdk_struct.SignalingRoom, error) {
+	s := c.signaling
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for _, cl := range s.calls {
+		if cl.invitation.GroupID == groupID {
+			return s.room(cl, ""), nil
+		}
+	}
+	return nil, sdkerrs.ErrCallNotFound.WrapMsg("groupID", groupID)
+}
+
+// applyInGroup is apply for a group call and the actions of the login user, the message is of the part of
+// the login user in the call. It is called with the lock held.
+func (s *signaling) applyInGroup(cl *call, next callState, action string) *sdk_struct.CallElem {
+	if cl.timer != nil && (cl.state == callRinging || next != callInCall) {
+		cl.timer.Stop()
+		cl.timer = nil
+	}
+	cl.state = next
+	switch action {
+	case constant.SignalingAccept, constant.SignalingJoin:
+		cl.acceptTime = utils.GetServerTimestampByMill()
+		cl.participants[s.conv.loginUserID] = cl.acceptTime
+		return nil
+	case constant.SignalingHungUp:
+		delete(cl.participants, s.conv.loginUserID)
+	}
+	if next == callEnded || len(cl.participants) == 0 {
+		delete(s.calls, cl.invitation.RoomID)
+	}
+	return s.callElem(cl, action)
+}
+
+// room returns the group call with its roster by join time.
+func (s *signaling) room(cl *call, userID string) *sdk_struct.SignalingRoom {
+	room := &sdk_struct.SignalingRoom{Invitation: cl.invitation, UserID: userID}
+	for id, joinTime := range cl.participants {
+		room.Participants = append(room.Participants, &sdk_struct.SignalingParticipant{UserID: id, JoinTime: joinTime})
+	}
+	sort.Slice(room.Participants, func(i, j int) bool {
+		if room.Participants[i].JoinTime != room.Participants[j].JoinTime {
+			return room.Participants[i].JoinTime < room.Participants[j].JoinTime
+		}
+		return room.Participants[i].UserID < room.Participants[j].UserID
+	})
+	return room
+}
+
+func (s *signaling) onGroupInvitation(ctx context.Context, elem *sdk_struct.SignalingElem) {
+	invitation := elem.Invitation
+	if invitation.InviterUserID != elem.UserID {
+		log.ZWarn(ctx, "invalid group call invitation", nil, "invitation", invitation)
+		return
+	}
+	if invitation.Timeout <= 0 {
+		invitation.Timeout = defaultCallTimeout
+	}
+	state := callOngoing
+	if datautil.Contain(s.conv.loginUserID, invitation.InviteeUserIDList...) && s.rings(ctx, invitation) {
+		state = callRinging
+	}
+	s.lock.Lock()
+	if _, ok := s.calls[invitation.RoomID]; ok {
+		s.lock.Unlock()
+		return
+	}
+	if here, elsewhere := s.busy(); state == callRinging && (here || elsewhere) {
+		// the members in a call are not rung, they can still join
+		state = callOngoing
+	}
+	cl := &call{invitation: invitation, state: state, participants: map[string]int64{invitation.InviterUserID: invitation.InitiateTime}}
+	s.calls[invitation.RoomID] = cl
+	if state == callRinging {
+		s.startTimer(ctx, cl)
+	}
+	room := s.room(cl, invitation.InviterUserID)
+	s.lock.Unlock()
+	if state == callRinging {
+		s.listener().OnReceiveNewInvitation(utils.StructToJsonString(elem))
+	}
+	s.listener().OnRoomParticipantConnected(utils.StructToJsonString(room))
+}
+
+// rings tells whether the group call rings the login user, not when the group conversation receives no messages.
+func (s *signaling) rings(ctx context.Context, invitation *sdk_struct.SignalingInvitation) bool {
+	conversationID := s.conv.getConversationIDBySessionType(invitation.GroupID, int(invitation.SessionType))
+	conversation, err := s.conv.db.GetConversation(ctx, conversationID)
+	if err != nil {
+		return true
+	}
+	return conversation.RecvMsgOpt != constant.NotReceiveMessage
+}
+
+// onGroupSignal keeps the roster of the group call up to date with the signals of the members, the earliest
+// participant sends the roster to the group when someone joined. The call is over once nobody is in it.
+func (s *signaling) onGroupSignal(ctx context.Context, elem *sdk_struct.SignalingElem) {
+	if elem.Action == constant.SignalingInvite {
+		s.onGroupInvitation(ctx, elem)
+		return
+	}
+	s.lock.Lock()
+	cl, ok := s.calls[elem.Invitation.RoomID]
+	if !ok || cl.invitation.GroupID != elem.Invitation.GroupID {
+		s.lock.Unlock()
+		log.ZDebug(ctx, "signal of an unknown group call", "action", elem.Action, "roomID", elem.Invitation.RoomID)
+		return
+	}
+	ringing := cl.state == callRinging
+	var connected, disconnected, ended, elsewhere bool
+	var callElem *sdk_struct.CallElem
+	if ringing && elem.UserID == s.conv.loginUserID {
+		// answered or declined on another device of the login user, the call no longer rings here
+		switch elem.Action {
+		case constant.SignalingReject:
+			callElem = s.callElem(cl, elem.Action)
+			fallthrough
+		case constant.SignalingAccept, constant.SignalingJoin:
+			if cl.timer != nil {
+				cl.timer.Stop()
+				cl.timer = nil
+			}
+			cl.state = callOngoing
+			ringing, elsewhere = false, true
+		}
+	}
+	switch elem.Action {
+	case constant.SignalingAccept, constant.SignalingJoin:
+		if _, ok := cl.participants[elem.UserID]; !ok {
+			cl.participants[elem.UserID] = utils.GetServerTimestampByMill()
+			connected = true
+		}
+	case constant.SignalingRoster:
+		for _, p := range elem.Participants {
+			if _, ok := cl.participants[p.UserID]; !ok && p.UserID != s.conv.loginUserID {
+				cl.participants[p.UserID] = p.JoinTime
+				connected = true
+			}
+		}
+	case constant.SignalingHungUp:
+		if _, ok := cl.participants[elem.UserID]; ok {
+			delete(cl.participants, elem.UserID)
+			disconnected = true
+		}
+		ended = len(cl.participants) == 0
+	case constant.SignalingCancel, constant.SignalingTimeout:
+		ended = elem.UserID == cl.invitation.InviterUserID
+	}
+	if ended {
+		if cl.timer != nil {
+			cl.timer.Stop()
+			cl.timer = nil
+		}
+		cl.state = callEnded
+		delete(s.calls, cl.invitation.RoomID)
+		if ringing {
+			action := elem.Action
+			if action == constant.SignalingHungUp {
+				action = constant.SignalingCancel
+			}
+			callElem = s.callElem(cl, action)
+		}
+	}
+	sendRoster := connected && elem.Action != constant.SignalingRoster && cl.state == callInCall &&
+		s.room(cl, "").Participants[0].UserID == s.conv.loginUserID
+	room := s.room(cl, elem.UserID)
+	s.lock.Unlock()
+	elem.Invitation = cl.invitation
+	inviter := cl.invitation.InviterUserID == s.conv.loginUserID
+	switch {
+	case elsewhere && elem.Action == constant.SignalingReject:
+		s.listener().OnInviteeRejectedByOtherDevice(utils.StructToJsonString(elem))
+	case elsewhere:
+		s.listener().OnInviteeAcceptedByOtherDevice(utils.StructToJsonString(elem))
+	case elem.Action == constant.SignalingAccept && inviter:
+		s.listener().OnInviteeAccepted(utils.StructToJsonString(elem))
+	case elem.Action == constant.SignalingReject && inviter:
+		s.listener().OnInviteeRejected(utils.StructToJsonString(elem))
+	case ended && ringing && elem.Action == constant.SignalingTimeout:
+		s.listener().OnInvitationTimeout(utils.StructToJsonString(elem))
+	case ended && ringing:
+		s.listener().OnInvitationCancelled(utils.StructToJsonString(elem))
+	}
+	if connected {
+		s.listener().OnRoomParticipantConnected(utils.StructToJsonString(room))
+	}
+	if disconnected {
+		s.listener().OnRoomParticipantDisconnected(utils.StructToJsonString(room))
+	}
+	if sendRoster {
+		roster := &sdk_struct.SignalingElem{Action: constant.SignalingRoster, Invitation: cl.invitation, UserID: s.conv.loginUserID, Participants: room.Participants}
+		if err := s.send(ctx, cl.invitation, roster); err != nil {
+			log.ZWarn(ctx, "send group call roster failed", err, "roomID", cl.invitation.RoomID)
+		}
+	}
+	s.callOver(ctx, cl.invitation, callElem)
+}
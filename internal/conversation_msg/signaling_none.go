@@ -0,0 +1,41 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build no_signaling
+// +build no_signaling
+
+package conversation_msg
+
+import (
+	"context"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+
+	"github.com/openimsdk/protocol/sdkws"
+)
+
+// signaling is left out of the build by the no_signaling tag, the signals received are dropped.
+type signaling struct{}
+
+func newSignaling(*Conversation) *signaling {
+	return &signaling{}
+}
+
+func (s *signaling) onNewMsg(ctx context.Context, msg *sdkws.MsgData) {
+	log.ZDebug(ctx, "signaling is not built, signal dropped", "clientMsgID", msg.ClientMsgID)
+}
+
+func (s *signaling) onData(ctx context.Context, msg *sdkws.MsgData) {
+	log.ZDebug(ctx, "signaling is not built, signaling data dropped", "clientMsgID", msg.ClientMsgID)
+}
@@ -0,0 +1,116 @@
+//go:build !no_signaling
+// +build !no_signaling
+
+package conversation_msg
+
+import (
+	"testing"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+func TestNextCallState(t *testing.T) {
+	tests := []struct {
+		state     callState
+		action    string
+		byInviter bool
+		next      callState
+		ok        bool
+	}{
+		{callCalling, constant.SignalingAccept, false, callInCall, true},
+		{callRinging, constant.SignalingAccept, false, callInCall, true},
+		{callCalling, constant.SignalingAccept, true, callInCall, false},
+		{callRinging, constant.SignalingReject, false, callEnded, true},
+		{callCalling, constant.SignalingCancel, true, callEnded, true},
+		{callRinging, constant.SignalingCancel, false, callEnded, false},
+		{callRinging, constant.SignalingTimeout, false, callEnded, true},
+		{callInCall, constant.SignalingTimeout, true, callEnded, false},
+		{callInCall, constant.SignalingHungUp, false, callEnded, true},
+		{callInCall, constant.SignalingHungUp, true, callEnded, true},
+		{callCalling, constant.SignalingHungUp, true, callEnded, false},
+		{callInCall, constant.SignalingCancel, true, callEnded, false},
+		{callInCall, "unknown", true, callInCall, false},
+		{callCalling, constant.SignalingBusy, false, callEnded, true},
+		{callRinging, constant.SignalingBusy, false, callEnded, false},
+		{callElsewhere, constant.SignalingAccept, false, callElsewhere, true},
+		{callElsewhere, constant.SignalingHungUp, true, callEnded, true},
+		{callElsewhere, constant.SignalingTimeout, false, callEnded, true},
+		{callElsewhere, constant.SignalingJoin, false, callElsewhere, false},
+	}
+	for _, tt := range tests {
+		next, ok := nextCallState(tt.state, tt.action, tt.byInviter)
+		if ok != tt.ok || (ok && next != tt.next) {
+			t.Errorf("nextCallState(%d, %s, %v) = %d, %v", tt.state, tt.action, tt.byInviter, next, ok)
+		}
+	}
+}
+
+func TestNextGroupCallState(t *testing.T) {
+	tests := []struct {
+		state     callState
+		action    string
+		byInviter bool
+		alone     bool
+		next      callState
+		ok        bool
+	}{
+		{callRinging, constant.SignalingAccept, false, false, callInCall, true},
+		{callOngoing, constant.SignalingAccept, false, false, callInCall, false},
+		{callOngoing, constant.SignalingJoin, false, false, callInCall, true},
+		{callRinging, constant.SignalingReject, false, false, callOngoing, true},
+		{callRinging, constant.SignalingTimeout, false, false, callOngoing, true},
+		{callInCall, constant.SignalingTimeout, true, true, callEnded, true},
+		{callInCall, constant.SignalingTimeout, true, false, callEnded, false},
+		{callInCall, constant.SignalingCancel, true, true, callEnded, true},
+		{callInCall, constant.SignalingCancel, false, true, callEnded, false},
+		{callInCall, constant.SignalingHungUp, false, false, callOngoing, true},
+		{callOngoing, constant.SignalingHungUp, false, false, callOngoing, false},
+	}
+	for _, tt := range tests {
+		next, ok := nextGroupCallState(tt.state, tt.action, tt.byInviter, tt.alone)
+		if ok != tt.ok || (ok && next != tt.next) {
+			t.Errorf("nextGroupCallState(%d, %s, %v, %v) = %d, %v", tt.state, tt.action, tt.byInviter, tt.alone, next, ok)
+		}
+	}
+}
+
+func TestGroupCallRoster(t *testing.T) {
+	c := &Conversation{loginUserID: "self"}
+	s := newSignaling(c)
+	invitation := &sdk_struct.SignalingInvitation{RoomID: "room", GroupID: "group", InviterUserID: "a", InitiateTime: 1}
+	s.calls["room"] = &call{invitation: invitation, state: callOngoing, participants: map[string]int64{"a": 1, "b": 2}}
+	room := s.room(s.calls["room"], "")
+	if len(room.Participants) != 2 || room.Participants[0].UserID != "a" || room.Participants[1].UserID != "b" {
+		t.Fatal(room.Participants)
+	}
+	cl := s.calls["room"]
+	if callElem := s.applyInGroup(cl, callInCall, constant.SignalingJoin); callElem != nil || len(cl.participants) != 3 {
+		t.Fatal(callElem, cl.participants)
+	}
+	callElem := s.applyInGroup(cl, callOngoing, constant.SignalingHungUp)
+	if callElem == nil || callElem.State != constant.CallCompleted || len(cl.participants) != 2 || s.calls["room"] != cl {
+		t.Fatal(callElem, cl.participants)
+	}
+}
+
+func TestSignalingBusy(t *testing.T) {
+	c := &Conversation{loginUserID: "self"}
+	s := newSignaling(c)
+	if here, elsewhere := s.busy(); here || elsewhere {
+		t.Fatal(here, elsewhere)
+	}
+	s.calls["ringing"] = &call{state: callRinging}
+	s.calls["group"] = &call{state: callOngoing, participants: map[string]int64{"a": 1}}
+	if here, elsewhere := s.busy(); here || elsewhere {
+		t.Fatal(here, elsewhere)
+	}
+	s.calls["group"].participants["self"] = 2
+	if here, elsewhere := s.busy(); here || !elsewhere {
+		t.Fatal(here, elsewhere)
+	}
+	s.calls["call"] = &call{state: callInCall}
+	if here, _ := s.busy(); !here {
+		t.Fatal(here)
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversation_msg
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/converter"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+	"github.com/openimsdk/protocol/sdkws"
+)
+
+// SetSignMessages sets whether the messages sent are signed with the key of the device, it needs e2ee enabled.
+func (c *Conversation) SetSignMessages(sign bool) {
+	c.signMessages = sign
+}
+
+// signedData is what the signature of a message covers, as it is on the wire: the content of an encrypted
+// message is the envelope.
+func signedData(msg *sdk_struct.MsgStruct) []byte {
+	data, _ := json.Marshal([]any{msg.ClientMsgID, msg.SendID, msg.RecvID, msg.GroupID, msg.SessionType, msg.ContentType, msg.Content})
+	return data
+}
+
+// sign returns the wire message with the signature of the device in its attached info, the message itself
+// when the messages are not signed.
+func (c *Conversation) sign(ctx context.Context, wire *sdk_struct.MsgStruct) (*sdk_struct.MsgStruct, error) {
+	if !c.signMessages || c.e2ee == nil || wire.ContentType == constant.Typing {
+		return wire, nil
+	}
+	signature, err := c.e2ee.Sign(ctx, signedData(wire))
+	if err != nil || signature == nil {
+		return wire, err
+	}
+	signed := *wire
+	var attachedInfo sdk_struct.AttachedInfoElem
+	if wire.AttachedInfoElem != nil {
+		attachedInfo = *wire.AttachedInfoElem
+	}
+	attachedInfo.Signature = signature
+	signed.AttachedInfoElem = &attachedInfo
+	return &signed, nil
+}
+
+// unseal verifies the signature of a received message then decrypts it, the status of the signature is kept
+// in the attached info of the message and the signature dropped.
+func (c *Conversation) unseal(ctx context.Context, conversationID string, msg *sdk_struct.MsgStruct) *sdk_struct.MsgStruct {
+	if c.e2ee != nil && msg.AttachedInfo != "" {
+		var attachedInfo sdk_struct.AttachedInfoElem
+		if err := utils.JsonStringToStruct(msg.AttachedInfo, &attachedInfo); err == nil && attachedInfo.Signature != nil {
+			attachedInfo.SignatureStatus = c.e2ee.Verify(ctx, msg.SendID, signedData(msg), attachedInfo.Signature)
+			if attachedInfo.SignatureStatus != constant.MsgSignatureValid {
+				log.ZWarn(ctx, "message signature not valid", nil, "conversationID", conversationID,
+					"clientMsgID", msg.ClientMsgID, "status", attachedInfo.SignatureStatus)
+			}
+			attachedInfo.Signature = nil
+			msg.AttachedInfo = utils.StructToJsonString(&attachedInfo)
+		}
+	}
+	return c.decrypt(ctx, conversationID, msg)
+}
+
+// unsealMsgData unseals a message pulled from the server in place, the content of an encrypted one is replaced
+// by the one decrypted.
+func (c *Conversation) unsealMsgData(ctx context.Context, conversationID string, msg *sdkws.MsgData) {
+	if c.e2ee == nil || (msg.ContentType != constant.E2EEMessage && msg.AttachedInfo == "") {
+		return
+	}
+	unsealed := c.unseal(ctx, conversationID, converter.MsgDataToMsgStruct(msg))
+	msg.ContentType, msg.Content, msg.AttachedInfo = unsealed.ContentType, []byte(unsealed.Content), unsealed.AttachedInfo
+}
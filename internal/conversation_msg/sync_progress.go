@@ -0,0 +1,132 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversation_msg
+
+import (
+	"context"
+	"sync"
+
+	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/syncer"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+// syncPhaseWeights are the shares of the phases in the progress of the whole initial sync, summing up to 100.
+var syncPhaseWeights = map[string]int{
+	constant.SyncPhaseConversations: 25,
+	constant.SyncPhaseFriends:       10,
+	constant.SyncPhaseGroups:        10,
+	constant.SyncPhaseGroupMembers:  15,
+	constant.SyncPhaseMessages:      40,
+}
+
+// syncProgress tracks the phases of the initial sync for the sync progress listener, the phases run at the
+// same time. The listener is only called when a percentage changes, not for every item.
+type syncProgress struct {
+	lock     sync.Mutex
+	listener func() open_im_sdk_callback.OnSyncProgressListener
+	phases   map[string]*sdk_struct.SyncProgress
+	percent  int
+}
+
+func newSyncProgress(listener func() open_im_sdk_callback.OnSyncProgressListener) *syncProgress {
+	return &syncProgress{listener: listener, phases: make(map[string]*sdk_struct.SyncProgress)}
+}
+
+func (p *syncProgress) Update(phase string, done, total int) {
+	if p == nil {
+		return
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	state := p.phase(phase)
+	if state.PhasePercent == 100 {
+		return
+	}
+	state.Done, state.Total = done, total
+	phasePercent := state.PhasePercent
+	if total > 0 {
+		phasePercent = done * 100 / total
+		// a phase is only complete once it is done
+		if phasePercent > 99 {
+			phasePercent = 99
+		}
+	}
+	p.notify(state, phasePercent)
+}
+
+func (p *syncProgress) Done(phase string) {
+	if p == nil {
+		return
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	state := p.phase(phase)
+	if state.Total < state.Done {
+		state.Total = state.Done
+	}
+	state.Done = state.Total
+	p.notify(state, 100)
+}
+
+// finish completes the phases that had nothing to sync.
+func (p *syncProgress) finish() {
+	if p == nil {
+		return
+	}
+	for phase := range syncPhaseWeights {
+		p.Done(phase)
+	}
+}
+
+func (p *syncProgress) phase(phase string) *sdk_struct.SyncProgress {
+	state, ok := p.phases[phase]
+	if !ok {
+		state = &sdk_struct.SyncProgress{Phase: phase}
+		p.phases[phase] = state
+	}
+	return state
+}
+
+func (p *syncProgress) notify(state *sdk_struct.SyncProgress, phasePercent int) {
+	if phasePercent == state.PhasePercent && state.Percent == p.percent && state.Total != 0 {
+		return
+	}
+	state.PhasePercent = phasePercent
+	var percent int
+	for phase, weight := range syncPhaseWeights {
+		if s, ok := p.phases[phase]; ok {
+			percent += weight * s.PhasePercent / 100
+		}
+	}
+	if percent < p.percent {
+		percent = p.percent
+	}
+	p.percent = percent
+	state.Percent = percent
+	if p.listener == nil {
+		return
+	}
+	p.listener().OnSyncProgress(utils.StructToJsonString(state))
+}
+
+// syncPhase makes fn report its progress as the phase.
+func (c *Conversation) syncPhase(phase string, fn func(ctx context.Context) error) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		return syncer.RunPhase(ctx, phase, fn)
+	}
+}
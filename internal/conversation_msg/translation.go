@@ -0,0 +1,118 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversation_msg
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/tools/errs"
+)
+
+func (c *Conversation) SetTranslator(translator func() open_im_sdk_callback.Translator) {
+	c.translator = translator
+}
+
+// GetMessageTranslation gets the translation of the text of the message to the language, the translator of the
+// app is only asked for it when it is not cached yet.
+func (c *Conversation) GetMessageTranslation(ctx context.Context, conversationID, clientMsgID, lang string) (*model_struct.LocalMessageTranslation, error) {
+	if lang == "" {
+		return nil, sdkerrs.ErrArgs.WrapMsg("lang is empty")
+	}
+	translation, err := c.db.GetMessageTranslation(ctx, clientMsgID, lang)
+	if err == nil {
+		return translation, nil
+	}
+	if !errs.ErrRecordNotFound.Is(errs.Unwrap(err)) {
+		return nil, err
+	}
+	var translator open_im_sdk_callback.Translator
+	if c.translator != nil {
+		translator = c.translator()
+	}
+	if translator == nil {
+		return nil, sdkerrs.ErrArgs.WrapMsg("no translator is set, see SetTranslator")
+	}
+	message, err := c.db.GetMessage(ctx, conversationID, clientMsgID)
+	if err != nil {
+		return nil, err
+	}
+	var texts []string
+	for _, text := range messageTexts(LocalChatLogToMsgStruct(message)) {
+		if *text != "" {
+			texts = append(texts, *text)
+		}
+	}
+	if len(texts) == 0 {
+		return nil, sdkerrs.ErrArgs.WrapMsg("the message has no text to translate", "contentType", message.ContentType)
+	}
+	cb := &translateCallback{done: make(chan struct{})}
+	log.ZDebug(ctx, "translate message", "clientMsgID", clientMsgID, "lang", lang)
+	translator.Translate(strings.Join(texts, "\n"), lang, cb)
+	select {
+	case <-ctx.Done():
+		return nil, context.Cause(ctx)
+	case <-cb.done:
+	}
+	if cb.err != nil {
+		return nil, cb.err
+	}
+	translation = &model_struct.LocalMessageTranslation{
+		ClientMsgID:    clientMsgID,
+		Lang:           lang,
+		ConversationID: conversationID,
+		Text:           cb.text,
+		CreateTime:     time.Now().UnixMilli(),
+	}
+	if err := c.db.SetMessageTranslation(ctx, translation); err != nil {
+		return nil, err
+	}
+	return translation, nil
+}
+
+// invalidateTranslations deletes the cached translations of the messages, whose text changed.
+func (c *Conversation) invalidateTranslations(ctx context.Context, clientMsgIDs ...string) {
+	if err := c.db.DeleteMessageTranslations(ctx, clientMsgIDs); err != nil {
+		log.ZWarn(ctx, "DeleteMessageTranslations failed", err, "clientMsgIDs", clientMsgIDs)
+	}
+}
+
+// translateCallback is handed to the translator, the first result or error ends the translation.
+type translateCallback struct {
+	once sync.Once
+	done chan struct{}
+	text string
+	err  error
+}
+
+func (t *translateCallback) OnError(errCode int32, errMsg string) {
+	t.once.Do(func() {
+		t.err = errs.NewCodeError(int(errCode), errMsg).Wrap()
+		close(t.done)
+	})
+}
+
+func (t *translateCallback) OnSuccess(data string) {
+	t.once.Do(func() {
+		t.text = data
+		close(t.done)
+	})
+}
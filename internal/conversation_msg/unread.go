@@ -0,0 +1,200 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversation_msg
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/tools/utils/datautil"
+)
+
+const (
+	// unreadReconcileInterval is the time after which the total unread count kept is checked against the local
+	// database again, in the background of the next read.
+	unreadReconcileInterval = 5 * time.Minute
+	// unreadReconcileBatch is the most conversations read together by a reconciliation.
+	unreadReconcileBatch = 500
+)
+
+// countedUnread is the unread count of the conversation in the total, the same as GetTotalUnreadMsgCountDB:
+// the conversations not muted which are shown.
+func countedUnread(conversation *model_struct.LocalConversation) int32 {
+	if conversation.RecvMsgOpt >= constant.ReceiveNotNotifyMessage || conversation.LatestMsgSendTime <= 0 || conversation.UnreadCount < 0 {
+		return 0
+	}
+	return conversation.UnreadCount
+}
+
+// unreadCounts keeps the unread counts of the conversations in the total, so that the total is read in memory
+// and changed by the conversations changed only. The counts are loaded from the local database on the first
+// read and checked against it every unreadReconcileInterval.
+type unreadCounts struct {
+	lock       sync.Mutex
+	loaded     bool
+	counts     map[string]int32
+	total      int32
+	reconciled time.Time
+	// reconciling is set while a reconciliation runs in the background
+	reconciling bool
+}
+
+// get returns the total, false when the counts are not loaded.
+func (u *unreadCounts) get() (int32, bool) {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+	return u.total, u.loaded
+}
+
+// set changes the counts of the conversations, nothing until the counts are loaded.
+func (u *unreadCounts) set(conversations ...*model_struct.LocalConversation) {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+	if !u.loaded {
+		return
+	}
+	for _, conversation := range conversations {
+		u.setCount(conversation.ConversationID, countedUnread(conversation))
+	}
+}
+
+// remove drops the counts of the conversations deleted.
+func (u *unreadCounts) remove(conversationIDs ...string) {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+	if !u.loaded {
+		return
+	}
+	for _, conversationID := range conversationIDs {
+		u.setCount(conversationID, 0)
+	}
+}
+
+// setCount is called with the lock.
+func (u *unreadCounts) setCount(conversationID string, count int32) {
+	u.total += count - u.counts[conversationID]
+	if count == 0 {
+		delete(u.counts, conversationID)
+	} else {
+		u.counts[conversationID] = count
+	}
+}
+
+// load replaces the counts with the ones of the local database, it returns the difference from the total kept
+// when they were loaded already.
+func (u *unreadCounts) load(counts map[string]int32) (drift int32) {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+	var total int32
+	for _, count := range counts {
+		total += count
+	}
+	if u.loaded {
+		drift = total - u.total
+	}
+	u.loaded, u.counts, u.total, u.reconciled = true, counts, total, time.Now()
+	return drift
+}
+
+// invalidate drops the counts, they are loaded again on the next read.
+func (u *unreadCounts) invalidate() {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+	u.loaded, u.counts, u.total = false, nil, 0
+}
+
+// startReconcile tells whether a reconciliation is due, and marks it running if so.
+func (u *unreadCounts) startReconcile() bool {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+	if !u.loaded || u.reconciling || time.Since(u.reconciled) < unreadReconcileInterval {
+		return false
+	}
+	u.reconciling = true
+	return true
+}
+
+func (u *unreadCounts) endReconcile() {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+	u.reconciling = false
+}
+
+// totalUnread returns the total unread count kept, loading it from the local database first if needed.
+func (c *Conversation) totalUnread(ctx context.Context) (int32, error) {
+	if total, ok := c.unread.get(); ok {
+		if c.unread.startReconcile() {
+			go func() {
+				defer c.unread.endReconcile()
+				if _, err := c.reconcileUnread(context.WithoutCancel(ctx)); err != nil {
+					log.ZWarn(ctx, "reconcile unread counts failed", err)
+				}
+			}()
+		}
+		return total, nil
+	}
+	return c.reconcileUnread(ctx)
+}
+
+// reconcileUnread loads the unread counts of the conversations from the local database, the difference from
+// the ones kept is logged.
+func (c *Conversation) reconcileUnread(ctx context.Context) (int32, error) {
+	conversationIDs, err := c.db.FindAllUnreadConversationConversationID(ctx)
+	if err != nil {
+		return 0, err
+	}
+	counts := make(map[string]int32, len(conversationIDs))
+	for start := 0; start < len(conversationIDs); start += unreadReconcileBatch {
+		conversations, err := c.db.GetMultipleConversationDB(ctx, conversationIDs[start:min(start+unreadReconcileBatch, len(conversationIDs))])
+		if err != nil {
+			return 0, err
+		}
+		for _, conversation := range conversations {
+			if count := countedUnread(conversation); count > 0 {
+				counts[conversation.ConversationID] = count
+			}
+		}
+	}
+	if drift := c.unread.load(counts); drift != 0 {
+		log.ZWarn(ctx, "unread counts drifted from the local database", nil, "drift", drift)
+	}
+	total, _ := c.unread.get()
+	return total, nil
+}
+
+// refreshUnread reads the unread counts of the conversations changed from the local database, all of them when
+// none is given.
+func (c *Conversation) refreshUnread(ctx context.Context, conversationIDs []string) error {
+	if len(conversationIDs) == 0 {
+		_, err := c.reconcileUnread(ctx)
+		return err
+	}
+	if _, ok := c.unread.get(); !ok {
+		return nil
+	}
+	conversations, err := c.db.GetMultipleConversationDB(ctx, conversationIDs)
+	if err != nil {
+		return err
+	}
+	c.unread.set(conversations...)
+	c.unread.remove(datautil.SliceSub(conversationIDs, datautil.Slice(conversations, func(conversation *model_struct.LocalConversation) string {
+		return conversation.ConversationID
+	}))...)
+	return nil
+}
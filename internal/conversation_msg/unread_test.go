@@ -0,0 +1,36 @@
+package conversation_msg
+
+import (
+	"testing"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+)
+
+func TestUnreadCounts(t *testing.T) {
+	var u unreadCounts
+	u.set(&model_struct.LocalConversation{ConversationID: "a", UnreadCount: 1, LatestMsgSendTime: 1})
+	if _, ok := u.get(); ok {
+		t.Fatal("counts are loaded")
+	}
+	if drift := u.load(map[string]int32{"a": 2, "b": 3}); drift != 0 {
+		t.Fatal(drift)
+	}
+	u.set(&model_struct.LocalConversation{ConversationID: "a", UnreadCount: 5, LatestMsgSendTime: 1},
+		&model_struct.LocalConversation{ConversationID: "b", UnreadCount: 3, LatestMsgSendTime: 1, RecvMsgOpt: constant.ReceiveNotNotifyMessage},
+		&model_struct.LocalConversation{ConversationID: "c", UnreadCount: 4})
+	if total, _ := u.get(); total != 5 {
+		t.Fatal(total)
+	}
+	u.remove("a")
+	if total, _ := u.get(); total != 0 {
+		t.Fatal(total)
+	}
+	if drift := u.load(map[string]int32{"a": 1}); drift != 1 {
+		t.Fatal(drift)
+	}
+	u.invalidate()
+	if _, ok := u.get(); ok {
+		t.Fatal("counts are not dropped")
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversation_msg
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/ccontext"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+	"github.com/openimsdk/tools/errs"
+)
+
+func CheckVideoConstraints(constraints sdk_struct.VideoTranscodeConstraints) error {
+	if constraints.MaxSide < 0 || constraints.MaxBitrate < 0 || constraints.MaxBytes < 0 {
+		return sdkerrs.ErrArgs.WrapMsg("video constraints are negative")
+	}
+	return nil
+}
+
+func (c *Conversation) SetVideoTranscoder(videoTranscoder func() open_im_sdk_callback.VideoTranscoder) {
+	c.videoTranscoder = videoTranscoder
+}
+
+// SetVideoConstraints sets what the video transcoder is asked for.
+func (c *Conversation) SetVideoConstraints(constraints sdk_struct.VideoTranscodeConstraints) {
+	c.videoConstraints = constraints
+}
+
+// transcodeVideo has the video transcoder of the app transcode the video of the message and waits for it, the
+// progress is passed on. Returns the path of the video to upload, "" to upload the original.
+func (c *Conversation) transcodeVideo(ctx context.Context, s *sdk_struct.MsgStruct, videoPath string, progress func(progress int)) (string, error) {
+	if c.videoTranscoder == nil {
+		return "", nil
+	}
+	transcoder := c.videoTranscoder()
+	if transcoder == nil {
+		return "", nil
+	}
+	if options, ok := ccontext.GetSendMsgOptions(ctx); ok && options.TranscodeVideo != nil && !*options.TranscodeVideo {
+		return "", nil
+	}
+	constraints := c.videoConstraints
+	constraints.OutputPath = c.transcodeOutputPath(s.ClientMsgID, videoPath)
+	cb := &videoTranscodeCallback{progress: progress, done: make(chan struct{})}
+	data, err := json.Marshal(constraints)
+	if err != nil {
+		return "", errs.Wrap(err)
+	}
+	log.ZDebug(ctx, "transcode video", "path", videoPath, "constraints", constraints)
+	transcoder.Transcode(videoPath, string(data), cb)
+	select {
+	case <-ctx.Done():
+		return "", context.Cause(ctx)
+	case <-cb.done:
+	}
+	if cb.err != nil {
+		return "", cb.err
+	}
+	if cb.result.Path == "" {
+		return "", nil
+	}
+	info, err := os.Stat(cb.result.Path)
+	if err != nil {
+		return "", errs.WrapMsg(err, "transcoded video not found")
+	}
+	if cb.result.VideoType != "" {
+		s.VideoElem.VideoType = cb.result.VideoType
+	}
+	if cb.result.Duration > 0 {
+		s.VideoElem.Duration = cb.result.Duration
+	}
+	s.VideoElem.VideoSize = info.Size()
+	s.VideoElem.VideoUUID = replaceExt(s.VideoElem.VideoUUID, filepath.Ext(cb.result.Path))
+	return cb.result.Path, nil
+}
+
+func (c *Conversation) transcodeOutputPath(clientMsgID, videoPath string) string {
+	return filepath.Join(c.DataDir, clientMsgID+".transcode"+filepath.Ext(videoPath))
+}
+
+// videoTranscodeCallback is handed to the video transcoder, the first result or error ends the transcode.
+type videoTranscodeCallback struct {
+	progress func(progress int)
+	once     sync.Once
+	done     chan struct{}
+	result   sdk_struct.VideoTranscodeResult
+	err      error
+}
+
+func (v *videoTranscodeCallback) OnError(errCode int32, errMsg string) {
+	v.once.Do(func() {
+		v.err = errs.NewCodeError(int(errCode), errMsg).Wrap()
+		close(v.done)
+	})
+}
+
+func (v *videoTranscodeCallback) OnSuccess(data string) {
+	v.once.Do(func() {
+		if data != "" {
+			if err := json.Unmarshal([]byte(data), &v.result); err != nil {
+				v.err = sdkerrs.ErrArgs.WrapMsg("invalid video transcode result " + err.Error())
+			}
+		}
+		close(v.done)
+	})
+}
+
+func (v *videoTranscodeCallback) OnProgress(progress int) {
+	select {
+	case <-v.done:
+	default:
+		v.progress(progress)
+	}
+}
@@ -0,0 +1,52 @@
+package conversation_msg
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/ccontext"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+type testTranscoder struct{}
+
+func (testTranscoder) Transcode(sourcePath string, constraints string, callback open_im_sdk_callback.VideoTranscodeCallback) {
+	var c sdk_struct.VideoTranscodeConstraints
+	if err := json.Unmarshal([]byte(constraints), &c); err != nil {
+		callback.OnError(1, err.Error())
+		return
+	}
+	go func() {
+		callback.OnProgress(50)
+		if err := os.WriteFile(c.OutputPath, []byte("video"), 0644); err != nil {
+			callback.OnError(1, err.Error())
+			return
+		}
+		callback.OnSuccess(`{"path":"` + c.OutputPath + `","videoType":"mp4","duration":3}`)
+	}()
+}
+
+func TestTranscodeVideo(t *testing.T) {
+	c := &Conversation{DataDir: t.TempDir() + "/"}
+	c.SetVideoTranscoder(func() open_im_sdk_callback.VideoTranscoder { return testTranscoder{} })
+	c.SetVideoConstraints(sdk_struct.VideoTranscodeConstraints{MaxSide: 720})
+	s := &sdk_struct.MsgStruct{ClientMsgID: "1", VideoElem: &sdk_struct.VideoElem{VideoUUID: "uuid.mov", VideoType: "mov"}}
+	var progress []int
+	path, err := c.transcodeVideo(context.Background(), s, "/video.mov", func(p int) { progress = append(progress, p) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != c.transcodeOutputPath("1", "/video.mov") || s.VideoElem.VideoType != "mp4" || s.VideoElem.VideoSize != 5 ||
+		s.VideoElem.Duration != 3 || s.VideoElem.VideoUUID != "uuid.mov" || len(progress) != 1 {
+		t.Fatal(path, s.VideoElem, progress)
+	}
+	// the original is sent when the message asks so
+	no := false
+	ctx := ccontext.WithSendMsgOptions(context.Background(), &sdk_struct.SendMsgOptions{TranscodeVideo: &no})
+	if path, err := c.transcodeVideo(ctx, s, "/video.mov", func(int) {}); err != nil || path != "" {
+		t.Fatal(path, err)
+	}
+}
@@ -0,0 +1,202 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package customerservice is the customer-service sessions of the login user: a customer requests an agent
+// of a queue, waits for the assignment, may be transferred to another agent, and rates the session once it is
+// closed. The customer and the agent talk in the conversation of the session, the sessions themselves are kept
+// in the local database and kept up to date by the customer-service notifications.
+package customerservice
+
+import (
+	"context"
+
+	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/api"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/db_interface"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/server_api_params"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/tools/utils/datautil"
+)
+
+type CustomerService struct {
+	loginUserID string
+	db          db_interface.DataBase
+	enabled     bool
+	listener    func() open_im_sdk_callback.OnCustomerServiceListener
+}
+
+func NewCustomerService() *CustomerService {
+	return &CustomerService{}
+}
+
+func (s *CustomerService) SetLoginUserID(loginUserID string) {
+	s.loginUserID = loginUserID
+}
+
+func (s *CustomerService) SetDataBase(db db_interface.DataBase) {
+	s.db = db
+}
+
+// SetEnabled turns the customer service on for the servers serving it, the sessions are not synced otherwise.
+func (s *CustomerService) SetEnabled(enabled bool) {
+	s.enabled = enabled
+}
+
+func (s *CustomerService) SetListener(listener func() open_im_sdk_callback.OnCustomerServiceListener) {
+	s.listener = listener
+}
+
+func (s *CustomerService) check() error {
+	if !s.enabled {
+		return sdkerrs.ErrArgs.WrapMsg("the customer service is not enabled, see EnableCustomerService of the config")
+	}
+	return nil
+}
+
+// RequestAgent asks for an agent of the queue, the default queue when it is empty. The session is serving when
+// an agent was free and waiting in the queue otherwise, OnSessionAssigned is called once an agent takes it.
+func (s *CustomerService) RequestAgent(ctx context.Context, queue, ex string) (*model_struct.LocalCustomerServiceSession, error) {
+	if err := s.check(); err != nil {
+		return nil, err
+	}
+	resp, err := api.RequestCustomerServiceAgent.Invoke(ctx, &server_api_params.RequestCustomerServiceAgentReq{Queue: queue, Ex: ex})
+	if err != nil {
+		return nil, err
+	}
+	return s.save(ctx, resp.Session)
+}
+
+// TransferSession hands the session of the agent over to another agent, or to the next free agent of the queue
+// when toAgentUserID is empty.
+func (s *CustomerService) TransferSession(ctx context.Context, sessionID, toAgentUserID, toQueue, reason string) (*model_struct.LocalCustomerServiceSession, error) {
+	if err := s.check(); err != nil {
+		return nil, err
+	}
+	if toAgentUserID == "" && toQueue == "" {
+		return nil, sdkerrs.ErrArgs.WrapMsg("a session is transferred to an agent or a queue")
+	}
+	if toAgentUserID == s.loginUserID {
+		return nil, sdkerrs.ErrArgs.WrapMsg("a session can't be transferred to its agent")
+	}
+	resp, err := api.TransferCustomerServiceSession.Invoke(ctx, &server_api_params.TransferCustomerServiceSessionReq{
+		SessionID:     sessionID,
+		ToAgentUserID: toAgentUserID,
+		ToQueue:       toQueue,
+		Reason:        reason,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.save(ctx, resp.Session)
+}
+
+// CloseSession ends the session, by the customer or by the agent.
+func (s *CustomerService) CloseSession(ctx context.Context, sessionID, reason string) (*model_struct.LocalCustomerServiceSession, error) {
+	if err := s.check(); err != nil {
+		return nil, err
+	}
+	resp, err := api.CloseCustomerServiceSession.Invoke(ctx, &server_api_params.CloseCustomerServiceSessionReq{SessionID: sessionID, Reason: reason})
+	if err != nil {
+		return nil, err
+	}
+	return s.save(ctx, resp.Session)
+}
+
+// RateSession rates the session from 1 to constant.MaxSatisfactionRating, the server puts the rating in the
+// conversation of the session as a satisfaction rating message.
+func (s *CustomerService) RateSession(ctx context.Context, sessionID string, rating int32, comment string) (*model_struct.LocalCustomerServiceSession, error) {
+	if err := s.check(); err != nil {
+		return nil, err
+	}
+	if rating < 1 || rating > constant.MaxSatisfactionRating {
+		return nil, sdkerrs.ErrArgs.WrapMsg("the rating is from 1 to 5", "rating", rating)
+	}
+	resp, err := api.RateCustomerServiceSession.Invoke(ctx, &server_api_params.RateCustomerServiceSessionReq{
+		SessionID: sessionID,
+		Rating:    rating,
+		Comment:   comment,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.save(ctx, resp.Session)
+}
+
+// GetSession gets the local session.
+func (s *CustomerService) GetSession(ctx context.Context, sessionID string) (*model_struct.LocalCustomerServiceSession, error) {
+	if err := s.check(); err != nil {
+		return nil, err
+	}
+	return s.db.GetCustomerServiceSession(ctx, sessionID)
+}
+
+// GetSessions gets the local sessions, the waiting and serving ones only when activeOnly is set, the latest
+// first.
+func (s *CustomerService) GetSessions(ctx context.Context, activeOnly bool) ([]*model_struct.LocalCustomerServiceSession, error) {
+	if err := s.check(); err != nil {
+		return nil, err
+	}
+	var states []string
+	if activeOnly {
+		states = []string{constant.CustomerServiceWaiting, constant.CustomerServiceServing}
+	}
+	return s.db.GetCustomerServiceSessions(ctx, states)
+}
+
+// SyncSessions gets the active sessions from the server, the local active sessions the server no longer has
+// were closed while the login user was offline.
+func (s *CustomerService) SyncSessions(ctx context.Context) error {
+	if !s.enabled {
+		return nil
+	}
+	resp, err := api.GetActiveCustomerServiceSessions.Invoke(ctx, &server_api_params.GetActiveCustomerServiceSessionsReq{})
+	if err != nil {
+		return err
+	}
+	local, err := s.db.GetCustomerServiceSessions(ctx, []string{constant.CustomerServiceWaiting, constant.CustomerServiceServing})
+	if err != nil {
+		return err
+	}
+	active := datautil.SliceSetAny(resp.Sessions, func(session *model_struct.LocalCustomerServiceSession) string {
+		return session.SessionID
+	})
+	sessions := resp.Sessions
+	var closed []*model_struct.LocalCustomerServiceSession
+	for _, session := range local {
+		if _, ok := active[session.SessionID]; !ok {
+			session.State = constant.CustomerServiceClosed
+			closed = append(closed, session)
+		}
+	}
+	if err := s.db.SetCustomerServiceSessions(ctx, append(sessions, closed...)); err != nil {
+		return err
+	}
+	for _, session := range closed {
+		s.listener().OnSessionClosed(utils.StructToJsonString(session))
+	}
+	return nil
+}
+
+func (s *CustomerService) save(ctx context.Context, session *model_struct.LocalCustomerServiceSession) (*model_struct.LocalCustomerServiceSession, error) {
+	if session == nil {
+		return nil, sdkerrs.ErrSdkInternal.WrapMsg("no session in the response")
+	}
+	if err := s.db.SetCustomerServiceSessions(ctx, []*model_struct.LocalCustomerServiceSession{session}); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
@@ -0,0 +1,69 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package customerservice
+
+import (
+	"context"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/errreport"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/server_api_params"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/protocol/sdkws"
+	"github.com/openimsdk/tools/errs"
+)
+
+// DoNotification handles the customer-service notifications, the session they carry replaces the local one.
+func (s *CustomerService) DoNotification(ctx context.Context, msg *sdkws.MsgData) {
+	log.ZDebug(ctx, "customer service notification", "msg", msg)
+	if !s.enabled {
+		return
+	}
+	if err := s.doNotification(ctx, msg); err != nil {
+		errreport.Report(ctx, errreport.SourceNotification, "DoCustomerServiceNotification", err, "contentType", msg.ContentType)
+	}
+}
+
+func (s *CustomerService) doNotification(ctx context.Context, msg *sdkws.MsgData) error {
+	var tips server_api_params.CustomerServiceTips
+	if err := utils.UnmarshalNotificationElem(msg.Content, &tips); err != nil {
+		return err
+	}
+	if tips.Session == nil {
+		return errs.New("no session in the notification", "contentType", msg.ContentType).Wrap()
+	}
+	switch msg.ContentType {
+	case constant.CustomerServiceQueueNotification, constant.CustomerServiceAssignedNotification,
+		constant.CustomerServiceTransferredNotification, constant.CustomerServiceClosedNotification:
+	default:
+		return errs.New("unknown content type", "contentType", msg.ContentType).Wrap()
+	}
+	if err := s.db.SetCustomerServiceSessions(ctx, []*model_struct.LocalCustomerServiceSession{tips.Session}); err != nil {
+		return err
+	}
+	switch msg.ContentType {
+	case constant.CustomerServiceQueueNotification:
+		s.listener().OnSessionQueueChanged(utils.StructToJsonString(tips.Session))
+	case constant.CustomerServiceAssignedNotification:
+		s.listener().OnSessionAssigned(utils.StructToJsonString(tips.Session))
+	case constant.CustomerServiceTransferredNotification:
+		s.listener().OnSessionTransferred(utils.StructToJsonString(&tips))
+	case constant.CustomerServiceClosedNotification:
+		s.listener().OnSessionClosed(utils.StructToJsonString(&tips))
+	}
+	return nil
+}
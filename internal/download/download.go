@@ -0,0 +1,614 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package download
+
+import (
+	"context"
+	"crypto/cipher"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/internal/third/file"
+	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/network"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+const (
+	DefaultConcurrency = 3
+	// partialSuffix names the file a download saves to until it completes
+	partialSuffix = ".download"
+	// progressInterval is the least time between two progress reports of a download
+	progressInterval = 200 * time.Millisecond
+	readBufferSize   = 32 * 1024
+)
+
+// errCorrupted is the error of a file saved that doesn't match the md5 of its download.
+var errCorrupted = errors.New("download corrupted")
+
+// Manager downloads the media queued by the app, the highest priority first and at most concurrency of them
+// at the same time. A paused, failed or interrupted download resumes from the bytes already saved.
+type Manager struct {
+	lock        sync.Mutex
+	ctx         context.Context
+	dir         string
+	concurrency int
+	running     int
+	seq         int64
+	items       map[string]*item
+	listener    func() open_im_sdk_callback.OnDownloadListener
+	onComplete  func()
+	// mediaKey encrypts the files downloaded, nil saves them plain
+	mediaKey []byte
+}
+
+type item struct {
+	info sdk_struct.DownloadInfo
+	// seq is the order the download was queued in, within a priority
+	seq    int64
+	cancel context.CancelFunc
+	// stopTo is the state of a running download stopped by a pause or a cancel
+	stopTo     string
+	reportTime time.Time
+	// speed measures the download running, nil while it isn't
+	speed *file.Speed
+}
+
+func NewManager(ctx context.Context) *Manager {
+	return &Manager{ctx: ctx, items: make(map[string]*item)}
+}
+
+// SetDir sets the directory the downloads without a file path are saved to.
+func (m *Manager) SetDir(dir string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.dir = dir
+}
+
+// CheckConcurrency checks the number of downloads at the same time set in the config, 0 keeps the default.
+func CheckConcurrency(concurrency int) error {
+	if concurrency < 0 {
+		return sdkerrs.ErrArgs.WrapMsg(fmt.Sprintf("invalid download concurrency %d", concurrency))
+	}
+	return nil
+}
+
+// SetConcurrency sets the number of downloads at the same time, 0 keeps the default.
+func (m *Manager) SetConcurrency(concurrency int) {
+	m.lock.Lock()
+	m.concurrency = concurrency
+	m.lock.Unlock()
+	m.Schedule()
+}
+
+// SetOnComplete sets the function called after each download completes.
+func (m *Manager) SetOnComplete(onComplete func()) {
+	m.onComplete = onComplete
+}
+
+func (m *Manager) SetListener(listener func() open_im_sdk_callback.OnDownloadListener) {
+	m.listener = listener
+}
+
+// Enqueue queues the download of the url. The download of a file already queued takes the new priority, a
+// file already saved completes at once.
+func (m *Manager) Enqueue(ctx context.Context, req *sdk_struct.DownloadReq) (*sdk_struct.DownloadInfo, error) {
+	u, err := url.Parse(req.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, sdkerrs.ErrArgs.WrapMsg("invalid download url " + req.URL)
+	}
+	m.lock.Lock()
+	filePath := req.FilePath
+	if filePath == "" {
+		if m.dir == "" {
+			m.lock.Unlock()
+			return nil, sdkerrs.ErrArgs.WrapMsg("no file path and no download directory")
+		}
+		filePath = filepath.Join(m.dir, utils.URLFileName(req.URL))
+	}
+	masterKey := m.mediaKey
+	m.lock.Unlock()
+	if req.Md5 != "" {
+		// a half written or changed file is downloaded again rather than handed to the app
+		err := verifyFile(filePath, req.Md5, masterKey)
+		if errors.Is(err, ErrNoMediaKey) {
+			return nil, sdkerrs.ErrArgs.WrapMsg(err.Error())
+		}
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			log.ZWarn(ctx, "saved file corrupted, download it again", err, "url", req.URL, "filePath", filePath)
+			if err := os.Remove(filePath); err != nil {
+				return nil, err
+			}
+		}
+	}
+	m.lock.Lock()
+	id := utils.Md5(filePath)
+	it, ok := m.items[id]
+	if ok && it.info.State != constant.DownloadStateCompleted {
+		it.info.Priority = req.Priority
+		if it.info.State == constant.DownloadStatePaused || it.info.State == constant.DownloadStateFailed ||
+			it.info.State == constant.DownloadStateCorrupted {
+			it.info.State = constant.DownloadStateQueued
+			it.info.Error = ""
+		}
+		info := it.info
+		m.lock.Unlock()
+		m.stateChanged(ctx, info)
+		m.Schedule()
+		return &info, nil
+	}
+	m.seq++
+	it = &item{
+		info: sdk_struct.DownloadInfo{
+			ID:       id,
+			URL:      req.URL,
+			FilePath: filePath,
+			Priority: req.Priority,
+			Prefetch: req.Prefetch,
+			Md5:      req.Md5,
+			State:    constant.DownloadStateQueued,
+			// RemainingTime is unknown until the download runs
+			RemainingTime: -1,
+		},
+		seq: m.seq,
+	}
+	if stat, err := os.Stat(filePath); err == nil && stat.Mode().IsRegular() {
+		size, _ := plainSize(filePath)
+		it.info.State = constant.DownloadStateCompleted
+		it.info.Downloaded = size
+		it.info.Total = size
+		it.info.RemainingTime = 0
+		// the media cache removes the least recently used files first, asking for the file again uses it
+		now := time.Now()
+		_ = os.Chtimes(filePath, now, now)
+	} else if size, err := plainSize(filePath + partialSuffix); err == nil {
+		it.info.Downloaded = size
+	}
+	m.items[id] = it
+	info := it.info
+	m.lock.Unlock()
+	m.stateChanged(ctx, info)
+	m.Schedule()
+	return &info, nil
+}
+
+// Pause stops the download, its saved bytes are kept for Resume.
+func (m *Manager) Pause(ctx context.Context, id string) error {
+	m.lock.Lock()
+	it, ok := m.items[id]
+	if !ok {
+		m.lock.Unlock()
+		return sdkerrs.ErrArgs.WrapMsg("download not found " + id)
+	}
+	switch it.info.State {
+	case constant.DownloadStateDownloading:
+		// the download reports the pause once stopped
+		it.stopTo = constant.DownloadStatePaused
+		it.cancel()
+		m.lock.Unlock()
+		return nil
+	case constant.DownloadStateQueued:
+		it.info.State = constant.DownloadStatePaused
+		info := it.info
+		m.lock.Unlock()
+		m.stateChanged(ctx, info)
+		return nil
+	default:
+		state := it.info.State
+		m.lock.Unlock()
+		return sdkerrs.ErrArgs.WrapMsg("download is " + state)
+	}
+}
+
+// Resume queues again a paused, failed or corrupted download.
+func (m *Manager) Resume(ctx context.Context, id string) error {
+	m.lock.Lock()
+	it, ok := m.items[id]
+	if !ok {
+		m.lock.Unlock()
+		return sdkerrs.ErrArgs.WrapMsg("download not found " + id)
+	}
+	switch it.info.State {
+	case constant.DownloadStatePaused, constant.DownloadStateFailed, constant.DownloadStateCorrupted:
+	default:
+		state := it.info.State
+		m.lock.Unlock()
+		return sdkerrs.ErrArgs.WrapMsg("download is " + state)
+	}
+	it.info.State = constant.DownloadStateQueued
+	it.info.Error = ""
+	info := it.info
+	m.lock.Unlock()
+	m.stateChanged(ctx, info)
+	m.Schedule()
+	return nil
+}
+
+// Cancel stops the download and removes its saved bytes.
+func (m *Manager) Cancel(ctx context.Context, id string) error {
+	m.lock.Lock()
+	it, ok := m.items[id]
+	if !ok {
+		m.lock.Unlock()
+		return sdkerrs.ErrArgs.WrapMsg("download not found " + id)
+	}
+	switch it.info.State {
+	case constant.DownloadStateDownloading:
+		it.stopTo = constant.DownloadStateCanceled
+		it.cancel()
+		m.lock.Unlock()
+		return nil
+	case constant.DownloadStateCompleted, constant.DownloadStateCanceled:
+		state := it.info.State
+		m.lock.Unlock()
+		return sdkerrs.ErrArgs.WrapMsg("download is " + state)
+	}
+	it.info.State = constant.DownloadStateCanceled
+	delete(m.items, id)
+	info := it.info
+	m.lock.Unlock()
+	removePartial(ctx, info.FilePath)
+	m.stateChanged(ctx, info)
+	return nil
+}
+
+// List is the downloads in the order they were queued, the completed ones included.
+func (m *Manager) List() []*sdk_struct.DownloadInfo {
+	m.lock.Lock()
+	items := make([]*item, 0, len(m.items))
+	for _, it := range m.items {
+		items = append(items, it)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].seq < items[j].seq })
+	infos := make([]*sdk_struct.DownloadInfo, 0, len(items))
+	for _, it := range items {
+		info := it.info
+		infos = append(infos, &info)
+	}
+	m.lock.Unlock()
+	return infos
+}
+
+// Schedule starts the queued downloads while fewer than the concurrency run. The prefetches wait while the
+// network is metered, Schedule is called again once they are allowed.
+func (m *Manager) Schedule() {
+	type start struct {
+		ctx context.Context
+		it  *item
+	}
+	var starts []start
+	var infos []sdk_struct.DownloadInfo
+	m.lock.Lock()
+	concurrency := m.concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+	for m.running < concurrency && m.ctx.Err() == nil {
+		it := m.next()
+		if it == nil {
+			break
+		}
+		ctx, cancel := context.WithCancel(m.ctx)
+		it.cancel = cancel
+		it.info.State = constant.DownloadStateDownloading
+		m.running++
+		starts = append(starts, start{ctx: ctx, it: it})
+		infos = append(infos, it.info)
+	}
+	m.lock.Unlock()
+	for _, info := range infos {
+		m.stateChanged(m.ctx, info)
+	}
+	for _, s := range starts {
+		go m.run(s.ctx, s.it)
+	}
+}
+
+// next is the queued download of the highest priority, the first queued of them. Holds lock.
+func (m *Manager) next() *item {
+	var next *item
+	deferred := network.Deferred(constant.MeteredMediaPrefetch)
+	for _, it := range m.items {
+		if it.info.State != constant.DownloadStateQueued || (it.info.Prefetch && deferred) {
+			continue
+		}
+		if next == nil || it.info.Priority > next.info.Priority ||
+			(it.info.Priority == next.info.Priority && it.seq < next.seq) {
+			next = it
+		}
+	}
+	return next
+}
+
+func (m *Manager) run(ctx context.Context, it *item) {
+	err := m.download(ctx, it)
+	if errors.Is(err, errCorrupted) && ctx.Err() == nil {
+		// a resumed download may join the bytes of two versions of the file, it is saved once more from the start
+		log.ZWarn(ctx, "download corrupted, download it again", err, "url", it.info.URL, "filePath", it.info.FilePath)
+		err = m.download(ctx, it)
+	}
+	m.lock.Lock()
+	m.running--
+	it.cancel = nil
+	switch {
+	case err == nil:
+		it.info.State = constant.DownloadStateCompleted
+	case it.stopTo != "":
+		it.info.State = it.stopTo
+	case m.ctx.Err() != nil:
+		// stopped by the logout, resumed from the saved bytes when queued again
+		it.info.State = constant.DownloadStatePaused
+	case errors.Is(err, errCorrupted):
+		it.info.State = constant.DownloadStateCorrupted
+		it.info.Error = err.Error()
+	default:
+		it.info.State = constant.DownloadStateFailed
+		it.info.Error = err.Error()
+	}
+	it.stopTo = ""
+	it.speed = nil
+	it.info.Speed = 0
+	if it.info.State == constant.DownloadStateCompleted {
+		it.info.RemainingTime = 0
+	} else {
+		it.info.RemainingTime = -1
+	}
+	if it.info.State == constant.DownloadStateCanceled {
+		delete(m.items, it.info.ID)
+	}
+	info := it.info
+	m.lock.Unlock()
+	if info.State == constant.DownloadStateCanceled {
+		removePartial(ctx, info.FilePath)
+	}
+	if info.State == constant.DownloadStateFailed || info.State == constant.DownloadStateCorrupted {
+		log.ZWarn(ctx, "download failed", err, "url", info.URL, "filePath", info.FilePath)
+	} else {
+		log.ZDebug(ctx, "download stopped", "url", info.URL, "filePath", info.FilePath, "state", info.State)
+	}
+	m.stateChanged(ctx, info)
+	if info.State == constant.DownloadStateCompleted && m.onComplete != nil {
+		m.onComplete()
+	}
+	m.Schedule()
+}
+
+// download saves the url to the partial file from its size on, and renames it to the file once complete.
+func (m *Manager) download(ctx context.Context, it *item) error {
+	// the url, the file path and the md5 of an item never change
+	rawURL, filePath, sum := it.info.URL, it.info.FilePath, it.info.Md5
+	masterKey := m.getMediaKey()
+	partial := filePath + partialSuffix
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return err
+	}
+	out, err := os.OpenFile(partial, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	block, header, offset, err := preparePartial(out, masterKey)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	complete := func(size int64) error {
+		if err := out.Close(); err != nil {
+			return err
+		}
+		if sum != "" {
+			if err := verifyFile(partial, sum, masterKey); err != nil {
+				removePartial(ctx, filePath)
+				return err
+			}
+		}
+		m.progress(ctx, it, size, size, true)
+		return os.Rename(partial, filePath)
+	}
+	var total int64
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		total = contentRangeTotal(resp.Header.Get("Content-Range"))
+	case http.StatusOK:
+		// the server ignores the range, the file is saved again from the start
+		if offset > 0 {
+			// the header of an encrypted file is kept
+			if err := out.Truncate(header); err != nil {
+				return err
+			}
+			if _, err := out.Seek(header, io.SeekStart); err != nil {
+				return err
+			}
+			offset = 0
+		}
+		total = resp.ContentLength
+	case http.StatusRequestedRangeNotSatisfiable:
+		// the partial file is complete when the range starts at the size of the file
+		if total = contentRangeTotal(resp.Header.Get("Content-Range")); total <= 0 || total != offset {
+			_ = out.Truncate(0)
+			return fmt.Errorf("GET %s failed, range from %d not satisfiable", rawURL, offset)
+		}
+		return complete(offset)
+	default:
+		return sdkerrs.ErrNetwork.WrapMsg(fmt.Sprintf("GET %s failed, status code %d", rawURL, resp.StatusCode))
+	}
+	if total < 0 {
+		total = 0
+	}
+	// the bytes saved by an earlier run don't count in the speed
+	m.lock.Lock()
+	it.speed = file.NewSpeed(offset)
+	m.lock.Unlock()
+	m.progress(ctx, it, offset, total, true)
+	var stream cipher.Stream
+	if block != nil {
+		stream = mediaStream(block, offset)
+	}
+	reader := network.ThrottleReader(ctx, constant.BandwidthDownload, resp.Body)
+	buf := make([]byte, readBufferSize)
+	for {
+		n, err := reader.Read(buf)
+		if n > 0 {
+			if stream != nil {
+				stream.XORKeyStream(buf[:n], buf[:n])
+			}
+			if _, err := out.Write(buf[:n]); err != nil {
+				return err
+			}
+			offset += int64(n)
+			m.progress(ctx, it, offset, total, false)
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if total > 0 && offset != total {
+		return fmt.Errorf("GET %s incomplete, %d of %d bytes", rawURL, offset, total)
+	}
+	return complete(offset)
+}
+
+// progress sets the bytes saved of the download, reported at most once per progressInterval unless forced.
+func (m *Manager) progress(ctx context.Context, it *item, downloaded, total int64, force bool) {
+	m.lock.Lock()
+	it.info.Downloaded = downloaded
+	it.info.Total = total
+	if it.speed != nil {
+		speed := it.speed.Update(downloaded, total)
+		it.info.Speed = speed.Speed
+		it.info.AverageSpeed = speed.AverageSpeed
+		it.info.RemainingTime = speed.RemainingTime
+	}
+	if !force && time.Since(it.reportTime) < progressInterval {
+		m.lock.Unlock()
+		return
+	}
+	it.reportTime = time.Now()
+	info := it.info
+	totalProgress := m.totalProgress()
+	m.lock.Unlock()
+	if m.listener == nil {
+		return
+	}
+	m.listener().OnDownloadProgress(utils.StructToJsonString(info))
+	m.listener().OnDownloadTotalProgress(utils.StructToJsonString(totalProgress))
+}
+
+// totalProgress sums the downloads not finished. Holds lock.
+func (m *Manager) totalProgress() *sdk_struct.DownloadTotalProgress {
+	var progress sdk_struct.DownloadTotalProgress
+	unknownTotal := false
+	for _, it := range m.items {
+		switch it.info.State {
+		case constant.DownloadStateDownloading:
+			progress.Active++
+		case constant.DownloadStateQueued:
+			progress.Queued++
+		case constant.DownloadStatePaused:
+		default:
+			continue
+		}
+		progress.Downloaded += it.info.Downloaded
+		progress.Total += it.info.Total
+		progress.Speed += it.info.Speed
+		unknownTotal = unknownTotal || it.info.Total == 0
+	}
+	switch {
+	case progress.Active == 0 && progress.Queued == 0 && progress.Total == progress.Downloaded:
+		progress.RemainingTime = 0
+	case unknownTotal || progress.Speed == 0:
+		progress.RemainingTime = -1
+	default:
+		progress.RemainingTime = (progress.Total - progress.Downloaded) * 1000 / progress.Speed
+	}
+	return &progress
+}
+
+func (m *Manager) stateChanged(ctx context.Context, info sdk_struct.DownloadInfo) {
+	if m.listener == nil {
+		return
+	}
+	m.lock.Lock()
+	totalProgress := m.totalProgress()
+	m.lock.Unlock()
+	m.listener().OnDownloadStateChanged(utils.StructToJsonString(info))
+	m.listener().OnDownloadTotalProgress(utils.StructToJsonString(totalProgress))
+}
+
+// verifyFile tells whether the plain content of the file matches the md5, an errCorrupted when it doesn't.
+func verifyFile(filePath, sum string, masterKey []byte) error {
+	file, err := OpenMedia(filePath, masterKey)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	h := md5.New()
+	if _, err := io.Copy(h, file); err != nil {
+		return err
+	}
+	if actual := hex.EncodeToString(h.Sum(nil)); !strings.EqualFold(actual, sum) {
+		return fmt.Errorf("%w, md5 %s, expected %s", errCorrupted, actual, sum)
+	}
+	return nil
+}
+
+func removePartial(ctx context.Context, filePath string) {
+	if err := os.Remove(filePath + partialSuffix); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.ZWarn(ctx, "remove partial download failed", err, "filePath", filePath)
+	}
+}
+
+// contentRangeTotal is the size of the file in a Content-Range header, -1 when unknown.
+func contentRangeTotal(contentRange string) int64 {
+	i := strings.LastIndexByte(contentRange, '/')
+	if i < 0 {
+		return -1
+	}
+	total, err := strconv.ParseInt(contentRange[i+1:], 10, 64)
+	if err != nil {
+		return -1
+	}
+	return total
+}
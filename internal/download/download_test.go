@@ -0,0 +1,110 @@
+package download
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+func TestResumeFromPartial(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789"), 1000)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "media.bin", time.Time{}, bytes.NewReader(content))
+	}))
+	defer server.Close()
+	filePath := filepath.Join(t.TempDir(), "media.bin")
+	// the bytes saved by an interrupted download
+	if err := os.WriteFile(filePath+partialSuffix, content[:4000], 0644); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	m := NewManager(ctx)
+	info, err := m.Enqueue(ctx, &sdk_struct.DownloadReq{URL: server.URL + "/media.bin", FilePath: filePath})
+	if err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		list := m.List()
+		if len(list) != 1 || list[0].ID != info.ID {
+			t.Fatal(list)
+		}
+		if list[0].State == constant.DownloadStateCompleted {
+			break
+		}
+		if list[0].State == constant.DownloadStateFailed || time.Now().After(deadline) {
+			t.Fatal(list[0])
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, content) {
+		t.Fatal("downloaded file differs", len(data))
+	}
+}
+
+func TestVerifyMd5(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789"), 1000)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "media.bin", time.Time{}, bytes.NewReader(content))
+	}))
+	defer server.Close()
+	ctx := context.Background()
+	m := NewManager(ctx)
+	wait := func(id string) sdk_struct.DownloadInfo {
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			for _, info := range m.List() {
+				if info.ID == id && info.State != constant.DownloadStateQueued && info.State != constant.DownloadStateDownloading {
+					return *info
+				}
+			}
+			if time.Now().After(deadline) {
+				t.Fatal("download not finished", id)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	sum := md5.Sum(content)
+	// the partial file was changed, the download is saved again from the start
+	filePath := filepath.Join(t.TempDir(), "media.bin")
+	if err := os.WriteFile(filePath+partialSuffix, bytes.Repeat([]byte("x"), 4000), 0644); err != nil {
+		t.Fatal(err)
+	}
+	info, err := m.Enqueue(ctx, &sdk_struct.DownloadReq{URL: server.URL + "/media.bin", FilePath: filePath, Md5: hex.EncodeToString(sum[:])})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info := wait(info.ID); info.State != constant.DownloadStateCompleted {
+		t.Fatal(info)
+	}
+	if data, err := os.ReadFile(filePath); err != nil || !bytes.Equal(data, content) {
+		t.Fatal("downloaded file differs", err)
+	}
+	// the file never matches, it is flagged and not saved
+	filePath = filepath.Join(t.TempDir(), "other.bin")
+	info, err = m.Enqueue(ctx, &sdk_struct.DownloadReq{URL: server.URL + "/other.bin", FilePath: filePath, Md5: strings.Repeat("0", 32)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info := wait(info.ID); info.State != constant.DownloadStateCorrupted {
+		t.Fatal(info)
+	}
+	if _, err := os.Stat(filePath); !os.IsNotExist(err) {
+		t.Fatal("corrupted file saved", err)
+	}
+}
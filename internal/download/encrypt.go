@@ -0,0 +1,365 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package download
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+)
+
+// A file downloaded while a media key is set is encrypted with AES-CTR under a key of its own, derived from
+// the media key and the random salt in the header of the file. The counter mode lets a download be resumed
+// from any byte saved and a stream be read from any offset.
+const (
+	mediaMagic      = "OIMMED01"
+	mediaSaltSize   = 16
+	mediaCheckSize  = 8
+	mediaHeaderSize = len(mediaMagic) + mediaSaltSize + mediaCheckSize
+	// decryptedDir names the directory the decrypted copies of the files are kept in until the logout
+	decryptedDir = ".decrypted"
+)
+
+var (
+	// ErrNoMediaKey is the error of an encrypted file opened while no media key is set.
+	ErrNoMediaKey = errors.New("media file encrypted, no media key set")
+	// ErrWrongMediaKey is the error of an encrypted file opened with another media key than its own.
+	ErrWrongMediaKey = errors.New("media file encrypted with another media key")
+)
+
+// mediaFileKey derives the key of a file from the media key and the salt of the file.
+func mediaFileKey(masterKey, salt []byte) []byte {
+	mac := hmac.New(sha256.New, masterKey)
+	mac.Write([]byte("openim media file key"))
+	mac.Write(salt)
+	return mac.Sum(nil)
+}
+
+// mediaKeyCheck tells the key of a file from another one without decrypting it.
+func mediaKeyCheck(key []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte("openim media key check"))
+	return mac.Sum(nil)[:mediaCheckSize]
+}
+
+// newMediaHeader is the header of a file encrypted with a new salt, and the key of the file.
+func newMediaHeader(masterKey []byte) ([]byte, []byte, error) {
+	salt := make([]byte, mediaSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, nil, err
+	}
+	key := mediaFileKey(masterKey, salt)
+	header := make([]byte, 0, mediaHeaderSize)
+	header = append(header, mediaMagic...)
+	header = append(header, salt...)
+	header = append(header, mediaKeyCheck(key)...)
+	return header, key, nil
+}
+
+// readMediaHeader is the key of an encrypted file, nil for a plain one.
+func readMediaHeader(r io.ReaderAt, masterKey []byte) ([]byte, error) {
+	header := make([]byte, mediaHeaderSize)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if string(header[:len(mediaMagic)]) != mediaMagic {
+		return nil, nil
+	}
+	if len(masterKey) == 0 {
+		return nil, ErrNoMediaKey
+	}
+	key := mediaFileKey(masterKey, header[len(mediaMagic):len(mediaMagic)+mediaSaltSize])
+	if !hmac.Equal(mediaKeyCheck(key), header[len(mediaMagic)+mediaSaltSize:]) {
+		return nil, ErrWrongMediaKey
+	}
+	return key, nil
+}
+
+// mediaStream is the key stream of the file from the offset of the plain content on.
+func mediaStream(block cipher.Block, offset int64) cipher.Stream {
+	iv := make([]byte, aes.BlockSize)
+	binary.BigEndian.PutUint64(iv[aes.BlockSize-8:], uint64(offset/aes.BlockSize))
+	stream := cipher.NewCTR(block, iv)
+	if skip := offset % aes.BlockSize; skip > 0 {
+		discard := make([]byte, skip)
+		stream.XORKeyStream(discard, discard)
+	}
+	return stream
+}
+
+// preparePartial makes the partial file match the media key, a partial saved with another key or without
+// the one set is saved again from the start. Returns the cipher of the file, nil when not encrypted, the size
+// of its header and the bytes of the plain content saved.
+func preparePartial(file *os.File, masterKey []byte) (cipher.Block, int64, int64, error) {
+	size, err := file.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if size > 0 {
+		key, err := readMediaHeader(file, masterKey)
+		switch {
+		case err == nil && (key == nil) == (len(masterKey) == 0):
+			if key == nil {
+				return nil, 0, size, nil
+			}
+			block, err := aes.NewCipher(key)
+			return block, int64(mediaHeaderSize), size - int64(mediaHeaderSize), err
+		case err != nil && !errors.Is(err, ErrNoMediaKey) && !errors.Is(err, ErrWrongMediaKey):
+			return nil, 0, 0, err
+		}
+		if err := file.Truncate(0); err != nil {
+			return nil, 0, 0, err
+		}
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return nil, 0, 0, err
+		}
+	}
+	if len(masterKey) == 0 {
+		return nil, 0, 0, nil
+	}
+	header, key, err := newMediaHeader(masterKey)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if _, err := file.Write(header); err != nil {
+		return nil, 0, 0, err
+	}
+	block, err := aes.NewCipher(key)
+	return block, int64(mediaHeaderSize), 0, err
+}
+
+// plainSize is the size of the plain content of a file, encrypted or not.
+func plainSize(filePath string) (int64, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+	stat, err := file.Stat()
+	if err != nil {
+		return 0, err
+	}
+	magic := make([]byte, len(mediaMagic))
+	if _, err := file.ReadAt(magic, 0); err == nil && bytes.Equal(magic, []byte(mediaMagic)) {
+		return stat.Size() - int64(mediaHeaderSize), nil
+	}
+	return stat.Size(), nil
+}
+
+// MediaFile reads the plain content of a downloaded file, decrypted when it was saved encrypted.
+type MediaFile struct {
+	file   *os.File
+	block  cipher.Block
+	stream cipher.Stream
+	header int64
+	offset int64
+	size   int64
+}
+
+// OpenMedia opens a downloaded file with the media key, a plain file is read as is.
+func OpenMedia(filePath string, masterKey []byte) (*MediaFile, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	stat, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	key, err := readMediaHeader(file, masterKey)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	media := &MediaFile{file: file, size: stat.Size()}
+	if key != nil {
+		if media.block, err = aes.NewCipher(key); err != nil {
+			file.Close()
+			return nil, err
+		}
+		media.header = int64(mediaHeaderSize)
+		media.size -= media.header
+	}
+	return media, nil
+}
+
+// Encrypted tells whether the file is saved encrypted.
+func (f *MediaFile) Encrypted() bool {
+	return f.block != nil
+}
+
+// Size is the size of the plain content.
+func (f *MediaFile) Size() int64 {
+	return f.size
+}
+
+func (f *MediaFile) Read(p []byte) (int, error) {
+	if f.offset >= f.size {
+		return 0, io.EOF
+	}
+	if rest := f.size - f.offset; int64(len(p)) > rest {
+		p = p[:rest]
+	}
+	n, err := f.file.ReadAt(p, f.header+f.offset)
+	if n > 0 && f.block != nil {
+		if f.stream == nil {
+			f.stream = mediaStream(f.block, f.offset)
+		}
+		f.stream.XORKeyStream(p[:n], p[:n])
+	}
+	f.offset += int64(n)
+	if errors.Is(err, io.EOF) && n > 0 {
+		err = nil
+	}
+	return n, err
+}
+
+func (f *MediaFile) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += f.offset
+	case io.SeekEnd:
+		offset += f.size
+	default:
+		return 0, errors.New("invalid whence")
+	}
+	if offset < 0 {
+		return 0, errors.New("negative position")
+	}
+	if offset != f.offset {
+		f.offset = offset
+		f.stream = nil
+	}
+	return offset, nil
+}
+
+func (f *MediaFile) Close() error {
+	return f.file.Close()
+}
+
+// SetMediaKey sets the key the files downloaded are encrypted with, an empty one saves them plain. The files
+// saved before are read as they were saved.
+func (m *Manager) SetMediaKey(masterKey string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if masterKey == "" {
+		m.mediaKey = nil
+		return
+	}
+	m.mediaKey = []byte(masterKey)
+}
+
+func (m *Manager) getMediaKey() []byte {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.mediaKey
+}
+
+// OpenFile opens a downloaded file, decrypted with the media key.
+func (m *Manager) OpenFile(filePath string) (*MediaFile, error) {
+	return OpenMedia(filePath, m.getMediaKey())
+}
+
+// DecryptedPath is the path of a plain copy of an encrypted file, kept until ClearDecrypted. The path of a
+// plain file is itself.
+func (m *Manager) DecryptedPath(ctx context.Context, filePath string) (string, error) {
+	media, err := m.OpenFile(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer media.Close()
+	if !media.Encrypted() {
+		return filePath, nil
+	}
+	source, err := media.file.Stat()
+	if err != nil {
+		return "", err
+	}
+	decrypted := filepath.Join(m.decryptedDir(), utils.Md5(filePath)+filepath.Ext(filePath))
+	if stat, err := os.Stat(decrypted); err == nil && stat.Size() == media.Size() && !stat.ModTime().Before(source.ModTime()) {
+		return decrypted, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(decrypted), 0700); err != nil {
+		return "", err
+	}
+	tmp := decrypted + partialSuffix
+	file, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(file, media); err != nil {
+		file.Close()
+		_ = os.Remove(tmp)
+		return "", err
+	}
+	if err := file.Close(); err != nil {
+		_ = os.Remove(tmp)
+		return "", err
+	}
+	if err := os.Rename(tmp, decrypted); err != nil {
+		return "", err
+	}
+	log.ZDebug(ctx, "media file decrypted", "filePath", filePath, "decrypted", decrypted)
+	return decrypted, nil
+}
+
+// ClearDecrypted removes the plain copies of the encrypted files.
+func (m *Manager) ClearDecrypted(ctx context.Context) {
+	dir := m.decryptedDir()
+	if err := os.RemoveAll(dir); err != nil {
+		log.ZWarn(ctx, "remove decrypted media failed", err, "dir", dir)
+	}
+}
+
+// DecryptedFiles is the paths of the plain copies of the encrypted files.
+func (m *Manager) DecryptedFiles() []string {
+	entries, err := os.ReadDir(m.decryptedDir())
+	if err != nil {
+		return nil
+	}
+	var files []string
+	for _, entry := range entries {
+		if entry.Type().IsRegular() {
+			files = append(files, filepath.Join(m.decryptedDir(), entry.Name()))
+		}
+	}
+	return files
+}
+
+func (m *Manager) decryptedDir() string {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.dir == "" {
+		return filepath.Join(os.TempDir(), "openim"+decryptedDir)
+	}
+	return filepath.Join(m.dir, decryptedDir)
+}
@@ -0,0 +1,112 @@
+package download
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+func TestEncryptedDownload(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789"), 1000)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "media.bin", time.Time{}, bytes.NewReader(content))
+	}))
+	defer server.Close()
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "media.bin")
+	masterKey := []byte("master key")
+	// the encrypted bytes saved by an interrupted download
+	partial, err := os.OpenFile(filePath+partialSuffix, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, _, _, err := preparePartial(partial, masterKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved := append([]byte(nil), content[:4001]...)
+	mediaStream(block, 0).XORKeyStream(saved, saved)
+	if _, err := partial.Write(saved); err != nil {
+		t.Fatal(err)
+	}
+	partial.Close()
+
+	ctx := context.Background()
+	m := NewManager(ctx)
+	m.SetDir(dir)
+	m.SetMediaKey(string(masterKey))
+	sum := md5.Sum(content)
+	info, err := m.Enqueue(ctx, &sdk_struct.DownloadReq{URL: server.URL + "/media.bin", FilePath: filePath, Md5: hex.EncodeToString(sum[:])})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Downloaded != 4001 {
+		t.Fatal("downloaded", info.Downloaded)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		info := m.List()[0]
+		if info.State == constant.DownloadStateCompleted {
+			break
+		}
+		if info.State == constant.DownloadStateFailed || info.State == constant.DownloadStateCorrupted || time.Now().After(deadline) {
+			t.Fatal(info)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != mediaHeaderSize+len(content) || bytes.Contains(data, content[:20]) {
+		t.Fatal("file not encrypted", len(data))
+	}
+
+	media, err := m.OpenFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer media.Close()
+	if plain, err := io.ReadAll(media); err != nil || !bytes.Equal(plain, content) {
+		t.Fatal("decrypted file differs", err)
+	}
+	// a stream is read from any offset
+	if _, err := media.Seek(5003, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	part := make([]byte, 10)
+	if _, err := io.ReadFull(media, part); err != nil || !bytes.Equal(part, content[5003:5013]) {
+		t.Fatal("decrypted part differs", err, string(part))
+	}
+
+	if _, err := OpenMedia(filePath, []byte("other key")); !errors.Is(err, ErrWrongMediaKey) {
+		t.Fatal("opened with another key", err)
+	}
+	if _, err := OpenMedia(filePath, nil); !errors.Is(err, ErrNoMediaKey) {
+		t.Fatal("opened without a key", err)
+	}
+
+	decrypted, err := m.DecryptedPath(ctx, filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plain, err := os.ReadFile(decrypted); err != nil || !bytes.Equal(plain, content) {
+		t.Fatal("decrypted copy differs", err)
+	}
+	m.ClearDecrypted(ctx)
+	if _, err := os.Stat(decrypted); !os.IsNotExist(err) {
+		t.Fatal("decrypted copy kept", err)
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package e2ee
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/api"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/backup"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/server_api_params"
+	"github.com/openimsdk/tools/errs"
+)
+
+// keyBackup is the keys and the sessions of the login user, the sessions go on from where they were backed
+// up: the messages the peers sent since still decrypt, the ones this device sent since do not.
+type keyBackup struct {
+	UserID   string                           `json:"userID"`
+	Keys     []*model_struct.LocalE2EEKey     `json:"keys"`
+	Sessions []*model_struct.LocalE2EESession `json:"sessions"`
+}
+
+// Backup uploads the keys and the sessions encrypted with the passphrase, it replaces the previous backup.
+func (e *E2EE) Backup(ctx context.Context, passphrase string) error {
+	if passphrase == "" {
+		return sdkerrs.ErrArgs.WrapMsg("passphrase is required")
+	}
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	b := &keyBackup{UserID: e.loginUserID}
+	for _, keyType := range []int32{constant.E2EEKeyIdentity, constant.E2EEKeySignedPreKey, constant.E2EEKeyOneTimePreKey} {
+		keys, err := e.db.GetE2EEKeysByType(ctx, keyType)
+		if err != nil {
+			return err
+		}
+		b.Keys = append(b.Keys, keys...)
+	}
+	if len(b.Keys) == 0 {
+		return sdkerrs.ErrArgs.WrapMsg("e2ee is not enabled")
+	}
+	sessions, err := e.db.GetAllE2EESessions(ctx)
+	if err != nil {
+		return err
+	}
+	b.Sessions = sessions
+	data, err := json.Marshal(b)
+	if err != nil {
+		return errs.WrapMsg(err, "json.Marshal failed")
+	}
+	sealed, err := backup.Seal(passphrase, data)
+	if err != nil {
+		return err
+	}
+	if err := api.UploadE2EEBackup.Execute(ctx, &server_api_params.UploadE2EEBackupReq{Backup: sealed}); err != nil {
+		return err
+	}
+	log.ZInfo(ctx, "e2ee keys backed up", "keys", len(b.Keys), "sessions", len(b.Sessions))
+	return nil
+}
+
+// Restore replaces the keys and the sessions of the device with the backup and publishes the restored
+// identity, the peers keep their sessions and see no key change.
+func (e *E2EE) Restore(ctx context.Context, passphrase string) error {
+	if passphrase == "" {
+		return sdkerrs.ErrArgs.WrapMsg("passphrase is required")
+	}
+	sealed, err := api.ExtractField(ctx, api.GetE2EEBackup.Invoke, &server_api_params.GetE2EEBackupReq{},
+		func(resp *server_api_params.GetE2EEBackupResp) []byte { return resp.Backup })
+	if err != nil {
+		return err
+	}
+	if len(sealed) == 0 {
+		return sdkerrs.ErrArgs.WrapMsg("there is no e2ee key backup")
+	}
+	data, err := backup.Open(passphrase, sealed)
+	if err != nil {
+		if errors.Is(err, backup.ErrWrongPassphrase) {
+			return sdkerrs.ErrArgs.WrapMsg(err.Error())
+		}
+		return err
+	}
+	var b keyBackup
+	if err := json.Unmarshal(data, &b); err != nil {
+		return errs.WrapMsg(err, "invalid e2ee key backup")
+	}
+	if b.UserID != e.loginUserID {
+		return sdkerrs.ErrArgs.WrapMsg("the e2ee key backup is of another user")
+	}
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.db.InsertE2EEKeys(ctx, b.Keys); err != nil {
+		return err
+	}
+	for _, s := range b.Sessions {
+		// a backup of when the sessions were kept per user, the next message establishes them again
+		if s.DeviceID == "" {
+			continue
+		}
+		if err := e.db.SetE2EESession(ctx, s); err != nil {
+			return err
+		}
+	}
+	e.identity = nil
+	identity, err := e.loadIdentity(ctx)
+	if err != nil {
+		return err
+	}
+	if identity == nil {
+		return sdkerrs.ErrArgs.WrapMsg("the e2ee key backup has no identity")
+	}
+	log.ZInfo(ctx, "e2ee keys restored", "keys", len(b.Keys), "sessions", len(b.Sessions))
+	return e.publish(ctx, identity, 0)
+}
@@ -0,0 +1,209 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package e2ee
+
+import (
+	"context"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/api"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	e2eecrypto "github.com/openimsdk/openim-sdk-core/v3/pkg/e2ee"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/server_api_params"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+func newDevice(userID string, identityKey, signingKey []byte) *model_struct.LocalE2EEDevice {
+	return &model_struct.LocalE2EEDevice{
+		UserID:      userID,
+		DeviceID:    e2eecrypto.DeviceID(identityKey, signingKey),
+		IdentityKey: hex.EncodeToString(identityKey),
+		SigningKey:  hex.EncodeToString(signingKey),
+		CreateTime:  time.Now().UnixMilli(),
+	}
+}
+
+// device returns the device as shown to the app, the safety number is the one with the identity of the login user.
+func (e *E2EE) device(device *model_struct.LocalE2EEDevice) *sdk_struct.E2EEDevice {
+	d := &sdk_struct.E2EEDevice{
+		UserID:     device.UserID,
+		DeviceID:   device.DeviceID,
+		Verified:   device.Verified,
+		CreateTime: device.CreateTime,
+	}
+	identityKey, err1 := hex.DecodeString(device.IdentityKey)
+	signingKey, err2 := hex.DecodeString(device.SigningKey)
+	if err1 != nil || err2 != nil {
+		return d
+	}
+	fingerprint := e2eecrypto.Fingerprint(identityKey, signingKey)
+	d.Fingerprint = hex.EncodeToString(fingerprint)
+	if e.identity != nil && device.UserID != e.loginUserID {
+		d.SafetyNumber = e2eecrypto.SafetyNumber(e2eecrypto.Fingerprint(e.identity.DHKey.Public, e.identity.SigningPublicKey()), fingerprint)
+	}
+	return d
+}
+
+// seenDevice records the device of the identity a session was established with, a device the user did not
+// have before is reported as added unless it is the first one seen. It returns whether it was reported.
+func (e *E2EE) seenDevice(ctx context.Context, userID string, identityKey, signingKey []byte) bool {
+	devices, err := e.db.GetE2EEDevices(ctx, userID)
+	if err != nil {
+		log.ZWarn(ctx, "GetE2EEDevices failed", err, "userID", userID)
+		return false
+	}
+	device := newDevice(userID, identityKey, signingKey)
+	for _, d := range devices {
+		if d.DeviceID == device.DeviceID {
+			return false
+		}
+	}
+	if err := e.db.SetE2EEDevice(ctx, device); err != nil {
+		log.ZWarn(ctx, "SetE2EEDevice failed", err, "userID", userID)
+		return false
+	}
+	if len(devices) == 0 {
+		return false
+	}
+	e.devicesChanged(&sdk_struct.E2EEDevicesChange{UserID: userID, Added: []*sdk_struct.E2EEDevice{e.device(device)}})
+	return true
+}
+
+func (e *E2EE) devicesChanged(change *sdk_struct.E2EEDevicesChange) {
+	if e.listener != nil {
+		e.listener().OnDevicesChanged(utils.StructToJsonString(change))
+	}
+}
+
+// GetDevices returns the devices of the user as the server lists them with their fingerprints, the devices
+// added and removed since they were last seen are reported, the sessions with the removed ones dropped. The verification of a device is kept for as
+// long as the device is listed.
+func (e *E2EE) GetDevices(ctx context.Context, userID string) ([]*sdk_struct.E2EEDevice, error) {
+	listed, err := api.ExtractField(ctx, api.GetE2EEDevices.Invoke, &server_api_params.GetE2EEDevicesReq{UserID: userID},
+		func(resp *server_api_params.GetE2EEDevicesResp) []*server_api_params.E2EEDevice { return resp.Devices })
+	if err != nil {
+		return nil, err
+	}
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if _, err := e.loadIdentity(ctx); err != nil {
+		return nil, err
+	}
+	known, err := e.db.GetE2EEDevices(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	knownByID := make(map[string]*model_struct.LocalE2EEDevice, len(known))
+	for _, d := range known {
+		knownByID[d.DeviceID] = d
+	}
+	change := &sdk_struct.E2EEDevicesChange{UserID: userID}
+	devices := make([]*sdk_struct.E2EEDevice, 0, len(listed))
+	for _, l := range listed {
+		device := newDevice(userID, l.IdentityKey, l.SigningKey)
+		if d, ok := knownByID[device.DeviceID]; ok {
+			delete(knownByID, device.DeviceID)
+			devices = append(devices, e.device(d))
+			continue
+		}
+		if err := e.db.SetE2EEDevice(ctx, device); err != nil {
+			return nil, err
+		}
+		devices = append(devices, e.device(device))
+		change.Added = append(change.Added, e.device(device))
+	}
+	for _, d := range knownByID {
+		if err := e.db.DeleteE2EEDevice(ctx, userID, d.DeviceID); err != nil {
+			return nil, err
+		}
+		// the messages are no longer encrypted for the device
+		if err := e.db.DeleteE2EESession(ctx, userID, d.DeviceID); err != nil {
+			return nil, err
+		}
+		change.Removed = append(change.Removed, e.device(d))
+	}
+	// the first devices seen of the user are no change
+	if len(known) > 0 && (len(change.Added) > 0 || len(change.Removed) > 0) {
+		e.devicesChanged(change)
+	}
+	return devices, nil
+}
+
+// GetSelfDevice returns the device of the login user with its fingerprint, what the other users verify.
+func (e *E2EE) GetSelfDevice(ctx context.Context) (*sdk_struct.E2EEDevice, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	identity, err := e.loadIdentity(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if identity == nil {
+		return nil, sdkerrs.ErrArgs.WrapMsg("e2ee is not enabled")
+	}
+	return e.device(newDevice(e.loginUserID, identity.DHKey.Public, identity.SigningPublicKey())), nil
+}
+
+// SetDeviceVerified marks the device of the user as verified or not, after the users compared the safety number.
+func (e *E2EE) SetDeviceVerified(ctx context.Context, userID, deviceID string, verified bool) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	device, err := e.getDevice(ctx, userID, deviceID)
+	if err != nil {
+		return err
+	}
+	device.Verified = verified
+	return e.db.SetE2EEDevice(ctx, device)
+}
+
+// VerifySafetyNumber marks the device of the user with the safety number as verified, the number scanned or
+// typed from the screen of the user. It returns false when no device of the user has it.
+func (e *E2EE) VerifySafetyNumber(ctx context.Context, userID, safetyNumber string) (bool, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if _, err := e.loadIdentity(ctx); err != nil {
+		return false, err
+	}
+	devices, err := e.db.GetE2EEDevices(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	safetyNumber = strings.Join(strings.Fields(safetyNumber), "")
+	for _, device := range devices {
+		if strings.ReplaceAll(e.device(device).SafetyNumber, " ", "") != safetyNumber {
+			continue
+		}
+		device.Verified = true
+		return true, e.db.SetE2EEDevice(ctx, device)
+	}
+	return false, nil
+}
+
+func (e *E2EE) getDevice(ctx context.Context, userID, deviceID string) (*model_struct.LocalE2EEDevice, error) {
+	devices, err := e.db.GetE2EEDevices(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, device := range devices {
+		if device.DeviceID == deviceID {
+			return device, nil
+		}
+	}
+	return nil, sdkerrs.ErrArgs.WrapMsg("unknown e2ee device", "userID", userID, "deviceID", deviceID)
+}
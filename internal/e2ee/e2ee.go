@@ -0,0 +1,576 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package e2ee encrypts the messages of the single chats end to end once the login user enabled it: the keys
+// of the user are kept in the local database and published to the server, a session is established with
+// each device of a peer on the first message and kept per device. The peers that did not enable it get the
+// messages in plain.
+package e2ee
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/api"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/db_interface"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	e2eecrypto "github.com/openimsdk/openim-sdk-core/v3/pkg/e2ee"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/server_api_params"
+	"github.com/openimsdk/tools/errs"
+)
+
+const (
+	// preKeyBatch is the one time pre keys published at a time
+	preKeyBatch = 100
+	// preKeyLow is the count of the one time pre keys left on the server below which more are published
+	preKeyLow = 20
+)
+
+// payload is what is encrypted of a message, its content as sent in plain.
+type payload struct {
+	ContentType int32  `json:"contentType"`
+	Content     string `json:"content"`
+}
+
+type E2EE struct {
+	loginUserID string
+	db          db_interface.DataBase
+	listener    func() open_im_sdk_callback.OnE2EEListener
+
+	// lock serializes the changes of the keys and the sessions
+	lock     sync.Mutex
+	identity *e2eecrypto.Identity
+	// fetchedDevices are the users whose devices were fetched to verify a signature
+	fetchedDevices map[string]struct{}
+}
+
+func NewE2EE() *E2EE {
+	return &E2EE{}
+}
+
+func (e *E2EE) SetLoginUserID(loginUserID string) {
+	e.loginUserID = loginUserID
+}
+
+func (e *E2EE) SetDataBase(db db_interface.DataBase) {
+	e.db = db
+}
+
+func (e *E2EE) SetListener(listener func() open_im_sdk_callback.OnE2EEListener) {
+	e.listener = listener
+}
+
+// loadIdentity returns the identity of the login user, nil when the encryption is not enabled.
+func (e *E2EE) loadIdentity(ctx context.Context) (*e2eecrypto.Identity, error) {
+	if e.identity != nil {
+		return e.identity, nil
+	}
+	keys, err := e.db.GetE2EEKeysByType(ctx, constant.E2EEKeyIdentity)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	var identity e2eecrypto.Identity
+	if err := json.Unmarshal([]byte(keys[len(keys)-1].KeyData), &identity); err != nil {
+		return nil, errs.WrapMsg(err, "invalid e2ee identity")
+	}
+	e.identity = &identity
+	return e.identity, nil
+}
+
+// Enable generates the keys of the login user and publishes them, the single chats with the users who
+// enabled it are encrypted from then on. The keys of a user who enabled it before are published again.
+func (e *E2EE) Enable(ctx context.Context) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	identity, err := e.loadIdentity(ctx)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UnixMilli()
+	if identity == nil {
+		if identity, err = e2eecrypto.GenerateIdentity(); err != nil {
+			return err
+		}
+		key := &model_struct.LocalE2EEKey{KeyType: constant.E2EEKeyIdentity, KeyID: e.loginUserID, CreateTime: now}
+		if key.KeyData, err = marshal(identity); err != nil {
+			return err
+		}
+		if err := e.db.InsertE2EEKeys(ctx, []*model_struct.LocalE2EEKey{key}); err != nil {
+			return err
+		}
+		e.identity = identity
+	}
+	return e.publish(ctx, identity, preKeyBatch)
+}
+
+// IsEnabled reports whether the login user enabled the encryption.
+func (e *E2EE) IsEnabled(ctx context.Context) (bool, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	identity, err := e.loadIdentity(ctx)
+	return identity != nil, err
+}
+
+// ReplenishPreKeys publishes more one time pre keys when the server is running out of them, called at login.
+func (e *E2EE) ReplenishPreKeys(ctx context.Context) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	identity, err := e.loadIdentity(ctx)
+	if err != nil || identity == nil {
+		return err
+	}
+	count, err := api.ExtractField(ctx, api.GetE2EEPreKeyCount.Invoke, &server_api_params.GetE2EEPreKeyCountReq{},
+		func(resp *server_api_params.GetE2EEPreKeyCountResp) int32 { return resp.Count })
+	if err != nil {
+		return err
+	}
+	if count >= preKeyLow {
+		return nil
+	}
+	log.ZInfo(ctx, "replenish e2ee one time pre keys", "left", count)
+	return e.publish(ctx, identity, preKeyBatch-int(count))
+}
+
+// publish uploads the identity, the current signed pre key and n new one time pre keys, a signed pre key is
+// generated when there is none.
+func (e *E2EE) publish(ctx context.Context, identity *e2eecrypto.Identity, n int) error {
+	now := time.Now().UnixMilli()
+	var newKeys []*model_struct.LocalE2EEKey
+	signedPreKeys, err := e.db.GetE2EEKeysByType(ctx, constant.E2EEKeySignedPreKey)
+	if err != nil {
+		return err
+	}
+	var (
+		signedPreKeyID string
+		signedPreKey   *e2eecrypto.KeyPair
+	)
+	if len(signedPreKeys) > 0 {
+		last := signedPreKeys[len(signedPreKeys)-1]
+		signedPreKeyID = last.KeyID
+		if err := json.Unmarshal([]byte(last.KeyData), &signedPreKey); err != nil {
+			return errs.WrapMsg(err, "invalid e2ee signed pre key")
+		}
+	} else {
+		key, err := newKey(constant.E2EEKeySignedPreKey, now)
+		if err != nil {
+			return err
+		}
+		newKeys = append(newKeys, key.record)
+		signedPreKeyID, signedPreKey = key.record.KeyID, key.pair
+	}
+	req := &server_api_params.UploadE2EEKeysReq{
+		IdentityKey:           identity.DHKey.Public,
+		SigningKey:            identity.SigningPublicKey(),
+		SignedPreKeyID:        signedPreKeyID,
+		SignedPreKey:          signedPreKey.Public,
+		SignedPreKeySignature: identity.SignPreKey(signedPreKey.Public),
+	}
+	for i := 0; i < n; i++ {
+		key, err := newKey(constant.E2EEKeyOneTimePreKey, now)
+		if err != nil {
+			return err
+		}
+		newKeys = append(newKeys, key.record)
+		req.OneTimePreKeys = append(req.OneTimePreKeys, &server_api_params.E2EEPreKey{KeyID: key.record.KeyID, PublicKey: key.pair.Public})
+	}
+	// the private keys first, the messages of the peers using the published ones must decrypt
+	if err := e.db.InsertE2EEKeys(ctx, newKeys); err != nil {
+		return err
+	}
+	return api.UploadE2EEKeys.Execute(ctx, req)
+}
+
+type generatedKey struct {
+	record *model_struct.LocalE2EEKey
+	pair   *e2eecrypto.KeyPair
+}
+
+func newKey(keyType int32, now int64) (*generatedKey, error) {
+	pair, err := e2eecrypto.GenerateKeyPair()
+	if err != nil {
+		return nil, err
+	}
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	data, err := marshal(pair)
+	if err != nil {
+		return nil, err
+	}
+	return &generatedKey{
+		record: &model_struct.LocalE2EEKey{KeyType: keyType, KeyID: hex.EncodeToString(id), KeyData: data, CreateTime: now},
+		pair:   pair,
+	}, nil
+}
+
+func (e *E2EE) getKeyPair(ctx context.Context, keyType int32, keyID string) (*e2eecrypto.KeyPair, error) {
+	key, err := e.db.GetE2EEKey(ctx, keyType, keyID)
+	if err != nil {
+		return nil, err
+	}
+	var pair e2eecrypto.KeyPair
+	if err := json.Unmarshal([]byte(key.KeyData), &pair); err != nil {
+		return nil, errs.WrapMsg(err, "invalid e2ee key", "keyType", keyType)
+	}
+	return &pair, nil
+}
+
+// session is the sessions with a device of a peer, identityKey is the identity of the device.
+type session struct {
+	userID      string
+	deviceID    string
+	identityKey []byte
+	record      *e2eecrypto.Record
+}
+
+func (e *E2EE) getSession(ctx context.Context, userID, deviceID string) (*session, error) {
+	local, err := e.db.GetE2EESession(ctx, userID, deviceID)
+	if err != nil {
+		if errs.ErrRecordNotFound.Is(errs.Unwrap(err)) {
+			return &session{userID: userID, deviceID: deviceID, record: &e2eecrypto.Record{}}, nil
+		}
+		return nil, err
+	}
+	return toSession(local)
+}
+
+// getSessions returns the sessions with the devices of the user.
+func (e *E2EE) getSessions(ctx context.Context, userID string) ([]*session, error) {
+	locals, err := e.db.GetE2EESessions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	sessions := make([]*session, 0, len(locals))
+	for _, local := range locals {
+		s, err := toSession(local)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, s)
+	}
+	return sessions, nil
+}
+
+func toSession(local *model_struct.LocalE2EESession) (*session, error) {
+	s := &session{userID: local.UserID, deviceID: local.DeviceID, record: &e2eecrypto.Record{}}
+	var err error
+	if s.identityKey, err = hex.DecodeString(local.IdentityKey); err != nil {
+		return nil, errs.WrapMsg(err, "invalid e2ee session", "userID", local.UserID, "deviceID", local.DeviceID)
+	}
+	if err := json.Unmarshal([]byte(local.Record), s.record); err != nil {
+		return nil, errs.WrapMsg(err, "invalid e2ee session", "userID", local.UserID, "deviceID", local.DeviceID)
+	}
+	return s, nil
+}
+
+func (e *E2EE) setSession(ctx context.Context, s *session) error {
+	record, err := marshal(s.record)
+	if err != nil {
+		return err
+	}
+	return e.db.SetE2EESession(ctx, &model_struct.LocalE2EESession{
+		UserID:      s.userID,
+		DeviceID:    s.deviceID,
+		IdentityKey: hex.EncodeToString(s.identityKey),
+		Record:      record,
+		UpdateTime:  time.Now().UnixMilli(),
+	})
+}
+
+// seen records the device a session was established with, the listener is told of a new identity key when
+// the user had other devices before: the user reinstalled or added a device, or someone else is in the middle.
+func (e *E2EE) seen(ctx context.Context, s *session, identityKey, signingKey []byte) {
+	if e.seenDevice(ctx, s.userID, identityKey, signingKey) {
+		log.ZWarn(ctx, "e2ee identity key changed", nil, "userID", s.userID, "deviceID", s.deviceID)
+		if e.listener != nil {
+			e.listener().OnIdentityKeyChanged(s.userID)
+		}
+	}
+	s.identityKey = identityKey
+}
+
+// selfDeviceID is the device ID of the identity of the login user.
+func selfDeviceID(identity *e2eecrypto.Identity) string {
+	return e2eecrypto.DeviceID(identity.DHKey.Public, identity.SigningPublicKey())
+}
+
+// Encrypt returns the content of the encrypted message to the user, ok is false when the message is to be
+// sent in plain: the encryption is not enabled or the user did not enable it. The message is encrypted for
+// each device of the user, the bundles of the devices without a session are fetched first.
+func (e *E2EE) Encrypt(ctx context.Context, userID string, contentType int32, content string) (envelope string, ok bool, err error) {
+	enabled, missing, err := e.devicesWithoutSession(ctx, userID)
+	if err != nil || !enabled {
+		return "", false, err
+	}
+	var bundles []*e2eecrypto.Bundle
+	if missing != nil {
+		// out of the lock, the other messages are not held up by the request
+		bundles, err = api.ExtractField(ctx, api.GetE2EEKeyBundle.Invoke, &server_api_params.GetE2EEKeyBundleReq{UserID: userID, DeviceIDs: missing},
+			func(resp *server_api_params.GetE2EEKeyBundleResp) []*e2eecrypto.Bundle { return resp.Bundles })
+		if err != nil {
+			return "", false, err
+		}
+	}
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	identity, err := e.loadIdentity(ctx)
+	if err != nil || identity == nil {
+		return "", false, err
+	}
+	sessions, err := e.getSessions(ctx, userID)
+	if err != nil {
+		return "", false, err
+	}
+	byDevice := make(map[string]*session, len(sessions))
+	for _, s := range sessions {
+		byDevice[s.deviceID] = s
+	}
+	for _, bundle := range bundles {
+		if bundle == nil || len(bundle.IdentityKey) == 0 {
+			continue
+		}
+		deviceID := e2eecrypto.DeviceID(bundle.IdentityKey, bundle.SigningKey)
+		s, ok := byDevice[deviceID]
+		if ok && s.record.Current() != nil {
+			// established by another message while the bundles were fetched
+			continue
+		}
+		if !ok {
+			s = &session{userID: userID, deviceID: deviceID, record: &e2eecrypto.Record{}}
+			byDevice[deviceID] = s
+		}
+		current, err := e2eecrypto.Initiate(identity, bundle)
+		if err != nil {
+			return "", false, errs.WrapMsg(err, "e2ee session not established", "userID", userID, "deviceID", deviceID)
+		}
+		e.seen(ctx, s, bundle.IdentityKey, bundle.SigningKey)
+		s.record.Add(current)
+	}
+	plaintext, err := marshal(&payload{ContentType: contentType, Content: content})
+	if err != nil {
+		return "", false, err
+	}
+	sealed := &e2eecrypto.Message{SenderDevice: selfDeviceID(identity), Envelopes: make(map[string]*e2eecrypto.Envelope, len(byDevice))}
+	for deviceID, s := range byDevice {
+		current := s.record.Current()
+		if current == nil {
+			continue
+		}
+		if sealed.Envelopes[deviceID], err = current.Encrypt([]byte(plaintext)); err != nil {
+			return "", false, err
+		}
+		if err := e.setSession(ctx, s); err != nil {
+			return "", false, err
+		}
+	}
+	if len(sealed.Envelopes) == 0 {
+		return "", false, nil
+	}
+	envelope, err = marshal(sealed)
+	return envelope, err == nil, err
+}
+
+// devicesWithoutSession returns whether the encryption is enabled and the known devices of the user without a
+// session, missing is nil when the bundles need not be fetched and empty when the ones of all the devices do:
+// no session was established with the user yet.
+func (e *E2EE) devicesWithoutSession(ctx context.Context, userID string) (enabled bool, missing []string, err error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	identity, err := e.loadIdentity(ctx)
+	if err != nil || identity == nil {
+		return false, nil, err
+	}
+	sessions, err := e.getSessions(ctx, userID)
+	if err != nil {
+		return false, nil, err
+	}
+	established := make(map[string]struct{}, len(sessions))
+	for _, s := range sessions {
+		if s.record.Current() != nil {
+			established[s.deviceID] = struct{}{}
+		}
+	}
+	if len(established) == 0 {
+		return true, []string{}, nil
+	}
+	devices, err := e.db.GetE2EEDevices(ctx, userID)
+	if err != nil {
+		return false, nil, err
+	}
+	for _, device := range devices {
+		if _, ok := established[device.DeviceID]; !ok {
+			missing = append(missing, device.DeviceID)
+		}
+	}
+	return true, missing, nil
+}
+
+// Decrypt returns the content type and the content of the encrypted message from the user, the envelope of
+// this device is decrypted with the session of the device of the sender, a pre key envelope establishes a
+// new session. The key of the message is kept by its client message ID, the message pulled again decrypts.
+func (e *E2EE) Decrypt(ctx context.Context, userID, clientMsgID, content string) (contentType int32, plain string, err error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	identity, err := e.loadIdentity(ctx)
+	if err != nil {
+		return 0, "", err
+	}
+	if identity == nil {
+		return 0, "", errs.New("e2ee is not enabled").Wrap()
+	}
+	var sealed e2eecrypto.Message
+	if err := json.Unmarshal([]byte(content), &sealed); err != nil {
+		return 0, "", errs.WrapMsg(err, "invalid e2ee message")
+	}
+	envelope := sealed.Envelopes[selfDeviceID(identity)]
+	if envelope == nil {
+		return 0, "", errs.New("the e2ee message is not encrypted for this device").Wrap()
+	}
+	plaintext, err := e.decryptKept(ctx, clientMsgID, envelope)
+	if err != nil {
+		return 0, "", err
+	}
+	if plaintext == nil {
+		if plaintext, err = e.decryptEnvelope(ctx, identity, userID, clientMsgID, sealed.SenderDevice, envelope); err != nil {
+			return 0, "", err
+		}
+	}
+	var p payload
+	if err := json.Unmarshal(plaintext, &p); err != nil {
+		return 0, "", errs.WrapMsg(err, "invalid e2ee payload")
+	}
+	return p.ContentType, p.Content, nil
+}
+
+// decryptKept decrypts the envelope with the key kept of the message, nil when there is none.
+func (e *E2EE) decryptKept(ctx context.Context, clientMsgID string, envelope *e2eecrypto.Envelope) ([]byte, error) {
+	local, err := e.db.GetE2EEMessageKey(ctx, clientMsgID)
+	if err != nil {
+		if errs.ErrRecordNotFound.Is(errs.Unwrap(err)) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var key e2eecrypto.MessageKey
+	if err := json.Unmarshal([]byte(local.Key), &key); err != nil {
+		return nil, errs.WrapMsg(err, "invalid e2ee message key", "clientMsgID", clientMsgID)
+	}
+	return key.Open(envelope)
+}
+
+// decryptEnvelope decrypts the envelope with the session of the device of the sender and keeps the key of the
+// message, the device of a pre key envelope is the one of its identity.
+func (e *E2EE) decryptEnvelope(ctx context.Context, identity *e2eecrypto.Identity, userID, clientMsgID, deviceID string,
+	envelope *e2eecrypto.Envelope) ([]byte, error) {
+	if envelope.PreKey != nil {
+		deviceID = e2eecrypto.DeviceID(envelope.PreKey.IdentityKey, envelope.PreKey.SigningKey)
+	}
+	s, err := e.getSession(ctx, userID, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		plaintext []byte
+		key       *e2eecrypto.MessageKey
+	)
+	if envelope.PreKey != nil && s.record.Find(envelope.PreKey.EphemeralKey) == nil {
+		if plaintext, key, err = e.respond(ctx, identity, s, envelope); err != nil {
+			return nil, err
+		}
+	} else if plaintext, key, err = s.record.DecryptKey(envelope); err != nil {
+		return nil, err
+	}
+	if err := e.setSession(ctx, s); err != nil {
+		return nil, err
+	}
+	data, err := marshal(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := e.db.SetE2EEMessageKey(ctx, &model_struct.LocalE2EEMessageKey{
+		ClientMsgID: clientMsgID,
+		Key:         data,
+		CreateTime:  time.Now().UnixMilli(),
+	}); err != nil {
+		log.ZWarn(ctx, "keep e2ee message key failed", err, "clientMsgID", clientMsgID)
+	}
+	return plaintext, nil
+}
+
+// respond establishes the session of the pre key message and makes it the current one, the one time pre
+// key it used is removed.
+func (e *E2EE) respond(ctx context.Context, identity *e2eecrypto.Identity, s *session, sealed *e2eecrypto.Envelope) ([]byte, *e2eecrypto.MessageKey, error) {
+	preKey := sealed.PreKey
+	signedPreKey, err := e.getKeyPair(ctx, constant.E2EEKeySignedPreKey, preKey.SignedPreKeyID)
+	if err != nil {
+		return nil, nil, err
+	}
+	var oneTimePreKey *e2eecrypto.KeyPair
+	if preKey.OneTimePreKeyID != "" {
+		if oneTimePreKey, err = e.getKeyPair(ctx, constant.E2EEKeyOneTimePreKey, preKey.OneTimePreKeyID); err != nil {
+			return nil, nil, err
+		}
+	}
+	responded, err := e2eecrypto.Respond(identity, signedPreKey, oneTimePreKey, preKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	plaintext, key, err := responded.DecryptKey(sealed)
+	if err != nil {
+		return nil, nil, err
+	}
+	e.seen(ctx, s, preKey.IdentityKey, preKey.SigningKey)
+	s.record.Add(responded)
+	if oneTimePreKey != nil {
+		if err := e.db.DeleteE2EEKey(ctx, constant.E2EEKeyOneTimePreKey, preKey.OneTimePreKeyID); err != nil {
+			log.ZWarn(ctx, "delete used one time pre key failed", err, "keyID", preKey.OneTimePreKeyID)
+		}
+	}
+	return plaintext, key, nil
+}
+
+// ResetSession drops the sessions with the devices of the user, the next message establishes new ones.
+func (e *E2EE) ResetSession(ctx context.Context, userID string) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	sessions, err := e.getSessions(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, s := range sessions {
+		if err := e.db.DeleteE2EESession(ctx, userID, s.deviceID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func marshal(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", errs.WrapMsg(err, "json.Marshal failed")
+	}
+	return string(data), nil
+}
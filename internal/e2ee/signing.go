@@ -0,0 +1,94 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package e2ee
+
+import (
+	"context"
+	"encoding/hex"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	e2eecrypto "github.com/openimsdk/openim-sdk-core/v3/pkg/e2ee"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+// Sign signs the message with the identity of the device, nil when the encryption is not enabled.
+func (e *E2EE) Sign(ctx context.Context, message []byte) (*sdk_struct.MsgSignature, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	identity, err := e.loadIdentity(ctx)
+	if err != nil || identity == nil {
+		return nil, err
+	}
+	return &sdk_struct.MsgSignature{
+		DeviceID:  selfDeviceID(identity),
+		Signature: identity.Sign(message),
+	}, nil
+}
+
+// Verify returns the constant.MsgSignature* of the message of the user. The devices of the user are fetched
+// once per login when the one that signed is not known.
+func (e *E2EE) Verify(ctx context.Context, userID string, message []byte, signature *sdk_struct.MsgSignature) int32 {
+	if signature == nil {
+		return constant.MsgSignatureNone
+	}
+	signingKey, fetched := e.signingKey(ctx, userID, signature.DeviceID)
+	if signingKey == nil && !fetched {
+		if _, err := e.GetDevices(ctx, userID); err != nil {
+			log.ZWarn(ctx, "get the devices of the signer failed", err, "userID", userID)
+		}
+		signingKey, _ = e.signingKey(ctx, userID, signature.DeviceID)
+	}
+	if signingKey == nil {
+		return constant.MsgSignatureUnknownDevice
+	}
+	if !e2eecrypto.VerifyMessage(signingKey, message, signature.Signature) {
+		return constant.MsgSignatureInvalid
+	}
+	return constant.MsgSignatureValid
+}
+
+// signingKey returns the signing key of the device of the user, nil when the device is not known, and
+// whether the devices of the user were fetched this login already.
+func (e *E2EE) signingKey(ctx context.Context, userID, deviceID string) ([]byte, bool) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	_, fetched := e.fetchedDevices[userID]
+	if e.fetchedDevices == nil {
+		e.fetchedDevices = make(map[string]struct{})
+	}
+	e.fetchedDevices[userID] = struct{}{}
+	if userID == e.loginUserID {
+		if identity, err := e.loadIdentity(ctx); err == nil && identity != nil &&
+			selfDeviceID(identity) == deviceID {
+			return identity.SigningPublicKey(), fetched
+		}
+	}
+	devices, err := e.db.GetE2EEDevices(ctx, userID)
+	if err != nil {
+		log.ZWarn(ctx, "GetE2EEDevices failed", err, "userID", userID)
+		return nil, fetched
+	}
+	for _, device := range devices {
+		if device.DeviceID == deviceID {
+			signingKey, err := hex.DecodeString(device.SigningKey)
+			if err != nil {
+				return nil, fetched
+			}
+			return signingKey, fetched
+		}
+	}
+	return nil, fetched
+}
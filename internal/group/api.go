@@ -19,19 +19,22 @@ import (
 	"time"
 
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
-	"github.com/openimsdk/tools/errs"
 
 	"github.com/openimsdk/tools/utils/datautil"
 
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/datafetcher"
 
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/audit"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/page"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdk_params_callback"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
 
 	"github.com/openimsdk/protocol/group"
 	"github.com/openimsdk/protocol/sdkws"
+	"github.com/openimsdk/tools/errs"
 )
 
 func (g *Group) CreateGroup(ctx context.Context, req *group.CreateGroupReq) (*sdkws.GroupInfo, error) {
@@ -60,6 +63,9 @@ func (g *Group) CreateGroup(ctx context.Context, req *group.CreateGroupReq) (*sd
 }
 
 func (g *Group) JoinGroup(ctx context.Context, groupID, reqMsg string, joinSource int32, ex string) error {
+	if _, ok := g.guestGroupIDs[groupID]; g.guestGroupIDs != nil && !ok {
+		return sdkerrs.ErrGuestNotAllowed.WrapMsg("guest can not join group " + groupID)
+	}
 	req := &group.JoinGroupReq{GroupID: groupID, ReqMessage: reqMsg, JoinSource: joinSource, InviterUserID: g.loginUserID, Ex: ex}
 	if err := g.joinGroup(ctx, req); err != nil {
 		return err
@@ -87,6 +93,7 @@ func (g *Group) DismissGroup(ctx context.Context, groupID string) error {
 	if err := g.dismissGroup(ctx, groupID); err != nil {
 		return err
 	}
+	audit.Record(ctx, audit.ActionAdminEdit, "DismissGroup", groupID)
 	g.groupSyncMutex.Lock()
 	defer g.groupSyncMutex.Unlock()
 
@@ -105,6 +112,7 @@ func (g *Group) ChangeGroupMute(ctx context.Context, groupID string, isMute bool
 	if err != nil {
 		return err
 	}
+	audit.Record(ctx, audit.ActionAdminEdit, "ChangeGroupMute", groupID, "isMute", isMute)
 
 	g.groupSyncMutex.Lock()
 	defer g.groupSyncMutex.Unlock()
@@ -116,11 +124,17 @@ func (g *Group) ChangeGroupMute(ctx context.Context, groupID string, isMute bool
 }
 
 func (g *Group) ChangeGroupMemberMute(ctx context.Context, groupID, userID string, mutedSeconds int) error {
+	var err error
 	if mutedSeconds == 0 {
-		return g.cancelMuteGroupMember(ctx, &group.CancelMuteGroupMemberReq{GroupID: groupID, UserID: userID})
+		err = g.cancelMuteGroupMember(ctx, &group.CancelMuteGroupMemberReq{GroupID: groupID, UserID: userID})
 	} else {
-		return g.muteGroupMember(ctx, &group.MuteGroupMemberReq{GroupID: groupID, UserID: userID, MutedSeconds: uint32(mutedSeconds)})
+		err = g.muteGroupMember(ctx, &group.MuteGroupMemberReq{GroupID: groupID, UserID: userID, MutedSeconds: uint32(mutedSeconds)})
+	}
+	if err != nil {
+		return err
 	}
+	audit.Record(ctx, audit.ActionAdminEdit, "ChangeGroupMemberMute", groupID, "userID", userID, "mutedSeconds", mutedSeconds)
+	return nil
 }
 
 func (g *Group) TransferGroupOwner(ctx context.Context, groupID, newOwnerUserID string) error {
@@ -128,6 +142,7 @@ func (g *Group) TransferGroupOwner(ctx context.Context, groupID, newOwnerUserID
 	if err := g.transferGroup(ctx, req); err != nil {
 		return err
 	}
+	audit.Record(ctx, audit.ActionAdminEdit, "TransferGroupOwner", groupID, "newOwnerUserID", newOwnerUserID)
 	g.groupSyncMutex.Lock()
 	defer g.groupSyncMutex.Unlock()
 
@@ -142,6 +157,7 @@ func (g *Group) KickGroupMember(ctx context.Context, groupID string, reason stri
 	if err := g.kickGroupMember(ctx, req); err != nil {
 		return err
 	}
+	audit.Record(ctx, audit.ActionAdminEdit, "KickGroupMember", groupID, "userIDs", userIDList, "reason", reason)
 
 	g.groupSyncMutex.Lock()
 	defer g.groupSyncMutex.Unlock()
@@ -153,6 +169,7 @@ func (g *Group) SetGroupInfo(ctx context.Context, groupInfo *group.SetGroupInfoE
 	if err := g.setGroupInfo(ctx, groupInfo); err != nil {
 		return err
 	}
+	audit.Record(ctx, audit.ActionAdminEdit, "SetGroupInfo", groupInfo.GroupID)
 
 	g.groupSyncMutex.Lock()
 	defer g.groupSyncMutex.Unlock()
@@ -165,6 +182,9 @@ func (g *Group) SetGroupMemberInfo(ctx context.Context, groupMemberInfo *group.S
 	if err := g.setGroupMemberInfo(ctx, req); err != nil {
 		return err
 	}
+	if groupMemberInfo.UserID != g.loginUserID {
+		audit.Record(ctx, audit.ActionAdminEdit, "SetGroupMemberInfo", groupMemberInfo.GroupID, "userID", groupMemberInfo.UserID)
+	}
 
 	g.groupSyncMutex.Lock()
 	defer g.groupSyncMutex.Unlock()
@@ -228,6 +248,55 @@ func (g *Group) GetSpecifiedGroupsInfo(ctx context.Context, groupIDs []string) (
 	return dataFetcher.FetchMissingAndCombineLocal(ctx, groupIDs)
 }
 
+// GetGroupsInfo returns the groups in the order of the IDs, the local ones first and the others from the server.
+// The groups the server did not return, or that could not be asked for, are reported failed.
+func (g *Group) GetGroupsInfo(ctx context.Context, groupIDs []string) (*sdk_params_callback.GetGroupsInfoCallback, error) {
+	groupIDs = datautil.Distinct(groupIDs)
+	res := &sdk_params_callback.GetGroupsInfoCallback{
+		GroupList:  make([]*model_struct.LocalGroup, 0, len(groupIDs)),
+		FailedList: []*sdk_params_callback.BatchFailure{},
+	}
+	if len(groupIDs) == 0 {
+		return res, nil
+	}
+	localGroups, err := g.db.GetGroups(ctx, groupIDs)
+	if err != nil {
+		return nil, err
+	}
+	groupMap := datautil.SliceToMap(localGroups, func(e *model_struct.LocalGroup) string {
+		return e.GroupID
+	})
+	var missing []string
+	for _, groupID := range groupIDs {
+		if _, ok := groupMap[groupID]; !ok {
+			missing = append(missing, groupID)
+		}
+	}
+	var serverErr error
+	if len(missing) > 0 {
+		serverGroups, err := g.getGroupsInfoFromServer(ctx, missing)
+		if err != nil {
+			log.ZWarn(ctx, "getGroupsInfoFromServer err", err, "groupIDs", missing)
+			serverErr = err
+		}
+		for _, serverGroup := range serverGroups {
+			groupMap[serverGroup.GroupID] = ServerGroupToLocalGroup(serverGroup)
+		}
+	}
+	for _, groupID := range groupIDs {
+		switch localGroup, ok := groupMap[groupID]; {
+		case ok:
+			res.GroupList = append(res.GroupList, localGroup)
+		case serverErr != nil:
+			res.FailedList = append(res.FailedList, sdk_params_callback.NewBatchFailure(groupID, serverErr))
+		default:
+			res.FailedList = append(res.FailedList, sdk_params_callback.NewBatchFailure(groupID,
+				sdkerrs.ErrGroupIDNotFound.WrapMsg("group not found", "groupID", groupID)))
+		}
+	}
+	return res, nil
+}
+
 // GetSpecifiedGroupsInfoSafe fetches group info without writing to local storage or touching version sync.
 func (g *Group) GetSpecifiedGroupsInfoSafe(ctx context.Context, groupIDs []string) ([]*model_struct.LocalGroup, error) {
 	if len(groupIDs) == 0 {
@@ -308,10 +377,16 @@ func (g *Group) SearchGroups(ctx context.Context, param sdk_params_callback.Sear
 }
 
 func (g *Group) GetGroupMemberOwnerAndAdmin(ctx context.Context, groupID string) ([]*model_struct.LocalGroupMember, error) {
+	if err := g.syncMembersOnAccessWithLock(ctx, groupID); err != nil {
+		return nil, err
+	}
 	return g.db.GetGroupMemberOwnerAndAdminDB(ctx, groupID)
 }
 
 func (g *Group) GetGroupMemberListByJoinTimeFilter(ctx context.Context, groupID string, offset, count int32, joinTimeBegin, joinTimeEnd int64, userIDs []string) ([]*model_struct.LocalGroupMember, error) {
+	if err := g.syncMembersOnAccessWithLock(ctx, groupID); err != nil {
+		return nil, err
+	}
 	if joinTimeEnd == 0 {
 		joinTimeEnd = time.Now().UnixMilli()
 	}
@@ -351,16 +426,8 @@ func (g *Group) GetSpecifiedGroupMembersInfo(ctx context.Context, groupID string
 		return nil, err
 	}
 	if datautil.Contain(groupID, lvs.UIDList...) {
-
-		_, err := g.db.GetVersionSync(ctx, g.groupAndMemberVersionTableName(), groupID)
-		if err != nil {
-			if !errs.ErrRecordNotFound.Is(err) {
-				return nil, err
-			}
-			err := g.IncrSyncGroupAndMember(ctx, groupID)
-			if err != nil {
-				return nil, err
-			}
+		if err := g.syncMembersOnAccess(ctx, groupID); err != nil {
+			return nil, err
 		}
 	} else { // If the user is no longer in the group, return nil immediately
 		return nil, nil
@@ -412,22 +479,33 @@ func (g *Group) GetGroupMemberList(ctx context.Context, groupID string, filter,
 		return nil, err
 	}
 	if datautil.Contain(groupID, lvs.UIDList...) {
-
-		_, err := g.db.GetVersionSync(ctx, g.groupAndMemberVersionTableName(), groupID)
-		if err != nil {
-			if !errs.ErrRecordNotFound.Is(err) {
-				return nil, err
-			}
-			err := g.IncrSyncGroupAndMember(ctx, groupID)
-			if err != nil {
-				return nil, err
-			}
+		if err := g.syncMembersOnAccess(ctx, groupID); err != nil {
+			return nil, err
 		}
 	} else { // If the user is no longer in the group, return nil immediately
 		return nil, nil
 	}
 
-	dataFetcher := datafetcher.NewDataFetcher(
+	dataFetcher := g.groupMemberDataFetcher(groupID, filter)
+	switch filter {
+	case constant.GroupFilterOrdinaryUsers:
+		groupOwnerAndGroupMember, err := g.db.GetGroupMemberListSplit(ctx, groupID, constant.GroupFilterOwnerAndAdmin, 0, 100)
+		if err != nil {
+			return nil, err
+		}
+		offset = offset + int32(len(groupOwnerAndGroupMember))
+	case constant.GroupFilterAdminAndOrdinaryUsers:
+		groupOwnerAndGroupMember, err := g.db.GetGroupMemberListSplit(ctx, groupID, constant.GroupFilterOwner, 0, 100)
+		if err != nil {
+			return nil, err
+		}
+		offset = offset + int32(len(groupOwnerAndGroupMember))
+	}
+	return dataFetcher.FetchWithPagination(ctx, int(offset), int(count))
+}
+
+func (g *Group) groupMemberDataFetcher(groupID string, filter int32) *datafetcher.DataFetcher[*model_struct.LocalGroupMember] {
+	return datafetcher.NewDataFetcher(
 		g.db,
 		g.groupAndMemberVersionTableName(),
 		groupID,
@@ -466,21 +544,76 @@ func (g *Group) GetGroupMemberList(ctx context.Context, groupID string, filter,
 			return datautil.Batch(ServerGroupMemberToLocalGroupMember, serverGroupMember), nil
 		},
 	)
-	switch filter {
-	case constant.GroupFilterOrdinaryUsers:
-		groupOwnerAndGroupMember, err := g.db.GetGroupMemberListSplit(ctx, groupID, constant.GroupFilterOwnerAndAdmin, 0, 100)
+}
+
+// GetGroupMemberListByCursor gets the page of the group members of the filter following the cursor, the first page for the empty cursor.
+func (g *Group) GetGroupMemberListByCursor(ctx context.Context, groupID string, filter int32, cursor string, count int32) (*sdk_params_callback.GetGroupMemberListByCursorCallback, error) {
+	if count <= 0 {
+		return nil, sdkerrs.ErrArgs.WrapMsg("count must be greater than 0")
+	}
+	after, err := page.DecodeCursor(page.CursorGroupMembers, cursor)
+	if err != nil {
+		return nil, err
+	}
+	res := &sdk_params_callback.GetGroupMemberListByCursorCallback{GroupMemberList: []*model_struct.LocalGroupMember{}, NextCursor: cursor}
+	g.groupSyncMutex.Lock()
+	defer g.groupSyncMutex.Unlock()
+
+	lvs, err := g.db.GetVersionSync(ctx, g.groupTableName(), g.loginUserID)
+	if err != nil {
+		return nil, err
+	}
+	if !datautil.Contain(groupID, lvs.UIDList...) {
+		return res, nil
+	}
+	if err := g.syncMembersOnAccess(ctx, groupID); err != nil {
+		return nil, err
+	}
+
+	var (
+		afterID string
+		index   int
+	)
+	if after != nil {
+		afterID, index = after.ID, after.Index
+	} else {
+		// the owner and the admins lead the member list, skip those the filter leaves out as GetGroupMemberList does
+		var skipped []*model_struct.LocalGroupMember
+		switch filter {
+		case constant.GroupFilterOrdinaryUsers:
+			skipped, err = g.db.GetGroupMemberListSplit(ctx, groupID, constant.GroupFilterOwnerAndAdmin, 0, 100)
+		case constant.GroupFilterAdminAndOrdinaryUsers:
+			skipped, err = g.db.GetGroupMemberListSplit(ctx, groupID, constant.GroupFilterOwner, 0, 100)
+		}
 		if err != nil {
 			return nil, err
 		}
-		offset = offset + int32(len(groupOwnerAndGroupMember))
-	case constant.GroupFilterAdminAndOrdinaryUsers:
-		groupOwnerAndGroupMember, err := g.db.GetGroupMemberListSplit(ctx, groupID, constant.GroupFilterOwner, 0, 100)
+		index = len(skipped)
+	}
+	dataFetcher := g.groupMemberDataFetcher(groupID, filter)
+	for {
+		members, err := dataFetcher.FetchAfter(ctx, afterID, index, int(count))
 		if err != nil {
 			return nil, err
 		}
-		offset = offset + int32(len(groupOwnerAndGroupMember))
+		last := ""
+		if n := int(count) - len(res.GroupMemberList); len(members.Data) > n {
+			members.Data = members.Data[:n]
+			last = members.Data[n-1].UserID
+		}
+		res.GroupMemberList = append(res.GroupMemberList, members.Data...)
+		if len(members.UIDs) > 0 {
+			afterID, index = members.After(last)
+		}
+		res.HasMore = members.HasMore || last != ""
+		if !res.HasMore || len(res.GroupMemberList) >= int(count) {
+			break
+		}
 	}
-	return dataFetcher.FetchWithPagination(ctx, int(offset), int(count))
+	if afterID != "" {
+		res.NextCursor = (&page.Cursor{List: page.CursorGroupMembers, ID: afterID, Index: index}).Encode()
+	}
+	return res, nil
 }
 
 func (g *Group) GetGroupApplicationListAsRecipient(ctx context.Context, req *sdk_params_callback.GetGroupApplicationListAsRecipientReq) ([]*model_struct.LocalGroupRequest, error) {
@@ -500,6 +633,9 @@ func (g *Group) GetGroupApplicationListAsApplicant(ctx context.Context, req *sdk
 }
 
 func (g *Group) SearchGroupMembers(ctx context.Context, searchParam *sdk_params_callback.SearchGroupMembersParam) ([]*model_struct.LocalGroupMember, error) {
+	if err := g.syncMembersOnAccessWithLock(ctx, searchParam.GroupID); err != nil {
+		return nil, err
+	}
 	return g.db.SearchGroupMembersDB(ctx, searchParam.KeywordList[0], searchParam.GroupID, searchParam.IsSearchMemberNickname, searchParam.IsSearchUserID, searchParam.Offset, searchParam.Count)
 }
 
@@ -517,6 +653,43 @@ func (g *Group) IsJoinGroup(ctx context.Context, groupID string) (bool, error) {
 	return false, nil
 }
 
+// IsGroupMate tells whether the user is a member of one of the joined groups. The groups whose members are not
+// synced locally are asked to the server, an error is returned while the joined groups are not synced.
+func (g *Group) IsGroupMate(ctx context.Context, userID string) (bool, error) {
+	g.groupSyncMutex.Lock()
+	defer g.groupSyncMutex.Unlock()
+
+	lvs, err := g.db.GetVersionSync(ctx, g.groupTableName(), g.loginUserID)
+	if err != nil {
+		return false, err
+	}
+	var unsynced []string
+	for _, groupID := range lvs.UIDList {
+		if _, err := g.db.GetVersionSync(ctx, g.groupAndMemberVersionTableName(), groupID); err != nil {
+			if !errs.ErrRecordNotFound.Is(err) {
+				return false, err
+			}
+			unsynced = append(unsynced, groupID)
+			continue
+		}
+		if _, err := g.db.GetGroupMemberInfoByGroupIDUserID(ctx, groupID, userID); err == nil {
+			return true, nil
+		} else if !errs.ErrRecordNotFound.Is(errs.Unwrap(err)) {
+			return false, err
+		}
+	}
+	for _, groupID := range unsynced {
+		members, err := g.getDesignatedGroupMembers(ctx, groupID, []string{userID})
+		if err != nil {
+			return false, err
+		}
+		if len(members) > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 func (g *Group) GetUsersInGroup(ctx context.Context, groupID string, userIDList []string) ([]string, error) {
 	g.groupSyncMutex.Lock()
 	defer g.groupSyncMutex.Unlock()
@@ -528,6 +701,9 @@ func (g *Group) GetUsersInGroup(ctx context.Context, groupID string, userIDList
 	if !datautil.Contain(groupID, lvs.UIDList...) {
 		return nil, nil
 	}
+	if err := g.syncMembersOnAccess(ctx, groupID); err != nil {
+		return nil, err
+	}
 	lvs, err = g.db.GetVersionSync(ctx, g.groupAndMemberVersionTableName(), groupID)
 	if err != nil {
 		return nil, err
@@ -2,10 +2,11 @@ package group
 
 import (
 	"context"
+	"strings"
 
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/datafetcher"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
-	"github.com/openimsdk/tools/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
 	"github.com/openimsdk/tools/utils/datautil"
 )
 
@@ -13,6 +14,14 @@ func (g *Group) buildGroupMemberKey(groupID string, userID string) string {
 	return groupID + ":" + userID
 }
 
+// deleteGroupMembersCache drops the members of the group cached.
+func (g *Group) deleteGroupMembersCache(groupID string) {
+	prefix := groupID + ":"
+	g.groupMemberCache.DeleteCon(func(key string, _ *model_struct.LocalGroupMember) bool {
+		return strings.HasPrefix(key, prefix)
+	})
+}
+
 func (g *Group) GetGroupMembersInfoFunc(ctx context.Context, groupID string, userIDs []string,
 	fetchFunc func(ctx context.Context, missingKeys []string) ([]*model_struct.LocalGroupMember, error),
 ) (map[string]*model_struct.LocalGroupMember, error) {
@@ -26,7 +35,7 @@ func (g *Group) GetGroupMembersInfoFunc(ctx context.Context, groupID string, use
 		if member, ok := g.groupMemberCache.Load(key); ok {
 			res[userID] = member
 		} else {
-			missingKeys = append(missingKeys, userIDs...)
+			missingKeys = append(missingKeys, userID)
 		}
 	}
 
@@ -27,13 +27,13 @@ import (
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/datafetcher"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/db_interface"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/page"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/syncer"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
 	"github.com/openimsdk/protocol/group"
 	"github.com/openimsdk/protocol/sdkws"
-	"github.com/openimsdk/tools/log"
 	"github.com/openimsdk/tools/utils/datautil"
 )
 
@@ -49,10 +49,13 @@ func NewGroup(
 	g := &Group{
 		conversationEventQueue: conversationEventQueue,
 		filter:                 NewNotificationFilter(NotificationFilterCacheSize, NotificationFilterTimeout),
+		hotGroups:              newHotGroups(hotGroupSize),
 	}
 	g.initSyncer()
 	g.groupMemberCache = cache.NewCache[string, *model_struct.LocalGroupMember]()
-	g.groupInfoCache = cache.NewCache[string, *model_struct.LocalGroup]()
+	groupInfos := cache.NewTTLStore[string, *model_struct.LocalGroup](0, cache.DefaultInfoCapacity)
+	cache.Register("groupInfo", groupInfos)
+	g.groupInfoCache = cache.NewCacheWithStore(groupInfos)
 	return g
 }
 
@@ -68,6 +71,14 @@ type Group struct {
 	groupMemberCache       *cache.Cache[string, *model_struct.LocalGroupMember]
 	groupInfoCache         *cache.Cache[string, *model_struct.LocalGroup]
 	filter                 *NotificationFilter
+	hotGroups              *hotGroups
+	// guestGroupIDs are the groups a guest login may join, nil when the login user is not a guest
+	guestGroupIDs map[string]struct{}
+}
+
+// SetGroupMemberCacheStore sets the store of the group member cache, the members cached before are dropped.
+func (g *Group) SetGroupMemberCacheStore(store cache.Store[string, *model_struct.LocalGroupMember]) {
+	g.groupMemberCache = cache.NewCacheWithStore[string, *model_struct.LocalGroupMember](store)
 }
 
 func (g *Group) initSyncer() {
@@ -81,6 +92,7 @@ func (g *Group) initSyncer() {
 		}),
 		syncer.WithDelete[*model_struct.LocalGroup, group.GetJoinedGroupListResp, string](func(ctx context.Context, value *model_struct.LocalGroup) error {
 			g.groupInfoCache.Delete(value.GroupID)
+			g.deleteGroupMembersCache(value.GroupID)
 			if err := g.db.DeleteGroupAllMembers(ctx, value.GroupID); err != nil {
 				return err
 			}
@@ -166,6 +178,7 @@ func (g *Group) initSyncer() {
 			return g.db.InsertGroupMember(ctx, value)
 		}),
 		syncer.WithDelete[*model_struct.LocalGroupMember, group.GetGroupMemberListResp, [2]string](func(ctx context.Context, value *model_struct.LocalGroupMember) error {
+			g.groupMemberCache.Delete(g.buildGroupMemberKey(value.GroupID, value.UserID))
 			return g.db.DeleteGroupMember(ctx, value.GroupID, value.UserID)
 		}),
 		syncer.WithUpdate[*model_struct.LocalGroupMember, group.GetGroupMemberListResp, [2]string](func(ctx context.Context, server, local *model_struct.LocalGroupMember) error {
@@ -213,6 +226,7 @@ func (g *Group) initSyncer() {
 			return g.db.BatchInsertGroupMember(ctx, values)
 		}),
 		syncer.WithDeleteAll[*model_struct.LocalGroupMember, group.GetGroupMemberListResp, [2]string](func(ctx context.Context, groupID string) error {
+			g.deleteGroupMembersCache(groupID)
 			return g.db.DeleteGroupAllMembers(ctx, groupID)
 		}),
 		syncer.WithBatchPageReq[*model_struct.LocalGroupMember, group.GetGroupMemberListResp, [2]string](func(entityID string) page.PageReq {
@@ -273,7 +287,13 @@ func (g *Group) SetDataBase(db db_interface.DataBase) {
 	g.db = db
 }
 
+// SetGuestGroupIDs restricts joining groups to the designated groups of a guest login.
+func (g *Group) SetGuestGroupIDs(groupIDs []string) {
+	g.guestGroupIDs = datautil.SliceSet(groupIDs)
+}
+
 // SetLoginUserID sets the loginUserID field in Group struct
 func (g *Group) SetLoginUserID(loginUserID string) {
 	g.loginUserID = loginUserID
+	g.hotGroups.clear()
 }
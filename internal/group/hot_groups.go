@@ -0,0 +1,155 @@
+package group
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/golang-lru/v2/simplelru"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/tools/errs"
+)
+
+const (
+	hotGroupSize     = 32
+	memberStaleAfter = 5 * time.Minute
+)
+
+// hotGroups are the groups whose members were accessed lately, with the time their members were last synced.
+// Only the members of the hot groups are synced at login and on reconnect, the members of the other groups
+// are pulled the first time they are accessed.
+type hotGroups struct {
+	lock sync.Mutex
+	data *simplelru.LRU[string, int64]
+	// the groups whose members are being refreshed in the background
+	refreshing map[string]struct{}
+}
+
+func newHotGroups(size int) *hotGroups {
+	lru, err := simplelru.NewLRU[string, int64](size, nil)
+	if err != nil {
+		panic(err)
+	}
+	return &hotGroups{data: lru, refreshing: make(map[string]struct{})}
+}
+
+// touch marks the group accessed and tells whether its members were not synced lately.
+func (h *hotGroups) touch(groupID string) (stale bool) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	syncedAt, ok := h.data.Get(groupID)
+	if !ok {
+		h.data.Add(groupID, 0)
+		return true
+	}
+	return time.Since(time.UnixMilli(syncedAt)) > memberStaleAfter
+}
+
+// synced records the members of the group synced now.
+func (h *hotGroups) synced(groupID string) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.data.Add(groupID, time.Now().UnixMilli())
+}
+
+// seed adds the groups not accessed yet, the first one the most recently used, so that the login after a
+// restart syncs the members of the groups chatted in lately.
+func (h *hotGroups) seed(groupIDs []string) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	for i := len(groupIDs) - 1; i >= 0; i-- {
+		if !h.data.Contains(groupIDs[i]) {
+			h.data.Add(groupIDs[i], 0)
+		}
+	}
+}
+
+// startRefresh tells whether the caller refreshes the members of the group, false when a refresh is running.
+func (h *hotGroups) startRefresh(groupID string) bool {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if _, ok := h.refreshing[groupID]; ok {
+		return false
+	}
+	h.refreshing[groupID] = struct{}{}
+	return true
+}
+
+func (h *hotGroups) endRefresh(groupID string) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	delete(h.refreshing, groupID)
+}
+
+func (h *hotGroups) len() int {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.data.Len()
+}
+
+func (h *hotGroups) groupIDs() []string {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.data.Keys()
+}
+
+func (h *hotGroups) clear() {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.data.Purge()
+}
+
+// syncMembersOnAccess pulls the members of a group never synced before they are read, the members of a group
+// not synced lately are read as they are and refreshed in the background. The caller holds groupSyncMutex.
+func (g *Group) syncMembersOnAccess(ctx context.Context, groupID string) error {
+	stale := g.hotGroups.touch(groupID)
+	_, err := g.db.GetVersionSync(ctx, g.groupAndMemberVersionTableName(), groupID)
+	if err == nil {
+		if stale && g.hotGroups.startRefresh(groupID) {
+			go g.refreshGroupMembers(context.WithoutCancel(ctx), groupID)
+		}
+		return nil
+	}
+	if !errs.ErrRecordNotFound.Is(err) {
+		return err
+	}
+	return g.IncrSyncGroupAndMember(ctx, groupID)
+}
+
+func (g *Group) syncMembersOnAccessWithLock(ctx context.Context, groupID string) error {
+	g.groupSyncMutex.Lock()
+	defer g.groupSyncMutex.Unlock()
+	return g.syncMembersOnAccess(ctx, groupID)
+}
+
+func (g *Group) refreshGroupMembers(ctx context.Context, groupID string) {
+	defer g.hotGroups.endRefresh(groupID)
+	g.groupSyncMutex.Lock()
+	defer g.groupSyncMutex.Unlock()
+	if err := g.IncrSyncGroupAndMember(ctx, groupID); err != nil {
+		log.ZWarn(ctx, "refresh group members failed", err, "groupID", groupID)
+	}
+}
+
+// seedHotGroups fills the empty hot groups, like after a restart, with the groups of the most recent conversations.
+func (g *Group) seedHotGroups(ctx context.Context) error {
+	if g.hotGroups.len() > 0 {
+		return nil
+	}
+	conversations, err := g.db.GetConversationListSplitDB(ctx, 0, hotGroupSize*2)
+	if err != nil {
+		return err
+	}
+	var groupIDs []string
+	for _, conversation := range conversations {
+		if conversation.ConversationType == constant.ReadGroupChatType && conversation.GroupID != "" {
+			groupIDs = append(groupIDs, conversation.GroupID)
+		}
+	}
+	if len(groupIDs) > hotGroupSize {
+		groupIDs = groupIDs[:hotGroupSize]
+	}
+	g.hotGroups.seed(groupIDs)
+	return nil
+}
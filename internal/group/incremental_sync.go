@@ -3,14 +3,16 @@ package group
 import (
 	"context"
 	"sync"
+	"sync/atomic"
 
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/syncer"
 	constantpb "github.com/openimsdk/protocol/constant"
 	"github.com/openimsdk/protocol/group"
 	"github.com/openimsdk/protocol/sdkws"
 	"github.com/openimsdk/tools/errs"
-	"github.com/openimsdk/tools/log"
 	"github.com/openimsdk/tools/utils/datautil"
 )
 
@@ -25,20 +27,55 @@ func (g *Group) groupTableName() string {
 func (g *Group) SyncAllJoinedGroupsAndMembersWithLock(ctx context.Context) error {
 	g.groupSyncMutex.Lock()
 	defer g.groupSyncMutex.Unlock()
+	if err := syncer.RunPhase(ctx, constant.SyncPhaseGroups, g.IncrSyncJoinGroup); err != nil {
+		return err
+	}
+	return syncer.RunPhase(ctx, constant.SyncPhaseGroupMembers, g.IncrSyncJoinGroupMember)
+}
+
+// ForceResyncGroups drops the versions of the joined groups and of their members, so that the server sends
+// them all again.
+func (g *Group) ForceResyncGroups(ctx context.Context) error {
+	g.groupSyncMutex.Lock()
+	defer g.groupSyncMutex.Unlock()
+	groups, err := g.db.GetJoinedGroupListDB(ctx)
+	if err != nil {
+		return err
+	}
+	for _, localGroup := range groups {
+		if err := g.db.DeleteVersionSync(ctx, g.groupAndMemberVersionTableName(), localGroup.GroupID); err != nil {
+			return err
+		}
+	}
+	if err := g.db.DeleteVersionSync(ctx, g.groupTableName(), g.loginUserID); err != nil {
+		return err
+	}
 	if err := g.IncrSyncJoinGroup(ctx); err != nil {
 		return err
 	}
 	return g.IncrSyncJoinGroupMember(ctx)
 }
 
+// IncrSyncJoinGroupMember syncs the members of the hot joined groups, the members of the other groups are
+// pulled when they are accessed.
 func (g *Group) IncrSyncJoinGroupMember(ctx context.Context) error {
 	groups, err := g.db.GetJoinedGroupListDB(ctx)
 	if err != nil {
 		return err
 	}
-	groupIDs := datautil.Slice(groups, func(e *model_struct.LocalGroup) string {
+	joined := datautil.SliceSet(datautil.Slice(groups, func(e *model_struct.LocalGroup) string {
 		return e.GroupID
-	})
+	}))
+	if err := g.seedHotGroups(ctx); err != nil {
+		return err
+	}
+	var groupIDs []string
+	for _, groupID := range g.hotGroups.groupIDs() {
+		if _, ok := joined[groupID]; ok {
+			groupIDs = append(groupIDs, groupID)
+		}
+	}
+	log.ZDebug(ctx, "sync members of hot groups", "hot", len(groupIDs), "joined", len(joined))
 	return g.IncrSyncGroupAndMember(ctx, groupIDs...)
 }
 
@@ -49,6 +86,10 @@ func (g *Group) IncrSyncGroupAndMember(ctx context.Context, groupIDs ...string)
 	}
 	const maxSyncNum = constantpb.MaxSyncPullNumber
 	groupIDSet := datautil.SliceSet(groupIDs)
+	// the progress counts the groups, not the members synced by each group
+	var synced atomic.Int32
+	total := len(groupIDSet)
+	memberCtx := syncer.WithPhase(ctx, "")
 	var groups []*group.GetIncrementalGroupMemberReq
 	if len(groupIDs) > maxSyncNum {
 		groups = make([]*group.GetIncrementalGroupMemberReq, 0, maxSyncNum)
@@ -87,10 +128,14 @@ func (g *Group) IncrSyncGroupAndMember(ctx context.Context, groupIDs ...string)
 			wg.Add(1)
 			go func() error {
 				defer wg.Done()
-				if err := g.syncGroupAndMember(ctx, tempGroupID, tempResp); err != nil {
+				defer func() {
+					syncer.ReportProgress(ctx, int(synced.Add(1)), total)
+				}()
+				if err := g.syncGroupAndMember(memberCtx, tempGroupID, tempResp); err != nil {
 					log.ZError(ctx, "sync Group And Member error", errs.Wrap(err))
 					return errs.Wrap(err)
 				}
+				g.hotGroups.synced(tempGroupID)
 				return nil
 			}()
 			delete(groupIDSet, tempGroupID)
@@ -137,24 +182,7 @@ func (g *Group) syncGroupAndMember(ctx context.Context, groupID string, resp *gr
 			if !ok {
 				return errs.New("group info type error")
 			}
-			if groupInfo == nil {
-				return nil
-			}
-			local, err := g.db.GetJoinedGroupListDB(ctx)
-			if err != nil {
-				return err
-			}
-			log.ZDebug(ctx, "group info", "groupInfo", groupInfo)
-			changes := datautil.Batch(ServerGroupToLocalGroup, []*sdkws.GroupInfo{groupInfo})
-			kv := datautil.SliceToMapAny(local, func(e *model_struct.LocalGroup) (string, *model_struct.LocalGroup) {
-				return e.GroupID, e
-			})
-			for i, change := range changes {
-				key := change.GroupID
-				kv[key] = changes[i]
-			}
-			server := datautil.Values(kv)
-			return g.groupSyncer.Sync(ctx, server, local, nil)
+			return g.syncGroupInfo(ctx, groupInfo)
 		},
 		Syncer: func(server, local []*model_struct.LocalGroupMember) error {
 			return g.groupMemberSyncer.Sync(ctx, server, local, nil)
@@ -181,8 +209,37 @@ func (g *Group) syncGroupAndMember(ctx context.Context, groupID string, resp *gr
 	return groupMemberSyncer.IncrementalSync()
 }
 
+func (g *Group) syncGroupInfo(ctx context.Context, groupInfo *sdkws.GroupInfo) error {
+	if groupInfo == nil {
+		return nil
+	}
+	local, err := g.db.GetJoinedGroupListDB(ctx)
+	if err != nil {
+		return err
+	}
+	log.ZDebug(ctx, "group info", "groupInfo", groupInfo)
+	changes := datautil.Batch(ServerGroupToLocalGroup, []*sdkws.GroupInfo{groupInfo})
+	kv := datautil.SliceToMapAny(local, func(e *model_struct.LocalGroup) (string, *model_struct.LocalGroup) {
+		return e.GroupID, e
+	})
+	for i, change := range changes {
+		key := change.GroupID
+		kv[key] = changes[i]
+	}
+	server := datautil.Values(kv)
+	return g.groupSyncer.Sync(ctx, server, local, nil)
+}
+
 func (g *Group) onlineSyncGroupAndMember(ctx context.Context, groupID string, deleteGroupMembers, updateGroupMembers, insertGroupMembers []*sdkws.GroupMemberFullInfo,
 	updateGroup *sdkws.GroupInfo, sortVersion uint64, version uint64, versionID string) error {
+	// the members of a group never synced are pulled in full the first time they are accessed, the changes
+	// of the members are of no use until then
+	if _, err := g.db.GetVersionSync(ctx, g.groupAndMemberVersionTableName(), groupID); err != nil {
+		if !errs.ErrRecordNotFound.Is(err) {
+			return err
+		}
+		return g.syncGroupInfo(ctx, updateGroup)
+	}
 	groupMemberSyncer := syncer.VersionSynchronizer[*model_struct.LocalGroupMember, *group.GetIncrementalGroupMemberResp]{
 		Ctx:       ctx,
 		DB:        g.db,
@@ -250,24 +307,7 @@ func (g *Group) onlineSyncGroupAndMember(ctx context.Context, groupID string, de
 			if !ok {
 				return errs.New("group info type error")
 			}
-			if groupInfo == nil {
-				return nil
-			}
-			local, err := g.db.GetJoinedGroupListDB(ctx)
-			if err != nil {
-				return err
-			}
-			log.ZDebug(ctx, "group info", "groupInfo", groupInfo)
-			changes := datautil.Batch(ServerGroupToLocalGroup, []*sdkws.GroupInfo{groupInfo})
-			kv := datautil.SliceToMapAny(local, func(e *model_struct.LocalGroup) (string, *model_struct.LocalGroup) {
-				return e.GroupID, e
-			})
-			for i, change := range changes {
-				key := change.GroupID
-				kv[key] = changes[i]
-			}
-			server := datautil.Values(kv)
-			return g.groupSyncer.Sync(ctx, server, local, nil)
+			return g.syncGroupInfo(ctx, groupInfo)
 		},
 		Syncer: func(server, local []*model_struct.LocalGroupMember) error {
 			return g.groupMemberSyncer.Sync(ctx, server, local, nil)
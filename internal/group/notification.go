@@ -19,12 +19,12 @@ import (
 	"fmt"
 
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/errreport"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
 	"github.com/openimsdk/tools/errs"
 	"github.com/openimsdk/tools/utils/datautil"
 
 	"github.com/openimsdk/protocol/sdkws"
-	"github.com/openimsdk/tools/log"
 )
 
 const (
@@ -34,7 +34,7 @@ const (
 
 func (g *Group) DoNotification(ctx context.Context, msg *sdkws.MsgData) {
 	if err := g.doNotification(ctx, msg); err != nil {
-		log.ZError(ctx, "DoGroupNotification failed", err)
+		errreport.Report(ctx, errreport.SourceNotification, "DoGroupNotification", err, "contentType", msg.ContentType)
 	}
 }
 
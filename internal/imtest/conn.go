@@ -0,0 +1,298 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !js
+// +build !js
+
+package imtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/internal/interaction"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/protocol/sdkws"
+	"github.com/openimsdk/tools/errs"
+	"google.golang.org/protobuf/proto"
+)
+
+// ErrConnClosed is returned by the reads and writes of a Conn closed or dropped.
+var ErrConnClosed = errors.New("imtest: conn closed")
+
+// connFrames is the most frames queued for the sdk to read.
+const connFrames = 1024
+
+// Handler answers a request sent on the long connection with the data of the response, an error is answered
+// with its code and message.
+type Handler func(req *interaction.GeneralWsReq) ([]byte, error)
+
+// Reply makes a Handler of a function of the protobuf messages of the request and the response.
+func Reply[R any, PR interface {
+	*R
+	proto.Message
+}](fn func(req PR) (proto.Message, error)) Handler {
+	return func(req *interaction.GeneralWsReq) ([]byte, error) {
+		var r PR = new(R)
+		if err := proto.Unmarshal(req.Data, r); err != nil {
+			return nil, errs.ErrArgs.WrapMsg("imtest: unmarshal request " + err.Error())
+		}
+		resp, err := fn(r)
+		if err != nil || resp == nil {
+			return nil, err
+		}
+		return proto.Marshal(resp)
+	}
+}
+
+// Conn is a LongConn serving scripted responses and pushes in memory, set with LongConnMgr.SetConn. The
+// requests are answered by the handlers of their reqIdentifier, the ones without a handler with an empty
+// successful response. It can be dialed again after Drop, as the sdk reconnects.
+type Conn struct {
+	encoder    interaction.Encoder
+	compressor interaction.Compressor
+
+	lock     sync.Mutex
+	handlers map[int]Handler
+	requests []*interaction.GeneralWsReq
+	dials    int
+	reject   *rejection
+	// compression is the one of the last dial
+	compression bool
+	frames      chan []byte
+	closed      chan struct{}
+	pong        interaction.PingPongHandler
+}
+
+type rejection struct {
+	ErrCode int    `json:"errCode"`
+	ErrMsg  string `json:"errMsg"`
+}
+
+// NewConn returns a Conn not dialed yet.
+func NewConn() *Conn {
+	return &Conn{
+		encoder:    interaction.NewGobEncoder(),
+		compressor: interaction.NewGzipCompressor(),
+		handlers:   make(map[int]Handler),
+	}
+}
+
+// Handle sets the handler of the requests of reqIdentifier, replacing the one set.
+func (c *Conn) Handle(reqIdentifier int, handler Handler) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.handlers[reqIdentifier] = handler
+}
+
+// RejectDial has the following dials rejected with the error code, as the server rejects a handshake, e.g. with
+// errs.TokenExpiredError. A code of 0 accepts them again.
+func (c *Conn) RejectDial(errCode int, errMsg string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if errCode == 0 {
+		c.reject = nil
+		return
+	}
+	c.reject = &rejection{ErrCode: errCode, ErrMsg: errMsg}
+}
+
+// Requests returns the requests sent so far, in order.
+func (c *Conn) Requests() []*interaction.GeneralWsReq {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return append([]*interaction.GeneralWsReq(nil), c.requests...)
+}
+
+// Dials returns the number of the dials accepted.
+func (c *Conn) Dials() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.dials
+}
+
+// Push sends a frame of the server, e.g. constant.KickOnlineMsg.
+func (c *Conn) Push(reqIdentifier int, m proto.Message) error {
+	var data []byte
+	if m != nil {
+		var err error
+		if data, err = proto.Marshal(m); err != nil {
+			return err
+		}
+	}
+	return c.send(&interaction.GeneralWsResp{ReqIdentifier: reqIdentifier, Data: data})
+}
+
+// PushMessages pushes the messages and notifications as the server does when they are sent.
+func (c *Conn) PushMessages(msgs *sdkws.PushMessages) error {
+	return c.Push(constant.PushMsg, msgs)
+}
+
+// Drop closes the connection as if the network was lost, the sdk dials again.
+func (c *Conn) Drop() {
+	_ = c.Close()
+}
+
+func (c *Conn) send(resp *interaction.GeneralWsResp) error {
+	data, err := c.encoder.Encode(resp)
+	if err != nil {
+		return err
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.closed == nil {
+		return ErrConnClosed
+	}
+	if c.compression {
+		if data, err = c.compressor.Compress(data); err != nil {
+			return err
+		}
+	}
+	select {
+	case <-c.closed:
+		return ErrConnClosed
+	case c.frames <- data:
+		return nil
+	default:
+		return errs.New("imtest: too many frames queued").Wrap()
+	}
+}
+
+func (c *Conn) Close() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.closed != nil {
+		close(c.closed)
+		c.closed = nil
+	}
+	return nil
+}
+
+func (c *Conn) WriteMessage(messageType int, message []byte) error {
+	c.lock.Lock()
+	closed, compression, pong := c.closed == nil, c.compression, c.pong
+	c.lock.Unlock()
+	if closed {
+		return ErrConnClosed
+	}
+	switch messageType {
+	case interaction.PingMessage:
+		if pong != nil {
+			go pong(string(message))
+		}
+		return nil
+	case interaction.MessageBinary:
+	default:
+		return nil
+	}
+	if compression {
+		var err error
+		if message, err = c.compressor.DeCompress(message); err != nil {
+			return err
+		}
+	}
+	var req interaction.GeneralWsReq
+	if err := c.encoder.Decode(message, &req); err != nil {
+		return err
+	}
+	c.lock.Lock()
+	c.requests = append(c.requests, &req)
+	handler := c.handlers[req.ReqIdentifier]
+	c.lock.Unlock()
+	resp := &interaction.GeneralWsResp{ReqIdentifier: req.ReqIdentifier, MsgIncr: req.MsgIncr, OperationID: req.OperationID}
+	if handler != nil {
+		data, err := handler(&req)
+		if err != nil {
+			codeErr, ok := errs.Unwrap(err).(errs.CodeError)
+			if !ok {
+				codeErr = errs.ErrInternalServer
+			}
+			resp.ErrCode, resp.ErrMsg = codeErr.Code(), err.Error()
+		}
+		resp.Data = data
+	}
+	// answered asynchronously as the server does, the write holds the lock of the connection
+	go func() { _ = c.send(resp) }()
+	return nil
+}
+
+func (c *Conn) ReadMessage() (int, []byte, error) {
+	c.lock.Lock()
+	frames, closed := c.frames, c.closed
+	c.lock.Unlock()
+	if closed == nil {
+		return 0, nil, ErrConnClosed
+	}
+	select {
+	case frame := <-frames:
+		return interaction.MessageBinary, frame, nil
+	case <-closed:
+		return 0, nil, ErrConnClosed
+	}
+}
+
+func (c *Conn) SetReadDeadline(timeout time.Duration) error {
+	return nil
+}
+
+func (c *Conn) SetWriteDeadline(timeout time.Duration) error {
+	return nil
+}
+
+// Dial accepts the connection unless RejectDial was set, the frames pushed before are dropped.
+func (c *Conn) Dial(urlStr string, requestHeader http.Header) (*http.Response, error) {
+	u, err := url.Parse(urlStr)
+	if err != nil {
+		return nil, err
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.reject != nil {
+		body, _ := json.Marshal(c.reject)
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(body))},
+			errs.New("imtest: dial rejected").Wrap()
+	}
+	if c.closed != nil {
+		close(c.closed)
+	}
+	c.dials++
+	c.compression = u.Query().Get("compression") == constant.CompressionGzip
+	c.frames = make(chan []byte, connFrames)
+	c.closed = make(chan struct{})
+	return &http.Response{StatusCode: http.StatusSwitchingProtocols, Body: http.NoBody}, nil
+}
+
+func (c *Conn) IsNil() bool {
+	return c == nil
+}
+
+func (c *Conn) SetReadLimit(limit int64) {}
+
+func (c *Conn) SetPingHandler(handler interaction.PingPongHandler) {}
+
+func (c *Conn) SetPongHandler(handler interaction.PingPongHandler) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.pong = handler
+}
+
+func (c *Conn) LocalAddr() string {
+	return "imtest"
+}
@@ -0,0 +1,110 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !js
+// +build !js
+
+// Package imtest runs the internal modules against a scripted server in memory, so that conversation_msg,
+// group, relation and the others can be tested end to end without an OpenIM server:
+//
+//	h := imtest.New(t, "user1")
+//	h.Server.Handle(api.GetUsersInfo.Route(), imtest.Respond(func(req *user.GetDesignateUsersReq) (*user.GetDesignateUsersResp, error) {...}))
+//	h.Conn.Handle(constant.GetNewestSeq, imtest.Reply(func(req *sdkws.GetMaxSeqReq) (proto.Message, error) {...}))
+//	g := group.NewGroup(h.ConversationEventQueue)
+//	g.SetDataBase(h.DB)
+//	...
+//	h.Conn.PushMessages(&sdkws.PushMessages{...})
+package imtest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/internal/interaction"
+	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/ccontext"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/common"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+	pconstant "github.com/openimsdk/protocol/constant"
+)
+
+// connectTimeout bounds the wait of New for the long connection.
+const connectTimeout = 5 * time.Second
+
+// Harness is a logged in user of a scripted server: the context tells the address of Server, the long
+// connection dials Conn and the database is in memory. Everything is closed with the test.
+type Harness struct {
+	Ctx    context.Context
+	Conn   *Conn
+	Server *Server
+	DB     *db.DataBase
+
+	LongConnMgr            *interaction.LongConnMgr
+	PushMsgAndMaxSeqCh     chan common.Cmd2Value
+	LoginMgrCh             chan common.Cmd2Value
+	ConversationEventQueue chan common.Cmd2Value
+}
+
+// New logs in userID to a new scripted server, it returns once the long connection is connected.
+func New(t testing.TB, userID string) *Harness {
+	t.Helper()
+	h := &Harness{
+		Conn:                   NewConn(),
+		Server:                 NewServer(),
+		PushMsgAndMaxSeqCh:     make(chan common.Cmd2Value, 1000),
+		LoginMgrCh:             make(chan common.Cmd2Value, 1),
+		ConversationEventQueue: make(chan common.Cmd2Value, 1000),
+	}
+	t.Cleanup(h.Server.Close)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		// the read of the long connection waits for a frame, it ends with the conn closed
+		cancel()
+		_ = h.Conn.Close()
+	})
+	h.Ctx = ccontext.WithOperationID(ccontext.WithInfo(ctx, ccontext.NewGlobalConfig(userID, "imtest", &sdk_struct.IMConfig{
+		PlatformID: pconstant.LinuxPlatformID,
+		ApiAddr:    h.Server.URL,
+		WsAddr:     "ws://imtest",
+	})), utils.OperationIDGenerator())
+	database, err := db.NewDataBase(h.Ctx, userID, db.MemoryDBDir, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = database.Close(context.Background()) })
+	h.DB = database
+	h.LongConnMgr = interaction.NewLongConnMgr(h.Ctx, func(map[string][]int32) {}, h.PushMsgAndMaxSeqCh, h.LoginMgrCh)
+	h.LongConnMgr.SetListener(func() open_im_sdk_callback.OnConnListener { return ConnListener{} })
+	h.LongConnMgr.SetConn(h.Conn)
+	h.LongConnMgr.Run(h.Ctx, h.Ctx)
+	for deadline := time.Now().Add(connectTimeout); !h.LongConnMgr.IsConnected(); time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("imtest: long connection not connected")
+		}
+	}
+	return h
+}
+
+// ConnListener is an OnConnListener doing nothing.
+type ConnListener struct{}
+
+func (ConnListener) OnConnecting()                                {}
+func (ConnListener) OnConnectSuccess()                            {}
+func (ConnListener) OnConnectFailed(errCode int32, errMsg string) {}
+func (ConnListener) OnKickedOffline()                             {}
+func (ConnListener) OnUserTokenExpired()                          {}
+func (ConnListener) OnUserTokenInvalid(errMsg string)             {}
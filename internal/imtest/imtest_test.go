@@ -0,0 +1,85 @@
+//go:build !js
+// +build !js
+
+package imtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/api"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/protocol/sdkws"
+	"github.com/openimsdk/protocol/user"
+	"github.com/openimsdk/tools/errs"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestHarness(t *testing.T) {
+	h := New(t, "user1")
+
+	h.Conn.Handle(constant.GetNewestSeq, Reply(func(req *sdkws.GetMaxSeqReq) (proto.Message, error) {
+		return &sdkws.GetMaxSeqResp{MaxSeqs: map[string]int64{"si_user1_user2": 7}}, nil
+	}))
+	var seqResp sdkws.GetMaxSeqResp
+	if err := h.LongConnMgr.SendReqWaitResp(h.Ctx, &sdkws.GetMaxSeqReq{UserID: "user1"}, constant.GetNewestSeq, &seqResp); err != nil {
+		t.Fatal(err)
+	}
+	if seqResp.MaxSeqs["si_user1_user2"] != 7 {
+		t.Fatalf("max seqs %v", seqResp.MaxSeqs)
+	}
+	h.Conn.Handle(constant.GetNewestSeq, Reply(func(req *sdkws.GetMaxSeqReq) (proto.Message, error) {
+		return nil, errs.ErrNoPermission.WrapMsg("scripted")
+	}))
+	if err := h.LongConnMgr.SendReqWaitResp(h.Ctx, &sdkws.GetMaxSeqReq{UserID: "user1"}, constant.GetNewestSeq, &seqResp); err == nil {
+		t.Fatal("the error scripted was not returned")
+	}
+
+	if err := h.Conn.PushMessages(&sdkws.PushMessages{Msgs: map[string]*sdkws.PullMsgs{
+		"si_user1_user2": {Msgs: []*sdkws.MsgData{{ClientMsgID: "m1", Seq: 8}}},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	timeout := time.After(5 * time.Second)
+	for pushed := false; !pushed; {
+		select {
+		case cmd := <-h.PushMsgAndMaxSeqCh:
+			if cmd.Cmd == constant.CmdPushMsg {
+				msgs := cmd.Value.(*sdkws.PushMessages)
+				if msgs.Msgs["si_user1_user2"].Msgs[0].ClientMsgID != "m1" {
+					t.Fatalf("pushed %v", msgs)
+				}
+				pushed = true
+			}
+		case <-timeout:
+			t.Fatal("the message pushed was not received")
+		}
+	}
+
+	h.Server.Handle(api.GetUsersInfo.Route(), Respond(func(req *user.GetDesignateUsersReq) (*user.GetDesignateUsersResp, error) {
+		return &user.GetDesignateUsersResp{UsersInfo: []*sdkws.UserInfo{{UserID: req.UserIDs[0], Nickname: "user 2"}}}, nil
+	}))
+	users, err := api.GetUsersInfo.Invoke(h.Ctx, &user.GetDesignateUsersReq{UserIDs: []string{"user2"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users.UsersInfo) != 1 || users.UsersInfo[0].Nickname != "user 2" {
+		t.Fatalf("users %v", users.UsersInfo)
+	}
+	if calls := h.Server.Calls(api.GetUsersInfo.Route()); len(calls) != 1 {
+		t.Fatalf("%d calls", len(calls))
+	}
+	if _, err := api.UserClientConfig.Invoke(h.Ctx, &user.GetUserClientConfigReq{UserID: "user1"}); err == nil {
+		t.Fatal("a route not scripted was answered")
+	}
+}
+
+func TestConnDrop(t *testing.T) {
+	h := New(t, "user1")
+	h.Conn.Drop()
+	for deadline := time.Now().Add(10 * time.Second); h.Conn.Dials() < 2; time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("the long connection did not dial again")
+		}
+	}
+}
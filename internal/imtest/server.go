@@ -0,0 +1,105 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !js
+// +build !js
+
+package imtest
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/network"
+	"github.com/openimsdk/tools/errs"
+)
+
+// Route answers the json body of an api request with the data of the response, an error is answered with its
+// code and message.
+type Route func(req json.RawMessage) (any, error)
+
+// Respond makes a Route of a function of the request and the response, e.g. of the ones of pkg/api.
+func Respond[Req any, Resp any](fn func(req *Req) (*Resp, error)) Route {
+	return func(data json.RawMessage) (any, error) {
+		req := new(Req)
+		if err := json.Unmarshal(data, req); err != nil {
+			return nil, errs.ErrArgs.WrapMsg("imtest: unmarshal request " + err.Error())
+		}
+		resp, err := fn(req)
+		if err != nil {
+			return nil, err
+		}
+		return resp, nil
+	}
+}
+
+// Server is an http api stub serving scripted responses by route, as ApiAddr. The routes without a Route are
+// answered with errs.ServerInternalError.
+type Server struct {
+	*httptest.Server
+
+	lock   sync.Mutex
+	routes map[string]Route
+	calls  map[string][]json.RawMessage
+}
+
+// NewServer starts a Server, closed with Close.
+func NewServer() *Server {
+	s := &Server{routes: make(map[string]Route), calls: make(map[string][]json.RawMessage)}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// Handle sets the Route of the api, e.g. api.GetUsersInfo.Route(), replacing the one set.
+func (s *Server) Handle(route string, fn Route) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.routes[route] = fn
+}
+
+// Calls returns the bodies of the requests of the route received so far, in order.
+func (s *Server) Calls(route string) []json.RawMessage {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]json.RawMessage(nil), s.calls[route]...)
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.lock.Lock()
+	s.calls[r.URL.Path] = append(s.calls[r.URL.Path], body)
+	route := s.routes[r.URL.Path]
+	s.lock.Unlock()
+	var resp network.ApiResponse
+	if route == nil {
+		resp.ErrCode, resp.ErrMsg = errs.ServerInternalError, "imtest: no route "+r.URL.Path
+	} else if data, err := route(body); err != nil {
+		codeErr, ok := errs.Unwrap(err).(errs.CodeError)
+		if !ok {
+			codeErr = errs.ErrInternalServer
+		}
+		resp.ErrCode, resp.ErrMsg = codeErr.Code(), err.Error()
+	} else if resp.Data, err = json.Marshal(data); err != nil {
+		resp.ErrCode, resp.ErrMsg = errs.ServerInternalError, "imtest: marshal response "+err.Error()
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(&resp)
+}
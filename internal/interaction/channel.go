@@ -0,0 +1,80 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interaction
+
+import (
+	"context"
+	"sync"
+)
+
+// Logical channels of the long connection. The requests of a channel are written without waiting for the
+// responses of the previous ones, so that independent streams share the connection instead of queuing
+// behind each other. The requests without a channel keep waiting for their response before the next request
+// is written. Only the requests sent with SendReqWaitResp use the channels, the syncs over the http api do not.
+const (
+	// ChannelMessage pulls the messages
+	ChannelMessage = "message"
+)
+
+// defaultChannelWindow is the number of requests of a channel waiting for their response at most, the
+// following requests of the channel wait for a free slot so that a busy channel can not fill the send queue.
+const defaultChannelWindow = 4
+
+var channelWindowSizes = map[string]int{
+	ChannelMessage: pullMsgGoroutineLimit,
+}
+
+type channelKey struct{}
+
+// WithChannel makes the long connection requests made with the context use the channel.
+func WithChannel(ctx context.Context, channel string) context.Context {
+	return context.WithValue(ctx, channelKey{}, channel)
+}
+
+func channelFromContext(ctx context.Context) string {
+	channel, _ := ctx.Value(channelKey{}).(string)
+	return channel
+}
+
+// channelWindows are the flow control windows of the channels.
+type channelWindows struct {
+	lock    sync.Mutex
+	windows map[string]chan struct{}
+}
+
+func newChannelWindows() *channelWindows {
+	return &channelWindows{windows: make(map[string]chan struct{})}
+}
+
+// acquire waits for a free slot in the window of the channel, release gives it back once the response came.
+func (w *channelWindows) acquire(ctx context.Context, channel string) (release func(), err error) {
+	w.lock.Lock()
+	window, ok := w.windows[channel]
+	if !ok {
+		size, ok := channelWindowSizes[channel]
+		if !ok {
+			size = defaultChannelWindow
+		}
+		window = make(chan struct{}, size)
+		w.windows[channel] = window
+	}
+	w.lock.Unlock()
+	select {
+	case window <- struct{}{}:
+		return func() { <-window }, nil
+	case <-ctx.Done():
+		return nil, context.Cause(ctx)
+	}
+}
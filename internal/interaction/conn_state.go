@@ -0,0 +1,132 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interaction
+
+import (
+	"context"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/network"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+// maxStateHistory is the number of the last changes of the connection state kept for the diagnostics
+const maxStateHistory = 100
+
+// dialStateReporter is implemented by the connections that report the phases of their handshake.
+type dialStateReporter interface {
+	SetDialStateHandler(handler func(state string))
+}
+
+// contextDialer is implemented by the connections that dial with the network settings of the user context,
+// its proxy, its certificate pins and its bandwidth limits.
+type contextDialer interface {
+	SetUserContext(ctx context.Context)
+}
+
+func (c *LongConnMgr) SetStateListener(listener func() open_im_sdk_callback.OnConnStateListener) {
+	c.stateListener = listener
+}
+
+func (c *LongConnMgr) SetNetworkQualityListener(listener func() open_im_sdk_callback.OnNetworkQualityListener) {
+	c.qualityListener = listener
+}
+
+// SetDegradedThresholds sets the round trip time and the loss rate over which the connection is weak,
+// it is reported degraded and the network quality listener is called.
+func (c *LongConnMgr) SetDegradedThresholds(rtt time.Duration, lossRate float64) {
+	c.quality.setThresholds(rtt, lossRate)
+}
+
+// GetNetworkQuality returns the current estimates of the round trip time, jitter, loss rate and throughput.
+func (c *LongConnMgr) GetNetworkQuality(_ context.Context) (*sdk_struct.NetworkQuality, error) {
+	return c.quality.snapshot(), nil
+}
+
+func (c *LongConnMgr) notifyState(state *sdk_struct.ConnState) {
+	c.stateLock.Lock()
+	c.state = state.State
+	if len(c.stateHistory) >= maxStateHistory {
+		c.stateHistory = append(c.stateHistory[:0], c.stateHistory[1:]...)
+	}
+	c.stateHistory = append(c.stateHistory, &sdk_struct.ConnStateChange{ConnState: *state, Time: time.Now().UnixMilli()})
+	c.stateLock.Unlock()
+	if c.stateListener == nil {
+		return
+	}
+	c.stateListener().OnConnStateChanged(utils.StructToJsonString(state))
+}
+
+func (c *LongConnMgr) getState() string {
+	c.stateLock.Lock()
+	defer c.stateLock.Unlock()
+	return c.state
+}
+
+// StateHistory returns the last changes of the connection state, the oldest first.
+func (c *LongConnMgr) StateHistory() []*sdk_struct.ConnStateChange {
+	c.stateLock.Lock()
+	defer c.stateLock.Unlock()
+	history := make([]*sdk_struct.ConnStateChange, len(c.stateHistory))
+	copy(history, c.stateHistory)
+	return history
+}
+
+// PendingRequests is the number of the requests waiting to be written to the connection.
+func (c *LongConnMgr) PendingRequests() int {
+	return len(c.send)
+}
+
+// connectedState is degraded when the connection is weak.
+func (c *LongConnMgr) connectedState() string {
+	if c.quality.isWeak() {
+		return constant.ConnStateDegraded
+	}
+	return constant.ConnStateConnected
+}
+
+func (c *LongConnMgr) notifyConnected() {
+	c.notifyState(&sdk_struct.ConnState{State: c.connectedState(), RTT: c.quality.snapshot().RTT})
+}
+
+func (c *LongConnMgr) onPingSent() {
+	c.quality.pingSent(time.Now().UnixMilli())
+}
+
+func (c *LongConnMgr) onPong() {
+	if c.quality.pongReceived(time.Now().UnixMilli(), network.GetTrafficStats().WsWireBytes) {
+		c.onQualityChanged()
+	}
+}
+
+func (c *LongConnMgr) onAck(lost bool) {
+	if c.quality.ackReceived(lost) {
+		c.onQualityChanged()
+	}
+}
+
+// onQualityChanged is called when the connection crosses the weak threshold, in either direction.
+func (c *LongConnMgr) onQualityChanged() {
+	switch c.getState() {
+	case constant.ConnStateConnected, constant.ConnStateDegraded:
+		c.notifyConnected()
+	}
+	if c.qualityListener != nil {
+		c.qualityListener().OnNetworkQualityChanged(utils.StructToJsonString(c.quality.snapshot()))
+	}
+}
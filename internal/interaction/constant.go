@@ -17,6 +17,18 @@ package interaction
 const (
 	WebSocket = iota
 	Tcp
+	QUIC
+	LongPollingConn
+	// CustomConn is a connection set with SetConn, kept whatever the transport configured
+	CustomConn
+)
+
+// Transport names used in the config.
+const (
+	TransportWebSocket = "websocket"
+	TransportQUIC      = "quic"
+	// TransportLongPolling always uses http long polling, websocket falls back to it when enabled in the config
+	TransportLongPolling = "longpolling"
 )
 
 const (
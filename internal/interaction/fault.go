@@ -0,0 +1,55 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interaction
+
+import (
+	"context"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/crash"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/fault"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+)
+
+// faultCheckInterval is how often the reconnect interval of the fault injection is read again, it can be
+// changed by UpdateConfig.
+const faultCheckInterval = time.Second
+
+// faultReconnectLoop closes the long connection every reconnect interval of the fault injection, readPump
+// reconnects as after a network loss.
+func (c *LongConnMgr) faultReconnectLoop(ctx context.Context) {
+	defer crash.Recover(ctx, "faultReconnectLoop")
+	ticker := time.NewTicker(faultCheckInterval)
+	defer ticker.Stop()
+	last := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		interval := fault.ReconnectInterval()
+		if interval <= 0 || !c.IsConnected() {
+			last = time.Now()
+			continue
+		}
+		if time.Since(last) < interval {
+			continue
+		}
+		last = time.Now()
+		log.ZWarn(ctx, "fault injected, close the long connection", nil, "interval", interval)
+		_ = c.conn.Close()
+	}
+}
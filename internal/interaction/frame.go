@@ -0,0 +1,52 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interaction
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// frameHeaderSize is the size of the frame header of the transports without message framing of their own,
+// the message type in one byte and the payload length in four bytes big endian.
+const frameHeaderSize = 5
+
+var errFrameTooLarge = errors.New("frame exceeds the read limit")
+
+func encodeFrame(typ byte, data []byte) []byte {
+	buf := make([]byte, frameHeaderSize+len(data))
+	buf[0] = typ
+	binary.BigEndian.PutUint32(buf[1:frameHeaderSize], uint32(len(data)))
+	copy(buf[frameHeaderSize:], data)
+	return buf
+}
+
+// decodeFrame reads a frame, a limit of 0 means no limit on the payload size.
+func decodeFrame(r io.Reader, limit int64) (byte, []byte, error) {
+	var header [frameHeaderSize]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, nil, err
+	}
+	size := binary.BigEndian.Uint32(header[1:])
+	if limit > 0 && int64(size) > limit {
+		return 0, nil, errFrameTooLarge
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return 0, nil, err
+	}
+	return header[0], data, nil
+}
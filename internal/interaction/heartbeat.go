@@ -0,0 +1,148 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interaction
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// number of pongs in a row after which an adaptive heartbeat lengthens its interval
+	stablePongCount = 3
+	// the longest interval of an adaptive heartbeat by default, as a multiple of the interval
+	DefaultMaxIntervalFactor = 4
+	// the interval of the pings when the config sets none
+	DefaultHeartbeatInterval = pingPeriod
+)
+
+// heartbeatPolicy decides how often the client pings the server. With adaptive on, the interval grows while
+// pongs keep coming and jumps to the longest one in background, any failure brings it back to the base interval.
+type heartbeatPolicy struct {
+	lock       sync.Mutex
+	base       time.Duration
+	max        time.Duration
+	adaptive   bool
+	background bool
+	// reduced is the reduced activity mode, the interval is the longest one also without adaptive
+	reduced bool
+	current time.Duration
+	pongs   int
+	// changed wakes up the heartbeat loop when the interval is reset
+	changed chan struct{}
+}
+
+func newHeartbeatPolicy() *heartbeatPolicy {
+	return &heartbeatPolicy{
+		base:    pingPeriod,
+		max:     pingPeriod,
+		current: pingPeriod,
+		changed: make(chan struct{}, 1),
+	}
+}
+
+func (h *heartbeatPolicy) set(interval, maxInterval time.Duration, adaptive bool) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if interval <= 0 {
+		interval = pingPeriod
+	}
+	if maxInterval < interval {
+		maxInterval = interval
+		if adaptive {
+			maxInterval = interval * DefaultMaxIntervalFactor
+		}
+	}
+	h.base, h.max, h.adaptive = interval, maxInterval, adaptive
+	h.reset()
+}
+
+func (h *heartbeatPolicy) interval() time.Duration {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if h.reduced {
+		if h.max > h.base {
+			return h.max
+		}
+		return h.base * DefaultMaxIntervalFactor
+	}
+	if h.adaptive && h.background {
+		return h.max
+	}
+	return h.current
+}
+
+// readTimeout is how long the connection may stay silent, a quarter longer than the ping interval.
+func (h *heartbeatPolicy) readTimeout() time.Duration {
+	interval := h.interval()
+	return interval + interval/4
+}
+
+func (h *heartbeatPolicy) onPong() {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if !h.adaptive {
+		return
+	}
+	h.pongs++
+	if h.pongs < stablePongCount {
+		return
+	}
+	h.pongs = 0
+	h.current += h.base / 2
+	if h.current > h.max {
+		h.current = h.max
+	}
+}
+
+// onFailure is called when a ping can not be sent or the connection is lost.
+func (h *heartbeatPolicy) onFailure() {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.reset()
+}
+
+func (h *heartbeatPolicy) setBackground(background bool) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if h.background == background {
+		return
+	}
+	h.background = background
+	if !background {
+		h.reset()
+	}
+}
+
+func (h *heartbeatPolicy) setReduced(reduced bool) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if h.reduced == reduced {
+		return
+	}
+	h.reduced = reduced
+	if !reduced {
+		h.reset()
+	}
+}
+
+func (h *heartbeatPolicy) reset() {
+	h.current = h.base
+	h.pongs = 0
+	select {
+	case h.changed <- struct{}{}:
+	default:
+	}
+}
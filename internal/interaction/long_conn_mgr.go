@@ -22,10 +22,10 @@ import (
 	"fmt"
 	"io"
 	"runtime"
-	"runtime/debug"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/golang/protobuf/proto"
@@ -39,12 +39,18 @@ import (
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/ccontext"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/common"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/crash"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/fault"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/network"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/recorder"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/telemetry"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
 
 	"github.com/openimsdk/protocol/sdkws"
 	"github.com/openimsdk/tools/errs"
-	"github.com/openimsdk/tools/log"
 )
 
 const (
@@ -82,12 +88,24 @@ var (
 	ErrPanic                     = errors.New("panic error")
 )
 
+func init() {
+	sdkerrs.RegisterClassifier(func(err error) errs.CodeError {
+		for _, connErr := range []error{ErrChanClosed, ErrConnClosed, ErrClientClosed, ErrLongPollingClosed} {
+			if errors.Is(err, connErr) {
+				return sdkerrs.ErrNetwork
+			}
+		}
+		return nil
+	})
+}
+
 type LongConnMgr struct {
 	//conn status mutex
 	w          sync.Mutex
 	connStatus int
 	// The long connection,can be set tcp or websocket.
 	conn       LongConn
+	connType   int
 	listener   func() open_im_sdk_callback.OnConnListener
 	userOnline func(map[string][]int32)
 	// Buffered channel of outbound messages.
@@ -97,26 +115,53 @@ type LongConnMgr struct {
 	loginMgrCh         chan common.Cmd2Value
 	closedErr          error
 	ctx                context.Context
-	IsCompression      bool
-	Syncer             *WsRespAsyn
-	encoder            Encoder
-	compressor         Compressor
-	reconnectStrategy  ReconnectStrategy
+	// gzip of the frames of the current connection, set on reconnect while the pumps read it
+	compression       atomic.Bool
+	Syncer            *WsRespAsyn
+	encoder           Encoder
+	compressor        Compressor
+	reconnectStrategy ReconnectStrategy
+	// forceReconnect wakes up readPump waiting to reconnect
+	forceReconnect chan struct{}
+	channels       *channelWindows
+	// serverTimeSync wakes up serverTimeLoop to sample the server clock
+	serverTimeSync chan struct{}
 
 	mutex        sync.Mutex
 	IsBackground bool
+	// reducedActivity is the mode of the app in background, see SetReducedActivity
+	reducedActivity bool
 	// write conn lock
 	connWrite *sync.Mutex
 
 	sub *subscription
 
+	heartbeatPolicy *heartbeatPolicy
+
+	stateListener   func() open_im_sdk_callback.OnConnStateListener
+	stateLock       sync.Mutex
+	state           string
+	stateHistory    []*sdk_struct.ConnStateChange
+	quality         *networkQuality
+	fallback        *transportFallback
+	qualityListener func() open_im_sdk_callback.OnNetworkQualityListener
+
 	mb *MessageBatcher
+
+	connectedLock sync.Mutex
+	// connectedHooks run after each successful connection, see OnConnected
+	connectedHooks []func(ctx context.Context)
+
+	// replay answers the requests when a recording is replayed, see SetReplay
+	replay *replaySource
 }
 
 type Message struct {
 	Message GeneralWsReq
 	Resp    chan *GeneralWsResp
 	Order   *ccontext.SendOrderInfo
+	// Channel is the logical channel of the request, see WithChannel
+	Channel string
 }
 
 type laneState struct {
@@ -140,18 +185,25 @@ func NewLongConnMgr(ctx context.Context, userOnline func(map[string][]int32), pu
 		userOnline:         userOnline,
 		pushMsgAndMaxSeqCh: pushMsgAndMaxSeqCh,
 		loginMgrCh:         loginMgrCh,
-		IsCompression:      true,
 		Syncer:             NewWsRespAsyn(),
 		encoder:            NewGobEncoder(),
 		compressor:         NewGzipCompressor(),
-		reconnectStrategy:  NewExponentialRetry(),
+		reconnectStrategy:  NewBackoffRetry(),
+		forceReconnect:     make(chan struct{}, 1),
+		serverTimeSync:     make(chan struct{}, 1),
+		channels:           newChannelWindows(),
 		sub:                newSubscription(),
+		heartbeatPolicy:    newHeartbeatPolicy(),
+		quality:            newNetworkQuality(),
+		fallback:           &transportFallback{},
 	}
 	l.send = make(chan Message, 10)
 	l.conn = NewWebSocket(WebSocket)
+	l.connType = WebSocket
 	l.connWrite = new(sync.Mutex)
 	l.ctx = ctx
 	l.mb = NewMessageBatcher(l.doBatch)
+	l.compression.Store(true)
 	return l
 }
 
@@ -165,9 +217,16 @@ func (c *LongConnMgr) SetListener(listener func() open_im_sdk_callback.OnConnLis
 }
 
 func (c *LongConnMgr) Run(ctx, fgCtx context.Context) {
+	if c.replay != nil {
+		// the frames come from Replay
+		c.SetConnectionStatus(Connected)
+		return
+	}
 	go c.readPump(ctx, fgCtx)
 	go c.writePump(ctx)
 	go c.heartbeat(ctx, fgCtx)
+	go c.serverTimeLoop(ctx)
+	go c.faultReconnectLoop(ctx)
 }
 
 func (c *LongConnMgr) ResumeForegroundTasks(ctx, fgCtx context.Context) {
@@ -180,7 +239,18 @@ func (c *LongConnMgr) SendReqWaitResp(ctx context.Context, m proto.Message, reqI
 	if err != nil {
 		return sdkerrs.ErrArgs
 	}
+	if c.replay != nil {
+		return c.replay.respond(ctx, reqIdentifier, data, resp)
+	}
 	orderInfo, _ := ccontext.GetSendOrderInfo(ctx)
+	channel := channelFromContext(ctx)
+	if channel != "" {
+		release, err := c.channels.acquire(ctx, channel)
+		if err != nil {
+			return sdkerrs.ErrCtxDeadline
+		}
+		defer release()
+	}
 	msg := Message{
 		Message: GeneralWsReq{
 			ReqIdentifier: reqIdentifier,
@@ -188,9 +258,11 @@ func (c *LongConnMgr) SendReqWaitResp(ctx context.Context, m proto.Message, reqI
 			OperationID:   ccontext.Info(ctx).OperationID(),
 			Data:          data,
 		},
-		Resp:  make(chan *GeneralWsResp, 1),
-		Order: orderInfo,
+		Resp:    make(chan *GeneralWsResp, 1),
+		Order:   orderInfo,
+		Channel: channel,
 	}
+	start := time.Now()
 	c.send <- msg
 	log.ZDebug(ctx, "send message to send channel success", "msg", m, "reqIdentifier", reqIdentifier)
 	select {
@@ -198,8 +270,11 @@ func (c *LongConnMgr) SendReqWaitResp(ctx context.Context, m proto.Message, reqI
 		return sdkerrs.ErrCtxDeadline
 	case v, ok := <-msg.Resp:
 		if !ok {
-			return errors.New("response channel closed")
+			return sdkerrs.ErrNetwork.WrapMsg("response channel closed")
 		}
+		telemetry.Record(telemetry.WsAckLatency, telemetry.Since(start),
+			telemetry.Int("req_identifier", reqIdentifier), telemetry.Bool("error", v.ErrCode != 0))
+		recorder.RecordExchange(reqIdentifier, data, v.OperationID, v.ErrCode, v.ErrMsg, v.Data)
 		if v.ErrCode != 0 {
 			return errs.NewCodeError(v.ErrCode, v.ErrMsg)
 		}
@@ -217,13 +292,7 @@ func (c *LongConnMgr) SendReqWaitResp(ctx context.Context, m proto.Message, reqI
 // reads from this goroutine.
 
 func (c *LongConnMgr) readPump(ctx context.Context, fgCtx context.Context) {
-	defer func() {
-		if r := recover(); r != nil {
-			err := fmt.Sprintf("panic: %+v\n%s", r, debug.Stack())
-
-			log.ZWarn(ctx, "readPump panic", nil, "panic info", err)
-		}
-	}()
+	defer crash.Recover(ctx, "readPump")
 
 	log.ZDebug(ctx, "readPump start", "goroutine ID:", getGoroutineID())
 	defer func() {
@@ -251,21 +320,53 @@ func (c *LongConnMgr) readPump(ctx context.Context, fgCtx context.Context) {
 		}
 		if err != nil {
 			log.ZWarn(c.ctx, "reConn", err)
-			time.Sleep(c.reconnectStrategy.GetSleepInterval())
+			if c.reconnectStrategy.Exhausted() {
+				log.ZError(c.ctx, "reconnect attempts exhausted, wait for ForceReconnect", err)
+				c.notifyState(&sdk_struct.ConnState{State: constant.ConnStateFailed, ErrCode: sdkerrs.NetworkError, ErrMsg: err.Error()})
+				if !c.waitReconnect(ctx, fgCtx, nil) {
+					return
+				}
+				continue
+			}
+			interval := c.reconnectStrategy.GetSleepInterval()
+			c.notifyState(&sdk_struct.ConnState{State: constant.ConnStateReconnectScheduled, NextAttemptTime: time.Now().Add(interval).UnixMilli()})
+			timer := time.NewTimer(interval)
+			if !c.waitReconnect(ctx, fgCtx, timer.C) {
+				timer.Stop()
+				return
+			}
+			timer.Stop()
 			continue
 		}
 		c.conn.SetReadLimit(maxMessageSize)
-		_ = c.conn.SetReadDeadline(pongWait)
+		_ = c.conn.SetReadDeadline(c.heartbeatPolicy.readTimeout())
 		messageType, message, err := c.conn.ReadMessage()
 		if err != nil {
 			log.ZError(c.ctx, "readMessage err", err, "goroutine ID:", getGoroutineID())
 			_ = c.close()
-			cliconf.ClearConfig()
+			c.heartbeatPolicy.onFailure()
+			c.quality.reset()
+			c.notifyState(&sdk_struct.ConnState{State: constant.ConnStateDisconnected, ErrMsg: err.Error()})
+			cliconf.ClearConfig(ctx)
 			c.sub.onConnClosed(err)
 			continue
 		}
 		switch messageType {
 		case MessageBinary:
+			f := fault.NextFrame()
+			if f.Drop {
+				log.ZWarn(c.ctx, "fault injected, frame received dropped", nil)
+				continue
+			}
+			if f.Delay > 0 {
+				time.Sleep(f.Delay)
+			}
+			if f.Duplicate {
+				if err := c.handleMessage(message); err != nil {
+					c.closedErr = err
+					return
+				}
+			}
 			err := c.handleMessage(message)
 			if err != nil {
 				c.closedErr = err
@@ -282,19 +383,30 @@ func (c *LongConnMgr) readPump(ctx context.Context, fgCtx context.Context) {
 	}
 }
 
+// waitReconnect waits for the timer or ForceReconnect, it returns false when readPump has to stop.
+// A nil timer only waits for ForceReconnect.
+func (c *LongConnMgr) waitReconnect(ctx context.Context, fgCtx context.Context, timer <-chan time.Time) bool {
+	select {
+	case <-timer:
+	case <-c.forceReconnect:
+		log.ZInfo(c.ctx, "reconnect forced")
+	case <-ctx.Done():
+		c.closedErr = ctx.Err()
+		return false
+	case <-fgCtx.Done():
+		c.closedErr = context.Cause(fgCtx)
+		return false
+	}
+	return true
+}
+
 // writePump pumps messages from the hub to the websocket connection.
 //
 // A goroutine running writePump is started for each connection. The
 // application ensures that there is at most one writer to a connection by
 // executing all writes from this goroutine.
 func (c *LongConnMgr) writePump(ctx context.Context) {
-	defer func() {
-		if r := recover(); r != nil {
-			err := fmt.Sprintf("panic: %+v\n%s", r, debug.Stack())
-
-			log.ZWarn(ctx, "writePump panic", nil, "panic info", err)
-		}
-	}()
+	defer crash.Recover(ctx, "writePump")
 
 	log.ZDebug(ctx, "writePump start", "goroutine ID:", getGoroutineID())
 
@@ -413,7 +525,25 @@ func laneByType(laneType ccontext.SendOrderLane, textLane, mediaLane *laneState)
 func (c *LongConnMgr) dispatchMessage(message Message) {
 	log.ZDebug(c.ctx, "writePump recv message", "reqIdentifier", message.Message.ReqIdentifier,
 		"operationID", message.Message.OperationID, "sendID", message.Message.SendID)
+	if message.Channel != "" {
+		// the response is waited for aside so that the following requests are written right away
+		tempChan, err := c.writeBinaryMsgAndRetry(&message.Message)
+		if err != nil {
+			c.Syncer.DelCh(message.Message.MsgIncr)
+			c.notifyResult(message, nil, err)
+			return
+		}
+		go func() {
+			resp, err := c.waitResp(&message.Message, tempChan)
+			c.notifyResult(message, resp, err)
+		}()
+		return
+	}
 	resp, err := c.sendAndWaitResp(&message.Message)
+	c.notifyResult(message, resp, err)
+}
+
+func (c *LongConnMgr) notifyResult(message Message, resp *GeneralWsResp, err error) {
 	if err != nil {
 		resp = &GeneralWsResp{
 			ReqIdentifier: message.Message.ReqIdentifier,
@@ -434,18 +564,12 @@ func (c *LongConnMgr) dispatchMessage(message Message) {
 }
 
 func (c *LongConnMgr) heartbeat(ctx context.Context, fgCtx context.Context) {
-	defer func() {
-		if r := recover(); r != nil {
-			err := fmt.Sprintf("panic: %+v\n%s", r, debug.Stack())
-
-			log.ZWarn(ctx, "heartbeat panic", nil, "panic info", err)
-		}
-	}()
+	defer crash.Recover(ctx, "heartbeat")
 
 	log.ZDebug(ctx, "heartbeat start", "goroutine ID:", getGoroutineID())
-	ticker := time.NewTicker(pingPeriod)
+	timer := time.NewTimer(c.heartbeatPolicy.interval())
 	defer func() {
-		ticker.Stop()
+		timer.Stop()
 		log.ZWarn(c.ctx, "heartbeat closed", nil, "heartbeat", "heartbeat done sdk logout.....")
 	}()
 	for {
@@ -457,10 +581,12 @@ func (c *LongConnMgr) heartbeat(ctx context.Context, fgCtx context.Context) {
 			c.closedErr = context.Cause(fgCtx)
 			log.ZInfo(c.ctx, "SDK transitioning from foreground to background, heartbeat goroutine ended.")
 			return
-		case <-ticker.C:
+		case <-c.heartbeatPolicy.changed:
+		case <-timer.C:
 			log.ZInfo(ctx, "sendPingMessage", "goroutine ID:", getGoroutineID())
 			c.sendPingMessage(ctx)
 		}
+		timer.Reset(c.heartbeatPolicy.interval())
 	}
 
 }
@@ -475,8 +601,15 @@ func (c *LongConnMgr) sendPingMessage(ctx context.Context) {
 		c.conn.SetWriteDeadline(writeWait)
 		if err := c.conn.WriteMessage(PingMessage, []byte(opid)); err != nil {
 			log.ZWarn(ctx, "ping Message failed", err, "goroutine ID:", getGoroutineID(), "opid", opid)
+			c.heartbeatPolicy.onFailure()
 			return
 		}
+		c.onPingSent()
+		if c.connType == LongPollingConn && c.fallback.probeWebSocket() {
+			// reconnect to find out whether websocket works again
+			c.closedErr = errors.New("probe websocket")
+			_ = c.close()
+		}
 	} else {
 		log.ZDebug(ctx, "ping Message failed, connection", "connStatus", c.GetConnectionStatus(), "goroutine ID:", getGoroutineID(), "opid", opid)
 	}
@@ -537,17 +670,22 @@ func getGoroutineID() int64 {
 
 func (c *LongConnMgr) sendAndWaitResp(msg *GeneralWsReq) (*GeneralWsResp, error) {
 	tempChan, err := c.writeBinaryMsgAndRetry(msg)
-	defer c.Syncer.DelCh(msg.MsgIncr)
 	if err != nil {
+		c.Syncer.DelCh(msg.MsgIncr)
 		return nil, err
-	} else {
-		select {
-		case resp := <-tempChan:
-			return resp, nil
-		case <-time.After(sendAndWaitTime):
-			return nil, sdkerrs.ErrNetworkTimeOut
-		}
+	}
+	return c.waitResp(msg, tempChan)
+}
 
+func (c *LongConnMgr) waitResp(msg *GeneralWsReq, tempChan chan *GeneralWsResp) (*GeneralWsResp, error) {
+	defer c.Syncer.DelCh(msg.MsgIncr)
+	select {
+	case resp := <-tempChan:
+		c.onAck(false)
+		return resp, nil
+	case <-time.After(sendAndWaitTime):
+		c.onAck(true)
+		return nil, sdkerrs.ErrNetworkTimeOut
 	}
 }
 
@@ -624,15 +762,29 @@ func (c *LongConnMgr) writeBinaryMsgNoLock(req GeneralWsReq) error {
 		return sdkerrs.ErrNetwork.WrapMsg("connection closed,re conning...")
 	}
 	_ = c.conn.SetWriteDeadline(writeWait)
-	if c.IsCompression {
+	network.AddWsRawBytes(len(encodeBuf))
+	if c.compression.Load() {
 		resultBuf, compressErr := c.compressor.CompressWithPool(encodeBuf)
 		if compressErr != nil {
 			return compressErr
 		}
-		return c.conn.WriteMessage(MessageBinary, resultBuf)
-	} else {
-		return c.conn.WriteMessage(MessageBinary, encodeBuf)
+		network.AddWsCompressed(len(encodeBuf), len(resultBuf))
+		encodeBuf = resultBuf
 	}
+	f := fault.NextFrame()
+	if f.Drop {
+		log.ZWarn(c.ctx, "fault injected, frame sent dropped", nil, "reqIdentifier", req.ReqIdentifier)
+		return nil
+	}
+	if f.Delay > 0 {
+		time.Sleep(f.Delay)
+	}
+	if f.Duplicate {
+		if err := c.conn.WriteMessage(MessageBinary, encodeBuf); err != nil {
+			return err
+		}
+	}
+	return c.conn.WriteMessage(MessageBinary, encodeBuf)
 }
 
 func (c *LongConnMgr) close() error {
@@ -647,14 +799,17 @@ func (c *LongConnMgr) close() error {
 }
 
 func (c *LongConnMgr) handleMessage(message []byte) error {
-	if c.IsCompression {
+	if c.compression.Load() {
+		compressed := len(message)
 		var decompressErr error
 		message, decompressErr = c.compressor.DecompressWithPool(message)
 		if decompressErr != nil {
 			log.ZError(c.ctx, "DeCompress failed", decompressErr, message)
 			return sdkerrs.ErrMsgDeCompression
 		}
+		network.AddWsCompressed(len(message), compressed)
 	}
+	network.AddWsRawBytes(len(message))
 	var wsResp GeneralWsResp
 	err := c.encoder.Decode(message, &wsResp)
 	if err != nil {
@@ -664,6 +819,7 @@ func (c *LongConnMgr) handleMessage(message []byte) error {
 	ctx := context.WithValue(c.ctx, "operationID", wsResp.OperationID)
 	log.ZInfo(ctx, "recv msg", "errCode", wsResp.ErrCode, "errMsg", wsResp.ErrMsg,
 		"reqIdentifier", wsResp.ReqIdentifier)
+	recordFrame(&wsResp)
 	switch wsResp.ReqIdentifier {
 	case constant.PushMsg:
 		if err = c.doPushMsg(ctx, wsResp); err != nil {
@@ -813,19 +969,40 @@ func (c *LongConnMgr) reConn(ctx context.Context, num *int) (needRecon bool, err
 	if c.IsConnected() {
 		return true, nil
 	}
+	if *num > 0 {
+		defer func() {
+			result := "success"
+			if err != nil {
+				result = "failed"
+			}
+			telemetry.Add(telemetry.WsReconnects, 1, telemetry.String("result", result))
+		}()
+	}
 	c.connWrite.Lock()
 	defer c.connWrite.Unlock()
 	c.listener().OnConnecting()
+	c.notifyState(&sdk_struct.ConnState{State: constant.ConnStateConnecting})
 	c.SetConnectionStatus(Connecting)
+	addr := c.selectTransport(ctx)
+	c.selectCompression(ctx)
+	if dialer, ok := c.conn.(contextDialer); ok {
+		dialer.SetUserContext(ctx)
+	}
+	if reporter, ok := c.conn.(dialStateReporter); ok {
+		reporter.SetDialStateHandler(func(state string) {
+			c.notifyState(&sdk_struct.ConnState{State: state})
+		})
+	}
 	url := fmt.Sprintf("%s?sendID=%s&token=%s&platformID=%d&operationID=%s&isBackground=%t&sdkVersion=%s",
-		ccontext.Info(ctx).WsAddr(), ccontext.Info(ctx).UserID(), ccontext.Info(ctx).Token(),
+		addr, ccontext.Info(ctx).UserID(), ccontext.Info(ctx).Token(),
 		ccontext.Info(ctx).PlatformID(), ccontext.Info(ctx).OperationID(), c.GetBackground(),
 		version.Version)
-	if c.IsCompression {
+	if c.compression.Load() {
 		url += fmt.Sprintf("&compression=%s", "gzip")
 	}
 	log.ZDebug(ctx, "conn start", "url", url)
 	resp, err := c.conn.Dial(url, nil)
+	c.fallback.dialed(c.connType, resp, err)
 	if err != nil {
 		c.SetConnectionStatus(Closed)
 		if resp != nil {
@@ -859,12 +1036,14 @@ func (c *LongConnMgr) reConn(ctx context.Context, num *int) (needRecon bool, err
 			}
 		}
 		c.listener().OnConnectFailed(sdkerrs.NetworkError, err.Error())
+		c.notifyState(&sdk_struct.ConnState{State: constant.ConnStateDisconnected, ErrCode: sdkerrs.NetworkError, ErrMsg: err.Error()})
 		return true, err
 	}
 	if err := c.writeConnFirstSubMsg(ctx); err != nil {
 		log.ZError(ctx, "first write user online sub info error", err)
 		ccontext.GetApiErrCodeCallback(ctx).OnError(ctx, err)
 		c.listener().OnConnectFailed(sdkerrs.NetworkError, err.Error())
+		c.notifyState(&sdk_struct.ConnState{State: constant.ConnStateDisconnected, ErrCode: sdkerrs.NetworkError, ErrMsg: err.Error()})
 		c.conn.Close()
 		return true, err
 	}
@@ -878,10 +1057,96 @@ func (c *LongConnMgr) reConn(ctx context.Context, num *int) (needRecon bool, err
 	*num++
 	log.ZInfo(c.ctx, "long conn establish success", "localAddr", c.conn.LocalAddr(), "connNum", *num)
 	c.reconnectStrategy.Reset()
+	recorder.Record(&recorder.Event{Kind: recorder.KindConnected})
 	_ = common.DispatchConnected(ctx, c.pushMsgAndMaxSeqCh)
+	select {
+	case c.serverTimeSync <- struct{}{}:
+	default:
+	}
+	c.runConnectedHooks(ctx)
 	return true, nil
 }
 
+// OnConnected registers fn to run after each successful connection, for the state the server has to be told
+// again after reconnecting. The hooks run in their own goroutine and must not block the connection.
+func (c *LongConnMgr) OnConnected(fn func(ctx context.Context)) {
+	c.connectedLock.Lock()
+	defer c.connectedLock.Unlock()
+	c.connectedHooks = append(c.connectedHooks, fn)
+}
+
+func (c *LongConnMgr) runConnectedHooks(ctx context.Context) {
+	c.connectedLock.Lock()
+	hooks := c.connectedHooks
+	c.connectedLock.Unlock()
+	if len(hooks) == 0 {
+		return
+	}
+	go func() {
+		for _, fn := range hooks {
+			fn(ctx)
+		}
+	}()
+}
+
+// selectTransport switches the long connection to the configured transport and returns its address.
+// With the long polling fallback on, websocket failing to connect several times in a row switches to long
+// polling, which tries websocket again from time to time. A server answering it has no long polling endpoints keeps the
+// connection on websocket.
+func (c *LongConnMgr) selectTransport(ctx context.Context) string {
+	if c.connType == CustomConn {
+		return ccontext.Info(ctx).WsAddr()
+	}
+	connType := WebSocket
+	switch ccontext.Info(ctx).Transport() {
+	case TransportQUIC:
+		// a build without it does not take the config
+		connType = QUIC
+	case TransportLongPolling:
+		if c.fallback.longPollingSupported() {
+			connType = LongPollingConn
+		} else {
+			log.ZWarn(ctx, "long polling not supported by the server, using websocket", nil)
+		}
+	default:
+		if c.fallback.useLongPolling(ccontext.Info(ctx).LongPollingFallback()) {
+			connType = LongPollingConn
+		}
+	}
+	if connType != c.connType {
+		switch connType {
+		case QUIC:
+			c.conn = newQuic()
+		case LongPollingConn:
+			c.conn = NewLongPolling(LongPollingConn)
+		default:
+			c.conn = NewWebSocket(WebSocket)
+		}
+		log.ZInfo(ctx, "long connection transport changed", "from", c.connType, "to", connType)
+		c.connType = connType
+	}
+	if connType == QUIC && ccontext.Info(ctx).QuicAddr() != "" {
+		return ccontext.Info(ctx).QuicAddr()
+	}
+	return ccontext.Info(ctx).WsAddr()
+}
+
+// SetConn has the long connection dial conn instead of the transport configured, e.g. a scripted one in
+// tests. Set before Run.
+func (c *LongConnMgr) SetConn(conn LongConn) {
+	c.conn = conn
+	c.connType = CustomConn
+}
+
+// selectCompression applies the configured compression to the following connection.
+func (c *LongConnMgr) selectCompression(ctx context.Context) {
+	mode := ccontext.Info(ctx).Compression()
+	c.compression.Store(mode == "" || mode == constant.CompressionGzip)
+	if negotiator, ok := c.conn.(compressionNegotiator); ok {
+		negotiator.EnableCompression(mode == constant.CompressionDeflate)
+	}
+}
+
 func (c *LongConnMgr) doPushMsg(ctx context.Context, wsResp GeneralWsResp) error {
 	var msg sdkws.PushMessages
 	err := proto.Unmarshal(wsResp.Data, &msg)
@@ -941,11 +1206,59 @@ func (c *LongConnMgr) SetBackground(isBackground bool) {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 	c.IsBackground = isBackground
+	c.heartbeatPolicy.setBackground(isBackground)
+}
+
+// SetReducedActivity switches the reduced activity mode of the app in background: the heartbeat slows down
+// to its longest interval, the gaps of the pushed messages are left to the catch-up sync of the foreground
+// and the pushed messages still buffered are written right away.
+func (c *LongConnMgr) SetReducedActivity(reduced bool) {
+	c.mutex.Lock()
+	c.reducedActivity = reduced
+	c.mutex.Unlock()
+	c.heartbeatPolicy.setReduced(reduced)
+	if reduced {
+		c.mb.Flush()
+	}
+}
+
+func (c *LongConnMgr) ReducedActivity() bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.reducedActivity
+}
+
+// SetHeartbeat sets the ping interval, with adaptive on the interval grows up to maxInterval while the
+// connection is stable, and is maxInterval in background.
+func (c *LongConnMgr) SetHeartbeat(interval, maxInterval time.Duration, adaptive bool) {
+	c.heartbeatPolicy.set(interval, maxInterval, adaptive)
+}
+
+// SetReconnectPolicy sets the delays between the reconnection attempts, see BackoffRetry.
+func (c *LongConnMgr) SetReconnectPolicy(initial time.Duration, multiplier float64, max time.Duration, jitter float64, maxAttempts int) {
+	if b, ok := c.reconnectStrategy.(*BackoffRetry); ok {
+		b.Set(initial, multiplier, max, jitter, maxAttempts)
+	}
+}
+
+// ForceReconnect reconnects right away instead of waiting for the next attempt, also after the attempts were
+// exhausted. Nothing happens while connected.
+func (c *LongConnMgr) ForceReconnect(ctx context.Context) error {
+	if c.IsConnected() {
+		log.ZInfo(ctx, "force reconnect while connected, ignored")
+		return nil
+	}
+	c.reconnectStrategy.Reset()
+	select {
+	case c.forceReconnect <- struct{}{}:
+	default:
+	}
+	return nil
 }
 
 // receive ping and send pong.
 func (c *LongConnMgr) pingHandler(_ string) error {
-	if err := c.conn.SetReadDeadline(pongWait); err != nil {
+	if err := c.conn.SetReadDeadline(c.heartbeatPolicy.readTimeout()); err != nil {
 		return err
 	}
 
@@ -955,7 +1268,9 @@ func (c *LongConnMgr) pingHandler(_ string) error {
 // when client send pong.
 func (c *LongConnMgr) pongHandler(appData string) error {
 	log.ZDebug(c.ctx, "server Pong Message Received", "appData", appData)
-	if err := c.conn.SetReadDeadline(pongWait); err != nil {
+	c.heartbeatPolicy.onPong()
+	c.onPong()
+	if err := c.conn.SetReadDeadline(c.heartbeatPolicy.readTimeout()); err != nil {
 		return err
 	}
 	return nil
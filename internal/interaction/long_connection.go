@@ -59,3 +59,8 @@ type LongConn interface {
 	// LocalAddr returns the local network address.
 	LocalAddr() string
 }
+
+// compressionNegotiator is implemented by the connections that can negotiate compression in the handshake.
+type compressionNegotiator interface {
+	EnableCompression(enable bool)
+}
@@ -0,0 +1,340 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interaction
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/network"
+)
+
+const (
+	longPollingPath = "/longpoll"
+	// the server holds a poll for at most this long before answering with no content
+	longPollingHold = 20 * time.Second
+)
+
+var (
+	ErrLongPollingClosed = errors.New("long polling session closed")
+	// ErrLongPollingUnsupported is returned by Dial when the server has no long polling endpoints.
+	ErrLongPollingUnsupported = errors.New("long polling not supported by the server")
+)
+
+// LongPolling is a LongConn tunneled over plain HTTP requests, for networks blocking websocket.
+//
+//   - POST {base}/connect?{the websocket query} answers {"errCode":0,"data":{"sessionID":""}}, an error
+//     body is the same as the one of a rejected websocket handshake.
+//   - POST {base}/send?sessionID= with one frame in the body.
+//   - POST {base}/recv?sessionID=&ack= is held by the server until there are frames with a sequence greater
+//     than ack. The body is the 8 bytes big endian sequence of the first frame followed by the frames, a
+//     poll without frames answers 204.
+//   - POST {base}/close?sessionID=
+//
+// Servers without these endpoints answer the connect with 404, and the long connection stays on websocket.
+// The frames are the ones of the other transports without framing, see encodeFrame. The sequence makes
+// frames answered again after a lost response be dropped, so messages arrive once and in order.
+type LongPolling struct {
+	ConnType  int
+	client    *http.Client
+	base      string
+	sessionID string
+	ack       uint64
+	pending   []longPollingFrame
+
+	lock          sync.Mutex
+	readDeadline  time.Time
+	writeDeadline time.Time
+	readLimit     int64
+	pingHandler   PingPongHandler
+	pongHandler   PingPongHandler
+	ctx           context.Context
+	cancel        context.CancelFunc
+	// userCtx is of the user context whose network settings the requests take
+	userCtx context.Context
+}
+
+type longPollingFrame struct {
+	typ  byte
+	data []byte
+}
+
+func NewLongPolling(connType int) *LongPolling {
+	l := &LongPolling{ConnType: connType, userCtx: context.Background()}
+	l.client = &http.Client{Transport: &http.Transport{
+		Proxy: func(req *http.Request) (*url.URL, error) {
+			return network.WsProxy(l.getUserContext())(req)
+		},
+		TLSClientConfig: &tls.Config{VerifyConnection: func(cs tls.ConnectionState) error {
+			return network.TLSConfig(l.getUserContext(), "").VerifyConnection(cs)
+		}},
+	}}
+	return l
+}
+
+// SetUserContext makes the following requests go through the proxy, check the pins and take the bandwidth of
+// the user context of ctx.
+func (l *LongPolling) SetUserContext(ctx context.Context) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.userCtx = ctx
+}
+
+func (l *LongPolling) getUserContext() context.Context {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return l.userCtx
+}
+
+// longPollingBase turns the websocket address into the http address of the long polling endpoints.
+func longPollingBase(wsAddr string) string {
+	switch {
+	case strings.HasPrefix(wsAddr, "wss://"):
+		wsAddr = "https://" + strings.TrimPrefix(wsAddr, "wss://")
+	case strings.HasPrefix(wsAddr, "ws://"):
+		wsAddr = "http://" + strings.TrimPrefix(wsAddr, "ws://")
+	}
+	return strings.TrimSuffix(wsAddr, "/") + longPollingPath
+}
+
+func (l *LongPolling) Dial(urlStr string, _ http.Header) (*http.Response, error) {
+	u, err := url.Parse(urlStr)
+	if err != nil {
+		return nil, err
+	}
+	query := u.RawQuery
+	u.RawQuery = ""
+	l.base = longPollingBase(u.String())
+	l.ctx, l.cancel = context.WithCancel(context.WithoutCancel(l.getUserContext()))
+	l.sessionID, l.ack, l.pending = "", 0, nil
+	ctx, cancel := context.WithTimeout(l.ctx, writeWait)
+	defer cancel()
+	resp, body, err := l.post(ctx, "/connect?"+query, nil)
+	if err != nil {
+		return nil, err
+	}
+	switch resp.StatusCode {
+	case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return nil, ErrLongPollingUnsupported
+	}
+	var result struct {
+		ErrCode int `json:"errCode"`
+		Data    struct {
+			SessionID string `json:"sessionID"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, err
+	}
+	if result.ErrCode != 0 || result.Data.SessionID == "" {
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		return resp, errors.New("long polling connect rejected")
+	}
+	l.sessionID = result.Data.SessionID
+	return nil, nil
+}
+
+func (l *LongPolling) post(ctx context.Context, path string, body []byte) (*http.Response, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, l.base+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(network.ThrottleReader(ctx, constant.BandwidthSync, resp.Body))
+	if err != nil {
+		return nil, nil, err
+	}
+	network.AddWsWireBytes(len(body) + len(data))
+	return resp, data, nil
+}
+
+func (l *LongPolling) Close() error {
+	if l.cancel == nil {
+		return nil
+	}
+	l.cancel()
+	if l.sessionID != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), writeWait)
+		defer cancel()
+		_, _, _ = l.post(ctx, "/close?sessionID="+url.QueryEscape(l.sessionID), nil)
+	}
+	return nil
+}
+
+// WriteMessage sends the frame in its own request, a ping is answered by the request succeeding.
+func (l *LongPolling) WriteMessage(messageType int, message []byte) error {
+	if messageType == PongMessage {
+		return nil
+	}
+	ctx, cancel := l.deadlineContext(l.getWriteDeadline())
+	defer cancel()
+	resp, _, err := l.post(ctx, "/send?sessionID="+url.QueryEscape(l.sessionID), encodeFrame(byte(messageType), message))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("long polling send status %d", resp.StatusCode)
+	}
+	if messageType == PingMessage {
+		l.lock.Lock()
+		handler := l.pongHandler
+		l.lock.Unlock()
+		if handler != nil {
+			return handler(string(message))
+		}
+	}
+	return nil
+}
+
+func (l *LongPolling) ReadMessage() (int, []byte, error) {
+	for {
+		if len(l.pending) > 0 {
+			frame := l.pending[0]
+			l.pending = l.pending[1:]
+			if frame.typ == PingMessage {
+				l.lock.Lock()
+				handler := l.pingHandler
+				l.lock.Unlock()
+				if handler != nil {
+					if err := handler(string(frame.data)); err != nil {
+						return 0, nil, err
+					}
+				}
+				continue
+			}
+			return int(frame.typ), frame.data, nil
+		}
+		if err := l.poll(); err != nil {
+			return 0, nil, err
+		}
+	}
+}
+
+func (l *LongPolling) poll() error {
+	deadline := l.getReadDeadline()
+	if !deadline.IsZero() && time.Until(deadline) > longPollingHold+writeWait {
+		deadline = time.Now().Add(longPollingHold + writeWait)
+	}
+	ctx, cancel := l.deadlineContext(deadline)
+	defer cancel()
+	resp, body, err := l.post(ctx, fmt.Sprintf("/recv?sessionID=%s&ack=%d", url.QueryEscape(l.sessionID), l.ack), nil)
+	if err != nil {
+		if l.ctx.Err() != nil {
+			return ErrLongPollingClosed
+		}
+		return err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNoContent:
+		return nil
+	case http.StatusNotFound, http.StatusGone:
+		return ErrLongPollingClosed
+	default:
+		return fmt.Errorf("long polling recv status %d", resp.StatusCode)
+	}
+	if len(body) < 8 {
+		return ErrNotSupportMessageProtocol
+	}
+	seq := binary.BigEndian.Uint64(body[:8])
+	reader := bytes.NewReader(body[8:])
+	for ; reader.Len() > 0; seq++ {
+		typ, data, err := decodeFrame(reader, l.readLimit)
+		if err != nil {
+			return err
+		}
+		if seq <= l.ack {
+			// sent again because the previous response was lost
+			continue
+		}
+		l.ack = seq
+		l.pending = append(l.pending, longPollingFrame{typ: typ, data: data})
+	}
+	return nil
+}
+
+func (l *LongPolling) deadlineContext(deadline time.Time) (context.Context, context.CancelFunc) {
+	if deadline.IsZero() {
+		return context.WithCancel(l.ctx)
+	}
+	return context.WithDeadline(l.ctx, deadline)
+}
+
+func (l *LongPolling) getReadDeadline() time.Time {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return l.readDeadline
+}
+
+func (l *LongPolling) getWriteDeadline() time.Time {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return l.writeDeadline
+}
+
+func (l *LongPolling) SetReadDeadline(timeout time.Duration) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.readDeadline = time.Now().Add(timeout)
+	return nil
+}
+
+func (l *LongPolling) SetWriteDeadline(timeout time.Duration) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.writeDeadline = time.Now().Add(timeout)
+	return nil
+}
+
+func (l *LongPolling) IsNil() bool {
+	return l.sessionID == ""
+}
+
+func (l *LongPolling) SetReadLimit(limit int64) {
+	l.readLimit = limit
+}
+
+func (l *LongPolling) SetPingHandler(handler PingPongHandler) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.pingHandler = handler
+}
+
+func (l *LongPolling) SetPongHandler(handler PingPongHandler) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.pongHandler = handler
+}
+
+func (l *LongPolling) LocalAddr() string {
+	return "longpoll/" + l.sessionID
+}
@@ -54,6 +54,16 @@ func (b *MessageBatcher) Close() {
 	dispatch(handler, ctxs, pending)
 }
 
+// Flush hands the buffered data to the handler right away.
+func (b *MessageBatcher) Flush() {
+	b.mutex.Lock()
+	pending, ctxs := b.consumeLocked()
+	b.cancelTimerLocked()
+	handler := b.handler
+	b.mutex.Unlock()
+	dispatch(handler, ctxs, pending)
+}
+
 func (b *MessageBatcher) dispatch(ctxs []context.Context, messages *sdkws.PushMessages) {
 	if messages == nil || b.handler == nil {
 		return
@@ -17,21 +17,25 @@ package interaction
 import (
 	"context"
 	"errors"
-	"fmt"
-	"runtime/debug"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/api"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/common"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/crash"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/db_interface"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/errreport"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/telemetry"
 	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
 	"github.com/openimsdk/protocol/msg"
 	"github.com/openimsdk/protocol/sdkws"
 	"github.com/openimsdk/tools/errs"
-	"github.com/openimsdk/tools/log"
 )
 
 const (
@@ -56,6 +60,10 @@ type MsgSyncer struct {
 	reinstalled            bool                  //true if the app was uninstalled and reinstalled
 	isSyncing              bool                  // indicates whether data is being synced
 	isSyncingLock          sync.Mutex            // lock for syncing state
+	catchUpPending         atomic.Bool           // the foreground catch-up sync did not finish yet
+	seqGaps                seqGaps               // the seq ranges missed by the pushes
+	syncWorkers            int                   // number of message batches pulled at the same time
+	lifecycleListener      func() open_im_sdk_callback.OnAppLifecycleListener
 }
 
 func (m *MsgSyncer) SetLoginUserID(loginUserID string) {
@@ -66,6 +74,10 @@ func (m *MsgSyncer) SetDataBase(db db_interface.DataBase) {
 	m.db = db
 }
 
+func (m *MsgSyncer) SetAppLifecycleListener(listener func() open_im_sdk_callback.OnAppLifecycleListener) {
+	m.lifecycleListener = listener
+}
+
 // NewMsgSyncer creates a new instance of the message synchronizer.
 func NewMsgSyncer(conversationEventQueue, PushMsgAndMaxSeqCh chan common.Cmd2Value,
 	longConnMgr *LongConnMgr) *MsgSyncer {
@@ -110,8 +122,11 @@ func (m *MsgSyncer) LoadSeq(ctx context.Context) error {
 	}
 	var wg sync.WaitGroup
 	resultMaps := make([]map[string]SyncedSeq, currency)
+	// the parts are read by at most the number of sync workers at the same time
+	workers := make(chan struct{}, m.workers())
 
 	for i := 0; i < currency; i++ {
+		workers <- struct{}{}
 		wg.Add(1)
 		start := i * partSize
 		end := start + partSize
@@ -122,7 +137,10 @@ func (m *MsgSyncer) LoadSeq(ctx context.Context) error {
 		resultMaps[i] = make(map[string]SyncedSeq)
 
 		go func(i, start, end int) {
-			defer wg.Done()
+			defer func() {
+				<-workers
+				wg.Done()
+			}()
 			for _, v := range conversationIDList[start:end] {
 				maxSyncedSeq, err := m.db.CheckConversationNormalMsgSeq(ctx, v)
 				resultMaps[i][v] = SyncedSeq{
@@ -143,7 +161,7 @@ func (m *MsgSyncer) LoadSeq(ctx context.Context) error {
 				log.ZError(ctx, "get group normal seq failed", errs.Wrap(v.Err), "conversationID", k)
 				continue
 			}
-			m.syncedMaxSeqs[k] = v.MaxSyncedSeq
+			m.setSyncedMaxSeq(k, v.MaxSyncedSeq)
 		}
 	}
 	notificationSeqs, err := m.db.GetNotificationAllSeqs(ctx)
@@ -152,7 +170,7 @@ func (m *MsgSyncer) LoadSeq(ctx context.Context) error {
 		return err
 	}
 	for _, notificationSeq := range notificationSeqs {
-		m.syncedMaxSeqs[notificationSeq.ConversationID] = notificationSeq.Seq
+		m.setSyncedMaxSeq(notificationSeq.ConversationID, notificationSeq.Seq)
 	}
 	log.ZDebug(ctx, "loadSeq", "syncedMaxSeqs", m.syncedMaxSeqs)
 	return nil
@@ -161,13 +179,7 @@ func (m *MsgSyncer) LoadSeq(ctx context.Context) error {
 // DoListener Listen to the message pipe of the message synchronizer
 // and process received and pushed messages
 func (m *MsgSyncer) DoListener(ctx context.Context) {
-	defer func() {
-		if r := recover(); r != nil {
-			err := fmt.Sprintf("panic: %+v\n%s", r, debug.Stack())
-
-			log.ZWarn(ctx, "DoListener panic", nil, "panic info", err)
-		}
-	}()
+	defer crash.Recover(ctx, "MsgSyncer DoListener")
 	for {
 		select {
 		case cmd := <-m.PushMsgAndMaxSeqCh:
@@ -205,6 +217,16 @@ func (m *MsgSyncer) handlePushMsgAndEvent(cmd common.Cmd2Value) {
 		} else {
 			log.ZWarn(cmd.Ctx, "syncing, ignore wake up event", nil, "cmd", cmd.Cmd, "value", cmd.Value)
 		}
+	case constant.CmdCatchUpSync:
+		log.ZInfo(cmd.Ctx, "app enter foreground, start catch-up sync", "cmd", cmd.Cmd, "value", cmd.Value)
+		m.catchUpPending.Store(true)
+		m.doCatchUpSync(cmd.Ctx)
+	case constant.CmdResyncMsgs:
+		log.ZInfo(cmd.Ctx, "force resync msgs", "cmd", cmd.Cmd, "value", cmd.Value)
+		m.doResyncMsgs(cmd.Ctx)
+	case constant.CmdBackfill:
+		log.ZInfo(cmd.Ctx, "backfill allowed, repair the seq gaps", "cmd", cmd.Cmd, "value", cmd.Value)
+		m.repairPendingGaps(cmd.Ctx)
 	case constant.CmdIMMessageSync:
 		if conversationIDs, ok := cmd.Value.([]string); ok {
 			log.ZInfo(cmd.Ctx, "manual trigger IM message synchronization", "cmd", cmd.Cmd, "value", cmd.Value)
@@ -241,7 +263,7 @@ func (m *MsgSyncer) getNeedSyncConversations(ctx context.Context, maxSeqToSync m
 				ConversationID: conversationID,
 				Seq:            seq,
 			})
-			m.syncedMaxSeqs[conversationID] = seq
+			m.setSyncedMaxSeq(conversationID, seq)
 		}
 
 		if len(notificationSeqs) > 0 {
@@ -300,7 +322,7 @@ func (m *MsgSyncer) compareSeqsAndBatchSync(ctx context.Context, maxSeqToSync ma
 				ConversationID: conversationID,
 				Seq:            seq,
 			})
-			m.syncedMaxSeqs[conversationID] = seq
+			m.setSyncedMaxSeq(conversationID, seq)
 		}
 
 		if len(notificationSeqs) > 0 {
@@ -327,7 +349,9 @@ func (m *MsgSyncer) compareSeqsAndBatchSync(ctx context.Context, maxSeqToSync ma
 			}
 			m.reinstalled = false
 		}()
-		_ = m.syncAndTriggerReinstallMsgs(ctx, needSyncSeqMap, pullNums)
+		_ = timeSync(ctx, "reinstall", needSyncSeqMap, func(ctx context.Context) error {
+			return m.syncAndTriggerReinstallMsgs(ctx, needSyncSeqMap, pullNums)
+		})
 	} else {
 		for conversationID, maxSeq := range maxSeqToSync {
 			if syncedMaxSeq, ok := m.syncedMaxSeqs[conversationID]; ok {
@@ -340,10 +364,26 @@ func (m *MsgSyncer) compareSeqsAndBatchSync(ctx context.Context, maxSeqToSync ma
 				}
 			}
 		}
-		_ = m.syncAndTriggerMsgs(ctx, needSyncSeqMap, pullNums)
+		_ = timeSync(ctx, "incremental", needSyncSeqMap, func(ctx context.Context) error {
+			return m.syncAndTriggerMsgs(ctx, needSyncSeqMap, pullNums)
+		})
 	}
 }
 
+// timeSync records the duration of a sync of the messages for the telemetry, a sync with nothing to pull is
+// not recorded.
+func timeSync(ctx context.Context, kind string, seqMap map[string][2]int64, sync func(ctx context.Context) error) error {
+	if len(seqMap) == 0 {
+		return sync(ctx)
+	}
+	ctx, span := telemetry.StartSpan(ctx, "msg.sync", telemetry.String("kind", kind), telemetry.Int("conversations", len(seqMap)))
+	start := time.Now()
+	err := sync(ctx)
+	telemetry.Record(telemetry.SyncDuration, telemetry.Since(start), telemetry.String("kind", kind), telemetry.Bool("error", err != nil))
+	span.End(err)
+	return err
+}
+
 // startSync checks if the sync is already in progress.
 // If syncing is in progress, it returns false. Otherwise, it starts syncing and returns true.
 func (m *MsgSyncer) startSync() bool {
@@ -409,25 +449,37 @@ func (m *MsgSyncer) pushTriggerAndSync(ctx context.Context, pushMessages map[str
 		if lastSeq == expectedLast {
 			log.ZDebug(ctx, "trigger msgs", "conversationID", conversationID, "msgs", storageMsgs)
 			res[conversationID] = &sdkws.PullMsgs{Msgs: storageMsgs}
-			m.syncedMaxSeqs[conversationID] = lastSeq
+			m.setSyncedMaxSeq(conversationID, lastSeq)
 		} else if lastSeq > m.syncedMaxSeqs[conversationID] {
 			// must pull message when message type is notification
 			needSyncSeqMap[conversationID] = [2]int64{
 				m.syncedMaxSeqs[conversationID] + 1,
 				lastSeq,
 			}
+			// the pushed messages are not stored either, the whole range is pulled again
+			m.seqGaps.detect(ctx, conversationID, m.syncedMaxSeqs[conversationID]+1, lastSeq)
 		}
 	}
 
 	if len(res) > 0 {
 		_ = triggerFunc(ctx, res)
 	}
-	m.syncAndTriggerMsgs(ctx, needSyncSeqMap, defaultPullNums)
+	if len(needSyncSeqMap) > 0 && m.longConnMgr.ReducedActivity() {
+		log.ZDebug(ctx, "reduced activity, leave the gaps to the catch-up sync", "needSyncSeqMap", needSyncSeqMap)
+		return
+	}
+	m.repairSeqGaps(ctx, needSyncSeqMap)
 }
 
 // Called after successful reconnection to synchronize the latest message
 func (m *MsgSyncer) doConnected(ctx context.Context) {
 	reinstalled := m.reinstalled
+	m.longConnMgr.notifyState(&sdk_struct.ConnState{State: constant.ConnStateSyncing})
+	defer func() {
+		if m.longConnMgr.IsConnected() {
+			m.longConnMgr.notifyConnected()
+		}
+	}()
 	if reinstalled {
 		common.DispatchSyncFlag(ctx, constant.AppDataSyncStart, m.conversationEventQueue)
 	} else {
@@ -457,17 +509,20 @@ func (m *MsgSyncer) doConnected(ctx context.Context) {
 	}
 
 	if err != nil {
-		log.ZError(ctx, "get max seq error", err)
+		errreport.Report(ctx, errreport.SourceSync, "get max seq", err)
 		common.DispatchSyncFlag(ctx, constant.MsgSyncFailed, m.conversationEventQueue)
 		return
 	}
 
 	m.compareSeqsAndBatchSync(ctx, resp.MaxSeqs, connectPullNums)
+	m.repairPendingGaps(ctx)
 	if reinstalled {
 		common.DispatchSyncFlag(ctx, constant.AppDataSyncFinish, m.conversationEventQueue)
 	} else {
 		common.DispatchSyncFlag(ctx, constant.MsgSyncEnd, m.conversationEventQueue)
 	}
+	// the reconnection synced what the failed catch-up sync could not
+	m.notifyCaughtUp()
 }
 
 func (m *MsgSyncer) doWakeupDataSync(ctx context.Context) {
@@ -480,6 +535,47 @@ func (m *MsgSyncer) doWakeupDataSync(ctx context.Context) {
 		log.ZDebug(ctx, "get max seq success", "resp", resp.MaxSeqs)
 	}
 	m.compareSeqsAndBatchSync(ctx, resp.MaxSeqs, defaultPullNums)
+	m.repairPendingGaps(ctx)
+}
+
+// doCatchUpSync pulls what was missed in the reduced activity mode, only the latest messages of each
+// conversation like after connecting, so that the conversation list is right quickly, the older ones are
+// pulled when the conversation is opened. When it fails the sync after the next connection catches up.
+func (m *MsgSyncer) doCatchUpSync(ctx context.Context) {
+	common.DispatchSyncData(ctx, m.conversationEventQueue)
+	var resp sdkws.GetMaxSeqResp
+	if err := m.longConnMgr.SendReqWaitResp(ctx, &sdkws.GetMaxSeqReq{UserID: m.loginUserID}, constant.GetNewestSeq, &resp); err != nil {
+		log.ZWarn(ctx, "catch-up sync get max seq error, catch up after connecting", err)
+		return
+	}
+	m.compareSeqsAndBatchSync(ctx, resp.MaxSeqs, connectPullNums)
+	m.repairPendingGaps(ctx)
+	m.notifyCaughtUp()
+}
+
+// doResyncMsgs drops the synced seqs kept in memory, loads them again from the messages stored locally and
+// pulls what the server has beyond them.
+func (m *MsgSyncer) doResyncMsgs(ctx context.Context) {
+	m.syncedMaxSeqsLock.Lock()
+	m.syncedMaxSeqs = make(map[string]int64)
+	m.syncedMaxSeqsLock.Unlock()
+	if err := m.LoadSeq(ctx); err != nil {
+		log.ZError(ctx, "resync msgs load seq error", err)
+		return
+	}
+	var resp sdkws.GetMaxSeqResp
+	if err := m.longConnMgr.SendReqWaitResp(ctx, &sdkws.GetMaxSeqReq{UserID: m.loginUserID}, constant.GetNewestSeq, &resp); err != nil {
+		log.ZError(ctx, "resync msgs get max seq error", err)
+		return
+	}
+	m.compareSeqsAndBatchSync(ctx, resp.MaxSeqs, connectPullNums)
+}
+
+func (m *MsgSyncer) notifyCaughtUp() {
+	if !m.catchUpPending.CompareAndSwap(true, false) || m.lifecycleListener == nil {
+		return
+	}
+	m.lifecycleListener().OnSyncCaughtUp()
 }
 
 func (m *MsgSyncer) doIMMessageSync(ctx context.Context, conversationIDs []string) {
@@ -500,6 +596,24 @@ func (m *MsgSyncer) doIMMessageSync(ctx context.Context, conversationIDs []strin
 	m.compareSeqsAndBatchSync(ctx, maxSeqMap, defaultPullNums)
 }
 
+// setSyncedMaxSeq is only called by the goroutine of the syncer, which reads syncedMaxSeqs without the lock.
+func (m *MsgSyncer) setSyncedMaxSeq(conversationID string, seq int64) {
+	m.syncedMaxSeqsLock.Lock()
+	defer m.syncedMaxSeqsLock.Unlock()
+	m.syncedMaxSeqs[conversationID] = seq
+}
+
+// SyncedMaxSeqs returns the seq the messages of each conversation are synced up to.
+func (m *MsgSyncer) SyncedMaxSeqs() map[string]int64 {
+	m.syncedMaxSeqsLock.RLock()
+	defer m.syncedMaxSeqsLock.RUnlock()
+	seqs := make(map[string]int64, len(m.syncedMaxSeqs))
+	for conversationID, seq := range m.syncedMaxSeqs {
+		seqs[conversationID] = seq
+	}
+	return seqs
+}
+
 func IsNotification(conversationID string) bool {
 	return strings.HasPrefix(conversationID, "n_")
 }
@@ -512,11 +626,13 @@ func (m *MsgSyncer) syncAndTriggerMsgs(ctx context.Context, seqMap map[string][2
 
 	log.ZDebug(ctx, "current sync seqMap", "seqMap", seqMap)
 	var (
+		batches    []map[string][2]int64
 		tempSeqMap = make(map[string][2]int64, 50)
 		msgNum     = 0
 	)
 
-	for k, v := range seqMap {
+	for _, k := range m.prioritySyncOrder(ctx, seqMap) {
+		v := seqMap[k]
 		oneConversationSyncNum := v[1] - v[0] + 1
 		tempSeqMap[k] = v
 		// For notification conversations, use oneConversationSyncNum directly
@@ -527,18 +643,9 @@ func (m *MsgSyncer) syncAndTriggerMsgs(ctx context.Context, seqMap map[string][2
 			msgNum += int(min(oneConversationSyncNum, syncMsgNum))
 		}
 
-		// If accumulated msgNum reaches SplitPullMsgNum, trigger a batch pull
+		// If accumulated msgNum reaches SplitPullMsgNum, close the batch
 		if msgNum >= SplitPullMsgNum {
-			resp, err := m.pullMsgBySeqRange(ctx, tempSeqMap, syncMsgNum)
-			if err != nil {
-				log.ZError(ctx, "syncMsgFromServer error", err, "tempSeqMap", tempSeqMap)
-				return err
-			}
-			_ = m.triggerConversation(ctx, resp.Msgs)
-			_ = m.triggerNotification(ctx, resp.NotificationMsgs)
-			for conversationID, seqs := range tempSeqMap {
-				m.syncedMaxSeqs[conversationID] = seqs[1]
-			}
+			batches = append(batches, tempSeqMap)
 			// Reset tempSeqMap and msgNum to handle the next batch
 			tempSeqMap = make(map[string][2]int64, 50)
 			msgNum = 0
@@ -547,18 +654,17 @@ func (m *MsgSyncer) syncAndTriggerMsgs(ctx context.Context, seqMap map[string][2
 
 	// Handle remaining messages to ensure all are synced
 	if len(tempSeqMap) > 0 {
-		resp, err := m.pullMsgBySeqRange(ctx, tempSeqMap, syncMsgNum)
-		if err != nil {
-			log.ZError(ctx, "syncMsgFromServer error", err, "tempSeqMap", tempSeqMap)
-			return err
-		}
+		batches = append(batches, tempSeqMap)
+	}
+
+	err := m.backfill(ctx, batches, syncMsgNum, func(resp *sdkws.PullMessageBySeqsResp) {
 		_ = m.triggerConversation(ctx, resp.Msgs)
 		_ = m.triggerNotification(ctx, resp.NotificationMsgs)
-		for conversationID, seqs := range tempSeqMap {
-			m.syncedMaxSeqs[conversationID] = seqs[1]
-		}
+	})
+	if err != nil {
+		errreport.Report(ctx, errreport.SourceSync, "syncMsgFromServer", err, "batches", len(batches))
+		return err
 	}
-
 	return nil
 }
 
@@ -567,12 +673,14 @@ func (m *MsgSyncer) syncAndTriggerReinstallMsgs(ctx context.Context, seqMap map[
 	if len(seqMap) > 0 {
 		log.ZDebug(ctx, "current sync seqMap", "seqMap", seqMap)
 		var (
+			batches    []map[string][2]int64
 			tempSeqMap = make(map[string][2]int64, 50)
 			msgNum     = 0
 			total      = len(seqMap)
 		)
 
-		for k, v := range seqMap {
+		for _, k := range m.prioritySyncOrder(ctx, seqMap) {
+			v := seqMap[k]
 			oneConversationSyncNum := min(v[1]-v[0]+1, syncMsgNum)
 			tempSeqMap[k] = v
 			if oneConversationSyncNum > 0 {
@@ -581,39 +689,24 @@ func (m *MsgSyncer) syncAndTriggerReinstallMsgs(ctx context.Context, seqMap map[
 			}
 
 			if msgNum >= SplitPullMsgNum {
-				resp, err := m.pullMsgBySeqRange(ctx, tempSeqMap, syncMsgNum)
-				if err != nil {
-					log.ZError(ctx, "syncMsgFromServer err", err, "tempSeqMap", tempSeqMap)
-					return err
-				}
-
-				m.checkMessagesAndGetLastMessage(ctx, resp.Msgs)
-				_ = m.triggerReinstallConversation(ctx, resp.Msgs, total)
-				_ = m.triggerNotification(ctx, resp.NotificationMsgs)
-
-				for conversationID, seqs := range tempSeqMap {
-					m.syncedMaxSeqs[conversationID] = seqs[1]
-				}
-
+				batches = append(batches, tempSeqMap)
 				tempSeqMap = make(map[string][2]int64, 50)
 				msgNum = 0
 			}
 		}
 
 		if len(tempSeqMap) > 0 && msgNum > 0 {
-			resp, err := m.pullMsgBySeqRange(ctx, tempSeqMap, syncMsgNum)
-			if err != nil {
-				log.ZError(ctx, "syncMsgFromServer err", err, "seqMap", seqMap)
-				return err
-			}
+			batches = append(batches, tempSeqMap)
+		}
 
+		err := m.backfill(ctx, batches, syncMsgNum, func(resp *sdkws.PullMessageBySeqsResp) {
 			m.checkMessagesAndGetLastMessage(ctx, resp.Msgs)
 			_ = m.triggerReinstallConversation(ctx, resp.Msgs, total)
 			_ = m.triggerNotification(ctx, resp.NotificationMsgs)
-
-			for conversationID, seqs := range tempSeqMap {
-				m.syncedMaxSeqs[conversationID] = seqs[1]
-			}
+		})
+		if err != nil {
+			errreport.Report(ctx, errreport.SourceSync, "syncMsgFromServer", err, "conversations", len(seqMap))
+			return err
 		}
 	} else {
 		log.ZDebug(ctx, "noting conversation to sync", "syncMsgNum", syncMsgNum)
@@ -677,8 +770,44 @@ func (m *MsgSyncer) pullMsgBySeqRange(ctx context.Context, seqMap map[string][2]
 			Num:            syncMsgNum,
 		})
 	}
+	if api.PullMessageBySeqs.Streamable(ctx) {
+		resp, err = m.streamMsgBySeqRange(ctx, &req)
+		if err == nil {
+			return resp, nil
+		}
+		log.ZWarn(ctx, "stream pull messages failed, pull over the long connection", err)
+	}
 	resp = &sdkws.PullMessageBySeqsResp{}
-	if err := m.longConnMgr.SendReqWaitResp(ctx, &req, constant.PullMsgByRange, resp); err != nil {
+	if err := m.longConnMgr.SendReqWaitResp(WithChannel(ctx, ChannelMessage), &req, constant.PullMsgByRange, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// streamMsgBySeqRange pulls the messages over a grpc stream, the server sends them in several parts instead
+// of a single large response.
+func (m *MsgSyncer) streamMsgBySeqRange(ctx context.Context, req *sdkws.PullMessageBySeqsReq) (*sdkws.PullMessageBySeqsResp, error) {
+	resp := &sdkws.PullMessageBySeqsResp{
+		Msgs:             make(map[string]*sdkws.PullMsgs),
+		NotificationMsgs: make(map[string]*sdkws.PullMsgs),
+	}
+	merge := func(dst, src map[string]*sdkws.PullMsgs) {
+		for conversationID, part := range src {
+			pulled, ok := dst[conversationID]
+			if !ok {
+				dst[conversationID] = part
+				continue
+			}
+			pulled.Msgs = append(pulled.Msgs, part.Msgs...)
+			pulled.IsEnd, pulled.EndSeq = part.IsEnd, part.EndSeq
+		}
+	}
+	err := api.PullMessageBySeqs.Stream(ctx, req, func(part *sdkws.PullMessageBySeqsResp) error {
+		merge(resp.Msgs, part.Msgs)
+		merge(resp.NotificationMsgs, part.NotificationMsgs)
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -692,7 +821,7 @@ func (m *MsgSyncer) fetchLatestValidMessages(ctx context.Context, conversationID
 		ConversationIDs: conversationID,
 	}
 	resp = &msg.GetLastMessageResp{}
-	if err := m.longConnMgr.SendReqWaitResp(ctx, &req, constant.PullConvLastMessage, resp); err != nil {
+	if err := m.longConnMgr.SendReqWaitResp(WithChannel(ctx, ChannelMessage), &req, constant.PullConvLastMessage, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -706,7 +835,7 @@ func (m *MsgSyncer) syncMsgBySeqs(ctx context.Context, conversationID string, se
 	seqsList := m.splitSeqs(split, seqsNeedSync)
 	for i := 0; i < len(seqsList); {
 		var pullMsgResp sdkws.PullMessageBySeqsResp
-		err := m.longConnMgr.SendReqWaitResp(ctx, &pullMsgReq, constant.PullMsgByRange, &pullMsgResp)
+		err := m.longConnMgr.SendReqWaitResp(WithChannel(ctx, ChannelMessage), &pullMsgReq, constant.PullMsgByRange, &pullMsgResp)
 		if err != nil {
 			log.ZError(ctx, "syncMsgFromServerSplit err", err, "pullMsgReq", &pullMsgReq)
 			continue
@@ -720,6 +849,7 @@ func (m *MsgSyncer) syncMsgBySeqs(ctx context.Context, conversationID string, se
 // triggers a conversation with a new message.
 func (m *MsgSyncer) triggerConversation(ctx context.Context, msgs map[string]*sdkws.PullMsgs) error {
 	if len(msgs) > 0 {
+		recordDispatch(ctx, constant.CmdNewMsgCome, msgs)
 		err := common.DispatchNewMessage(ctx, sdk_struct.CmdNewMsgComeToConversation{Msgs: msgs}, m.conversationEventQueue)
 		if err != nil {
 			log.ZError(ctx, "triggerCmdNewMsgCome err", err, "msgs", msgs)
@@ -735,6 +865,7 @@ func (m *MsgSyncer) triggerConversation(ctx context.Context, msgs map[string]*sd
 // triggers a conversation with a new message.
 func (m *MsgSyncer) triggerReinstallConversation(ctx context.Context, msgs map[string]*sdkws.PullMsgs, total int) (err error) {
 	if len(msgs) > 0 {
+		recordDispatch(ctx, constant.CmdMsgSyncInReinstall, msgs)
 		err = common.DispatchMsgSyncInReinstall(ctx, sdk_struct.CmdMsgSyncInReinstall{
 			Msgs:  msgs,
 			Total: total,
@@ -752,6 +883,7 @@ func (m *MsgSyncer) triggerReinstallConversation(ctx context.Context, msgs map[s
 
 func (m *MsgSyncer) triggerNotification(ctx context.Context, msgs map[string]*sdkws.PullMsgs) error {
 	if len(msgs) > 0 {
+		recordDispatch(ctx, constant.CmdNotification, msgs)
 		common.DispatchNotification(ctx, sdk_struct.CmdNewMsgComeToConversation{Msgs: msgs}, m.conversationEventQueue)
 	} else {
 		log.ZDebug(ctx, "triggerNotification is nil", "notifications", msgs)
@@ -0,0 +1,172 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interaction
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+const (
+	DefaultDegradedRTT      = 2 * time.Second
+	DefaultDegradedLossRate = 0.2
+	// number of the latest pings and requests the loss rate is computed over
+	lossWindow = 20
+)
+
+// networkQuality estimates the quality of the long connection from the heartbeat and the request acks.
+// The round trip time is smoothed as in RFC 6298 and the jitter as in RFC 3550.
+type networkQuality struct {
+	lock sync.Mutex
+
+	rtt     float64
+	jitter  float64
+	lastRTT float64
+	hasRTT  bool
+	// pingTime is when the unanswered ping was sent in milliseconds
+	pingTime int64
+
+	// outcomes of the latest pings and requests, true when lost
+	outcomes     [lossWindow]bool
+	outcomeCount int
+	outcomeNext  int
+
+	wireBytes  int64
+	wireTime   int64
+	throughput float64
+
+	degradedRTT      time.Duration
+	degradedLossRate float64
+	weak             bool
+}
+
+func newNetworkQuality() *networkQuality {
+	return &networkQuality{degradedRTT: DefaultDegradedRTT, degradedLossRate: DefaultDegradedLossRate}
+}
+
+func (q *networkQuality) setThresholds(rtt time.Duration, lossRate float64) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if rtt <= 0 {
+		rtt = DefaultDegradedRTT
+	}
+	if lossRate <= 0 || lossRate > 1 {
+		lossRate = DefaultDegradedLossRate
+	}
+	q.degradedRTT, q.degradedLossRate = rtt, lossRate
+}
+
+// pingSent counts the previous ping as lost when it is still unanswered.
+func (q *networkQuality) pingSent(now int64) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if q.pingTime != 0 {
+		q.record(true)
+	}
+	q.pingTime = now
+}
+
+// pongReceived returns true when the connection crossed the weak threshold.
+func (q *networkQuality) pongReceived(now int64, wireBytes int64) bool {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if q.pingTime == 0 {
+		return false
+	}
+	q.addRTT(float64(now - q.pingTime))
+	q.pingTime = 0
+	q.record(false)
+	if q.wireTime != 0 && now > q.wireTime {
+		q.throughput = float64(wireBytes-q.wireBytes) * 1000 / float64(now-q.wireTime)
+	}
+	q.wireBytes, q.wireTime = wireBytes, now
+	return q.updateWeak()
+}
+
+// ackReceived records whether a request got its response in time, and returns true when the connection
+// crossed the weak threshold.
+func (q *networkQuality) ackReceived(lost bool) bool {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	q.record(lost)
+	return q.updateWeak()
+}
+
+// reset forgets the unanswered ping when the connection is closed, it is not a loss.
+func (q *networkQuality) reset() {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	q.pingTime = 0
+	q.wireTime = 0
+}
+
+func (q *networkQuality) addRTT(rtt float64) {
+	if !q.hasRTT {
+		q.rtt, q.lastRTT, q.hasRTT = rtt, rtt, true
+		return
+	}
+	q.jitter += (math.Abs(rtt-q.lastRTT) - q.jitter) / 16
+	q.rtt += (rtt - q.rtt) / 8
+	q.lastRTT = rtt
+}
+
+func (q *networkQuality) record(lost bool) {
+	q.outcomes[q.outcomeNext] = lost
+	q.outcomeNext = (q.outcomeNext + 1) % lossWindow
+	if q.outcomeCount < lossWindow {
+		q.outcomeCount++
+	}
+}
+
+func (q *networkQuality) lossRate() float64 {
+	if q.outcomeCount == 0 {
+		return 0
+	}
+	var lost int
+	for i := 0; i < q.outcomeCount; i++ {
+		if q.outcomes[i] {
+			lost++
+		}
+	}
+	return float64(lost) / float64(q.outcomeCount)
+}
+
+func (q *networkQuality) updateWeak() bool {
+	weak := q.rtt > float64(q.degradedRTT.Milliseconds()) || q.lossRate() >= q.degradedLossRate
+	changed := weak != q.weak
+	q.weak = weak
+	return changed
+}
+
+func (q *networkQuality) isWeak() bool {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	return q.weak
+}
+
+func (q *networkQuality) snapshot() *sdk_struct.NetworkQuality {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	return &sdk_struct.NetworkQuality{
+		RTT:        int64(q.rtt),
+		Jitter:     int64(q.jitter),
+		LossRate:   q.lossRate(),
+		Throughput: int64(q.throughput),
+		Weak:       q.weak,
+	}
+}
@@ -0,0 +1,36 @@
+package interaction
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNetworkQuality(t *testing.T) {
+	q := newNetworkQuality()
+	q.setThresholds(time.Second, 0.3)
+
+	q.pingSent(1000)
+	if q.pongReceived(1100, 0) {
+		t.Fatal("a fast pong must not make the connection weak")
+	}
+	if quality := q.snapshot(); quality.RTT != 100 || quality.LossRate != 0 {
+		t.Fatalf("unexpected quality %+v", quality)
+	}
+
+	// the unanswered ping is lost once the next one is sent
+	q.pingSent(2000)
+	q.pingSent(3000)
+	if !q.pongReceived(3100, 0) {
+		t.Fatal("losing a third of the pings must cross the loss threshold")
+	}
+	if quality := q.snapshot(); !quality.Weak || quality.LossRate < 0.3 {
+		t.Fatalf("unexpected quality %+v", quality)
+	}
+
+	for i := 0; i < lossWindow; i++ {
+		q.ackReceived(false)
+	}
+	if q.isWeak() {
+		t.Fatal("the connection must recover once the lost pings leave the window")
+	}
+}
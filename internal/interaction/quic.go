@@ -0,0 +1,205 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build quic && !js
+
+package interaction
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/network"
+	"github.com/quic-go/quic-go"
+)
+
+// QuicSupported tells whether the QUIC transport is built, it is with the quic tag.
+const QuicSupported = true
+
+const (
+	quicALPN        = "openim"
+	quicDialTimeout = time.Second * 10
+	// quicHandshakeFrame carries the connection url from the client, and the handshake result from the server.
+	quicHandshakeFrame = 1
+)
+
+// quicSessionCache keeps the session tickets of the server so that reconnections send data in the first
+// round trip (0-RTT).
+var quicSessionCache = tls.NewLRUClientSessionCache(8)
+
+// Quic is a LongConn over a single bidirectional QUIC stream carrying frames. The connection survives the client address
+// changing (NAT rebinding, switching network paths) without a new handshake.
+type Quic struct {
+	ConnType    int
+	conn        quic.Connection
+	stream      quic.Stream
+	readLimit   int64
+	pingHandler PingPongHandler
+	pongHandler PingPongHandler
+	writeLock   sync.Mutex
+	// userCtx is of the user context whose certificate pins the handshake checks
+	userCtx context.Context
+}
+
+func newQuic() LongConn {
+	return &Quic{ConnType: QUIC, userCtx: context.Background()}
+}
+
+// SetUserContext makes the following handshakes check the pins of the user context of ctx.
+func (q *Quic) SetUserContext(ctx context.Context) {
+	q.userCtx = ctx
+}
+
+func (q *Quic) Dial(urlStr string, _ http.Header) (*http.Response, error) {
+	u, err := url.Parse(urlStr)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), quicDialTimeout)
+	defer cancel()
+	tlsConf := network.TLSConfig(q.userCtx, u.Hostname())
+	tlsConf.NextProtos = []string{quicALPN}
+	tlsConf.ClientSessionCache = quicSessionCache
+	conn, err := quic.DialAddrEarly(ctx, u.Host, tlsConf, &quic.Config{
+		KeepAlivePeriod: pingPeriod,
+		MaxIdleTimeout:  pongWait,
+	})
+	if err != nil {
+		return nil, err
+	}
+	stream, err := conn.OpenStreamSync(ctx)
+	if err != nil {
+		_ = conn.CloseWithError(0, "")
+		return nil, err
+	}
+	q.conn, q.stream = conn, stream
+	if err := q.writeFrame(quicHandshakeFrame, []byte(u.RequestURI())); err != nil {
+		_ = q.Close()
+		return nil, err
+	}
+	_ = stream.SetReadDeadline(time.Now().Add(quicDialTimeout))
+	typ, body, err := q.readFrame()
+	if err != nil {
+		_ = q.Close()
+		return nil, err
+	}
+	_ = stream.SetReadDeadline(time.Time{})
+	if typ != quicHandshakeFrame {
+		_ = q.Close()
+		return nil, ErrNotSupportMessageProtocol
+	}
+	var result struct {
+		ErrCode int `json:"errCode"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		_ = q.Close()
+		return nil, err
+	}
+	if result.ErrCode != 0 {
+		_ = q.Close()
+		// the same response the websocket handshake gets, so that token errors are handled alike
+		return &http.Response{StatusCode: http.StatusBadRequest, Body: io.NopCloser(bytes.NewReader(body))},
+			errors.New("quic handshake rejected")
+	}
+	return nil, nil
+}
+
+func (q *Quic) Close() error {
+	if q.conn == nil {
+		return nil
+	}
+	return q.conn.CloseWithError(0, "")
+}
+
+func (q *Quic) WriteMessage(messageType int, message []byte) error {
+	return q.writeFrame(byte(messageType), message)
+}
+
+func (q *Quic) ReadMessage() (int, []byte, error) {
+	for {
+		typ, data, err := q.readFrame()
+		if err != nil {
+			return 0, nil, err
+		}
+		switch typ {
+		case PingMessage:
+			if q.pingHandler != nil {
+				if err := q.pingHandler(string(data)); err != nil {
+					return 0, nil, err
+				}
+			}
+		case PongMessage:
+			if q.pongHandler != nil {
+				if err := q.pongHandler(string(data)); err != nil {
+					return 0, nil, err
+				}
+			}
+		default:
+			return int(typ), data, nil
+		}
+	}
+}
+
+func (q *Quic) writeFrame(typ byte, data []byte) error {
+	q.writeLock.Lock()
+	defer q.writeLock.Unlock()
+	n, err := q.stream.Write(encodeFrame(typ, data))
+	network.AddWsWireBytes(n)
+	return err
+}
+
+func (q *Quic) readFrame() (byte, []byte, error) {
+	typ, data, err := decodeFrame(q.stream, q.readLimit)
+	if err != nil {
+		return 0, nil, err
+	}
+	network.AddWsWireBytes(frameHeaderSize + len(data))
+	return typ, data, nil
+}
+
+func (q *Quic) SetReadDeadline(timeout time.Duration) error {
+	return q.stream.SetReadDeadline(time.Now().Add(timeout))
+}
+
+func (q *Quic) SetWriteDeadline(timeout time.Duration) error {
+	return q.stream.SetWriteDeadline(time.Now().Add(timeout))
+}
+
+func (q *Quic) IsNil() bool {
+	return q.stream == nil
+}
+
+func (q *Quic) SetReadLimit(limit int64) {
+	q.readLimit = limit
+}
+
+func (q *Quic) SetPingHandler(handler PingPongHandler) {
+	q.pingHandler = handler
+}
+
+func (q *Quic) SetPongHandler(handler PingPongHandler) {
+	q.pongHandler = handler
+}
+
+func (q *Quic) LocalAddr() string {
+	return q.conn.LocalAddr().String()
+}
@@ -1,11 +1,15 @@
 package interaction
 
 import (
+	"math/rand"
+	"sync"
 	"time"
 )
 
 type ReconnectStrategy interface {
 	GetSleepInterval() time.Duration
+	// Exhausted reports whether to stop reconnecting until a reconnection is forced
+	Exhausted() bool
 	Reset()
 }
 
@@ -27,6 +31,97 @@ func (rs *ExponentialRetry) GetSleepInterval() time.Duration {
 	return time.Second * time.Duration(rs.attempts[interval])
 }
 
+func (rs *ExponentialRetry) Exhausted() bool {
+	return false
+}
+
 func (rs *ExponentialRetry) Reset() {
 	rs.index = -1
 }
+
+const (
+	DefaultReconnectInitialDelay = time.Second
+	DefaultReconnectMultiplier   = 2
+	DefaultReconnectMaxDelay     = 16 * time.Second
+)
+
+// BackoffRetry waits initial before the first retry, then multiplier times longer each time up to max. Jitter
+// is the fraction of the delay randomly added or removed, so that clients do not reconnect all at once.
+// After maxAttempts failed attempts in a row the strategy is exhausted, 0 retries forever.
+type BackoffRetry struct {
+	lock        sync.Mutex
+	initial     time.Duration
+	multiplier  float64
+	max         time.Duration
+	jitter      float64
+	maxAttempts int
+	attempts    int
+	next        time.Duration
+}
+
+func NewBackoffRetry() *BackoffRetry {
+	b := &BackoffRetry{}
+	b.Set(0, 0, 0, 0, 0)
+	return b
+}
+
+// Set replaces the policy, zero values take the defaults of 1s, 2 and 16s without jitter.
+func (b *BackoffRetry) Set(initial time.Duration, multiplier float64, max time.Duration, jitter float64, maxAttempts int) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if initial <= 0 {
+		initial = DefaultReconnectInitialDelay
+	}
+	if multiplier < 1 {
+		multiplier = DefaultReconnectMultiplier
+	}
+	if max <= 0 {
+		max = DefaultReconnectMaxDelay
+	}
+	if max < initial {
+		max = initial
+	}
+	if jitter < 0 {
+		jitter = 0
+	} else if jitter > 1 {
+		jitter = 1
+	}
+	if maxAttempts < 0 {
+		maxAttempts = 0
+	}
+	b.initial, b.multiplier, b.max, b.jitter, b.maxAttempts = initial, multiplier, max, jitter, maxAttempts
+	b.reset()
+}
+
+func (b *BackoffRetry) GetSleepInterval() time.Duration {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.attempts++
+	interval := b.next
+	b.next = time.Duration(float64(b.next) * b.multiplier)
+	if b.next > b.max {
+		b.next = b.max
+	}
+	if b.jitter > 0 {
+		interval += time.Duration((rand.Float64()*2 - 1) * b.jitter * float64(interval))
+	}
+	return interval
+}
+
+// Exhausted reports whether the attempts since the last success reached the maximum.
+func (b *BackoffRetry) Exhausted() bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.maxAttempts > 0 && b.attempts >= b.maxAttempts
+}
+
+func (b *BackoffRetry) Reset() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.reset()
+}
+
+func (b *BackoffRetry) reset() {
+	b.attempts = 0
+	b.next = b.initial
+}
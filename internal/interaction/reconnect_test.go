@@ -0,0 +1,38 @@
+package interaction
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoffRetry(t *testing.T) {
+	b := NewBackoffRetry()
+	b.Set(time.Second, 3, 10*time.Second, 0, 4)
+	expect := []time.Duration{time.Second, 3 * time.Second, 9 * time.Second, 10 * time.Second}
+	for i, e := range expect {
+		if b.Exhausted() {
+			t.Fatalf("exhausted after %d attempts", i)
+		}
+		if interval := b.GetSleepInterval(); interval != e {
+			t.Fatalf("attempt %d waits %s, expect %s", i, interval, e)
+		}
+	}
+	if !b.Exhausted() {
+		t.Fatal("not exhausted after the max attempts")
+	}
+	b.Reset()
+	if b.Exhausted() || b.GetSleepInterval() != time.Second {
+		t.Fatal("reset did not restart from the initial delay")
+	}
+}
+
+func TestBackoffRetryJitter(t *testing.T) {
+	b := NewBackoffRetry()
+	b.Set(time.Second, 2, time.Minute, 0.5, 0)
+	for i := 0; i < 100; i++ {
+		b.Reset()
+		if interval := b.GetSleepInterval(); interval < time.Second/2 || interval > time.Second*3/2 {
+			t.Fatalf("interval %s out of the jitter range", interval)
+		}
+	}
+}
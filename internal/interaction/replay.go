@@ -0,0 +1,180 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interaction
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/common"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/recorder"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/protocol/sdkws"
+	"github.com/openimsdk/tools/errs"
+	protov2 "google.golang.org/protobuf/proto"
+)
+
+// recordedFrames are the frames the server pushes, the others are the responses recorded with their requests
+var recordedFrames = map[int]struct{}{
+	constant.PushMsg:               {},
+	constant.KickOnlineMsg:         {},
+	constant.WsSubUserOnlineStatus: {},
+}
+
+// replaySource answers the requests of a replay with the responses recorded for the same requests, in the
+// order they were recorded.
+type replaySource struct {
+	lock      sync.Mutex
+	responses map[replayKey][]*recorder.Event
+	// missed counts the requests not recorded
+	missed int
+}
+
+type replayKey struct {
+	reqIdentifier int
+	reqKey        string
+}
+
+// SetReplay has the long connection replay the events of a recording instead of connecting, set before Run.
+func (c *LongConnMgr) SetReplay(events []*recorder.Event) {
+	r := &replaySource{responses: make(map[replayKey][]*recorder.Event)}
+	for _, e := range events {
+		if e.Kind == recorder.KindExchange {
+			key := replayKey{reqIdentifier: e.ReqIdentifier, reqKey: e.ReqKey}
+			r.responses[key] = append(r.responses[key], e)
+		}
+	}
+	c.replay = r
+}
+
+// ReplayMissed returns the number of the requests of the replay that were not recorded.
+func (c *LongConnMgr) ReplayMissed() int {
+	if c.replay == nil {
+		return 0
+	}
+	c.replay.lock.Lock()
+	defer c.replay.lock.Unlock()
+	return c.replay.missed
+}
+
+func (r *replaySource) respond(ctx context.Context, reqIdentifier int, data []byte, resp proto.Message) error {
+	key := replayKey{reqIdentifier: reqIdentifier, reqKey: recorder.ReqKey(data)}
+	r.lock.Lock()
+	queue := r.responses[key]
+	var e *recorder.Event
+	if len(queue) > 0 {
+		e = queue[0]
+		// the last response answers the requests repeated more than recorded
+		if len(queue) > 1 {
+			r.responses[key] = queue[1:]
+		}
+	} else {
+		r.missed++
+	}
+	r.lock.Unlock()
+	if e == nil {
+		log.ZWarn(ctx, "replay request not recorded", nil, "reqIdentifier", reqIdentifier, "reqKey", key.reqKey)
+		return sdkerrs.ErrNetwork.WrapMsg("request not recorded")
+	}
+	recorder.RecordExchange(reqIdentifier, data, e.OperationID, e.ErrCode, e.ErrMsg, e.Data)
+	if e.ErrCode != 0 {
+		return errs.NewCodeError(e.ErrCode, e.ErrMsg)
+	}
+	if err := proto.Unmarshal(e.Data, resp); err != nil {
+		return sdkerrs.ErrArgs
+	}
+	return nil
+}
+
+// Replay feeds the connections and the frames of a recording through the pipeline, waiting between them as
+// recorded divided by speed, without waiting when speed is 0. The messages pushed are handed to the sync one
+// frame at a time, so that a replay dispatches the same batches each time.
+func (c *LongConnMgr) Replay(ctx context.Context, events []*recorder.Event, speed float64) error {
+	if c.replay == nil {
+		return sdkerrs.ErrArgs.WrapMsg("not in replay mode")
+	}
+	var last int64
+	for _, e := range events {
+		if speed > 0 && e.Time > last {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Duration(float64(e.Time-last)/speed) * time.Millisecond):
+			}
+		}
+		last = e.Time
+		switch e.Kind {
+		case recorder.KindConnected:
+			recorder.Record(&recorder.Event{Kind: recorder.KindConnected})
+			if err := common.DispatchConnected(ctx, c.pushMsgAndMaxSeqCh); err != nil {
+				return err
+			}
+		case recorder.KindFrame:
+			c.replayFrame(ctx, e)
+		}
+	}
+	return nil
+}
+
+func (c *LongConnMgr) replayFrame(ctx context.Context, e *recorder.Event) {
+	wsResp := GeneralWsResp{ReqIdentifier: e.ReqIdentifier, ErrCode: e.ErrCode, ErrMsg: e.ErrMsg,
+		OperationID: e.OperationID, Data: e.Data}
+	recordFrame(&wsResp)
+	switch e.ReqIdentifier {
+	case constant.PushMsg:
+		if err := c.doPushMsg(ctx, wsResp); err != nil {
+			log.ZError(ctx, "replay push msg failed", err, "operationID", e.OperationID)
+			return
+		}
+		c.mb.Flush()
+	case constant.WsSubUserOnlineStatus:
+		if err := c.handlerUserOnlineChange(ctx, wsResp); err != nil {
+			log.ZError(ctx, "replay user online change failed", err)
+		}
+	default:
+		// a kick would log out in the middle of the replay
+		log.ZInfo(ctx, "replay frame skipped", "reqIdentifier", e.ReqIdentifier)
+	}
+}
+
+// recordFrame records a frame pushed by the server.
+func recordFrame(wsResp *GeneralWsResp) {
+	if !recorder.Enabled() {
+		return
+	}
+	if _, ok := recordedFrames[wsResp.ReqIdentifier]; !ok {
+		return
+	}
+	recorder.Record(&recorder.Event{Kind: recorder.KindFrame, ReqIdentifier: wsResp.ReqIdentifier,
+		OperationID: wsResp.OperationID, ErrCode: wsResp.ErrCode, ErrMsg: wsResp.ErrMsg, Data: wsResp.Data})
+}
+
+// recordDispatch records the messages the sync dispatches to the conversations with the command.
+func recordDispatch(ctx context.Context, cmd string, msgs map[string]*sdkws.PullMsgs) {
+	if !recorder.Enabled() {
+		return
+	}
+	// deterministic, the dispatches of a replay are compared byte to byte
+	data, err := protov2.MarshalOptions{Deterministic: true}.Marshal(&sdkws.PushMessages{Msgs: msgs})
+	if err != nil {
+		log.ZWarn(ctx, "record dispatch failed", err, "cmd", cmd)
+		return
+	}
+	recorder.Record(&recorder.Event{Kind: recorder.KindDispatch, Cmd: cmd, Data: data})
+}
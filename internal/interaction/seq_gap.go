@@ -0,0 +1,178 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interaction
+
+import (
+	"context"
+	"sync"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/network"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+	"github.com/openimsdk/protocol/sdkws"
+)
+
+const (
+	// gapRepairMaxSeqs bounds the messages pulled again for a gap, the older ones of a larger gap are pulled
+	// when the conversation is opened
+	gapRepairMaxSeqs = 200
+	gapRepairTimes   = 3
+)
+
+// seqGaps are the seq ranges missing in the conversations, found when the seq of a push does not follow the
+// synced one or when a pull does not return the whole range.
+type seqGaps struct {
+	lock     sync.Mutex
+	detected int64
+	repaired int64
+	pending  map[string]*sdk_struct.SeqGap
+}
+
+func (g *seqGaps) detect(ctx context.Context, conversationID string, begin, end int64) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	log.ZWarn(ctx, "seq gap detected", nil, "conversationID", conversationID, "begin", begin, "end", end)
+	g.detected++
+	if g.pending == nil {
+		g.pending = make(map[string]*sdk_struct.SeqGap)
+	}
+	if gap, ok := g.pending[conversationID]; ok {
+		gap.BeginSeq, gap.EndSeq = min(gap.BeginSeq, begin), max(gap.EndSeq, end)
+		gap.RepairTimes = 0
+		return
+	}
+	g.pending[conversationID] = &sdk_struct.SeqGap{ConversationID: conversationID, BeginSeq: begin, EndSeq: end}
+}
+
+// toRepair returns the pending gaps to pull again, the ones pulled too many times are left to the check of
+// the messages when the conversation is opened.
+func (g *seqGaps) toRepair() map[string][2]int64 {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	seqMap := make(map[string][2]int64)
+	for conversationID, gap := range g.pending {
+		if gap.RepairTimes < gapRepairTimes {
+			seqMap[conversationID] = [2]int64{gap.BeginSeq, gap.EndSeq}
+		}
+	}
+	return seqMap
+}
+
+// verify checks the pulled messages of the range, the gap is repaired when none of its seqs is missing.
+// With keepOlder the part of the gap older than the range stays pending, else it is left to the check of
+// the messages when the conversation is opened.
+func (g *seqGaps) verify(ctx context.Context, conversationID string, begin, end int64, pulled *sdkws.PullMsgs, keepOlder bool) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	gap, ok := g.pending[conversationID]
+	if !ok {
+		return
+	}
+	older := keepOlder && gap.BeginSeq < begin
+	if !older {
+		gap.RepairTimes++
+	}
+	begin, end = max(gap.BeginSeq, begin), min(gap.EndSeq, end)
+	seqs := make(map[int64]struct{})
+	if pulled != nil {
+		for _, msg := range pulled.Msgs {
+			seqs[msg.Seq] = struct{}{}
+		}
+	}
+	missingBegin, missingEnd := int64(0), int64(0)
+	for seq := begin; seq <= end; seq++ {
+		if _, ok := seqs[seq]; ok {
+			continue
+		}
+		if missingBegin == 0 {
+			missingBegin = seq
+		}
+		missingEnd = seq
+	}
+	if older {
+		if missingBegin == 0 {
+			missingEnd = begin - 1
+		}
+		log.ZDebug(ctx, "seq gap backfill deferred", "conversationID", conversationID, "begin", gap.BeginSeq, "end", missingEnd)
+		gap.EndSeq = missingEnd
+		return
+	}
+	if missingBegin == 0 {
+		log.ZInfo(ctx, "seq gap repaired", "conversationID", conversationID, "begin", gap.BeginSeq, "end", gap.EndSeq)
+		delete(g.pending, conversationID)
+		g.repaired++
+		return
+	}
+	log.ZWarn(ctx, "seq gap not repaired, pull again later", nil, "conversationID", conversationID,
+		"begin", missingBegin, "end", missingEnd, "repairTimes", gap.RepairTimes)
+	gap.BeginSeq, gap.EndSeq = missingBegin, missingEnd
+}
+
+func (g *seqGaps) stats() *sdk_struct.SeqGapStats {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	stats := &sdk_struct.SeqGapStats{Detected: g.detected, Repaired: g.repaired, Pending: make([]*sdk_struct.SeqGap, 0, len(g.pending))}
+	for _, gap := range g.pending {
+		pending := *gap
+		stats.Pending = append(stats.Pending, &pending)
+	}
+	return stats
+}
+
+// repairSeqGaps pulls the whole seq ranges again, up to gapRepairMaxSeqs messages each, and checks the gaps in
+// them came back, what is still missing is pulled again after the next sync.
+func (m *MsgSyncer) repairSeqGaps(ctx context.Context, seqMap map[string][2]int64) {
+	if len(seqMap) == 0 {
+		return
+	}
+	pullNums := int64(gapRepairMaxSeqs)
+	// on a metered network only the latest messages, the older ones of the gaps wait for the backfill
+	deferred := network.Deferred(constant.MeteredBackfill)
+	if deferred {
+		pullNums = defaultPullNums
+	}
+	resp, err := m.pullMsgBySeqRange(ctx, seqMap, pullNums)
+	if err != nil {
+		log.ZWarn(ctx, "pull seq gaps failed, pull again after the next sync", err, "seqMap", seqMap)
+		return
+	}
+	_ = m.triggerConversation(ctx, resp.Msgs)
+	_ = m.triggerNotification(ctx, resp.NotificationMsgs)
+	for conversationID, seqs := range seqMap {
+		pulled := resp.Msgs[conversationID]
+		if IsNotification(conversationID) {
+			pulled = resp.NotificationMsgs[conversationID]
+		}
+		m.seqGaps.verify(ctx, conversationID, max(seqs[0], seqs[1]-pullNums+1), seqs[1], pulled, deferred)
+		if seqs[1] > m.syncedMaxSeqs[conversationID] {
+			m.setSyncedMaxSeq(conversationID, seqs[1])
+		}
+	}
+}
+
+// repairPendingGaps pulls again the gaps a previous repair did not get back, once the backfill is allowed.
+func (m *MsgSyncer) repairPendingGaps(ctx context.Context) {
+	if network.Deferred(constant.MeteredBackfill) {
+		return
+	}
+	m.repairSeqGaps(ctx, m.seqGaps.toRepair())
+}
+
+// GetSeqGapStats returns the gaps detected in the seqs of the conversations since login and how many of them
+// were repaired.
+func (m *MsgSyncer) GetSeqGapStats(_ context.Context) (*sdk_struct.SeqGapStats, error) {
+	return m.seqGaps.stats(), nil
+}
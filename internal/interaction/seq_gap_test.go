@@ -0,0 +1,57 @@
+package interaction
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openimsdk/protocol/sdkws"
+)
+
+func pulledSeqs(seqs ...int64) *sdkws.PullMsgs {
+	pulled := &sdkws.PullMsgs{}
+	for _, seq := range seqs {
+		pulled.Msgs = append(pulled.Msgs, &sdkws.MsgData{Seq: seq})
+	}
+	return pulled
+}
+
+func TestSeqGaps(t *testing.T) {
+	ctx := context.Background()
+	var g seqGaps
+
+	g.detect(ctx, "si_1_2", 5, 8)
+	if seqMap := g.toRepair(); seqMap["si_1_2"] != [2]int64{5, 8} {
+		t.Fatalf("unexpected gaps to repair %v", seqMap)
+	}
+
+	// seq 7 did not come back, only it is pulled again
+	g.verify(ctx, "si_1_2", 5, 8, pulledSeqs(5, 6, 8), false)
+	if seqMap := g.toRepair(); seqMap["si_1_2"] != [2]int64{7, 7} {
+		t.Fatalf("unexpected gaps to repair %v", seqMap)
+	}
+
+	g.verify(ctx, "si_1_2", 7, 7, pulledSeqs(7), false)
+	if stats := g.stats(); stats.Detected != 1 || stats.Repaired != 1 || len(stats.Pending) != 0 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+
+	// with the backfill deferred only the older part stays pending, not counted as a repair
+	g.detect(ctx, "si_1_4", 1, 30)
+	g.verify(ctx, "si_1_4", 21, 30, pulledSeqs(21, 22, 23, 24, 25, 26, 27, 28, 29, 30), true)
+	if stats := g.stats(); len(stats.Pending) != 1 || stats.Pending[0].EndSeq != 20 || stats.Pending[0].RepairTimes != 0 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+	g.verify(ctx, "si_1_4", 1, 20, pulledSeqs(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20), false)
+
+	// a gap pulled too many times is given up
+	g.detect(ctx, "si_1_3", 1, 1)
+	for i := 0; i < gapRepairTimes; i++ {
+		g.verify(ctx, "si_1_3", 1, 1, nil, false)
+	}
+	if seqMap := g.toRepair(); len(seqMap) != 0 {
+		t.Fatalf("unexpected gaps to repair %v", seqMap)
+	}
+	if stats := g.stats(); len(stats.Pending) != 1 || stats.Pending[0].RepairTimes != gapRepairTimes {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+}
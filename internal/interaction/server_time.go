@@ -0,0 +1,78 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interaction
+
+import (
+	"context"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/api"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/protocol/msg"
+)
+
+const (
+	// serverTimeSyncInterval is how often the server clock is sampled again, besides after each connection
+	serverTimeSyncInterval = 10 * time.Minute
+	serverTimeSamples      = 3
+)
+
+// syncServerTime samples the server clock a few times and keeps the offset of the sample with the shortest
+// round trip, the error of an offset is at most half of its round trip.
+func syncServerTime(ctx context.Context) error {
+	var (
+		bestRTT int64 = -1
+		offset  int64
+	)
+	for i := 0; i < serverTimeSamples; i++ {
+		start := time.Now()
+		resp, err := api.GetServerTime.Invoke(ctx, &msg.GetServerTimeReq{})
+		if err != nil {
+			if bestRTT < 0 {
+				return err
+			}
+			break
+		}
+		end := time.Now()
+		rtt := end.Sub(start).Milliseconds()
+		if bestRTT >= 0 && rtt >= bestRTT {
+			continue
+		}
+		bestRTT = rtt
+		offset = resp.ServerTime + rtt/2 - end.UnixMilli()
+	}
+	utils.SetServerTimeOffset(offset)
+	log.ZDebug(ctx, "server time synced", "offset", offset, "rtt", bestRTT)
+	return nil
+}
+
+// serverTimeLoop keeps the server clock offset up to date, it samples after each connection and then
+// periodically.
+func (c *LongConnMgr) serverTimeLoop(ctx context.Context) {
+	ticker := time.NewTicker(serverTimeSyncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-c.serverTimeSync:
+		case <-ticker.C:
+		}
+		if err := syncServerTime(ctx); err != nil {
+			log.ZWarn(ctx, "sync server time failed", err)
+		}
+	}
+}
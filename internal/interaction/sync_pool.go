@@ -0,0 +1,99 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interaction
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/protocol/sdkws"
+)
+
+// DefaultSyncWorkers is the number of message batches pulled at the same time when the config does not set one.
+const DefaultSyncWorkers = 4
+
+// CheckSyncWorkers checks the number of workers of the message backfill set in the config, 0 keeps the default.
+func CheckSyncWorkers(workers int) error {
+	if workers < 0 {
+		return sdkerrs.ErrArgs.WrapMsg(fmt.Sprintf("invalid msg sync workers %d", workers))
+	}
+	return nil
+}
+
+// SetSyncWorkers sets the number of message batches pulled at the same time, 0 keeps the default.
+func (m *MsgSyncer) SetSyncWorkers(workers int) {
+	if workers <= 0 {
+		workers = DefaultSyncWorkers
+	}
+	m.syncWorkers = workers
+}
+
+func (m *MsgSyncer) workers() int {
+	if m.syncWorkers <= 0 {
+		return DefaultSyncWorkers
+	}
+	return m.syncWorkers
+}
+
+// syncBatch is a batch of conversations pulled by one request of a backfill.
+type syncBatch struct {
+	seqMap map[string][2]int64
+	resp   *sdkws.PullMessageBySeqsResp
+	err    error
+	done   chan struct{}
+}
+
+// backfill pulls the batches with at most the configured number of pulls at the same time. The pulled batches
+// are handed to trigger one at a time in the order of the batches, so the messages of a conversation keep
+// their order and the priority order of the conversations is kept. A batch is only pulled when a worker is
+// free, the pulls wait while trigger falls behind so that the memory held stays bounded. The synced seqs of a
+// batch move forward once it is triggered, the first failed pull stops the backfill.
+func (m *MsgSyncer) backfill(ctx context.Context, batches []map[string][2]int64, syncMsgNum int64,
+	trigger func(resp *sdkws.PullMessageBySeqsResp)) error {
+	if len(batches) == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	// the batch being triggered and the ones queued are the pulls running at most
+	pending := make(chan *syncBatch, m.workers()-1)
+	go func() {
+		defer close(pending)
+		for _, seqMap := range batches {
+			batch := &syncBatch{seqMap: seqMap, done: make(chan struct{})}
+			select {
+			case pending <- batch:
+			case <-ctx.Done():
+				return
+			}
+			go func() {
+				defer close(batch.done)
+				batch.resp, batch.err = m.pullMsgBySeqRange(ctx, batch.seqMap, syncMsgNum)
+			}()
+		}
+	}()
+	for batch := range pending {
+		<-batch.done
+		if batch.err != nil {
+			return batch.err
+		}
+		trigger(batch.resp)
+		for conversationID, seqs := range batch.seqMap {
+			m.setSyncedMaxSeq(conversationID, seqs[1])
+		}
+	}
+	return nil
+}
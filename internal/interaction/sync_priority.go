@@ -0,0 +1,71 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interaction
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/tools/utils/datautil"
+)
+
+const (
+	priorityPinned = iota
+	priorityRecent
+	priorityNotStored
+	priorityNotification
+)
+
+// prioritySyncOrder orders the conversations to pull the messages of, so that the chats at the top of the
+// conversation list are filled first: the pinned ones, then the ones with the latest messages. The
+// conversations not stored locally yet follow, those with the most messages first, and the notifications last.
+func (m *MsgSyncer) prioritySyncOrder(ctx context.Context, seqMap map[string][2]int64) []string {
+	order := datautil.Keys(seqMap)
+	if len(order) < 2 {
+		return order
+	}
+	conversations, err := m.db.GetAllConversations(ctx)
+	if err != nil {
+		log.ZWarn(ctx, "get conversations to order the sync failed", err)
+	}
+	local := datautil.SliceToMap(conversations, func(c *model_struct.LocalConversation) string {
+		return c.ConversationID
+	})
+	rank := func(conversationID string) (int, int64) {
+		if IsNotification(conversationID) {
+			return priorityNotification, 0
+		}
+		conversation, ok := local[conversationID]
+		if !ok {
+			return priorityNotStored, seqMap[conversationID][1]
+		}
+		if conversation.IsPinned {
+			return priorityPinned, conversation.LatestMsgSendTime
+		}
+		return priorityRecent, conversation.LatestMsgSendTime
+	}
+	slices.SortStableFunc(order, func(a, b string) int {
+		aPriority, aKey := rank(a)
+		bPriority, bKey := rank(b)
+		if aPriority != bPriority {
+			return cmp.Compare(aPriority, bPriority)
+		}
+		return cmp.Compare(bKey, aKey)
+	})
+	return order
+}
@@ -0,0 +1,97 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interaction
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// websocket failing to connect this many times in a row without an answer from the server falls back
+	longPollingFallbackFailures = 3
+	// how long long polling is used before websocket is tried again
+	webSocketProbeInterval = 5 * time.Minute
+)
+
+// transportFallback decides when the long connection falls back from websocket to long polling and back.
+type transportFallback struct {
+	lock       sync.Mutex
+	wsFailures int
+	active     bool
+	since      time.Time
+	// the server answered it has no long polling, it is not tried again
+	unsupported bool
+}
+
+func (f *transportFallback) useLongPolling(enabled bool) bool {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if !enabled || f.unsupported {
+		f.active = false
+		return false
+	}
+	if !f.active && f.wsFailures >= longPollingFallbackFailures {
+		f.active = true
+		f.since = time.Now()
+	}
+	return f.active
+}
+
+// dialed counts the websocket dials blocked before reaching the server, a rejected handshake has an
+// http response and is not counted. Long polling failing to connect goes back to websocket, for good when the
+// server does not support it.
+func (f *transportFallback) dialed(connType int, resp *http.Response, err error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	switch connType {
+	case WebSocket:
+		if err != nil && resp == nil {
+			f.wsFailures++
+		} else {
+			f.wsFailures = 0
+		}
+	case LongPollingConn:
+		if errors.Is(err, ErrLongPollingUnsupported) {
+			f.unsupported = true
+		}
+		if err != nil && f.active {
+			f.active = false
+			f.wsFailures = 0
+		}
+	}
+}
+
+// probeWebSocket returns true when long polling has been used long enough that websocket should be tried
+// again, websocket failing once more falls back immediately.
+func (f *transportFallback) probeWebSocket() bool {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if !f.active || time.Since(f.since) < webSocketProbeInterval {
+		return false
+	}
+	f.active = false
+	f.wsFailures = longPollingFallbackFailures - 1
+	return true
+}
+
+// longPollingSupported is false once the server answered it has no long polling endpoints.
+func (f *transportFallback) longPollingSupported() bool {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return !f.unsupported
+}
@@ -0,0 +1,34 @@
+package interaction
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// a server without the long polling endpoints keeps the connection on websocket
+func TestLongPollingUnsupported(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+	conn := NewLongPolling(LongPollingConn)
+	defer conn.Close()
+	resp, err := conn.Dial("ws"+server.URL[len("http"):]+"?sendID=a", nil)
+	if !errors.Is(err, ErrLongPollingUnsupported) {
+		t.Fatal(err)
+	}
+	var f transportFallback
+	for i := 0; i < longPollingFallbackFailures; i++ {
+		f.dialed(WebSocket, nil, errors.New("blocked"))
+	}
+	if !f.useLongPolling(true) {
+		t.Fatal("websocket blocked does not fall back")
+	}
+	f.dialed(LongPollingConn, resp, err)
+	for i := 0; i < longPollingFallbackFailures; i++ {
+		f.dialed(WebSocket, nil, errors.New("blocked"))
+	}
+	if f.useLongPolling(true) || f.longPollingSupported() {
+		t.Fatal("falls back to the long polling the server does not support")
+	}
+}
@@ -17,16 +17,44 @@
 package interaction
 
 import (
+	"context"
+	"crypto/tls"
+	"net"
 	"net/http"
+	"net/http/httptrace"
+	"strings"
 	"time"
 
 	"github.com/gorilla/websocket"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/network"
 )
 
 type Default struct {
-	ConnType  int
-	conn      *websocket.Conn
-	isSetConf bool
+	ConnType          int
+	conn              *websocket.Conn
+	isSetConf         bool
+	enableCompression bool
+	dialStateHandler  func(state string)
+	// userCtx is of the user context whose network settings the handshake takes
+	userCtx context.Context
+}
+
+// countingConn counts the bytes on the socket, after the websocket compression and the tls encryption.
+type countingConn struct {
+	net.Conn
+}
+
+func (c *countingConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	network.AddWsWireBytes(n)
+	return n, err
+}
+
+func (c *countingConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	network.AddWsWireBytes(n)
+	return n, err
 }
 
 func (d *Default) SetReadDeadline(timeout time.Duration) error {
@@ -55,7 +83,7 @@ func (d *Default) LocalAddr() string {
 }
 
 func NewWebSocket(connType int) *Default {
-	return &Default{ConnType: connType}
+	return &Default{ConnType: connType, userCtx: context.Background()}
 }
 func (d *Default) Close() error {
 	return d.conn.Close()
@@ -70,7 +98,26 @@ func (d *Default) ReadMessage() (int, []byte, error) {
 }
 
 func (d *Default) Dial(urlStr string, requestHeader http.Header) (*http.Response, error) {
-	conn, httpResp, err := websocket.DefaultDialer.Dial(urlStr, requestHeader)
+	dialer := *websocket.DefaultDialer
+	dialer.Proxy = network.WsProxy(d.userCtx)
+	dialer.TLSClientConfig = network.TLSConfig(d.userCtx, "")
+	dialer.EnableCompression = d.enableCompression
+	dialer.NetDial = d.netDial
+	secure := strings.HasPrefix(urlStr, "wss")
+	trace := &httptrace.ClientTrace{
+		GotConn: func(httptrace.GotConnInfo) {
+			if !secure {
+				d.reportDialState(constant.ConnStateAuthenticating)
+			}
+		},
+		TLSHandshakeStart: func() {
+			d.reportDialState(constant.ConnStateTLSHandshake)
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			d.reportDialState(constant.ConnStateAuthenticating)
+		},
+	}
+	conn, httpResp, err := dialer.DialContext(httptrace.WithClientTrace(context.Background(), trace), urlStr, requestHeader)
 	if err == nil {
 		d.conn = conn
 	}
@@ -78,6 +125,52 @@ func (d *Default) Dial(urlStr string, requestHeader http.Header) (*http.Response
 
 }
 
+// netDial resolves the address itself so that the resolving is reported, and counts and throttles the socket bytes.
+func (d *Default) netDial(proto, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	hosts := []string{host}
+	if net.ParseIP(host) == nil {
+		d.reportDialState(constant.ConnStateDNSResolving)
+		hosts, err = net.DefaultResolver.LookupHost(context.Background(), host)
+		if err != nil {
+			return nil, err
+		}
+	}
+	dialer := &net.Dialer{Timeout: writeWait}
+	for _, h := range hosts {
+		var conn net.Conn
+		conn, err = dialer.Dial(proto, net.JoinHostPort(h, port))
+		if err == nil {
+			return network.ThrottleConn(d.userCtx, &countingConn{Conn: conn}, constant.BandwidthSync), nil
+		}
+	}
+	return nil, err
+}
+
+// SetUserContext makes the following handshakes go through the proxy, check the pins and take the bandwidth
+// of the user context of ctx.
+func (d *Default) SetUserContext(ctx context.Context) {
+	d.userCtx = ctx
+}
+
+func (d *Default) SetDialStateHandler(handler func(state string)) {
+	d.dialStateHandler = handler
+}
+
+func (d *Default) reportDialState(state string) {
+	if d.dialStateHandler != nil {
+		d.dialStateHandler(state)
+	}
+}
+
+// EnableCompression negotiates the permessage-deflate extension in the following handshakes.
+func (d *Default) EnableCompression(enable bool) {
+	d.enableCompression = enable
+}
+
 func (d *Default) IsNil() bool {
 	if d.conn != nil {
 		return false
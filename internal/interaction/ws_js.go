@@ -28,8 +28,8 @@ import (
 	"time"
 
 	"github.com/coder/websocket"
-
-	"github.com/openimsdk/tools/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/network"
 )
 
 const (
@@ -147,7 +147,9 @@ func (w *JSWebSocket) Read() (websocket.MessageType, []byte, error) {
 		ctx, cancel = context.WithDeadline(ctx, deadline)
 		defer cancel()
 	}
-	return w.conn.Read(ctx)
+	typ, b, err := w.conn.Read(ctx)
+	network.AddWsWireBytes(len(b))
+	return typ, b, err
 }
 
 func (w *JSWebSocket) Write(typ websocket.MessageType, p []byte) error {
@@ -157,6 +159,7 @@ func (w *JSWebSocket) Write(typ websocket.MessageType, p []byte) error {
 		ctx, cancel = context.WithDeadline(ctx, deadline)
 		defer cancel()
 	}
+	network.AddWsWireBytes(len(p))
 	return w.conn.Write(ctx, typ, p)
 }
 
@@ -20,10 +20,10 @@ import (
 	"sync"
 	"time"
 
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
 	"github.com/openimsdk/tools/errs"
-
-	"github.com/openimsdk/tools/log"
 )
 
 type GeneralWsResp struct {
@@ -112,7 +112,7 @@ func (u *WsRespAsyn) notifyCh(ch chan *GeneralWsResp, value *GeneralWsResp, time
 	if flag == 1 {
 		return nil
 	} else {
-		return errors.New("send cmd timeout")
+		return sdkerrs.ErrNetworkTimeOut.WrapMsg("send cmd timeout")
 	}
 }
 
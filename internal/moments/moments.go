@@ -0,0 +1,241 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package moments is the feed of the moments of the friends: the posts are fetched from the server page by
+// page with their likes and comments and cached in the local database, which serves the feed while the
+// server can't be reached. The moment notifications keep the cache up to date.
+package moments
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/openimsdk/openim-sdk-core/v3/internal/third/file"
+	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/api"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/db_interface"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/page"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdk_params_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/server_api_params"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+)
+
+const (
+	// maxMomentMedias is the images and videos a moment has at most
+	maxMomentMedias = 9
+	// maxMomentFeedCount is the moments a page of the feed has at most
+	maxMomentFeedCount = 100
+)
+
+type Moments struct {
+	loginUserID string
+	db          db_interface.DataBase
+	file        *file.File
+	listener    func() open_im_sdk_callback.OnMomentsListener
+}
+
+func NewMoments(file *file.File) *Moments {
+	return &Moments{file: file}
+}
+
+func (m *Moments) SetLoginUserID(loginUserID string) {
+	m.loginUserID = loginUserID
+}
+
+func (m *Moments) SetDataBase(db db_interface.DataBase) {
+	m.db = db
+}
+
+func (m *Moments) SetListener(listener func() open_im_sdk_callback.OnMomentsListener) {
+	m.listener = listener
+}
+
+// PublishMoment uploads the local files of the media then publishes the moment, which is returned as
+// published and cached.
+func (m *Moments) PublishMoment(ctx context.Context, params *sdk_params_callback.PublishMomentParams) (*model_struct.LocalMoment, error) {
+	if params.Content == "" && len(params.Medias) == 0 {
+		return nil, sdkerrs.ErrArgs.WrapMsg("a moment has a content or medias")
+	}
+	if len(params.Medias) > maxMomentMedias {
+		return nil, sdkerrs.ErrArgs.WrapMsg("a moment has 9 medias at most", "medias", len(params.Medias))
+	}
+	switch params.Visibility {
+	case constant.MomentVisibleFriends, constant.MomentVisiblePrivate:
+	case constant.MomentVisibleUsers:
+		if len(params.VisibleUserIDs) == 0 {
+			return nil, sdkerrs.ErrArgs.WrapMsg("visibleUserIDs can't be empty for a moment seen by some users")
+		}
+	default:
+		return nil, sdkerrs.ErrArgs.WrapMsg("unknown visibility", "visibility", params.Visibility)
+	}
+	req := &server_api_params.PublishMomentReq{
+		Content:        params.Content,
+		Visibility:     params.Visibility,
+		VisibleUserIDs: params.VisibleUserIDs,
+		Ex:             params.Ex,
+	}
+	for _, media := range params.Medias {
+		if media.Type != constant.MomentMediaImage && media.Type != constant.MomentMediaVideo {
+			return nil, sdkerrs.ErrArgs.WrapMsg("the media of a moment is an image or a video", "type", media.Type)
+		}
+		published := media.MomentMedia
+		if media.FilePath != "" {
+			url, err := m.upload(ctx, media.FilePath, "moment-"+media.Type)
+			if err != nil {
+				return nil, err
+			}
+			published.URL = url
+		}
+		if media.ThumbnailPath != "" {
+			url, err := m.upload(ctx, media.ThumbnailPath, "moment-thumbnail")
+			if err != nil {
+				return nil, err
+			}
+			published.ThumbnailURL = url
+		}
+		if published.URL == "" {
+			return nil, sdkerrs.ErrArgs.WrapMsg("a media has a filePath or a url")
+		}
+		req.Medias = append(req.Medias, &published)
+	}
+	resp, err := api.PublishMoment.Invoke(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	m.cache(ctx, resp.Moment)
+	return resp.Moment, nil
+}
+
+func (m *Moments) upload(ctx context.Context, path, cause string) (string, error) {
+	res, err := m.file.UploadFile(ctx, &file.UploadFileReq{
+		Filepath: path,
+		Name:     "moment/" + utils.GetMsgID(m.loginUserID) + filepath.Ext(path),
+		Cause:    cause,
+	}, nil)
+	if err != nil {
+		return "", err
+	}
+	return res.URL, nil
+}
+
+// DeleteMoment deletes a moment of the login user.
+func (m *Moments) DeleteMoment(ctx context.Context, momentID string) error {
+	if err := api.DeleteMoment.Execute(ctx, &server_api_params.DeleteMomentReq{MomentID: momentID}); err != nil {
+		return err
+	}
+	return m.db.DeleteMoment(ctx, momentID)
+}
+
+// GetMomentFeed gets the page of the feed following the cursor, the first page for the empty cursor. The feed
+// is the moments of the friends and of the login user, or the moments of the user when userID is set. The
+// page comes from the local cache when the server can't be reached.
+func (m *Moments) GetMomentFeed(ctx context.Context, userID, cursor string, count int) (*sdk_params_callback.GetMomentsCallback, error) {
+	if count <= 0 || count > maxMomentFeedCount {
+		return nil, sdkerrs.ErrArgs.WrapMsg("count is from 1 to 100", "count", count)
+	}
+	after, err := page.DecodeCursor(page.CursorMoments, cursor)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		createTime int64
+		momentID   string
+	)
+	if after != nil {
+		createTime, momentID = after.Time, after.ID
+	}
+	res := &sdk_params_callback.GetMomentsCallback{}
+	resp, err := api.GetMomentFeed.Invoke(ctx, &server_api_params.GetMomentFeedReq{
+		UserID:     userID,
+		CreateTime: createTime,
+		MomentID:   momentID,
+		Count:      int32(count + 1),
+	})
+	switch {
+	case err == nil:
+		res.Moments = resp.Moments
+		if err := m.db.SetMoments(ctx, res.Moments); err != nil {
+			log.ZWarn(ctx, "cache moments failed", err, "count", len(res.Moments))
+		}
+	case sdkerrs.Category(int(sdkerrs.Code(err))) == sdkerrs.CategoryNetwork:
+		log.ZWarn(ctx, "get moment feed failed, the cached moments are returned", err, "userID", userID)
+		if res.Moments, err = m.db.GetMomentsAfter(ctx, userID, createTime, momentID, count+1); err != nil {
+			return nil, err
+		}
+		res.Cached = true
+	default:
+		return nil, err
+	}
+	if res.HasMore = len(res.Moments) > count; res.HasMore {
+		res.Moments = res.Moments[:count]
+	}
+	if n := len(res.Moments); n > 0 {
+		last := res.Moments[n-1]
+		res.NextCursor = (&page.Cursor{List: page.CursorMoments, ID: last.MomentID, Time: last.CreateTime}).Encode()
+	} else {
+		res.NextCursor = cursor
+	}
+	return res, nil
+}
+
+// LikeMoment likes the moment, or takes the like back.
+func (m *Moments) LikeMoment(ctx context.Context, momentID string, like bool) (*model_struct.LocalMoment, error) {
+	resp, err := api.LikeMoment.Invoke(ctx, &server_api_params.LikeMomentReq{MomentID: momentID, Like: like})
+	if err != nil {
+		return nil, err
+	}
+	m.cache(ctx, resp.Moment)
+	return resp.Moment, nil
+}
+
+// CommentMoment comments the moment, or replies to the comment of the user.
+func (m *Moments) CommentMoment(ctx context.Context, momentID, replyToUserID, content string) (*model_struct.LocalMoment, error) {
+	if content == "" {
+		return nil, sdkerrs.ErrArgs.WrapMsg("content can't be empty")
+	}
+	resp, err := api.CommentMoment.Invoke(ctx, &server_api_params.CommentMomentReq{
+		MomentID:      momentID,
+		ReplyToUserID: replyToUserID,
+		Content:       content,
+	})
+	if err != nil {
+		return nil, err
+	}
+	m.cache(ctx, resp.Moment)
+	return resp.Moment, nil
+}
+
+// DeleteMomentComment deletes a comment of the login user, or any comment of a moment of the login user.
+func (m *Moments) DeleteMomentComment(ctx context.Context, momentID, commentID string) (*model_struct.LocalMoment, error) {
+	resp, err := api.DeleteMomentComment.Invoke(ctx, &server_api_params.DeleteMomentCommentReq{MomentID: momentID, CommentID: commentID})
+	if err != nil {
+		return nil, err
+	}
+	m.cache(ctx, resp.Moment)
+	return resp.Moment, nil
+}
+
+// cache keeps the moment as returned by the server, the cache is only a fallback so a failure is logged.
+func (m *Moments) cache(ctx context.Context, moment *model_struct.LocalMoment) {
+	if moment == nil {
+		return
+	}
+	if err := m.db.SetMoments(ctx, []*model_struct.LocalMoment{moment}); err != nil {
+		log.ZWarn(ctx, "cache moment failed", err, "momentID", moment.MomentID)
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package moments
+
+import (
+	"context"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/errreport"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/server_api_params"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/protocol/sdkws"
+	"github.com/openimsdk/tools/errs"
+)
+
+// DoNotification handles the moment notifications, it updates the cache then calls the listener.
+func (m *Moments) DoNotification(ctx context.Context, msg *sdkws.MsgData) {
+	log.ZDebug(ctx, "moment notification", "msg", msg)
+	if err := m.doNotification(ctx, msg); err != nil {
+		errreport.Report(ctx, errreport.SourceNotification, "DoMomentNotification", err, "contentType", msg.ContentType)
+	}
+}
+
+func (m *Moments) doNotification(ctx context.Context, msg *sdkws.MsgData) error {
+	var tips server_api_params.MomentTips
+	if err := utils.UnmarshalNotificationElem(msg.Content, &tips); err != nil {
+		return err
+	}
+	if msg.ContentType == constant.MomentDeletedNotification {
+		if err := m.db.DeleteMoment(ctx, tips.MomentID); err != nil {
+			return err
+		}
+		m.listener().OnMomentDeleted(tips.MomentID)
+		return nil
+	}
+	if tips.Moment == nil {
+		return errs.New("moment notification without the moment", "contentType", msg.ContentType).Wrap()
+	}
+	m.cache(ctx, tips.Moment)
+	switch msg.ContentType {
+	case constant.MomentPublishedNotification:
+		m.listener().OnNewMoment(utils.StructToJsonString(tips.Moment))
+	case constant.MomentLikedNotification:
+		m.listener().OnMomentLiked(utils.StructToJsonString(tips))
+	case constant.MomentCommentedNotification:
+		m.listener().OnMomentCommented(utils.StructToJsonString(tips))
+	default:
+		return errs.New("unknown content type", "contentType", msg.ContentType).Wrap()
+	}
+	return nil
+}
@@ -0,0 +1,220 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package organization
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdk_params_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/tools/utils/datautil"
+)
+
+// departmentTree indexes the local departments by ID and by parent.
+type departmentTree struct {
+	departments map[string]*model_struct.LocalDepartment
+	children    map[string][]*model_struct.LocalDepartment
+}
+
+func (o *Organization) departmentTree(ctx context.Context) (*departmentTree, error) {
+	departments, err := o.db.GetAllDepartments(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tree := &departmentTree{
+		departments: make(map[string]*model_struct.LocalDepartment, len(departments)),
+		children:    make(map[string][]*model_struct.LocalDepartment),
+	}
+	// the departments are sorted, so are the children of each parent
+	for _, department := range departments {
+		tree.departments[department.DepartmentID] = department
+		tree.children[department.ParentID] = append(tree.children[department.ParentID], department)
+	}
+	return tree, nil
+}
+
+// path is the departments from the top department down to the department, a cycle in the parents is cut.
+func (t *departmentTree) path(departmentID string) []*model_struct.LocalDepartment {
+	var path []*model_struct.LocalDepartment
+	seen := make(map[string]struct{})
+	for department := t.departments[departmentID]; department != nil; department = t.departments[department.ParentID] {
+		if _, ok := seen[department.DepartmentID]; ok {
+			break
+		}
+		seen[department.DepartmentID] = struct{}{}
+		path = append(path, department)
+	}
+	slices.Reverse(path)
+	return path
+}
+
+// GetSubDepartments gets the departments right under the department and its members, the top departments
+// for an empty departmentID.
+func (o *Organization) GetSubDepartments(ctx context.Context, departmentID string) (*sdk_params_callback.GetSubDepartmentsCallback, error) {
+	if err := o.check(ctx); err != nil {
+		return nil, err
+	}
+	departments, err := o.db.GetSubDepartments(ctx, departmentID)
+	if err != nil {
+		return nil, err
+	}
+	res := &sdk_params_callback.GetSubDepartmentsCallback{Departments: departments}
+	if departmentID != "" {
+		if res.Members, err = o.db.GetDepartmentMembers(ctx, []string{departmentID}); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// GetDepartmentTree walks the departments from the department down, from the top departments for an empty
+// departmentID. depth is the levels of sub departments walked, 0 for all of them, and the members of the
+// departments walked are attached when withMembers is set.
+func (o *Organization) GetDepartmentTree(ctx context.Context, departmentID string, depth int, withMembers bool) ([]*sdk_params_callback.DepartmentNode, error) {
+	if err := o.check(ctx); err != nil {
+		return nil, err
+	}
+	if depth < 0 {
+		return nil, sdkerrs.ErrArgs.WrapMsg("depth can't be negative", "depth", depth)
+	}
+	tree, err := o.departmentTree(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var roots []*model_struct.LocalDepartment
+	if departmentID == "" {
+		roots = tree.children[""]
+	} else {
+		department, ok := tree.departments[departmentID]
+		if !ok {
+			return nil, sdkerrs.ErrArgs.WrapMsg("department not found", "departmentID", departmentID)
+		}
+		roots = []*model_struct.LocalDepartment{department}
+	}
+	var (
+		walked []*sdk_params_callback.DepartmentNode
+		seen   = make(map[string]struct{})
+		walk   func(department *model_struct.LocalDepartment, level int) *sdk_params_callback.DepartmentNode
+	)
+	walk = func(department *model_struct.LocalDepartment, level int) *sdk_params_callback.DepartmentNode {
+		seen[department.DepartmentID] = struct{}{}
+		node := &sdk_params_callback.DepartmentNode{LocalDepartment: department}
+		walked = append(walked, node)
+		if depth > 0 && level >= depth {
+			return node
+		}
+		for _, child := range tree.children[department.DepartmentID] {
+			if _, ok := seen[child.DepartmentID]; ok {
+				continue
+			}
+			node.SubDepartments = append(node.SubDepartments, walk(child, level+1))
+		}
+		return node
+	}
+	nodes := make([]*sdk_params_callback.DepartmentNode, 0, len(roots))
+	for _, root := range roots {
+		nodes = append(nodes, walk(root, 0))
+	}
+	if withMembers && len(walked) > 0 {
+		members, err := o.db.GetDepartmentMembers(ctx, datautil.Slice(walked, func(node *sdk_params_callback.DepartmentNode) string {
+			return node.DepartmentID
+		}))
+		if err != nil {
+			return nil, err
+		}
+		byDepartment := make(map[string][]*model_struct.LocalDepartmentMember)
+		for _, member := range members {
+			byDepartment[member.DepartmentID] = append(byDepartment[member.DepartmentID], member)
+		}
+		for _, node := range walked {
+			node.Members = byDepartment[node.DepartmentID]
+		}
+	}
+	return nodes, nil
+}
+
+// GetDepartmentMembers gets the members of the department by order.
+func (o *Organization) GetDepartmentMembers(ctx context.Context, departmentID string) ([]*model_struct.LocalDepartmentMember, error) {
+	if err := o.check(ctx); err != nil {
+		return nil, err
+	}
+	return o.db.GetDepartmentMembers(ctx, []string{departmentID})
+}
+
+// GetUserInDepartments gets the departments the user is in, with the path from the top department to each.
+func (o *Organization) GetUserInDepartments(ctx context.Context, userID string) ([]*sdk_params_callback.UserInDepartment, error) {
+	if err := o.check(ctx); err != nil {
+		return nil, err
+	}
+	members, err := o.db.GetUserDepartmentMembers(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(members) == 0 {
+		return nil, nil
+	}
+	tree, err := o.departmentTree(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*sdk_params_callback.UserInDepartment, 0, len(members))
+	for _, member := range members {
+		res = append(res, &sdk_params_callback.UserInDepartment{
+			Member:     member,
+			Department: tree.departments[member.DepartmentID],
+			Path:       tree.path(member.DepartmentID),
+		})
+	}
+	return res, nil
+}
+
+// SearchOrganization searches the local organization for the members by nickname or position and for the
+// departments by name, with the members of the departments found.
+func (o *Organization) SearchOrganization(ctx context.Context, params *sdk_params_callback.SearchOrganizationParams) (*sdk_params_callback.SearchOrganizationCallback, error) {
+	if err := o.check(ctx); err != nil {
+		return nil, err
+	}
+	if params.Keyword == "" {
+		return nil, sdkerrs.ErrArgs.WrapMsg("keyword can't be empty")
+	}
+	if !params.IsSearchNickname && !params.IsSearchPosition && !params.IsSearchDepartment {
+		return nil, sdkerrs.ErrArgs.WrapMsg("nothing to search, set isSearchNickname, isSearchPosition or isSearchDepartment")
+	}
+	res := &sdk_params_callback.SearchOrganizationCallback{}
+	if params.IsSearchDepartment {
+		departments, err := o.db.GetAllDepartments(ctx)
+		if err != nil {
+			return nil, err
+		}
+		keyword := strings.ToLower(params.Keyword)
+		for _, department := range departments {
+			if strings.Contains(strings.ToLower(department.Name), keyword) {
+				res.Departments = append(res.Departments, department)
+			}
+		}
+	}
+	departmentIDs := datautil.Slice(res.Departments, func(department *model_struct.LocalDepartment) string {
+		return department.DepartmentID
+	})
+	var err error
+	res.Members, err = o.db.SearchDepartmentMembers(ctx, params.Keyword, params.IsSearchNickname, params.IsSearchPosition, departmentIDs, params.Count)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,41 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package organization
+
+import (
+	"context"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/errreport"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/protocol/sdkws"
+	"github.com/openimsdk/tools/errs"
+)
+
+// DoNotification handles the organization notifications, the changes they announce are synced.
+func (o *Organization) DoNotification(ctx context.Context, msg *sdkws.MsgData) {
+	log.ZDebug(ctx, "organization notification", "msg", msg)
+	// the changes are synced on the first read when it is yet to come
+	if !o.enabled || !o.used.Load() {
+		return
+	}
+	switch msg.ContentType {
+	case constant.OrganizationChangedNotification:
+		o.requestSync(ctx)
+	default:
+		errreport.Report(ctx, errreport.SourceNotification, "DoOrganizationNotification",
+			errs.New("unknown content type", "contentType", msg.ContentType).Wrap(), "contentType", msg.ContentType)
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package organization is the directory of the organization: its departments and the users in them are synced
+// incrementally from the server to the local database, where they are walked as a tree and searched.
+package organization
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/api"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/db_interface"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/page"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/server_api_params"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/syncer"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/protocol/sdkws"
+)
+
+const (
+	departmentSyncLimit int64 = 100000
+	memberSyncLimit     int64 = 1000000
+)
+
+type Organization struct {
+	loginUserID string
+	db          db_interface.DataBase
+	enabled     bool
+	listener    func() open_im_sdk_callback.OnOrganizationListener
+
+	departmentSyncer *syncer.Syncer[*model_struct.LocalDepartment, server_api_params.GetPaginationDepartmentsResp, string]
+	memberSyncer     *syncer.Syncer[*model_struct.LocalDepartmentMember, server_api_params.GetPaginationDepartmentMembersResp, [2]string]
+	syncMutex        sync.Mutex
+
+	syncLock  sync.Mutex
+	syncTimer *time.Timer
+
+	// used is set by the first read of the organization, it is not synced before
+	used atomic.Bool
+}
+
+func NewOrganization() *Organization {
+	o := &Organization{}
+	o.initSyncer()
+	return o
+}
+
+func (o *Organization) SetLoginUserID(loginUserID string) {
+	o.loginUserID = loginUserID
+}
+
+func (o *Organization) SetDataBase(db db_interface.DataBase) {
+	o.db = db
+}
+
+// SetEnabled turns the organization on for the servers serving it, it is neither synced nor read otherwise.
+func (o *Organization) SetEnabled(enabled bool) {
+	o.enabled = enabled
+}
+
+func (o *Organization) SetListener(listener func() open_im_sdk_callback.OnOrganizationListener) {
+	o.listener = listener
+}
+
+// check fails when the organization is not enabled, and syncs it on its first read.
+func (o *Organization) check(ctx context.Context) error {
+	if !o.enabled {
+		return sdkerrs.ErrArgs.WrapMsg("the organization is not enabled, see EnableOrganization of the config")
+	}
+	o.use(ctx)
+	return nil
+}
+
+func (o *Organization) initSyncer() {
+	o.departmentSyncer = syncer.New2[*model_struct.LocalDepartment, server_api_params.GetPaginationDepartmentsResp, string](
+		syncer.WithInsert[*model_struct.LocalDepartment, server_api_params.GetPaginationDepartmentsResp, string](func(ctx context.Context, value *model_struct.LocalDepartment) error {
+			return o.db.InsertDepartment(ctx, value)
+		}),
+		syncer.WithDelete[*model_struct.LocalDepartment, server_api_params.GetPaginationDepartmentsResp, string](func(ctx context.Context, value *model_struct.LocalDepartment) error {
+			return o.db.DeleteDepartment(ctx, value.DepartmentID)
+		}),
+		syncer.WithUpdate[*model_struct.LocalDepartment, server_api_params.GetPaginationDepartmentsResp, string](func(ctx context.Context, server, local *model_struct.LocalDepartment) error {
+			return o.db.UpdateDepartment(ctx, server)
+		}),
+		syncer.WithUUID[*model_struct.LocalDepartment, server_api_params.GetPaginationDepartmentsResp, string](func(value *model_struct.LocalDepartment) string {
+			return value.DepartmentID
+		}),
+		syncer.WithNotice[*model_struct.LocalDepartment, server_api_params.GetPaginationDepartmentsResp, string](func(ctx context.Context, state int, server, local *model_struct.LocalDepartment) error {
+			switch state {
+			case syncer.Insert:
+				o.listener().OnDepartmentAdded(utils.StructToJsonString(server))
+			case syncer.Delete:
+				o.listener().OnDepartmentDeleted(utils.StructToJsonString(local))
+			case syncer.Update:
+				o.listener().OnDepartmentInfoChanged(utils.StructToJsonString(server))
+			}
+			return nil
+		}),
+		syncer.WithBatchInsert[*model_struct.LocalDepartment, server_api_params.GetPaginationDepartmentsResp, string](func(ctx context.Context, values []*model_struct.LocalDepartment) error {
+			return o.db.BatchInsertDepartments(ctx, values)
+		}),
+		syncer.WithDeleteAll[*model_struct.LocalDepartment, server_api_params.GetPaginationDepartmentsResp, string](func(ctx context.Context, _ string) error {
+			return o.db.DeleteAllDepartments(ctx)
+		}),
+		syncer.WithBatchPageReq[*model_struct.LocalDepartment, server_api_params.GetPaginationDepartmentsResp, string](func(entityID string) page.PageReq {
+			return &server_api_params.GetPaginationDepartmentsReq{Pagination: &sdkws.RequestPagination{ShowNumber: 500}}
+		}),
+		syncer.WithBatchPageRespConvertFunc[*model_struct.LocalDepartment, server_api_params.GetPaginationDepartmentsResp, string](func(resp *server_api_params.GetPaginationDepartmentsResp) []*model_struct.LocalDepartment {
+			return resp.Departments
+		}),
+		syncer.WithReqApiRouter[*model_struct.LocalDepartment, server_api_params.GetPaginationDepartmentsResp, string](api.GetPaginationDepartments.Route()),
+		syncer.WithFullSyncLimit[*model_struct.LocalDepartment, server_api_params.GetPaginationDepartmentsResp, string](departmentSyncLimit),
+	)
+
+	o.memberSyncer = syncer.New2[*model_struct.LocalDepartmentMember, server_api_params.GetPaginationDepartmentMembersResp, [2]string](
+		syncer.WithInsert[*model_struct.LocalDepartmentMember, server_api_params.GetPaginationDepartmentMembersResp, [2]string](func(ctx context.Context, value *model_struct.LocalDepartmentMember) error {
+			return o.db.InsertDepartmentMember(ctx, value)
+		}),
+		syncer.WithDelete[*model_struct.LocalDepartmentMember, server_api_params.GetPaginationDepartmentMembersResp, [2]string](func(ctx context.Context, value *model_struct.LocalDepartmentMember) error {
+			return o.db.DeleteDepartmentMember(ctx, value.DepartmentID, value.UserID)
+		}),
+		syncer.WithUpdate[*model_struct.LocalDepartmentMember, server_api_params.GetPaginationDepartmentMembersResp, [2]string](func(ctx context.Context, server, local *model_struct.LocalDepartmentMember) error {
+			return o.db.UpdateDepartmentMember(ctx, server)
+		}),
+		syncer.WithUUID[*model_struct.LocalDepartmentMember, server_api_params.GetPaginationDepartmentMembersResp, [2]string](func(value *model_struct.LocalDepartmentMember) [2]string {
+			return [...]string{value.DepartmentID, value.UserID}
+		}),
+		syncer.WithNotice[*model_struct.LocalDepartmentMember, server_api_params.GetPaginationDepartmentMembersResp, [2]string](func(ctx context.Context, state int, server, local *model_struct.LocalDepartmentMember) error {
+			switch state {
+			case syncer.Insert:
+				o.listener().OnDepartmentMemberAdded(utils.StructToJsonString(server))
+			case syncer.Delete:
+				o.listener().OnDepartmentMemberDeleted(utils.StructToJsonString(local))
+			case syncer.Update:
+				o.listener().OnDepartmentMemberInfoChanged(utils.StructToJsonString(server))
+			}
+			return nil
+		}),
+		syncer.WithBatchInsert[*model_struct.LocalDepartmentMember, server_api_params.GetPaginationDepartmentMembersResp, [2]string](func(ctx context.Context, values []*model_struct.LocalDepartmentMember) error {
+			return o.db.BatchInsertDepartmentMembers(ctx, values)
+		}),
+		syncer.WithDeleteAll[*model_struct.LocalDepartmentMember, server_api_params.GetPaginationDepartmentMembersResp, [2]string](func(ctx context.Context, _ string) error {
+			return o.db.DeleteAllDepartmentMembers(ctx)
+		}),
+		syncer.WithBatchPageReq[*model_struct.LocalDepartmentMember, server_api_params.GetPaginationDepartmentMembersResp, [2]string](func(entityID string) page.PageReq {
+			return &server_api_params.GetPaginationDepartmentMembersReq{Pagination: &sdkws.RequestPagination{ShowNumber: 500}}
+		}),
+		syncer.WithBatchPageRespConvertFunc[*model_struct.LocalDepartmentMember, server_api_params.GetPaginationDepartmentMembersResp, [2]string](func(resp *server_api_params.GetPaginationDepartmentMembersResp) []*model_struct.LocalDepartmentMember {
+			return resp.Members
+		}),
+		syncer.WithReqApiRouter[*model_struct.LocalDepartmentMember, server_api_params.GetPaginationDepartmentMembersResp, [2]string](api.GetPaginationDepartmentMembers.Route()),
+		syncer.WithFullSyncLimit[*model_struct.LocalDepartmentMember, server_api_params.GetPaginationDepartmentMembersResp, [2]string](memberSyncLimit),
+	)
+}
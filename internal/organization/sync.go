@@ -0,0 +1,202 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package organization
+
+import (
+	"context"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/api"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/server_api_params"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/syncer"
+	"github.com/openimsdk/tools/utils/datautil"
+)
+
+// organizationSyncDelay gathers the organization notifications arriving together into one sync
+const organizationSyncDelay = 500 * time.Millisecond
+
+// memberKey is the key of a department member in the version of the department members.
+func memberKey(departmentID, userID string) string {
+	return departmentID + "/" + userID
+}
+
+func (o *Organization) IncrSyncDepartments(ctx context.Context) error {
+	departmentSyncer := syncer.VersionSynchronizer[*model_struct.LocalDepartment, *server_api_params.GetIncrementalDepartmentsResp]{
+		Ctx:       ctx,
+		DB:        o.db,
+		TableName: model_struct.LocalDepartment{}.TableName(),
+		EntityID:  o.loginUserID,
+		Key: func(department *model_struct.LocalDepartment) string {
+			return department.DepartmentID
+		},
+		Local: func() ([]*model_struct.LocalDepartment, error) {
+			return o.db.GetAllDepartments(ctx)
+		},
+		Server: func(version *model_struct.LocalVersionSync) (*server_api_params.GetIncrementalDepartmentsResp, error) {
+			return api.GetIncrementalDepartments.Invoke(ctx, &server_api_params.GetIncrementalDepartmentsReq{
+				VersionID: version.VersionID,
+				Version:   version.Version,
+			})
+		},
+		Full: func(resp *server_api_params.GetIncrementalDepartmentsResp) bool {
+			return resp.Full
+		},
+		Version: func(resp *server_api_params.GetIncrementalDepartmentsResp) (string, uint64) {
+			return resp.VersionID, resp.Version
+		},
+		Delete: func(resp *server_api_params.GetIncrementalDepartmentsResp) []string {
+			return resp.Delete
+		},
+		Update: func(resp *server_api_params.GetIncrementalDepartmentsResp) []*model_struct.LocalDepartment {
+			return resp.Update
+		},
+		Insert: func(resp *server_api_params.GetIncrementalDepartmentsResp) []*model_struct.LocalDepartment {
+			return resp.Insert
+		},
+		Syncer: func(server, local []*model_struct.LocalDepartment) error {
+			return o.departmentSyncer.Sync(ctx, server, local, nil)
+		},
+		FullSyncer: func(ctx context.Context) error {
+			return o.departmentSyncer.FullSync(ctx, o.loginUserID)
+		},
+		// the local departments are the ones of the server once the full sync is over
+		FullID: func(ctx context.Context) ([]string, error) {
+			departments, err := o.db.GetAllDepartments(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return datautil.Slice(departments, func(department *model_struct.LocalDepartment) string {
+				return department.DepartmentID
+			}), nil
+		},
+	}
+	return departmentSyncer.IncrementalSync()
+}
+
+func (o *Organization) IncrSyncDepartmentMembers(ctx context.Context) error {
+	key := func(member *model_struct.LocalDepartmentMember) string {
+		return memberKey(member.DepartmentID, member.UserID)
+	}
+	memberSyncer := syncer.VersionSynchronizer[*model_struct.LocalDepartmentMember, *server_api_params.GetIncrementalDepartmentMembersResp]{
+		Ctx:       ctx,
+		DB:        o.db,
+		TableName: model_struct.LocalDepartmentMember{}.TableName(),
+		EntityID:  o.loginUserID,
+		Key:       key,
+		Local: func() ([]*model_struct.LocalDepartmentMember, error) {
+			return o.db.GetAllDepartmentMembers(ctx)
+		},
+		Server: func(version *model_struct.LocalVersionSync) (*server_api_params.GetIncrementalDepartmentMembersResp, error) {
+			return api.GetIncrementalDepartmentMembers.Invoke(ctx, &server_api_params.GetIncrementalDepartmentMembersReq{
+				VersionID: version.VersionID,
+				Version:   version.Version,
+			})
+		},
+		Full: func(resp *server_api_params.GetIncrementalDepartmentMembersResp) bool {
+			return resp.Full
+		},
+		Version: func(resp *server_api_params.GetIncrementalDepartmentMembersResp) (string, uint64) {
+			return resp.VersionID, resp.Version
+		},
+		Delete: func(resp *server_api_params.GetIncrementalDepartmentMembersResp) []string {
+			return datautil.Slice(resp.Delete, func(id *server_api_params.DepartmentMemberID) string {
+				return memberKey(id.DepartmentID, id.UserID)
+			})
+		},
+		Update: func(resp *server_api_params.GetIncrementalDepartmentMembersResp) []*model_struct.LocalDepartmentMember {
+			return resp.Update
+		},
+		Insert: func(resp *server_api_params.GetIncrementalDepartmentMembersResp) []*model_struct.LocalDepartmentMember {
+			return resp.Insert
+		},
+		Syncer: func(server, local []*model_struct.LocalDepartmentMember) error {
+			return o.memberSyncer.Sync(ctx, server, local, nil)
+		},
+		FullSyncer: func(ctx context.Context) error {
+			return o.memberSyncer.FullSync(ctx, o.loginUserID)
+		},
+		FullID: func(ctx context.Context) ([]string, error) {
+			members, err := o.db.GetAllDepartmentMembers(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return datautil.Slice(members, key), nil
+		},
+	}
+	return memberSyncer.IncrementalSync()
+}
+
+// IncrSyncOrganizationWithLock syncs the departments then their members, it does nothing when the
+// organization is not enabled.
+func (o *Organization) IncrSyncOrganizationWithLock(ctx context.Context) error {
+	if !o.enabled {
+		return nil
+	}
+	o.syncMutex.Lock()
+	defer o.syncMutex.Unlock()
+	if err := o.IncrSyncDepartments(ctx); err != nil {
+		return err
+	}
+	return o.IncrSyncDepartmentMembers(ctx)
+}
+
+// SyncIfUsed syncs the organization when it has been read since the login, the sync of the others is left to
+// their first read so that it does not slow the login down.
+func (o *Organization) SyncIfUsed(ctx context.Context) error {
+	if !o.used.Load() {
+		return nil
+	}
+	return o.IncrSyncOrganizationWithLock(ctx)
+}
+
+// use syncs the organization on its first read since the login. The read waits for the sync when the
+// organization was never synced to this device, it is served from the local database meanwhile otherwise.
+func (o *Organization) use(ctx context.Context) {
+	if o.used.Swap(true) {
+		return
+	}
+	version, err := o.db.GetVersionSync(ctx, model_struct.LocalDepartment{}.TableName(), o.loginUserID)
+	if err == nil && version.Version > 0 {
+		go func() {
+			if err := o.IncrSyncOrganizationWithLock(context.WithoutCancel(ctx)); err != nil {
+				log.ZWarn(ctx, "sync organization on first use failed", err)
+			}
+		}()
+		return
+	}
+	if err := o.IncrSyncOrganizationWithLock(ctx); err != nil {
+		log.ZWarn(ctx, "sync organization on first use failed", err)
+	}
+}
+
+// requestSync syncs the organization a moment later, so that the notifications of a reorganization share
+// one incremental sync.
+func (o *Organization) requestSync(ctx context.Context) {
+	o.syncLock.Lock()
+	defer o.syncLock.Unlock()
+	if o.syncTimer != nil {
+		return
+	}
+	o.syncTimer = time.AfterFunc(organizationSyncDelay, func() {
+		o.syncLock.Lock()
+		o.syncTimer = nil
+		o.syncLock.Unlock()
+		if err := o.IncrSyncOrganizationWithLock(context.WithoutCancel(ctx)); err != nil {
+			log.ZWarn(ctx, "sync organization after notifications failed", err)
+		}
+	})
+}
@@ -0,0 +1,146 @@
+package qrlogin
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/api"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/ccontext"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/server_api_params"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+const pollInterval = time.Second * 2
+
+// QRLogin implements logging in a device that has no session by scanning a QR code with a logged-in device.
+// The device showing the QR code polls the handshake state, the logged-in device scans and approves it.
+type QRLogin struct {
+	listener func() open_im_sdk_callback.OnQRLoginListener
+
+	lock   sync.Mutex
+	cancel context.CancelFunc
+}
+
+func NewQRLogin() *QRLogin {
+	return &QRLogin{}
+}
+
+func (q *QRLogin) SetListener(listener func() open_im_sdk_callback.OnQRLoginListener) {
+	q.listener = listener
+}
+
+// CreateLoginQRCode creates a QR code for this device and watches its state until it is confirmed,
+// rejected, expired or canceled. A previously created QR code stops being watched.
+func (q *QRLogin) CreateLoginQRCode(ctx context.Context) (*sdk_struct.QRLoginCode, error) {
+	resp, err := api.CreateQRLogin.Invoke(ctx, &server_api_params.CreateQRLoginReq{PlatformID: ccontext.Info(ctx).PlatformID()})
+	if err != nil {
+		return nil, err
+	}
+	q.lock.Lock()
+	if q.cancel != nil {
+		q.cancel()
+	}
+	pollCtx, cancel := context.WithCancel(ctx)
+	q.cancel = cancel
+	q.lock.Unlock()
+	go q.poll(pollCtx, resp.QRID, resp.ExpireTime)
+	return &sdk_struct.QRLoginCode{
+		QRID:       resp.QRID,
+		Payload:    constant.QRLoginPayloadPrefix + resp.QRID,
+		ExpireTime: resp.ExpireTime,
+	}, nil
+}
+
+// CancelLoginQRCode stops watching the QR code created by this device.
+func (q *QRLogin) CancelLoginQRCode(_ context.Context) error {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if q.cancel != nil {
+		q.cancel()
+		q.cancel = nil
+	}
+	return nil
+}
+
+// ScanLoginQRCode tells the device showing the QR code that it was scanned, and returns the platform
+// requesting the login so it can be shown to the user before approving.
+func (q *QRLogin) ScanLoginQRCode(ctx context.Context, payload string) (int32, error) {
+	qrID, err := parsePayload(payload)
+	if err != nil {
+		return 0, err
+	}
+	return api.ExtractField(ctx, api.ScanQRLogin.Invoke, &server_api_params.ScanQRLoginReq{QRID: qrID},
+		func(resp *server_api_params.ScanQRLoginResp) int32 { return resp.PlatformID })
+}
+
+// ApproveLoginQRCode approves the login of the device showing the QR code with the login user's account.
+func (q *QRLogin) ApproveLoginQRCode(ctx context.Context, payload string) error {
+	return q.confirm(ctx, payload, true)
+}
+
+// RejectLoginQRCode rejects the login of the device showing the QR code.
+func (q *QRLogin) RejectLoginQRCode(ctx context.Context, payload string) error {
+	return q.confirm(ctx, payload, false)
+}
+
+func (q *QRLogin) confirm(ctx context.Context, payload string, approve bool) error {
+	qrID, err := parsePayload(payload)
+	if err != nil {
+		return err
+	}
+	return api.ConfirmQRLogin.Execute(ctx, &server_api_params.ConfirmQRLoginReq{QRID: qrID, Approve: approve})
+}
+
+func (q *QRLogin) poll(ctx context.Context, qrID string, expireTime int64) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	state := int32(constant.QRLoginStateWaiting)
+	for {
+		select {
+		case <-ctx.Done():
+			q.notify(&sdk_struct.QRLoginState{QRID: qrID, State: constant.QRLoginStateCanceled})
+			return
+		case <-ticker.C:
+		}
+		if expireTime > 0 && time.Now().UnixMilli() > expireTime {
+			q.notify(&sdk_struct.QRLoginState{QRID: qrID, State: constant.QRLoginStateExpired})
+			return
+		}
+		resp, err := api.GetQRLoginStatus.Invoke(ctx, &server_api_params.GetQRLoginStatusReq{QRID: qrID})
+		if err != nil {
+			log.ZWarn(ctx, "GetQRLoginStatus failed", err, "qrID", qrID)
+			continue
+		}
+		if resp.State == state {
+			continue
+		}
+		state = resp.State
+		q.notify(&sdk_struct.QRLoginState{QRID: qrID, State: resp.State, UserID: resp.UserID, Token: resp.Token})
+		switch resp.State {
+		case constant.QRLoginStateConfirmed, constant.QRLoginStateRejected, constant.QRLoginStateExpired:
+			return
+		}
+	}
+}
+
+func (q *QRLogin) notify(state *sdk_struct.QRLoginState) {
+	if q.listener == nil {
+		return
+	}
+	q.listener().OnQRLoginStateChanged(utils.StructToJsonString(state))
+}
+
+func parsePayload(payload string) (string, error) {
+	qrID, ok := strings.CutPrefix(payload, constant.QRLoginPayloadPrefix)
+	if !ok || qrID == "" {
+		return "", sdkerrs.ErrArgs.WrapMsg("invalid qr login payload")
+	}
+	return qrID, nil
+}
@@ -10,11 +10,11 @@ import (
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/datafetcher"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/page"
 	sdk "github.com/openimsdk/openim-sdk-core/v3/pkg/sdk_params_callback"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/server_api_params"
-
-	"github.com/openimsdk/tools/log"
 )
 
 func (r *Relation) GetSpecifiedFriendsInfo(ctx context.Context, friendUserIDList []string, filterBlack bool) ([]*model_struct.LocalFriend, error) {
@@ -69,7 +69,12 @@ func (r *Relation) GetSpecifiedFriendsInfo(ctx context.Context, friendUserIDList
 	return res, nil
 }
 
+// AddFriend applies to add the user as a friend. The published privacy settings of the user are checked first
+// so that every device refuses the same applications, the server has the last word.
 func (r *Relation) AddFriend(ctx context.Context, req *relation.ApplyToAddFriendReq) error {
+	if err := r.user.CheckAddFriend(ctx, req.ToUserID, r.isGroupMate); err != nil {
+		return err
+	}
 	return r.addFriend(ctx, req)
 }
 
@@ -185,8 +190,8 @@ func (r *Relation) GetFriendList(ctx context.Context, filterBlack bool) ([]*mode
 	return res, nil
 }
 
-func (r *Relation) GetFriendListPage(ctx context.Context, offset, count int32, filterBlack bool) ([]*model_struct.LocalFriend, error) {
-	dataFetcher := datafetcher.NewDataFetcher(
+func (r *Relation) friendDataFetcher() *datafetcher.DataFetcher[*model_struct.LocalFriend] {
+	return datafetcher.NewDataFetcher(
 		r.db,
 		r.friendListTableName(),
 		r.loginUserID,
@@ -208,6 +213,10 @@ func (r *Relation) GetFriendListPage(ctx context.Context, offset, count int32, f
 			return datautil.Batch(ServerFriendToLocalFriend, serverFriend), nil
 		},
 	)
+}
+
+func (r *Relation) GetFriendListPage(ctx context.Context, offset, count int32, filterBlack bool) ([]*model_struct.LocalFriend, error) {
+	dataFetcher := r.friendDataFetcher()
 	localBlackList, err := r.db.GetBlackListDB(ctx)
 	if err != nil {
 		return nil, err
@@ -234,6 +243,58 @@ func (r *Relation) GetFriendListPage(ctx context.Context, offset, count int32, f
 	return res, nil
 }
 
+// GetFriendListByCursor gets the page of the friend list following the cursor, the first page for the empty cursor.
+func (r *Relation) GetFriendListByCursor(ctx context.Context, cursor string, count int32, filterBlack bool) (*sdk.GetFriendListByCursorCallback, error) {
+	if count <= 0 {
+		return nil, sdkerrs.ErrArgs.WrapMsg("count must be greater than 0")
+	}
+	after, err := page.DecodeCursor(page.CursorFriends, cursor)
+	if err != nil {
+		return nil, err
+	}
+	var blackUserIDs map[string]struct{}
+	if filterBlack {
+		localBlackList, err := r.db.GetBlackListDB(ctx)
+		if err != nil {
+			return nil, err
+		}
+		blackUserIDs = datautil.SliceSetAny(localBlackList, func(e *model_struct.LocalBlack) string {
+			return e.BlockUserID
+		})
+	}
+	var (
+		afterID string
+		index   int
+	)
+	if after != nil {
+		afterID, index = after.ID, after.Index
+	}
+	res := &sdk.GetFriendListByCursorCallback{FriendList: []*model_struct.LocalFriend{}, NextCursor: cursor}
+	dataFetcher := r.friendDataFetcher()
+	for {
+		friends, err := dataFetcher.FetchAfter(ctx, afterID, index, int(count)-len(res.FriendList))
+		if err != nil {
+			return nil, err
+		}
+		for _, friend := range friends.Data {
+			if _, ok := blackUserIDs[friend.FriendUserID]; !ok {
+				res.FriendList = append(res.FriendList, friend)
+			}
+		}
+		if len(friends.UIDs) > 0 {
+			afterID, index = friends.After("")
+		}
+		res.HasMore = friends.HasMore
+		if !res.HasMore || len(res.FriendList) >= int(count) {
+			break
+		}
+	}
+	if afterID != "" {
+		res.NextCursor = (&page.Cursor{List: page.CursorFriends, ID: afterID, Index: index}).Encode()
+	}
+	return res, nil
+}
+
 func (r *Relation) SearchFriends(ctx context.Context, param *sdk.SearchFriendsParam) ([]*sdk.SearchFriendItem, error) {
 	if len(param.KeywordList) == 0 || (!param.IsSearchNickname && !param.IsSearchUserID && !param.IsSearchRemark) {
 		return nil, sdkerrs.ErrArgs.WrapMsg("keyword is null or search field all false")
@@ -2,13 +2,18 @@ package relation
 
 import (
 	"context"
+	"time"
 
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/syncer"
 	"github.com/openimsdk/protocol/relation"
 	"github.com/openimsdk/tools/utils/datautil"
 )
 
+// friendSyncDelay gathers the friend notifications arriving together into one sync
+const friendSyncDelay = 200 * time.Millisecond
+
 func (r *Relation) IncrSyncFriends(ctx context.Context) error {
 	friendSyncer := syncer.VersionSynchronizer[*model_struct.LocalFriend, *relation.GetIncrementalFriendsResp]{
 		Ctx:       ctx,
@@ -65,12 +70,57 @@ func (r *Relation) IncrSyncFriends(ctx context.Context) error {
 	return friendSyncer.IncrementalSync()
 }
 
+// requestFriendSync syncs the friends a moment later, so that a burst of friend notifications, like the ones
+// pulled after reconnecting, shares one incremental sync instead of one request each.
+func (r *Relation) requestFriendSync(ctx context.Context) {
+	r.friendSyncLock.Lock()
+	defer r.friendSyncLock.Unlock()
+	if r.friendSyncTimer != nil {
+		return
+	}
+	var timer *time.Timer
+	timer = time.AfterFunc(friendSyncDelay, func() {
+		r.friendSyncLock.Lock()
+		// stopped after it fired
+		if r.friendSyncTimer != timer {
+			r.friendSyncLock.Unlock()
+			return
+		}
+		r.friendSyncTimer = nil
+		r.friendSyncLock.Unlock()
+		if err := r.IncrSyncFriendsWithLock(context.WithoutCancel(ctx)); err != nil {
+			log.ZWarn(ctx, "sync friends after notifications failed", err)
+		}
+	})
+	r.friendSyncTimer = timer
+}
+
+// StopFriendSync drops the friend sync waiting for its delay, so that it does not run after the user logs out.
+func (r *Relation) StopFriendSync() {
+	r.friendSyncLock.Lock()
+	defer r.friendSyncLock.Unlock()
+	if r.friendSyncTimer != nil {
+		r.friendSyncTimer.Stop()
+		r.friendSyncTimer = nil
+	}
+}
+
 func (r *Relation) IncrSyncFriendsWithLock(ctx context.Context) error {
 	r.relationSyncMutex.Lock()
 	defer r.relationSyncMutex.Unlock()
 	return r.IncrSyncFriends(ctx)
 }
 
+// ForceResyncFriends drops the version of the friend list, so that the server sends the full list again.
+func (r *Relation) ForceResyncFriends(ctx context.Context) error {
+	r.relationSyncMutex.Lock()
+	defer r.relationSyncMutex.Unlock()
+	if err := r.db.DeleteVersionSync(ctx, r.friendListTableName(), r.loginUserID); err != nil {
+		return err
+	}
+	return r.IncrSyncFriends(ctx)
+}
+
 func (r *Relation) friendListTableName() string {
 	return model_struct.LocalFriend{}.TableName()
 }
@@ -5,14 +5,14 @@ import (
 	"fmt"
 
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/errreport"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
 	"github.com/openimsdk/protocol/sdkws"
-	"github.com/openimsdk/tools/log"
 )
 
 func (r *Relation) DoNotification(ctx context.Context, msg *sdkws.MsgData) {
 	if err := r.doNotification(ctx, msg); err != nil {
-		log.ZError(ctx, "doNotification error", err, "msg", msg)
+		errreport.Report(ctx, errreport.SourceNotification, "DoFriendNotification", err, "contentType", msg.ContentType)
 	}
 }
 
@@ -36,7 +36,7 @@ func (r *Relation) doNotification(ctx context.Context, msg *sdkws.MsgData) error
 		if tips.Request != nil {
 			r.friendshipListener.OnFriendApplicationAccepted(*ServerFriendRequestToLocalFriendRequest(tips.Request))
 		}
-		return r.IncrSyncFriends(ctx)
+		r.requestFriendSync(ctx)
 	case constant.FriendApplicationRejectedNotification:
 		var tips sdkws.FriendApplicationRejectedTips
 		if err := utils.UnmarshalNotificationElem(msg.Content, &tips); err != nil {
@@ -50,9 +50,9 @@ func (r *Relation) doNotification(ctx context.Context, msg *sdkws.MsgData) error
 		}
 		if tips.Friend != nil && tips.Friend.FriendUser != nil {
 			if tips.Friend.FriendUser.UserID == r.loginUserID {
-				return r.IncrSyncFriends(ctx)
+				r.requestFriendSync(ctx)
 			} else if tips.Friend.OwnerUserID == r.loginUserID {
-				return r.IncrSyncFriends(ctx)
+				r.requestFriendSync(ctx)
 			}
 		}
 	case constant.FriendDeletedNotification:
@@ -62,7 +62,7 @@ func (r *Relation) doNotification(ctx context.Context, msg *sdkws.MsgData) error
 		}
 		if tips.FromToUserID != nil {
 			if tips.FromToUserID.FromUserID == r.loginUserID {
-				return r.IncrSyncFriends(ctx)
+				r.requestFriendSync(ctx)
 			}
 		}
 	case constant.FriendRemarkSetNotification:
@@ -72,7 +72,7 @@ func (r *Relation) doNotification(ctx context.Context, msg *sdkws.MsgData) error
 		}
 		if tips.FromToUserID != nil {
 			if tips.FromToUserID.FromUserID == r.loginUserID {
-				return r.IncrSyncFriends(ctx)
+				r.requestFriendSync(ctx)
 			}
 		}
 	case constant.FriendInfoUpdatedNotification:
@@ -81,7 +81,7 @@ func (r *Relation) doNotification(ctx context.Context, msg *sdkws.MsgData) error
 			return err
 		}
 		if tips.UserID != r.loginUserID {
-			return r.IncrSyncFriends(ctx)
+			r.requestFriendSync(ctx)
 		}
 	case constant.BlackAddedNotification:
 		var tips sdkws.BlackAddedTips
@@ -105,7 +105,7 @@ func (r *Relation) doNotification(ctx context.Context, msg *sdkws.MsgData) error
 			return err
 		}
 		if tips.FromToUserID.ToUserID == r.loginUserID {
-			return r.IncrSyncFriends(ctx)
+			r.requestFriendSync(ctx)
 		}
 	default:
 		return fmt.Errorf("type failed %d", msg.ContentType)
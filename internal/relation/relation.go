@@ -3,6 +3,7 @@ package relation
 import (
 	"context"
 	"sync"
+	"time"
 
 	"github.com/openimsdk/openim-sdk-core/v3/internal/user"
 	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
@@ -11,13 +12,13 @@ import (
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/db_interface"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/page"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/syncer"
 	"github.com/openimsdk/protocol/relation"
 	"github.com/openimsdk/tools/utils/datautil"
 
 	"github.com/openimsdk/protocol/sdkws"
-	"github.com/openimsdk/tools/log"
 )
 
 const (
@@ -35,11 +36,15 @@ type Relation struct {
 	loginUserID            string
 	db                     db_interface.DataBase
 	user                   *user.User
+	isGroupMate            func(ctx context.Context, userID string) (bool, error)
 	friendSyncer           *syncer.Syncer[*model_struct.LocalFriend, relation.GetPaginationFriendsResp, [2]string]
 	blackSyncer            *syncer.Syncer[*model_struct.LocalBlack, syncer.NoResp, [2]string]
 	conversationEventQueue chan common.Cmd2Value
 	listenerForService     open_im_sdk_callback.OnListenerForService
 	relationSyncMutex      sync.Mutex
+
+	friendSyncLock  sync.Mutex
+	friendSyncTimer *time.Timer
 }
 
 func (r *Relation) initSyncer() {
@@ -51,7 +56,7 @@ func (r *Relation) initSyncer() {
 			return r.db.DeleteFriendDB(ctx, value.FriendUserID)
 		}),
 		syncer.WithUpdate[*model_struct.LocalFriend, relation.GetPaginationFriendsResp, [2]string](func(ctx context.Context, server, local *model_struct.LocalFriend) error {
-			r.user.UserCache().Delete(server.FriendUserID)
+			r.user.UserCache().Invalidate(server.FriendUserID)
 			return r.db.UpdateFriend(ctx, server)
 		}),
 		syncer.WithUUID[*model_struct.LocalFriend, relation.GetPaginationFriendsResp, [2]string](func(value *model_struct.LocalFriend) [2]string {
@@ -167,7 +172,13 @@ func (r *Relation) SetDataBase(db db_interface.DataBase) {
 	r.db = db
 }
 
+// SetGroupMateChecker sets the function telling whether a user shares a group with the login user.
+func (r *Relation) SetGroupMateChecker(isGroupMate func(ctx context.Context, userID string) (bool, error)) {
+	r.isGroupMate = isGroupMate
+}
+
 // SetLoginUserID sets the loginUserID field in Relation struct
 func (r *Relation) SetLoginUserID(loginUserID string) {
+	r.StopFriendSync()
 	r.loginUserID = loginUserID
 }
@@ -5,7 +5,7 @@ import (
 
 	"github.com/openimsdk/tools/utils/datautil"
 
-	"github.com/openimsdk/tools/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
 )
 
 func (r *Relation) SyncAllBlackList(ctx context.Context) error {
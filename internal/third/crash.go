@@ -0,0 +1,44 @@
+package third
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/crash"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/tools/errs"
+)
+
+// UploadCrashReports uploads the crash reports saved in one archive, and removes them once uploaded. Returns
+// the number of reports uploaded.
+func (c *Third) UploadCrashReports(ctx context.Context, ex string, progress Progress) (int, error) {
+	if c.logUploadLock.TryLock() {
+		defer c.logUploadLock.Unlock()
+	} else {
+		return 0, errs.New("log file is uploading").Wrap()
+	}
+	paths := crash.Paths()
+	if len(paths) == 0 {
+		return 0, errs.New("not found crash report").Wrap()
+	}
+	zippath := filepath.Join(c.LogFilePath, fmt.Sprintf("crash_%d_%d.zip", time.Now().UnixMilli(), rand.Uint32()))
+	defer os.Remove(zippath)
+	if err := zipFiles(zippath, paths); err != nil {
+		return 0, err
+	}
+	if err := c.uploadLogZip(ctx, zippath, ex, progress); err != nil {
+		return 0, err
+	}
+	ids := make([]string, 0, len(paths))
+	for _, path := range paths {
+		ids = append(ids, crash.ReportID(path))
+	}
+	if err := crash.Delete(ids); err != nil {
+		log.ZWarn(ctx, "remove uploaded crash reports failed", err, "ids", ids)
+	}
+	return len(paths), nil
+}
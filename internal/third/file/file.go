@@ -18,7 +18,14 @@ import "io"
 
 type ReadFile interface {
 	io.Reader
+	io.ReaderAt
 	io.Closer
 	Size() int64
 	StartSeek(whence int) error
 }
+
+// fingerprinter is a file that tells whether it changed. The hashes of its parts are kept with its upload,
+// an interrupted upload resumes without reading the file again.
+type fingerprinter interface {
+	Fingerprint() string
+}
@@ -18,6 +18,9 @@ package file
 
 import (
 	"bufio"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
 	"io"
 	"os"
 )
@@ -35,6 +38,7 @@ func Open(req *UploadFileReq) (ReadFile, error) {
 		return nil, err
 	}
 	df := &defaultFile{
+		path: req.Filepath,
 		file: file,
 		info: info,
 	}
@@ -43,6 +47,7 @@ func Open(req *UploadFileReq) (ReadFile, error) {
 }
 
 type defaultFile struct {
+	path   string
 	file   *os.File
 	info   os.FileInfo
 	reader io.Reader
@@ -56,6 +61,10 @@ func (d *defaultFile) Read(p []byte) (n int, err error) {
 	return d.reader.Read(p)
 }
 
+func (d *defaultFile) ReadAt(p []byte, off int64) (n int, err error) {
+	return d.file.ReadAt(p, off)
+}
+
 func (d *defaultFile) Close() error {
 	return d.file.Close()
 }
@@ -71,3 +80,9 @@ func (d *defaultFile) StartSeek(whence int) error {
 func (d *defaultFile) Size() int64 {
 	return d.info.Size()
 }
+
+// Fingerprint changes with the path, the size and the modification time of the file.
+func (d *defaultFile) Fingerprint() string {
+	sum := md5.Sum([]byte(fmt.Sprintf("%s|%d|%d", d.path, d.info.Size(), d.info.ModTime().UnixNano())))
+	return hex.EncodeToString(sum[:])
+}
@@ -79,6 +79,32 @@ func (j *jsFile) Read(p []byte) (n int, err error) {
 	return j.reader.Read(p)
 }
 
+// ReadAt reads from the file without moving the offset of Read, the parts of an upload are read with it.
+func (j *jsFile) ReadAt(p []byte, off int64) (n int, err error) {
+	if off < 0 {
+		return 0, errors.New("read offset < 0")
+	}
+	if off >= j.size {
+		return 0, io.EOF
+	}
+	length := int64(len(p))
+	if off+length > j.size {
+		length = j.size - off
+	}
+	data, err := j.file.Read(off, length)
+	if err != nil {
+		return 0, err
+	}
+	if len(data) > len(p) {
+		return 0, errors.New("js read data > length")
+	}
+	n = copy(p, data)
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
 func (j *jsFile) Close() error {
 	return j.file.Close()
 }
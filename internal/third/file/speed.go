@@ -0,0 +1,138 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package file
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+const (
+	// speedWindow is the time the instantaneous speed of a transfer is measured over
+	speedWindow = 2 * time.Second
+	// speedInterval is the least time between two speed reports of a transfer
+	speedInterval = 500 * time.Millisecond
+)
+
+// SpeedCallback is optionally implemented by an UploadFileCallback, called with the speed and the time left of
+// the upload.
+type SpeedCallback interface {
+	OnUploadSpeed(speed string)
+}
+
+type speedSample struct {
+	time        time.Time
+	transferred int64
+}
+
+// Speed measures the speed of a transfer and estimates the time it has left. The bytes transferred before it
+// was started, by an earlier session of a resumed transfer, don't count in the speeds.
+type Speed struct {
+	lock       sync.Mutex
+	start      speedSample
+	samples    []speedSample
+	reportTime time.Time
+	now        func() time.Time
+}
+
+// NewSpeed starts measuring a transfer with the bytes already transferred.
+func NewSpeed(transferred int64) *Speed {
+	return newSpeed(transferred, time.Now)
+}
+
+func newSpeed(transferred int64, now func() time.Time) *Speed {
+	start := speedSample{time: now(), transferred: transferred}
+	return &Speed{start: start, samples: []speedSample{start}, now: now}
+}
+
+// Update records the bytes transferred of the total, the total is 0 while unknown.
+func (s *Speed) Update(transferred, total int64) *sdk_struct.TransferSpeed {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.update(transferred, total)
+}
+
+// Report records the bytes transferred like Update, and tells whether speedInterval passed since the last
+// report. A forced report always passes.
+func (s *Speed) Report(transferred, total int64, force bool) (*sdk_struct.TransferSpeed, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	speed := s.update(transferred, total)
+	if !force && s.now().Sub(s.reportTime) < speedInterval {
+		return speed, false
+	}
+	s.reportTime = s.now()
+	return speed, true
+}
+
+func (s *Speed) update(transferred, total int64) *sdk_struct.TransferSpeed {
+	now := s.now()
+	s.samples = append(s.samples, speedSample{time: now, transferred: transferred})
+	// the oldest sample kept is the last one at least speedWindow old, the window is always covered
+	var drop int
+	for drop+1 < len(s.samples) && now.Sub(s.samples[drop+1].time) >= speedWindow {
+		drop++
+	}
+	s.samples = s.samples[drop:]
+	speed := &sdk_struct.TransferSpeed{
+		Transferred:   transferred,
+		Total:         total,
+		Speed:         rate(s.samples[0], s.samples[len(s.samples)-1]),
+		AverageSpeed:  rate(s.start, s.samples[len(s.samples)-1]),
+		RemainingTime: -1,
+	}
+	if total > 0 && transferred >= total {
+		speed.RemainingTime = 0
+		return speed
+	}
+	bytesPerSecond := speed.Speed
+	if bytesPerSecond <= 0 {
+		bytesPerSecond = speed.AverageSpeed
+	}
+	if total > 0 && bytesPerSecond > 0 {
+		speed.RemainingTime = (total - transferred) * 1000 / bytesPerSecond
+	}
+	return speed
+}
+
+// rate is the bytes per second transferred from one sample to the other.
+func rate(from, to speedSample) int64 {
+	elapsed := to.time.Sub(from.time)
+	if elapsed <= 0 || to.transferred <= from.transferred {
+		return 0
+	}
+	return int64(float64(to.transferred-from.transferred) / elapsed.Seconds())
+}
+
+// reportSpeed calls the SpeedCallback of an upload callback that implements it, at most once per speedInterval
+// until the upload is complete.
+func reportSpeed(cb UploadFileCallback, speed *Speed, transferred, total int64) {
+	speedCallback, ok := cb.(SpeedCallback)
+	if !ok {
+		return
+	}
+	info, due := speed.Report(transferred, total, transferred >= total)
+	if !due {
+		return
+	}
+	data, err := json.Marshal(info)
+	if err != nil {
+		return
+	}
+	speedCallback.OnUploadSpeed(string(data))
+}
@@ -0,0 +1,68 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package file
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSpeed(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	clock := func() time.Time { return now }
+	// 1000 bytes saved by an earlier session of the transfer
+	s := newSpeed(1000, clock)
+	for i := 1; i <= 4; i++ {
+		now = now.Add(time.Second)
+		s.Update(1000+int64(i)*100, 11000)
+	}
+	// the speed doubles for the last seconds
+	for i := 1; i <= 2; i++ {
+		now = now.Add(time.Second)
+		s.Update(1400+int64(i)*200, 11000)
+	}
+	speed := s.Update(1800, 11000)
+	if speed.Speed != 200 {
+		t.Fatalf("speed %d, expect 200", speed.Speed)
+	}
+	if expect := int64(800 / 6); speed.AverageSpeed != expect {
+		t.Fatalf("average speed %d, expect %d", speed.AverageSpeed, expect)
+	}
+	if speed.RemainingTime != (11000-1800)*1000/200 {
+		t.Fatalf("remaining time %d", speed.RemainingTime)
+	}
+	now = now.Add(time.Second)
+	if speed := s.Update(11000, 11000); speed.RemainingTime != 0 {
+		t.Fatalf("remaining time of a complete transfer %d", speed.RemainingTime)
+	}
+	if speed := newSpeed(0, clock).Update(0, 0); speed.RemainingTime != -1 {
+		t.Fatalf("remaining time of an unknown total %d", speed.RemainingTime)
+	}
+}
+
+func TestSpeedReport(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	s := newSpeed(0, func() time.Time { return now })
+	if _, due := s.Report(10, 100, false); !due {
+		t.Fatal("first report not due")
+	}
+	now = now.Add(speedInterval / 2)
+	if _, due := s.Report(20, 100, false); due {
+		t.Fatal("report due before the interval")
+	}
+	if _, due := s.Report(100, 100, true); !due {
+		t.Fatal("forced report not due")
+	}
+}
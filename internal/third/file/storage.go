@@ -0,0 +1,257 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package file
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/network"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+const (
+	defaultStorageRegion = "us-east-1"
+	// unsignedPayload signs the request without hashing the file first, the transfer is protected by tls
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+	amzDateFormat   = "20060102T150405Z"
+)
+
+// Storage is where the uploads are put instead of the object storage of the server, set by the app
+// embedding the sdk. An upload with its own ObjectStorage still goes to that bucket.
+type Storage interface {
+	// PutObject stores the size bytes of r by the name and returns the url the file is downloaded from.
+	PutObject(ctx context.Context, name, contentType string, r io.Reader, size int64) (string, error)
+}
+
+// CheckObjectStorage checks the storage an upload is put to instead of the one of the server.
+func CheckObjectStorage(storage *sdk_struct.ObjectStorage) error {
+	if storage == nil {
+		return nil
+	}
+	u, err := url.Parse(storage.Endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return sdkerrs.ErrArgs.WrapMsg("invalid storage endpoint " + storage.Endpoint)
+	}
+	if storage.Bucket == "" {
+		return sdkerrs.ErrArgs.WrapMsg("storage bucket is empty")
+	}
+	if storage.AccessKeyID == "" || storage.SecretAccessKey == "" {
+		return sdkerrs.ErrArgs.WrapMsg("storage credentials are empty")
+	}
+	return nil
+}
+
+// putObject uploads the file in one request to the bucket of the storage of the request, signed with its
+// credentials. The server is not asked for the hash, nothing is deduplicated.
+func (f *File) putObject(ctx context.Context, req *UploadFileReq, file ReadFile, fileSize int64, cb UploadFileCallback) (*UploadFileResp, error) {
+	storage := req.Storage
+	if err := CheckObjectStorage(storage); err != nil {
+		return nil, err
+	}
+	objectURL := storageObjectURL(storage, req.Name)
+	h := md5.New()
+	speed := NewSpeed(0)
+	body := &countReader{r: io.TeeReader(io.NewSectionReader(file, 0, fileSize), h), fn: func(n int64) {
+		reportSpeed(cb, speed, n, fileSize)
+		cb.UploadComplete(fileSize, n, n)
+	}}
+	ctx, cancel := network.RequestTimeout(ctx, constant.RequestClassUpload)
+	defer cancel()
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPut, objectURL.String(), network.ThrottleReader(ctx, constant.BandwidthUpload, body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.ContentLength = fileSize
+	if req.ContentType != "" {
+		httpReq.Header.Set("Content-Type", req.ContentType)
+	}
+	signV4(httpReq, storage, unsignedPayload, time.Now())
+	log.ZDebug(ctx, "put object", "url", objectURL.String(), "size", fileSize)
+	resp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, sdkerrs.ErrNetwork.WrapMsg(fmt.Sprintf("PUT %s failed, status code %d, body %s", objectURL.String(), resp.StatusCode, string(data)))
+	}
+	rawURL := objectURL.String()
+	if storage.URL != "" {
+		rawURL = strings.TrimSuffix(storage.URL, "/") + "/" + escapePath(req.Name)
+	}
+	cb.Complete(fileSize, rawURL, 1)
+	return &UploadFileResp{URL: rawURL, Md5: hex.EncodeToString(h.Sum(nil))}, nil
+}
+
+// putStorage uploads the file to the Storage set with SetStorage.
+func (f *File) putStorage(ctx context.Context, req *UploadFileReq, file ReadFile, fileSize int64, cb UploadFileCallback) (*UploadFileResp, error) {
+	h := md5.New()
+	speed := NewSpeed(0)
+	body := &countReader{r: io.TeeReader(io.NewSectionReader(file, 0, fileSize), h), fn: func(n int64) {
+		reportSpeed(cb, speed, n, fileSize)
+		cb.UploadComplete(fileSize, n, n)
+	}}
+	log.ZDebug(ctx, "put object to storage", "name", req.Name, "size", fileSize)
+	rawURL, err := f.storage.PutObject(ctx, req.Name, req.ContentType, network.ThrottleReader(ctx, constant.BandwidthUpload, body), fileSize)
+	if err != nil {
+		return nil, err
+	}
+	cb.Complete(fileSize, rawURL, 1)
+	return &UploadFileResp{URL: rawURL, Md5: hex.EncodeToString(h.Sum(nil))}, nil
+}
+
+// storageObjectURL is the url of the object in the bucket, in the host unless the storage is path style.
+func storageObjectURL(storage *sdk_struct.ObjectStorage, name string) *url.URL {
+	u, _ := url.Parse(storage.Endpoint)
+	base := strings.TrimSuffix(u.Path, "/")
+	if storage.PathStyle {
+		u.Path = base + "/" + storage.Bucket + "/" + name
+	} else {
+		u.Host = storage.Bucket + "." + u.Host
+		u.Path = base + "/" + name
+	}
+	// sent as signed, the url package would leave some of the characters aws escapes
+	u.RawPath = escapePath(u.Path)
+	u.RawQuery = ""
+	return u
+}
+
+// signV4 signs the request with the aws signature version 4, with all its headers and the host.
+func signV4(req *http.Request, storage *sdk_struct.ObjectStorage, payloadHash string, now time.Time) {
+	region := storage.Region
+	if region == "" {
+		region = defaultStorageRegion
+	}
+	amzDate := now.UTC().Format(amzDateFormat)
+	date := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if storage.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", storage.SessionToken)
+	}
+	headers := map[string]string{"host": req.URL.Host}
+	for key, values := range req.Header {
+		headers[strings.ToLower(key)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		escapePath(req.URL.Path),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := date + "/" + region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex(canonicalRequest)
+	key := hmacSHA256([]byte("AWS4"+storage.SecretAccessKey), date)
+	for _, part := range []string{region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		storage.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var pairs []string
+	for _, key := range keys {
+		values := append([]string(nil), query[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			pairs = append(pairs, escape(key)+"="+escape(value))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// escapePath escapes the segments of the path as aws does, the slashes are kept.
+func escapePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = escape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// escape encodes everything but the unreserved characters of rfc 3986.
+func escape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func sha256Hex(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
+
+// countReader reports the bytes read.
+type countReader struct {
+	r  io.Reader
+	n  int64
+	fn func(n int64)
+}
+
+func (c *countReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	if n > 0 {
+		c.n += int64(n)
+		c.fn(c.n)
+	}
+	return n, err
+}
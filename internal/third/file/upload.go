@@ -15,10 +15,12 @@
 package file
 
 import (
+	"bufio"
 	"context"
 	"crypto/md5"
 	"encoding/base64"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -30,12 +32,17 @@ import (
 	"time"
 
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/api"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/db_interface"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/network"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
 	"github.com/openimsdk/tools/errs"
 
 	"github.com/openimsdk/protocol/third"
-	"github.com/openimsdk/tools/log"
+	"golang.org/x/sync/errgroup"
 )
 
 type UploadFileReq struct {
@@ -44,12 +51,26 @@ type UploadFileReq struct {
 	ContentType string `json:"contentType"`
 	Cause       string `json:"cause"`
 	Uuid        string `json:"uuid"`
+	// Storage puts the file to this bucket instead of the storage of the server
+	Storage *sdk_struct.ObjectStorage `json:"storage,omitempty"`
 }
 
 type UploadFileResp struct {
 	URL string `json:"url"`
+	// Deduplicated tells the server had the object of the hash, nothing was transferred
+	Deduplicated bool `json:"deduplicated"`
+	// Md5 of the content of the file, the downloads of the url are verified with it
+	Md5 string `json:"md5"`
 }
 
+const (
+	DefaultUploadParallelism = 3
+	// uploadPartRetries is the number of times a failed part is put again
+	uploadPartRetries = 3
+	// partReadBufferSize is the buffer of the reads of a part being uploaded
+	partReadBufferSize = 256 * 1024
+)
+
 type partInfo struct {
 	ContentType string
 	PartSize    int64
@@ -71,6 +92,8 @@ type File struct {
 	partLimit   *third.PartLimitResp
 	mapLocker   sync.Locker
 	uploading   map[string]*lockInfo
+	parallelism int // number of parts of a file uploaded at the same time
+	storage     Storage
 }
 
 // SetDataBase sets the DataBase field in File struct
@@ -119,7 +142,7 @@ func (f *File) UploadFile(ctx context.Context, req *UploadFileReq, cb UploadFile
 		cb = emptyUploadCallback{}
 	}
 	if req.Name == "" {
-		return nil, errors.New("name is empty")
+		return nil, sdkerrs.ErrArgs.WrapMsg("name is empty")
 	}
 	if req.Name[0] == '/' {
 		req.Name = req.Name[1:]
@@ -134,9 +157,17 @@ func (f *File) UploadFile(ctx context.Context, req *UploadFileReq, cb UploadFile
 	defer file.Close()
 	fileSize := file.Size()
 	cb.Open(fileSize)
-	info, err := f.getPartInfo(ctx, file, fileSize, cb)
-	if err != nil {
-		return nil, err
+	if req.Storage != nil {
+		return f.putObject(ctx, req, file, fileSize, cb)
+	}
+	if f.storage != nil {
+		return f.putStorage(ctx, req, file, fileSize, cb)
+	}
+	info := f.storedPartInfo(ctx, file, fileSize, cb)
+	if info == nil {
+		if info, err = f.getPartInfo(ctx, file, fileSize, cb); err != nil {
+			return nil, err
+		}
 	}
 	if req.ContentType == "" {
 		req.ContentType = info.ContentType
@@ -145,9 +176,6 @@ func (f *File) UploadFile(ctx context.Context, req *UploadFileReq, cb UploadFile
 	partSizes := info.PartSizes
 	partMd5s := info.PartMd5s
 	partMd5Val := info.PartMd5
-	if err := file.StartSeek(0); err != nil {
-		return nil, err
-	}
 	f.lockHash(partMd5Val)
 	defer f.unlockHash(partMd5Val)
 	maxParts := 20
@@ -167,57 +195,50 @@ func (f *File) UploadFile(ctx context.Context, req *UploadFileReq, cb UploadFile
 		return nil, err
 	}
 	if uploadInfo.Resp.Upload == nil {
+		log.ZDebug(ctx, "upload deduplicated, the server has the object", "partHash", partMd5Val, "name", req.Name)
+		f.keepUploadedFile(ctx, file, info)
 		cb.Complete(fileSize, uploadInfo.Resp.Url, 0)
 		return &UploadFileResp{
-			URL: uploadInfo.Resp.Url,
+			URL:          uploadInfo.Resp.Url,
+			Deduplicated: true,
+			Md5:          info.FileMd5,
 		}, nil
 	}
 	if uploadInfo.Resp.Upload.PartSize != partSize {
 		f.cleanPartLimit()
 		return nil, fmt.Errorf("part fileSize not match, expect %d, got %d", partSize, uploadInfo.Resp.Upload.PartSize)
 	}
+	f.keepPartInfo(ctx, uploadInfo, file, info)
 	cb.UploadID(uploadInfo.Resp.Upload.UploadID)
 	uploadedSize := fileSize
+	offsets := make([]int64, len(partSizes))
+	var pending []int
 	for i := 0; i < len(partSizes); i++ {
+		if i > 0 {
+			offsets[i] = offsets[i-1] + partSizes[i-1]
+		}
 		if !uploadInfo.Bitmap.Get(i) {
 			uploadedSize -= partSizes[i]
+			pending = append(pending, i)
+		} else {
+			cb.UploadPartComplete(i, partSizes[i], partMd5s[i])
 		}
 	}
 	continueUpload := uploadedSize > 0
-	for i, currentPartSize := range partSizes {
-		partNumber := int32(i + 1)
-		md5Reader := NewMd5Reader(io.LimitReader(file, currentPartSize))
-		if uploadInfo.Bitmap.Get(i) {
-			if _, err := io.Copy(io.Discard, md5Reader); err != nil {
-				return nil, err
+	progress := &uploadProgress{cb: cb, fileSize: fileSize, uploaded: uploadedSize, sending: make(map[int]int64), speed: NewSpeed(uploadedSize)}
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(f.uploadParallelism())
+	for _, i := range pending {
+		g.Go(func() error {
+			if err := f.uploadPart(gctx, file, uploadInfo, progress, i, offsets[i], partSizes[i], partMd5s[i]); err != nil {
+				log.ZError(ctx, "upload part failed", err, "partMd5Val", partMd5Val, "name", req.Name, "partNumber", i+1)
+				return err
 			}
-		} else {
-			reader := NewProgressReader(md5Reader, func(current int64) {
-				cb.UploadComplete(fileSize, uploadedSize+current, uploadedSize)
-			})
-			urlval, header, err := uploadInfo.GetPartSign(ctx, partNumber)
-			if err != nil {
-				return nil, err
-			}
-			if err := f.doPut(ctx, http.DefaultClient, urlval, header, reader, currentPartSize); err != nil {
-				log.ZError(ctx, "doPut", err, "partMd5Val", partMd5Val, "name", req.Name, "partNumber", partNumber)
-				return nil, err
-			}
-			uploadedSize += currentPartSize
-			if uploadInfo.DBInfo != nil && uploadInfo.Bitmap != nil {
-				uploadInfo.Bitmap.Set(i)
-				uploadInfo.DBInfo.UploadInfo = base64.StdEncoding.EncodeToString(uploadInfo.Bitmap.Serialize())
-				if err := f.database.UpdateUpload(ctx, uploadInfo.DBInfo); err != nil {
-					log.ZError(ctx, "SetUploadPartPush", err, "partMd5Val", partMd5Val, "name", req.Name, "partNumber", partNumber)
-				}
-			}
-		}
-		md5val := md5Reader.Md5()
-		if md5val != partMd5s[i] {
-			return nil, fmt.Errorf("upload part %d failed, md5 not match, expect %s, got %s", i, partMd5s[i], md5val)
-		}
-		cb.UploadPartComplete(i, currentPartSize, partMd5s[i])
-		log.ZDebug(ctx, "upload part success", "partMd5Val", md5val, "name", req.Name, "partNumber", partNumber)
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
 	}
 	log.ZDebug(ctx, "upload all part success", "partHash", partMd5Val, "name", req.Name)
 	resp, err := f.completeMultipartUpload(ctx, &third.CompleteMultipartUploadReq{
@@ -240,11 +261,204 @@ func (f *File) UploadFile(ctx context.Context, req *UploadFileReq, cb UploadFile
 			log.ZError(ctx, "DeleteUpload", err, "partMd5Val", info.PartMd5, "name", req.Name)
 		}
 	}
+	f.keepUploadedFile(ctx, file, info)
 	return &UploadFileResp{
 		URL: resp.Url,
+		Md5: info.FileMd5,
 	}, nil
 }
 
+// storedPartInfo is the part info kept with the upload of the file, or once the file was uploaded, when the
+// file did not change since, the file is not hashed again.
+func (f *File) storedPartInfo(ctx context.Context, file ReadFile, fileSize int64, cb UploadFileCallback) *partInfo {
+	fp, ok := file.(fingerprinter)
+	if !ok {
+		return nil
+	}
+	var partHash, stored string
+	if dbUpload, err := f.database.GetUploadByFingerprint(ctx, fp.Fingerprint()); err == nil && dbUpload.PartInfo != "" {
+		partHash, stored = dbUpload.PartHash, dbUpload.PartInfo
+	} else if uploaded, err := f.database.GetUploadedFile(ctx, fp.Fingerprint()); err == nil {
+		partHash, stored = uploaded.PartHash, uploaded.PartInfo
+	} else {
+		return nil
+	}
+	var info partInfo
+	if err := json.Unmarshal([]byte(stored), &info); err != nil {
+		log.ZWarn(ctx, "parse upload part info failed", err, "partHash", partHash)
+		return nil
+	}
+	// the part size follows the part limit of the server, which may have changed
+	partSize, err := f.partSize(ctx, fileSize)
+	if err != nil || info.PartSize != partSize || len(info.PartSizes) != info.PartNum || len(info.PartMd5s) != info.PartNum {
+		return nil
+	}
+	log.ZDebug(ctx, "stored part info, the file is not hashed", "partHash", info.PartMd5, "partNum", info.PartNum)
+	cb.PartSize(info.PartSize, info.PartNum)
+	cb.HashPartComplete(info.PartMd5, info.FileMd5)
+	return &info
+}
+
+// keepPartInfo keeps the fingerprint and the part info of the file with its upload in the local database.
+func (f *File) keepPartInfo(ctx context.Context, uploadInfo *UploadInfo, file ReadFile, info *partInfo) {
+	fp, ok := file.(fingerprinter)
+	if !ok || uploadInfo.DBInfo == nil {
+		return
+	}
+	fingerprint := fp.Fingerprint()
+	if uploadInfo.DBInfo.Fingerprint == fingerprint && uploadInfo.DBInfo.PartInfo != "" {
+		return
+	}
+	data, err := json.Marshal(info)
+	if err != nil {
+		return
+	}
+	uploadInfo.DBInfo.Fingerprint = fingerprint
+	uploadInfo.DBInfo.PartInfo = string(data)
+	if err := f.database.UpdateUpload(ctx, uploadInfo.DBInfo); err != nil {
+		log.ZError(ctx, "UpdateUpload", err, "partHash", uploadInfo.DBInfo.PartHash)
+	}
+}
+
+// keepUploadedFile keeps the part info of the file uploaded, the server is asked for its hash when it is sent
+// again.
+func (f *File) keepUploadedFile(ctx context.Context, file ReadFile, info *partInfo) {
+	fp, ok := file.(fingerprinter)
+	if !ok {
+		return
+	}
+	data, err := json.Marshal(info)
+	if err != nil {
+		return
+	}
+	uploaded := &model_struct.LocalUploadedFile{
+		Fingerprint: fp.Fingerprint(),
+		PartHash:    info.PartMd5,
+		PartInfo:    string(data),
+		CreateTime:  time.Now().UnixMilli(),
+	}
+	if err := f.database.SetUploadedFile(ctx, uploaded); err != nil {
+		log.ZWarn(ctx, "SetUploadedFile", err, "partHash", info.PartMd5)
+	}
+}
+
+// uploadPart puts the part of the file at offset, retried up to uploadPartRetries times. The parts are read
+// at their offsets so that several of them are uploaded at the same time.
+func (f *File) uploadPart(ctx context.Context, file ReadFile, info *UploadInfo, progress *uploadProgress,
+	index int, offset int64, size int64, partMd5 string) error {
+	partNumber := int32(index + 1)
+	var err error
+	for attempt := 0; ; attempt++ {
+		var retry bool
+		if retry, err = f.putPart(ctx, file, info, progress, index, offset, size, partMd5); err == nil {
+			break
+		}
+		progress.reset(index)
+		if !retry || attempt >= uploadPartRetries || ctx.Err() != nil {
+			return err
+		}
+		log.ZWarn(ctx, "upload part failed, retry", err, "partNumber", partNumber, "attempt", attempt+1)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(time.Duration(attempt+1) * time.Second):
+		}
+	}
+	info.setUploaded(ctx, f.database, index)
+	progress.complete(index, size, partMd5)
+	log.ZDebug(ctx, "upload part success", "partMd5Val", partMd5, "partNumber", partNumber)
+	return nil
+}
+
+// putPart puts the part once, it is not retried when the file changed since its hash was computed.
+func (f *File) putPart(ctx context.Context, file ReadFile, info *UploadInfo, progress *uploadProgress,
+	index int, offset int64, size int64, partMd5 string) (bool, error) {
+	partNumber := int32(index + 1)
+	md5Reader := NewMd5Reader(bufio.NewReaderSize(io.NewSectionReader(file, offset, size), partReadBufferSize))
+	reader := NewProgressReader(md5Reader, func(current int64) {
+		progress.sent(index, current)
+	})
+	urlval, header, err := info.GetPartSign(ctx, partNumber)
+	if err != nil {
+		return true, err
+	}
+	if err := f.doPut(ctx, http.DefaultClient, urlval, header, reader, size); err != nil {
+		return true, err
+	}
+	if md5val := md5Reader.Md5(); md5val != partMd5 {
+		return false, fmt.Errorf("upload part %d failed, md5 not match, expect %s, got %s", index, partMd5, md5val)
+	}
+	return false, nil
+}
+
+// uploadProgress sums the bytes sent of the parts uploaded at the same time into the progress of the file.
+type uploadProgress struct {
+	lock     sync.Mutex
+	cb       UploadFileCallback
+	fileSize int64
+	uploaded int64         // bytes of the parts uploaded
+	sending  map[int]int64 // bytes sent of the parts being uploaded
+	speed    *Speed
+}
+
+func (p *uploadProgress) sent(index int, current int64) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.sending[index] = current
+	p.report()
+}
+
+// reset drops the bytes sent of a failed part, they are sent again.
+func (p *uploadProgress) reset(index int) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	delete(p.sending, index)
+}
+
+func (p *uploadProgress) complete(index int, size int64, partMd5 string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	delete(p.sending, index)
+	p.uploaded += size
+	p.cb.UploadPartComplete(index, size, partMd5)
+	p.report()
+}
+
+func (p *uploadProgress) report() {
+	stream := p.uploaded
+	for _, n := range p.sending {
+		stream += n
+	}
+	reportSpeed(p.cb, p.speed, stream, p.fileSize)
+	p.cb.UploadComplete(p.fileSize, stream, p.uploaded)
+}
+
+// CheckUploadParallelism checks the number of parts uploaded at the same time set in the config, 0 keeps
+// the default.
+func CheckUploadParallelism(parallelism int) error {
+	if parallelism < 0 {
+		return errs.ErrArgs.WrapMsg(fmt.Sprintf("invalid upload parallelism %d", parallelism))
+	}
+	return nil
+}
+
+// SetStorage sets the Storage the uploads are put to, nil puts them to the object storage of the server.
+func (f *File) SetStorage(storage Storage) {
+	f.storage = storage
+}
+
+// SetUploadParallelism sets the number of parts of a file uploaded at the same time, 0 keeps the default.
+func (f *File) SetUploadParallelism(parallelism int) {
+	f.parallelism = parallelism
+}
+
+func (f *File) uploadParallelism() int {
+	if f.parallelism <= 0 {
+		return DefaultUploadParallelism
+	}
+	return f.parallelism
+}
+
 func (f *File) cleanPartLimit() {
 	f.confLock.Lock()
 	defer f.confLock.Unlock()
@@ -289,10 +503,10 @@ func (f *File) partSize(ctx context.Context, size int64) (int64, error) {
 		}
 	}
 	if size <= 0 {
-		return 0, errors.New("size must be greater than 0")
+		return 0, sdkerrs.ErrArgs.WrapMsg("size must be greater than 0")
 	}
 	if size > f.partLimit.MaxPartSize*int64(f.partLimit.MaxNumSize) {
-		return 0, fmt.Errorf("size must be less than %db", f.partLimit.MaxPartSize*int64(f.partLimit.MaxNumSize))
+		return 0, sdkerrs.ErrArgs.WrapMsg(fmt.Sprintf("size must be less than %db", f.partLimit.MaxPartSize*int64(f.partLimit.MaxNumSize)))
 	}
 	if size <= f.partLimit.MinPartSize*int64(f.partLimit.MaxNumSize) {
 		return f.partLimit.MinPartSize, nil
@@ -340,6 +554,7 @@ type UploadInfo struct {
 	CreateTime   time.Time
 	BatchSignNum int32
 	f            *File
+	lock         sync.Mutex // the parts uploaded at the same time share the signs and the bitmap
 }
 
 func (u *UploadInfo) getIndex(partNumber int32) int {
@@ -401,6 +616,8 @@ func (u *UploadInfo) GetPartSign(ctx context.Context, partNumber int32) (*url.UR
 	if partNumber < 1 || int(partNumber) > u.PartNum {
 		return nil, nil, errors.New("invalid partNumber")
 	}
+	u.lock.Lock()
+	defer u.lock.Unlock()
 	if index := u.getIndex(partNumber); index >= 0 {
 		return u.buildRequest(index)
 	}
@@ -430,6 +647,20 @@ func (u *UploadInfo) GetPartSign(ctx context.Context, partNumber int32) (*url.UR
 	return u.buildRequest(index)
 }
 
+// setUploaded marks the part uploaded in the upload kept in the local database.
+func (u *UploadInfo) setUploaded(ctx context.Context, database db_interface.DataBase, index int) {
+	if u.DBInfo == nil || u.Bitmap == nil {
+		return
+	}
+	u.lock.Lock()
+	defer u.lock.Unlock()
+	u.Bitmap.Set(index)
+	u.DBInfo.UploadInfo = base64.StdEncoding.EncodeToString(u.Bitmap.Serialize())
+	if err := database.UpdateUpload(ctx, u.DBInfo); err != nil {
+		log.ZError(ctx, "SetUploadPartPush", err, "partHash", u.DBInfo.PartHash, "partNumber", index+1)
+	}
+}
+
 func (f *File) getLocalUploadInfo(ctx context.Context, req *third.InitiateMultipartUploadReq) (info *UploadInfo) {
 	partNum := f.getPartNum(req.Size, req.PartSize)
 	if partNum <= 1 {
@@ -514,8 +745,10 @@ func (f *File) getUpload(ctx context.Context, req *third.InitiateMultipartUpload
 }
 
 func (f *File) doPut(ctx context.Context, client *http.Client, url *url.URL, header http.Header, reader io.Reader, size int64) error {
+	ctx, cancel := network.RequestTimeout(ctx, constant.RequestClassUpload)
+	defer cancel()
 	rawURL := url.String()
-	req, err := http.NewRequestWithContext(ctx, http.MethodPut, rawURL, reader)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, rawURL, network.ThrottleReader(ctx, constant.BandwidthUpload, reader))
 	if err != nil {
 		return err
 	}
@@ -538,7 +771,7 @@ func (f *File) doPut(ctx context.Context, client *http.Client, url *url.URL, hea
 	}
 	log.ZDebug(ctx, "do put resp body", "url", rawURL, "body", string(body))
 	if resp.StatusCode/200 != 1 {
-		return fmt.Errorf("PUT %s failed, status code %d, body %s", rawURL, resp.StatusCode, string(body))
+		return sdkerrs.ErrNetwork.WrapMsg(fmt.Sprintf("PUT %s failed, status code %d, body %s", rawURL, resp.StatusCode, string(body)))
 	}
 	return nil
 }
@@ -563,6 +796,9 @@ func (f *File) getPartInfo(ctx context.Context, r io.Reader, fileSize int64, cb
 	fileMd5 := md5.New()
 	var contentType string
 	for i := 0; i < partNum; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		h := md5.New()
 		r := io.LimitReader(r, partSize)
 		for {
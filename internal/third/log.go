@@ -8,6 +8,7 @@ import (
 	"math/rand"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -16,15 +17,17 @@ import (
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/api"
 
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/ccontext"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
 	"github.com/openimsdk/openim-sdk-core/v3/version"
 	"github.com/openimsdk/protocol/constant"
 	"github.com/openimsdk/protocol/third"
 	"github.com/openimsdk/tools/errs"
-	"github.com/openimsdk/tools/log"
 )
 
 const (
 	buffer = 10 * 1024 * 1024
+	// logPrefix is the prefix of the names of the log files of the SDK
+	logPrefix = "open-im-sdk-core"
 )
 
 func (c *Third) uploadLogs(ctx context.Context, line int, ex string, progress Progress) (err error) {
@@ -42,39 +45,56 @@ func (c *Third) uploadLogs(ctx context.Context, line int, ex string, progress Pr
 	if err != nil {
 		return err
 	}
-	files := make([]string, 0, len(entrys))
+	names := make([]string, 0, len(entrys))
+	for _, entry := range entrys {
+		if (!entry.IsDir()) && checkLogPath(entry.Name()) {
+			names = append(names, entry.Name())
+		}
+	}
+	sortLogFiles(names)
+	files := make([]string, 0, len(names))
 	switch line {
 	case 0:
 		// all logs
-		for _, entry := range entrys {
-			if (!entry.IsDir()) && (!strings.HasSuffix(entry.Name(), ".zip")) && checkLogPath(entry.Name()) {
-				files = append(files, filepath.Join(logFilePath, entry.Name()))
-			}
+		for _, name := range names {
+			files = append(files, filepath.Join(logFilePath, name))
 		}
 		if len(files) == 0 {
 			return errs.New("not found log file").Wrap()
 		}
 		defer func() {
 			if err == nil {
+				last := files[len(files)-1]
+				if _, part, _ := log.ParseFileName(logPrefix, filepath.Base(last)); part > 0 {
+					// no file is being written, the compressed parts are all removed
+					last = ""
+				}
 				// remove old file
-				for _, f := range files[:len(files)-1] {
+				for _, f := range files {
+					if f == last {
+						continue
+					}
 					if err := os.Remove(f); err != nil {
 						log.ZError(ctx, "remove file failed", err, "file name", f)
 					}
 				}
+				if last == "" {
+					return
+				}
 				// truncate now log file
-				f, err := os.OpenFile(files[len(files)-1], os.O_WRONLY|os.O_TRUNC, 0644)
+				f, err := os.OpenFile(last, os.O_WRONLY|os.O_TRUNC, 0644)
 				if err != nil {
-					log.ZError(ctx, "remove file failed", err, "file name", f)
+					log.ZError(ctx, "remove file failed", err, "file name", last)
+					return
 				}
 				_ = f.Close()
 			}
 		}()
 	default:
-		for i := len(entrys) - 1; i >= 0; i-- {
-			// get newest log file
-			if (!entrys[i].IsDir()) && (!strings.HasSuffix(entrys[i].Name(), ".zip")) && checkLogPath(entrys[i].Name()) {
-				files = append(files, filepath.Join(logFilePath, entrys[i].Name()))
+		for i := len(names) - 1; i >= 0; i-- {
+			// get newest log file, the one being written
+			if _, part, _ := log.ParseFileName(logPrefix, names[i]); part == 0 {
+				files = append(files, filepath.Join(logFilePath, names[i]))
 				break
 			}
 		}
@@ -106,6 +126,11 @@ func (c *Third) uploadLogs(ctx context.Context, line int, ex string, progress Pr
 	if err := zipFiles(zippath, files); err != nil {
 		return err
 	}
+	return c.uploadLogZip(ctx, zippath, ex, progress)
+}
+
+// uploadLogZip uploads the archive of the logs and tells the server its url.
+func (c *Third) uploadLogZip(ctx context.Context, zippath string, ex string, progress Progress) error {
 	reqUpload := &file.UploadFileReq{Filepath: zippath, Name: fmt.Sprintf("sdk_log_%s_%s_%s_%s_%s",
 		c.loginUserID, c.appFramework, constant.PlatformID2Name[int(c.platform)], version.Version, filepath.Base(zippath)), Cause: "sdklog", ContentType: "application/zip"}
 	resp, err := c.fileUploader.UploadFile(ctx, reqUpload, &progressConvert{ctx: ctx, p: progress})
@@ -124,18 +149,24 @@ func (c *Third) uploadLogs(ctx context.Context, line int, ex string, progress Pr
 }
 
 func checkLogPath(logPath string) bool {
-	if len(logPath) < len("open-im-sdk-core.yyyy-mm-dd") {
-		return false
-	}
-	logTime := logPath[len(logPath)-len(".yyyy-mm-dd"):]
-	if _, err := time.Parse(".2006-01-02", logTime); err != nil {
-		return false
-	}
-	if !strings.HasPrefix(logPath, "open-im-sdk-core.") {
-		return false
-	}
+	_, _, ok := log.ParseFileName(logPrefix, logPath)
+	return ok
+}
 
-	return true
+// sortLogFiles sorts the names of the log files from the oldest to the newest, the file being written of a day
+// comes after the parts rotated of the day.
+func sortLogFiles(names []string) {
+	sort.Slice(names, func(i, j int) bool {
+		di, pi, _ := log.ParseFileName(logPrefix, names[i])
+		dj, pj, _ := log.ParseFileName(logPrefix, names[j])
+		if !di.Equal(dj) {
+			return di.Before(dj)
+		}
+		if pi == 0 || pj == 0 {
+			return pj == 0 && pi != 0
+		}
+		return pi < pj
+	})
 }
 
 func (c *Third) fileCopy(src, dst string) error {
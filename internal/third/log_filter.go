@@ -0,0 +1,245 @@
+package third
+
+import (
+	"archive/zip"
+	"bufio"
+	"compress/flate"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+	"github.com/openimsdk/tools/errs"
+)
+
+const (
+	// logTimeLayout is the layout of the time each entry of the log files starts with
+	logTimeLayout = "2006-01-02 15:04:05.000"
+	// pendingLogPrefix names the archive of the filtered logs kept until it is uploaded, an upload of the same
+	// filter interrupted before resumes with it
+	pendingLogPrefix = "sdk_log_pending_"
+	// pendingLogTTL is how long an archive not uploaded is resumed, an older one is built again
+	pendingLogTTL = 24 * time.Hour
+)
+
+var (
+	logLevels = map[string]int{"debug": 0, "info": 1, "warn": 2, "error": 3, "dpanic": 4, "panic": 5, "fatal": 6}
+	// logColor is the color of the level written to the log files
+	logColor = regexp.MustCompile("\x1b\\[[0-9;]*m")
+)
+
+// logFilter selects the entries of the log files. An entry spans its first line, which starts with its time,
+// and the lines after it, e.g. a stack trace.
+type logFilter struct {
+	start   time.Time
+	end     time.Time
+	level   int
+	modules []string
+}
+
+func newLogFilter(filter *sdk_struct.LogFilter) (*logFilter, error) {
+	f := &logFilter{modules: filter.Modules}
+	if filter.StartTime > 0 {
+		f.start = time.UnixMilli(filter.StartTime)
+	}
+	if filter.EndTime > 0 {
+		f.end = time.UnixMilli(filter.EndTime)
+	}
+	if !f.start.IsZero() && !f.end.IsZero() && f.end.Before(f.start) {
+		return nil, sdkerrs.ErrArgs.WrapMsg("log filter ends before it starts")
+	}
+	if filter.Level != "" {
+		level, ok := logLevels[strings.ToLower(filter.Level)]
+		if !ok {
+			return nil, sdkerrs.ErrArgs.WrapMsg("invalid log level " + filter.Level)
+		}
+		f.level = level
+	}
+	return f, nil
+}
+
+// matchFile tells whether the log file of a day may hold entries within the time of the filter.
+func (f *logFilter) matchFile(name string) bool {
+	day, _, ok := log.ParseFileName(logPrefix, name)
+	if !ok {
+		return false
+	}
+	if !f.start.IsZero() && !day.AddDate(0, 0, 1).After(f.start) {
+		return false
+	}
+	return f.end.IsZero() || !day.After(f.end)
+}
+
+// entry tells whether the entry the line starts passes the filter, ok is false for a line within an entry.
+func (f *logFilter) entry(line string) (match, ok bool) {
+	if len(line) < len(logTimeLayout) {
+		return false, false
+	}
+	t, err := time.ParseInLocation(logTimeLayout, line[:len(logTimeLayout)], time.Local)
+	if err != nil {
+		return false, false
+	}
+	if (!f.start.IsZero() && t.Before(f.start)) || (!f.end.IsZero() && t.After(f.end)) {
+		return false, true
+	}
+	fields := strings.Split(line, "\t")
+	if f.level > 0 && len(fields) > 1 {
+		if level, ok := logLevels[strings.ToLower(strings.TrimSpace(logColor.ReplaceAllString(fields[1], "")))]; ok && level < f.level {
+			return false, true
+		}
+	}
+	if len(f.modules) == 0 {
+		return true, true
+	}
+	module := logModule(fields)
+	for _, m := range f.modules {
+		if m == module {
+			return true, true
+		}
+	}
+	return false, true
+}
+
+// logModule is the package of the caller of an entry, the last directory of its file.
+func logModule(fields []string) string {
+	for _, field := range fields[1:] {
+		field = strings.TrimSpace(field)
+		if !strings.HasPrefix(field, "[") || !strings.Contains(field, ".go:") {
+			continue
+		}
+		return path.Base(path.Dir(strings.Trim(field, "[]")))
+	}
+	return ""
+}
+
+// zipFiltered writes the entries of the files that pass the filter to the archive, one file each holding
+// some. Returns the number of entries written.
+func (f *logFilter) zipFiltered(zipPath string, files []string) (int, error) {
+	zipFile, err := os.Create(zipPath)
+	if err != nil {
+		return 0, err
+	}
+	defer zipFile.Close()
+	zipWriter := zip.NewWriter(zipFile)
+	zipWriter.RegisterCompressor(zip.Deflate, func(w io.Writer) (io.WriteCloser, error) {
+		return flate.NewWriter(w, flate.BestCompression)
+	})
+	var entries int
+	for _, file := range files {
+		n, err := f.zipFile(zipWriter, file)
+		if err != nil {
+			return 0, err
+		}
+		entries += n
+	}
+	if err := zipWriter.Close(); err != nil {
+		return 0, err
+	}
+	return entries, zipFile.Close()
+}
+
+func (f *logFilter) zipFile(zipWriter *zip.Writer, filename string) (int, error) {
+	file, err := log.OpenFile(filename)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), buffer)
+	var (
+		writer  io.Writer
+		keep    bool
+		entries int
+	)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if match, ok := f.entry(line); ok {
+			keep = match
+			if match {
+				entries++
+			}
+		}
+		if !keep {
+			continue
+		}
+		if writer == nil {
+			header := &zip.FileHeader{Name: strings.TrimSuffix(filepath.Base(filename), ".gz"), Method: zip.Deflate, Modified: time.Now()}
+			if writer, err = zipWriter.CreateHeader(header); err != nil {
+				return 0, err
+			}
+		}
+		if _, err := io.WriteString(writer, line+"\n"); err != nil {
+			return 0, err
+		}
+	}
+	return entries, scanner.Err()
+}
+
+// UploadFilteredLogs uploads the entries of the log files that pass the filter, compressed. An upload
+// interrupted resumes when the same filter is uploaded again.
+func (c *Third) UploadFilteredLogs(ctx context.Context, filter *sdk_struct.LogFilter, ex string, progress Progress) error {
+	if c.logUploadLock.TryLock() {
+		defer c.logUploadLock.Unlock()
+	} else {
+		return errs.New("log file is uploading").Wrap()
+	}
+	f, err := newLogFilter(filter)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(filter)
+	if err != nil {
+		return err
+	}
+	logFilePath := c.LogFilePath
+	entrys, err := os.ReadDir(logFilePath)
+	if err != nil {
+		return err
+	}
+	zipPath := filepath.Join(logFilePath, pendingLogPrefix+utils.Md5(string(data))+".zip")
+	var files []string
+	for _, entry := range entrys {
+		name := entry.Name()
+		if strings.HasPrefix(name, pendingLogPrefix) && filepath.Join(logFilePath, name) != zipPath {
+			// the archive of another filter is not resumed
+			_ = os.Remove(filepath.Join(logFilePath, name))
+			continue
+		}
+		if !entry.IsDir() && checkLogPath(name) && f.matchFile(name) {
+			files = append(files, filepath.Join(logFilePath, name))
+		}
+	}
+	if stat, err := os.Stat(zipPath); err == nil && time.Since(stat.ModTime()) < pendingLogTTL {
+		log.ZInfo(ctx, "resume the upload of the filtered logs", "zipPath", zipPath, "size", stat.Size())
+	} else {
+		if len(files) == 0 {
+			return errs.New("not found log file").Wrap()
+		}
+		entries, err := f.zipFiltered(zipPath, files)
+		if err != nil {
+			_ = os.Remove(zipPath)
+			return err
+		}
+		if entries == 0 {
+			_ = os.Remove(zipPath)
+			return errs.New("no log entry matches the filter").Wrap()
+		}
+		log.ZInfo(ctx, "logs filtered", "files", len(files), "entries", entries, "filter", filter)
+	}
+	if err := c.uploadLogZip(ctx, zipPath, ex, progress); err != nil {
+		return err
+	}
+	if err := os.Remove(zipPath); err != nil {
+		log.ZWarn(ctx, "remove uploaded logs failed", err, "zipPath", zipPath)
+	}
+	return nil
+}
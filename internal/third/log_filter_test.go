@@ -0,0 +1,74 @@
+package third
+
+import (
+	"archive/zip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+func TestLogFilter(t *testing.T) {
+	day := time.Date(2024, 5, 6, 0, 0, 0, 0, time.Local)
+	entry := func(hour int, level, caller, msg string) string {
+		return day.Add(time.Duration(hour)*time.Hour).Format(logTimeLayout) + "\t\x1b[31m" + level + "\x1b[0m\t\x1b[31m[PID:1]        \x1b[0m\t[v3.8]    \t[Go/Linux]    \t[" + caller + "]    \t" + msg
+	}
+	lines := []string{
+		entry(1, "ERROR", "conversation_msg/api.go:10", "too early"),
+		entry(9, "INFO", "conversation_msg/api.go:11", "not an error"),
+		entry(10, "ERROR", "conversation_msg/api.go:12", "kept"),
+		"goroutine 1 [running]:",
+		entry(11, "ERROR", "interaction/long_conn.go:13", "other module"),
+		"stack of the other module",
+		entry(12, "WARN", "conversation_msg/sync.go:14", "kept too"),
+	}
+	dir := t.TempDir()
+	logFile := filepath.Join(dir, "open-im-sdk-core.2024-05-06")
+	if err := os.WriteFile(logFile, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	f, err := newLogFilter(&sdk_struct.LogFilter{
+		StartTime: day.Add(8 * time.Hour).UnixMilli(),
+		Level:     "warn",
+		Modules:   []string{"conversation_msg"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !f.matchFile(filepath.Base(logFile)) || f.matchFile("open-im-sdk-core.2024-05-05") {
+		t.Fatal("log files matched by day")
+	}
+	zipPath := filepath.Join(dir, "logs.zip")
+	entries, err := f.zipFiltered(zipPath, []string{logFile})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entries != 2 {
+		t.Fatal("entries", entries)
+	}
+	reader, err := zip.OpenReader(zipPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+	if len(reader.File) != 1 {
+		t.Fatal("files", len(reader.File))
+	}
+	rc, err := reader.File[0].Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(rc)
+	rc.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := strings.Join([]string{lines[2], lines[3], lines[6]}, "\n") + "\n"
+	if string(data) != expected {
+		t.Fatalf("filtered logs\n%s", data)
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package third
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/api"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/server_api_params"
+)
+
+const (
+	pushRetryTimes    = 3
+	pushRetryInterval = time.Second
+	// badgeReportDelay gathers the unread count changes of a burst of messages into one report
+	badgeReportDelay = time.Second
+)
+
+// pushState is the offline push token and the app badge the server has to know. What failed to reach the
+// server is sent again after the next connection.
+type pushState struct {
+	lock sync.Mutex
+
+	token         *server_api_params.SetOfflinePushTokenReq
+	badge         int32
+	badgePending  bool
+	badgeReported bool
+	badgeTimer    *time.Timer
+}
+
+// SetOfflinePushToken registers the device token of the push provider, platform 0 is the login platform.
+// The token is registered again after each connection, so it survives the server losing it.
+func (c *Third) SetOfflinePushToken(ctx context.Context, platform int32, token, provider string) error {
+	switch provider {
+	case constant.PushProviderFCM, constant.PushProviderAPNs, constant.PushProviderGeTui, constant.PushProviderJPush:
+	default:
+		return sdkerrs.ErrArgs.WrapMsg("unsupported push provider " + provider)
+	}
+	if token == "" {
+		return sdkerrs.ErrArgs.WrapMsg("push token is empty")
+	}
+	if platform == 0 {
+		platform = c.platform
+	}
+	req := &server_api_params.SetOfflinePushTokenReq{
+		PlatformID: platform,
+		Token:      token,
+		Provider:   provider,
+	}
+	c.push.lock.Lock()
+	c.push.token = req
+	c.push.lock.Unlock()
+	return c.registerPushToken(ctx)
+}
+
+func (c *Third) registerPushToken(ctx context.Context) error {
+	c.push.lock.Lock()
+	req := c.push.token
+	c.push.lock.Unlock()
+	if req == nil {
+		return nil
+	}
+	send := *req
+	send.Account = c.loginUserID
+	return retryPush(ctx, func() error {
+		return api.SetOfflinePushToken.Execute(ctx, &send)
+	})
+}
+
+// ReportBadge reports the unread count as the app badge a moment later, the last count of a burst is sent.
+func (c *Third) ReportBadge(ctx context.Context, appUnreadCount int32) {
+	c.push.lock.Lock()
+	defer c.push.lock.Unlock()
+	if c.push.badgeReported && !c.push.badgePending && c.push.badge == appUnreadCount {
+		return
+	}
+	c.push.badge = appUnreadCount
+	c.push.badgePending = true
+	if c.push.badgeTimer != nil {
+		return
+	}
+	c.push.badgeTimer = time.AfterFunc(badgeReportDelay, func() {
+		c.push.lock.Lock()
+		c.push.badgeTimer = nil
+		c.push.lock.Unlock()
+		if err := c.sendBadge(context.WithoutCancel(ctx)); err != nil {
+			log.ZWarn(ctx, "report app badge failed, report again after reconnecting", err)
+		}
+	})
+}
+
+func (c *Third) sendBadge(ctx context.Context) error {
+	c.push.lock.Lock()
+	badge := c.push.badge
+	c.push.lock.Unlock()
+	err := retryPush(ctx, func() error {
+		return c.SetAppBadge(ctx, badge)
+	})
+	c.push.lock.Lock()
+	defer c.push.lock.Unlock()
+	if err == nil {
+		c.push.badgeReported = true
+		if c.push.badge == badge {
+			c.push.badgePending = false
+		}
+	}
+	return err
+}
+
+// OnConnected registers the push token again after each connection, as the server may have dropped it while
+// the device was offline, and reports the badge that failed to reach the server.
+func (c *Third) OnConnected(ctx context.Context) {
+	c.push.lock.Lock()
+	hasToken := c.push.token != nil
+	badgePending := c.push.badgePending && c.push.badgeTimer == nil
+	c.push.lock.Unlock()
+	if hasToken {
+		if err := c.registerPushToken(ctx); err != nil {
+			log.ZWarn(ctx, "register push token after connecting failed", err)
+		}
+	}
+	if badgePending {
+		if err := c.sendBadge(ctx); err != nil {
+			log.ZWarn(ctx, "report app badge after connecting failed", err)
+		}
+	}
+}
+
+func retryPush(ctx context.Context, fn func() error) error {
+	var err error
+	interval := pushRetryInterval
+	for i := 0; i < pushRetryTimes; i++ {
+		if i > 0 {
+			select {
+			case <-time.After(interval):
+			case <-ctx.Done():
+				return err
+			}
+			interval *= 2
+		}
+		if err = fn(); err == nil {
+			return nil
+		}
+	}
+	return err
+}
@@ -27,6 +27,7 @@ type Third struct {
 	LogFilePath   string
 	fileUploader  *file.File
 	logUploadLock sync.Mutex
+	push          pushState
 }
 
 func (t *Third) SetPlatform(platform int32) {
@@ -2,21 +2,24 @@ package user
 
 import (
 	"context"
+	"time"
 
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/cliconf"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/common"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
 	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
 	"github.com/openimsdk/protocol/sdkws"
 	userPb "github.com/openimsdk/protocol/user"
-	"github.com/openimsdk/tools/log"
 	"github.com/openimsdk/tools/utils/datautil"
 )
 
 func (u *User) UserOnlineStatusChange(users map[string][]int32) {
+	now := time.Now().UnixMilli()
 	for userID, onlinePlatformIDs := range users {
+		u.lastSeen.update(userID, onlinePlatformIDs, now)
 		status := userPb.OnlineStatus{
 			UserID:      userID,
 			PlatformIDs: onlinePlatformIDs,
@@ -37,6 +40,9 @@ func (u *User) GetSelfUserInfo(ctx context.Context) (*model_struct.LocalUser, er
 func (u *User) SetSelfInfo(ctx context.Context, userInfo *sdkws.UserInfoWithEx) error {
 	// updateSelfUserInfo updates the user's information with Ex field.
 	userInfo.UserID = u.loginUserID
+	if userInfo.Ex != nil {
+		userInfo.Ex.Value = u.keepPublishedPrivacy(ctx, userInfo.Ex.Value)
+	}
 	if err := u.updateUserInfo(ctx, userInfo); err != nil {
 		return err
 	}
@@ -46,6 +52,9 @@ func (u *User) SetSelfInfo(ctx context.Context, userInfo *sdkws.UserInfoWithEx)
 	}
 	return nil
 }
+
+// GetUsersInfo returns the public profiles of the users. The face url and the ex field of a user whose privacy
+// settings hide the profile from the login user are left out.
 func (u *User) GetUsersInfo(ctx context.Context, userIDs []string) ([]*sdk_struct.PublicUser, error) {
 	usersInfo, err := u.GetUsersInfoWithCache(ctx, userIDs)
 	if err != nil {
@@ -53,6 +62,15 @@ func (u *User) GetUsersInfo(ctx context.Context, userIDs []string) ([]*sdk_struc
 	}
 
 	res := datautil.Batch(LocalUserToPublicUser, usersInfo)
+	hidden, err := u.profileHiddenUsers(ctx, usersInfo)
+	if err != nil {
+		return nil, err
+	}
+	for _, userInfo := range res {
+		if _, ok := hidden[userInfo.UserID]; ok {
+			userInfo.FaceURL, userInfo.Ex = "", ""
+		}
+	}
 
 	friendList, err := u.GetFriendInfoList(ctx, userIDs)
 	if err != nil {
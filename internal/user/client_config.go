@@ -0,0 +1,49 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package user
+
+import (
+	"context"
+
+	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/cliconf"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+)
+
+func (u *User) SetClientConfigListener(listener func() open_im_sdk_callback.OnClientConfigListener) {
+	u.clientConfigListener = listener
+}
+
+// InitClientConfig loads the client config cached in the database, it is used until the server is reached, and
+// reports the changes of the config to the listener. It is called at login, after the database is set.
+func (u *User) InitClientConfig(ctx context.Context) {
+	cliconf.SetCache(ctx, u.DataBase)
+	cliconf.SetChangedListener(ctx, func(ctx context.Context, config *cliconf.ClientConfig) {
+		u.clientConfigListener().OnClientConfigChanged(utils.StructToJsonString(config))
+	})
+}
+
+// GetClientConfig gets the client config defined by the server, the last one known when the server can't be
+// reached.
+func (u *User) GetClientConfig(ctx context.Context) (*cliconf.ClientConfig, error) {
+	return cliconf.GetClientConfig(ctx)
+}
+
+// SyncClientConfig fetches the client config from the server again, at login and when the server pushed that it
+// changed.
+func (u *User) SyncClientConfig(ctx context.Context) error {
+	_, err := cliconf.RefreshClientConfig(ctx)
+	return err
+}
@@ -0,0 +1,78 @@
+package user
+
+import (
+	"context"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/exprofile"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/protocol/sdkws"
+	"github.com/openimsdk/protocol/wrapperspb"
+)
+
+// RegisterExProfileSchema registers the typed fields the app keeps in the user ex field, replacing the
+// schema registered before for this user context.
+func (u *User) RegisterExProfileSchema(_ context.Context, fields []*exprofile.Field) error {
+	schema, err := exprofile.NewSchema(fields)
+	if err != nil {
+		return err
+	}
+	u.exProfileSchema.Store(schema)
+	return nil
+}
+
+// getExProfileSchema returns the registered schema, an empty schema when nothing is registered.
+func (u *User) getExProfileSchema() *exprofile.Schema {
+	if schema := u.exProfileSchema.Load(); schema != nil {
+		return schema
+	}
+	return &exprofile.Schema{}
+}
+
+// GetUsersExProfile returns the decoded ex field of the users, keyed by user ID. The profile of a user whose
+// privacy settings hide it from the login user is empty.
+func (u *User) GetUsersExProfile(ctx context.Context, userIDs []string) (map[string]exprofile.Profile, error) {
+	users, err := u.GetUsersInfoWithCache(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	hidden, err := u.profileHiddenUsers(ctx, users)
+	if err != nil {
+		return nil, err
+	}
+	schema := u.getExProfileSchema()
+	res := make(map[string]exprofile.Profile, len(users))
+	for _, user := range users {
+		if _, ok := hidden[user.UserID]; ok {
+			res[user.UserID] = exprofile.Profile{}
+			continue
+		}
+		profile, err := schema.Decode(user.Ex)
+		if err != nil {
+			log.ZWarn(ctx, "decode user ex profile failed", err, "userID", user.UserID, "ex", user.Ex)
+			profile = exprofile.Profile{}
+		}
+		res[user.UserID] = profile
+	}
+	return res, nil
+}
+
+// UpdateSelfExProfile changes only the given keys of the login user's ex field, a null value removes the key.
+// The ex field is read from the server before merging so updates from other devices are not overwritten.
+func (u *User) UpdateSelfExProfile(ctx context.Context, fields map[string]any) error {
+	if len(fields) == 0 {
+		return sdkerrs.ErrArgs.WrapMsg("ex profile fields is empty")
+	}
+	users, err := u.getUsersInfo(ctx, []string{u.loginUserID})
+	if err != nil {
+		return err
+	}
+	if len(users) == 0 {
+		return sdkerrs.ErrUserIDNotFound.WrapMsg(u.loginUserID)
+	}
+	ex, err := u.getExProfileSchema().Merge(users[0].Ex, fields)
+	if err != nil {
+		return err
+	}
+	return u.SetSelfInfo(ctx, &sdkws.UserInfoWithEx{Ex: &wrapperspb.StringValue{Value: ex}})
+}
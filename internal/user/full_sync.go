@@ -4,8 +4,8 @@ import (
 	"context"
 
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
 	"github.com/openimsdk/tools/errs"
-	"github.com/openimsdk/tools/log"
 )
 
 func (u *User) SyncLoginUserInfo(ctx context.Context) error {
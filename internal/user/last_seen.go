@@ -0,0 +1,177 @@
+package user
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+	"github.com/openimsdk/tools/utils/datautil"
+)
+
+var defaultLastSeenPrecision = []int64{60, 5 * 60, 15 * 60, 30 * 60, 60 * 60, 3 * 60 * 60, 12 * 60 * 60, 24 * 60 * 60, 7 * 24 * 60 * 60}
+
+type lastSeenState struct {
+	online bool
+	// time is when the user was seen online or went offline, 0 when the user has not been seen online
+	time int64
+}
+
+// lastSeenCache keeps the last seen time of the subscribed users observed by this sdk instance.
+type lastSeenCache struct {
+	lock      sync.Mutex
+	users     map[string]*lastSeenState
+	precision []int64
+}
+
+func newLastSeenCache() *lastSeenCache {
+	return &lastSeenCache{users: make(map[string]*lastSeenState), precision: defaultLastSeenPrecision}
+}
+
+func (c *lastSeenCache) setPrecision(precision []int64) {
+	precision = datautil.Filter(precision, func(e int64) (int64, bool) { return e, e > 0 })
+	if len(precision) == 0 {
+		return
+	}
+	slices.Sort(precision)
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.precision = slices.Compact(precision)
+}
+
+func (c *lastSeenCache) update(userID string, platformIDs []int32, now int64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if len(platformIDs) > 0 {
+		c.users[userID] = &lastSeenState{online: true, time: now}
+		return
+	}
+	state, ok := c.users[userID]
+	switch {
+	case !ok:
+		c.users[userID] = &lastSeenState{}
+	case state.online:
+		state.online = false
+		state.time = now
+	}
+}
+
+func (c *lastSeenCache) get(userID string, now int64) *sdk_struct.UserLastSeen {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	res := &sdk_struct.UserLastSeen{UserID: userID, Status: constant.LastSeenUnknown}
+	state, ok := c.users[userID]
+	if !ok {
+		return res
+	}
+	if state.online {
+		res.Status = constant.LastSeenOnline
+		return res
+	}
+	if state.time == 0 {
+		return res
+	}
+	elapsed := (now - state.time) / 1000
+	for _, bucket := range c.precision {
+		if elapsed <= bucket {
+			res.Status = constant.LastSeenRecently
+			res.About = bucket
+			return res
+		}
+	}
+	res.Status = constant.LastSeenLongAgo
+	return res
+}
+
+// SetOnlineStatusGetter sets the function used to subscribe to and read the online platforms of users.
+func (u *User) SetOnlineStatusGetter(getter func(ctx context.Context, userIDs []string) (map[string][]int32, error)) {
+	u.getUserOnline = getter
+}
+
+// SetLastSeenPrecision sets the buckets in seconds the last seen time is rounded up to.
+func (u *User) SetLastSeenPrecision(precision []int64) {
+	u.lastSeen.setPrecision(precision)
+}
+
+// GetUserLastSeen returns when the users were last seen. The users are subscribed so that the time they go
+// offline is observed. The last seen time and the online status of a user who hides them from the login user
+// by their privacy settings are hidden.
+func (u *User) GetUserLastSeen(ctx context.Context, userIDs []string) ([]*sdk_struct.UserLastSeen, error) {
+	userIDs = datautil.Distinct(userIDs)
+	if len(userIDs) == 0 {
+		return []*sdk_struct.UserLastSeen{}, nil
+	}
+	online, err := u.getUserOnline(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UnixMilli()
+	for userID, platformIDs := range online {
+		u.lastSeen.update(userID, platformIDs, now)
+	}
+	visible, err := u.lastSeenVisibleUsers(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*sdk_struct.UserLastSeen, 0, len(userIDs))
+	for _, userID := range userIDs {
+		lastSeen := u.lastSeen.get(userID, now)
+		if _, ok := visible[userID]; !ok {
+			lastSeen.Status = constant.LastSeenHidden
+			lastSeen.About = 0
+		}
+		res = append(res, lastSeen)
+	}
+	return res, nil
+}
+
+// lastSeenVisibleUsers returns the users whose published privacy settings show their last seen time and their
+// online status to the login user, read in one request for the users not cached. The users whose settings
+// cannot be read are hidden until they are read.
+func (u *User) lastSeenVisibleUsers(ctx context.Context, userIDs []string) (map[string]struct{}, error) {
+	visible := make(map[string]struct{}, len(userIDs))
+	others := make([]string, 0, len(userIDs))
+	for _, userID := range userIDs {
+		if userID == u.loginUserID {
+			visible[userID] = struct{}{}
+		} else {
+			others = append(others, userID)
+		}
+	}
+	if len(others) == 0 {
+		return visible, nil
+	}
+	privacy, err := u.getUsersPublicPrivacy(ctx, others)
+	if err != nil {
+		log.ZWarn(ctx, "get privacy settings failed", err, "userIDs", others)
+		return visible, nil
+	}
+	var friendsOnly []string
+	for _, userID := range others {
+		settings, ok := privacy[userID]
+		if !ok {
+			continue
+		}
+		switch settings.LastSeenVisibility {
+		case constant.ProfileVisibleToNone:
+		case constant.ProfileVisibleToFriends:
+			friendsOnly = append(friendsOnly, userID)
+		default:
+			visible[userID] = struct{}{}
+		}
+	}
+	if len(friendsOnly) > 0 {
+		// the friendship is mutual, a friend of the login user has the login user as a friend
+		friends, err := u.GetFriendInfoList(ctx, friendsOnly)
+		if err != nil {
+			return nil, err
+		}
+		for _, friend := range friends {
+			visible[friend.FriendUserID] = struct{}{}
+		}
+	}
+	return visible, nil
+}
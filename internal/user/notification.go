@@ -3,18 +3,19 @@ package user
 import (
 	"context"
 
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/errreport"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
-	"github.com/openimsdk/protocol/constant"
 	"github.com/openimsdk/protocol/sdkws"
 	"github.com/openimsdk/tools/errs"
-	"github.com/openimsdk/tools/log"
 )
 
 // DoNotification handles incoming notifications for the user.
 func (u *User) DoNotification(ctx context.Context, msg *sdkws.MsgData) {
 	log.ZDebug(ctx, "user notification", "msg", msg)
 	if err := u.doNotification(ctx, msg); err != nil {
-		log.ZError(ctx, "DoUserNotification failed", err)
+		errreport.Report(ctx, errreport.SourceNotification, "DoUserNotification", err, "contentType", msg.ContentType)
 	}
 }
 
@@ -22,6 +23,10 @@ func (u *User) doNotification(ctx context.Context, msg *sdkws.MsgData) error {
 	switch msg.ContentType {
 	case constant.UserInfoUpdatedNotification:
 		return u.userInfoUpdatedNotification(ctx, msg)
+	case constant.UserCommandAddNotification, constant.UserCommandUpdateNotification, constant.UserCommandDeleteNotification:
+		return u.userCommandNotification(ctx, msg)
+	case constant.ClientConfigChangedNotification:
+		return u.SyncClientConfig(ctx)
 	default:
 		return errs.New("unknown content type", "contentType", msg.ContentType, "clientMsgID", msg.ClientMsgID, "serverMsgID", msg.ServerMsgID).Wrap()
 	}
@@ -40,7 +45,21 @@ func (u *User) userInfoUpdatedNotification(ctx context.Context, msg *sdkws.MsgDa
 			return err
 		}
 	} else {
-		log.ZDebug(ctx, "detail.UserID != u.loginUserID, do nothing", "detail.UserID", tips.UserID, "u.loginUserID", u.loginUserID)
+		// the nickname and face url of the other user are loaded again when next shown
+		u.UserCache().Invalidate(tips.UserID)
 	}
 	return nil
 }
+
+// userCommandNotification handles notifications about the login user's commands changed on another device.
+func (u *User) userCommandNotification(ctx context.Context, msg *sdkws.MsgData) error {
+	tips := sdkws.UserCommandUpdateTips{}
+	if err := utils.UnmarshalNotificationElem(msg.Content, &tips); err != nil {
+		return err
+	}
+	if tips.ToUserID != u.loginUserID {
+		log.ZDebug(ctx, "tips.ToUserID != u.loginUserID, do nothing", "tips.ToUserID", tips.ToUserID, "u.loginUserID", u.loginUserID)
+		return nil
+	}
+	return u.SyncPrivacySettings(ctx)
+}
@@ -0,0 +1,273 @@
+package user
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/api"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	userPb "github.com/openimsdk/protocol/user"
+	"github.com/openimsdk/protocol/wrapperspb"
+	"github.com/openimsdk/tools/errs"
+)
+
+// privacyExKey is the key of the user ex field the privacy settings other users respect are published under.
+const privacyExKey = "openimPrivacy"
+
+// publicPrivacy is the part of the privacy settings published in the user ex field, the read receipt opt-out
+// stays private. The zero value is the default settings.
+type publicPrivacy struct {
+	AddFriendPermission int32 `json:"addFriendPermission"`
+	ProfileVisibility   int32 `json:"profileVisibility"`
+	LastSeenVisibility  int32 `json:"lastSeenVisibility"`
+}
+
+func newPublicPrivacy(settings *model_struct.LocalPrivacySettings) publicPrivacy {
+	return publicPrivacy{
+		AddFriendPermission: settings.AddFriendPermission,
+		ProfileVisibility:   settings.ProfileVisibility,
+		LastSeenVisibility:  settings.LastSeenVisibility,
+	}
+}
+
+// decodePublicPrivacy reads the published privacy settings from a user ex field, the default settings when
+// there are none.
+func decodePublicPrivacy(ex string) publicPrivacy {
+	var (
+		fields  map[string]json.RawMessage
+		privacy publicPrivacy
+	)
+	if err := json.Unmarshal([]byte(ex), &fields); err != nil || fields[privacyExKey] == nil {
+		return privacy
+	}
+	_ = json.Unmarshal(fields[privacyExKey], &privacy)
+	return privacy
+}
+
+// getUsersPublicPrivacy returns the published privacy settings of the users, read with their cached profiles
+// in one request for the users not cached.
+func (u *User) getUsersPublicPrivacy(ctx context.Context, userIDs []string) (map[string]publicPrivacy, error) {
+	users, err := u.GetUsersInfoWithCache(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[string]publicPrivacy, len(users))
+	for _, user := range users {
+		res[user.UserID] = decodePublicPrivacy(user.Ex)
+	}
+	return res, nil
+}
+
+// GetPrivacySettings returns the login user's privacy settings. The local copy is used when present,
+// otherwise the settings are pulled from the server and stored.
+func (u *User) GetPrivacySettings(ctx context.Context) (*model_struct.LocalPrivacySettings, error) {
+	settings, err := u.DataBase.GetPrivacySettings(ctx, u.loginUserID)
+	if err == nil {
+		return settings, nil
+	}
+	if !errs.ErrRecordNotFound.Is(errs.Unwrap(err)) {
+		return nil, err
+	}
+	return u.syncPrivacySettings(ctx)
+}
+
+// SetPrivacySettings saves the privacy settings on the server as a user command, so that the other
+// devices of the login user receive a command notification and sync the same settings. The settings other
+// users have to respect are published in the user ex field, which has to be a json object.
+func (u *User) SetPrivacySettings(ctx context.Context, settings *model_struct.LocalPrivacySettings) error {
+	if err := checkPrivacySettings(settings); err != nil {
+		return err
+	}
+	settings.UserID = u.loginUserID
+	settings.UpdateTime = time.Now().UnixMilli()
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return sdkerrs.ErrSdkInternal.WrapMsg("json.Marshal privacy settings failed " + err.Error())
+	}
+	_, exist, err := u.getPrivacySettingsFromServer(ctx)
+	if err != nil {
+		return err
+	}
+	// the other users read the rules they have to respect from the user ex field
+	if err := u.UpdateSelfExProfile(ctx, map[string]any{privacyExKey: newPublicPrivacy(settings)}); err != nil {
+		return err
+	}
+	value := &wrapperspb.StringValue{Value: string(data)}
+	if exist {
+		err = api.ProcessUserCommandUpdate.Execute(ctx, &userPb.ProcessUserCommandUpdateReq{
+			UserID: u.loginUserID, Type: constant.UserCommandTypePrivacy, Uuid: constant.UserCommandPrivacyUUID, Value: value})
+	} else {
+		err = api.ProcessUserCommandAdd.Execute(ctx, &userPb.ProcessUserCommandAddReq{
+			UserID: u.loginUserID, Type: constant.UserCommandTypePrivacy, Uuid: constant.UserCommandPrivacyUUID, Value: value})
+	}
+	if err != nil {
+		return err
+	}
+	return u.DataBase.SetPrivacySettings(ctx, settings)
+}
+
+// SyncPrivacySettings pulls the privacy settings from the server into the local database.
+func (u *User) SyncPrivacySettings(ctx context.Context) error {
+	_, err := u.syncPrivacySettings(ctx)
+	return err
+}
+
+// ReadReceiptDisabled reports whether the login user has opted out of sending read receipts.
+func (u *User) ReadReceiptDisabled(ctx context.Context) bool {
+	settings, err := u.GetPrivacySettings(ctx)
+	if err != nil {
+		log.ZWarn(ctx, "GetPrivacySettings failed", err)
+		return false
+	}
+	return settings.ReadReceiptDisabled
+}
+
+// CheckAddFriend returns ErrPrivacyRestricted when the published privacy settings of the user do not accept
+// a friend application from the login user. isGroupMate tells whether the user shares a group with the login
+// user. The check runs on the client to refuse early, a user whose settings or groups cannot be read is left
+// to the server.
+func (u *User) CheckAddFriend(ctx context.Context, userID string, isGroupMate func(ctx context.Context, userID string) (bool, error)) error {
+	privacy, err := u.getUsersPublicPrivacy(ctx, []string{userID})
+	if err != nil {
+		log.ZWarn(ctx, "get privacy settings failed, add friend unchecked", err, "userID", userID)
+		return nil
+	}
+	return checkAddFriendPermission(ctx, userID, privacy[userID], isGroupMate)
+}
+
+func checkAddFriendPermission(ctx context.Context, userID string, privacy publicPrivacy, isGroupMate func(ctx context.Context, userID string) (bool, error)) error {
+	switch privacy.AddFriendPermission {
+	case constant.AddFriendRejectAll:
+		return sdkerrs.ErrPrivacyRestricted.WrapMsg("the user does not accept friend applications")
+	case constant.AddFriendOnlyGroupMate:
+		groupMate, err := isGroupMate(ctx, userID)
+		if err != nil {
+			log.ZWarn(ctx, "check group mate failed, add friend unchecked", err, "userID", userID)
+			return nil
+		}
+		if !groupMate {
+			return sdkerrs.ErrPrivacyRestricted.WrapMsg("the user only accepts friend applications from group members")
+		}
+	}
+	return nil
+}
+
+// keepPublishedPrivacy carries the published privacy settings of the login user over to a new ex field set by
+// the app, so that replacing the ex field does not drop them.
+func (u *User) keepPublishedPrivacy(ctx context.Context, ex string) string {
+	self, err := u.GetLoginUser(ctx, u.loginUserID)
+	if err != nil {
+		return ex
+	}
+	var current map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(self.Ex), &current); err != nil || current[privacyExKey] == nil {
+		return ex
+	}
+	var fields map[string]json.RawMessage
+	if strings.TrimSpace(ex) != "" {
+		if err := json.Unmarshal([]byte(ex), &fields); err != nil {
+			log.ZWarn(ctx, "user ex is not a json object, the published privacy settings are dropped", err)
+			return ex
+		}
+	}
+	if _, ok := fields[privacyExKey]; ok {
+		return ex
+	}
+	if fields == nil {
+		fields = make(map[string]json.RawMessage)
+	}
+	fields[privacyExKey] = current[privacyExKey]
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return ex
+	}
+	return string(data)
+}
+
+// profileHiddenUsers returns the users whose published privacy settings hide their profile from the login user.
+func (u *User) profileHiddenUsers(ctx context.Context, users []*model_struct.LocalUser) (map[string]struct{}, error) {
+	hidden := make(map[string]struct{})
+	var friendsOnly []string
+	for _, user := range users {
+		if user.UserID == u.loginUserID {
+			continue
+		}
+		switch decodePublicPrivacy(user.Ex).ProfileVisibility {
+		case constant.ProfileVisibleToNone:
+			hidden[user.UserID] = struct{}{}
+		case constant.ProfileVisibleToFriends:
+			hidden[user.UserID] = struct{}{}
+			friendsOnly = append(friendsOnly, user.UserID)
+		}
+	}
+	if len(friendsOnly) > 0 {
+		friends, err := u.GetFriendInfoList(ctx, friendsOnly)
+		if err != nil {
+			return nil, err
+		}
+		for _, friend := range friends {
+			delete(hidden, friend.FriendUserID)
+		}
+	}
+	return hidden, nil
+}
+
+func (u *User) syncPrivacySettings(ctx context.Context) (*model_struct.LocalPrivacySettings, error) {
+	settings, _, err := u.getPrivacySettingsFromServer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.DataBase.SetPrivacySettings(ctx, settings); err != nil {
+		return nil, err
+	}
+	log.ZDebug(ctx, "syncPrivacySettings", "settings", settings)
+	return settings, nil
+}
+
+// getPrivacySettingsFromServer returns the privacy settings of the login user stored in their user commands,
+// the default settings and false when they were never saved. Other users read the published part, see
+// getUsersPublicPrivacy.
+func (u *User) getPrivacySettingsFromServer(ctx context.Context) (*model_struct.LocalPrivacySettings, bool, error) {
+	userID := u.loginUserID
+	commands, err := api.ExtractField(ctx, api.ProcessUserCommandGetAll.Invoke,
+		&userPb.ProcessUserCommandGetAllReq{UserID: userID}, (*userPb.ProcessUserCommandGetAllResp).GetCommandResp)
+	if err != nil {
+		return nil, false, err
+	}
+	settings := &model_struct.LocalPrivacySettings{UserID: userID}
+	for _, command := range commands {
+		if command.Type != constant.UserCommandTypePrivacy || command.Uuid != constant.UserCommandPrivacyUUID {
+			continue
+		}
+		if err := json.Unmarshal([]byte(command.Value), settings); err != nil {
+			return nil, false, sdkerrs.ErrSdkInternal.WrapMsg("json.Unmarshal privacy settings failed " + err.Error())
+		}
+		settings.UserID = userID
+		return settings, true, nil
+	}
+	return settings, false, nil
+}
+
+func checkPrivacySettings(settings *model_struct.LocalPrivacySettings) error {
+	switch settings.AddFriendPermission {
+	case constant.AddFriendAllowAll, constant.AddFriendNeedVerify, constant.AddFriendRejectAll, constant.AddFriendOnlyGroupMate:
+	default:
+		return sdkerrs.ErrArgs.WrapMsg("invalid addFriendPermission")
+	}
+	switch settings.ProfileVisibility {
+	case constant.ProfileVisibleToAll, constant.ProfileVisibleToFriends, constant.ProfileVisibleToNone:
+	default:
+		return sdkerrs.ErrArgs.WrapMsg("invalid profileVisibility")
+	}
+	switch settings.LastSeenVisibility {
+	case constant.ProfileVisibleToAll, constant.ProfileVisibleToFriends, constant.ProfileVisibleToNone:
+	default:
+		return sdkerrs.ErrArgs.WrapMsg("invalid lastSeenVisibility")
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+)
+
+func TestCheckAddFriendPermission(t *testing.T) {
+	groupMates := map[string]bool{"mate": true}
+	isGroupMate := func(_ context.Context, userID string) (bool, error) {
+		if userID == "unsynced" {
+			return false, errors.New("joined groups not synced")
+		}
+		return groupMates[userID], nil
+	}
+	for _, c := range []struct {
+		userID     string
+		permission int32
+		refused    bool
+	}{
+		{"stranger", constant.AddFriendAllowAll, false},
+		{"stranger", constant.AddFriendNeedVerify, false},
+		{"mate", constant.AddFriendRejectAll, true},
+		{"mate", constant.AddFriendOnlyGroupMate, false},
+		{"stranger", constant.AddFriendOnlyGroupMate, true},
+		// left to the server
+		{"unsynced", constant.AddFriendOnlyGroupMate, false},
+	} {
+		err := checkAddFriendPermission(context.Background(), c.userID, publicPrivacy{AddFriendPermission: c.permission}, isGroupMate)
+		if refused := sdkerrs.ErrPrivacyRestricted.Is(err); refused != c.refused || (err != nil && !refused) {
+			t.Fatal(c.userID, c.permission, err)
+		}
+	}
+}
+
+func TestDecodePublicPrivacy(t *testing.T) {
+	for ex, expect := range map[string]publicPrivacy{
+		"":            {},
+		"plain text":  {},
+		`{"level":3}`: {},
+		`{"openimPrivacy":{"addFriendPermission":2,"profileVisibility":1,"lastSeenVisibility":2}}`: {
+			AddFriendPermission: constant.AddFriendRejectAll,
+			ProfileVisibility:   constant.ProfileVisibleToFriends,
+			LastSeenVisibility:  constant.ProfileVisibleToNone,
+		},
+	} {
+		if privacy := decodePublicPrivacy(ex); privacy != expect {
+			t.Fatal(ex, privacy)
+		}
+	}
+}
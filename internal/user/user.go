@@ -18,9 +18,10 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"sync/atomic"
 
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
-	"github.com/openimsdk/tools/log"
 
 	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/cache"
@@ -28,6 +29,7 @@ import (
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/db_interface"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/exprofile"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/syncer"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
 	"github.com/openimsdk/tools/utils/datautil"
@@ -35,7 +37,7 @@ import (
 
 // NewUser creates a new User object.
 func NewUser(conversationEventQueue chan common.Cmd2Value) *User {
-	user := &User{conversationEventQueue: conversationEventQueue}
+	user := &User{conversationEventQueue: conversationEventQueue, lastSeen: newLastSeenCache()}
 	user.initSyncer()
 	return user
 }
@@ -45,10 +47,15 @@ type User struct {
 	db_interface.DataBase
 	loginUserID            string
 	listener               func() open_im_sdk_callback.OnUserListener
+	clientConfigListener   func() open_im_sdk_callback.OnClientConfigListener
 	userSyncer             *syncer.Syncer[*model_struct.LocalUser, syncer.NoResp, string]
 	conversationEventQueue chan common.Cmd2Value
 	userCache              *cache.UserCache[string, *model_struct.LocalUser]
+	userCacheStore         cache.Store[string, *model_struct.LocalUser]
 	once                   sync.Once
+	getUserOnline          func(ctx context.Context, userIDs []string) (map[string][]int32, error)
+	lastSeen               *lastSeenCache
+	exProfileSchema        atomic.Pointer[exprofile.Schema]
 
 	//OnlineStatusCache *cache.Cache[string, *userPb.OnlineStatus]
 }
@@ -60,11 +67,17 @@ func (u *User) UserCache() *cache.UserCache[string, *model_struct.LocalUser] {
 			nil,
 			u.GetLoginUser,
 			u.GetUsersInfoFromServer,
+			u.userCacheStore,
 		)
 	})
 	return u.userCache
 }
 
+// SetUserCacheStore sets the store of the user info cache, before the cache is first used.
+func (u *User) SetUserCacheStore(store cache.Store[string, *model_struct.LocalUser]) {
+	u.userCacheStore = store
+}
+
 // SetDataBase sets the DataBase field in User struct
 func (u *User) SetDataBase(db db_interface.DataBase) {
 	u.DataBase = db
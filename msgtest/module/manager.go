@@ -11,13 +11,13 @@ import (
 
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/api"
 
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/network"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
 	authPB "github.com/openimsdk/protocol/auth"
 	"github.com/openimsdk/protocol/msg"
 	"github.com/openimsdk/tools/errs"
-	"github.com/openimsdk/tools/log"
 	"github.com/openimsdk/tools/mcontext"
 )
 
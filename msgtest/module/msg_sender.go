@@ -13,11 +13,11 @@ import (
 
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/common"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
 	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
 	pbconstant "github.com/openimsdk/protocol/constant"
 	"github.com/openimsdk/protocol/sdkws"
-	"github.com/openimsdk/tools/log"
 	"github.com/openimsdk/tools/mcontext"
 )
 
@@ -120,10 +120,7 @@ func newIMconfig(platformID int32, apiAddr, wsAddr string) sdk_struct.IMConfig {
 }
 
 func newUserCtx(userID, token string, imConfig sdk_struct.IMConfig) context.Context {
-	return ccontext.WithInfo(context.Background(), &ccontext.GlobalConfig{
-		UserID:   userID,
-		Token:    token,
-		IMConfig: &imConfig})
+	return ccontext.WithInfo(context.Background(), ccontext.NewGlobalConfig(userID, token, &imConfig))
 }
 
 func NewUser(userID, token string, timeOffset int64, p *PressureTester, imConfig sdk_struct.IMConfig, opts ...func(core *SendMsgUser)) *SendMsgUser {
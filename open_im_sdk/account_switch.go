@@ -0,0 +1,147 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package open_im_sdk
+
+import (
+	"context"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/cliconf"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/db_interface"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+)
+
+// maxKeptAccounts bounds the accounts switched away from whose database stays open, the least recently
+// used one is closed beyond it.
+const maxKeptAccounts = 4
+
+// keptAccount is an account switched away from, its database is opened and migrated already.
+type keptAccount struct {
+	db       db_interface.DataBase
+	token    string
+	keptTime time.Time
+}
+
+// SwitchAccount Log out the current account and log in userID without the cold start of a login, the
+// database of an account switched away from is kept open. The token may be empty for an account switched
+// away from before, its last token is used. Can be called logged out.
+func SwitchAccount(callback open_im_sdk_callback.Base, operationID string, userID, token string) {
+	call(callback, operationID, IMUserContext.SwitchAccount, userID, token)
+}
+
+func (u *UserContext) SwitchAccount(ctx context.Context, userID, token string) error {
+	if userID == "" {
+		return sdkerrs.ErrArgs.WrapMsg("userID is empty")
+	}
+	if u.info.Config() == nil {
+		return sdkerrs.ErrSDKNotInit
+	}
+	status := u.getLoginStatus(ctx)
+	if status == Logging {
+		return sdkerrs.ErrArgs.WrapMsg("a login is in progress")
+	}
+	if status == Logged && u.info.UserID == userID {
+		return nil
+	}
+	if token == "" {
+		u.keptMutex.Lock()
+		if kept, ok := u.keptAccounts[userID]; ok {
+			token = kept.token
+		}
+		u.keptMutex.Unlock()
+		if token == "" {
+			return sdkerrs.ErrArgs.WrapMsg("the account was not switched away from, a token is required")
+		}
+	}
+	start := time.Now()
+	if status == Logged {
+		u.keepAccount(ctx)
+	}
+	cliconf.SetLoginUserID(ctx, userID)
+	if err := u.login(ctx, userID, token, nil); err != nil {
+		return err
+	}
+	log.ZInfo(ctx, "account switched", "userID", userID, "cost", time.Since(start))
+	return nil
+}
+
+// keepAccount stops the session of the logged in user like a logout, without closing its database.
+func (u *UserContext) keepAccount(ctx context.Context) {
+	userID, token, db := u.info.UserID, u.info.Token(), u.db
+	u.Exit()
+	u.initResources()
+	u.putKeptAccount(ctx, userID, token, db)
+}
+
+// putKeptAccount keeps the database of userID open, closing the least recently used one beyond maxKeptAccounts.
+func (u *UserContext) putKeptAccount(ctx context.Context, userID, token string, db db_interface.DataBase) {
+	u.keptMutex.Lock()
+	defer u.keptMutex.Unlock()
+	if u.keptAccounts == nil {
+		u.keptAccounts = make(map[string]*keptAccount)
+	}
+	u.keptAccounts[userID] = &keptAccount{db: db, token: token, keptTime: time.Now()}
+	for len(u.keptAccounts) > maxKeptAccounts {
+		var oldest string
+		for id, kept := range u.keptAccounts {
+			if oldest == "" || kept.keptTime.Before(u.keptAccounts[oldest].keptTime) {
+				oldest = id
+			}
+		}
+		u.closeKeptAccountLocked(ctx, oldest)
+	}
+	log.ZDebug(ctx, "account kept", "userID", userID, "kept", len(u.keptAccounts))
+}
+
+// takeKeptDB returns the database kept open for userID, nil when the account was not switched away from.
+func (u *UserContext) takeKeptDB(userID string) db_interface.DataBase {
+	u.keptMutex.Lock()
+	defer u.keptMutex.Unlock()
+	kept, ok := u.keptAccounts[userID]
+	if !ok {
+		return nil
+	}
+	delete(u.keptAccounts, userID)
+	return kept.db
+}
+
+// closeKeptAccount closes the database kept open for userID, if any.
+func (u *UserContext) closeKeptAccount(ctx context.Context, userID string) {
+	u.keptMutex.Lock()
+	defer u.keptMutex.Unlock()
+	u.closeKeptAccountLocked(ctx, userID)
+}
+
+// closeKeptAccounts closes the databases of all the accounts switched away from.
+func (u *UserContext) closeKeptAccounts(ctx context.Context) {
+	u.keptMutex.Lock()
+	defer u.keptMutex.Unlock()
+	for userID := range u.keptAccounts {
+		u.closeKeptAccountLocked(ctx, userID)
+	}
+}
+
+func (u *UserContext) closeKeptAccountLocked(ctx context.Context, userID string) {
+	kept, ok := u.keptAccounts[userID]
+	if !ok {
+		return
+	}
+	delete(u.keptAccounts, userID)
+	if err := kept.db.Close(ctx); err != nil {
+		log.ZWarn(ctx, "close kept account db failed", err, "userID", userID)
+	}
+}
@@ -0,0 +1,39 @@
+package open_im_sdk
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/ccontext"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+// a failed login can be retried and keeps the database of the account switched back to
+func TestLoginFailed(t *testing.T) {
+	u := NewLoginMgr()
+	u.info.SetConfig(&sdk_struct.IMConfig{DataDir: t.TempDir(), PlatformID: 1})
+	u.initResources()
+	ctx := ccontext.WithInfo(context.Background(), u.info)
+	kept, err := db.NewDataBase(ctx, "u1", t.TempDir(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the closed database fails the login after it is taken
+	if err := kept.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+	u.putKeptAccount(ctx, "u1", "t0", kept)
+	if err := u.login(ctx, "u1", "t1", nil); err == nil {
+		t.Fatal("login with a closed database succeeded")
+	}
+	if status := u.getLoginStatus(ctx); status != LogoutStatus {
+		t.Fatal("login status", status)
+	}
+	u.keptMutex.Lock()
+	account, ok := u.keptAccounts["u1"]
+	u.keptMutex.Unlock()
+	if !ok || account.db != kept || account.token != "t1" {
+		t.Fatal("the database switched back to is not kept", account)
+	}
+}
@@ -5,9 +5,10 @@ import (
 	"sync/atomic"
 
 	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/audit"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/common"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
 	"github.com/openimsdk/tools/errs"
-	"github.com/openimsdk/tools/log"
 )
 
 type apiErrCallback struct {
@@ -51,6 +52,7 @@ func (c *apiErrCallback) OnError(ctx context.Context, err error) {
 	case errs.TokenKickedError:
 		if atomic.CompareAndSwapInt32(&c.kickedOfflineState, 0, 1) {
 			log.ZError(ctx, "OnKickedOffline callback", err)
+			audit.Record(ctx, audit.ActionKickedOffline, "OnKickedOffline", "")
 			c.listener().OnKickedOffline()
 			_ = common.DispatchLogout(ctx, c.loginMgrCh)
 		}
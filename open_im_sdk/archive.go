@@ -0,0 +1,62 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package open_im_sdk
+
+import (
+	"context"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+)
+
+// ArchiveMessages Move the messages sent before beforeTime, in milliseconds, to the archive database next to
+// the local database. The history still reads them, the archive is attached on demand, and the local database
+// stays small. Returns the number of messages archived.
+func ArchiveMessages(callback open_im_sdk_callback.Base, operationID string, beforeTime int64) {
+	call(callback, operationID, IMUserContext.ArchiveMessages, beforeTime)
+}
+
+func (u *UserContext) ArchiveMessages(ctx context.Context, beforeTime int64) (int64, error) {
+	if beforeTime <= 0 {
+		return 0, sdkerrs.ErrArgs.WrapMsg("archive time is required")
+	}
+	return u.db.ArchiveMessages(ctx, beforeTime)
+}
+
+func checkArchiveMessagesAfterDays(days int) error {
+	if days < 0 {
+		return sdkerrs.ErrArgs.WrapMsg("archive messages after days can't be negative")
+	}
+	return nil
+}
+
+// autoArchive archives the messages older than ArchiveMessagesAfterDays, in background before the compaction
+// which gives their pages back.
+func (u *UserContext) autoArchive(ctx context.Context) {
+	config := u.info.Config()
+	days := config.ArchiveMessagesAfterDays
+	if days == 0 || config.InMemoryDB {
+		return
+	}
+	beforeTime := time.Now().AddDate(0, 0, -days).UnixMilli()
+	archived, err := u.db.ArchiveMessages(ctx, beforeTime)
+	if err != nil {
+		log.ZWarn(ctx, "auto archive messages failed", err, "beforeTime", beforeTime)
+		return
+	}
+	log.ZInfo(ctx, "auto archive messages", "beforeTime", beforeTime, "archived", archived)
+}
@@ -0,0 +1,39 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package open_im_sdk
+
+import (
+	"context"
+
+	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdk_params_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+)
+
+// GetAuditLogs Get the audit log of the login user, the latest first, see EnableAuditLog of the config.
+func GetAuditLogs(callback open_im_sdk_callback.Base, operationID string, params string) {
+	call(callback, operationID, IMUserContext.GetAuditLogs, params)
+}
+
+func (u *UserContext) GetAuditLogs(ctx context.Context, params *sdk_params_callback.GetAuditLogsParams) ([]*model_struct.LocalAuditLog, error) {
+	if !u.info.Config().EnableAuditLog {
+		return nil, sdkerrs.ErrArgs.WrapMsg("the audit log is not enabled, see EnableAuditLog of the config")
+	}
+	if params.Count <= 0 {
+		return nil, sdkerrs.ErrArgs.WrapMsg("count must be positive")
+	}
+	return u.db.GetAuditLogs(ctx, params.Actions, params.StartTime, params.EndTime, params.Offset, params.Count)
+}
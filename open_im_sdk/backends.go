@@ -0,0 +1,85 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package open_im_sdk
+
+import (
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/internal/third/file"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/cache"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+)
+
+// SetUserCacheStore Keep the user info cache in the store instead of in memory, e.g. a cache shared by the
+// processes of a desktop app. Set before login, nil keeps it in memory again.
+func (u *UserContext) SetUserCacheStore(store cache.Store[string, *model_struct.LocalUser]) {
+	u.userCacheStore = store
+	u.user.SetUserCacheStore(store)
+}
+
+// SetGroupMemberCacheStore Keep the group member cache in the store instead of in memory, the keys are the
+// group ID and the user ID. Set before login, nil keeps it in memory again.
+func (u *UserContext) SetGroupMemberCacheStore(store cache.Store[string, *model_struct.LocalGroupMember]) {
+	u.groupMemberCacheStore = store
+	u.group.SetGroupMemberCacheStore(store)
+}
+
+// SetFileStorage Put the uploaded files and the files of the messages to the storage instead of the object
+// storage of the server, nil puts them to the server again.
+func (u *UserContext) SetFileStorage(storage file.Storage) {
+	u.fileStorage = storage
+	u.file.SetStorage(storage)
+}
+
+// setBackends gives the backends set by the app to the modules created again at the logout.
+func (u *UserContext) setBackends() {
+	if u.userCacheStore != nil {
+		u.user.SetUserCacheStore(u.userCacheStore)
+	}
+	if u.groupMemberCacheStore != nil {
+		u.group.SetGroupMemberCacheStore(u.groupMemberCacheStore)
+	}
+	u.file.SetStorage(u.fileStorage)
+}
+
+// setInfoCacheStores bounds the user info and group member caches by InfoCacheTTL and InfoCacheCapacity at the
+// login, unless the app keeps them in its own stores.
+func (u *UserContext) setInfoCacheStores() {
+	config := u.info.Config()
+	ttl, capacity := time.Duration(config.InfoCacheTTL)*time.Second, config.InfoCacheCapacity
+	users, members := u.userCacheStore, u.groupMemberCacheStore
+	if users == nil {
+		users = cache.NewTTLStore[string, *model_struct.LocalUser](ttl, capacity)
+		u.user.SetUserCacheStore(users)
+	}
+	if members == nil {
+		members = cache.NewTTLStore[string, *model_struct.LocalGroupMember](ttl, capacity)
+		u.group.SetGroupMemberCacheStore(members)
+	}
+	cache.Register("user", users)
+	cache.Register("groupMember", members)
+}
+
+func (c *Client) SetUserCacheStore(store cache.Store[string, *model_struct.LocalUser]) {
+	c.u.SetUserCacheStore(store)
+}
+
+func (c *Client) SetGroupMemberCacheStore(store cache.Store[string, *model_struct.LocalGroupMember]) {
+	c.u.SetGroupMemberCacheStore(store)
+}
+
+func (c *Client) SetFileStorage(storage file.Storage) {
+	c.u.SetFileStorage(storage)
+}
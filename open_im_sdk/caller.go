@@ -27,10 +27,11 @@ import (
 
 	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/ccontext"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/crash"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
 
 	"github.com/openimsdk/tools/errs"
-	"github.com/openimsdk/tools/log"
 )
 
 func isNumeric(kind reflect.Kind) bool {
@@ -87,21 +88,24 @@ func setNumeric(in interface{}, out interface{}) {
 	}
 }
 
-func call_(operationID string, fn any, args ...any) (res any, err error) {
+func call_(userContext *UserContext, operationID string, fn any, args ...any) (res any, err error) {
 	t := time.Now()
 	funcPtr := reflect.ValueOf(fn).Pointer()
 	funcName := runtime.FuncForPC(funcPtr).Name()
 	if operationID == "" {
 		return nil, sdkerrs.ErrArgs.WrapMsg("call function operationID is empty")
 	}
-	if err := CheckResourceLoad(IMUserContext, funcName); err != nil {
+	if err := CheckResourceLoad(userContext, funcName); err != nil {
 		return nil, err
 	}
-	ctx := ccontext.WithOperationID(IMUserContext.Context(), operationID)
+	ctx, done := trackOperation(ccontext.WithOperationID(userContext.Context(), operationID), operationID, funcName)
+	defer done()
 
 	defer func(start time.Time) {
 		if r := recover(); r != nil {
-			p := fmt.Sprintf("panic: %+v\n%s", r, debug.Stack())
+			stack := debug.Stack()
+			crash.Capture(ctx, "call "+funcName, r, stack)
+			p := fmt.Sprintf("panic: %+v\n%s", r, stack)
 			err = fmt.Errorf("call panic: %+v", p)
 		} else {
 			elapsed := time.Since(start).Milliseconds()
@@ -132,6 +136,14 @@ func call_(operationID string, fn any, args ...any) (res any, err error) {
 	ins = append(ins, reflect.ValueOf(ctx))
 	for i := 0; i < len(args); i++ {
 		inFnField := fnt.In(i + 1)
+		if args[i] == nil { // e.g. a listener not given by the app
+			switch inFnField.Kind() {
+			case reflect.Interface, reflect.Ptr, reflect.Slice, reflect.Map, reflect.Func, reflect.Chan:
+				ins = append(ins, reflect.Zero(inFnField))
+				continue
+			}
+			return nil, sdkerrs.ErrArgs.WrapMsg(fmt.Sprintf("go call arg %d is nil, is %s", i, inFnField))
+		}
 		arg := reflect.TypeOf(args[i])
 		if arg.String() == inFnField.String() || inFnField.Kind() == reflect.Interface {
 			ins = append(ins, reflect.ValueOf(args[i]))
@@ -147,7 +159,7 @@ func call_(operationID string, fn any, args ...any) (res any, err error) {
 			case reflect.Struct, reflect.Slice, reflect.Array, reflect.Map:
 				v := reflect.New(inFnField)
 				if err := json.Unmarshal([]byte(args[i].(string)), v.Interface()); err != nil {
-					return nil, sdkerrs.ErrSdkInternal.WrapMsg(fmt.Sprintf("go call json.Unmarshal error: %s", err))
+					return nil, sdkerrs.ErrArgs.WrapMsg(fmt.Sprintf("go call json.Unmarshal error: %s", err))
 				}
 				if ptr == 0 {
 					v = v.Elem()
@@ -180,7 +192,7 @@ func call_(operationID string, fn any, args ...any) (res any, err error) {
 
 	if fnt.Out(len(outs) - 1).Implements(reflect.ValueOf(new(error)).Elem().Type()) {
 		if errValueOf := outs[len(outs)-1]; !errValueOf.IsNil() {
-			return nil, errValueOf.Interface().(error)
+			return nil, canceledErr(ctx, errValueOf.Interface().(error))
 		}
 		if len(outs) == 1 {
 			return "", nil
@@ -219,14 +231,12 @@ func call(callback open_im_sdk_callback.Base, operationID string, fn any, args .
 		log.ZWarn(context.Background(), "callback is nil", nil)
 		return
 	}
+	// the instance is taken before the goroutine starts, so switching the instance does not affect this call
+	userContext := IMUserContext
 	go func() {
-		res, err := call_(operationID, fn, args...)
+		res, err := call_(userContext, operationID, fn, args...)
 		if err != nil {
-			if code, ok := errs.Unwrap(err).(errs.CodeError); ok {
-				callback.OnError(int32(code.Code()), err.Error())
-			} else {
-				callback.OnError(sdkerrs.UnknownCode, fmt.Sprintf("error %T not implement CodeError: %s", err, err))
-			}
+			callback.OnError(sdkerrs.Code(err), err.Error())
 			return
 		}
 		data, err := json.Marshal(res)
@@ -240,6 +250,7 @@ func call(callback open_im_sdk_callback.Base, operationID string, fn any, args .
 
 func syncCall(operationID string, fn any, args ...any) (res string) {
 	err := error(nil)
+	userContext := IMUserContext
 	if operationID == "" {
 		return ""
 	}
@@ -250,7 +261,7 @@ func syncCall(operationID string, fn any, args ...any) (res string) {
 	}
 	funcPtr := reflect.ValueOf(fn).Pointer()
 	funcName := runtime.FuncForPC(funcPtr).Name()
-	if err = CheckResourceLoad(IMUserContext, funcName); err != nil {
+	if err = CheckResourceLoad(userContext, funcName); err != nil {
 		return ""
 	}
 	fnt := fnv.Type()
@@ -261,11 +272,13 @@ func syncCall(operationID string, fn any, args ...any) (res string) {
 	}
 	ins := make([]reflect.Value, 0, numIn)
 
-	ctx := ccontext.WithOperationID(IMUserContext.Context(), operationID)
+	ctx := ccontext.WithOperationID(userContext.Context(), operationID)
 	t := time.Now()
 	defer func(start time.Time) {
 		if r := recover(); r != nil {
-			fmt.Printf("panic: %+v\n%s", r, debug.Stack())
+			stack := debug.Stack()
+			crash.Capture(ctx, "syncCall "+funcName, r, stack)
+			fmt.Printf("panic: %+v\n%s", r, stack)
 		} else {
 			elapsed := time.Since(start).Milliseconds()
 			if err == nil {
@@ -363,12 +376,14 @@ func messageCall(callback open_im_sdk_callback.SendMsgCallBack, operationID stri
 		log.ZWarn(context.Background(), "callback is nil", nil)
 		return
 	}
-	messageCall_(callback, operationID, fn, args...)
+	messageCall_(IMUserContext, callback, operationID, fn, args...)
 }
-func messageCall_(callback open_im_sdk_callback.SendMsgCallBack, operationID string, fn any, args ...any) {
+func messageCall_(userContext *UserContext, callback open_im_sdk_callback.SendMsgCallBack, operationID string, fn any, args ...any) {
 	defer func() {
 		if r := recover(); r != nil {
-			fmt.Println(" panic err:", r, string(debug.Stack()))
+			stack := debug.Stack()
+			crash.Capture(context.Background(), "messageCall", r, stack)
+			fmt.Println(" panic err:", r, string(stack))
 			callback.OnError(sdkerrs.SdkInternalError, fmt.Sprintf("recover: %+v", r))
 			return
 		}
@@ -377,10 +392,8 @@ func messageCall_(callback open_im_sdk_callback.SendMsgCallBack, operationID str
 		callback.OnError(sdkerrs.ArgsError, sdkerrs.ErrArgs.WrapMsg("operationID is empty").Error())
 		return
 	}
-	if err := CheckResourceLoad(IMUserContext, ""); err != nil {
-		if code, ok := errs.Unwrap(err).(errs.CodeError); ok {
-			callback.OnError(int32(code.Code()), err.Error())
-		}
+	if err := CheckResourceLoad(userContext, ""); err != nil {
+		callback.OnError(sdkerrs.Code(err), err.Error())
 		return
 	}
 	fnv := reflect.ValueOf(fn)
@@ -397,7 +410,7 @@ func messageCall_(callback open_im_sdk_callback.SendMsgCallBack, operationID str
 
 	t := time.Now()
 	ins := make([]reflect.Value, 0, numIn)
-	ctx := ccontext.WithOperationID(IMUserContext.Context(), operationID)
+	ctx := ccontext.WithOperationID(userContext.Context(), operationID)
 	ctx = ccontext.WithSendMessageCallback(ctx, callback)
 	funcPtr := reflect.ValueOf(fn).Pointer()
 	funcName := runtime.FuncForPC(funcPtr).Name()
@@ -445,11 +458,7 @@ func messageCall_(callback open_im_sdk_callback.SendMsgCallBack, operationID str
 	}
 	if lastErr {
 		if last := outVals[len(outVals)-1]; last != nil {
-			if code, ok := errs.Unwrap(last.(error)).(errs.CodeError); ok {
-				callback.OnError(int32(code.Code()), last.(error).Error())
-			} else {
-				callback.OnError(sdkerrs.UnknownCode, fmt.Sprintf("error %T not implement CodeError: %s", last.(error), last.(error).Error()))
-			}
+			callback.OnError(sdkerrs.Code(last.(error)), last.(error).Error())
 			return
 		}
 
@@ -0,0 +1,40 @@
+package open_im_sdk
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/ccontext"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+// a listener the app leaves nil is passed as nil
+func TestCallNilArg(t *testing.T) {
+	u := NewLoginMgr()
+	u.info.SetConfig(&sdk_struct.IMConfig{})
+	u.ctx = ccontext.WithInfo(context.Background(), u.info)
+	u.setLoginStatus(Logged)
+	var got open_im_sdk_callback.LogoutWipeProgress = &emptyLogoutWipeProgress{}
+	fn := func(_ context.Context, progress open_im_sdk_callback.LogoutWipeProgress, params *sdk_struct.IMConfig) error {
+		got = progress
+		if params != nil {
+			t.Error("params is not nil", params)
+		}
+		return nil
+	}
+	if _, err := call_(u, "op", fn, nil, nil); err != nil || got != nil {
+		t.Fatal(got, err)
+	}
+	if _, err := call_(u, "op", func(context.Context, int) error { return nil }, nil); !sdkerrs.ErrArgs.Is(err) {
+		t.Fatal(err)
+	}
+	if _, err := call_(u, "op", u.LogoutWithMode, "unknown", nil); !sdkerrs.ErrArgs.Is(err) {
+		t.Fatal(err)
+	}
+}
+
+type emptyLogoutWipeProgress struct{}
+
+func (emptyLogoutWipeProgress) OnProgress(int, int) {}
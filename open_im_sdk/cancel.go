@@ -0,0 +1,111 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package open_im_sdk
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+)
+
+// cancelableFuncs are the functions running long enough to be canceled by their operationID with CancelOperation.
+// The others may leave goroutines running with their context once they return, e.g. Login, so their context
+// is not canceled.
+var cancelableFuncs = map[string]struct{}{
+	"GetAdvancedHistoryMessageList-fm":        {},
+	"GetAdvancedHistoryMessageListReverse-fm": {},
+	"FindMessageList-fm":                      {},
+	"SearchLocalMessages-fm":                  {},
+	"SearchConversation-fm":                   {},
+	"SearchFriends-fm":                        {},
+	"SearchGroups-fm":                         {},
+	"SearchGroupMembers-fm":                   {},
+	"UploadFile-fm":                           {},
+	"UploadLogs-fm":                           {},
+}
+
+// operations holds the cancel functions of the cancelable calls running, by their operationID.
+var operations = struct {
+	sync.Mutex
+	seq     uint64
+	cancels map[string]map[uint64]context.CancelFunc
+}{cancels: make(map[string]map[uint64]context.CancelFunc)}
+
+// trackOperation makes the call of a cancelable function cancelable by its operationID, done must be called
+// once the call returns.
+func trackOperation(ctx context.Context, operationID string, funcName string) (context.Context, func()) {
+	parts := strings.Split(funcName, ".")
+	if _, ok := cancelableFuncs[parts[len(parts)-1]]; !ok {
+		return ctx, func() {}
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	operations.Lock()
+	operations.seq++
+	seq := operations.seq
+	if operations.cancels[operationID] == nil {
+		operations.cancels[operationID] = make(map[uint64]context.CancelFunc)
+	}
+	operations.cancels[operationID][seq] = cancel
+	operations.Unlock()
+	return ctx, func() {
+		operations.Lock()
+		delete(operations.cancels[operationID], seq)
+		if len(operations.cancels[operationID]) == 0 {
+			delete(operations.cancels, operationID)
+		}
+		operations.Unlock()
+		cancel()
+	}
+}
+
+// canceledErr gives the error of a call canceled midway the canceled code, whichever layer noticed it first,
+// e.g. sqlite returns an interrupted error.
+func canceledErr(ctx context.Context, err error) error {
+	if errors.Is(ctx.Err(), context.Canceled) && sdkerrs.Code(err) != sdkerrs.CanceledError {
+		return sdkerrs.ErrCanceled.WrapMsg(err.Error())
+	}
+	return err
+}
+
+// CancelOperation Cancel the running call made with the operationID, e.g. a history pull, a search or an
+// upload. The call returns the error code 10011 and releases what it holds. Can be called before login.
+func CancelOperation(callback open_im_sdk_callback.Base, operationID string, canceledOperationID string) {
+	call(callback, operationID, IMUserContext.CancelOperation, canceledOperationID)
+}
+
+func (u *UserContext) CancelOperation(ctx context.Context, canceledOperationID string) error {
+	n := cancelOperation(canceledOperationID)
+	if n == 0 {
+		return sdkerrs.ErrArgs.WrapMsg("no cancelable call running with the operationID " + canceledOperationID)
+	}
+	log.ZInfo(ctx, "operation canceled", "canceledOperationID", canceledOperationID, "calls", n)
+	return nil
+}
+
+// cancelOperation cancels the calls running with the operationID and returns how many there were.
+func cancelOperation(operationID string) int {
+	operations.Lock()
+	defer operations.Unlock()
+	cancels := operations.cancels[operationID]
+	for _, cancel := range cancels {
+		cancel()
+	}
+	return len(cancels)
+}
@@ -0,0 +1,148 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package open_im_sdk
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+)
+
+// conversationChanges coalesces the conversations changed within ConversationChangeWindow into one
+// OnConversationChanged, each conversation once as it was last changed, in the order they first changed. The
+// zero value is ready to use.
+type conversationChanges struct {
+	mu       sync.Mutex
+	ids      []string
+	changed  map[string]json.RawMessage
+	listener open_im_sdk_callback.OnConversationListener
+	timer    *time.Timer
+}
+
+// add keeps the conversations of the list until the window ends, false when the list is not a json array of
+// conversations.
+func (c *conversationChanges) add(listener open_im_sdk_callback.OnConversationListener, window time.Duration, conversationList string) bool {
+	var list []json.RawMessage
+	if err := json.Unmarshal([]byte(conversationList), &list); err != nil {
+		return false
+	}
+	ids := make([]string, 0, len(list))
+	for _, raw := range list {
+		var conversation struct {
+			ConversationID string `json:"conversationID"`
+		}
+		if err := json.Unmarshal(raw, &conversation); err != nil || conversation.ConversationID == "" {
+			return false
+		}
+		ids = append(ids, conversation.ConversationID)
+	}
+	if len(ids) == 0 {
+		return true
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.changed == nil {
+		c.changed = make(map[string]json.RawMessage)
+	}
+	for i, id := range ids {
+		if _, ok := c.changed[id]; !ok {
+			c.ids = append(c.ids, id)
+		}
+		c.changed[id] = list[i]
+	}
+	c.listener = listener
+	if c.timer == nil {
+		c.timer = time.AfterFunc(window, c.flush)
+	}
+	return true
+}
+
+// flush calls OnConversationChanged with the conversations kept, if any.
+func (c *conversationChanges) flush() {
+	c.flushThen(nil)
+}
+
+// flushThen flushes the conversations kept then makes the call, under the lock so that a flush of the timer
+// does not come after a later call. The listener only queues the calls, see listenerDispatcher.
+func (c *conversationChanges) flushThen(call func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.timer != nil {
+		c.timer.Stop()
+		c.timer = nil
+	}
+	if len(c.ids) > 0 {
+		list := make([]json.RawMessage, 0, len(c.ids))
+		for _, id := range c.ids {
+			list = append(list, c.changed[id])
+		}
+		if data, err := json.Marshal(list); err != nil {
+			log.ZWarn(context.Background(), "marshal coalesced conversations failed", err, "count", len(c.ids))
+		} else {
+			c.listener.OnConversationChanged(string(data))
+		}
+		c.ids, c.changed, c.listener = nil, nil, nil
+	}
+	if call != nil {
+		call()
+	}
+}
+
+// coalescedConversationListener coalesces the changes of the conversations within the window, the other
+// calls deliver the changes kept first so that they keep their order.
+type coalescedConversationListener struct {
+	c      *conversationChanges
+	window time.Duration
+	l      open_im_sdk_callback.OnConversationListener
+}
+
+func (l coalescedConversationListener) OnSyncServerStart(reinstalled bool) {
+	l.c.flushThen(func() { l.l.OnSyncServerStart(reinstalled) })
+}
+
+func (l coalescedConversationListener) OnSyncServerFinish(reinstalled bool) {
+	l.c.flushThen(func() { l.l.OnSyncServerFinish(reinstalled) })
+}
+
+func (l coalescedConversationListener) OnSyncServerProgress(progress int) {
+	l.l.OnSyncServerProgress(progress)
+}
+
+func (l coalescedConversationListener) OnSyncServerFailed(reinstalled bool) {
+	l.c.flushThen(func() { l.l.OnSyncServerFailed(reinstalled) })
+}
+
+func (l coalescedConversationListener) OnNewConversation(conversationList string) {
+	l.c.flushThen(func() { l.l.OnNewConversation(conversationList) })
+}
+
+func (l coalescedConversationListener) OnConversationChanged(conversationList string) {
+	if l.window > 0 && l.c.add(l.l, l.window, conversationList) {
+		return
+	}
+	l.c.flushThen(func() { l.l.OnConversationChanged(conversationList) })
+}
+
+func (l coalescedConversationListener) OnTotalUnreadMessageCountChanged(totalUnreadCount int32) {
+	l.l.OnTotalUnreadMessageCountChanged(totalUnreadCount)
+}
+
+func (l coalescedConversationListener) OnConversationUserInputStatusChanged(change string) {
+	l.l.OnConversationUserInputStatusChanged(change)
+}
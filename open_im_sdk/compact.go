@@ -0,0 +1,99 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package open_im_sdk
+
+import (
+	"context"
+
+	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+)
+
+// CompactDatabase Give the space of the deleted data of the local database back to the file system, the
+// first compaction rebuilds the whole file. The progress is called with the pages freed and to free.
+func CompactDatabase(callback open_im_sdk_callback.Base, operationID string, progress open_im_sdk_callback.CompactDatabaseProgress) {
+	call(callback, operationID, IMUserContext.CompactDatabase, progress)
+}
+
+func (u *UserContext) CompactDatabase(ctx context.Context, progress open_im_sdk_callback.CompactDatabaseProgress) error {
+	// the compaction in background gives way to the one asked for
+	u.stopAutoCompact()
+	return u.db.Compact(ctx, 0, func(done, total int) {
+		if progress != nil {
+			progress.OnProgress(done, total)
+		}
+	})
+}
+
+func checkAutoCompactRatio(ratio float64) error {
+	if ratio < 0 || ratio >= 1 {
+		return sdkerrs.ErrArgs.WrapMsg("db auto compact ratio is between 0 and 1")
+	}
+	return nil
+}
+
+// startAutoCompact compacts the local database while the app is in background, once the share of its
+// unused pages is over DBAutoCompactRatio, after archiving the old messages. It stops when the app comes
+// back to foreground.
+func (u *UserContext) startAutoCompact() {
+	config := u.info.Config()
+	ratio := config.DBAutoCompactRatio
+	if (ratio == 0 && config.ArchiveMessagesAfterDays == 0) || u.db == nil {
+		return
+	}
+	u.compactMutex.Lock()
+	defer u.compactMutex.Unlock()
+	if u.compactCancel != nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(u.ctx)
+	u.compactCancel = cancel
+	go func() {
+		defer func() {
+			u.compactMutex.Lock()
+			u.compactCancel = nil
+			u.compactMutex.Unlock()
+			cancel()
+		}()
+		u.autoArchive(ctx)
+		if ratio == 0 || ctx.Err() != nil {
+			return
+		}
+		free, total, err := u.db.FreePages(ctx)
+		if err != nil {
+			log.ZWarn(ctx, "get db free pages failed", err)
+			return
+		}
+		if total == 0 || float64(free)/float64(total) < ratio {
+			return
+		}
+		log.ZInfo(ctx, "auto compact db", "freePages", free, "pageCount", total)
+		err = u.db.Compact(ctx, 0, func(done, total int) {
+			log.ZDebug(ctx, "auto compact db progress", "done", done, "total", total)
+		})
+		if err != nil && ctx.Err() == nil {
+			log.ZWarn(ctx, "auto compact db failed", err)
+		}
+	}()
+}
+
+func (u *UserContext) stopAutoCompact() {
+	u.compactMutex.Lock()
+	defer u.compactMutex.Unlock()
+	if u.compactCancel != nil {
+		u.compactCancel()
+	}
+}
@@ -0,0 +1,393 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package open_im_sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/internal/interaction"
+	"github.com/openimsdk/openim-sdk-core/v3/internal/third/file"
+	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/cache"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/fault"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/network"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/telemetry"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+	pbConstant "github.com/openimsdk/protocol/constant"
+
+	conv "github.com/openimsdk/openim-sdk-core/v3/internal/conversation_msg"
+	"github.com/openimsdk/tools/errs"
+	tlog "github.com/openimsdk/tools/log"
+)
+
+// ValidateConfig Check a config of InitSDK without initializing, returns the errors of its fields and the fields
+// the SDK does not know, e.g. misspelled ones, which InitSDK ignores. Can be called before InitSDK.
+func ValidateConfig(callback open_im_sdk_callback.Base, operationID string, config string) {
+	call(callback, operationID, IMUserContext.ValidateConfig, config)
+}
+
+// GetEffectiveConfig Get the config the SDK runs with, the fields left empty hold the default used instead,
+// listed in defaulted. The passwords of the proxy and the headers of the telemetry are hidden.
+func GetEffectiveConfig(callback open_im_sdk_callback.Base, operationID string) {
+	call(callback, operationID, IMUserContext.GetEffectiveConfig)
+}
+
+// imConfigCheck checks a field of IMConfig, by its json name.
+type imConfigCheck struct {
+	field string
+	check func(config *sdk_struct.IMConfig) error
+}
+
+// imConfigChecks are run on the config of InitSDK and of UpdateConfig before any of it is applied.
+var imConfigChecks = []imConfigCheck{
+	{"platformID", func(config *sdk_struct.IMConfig) error {
+		if _, ok := pbConstant.PlatformID2Name[int(config.PlatformID)]; !ok {
+			return errs.New("unknown platform " + strconv.Itoa(int(config.PlatformID)))
+		}
+		return nil
+	}},
+	{"apiAddr", func(config *sdk_struct.IMConfig) error { return checkAddr(config.ApiAddr, true, "http", "https") }},
+	{"wsAddr", func(config *sdk_struct.IMConfig) error { return checkAddr(config.WsAddr, true, "ws", "wss") }},
+	{"quicAddr", func(config *sdk_struct.IMConfig) error { return checkAddr(config.QuicAddr, false, "quic") }},
+	{"transport", func(config *sdk_struct.IMConfig) error {
+		if config.Transport == interaction.TransportQUIC && !interaction.QuicSupported {
+			return errs.New("quic is not supported by this build, it is built with the quic tag")
+		}
+		return checkOneOf(config.Transport, "", interaction.TransportWebSocket, interaction.TransportQUIC, interaction.TransportLongPolling)
+	}},
+	{"compression", func(config *sdk_struct.IMConfig) error {
+		return checkOneOf(config.Compression, "", constant.CompressionGzip, constant.CompressionDeflate, constant.CompressionNone)
+	}},
+	{"apiTransport", func(config *sdk_struct.IMConfig) error {
+		return checkOneOf(config.ApiTransport, "", constant.ApiTransportHTTP, constant.ApiTransportGRPC)
+	}},
+	{"grpcAddr", func(config *sdk_struct.IMConfig) error {
+		return checkAddr(config.GrpcAddr, config.ApiTransport == constant.ApiTransportGRPC, "grpc", "grpcs")
+	}},
+	{"logLevel", func(config *sdk_struct.IMConfig) error { return checkLogLevel(config.LogLevel) }},
+	{"logModuleLevels", func(config *sdk_struct.IMConfig) error {
+		modules := log.Modules()
+		for module, level := range config.LogModuleLevels {
+			if i := sort.SearchStrings(modules, module); i == len(modules) || modules[i] != module {
+				return errs.New("unknown log module " + module + ", expected one of " + strings.Join(modules, ", "))
+			}
+			if err := checkLogLevel(level); err != nil {
+				return err
+			}
+		}
+		return nil
+	}},
+	{"proxy", func(config *sdk_struct.IMConfig) error { return network.CheckProxy(config.Proxy) }},
+	{"certificatePins", func(config *sdk_struct.IMConfig) error { return network.CheckCertificatePins(config.CertificatePins) }},
+	{"reconnectInitialDelay", func(config *sdk_struct.IMConfig) error { return checkNotNegative(config.ReconnectInitialDelay) }},
+	{"reconnectMultiplier", func(config *sdk_struct.IMConfig) error {
+		if config.ReconnectMultiplier != 0 && config.ReconnectMultiplier < 1 {
+			return errs.New("must be at least 1, or 0 for the default")
+		}
+		return nil
+	}},
+	{"reconnectMaxDelay", func(config *sdk_struct.IMConfig) error { return checkNotNegative(config.ReconnectMaxDelay) }},
+	{"reconnectJitter", func(config *sdk_struct.IMConfig) error { return checkRatio(config.ReconnectJitter) }},
+	{"reconnectMaxAttempts", func(config *sdk_struct.IMConfig) error { return checkNotNegative(config.ReconnectMaxAttempts) }},
+	{"heartbeatInterval", func(config *sdk_struct.IMConfig) error { return checkNotNegative(config.HeartbeatInterval) }},
+	{"maxHeartbeatInterval", func(config *sdk_struct.IMConfig) error { return checkNotNegative(config.MaxHeartbeatInterval) }},
+	{"degradedRTT", func(config *sdk_struct.IMConfig) error { return checkNotNegative(config.DegradedRTT) }},
+	{"degradedLossRate", func(config *sdk_struct.IMConfig) error { return checkRatio(config.DegradedLossRate) }},
+	{"tokenRefreshAdvance", func(config *sdk_struct.IMConfig) error { return checkNotNegative(config.TokenRefreshAdvance) }},
+	{"guestSendLimit", func(config *sdk_struct.IMConfig) error { return checkNotNegative(config.GuestSendLimit) }},
+	{"bandwidthLimit", func(config *sdk_struct.IMConfig) error { return network.CheckBandwidthLimit(config.BandwidthLimit) }},
+	{"requestPolicies", func(config *sdk_struct.IMConfig) error { return network.CheckRequestPolicies(config.RequestPolicies) }},
+	{"allowOnMetered", func(config *sdk_struct.IMConfig) error { return network.CheckAllowOnMetered(config.AllowOnMetered) }},
+	{"conflictPolicies", func(config *sdk_struct.IMConfig) error { return conv.CheckConflictPolicies(config.ConflictPolicies) }},
+	{"msgSyncWorkers", func(config *sdk_struct.IMConfig) error { return interaction.CheckSyncWorkers(config.MsgSyncWorkers) }},
+	{"msgWriteBatchSize", func(config *sdk_struct.IMConfig) error { return conv.CheckMsgWriteBatchSize(config.MsgWriteBatchSize) }},
+	{"uploadParallelism", func(config *sdk_struct.IMConfig) error { return file.CheckUploadParallelism(config.UploadParallelism) }},
+	{"dbAutoCompactRatio", func(config *sdk_struct.IMConfig) error { return checkAutoCompactRatio(config.DBAutoCompactRatio) }},
+	{"archiveMessagesAfterDays", func(config *sdk_struct.IMConfig) error {
+		return checkArchiveMessagesAfterDays(config.ArchiveMessagesAfterDays)
+	}},
+	{"dbFileName", func(config *sdk_struct.IMConfig) error { return db.CheckFileNameFormat(config.DBFileName) }},
+	{"dbPragmas", func(config *sdk_struct.IMConfig) error { return db.CheckPragmas(config.DBPragmas) }},
+	{"storageQuota", func(config *sdk_struct.IMConfig) error { return checkNotNegative(config.StorageQuota) }},
+	{"mediaCacheLimit", func(config *sdk_struct.IMConfig) error { return checkMediaCacheLimit(config.MediaCacheLimit) }},
+	// the image and video fields are checked together, reported on the first of them
+	{"imageMaxSide", func(config *sdk_struct.IMConfig) error { return conv.CheckImageCompression(imageCompression(config)) }},
+	{"videoMaxSide", func(config *sdk_struct.IMConfig) error { return conv.CheckVideoConstraints(videoConstraints(config)) }},
+	{"dbSlowQueryThreshold", func(config *sdk_struct.IMConfig) error { return checkNotNegative(config.DBSlowQueryThreshold) }},
+	{"telemetry", func(config *sdk_struct.IMConfig) error { return telemetry.Check(config.Telemetry) }},
+	{"runtimeStatsInterval", func(config *sdk_struct.IMConfig) error { return checkNotNegative(config.RuntimeStatsInterval) }},
+	{"infoCacheTTL", func(config *sdk_struct.IMConfig) error { return checkNotNegative(config.InfoCacheTTL) }},
+	{"infoCacheCapacity", func(config *sdk_struct.IMConfig) error { return checkNotNegative(config.InfoCacheCapacity) }},
+	{"conversationChangeWindow", func(config *sdk_struct.IMConfig) error {
+		return checkNotNegative(config.ConversationChangeWindow)
+	}},
+	{"faultInjection", func(config *sdk_struct.IMConfig) error { return fault.Check(config.FaultInjection) }},
+	{"sensitiveWordMode", func(config *sdk_struct.IMConfig) error {
+		return checkOneOf(config.SensitiveWordMode, "", constant.SensitiveWordBlock, constant.SensitiveWordReplace, constant.SensitiveWordWarn)
+	}},
+	{"sensitiveWordMask", func(config *sdk_struct.IMConfig) error { return conv.CheckSensitiveWordMask(config.SensitiveWordMask) }},
+}
+
+func checkAddr(addr string, required bool, schemes ...string) error {
+	if addr == "" {
+		if required {
+			return errs.New("is required")
+		}
+		return nil
+	}
+	u, err := url.Parse(addr)
+	if err != nil {
+		return errs.New("is not a url " + err.Error())
+	}
+	if u.Host == "" {
+		return errs.New("has no host")
+	}
+	if err := checkOneOf(u.Scheme, schemes...); err != nil {
+		return errs.New("scheme " + u.Scheme + " is not one of " + strings.Join(schemes, ", "))
+	}
+	return nil
+}
+
+func checkOneOf(value string, values ...string) error {
+	for _, v := range values {
+		if value == v {
+			return nil
+		}
+	}
+	return errs.New(strconv.Quote(value) + " is not one of " + strings.Join(values[1:], ", "))
+}
+
+func checkLogLevel(level uint32) error {
+	if level > tlog.LevelDebugWithSQL {
+		return errs.New("log level " + strconv.Itoa(int(level)) + " is over " + strconv.Itoa(tlog.LevelDebugWithSQL))
+	}
+	return nil
+}
+
+func checkNotNegative[T int | int32 | int64](value T) error {
+	if value < 0 {
+		return errs.New("must not be negative")
+	}
+	return nil
+}
+
+func checkRatio(ratio float64) error {
+	if ratio < 0 || ratio > 1 {
+		return errs.New("must be between 0 and 1")
+	}
+	return nil
+}
+
+// validateIMConfig runs all the checks of the config, it is valid when none fails.
+func validateIMConfig(config *sdk_struct.IMConfig) []*sdk_struct.ConfigFieldError {
+	var res []*sdk_struct.ConfigFieldError
+	for _, c := range imConfigChecks {
+		if err := c.check(config); err != nil {
+			res = append(res, &sdk_struct.ConfigFieldError{Field: c.field, Reason: errorReason(err)})
+		}
+	}
+	return res
+}
+
+// configError is the error of the invalid fields of a config.
+func configError(fieldErrors []*sdk_struct.ConfigFieldError) error {
+	reasons := make([]string, 0, len(fieldErrors))
+	for _, e := range fieldErrors {
+		reasons = append(reasons, e.Field+": "+e.Reason)
+	}
+	return sdkerrs.ErrArgs.WrapMsg("invalid config " + strings.Join(reasons, "; "))
+}
+
+// errorReason is the message of err without its code and stack.
+func errorReason(err error) string {
+	var wrapper errs.ErrWrapper
+	if errors.As(err, &wrapper) {
+		return strings.TrimPrefix(wrapper.Error(), wrapper.Unwrap().Error()+" ")
+	}
+	if codeErr, ok := errs.Unwrap(err).(errs.CodeError); ok {
+		return codeErr.Msg()
+	}
+	return err.Error()
+}
+
+// unknownConfigFields are the fields of the json config not in IMConfig.
+func unknownConfigFields(config string) ([]string, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(config), &fields); err != nil {
+		return nil, sdkerrs.ErrArgs.WrapMsg("config is not a json object " + err.Error())
+	}
+	known, err := configFields(&sdk_struct.IMConfig{})
+	if err != nil {
+		return nil, err
+	}
+	var unknown []string
+	for name := range fields {
+		if _, ok := known[name]; !ok {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown, nil
+}
+
+// imConfigDefault sets the default of a field of IMConfig left empty, returns false when the field was set.
+type imConfigDefault struct {
+	field string
+	set   func(config *sdk_struct.IMConfig) bool
+}
+
+// imConfigDefaults are the values the SDK uses for the fields left empty, in the order they depend on each other.
+var imConfigDefaults = []imConfigDefault{
+	{"logRemainCount", func(config *sdk_struct.IMConfig) bool { return setDefault(&config.LogRemainCount, 1) }},
+	{"logMaxSize", func(config *sdk_struct.IMConfig) bool {
+		return setDefault(&config.LogMaxSize, uint32(log.DefaultMaxFileSize>>20))
+	}},
+	{"logMaxAge", func(config *sdk_struct.IMConfig) bool { return setDefault(&config.LogMaxAge, config.LogRemainCount) }},
+	{"transport", func(config *sdk_struct.IMConfig) bool {
+		return setDefault(&config.Transport, interaction.TransportWebSocket)
+	}},
+	{"compression", func(config *sdk_struct.IMConfig) bool {
+		return setDefault(&config.Compression, constant.CompressionGzip)
+	}},
+	{"apiTransport", func(config *sdk_struct.IMConfig) bool {
+		return setDefault(&config.ApiTransport, constant.ApiTransportHTTP)
+	}},
+	{"tokenRefreshAdvance", func(config *sdk_struct.IMConfig) bool {
+		return setDefault(&config.TokenRefreshAdvance, defaultTokenRefreshAdvance)
+	}},
+	{"guestSendLimit", func(config *sdk_struct.IMConfig) bool {
+		return setDefault(&config.GuestSendLimit, defaultGuestSendLimit)
+	}},
+	{"infoCacheTTL", func(config *sdk_struct.IMConfig) bool {
+		return setDefault(&config.InfoCacheTTL, int64(cache.DefaultInfoTTL/time.Second))
+	}},
+	{"infoCacheCapacity", func(config *sdk_struct.IMConfig) bool {
+		return setDefault(&config.InfoCacheCapacity, cache.DefaultInfoCapacity)
+	}},
+	{"reconnectInitialDelay", func(config *sdk_struct.IMConfig) bool {
+		return setDefault(&config.ReconnectInitialDelay, interaction.DefaultReconnectInitialDelay.Milliseconds())
+	}},
+	{"reconnectMultiplier", func(config *sdk_struct.IMConfig) bool {
+		return setDefault(&config.ReconnectMultiplier, interaction.DefaultReconnectMultiplier)
+	}},
+	{"reconnectMaxDelay", func(config *sdk_struct.IMConfig) bool {
+		return setDefault(&config.ReconnectMaxDelay, interaction.DefaultReconnectMaxDelay.Milliseconds())
+	}},
+	{"heartbeatInterval", func(config *sdk_struct.IMConfig) bool {
+		return setDefault(&config.HeartbeatInterval, int64(interaction.DefaultHeartbeatInterval/time.Second))
+	}},
+	{"maxHeartbeatInterval", func(config *sdk_struct.IMConfig) bool {
+		if config.MaxHeartbeatInterval >= config.HeartbeatInterval {
+			return false
+		}
+		config.MaxHeartbeatInterval = config.HeartbeatInterval
+		if config.AdaptiveHeartbeat {
+			config.MaxHeartbeatInterval *= interaction.DefaultMaxIntervalFactor
+		}
+		return true
+	}},
+	{"degradedRTT", func(config *sdk_struct.IMConfig) bool {
+		return setDefault(&config.DegradedRTT, interaction.DefaultDegradedRTT.Milliseconds())
+	}},
+	{"degradedLossRate", func(config *sdk_struct.IMConfig) bool {
+		return setDefault(&config.DegradedLossRate, interaction.DefaultDegradedLossRate)
+	}},
+	{"msgSyncWorkers", func(config *sdk_struct.IMConfig) bool {
+		return setDefault(&config.MsgSyncWorkers, interaction.DefaultSyncWorkers)
+	}},
+	{"msgWriteBatchSize", func(config *sdk_struct.IMConfig) bool {
+		return setDefault(&config.MsgWriteBatchSize, conv.DefaultMsgWriteBatchSize)
+	}},
+	{"uploadParallelism", func(config *sdk_struct.IMConfig) bool {
+		return setDefault(&config.UploadParallelism, file.DefaultUploadParallelism)
+	}},
+	{"imageQuality", func(config *sdk_struct.IMConfig) bool {
+		return setDefault(&config.ImageQuality, conv.DefaultImageQuality)
+	}},
+	{"dbSlowQueryThreshold", func(config *sdk_struct.IMConfig) bool {
+		return setDefault(&config.DBSlowQueryThreshold, db.DefaultSlowQueryThreshold.Milliseconds())
+	}},
+	{"sensitiveWordMask", func(config *sdk_struct.IMConfig) bool {
+		return setDefault(&config.SensitiveWordMask, conv.DefaultSensitiveWordMask)
+	}},
+}
+
+func setDefault[T comparable](field *T, value T) bool {
+	var zero T
+	if *field != zero {
+		return false
+	}
+	*field = value
+	return true
+}
+
+// effectiveIMConfig copies the config with the defaults of the fields left empty, and without its secrets.
+func effectiveIMConfig(config *sdk_struct.IMConfig) *sdk_struct.EffectiveConfig {
+	res := &sdk_struct.EffectiveConfig{Config: redactConfig(config), Defaulted: []string{}}
+	for _, d := range imConfigDefaults {
+		if d.set(res.Config) {
+			res.Defaulted = append(res.Defaulted, d.field)
+		}
+	}
+	return res
+}
+
+// checkIMConfig logs the errors of the config, returns whether it is valid.
+func checkIMConfig(ctx context.Context, config *sdk_struct.IMConfig) bool {
+	fieldErrors := validateIMConfig(config)
+	for _, e := range fieldErrors {
+		log.ZError(ctx, "invalid config field", nil, "field", e.Field, "reason", e.Reason)
+	}
+	return len(fieldErrors) == 0
+}
+
+func (u *UserContext) ValidateConfig(_ context.Context, config string) (*sdk_struct.ConfigValidation, error) {
+	unknown, err := unknownConfigFields(config)
+	if err != nil {
+		return nil, err
+	}
+	var configArgs sdk_struct.IMConfig
+	if err := json.Unmarshal([]byte(config), &configArgs); err != nil {
+		return nil, sdkerrs.ErrArgs.WrapMsg(err.Error())
+	}
+	res := &sdk_struct.ConfigValidation{Errors: validateIMConfig(&configArgs), UnknownFields: unknown}
+	if res.Errors == nil {
+		res.Errors = []*sdk_struct.ConfigFieldError{}
+	}
+	if res.UnknownFields == nil {
+		res.UnknownFields = []string{}
+	}
+	return res, nil
+}
+
+func (u *UserContext) GetEffectiveConfig(_ context.Context) (*sdk_struct.EffectiveConfig, error) {
+	u.configMutex.Lock()
+	defer u.configMutex.Unlock()
+	if u.info.Config() == nil {
+		return nil, sdkerrs.ErrSDKNotInit
+	}
+	return effectiveIMConfig(u.info.Config()), nil
+}
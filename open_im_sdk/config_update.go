@@ -0,0 +1,361 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package open_im_sdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"maps"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/fault"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/i18n"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/network"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/profiling"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/telemetry"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+// UpdateConfig Change the fields of the IMConfig given in config at runtime, e.g. {"apiAddr": "...", "logLevel": 3},
+// the others keep their value. The fields of the database, the log files and the platform need InitSDK again and
+// are refused. The long connection is opened again for the address, transport, compression and proxy fields,
+// reported in reconnect.
+func UpdateConfig(callback open_im_sdk_callback.Base, operationID string, config string) {
+	call(callback, operationID, IMUserContext.UpdateConfig, config)
+}
+
+// configField is a field of IMConfig that can change at runtime, checked by imConfigChecks before anything is
+// applied. apply applies it, or applies old back when a later field fails. The fields without apply are read
+// from the config each time they are used.
+type configField struct {
+	reconnect bool
+	apply     func(u *UserContext, ctx context.Context, old, config *sdk_struct.IMConfig) error
+}
+
+var runtimeConfigFields = map[string]configField{
+	"apiAddr":             {},
+	"wsAddr":              {reconnect: true},
+	"quicAddr":            {reconnect: true},
+	"transport":           {reconnect: true},
+	"longPollingFallback": {reconnect: true},
+	"compression":         {reconnect: true},
+	"proxy": {reconnect: true, apply: func(_ *UserContext, ctx context.Context, _, _ *sdk_struct.IMConfig) error {
+		// the requests take the proxy of the config, the idle connections are of the previous one
+		network.CloseIdleConnections(ctx)
+		return nil
+	}},
+	"certificatePins": {reconnect: true, apply: func(_ *UserContext, ctx context.Context, _, config *sdk_struct.IMConfig) error {
+		return network.SetCertificatePins(ctx, config.CertificatePins)
+	}},
+	"apiTransport": {apply: applyGrpc},
+	"grpcAddr":     {apply: applyGrpc},
+	"logLevel": {apply: func(_ *UserContext, _ context.Context, _, config *sdk_struct.IMConfig) error {
+		return log.SetLevel("", int(config.LogLevel))
+	}},
+	"logModuleLevels": {apply: func(_ *UserContext, _ context.Context, old, config *sdk_struct.IMConfig) error {
+		for module := range old.LogModuleLevels {
+			if _, ok := config.LogModuleLevels[module]; !ok {
+				if err := log.SetLevel(module, -1); err != nil {
+					return err
+				}
+			}
+		}
+		for module, level := range config.LogModuleLevels {
+			if err := log.SetLevel(module, int(level)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}},
+	"logPrivacy": {apply: func(_ *UserContext, _ context.Context, _, config *sdk_struct.IMConfig) error {
+		log.SetPrivacy(config.LogPrivacy)
+		return nil
+	}},
+	"stopGoroutineOnBackground": {},
+	"lastSeenPrecision": {apply: func(u *UserContext, _ context.Context, _, config *sdk_struct.IMConfig) error {
+		u.user.SetLastSeenPrecision(config.LastSeenPrecision)
+		return nil
+	}},
+	"tokenRefreshAdvance":   {},
+	"guestSendLimit":        {},
+	"reconnectInitialDelay": {apply: applyReconnectPolicy},
+	"reconnectMultiplier":   {apply: applyReconnectPolicy},
+	"reconnectMaxDelay":     {apply: applyReconnectPolicy},
+	"reconnectJitter":       {apply: applyReconnectPolicy},
+	"reconnectMaxAttempts":  {apply: applyReconnectPolicy},
+	"heartbeatInterval":     {apply: applyHeartbeat},
+	"adaptiveHeartbeat":     {apply: applyHeartbeat},
+	"maxHeartbeatInterval":  {apply: applyHeartbeat},
+	"degradedRTT":           {apply: applyDegradedThresholds},
+	"degradedLossRate":      {apply: applyDegradedThresholds},
+	"bandwidthLimit": {apply: func(_ *UserContext, ctx context.Context, _, config *sdk_struct.IMConfig) error {
+		return network.SetBandwidthLimit(ctx, config.BandwidthLimit)
+	}},
+	"requestPolicies": {apply: func(_ *UserContext, _ context.Context, _, config *sdk_struct.IMConfig) error {
+		return network.SetRequestPolicies(config.RequestPolicies)
+	}},
+	"faultInjection": {apply: func(_ *UserContext, _ context.Context, _, config *sdk_struct.IMConfig) error {
+		return fault.Configure(config.FaultInjection)
+	}},
+	"allowOnMetered": {apply: func(_ *UserContext, _ context.Context, _, config *sdk_struct.IMConfig) error {
+		return network.SetAllowOnMetered(config.AllowOnMetered)
+	}},
+	"autoReportBadge": {apply: func(u *UserContext, _ context.Context, _, config *sdk_struct.IMConfig) error {
+		if config.AutoReportBadge {
+			u.conversation.SetBadgeReporter(u.third.ReportBadge)
+		} else {
+			u.conversation.SetBadgeReporter(nil)
+		}
+		return nil
+	}},
+	"conflictPolicies": {
+		apply: func(u *UserContext, _ context.Context, _, config *sdk_struct.IMConfig) error {
+			u.conversation.SetConflictPolicies(config.ConflictPolicies)
+			return nil
+		},
+	},
+	"msgSyncWorkers": {
+		apply: func(u *UserContext, _ context.Context, _, config *sdk_struct.IMConfig) error {
+			u.msgSyncer.SetSyncWorkers(config.MsgSyncWorkers)
+			return nil
+		},
+	},
+	"msgWriteBatchSize": {
+		apply: func(u *UserContext, _ context.Context, _, config *sdk_struct.IMConfig) error {
+			u.conversation.SetMsgWriteBatchSize(config.MsgWriteBatchSize)
+			return nil
+		},
+	},
+	"downloadConcurrency": {
+		apply: func(u *UserContext, _ context.Context, _, config *sdk_struct.IMConfig) error {
+			u.download.SetConcurrency(config.DownloadConcurrency)
+			return nil
+		},
+	},
+	"uploadParallelism": {
+		apply: func(u *UserContext, _ context.Context, _, config *sdk_struct.IMConfig) error {
+			u.file.SetUploadParallelism(config.UploadParallelism)
+			return nil
+		},
+	},
+	"dbAutoCompactRatio":       {},
+	"archiveMessagesAfterDays": {},
+	"storageQuota":             {},
+	"storageEvictMessages":     {},
+	"mediaCacheLimit":          {},
+	"stripImageMetadata": {apply: func(u *UserContext, _ context.Context, _, config *sdk_struct.IMConfig) error {
+		u.conversation.SetStripImageMetadata(config.StripImageMetadata)
+		return nil
+	}},
+	"imageMaxSide":         imageCompressionField,
+	"imageQuality":         imageCompressionField,
+	"imageMaxBytes":        imageCompressionField,
+	"thumbnailMaxSide":     imageCompressionField,
+	"videoMaxSide":         videoConstraintsField,
+	"videoMaxBitrate":      videoConstraintsField,
+	"videoMaxBytes":        videoConstraintsField,
+	"dbInstrumentation":    {apply: applyDBInstrumentation},
+	"dbSlowQueryThreshold": {apply: applyDBInstrumentation},
+	"telemetry": {apply: func(_ *UserContext, _ context.Context, _, config *sdk_struct.IMConfig) error {
+		return telemetry.Configure(config.Telemetry, telemetry.Int("openim.platform_id", int(config.PlatformID)), telemetry.String("openim.system_type", config.SystemType))
+	}},
+	"runtimeStatsInterval": {
+		apply: func(_ *UserContext, _ context.Context, _, config *sdk_struct.IMConfig) error {
+			profiling.StartStats(time.Duration(config.RuntimeStatsInterval) * time.Second)
+			return nil
+		},
+	},
+	"conversationChangeWindow": {},
+	"signMessages": {apply: func(u *UserContext, _ context.Context, _, config *sdk_struct.IMConfig) error {
+		u.conversation.SetSignMessages(config.SignMessages)
+		return nil
+	}},
+	"sensitiveWordMode":            sensitiveWordField,
+	"sensitiveWordMask":            sensitiveWordField,
+	"filterReceivedSensitiveWords": sensitiveWordField,
+	"locale": {apply: func(_ *UserContext, _ context.Context, _, config *sdk_struct.IMConfig) error {
+		i18n.SetLocale(config.Locale)
+		return nil
+	}},
+	"moderateReceivedMessages": {apply: func(u *UserContext, _ context.Context, _, config *sdk_struct.IMConfig) error {
+		u.conversation.SetModerateReceived(config.ModerateReceivedMessages)
+		return nil
+	}},
+}
+
+var (
+	sensitiveWordField = configField{
+		apply: func(u *UserContext, _ context.Context, _, config *sdk_struct.IMConfig) error {
+			u.conversation.SetSensitiveWordFilter(config.SensitiveWordMode, config.SensitiveWordMask, config.FilterReceivedSensitiveWords)
+			return nil
+		},
+	}
+	imageCompressionField = configField{
+		apply: func(u *UserContext, _ context.Context, _, config *sdk_struct.IMConfig) error {
+			u.conversation.SetImageCompression(imageCompression(config))
+			return nil
+		},
+	}
+	videoConstraintsField = configField{
+		apply: func(u *UserContext, _ context.Context, _, config *sdk_struct.IMConfig) error {
+			u.conversation.SetVideoConstraints(videoConstraints(config))
+			return nil
+		},
+	}
+)
+
+func applyGrpc(_ *UserContext, ctx context.Context, _, config *sdk_struct.IMConfig) error {
+	var grpcAddr string
+	if config.ApiTransport == constant.ApiTransportGRPC {
+		grpcAddr = config.GrpcAddr
+	}
+	return network.SetGrpc(ctx, grpcAddr)
+}
+
+func applyReconnectPolicy(u *UserContext, _ context.Context, _, config *sdk_struct.IMConfig) error {
+	u.longConnMgr.SetReconnectPolicy(time.Duration(config.ReconnectInitialDelay)*time.Millisecond, config.ReconnectMultiplier,
+		time.Duration(config.ReconnectMaxDelay)*time.Millisecond, config.ReconnectJitter, config.ReconnectMaxAttempts)
+	return nil
+}
+
+func applyHeartbeat(u *UserContext, _ context.Context, _, config *sdk_struct.IMConfig) error {
+	u.longConnMgr.SetHeartbeat(time.Duration(config.HeartbeatInterval)*time.Second,
+		time.Duration(config.MaxHeartbeatInterval)*time.Second, config.AdaptiveHeartbeat)
+	return nil
+}
+
+func applyDegradedThresholds(u *UserContext, _ context.Context, _, config *sdk_struct.IMConfig) error {
+	u.longConnMgr.SetDegradedThresholds(time.Duration(config.DegradedRTT)*time.Millisecond, config.DegradedLossRate)
+	return nil
+}
+
+func applyDBInstrumentation(_ *UserContext, _ context.Context, _, config *sdk_struct.IMConfig) error {
+	db.SetInstrumentation(config.DBInstrumentation, time.Duration(config.DBSlowQueryThreshold)*time.Millisecond)
+	return nil
+}
+
+func (u *UserContext) UpdateConfig(ctx context.Context, fields map[string]json.RawMessage) (*sdk_struct.ConfigUpdate, error) {
+	u.configMutex.Lock()
+	defer u.configMutex.Unlock()
+	old := u.info.Config()
+	if old == nil {
+		return nil, sdkerrs.ErrSDKNotInit
+	}
+	previous, err := configFields(old)
+	if err != nil {
+		return nil, err
+	}
+	merged := maps.Clone(previous)
+	var unknown, initOnly []string
+	for name, value := range fields {
+		if _, ok := merged[name]; !ok {
+			unknown = append(unknown, name)
+			continue
+		}
+		merged[name] = value
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, sdkerrs.ErrArgs.WrapMsg("unknown config fields " + strings.Join(unknown, ", "))
+	}
+	data, err := json.Marshal(merged)
+	if err != nil {
+		return nil, sdkerrs.ErrArgs.WrapMsg(err.Error())
+	}
+	var config sdk_struct.IMConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, sdkerrs.ErrArgs.WrapMsg(err.Error())
+	}
+	updated, err := configFields(&config)
+	if err != nil {
+		return nil, err
+	}
+	var changed []string
+	for name := range fields {
+		if bytes.Equal(previous[name], updated[name]) {
+			continue
+		}
+		if _, ok := runtimeConfigFields[name]; !ok {
+			initOnly = append(initOnly, name)
+		}
+		changed = append(changed, name)
+	}
+	sort.Strings(initOnly)
+	sort.Strings(changed)
+	if len(initOnly) > 0 {
+		return nil, sdkerrs.ErrArgs.WrapMsg("config fields changed only by InitSDK " + strings.Join(initOnly, ", "))
+	}
+	if fieldErrors := validateIMConfig(&config); len(fieldErrors) > 0 {
+		return nil, configError(fieldErrors)
+	}
+	res := &sdk_struct.ConfigUpdate{Applied: []string{}, Reconnect: []string{}}
+	for i, name := range changed {
+		field := runtimeConfigFields[name]
+		if field.apply != nil {
+			if err := field.apply(u, ctx, old, &config); err != nil {
+				for _, name := range changed[:i] {
+					if apply := runtimeConfigFields[name].apply; apply != nil {
+						_ = apply(u, ctx, &config, old)
+					}
+				}
+				return nil, err
+			}
+		}
+		if field.reconnect {
+			res.Reconnect = append(res.Reconnect, name)
+		} else {
+			res.Applied = append(res.Applied, name)
+		}
+	}
+	u.info.SetConfig(&config)
+	log.ZInfo(ctx, "config updated", "applied", res.Applied, "reconnect", res.Reconnect)
+	if len(res.Reconnect) > 0 && u.getLoginStatus(ctx) == Logged {
+		u.longConnMgr.Close(ctx)
+	}
+	return res, nil
+}
+
+// updateConfig publishes a copy of the config changed by change, the config read by the requests is never
+// changed in place.
+func (u *UserContext) updateConfig(change func(config *sdk_struct.IMConfig)) {
+	u.configMutex.Lock()
+	defer u.configMutex.Unlock()
+	if old := u.info.Config(); old != nil {
+		config := *old
+		change(&config)
+		u.info.SetConfig(&config)
+	}
+}
+
+// configFields returns the json of each field of the config.
+func configFields(config *sdk_struct.IMConfig) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, sdkerrs.ErrSdkInternal.WrapMsg(err.Error())
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, sdkerrs.ErrSdkInternal.WrapMsg(err.Error())
+	}
+	return fields, nil
+}
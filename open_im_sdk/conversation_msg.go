@@ -28,6 +28,11 @@ func GetConversationListSplit(callback open_im_sdk_callback.Base, operationID st
 	call(callback, operationID, IMUserContext.Conversation().GetConversationListSplit, offset, count)
 }
 
+// GetConversationListByCursor gets the page of the conversation list following the opaque cursor, the first page for an empty cursor.
+func GetConversationListByCursor(callback open_im_sdk_callback.Base, operationID string, cursor string, count int) {
+	call(callback, operationID, IMUserContext.Conversation().GetConversationListByCursor, cursor, count)
+}
+
 func GetOneConversation(callback open_im_sdk_callback.Base, operationID string, sessionType int32, sourceID string) {
 	call(callback, operationID, IMUserContext.Conversation().GetOneConversation, sessionType, sourceID)
 }
@@ -36,6 +41,11 @@ func GetMultipleConversation(callback open_im_sdk_callback.Base, operationID str
 	call(callback, operationID, IMUserContext.Conversation().GetMultipleConversation, conversationIDList)
 }
 
+// GetConversations Get several conversations at once, the ones not found are returned in the failed list.
+func GetConversations(callback open_im_sdk_callback.Base, operationID string, conversationIDs string) {
+	call(callback, operationID, IMUserContext.Conversation().GetConversations, conversationIDs)
+}
+
 func SetConversation(callback open_im_sdk_callback.Base, operationID string, conversationID string, req string) {
 	call(callback, operationID, IMUserContext.Conversation().SetConversation, conversationID, req)
 }
@@ -81,6 +91,12 @@ func CreateCardMessage(operationID string, cardInfo string) string {
 	return syncCall(operationID, IMUserContext.Conversation().CreateCardMessage, cardInfo)
 }
 
+// CreateSatisfactionRatingInviteMessage Create the message an agent sends to invite the customer to rate the
+// session.
+func CreateSatisfactionRatingInviteMessage(operationID string, sessionID string) string {
+	return syncCall(operationID, IMUserContext.Conversation().CreateSatisfactionRatingInviteMessage, sessionID)
+}
+
 func CreateVideoMessageFromFullPath(operationID string, videoFullPath string, videoType string, duration int64, snapshotFullPath string) string {
 	return syncCall(operationID, IMUserContext.Conversation().CreateVideoMessageFromFullPath, videoFullPath, videoType, duration, snapshotFullPath)
 }
@@ -137,6 +153,12 @@ func SendMessage(callback open_im_sdk_callback.SendMsgCallBack, operationID, mes
 	messageCall(callback, operationID, IMUserContext.Conversation().SendMessage, message, recvID, groupID, offlinePushInfo, isOnlineOnly)
 }
 
+// SendMessageWithOptions sends a message like SendMessage, options are the sdk_struct.SendMsgOptions of this
+// message, "{}" for the ones of IMConfig.
+func SendMessageWithOptions(callback open_im_sdk_callback.SendMsgCallBack, operationID, message, recvID, groupID, offlinePushInfo string, isOnlineOnly bool, options string) {
+	messageCall(callback, operationID, IMUserContext.Conversation().SendMessageWithOptions, message, recvID, groupID, offlinePushInfo, isOnlineOnly, options)
+}
+
 func SendMessageNotOss(callback open_im_sdk_callback.SendMsgCallBack, operationID string, message, recvID, groupID string, offlinePushInfo string, isOnlineOnly bool) {
 	messageCall(callback, operationID, IMUserContext.Conversation().SendMessageNotOss, message, recvID, groupID, offlinePushInfo, isOnlineOnly)
 }
@@ -145,10 +167,21 @@ func FindMessageList(callback open_im_sdk_callback.Base, operationID string, fin
 	call(callback, operationID, IMUserContext.Conversation().FindMessageList, findMessageOptions)
 }
 
+// GetMessages Get messages of several conversations at once by their clientMsgIDs, the ones not found are
+// returned in the failed list.
+func GetMessages(callback open_im_sdk_callback.Base, operationID string, conversationArgs string) {
+	call(callback, operationID, IMUserContext.Conversation().GetMessages, conversationArgs)
+}
+
 func GetAdvancedHistoryMessageList(callback open_im_sdk_callback.Base, operationID string, getMessageOptions string) {
 	call(callback, operationID, IMUserContext.Conversation().GetAdvancedHistoryMessageList, getMessageOptions)
 }
 
+// GetMessageListByCursor gets the page of the history messages older than the opaque cursor, the latest messages for an empty cursor.
+func GetMessageListByCursor(callback open_im_sdk_callback.Base, operationID string, conversationID, cursor string, count int) {
+	call(callback, operationID, IMUserContext.Conversation().GetMessageListByCursor, conversationID, cursor, count)
+}
+
 func GetAdvancedHistoryMessageListReverse(callback open_im_sdk_callback.Base, operationID string, getMessageOptions string) {
 	call(callback, operationID, IMUserContext.Conversation().GetAdvancedHistoryMessageListReverse, getMessageOptions)
 }
@@ -157,6 +190,18 @@ func RevokeMessage(callback open_im_sdk_callback.Base, operationID string, conve
 	call(callback, operationID, IMUserContext.Conversation().RevokeMessage, conversationID, clientMsgID)
 }
 
+// SubmitInteractiveAction Route the action of an element of the interactive card to its bot, the payload of a
+// menu is the value of the option chosen. The card edited by the bot comes with OnMsgEdited.
+func SubmitInteractiveAction(callback open_im_sdk_callback.Base, operationID string, conversationID, clientMsgID, actionID, payload string) {
+	call(callback, operationID, IMUserContext.Conversation().SubmitInteractiveAction, conversationID, clientMsgID, actionID, payload)
+}
+
+// GetMessageTranslation Get the translation of the text of the message to the language, the translator set with
+// SetTranslator is asked for it once, then it is cached until the message is edited or revoked.
+func GetMessageTranslation(callback open_im_sdk_callback.Base, operationID string, conversationID, clientMsgID, lang string) {
+	call(callback, operationID, IMUserContext.Conversation().GetMessageTranslation, conversationID, clientMsgID, lang)
+}
+
 func TypingStatusUpdate(callback open_im_sdk_callback.Base, operationID string, recvID string, msgTip string) {
 	call(callback, operationID, IMUserContext.Conversation().TypingStatusUpdate, recvID, msgTip)
 }
@@ -228,3 +273,19 @@ func ChangeInputStates(callback open_im_sdk_callback.Base, operationID string, c
 func GetInputStates(callback open_im_sdk_callback.Base, operationID string, conversationID string, userID string) {
 	call(callback, operationID, IMUserContext.Conversation().GetInputStates, conversationID, userID)
 }
+
+// GetSeqGapStats Get the gaps found in the messages of the conversations since login, the repaired and the pending ones.
+func GetSeqGapStats(callback open_im_sdk_callback.Base, operationID string) {
+	call(callback, operationID, IMUserContext.MsgSyncer().GetSeqGapStats)
+}
+
+// GetMsgWriteStats Get the synced messages written to the local database since login, the transactions and the throughput.
+func GetMsgWriteStats(callback open_im_sdk_callback.Base, operationID string) {
+	call(callback, operationID, IMUserContext.Conversation().GetMsgWriteStats)
+}
+
+// OpenViewOnceMessage Get the view-once picture or video received with its media, once: the media are removed
+// from the message after. Sent with the restrictions of SendMessageWithOptions.
+func OpenViewOnceMessage(callback open_im_sdk_callback.Base, operationID string, conversationID, clientMsgID string) {
+	call(callback, operationID, IMUserContext.Conversation().OpenViewOnceMessage, conversationID, clientMsgID)
+}
@@ -0,0 +1,77 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package open_im_sdk
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/crash"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+// crashDir names the directory in DataDir the crash reports are saved to
+const crashDir = "crash"
+
+// GetCrashReports Get the panics recovered in the SDK since the reports were last uploaded or deleted, the
+// oldest first. Available after InitSDK, so that the app checks them on the next launch.
+func GetCrashReports(callback open_im_sdk_callback.Base, operationID string) {
+	call(callback, operationID, IMUserContext.GetCrashReports)
+}
+
+// UploadCrashReports Upload the crash reports with the logs API, they are removed once uploaded. Returns the
+// number of reports uploaded.
+func UploadCrashReports(callback open_im_sdk_callback.Base, operationID string, ex string, progress open_im_sdk_callback.UploadLogProgress) {
+	call(callback, operationID, IMUserContext.Third().UploadCrashReports, ex, progress)
+}
+
+// DeleteCrashReports Remove the crash reports of the ids.
+func DeleteCrashReports(callback open_im_sdk_callback.Base, operationID string, ids string) {
+	call(callback, operationID, IMUserContext.DeleteCrashReports, ids)
+}
+
+func (u *UserContext) GetCrashReports(ctx context.Context) ([]*sdk_struct.CrashReport, error) {
+	return crash.List()
+}
+
+func (u *UserContext) DeleteCrashReports(ctx context.Context, ids []string) error {
+	return crash.Delete(ids)
+}
+
+// setCrashReports has the panics recovered saved with the tail of the log and the state of the SDK.
+func (u *UserContext) setCrashReports(config *sdk_struct.IMConfig) {
+	if config.DataDir != "" {
+		crash.SetDir(filepath.Join(config.DataDir, crashDir))
+	}
+	crash.SetLogDir(config.LogFilePath)
+	crash.SetState(u.crashState)
+}
+
+// crashState is the state of the SDK kept in a crash report.
+func (u *UserContext) crashState() map[string]any {
+	state := map[string]any{
+		"loginStatus": u.getLoginStatus(context.Background()),
+		"userID":      u.loginUserID,
+	}
+	if config := u.info.Config(); config != nil {
+		state["platformID"] = config.PlatformID
+		state["systemType"] = config.SystemType
+	}
+	if u.longConnMgr != nil {
+		state["connectionStatus"] = u.longConnMgr.GetConnectionStatus()
+	}
+	return state
+}
@@ -0,0 +1,50 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package open_im_sdk
+
+import "github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
+
+// RequestCustomerServiceAgent Ask for an agent of the queue, the default queue for an empty queue. The session
+// waits in the queue until OnSessionAssigned when no agent is free.
+func RequestCustomerServiceAgent(callback open_im_sdk_callback.Base, operationID string, queue string, ex string) {
+	call(callback, operationID, IMUserContext.CustomerService().RequestAgent, queue, ex)
+}
+
+// TransferCustomerServiceSession Hand the session over to another agent, or to the next free agent of the queue
+// for an empty toAgentUserID.
+func TransferCustomerServiceSession(callback open_im_sdk_callback.Base, operationID string, sessionID string, toAgentUserID string, toQueue string, reason string) {
+	call(callback, operationID, IMUserContext.CustomerService().TransferSession, sessionID, toAgentUserID, toQueue, reason)
+}
+
+// CloseCustomerServiceSession Close the session.
+func CloseCustomerServiceSession(callback open_im_sdk_callback.Base, operationID string, sessionID string, reason string) {
+	call(callback, operationID, IMUserContext.CustomerService().CloseSession, sessionID, reason)
+}
+
+// RateCustomerServiceSession Rate the session from 1 to 5 with an optional comment.
+func RateCustomerServiceSession(callback open_im_sdk_callback.Base, operationID string, sessionID string, rating int32, comment string) {
+	call(callback, operationID, IMUserContext.CustomerService().RateSession, sessionID, rating, comment)
+}
+
+// GetCustomerServiceSession Get the local session.
+func GetCustomerServiceSession(callback open_im_sdk_callback.Base, operationID string, sessionID string) {
+	call(callback, operationID, IMUserContext.CustomerService().GetSession, sessionID)
+}
+
+// GetCustomerServiceSessions Get the local sessions, the latest first, only the waiting and serving ones when
+// activeOnly is set.
+func GetCustomerServiceSessions(callback open_im_sdk_callback.Base, operationID string, activeOnly bool) {
+	call(callback, operationID, IMUserContext.CustomerService().GetSessions, activeOnly)
+}
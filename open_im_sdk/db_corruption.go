@@ -0,0 +1,74 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package open_im_sdk
+
+import (
+	"context"
+	"strings"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+
+	"github.com/openimsdk/tools/utils/datautil"
+	"github.com/openimsdk/tools/utils/jsonutil"
+)
+
+// tableResyncScopes are the scopes pulled again from the server when a table was not fully salvaged.
+var tableResyncScopes = map[string][]string{
+	"local_conversations": {constant.ResyncScopeConversations},
+	"local_friends":       {constant.ResyncScopeFriends},
+	"local_blacks":        {constant.ResyncScopeFriends},
+	"local_groups":        {constant.ResyncScopeGroups},
+	"local_group_members": {constant.ResyncScopeGroups},
+	"local_sync_version": {constant.ResyncScopeConversations, constant.ResyncScopeFriends,
+		constant.ResyncScopeGroups, constant.ResyncScopeMessages},
+}
+
+func resyncScopesOf(tables []string) []string {
+	var scopes []string
+	for _, table := range tables {
+		if strings.HasPrefix(table, constant.ChatLogsTableNamePre) {
+			scopes = append(scopes, constant.ResyncScopeMessages)
+			continue
+		}
+		scopes = append(scopes, tableResyncScopes[table]...)
+	}
+	return datautil.Distinct(scopes)
+}
+
+// recoverDBCorruption reports the corruption recovered from at login, the scopes of the tables not fully
+// salvaged are pulled again by resyncDBCorruption once the sdk runs.
+func (u *UserContext) recoverDBCorruption(ctx context.Context, incident *sdk_struct.DBCorruptionIncident) {
+	incident.ResyncScopes = resyncScopesOf(append(append([]string{}, incident.Damaged...), incident.Lost...))
+	log.ZWarn(ctx, "db corruption recovered", nil, "incident", incident)
+	u.DBCorruptionListener().OnDBCorruptionRecovered(jsonutil.StructToJsonString(incident))
+	u.corruptionResyncScopes = incident.ResyncScopes
+}
+
+// resyncDBCorruption pulls the scopes lost to a corruption again, from scratch as the sync versions
+// salvaged would only pull the changes.
+func (u *UserContext) resyncDBCorruption() {
+	scopes := u.corruptionResyncScopes
+	u.corruptionResyncScopes = nil
+	if len(scopes) == 0 {
+		return
+	}
+	go func() {
+		if err := u.ForceResyncAll(u.ctx, scopes); err != nil {
+			log.ZError(u.ctx, "resync after db corruption failed", err, "scopes", scopes)
+		}
+	}()
+}
@@ -0,0 +1,185 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package open_im_sdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/cache"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/network"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/profiling"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/version"
+
+	"github.com/openimsdk/tools/errs"
+)
+
+const (
+	// diagnosticsDir names the directory in DataDir the diagnostics are written to
+	diagnosticsDir    = "diagnostics"
+	diagnosticsPrefix = "diagnostics_"
+	// maxDiagnostics is the number of the diagnostic files kept, the oldest are removed beyond it
+	maxDiagnostics = 5
+	redacted       = "***"
+)
+
+// CollectDiagnostics Collect the history of the connection state, the sync checkpoints, the seq gaps, the size
+// and the stats of the local database, the pending queues, the stats of the runtime and the config into a json file to attach to a bug
+// report. Returns the path of the file. Can be called before login, without the parts that need the database.
+func CollectDiagnostics(callback open_im_sdk_callback.Base, operationID string) {
+	call(callback, operationID, IMUserContext.CollectDiagnostics)
+}
+
+func (u *UserContext) CollectDiagnostics(ctx context.Context) (string, error) {
+	diagnostics := u.diagnostics(ctx)
+	data, err := json.MarshalIndent(diagnostics, "", "  ")
+	if err != nil {
+		return "", errs.WrapMsg(err, "marshal diagnostics failed")
+	}
+	dir := filepath.Join(u.info.Config().DataDir, diagnosticsDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", errs.WrapMsg(err, "create diagnostics dir failed")
+	}
+	path := filepath.Join(dir, diagnosticsPrefix+time.UnixMilli(diagnostics.Time).Format("20060102_150405.000")+".json")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", errs.WrapMsg(err, "write diagnostics failed")
+	}
+	removeOldDiagnostics(ctx, dir)
+	return path, nil
+}
+
+// diagnostics collects the state of the SDK, the parts that fail are left out with their error.
+func (u *UserContext) diagnostics(ctx context.Context) *sdk_struct.Diagnostics {
+	d := &sdk_struct.Diagnostics{
+		Time:        time.Now().UnixMilli(),
+		Version:     version.Version,
+		UserID:      u.info.UserID,
+		LoginStatus: u.getLoginStatus(ctx),
+		Config:      redactConfig(u.info.Config()),
+	}
+	fail := func(part string, err error) {
+		log.ZWarn(ctx, "collect diagnostics failed", err, "part", part)
+		d.Errors = append(d.Errors, part+": "+err.Error())
+	}
+	d.Connection = &sdk_struct.ConnDiagnostics{
+		Status:  u.longConnMgr.GetConnectionStatus(),
+		History: u.longConnMgr.StateHistory(),
+		Traffic: network.GetTrafficStats(),
+	}
+	if n := len(d.Connection.History); n > 0 {
+		d.Connection.State = d.Connection.History[n-1].State
+	}
+	d.Connection.Quality, _ = u.longConnMgr.GetNetworkQuality(ctx)
+	d.Sync = &sdk_struct.SyncDiagnostics{Checkpoints: u.msgSyncer.SyncedMaxSeqs()}
+	d.Sync.SeqGaps, _ = u.msgSyncer.GetSeqGapStats(ctx)
+	d.Sync.MsgWrite, _ = u.conversation.GetMsgWriteStats(ctx)
+	d.Queues = &sdk_struct.QueueDiagnostics{
+		SendTasks:          u.conversation.PendingSendTasks(),
+		WsRequests:         u.longConnMgr.PendingRequests(),
+		SyncCommands:       len(u.msgSyncerCh),
+		ConversationEvents: len(u.conversationEventQueue),
+		Downloads:          make(map[string]int),
+	}
+	for _, info := range u.download.List() {
+		d.Queues.Downloads[info.State]++
+	}
+	d.Runtime = profiling.Stats()
+	d.Caches = cache.GetStats()
+	if d.LoginStatus != Logged {
+		d.Errors = append(d.Errors, "database: not logged in")
+		return d
+	}
+	d.Database = &sdk_struct.DBDiagnostics{Queries: db.GetQueryStats()}
+	var err error
+	if d.Database.SchemaVersion, err = u.db.SchemaVersion(ctx); err != nil {
+		fail("schema version", err)
+	}
+	if d.Database.Storage, err = u.GetStorageUsage(ctx); err != nil {
+		fail("storage", err)
+	}
+	if sending, err := u.db.GetAllSendingMessages(ctx); err != nil {
+		fail("sending messages", err)
+	} else {
+		d.Queues.SendingMessages = len(sending)
+	}
+	return d
+}
+
+// redactConfig copies the config without the passwords of the proxy and the headers of the telemetry, which
+// may hold api keys.
+func redactConfig(config *sdk_struct.IMConfig) *sdk_struct.IMConfig {
+	if config == nil {
+		return nil
+	}
+	c := *config
+	if c.Proxy != nil {
+		proxy := *c.Proxy
+		if proxy.Password != "" {
+			proxy.Password = redacted
+		}
+		proxy.URL, proxy.ApiURL, proxy.WsURL = redactURL(proxy.URL), redactURL(proxy.ApiURL), redactURL(proxy.WsURL)
+		c.Proxy = &proxy
+	}
+	if c.Telemetry != nil {
+		telemetry := *c.Telemetry
+		telemetry.Headers = make(map[string]string, len(c.Telemetry.Headers))
+		for k := range c.Telemetry.Headers {
+			telemetry.Headers[k] = redacted
+		}
+		c.Telemetry = &telemetry
+	}
+	return &c
+}
+
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.User == nil {
+		return rawURL
+	}
+	return u.Redacted()
+}
+
+func removeOldDiagnostics(ctx context.Context, dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasPrefix(entry.Name(), diagnosticsPrefix) {
+			names = append(names, entry.Name())
+		}
+	}
+	if len(names) <= maxDiagnostics {
+		return
+	}
+	// the names sort by the time they were collected
+	sort.Strings(names)
+	for _, name := range names[:len(names)-maxDiagnostics] {
+		if err := os.Remove(filepath.Join(dir, name)); err != nil {
+			log.ZWarn(ctx, "remove old diagnostics failed", err, "name", name)
+		}
+	}
+}
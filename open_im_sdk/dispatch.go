@@ -0,0 +1,719 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package open_im_sdk
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/crash"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+const (
+	// listenerWorkers is the number of listener calls running at the same time
+	listenerWorkers = 4
+	// listenerBacklogWarning is the number of calls waiting for one listener over which a slow listener is logged
+	listenerBacklogWarning = 1000
+	// eventListenerKey is the queue of the envelopes of the events given to the event listener
+	eventListenerKey = "event"
+)
+
+// sharedQueues are the listeners whose calls go to the queue of another one, so that they are delivered in the
+// order they were made: a conversation changed by a message comes after the message.
+var sharedQueues = map[string]string{
+	"advancedMsg": "conversation",
+}
+
+// listenerDispatcher runs the calls of the listeners on a bounded pool of workers, so that the sync and the
+// long connection goroutines only queue them and never wait for the app. The calls of a listener run one at
+// a time in the order they were made, which keeps the events of a conversation in order, while the
+// listeners of different kinds run side by side. Each call is an event stamped with sequence IDs, see
+// sdk_struct.EventEnvelope. The zero value is ready to use.
+type listenerDispatcher struct {
+	mu      sync.Mutex
+	queues  map[string]*listenerQueue
+	ready   []*listenerQueue
+	workers int
+
+	// seq is the sequence ID of the last event, conversationSeqs the one of the last event of each conversation
+	seq              int64
+	conversationSeqs map[string]int64
+	eventListener    open_im_sdk_callback.OnEventListener
+}
+
+type listenerQueue struct {
+	key     string
+	calls   []func()
+	running bool
+	warned  bool
+}
+
+// setEventListener has the envelopes of the events dispatched from now on given to the listener, nil stops it.
+func (d *listenerDispatcher) setEventListener(listener open_im_sdk_callback.OnEventListener) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.eventListener = listener
+}
+
+// dispatch stamps the event of the listener of key with the next sequence IDs, whether or not the app set the
+// listener or the event listener, and queues its call when given, and its envelope for the event listener
+// when set. The conversations of the event are given by the emitter, the data of the envelope is the argument
+// of the call.
+func (d *listenerDispatcher) dispatch(key, event string, conversationIDs []string, call func(), args ...any) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.seq++
+	var conversationSeqs map[string]int64
+	for _, conversationID := range conversationIDs {
+		if d.conversationSeqs == nil {
+			d.conversationSeqs = make(map[string]int64)
+		}
+		d.conversationSeqs[conversationID]++
+		if conversationSeqs == nil {
+			conversationSeqs = make(map[string]int64, len(conversationIDs))
+		}
+		conversationSeqs[conversationID] = d.conversationSeqs[conversationID]
+	}
+	queue := key
+	if shared, ok := sharedQueues[key]; ok {
+		queue = shared
+	}
+	if call != nil {
+		d.enqueue(queue, call)
+	}
+	if d.eventListener == nil {
+		return
+	}
+	envelope := &sdk_struct.EventEnvelope{Seq: d.seq, Listener: key, Event: event, ConversationSeqs: conversationSeqs}
+	listener := d.eventListener
+	d.enqueue(eventListenerKey, func() {
+		envelope.Data = eventData(args)
+		listener.OnEvent(utils.StructToJsonString(envelope))
+	})
+}
+
+// ifSet returns the call of the listener of the app, nil when the app has not set the listener.
+func ifSet[T any](listener T, call func()) func() {
+	if any(listener) == nil {
+		return nil
+	}
+	return call
+}
+
+// enqueue queues the call of the listener of key, called with the lock.
+func (d *listenerDispatcher) enqueue(key string, call func()) {
+	if d.queues == nil {
+		d.queues = make(map[string]*listenerQueue)
+	}
+	q, ok := d.queues[key]
+	if !ok {
+		q = &listenerQueue{key: key}
+		d.queues[key] = q
+	}
+	q.calls = append(q.calls, call)
+	if len(q.calls) > listenerBacklogWarning && !q.warned {
+		q.warned = true
+		log.ZWarn(context.Background(), "listener is slow, calls are piling up", nil, "listener", key, "backlog", len(q.calls))
+	}
+	if q.running || len(q.calls) > 1 {
+		return
+	}
+	d.ready = append(d.ready, q)
+	if d.workers < listenerWorkers {
+		d.workers++
+		go d.work()
+	}
+}
+
+// work runs the calls of the ready listeners, one call at a time per listener, until none is left.
+func (d *listenerDispatcher) work() {
+	d.mu.Lock()
+	for len(d.ready) > 0 {
+		q := d.ready[0]
+		d.ready = d.ready[1:]
+		call := q.calls[0]
+		q.calls = q.calls[1:]
+		q.running = true
+		d.mu.Unlock()
+		d.run(q.key, call)
+		d.mu.Lock()
+		q.running = false
+		if len(q.calls) > 0 {
+			// back at the end, the other listeners waiting get their turn
+			d.ready = append(d.ready, q)
+		} else {
+			delete(d.queues, q.key)
+		}
+	}
+	d.workers--
+	d.mu.Unlock()
+}
+
+func (d *listenerDispatcher) run(key string, call func()) {
+	defer crash.Recover(context.Background(), "listener "+key)
+	call()
+}
+
+// eventData is the argument of a call as the data of its envelope: a string as it is, the json of anything else
+// and the json array of the arguments when there are several.
+func eventData(args []any) string {
+	switch len(args) {
+	case 0:
+		return ""
+	case 1:
+		if data, ok := args[0].(string); ok {
+			return data
+		}
+		return utils.StructToJsonString(args[0])
+	default:
+		return utils.StructToJsonString(args)
+	}
+}
+
+// conversationListIDs returns the conversations of the json array of conversations.
+func conversationListIDs(conversationList string) []string {
+	var conversations []struct {
+		ConversationID string `json:"conversationID"`
+	}
+	if err := json.Unmarshal([]byte(conversationList), &conversations); err != nil {
+		return nil
+	}
+	ids := make([]string, 0, len(conversations))
+	for _, conversation := range conversations {
+		if conversation.ConversationID != "" {
+			ids = append(ids, conversation.ConversationID)
+		}
+	}
+	return ids
+}
+
+// inputStatusConversationIDs returns the conversation of the json of an input status change.
+func inputStatusConversationIDs(change string) []string {
+	var c struct {
+		ConversationID string `json:"conversationID"`
+	}
+	if err := json.Unmarshal([]byte(change), &c); err != nil || c.ConversationID == "" {
+		return nil
+	}
+	return []string{c.ConversationID}
+}
+
+// msgConversationIDs returns the conversation of the json of a message, read from the fields it is made of.
+func msgConversationIDs(message string) []string {
+	var msg struct {
+		SessionType int32  `json:"sessionType"`
+		SendID      string `json:"sendID"`
+		RecvID      string `json:"recvID"`
+		GroupID     string `json:"groupID"`
+	}
+	if err := json.Unmarshal([]byte(message), &msg); err != nil {
+		return nil
+	}
+	conversationID := utils.GetConversationIDByMsg(&sdk_struct.MsgStruct{SessionType: msg.SessionType,
+		SendID: msg.SendID, RecvID: msg.RecvID, GroupID: msg.GroupID})
+	if conversationID == "" {
+		return nil
+	}
+	return []string{conversationID}
+}
+
+// The listeners handed to the modules, queuing their calls to the listeners of the app on the dispatcher.
+
+type dispatchedConnListener struct {
+	d *listenerDispatcher
+	l open_im_sdk_callback.OnConnListener
+}
+
+func (l dispatchedConnListener) OnConnecting() {
+	l.d.dispatch("conn", "OnConnecting", nil, ifSet(l.l, func() { l.l.OnConnecting() }))
+}
+
+func (l dispatchedConnListener) OnConnectSuccess() {
+	l.d.dispatch("conn", "OnConnectSuccess", nil, ifSet(l.l, func() { l.l.OnConnectSuccess() }))
+}
+
+func (l dispatchedConnListener) OnConnectFailed(errCode int32, errMsg string) {
+	l.d.dispatch("conn", "OnConnectFailed", nil, ifSet(l.l, func() { l.l.OnConnectFailed(errCode, errMsg) }), errCode, errMsg)
+}
+
+func (l dispatchedConnListener) OnKickedOffline() {
+	l.d.dispatch("conn", "OnKickedOffline", nil, ifSet(l.l, func() { l.l.OnKickedOffline() }))
+}
+
+func (l dispatchedConnListener) OnUserTokenExpired() {
+	l.d.dispatch("conn", "OnUserTokenExpired", nil, ifSet(l.l, func() { l.l.OnUserTokenExpired() }))
+}
+
+func (l dispatchedConnListener) OnUserTokenInvalid(errMsg string) {
+	l.d.dispatch("conn", "OnUserTokenInvalid", nil, ifSet(l.l, func() { l.l.OnUserTokenInvalid(errMsg) }), errMsg)
+}
+
+type dispatchedGroupListener struct {
+	d *listenerDispatcher
+	l open_im_sdk_callback.OnGroupListener
+}
+
+func (l dispatchedGroupListener) OnJoinedGroupAdded(groupInfo string) {
+	l.d.dispatch("group", "OnJoinedGroupAdded", nil, ifSet(l.l, func() { l.l.OnJoinedGroupAdded(groupInfo) }), groupInfo)
+}
+
+func (l dispatchedGroupListener) OnJoinedGroupDeleted(groupInfo string) {
+	l.d.dispatch("group", "OnJoinedGroupDeleted", nil, ifSet(l.l, func() { l.l.OnJoinedGroupDeleted(groupInfo) }), groupInfo)
+}
+
+func (l dispatchedGroupListener) OnGroupMemberAdded(groupMemberInfo string) {
+	l.d.dispatch("group", "OnGroupMemberAdded", nil, ifSet(l.l, func() { l.l.OnGroupMemberAdded(groupMemberInfo) }), groupMemberInfo)
+}
+
+func (l dispatchedGroupListener) OnGroupMemberDeleted(groupMemberInfo string) {
+	l.d.dispatch("group", "OnGroupMemberDeleted", nil, ifSet(l.l, func() { l.l.OnGroupMemberDeleted(groupMemberInfo) }), groupMemberInfo)
+}
+
+func (l dispatchedGroupListener) OnGroupApplicationAdded(groupApplication string) {
+	l.d.dispatch("group", "OnGroupApplicationAdded", nil, ifSet(l.l, func() { l.l.OnGroupApplicationAdded(groupApplication) }), groupApplication)
+}
+
+func (l dispatchedGroupListener) OnGroupApplicationDeleted(groupApplication string) {
+	l.d.dispatch("group", "OnGroupApplicationDeleted", nil, ifSet(l.l, func() { l.l.OnGroupApplicationDeleted(groupApplication) }), groupApplication)
+}
+
+func (l dispatchedGroupListener) OnGroupInfoChanged(groupInfo string) {
+	l.d.dispatch("group", "OnGroupInfoChanged", nil, ifSet(l.l, func() { l.l.OnGroupInfoChanged(groupInfo) }), groupInfo)
+}
+
+func (l dispatchedGroupListener) OnGroupDismissed(groupInfo string) {
+	l.d.dispatch("group", "OnGroupDismissed", nil, ifSet(l.l, func() { l.l.OnGroupDismissed(groupInfo) }), groupInfo)
+}
+
+func (l dispatchedGroupListener) OnGroupMemberInfoChanged(groupMemberInfo string) {
+	l.d.dispatch("group", "OnGroupMemberInfoChanged", nil, ifSet(l.l, func() { l.l.OnGroupMemberInfoChanged(groupMemberInfo) }), groupMemberInfo)
+}
+
+func (l dispatchedGroupListener) OnGroupApplicationAccepted(groupApplication string) {
+	l.d.dispatch("group", "OnGroupApplicationAccepted", nil, ifSet(l.l, func() { l.l.OnGroupApplicationAccepted(groupApplication) }), groupApplication)
+}
+
+func (l dispatchedGroupListener) OnGroupApplicationRejected(groupApplication string) {
+	l.d.dispatch("group", "OnGroupApplicationRejected", nil, ifSet(l.l, func() { l.l.OnGroupApplicationRejected(groupApplication) }), groupApplication)
+}
+
+type dispatchedFriendshipListener struct {
+	d *listenerDispatcher
+	l open_im_sdk_callback.OnFriendshipListener
+}
+
+func (l dispatchedFriendshipListener) OnFriendApplicationAdded(friendApplication string) {
+	l.d.dispatch("friendship", "OnFriendApplicationAdded", nil, ifSet(l.l, func() { l.l.OnFriendApplicationAdded(friendApplication) }), friendApplication)
+}
+
+func (l dispatchedFriendshipListener) OnFriendApplicationDeleted(friendApplication string) {
+	l.d.dispatch("friendship", "OnFriendApplicationDeleted", nil, ifSet(l.l, func() { l.l.OnFriendApplicationDeleted(friendApplication) }), friendApplication)
+}
+
+func (l dispatchedFriendshipListener) OnFriendApplicationAccepted(friendApplication string) {
+	l.d.dispatch("friendship", "OnFriendApplicationAccepted", nil, ifSet(l.l, func() { l.l.OnFriendApplicationAccepted(friendApplication) }), friendApplication)
+}
+
+func (l dispatchedFriendshipListener) OnFriendApplicationRejected(friendApplication string) {
+	l.d.dispatch("friendship", "OnFriendApplicationRejected", nil, ifSet(l.l, func() { l.l.OnFriendApplicationRejected(friendApplication) }), friendApplication)
+}
+
+func (l dispatchedFriendshipListener) OnFriendAdded(friendInfo string) {
+	l.d.dispatch("friendship", "OnFriendAdded", nil, ifSet(l.l, func() { l.l.OnFriendAdded(friendInfo) }), friendInfo)
+}
+
+func (l dispatchedFriendshipListener) OnFriendDeleted(friendInfo string) {
+	l.d.dispatch("friendship", "OnFriendDeleted", nil, ifSet(l.l, func() { l.l.OnFriendDeleted(friendInfo) }), friendInfo)
+}
+
+func (l dispatchedFriendshipListener) OnFriendInfoChanged(friendInfo string) {
+	l.d.dispatch("friendship", "OnFriendInfoChanged", nil, ifSet(l.l, func() { l.l.OnFriendInfoChanged(friendInfo) }), friendInfo)
+}
+
+func (l dispatchedFriendshipListener) OnBlackAdded(blackInfo string) {
+	l.d.dispatch("friendship", "OnBlackAdded", nil, ifSet(l.l, func() { l.l.OnBlackAdded(blackInfo) }), blackInfo)
+}
+
+func (l dispatchedFriendshipListener) OnBlackDeleted(blackInfo string) {
+	l.d.dispatch("friendship", "OnBlackDeleted", nil, ifSet(l.l, func() { l.l.OnBlackDeleted(blackInfo) }), blackInfo)
+}
+
+type dispatchedConversationListener struct {
+	d *listenerDispatcher
+	l open_im_sdk_callback.OnConversationListener
+}
+
+func (l dispatchedConversationListener) OnSyncServerStart(reinstalled bool) {
+	l.d.dispatch("conversation", "OnSyncServerStart", nil, ifSet(l.l, func() { l.l.OnSyncServerStart(reinstalled) }), reinstalled)
+}
+
+func (l dispatchedConversationListener) OnSyncServerFinish(reinstalled bool) {
+	l.d.dispatch("conversation", "OnSyncServerFinish", nil, ifSet(l.l, func() { l.l.OnSyncServerFinish(reinstalled) }), reinstalled)
+}
+
+func (l dispatchedConversationListener) OnSyncServerProgress(progress int) {
+	l.d.dispatch("conversation", "OnSyncServerProgress", nil, ifSet(l.l, func() { l.l.OnSyncServerProgress(progress) }), progress)
+}
+
+func (l dispatchedConversationListener) OnSyncServerFailed(reinstalled bool) {
+	l.d.dispatch("conversation", "OnSyncServerFailed", nil, ifSet(l.l, func() { l.l.OnSyncServerFailed(reinstalled) }), reinstalled)
+}
+
+func (l dispatchedConversationListener) OnNewConversation(conversationList string) {
+	l.d.dispatch("conversation", "OnNewConversation", conversationListIDs(conversationList), ifSet(l.l, func() { l.l.OnNewConversation(conversationList) }), conversationList)
+}
+
+func (l dispatchedConversationListener) OnConversationChanged(conversationList string) {
+	l.d.dispatch("conversation", "OnConversationChanged", conversationListIDs(conversationList), ifSet(l.l, func() { l.l.OnConversationChanged(conversationList) }), conversationList)
+}
+
+func (l dispatchedConversationListener) OnTotalUnreadMessageCountChanged(totalUnreadCount int32) {
+	l.d.dispatch("conversation", "OnTotalUnreadMessageCountChanged", nil, ifSet(l.l, func() { l.l.OnTotalUnreadMessageCountChanged(totalUnreadCount) }), totalUnreadCount)
+}
+
+func (l dispatchedConversationListener) OnConversationUserInputStatusChanged(change string) {
+	l.d.dispatch("conversation", "OnConversationUserInputStatusChanged", inputStatusConversationIDs(change), ifSet(l.l, func() { l.l.OnConversationUserInputStatusChanged(change) }), change)
+}
+
+type dispatchedAdvancedMsgListener struct {
+	d *listenerDispatcher
+	l open_im_sdk_callback.OnAdvancedMsgListener
+}
+
+func (l dispatchedAdvancedMsgListener) OnRecvNewMessage(message string) {
+	l.d.dispatch("advancedMsg", "OnRecvNewMessage", msgConversationIDs(message), ifSet(l.l, func() { l.l.OnRecvNewMessage(message) }), message)
+}
+
+func (l dispatchedAdvancedMsgListener) OnRecvC2CReadReceipt(msgReceiptList string) {
+	l.d.dispatch("advancedMsg", "OnRecvC2CReadReceipt", nil, ifSet(l.l, func() { l.l.OnRecvC2CReadReceipt(msgReceiptList) }), msgReceiptList)
+}
+
+func (l dispatchedAdvancedMsgListener) OnNewRecvMessageRevoked(messageRevoked string) {
+	l.d.dispatch("advancedMsg", "OnNewRecvMessageRevoked", nil, ifSet(l.l, func() { l.l.OnNewRecvMessageRevoked(messageRevoked) }), messageRevoked)
+}
+
+func (l dispatchedAdvancedMsgListener) OnRecvOfflineNewMessage(message string) {
+	l.d.dispatch("advancedMsg", "OnRecvOfflineNewMessage", msgConversationIDs(message), ifSet(l.l, func() { l.l.OnRecvOfflineNewMessage(message) }), message)
+}
+
+func (l dispatchedAdvancedMsgListener) OnMsgDeleted(message string) {
+	l.d.dispatch("advancedMsg", "OnMsgDeleted", msgConversationIDs(message), ifSet(l.l, func() { l.l.OnMsgDeleted(message) }), message)
+}
+
+func (l dispatchedAdvancedMsgListener) OnRecvOnlineOnlyMessage(message string) {
+	l.d.dispatch("advancedMsg", "OnRecvOnlineOnlyMessage", msgConversationIDs(message), ifSet(l.l, func() { l.l.OnRecvOnlineOnlyMessage(message) }), message)
+}
+
+func (l dispatchedAdvancedMsgListener) OnMsgEdited(message string) {
+	l.d.dispatch("advancedMsg", "OnMsgEdited", msgConversationIDs(message), ifSet(l.l, func() { l.l.OnMsgEdited(message) }), message)
+}
+
+type dispatchedUserListener struct {
+	d *listenerDispatcher
+	l open_im_sdk_callback.OnUserListener
+}
+
+func (l dispatchedUserListener) OnSelfInfoUpdated(userInfo string) {
+	l.d.dispatch("user", "OnSelfInfoUpdated", nil, ifSet(l.l, func() { l.l.OnSelfInfoUpdated(userInfo) }), userInfo)
+}
+
+func (l dispatchedUserListener) OnUserStatusChanged(userOnlineStatus string) {
+	l.d.dispatch("user", "OnUserStatusChanged", nil, ifSet(l.l, func() { l.l.OnUserStatusChanged(userOnlineStatus) }), userOnlineStatus)
+}
+
+type dispatchedBusinessListener struct {
+	d *listenerDispatcher
+	l open_im_sdk_callback.OnCustomBusinessListener
+}
+
+func (l dispatchedBusinessListener) OnRecvCustomBusinessMessage(businessMessage string) {
+	l.d.dispatch("business", "OnRecvCustomBusinessMessage", nil, ifSet(l.l, func() { l.l.OnRecvCustomBusinessMessage(businessMessage) }), businessMessage)
+}
+
+type dispatchedMsgKvListener struct {
+	d *listenerDispatcher
+	l open_im_sdk_callback.OnMessageKvInfoListener
+}
+
+func (l dispatchedMsgKvListener) OnMessageKvInfoChanged(messageChangedList string) {
+	l.d.dispatch("msgKv", "OnMessageKvInfoChanged", nil, ifSet(l.l, func() { l.l.OnMessageKvInfoChanged(messageChangedList) }), messageChangedList)
+}
+
+type dispatchedConnStateListener struct {
+	d *listenerDispatcher
+	l open_im_sdk_callback.OnConnStateListener
+}
+
+func (l dispatchedConnStateListener) OnConnStateChanged(state string) {
+	l.d.dispatch("connState", "OnConnStateChanged", nil, ifSet(l.l, func() { l.l.OnConnStateChanged(state) }), state)
+}
+
+type dispatchedNetworkQualityListener struct {
+	d *listenerDispatcher
+	l open_im_sdk_callback.OnNetworkQualityListener
+}
+
+func (l dispatchedNetworkQualityListener) OnNetworkQualityChanged(quality string) {
+	l.d.dispatch("networkQuality", "OnNetworkQualityChanged", nil, ifSet(l.l, func() { l.l.OnNetworkQualityChanged(quality) }), quality)
+}
+
+type dispatchedSyncConflictListener struct {
+	d *listenerDispatcher
+	l open_im_sdk_callback.OnSyncConflictListener
+}
+
+func (l dispatchedSyncConflictListener) OnSyncConflict(conflict string) {
+	l.d.dispatch("syncConflict", "OnSyncConflict", nil, ifSet(l.l, func() { l.l.OnSyncConflict(conflict) }), conflict)
+}
+
+type dispatchedSyncProgressListener struct {
+	d *listenerDispatcher
+	l open_im_sdk_callback.OnSyncProgressListener
+}
+
+func (l dispatchedSyncProgressListener) OnSyncProgress(progress string) {
+	l.d.dispatch("syncProgress", "OnSyncProgress", nil, ifSet(l.l, func() { l.l.OnSyncProgress(progress) }), progress)
+}
+
+type dispatchedDBMigrationListener struct {
+	d *listenerDispatcher
+	l open_im_sdk_callback.OnDBMigrationListener
+}
+
+func (l dispatchedDBMigrationListener) OnDBMigrationProgress(progress string) {
+	l.d.dispatch("dbMigration", "OnDBMigrationProgress", nil, ifSet(l.l, func() { l.l.OnDBMigrationProgress(progress) }), progress)
+}
+
+type dispatchedDBCorruptionListener struct {
+	d *listenerDispatcher
+	l open_im_sdk_callback.OnDBCorruptionListener
+}
+
+func (l dispatchedDBCorruptionListener) OnDBCorruptionRecovered(incident string) {
+	l.d.dispatch("dbCorruption", "OnDBCorruptionRecovered", nil, ifSet(l.l, func() { l.l.OnDBCorruptionRecovered(incident) }), incident)
+}
+
+type dispatchedDownloadListener struct {
+	d *listenerDispatcher
+	l open_im_sdk_callback.OnDownloadListener
+}
+
+func (l dispatchedDownloadListener) OnDownloadStateChanged(info string) {
+	l.d.dispatch("download", "OnDownloadStateChanged", nil, ifSet(l.l, func() { l.l.OnDownloadStateChanged(info) }), info)
+}
+
+func (l dispatchedDownloadListener) OnDownloadProgress(info string) {
+	l.d.dispatch("download", "OnDownloadProgress", nil, ifSet(l.l, func() { l.l.OnDownloadProgress(info) }), info)
+}
+
+func (l dispatchedDownloadListener) OnDownloadTotalProgress(progress string) {
+	l.d.dispatch("download", "OnDownloadTotalProgress", nil, ifSet(l.l, func() { l.l.OnDownloadTotalProgress(progress) }), progress)
+}
+
+type dispatchedMediaCacheListener struct {
+	d *listenerDispatcher
+	l open_im_sdk_callback.OnMediaCacheListener
+}
+
+func (l dispatchedMediaCacheListener) OnMediaEvicted(eviction string) {
+	l.d.dispatch("mediaCache", "OnMediaEvicted", nil, ifSet(l.l, func() { l.l.OnMediaEvicted(eviction) }), eviction)
+}
+
+type dispatchedSdkErrorListener struct {
+	d *listenerDispatcher
+	l open_im_sdk_callback.OnSdkErrorListener
+}
+
+func (l dispatchedSdkErrorListener) OnSdkError(sdkError string) {
+	l.d.dispatch("sdkError", "OnSdkError", nil, ifSet(l.l, func() { l.l.OnSdkError(sdkError) }), sdkError)
+}
+
+type dispatchedMomentsListener struct {
+	d *listenerDispatcher
+	l open_im_sdk_callback.OnMomentsListener
+}
+
+func (l dispatchedMomentsListener) OnNewMoment(moment string) {
+	l.d.dispatch("moments", "OnNewMoment", nil, ifSet(l.l, func() { l.l.OnNewMoment(moment) }), moment)
+}
+
+func (l dispatchedMomentsListener) OnMomentDeleted(momentID string) {
+	l.d.dispatch("moments", "OnMomentDeleted", nil, ifSet(l.l, func() { l.l.OnMomentDeleted(momentID) }), momentID)
+}
+
+func (l dispatchedMomentsListener) OnMomentLiked(momentTips string) {
+	l.d.dispatch("moments", "OnMomentLiked", nil, ifSet(l.l, func() { l.l.OnMomentLiked(momentTips) }), momentTips)
+}
+
+func (l dispatchedMomentsListener) OnMomentCommented(momentTips string) {
+	l.d.dispatch("moments", "OnMomentCommented", nil, ifSet(l.l, func() { l.l.OnMomentCommented(momentTips) }), momentTips)
+}
+
+type dispatchedOrganizationListener struct {
+	d *listenerDispatcher
+	l open_im_sdk_callback.OnOrganizationListener
+}
+
+func (l dispatchedOrganizationListener) OnDepartmentAdded(departmentInfo string) {
+	l.d.dispatch("organization", "OnDepartmentAdded", nil, ifSet(l.l, func() { l.l.OnDepartmentAdded(departmentInfo) }), departmentInfo)
+}
+
+func (l dispatchedOrganizationListener) OnDepartmentDeleted(departmentInfo string) {
+	l.d.dispatch("organization", "OnDepartmentDeleted", nil, ifSet(l.l, func() { l.l.OnDepartmentDeleted(departmentInfo) }), departmentInfo)
+}
+
+func (l dispatchedOrganizationListener) OnDepartmentInfoChanged(departmentInfo string) {
+	l.d.dispatch("organization", "OnDepartmentInfoChanged", nil, ifSet(l.l, func() { l.l.OnDepartmentInfoChanged(departmentInfo) }), departmentInfo)
+}
+
+func (l dispatchedOrganizationListener) OnDepartmentMemberAdded(memberInfo string) {
+	l.d.dispatch("organization", "OnDepartmentMemberAdded", nil, ifSet(l.l, func() { l.l.OnDepartmentMemberAdded(memberInfo) }), memberInfo)
+}
+
+func (l dispatchedOrganizationListener) OnDepartmentMemberDeleted(memberInfo string) {
+	l.d.dispatch("organization", "OnDepartmentMemberDeleted", nil, ifSet(l.l, func() { l.l.OnDepartmentMemberDeleted(memberInfo) }), memberInfo)
+}
+
+func (l dispatchedOrganizationListener) OnDepartmentMemberInfoChanged(memberInfo string) {
+	l.d.dispatch("organization", "OnDepartmentMemberInfoChanged", nil, ifSet(l.l, func() { l.l.OnDepartmentMemberInfoChanged(memberInfo) }), memberInfo)
+}
+
+type dispatchedClientConfigListener struct {
+	d *listenerDispatcher
+	l open_im_sdk_callback.OnClientConfigListener
+}
+
+func (l dispatchedClientConfigListener) OnClientConfigChanged(config string) {
+	l.d.dispatch("clientConfig", "OnClientConfigChanged", nil, ifSet(l.l, func() { l.l.OnClientConfigChanged(config) }), config)
+}
+
+type dispatchedCustomerServiceListener struct {
+	d *listenerDispatcher
+	l open_im_sdk_callback.OnCustomerServiceListener
+}
+
+func (l dispatchedCustomerServiceListener) OnSessionQueueChanged(sessionInfo string) {
+	l.d.dispatch("customerService", "OnSessionQueueChanged", nil, ifSet(l.l, func() { l.l.OnSessionQueueChanged(sessionInfo) }), sessionInfo)
+}
+
+func (l dispatchedCustomerServiceListener) OnSessionAssigned(sessionInfo string) {
+	l.d.dispatch("customerService", "OnSessionAssigned", nil, ifSet(l.l, func() { l.l.OnSessionAssigned(sessionInfo) }), sessionInfo)
+}
+
+func (l dispatchedCustomerServiceListener) OnSessionTransferred(sessionTips string) {
+	l.d.dispatch("customerService", "OnSessionTransferred", nil, ifSet(l.l, func() { l.l.OnSessionTransferred(sessionTips) }), sessionTips)
+}
+
+func (l dispatchedCustomerServiceListener) OnSessionClosed(sessionTips string) {
+	l.d.dispatch("customerService", "OnSessionClosed", nil, ifSet(l.l, func() { l.l.OnSessionClosed(sessionTips) }), sessionTips)
+}
+
+type dispatchedE2EEListener struct {
+	d *listenerDispatcher
+	l open_im_sdk_callback.OnE2EEListener
+}
+
+func (l dispatchedE2EEListener) OnIdentityKeyChanged(userID string) {
+	l.d.dispatch("e2ee", "OnIdentityKeyChanged", nil, ifSet(l.l, func() { l.l.OnIdentityKeyChanged(userID) }), userID)
+}
+
+func (l dispatchedE2EEListener) OnDevicesChanged(change string) {
+	l.d.dispatch("e2ee", "OnDevicesChanged", nil, ifSet(l.l, func() { l.l.OnDevicesChanged(change) }), change)
+}
+
+type dispatchedAppLifecycleListener struct {
+	d *listenerDispatcher
+	l open_im_sdk_callback.OnAppLifecycleListener
+}
+
+func (l dispatchedAppLifecycleListener) OnSyncCaughtUp() {
+	l.d.dispatch("appLifecycle", "OnSyncCaughtUp", nil, ifSet(l.l, func() { l.l.OnSyncCaughtUp() }))
+}
+
+type dispatchedTokenListener struct {
+	d *listenerDispatcher
+	l open_im_sdk_callback.OnTokenListener
+}
+
+func (l dispatchedTokenListener) OnTokenWillExpire(expireTime int64) {
+	l.d.dispatch("token", "OnTokenWillExpire", nil, ifSet(l.l, func() { l.l.OnTokenWillExpire(expireTime) }), expireTime)
+}
+
+type dispatchedQRLoginListener struct {
+	d *listenerDispatcher
+	l open_im_sdk_callback.OnQRLoginListener
+}
+
+func (l dispatchedQRLoginListener) OnQRLoginStateChanged(state string) {
+	l.d.dispatch("qrLogin", "OnQRLoginStateChanged", nil, ifSet(l.l, func() { l.l.OnQRLoginStateChanged(state) }), state)
+}
+
+type dispatchedSignalingListener struct {
+	d *listenerDispatcher
+	l open_im_sdk_callback.OnSignalingListener
+}
+
+func (l dispatchedSignalingListener) OnReceiveNewInvitation(receiveNewInvitationCallback string) {
+	l.d.dispatch("signaling", "OnReceiveNewInvitation", nil, ifSet(l.l, func() { l.l.OnReceiveNewInvitation(receiveNewInvitationCallback) }), receiveNewInvitationCallback)
+}
+
+func (l dispatchedSignalingListener) OnInviteeAccepted(inviteeAcceptedCallback string) {
+	l.d.dispatch("signaling", "OnInviteeAccepted", nil, ifSet(l.l, func() { l.l.OnInviteeAccepted(inviteeAcceptedCallback) }), inviteeAcceptedCallback)
+}
+
+func (l dispatchedSignalingListener) OnInviteeAcceptedByOtherDevice(inviteeAcceptedCallback string) {
+	l.d.dispatch("signaling", "OnInviteeAcceptedByOtherDevice", nil, ifSet(l.l, func() { l.l.OnInviteeAcceptedByOtherDevice(inviteeAcceptedCallback) }), inviteeAcceptedCallback)
+}
+
+func (l dispatchedSignalingListener) OnInviteeRejected(inviteeRejectedCallback string) {
+	l.d.dispatch("signaling", "OnInviteeRejected", nil, ifSet(l.l, func() { l.l.OnInviteeRejected(inviteeRejectedCallback) }), inviteeRejectedCallback)
+}
+
+func (l dispatchedSignalingListener) OnInviteeRejectedByOtherDevice(inviteeRejectedCallback string) {
+	l.d.dispatch("signaling", "OnInviteeRejectedByOtherDevice", nil, ifSet(l.l, func() { l.l.OnInviteeRejectedByOtherDevice(inviteeRejectedCallback) }), inviteeRejectedCallback)
+}
+
+func (l dispatchedSignalingListener) OnInviteeBusy(inviteeBusyCallback string) {
+	l.d.dispatch("signaling", "OnInviteeBusy", nil, ifSet(l.l, func() { l.l.OnInviteeBusy(inviteeBusyCallback) }), inviteeBusyCallback)
+}
+
+func (l dispatchedSignalingListener) OnInvitationCancelled(invitationCancelledCallback string) {
+	l.d.dispatch("signaling", "OnInvitationCancelled", nil, ifSet(l.l, func() { l.l.OnInvitationCancelled(invitationCancelledCallback) }), invitationCancelledCallback)
+}
+
+func (l dispatchedSignalingListener) OnInvitationTimeout(invitationTimeoutCallback string) {
+	l.d.dispatch("signaling", "OnInvitationTimeout", nil, ifSet(l.l, func() { l.l.OnInvitationTimeout(invitationTimeoutCallback) }), invitationTimeoutCallback)
+}
+
+func (l dispatchedSignalingListener) OnHangUp(hangUpCallback string) {
+	l.d.dispatch("signaling", "OnHangUp", nil, ifSet(l.l, func() { l.l.OnHangUp(hangUpCallback) }), hangUpCallback)
+}
+
+func (l dispatchedSignalingListener) OnRoomParticipantConnected(onRoomParticipantConnectedCallback string) {
+	l.d.dispatch("signaling", "OnRoomParticipantConnected", nil, ifSet(l.l, func() { l.l.OnRoomParticipantConnected(onRoomParticipantConnectedCallback) }), onRoomParticipantConnectedCallback)
+}
+
+func (l dispatchedSignalingListener) OnRoomParticipantDisconnected(onRoomParticipantDisconnectedCallback string) {
+	l.d.dispatch("signaling", "OnRoomParticipantDisconnected", nil, ifSet(l.l, func() { l.l.OnRoomParticipantDisconnected(onRoomParticipantDisconnectedCallback) }), onRoomParticipantDisconnectedCallback)
+}
+
+func (l dispatchedSignalingListener) OnReceiveSignalingData(signalingData string) {
+	l.d.dispatch("signaling", "OnReceiveSignalingData", nil, ifSet(l.l, func() { l.l.OnReceiveSignalingData(signalingData) }), signalingData)
+}
@@ -0,0 +1,36 @@
+package open_im_sdk
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+type eventRecorder chan string
+
+func (r eventRecorder) OnEvent(envelope string) { r <- envelope }
+
+// the events of a listener the app has not set are stamped as well
+func TestDispatchSeqs(t *testing.T) {
+	var d listenerDispatcher
+	msgs := dispatchedAdvancedMsgListener{d: &d}
+	message := `{"sessionType":1,"sendID":"b","recvID":"a","content":"hi"}`
+	msgs.OnRecvNewMessage(message)
+	events := make(eventRecorder, 1)
+	d.setEventListener(events)
+	msgs.OnRecvNewMessage(message)
+	select {
+	case data := <-events:
+		var envelope sdk_struct.EventEnvelope
+		if err := json.Unmarshal([]byte(data), &envelope); err != nil {
+			t.Fatal(err)
+		}
+		if envelope.Seq != 2 || envelope.ConversationSeqs["si_a_b"] != 2 || envelope.Data != message {
+			t.Fatal(data)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no envelope")
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !no_files
+// +build !no_files
+
+package open_im_sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/openimsdk/openim-sdk-core/v3/internal/download"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+// downloadManager is the download manager of the user context, nil before it is initialized.
+func (u *UserContext) downloadManager() *download.Manager {
+	m, _ := u.download.(*download.Manager)
+	return m
+}
+
+func (u *UserContext) EnqueueDownload(ctx context.Context, req *sdk_struct.DownloadReq) (*sdk_struct.DownloadInfo, error) {
+	return u.downloadManager().Enqueue(ctx, req)
+}
+
+func (u *UserContext) PauseDownload(ctx context.Context, downloadID string) error {
+	return u.downloadManager().Pause(ctx, downloadID)
+}
+
+func (u *UserContext) ResumeDownload(ctx context.Context, downloadID string) error {
+	return u.downloadManager().Resume(ctx, downloadID)
+}
+
+func (u *UserContext) CancelDownload(ctx context.Context, downloadID string) error {
+	return u.downloadManager().Cancel(ctx, downloadID)
+}
+
+func (u *UserContext) GetDownloads(ctx context.Context) ([]*sdk_struct.DownloadInfo, error) {
+	return u.download.List(), nil
+}
+
+// maxMediaChunk is the most bytes ReadMediaFile reads at once.
+const maxMediaChunk = 4 << 20
+
+func (u *UserContext) GetMediaFilePath(ctx context.Context, filePath string) (string, error) {
+	path, err := u.downloadManager().DecryptedPath(ctx, filePath)
+	if err != nil {
+		return "", mediaFileError(err)
+	}
+	return path, nil
+}
+
+func (u *UserContext) ReadMediaFile(ctx context.Context, filePath string, offset, length int64) (*sdk_struct.MediaFileChunk, error) {
+	if offset < 0 || length <= 0 || length > maxMediaChunk {
+		return nil, sdkerrs.ErrArgs.WrapMsg(fmt.Sprintf("invalid offset %d or length %d, at most %d bytes are read at once", offset, length, maxMediaChunk))
+	}
+	media, err := u.downloadManager().OpenFile(filePath)
+	if err != nil {
+		return nil, mediaFileError(err)
+	}
+	defer media.Close()
+	chunk := &sdk_struct.MediaFileChunk{Size: media.Size()}
+	if offset >= chunk.Size {
+		chunk.EOF = true
+		return chunk, nil
+	}
+	if _, err := media.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	chunk.Data = make([]byte, min(length, chunk.Size-offset))
+	if _, err := io.ReadFull(media, chunk.Data); err != nil {
+		return nil, err
+	}
+	chunk.EOF = offset+int64(len(chunk.Data)) >= chunk.Size
+	return chunk, nil
+}
+
+// mediaFileError tells the app a missing file or key apart from a failure.
+func mediaFileError(err error) error {
+	if errors.Is(err, os.ErrNotExist) || errors.Is(err, download.ErrNoMediaKey) || errors.Is(err, download.ErrWrongMediaKey) {
+		return sdkerrs.ErrArgs.WrapMsg(err.Error())
+	}
+	return err
+}
@@ -0,0 +1,86 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package open_im_sdk
+
+import (
+	"context"
+
+	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+)
+
+// EnableE2EE Generate the encryption keys of the login user and publish them, the single chats with the users
+// who enabled it are encrypted end to end from then on, the others stay in plain.
+func EnableE2EE(callback open_im_sdk_callback.Base, operationID string) {
+	call(callback, operationID, IMUserContext.E2EE().Enable)
+}
+
+// IsE2EEEnabled Whether the login user enabled the end-to-end encryption.
+func IsE2EEEnabled(callback open_im_sdk_callback.Base, operationID string) {
+	call(callback, operationID, IMUserContext.E2EE().IsEnabled)
+}
+
+// ResetE2EESession Drop the encryption session with the user, the next message establishes a new one.
+func ResetE2EESession(callback open_im_sdk_callback.Base, operationID string, userID string) {
+	call(callback, operationID, IMUserContext.E2EE().ResetSession, userID)
+}
+
+// BackupE2EEKeys Upload the encryption keys and sessions encrypted with the passphrase, a new device restores
+// them with RestoreE2EEKeys.
+func BackupE2EEKeys(callback open_im_sdk_callback.Base, operationID string, passphrase string) {
+	call(callback, operationID, IMUserContext.E2EE().Backup, passphrase)
+}
+
+// RestoreE2EEKeys Restore the encryption keys and sessions backed up with the passphrase, the messages stored
+// before as they could not be decrypted are decrypted, the callback gets their count.
+func RestoreE2EEKeys(callback open_im_sdk_callback.Base, operationID string, passphrase string) {
+	call(callback, operationID, IMUserContext.RestoreE2EEKeys, passphrase)
+}
+
+func (u *UserContext) RestoreE2EEKeys(ctx context.Context, passphrase string) (int, error) {
+	if err := u.e2ee.Restore(ctx, passphrase); err != nil {
+		return 0, err
+	}
+	return u.conversation.DecryptStoredMessages(ctx)
+}
+
+// GetE2EEDevices Get the devices of the user with their fingerprints, safety numbers and verification, the
+// changes since they were last seen are reported to OnDevicesChanged.
+func GetE2EEDevices(callback open_im_sdk_callback.Base, operationID string, userID string) {
+	call(callback, operationID, IMUserContext.E2EE().GetDevices, userID)
+}
+
+// GetSelfE2EEDevice Get the device of the login user with its fingerprint.
+func GetSelfE2EEDevice(callback open_im_sdk_callback.Base, operationID string) {
+	call(callback, operationID, IMUserContext.E2EE().GetSelfDevice)
+}
+
+// SetE2EEDeviceVerified Mark the device of the user as verified or not.
+func SetE2EEDeviceVerified(callback open_im_sdk_callback.Base, operationID string, userID, deviceID string, verified bool) {
+	call(callback, operationID, IMUserContext.E2EE().SetDeviceVerified, userID, deviceID, verified)
+}
+
+// VerifyE2EESafetyNumber Mark the device of the user with the safety number as verified, the callback gets
+// false when no device of the user has it.
+func VerifyE2EESafetyNumber(callback open_im_sdk_callback.Base, operationID string, userID, safetyNumber string) {
+	call(callback, operationID, IMUserContext.E2EE().VerifySafetyNumber, userID, safetyNumber)
+}
+
+// replenishPreKeys publishes more one time pre keys at login when the server is running out of them.
+func (u *UserContext) replenishPreKeys(ctx context.Context) {
+	if err := u.e2ee.ReplenishPreKeys(ctx); err != nil {
+		log.ZWarn(ctx, "replenish e2ee pre keys failed", err)
+	}
+}
@@ -19,7 +19,7 @@ import (
 	"errors"
 
 	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
-	"github.com/openimsdk/tools/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
 )
 
 var ErrNotImplemented = errors.New("not set listener")
@@ -270,3 +270,317 @@ func (e *emptyCustomBusinessListener) OnRecvCustomBusinessMessage(businessMessag
 		"businessMessage", businessMessage)
 
 }
+
+type emptyQRLoginListener struct {
+	ctx context.Context
+}
+
+func newEmptyQRLoginListener(ctx context.Context) open_im_sdk_callback.OnQRLoginListener {
+	return &emptyQRLoginListener{ctx: ctx}
+}
+
+func (e *emptyQRLoginListener) OnQRLoginStateChanged(state string) {
+	log.ZWarn(e.ctx, "QRLoginListener is not implemented", nil, "state", state)
+}
+
+type emptyTokenListener struct {
+	ctx context.Context
+}
+
+func newEmptyTokenListener(ctx context.Context) open_im_sdk_callback.OnTokenListener {
+	return &emptyTokenListener{ctx: ctx}
+}
+
+func (e *emptyTokenListener) OnTokenWillExpire(expireTime int64) {
+	log.ZWarn(e.ctx, "TokenListener is not implemented", nil, "expireTime", expireTime)
+}
+
+type emptyConnStateListener struct {
+	ctx context.Context
+}
+
+func newEmptyConnStateListener(ctx context.Context) open_im_sdk_callback.OnConnStateListener {
+	return &emptyConnStateListener{ctx: ctx}
+}
+
+func (e *emptyConnStateListener) OnConnStateChanged(state string) {
+	log.ZWarn(e.ctx, "ConnStateListener is not implemented", nil, "state", state)
+}
+
+type emptyNetworkQualityListener struct {
+	ctx context.Context
+}
+
+func newEmptyNetworkQualityListener(ctx context.Context) open_im_sdk_callback.OnNetworkQualityListener {
+	return &emptyNetworkQualityListener{ctx: ctx}
+}
+
+func (e *emptyNetworkQualityListener) OnNetworkQualityChanged(quality string) {
+	log.ZWarn(e.ctx, "NetworkQualityListener is not implemented", nil, "quality", quality)
+}
+
+type emptyAppLifecycleListener struct {
+	ctx context.Context
+}
+
+func newEmptyAppLifecycleListener(ctx context.Context) open_im_sdk_callback.OnAppLifecycleListener {
+	return &emptyAppLifecycleListener{ctx: ctx}
+}
+
+func (e *emptyAppLifecycleListener) OnSyncCaughtUp() {
+	log.ZWarn(e.ctx, "AppLifecycleListener is not implemented", nil)
+}
+
+type emptySyncProgressListener struct {
+	ctx context.Context
+}
+
+func newEmptySyncProgressListener(ctx context.Context) open_im_sdk_callback.OnSyncProgressListener {
+	return &emptySyncProgressListener{ctx: ctx}
+}
+
+func (e *emptySyncProgressListener) OnSyncProgress(progress string) {
+	log.ZWarn(e.ctx, "SyncProgressListener is not implemented", nil, "progress", progress)
+}
+
+type emptySyncConflictListener struct {
+	ctx context.Context
+}
+
+func newEmptySyncConflictListener(ctx context.Context) open_im_sdk_callback.OnSyncConflictListener {
+	return &emptySyncConflictListener{ctx: ctx}
+}
+
+func (e *emptySyncConflictListener) OnSyncConflict(conflict string) {
+	log.ZWarn(e.ctx, "SyncConflictListener is not implemented", nil, "conflict", conflict)
+}
+
+type emptyDBMigrationListener struct {
+	ctx context.Context
+}
+
+func newEmptyDBMigrationListener(ctx context.Context) open_im_sdk_callback.OnDBMigrationListener {
+	return &emptyDBMigrationListener{ctx: ctx}
+}
+
+func (e *emptyDBMigrationListener) OnDBMigrationProgress(progress string) {
+	log.ZWarn(e.ctx, "DBMigrationListener is not implemented", nil, "progress", progress)
+}
+
+type emptyDownloadListener struct {
+	ctx context.Context
+}
+
+func newEmptyDownloadListener(ctx context.Context) open_im_sdk_callback.OnDownloadListener {
+	return &emptyDownloadListener{ctx: ctx}
+}
+
+func (e *emptyDownloadListener) OnDownloadStateChanged(info string) {
+	log.ZWarn(e.ctx, "DownloadListener is not implemented", nil, "info", info)
+}
+
+func (e *emptyDownloadListener) OnDownloadProgress(info string) {
+}
+
+func (e *emptyDownloadListener) OnDownloadTotalProgress(progress string) {
+}
+
+type emptyMediaCacheListener struct {
+	ctx context.Context
+}
+
+func newEmptyMediaCacheListener(ctx context.Context) open_im_sdk_callback.OnMediaCacheListener {
+	return &emptyMediaCacheListener{ctx: ctx}
+}
+
+func (e *emptyMediaCacheListener) OnMediaEvicted(eviction string) {
+	log.ZWarn(e.ctx, "MediaCacheListener is not implemented", nil, "eviction", eviction)
+}
+
+type emptyMomentsListener struct {
+	ctx context.Context
+}
+
+func newEmptyMomentsListener(ctx context.Context) open_im_sdk_callback.OnMomentsListener {
+	return &emptyMomentsListener{ctx: ctx}
+}
+
+func (e *emptyMomentsListener) OnNewMoment(moment string) {
+	log.ZWarn(e.ctx, "MomentsListener is not implemented", nil, "moment", moment)
+}
+
+func (e *emptyMomentsListener) OnMomentDeleted(momentID string) {
+	log.ZWarn(e.ctx, "MomentsListener is not implemented", nil, "momentID", momentID)
+}
+
+func (e *emptyMomentsListener) OnMomentLiked(momentTips string) {
+	log.ZWarn(e.ctx, "MomentsListener is not implemented", nil, "momentTips", momentTips)
+}
+
+func (e *emptyMomentsListener) OnMomentCommented(momentTips string) {
+	log.ZWarn(e.ctx, "MomentsListener is not implemented", nil, "momentTips", momentTips)
+}
+
+type emptyOrganizationListener struct {
+	ctx context.Context
+}
+
+func newEmptyOrganizationListener(ctx context.Context) open_im_sdk_callback.OnOrganizationListener {
+	return &emptyOrganizationListener{ctx: ctx}
+}
+
+func (e *emptyOrganizationListener) OnDepartmentAdded(departmentInfo string) {
+	log.ZWarn(e.ctx, "OrganizationListener is not implemented", nil, "departmentInfo", departmentInfo)
+}
+
+func (e *emptyOrganizationListener) OnDepartmentDeleted(departmentInfo string) {
+	log.ZWarn(e.ctx, "OrganizationListener is not implemented", nil, "departmentInfo", departmentInfo)
+}
+
+func (e *emptyOrganizationListener) OnDepartmentInfoChanged(departmentInfo string) {
+	log.ZWarn(e.ctx, "OrganizationListener is not implemented", nil, "departmentInfo", departmentInfo)
+}
+
+func (e *emptyOrganizationListener) OnDepartmentMemberAdded(memberInfo string) {
+	log.ZWarn(e.ctx, "OrganizationListener is not implemented", nil, "memberInfo", memberInfo)
+}
+
+func (e *emptyOrganizationListener) OnDepartmentMemberDeleted(memberInfo string) {
+	log.ZWarn(e.ctx, "OrganizationListener is not implemented", nil, "memberInfo", memberInfo)
+}
+
+func (e *emptyOrganizationListener) OnDepartmentMemberInfoChanged(memberInfo string) {
+	log.ZWarn(e.ctx, "OrganizationListener is not implemented", nil, "memberInfo", memberInfo)
+}
+
+type emptyClientConfigListener struct {
+	ctx context.Context
+}
+
+func newEmptyClientConfigListener(ctx context.Context) open_im_sdk_callback.OnClientConfigListener {
+	return &emptyClientConfigListener{ctx: ctx}
+}
+
+func (e *emptyClientConfigListener) OnClientConfigChanged(config string) {
+	log.ZWarn(e.ctx, "ClientConfigListener is not implemented", nil, "config", config)
+}
+
+type emptyCustomerServiceListener struct {
+	ctx context.Context
+}
+
+func newEmptyCustomerServiceListener(ctx context.Context) open_im_sdk_callback.OnCustomerServiceListener {
+	return &emptyCustomerServiceListener{ctx: ctx}
+}
+
+func (e *emptyCustomerServiceListener) OnSessionQueueChanged(sessionInfo string) {
+	log.ZWarn(e.ctx, "CustomerServiceListener is not implemented", nil, "sessionInfo", sessionInfo)
+}
+
+func (e *emptyCustomerServiceListener) OnSessionAssigned(sessionInfo string) {
+	log.ZWarn(e.ctx, "CustomerServiceListener is not implemented", nil, "sessionInfo", sessionInfo)
+}
+
+func (e *emptyCustomerServiceListener) OnSessionTransferred(sessionTips string) {
+	log.ZWarn(e.ctx, "CustomerServiceListener is not implemented", nil, "sessionTips", sessionTips)
+}
+
+func (e *emptyCustomerServiceListener) OnSessionClosed(sessionTips string) {
+	log.ZWarn(e.ctx, "CustomerServiceListener is not implemented", nil, "sessionTips", sessionTips)
+}
+
+type emptyE2EEListener struct {
+	ctx context.Context
+}
+
+func newEmptyE2EEListener(ctx context.Context) open_im_sdk_callback.OnE2EEListener {
+	return &emptyE2EEListener{ctx: ctx}
+}
+
+func (e *emptyE2EEListener) OnIdentityKeyChanged(userID string) {
+	log.ZWarn(e.ctx, "E2EEListener is not implemented", nil, "userID", userID)
+}
+
+func (e *emptyE2EEListener) OnDevicesChanged(change string) {
+	log.ZWarn(e.ctx, "E2EEListener is not implemented", nil, "change", change)
+}
+
+type emptySdkErrorListener struct {
+	ctx context.Context
+}
+
+func newEmptySdkErrorListener(ctx context.Context) open_im_sdk_callback.OnSdkErrorListener {
+	return &emptySdkErrorListener{ctx: ctx}
+}
+
+func (e *emptySdkErrorListener) OnSdkError(sdkError string) {
+	log.ZWarn(e.ctx, "SdkErrorListener is not implemented", nil, "sdkError", sdkError)
+}
+
+type emptyDBCorruptionListener struct {
+	ctx context.Context
+}
+
+func newEmptyDBCorruptionListener(ctx context.Context) open_im_sdk_callback.OnDBCorruptionListener {
+	return &emptyDBCorruptionListener{ctx: ctx}
+}
+
+func (e *emptyDBCorruptionListener) OnDBCorruptionRecovered(incident string) {
+	log.ZWarn(e.ctx, "DBCorruptionListener is not implemented", nil, "incident", incident)
+}
+
+type emptySignalingListener struct {
+	ctx context.Context
+}
+
+func newEmptySignalingListener(ctx context.Context) open_im_sdk_callback.OnSignalingListener {
+	return &emptySignalingListener{ctx: ctx}
+}
+
+func (e *emptySignalingListener) OnReceiveNewInvitation(receiveNewInvitationCallback string) {
+	log.ZWarn(e.ctx, "SignalingListener is not implemented", nil, "receiveNewInvitationCallback", receiveNewInvitationCallback)
+}
+
+func (e *emptySignalingListener) OnInviteeAccepted(inviteeAcceptedCallback string) {
+	log.ZWarn(e.ctx, "SignalingListener is not implemented", nil, "inviteeAcceptedCallback", inviteeAcceptedCallback)
+}
+
+func (e *emptySignalingListener) OnInviteeAcceptedByOtherDevice(inviteeAcceptedCallback string) {
+	log.ZWarn(e.ctx, "SignalingListener is not implemented", nil, "inviteeAcceptedCallback", inviteeAcceptedCallback)
+}
+
+func (e *emptySignalingListener) OnInviteeRejected(inviteeRejectedCallback string) {
+	log.ZWarn(e.ctx, "SignalingListener is not implemented", nil, "inviteeRejectedCallback", inviteeRejectedCallback)
+}
+
+func (e *emptySignalingListener) OnInviteeRejectedByOtherDevice(inviteeRejectedCallback string) {
+	log.ZWarn(e.ctx, "SignalingListener is not implemented", nil, "inviteeRejectedCallback", inviteeRejectedCallback)
+}
+
+func (e *emptySignalingListener) OnInviteeBusy(inviteeBusyCallback string) {
+	log.ZWarn(e.ctx, "SignalingListener is not implemented", nil, "inviteeBusyCallback", inviteeBusyCallback)
+}
+
+func (e *emptySignalingListener) OnInvitationCancelled(invitationCancelledCallback string) {
+	log.ZWarn(e.ctx, "SignalingListener is not implemented", nil, "invitationCancelledCallback", invitationCancelledCallback)
+}
+
+func (e *emptySignalingListener) OnInvitationTimeout(invitationTimeoutCallback string) {
+	log.ZWarn(e.ctx, "SignalingListener is not implemented", nil, "invitationTimeoutCallback", invitationTimeoutCallback)
+}
+
+func (e *emptySignalingListener) OnHangUp(hangUpCallback string) {
+	log.ZWarn(e.ctx, "SignalingListener is not implemented", nil, "hangUpCallback", hangUpCallback)
+}
+
+func (e *emptySignalingListener) OnRoomParticipantConnected(onRoomParticipantConnectedCallback string) {
+	log.ZWarn(e.ctx, "SignalingListener is not implemented", nil, "onRoomParticipantConnectedCallback", onRoomParticipantConnectedCallback)
+}
+
+func (e *emptySignalingListener) OnRoomParticipantDisconnected(onRoomParticipantDisconnectedCallback string) {
+	log.ZWarn(e.ctx, "SignalingListener is not implemented", nil, "onRoomParticipantDisconnectedCallback", onRoomParticipantDisconnectedCallback)
+}
+
+func (e *emptySignalingListener) OnReceiveSignalingData(signalingData string) {
+	log.ZWarn(e.ctx, "SignalingListener is not implemented", nil, "signalingData", signalingData)
+}
@@ -0,0 +1,37 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package open_im_sdk
+
+import (
+	"context"
+
+	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+// GetErrorInfo Get the category of an error code given to OnError and whether the call may be retried.
+// Can be called before login.
+func GetErrorInfo(callback open_im_sdk_callback.Base, operationID string, errCode int32) {
+	call(callback, operationID, IMUserContext.GetErrorInfo, errCode)
+}
+
+func (u *UserContext) GetErrorInfo(_ context.Context, errCode int32) (*sdk_struct.ErrorInfo, error) {
+	return &sdk_struct.ErrorInfo{
+		Code:      errCode,
+		Category:  sdkerrs.Category(int(errCode)),
+		Retryable: sdkerrs.Retryable(int(errCode)),
+	}, nil
+}
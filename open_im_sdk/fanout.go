@@ -0,0 +1,310 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package open_im_sdk
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
+)
+
+// listenerSet holds the listeners added besides the one set, they are called after it in the order they were added.
+type listenerSet[T comparable] struct {
+	mu   sync.RWMutex
+	list []T
+}
+
+func (s *listenerSet[T]) add(listener T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.list = append(s.list, listener)
+}
+
+// remove removes the listener added first equal to listener. The listeners of the bound languages are new proxies
+// on each call, when none is the same they are compared deeply, equal when they refer to the same object.
+func (s *listenerSet[T]) remove(listener T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, equal := range []func(l T) bool{
+		func(l T) bool { return l == listener },
+		func(l T) bool { return reflect.DeepEqual(l, listener) },
+	} {
+		for i, l := range s.list {
+			if equal(l) {
+				s.list = append(s.list[:i:i], s.list[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// with returns the listener set followed by the ones added, nil when there is none.
+func (s *listenerSet[T]) with(listener T) []T {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var zero T
+	list := make([]T, 0, len(s.list)+1)
+	if listener != zero {
+		list = append(list, listener)
+	}
+	return append(list, s.list...)
+}
+
+func (s *listenerSet[T]) empty() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.list) == 0
+}
+
+type conversationListeners []open_im_sdk_callback.OnConversationListener
+
+func (l conversationListeners) OnSyncServerStart(reinstalled bool) {
+	for _, listener := range l {
+		listener.OnSyncServerStart(reinstalled)
+	}
+}
+
+func (l conversationListeners) OnSyncServerFinish(reinstalled bool) {
+	for _, listener := range l {
+		listener.OnSyncServerFinish(reinstalled)
+	}
+}
+
+func (l conversationListeners) OnSyncServerProgress(progress int) {
+	for _, listener := range l {
+		listener.OnSyncServerProgress(progress)
+	}
+}
+
+func (l conversationListeners) OnSyncServerFailed(reinstalled bool) {
+	for _, listener := range l {
+		listener.OnSyncServerFailed(reinstalled)
+	}
+}
+
+func (l conversationListeners) OnNewConversation(conversationList string) {
+	for _, listener := range l {
+		listener.OnNewConversation(conversationList)
+	}
+}
+
+func (l conversationListeners) OnConversationChanged(conversationList string) {
+	for _, listener := range l {
+		listener.OnConversationChanged(conversationList)
+	}
+}
+
+func (l conversationListeners) OnTotalUnreadMessageCountChanged(totalUnreadCount int32) {
+	for _, listener := range l {
+		listener.OnTotalUnreadMessageCountChanged(totalUnreadCount)
+	}
+}
+
+func (l conversationListeners) OnConversationUserInputStatusChanged(change string) {
+	for _, listener := range l {
+		listener.OnConversationUserInputStatusChanged(change)
+	}
+}
+
+type advancedMsgListeners []open_im_sdk_callback.OnAdvancedMsgListener
+
+func (l advancedMsgListeners) OnRecvNewMessage(message string) {
+	for _, listener := range l {
+		listener.OnRecvNewMessage(message)
+	}
+}
+
+func (l advancedMsgListeners) OnRecvC2CReadReceipt(msgReceiptList string) {
+	for _, listener := range l {
+		listener.OnRecvC2CReadReceipt(msgReceiptList)
+	}
+}
+
+func (l advancedMsgListeners) OnNewRecvMessageRevoked(messageRevoked string) {
+	for _, listener := range l {
+		listener.OnNewRecvMessageRevoked(messageRevoked)
+	}
+}
+
+func (l advancedMsgListeners) OnRecvOfflineNewMessage(message string) {
+	for _, listener := range l {
+		listener.OnRecvOfflineNewMessage(message)
+	}
+}
+
+func (l advancedMsgListeners) OnMsgDeleted(message string) {
+	for _, listener := range l {
+		listener.OnMsgDeleted(message)
+	}
+}
+
+func (l advancedMsgListeners) OnRecvOnlineOnlyMessage(message string) {
+	for _, listener := range l {
+		listener.OnRecvOnlineOnlyMessage(message)
+	}
+}
+
+func (l advancedMsgListeners) OnMsgEdited(message string) {
+	for _, listener := range l {
+		listener.OnMsgEdited(message)
+	}
+}
+
+type friendshipListeners []open_im_sdk_callback.OnFriendshipListener
+
+func (l friendshipListeners) OnFriendApplicationAdded(friendApplication string) {
+	for _, listener := range l {
+		listener.OnFriendApplicationAdded(friendApplication)
+	}
+}
+
+func (l friendshipListeners) OnFriendApplicationDeleted(friendApplication string) {
+	for _, listener := range l {
+		listener.OnFriendApplicationDeleted(friendApplication)
+	}
+}
+
+func (l friendshipListeners) OnFriendApplicationAccepted(friendApplication string) {
+	for _, listener := range l {
+		listener.OnFriendApplicationAccepted(friendApplication)
+	}
+}
+
+func (l friendshipListeners) OnFriendApplicationRejected(friendApplication string) {
+	for _, listener := range l {
+		listener.OnFriendApplicationRejected(friendApplication)
+	}
+}
+
+func (l friendshipListeners) OnFriendAdded(friendInfo string) {
+	for _, listener := range l {
+		listener.OnFriendAdded(friendInfo)
+	}
+}
+
+func (l friendshipListeners) OnFriendDeleted(friendInfo string) {
+	for _, listener := range l {
+		listener.OnFriendDeleted(friendInfo)
+	}
+}
+
+func (l friendshipListeners) OnFriendInfoChanged(friendInfo string) {
+	for _, listener := range l {
+		listener.OnFriendInfoChanged(friendInfo)
+	}
+}
+
+func (l friendshipListeners) OnBlackAdded(blackInfo string) {
+	for _, listener := range l {
+		listener.OnBlackAdded(blackInfo)
+	}
+}
+
+func (l friendshipListeners) OnBlackDeleted(blackInfo string) {
+	for _, listener := range l {
+		listener.OnBlackDeleted(blackInfo)
+	}
+}
+
+type groupListeners []open_im_sdk_callback.OnGroupListener
+
+func (l groupListeners) OnJoinedGroupAdded(groupInfo string) {
+	for _, listener := range l {
+		listener.OnJoinedGroupAdded(groupInfo)
+	}
+}
+
+func (l groupListeners) OnJoinedGroupDeleted(groupInfo string) {
+	for _, listener := range l {
+		listener.OnJoinedGroupDeleted(groupInfo)
+	}
+}
+
+func (l groupListeners) OnGroupMemberAdded(groupMemberInfo string) {
+	for _, listener := range l {
+		listener.OnGroupMemberAdded(groupMemberInfo)
+	}
+}
+
+func (l groupListeners) OnGroupMemberDeleted(groupMemberInfo string) {
+	for _, listener := range l {
+		listener.OnGroupMemberDeleted(groupMemberInfo)
+	}
+}
+
+func (l groupListeners) OnGroupApplicationAdded(groupApplication string) {
+	for _, listener := range l {
+		listener.OnGroupApplicationAdded(groupApplication)
+	}
+}
+
+func (l groupListeners) OnGroupApplicationDeleted(groupApplication string) {
+	for _, listener := range l {
+		listener.OnGroupApplicationDeleted(groupApplication)
+	}
+}
+
+func (l groupListeners) OnGroupInfoChanged(groupInfo string) {
+	for _, listener := range l {
+		listener.OnGroupInfoChanged(groupInfo)
+	}
+}
+
+func (l groupListeners) OnGroupDismissed(groupInfo string) {
+	for _, listener := range l {
+		listener.OnGroupDismissed(groupInfo)
+	}
+}
+
+func (l groupListeners) OnGroupMemberInfoChanged(groupMemberInfo string) {
+	for _, listener := range l {
+		listener.OnGroupMemberInfoChanged(groupMemberInfo)
+	}
+}
+
+func (l groupListeners) OnGroupApplicationAccepted(groupApplication string) {
+	for _, listener := range l {
+		listener.OnGroupApplicationAccepted(groupApplication)
+	}
+}
+
+func (l groupListeners) OnGroupApplicationRejected(groupApplication string) {
+	for _, listener := range l {
+		listener.OnGroupApplicationRejected(groupApplication)
+	}
+}
+
+type userListeners []open_im_sdk_callback.OnUserListener
+
+func (l userListeners) OnSelfInfoUpdated(userInfo string) {
+	for _, listener := range l {
+		listener.OnSelfInfoUpdated(userInfo)
+	}
+}
+
+func (l userListeners) OnUserStatusChanged(userOnlineStatus string) {
+	for _, listener := range l {
+		listener.OnUserStatusChanged(userOnlineStatus)
+	}
+}
+
+type businessListeners []open_im_sdk_callback.OnCustomBusinessListener
+
+func (l businessListeners) OnRecvCustomBusinessMessage(businessMessage string) {
+	for _, listener := range l {
+		listener.OnRecvCustomBusinessMessage(businessMessage)
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !no_files
+// +build !no_files
+
+package open_im_sdk
+
+import (
+	"github.com/openimsdk/openim-sdk-core/v3/internal/download"
+	"github.com/openimsdk/openim-sdk-core/v3/internal/third/file"
+	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+func init() {
+	registerModule(ModuleFiles, moduleHooks{
+		init: func(u *UserContext) {
+			m := download.NewManager(u.ctx)
+			m.SetOnComplete(u.mediaCacheChanged)
+			u.download = m
+		},
+	})
+	imConfigChecks = append(imConfigChecks, imConfigCheck{"downloadConcurrency", func(config *sdk_struct.IMConfig) error {
+		return download.CheckConcurrency(config.DownloadConcurrency)
+	}})
+	imConfigDefaults = append(imConfigDefaults, imConfigDefault{"downloadConcurrency", func(config *sdk_struct.IMConfig) bool {
+		return setDefault(&config.DownloadConcurrency, download.DefaultConcurrency)
+	}})
+}
+
+// EnqueueDownload Queue the download of a media file, the download listener reports its state and progress.
+// A download interrupted before resumes from the bytes already saved. Returns the info of the download.
+func EnqueueDownload(callback open_im_sdk_callback.Base, operationID string, req string) {
+	call(callback, operationID, IMUserContext.EnqueueDownload, req)
+}
+
+// PauseDownload Pause a download, ResumeDownload continues it from the bytes saved.
+func PauseDownload(callback open_im_sdk_callback.Base, operationID string, downloadID string) {
+	call(callback, operationID, IMUserContext.PauseDownload, downloadID)
+}
+
+// ResumeDownload Queue again a paused or failed download.
+func ResumeDownload(callback open_im_sdk_callback.Base, operationID string, downloadID string) {
+	call(callback, operationID, IMUserContext.ResumeDownload, downloadID)
+}
+
+// CancelDownload Stop a download and remove the bytes it saved.
+func CancelDownload(callback open_im_sdk_callback.Base, operationID string, downloadID string) {
+	call(callback, operationID, IMUserContext.CancelDownload, downloadID)
+}
+
+// GetDownloads Get the downloads of the download manager in the order they were queued.
+func GetDownloads(callback open_im_sdk_callback.Base, operationID string) {
+	call(callback, operationID, IMUserContext.GetDownloads)
+}
+
+// PinMessageMedia Keep the message and its media files, e.g. a favorited one, when the media cache or the
+// storage quota removes files and old messages. The favorites are kept by the app, the SDK knows of them
+// only by their pins.
+func PinMessageMedia(callback open_im_sdk_callback.Base, operationID string, conversationID, clientMsgID string) {
+	call(callback, operationID, IMUserContext.PinMessageMedia, conversationID, clientMsgID)
+}
+
+// UnpinMessageMedia Let the media files of the message be removed again.
+func UnpinMessageMedia(callback open_im_sdk_callback.Base, operationID string, conversationID, clientMsgID string) {
+	call(callback, operationID, IMUserContext.UnpinMessageMedia, conversationID, clientMsgID)
+}
+
+// SetMediaCacheKey Encrypt the media downloaded with keys derived from the master key, an empty key saves them
+// plain. The files saved before are read as they were saved. Not a call, so that the key is never logged.
+func SetMediaCacheKey(masterKey string) {
+	listenerCall(IMUserContext.SetMediaCacheKey, masterKey)
+}
+
+// GetMediaFilePath Get the path of the plain content of a downloaded file. An encrypted file is decrypted to
+// a copy removed at the logout, the path of a plain file is itself.
+func GetMediaFilePath(callback open_im_sdk_callback.Base, operationID string, filePath string) {
+	call(callback, operationID, IMUserContext.GetMediaFilePath, filePath)
+}
+
+// ReadMediaFile Read at most length bytes of the plain content of a downloaded file from the offset on,
+// decrypted in memory.
+func ReadMediaFile(callback open_im_sdk_callback.Base, operationID string, filePath string, offset, length int64) {
+	call(callback, operationID, IMUserContext.ReadMediaFile, filePath, offset, length)
+}
+
+// ClearMediaCache Remove the media files in DataDir, except the pinned ones and the ones of the messages being
+// sent. Returns the number of files removed and the bytes freed.
+func ClearMediaCache(callback open_im_sdk_callback.Base, operationID string) {
+	call(callback, operationID, IMUserContext.ClearMediaCache)
+}
+
+func UploadFile(callback open_im_sdk_callback.Base, operationID string, req string, progress open_im_sdk_callback.UploadFileCallback) {
+	call(callback, operationID, IMUserContext.File().UploadFile, req, file.UploadFileCallback(progress))
+}
+
+func SetDownloadListener(listener open_im_sdk_callback.OnDownloadListener) {
+	listenerCall(IMUserContext.SetDownloadListener, listener)
+}
+
+func SetMediaCacheListener(listener open_im_sdk_callback.OnMediaCacheListener) {
+	listenerCall(IMUserContext.SetMediaCacheListener, listener)
+}
@@ -68,6 +68,11 @@ func GetSpecifiedGroupsInfo(callback open_im_sdk_callback.Base, operationID stri
 	call(callback, operationID, IMUserContext.Group().GetSpecifiedGroupsInfo, groupIDList)
 }
 
+// GetGroupsInfo Get several groups at once, the ones which could not be got are returned in the failed list.
+func GetGroupsInfo(callback open_im_sdk_callback.Base, operationID string, groupIDs string) {
+	call(callback, operationID, IMUserContext.Group().GetGroupsInfo, groupIDs)
+}
+
 func SearchGroups(callback open_im_sdk_callback.Base, operationID string, searchParam string) {
 	call(callback, operationID, IMUserContext.Group().SearchGroups, searchParam)
 }
@@ -88,6 +93,11 @@ func GetGroupMemberList(callback open_im_sdk_callback.Base, operationID string,
 	call(callback, operationID, IMUserContext.Group().GetGroupMemberList, groupID, filter, offset, count)
 }
 
+// GetGroupMemberListByCursor gets the page of the group members of the filter following the opaque cursor, the first page for an empty cursor.
+func GetGroupMemberListByCursor(callback open_im_sdk_callback.Base, operationID string, groupID string, filter int32, cursor string, count int32) {
+	call(callback, operationID, IMUserContext.Group().GetGroupMemberListByCursor, groupID, filter, cursor, count)
+}
+
 func GetGroupApplicationListAsRecipient(callback open_im_sdk_callback.Base, operationID, req string) {
 	call(callback, operationID, IMUserContext.Group().GetGroupApplicationListAsRecipient, req)
 }
@@ -0,0 +1,64 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package open_im_sdk
+
+import (
+	"context"
+
+	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/api"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/cliconf"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/server_api_params"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+const defaultGuestSendLimit = 10
+
+// GuestLogin Log in with a temporary identity issued by the server. The guest can only join and send to the
+// designated group conversations, with a limited number of messages per minute. The SDK only refuses the
+// calls a guest is not allowed to make early, the server must enforce the same restrictions on the guest token.
+func GuestLogin(callback open_im_sdk_callback.Base, operationID string) {
+	call(callback, operationID, IMUserContext.GuestLogin)
+}
+
+func (u *UserContext) GuestLogin(ctx context.Context) (*sdk_struct.GuestLoginInfo, error) {
+	resp, err := api.GuestLogin.Invoke(ctx, &server_api_params.GuestLoginReq{PlatformID: u.info.Config().PlatformID})
+	if err != nil {
+		return nil, err
+	}
+	sendLimit := resp.SendLimit
+	if sendLimit <= 0 {
+		sendLimit = u.info.Config().GuestSendLimit
+	}
+	if sendLimit <= 0 {
+		sendLimit = defaultGuestSendLimit
+	}
+	cliconf.SetLoginUserID(ctx, resp.UserID)
+	// guest mode starts with the login succeeding, a failed login leaves the current session as it is
+	err = u.login(ctx, resp.UserID, resp.Token, func() {
+		u.conversation.SetGuest(resp.GroupIDs, int(sendLimit))
+		u.group.SetGuestGroupIDs(resp.GroupIDs)
+		u.isGuest.Store(true)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &sdk_struct.GuestLoginInfo{UserID: resp.UserID, GroupIDs: resp.GroupIDs, SendLimit: sendLimit}, nil
+}
+
+// IsGuestLogin Whether the current login is a guest login.
+func IsGuestLogin() bool {
+	return IMUserContext.isGuest.Load()
+}
@@ -0,0 +1,55 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package open_im_sdk
+
+import (
+	"context"
+
+	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/i18n"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+)
+
+// SetLocalizedTemplates Override the templates of the texts the SDK generates in the locale, a json object of
+// the templates by key, see pkg/i18n for the keys and their arguments. An empty template restores the built-in
+// one. Can be called before login.
+func SetLocalizedTemplates(callback open_im_sdk_callback.Base, operationID string, locale, templates string) {
+	call(callback, operationID, IMUserContext.SetLocalizedTemplates, locale, templates)
+}
+
+// GetLocalizedText Get the text of the key in the locale of the config, e.g. the label of the edited messages,
+// args is a json object of its arguments.
+func GetLocalizedText(_ string, key, args string) string {
+	var kv map[string]string
+	if args != "" {
+		if err := utils.JsonStringToStruct(args, &kv); err != nil {
+			return i18n.T(key)
+		}
+	}
+	pairs := make([]string, 0, len(kv)*2)
+	for name, value := range kv {
+		pairs = append(pairs, name, value)
+	}
+	return i18n.T(key, pairs...)
+}
+
+func (u *UserContext) SetLocalizedTemplates(_ context.Context, locale string, templates map[string]string) error {
+	if locale == "" {
+		return sdkerrs.ErrArgs.WrapMsg("locale is empty")
+	}
+	i18n.SetTemplates(locale, templates)
+	return nil
+}
@@ -18,18 +18,31 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"strings"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strconv"
 
 	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/audit"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/backup"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/cache"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/ccontext"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/cliconf"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/common"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/network"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
 	pbConstant "github.com/openimsdk/protocol/constant"
 
 	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
 	"github.com/openimsdk/openim-sdk-core/v3/version"
 
-	"github.com/openimsdk/tools/log"
 	"github.com/openimsdk/tools/mcontext"
+	"github.com/openimsdk/tools/utils/datautil"
 )
 
 func GetSdkVersion() string {
@@ -55,17 +68,26 @@ func InitSDK(listener open_im_sdk_callback.OnConnListener, operationID string, c
 	} else {
 		logRemainCount = 1
 	}
+	if configArgs.LogMaxAge > 0 {
+		logRemainCount = configArgs.LogMaxAge
+	}
+	log.SetPrivacy(configArgs.LogPrivacy)
 	if err := log.InitLoggerFromConfig("open-im-sdk-core", "", configArgs.SystemType, pbConstant.PlatformID2Name[int(configArgs.PlatformID)], int(configArgs.LogLevel), configArgs.IsLogStandardOutput, false, configArgs.LogFilePath, uint(logRemainCount), rotationTime, version.Version, true); err != nil {
 		fmt.Println(operationID, "log init failed ", err.Error())
 	}
+	log.SetMaxFileSize(int64(configArgs.LogMaxSize) << 20)
 	fmt.Println("init log success")
 	ctx := mcontext.NewCtx(operationID)
-	if !strings.Contains(configArgs.ApiAddr, "http") {
-		log.ZError(ctx, "api is http protocol, api format is invalid", nil)
-		return false
+	for module, level := range configArgs.LogModuleLevels {
+		if err := log.SetLevel(module, int(level)); err != nil {
+			log.ZError(ctx, "invalid log module level", err, "module", module, "level", level)
+			return false
+		}
 	}
-	if !strings.Contains(configArgs.WsAddr, "ws") {
-		log.ZError(ctx, "ws is ws protocol, ws format is invalid", nil)
+	if unknown, err := unknownConfigFields(config); err == nil && len(unknown) > 0 {
+		log.ZWarn(ctx, "unknown config fields are ignored", nil, "fields", unknown)
+	}
+	if !checkIMConfig(ctx, &configArgs) {
 		return false
 	}
 
@@ -76,6 +98,7 @@ func InitSDK(listener open_im_sdk_callback.OnConnListener, operationID string, c
 	}
 	return IMUserContext.InitSDK(&configArgs, listener)
 }
+
 func UnInitSDK(_ string) {
 	IMUserContext.UnInitSDK()
 }
@@ -95,32 +118,430 @@ func Logout(callback open_im_sdk_callback.Base, operationID string) {
 	call(callback, operationID, IMUserContext.Logout)
 }
 
+// LogoutWithMode Logout and keep the local data, wipe the messages but keep the conversations and the
+// contacts, or overwrite and remove the local database and the media cache, see constant.LogoutModeKeepData.
+// The progress is called with the conversations or the files wiped and to wipe.
+func LogoutWithMode(callback open_im_sdk_callback.Base, operationID string, mode string, progress open_im_sdk_callback.LogoutWipeProgress) {
+	call(callback, operationID, IMUserContext.LogoutWithMode, mode, progress)
+}
+
 func SetAppBackgroundStatus(callback open_im_sdk_callback.Base, operationID string, isBackground bool) {
 	call(callback, operationID, IMUserContext.SetAppBackgroundStatus, isBackground)
 }
+
+// EnterBackground Call when the app goes to background instead of SetAppBackgroundStatus. Besides telling the
+// server, the SDK slows the heartbeat down, stops filling the gaps of the pushed messages and writes the
+// buffered ones right away, as the app may be suspended at any time.
+func EnterBackground(callback open_im_sdk_callback.Base, operationID string) {
+	call(callback, operationID, IMUserContext.EnterBackground)
+}
+
+// EnterForeground Call when the app comes back to foreground, the SDK syncs what was missed in background and
+// calls OnSyncCaughtUp of the app lifecycle listener once done.
+func EnterForeground(callback open_im_sdk_callback.Base, operationID string) {
+	call(callback, operationID, IMUserContext.EnterForeground)
+}
+
+// ForceResyncAll Drop the sync versions of the scopes, a json array of conversations, friends, groups and
+// messages, all of them when empty, and pull them again from the server. For when the local data went wrong.
+func ForceResyncAll(callback open_im_sdk_callback.Base, operationID string, scopes string) {
+	call(callback, operationID, IMUserContext.ForceResyncAll, scopes)
+}
+
 func NetworkStatusChanged(callback open_im_sdk_callback.Base, operationID string) {
 	call(callback, operationID, IMUserContext.NetworkStatusChanged)
 }
 
+// ForceReconnect Reconnect right away when the app knows the network is back, instead of waiting for the next
+// attempt. Also restarts reconnecting after the connection state became failed.
+func ForceReconnect(callback open_im_sdk_callback.Base, operationID string) {
+	call(callback, operationID, IMUserContext.LongConnMgr().ForceReconnect)
+}
+
+// GetNetworkQuality Get the round trip time, jitter, loss rate and throughput of the long connection.
+func GetNetworkQuality(callback open_im_sdk_callback.Base, operationID string) {
+	call(callback, operationID, IMUserContext.LongConnMgr().GetNetworkQuality)
+}
+
+// SetProxy Switch the proxy used by the api requests and the long connection without logging in again,
+// a null config restores the proxy from the environment. Can be called before login.
+func SetProxy(callback open_im_sdk_callback.Base, operationID string, proxyConfig string) {
+	call(callback, operationID, IMUserContext.SetProxy, proxyConfig)
+}
+
+// GetTrafficStats Get the bytes of the long connection and the api requests before and after compression.
+func GetTrafficStats(_ string) string {
+	return utils.StructToJsonString(network.GetTrafficStats())
+}
+
+// GetDBQueryStats Get the count, rows and durations of the queries of the local database by their shape, the
+// most time spent first. Only recorded when DBInstrumentation is set in the config.
+func GetDBQueryStats(_ string) string {
+	return utils.StructToJsonString(db.GetQueryStats())
+}
+
+// GetServerTime Get the current time of the server clock in milliseconds, the SDK corrects the local clock
+// with the offset sampled from the server after connecting and periodically.
+func GetServerTime(_ string) int64 {
+	return utils.GetServerTimestampByMill()
+}
+
+// SetBandwidthLimit Set the bytes per second the SDK may transfer in total and per category, 0 is unlimited.
+// Applies right away to the transfers in progress. Can be called before login.
+func SetBandwidthLimit(callback open_im_sdk_callback.Base, operationID string, bandwidthLimit string) {
+	call(callback, operationID, IMUserContext.SetBandwidthLimit, bandwidthLimit)
+}
+
+// SetNetworkClass Tell the SDK the class of the network: wifi, cellular or metered. On cellular and metered
+// networks the backfill of old messages and the media prefetch wait until wifi or AllowMeteredTransfer.
+// Can be called before login.
+func SetNetworkClass(callback open_im_sdk_callback.Base, operationID string, class string) {
+	call(callback, operationID, IMUserContext.SetNetworkClass, class)
+}
+
+// AllowMeteredTransfer Allow or defer again the backfill or the media prefetch on a metered network. Can be
+// called before login.
+func AllowMeteredTransfer(callback open_im_sdk_callback.Base, operationID string, kind string, allow bool) {
+	call(callback, operationID, IMUserContext.AllowMeteredTransfer, kind, allow)
+}
+
+// TrimMemory Free the memory of the in-memory caches on a memory warning of the platform: TrimMemoryModerate
+// removes the expired entries and half of the others, TrimMemoryCritical removes them all. The entries removed
+// are loaded again from the local database when used. Can be called before login.
+func TrimMemory(callback open_im_sdk_callback.Base, operationID string, level int) {
+	call(callback, operationID, IMUserContext.TrimMemory, level)
+}
+
+// SetDatabaseKey Set the key the local database is encrypted with, provisioned by the app from the keystore
+// or keychain. An existing plaintext database is encrypted in place at the next login. Must be called before
+// login, needs the SDK built with SQLCipher.
+func SetDatabaseKey(callback open_im_sdk_callback.Base, operationID string, key string) {
+	call(callback, operationID, IMUserContext.SetDatabaseKey, key)
+}
+
+// SetDraftEncryptionKey Set the key the drafts are encrypted with in the local database, for the apps that do
+// not encrypt the whole database with SetDatabaseKey. Must be called before login, the drafts saved with
+// another key are read empty. Not supported in the browser.
+func SetDraftEncryptionKey(callback open_im_sdk_callback.Base, operationID string, key string) {
+	call(callback, operationID, IMUserContext.SetDraftEncryptionKey, key)
+}
+
+// RotateDatabaseKey Encrypt the local database of the login user with a new key, the app stores the new key
+// in the keystore or keychain once it succeeds.
+func RotateDatabaseKey(callback open_im_sdk_callback.Base, operationID string, newKey string) {
+	call(callback, operationID, IMUserContext.RotateDatabaseKey, newKey)
+}
+
+// BackupLocalData Write the local database of the login user to a single archive at path, encrypted with the
+// passphrase. The archive holds the messages, conversations, relations and the sync state of the database.
+func BackupLocalData(callback open_im_sdk_callback.Base, operationID string, path, passphrase string) {
+	call(callback, operationID, IMUserContext.BackupLocalData, path, passphrase)
+}
+
+// RestoreLocalData Restore the local database from an archive of BackupLocalData, returns the manifest of the
+// archive. Must be called before login, the archive of a newer schema than the SDK knows is refused.
+func RestoreLocalData(callback open_im_sdk_callback.Base, operationID string, path, passphrase string) {
+	call(callback, operationID, IMUserContext.RestoreLocalData, path, passphrase)
+}
+
+// GetBandwidthLimit Get the bandwidth limits set by the config or SetBandwidthLimit.
+func GetBandwidthLimit(callback open_im_sdk_callback.Base, operationID string) {
+	call(callback, operationID, IMUserContext.GetBandwidthLimit)
+}
+
 func GetLoginStatus(operationID string) int {
 	return IMUserContext.GetLoginStatus(ccontext.WithOperationID(context.Background(), operationID))
 }
 
 func (u *UserContext) Login(ctx context.Context, userID, token string) error {
-	cliconf.SetLoginUserID(u.loginUserID)
-	return u.login(ctx, userID, token)
+	cliconf.SetLoginUserID(ctx, userID)
+	return u.login(ctx, userID, token, nil)
 }
 
 func (u *UserContext) Logout(ctx context.Context) error {
-	return u.logout(ctx, false)
+	return u.logout(ctx, false, constant.LogoutModeKeepData, nil)
+}
+
+func (u *UserContext) LogoutWithMode(ctx context.Context, mode string, progress open_im_sdk_callback.LogoutWipeProgress) error {
+	switch mode {
+	case constant.LogoutModeKeepData, constant.LogoutModeWipeMessages, constant.LogoutModeSecureWipe:
+	default:
+		return sdkerrs.ErrArgs.WrapMsg("unknown logout mode " + mode)
+	}
+	return u.logout(ctx, false, mode, func(done, total int) {
+		if progress != nil {
+			progress.OnProgress(done, total)
+		}
+	})
 }
 
 func (u *UserContext) SetAppBackgroundStatus(ctx context.Context, isBackground bool) error {
 	return u.setAppBackgroundStatus(ctx, isBackground)
 }
+func (u *UserContext) EnterBackground(ctx context.Context) error {
+	u.longConnMgr.SetReducedActivity(true)
+	err := u.switchAppBackground(ctx, true)
+	u.startAutoCompact()
+	return err
+}
+
+func (u *UserContext) EnterForeground(ctx context.Context) error {
+	u.longConnMgr.SetReducedActivity(false)
+	u.stopAutoCompact()
+	err := u.switchAppBackground(ctx, false)
+	// catch up also when the server was not told, a failed catch-up is finished by the sync after connecting
+	_ = common.DispatchCatchUpSync(ctx, u.msgSyncerCh)
+	return err
+}
+
+func (u *UserContext) ForceResyncAll(ctx context.Context, scopes []string) error {
+	if len(scopes) == 0 {
+		scopes = []string{constant.ResyncScopeConversations, constant.ResyncScopeFriends,
+			constant.ResyncScopeGroups, constant.ResyncScopeMessages}
+	}
+	resyncs := make([]func(ctx context.Context) error, 0, len(scopes))
+	for _, scope := range datautil.Distinct(scopes) {
+		switch scope {
+		case constant.ResyncScopeConversations:
+			resyncs = append(resyncs, u.conversation.ForceResyncConversations)
+		case constant.ResyncScopeFriends:
+			resyncs = append(resyncs, u.relation.ForceResyncFriends)
+		case constant.ResyncScopeGroups:
+			resyncs = append(resyncs, u.group.ForceResyncGroups)
+		case constant.ResyncScopeMessages:
+			// the seqs of the messages belong to the msg syncer goroutine
+			resyncs = append(resyncs, func(ctx context.Context) error {
+				return common.DispatchResyncMsgs(ctx, u.msgSyncerCh)
+			})
+		default:
+			return sdkerrs.ErrArgs.WrapMsg("unknown resync scope " + scope)
+		}
+	}
+	log.ZInfo(ctx, "force resync", "scopes", scopes)
+	for _, resync := range resyncs {
+		if err := resync(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (u *UserContext) NetworkStatusChanged(ctx context.Context) {
 	u.longConnMgr.Close(ctx)
 }
+
+func (u *UserContext) SetBandwidthLimit(ctx context.Context, limit *sdk_struct.BandwidthLimit) error {
+	if err := network.SetBandwidthLimit(ctx, limit); err != nil {
+		return err
+	}
+	u.updateConfig(func(config *sdk_struct.IMConfig) { config.BandwidthLimit = limit })
+	log.ZInfo(ctx, "bandwidth limit changed", "limit", limit)
+	return nil
+}
+
+func (u *UserContext) SetNetworkClass(ctx context.Context, class string) error {
+	resumed, err := network.SetNetworkClass(class)
+	if err != nil {
+		return err
+	}
+	log.ZInfo(ctx, "network class changed", "class", class)
+	if resumed {
+		u.resumeBackfill(ctx)
+		u.download.Schedule()
+	}
+	return nil
+}
+
+func (u *UserContext) TrimMemory(ctx context.Context, level int) error {
+	var keep float64
+	switch level {
+	case constant.TrimMemoryModerate:
+		keep = 0.5
+	case constant.TrimMemoryCritical:
+	default:
+		return sdkerrs.ErrArgs.WrapMsg("unknown trim memory level " + strconv.Itoa(level))
+	}
+	n := cache.Trim(keep)
+	if level == constant.TrimMemoryCritical {
+		debug.FreeOSMemory()
+	}
+	log.ZInfo(ctx, "memory trimmed", "level", level, "entries", n)
+	return nil
+}
+
+func (u *UserContext) AllowMeteredTransfer(ctx context.Context, kind string, allow bool) error {
+	resumed, err := network.AllowMeteredTransfer(kind, allow)
+	if err != nil {
+		return err
+	}
+	log.ZInfo(ctx, "metered transfer allowed", "kind", kind, "allow", allow)
+	if resumed && kind == constant.MeteredBackfill {
+		u.resumeBackfill(ctx)
+	}
+	if resumed && kind == constant.MeteredMediaPrefetch {
+		u.download.Schedule()
+	}
+	return nil
+}
+
+// resumeBackfill pulls the older messages of the seq gaps deferred on the metered network.
+func (u *UserContext) resumeBackfill(ctx context.Context) {
+	if u.getLoginStatus(ctx) != Logged {
+		return
+	}
+	_ = common.DispatchBackfill(ctx, u.msgSyncerCh)
+}
+
+func (u *UserContext) SetDatabaseKey(ctx context.Context, key string) error {
+	if status := u.getLoginStatus(ctx); status == Logging || status == Logged {
+		return sdkerrs.ErrArgs.WrapMsg("the database key is set before login, use RotateDatabaseKey after login")
+	}
+	u.dbKey = key
+	return nil
+}
+
+func (u *UserContext) SetDraftEncryptionKey(ctx context.Context, key string) error {
+	if status := u.getLoginStatus(ctx); status == Logging || status == Logged {
+		return sdkerrs.ErrArgs.WrapMsg("the draft encryption key is set before login")
+	}
+	u.draftKey = key
+	return nil
+}
+
+func (u *UserContext) RotateDatabaseKey(ctx context.Context, newKey string) error {
+	if newKey == "" {
+		return sdkerrs.ErrArgs.WrapMsg("empty database key")
+	}
+	if err := u.db.Rekey(ctx, newKey); err != nil {
+		return err
+	}
+	u.dbKey = newKey
+	log.ZInfo(ctx, "database key rotated")
+	return nil
+}
+
+func (u *UserContext) BackupLocalData(ctx context.Context, path, passphrase string) error {
+	if path == "" || passphrase == "" {
+		return sdkerrs.ErrArgs.WrapMsg("backup path and passphrase are required")
+	}
+	schemaVersion, err := u.db.SchemaVersion(ctx)
+	if err != nil {
+		return err
+	}
+	snapshot := path + ".db"
+	if err := u.db.BackupTo(ctx, snapshot); err != nil {
+		return err
+	}
+	defer os.Remove(snapshot)
+	manifest := &backup.Manifest{
+		UserID:        u.loginUserID,
+		BigVersion:    constant.BigVersion,
+		SDKVersion:    version.Version,
+		SchemaVersion: schemaVersion,
+		Encrypted:     u.dbKey != "",
+	}
+	if err := backup.Write(path, passphrase, manifest, snapshot); err != nil {
+		return err
+	}
+	log.ZInfo(ctx, "local data backed up", "path", path, "manifest", manifest)
+	audit.Record(ctx, audit.ActionExport, "BackupLocalData", path)
+	return nil
+}
+
+func (u *UserContext) RestoreLocalData(ctx context.Context, path, passphrase string) (*backup.Manifest, error) {
+	config := u.info.Config()
+	if status := u.getLoginStatus(ctx); status == Logging || status == Logged {
+		return nil, sdkerrs.ErrArgs.WrapMsg("local data is restored before login")
+	}
+	if config == nil {
+		return nil, sdkerrs.ErrSDKNotInit
+	}
+	if config.InMemoryDB {
+		return nil, sdkerrs.ErrArgs.WrapMsg("the local database is in memory, there is no file to restore")
+	}
+	if path == "" || passphrase == "" {
+		return nil, sdkerrs.ErrArgs.WrapMsg("backup path and passphrase are required")
+	}
+	var dbFileName string
+	// restored next to the database, the rename stays on the same file system
+	restored := filepath.Join(u.dbDir(), "OpenIM_restoring.db")
+	check := func(manifest *backup.Manifest) error {
+		if manifest.UserID == "" || manifest.BigVersion != constant.BigVersion {
+			return sdkerrs.ErrArgs.WrapMsg(fmt.Sprintf("backup of sdk %s is not compatible", manifest.BigVersion))
+		}
+		// an older schema is migrated at login, a newer one is unknown to the sdk
+		if manifest.SchemaVersion > db.LatestSchemaVersion() {
+			return sdkerrs.ErrArgs.WrapMsg(fmt.Sprintf("backup schema %d is newer than the sdk schema %d",
+				manifest.SchemaVersion, db.LatestSchemaVersion()))
+		}
+		if manifest.Encrypted && u.dbKey == "" {
+			return sdkerrs.ErrArgs.WrapMsg("backup of an encrypted database, set the database key first")
+		}
+		// the database of an account switched away from is still open
+		u.closeKeptAccount(ctx, manifest.UserID)
+		var err error
+		dbFileName, err = db.DBFileName(u.dbDir(), manifest.UserID)
+		return err
+	}
+	manifest, err := backup.Read(path, passphrase, check, restored)
+	if err != nil {
+		return nil, err
+	}
+	for _, suffix := range []string{"-wal", "-shm", "-journal"} {
+		_ = os.Remove(dbFileName + suffix)
+	}
+	if err := os.Rename(restored, dbFileName); err != nil {
+		_ = os.Remove(restored)
+		return nil, err
+	}
+	log.ZInfo(ctx, "local data restored", "path", path, "manifest", manifest)
+	return manifest, nil
+}
+
+func (u *UserContext) GetBandwidthLimit(ctx context.Context) (*sdk_struct.BandwidthLimit, error) {
+	return network.GetBandwidthLimit(ctx), nil
+}
+
+// SetProxy sets the proxy of the api requests and the long connection of the user context, the other user
+// contexts keep theirs.
+func (u *UserContext) SetProxy(ctx context.Context, config *sdk_struct.ProxyConfig) error {
+	if err := network.CheckProxy(config); err != nil {
+		return err
+	}
+	u.updateConfig(func(c *sdk_struct.IMConfig) { c.Proxy = config })
+	network.CloseIdleConnections(ctx)
+	log.ZInfo(ctx, "proxy changed", "proxy", config)
+	if u.getLoginStatus(ctx) == Logged {
+		// reconnect through the new proxy
+		u.longConnMgr.Close(ctx)
+	}
+	return nil
+}
 func (u *UserContext) GetLoginStatus(ctx context.Context) int {
 	return u.getLoginStatus(ctx)
 }
+
+// CreateLoginQRCode Create a QR code for logging in this device by scanning it with a logged-in device, can be called before login.
+func CreateLoginQRCode(callback open_im_sdk_callback.Base, operationID string) {
+	call(callback, operationID, IMUserContext.QRLogin().CreateLoginQRCode)
+}
+
+// CancelLoginQRCode Stop waiting for the QR code created by this device to be scanned.
+func CancelLoginQRCode(callback open_im_sdk_callback.Base, operationID string) {
+	call(callback, operationID, IMUserContext.QRLogin().CancelLoginQRCode)
+}
+
+// ScanLoginQRCode Notify the device showing the QR code that it was scanned.
+func ScanLoginQRCode(callback open_im_sdk_callback.Base, operationID string, payload string) {
+	call(callback, operationID, IMUserContext.QRLogin().ScanLoginQRCode, payload)
+}
+
+// ApproveLoginQRCode Approve the login of the device showing the QR code.
+func ApproveLoginQRCode(callback open_im_sdk_callback.Base, operationID string, payload string) {
+	call(callback, operationID, IMUserContext.QRLogin().ApproveLoginQRCode, payload)
+}
+
+// RejectLoginQRCode Reject the login of the device showing the QR code.
+func RejectLoginQRCode(callback open_im_sdk_callback.Base, operationID string, payload string) {
+	call(callback, operationID, IMUserContext.QRLogin().RejectLoginQRCode, payload)
+}
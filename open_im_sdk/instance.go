@@ -0,0 +1,143 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package open_im_sdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/ccontext"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/network"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+	"github.com/openimsdk/tools/mcontext"
+)
+
+// DefaultInstance is the handle of the instance created with the package and initialized by InitSDK.
+const DefaultInstance = "default"
+
+var (
+	instanceLock sync.Mutex
+	instanceSeq  int
+	// instances are the sdk instances created by CreateSDKInstance, each one has its own
+	// database, long connection and listeners.
+	instances   = make(map[string]*UserContext)
+	currentName = DefaultInstance
+	// defaultUserContext is the default instance, taken the first time another instance is used.
+	defaultUserContext *UserContext
+)
+
+func getInstance(handle string) (*UserContext, bool) {
+	if defaultUserContext == nil && currentName == DefaultInstance {
+		defaultUserContext = IMUserContext
+	}
+	if handle == DefaultInstance {
+		return defaultUserContext, true
+	}
+	u, ok := instances[handle]
+	return u, ok
+}
+
+// CreateSDKInstance creates an sdk instance independent of the others and initializes it with the config,
+// so that another account can be logged in side by side. It returns the instance handle, empty on failure.
+// The database, the long connection, the listeners, the proxy, the certificate pins, the bandwidth limit, the
+// grpc transport, the database pragmas and the client config are of the instance. The log, the telemetry, the
+// fault injection, the locale, the request policies, the metered transfers and the database file name are of
+// the process: the log config of the first InitSDK call applies to every instance, the others of the last one.
+func CreateSDKInstance(listener open_im_sdk_callback.OnConnListener, operationID string, config string) string {
+	var configArgs sdk_struct.IMConfig
+	if err := json.Unmarshal([]byte(config), &configArgs); err != nil {
+		fmt.Println(operationID, "Unmarshal failed ", err.Error(), config)
+		return ""
+	}
+	ctx := mcontext.NewCtx(operationID)
+	if configArgs.PlatformID == 0 || !checkIMConfig(ctx, &configArgs) {
+		return ""
+	}
+	if listener == nil {
+		log.ZError(ctx, "listener is nil", nil)
+		return ""
+	}
+	u := NewLoginMgr()
+	u.initResources()
+	if !u.InitSDK(&configArgs, listener) {
+		return ""
+	}
+	instanceLock.Lock()
+	defer instanceLock.Unlock()
+	instanceSeq++
+	handle := "instance_" + strconv.Itoa(instanceSeq)
+	instances[handle] = u
+	log.ZInfo(ctx, "CreateSDKInstance", "handle", handle)
+	return handle
+}
+
+// UseSDKInstance makes the exported functions, including the listener setters, operate on the instance.
+// Calls already started keep using the instance they started with.
+func UseSDKInstance(handle string) bool {
+	instanceLock.Lock()
+	defer instanceLock.Unlock()
+	u, ok := getInstance(handle)
+	if !ok {
+		return false
+	}
+	IMUserContext = u
+	currentName = handle
+	return true
+}
+
+// SDKInstanceClient returns the typed api of the instance of the handle whatever the current instance is, nil
+// when there is none.
+func SDKInstanceClient(handle string) *Client {
+	instanceLock.Lock()
+	defer instanceLock.Unlock()
+	u, ok := getInstance(handle)
+	if !ok || u == nil {
+		return nil
+	}
+	return u.Client()
+}
+
+// GetCurrentSDKInstance returns the handle of the instance the exported functions operate on.
+func GetCurrentSDKInstance() string {
+	instanceLock.Lock()
+	defer instanceLock.Unlock()
+	return currentName
+}
+
+// DestroySDKInstance removes an instance created by CreateSDKInstance, the instance must be logged out
+// and must not be the current one.
+func DestroySDKInstance(handle string) bool {
+	instanceLock.Lock()
+	defer instanceLock.Unlock()
+	u, ok := instances[handle]
+	if !ok || handle == currentName {
+		return false
+	}
+	if u.getLoginStatus(context.Background()) == Logged {
+		return false
+	}
+	u.UnInitSDK()
+	// the grpc connection and the idle connections of the instance are not used anymore
+	ctx := ccontext.WithInfo(context.Background(), u.info)
+	_ = network.SetGrpc(ctx, "")
+	network.CloseIdleConnections(ctx)
+	delete(instances, handle)
+	return true
+}
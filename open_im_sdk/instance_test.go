@@ -0,0 +1,46 @@
+package open_im_sdk
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/network"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+type emptyConnListener struct{}
+
+func (emptyConnListener) OnConnecting()                 {}
+func (emptyConnListener) OnConnectSuccess()             {}
+func (emptyConnListener) OnConnectFailed(int32, string) {}
+func (emptyConnListener) OnKickedOffline()              {}
+func (emptyConnListener) OnUserTokenExpired()           {}
+func (emptyConnListener) OnUserTokenInvalid(string)     {}
+
+// the instances keep their own network settings
+func TestSDKInstances(t *testing.T) {
+	create := func(limit int64) string {
+		config, _ := json.Marshal(&sdk_struct.IMConfig{PlatformID: 1, ApiAddr: "http://127.0.0.1:10002",
+			WsAddr: "ws://127.0.0.1:10001", DataDir: t.TempDir(), BandwidthLimit: &sdk_struct.BandwidthLimit{Global: limit}})
+		handle := CreateSDKInstance(emptyConnListener{}, "op", string(config))
+		if handle == "" {
+			t.Fatal("instance not created")
+		}
+		return handle
+	}
+	first, second := create(1<<20), create(2<<20)
+	defer DestroySDKInstance(first)
+	defer DestroySDKInstance(second)
+	for handle, limit := range map[string]int64{first: 1 << 20, second: 2 << 20} {
+		c := SDKInstanceClient(handle)
+		if c == nil {
+			t.Fatal("no instance", handle)
+		}
+		if got := network.GetBandwidthLimit(c.u.Context()); got == nil || got.Global != limit {
+			t.Fatal(handle, got)
+		}
+	}
+	if SDKInstanceClient("unknown") != nil {
+		t.Fatal("client of an unknown instance")
+	}
+}
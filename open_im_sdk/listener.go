@@ -43,6 +43,152 @@ func SetCustomBusinessListener(listener open_im_sdk_callback.OnCustomBusinessLis
 	listenerCall(IMUserContext.SetCustomBusinessListener, listener)
 }
 
+// AddGroupListener Add a group listener besides the one set. The listener set is called first, then the ones
+// added in the order they were added.
+func AddGroupListener(listener open_im_sdk_callback.OnGroupListener) {
+	listenerCall(IMUserContext.AddGroupListener, listener)
+}
+
+// RemoveGroupListener Remove a group listener added by AddGroupListener.
+func RemoveGroupListener(listener open_im_sdk_callback.OnGroupListener) {
+	listenerCall(IMUserContext.RemoveGroupListener, listener)
+}
+
+// AddConversationListener Add a conversation listener besides the one set, called after it in the order added.
+func AddConversationListener(listener open_im_sdk_callback.OnConversationListener) {
+	listenerCall(IMUserContext.AddConversationListener, listener)
+}
+
+func RemoveConversationListener(listener open_im_sdk_callback.OnConversationListener) {
+	listenerCall(IMUserContext.RemoveConversationListener, listener)
+}
+
+// AddAdvancedMsgListener Add a message listener besides the one set, called after it in the order added.
+func AddAdvancedMsgListener(listener open_im_sdk_callback.OnAdvancedMsgListener) {
+	listenerCall(IMUserContext.AddAdvancedMsgListener, listener)
+}
+
+func RemoveAdvancedMsgListener(listener open_im_sdk_callback.OnAdvancedMsgListener) {
+	listenerCall(IMUserContext.RemoveAdvancedMsgListener, listener)
+}
+
+// AddUserListener Add a user listener besides the one set, called after it in the order added.
+func AddUserListener(listener open_im_sdk_callback.OnUserListener) {
+	listenerCall(IMUserContext.AddUserListener, listener)
+}
+
+func RemoveUserListener(listener open_im_sdk_callback.OnUserListener) {
+	listenerCall(IMUserContext.RemoveUserListener, listener)
+}
+
+// AddFriendListener Add a friendship listener besides the one set, called after it in the order added.
+func AddFriendListener(listener open_im_sdk_callback.OnFriendshipListener) {
+	listenerCall(IMUserContext.AddFriendshipListener, listener)
+}
+
+func RemoveFriendListener(listener open_im_sdk_callback.OnFriendshipListener) {
+	listenerCall(IMUserContext.RemoveFriendshipListener, listener)
+}
+
+// AddCustomBusinessListener Add a custom business listener besides the one set, called after it in the order added.
+func AddCustomBusinessListener(listener open_im_sdk_callback.OnCustomBusinessListener) {
+	listenerCall(IMUserContext.AddCustomBusinessListener, listener)
+}
+
+func RemoveCustomBusinessListener(listener open_im_sdk_callback.OnCustomBusinessListener) {
+	listenerCall(IMUserContext.RemoveCustomBusinessListener, listener)
+}
+
 func SetMessageKvInfoListener(listener open_im_sdk_callback.OnMessageKvInfoListener) {
 	listenerCall(IMUserContext.SetMessageKvInfoListener, listener)
 }
+
+func SetQRLoginListener(listener open_im_sdk_callback.OnQRLoginListener) {
+	listenerCall(IMUserContext.SetQRLoginListener, listener)
+}
+
+func SetTokenListener(listener open_im_sdk_callback.OnTokenListener) {
+	listenerCall(IMUserContext.SetTokenListener, listener)
+}
+
+func SetConnStateListener(listener open_im_sdk_callback.OnConnStateListener) {
+	listenerCall(IMUserContext.SetConnStateListener, listener)
+}
+
+func SetNetworkQualityListener(listener open_im_sdk_callback.OnNetworkQualityListener) {
+	listenerCall(IMUserContext.SetNetworkQualityListener, listener)
+}
+
+func SetAppLifecycleListener(listener open_im_sdk_callback.OnAppLifecycleListener) {
+	listenerCall(IMUserContext.SetAppLifecycleListener, listener)
+}
+
+func SetSyncProgressListener(listener open_im_sdk_callback.OnSyncProgressListener) {
+	listenerCall(IMUserContext.SetSyncProgressListener, listener)
+}
+
+func SetSyncConflictListener(listener open_im_sdk_callback.OnSyncConflictListener) {
+	listenerCall(IMUserContext.SetSyncConflictListener, listener)
+}
+
+func SetDBMigrationListener(listener open_im_sdk_callback.OnDBMigrationListener) {
+	listenerCall(IMUserContext.SetDBMigrationListener, listener)
+}
+
+func SetDBCorruptionListener(listener open_im_sdk_callback.OnDBCorruptionListener) {
+	listenerCall(IMUserContext.SetDBCorruptionListener, listener)
+}
+
+func SetSdkErrorListener(listener open_im_sdk_callback.OnSdkErrorListener) {
+	listenerCall(IMUserContext.SetSdkErrorListener, listener)
+}
+
+// SetEventListener Get the envelope of every event with its sequence IDs, in their order, whether or not the
+// listener of the event is set.
+func SetEventListener(listener open_im_sdk_callback.OnEventListener) {
+	listenerCall(IMUserContext.SetEventListener, listener)
+}
+
+func SetE2EEListener(listener open_im_sdk_callback.OnE2EEListener) {
+	listenerCall(IMUserContext.SetE2EEListener, listener)
+}
+
+func SetClientConfigListener(listener open_im_sdk_callback.OnClientConfigListener) {
+	listenerCall(IMUserContext.SetClientConfigListener, listener)
+}
+
+func SetCustomerServiceListener(listener open_im_sdk_callback.OnCustomerServiceListener) {
+	listenerCall(IMUserContext.SetCustomerServiceListener, listener)
+}
+
+// SetConflictResolver Decide the conflicts between the local and the server state instead of the conflict
+// policies of the config.
+func SetConflictResolver(resolver open_im_sdk_callback.ConflictResolver) {
+	listenerCall(IMUserContext.SetConflictResolver, resolver)
+}
+
+// SetVideoTranscoder Transcode or compress the videos sent before they are uploaded.
+func SetVideoTranscoder(transcoder open_im_sdk_callback.VideoTranscoder) {
+	listenerCall(IMUserContext.SetVideoTranscoder, transcoder)
+}
+
+// SetTranslator Translate the text of the messages for GetMessageTranslation.
+func SetTranslator(translator open_im_sdk_callback.Translator) {
+	listenerCall(IMUserContext.SetTranslator, translator)
+}
+
+// SetModerator Check the messages sent before they are sent, and the ones received with ModerateReceivedMessages
+// of the config.
+func SetModerator(moderator open_im_sdk_callback.Moderator) {
+	listenerCall(IMUserContext.SetModerator, moderator)
+}
+
+// AddMessagePlugin Add a plugin rewriting the messages sent, received and stored, called after the ones added
+// before it.
+func AddMessagePlugin(plugin open_im_sdk_callback.MessagePlugin) {
+	listenerCall(IMUserContext.AddMessagePlugin, plugin)
+}
+
+func RemoveMessagePlugin(plugin open_im_sdk_callback.MessagePlugin) {
+	listenerCall(IMUserContext.RemoveMessagePlugin, plugin)
+}
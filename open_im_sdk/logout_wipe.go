@@ -0,0 +1,118 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package open_im_sdk
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+)
+
+// shredChunk is the size of the zeros written over a file at a time
+const shredChunk = 64 << 10
+
+// wipeMessages removes the messages of all the conversations and their latest messages, the conversations,
+// the friends and the groups are kept. The history is pulled from the server again when opened.
+func (u *UserContext) wipeMessages(ctx context.Context, progress func(done, total int)) error {
+	conversationIDs, err := u.db.GetAllConversationIDList(ctx)
+	if err != nil {
+		return err
+	}
+	for i, conversationID := range conversationIDs {
+		if err := u.db.DeleteConversationAllMessages(ctx, conversationID); err != nil {
+			return err
+		}
+		progress(i+1, len(conversationIDs))
+	}
+	sending, err := u.db.GetAllSendingMessages(ctx)
+	if err != nil {
+		return err
+	}
+	for _, message := range sending {
+		if err := u.db.DeleteSendingMessage(ctx, message.ConversationID, message.ClientMsgID); err != nil {
+			return err
+		}
+	}
+	if err := u.db.ResetAllConversation(ctx); err != nil {
+		return err
+	}
+	log.ZInfo(ctx, "messages wiped at logout", "conversations", len(conversationIDs))
+	return nil
+}
+
+// secureWipe overwrites with zeros and removes the files of the closed database, the media files and their
+// plain copies. The flash storage may keep the old blocks, the encryption of the database and the media is
+// what protects them there.
+func (u *UserContext) secureWipe(ctx context.Context, progress func(done, total int)) error {
+	var files []string
+	if !u.info.Config().InMemoryDB {
+		dbFileName, err := db.DBFileName(u.dbDir(), u.info.UserID)
+		if err != nil {
+			return err
+		}
+		for _, name := range []string{dbFileName, dbFileName + ".archive"} {
+			for _, suffix := range []string{"", "-wal", "-shm", "-journal"} {
+				if _, err := os.Stat(name + suffix); err == nil {
+					files = append(files, name+suffix)
+				}
+			}
+		}
+	}
+	for _, file := range u.mediaFiles(ctx) {
+		files = append(files, file.path)
+	}
+	files = append(files, u.download.DecryptedFiles()...)
+	var errs []error
+	for i, file := range files {
+		if err := shredFile(file); err != nil {
+			log.ZWarn(ctx, "wipe file failed", err, "file", file)
+			errs = append(errs, err)
+		}
+		progress(i+1, len(files))
+	}
+	log.ZInfo(ctx, "local data wiped at logout", "files", len(files), "failed", len(errs))
+	return errors.Join(errs...)
+}
+
+// shredFile overwrites the content of the file with zeros before removing it.
+func shredFile(path string) error {
+	file, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	info, err := file.Stat()
+	if err == nil {
+		zeros := make([]byte, shredChunk)
+		for left := info.Size(); left > 0 && err == nil; left -= shredChunk {
+			_, err = file.Write(zeros[:min(left, shredChunk)])
+		}
+	}
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
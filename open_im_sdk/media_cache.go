@@ -0,0 +1,144 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package open_im_sdk
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+func (u *UserContext) PinMessageMedia(ctx context.Context, conversationID, clientMsgID string) error {
+	if _, err := u.db.GetMessage(ctx, conversationID, clientMsgID); err != nil {
+		return err
+	}
+	return u.db.InsertMediaPin(ctx, &model_struct.LocalMediaPin{
+		ConversationID: conversationID,
+		ClientMsgID:    clientMsgID,
+		CreateTime:     time.Now().UnixMilli(),
+	})
+}
+
+func (u *UserContext) UnpinMessageMedia(ctx context.Context, conversationID, clientMsgID string) error {
+	return u.db.DeleteMediaPin(ctx, conversationID, clientMsgID)
+}
+
+func (u *UserContext) SetMediaCacheKey(masterKey string) {
+	u.mediaKey = masterKey
+	u.download.SetMediaKey(masterKey)
+}
+
+func (u *UserContext) ClearMediaCache(ctx context.Context) (*sdk_struct.MediaCacheResult, error) {
+	kept, err := u.keptMedia(ctx, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	files := u.mediaFiles(ctx)
+	var size int64
+	for _, file := range files {
+		size += file.size
+	}
+	result := &sdk_struct.MediaCacheResult{}
+	for _, file := range u.evictMedia(ctx, files, kept, size, 0, constant.MediaEvictReasonClear) {
+		result.RemovedMediaFiles++
+		result.FreedMedia += file.Size
+	}
+	log.ZInfo(ctx, "media cache cleared", "result", result)
+	return result, nil
+}
+
+func checkMediaCacheLimit(limit int64) error {
+	if limit < 0 {
+		return sdkerrs.ErrArgs.WrapMsg("media cache limit can't be negative")
+	}
+	return nil
+}
+
+// enforceMediaCacheLimit removes the least recently used media files beyond MediaCacheLimit. The media of the
+// pinned conversations and pinned friends, of the pinned messages and of the messages being sent are kept.
+func (u *UserContext) enforceMediaCacheLimit(ctx context.Context) error {
+	limit := u.info.Config().MediaCacheLimit
+	if limit <= 0 {
+		return nil
+	}
+	files := u.mediaFiles(ctx)
+	var size int64
+	for _, file := range files {
+		size += file.size
+	}
+	if size <= limit {
+		return nil
+	}
+	conversations, err := u.db.GetAllConversations(ctx)
+	if err != nil {
+		return err
+	}
+	pinnedFriends, err := u.pinnedFriends(ctx)
+	if err != nil {
+		return err
+	}
+	kept, err := u.keptMedia(ctx, conversations, pinnedFriends)
+	if err != nil {
+		return err
+	}
+	evicted := u.evictMedia(ctx, files, kept, size, limit, constant.MediaEvictReasonLimit)
+	log.ZInfo(ctx, "media cache evicted", "removed", len(evicted), "size", size, "limit", limit)
+	return nil
+}
+
+// evictMedia removes the files not kept, the least recently used first, until their size is within limit.
+// The files removed are reported to the media cache listener.
+func (u *UserContext) evictMedia(ctx context.Context, files []*mediaFile, kept map[string]struct{}, size, limit int64, reason string) []*sdk_struct.EvictedMedia {
+	// the media files are touched when used again, see EnqueueDownload
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.Before(files[j].modTime)
+	})
+	var evicted []*sdk_struct.EvictedMedia
+	for _, file := range files {
+		if size <= limit {
+			break
+		}
+		if _, ok := kept[filepath.Base(file.path)]; ok {
+			continue
+		}
+		if err := os.Remove(file.path); err != nil {
+			log.ZWarn(ctx, "remove media file failed", err, "path", file.path)
+			continue
+		}
+		size -= file.size
+		evicted = append(evicted, &sdk_struct.EvictedMedia{Path: file.path, Size: file.size})
+	}
+	if listener := u.MediaCacheListener(); len(evicted) > 0 && listener != nil {
+		listener.OnMediaEvicted(utils.StructToJsonString(&sdk_struct.MediaEviction{Reason: reason, Files: evicted}))
+	}
+	return evicted
+}
+
+// mediaCacheChanged has the media cache checked, called after each download.
+func (u *UserContext) mediaCacheChanged() {
+	select {
+	case u.mediaCacheCh <- struct{}{}:
+	default:
+	}
+}
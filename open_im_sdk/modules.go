@@ -0,0 +1,111 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package open_im_sdk
+
+import (
+	"context"
+	"sort"
+
+	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+// The modules of the sdk. The core one, login, messages, conversations, friends, groups and users, is always
+// built; the others are left out of the build by the build tag no_<module>, e.g. gomobile bind -tags
+// "no_moments no_org no_signaling" for an app of chat only. The core doesn't import the packages of the other
+// modules, the files of a module built register it with its hooks, so that a module left out leaves its code
+// out of the bindings.
+const (
+	ModuleCore      = "core"
+	ModuleFiles     = "files"
+	ModuleMoments   = "moments"
+	ModuleOrg       = "org"
+	ModuleSignaling = "signaling"
+)
+
+var modules = []string{ModuleCore}
+
+// moduleHooks set a module built up in the user context, a hook not needed is nil.
+type moduleHooks struct {
+	// init creates the module for a new session of the user context
+	init func(u *UserContext)
+	// login sets the module up for the login user
+	login func(ctx context.Context, u *UserContext, userID string, config *sdk_struct.IMConfig)
+	// setListener gives the listeners of the app to the module
+	setListener func(ctx context.Context, u *UserContext)
+}
+
+var builtModuleHooks []moduleHooks
+
+// registerModule is called by the init of the files of a module built.
+func registerModule(module string, hooks moduleHooks) {
+	modules = append(modules, module)
+	sort.Strings(modules)
+	builtModuleHooks = append(builtModuleHooks, hooks)
+}
+
+func (u *UserContext) initModules() {
+	for _, hooks := range builtModuleHooks {
+		if hooks.init != nil {
+			hooks.init(u)
+		}
+	}
+}
+
+func (u *UserContext) loginModules(ctx context.Context, userID string, config *sdk_struct.IMConfig) {
+	for _, hooks := range builtModuleHooks {
+		if hooks.login != nil {
+			hooks.login(ctx, u, userID, config)
+		}
+	}
+}
+
+func (u *UserContext) setModuleListeners(ctx context.Context) {
+	for _, hooks := range builtModuleHooks {
+		if hooks.setListener != nil {
+			hooks.setListener(ctx, u)
+		}
+	}
+}
+
+// GetSdkModules Get the json array of the modules built in the bindings, e.g. ["core","files"].
+func GetSdkModules() string {
+	return utils.StructToJsonString(modules)
+}
+
+// downloader is what the core uses of the download manager of the files module.
+type downloader interface {
+	SetDir(dir string)
+	SetConcurrency(concurrency int)
+	SetMediaKey(masterKey string)
+	SetListener(listener func() open_im_sdk_callback.OnDownloadListener)
+	Schedule()
+	List() []*sdk_struct.DownloadInfo
+	ClearDecrypted(ctx context.Context)
+	DecryptedFiles() []string
+}
+
+// noDownloader is the download manager of a build without the files module.
+type noDownloader struct{}
+
+func (noDownloader) SetDir(string)                                              {}
+func (noDownloader) SetConcurrency(int)                                         {}
+func (noDownloader) SetMediaKey(string)                                         {}
+func (noDownloader) SetListener(func() open_im_sdk_callback.OnDownloadListener) {}
+func (noDownloader) Schedule()                                                  {}
+func (noDownloader) List() []*sdk_struct.DownloadInfo                           { return nil }
+func (noDownloader) ClearDecrypted(context.Context)                             {}
+func (noDownloader) DecryptedFiles() []string                                   { return nil }
@@ -0,0 +1,109 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !no_moments
+// +build !no_moments
+
+package open_im_sdk
+
+import (
+	"context"
+
+	"github.com/openimsdk/openim-sdk-core/v3/internal/moments"
+	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdk_params_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+func init() {
+	registerModule(ModuleMoments, moduleHooks{
+		init: func(u *UserContext) {
+			m := moments.NewMoments(u.file)
+			u.moments = m
+			u.conversation.SetMoments(m)
+		},
+		login: func(_ context.Context, u *UserContext, userID string, _ *sdk_struct.IMConfig) {
+			u.Moments().SetLoginUserID(userID)
+			u.Moments().SetDataBase(u.db)
+		},
+		setListener: func(ctx context.Context, u *UserContext) {
+			setListener(ctx, &u.momentsListener, u.MomentsListener, u.Moments().SetListener, newEmptyMomentsListener)
+		},
+	})
+}
+
+func (u *UserContext) Moments() *moments.Moments {
+	m, _ := u.moments.(*moments.Moments)
+	return m
+}
+
+// PublishMoment Upload the local files of the media then publish the moment, the callback gets it as published.
+func PublishMoment(callback open_im_sdk_callback.Base, operationID string, params string) {
+	call(callback, operationID, IMUserContext.Moments().PublishMoment, params)
+}
+
+// DeleteMoment Delete a moment of the login user.
+func DeleteMoment(callback open_im_sdk_callback.Base, operationID string, momentID string) {
+	call(callback, operationID, IMUserContext.Moments().DeleteMoment, momentID)
+}
+
+// GetMomentFeed Get the page of the moments of the friends following the opaque cursor, or of the moments of the
+// user when userID is set. The page comes from the local cache when the server can't be reached.
+func GetMomentFeed(callback open_im_sdk_callback.Base, operationID string, userID, cursor string, count int) {
+	call(callback, operationID, IMUserContext.Moments().GetMomentFeed, userID, cursor, count)
+}
+
+// LikeMoment Like the moment, or take the like back.
+func LikeMoment(callback open_im_sdk_callback.Base, operationID string, momentID string, like bool) {
+	call(callback, operationID, IMUserContext.Moments().LikeMoment, momentID, like)
+}
+
+// CommentMoment Comment the moment, or reply to the comment of the user.
+func CommentMoment(callback open_im_sdk_callback.Base, operationID string, momentID, replyToUserID, content string) {
+	call(callback, operationID, IMUserContext.Moments().CommentMoment, momentID, replyToUserID, content)
+}
+
+// DeleteMomentComment Delete a comment of the login user, or any comment of a moment of the login user.
+func DeleteMomentComment(callback open_im_sdk_callback.Base, operationID string, momentID, commentID string) {
+	call(callback, operationID, IMUserContext.Moments().DeleteMomentComment, momentID, commentID)
+}
+
+func SetMomentsListener(listener open_im_sdk_callback.OnMomentsListener) {
+	listenerCall(IMUserContext.SetMomentsListener, listener)
+}
+
+func (c *Client) PublishMoment(ctx context.Context, params *sdk_params_callback.PublishMomentParams) (*model_struct.LocalMoment, error) {
+	return clientCall[*model_struct.LocalMoment](ctx, c, c.u.Moments().PublishMoment, params)
+}
+
+func (c *Client) DeleteMoment(ctx context.Context, momentID string) error {
+	return clientExec(ctx, c, c.u.Moments().DeleteMoment, momentID)
+}
+
+func (c *Client) GetMomentFeed(ctx context.Context, userID, cursor string, count int) (*sdk_params_callback.GetMomentsCallback, error) {
+	return clientCall[*sdk_params_callback.GetMomentsCallback](ctx, c, c.u.Moments().GetMomentFeed, userID, cursor, count)
+}
+
+func (c *Client) LikeMoment(ctx context.Context, momentID string, like bool) (*model_struct.LocalMoment, error) {
+	return clientCall[*model_struct.LocalMoment](ctx, c, c.u.Moments().LikeMoment, momentID, like)
+}
+
+func (c *Client) CommentMoment(ctx context.Context, momentID, replyToUserID, content string) (*model_struct.LocalMoment, error) {
+	return clientCall[*model_struct.LocalMoment](ctx, c, c.u.Moments().CommentMoment, momentID, replyToUserID, content)
+}
+
+func (c *Client) DeleteMomentComment(ctx context.Context, momentID, commentID string) (*model_struct.LocalMoment, error) {
+	return clientCall[*model_struct.LocalMoment](ctx, c, c.u.Moments().DeleteMomentComment, momentID, commentID)
+}
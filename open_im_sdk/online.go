@@ -23,3 +23,8 @@ func GetSubscribeUsersStatus(callback open_im_sdk_callback.Base, operationID str
 func GetUserStatus(callback open_im_sdk_callback.Base, operationID string, userIDs string) {
 	call(callback, operationID, IMUserContext.LongConnMgr().SubscribeUsersStatus, userIDs)
 }
+
+// GetUserLastSeen Get when users were last seen, rounded to the precision configured at init.
+func GetUserLastSeen(callback open_im_sdk_callback.Base, operationID string, userIDs string) {
+	call(callback, operationID, IMUserContext.User().GetUserLastSeen, userIDs)
+}
@@ -0,0 +1,103 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !no_org
+// +build !no_org
+
+package open_im_sdk
+
+import (
+	"context"
+
+	"github.com/openimsdk/openim-sdk-core/v3/internal/organization"
+	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdk_params_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+func init() {
+	registerModule(ModuleOrg, moduleHooks{
+		init: func(u *UserContext) {
+			o := organization.NewOrganization()
+			u.organization = o
+			u.conversation.SetOrganization(o)
+		},
+		login: func(_ context.Context, u *UserContext, userID string, config *sdk_struct.IMConfig) {
+			u.Organization().SetLoginUserID(userID)
+			u.Organization().SetDataBase(u.db)
+			u.Organization().SetEnabled(config.EnableOrganization)
+		},
+		setListener: func(ctx context.Context, u *UserContext) {
+			setListener(ctx, &u.organizationListener, u.OrganizationListener, u.Organization().SetListener, newEmptyOrganizationListener)
+		},
+	})
+}
+
+func (u *UserContext) Organization() *organization.Organization {
+	o, _ := u.organization.(*organization.Organization)
+	return o
+}
+
+// GetSubDepartments Get the departments right under the department and its members, the top departments for
+// an empty departmentID.
+func GetSubDepartments(callback open_im_sdk_callback.Base, operationID string, departmentID string) {
+	call(callback, operationID, IMUserContext.Organization().GetSubDepartments, departmentID)
+}
+
+// GetDepartmentTree Walk the departments from the department down, depth levels of sub departments or all of
+// them for 0, with their members when withMembers is set.
+func GetDepartmentTree(callback open_im_sdk_callback.Base, operationID string, departmentID string, depth int, withMembers bool) {
+	call(callback, operationID, IMUserContext.Organization().GetDepartmentTree, departmentID, depth, withMembers)
+}
+
+// GetDepartmentMembers Get the members of the department.
+func GetDepartmentMembers(callback open_im_sdk_callback.Base, operationID string, departmentID string) {
+	call(callback, operationID, IMUserContext.Organization().GetDepartmentMembers, departmentID)
+}
+
+// GetUserInDepartments Get the departments the user is in, with the path from the top department to each.
+func GetUserInDepartments(callback open_im_sdk_callback.Base, operationID string, userID string) {
+	call(callback, operationID, IMUserContext.Organization().GetUserInDepartments, userID)
+}
+
+// SearchOrganization Search the local organization for the members by nickname or position and for the
+// departments by name.
+func SearchOrganization(callback open_im_sdk_callback.Base, operationID string, searchParams string) {
+	call(callback, operationID, IMUserContext.Organization().SearchOrganization, searchParams)
+}
+
+func SetOrganizationListener(listener open_im_sdk_callback.OnOrganizationListener) {
+	listenerCall(IMUserContext.SetOrganizationListener, listener)
+}
+
+func (c *Client) GetSubDepartments(ctx context.Context, departmentID string) (*sdk_params_callback.GetSubDepartmentsCallback, error) {
+	return clientCall[*sdk_params_callback.GetSubDepartmentsCallback](ctx, c, c.u.Organization().GetSubDepartments, departmentID)
+}
+
+func (c *Client) GetDepartmentTree(ctx context.Context, departmentID string, depth int, withMembers bool) ([]*sdk_params_callback.DepartmentNode, error) {
+	return clientCall[[]*sdk_params_callback.DepartmentNode](ctx, c, c.u.Organization().GetDepartmentTree, departmentID, depth, withMembers)
+}
+
+func (c *Client) GetDepartmentMembers(ctx context.Context, departmentID string) ([]*model_struct.LocalDepartmentMember, error) {
+	return clientCall[[]*model_struct.LocalDepartmentMember](ctx, c, c.u.Organization().GetDepartmentMembers, departmentID)
+}
+
+func (c *Client) GetUserInDepartments(ctx context.Context, userID string) ([]*sdk_params_callback.UserInDepartment, error) {
+	return clientCall[[]*sdk_params_callback.UserInDepartment](ctx, c, c.u.Organization().GetUserInDepartments, userID)
+}
+
+func (c *Client) SearchOrganization(ctx context.Context, params *sdk_params_callback.SearchOrganizationParams) (*sdk_params_callback.SearchOrganizationCallback, error) {
+	return clientCall[*sdk_params_callback.SearchOrganizationCallback](ctx, c, c.u.Organization().SearchOrganization, params)
+}
@@ -0,0 +1,172 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package open_im_sdk
+
+import (
+	"context"
+
+	conv "github.com/openimsdk/openim-sdk-core/v3/internal/conversation_msg"
+	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/audit"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/dataexport"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/version"
+	"github.com/openimsdk/tools/errs"
+)
+
+// exportPageSize is the messages read from the local database at a time while they are exported.
+const exportPageSize = 500
+
+// ExportAllPersonalData Export the personal data of the login user kept by the sdk to the archive at path, for
+// the data portability requests: the profile, the relations, the groups, the conversations, the messages and
+// the manifest of their media, see pkg/dataexport for the format. Returns the manifest of the archive.
+func ExportAllPersonalData(callback open_im_sdk_callback.Base, operationID string, path string, progress open_im_sdk_callback.ExportPersonalDataProgress) {
+	call(callback, operationID, IMUserContext.ExportAllPersonalData, path, progress)
+}
+
+func (u *UserContext) ExportAllPersonalData(ctx context.Context, path string, progress open_im_sdk_callback.ExportPersonalDataProgress) (*dataexport.Manifest, error) {
+	if path == "" {
+		return nil, sdkerrs.ErrArgs.WrapMsg("export path is required")
+	}
+	conversations, err := u.db.GetAllConversations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	w, err := dataexport.Create(path)
+	if err != nil {
+		return nil, sdkerrs.ErrStorage.WrapMsg(err.Error())
+	}
+	manifest := &dataexport.Manifest{UserID: u.loginUserID, SDKVersion: version.Version, Conversations: len(conversations)}
+	// the profile, the friends and the users blocked, the groups and the conversations, then the messages of
+	// each conversation
+	steps := []func() error{
+		func() error { return u.exportProfile(ctx, w) },
+		func() error { return u.exportRelations(ctx, w) },
+		func() error {
+			groups, err := u.db.GetJoinedGroupListDB(ctx)
+			if err != nil {
+				return err
+			}
+			return w.WriteJSON(dataexport.GroupsEntry, groups)
+		},
+		func() error { return w.WriteJSON(dataexport.ConversationsEntry, conversations) },
+	}
+	for _, conversation := range conversations {
+		steps = append(steps, func() error {
+			count, err := u.exportMessages(ctx, w, conversation.ConversationID)
+			manifest.Messages += count
+			return err
+		})
+	}
+	for i, step := range steps {
+		if err := ctx.Err(); err != nil {
+			w.Abort()
+			return nil, err
+		}
+		if err := step(); err != nil {
+			w.Abort()
+			return nil, err
+		}
+		if progress != nil {
+			progress.OnProgress(i+1, len(steps))
+		}
+	}
+	if err := w.Close(manifest); err != nil {
+		return nil, sdkerrs.ErrStorage.WrapMsg(err.Error())
+	}
+	log.ZInfo(ctx, "personal data exported", "path", path, "manifest", manifest)
+	audit.Record(ctx, audit.ActionExport, "ExportAllPersonalData", path)
+	return manifest, nil
+}
+
+func (u *UserContext) exportProfile(ctx context.Context, w *dataexport.Writer) error {
+	user, err := u.db.GetLoginUser(ctx, u.loginUserID)
+	if err != nil && !errs.ErrRecordNotFound.Is(errs.Unwrap(err)) {
+		return err
+	}
+	privacy, err := u.db.GetPrivacySettings(ctx, u.loginUserID)
+	if err != nil && !errs.ErrRecordNotFound.Is(errs.Unwrap(err)) {
+		return err
+	}
+	return w.WriteJSON(dataexport.ProfileEntry, struct {
+		User            *model_struct.LocalUser            `json:"user"`
+		PrivacySettings *model_struct.LocalPrivacySettings `json:"privacySettings"`
+	}{user, privacy})
+}
+
+func (u *UserContext) exportRelations(ctx context.Context, w *dataexport.Writer) error {
+	friends, err := u.db.GetAllFriendList(ctx)
+	if err != nil {
+		return err
+	}
+	if err := w.WriteJSON(dataexport.FriendsEntry, friends); err != nil {
+		return err
+	}
+	blacks, err := u.db.GetBlackListDB(ctx)
+	if err != nil {
+		return err
+	}
+	return w.WriteJSON(dataexport.BlacksEntry, blacks)
+}
+
+// exportMessages streams the messages of the conversation, the oldest first, and adds their media to the
+// media entry. Returns the messages exported.
+func (u *UserContext) exportMessages(ctx context.Context, w *dataexport.Writer, conversationID string) (int64, error) {
+	lines, err := w.CreateLines(dataexport.MessagesEntry(conversationID))
+	if err != nil {
+		return 0, err
+	}
+	var (
+		count            int64
+		startTime        int64
+		startSeq         int64
+		startClientMsgID string
+		// seen are the messages sent at startTime, the messages without seq of the same time are paged by
+		// their client message ID only
+		seen = make(map[string]struct{})
+	)
+	for {
+		page, err := u.db.GetMessageList(ctx, conversationID, exportPageSize, startTime, startSeq, startClientMsgID, true)
+		if err != nil {
+			return count, err
+		}
+		var added int
+		for _, local := range page {
+			if local.SendTime != startTime {
+				startTime, seen = local.SendTime, make(map[string]struct{})
+			} else if _, ok := seen[local.ClientMsgID]; ok {
+				continue
+			}
+			seen[local.ClientMsgID] = struct{}{}
+			startSeq, startClientMsgID = local.Seq, local.ClientMsgID
+			msg := conv.LocalChatLogToMsgStruct(local)
+			if err := lines.Write(msg); err != nil {
+				return count, err
+			}
+			if media := dataexport.MediaOf(conversationID, msg); media != nil {
+				if err := w.AddMedia(media); err != nil {
+					return count, err
+				}
+			}
+			count++
+			added++
+		}
+		if len(page) < exportPageSize || added == 0 {
+			return count, nil
+		}
+	}
+}
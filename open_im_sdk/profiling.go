@@ -0,0 +1,56 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package open_im_sdk
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/profiling"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+// profileDir names the directory in DataDir the profiles are written to
+const profileDir = "profiles"
+
+// StartProfiling Start to profile the SDK, profiles is a json array of cpu, heap and goroutine, all of them when
+// empty. The cpu profile covers the time until StopProfiling, the heap and the goroutine profiles are taken
+// when it stops. A profiling left running stops after 10 minutes. Can be called before login.
+func StartProfiling(callback open_im_sdk_callback.Base, operationID string, profiles string) {
+	call(callback, operationID, IMUserContext.StartProfiling, profiles)
+}
+
+// StopProfiling Stop the profiling and return the paths of the pprof files written in DataDir/profiles.
+func StopProfiling(callback open_im_sdk_callback.Base, operationID string) {
+	call(callback, operationID, IMUserContext.StopProfiling)
+}
+
+func (u *UserContext) StartProfiling(ctx context.Context, profiles []string) error {
+	return profiling.Start(ctx, profiles)
+}
+
+func (u *UserContext) StopProfiling(ctx context.Context) ([]string, error) {
+	return profiling.Stop(ctx)
+}
+
+// setProfiling has the profiles written to the data dir and samples the runtime for the diagnostics.
+func (u *UserContext) setProfiling(config *sdk_struct.IMConfig) {
+	if config.DataDir != "" {
+		profiling.SetDir(filepath.Join(config.DataDir, profileDir))
+	}
+	profiling.StartStats(time.Duration(config.RuntimeStatsInterval) * time.Second)
+}
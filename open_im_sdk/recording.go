@@ -0,0 +1,224 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package open_im_sdk
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/recorder"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/version"
+	"github.com/openimsdk/protocol/sdkws"
+)
+
+const (
+	// recordingDir names the directory in DataDir the recordings are written to, and replayDir the one of the
+	// databases of the replays
+	recordingDir = "recordings"
+	replayDir    = "replay"
+	// replaySettle is how long the queues of the pipeline stay empty before a replay is done
+	replaySettle = time.Second
+	// replayTimeout bounds the wait for the pipeline after the events are fed
+	replayTimeout = time.Minute
+)
+
+// StartRecording Record the frames the long connection receives, the requests it sends with their responses and
+// the messages the sync dispatches to the conversations into a file of DataDir/recordings. Start it before
+// login to record the sync after login. The recording holds the messages, it is not redacted.
+func StartRecording(callback open_im_sdk_callback.Base, operationID string) {
+	call(callback, operationID, IMUserContext.StartRecording)
+}
+
+// StopRecording Stop the recording and return the path of its file.
+func StopRecording(callback open_im_sdk_callback.Base, operationID string) {
+	call(callback, operationID, IMUserContext.StopRecording)
+}
+
+// ReplayRecording Replay a recording of StartRecording against a fresh database in DataDir/replay. It logs in
+// as the recorded user without connecting, the recorded frames are fed through the sync and its requests are
+// answered with the recorded responses, then it logs out. The http api calls are not replayed. speed divides
+// the time between the events, 0 replays them without waiting. Returns the dispatches to the conversations
+// that differ from the recorded ones. Must be called while logged out.
+func ReplayRecording(callback open_im_sdk_callback.Base, operationID string, path string, speed float64) {
+	call(callback, operationID, IMUserContext.ReplayRecording, path, speed)
+}
+
+func (u *UserContext) StartRecording(ctx context.Context) (string, error) {
+	path := filepath.Join(u.info.Config().DataDir, recordingDir, "recording_"+time.Now().Format("20060102_150405")+".jsonl")
+	if err := recorder.Start(path, &recorder.Header{UserID: u.info.UserID, PlatformID: u.info.Config().PlatformID,
+		SDKVersion: version.Version}); err != nil {
+		return "", err
+	}
+	log.ZInfo(ctx, "recording started", "path", path)
+	return path, nil
+}
+
+func (u *UserContext) StopRecording(ctx context.Context) (string, error) {
+	path, events, err := recorder.Stop()
+	log.ZInfo(ctx, "recording stopped", "path", path, "events", events, "err", err)
+	return path, err
+}
+
+func (u *UserContext) ReplayRecording(ctx context.Context, path string, speed float64) (*sdk_struct.ReplayResult, error) {
+	if u.getLoginStatus(ctx) != LogoutStatus {
+		return nil, sdkerrs.ErrArgs.WrapMsg("log out before replaying a recording")
+	}
+	if speed < 0 {
+		return nil, sdkerrs.ErrArgs.WrapMsg("speed must not be negative")
+	}
+	header, events, err := recorder.Read(path)
+	if err != nil {
+		return nil, err
+	}
+	userID := header.UserID
+	for _, e := range events {
+		if e.Kind == recorder.KindLogin {
+			userID = e.UserID
+			break
+		}
+	}
+	if userID == "" {
+		return nil, sdkerrs.ErrArgs.WrapMsg("the recording has no login")
+	}
+	// the database kept open by SwitchAccount is not fresh
+	u.closeKeptAccount(ctx, userID)
+	name := time.Now().Format("20060102_150405")
+	res := &sdk_struct.ReplayResult{
+		Path:   filepath.Join(u.info.Config().DataDir, replayDir, name, "replayed.jsonl"),
+		DBDir:  filepath.Join(u.info.Config().DataDir, replayDir, name),
+		Events: len(events),
+	}
+	if err := recorder.Start(res.Path, &recorder.Header{UserID: userID, PlatformID: header.PlatformID,
+		SDKVersion: version.Version}); err != nil {
+		return nil, err
+	}
+	u.replayDBDir = res.DBDir
+	defer func() { u.replayDBDir = "" }()
+	u.longConnMgr.SetReplay(events)
+	log.ZInfo(ctx, "replay started", "path", path, "userID", userID, "events", len(events), "dbDir", res.DBDir)
+	if err := u.login(ctx, userID, "", nil); err != nil {
+		_, _, _ = recorder.Stop()
+		// the next login connects again
+		u.initResources()
+		return nil, err
+	}
+	replayErr := u.longConnMgr.Replay(ctx, events, speed)
+	if replayErr == nil {
+		replayErr = u.waitReplaySettled(ctx)
+	}
+	res.MissedRequests = u.longConnMgr.ReplayMissed()
+	_, _, stopErr := recorder.Stop()
+	if err := u.logout(ctx, true, constant.LogoutModeKeepData, nil); err != nil {
+		log.ZWarn(ctx, "logout after replay failed", err)
+	}
+	if replayErr != nil {
+		return nil, replayErr
+	}
+	if stopErr != nil {
+		return nil, stopErr
+	}
+	_, replayed, err := recorder.Read(res.Path)
+	if err != nil {
+		return nil, err
+	}
+	res.RecordedDispatches, res.ReplayedDispatches, res.Mismatches = diffDispatches(events, replayed)
+	log.ZInfo(ctx, "replay done", "result", res)
+	return res, nil
+}
+
+// waitReplaySettled waits until the sync and the conversations handled what the replay fed them.
+func (u *UserContext) waitReplaySettled(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, replayTimeout)
+	defer cancel()
+	ticker := time.NewTicker(replaySettle / 10)
+	defer ticker.Stop()
+	var idleSince time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return sdkerrs.ErrCtxDeadline.WrapMsg("the replay did not settle")
+		case now := <-ticker.C:
+			if len(u.msgSyncerCh) > 0 || len(u.conversationEventQueue) > 0 {
+				idleSince = time.Time{}
+				continue
+			}
+			if idleSince.IsZero() {
+				idleSince = now
+			} else if now.Sub(idleSince) >= replaySettle {
+				return nil
+			}
+		}
+	}
+}
+
+// diffDispatches compares the dispatches of a recording and of its replay in order.
+func diffDispatches(recorded, replayed []*recorder.Event) (int, int, []string) {
+	a, b := dispatches(recorded), dispatches(replayed)
+	var mismatches []string
+	for i := 0; i < len(a) || i < len(b); i++ {
+		switch {
+		case i >= len(a):
+			mismatches = append(mismatches, fmt.Sprintf("dispatch %d: replayed %s not recorded", i, describeDispatch(b[i])))
+		case i >= len(b):
+			mismatches = append(mismatches, fmt.Sprintf("dispatch %d: recorded %s not replayed", i, describeDispatch(a[i])))
+		case a[i].Cmd != b[i].Cmd || string(a[i].Data) != string(b[i].Data):
+			mismatches = append(mismatches, fmt.Sprintf("dispatch %d: recorded %s, replayed %s", i,
+				describeDispatch(a[i]), describeDispatch(b[i])))
+		}
+	}
+	return len(a), len(b), mismatches
+}
+
+func dispatches(events []*recorder.Event) []*recorder.Event {
+	var res []*recorder.Event
+	for _, e := range events {
+		if e.Kind == recorder.KindDispatch {
+			res = append(res, e)
+		}
+	}
+	return res
+}
+
+// describeDispatch tells the command and the seqs of the conversations of a dispatch.
+func describeDispatch(e *recorder.Event) string {
+	var msgs sdkws.PushMessages
+	if err := proto.Unmarshal(e.Data, &msgs); err != nil {
+		return e.Cmd + " (invalid data)"
+	}
+	conversationIDs := make([]string, 0, len(msgs.Msgs))
+	for conversationID := range msgs.Msgs {
+		conversationIDs = append(conversationIDs, conversationID)
+	}
+	sort.Strings(conversationIDs)
+	parts := make([]string, 0, len(conversationIDs))
+	for _, conversationID := range conversationIDs {
+		seqs := make([]string, 0, len(msgs.Msgs[conversationID].Msgs))
+		for _, msg := range msgs.Msgs[conversationID].Msgs {
+			seqs = append(seqs, fmt.Sprint(msg.Seq))
+		}
+		parts = append(parts, conversationID+"["+strings.Join(seqs, ",")+"]")
+	}
+	return e.Cmd + " " + strings.Join(parts, " ")
+}
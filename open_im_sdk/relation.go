@@ -28,6 +28,11 @@ func GetFriendListPage(callback open_im_sdk_callback.Base, operationID string, o
 	call(callback, operationID, IMUserContext.Relation().GetFriendListPage, offset, count, filterBlack)
 }
 
+// GetFriendListByCursor gets the page of the friend list following the opaque cursor, the first page for an empty cursor.
+func GetFriendListByCursor(callback open_im_sdk_callback.Base, operationID string, cursor string, count int32, filterBlack bool) {
+	call(callback, operationID, IMUserContext.Relation().GetFriendListByCursor, cursor, count, filterBlack)
+}
+
 func SearchFriends(callback open_im_sdk_callback.Base, operationID string, searchParam string) {
 	call(callback, operationID, IMUserContext.Relation().SearchFriends, searchParam)
 }
@@ -0,0 +1,54 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package open_im_sdk
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+
+	"github.com/openimsdk/tools/mcontext"
+	"github.com/openimsdk/tools/utils/jsonutil"
+)
+
+// reportSdkError passes an error of a background goroutine to the SdkErrorListener.
+func (u *UserContext) reportSdkError(ctx context.Context, source, operation string, err error, keysAndValues []any) {
+	listener := u.SdkErrorListener()
+	if listener == nil {
+		return
+	}
+	code := sdkerrs.Code(err)
+	sdkError := &sdk_struct.SdkError{
+		Source:      source,
+		Operation:   operation,
+		ErrCode:     code,
+		Category:    sdkerrs.Category(int(code)),
+		OperationID: mcontext.GetOperationID(ctx),
+		Time:        time.Now().UnixMilli(),
+	}
+	if err != nil {
+		sdkError.ErrMsg = err.Error()
+	}
+	if len(keysAndValues) > 0 {
+		sdkError.Context = make(map[string]any, len(keysAndValues)/2)
+		for i := 0; i+1 < len(keysAndValues); i += 2 {
+			sdkError.Context[fmt.Sprint(keysAndValues[i])] = keysAndValues[i+1]
+		}
+	}
+	listener.OnSdkError(jsonutil.StructToJsonString(sdkError))
+}
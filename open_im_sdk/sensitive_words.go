@@ -0,0 +1,28 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package open_im_sdk
+
+import "github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
+
+// SetSensitiveWordList Set the words of the sensitive word list with the name, a json array of strings, the
+// messages are checked against all the lists as the sensitiveWordMode of the config says. No word removes the list.
+func SetSensitiveWordList(callback open_im_sdk_callback.Base, operationID string, name, words string) {
+	call(callback, operationID, IMUserContext.Conversation().SetSensitiveWordList, name, words)
+}
+
+// FindSensitiveWords Get the sensitive words of the text, e.g. to warn the user while typing.
+func FindSensitiveWords(callback open_im_sdk_callback.Base, operationID string, text string) {
+	call(callback, operationID, IMUserContext.Conversation().FindSensitiveWords, text)
+}
@@ -0,0 +1,130 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !no_signaling
+// +build !no_signaling
+
+package open_im_sdk
+
+import (
+	"context"
+
+	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdk_params_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+func init() {
+	registerModule(ModuleSignaling, moduleHooks{
+		setListener: func(ctx context.Context, u *UserContext) {
+			setListener(ctx, &u.signalingListener, u.SignalingListener, u.conversation.SetSignalingListener, newEmptySignalingListener)
+		},
+	})
+}
+
+// SignalingInvite Call a user, the invitation is returned with its room ID which the other calls take. The
+// SignalingListener tells the answer, and the call message is inserted in the conversation once it is over.
+func SignalingInvite(callback open_im_sdk_callback.Base, operationID string, invitation string) {
+	call(callback, operationID, IMUserContext.Conversation().SignalingInvite, invitation)
+}
+
+// SignalingInviteInGroup Start a call in the group, the invitees are rung and the other members can join it. No
+// invitees rings all the members of a small group.
+func SignalingInviteInGroup(callback open_im_sdk_callback.Base, operationID string, invitation string) {
+	call(callback, operationID, IMUserContext.Conversation().SignalingInviteInGroup, invitation)
+}
+
+// SignalingJoin Join the call of a group, the roster changes are told by the SignalingListener.
+func SignalingJoin(callback open_im_sdk_callback.Base, operationID string, roomID, customData string) {
+	call(callback, operationID, IMUserContext.Conversation().SignalingJoin, roomID, customData)
+}
+
+// SignalingGetRoomByGroupID Get the call of the group with the users in it.
+func SignalingGetRoomByGroupID(callback open_im_sdk_callback.Base, operationID string, groupID string) {
+	call(callback, operationID, IMUserContext.Conversation().SignalingGetRoomByGroupID, groupID)
+}
+
+// SignalingAccept Answer the call the user is invited to, the app then joins the room with its media engine.
+func SignalingAccept(callback open_im_sdk_callback.Base, operationID string, roomID, customData string) {
+	call(callback, operationID, IMUserContext.Conversation().SignalingAccept, roomID, customData)
+}
+
+// SignalingReject Decline the call the user is invited to.
+func SignalingReject(callback open_im_sdk_callback.Base, operationID string, roomID, customData string) {
+	call(callback, operationID, IMUserContext.Conversation().SignalingReject, roomID, customData)
+}
+
+// SignalingCancel Call off the call of the user before it is answered.
+func SignalingCancel(callback open_im_sdk_callback.Base, operationID string, roomID, customData string) {
+	call(callback, operationID, IMUserContext.Conversation().SignalingCancel, roomID, customData)
+}
+
+// SignalingHungUp End the call in progress, or leave the call of a group.
+func SignalingHungUp(callback open_im_sdk_callback.Base, operationID string, roomID, customData string) {
+	call(callback, operationID, IMUserContext.Conversation().SignalingHungUp, roomID, customData)
+}
+
+// GetCallRecords Get the page of the local call records of the filter following the opaque cursor, the latest calls first.
+func GetCallRecords(callback open_im_sdk_callback.Base, operationID string, filter string, cursor string, count int) {
+	call(callback, operationID, IMUserContext.Conversation().GetCallRecords, filter, cursor, count)
+}
+
+// SendSignalingData Send the payload to the online devices of the user, it is neither stored nor pushed offline.
+func SendSignalingData(callback open_im_sdk_callback.Base, operationID string, toUserID, payload string) {
+	call(callback, operationID, IMUserContext.Conversation().SendSignalingData, toUserID, payload)
+}
+
+func SetSignalingListener(listener open_im_sdk_callback.OnSignalingListener) {
+	listenerCall(IMUserContext.SetSignalingListener, listener)
+}
+
+func (c *Client) SignalingInvite(ctx context.Context, invitation *sdk_struct.SignalingInvitation) (*sdk_struct.SignalingInvitation, error) {
+	return clientCall[*sdk_struct.SignalingInvitation](ctx, c, c.u.Conversation().SignalingInvite, invitation)
+}
+
+func (c *Client) SignalingInviteInGroup(ctx context.Context, invitation *sdk_struct.SignalingInvitation) (*sdk_struct.SignalingInvitation, error) {
+	return clientCall[*sdk_struct.SignalingInvitation](ctx, c, c.u.Conversation().SignalingInviteInGroup, invitation)
+}
+
+func (c *Client) SignalingJoin(ctx context.Context, roomID, customData string) error {
+	return clientExec(ctx, c, c.u.Conversation().SignalingJoin, roomID, customData)
+}
+
+func (c *Client) SignalingGetRoomByGroupID(ctx context.Context, groupID string) (*sdk_struct.SignalingRoom, error) {
+	return clientCall[*sdk_struct.SignalingRoom](ctx, c, c.u.Conversation().SignalingGetRoomByGroupID, groupID)
+}
+
+func (c *Client) SignalingAccept(ctx context.Context, roomID, customData string) error {
+	return clientExec(ctx, c, c.u.Conversation().SignalingAccept, roomID, customData)
+}
+
+func (c *Client) SignalingReject(ctx context.Context, roomID, customData string) error {
+	return clientExec(ctx, c, c.u.Conversation().SignalingReject, roomID, customData)
+}
+
+func (c *Client) SignalingCancel(ctx context.Context, roomID, customData string) error {
+	return clientExec(ctx, c, c.u.Conversation().SignalingCancel, roomID, customData)
+}
+
+func (c *Client) SignalingHungUp(ctx context.Context, roomID, customData string) error {
+	return clientExec(ctx, c, c.u.Conversation().SignalingHungUp, roomID, customData)
+}
+
+func (c *Client) SendSignalingData(ctx context.Context, toUserID, payload string) error {
+	return clientExec(ctx, c, c.u.Conversation().SendSignalingData, toUserID, payload)
+}
+
+func (c *Client) GetCallRecords(ctx context.Context, filter *sdk_params_callback.CallRecordFilter, cursor string, count int) (*sdk_params_callback.GetCallRecordsCallback, error) {
+	return clientCall[*sdk_params_callback.GetCallRecordsCallback](ctx, c, c.u.Conversation().GetCallRecords, filter, cursor, count)
+}
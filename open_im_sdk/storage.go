@@ -0,0 +1,487 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package open_im_sdk
+
+import (
+	"context"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+const (
+	storageCheckDelay    = time.Minute
+	storageCheckInterval = time.Hour
+	// evictMessageBatch is the most messages removed from a conversation at a time, the latest
+	// evictKeepMessages of a conversation are never removed
+	evictMessageBatch = 500
+	evictKeepMessages = 20
+)
+
+// mediaFileName matches the copies of the media of the messages in DataDir, named by the md5 of their source.
+var mediaFileName = regexp.MustCompile(`^[0-9a-f]{32}(\.[^.]*)?$`)
+
+var mediaContentTypes = []int{constant.Picture, constant.Sound, constant.Video, constant.File}
+
+type mediaFile struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// GetStorageUsage Get the bytes taken by the local database and the media files, and the storage quota.
+func GetStorageUsage(callback open_im_sdk_callback.Base, operationID string) {
+	call(callback, operationID, IMUserContext.GetStorageUsage)
+}
+
+func (u *UserContext) GetStorageUsage(ctx context.Context) (*sdk_struct.StorageUsage, error) {
+	usage, _, err := u.storageUsage(ctx)
+	return usage, err
+}
+
+func (u *UserContext) storageUsage(ctx context.Context) (*sdk_struct.StorageUsage, []*mediaFile, error) {
+	config := u.info.Config()
+	usage := &sdk_struct.StorageUsage{Quota: config.StorageQuota}
+	if !config.InMemoryDB {
+		dbFileName, err := db.DBFileName(u.dbDir(), u.info.UserID)
+		if err != nil {
+			return nil, nil, err
+		}
+		var main int64
+		if info, err := os.Stat(dbFileName); err == nil {
+			main = info.Size()
+		}
+		usage.Database = main
+		if info, err := os.Stat(dbFileName + "-wal"); err == nil {
+			usage.Database += info.Size()
+		}
+		if info, err := os.Stat(dbFileName + ".archive"); err == nil {
+			usage.Database += info.Size()
+		}
+		free, total, err := u.db.FreePages(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		if total > 0 {
+			usage.DatabaseFree = main * int64(free) / int64(total)
+		}
+	}
+	files := u.mediaFiles(ctx)
+	for _, file := range files {
+		usage.Media += file.size
+	}
+	usage.MediaFiles = len(files)
+	usage.Total = usage.Database + usage.Media
+	return usage, files, nil
+}
+
+// mediaFiles lists the copies of the media of the messages in DataDir.
+func (u *UserContext) mediaFiles(ctx context.Context) []*mediaFile {
+	config := u.info.Config()
+	entries, err := os.ReadDir(config.DataDir)
+	if err != nil {
+		log.ZWarn(ctx, "read data dir failed", err, "dataDir", config.DataDir)
+		return nil
+	}
+	var files []*mediaFile
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || !mediaFileName.MatchString(entry.Name()) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, &mediaFile{
+			path:    filepath.Join(config.DataDir, entry.Name()),
+			size:    info.Size(),
+			modTime: info.ModTime(),
+		})
+	}
+	return files
+}
+
+// storageQuotaWatcher keeps the media files within MediaCacheLimit and the local storage within StorageQuota
+// while logged in. The media cache is checked again after each download.
+func (u *UserContext) storageQuotaWatcher(ctx context.Context) {
+	if u.info.Config().StorageQuota <= 0 && u.info.Config().MediaCacheLimit <= 0 {
+		return
+	}
+	timer := time.NewTimer(storageCheckDelay)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		case <-u.mediaCacheCh:
+		}
+		if err := u.enforceMediaCacheLimit(ctx); err != nil && ctx.Err() == nil {
+			log.ZWarn(ctx, "enforce media cache limit failed", err)
+		}
+		if u.info.Config().StorageQuota > 0 {
+			if err := u.enforceStorageQuota(ctx); err != nil && ctx.Err() == nil {
+				log.ZWarn(ctx, "enforce storage quota failed", err)
+			}
+		}
+		timer.Reset(storageCheckInterval)
+	}
+}
+
+// enforceStorageQuota removes the oldest media files beyond the quota, then the oldest messages of the muted
+// conversations if StorageEvictMessages is set. The content of the pinned conversations and pinned friends,
+// of the messages being sent and of the messages whose media are pinned, e.g. the favorited ones, is kept.
+func (u *UserContext) enforceStorageQuota(ctx context.Context) error {
+	quota := u.info.Config().StorageQuota
+	usage, files, err := u.storageUsage(ctx)
+	if err != nil {
+		return err
+	}
+	if usage.Total <= quota {
+		return nil
+	}
+	conversations, err := u.db.GetAllConversations(ctx)
+	if err != nil {
+		return err
+	}
+	pinnedFriends, err := u.pinnedFriends(ctx)
+	if err != nil {
+		return err
+	}
+	kept, err := u.keptMedia(ctx, conversations, pinnedFriends)
+	if err != nil {
+		return err
+	}
+	evicted := u.evictMedia(ctx, files, kept, usage.Total, quota, constant.MediaEvictReasonQuota)
+	for _, file := range evicted {
+		usage.Total -= file.Size
+	}
+	log.ZInfo(ctx, "storage quota media evicted", "removed", len(evicted), "total", usage.Total, "quota", quota)
+	if usage.Total <= quota || !u.info.Config().StorageEvictMessages {
+		return nil
+	}
+	return u.evictMessages(ctx, conversations, pinnedFriends)
+}
+
+// evictMessages removes the oldest messages of the muted conversations, the least recently active first,
+// until the database fits the quota once compacted.
+func (u *UserContext) evictMessages(ctx context.Context, conversations []*model_struct.LocalConversation, pinnedFriends map[string]struct{}) error {
+	var muted []*model_struct.LocalConversation
+	for _, conversation := range conversations {
+		if conversation.RecvMsgOpt != constant.ReceiveMessage && !isKeptConversation(conversation, pinnedFriends) {
+			muted = append(muted, conversation)
+		}
+	}
+	sort.Slice(muted, func(i, j int) bool {
+		return muted[i].LatestMsgSendTime < muted[j].LatestMsgSendTime
+	})
+	var evicted int64
+	for fits := false; !fits && len(muted) > 0; {
+		remaining := muted[:0]
+		for _, conversation := range muted {
+			n, err := u.db.DeleteOldestMessages(ctx, conversation.ConversationID, evictMessageBatch, evictKeepMessages)
+			if err != nil {
+				return err
+			}
+			evicted += n
+			if n == evictMessageBatch {
+				remaining = append(remaining, conversation)
+			}
+			usage, _, err := u.storageUsage(ctx)
+			if err != nil {
+				return err
+			}
+			if fits = usage.Total-usage.DatabaseFree <= usage.Quota; fits {
+				break
+			}
+		}
+		muted = remaining
+	}
+	log.ZInfo(ctx, "storage quota messages evicted", "evicted", evicted)
+	if evicted == 0 {
+		return nil
+	}
+	return u.db.Compact(ctx, 0, nil)
+}
+
+func (u *UserContext) pinnedFriends(ctx context.Context) (map[string]struct{}, error) {
+	friends, err := u.db.GetAllFriendList(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pinned := make(map[string]struct{})
+	for _, friend := range friends {
+		if friend.IsPinned {
+			pinned[friend.FriendUserID] = struct{}{}
+		}
+	}
+	return pinned, nil
+}
+
+func isKeptConversation(conversation *model_struct.LocalConversation, pinnedFriends map[string]struct{}) bool {
+	if conversation.IsPinned {
+		return true
+	}
+	_, ok := pinnedFriends[conversation.UserID]
+	return ok && conversation.ConversationType == constant.SingleChatType
+}
+
+// keptMedia returns the names of the media files of the pinned conversations and pinned friends, of the
+// messages being sent and of the messages whose media are pinned.
+func (u *UserContext) keptMedia(ctx context.Context, conversations []*model_struct.LocalConversation, pinnedFriends map[string]struct{}) (map[string]struct{}, error) {
+	kept := make(map[string]struct{})
+	keep := func(msg *model_struct.LocalChatLog) {
+		for _, name := range msgMediaNames(msg) {
+			kept[name] = struct{}{}
+		}
+	}
+	for _, conversation := range conversations {
+		if !isKeptConversation(conversation, pinnedFriends) {
+			continue
+		}
+		for _, contentType := range mediaContentTypes {
+			msgs, err := u.db.SearchAllMessageByContentType(ctx, conversation.ConversationID, contentType)
+			if err != nil {
+				return nil, err
+			}
+			for _, msg := range msgs {
+				keep(msg)
+			}
+		}
+	}
+	sendingMessages, err := u.db.GetAllSendingMessages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, sending := range sendingMessages {
+		msg, err := u.db.GetMessage(ctx, sending.ConversationID, sending.ClientMsgID)
+		if err != nil {
+			continue
+		}
+		keep(msg)
+	}
+	pins, err := u.db.GetAllMediaPins(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, pin := range pins {
+		msg, err := u.db.GetMessage(ctx, pin.ConversationID, pin.ClientMsgID)
+		if err != nil {
+			continue
+		}
+		keep(msg)
+	}
+	return kept, nil
+}
+
+// mediaNames are the names the media file of the path may have in DataDir. The copy is named by the md5 of
+// the source, the source may be a copy itself.
+func mediaNames(path string) []string {
+	return []string{filepath.Base(utils.FileTmpPath(path, "")), filepath.Base(path)}
+}
+
+// msgMediaNames are the names the media files of the message may have in DataDir, the copies of its local
+// paths and the downloads of its urls.
+func msgMediaNames(msg *model_struct.LocalChatLog) []string {
+	var names []string
+	for _, path := range mediaPaths(msg) {
+		names = append(names, mediaNames(path)...)
+	}
+	for _, rawURL := range mediaURLs(msg) {
+		names = append(names, utils.URLFileName(rawURL))
+	}
+	return names
+}
+
+// mediaURLs returns the urls of the media of the message.
+func mediaURLs(msg *model_struct.LocalChatLog) []string {
+	var urls []string
+	switch msg.ContentType {
+	case constant.Picture:
+		var elem sdk_struct.PictureElem
+		if utils.JsonStringToStruct(msg.Content, &elem) == nil {
+			for _, picture := range []*sdk_struct.PictureBaseInfo{elem.SourcePicture, elem.BigPicture, elem.SnapshotPicture} {
+				if picture != nil {
+					urls = append(urls, picture.Url)
+				}
+			}
+		}
+	case constant.Sound:
+		var elem sdk_struct.SoundElem
+		if utils.JsonStringToStruct(msg.Content, &elem) == nil {
+			urls = append(urls, elem.SourceURL)
+		}
+	case constant.Video:
+		var elem sdk_struct.VideoElem
+		if utils.JsonStringToStruct(msg.Content, &elem) == nil {
+			urls = append(urls, elem.VideoURL, elem.SnapshotURL)
+		}
+	case constant.File:
+		var elem sdk_struct.FileElem
+		if utils.JsonStringToStruct(msg.Content, &elem) == nil {
+			urls = append(urls, elem.SourceURL)
+		}
+	}
+	result := urls[:0]
+	for _, rawURL := range urls {
+		if rawURL != "" {
+			result = append(result, rawURL)
+		}
+	}
+	return result
+}
+
+// mediaPaths returns the local paths of the media of the message.
+func mediaPaths(msg *model_struct.LocalChatLog) []string {
+	var paths []string
+	switch msg.ContentType {
+	case constant.Picture:
+		var elem sdk_struct.PictureElem
+		if utils.JsonStringToStruct(msg.Content, &elem) == nil {
+			paths = append(paths, elem.SourcePath)
+		}
+	case constant.Sound:
+		var elem sdk_struct.SoundElem
+		if utils.JsonStringToStruct(msg.Content, &elem) == nil {
+			paths = append(paths, elem.SoundPath)
+		}
+	case constant.Video:
+		var elem sdk_struct.VideoElem
+		if utils.JsonStringToStruct(msg.Content, &elem) == nil {
+			paths = append(paths, elem.VideoPath, elem.SnapshotPath)
+		}
+	case constant.File:
+		var elem sdk_struct.FileElem
+		if utils.JsonStringToStruct(msg.Content, &elem) == nil {
+			paths = append(paths, elem.FilePath)
+		}
+	}
+	result := paths[:0]
+	for _, path := range paths {
+		if path != "" {
+			result = append(result, path)
+		}
+	}
+	return result
+}
+
+// GetConversationStorageInfo Get the number of messages of the conversation in the local database, an estimate
+// of their bytes and the bytes of their media files.
+func GetConversationStorageInfo(callback open_im_sdk_callback.Base, operationID string, conversationID string) {
+	call(callback, operationID, IMUserContext.GetConversationStorageInfo, conversationID)
+}
+
+// PurgeConversationStorage Remove the media files and, if asked, the messages of the conversation from the
+// local storage, the messages stay on the server. The media of the removed messages are removed with them.
+func PurgeConversationStorage(callback open_im_sdk_callback.Base, operationID string, conversationID string, options string) {
+	call(callback, operationID, IMUserContext.PurgeConversationStorage, conversationID, options)
+}
+
+func (u *UserContext) GetConversationStorageInfo(ctx context.Context, conversationID string) (*sdk_struct.ConversationStorageInfo, error) {
+	if _, err := u.db.GetConversation(ctx, conversationID); err != nil {
+		return nil, err
+	}
+	count, size, err := u.db.GetConversationMsgStorage(ctx, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	media, err := u.conversationMedia(ctx, conversationID, 0)
+	if err != nil {
+		return nil, err
+	}
+	info := &sdk_struct.ConversationStorageInfo{
+		ConversationID: conversationID,
+		MessageCount:   count,
+		Database:       size,
+		MediaFiles:     len(media),
+	}
+	for _, file := range media {
+		info.Media += file.size
+	}
+	return info, nil
+}
+
+func (u *UserContext) PurgeConversationStorage(ctx context.Context, conversationID string, options *sdk_struct.PurgeStorageOptions) (*sdk_struct.PurgeStorageResult, error) {
+	if options == nil || (!options.Media && !options.Messages) {
+		return nil, sdkerrs.ErrArgs.WrapMsg("nothing to purge, set media or messages")
+	}
+	if _, err := u.db.GetConversation(ctx, conversationID); err != nil {
+		return nil, err
+	}
+	// the media first, the messages tell which files are theirs
+	media, err := u.conversationMedia(ctx, conversationID, options.BeforeTime)
+	if err != nil {
+		return nil, err
+	}
+	result := &sdk_struct.PurgeStorageResult{}
+	for _, file := range media {
+		if err := os.Remove(file.path); err != nil {
+			log.ZWarn(ctx, "remove media file failed", err, "path", file.path)
+			continue
+		}
+		result.RemovedMediaFiles++
+		result.FreedMedia += file.size
+	}
+	if options.Messages {
+		before := options.BeforeTime
+		if before == 0 {
+			before = math.MaxInt64
+		}
+		if result.DeletedMessages, err = u.db.DeleteMessagesBeforeSendTime(ctx, conversationID, before); err != nil {
+			return nil, err
+		}
+	}
+	log.ZInfo(ctx, "conversation storage purged", "conversationID", conversationID, "options", options, "result", result)
+	return result, nil
+}
+
+// conversationMedia returns the media files in DataDir of the messages of the conversation sent before
+// beforeTime, all of them when 0, by name.
+func (u *UserContext) conversationMedia(ctx context.Context, conversationID string, beforeTime int64) (map[string]*mediaFile, error) {
+	files := make(map[string]*mediaFile)
+	for _, file := range u.mediaFiles(ctx) {
+		files[filepath.Base(file.path)] = file
+	}
+	media := make(map[string]*mediaFile)
+	for _, contentType := range mediaContentTypes {
+		msgs, err := u.db.SearchAllMessageByContentType(ctx, conversationID, contentType)
+		if err != nil {
+			return nil, err
+		}
+		for _, msg := range msgs {
+			if beforeTime > 0 && msg.SendTime >= beforeTime {
+				continue
+			}
+			for _, name := range msgMediaNames(msg) {
+				if file, ok := files[name]; ok {
+					media[name] = file
+				}
+			}
+		}
+	}
+	return media, nil
+}
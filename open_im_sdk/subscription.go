@@ -0,0 +1,307 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package open_im_sdk
+
+import (
+	"encoding/json"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// DropPolicy decides what a subscription does with an event once its buffer is full.
+type DropPolicy int
+
+const (
+	// DropOldest drops the oldest event buffered for the new one
+	DropOldest DropPolicy = iota
+	// DropNewest drops the new event
+	DropNewest
+	// Block makes the sdk wait until the event is read, holding back the events after it
+	Block
+)
+
+// defaultEventBuffer is the buffer of the channels of a subscription when SubscribeOptions.Buffer is 0
+const defaultEventBuffer = 256
+
+// Event is a call of a listener, Name is the callback, e.g. OnRecvNewMessage, and Data its argument in json.
+type Event struct {
+	Name string
+	Data string
+}
+
+// Unmarshal parses the argument of the callback into v.
+func (e Event) Unmarshal(v any) error {
+	return json.Unmarshal([]byte(e.Data), v)
+}
+
+type SubscribeOptions struct {
+	// Buffer is the number of events each channel buffers
+	Buffer int
+	Policy DropPolicy
+}
+
+// Subscription receives the calls of the message, conversation, friend and group listeners on channels, for
+// the Go programs embedding the sdk. The listeners set are called as well. The channels are closed by Close.
+type Subscription struct {
+	Messages      <-chan Event
+	Conversations <-chan Event
+	Friends       <-chan Event
+	Groups        <-chan Event
+
+	u       *UserContext
+	policy  DropPolicy
+	chans   [4]chan Event
+	mu      sync.RWMutex
+	closed  bool
+	once    sync.Once
+	done    chan struct{}
+	dropped atomic.Uint64
+}
+
+const (
+	messageEvents = iota
+	conversationEvents
+	friendEvents
+	groupEvents
+)
+
+// Subscribe Receive the events of the current instance on channels, an alternative to the listeners for Go programs.
+func Subscribe(opts SubscribeOptions) *Subscription {
+	return IMUserContext.Subscribe(opts)
+}
+
+func (u *UserContext) Subscribe(opts SubscribeOptions) *Subscription {
+	if opts.Buffer <= 0 {
+		opts.Buffer = defaultEventBuffer
+	}
+	s := &Subscription{u: u, policy: opts.Policy, done: make(chan struct{})}
+	for i := range s.chans {
+		s.chans[i] = make(chan Event, opts.Buffer)
+	}
+	s.Messages, s.Conversations, s.Friends, s.Groups = s.chans[messageEvents], s.chans[conversationEvents], s.chans[friendEvents], s.chans[groupEvents]
+	u.advancedMsgListeners.add(subscriptionMsgListener{s})
+	u.conversationListeners.add(subscriptionConversationListener{s})
+	u.friendshipListeners.add(subscriptionFriendshipListener{s})
+	u.groupListeners.add(subscriptionGroupListener{s})
+	return s
+}
+
+// Dropped returns the number of events dropped as the buffers were full.
+func (s *Subscription) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Close stops the subscription and closes its channels, the events buffered can still be read.
+func (s *Subscription) Close() {
+	s.once.Do(func() {
+		s.u.advancedMsgListeners.remove(subscriptionMsgListener{s})
+		s.u.conversationListeners.remove(subscriptionConversationListener{s})
+		s.u.friendshipListeners.remove(subscriptionFriendshipListener{s})
+		s.u.groupListeners.remove(subscriptionGroupListener{s})
+		// the pushes blocked hold the read lock, done releases them
+		close(s.done)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.closed = true
+		for _, ch := range s.chans {
+			close(ch)
+		}
+	})
+}
+
+func (s *Subscription) push(kind int, name string, data string) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return
+	}
+	ch, event := s.chans[kind], Event{Name: name, Data: data}
+	switch s.policy {
+	case Block:
+		select {
+		case ch <- event:
+		case <-s.done:
+		}
+		return
+	case DropOldest:
+		for {
+			select {
+			case ch <- event:
+				return
+			default:
+			}
+			select {
+			case <-ch:
+				s.dropped.Add(1)
+			default:
+			}
+		}
+	default:
+		select {
+		case ch <- event:
+		default:
+			s.dropped.Add(1)
+		}
+	}
+}
+
+type subscriptionMsgListener struct{ s *Subscription }
+
+func (l subscriptionMsgListener) OnRecvNewMessage(message string) {
+	l.s.push(messageEvents, "OnRecvNewMessage", message)
+}
+
+func (l subscriptionMsgListener) OnRecvC2CReadReceipt(msgReceiptList string) {
+	l.s.push(messageEvents, "OnRecvC2CReadReceipt", msgReceiptList)
+}
+
+func (l subscriptionMsgListener) OnNewRecvMessageRevoked(messageRevoked string) {
+	l.s.push(messageEvents, "OnNewRecvMessageRevoked", messageRevoked)
+}
+
+func (l subscriptionMsgListener) OnRecvOfflineNewMessage(message string) {
+	l.s.push(messageEvents, "OnRecvOfflineNewMessage", message)
+}
+
+func (l subscriptionMsgListener) OnMsgDeleted(message string) {
+	l.s.push(messageEvents, "OnMsgDeleted", message)
+}
+
+func (l subscriptionMsgListener) OnRecvOnlineOnlyMessage(message string) {
+	l.s.push(messageEvents, "OnRecvOnlineOnlyMessage", message)
+}
+
+func (l subscriptionMsgListener) OnMsgEdited(message string) {
+	l.s.push(messageEvents, "OnMsgEdited", message)
+}
+
+type subscriptionConversationListener struct{ s *Subscription }
+
+func (l subscriptionConversationListener) OnSyncServerStart(reinstalled bool) {
+	l.s.push(conversationEvents, "OnSyncServerStart", strconv.FormatBool(reinstalled))
+}
+
+func (l subscriptionConversationListener) OnSyncServerFinish(reinstalled bool) {
+	l.s.push(conversationEvents, "OnSyncServerFinish", strconv.FormatBool(reinstalled))
+}
+
+func (l subscriptionConversationListener) OnSyncServerProgress(progress int) {
+	l.s.push(conversationEvents, "OnSyncServerProgress", strconv.Itoa(progress))
+}
+
+func (l subscriptionConversationListener) OnSyncServerFailed(reinstalled bool) {
+	l.s.push(conversationEvents, "OnSyncServerFailed", strconv.FormatBool(reinstalled))
+}
+
+func (l subscriptionConversationListener) OnNewConversation(conversationList string) {
+	l.s.push(conversationEvents, "OnNewConversation", conversationList)
+}
+
+func (l subscriptionConversationListener) OnConversationChanged(conversationList string) {
+	l.s.push(conversationEvents, "OnConversationChanged", conversationList)
+}
+
+func (l subscriptionConversationListener) OnTotalUnreadMessageCountChanged(totalUnreadCount int32) {
+	l.s.push(conversationEvents, "OnTotalUnreadMessageCountChanged", strconv.Itoa(int(totalUnreadCount)))
+}
+
+func (l subscriptionConversationListener) OnConversationUserInputStatusChanged(change string) {
+	l.s.push(conversationEvents, "OnConversationUserInputStatusChanged", change)
+}
+
+type subscriptionFriendshipListener struct{ s *Subscription }
+
+func (l subscriptionFriendshipListener) OnFriendApplicationAdded(friendApplication string) {
+	l.s.push(friendEvents, "OnFriendApplicationAdded", friendApplication)
+}
+
+func (l subscriptionFriendshipListener) OnFriendApplicationDeleted(friendApplication string) {
+	l.s.push(friendEvents, "OnFriendApplicationDeleted", friendApplication)
+}
+
+func (l subscriptionFriendshipListener) OnFriendApplicationAccepted(friendApplication string) {
+	l.s.push(friendEvents, "OnFriendApplicationAccepted", friendApplication)
+}
+
+func (l subscriptionFriendshipListener) OnFriendApplicationRejected(friendApplication string) {
+	l.s.push(friendEvents, "OnFriendApplicationRejected", friendApplication)
+}
+
+func (l subscriptionFriendshipListener) OnFriendAdded(friendInfo string) {
+	l.s.push(friendEvents, "OnFriendAdded", friendInfo)
+}
+
+func (l subscriptionFriendshipListener) OnFriendDeleted(friendInfo string) {
+	l.s.push(friendEvents, "OnFriendDeleted", friendInfo)
+}
+
+func (l subscriptionFriendshipListener) OnFriendInfoChanged(friendInfo string) {
+	l.s.push(friendEvents, "OnFriendInfoChanged", friendInfo)
+}
+
+func (l subscriptionFriendshipListener) OnBlackAdded(blackInfo string) {
+	l.s.push(friendEvents, "OnBlackAdded", blackInfo)
+}
+
+func (l subscriptionFriendshipListener) OnBlackDeleted(blackInfo string) {
+	l.s.push(friendEvents, "OnBlackDeleted", blackInfo)
+}
+
+type subscriptionGroupListener struct{ s *Subscription }
+
+func (l subscriptionGroupListener) OnJoinedGroupAdded(groupInfo string) {
+	l.s.push(groupEvents, "OnJoinedGroupAdded", groupInfo)
+}
+
+func (l subscriptionGroupListener) OnJoinedGroupDeleted(groupInfo string) {
+	l.s.push(groupEvents, "OnJoinedGroupDeleted", groupInfo)
+}
+
+func (l subscriptionGroupListener) OnGroupMemberAdded(groupMemberInfo string) {
+	l.s.push(groupEvents, "OnGroupMemberAdded", groupMemberInfo)
+}
+
+func (l subscriptionGroupListener) OnGroupMemberDeleted(groupMemberInfo string) {
+	l.s.push(groupEvents, "OnGroupMemberDeleted", groupMemberInfo)
+}
+
+func (l subscriptionGroupListener) OnGroupApplicationAdded(groupApplication string) {
+	l.s.push(groupEvents, "OnGroupApplicationAdded", groupApplication)
+}
+
+func (l subscriptionGroupListener) OnGroupApplicationDeleted(groupApplication string) {
+	l.s.push(groupEvents, "OnGroupApplicationDeleted", groupApplication)
+}
+
+func (l subscriptionGroupListener) OnGroupInfoChanged(groupInfo string) {
+	l.s.push(groupEvents, "OnGroupInfoChanged", groupInfo)
+}
+
+func (l subscriptionGroupListener) OnGroupDismissed(groupInfo string) {
+	l.s.push(groupEvents, "OnGroupDismissed", groupInfo)
+}
+
+func (l subscriptionGroupListener) OnGroupMemberInfoChanged(groupMemberInfo string) {
+	l.s.push(groupEvents, "OnGroupMemberInfoChanged", groupMemberInfo)
+}
+
+func (l subscriptionGroupListener) OnGroupApplicationAccepted(groupApplication string) {
+	l.s.push(groupEvents, "OnGroupApplicationAccepted", groupApplication)
+}
+
+func (l subscriptionGroupListener) OnGroupApplicationRejected(groupApplication string) {
+	l.s.push(groupEvents, "OnGroupApplicationRejected", groupApplication)
+}
@@ -15,8 +15,10 @@
 package open_im_sdk
 
 import (
-	"github.com/openimsdk/openim-sdk-core/v3/internal/third/file"
+	"context"
+
 	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
 )
 
@@ -24,6 +26,10 @@ func UpdateFcmToken(callback open_im_sdk_callback.Base, operationID, fcmToken st
 	call(callback, operationID, IMUserContext.Third().UpdateFcmToken, fcmToken, expireTime)
 }
 
+func SetOfflinePushToken(callback open_im_sdk_callback.Base, operationID string, platform int32, token, provider string) {
+	call(callback, operationID, IMUserContext.Third().SetOfflinePushToken, platform, token, provider)
+}
+
 func SetAppBadge(callback open_im_sdk_callback.Base, operationID string, appUnreadCount int32) {
 	call(callback, operationID, IMUserContext.Third().SetAppBadge, appUnreadCount)
 }
@@ -32,6 +38,24 @@ func UploadLogs(callback open_im_sdk_callback.Base, operationID string, line int
 	call(callback, operationID, IMUserContext.Third().UploadLogs, line, ex, progress)
 }
 
+// UploadFilteredLogs Upload the log entries within a time range, of a least level and of some modules,
+// compressed. An upload interrupted resumes when the same filter is uploaded again.
+func UploadFilteredLogs(callback open_im_sdk_callback.Base, operationID string, filter string, ex string, progress open_im_sdk_callback.UploadLogProgress) {
+	call(callback, operationID, IMUserContext.Third().UploadFilteredLogs, filter, ex, progress)
+}
+
+// SetLogLevel Set the log level of a module: sync, db, ws or conversation, or the global level when module is
+// empty. A negative level removes the level of the module, which then follows the global one. Can be called
+// before login.
+func SetLogLevel(callback open_im_sdk_callback.Base, operationID string, module string, level int) {
+	call(callback, operationID, IMUserContext.SetLogLevel, module, level)
+}
+
+// GetLogLevels Get the global log level, under the empty key, and the levels set for the modules.
+func GetLogLevels(callback open_im_sdk_callback.Base, operationID string) {
+	call(callback, operationID, IMUserContext.GetLogLevels)
+}
+
 func Logs(callback open_im_sdk_callback.Base, operationID string, logLevel int, file string, line int, msgs string, err string, keyAndValue string) {
 	if IMUserContext == nil || IMUserContext.Third() == nil {
 		callback.OnError(sdkerrs.SdkInternalError, "sdk not init")
@@ -40,6 +64,14 @@ func Logs(callback open_im_sdk_callback.Base, operationID string, logLevel int,
 	call(callback, operationID, IMUserContext.Third().Log, logLevel, file, line, msgs, err, keyAndValue)
 }
 
-func UploadFile(callback open_im_sdk_callback.Base, operationID string, req string, progress open_im_sdk_callback.UploadFileCallback) {
-	call(callback, operationID, IMUserContext.File().UploadFile, req, file.UploadFileCallback(progress))
+func (u *UserContext) SetLogLevel(ctx context.Context, module string, level int) error {
+	if err := log.SetLevel(module, level); err != nil {
+		return err
+	}
+	log.ZInfo(ctx, "log level changed", "module", module, "level", level)
+	return nil
+}
+
+func (u *UserContext) GetLogLevels(_ context.Context) (map[string]int, error) {
+	return log.Levels(), nil
 }
@@ -0,0 +1,96 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package open_im_sdk
+
+import (
+	"context"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/api"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/protocol/auth"
+)
+
+const (
+	defaultTokenRefreshAdvance = 300
+	parseTokenRetryInterval    = time.Minute
+)
+
+// RefreshToken Replace the token of the logged in user without logging in again.
+// Requests and reconnections use the new token from now on.
+func RefreshToken(callback open_im_sdk_callback.Base, operationID string, token string) {
+	call(callback, operationID, IMUserContext.RefreshToken, token)
+}
+
+func (u *UserContext) RefreshToken(ctx context.Context, token string) error {
+	if token == "" {
+		return sdkerrs.ErrArgs.WrapMsg("token is empty")
+	}
+	resp, err := api.ParseToken.Invoke(ctx, &auth.ParseTokenReq{Token: token})
+	if err != nil {
+		return err
+	}
+	if resp.UserID != u.info.UserID || resp.PlatformID != u.info.Config().PlatformID {
+		return sdkerrs.ErrArgs.WrapMsg("token does not belong to the login user and platform")
+	}
+	u.info.SetToken(token)
+	select {
+	case u.tokenRefreshedCh <- struct{}{}:
+	default:
+	}
+	log.ZInfo(ctx, "token refreshed", "expireTimeSeconds", resp.ExpireTimeSeconds)
+	return nil
+}
+
+// tokenExpireWatcher calls OnTokenWillExpire ahead of the token expiry, and starts over with the new token
+// once RefreshToken is called.
+func (u *UserContext) tokenExpireWatcher(ctx context.Context) {
+	advance := time.Duration(u.info.Config().TokenRefreshAdvance) * time.Second
+	if advance <= 0 {
+		advance = defaultTokenRefreshAdvance * time.Second
+	}
+	for {
+		var wait time.Duration
+		resp, err := api.ParseToken.Invoke(ctx, &auth.ParseTokenReq{Token: u.info.Token()})
+		if err != nil {
+			log.ZWarn(ctx, "parse token expire time failed", err)
+			wait = parseTokenRetryInterval
+		} else {
+			wait = time.Until(time.Unix(resp.ExpireTimeSeconds, 0).Add(-advance))
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-u.tokenRefreshedCh:
+			timer.Stop()
+			continue
+		case <-timer.C:
+		}
+		if err != nil {
+			continue
+		}
+		log.ZInfo(ctx, "token will expire", "expireTimeSeconds", resp.ExpireTimeSeconds)
+		u.TokenListener().OnTokenWillExpire(resp.ExpireTimeSeconds)
+		select {
+		case <-ctx.Done():
+			return
+		case <-u.tokenRefreshedCh:
+		}
+	}
+}
@@ -0,0 +1,334 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package open_im_sdk
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/cliconf"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/dataexport"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdk_params_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+	"github.com/openimsdk/protocol/group"
+	"github.com/openimsdk/protocol/relation"
+	"github.com/openimsdk/protocol/sdkws"
+	"github.com/openimsdk/tools/mcontext"
+)
+
+// Client is the typed api of an sdk instance for the go programs embedding the sdk, the parameters and the
+// results are the structs the json api marshals. The calls go through the same path as the json api, the
+// login checks, the logs and the error codes included. The operationID of a call is the one of its context,
+// a new one when it has none, and canceling the context cancels the calls CancelOperation can cancel.
+// The events are received with Subscribe.
+type Client struct {
+	u *UserContext
+}
+
+// NewClient returns the typed api of the current instance, see SDKInstanceClient for another one.
+func NewClient() *Client {
+	return IMUserContext.Client()
+}
+
+func (u *UserContext) Client() *Client {
+	return &Client{u: u}
+}
+
+func clientCall[R any](ctx context.Context, c *Client, fn any, args ...any) (R, error) {
+	var zero R
+	operationID := mcontext.GetOperationID(ctx)
+	if operationID == "" {
+		operationID = utils.OperationIDGenerator()
+	}
+	stop := context.AfterFunc(ctx, func() { cancelOperation(operationID) })
+	defer stop()
+	res, err := call_(c.u, operationID, fn, args...)
+	if err != nil {
+		return zero, err
+	}
+	r, _ := res.(R)
+	return r, nil
+}
+
+func clientExec(ctx context.Context, c *Client, fn any, args ...any) error {
+	_, err := clientCall[any](ctx, c, fn, args...)
+	return err
+}
+
+func (c *Client) Login(ctx context.Context, userID, token string) error {
+	return clientExec(ctx, c, c.u.Login, userID, token)
+}
+
+func (c *Client) Logout(ctx context.Context) error {
+	return clientExec(ctx, c, c.u.Logout)
+}
+
+func (c *Client) LogoutWithMode(ctx context.Context, mode string, progress open_im_sdk_callback.LogoutWipeProgress) error {
+	return clientExec(ctx, c, c.u.LogoutWithMode, mode, progress)
+}
+
+func (c *Client) ExportAllPersonalData(ctx context.Context, path string, progress open_im_sdk_callback.ExportPersonalDataProgress) (*dataexport.Manifest, error) {
+	return clientCall[*dataexport.Manifest](ctx, c, c.u.ExportAllPersonalData, path, progress)
+}
+
+func (c *Client) SetLocalizedTemplates(ctx context.Context, locale string, templates map[string]string) error {
+	return clientExec(ctx, c, c.u.SetLocalizedTemplates, locale, templates)
+}
+
+func (c *Client) EnableE2EE(ctx context.Context) error {
+	return clientExec(ctx, c, c.u.E2EE().Enable)
+}
+
+func (c *Client) IsE2EEEnabled(ctx context.Context) (bool, error) {
+	return clientCall[bool](ctx, c, c.u.E2EE().IsEnabled)
+}
+
+func (c *Client) ResetE2EESession(ctx context.Context, userID string) error {
+	return clientExec(ctx, c, c.u.E2EE().ResetSession, userID)
+}
+
+func (c *Client) BackupE2EEKeys(ctx context.Context, passphrase string) error {
+	return clientExec(ctx, c, c.u.E2EE().Backup, passphrase)
+}
+
+func (c *Client) RestoreE2EEKeys(ctx context.Context, passphrase string) (int, error) {
+	return clientCall[int](ctx, c, c.u.RestoreE2EEKeys, passphrase)
+}
+
+func (c *Client) GetE2EEDevices(ctx context.Context, userID string) ([]*sdk_struct.E2EEDevice, error) {
+	return clientCall[[]*sdk_struct.E2EEDevice](ctx, c, c.u.E2EE().GetDevices, userID)
+}
+
+func (c *Client) GetSelfE2EEDevice(ctx context.Context) (*sdk_struct.E2EEDevice, error) {
+	return clientCall[*sdk_struct.E2EEDevice](ctx, c, c.u.E2EE().GetSelfDevice)
+}
+
+func (c *Client) SetE2EEDeviceVerified(ctx context.Context, userID, deviceID string, verified bool) error {
+	return clientExec(ctx, c, c.u.E2EE().SetDeviceVerified, userID, deviceID, verified)
+}
+
+func (c *Client) VerifyE2EESafetyNumber(ctx context.Context, userID, safetyNumber string) (bool, error) {
+	return clientCall[bool](ctx, c, c.u.E2EE().VerifySafetyNumber, userID, safetyNumber)
+}
+
+func (c *Client) SetSensitiveWordList(ctx context.Context, name string, words []string) error {
+	return clientExec(ctx, c, c.u.Conversation().SetSensitiveWordList, name, words)
+}
+
+func (c *Client) FindSensitiveWords(ctx context.Context, text string) ([]string, error) {
+	return clientCall[[]string](ctx, c, c.u.Conversation().FindSensitiveWords, text)
+}
+
+func (c *Client) OpenViewOnceMessage(ctx context.Context, conversationID, clientMsgID string) (*sdk_struct.MsgStruct, error) {
+	return clientCall[*sdk_struct.MsgStruct](ctx, c, c.u.Conversation().OpenViewOnceMessage, conversationID, clientMsgID)
+}
+
+func (c *Client) RequestCustomerServiceAgent(ctx context.Context, queue, ex string) (*model_struct.LocalCustomerServiceSession, error) {
+	return clientCall[*model_struct.LocalCustomerServiceSession](ctx, c, c.u.CustomerService().RequestAgent, queue, ex)
+}
+
+func (c *Client) TransferCustomerServiceSession(ctx context.Context, sessionID, toAgentUserID, toQueue, reason string) (*model_struct.LocalCustomerServiceSession, error) {
+	return clientCall[*model_struct.LocalCustomerServiceSession](ctx, c, c.u.CustomerService().TransferSession, sessionID, toAgentUserID, toQueue, reason)
+}
+
+func (c *Client) CloseCustomerServiceSession(ctx context.Context, sessionID, reason string) (*model_struct.LocalCustomerServiceSession, error) {
+	return clientCall[*model_struct.LocalCustomerServiceSession](ctx, c, c.u.CustomerService().CloseSession, sessionID, reason)
+}
+
+func (c *Client) RateCustomerServiceSession(ctx context.Context, sessionID string, rating int32, comment string) (*model_struct.LocalCustomerServiceSession, error) {
+	return clientCall[*model_struct.LocalCustomerServiceSession](ctx, c, c.u.CustomerService().RateSession, sessionID, rating, comment)
+}
+
+func (c *Client) GetCustomerServiceSession(ctx context.Context, sessionID string) (*model_struct.LocalCustomerServiceSession, error) {
+	return clientCall[*model_struct.LocalCustomerServiceSession](ctx, c, c.u.CustomerService().GetSession, sessionID)
+}
+
+func (c *Client) GetCustomerServiceSessions(ctx context.Context, activeOnly bool) ([]*model_struct.LocalCustomerServiceSession, error) {
+	return clientCall[[]*model_struct.LocalCustomerServiceSession](ctx, c, c.u.CustomerService().GetSessions, activeOnly)
+}
+
+func (c *Client) GetLoginStatus(ctx context.Context) int {
+	return c.u.GetLoginStatus(ctx)
+}
+
+func (c *Client) GetAllConversationList(ctx context.Context) ([]*model_struct.LocalConversation, error) {
+	return clientCall[[]*model_struct.LocalConversation](ctx, c, c.u.Conversation().GetAllConversationList)
+}
+
+func (c *Client) GetConversationListSplit(ctx context.Context, offset, count int) ([]*model_struct.LocalConversation, error) {
+	return clientCall[[]*model_struct.LocalConversation](ctx, c, c.u.Conversation().GetConversationListSplit, offset, count)
+}
+
+func (c *Client) GetOneConversation(ctx context.Context, sessionType int32, sourceID string) (*model_struct.LocalConversation, error) {
+	return clientCall[*model_struct.LocalConversation](ctx, c, c.u.Conversation().GetOneConversation, sessionType, sourceID)
+}
+
+func (c *Client) GetConversations(ctx context.Context, conversationIDs []string) (*sdk_params_callback.GetConversationsCallback, error) {
+	return clientCall[*sdk_params_callback.GetConversationsCallback](ctx, c, c.u.Conversation().GetConversations, conversationIDs)
+}
+
+func (c *Client) GetTotalUnreadMsgCount(ctx context.Context) (int32, error) {
+	return clientCall[int32](ctx, c, c.u.Conversation().GetTotalUnreadMsgCount)
+}
+
+func (c *Client) MarkConversationMessageAsRead(ctx context.Context, conversationID string) error {
+	return clientExec(ctx, c, c.u.Conversation().MarkConversationMessageAsRead, conversationID)
+}
+
+func (c *Client) CreateTextMessage(ctx context.Context, text string) (*sdk_struct.MsgStruct, error) {
+	return clientCall[*sdk_struct.MsgStruct](ctx, c, c.u.Conversation().CreateTextMessage, text)
+}
+
+// SendMessage sends the message and waits for the result, the sent message.
+func (c *Client) SendMessage(ctx context.Context, message *sdk_struct.MsgStruct, recvID, groupID string,
+	offlinePushInfo *sdkws.OfflinePushInfo, isOnlineOnly bool) (*sdk_struct.MsgStruct, error) {
+	operationID := mcontext.GetOperationID(ctx)
+	if operationID == "" {
+		operationID = utils.OperationIDGenerator()
+	}
+	callback := &sendResult{ch: make(chan sendResultValue, 1)}
+	messageCall_(c.u, callback, operationID, c.u.Conversation().SendMessage, message, recvID, groupID, offlinePushInfo, isOnlineOnly)
+	select {
+	case res := <-callback.ch:
+		return res.message, res.err
+	case <-ctx.Done():
+		return nil, sdkerrs.ErrCanceled.WrapMsg(ctx.Err().Error(), "clientMsgID", message.ClientMsgID)
+	}
+}
+
+func (c *Client) GetAdvancedHistoryMessageList(ctx context.Context, req sdk_params_callback.GetAdvancedHistoryMessageListParams) (*sdk_params_callback.GetAdvancedHistoryMessageListCallback, error) {
+	return clientCall[*sdk_params_callback.GetAdvancedHistoryMessageListCallback](ctx, c, c.u.Conversation().GetAdvancedHistoryMessageList, req)
+}
+
+func (c *Client) GetMessages(ctx context.Context, req []*sdk_params_callback.ConversationArgs) (*sdk_params_callback.GetMessagesCallback, error) {
+	return clientCall[*sdk_params_callback.GetMessagesCallback](ctx, c, c.u.Conversation().GetMessages, req)
+}
+
+func (c *Client) FindMessageList(ctx context.Context, req []*sdk_params_callback.ConversationArgs) (*sdk_params_callback.FindMessageListCallback, error) {
+	return clientCall[*sdk_params_callback.FindMessageListCallback](ctx, c, c.u.Conversation().FindMessageList, req)
+}
+
+func (c *Client) SearchLocalMessages(ctx context.Context, req *sdk_params_callback.SearchLocalMessagesParams) (*sdk_params_callback.SearchLocalMessagesCallback, error) {
+	return clientCall[*sdk_params_callback.SearchLocalMessagesCallback](ctx, c, c.u.Conversation().SearchLocalMessages, req)
+}
+
+func (c *Client) RevokeMessage(ctx context.Context, conversationID, clientMsgID string) error {
+	return clientExec(ctx, c, c.u.Conversation().RevokeMessage, conversationID, clientMsgID)
+}
+
+func (c *Client) SubmitInteractiveAction(ctx context.Context, conversationID, clientMsgID, actionID, payload string) error {
+	return clientExec(ctx, c, c.u.Conversation().SubmitInteractiveAction, conversationID, clientMsgID, actionID, payload)
+}
+
+func (c *Client) GetMessageTranslation(ctx context.Context, conversationID, clientMsgID, lang string) (*model_struct.LocalMessageTranslation, error) {
+	return clientCall[*model_struct.LocalMessageTranslation](ctx, c, c.u.Conversation().GetMessageTranslation, conversationID, clientMsgID, lang)
+}
+
+func (c *Client) DeleteMessage(ctx context.Context, conversationID, clientMsgID string) error {
+	return clientExec(ctx, c, c.u.Conversation().DeleteMessage, conversationID, clientMsgID)
+}
+
+func (c *Client) GetUsersInfo(ctx context.Context, userIDs []string) ([]*sdk_struct.PublicUser, error) {
+	return clientCall[[]*sdk_struct.PublicUser](ctx, c, c.u.User().GetUsersInfo, userIDs)
+}
+
+func (c *Client) GetSelfUserInfo(ctx context.Context) (*model_struct.LocalUser, error) {
+	return clientCall[*model_struct.LocalUser](ctx, c, c.u.User().GetSelfUserInfo)
+}
+
+func (c *Client) SetSelfInfo(ctx context.Context, userInfo *sdkws.UserInfoWithEx) error {
+	return clientExec(ctx, c, c.u.User().SetSelfInfo, userInfo)
+}
+
+func (c *Client) GetClientConfig(ctx context.Context) (*cliconf.ClientConfig, error) {
+	return clientCall[*cliconf.ClientConfig](ctx, c, c.u.User().GetClientConfig)
+}
+
+func (c *Client) GetAuditLogs(ctx context.Context, params *sdk_params_callback.GetAuditLogsParams) ([]*model_struct.LocalAuditLog, error) {
+	return clientCall[[]*model_struct.LocalAuditLog](ctx, c, c.u.GetAuditLogs, params)
+}
+
+func (c *Client) GetFriendList(ctx context.Context, filterBlack bool) ([]*model_struct.LocalFriend, error) {
+	return clientCall[[]*model_struct.LocalFriend](ctx, c, c.u.Relation().GetFriendList, filterBlack)
+}
+
+func (c *Client) GetSpecifiedFriendsInfo(ctx context.Context, friendUserIDs []string, filterBlack bool) ([]*model_struct.LocalFriend, error) {
+	return clientCall[[]*model_struct.LocalFriend](ctx, c, c.u.Relation().GetSpecifiedFriendsInfo, friendUserIDs, filterBlack)
+}
+
+func (c *Client) AddFriend(ctx context.Context, req *relation.ApplyToAddFriendReq) error {
+	return clientExec(ctx, c, c.u.Relation().AddFriend, req)
+}
+
+func (c *Client) DeleteFriend(ctx context.Context, friendUserID string) error {
+	return clientExec(ctx, c, c.u.Relation().DeleteFriend, friendUserID)
+}
+
+func (c *Client) CreateGroup(ctx context.Context, req *group.CreateGroupReq) (*sdkws.GroupInfo, error) {
+	return clientCall[*sdkws.GroupInfo](ctx, c, c.u.Group().CreateGroup, req)
+}
+
+func (c *Client) JoinGroup(ctx context.Context, groupID, reqMsg string, joinSource int32, ex string) error {
+	return clientExec(ctx, c, c.u.Group().JoinGroup, groupID, reqMsg, joinSource, ex)
+}
+
+func (c *Client) QuitGroup(ctx context.Context, groupID string) error {
+	return clientExec(ctx, c, c.u.Group().QuitGroup, groupID)
+}
+
+func (c *Client) GetJoinedGroupList(ctx context.Context) ([]*model_struct.LocalGroup, error) {
+	return clientCall[[]*model_struct.LocalGroup](ctx, c, c.u.Group().GetJoinedGroupList)
+}
+
+func (c *Client) GetGroupsInfo(ctx context.Context, groupIDs []string) (*sdk_params_callback.GetGroupsInfoCallback, error) {
+	return clientCall[*sdk_params_callback.GetGroupsInfoCallback](ctx, c, c.u.Group().GetGroupsInfo, groupIDs)
+}
+
+func (c *Client) GetGroupMemberList(ctx context.Context, groupID string, filter, offset, count int32) ([]*model_struct.LocalGroupMember, error) {
+	return clientCall[[]*model_struct.LocalGroupMember](ctx, c, c.u.Group().GetGroupMemberList, groupID, filter, offset, count)
+}
+
+func (c *Client) GetSpecifiedGroupMembersInfo(ctx context.Context, groupID string, userIDs []string) ([]*model_struct.LocalGroupMember, error) {
+	return clientCall[[]*model_struct.LocalGroupMember](ctx, c, c.u.Group().GetSpecifiedGroupMembersInfo, groupID, userIDs)
+}
+
+type sendResultValue struct {
+	message *sdk_struct.MsgStruct
+	err     error
+}
+
+// sendResult is the callback of a message sent with the Client, it hands the result to the waiting call.
+type sendResult struct {
+	ch chan sendResultValue
+}
+
+func (s *sendResult) OnError(errCode int32, errMsg string) {
+	s.ch <- sendResultValue{err: sdkerrs.New(int(errCode), errMsg, "")}
+}
+
+func (s *sendResult) OnSuccess(data string) {
+	var message sdk_struct.MsgStruct
+	if err := json.Unmarshal([]byte(data), &message); err != nil {
+		s.ch <- sendResultValue{err: sdkerrs.ErrSdkInternal.WrapMsg(err.Error())}
+		return
+	}
+	s.ch <- sendResultValue{message: &message}
+}
+
+func (s *sendResult) OnProgress(progress int) {}
@@ -35,3 +35,35 @@ func GetSelfUserInfo(callback open_im_sdk_callback.Base, operationID string) {
 func GetUserClientConfig(callback open_im_sdk_callback.Base, operationID string) {
 	call(callback, operationID, IMUserContext.User().GetUserClientConfig)
 }
+
+// GetClientConfig Get the client config defined by the server, with its flags parsed, the last config known when
+// the server can't be reached.
+func GetClientConfig(callback open_im_sdk_callback.Base, operationID string) {
+	call(callback, operationID, IMUserContext.User().GetClientConfig)
+}
+
+// GetPrivacySettings obtains the login user's privacy settings.
+func GetPrivacySettings(callback open_im_sdk_callback.Base, operationID string) {
+	call(callback, operationID, IMUserContext.User().GetPrivacySettings)
+}
+
+// SetPrivacySettings sets the login user's privacy settings and syncs them to the other devices. The add friend
+// permission and the visibilities are published in the user ex field for the other users to respect.
+func SetPrivacySettings(callback open_im_sdk_callback.Base, operationID string, settings string) {
+	call(callback, operationID, IMUserContext.User().SetPrivacySettings, settings)
+}
+
+// RegisterUserExProfileSchema registers the typed fields stored in the user ex field.
+func RegisterUserExProfileSchema(callback open_im_sdk_callback.Base, operationID string, fields string) {
+	call(callback, operationID, IMUserContext.User().RegisterExProfileSchema, fields)
+}
+
+// GetUsersExProfile obtains the typed ex profile of the specified users.
+func GetUsersExProfile(callback open_im_sdk_callback.Base, operationID string, userIDs string) {
+	call(callback, operationID, IMUserContext.User().GetUsersExProfile, userIDs)
+}
+
+// UpdateSelfExProfile partially updates the user's own ex profile.
+func UpdateSelfExProfile(callback open_im_sdk_callback.Base, operationID string, fields string) {
+	call(callback, operationID, IMUserContext.User().UpdateSelfExProfile, fields)
+}
@@ -18,9 +18,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"runtime/debug"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 	"unsafe"
 
@@ -30,22 +30,35 @@ import (
 	"github.com/openimsdk/openim-sdk-core/v3/internal/relation"
 
 	conv "github.com/openimsdk/openim-sdk-core/v3/internal/conversation_msg"
+	"github.com/openimsdk/openim-sdk-core/v3/internal/customerservice"
+	"github.com/openimsdk/openim-sdk-core/v3/internal/e2ee"
 	"github.com/openimsdk/openim-sdk-core/v3/internal/group"
 	"github.com/openimsdk/openim-sdk-core/v3/internal/interaction"
+	"github.com/openimsdk/openim-sdk-core/v3/internal/qrlogin"
 	"github.com/openimsdk/openim-sdk-core/v3/internal/third"
 	"github.com/openimsdk/openim-sdk-core/v3/internal/user"
 	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/audit"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/cache"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/ccontext"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/common"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/crash"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/db"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/db_interface"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/errreport"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/fault"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/i18n"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/network"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/profiling"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/recorder"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/telemetry"
 	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
 	"github.com/openimsdk/protocol/push"
 	"github.com/openimsdk/protocol/sdkws"
-	"github.com/openimsdk/tools/log"
 	"github.com/openimsdk/tools/utils/jsonutil"
 )
 
@@ -82,24 +95,83 @@ func (u *UserContext) initResources() {
 	u.conversationEventQueue = make(chan common.Cmd2Value, 1000)
 	u.msgSyncerCh = make(chan common.Cmd2Value, 1000)
 	u.loginMgrCh = make(chan common.Cmd2Value, 1)
+	u.tokenRefreshedCh = make(chan struct{}, 1)
+	u.mediaCacheCh = make(chan struct{}, 1)
 
 	u.longConnMgr = interaction.NewLongConnMgr(u.ctx, u.userOnlineStatusChange, u.msgSyncerCh, u.loginMgrCh)
 	u.ctx = ccontext.WithApiErrCode(u.ctx, &apiErrCallback{loginMgrCh: u.loginMgrCh, listener: u.ConnListener})
 	u.setLoginStatus(LogoutStatus)
+	u.isGuest.Store(false)
 	u.user = user.NewUser(u.conversationEventQueue)
+	u.user.SetOnlineStatusGetter(u.longConnMgr.GetUserOnlinePlatformIDs)
 	u.file = file.NewFile()
+	u.download = noDownloader{}
 	u.relation = relation.NewRelation(u.conversationEventQueue, u.user)
 	u.group = group.NewGroup(u.conversationEventQueue)
+	u.relation.SetGroupMateChecker(u.group.IsGroupMate)
 	u.third = third.NewThird(u.file)
+	u.longConnMgr.OnConnected(u.third.OnConnected)
+	u.qrLogin = qrlogin.NewQRLogin()
+	u.e2ee = e2ee.NewE2EE()
+	u.customerService = customerservice.NewCustomerService()
 	u.msgSyncer = interaction.NewMsgSyncer(u.conversationEventQueue, u.msgSyncerCh, u.longConnMgr)
 	u.conversation = conv.NewConversation(u.longConnMgr, u.msgSyncerCh, u.conversationEventQueue,
 		u.relation, u.group, u.user, u.file)
+	u.conversation.SetE2EE(u.e2ee)
+	u.conversation.SetCustomerService(u.customerService)
+	u.initModules()
+	u.setBackends()
 	u.setListener(ctx)
 }
 
+// noLoginRequiredFuncs are the functions that can be called before login.
+var noLoginRequiredFuncs = map[string]struct{}{
+	"Login-fm":                 {},
+	"Log-fm":                   {},
+	"CreateLoginQRCode-fm":     {},
+	"CancelLoginQRCode-fm":     {},
+	"GuestLogin-fm":            {},
+	"SetProxy-fm":              {},
+	"SetBandwidthLimit-fm":     {},
+	"GetBandwidthLimit-fm":     {},
+	"SetNetworkClass-fm":       {},
+	"AllowMeteredTransfer-fm":  {},
+	"TrimMemory-fm":            {},
+	"SetDatabaseKey-fm":        {},
+	"SetDraftEncryptionKey-fm": {},
+	"SwitchAccount-fm":         {},
+	"RestoreLocalData-fm":      {},
+	"CollectDiagnostics-fm":    {},
+	"SetLogLevel-fm":           {},
+	"UpdateConfig-fm":          {},
+	"ValidateConfig-fm":        {},
+	"GetEffectiveConfig-fm":    {},
+	"GetLogLevels-fm":          {},
+	"StartProfiling-fm":        {},
+	"StopProfiling-fm":         {},
+	"StartRecording-fm":        {},
+	"StopRecording-fm":         {},
+	"ReplayRecording-fm":       {},
+	"GetErrorInfo-fm":          {},
+	"CancelOperation-fm":       {},
+}
+
+// guestDeniedFuncs are the functions a guest login can not call.
+var guestDeniedFuncs = map[string]struct{}{
+	"AddFriend-fm":           {},
+	"AddBlack-fm":            {},
+	"CreateGroup-fm":         {},
+	"InviteUserToGroup-fm":   {},
+	"SetGroupInfo-fm":        {},
+	"TransferGroupOwner-fm":  {},
+	"SetSelfInfo-fm":         {},
+	"UpdateSelfExProfile-fm": {},
+	"SetPrivacySettings-fm":  {},
+}
+
 // CheckResourceLoad checks the SDK is resource load status.
 func CheckResourceLoad(userContext *UserContext, funcName string) error {
-	if userContext.Info().IMConfig == nil {
+	if userContext.Info().Config() == nil {
 		return sdkerrs.ErrSDKNotInit.WrapMsg(funcName)
 	}
 
@@ -109,7 +181,7 @@ func CheckResourceLoad(userContext *UserContext, funcName string) error {
 
 	parts := strings.Split(funcName, ".")
 	shortFuncName := parts[len(parts)-1]
-	if shortFuncName == "Login-fm" || shortFuncName == "Log-fm" {
+	if _, ok := noLoginRequiredFuncs[shortFuncName]; ok {
 		return nil
 	}
 
@@ -117,6 +189,10 @@ func CheckResourceLoad(userContext *UserContext, funcName string) error {
 		return sdkerrs.ErrSDKNotLogin.WrapMsg(funcName)
 	}
 
+	if _, ok := guestDeniedFuncs[shortFuncName]; ok && userContext.isGuest.Load() {
+		return sdkerrs.ErrGuestNotAllowed.WrapMsg(funcName)
+	}
+
 	return nil
 }
 
@@ -126,28 +202,62 @@ type UserContext struct {
 	conversation *conv.Conversation
 	user         *user.User
 	file         *file.File
+	// download is the download manager of the files module, which does nothing when the module is left out
+	download downloader
 
 	db          db_interface.DataBase
+	dbKey       string // key of the encryption of the database, empty for a plaintext database
+	draftKey    string // key of the encryption of the drafts, empty to store them in plain
 	longConnMgr *interaction.LongConnMgr
 	msgSyncer   *interaction.MsgSyncer
 	third       *third.Third
-	token       string
-	loginUserID string
+	qrLogin     *qrlogin.QRLogin
+	e2ee        *e2ee.E2EE
+	// moments and organization are the modules of the same names, nil when they are left out
+	moments         conv.NotificationModule
+	organization    conv.SyncedModule
+	customerService *customerservice.CustomerService
+	token           string
+	loginUserID     string
 
 	justOnceFlag bool
 
 	w           sync.Mutex
 	loginStatus int
-
-	connListener         open_im_sdk_callback.OnConnListener
-	groupListener        open_im_sdk_callback.OnGroupListener
-	friendshipListener   open_im_sdk_callback.OnFriendshipListener
-	conversationListener open_im_sdk_callback.OnConversationListener
-	advancedMsgListener  open_im_sdk_callback.OnAdvancedMsgListener
-	userListener         open_im_sdk_callback.OnUserListener
-	signalingListener    open_im_sdk_callback.OnSignalingListener
-	businessListener     open_im_sdk_callback.OnCustomBusinessListener
-	msgKvListener        open_im_sdk_callback.OnMessageKvInfoListener
+	isGuest     atomic.Bool
+
+	connListener            open_im_sdk_callback.OnConnListener
+	groupListener           open_im_sdk_callback.OnGroupListener
+	friendshipListener      open_im_sdk_callback.OnFriendshipListener
+	conversationListener    open_im_sdk_callback.OnConversationListener
+	advancedMsgListener     open_im_sdk_callback.OnAdvancedMsgListener
+	userListener            open_im_sdk_callback.OnUserListener
+	signalingListener       open_im_sdk_callback.OnSignalingListener
+	businessListener        open_im_sdk_callback.OnCustomBusinessListener
+	msgKvListener           open_im_sdk_callback.OnMessageKvInfoListener
+	qrLoginListener         open_im_sdk_callback.OnQRLoginListener
+	tokenListener           open_im_sdk_callback.OnTokenListener
+	connStateListener       open_im_sdk_callback.OnConnStateListener
+	qualityListener         open_im_sdk_callback.OnNetworkQualityListener
+	lifecycleListener       open_im_sdk_callback.OnAppLifecycleListener
+	syncProgressListener    open_im_sdk_callback.OnSyncProgressListener
+	conflictListener        open_im_sdk_callback.OnSyncConflictListener
+	conflictResolver        open_im_sdk_callback.ConflictResolver
+	dbMigrationListener     open_im_sdk_callback.OnDBMigrationListener
+	dbCorruptionListener    open_im_sdk_callback.OnDBCorruptionListener
+	downloadListener        open_im_sdk_callback.OnDownloadListener
+	mediaCacheListener      open_im_sdk_callback.OnMediaCacheListener
+	sdkErrorListener        open_im_sdk_callback.OnSdkErrorListener
+	e2eeListener            open_im_sdk_callback.OnE2EEListener
+	momentsListener         open_im_sdk_callback.OnMomentsListener
+	organizationListener    open_im_sdk_callback.OnOrganizationListener
+	customerServiceListener open_im_sdk_callback.OnCustomerServiceListener
+	clientConfigListener    open_im_sdk_callback.OnClientConfigListener
+	videoTranscoder         open_im_sdk_callback.VideoTranscoder
+	translator              open_im_sdk_callback.Translator
+	moderator               open_im_sdk_callback.Moderator
+	// mediaKey encrypts the media downloaded, set by the app, never logged
+	mediaKey string
 
 	//conversationCh chan common.Cmd2Value
 
@@ -155,6 +265,8 @@ type UserContext struct {
 	cmdWsCh                chan common.Cmd2Value
 	msgSyncerCh            chan common.Cmd2Value
 	loginMgrCh             chan common.Cmd2Value
+	tokenRefreshedCh       chan struct{}
+	mediaCacheCh           chan struct{}
 
 	ctx       context.Context
 	cancel    context.CancelFunc
@@ -162,6 +274,37 @@ type UserContext struct {
 	fgCancel  context.CancelCauseFunc
 	info      *ccontext.GlobalConfig
 	id2MinSeq map[string]int64
+	// configMutex serializes the UpdateConfig calls
+	configMutex sync.Mutex
+	// listeners runs the calls of the listeners returned by the listener getters
+	listeners listenerDispatcher
+	// conversationChanges coalesces the calls of OnConversationChanged within ConversationChangeWindow
+	conversationChanges conversationChanges
+
+	// compactCancel stops the compaction of the database in background
+	compactCancel context.CancelFunc
+	compactMutex  sync.Mutex
+	// corruptionResyncScopes are pulled again after login, the database lost them to a corruption
+	corruptionResyncScopes []string
+	// replayDBDir is the directory of the fresh database of a replay, see ReplayRecording
+	replayDBDir string
+	// keptAccounts are the accounts switched away from, by user ID
+	keptAccounts map[string]*keptAccount
+	keptMutex    sync.Mutex
+
+	// the listeners added besides the ones set, by AddXxxListener and Subscribe
+	conversationListeners listenerSet[open_im_sdk_callback.OnConversationListener]
+	advancedMsgListeners  listenerSet[open_im_sdk_callback.OnAdvancedMsgListener]
+	friendshipListeners   listenerSet[open_im_sdk_callback.OnFriendshipListener]
+	groupListeners        listenerSet[open_im_sdk_callback.OnGroupListener]
+	userListeners         listenerSet[open_im_sdk_callback.OnUserListener]
+	businessListeners     listenerSet[open_im_sdk_callback.OnCustomBusinessListener]
+	messagePlugins        listenerSet[open_im_sdk_callback.MessagePlugin]
+
+	// the backends set by the app embedding the sdk, kept across the logouts
+	userCacheStore        cache.Store[string, *model_struct.LocalUser]
+	groupMemberCacheStore cache.Store[string, *model_struct.LocalGroupMember]
+	fileStorage           file.Storage
 }
 
 func (u *UserContext) Info() *ccontext.GlobalConfig {
@@ -169,39 +312,152 @@ func (u *UserContext) Info() *ccontext.GlobalConfig {
 }
 
 func (u *UserContext) ConnListener() open_im_sdk_callback.OnConnListener {
-	return u.connListener
+	return dispatchedConnListener{d: &u.listeners, l: u.connListener}
 }
 
 func (u *UserContext) GroupListener() open_im_sdk_callback.OnGroupListener {
-	return u.groupListener
+	if u.groupListeners.empty() {
+		return dispatchedGroupListener{d: &u.listeners, l: u.groupListener}
+	}
+	return dispatchedGroupListener{d: &u.listeners, l: groupListeners(u.groupListeners.with(u.groupListener))}
 }
 
 func (u *UserContext) FriendshipListener() open_im_sdk_callback.OnFriendshipListener {
-	return u.friendshipListener
+	if u.friendshipListeners.empty() {
+		return dispatchedFriendshipListener{d: &u.listeners, l: u.friendshipListener}
+	}
+	return dispatchedFriendshipListener{d: &u.listeners, l: friendshipListeners(u.friendshipListeners.with(u.friendshipListener))}
 }
 
 func (u *UserContext) ConversationListener() open_im_sdk_callback.OnConversationListener {
-	return u.conversationListener
+	var l open_im_sdk_callback.OnConversationListener
+	if u.conversationListeners.empty() {
+		l = dispatchedConversationListener{d: &u.listeners, l: u.conversationListener}
+	} else {
+		l = dispatchedConversationListener{d: &u.listeners, l: conversationListeners(u.conversationListeners.with(u.conversationListener))}
+	}
+	var window time.Duration
+	if u.info.Config() != nil {
+		window = time.Duration(u.info.Config().ConversationChangeWindow) * time.Millisecond
+	}
+	return coalescedConversationListener{c: &u.conversationChanges, window: window, l: l}
 }
 
 func (u *UserContext) AdvancedMsgListener() open_im_sdk_callback.OnAdvancedMsgListener {
-	return u.advancedMsgListener
+	if u.advancedMsgListeners.empty() {
+		return dispatchedAdvancedMsgListener{d: &u.listeners, l: u.advancedMsgListener}
+	}
+	return dispatchedAdvancedMsgListener{d: &u.listeners, l: advancedMsgListeners(u.advancedMsgListeners.with(u.advancedMsgListener))}
 }
 
 func (u *UserContext) UserListener() open_im_sdk_callback.OnUserListener {
-	return u.userListener
+	if u.userListeners.empty() {
+		return dispatchedUserListener{d: &u.listeners, l: u.userListener}
+	}
+	return dispatchedUserListener{d: &u.listeners, l: userListeners(u.userListeners.with(u.userListener))}
 }
 
 func (u *UserContext) SignalingListener() open_im_sdk_callback.OnSignalingListener {
-	return u.signalingListener
+	return dispatchedSignalingListener{d: &u.listeners, l: u.signalingListener}
 }
 
 func (u *UserContext) BusinessListener() open_im_sdk_callback.OnCustomBusinessListener {
-	return u.businessListener
+	if u.businessListeners.empty() {
+		return dispatchedBusinessListener{d: &u.listeners, l: u.businessListener}
+	}
+	return dispatchedBusinessListener{d: &u.listeners, l: businessListeners(u.businessListeners.with(u.businessListener))}
 }
 
 func (u *UserContext) MsgKvListener() open_im_sdk_callback.OnMessageKvInfoListener {
-	return u.msgKvListener
+	return dispatchedMsgKvListener{d: &u.listeners, l: u.msgKvListener}
+}
+
+func (u *UserContext) QRLoginListener() open_im_sdk_callback.OnQRLoginListener {
+	return dispatchedQRLoginListener{d: &u.listeners, l: u.qrLoginListener}
+}
+
+func (u *UserContext) TokenListener() open_im_sdk_callback.OnTokenListener {
+	return dispatchedTokenListener{d: &u.listeners, l: u.tokenListener}
+}
+
+func (u *UserContext) ConnStateListener() open_im_sdk_callback.OnConnStateListener {
+	return dispatchedConnStateListener{d: &u.listeners, l: u.connStateListener}
+}
+
+func (u *UserContext) NetworkQualityListener() open_im_sdk_callback.OnNetworkQualityListener {
+	return dispatchedNetworkQualityListener{d: &u.listeners, l: u.qualityListener}
+}
+
+func (u *UserContext) AppLifecycleListener() open_im_sdk_callback.OnAppLifecycleListener {
+	return dispatchedAppLifecycleListener{d: &u.listeners, l: u.lifecycleListener}
+}
+
+func (u *UserContext) SyncProgressListener() open_im_sdk_callback.OnSyncProgressListener {
+	return dispatchedSyncProgressListener{d: &u.listeners, l: u.syncProgressListener}
+}
+
+func (u *UserContext) SyncConflictListener() open_im_sdk_callback.OnSyncConflictListener {
+	return dispatchedSyncConflictListener{d: &u.listeners, l: u.conflictListener}
+}
+
+func (u *UserContext) DBMigrationListener() open_im_sdk_callback.OnDBMigrationListener {
+	return dispatchedDBMigrationListener{d: &u.listeners, l: u.dbMigrationListener}
+}
+
+func (u *UserContext) DBCorruptionListener() open_im_sdk_callback.OnDBCorruptionListener {
+	return dispatchedDBCorruptionListener{d: &u.listeners, l: u.dbCorruptionListener}
+}
+
+func (u *UserContext) DownloadListener() open_im_sdk_callback.OnDownloadListener {
+	return dispatchedDownloadListener{d: &u.listeners, l: u.downloadListener}
+}
+
+func (u *UserContext) MediaCacheListener() open_im_sdk_callback.OnMediaCacheListener {
+	return dispatchedMediaCacheListener{d: &u.listeners, l: u.mediaCacheListener}
+}
+
+func (u *UserContext) SdkErrorListener() open_im_sdk_callback.OnSdkErrorListener {
+	return dispatchedSdkErrorListener{d: &u.listeners, l: u.sdkErrorListener}
+}
+
+func (u *UserContext) E2EEListener() open_im_sdk_callback.OnE2EEListener {
+	return dispatchedE2EEListener{d: &u.listeners, l: u.e2eeListener}
+}
+
+func (u *UserContext) MomentsListener() open_im_sdk_callback.OnMomentsListener {
+	return dispatchedMomentsListener{d: &u.listeners, l: u.momentsListener}
+}
+
+func (u *UserContext) OrganizationListener() open_im_sdk_callback.OnOrganizationListener {
+	return dispatchedOrganizationListener{d: &u.listeners, l: u.organizationListener}
+}
+
+func (u *UserContext) ClientConfigListener() open_im_sdk_callback.OnClientConfigListener {
+	return dispatchedClientConfigListener{d: &u.listeners, l: u.clientConfigListener}
+}
+
+func (u *UserContext) CustomerServiceListener() open_im_sdk_callback.OnCustomerServiceListener {
+	return dispatchedCustomerServiceListener{d: &u.listeners, l: u.customerServiceListener}
+}
+
+func (u *UserContext) ConflictResolver() open_im_sdk_callback.ConflictResolver {
+	return u.conflictResolver
+}
+
+func (u *UserContext) VideoTranscoder() open_im_sdk_callback.VideoTranscoder {
+	return u.videoTranscoder
+}
+
+func (u *UserContext) Translator() open_im_sdk_callback.Translator {
+	return u.translator
+}
+
+func (u *UserContext) Moderator() open_im_sdk_callback.Moderator {
+	return u.moderator
+}
+
+func (u *UserContext) MessagePlugins() []open_im_sdk_callback.MessagePlugin {
+	return u.messagePlugins.with(nil)
 }
 
 func (u *UserContext) Exit() {
@@ -213,12 +469,13 @@ func (u *UserContext) Third() *third.Third {
 }
 
 func (u *UserContext) ImConfig() sdk_struct.IMConfig {
+	config := u.info.Config()
 	return sdk_struct.IMConfig{
-		PlatformID: u.info.PlatformID,
-		ApiAddr:    u.info.ApiAddr,
-		WsAddr:     u.info.WsAddr,
-		DataDir:    u.info.DataDir,
-		LogLevel:   u.info.LogLevel,
+		PlatformID: config.PlatformID,
+		ApiAddr:    config.ApiAddr,
+		WsAddr:     config.WsAddr,
+		DataDir:    config.DataDir,
+		LogLevel:   config.LogLevel,
 	}
 }
 
@@ -226,6 +483,18 @@ func (u *UserContext) Conversation() *conv.Conversation {
 	return u.conversation
 }
 
+func (u *UserContext) QRLogin() *qrlogin.QRLogin {
+	return u.qrLogin
+}
+
+func (u *UserContext) E2EE() *e2ee.E2EE {
+	return u.e2ee
+}
+
+func (u *UserContext) CustomerService() *customerservice.CustomerService {
+	return u.customerService
+}
+
 func (u *UserContext) User() *user.User {
 	return u.user
 }
@@ -254,6 +523,98 @@ func (u *UserContext) SetMessageKvInfoListener(messageKvInfoListener open_im_sdk
 	u.msgKvListener = messageKvInfoListener
 }
 
+func (u *UserContext) SetQRLoginListener(qrLoginListener open_im_sdk_callback.OnQRLoginListener) {
+	u.qrLoginListener = qrLoginListener
+}
+
+func (u *UserContext) SetTokenListener(tokenListener open_im_sdk_callback.OnTokenListener) {
+	u.tokenListener = tokenListener
+}
+
+func (u *UserContext) SetConnStateListener(connStateListener open_im_sdk_callback.OnConnStateListener) {
+	u.connStateListener = connStateListener
+}
+
+func (u *UserContext) SetNetworkQualityListener(qualityListener open_im_sdk_callback.OnNetworkQualityListener) {
+	u.qualityListener = qualityListener
+}
+
+func (u *UserContext) SetAppLifecycleListener(lifecycleListener open_im_sdk_callback.OnAppLifecycleListener) {
+	u.lifecycleListener = lifecycleListener
+}
+
+func (u *UserContext) SetSyncProgressListener(syncProgressListener open_im_sdk_callback.OnSyncProgressListener) {
+	u.syncProgressListener = syncProgressListener
+}
+
+func (u *UserContext) SetSyncConflictListener(conflictListener open_im_sdk_callback.OnSyncConflictListener) {
+	u.conflictListener = conflictListener
+}
+
+func (u *UserContext) SetDBMigrationListener(dbMigrationListener open_im_sdk_callback.OnDBMigrationListener) {
+	u.dbMigrationListener = dbMigrationListener
+}
+
+func (u *UserContext) SetDBCorruptionListener(dbCorruptionListener open_im_sdk_callback.OnDBCorruptionListener) {
+	u.dbCorruptionListener = dbCorruptionListener
+}
+
+func (u *UserContext) SetDownloadListener(downloadListener open_im_sdk_callback.OnDownloadListener) {
+	u.downloadListener = downloadListener
+}
+
+func (u *UserContext) SetMediaCacheListener(mediaCacheListener open_im_sdk_callback.OnMediaCacheListener) {
+	u.mediaCacheListener = mediaCacheListener
+}
+
+func (u *UserContext) SetSdkErrorListener(sdkErrorListener open_im_sdk_callback.OnSdkErrorListener) {
+	u.sdkErrorListener = sdkErrorListener
+}
+
+func (u *UserContext) SetEventListener(eventListener open_im_sdk_callback.OnEventListener) {
+	u.listeners.setEventListener(eventListener)
+}
+
+func (u *UserContext) SetE2EEListener(e2eeListener open_im_sdk_callback.OnE2EEListener) {
+	u.e2eeListener = e2eeListener
+}
+
+func (u *UserContext) SetMomentsListener(momentsListener open_im_sdk_callback.OnMomentsListener) {
+	u.momentsListener = momentsListener
+}
+
+func (u *UserContext) SetOrganizationListener(organizationListener open_im_sdk_callback.OnOrganizationListener) {
+	u.organizationListener = organizationListener
+}
+
+func (u *UserContext) SetClientConfigListener(clientConfigListener open_im_sdk_callback.OnClientConfigListener) {
+	u.clientConfigListener = clientConfigListener
+}
+
+func (u *UserContext) SetCustomerServiceListener(customerServiceListener open_im_sdk_callback.OnCustomerServiceListener) {
+	u.customerServiceListener = customerServiceListener
+}
+
+func (u *UserContext) SetSignalingListener(signalingListener open_im_sdk_callback.OnSignalingListener) {
+	u.signalingListener = signalingListener
+}
+
+func (u *UserContext) SetConflictResolver(conflictResolver open_im_sdk_callback.ConflictResolver) {
+	u.conflictResolver = conflictResolver
+}
+
+func (u *UserContext) SetVideoTranscoder(videoTranscoder open_im_sdk_callback.VideoTranscoder) {
+	u.videoTranscoder = videoTranscoder
+}
+
+func (u *UserContext) SetTranslator(translator open_im_sdk_callback.Translator) {
+	u.translator = translator
+}
+
+func (u *UserContext) SetModerator(moderator open_im_sdk_callback.Moderator) {
+	u.moderator = moderator
+}
+
 func (u *UserContext) SetFriendshipListener(friendshipListener open_im_sdk_callback.OnFriendshipListener) {
 	u.friendshipListener = friendshipListener
 }
@@ -270,24 +631,74 @@ func (u *UserContext) SetCustomBusinessListener(listener open_im_sdk_callback.On
 	u.businessListener = listener
 }
 
+func (u *UserContext) AddConversationListener(listener open_im_sdk_callback.OnConversationListener) {
+	u.conversationListeners.add(listener)
+}
+
+func (u *UserContext) RemoveConversationListener(listener open_im_sdk_callback.OnConversationListener) {
+	u.conversationListeners.remove(listener)
+}
+
+func (u *UserContext) AddAdvancedMsgListener(listener open_im_sdk_callback.OnAdvancedMsgListener) {
+	u.advancedMsgListeners.add(listener)
+}
+
+func (u *UserContext) RemoveAdvancedMsgListener(listener open_im_sdk_callback.OnAdvancedMsgListener) {
+	u.advancedMsgListeners.remove(listener)
+}
+
+func (u *UserContext) AddFriendshipListener(listener open_im_sdk_callback.OnFriendshipListener) {
+	u.friendshipListeners.add(listener)
+}
+
+func (u *UserContext) RemoveFriendshipListener(listener open_im_sdk_callback.OnFriendshipListener) {
+	u.friendshipListeners.remove(listener)
+}
+
+func (u *UserContext) AddGroupListener(listener open_im_sdk_callback.OnGroupListener) {
+	u.groupListeners.add(listener)
+}
+
+func (u *UserContext) RemoveGroupListener(listener open_im_sdk_callback.OnGroupListener) {
+	u.groupListeners.remove(listener)
+}
+
+func (u *UserContext) AddUserListener(listener open_im_sdk_callback.OnUserListener) {
+	u.userListeners.add(listener)
+}
+
+func (u *UserContext) RemoveUserListener(listener open_im_sdk_callback.OnUserListener) {
+	u.userListeners.remove(listener)
+}
+
+func (u *UserContext) AddCustomBusinessListener(listener open_im_sdk_callback.OnCustomBusinessListener) {
+	u.businessListeners.add(listener)
+}
+
+func (u *UserContext) RemoveCustomBusinessListener(listener open_im_sdk_callback.OnCustomBusinessListener) {
+	u.businessListeners.remove(listener)
+}
+
+func (u *UserContext) AddMessagePlugin(plugin open_im_sdk_callback.MessagePlugin) {
+	u.messagePlugins.add(plugin)
+}
+
+func (u *UserContext) RemoveMessagePlugin(plugin open_im_sdk_callback.MessagePlugin) {
+	u.messagePlugins.remove(plugin)
+}
+
 func (u *UserContext) GetLoginUserID() string {
 	return u.loginUserID
 }
 
 func (u *UserContext) logoutListener(ctx context.Context) {
-	defer func() {
-		if r := recover(); r != nil {
-			err := fmt.Sprintf("panic: %+v\n%s", r, debug.Stack())
-
-			log.ZWarn(ctx, "logoutListener panic", nil, "panic info", err)
-		}
-	}()
+	defer crash.Recover(ctx, "logoutListener")
 
 	for {
 		select {
 		case <-u.loginMgrCh:
 			log.ZDebug(ctx, "logoutListener exit")
-			err := u.logout(ctx, true)
+			err := u.logout(ctx, true, constant.LogoutModeKeepData, nil)
 			if err != nil {
 				log.ZError(ctx, "logout error", err)
 			}
@@ -366,7 +777,9 @@ func (u *UserContext) handlerSendingMsg(ctx context.Context, sendingMsg *model_s
 	return nil
 }
 
-func (u *UserContext) login(ctx context.Context, userID, token string) error {
+// login logs the user in, beforeLogged, when not nil, is called after everything succeeded and before the
+// calls that need a login are accepted.
+func (u *UserContext) login(ctx context.Context, userID, token string, beforeLogged func()) error {
 	if u.getLoginStatus(ctx) == Logged {
 		return sdkerrs.ErrLoginRepeat
 	}
@@ -375,50 +788,143 @@ func (u *UserContext) login(ctx context.Context, userID, token string) error {
 	t1 := time.Now()
 
 	u.info.UserID = userID
-	u.info.Token = token
+	u.info.SetToken(token)
+	recorder.Record(&recorder.Event{Kind: recorder.KindLogin, UserID: userID})
 
 	if err := u.initialize(ctx, userID); err != nil {
+		// the modules were set up for the user, start over from fresh ones so that the login can be retried
+		u.Exit()
+		u.initResources()
 		return err
 	}
 
 	u.run(ctx)
+	if beforeLogged != nil {
+		beforeLogged()
+	}
 	u.setLoginStatus(Logged)
+	u.resyncDBCorruption()
 	log.ZDebug(ctx, "login success...", "login cost time: ", time.Since(t1))
 	return nil
 }
 
-func (u *UserContext) initialize(ctx context.Context, userID string) error {
-	var err error
-	u.db, err = db.NewDataBase(ctx, userID, u.info.DataDir, int(u.info.LogLevel))
-	if err != nil {
-		return sdkerrs.ErrSdkInternal.WrapMsg("init database " + err.Error())
+func (u *UserContext) initialize(ctx context.Context, userID string) (err error) {
+	config := u.info.Config()
+	migrationCtx := db.WithMigrationProgress(ctx, func(version int, name string, done, total int) {
+		u.DBMigrationListener().OnDBMigrationProgress(jsonutil.StructToJsonString(&sdk_struct.DBMigrationProgress{
+			Version: version, Name: name, Done: done, Total: total}))
+	})
+	var incident *sdk_struct.DBCorruptionIncident
+	migrationCtx = db.WithPragmas(migrationCtx, config.DBPragmas)
+	migrationCtx = db.WithCorruptionHandler(migrationCtx, func(i *sdk_struct.DBCorruptionIncident) {
+		incident = i
+	})
+	dbDir := u.dbDir()
+	if config.InMemoryDB {
+		dbDir = db.MemoryDBDir
 	}
+	kept := u.takeKeptDB(userID)
+	if kept != nil {
+		// switched back to, the database was opened and migrated before
+		u.db = kept
+	} else {
+		u.db, err = db.NewDataBaseWithKey(migrationCtx, userID, dbDir, int(config.LogLevel), u.dbKey)
+		if err != nil {
+			return sdkerrs.ErrSdkInternal.WrapMsg("init database " + err.Error())
+		}
+	}
+	defer func() {
+		if err == nil {
+			return
+		}
+		// a database switched back to stays kept for the next attempt, one just opened is closed
+		if kept != nil {
+			u.putKeptAccount(ctx, userID, u.info.Token(), kept)
+		} else if closeErr := u.db.Close(ctx); closeErr != nil {
+			log.ZWarn(ctx, "close db of the failed login failed", closeErr, "userID", userID)
+		}
+	}()
+	u.db.SetFieldKey(u.draftKey)
 	u.checkSendingMessage(ctx)
 	u.user.SetLoginUserID(userID)
 	u.user.SetDataBase(u.db)
+	u.setInfoCacheStores()
+	u.user.InitClientConfig(ctx)
+	if config.EnableAuditLog {
+		audit.Enable(userID, u.db, config.UploadAuditLog)
+	} else {
+		audit.Disable(userID)
+	}
+	u.user.SetLastSeenPrecision(config.LastSeenPrecision)
+	u.longConnMgr.SetHeartbeat(time.Duration(config.HeartbeatInterval)*time.Second,
+		time.Duration(config.MaxHeartbeatInterval)*time.Second, config.AdaptiveHeartbeat)
+	u.longConnMgr.SetDegradedThresholds(time.Duration(config.DegradedRTT)*time.Millisecond, config.DegradedLossRate)
+	u.longConnMgr.SetReconnectPolicy(time.Duration(config.ReconnectInitialDelay)*time.Millisecond, config.ReconnectMultiplier,
+		time.Duration(config.ReconnectMaxDelay)*time.Millisecond, config.ReconnectJitter, config.ReconnectMaxAttempts)
 	u.file.SetLoginUserID(userID)
 	u.file.SetDataBase(u.db)
+	u.file.SetUploadParallelism(config.UploadParallelism)
+	u.download.SetDir(config.DataDir)
+	u.download.SetConcurrency(config.DownloadConcurrency)
+	u.download.SetMediaKey(u.mediaKey)
+	// the plain copies left by a crash
+	u.download.ClearDecrypted(ctx)
 	u.relation.SetDataBase(u.db)
 	u.relation.SetLoginUserID(userID)
 	u.group.SetDataBase(u.db)
 	u.group.SetLoginUserID(userID)
-	u.third.SetPlatform(u.info.PlatformID)
+	u.third.SetPlatform(config.PlatformID)
 	u.third.SetLoginUserID(userID)
-	u.third.SetAppFramework(u.info.SystemType)
-	u.third.SetLogFilePath(u.info.LogFilePath)
+	u.third.SetAppFramework(config.SystemType)
+	u.third.SetLogFilePath(config.LogFilePath)
+	u.e2ee.SetLoginUserID(userID)
+	u.e2ee.SetDataBase(u.db)
+	u.customerService.SetLoginUserID(userID)
+	u.customerService.SetDataBase(u.db)
+	u.customerService.SetEnabled(config.EnableCustomerService)
+	u.loginModules(ctx, userID, config)
 	u.msgSyncer.SetLoginUserID(userID)
 	u.msgSyncer.SetDataBase(u.db)
+	u.msgSyncer.SetSyncWorkers(config.MsgSyncWorkers)
 	u.conversation.SetLoginUserID(userID)
 	u.conversation.SetDataBase(u.db)
-	u.conversation.SetPlatform(u.info.PlatformID)
-	u.conversation.SetDataDir(u.info.DataDir)
+	u.conversation.SetPlatform(config.PlatformID)
+	u.conversation.SetDataDir(config.DataDir)
+	u.conversation.SetConflictPolicies(config.ConflictPolicies)
+	u.conversation.SetMsgWriteBatchSize(config.MsgWriteBatchSize)
+	u.conversation.SetStripImageMetadata(config.StripImageMetadata)
+	u.conversation.SetSignMessages(config.SignMessages)
+	u.conversation.SetSensitiveWordFilter(config.SensitiveWordMode, config.SensitiveWordMask, config.FilterReceivedSensitiveWords)
+	u.conversation.SetModerateReceived(config.ModerateReceivedMessages)
+	u.conversation.SetImageCompression(imageCompression(config))
+	u.conversation.SetVideoConstraints(videoConstraints(config))
+	if config.AutoReportBadge {
+		u.conversation.SetBadgeReporter(u.third.ReportBadge)
+	} else {
+		u.conversation.SetBadgeReporter(nil)
+	}
 	err = u.msgSyncer.LoadSeq(ctx)
 	if err != nil {
 		return err
 	}
+	if incident != nil {
+		u.recoverDBCorruption(ctx, incident)
+	}
 	return nil
 }
 
+// dbDir is the directory of the database files.
+func (u *UserContext) dbDir() string {
+	config := u.info.Config()
+	if u.replayDBDir != "" {
+		return u.replayDBDir
+	}
+	if config.DBDir != "" {
+		return config.DBDir
+	}
+	return config.DataDir
+}
+
 func (u *UserContext) setListener(ctx context.Context) {
 	setListener(ctx, &u.connListener, u.ConnListener, u.longConnMgr.SetListener, nil)
 	setListener(ctx, &u.userListener, u.UserListener, u.user.SetListener, newEmptyUserListener)
@@ -427,6 +933,37 @@ func (u *UserContext) setListener(ctx context.Context) {
 	setListener(ctx, &u.conversationListener, u.ConversationListener, u.conversation.SetConversationListener, newEmptyConversationListener)
 	setListener(ctx, &u.advancedMsgListener, u.AdvancedMsgListener, u.conversation.SetMsgListener, newEmptyAdvancedMsgListener)
 	setListener(ctx, &u.businessListener, u.BusinessListener, u.conversation.SetBusinessListener, newEmptyCustomBusinessListener)
+	setListener(ctx, &u.qrLoginListener, u.QRLoginListener, u.qrLogin.SetListener, newEmptyQRLoginListener)
+	setListener(ctx, &u.connStateListener, u.ConnStateListener, u.longConnMgr.SetStateListener, newEmptyConnStateListener)
+	setListener(ctx, &u.qualityListener, u.NetworkQualityListener, u.longConnMgr.SetNetworkQualityListener, newEmptyNetworkQualityListener)
+	setListener(ctx, &u.lifecycleListener, u.AppLifecycleListener, u.msgSyncer.SetAppLifecycleListener, newEmptyAppLifecycleListener)
+	setListener(ctx, &u.syncProgressListener, u.SyncProgressListener, u.conversation.SetSyncProgressListener, newEmptySyncProgressListener)
+	setListener(ctx, &u.conflictListener, u.SyncConflictListener, u.conversation.SetSyncConflictListener, newEmptySyncConflictListener)
+	setListener(ctx, &u.conflictResolver, u.ConflictResolver, u.conversation.SetConflictResolver, nil)
+	setListener(ctx, &u.videoTranscoder, u.VideoTranscoder, u.conversation.SetVideoTranscoder, nil)
+	setListener(ctx, &u.translator, u.Translator, u.conversation.SetTranslator, nil)
+	setListener(ctx, &u.moderator, u.Moderator, u.conversation.SetModerator, nil)
+	u.conversation.SetMessagePlugins(u.MessagePlugins)
+	setListener(ctx, &u.downloadListener, u.DownloadListener, u.download.SetListener, newEmptyDownloadListener)
+	setListener(ctx, &u.e2eeListener, u.E2EEListener, u.e2ee.SetListener, newEmptyE2EEListener)
+	setListener(ctx, &u.clientConfigListener, u.ClientConfigListener, u.user.SetClientConfigListener, newEmptyClientConfigListener)
+	setListener(ctx, &u.customerServiceListener, u.CustomerServiceListener, u.customerService.SetListener, newEmptyCustomerServiceListener)
+	u.setModuleListeners(ctx)
+	if u.tokenListener == nil {
+		u.tokenListener = newEmptyTokenListener(ctx)
+	}
+	if u.dbMigrationListener == nil {
+		u.dbMigrationListener = newEmptyDBMigrationListener(ctx)
+	}
+	if u.dbCorruptionListener == nil {
+		u.dbCorruptionListener = newEmptyDBCorruptionListener(ctx)
+	}
+	if u.mediaCacheListener == nil {
+		u.mediaCacheListener = newEmptyMediaCacheListener(ctx)
+	}
+	if u.sdkErrorListener == nil {
+		u.sdkErrorListener = newEmptySdkErrorListener(ctx)
+	}
 }
 
 func setListener[T any](ctx context.Context, listener *T, getter func() T, setFunc func(listener func() T), newFunc func(context.Context) T) {
@@ -441,18 +978,89 @@ func (u *UserContext) run(ctx context.Context) {
 	go u.msgSyncer.DoListener(ctx)
 	go common.DoListener(u.ctx, u.conversation)
 	go u.logoutListener(ctx)
+	go u.tokenExpireWatcher(ctx)
+	go u.storageQuotaWatcher(u.ctx)
+	go u.replenishPreKeys(u.ctx)
 }
 
 func (u *UserContext) setFGCtx() {
 	u.fgCtx, u.fgCancel = context.WithCancelCause(context.Background())
 }
 
+func imageCompression(config *sdk_struct.IMConfig) conv.ImageCompression {
+	return conv.ImageCompression{
+		MaxSide:          config.ImageMaxSide,
+		Quality:          config.ImageQuality,
+		MaxBytes:         config.ImageMaxBytes,
+		ThumbnailMaxSide: config.ThumbnailMaxSide,
+	}
+}
+
+func videoConstraints(config *sdk_struct.IMConfig) sdk_struct.VideoTranscodeConstraints {
+	return sdk_struct.VideoTranscodeConstraints{
+		MaxSide:    config.VideoMaxSide,
+		MaxBitrate: config.VideoMaxBitrate,
+		MaxBytes:   config.VideoMaxBytes,
+	}
+}
+
 func (u *UserContext) InitSDK(config *sdk_struct.IMConfig, listener open_im_sdk_callback.OnConnListener) bool {
 	if listener == nil {
 		return false
 	}
-	u.info.IMConfig = config
+	// the whole config is checked before any of it is applied
+	if !checkIMConfig(context.Background(), config) {
+		return false
+	}
+	if err := network.CheckProxy(config.Proxy); err != nil {
+		log.ZError(context.Background(), "invalid proxy config", err, "proxy", config.Proxy)
+		return false
+	}
+	// the settings of the network and the database are of the user context, the others of the process
+	ctx := ccontext.WithInfo(context.Background(), u.info)
+	if err := network.SetCertificatePins(ctx, config.CertificatePins); err != nil {
+		log.ZError(context.Background(), "invalid certificate pins", err, "certificatePins", config.CertificatePins)
+		return false
+	}
+	if err := network.SetBandwidthLimit(ctx, config.BandwidthLimit); err != nil {
+		log.ZError(context.Background(), "invalid bandwidth limit", err, "bandwidthLimit", config.BandwidthLimit)
+		return false
+	}
+	if err := network.SetRequestPolicies(config.RequestPolicies); err != nil {
+		log.ZError(context.Background(), "invalid request policies", err, "requestPolicies", config.RequestPolicies)
+		return false
+	}
+	if err := network.SetAllowOnMetered(config.AllowOnMetered); err != nil {
+		log.ZError(context.Background(), "invalid allow on metered", err, "allowOnMetered", config.AllowOnMetered)
+		return false
+	}
+	if err := db.SetFileNameFormat(config.DBFileName); err != nil {
+		log.ZError(context.Background(), "invalid db file name", err, "dbFileName", config.DBFileName)
+		return false
+	}
+	db.SetInstrumentation(config.DBInstrumentation, time.Duration(config.DBSlowQueryThreshold)*time.Millisecond)
+	if err := fault.Configure(config.FaultInjection); err != nil {
+		log.ZError(context.Background(), "invalid fault injection", err, "faultInjection", config.FaultInjection)
+		return false
+	}
+	i18n.SetLocale(config.Locale)
+	if err := telemetry.Configure(config.Telemetry, telemetry.Int("openim.platform_id", int(config.PlatformID)), telemetry.String("openim.system_type", config.SystemType)); err != nil {
+		log.ZError(context.Background(), "invalid telemetry config", err, "telemetry", config.Telemetry)
+		return false
+	}
+	var grpcAddr string
+	if config.ApiTransport == constant.ApiTransportGRPC {
+		grpcAddr = config.GrpcAddr
+	}
+	if err := network.SetGrpc(ctx, grpcAddr); err != nil {
+		log.ZError(context.Background(), "invalid grpc config", err, "grpcAddr", config.GrpcAddr)
+		return false
+	}
+	u.info.SetConfig(config)
 	u.connListener = listener
+	u.setCrashReports(config)
+	errreport.SetReporter(u.reportSdkError)
+	u.setProfiling(config)
 	return true
 }
 
@@ -469,19 +1077,21 @@ func (u *UserContext) UnInitSDK() {
 		fmt.Println("sdk not logout, please logout first")
 		return
 	}
-	u.Info().IMConfig = nil
+	u.closeKeptAccounts(context.Background())
+	profiling.StartStats(0)
+	u.Info().SetConfig(nil)
 	u.setLoginStatus(0)
 }
 
 // token error recycle recourse, kicked not recycle
-func (u *UserContext) logout(ctx context.Context, isTokenValid bool) error {
+func (u *UserContext) logout(ctx context.Context, isTokenValid bool, mode string, progress func(done, total int)) error {
 	if ccontext.Info(ctx).OperationID() == LogoutTips {
 		isTokenValid = true
 	}
 	if !isTokenValid {
 		ctx, cancel := context.WithTimeout(ctx, 20*time.Second)
 		defer cancel()
-		err := u.longConnMgr.SendReqWaitResp(ctx, &push.DelUserPushTokenReq{UserID: u.info.UserID, PlatformID: u.info.PlatformID}, constant.LogoutMsg, &push.DelUserPushTokenResp{})
+		err := u.longConnMgr.SendReqWaitResp(ctx, &push.DelUserPushTokenReq{UserID: u.info.UserID, PlatformID: u.info.Config().PlatformID}, constant.LogoutMsg, &push.DelUserPushTokenResp{})
 		if err != nil {
 			log.ZWarn(ctx, "TriggerCmdLogout server recycle resources failed...", err)
 		} else {
@@ -489,44 +1099,59 @@ func (u *UserContext) logout(ctx context.Context, isTokenValid bool) error {
 		}
 	}
 	u.Exit()
+	u.relation.StopFriendSync()
+	var wipeErr error
+	if mode == constant.LogoutModeWipeMessages {
+		wipeErr = u.wipeMessages(ctx, progress)
+	}
 	err := u.db.Close(u.ctx)
 	if err != nil {
 		log.ZWarn(ctx, "TriggerCmdLogout db recycle resources failed...", err)
 	}
+	if mode == constant.LogoutModeSecureWipe {
+		wipeErr = u.secureWipe(ctx, progress)
+	}
+	u.download.ClearDecrypted(ctx)
 	// user object must be rest  when user logout
 	u.initResources()
 	log.ZDebug(ctx, "TriggerCmdLogout client success...",
-		"isTokenValid", isTokenValid)
-	return nil
+		"isTokenValid", isTokenValid, "mode", mode)
+	return wipeErr
 }
 
 func (u *UserContext) setAppBackgroundStatus(ctx context.Context, isBackground bool) error {
+	if err := u.switchAppBackground(ctx, isBackground); err != nil {
+		return err
+	}
+	if !isBackground {
+		_ = common.DispatchWakeUp(ctx, u.msgSyncerCh)
+	}
+	return nil
+}
 
+// switchAppBackground moves the long connection to background or foreground and tells the server.
+func (u *UserContext) switchAppBackground(ctx context.Context, isBackground bool) error {
 	u.longConnMgr.SetBackground(isBackground)
 
 	if !isBackground {
-		if u.info.StopGoroutineOnBackground {
+		if u.info.Config().StopGoroutineOnBackground {
 			u.setFGCtx()
 			u.longConnMgr.ResumeForegroundTasks(u.ctx, u.fgCtx)
 		}
 	} else {
-		if u.info.StopGoroutineOnBackground {
+		if u.info.Config().StopGoroutineOnBackground {
 			u.fgCancel(errs.Wrap(fmt.Errorf("app in background")))
 			u.longConnMgr.Close(ctx)
 		}
 	}
 	var resp sdkws.SetAppBackgroundStatusResp
-	err := u.longConnMgr.SendReqWaitResp(ctx, &sdkws.SetAppBackgroundStatusReq{UserID: u.loginUserID, IsBackground: isBackground}, constant.SetBackgroundStatus, &resp)
-	if err != nil {
-		return err
-	} else {
-		if !isBackground {
-			_ = common.DispatchWakeUp(ctx, u.msgSyncerCh)
-		}
-		return nil
-	}
+	return u.longConnMgr.SendReqWaitResp(ctx, &sdkws.SetAppBackgroundStatusReq{UserID: u.loginUserID, IsBackground: isBackground}, constant.SetBackgroundStatus, &resp)
 }
 
 func (u *UserContext) LongConnMgr() *interaction.LongConnMgr {
 	return u.longConnMgr
 }
+
+func (u *UserContext) MsgSyncer() *interaction.MsgSyncer {
+	return u.msgSyncer
+}
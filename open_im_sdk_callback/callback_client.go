@@ -74,6 +74,9 @@ type OnAdvancedMsgListener interface {
 	OnRecvOfflineNewMessage(message string)
 	OnMsgDeleted(message string)
 	OnRecvOnlineOnlyMessage(message string)
+	// OnMsgEdited Called when a message was edited in place, like an interactive card by its bot, with the
+	// message as edited
+	OnMsgEdited(message string)
 }
 
 type OnUserListener interface {
@@ -88,6 +91,187 @@ type OnMessageKvInfoListener interface {
 	OnMessageKvInfoChanged(messageChangedList string)
 }
 
+// OnConnStateListener extends OnConnListener with the detailed states of the long connection.
+type OnConnStateListener interface {
+	// OnConnStateChanged Called when the long connection enters a state, e.g. dnsResolving, tlsHandshake,
+	// authenticating, syncing, connected, degraded or reconnectScheduled with the next attempt time
+	OnConnStateChanged(state string)
+}
+
+type OnNetworkQualityListener interface {
+	// OnNetworkQualityChanged Called when the long connection becomes weak or recovers, e.g. to show a weak connection banner
+	OnNetworkQualityChanged(quality string)
+}
+
+type OnSyncConflictListener interface {
+	// OnSyncConflict Called once a conflict between the local and the server state is resolved, for auditing
+	OnSyncConflict(conflict string)
+}
+
+// ConflictResolver decides a conflict between the local and the server state, returns server or local, an
+// empty string applies the conflict policies of the config. Called while syncing, so it must return quickly.
+type ConflictResolver interface {
+	ResolveConflict(conflict string) string
+}
+
+// VideoTranscoder transcodes or compresses the videos sent before they are uploaded. Transcode is called with the
+// path of the video and the sdk_struct.VideoTranscodeConstraints, the send waits until the callback reports the
+// sdk_struct.VideoTranscodeResult, an empty path sending the original. The progress is passed to the callback of
+// the send, before the one of the upload.
+type VideoTranscoder interface {
+	Transcode(sourcePath string, constraints string, callback VideoTranscodeCallback)
+}
+
+type VideoTranscodeCallback interface {
+	Base
+	OnProgress(progress int)
+}
+
+// Translator translates the text of the messages for GetMessageTranslation, the callback reports the translated
+// text with OnSuccess. It is only called for the translations not cached yet.
+type Translator interface {
+	Translate(text string, lang string, callback Base)
+}
+
+// Moderator checks the sdk_struct.MsgStruct sent before it is stored and sent, and the ones received with
+// ModerateReceivedMessages of the config. Moderate returns the sdk_struct.ModerationVerdict, or an empty string
+// to report it later with OnSuccess of the callback. An error of the callback blocks the message sent and passes
+// the message received.
+type Moderator interface {
+	Moderate(message string, received bool, callback Base) string
+}
+
+// MessagePlugin hooks into the sending and the receiving of the messages, e.g. to encrypt, filter or rewrite
+// them. The hooks returning a message return it rewritten, an empty string leaves it unchanged. The plugins are
+// called in the order they were added, each with the message rewritten by the previous ones.
+type MessagePlugin interface {
+	// BeforeSend Called with the sdk_struct.MsgStruct sent to the server, the local message is not rewritten.
+	// An error rejects the send.
+	BeforeSend(message string) (string, error)
+	// AfterSend Called once the send succeeded, with errCode 0, or failed
+	AfterSend(message string, errCode int32, errMsg string)
+	// OnReceive Called with the sdk_struct.MsgStruct of a new message received, before it is parsed, stored
+	// and dispatched
+	OnReceive(message string) string
+	// BeforeStore Called with the model_struct.LocalChatLog of a message written to the local database
+	BeforeStore(message string) string
+}
+
+type OnSyncProgressListener interface {
+	// OnSyncProgress Called as the phases of the initial sync progress: conversations, friends, groups,
+	// groupMembers and messages, with the items done of the phase and the percentage of the whole sync
+	OnSyncProgress(progress string)
+}
+
+type OnDBMigrationListener interface {
+	// OnDBMigrationProgress Called as a schema migration of the local database progresses at login, with the
+	// version and name of the migration and the items done
+	OnDBMigrationProgress(progress string)
+}
+
+type OnDBCorruptionListener interface {
+	// OnDBCorruptionRecovered Called when the local database was found corrupted at login and recovered from,
+	// with the tables salvaged and the scopes pulled again from the server
+	OnDBCorruptionRecovered(incident string)
+}
+
+type OnDownloadListener interface {
+	// OnDownloadStateChanged Called when a download is queued, started, paused, completed, failed, corrupted or
+	// canceled. A corrupted file, which didn't match the md5 of the download, is removed and never completes
+	OnDownloadStateChanged(info string)
+	// OnDownloadProgress Called as a download saves the bytes of the file
+	OnDownloadProgress(info string)
+	// OnDownloadTotalProgress Called with the bytes saved and to save of all the downloads not finished
+	OnDownloadTotalProgress(progress string)
+}
+
+type OnMediaCacheListener interface {
+	// OnMediaEvicted Called with the media files removed by the media cache limit, the storage quota or
+	// ClearMediaCache, the app drops its references to them
+	OnMediaEvicted(eviction string)
+}
+
+type OnSdkErrorListener interface {
+	// OnSdkError Called when a background task of the SDK failed, like a sync with the server or the handling
+	// of a notification, with the source, the operation, the error code and its category
+	OnSdkError(sdkError string)
+}
+
+type OnEventListener interface {
+	// OnEvent Called with the envelope of every event, set or not its listener, stamped with its sequence IDs and
+	// in their order, so that the app can tell a stale event of a conversation from a newer one
+	OnEvent(envelope string)
+}
+
+type OnMomentsListener interface {
+	// OnNewMoment Called when a friend published a moment
+	OnNewMoment(moment string)
+	// OnMomentDeleted Called when a moment of the feed was deleted
+	OnMomentDeleted(momentID string)
+	// OnMomentLiked Called when a moment of the login user, or one the login user commented, was liked, with
+	// the moment and the user who liked it
+	OnMomentLiked(momentTips string)
+	// OnMomentCommented Called when a moment of the login user, or one the login user commented, was commented,
+	// with the moment and the comment
+	OnMomentCommented(momentTips string)
+}
+
+type OnOrganizationListener interface {
+	// OnDepartmentAdded Called when a department was added to the organization
+	OnDepartmentAdded(departmentInfo string)
+	// OnDepartmentDeleted Called when a department was removed from the organization
+	OnDepartmentDeleted(departmentInfo string)
+	// OnDepartmentInfoChanged Called when a department was renamed, moved or changed otherwise
+	OnDepartmentInfoChanged(departmentInfo string)
+	// OnDepartmentMemberAdded Called when a user was added to a department
+	OnDepartmentMemberAdded(memberInfo string)
+	// OnDepartmentMemberDeleted Called when a user was removed from a department
+	OnDepartmentMemberDeleted(memberInfo string)
+	// OnDepartmentMemberInfoChanged Called when the position or other info of a user in a department changed
+	OnDepartmentMemberInfoChanged(memberInfo string)
+}
+
+type OnClientConfigListener interface {
+	// OnClientConfigChanged Called when the client config the server defines changed, with the new config
+	OnClientConfigChanged(config string)
+}
+
+type OnCustomerServiceListener interface {
+	// OnSessionQueueChanged Called when a waiting session moved in its queue, with the new queue position
+	OnSessionQueueChanged(sessionInfo string)
+	// OnSessionAssigned Called when an agent took a waiting session
+	OnSessionAssigned(sessionInfo string)
+	// OnSessionTransferred Called when a session was handed over to another agent, with the agent it was
+	// transferred from and the reason
+	OnSessionTransferred(sessionTips string)
+	// OnSessionClosed Called when a session was closed by the customer, the agent or the server
+	OnSessionClosed(sessionTips string)
+}
+
+type OnE2EEListener interface {
+	// OnIdentityKeyChanged Called when a user's encryption identity changed, the user reinstalled or someone
+	// else is in the middle, the app warns before more messages are sent to the user
+	OnIdentityKeyChanged(userID string)
+	// OnDevicesChanged Called when the devices of a contact changed since they were last seen, with the devices
+	// added and removed, the verification of the added ones starts over
+	OnDevicesChanged(change string)
+}
+
+type OnAppLifecycleListener interface {
+	// OnSyncCaughtUp Called when the catch-up sync after EnterForeground is done and the data is up to date
+	OnSyncCaughtUp()
+}
+
+type OnTokenListener interface {
+	// OnTokenWillExpire Called ahead of the token expiry, the app should get a new token from its server and call RefreshToken
+	OnTokenWillExpire(expireTime int64)
+}
+
+type OnQRLoginListener interface {
+	// OnQRLoginStateChanged Called on the device showing the QR code when the scanning device acts on it
+	OnQRLoginStateChanged(state string)
+}
+
 type OnListenerForService interface {
 	// OnGroupApplicationAdded Someone applied to join a group
 	OnGroupApplicationAdded(groupApplication string)
@@ -112,6 +296,8 @@ type OnSignalingListener interface {
 
 	OnInviteeRejectedByOtherDevice(inviteeRejectedCallback string)
 
+	OnInviteeBusy(inviteeBusyCallback string)
+
 	OnInvitationCancelled(invitationCancelledCallback string)
 
 	OnInvitationTimeout(invitationTimeoutCallback string)
@@ -121,6 +307,9 @@ type OnSignalingListener interface {
 	OnRoomParticipantConnected(onRoomParticipantConnectedCallback string)
 
 	OnRoomParticipantDisconnected(onRoomParticipantDisconnectedCallback string)
+
+	// OnReceiveSignalingData Received a payload of the custom signaling channel
+	OnReceiveSignalingData(signalingData string)
 }
 
 type UploadFileCallback interface {
@@ -142,6 +331,29 @@ type UploadFileCallback interface {
 	Complete(size int64, url string, typ int)
 }
 
+// UploadSpeedCallback is optionally implemented by an UploadFileCallback or a SendMsgCallBack
+type UploadSpeedCallback interface {
+	// OnUploadSpeed Called with the bytes uploaded, the speed of the last seconds, the average speed and the
+	// time left of the upload, at most twice a second
+	OnUploadSpeed(speed string)
+}
+
 type UploadLogProgress interface {
 	OnProgress(current int64, size int64)
 }
+
+type LogoutWipeProgress interface {
+	// OnProgress Called as the local data is wiped at the logout, with the conversations or files wiped and to wipe
+	OnProgress(done int, total int)
+}
+
+type CompactDatabaseProgress interface {
+	// OnProgress Called as the unused pages of the local database are freed, with the pages freed and to free
+	OnProgress(done int, total int)
+}
+
+type ExportPersonalDataProgress interface {
+	// OnProgress Called as the personal data is exported, with the parts of it exported and to export: the
+	// profile, the relations, the groups, the conversations and the messages of each conversation
+	OnProgress(done int, total int)
+}
@@ -17,9 +17,8 @@ package open_im_sdk_callback
 import (
 	"context"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
-
-	"github.com/openimsdk/tools/log"
 )
 
 type OnFriendshipListenerSdk interface {
@@ -1,18 +1,26 @@
 package api
 
 import (
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/server_api_params"
 	"github.com/openimsdk/protocol/auth"
 	"github.com/openimsdk/protocol/conversation"
 	"github.com/openimsdk/protocol/group"
 	"github.com/openimsdk/protocol/jssdk"
 	"github.com/openimsdk/protocol/msg"
 	"github.com/openimsdk/protocol/relation"
+	"github.com/openimsdk/protocol/sdkws"
 	"github.com/openimsdk/protocol/third"
 	"github.com/openimsdk/protocol/user"
 )
 
 var (
 	ParseToken = newApi[auth.ParseTokenReq, auth.ParseTokenResp]("/auth/parse_token")
+
+	CreateQRLogin    = newApi[server_api_params.CreateQRLoginReq, server_api_params.CreateQRLoginResp]("/auth/create_qr_login")
+	GetQRLoginStatus = newApi[server_api_params.GetQRLoginStatusReq, server_api_params.GetQRLoginStatusResp]("/auth/get_qr_login_status")
+	ScanQRLogin      = newApi[server_api_params.ScanQRLoginReq, server_api_params.ScanQRLoginResp]("/auth/scan_qr_login")
+	ConfirmQRLogin   = newApi[server_api_params.ConfirmQRLoginReq, server_api_params.ConfirmQRLoginResp]("/auth/confirm_qr_login")
+	GuestLogin       = newApi[server_api_params.GuestLoginReq, server_api_params.GuestLoginResp]("/auth/guest_login")
 )
 
 var (
@@ -21,6 +29,10 @@ var (
 	UpdateUserInfoEx = newApi[user.UpdateUserInfoExReq, user.UpdateUserInfoExResp]("/user/update_user_info_ex")
 	UserRegister     = newApi[user.UserRegisterReq, user.UserRegisterResp]("/user/user_register")
 	UserClientConfig = newApi[user.GetUserClientConfigReq, user.GetUserClientConfigResp]("/user/get_user_client_config")
+
+	ProcessUserCommandAdd    = newApi[user.ProcessUserCommandAddReq, user.ProcessUserCommandAddResp]("/user/process_user_command_add")
+	ProcessUserCommandUpdate = newApi[user.ProcessUserCommandUpdateReq, user.ProcessUserCommandUpdateResp]("/user/process_user_command_update")
+	ProcessUserCommandGetAll = newApi[user.ProcessUserCommandGetAllReq, user.ProcessUserCommandGetAllResp]("/user/process_user_command_get_all")
 )
 
 var (
@@ -53,6 +65,7 @@ var (
 	SetConversationHasReadSeq        = newApi[msg.SetConversationHasReadSeqReq, msg.SetConversationHasReadSeqResp]("/msg/set_conversation_has_read_seq")
 	SendMsg                          = newApi[msg.SendMsgReq, msg.SendMsgResp]("/msg/send_msg")
 	GetServerTime                    = newApi[msg.GetServerTimeReq, msg.GetServerTimeResp]("/msg/get_server_time")
+	PullMessageBySeqs                = newApi[sdkws.PullMessageBySeqsReq, sdkws.PullMessageBySeqsResp]("/msg/pull_msg_by_seq")
 )
 
 var (
@@ -93,15 +106,57 @@ var (
 	GetActiveConversation      = newApi[jssdk.GetActiveConversationsReq, jssdk.GetActiveConversationsResp]("/jssdk/get_active_conversations")
 )
 
+var (
+	UploadE2EEKeys     = newApi[server_api_params.UploadE2EEKeysReq, server_api_params.UploadE2EEKeysResp]("/e2ee/upload_keys")
+	GetE2EEKeyBundle   = newApi[server_api_params.GetE2EEKeyBundleReq, server_api_params.GetE2EEKeyBundleResp]("/e2ee/get_key_bundle")
+	GetE2EEPreKeyCount = newApi[server_api_params.GetE2EEPreKeyCountReq, server_api_params.GetE2EEPreKeyCountResp]("/e2ee/get_prekey_count")
+	UploadE2EEBackup   = newApi[server_api_params.UploadE2EEBackupReq, server_api_params.UploadE2EEBackupResp]("/e2ee/upload_key_backup")
+	GetE2EEDevices     = newApi[server_api_params.GetE2EEDevicesReq, server_api_params.GetE2EEDevicesResp]("/e2ee/get_devices")
+	GetE2EEBackup      = newApi[server_api_params.GetE2EEBackupReq, server_api_params.GetE2EEBackupResp]("/e2ee/get_key_backup")
+)
+
+var (
+	RequestCustomerServiceAgent      = newApi[server_api_params.RequestCustomerServiceAgentReq, server_api_params.CustomerServiceSessionResp]("/customer_service/request_agent")
+	TransferCustomerServiceSession   = newApi[server_api_params.TransferCustomerServiceSessionReq, server_api_params.CustomerServiceSessionResp]("/customer_service/transfer")
+	CloseCustomerServiceSession      = newApi[server_api_params.CloseCustomerServiceSessionReq, server_api_params.CustomerServiceSessionResp]("/customer_service/close")
+	RateCustomerServiceSession       = newApi[server_api_params.RateCustomerServiceSessionReq, server_api_params.CustomerServiceSessionResp]("/customer_service/rate")
+	GetActiveCustomerServiceSessions = newApi[server_api_params.GetActiveCustomerServiceSessionsReq, server_api_params.GetActiveCustomerServiceSessionsResp]("/customer_service/get_active_sessions")
+)
+
+var (
+	UploadAuditLogs = newApi[server_api_params.UploadAuditLogsReq, server_api_params.UploadAuditLogsResp]("/audit/upload_logs")
+)
+
+var (
+	SubmitInteractiveAction = newApi[server_api_params.SubmitInteractiveActionReq, server_api_params.SubmitInteractiveActionResp]("/bot/submit_action")
+)
+
+var (
+	PublishMoment       = newApi[server_api_params.PublishMomentReq, server_api_params.PublishMomentResp]("/moments/publish")
+	DeleteMoment        = newApi[server_api_params.DeleteMomentReq, server_api_params.DeleteMomentResp]("/moments/delete")
+	GetMomentFeed       = newApi[server_api_params.GetMomentFeedReq, server_api_params.GetMomentFeedResp]("/moments/get_feed")
+	LikeMoment          = newApi[server_api_params.LikeMomentReq, server_api_params.MomentResp]("/moments/like")
+	CommentMoment       = newApi[server_api_params.CommentMomentReq, server_api_params.MomentResp]("/moments/comment")
+	DeleteMomentComment = newApi[server_api_params.DeleteMomentCommentReq, server_api_params.MomentResp]("/moments/delete_comment")
+)
+
+var (
+	GetPaginationDepartments        = newApi[server_api_params.GetPaginationDepartmentsReq, server_api_params.GetPaginationDepartmentsResp]("/organization/get_departments")
+	GetPaginationDepartmentMembers  = newApi[server_api_params.GetPaginationDepartmentMembersReq, server_api_params.GetPaginationDepartmentMembersResp]("/organization/get_department_members")
+	GetIncrementalDepartments       = newApi[server_api_params.GetIncrementalDepartmentsReq, server_api_params.GetIncrementalDepartmentsResp]("/organization/get_incremental_departments")
+	GetIncrementalDepartmentMembers = newApi[server_api_params.GetIncrementalDepartmentMembersReq, server_api_params.GetIncrementalDepartmentMembersResp]("/organization/get_incremental_department_members")
+)
+
 var (
 	GetAdminToken = newApi[auth.GetAdminTokenReq, auth.GetAdminTokenResp]("/auth/get_admin_token")
 	GetUsersToken = newApi[auth.GetUserTokenReq, auth.GetUserTokenResp]("/auth/get_user_token")
 )
 
 var (
-	FcmUpdateToken = newApi[third.FcmUpdateTokenReq, third.FcmUpdateTokenResp]("/third/fcm_update_token")
-	SetAppBadge    = newApi[third.SetAppBadgeReq, third.SetAppBadgeResp]("/third/set_app_badge")
-	UploadLogs     = newApi[third.UploadLogsReq, third.UploadLogsResp]("/third/logs/upload")
+	FcmUpdateToken      = newApi[third.FcmUpdateTokenReq, third.FcmUpdateTokenResp]("/third/fcm_update_token")
+	SetOfflinePushToken = newApi[server_api_params.SetOfflinePushTokenReq, third.FcmUpdateTokenResp]("/third/fcm_update_token")
+	SetAppBadge         = newApi[third.SetAppBadgeReq, third.SetAppBadgeResp]("/third/set_app_badge")
+	UploadLogs          = newApi[third.UploadLogsReq, third.UploadLogsResp]("/third/logs/upload")
 )
 
 var (
@@ -2,29 +2,80 @@ package api
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
 
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/network"
 	"github.com/openimsdk/protocol/sdkws"
+	"google.golang.org/protobuf/proto"
 )
 
 func newApi[Req, Resp any](api string) Api[Req, Resp] {
-	return Api[Req, Resp]{
-		api: api,
+	a := Api[Req, Resp]{api: api, class: network.RequestClassOf(api)}
+	// the requests of the sdk's own structs are sent over http only, grpc needs the protocol messages
+	if _, ok := any(new(Req)).(proto.Message); ok {
+		a.grpcMethod = grpcMethods[api]
+		a.grpcStream = grpcStreams[api]
 	}
+	return a
 }
 
 type Api[Req, Resp any] struct {
 	api string
+	// class decides the timeout and the retries of the calls
+	class string
+	// grpcMethod is the full grpc method name of the api, empty when it is only served over http
+	grpcMethod string
+	// grpcStream is the full name of the server streaming variant of the api, if any
+	grpcStream string
 }
 
+// Invoke calls the api over grpc when it was negotiated at init and the server serves the method, and over
+// http otherwise. The timeout and the retries are the request policy of the class of the api.
 func (a Api[Req, Resp]) Invoke(ctx context.Context, req *Req) (*Resp, error) {
-	var resp Resp
-	if err := network.ApiPost(ctx, a.api, req, &resp); err != nil {
+	var resp *Resp
+	err := network.DoWithPolicy(ctx, a.class, func(ctx context.Context) error {
+		resp = new(Resp)
+		return a.invoke(ctx, req, resp)
+	})
+	if err != nil {
 		return nil, err
 	}
-	return &resp, nil
+	return resp, nil
+}
+
+func (a Api[Req, Resp]) invoke(ctx context.Context, req *Req, resp *Resp) error {
+	if a.grpcMethod != "" && network.GrpcAvailable(ctx, a.grpcMethod) {
+		err := network.GrpcInvoke(ctx, a.grpcMethod, req, resp)
+		if !errors.Is(err, network.ErrGrpcUnimplemented) {
+			return err
+		}
+	}
+	return network.ApiPost(ctx, a.api, req, resp)
+}
+
+// Streamable reports whether Stream receives the response in several parts for the user context of ctx.
+func (a Api[Req, Resp]) Streamable(ctx context.Context) bool {
+	return a.grpcStream != "" && network.GrpcAvailable(ctx, a.grpcStream)
+}
+
+// Stream calls fn for each part of the response of the streaming variant of the api, or once with the whole
+// response when the api can not be streamed.
+func (a Api[Req, Resp]) Stream(ctx context.Context, req *Req, fn func(resp *Resp) error) error {
+	if a.Streamable(ctx) {
+		err := network.GrpcStream(ctx, a.grpcStream, req, func() any { return new(Resp) }, func(resp any) error {
+			return fn(resp.(*Resp))
+		})
+		if !errors.Is(err, network.ErrGrpcUnimplemented) {
+			return err
+		}
+	}
+	resp, err := a.Invoke(ctx, req)
+	if err != nil {
+		return err
+	}
+	return fn(resp)
 }
 
 func (a Api[Req, Resp]) Execute(ctx context.Context, req *Req) error {
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"github.com/openimsdk/protocol/auth"
+	"github.com/openimsdk/protocol/conversation"
+	"github.com/openimsdk/protocol/group"
+	"github.com/openimsdk/protocol/msg"
+	"github.com/openimsdk/protocol/relation"
+	"github.com/openimsdk/protocol/third"
+	"github.com/openimsdk/protocol/user"
+)
+
+// grpcMethods are the grpc methods serving the api routes, with the same request and response messages.
+// The routes missing here are only served over http.
+var grpcMethods = map[string]string{
+	"/auth/parse_token": auth.Auth_ParseToken_FullMethodName,
+
+	"/user/get_users_info":               user.User_GetDesignateUsers_FullMethodName,
+	"/user/update_user_info":             user.User_UpdateUserInfo_FullMethodName,
+	"/user/update_user_info_ex":          user.User_UpdateUserInfoEx_FullMethodName,
+	"/user/user_register":                user.User_UserRegister_FullMethodName,
+	"/user/get_user_client_config":       user.User_GetUserClientConfig_FullMethodName,
+	"/user/process_user_command_add":     user.User_ProcessUserCommandAdd_FullMethodName,
+	"/user/process_user_command_update":  user.User_ProcessUserCommandUpdate_FullMethodName,
+	"/user/process_user_command_get_all": user.User_ProcessUserCommandGetAll_FullMethodName,
+
+	"/friend/add_friend":                     relation.Friend_ApplyToAddFriend_FullMethodName,
+	"/friend/delete_friend":                  relation.Friend_DeleteFriend_FullMethodName,
+	"/friend/get_friend_apply_list":          relation.Friend_GetPaginationFriendsApplyTo_FullMethodName,
+	"/friend/get_self_friend_apply_list":     relation.Friend_GetPaginationFriendsApplyFrom_FullMethodName,
+	"/friend/get_self_unhandled_apply_count": relation.Friend_GetSelfUnhandledApplyCount_FullMethodName,
+	"/friend/import_friend":                  relation.Friend_ImportFriends_FullMethodName,
+	"/friend/get_designated_friend_apply":    relation.Friend_GetDesignatedFriendsApply_FullMethodName,
+	"/friend/get_friend_list":                relation.Friend_GetPaginationFriends_FullMethodName,
+	"/friend/get_designated_friends":         relation.Friend_GetDesignatedFriends_FullMethodName,
+	"/friend/add_friend_response":            relation.Friend_RespondFriendApply_FullMethodName,
+	"/friend/update_friends":                 relation.Friend_UpdateFriends_FullMethodName,
+	"/friend/get_incremental_friends":        relation.Friend_GetIncrementalFriends_FullMethodName,
+	"/friend/get_full_friend_user_ids":       relation.Friend_GetFullFriendUserIDs_FullMethodName,
+	"/friend/add_black":                      relation.Friend_AddBlack_FullMethodName,
+	"/friend/remove_black":                   relation.Friend_RemoveBlack_FullMethodName,
+	"/friend/get_black_list":                 relation.Friend_GetPaginationBlacks_FullMethodName,
+
+	"/msg/clear_conversation_msg":                 msg.Msg_ClearConversationsMsg_FullMethodName,
+	"/msg/user_clear_all_msg":                     msg.Msg_UserClearAllMsg_FullMethodName,
+	"/msg/delete_msgs":                            msg.Msg_DeleteMsgs_FullMethodName,
+	"/msg/revoke_msg":                             msg.Msg_RevokeMsg_FullMethodName,
+	"/msg/mark_msgs_as_read":                      msg.Msg_MarkMsgsAsRead_FullMethodName,
+	"/msg/get_conversations_has_read_and_max_seq": msg.Msg_GetConversationsHasReadAndMaxSeq_FullMethodName,
+	"/msg/mark_conversation_as_read":              msg.Msg_MarkConversationAsRead_FullMethodName,
+	"/msg/set_conversation_has_read_seq":          msg.Msg_SetConversationHasReadSeq_FullMethodName,
+	"/msg/send_msg":                               msg.Msg_SendMsg_FullMethodName,
+	"/msg/get_server_time":                        msg.Msg_GetServerTime_FullMethodName,
+	"/msg/pull_msg_by_seq":                        msg.Msg_PullMessageBySeqs_FullMethodName,
+
+	"/group/create_group":                          group.Group_CreateGroup_FullMethodName,
+	"/group/set_group_info_ex":                     group.Group_SetGroupInfoEx_FullMethodName,
+	"/group/join_group":                            group.Group_JoinGroup_FullMethodName,
+	"/group/quit_group":                            group.Group_QuitGroup_FullMethodName,
+	"/group/get_groups_info":                       group.Group_GetGroupsInfo_FullMethodName,
+	"/group/get_group_member_list":                 group.Group_GetGroupMemberList_FullMethodName,
+	"/group/get_group_members_info":                group.Group_GetGroupMembersInfo_FullMethodName,
+	"/group/invite_user_to_group":                  group.Group_InviteUserToGroup_FullMethodName,
+	"/group/get_joined_group_list":                 group.Group_GetJoinedGroupList_FullMethodName,
+	"/group/kick_group":                            group.Group_KickGroupMember_FullMethodName,
+	"/group/transfer_group":                        group.Group_TransferGroupOwner_FullMethodName,
+	"/group/get_recv_group_applicationList":        group.Group_GetGroupApplicationList_FullMethodName,
+	"/group/get_user_req_group_applicationList":    group.Group_GetUserReqApplicationList_FullMethodName,
+	"/group/get_group_application_unhandled_count": group.Group_GetGroupApplicationUnhandledCount_FullMethodName,
+	"/group/group_application_response":            group.Group_GroupApplicationResponse_FullMethodName,
+	"/group/dismiss_group":                         group.Group_DismissGroup_FullMethodName,
+	"/group/mute_group_member":                     group.Group_MuteGroupMember_FullMethodName,
+	"/group/cancel_mute_group_member":              group.Group_CancelMuteGroupMember_FullMethodName,
+	"/group/mute_group":                            group.Group_MuteGroup_FullMethodName,
+	"/group/cancel_mute_group":                     group.Group_CancelMuteGroup_FullMethodName,
+	"/group/set_group_member_info":                 group.Group_SetGroupMemberInfo_FullMethodName,
+	"/group/get_incremental_join_groups":           group.Group_GetIncrementalJoinGroup_FullMethodName,
+	"/group/get_incremental_group_members_batch":   group.Group_BatchGetIncrementalGroupMember_FullMethodName,
+	"/group/get_full_join_group_ids":               group.Group_GetFullJoinGroupIDs_FullMethodName,
+	"/group/get_full_group_member_user_ids":        group.Group_GetFullGroupMemberUserIDs_FullMethodName,
+
+	"/conversation/get_conversations":             conversation.Conversation_GetConversations_FullMethodName,
+	"/conversation/get_all_conversations":         conversation.Conversation_GetAllConversations_FullMethodName,
+	"/conversation/set_conversations":             conversation.Conversation_SetConversations_FullMethodName,
+	"/conversation/get_incremental_conversations": conversation.Conversation_GetIncrementalConversation_FullMethodName,
+	"/conversation/get_full_conversation_ids":     conversation.Conversation_GetFullOwnerConversationIDs_FullMethodName,
+	"/conversation/get_owner_conversation":        conversation.Conversation_GetOwnerConversation_FullMethodName,
+
+	"/auth/get_admin_token": auth.Auth_GetAdminToken_FullMethodName,
+	"/auth/get_user_token":  auth.Auth_GetUserToken_FullMethodName,
+
+	"/third/fcm_update_token":           third.Third_FcmUpdateToken_FullMethodName,
+	"/third/set_app_badge":              third.Third_SetAppBadge_FullMethodName,
+	"/third/logs/upload":                third.Third_UploadLogs_FullMethodName,
+	"/object/part_limit":                third.Third_PartLimit_FullMethodName,
+	"/object/initiate_multipart_upload": third.Third_InitiateMultipartUpload_FullMethodName,
+	"/object/auth_sign":                 third.Third_AuthSign_FullMethodName,
+	"/object/complete_multipart_upload": third.Third_CompleteMultipartUpload_FullMethodName,
+	"/object/access_url":                third.Third_AccessURL_FullMethodName,
+}
+
+// grpcStreams are the server streaming methods returning the response of the api routes in parts, served
+// by the servers supporting them.
+var grpcStreams = map[string]string{
+	"/msg/pull_msg_by_seq": "/openim.msg.msg/PullMessageBySeqsStream",
+}
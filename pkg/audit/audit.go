@@ -0,0 +1,154 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package audit records the security-relevant actions of the login user done through the sdk in the local
+// database, for the regulated deployments which have to account for them: the exports of the local data, the
+// bulk deletions of messages, the edits of the group admins and the kicks of the device. The log is queried
+// locally and uploaded to the server when the config asks for it.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/api"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/ccontext"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/server_api_params"
+	"github.com/openimsdk/tools/utils/datautil"
+)
+
+// Actions are the kinds of actions recorded.
+const (
+	ActionExport        = "export"         // the local data was exported
+	ActionBulkDelete    = "bulk_delete"    // the messages of a conversation or of all of them were deleted
+	ActionAdminEdit     = "admin_edit"     // a group admin changed the group or its other members
+	ActionKickedOffline = "kicked_offline" // the device was kicked offline by another one
+)
+
+// uploadBatch is the logs uploaded in a request.
+const uploadBatch = 100
+
+// Store keeps the audit log, the local database.
+type Store interface {
+	InsertAuditLog(ctx context.Context, auditLog *model_struct.LocalAuditLog) error
+	GetAuditLogsToUpload(ctx context.Context, count int) ([]*model_struct.LocalAuditLog, error)
+	SetAuditLogsUploaded(ctx context.Context, ids []int64) error
+}
+
+type auditor struct {
+	store  Store
+	upload bool
+	// uploading is held by the upload running
+	uploading sync.Mutex
+}
+
+// auditors holds the auditor of each login user which enabled the audit log.
+var auditors sync.Map
+
+// Enable records the actions of the user in the store, and uploads them when upload is set.
+func Enable(userID string, store Store, upload bool) {
+	auditors.Store(userID, &auditor{store: store, upload: upload})
+}
+
+// Disable stops recording the actions of the user.
+func Disable(userID string) {
+	auditors.Delete(userID)
+}
+
+func get(ctx context.Context) *auditor {
+	a, ok := auditors.Load(ccontext.Info(ctx).UserID())
+	if !ok {
+		return nil
+	}
+	return a.(*auditor)
+}
+
+// Record records an action of the login user done by the operation of the sdk to the target, with its
+// arguments as the detail. It does nothing when the audit log is not enabled, a failed record is only logged.
+func Record(ctx context.Context, action, operation, target string, keysAndValues ...any) {
+	a := get(ctx)
+	if a == nil {
+		return
+	}
+	detail := make(map[string]any, len(keysAndValues)/2)
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		detail[fmt.Sprint(keysAndValues[i])] = keysAndValues[i+1]
+	}
+	data, err := json.Marshal(detail)
+	if err != nil {
+		log.ZWarn(ctx, "marshal audit detail failed", err, "action", action, "operation", operation)
+	}
+	auditLog := &model_struct.LocalAuditLog{
+		UserID:      ccontext.Info(ctx).UserID(),
+		OperationID: ccontext.Info(ctx).OperationID(),
+		Action:      action,
+		Operation:   operation,
+		Target:      target,
+		Detail:      string(data),
+		CreateTime:  time.Now().UnixMilli(),
+	}
+	if err := a.store.InsertAuditLog(ctx, auditLog); err != nil {
+		log.ZWarn(ctx, "record audit log failed", err, "auditLog", auditLog)
+		return
+	}
+	log.ZInfo(ctx, "audit", "action", action, "operation", operation, "target", target)
+	if a.upload {
+		go func() {
+			if err := a.uploadLogs(context.WithoutCancel(ctx)); err != nil {
+				log.ZWarn(ctx, "upload audit logs failed", err)
+			}
+		}()
+	}
+}
+
+// Upload uploads the logs not uploaded yet, those recorded while the server could not be reached. It does
+// nothing when the audit log is not uploaded.
+func Upload(ctx context.Context) error {
+	a := get(ctx)
+	if a == nil || !a.upload {
+		return nil
+	}
+	return a.uploadLogs(ctx)
+}
+
+func (a *auditor) uploadLogs(ctx context.Context) error {
+	if !a.uploading.TryLock() {
+		return nil
+	}
+	defer a.uploading.Unlock()
+	for {
+		auditLogs, err := a.store.GetAuditLogsToUpload(ctx, uploadBatch)
+		if err != nil {
+			return err
+		}
+		if len(auditLogs) == 0 {
+			return nil
+		}
+		if _, err := api.UploadAuditLogs.Invoke(ctx, &server_api_params.UploadAuditLogsReq{Logs: auditLogs}); err != nil {
+			return err
+		}
+		ids := datautil.Slice(auditLogs, func(auditLog *model_struct.LocalAuditLog) int64 { return auditLog.ID })
+		if err := a.store.SetAuditLogsUploaded(ctx, ids); err != nil {
+			return err
+		}
+		if len(auditLogs) < uploadBatch {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,189 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"time"
+)
+
+const (
+	manifestEntry = "manifest.json"
+	dbEntry       = "local.db"
+)
+
+// Manifest describes the database of a backup archive, it is checked before the database is restored.
+type Manifest struct {
+	UserID        string `json:"userID"`
+	BigVersion    string `json:"bigVersion"`
+	SDKVersion    string `json:"sdkVersion"`
+	SchemaVersion int    `json:"schemaVersion"`
+	// Encrypted is true when the database itself is encrypted with SQLCipher, it is then restored for the
+	// same database key only
+	Encrypted  bool   `json:"encrypted"`
+	DBSize     int64  `json:"dbSize"`
+	DBSHA256   string `json:"dbSha256"`
+	CreateTime int64  `json:"createTime"`
+}
+
+// Write writes the archive of the database file encrypted with the passphrase, the size and the hash of the
+// database are added to the manifest. The archive replaces the file at path only once complete.
+func Write(path, passphrase string, manifest *Manifest, dbFile string) error {
+	if passphrase == "" {
+		return errors.New("empty backup passphrase")
+	}
+	size, sum, err := fileHash(dbFile)
+	if err != nil {
+		return err
+	}
+	manifest.DBSize, manifest.DBSHA256, manifest.CreateTime = size, sum, time.Now().UnixMilli()
+	manifestData, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp)
+	if err := writeArchive(file, passphrase, manifestData, dbFile, size); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func writeArchive(w io.Writer, passphrase string, manifestData []byte, dbFile string, dbSize int64) error {
+	encrypted, err := newEncryptWriter(w, passphrase)
+	if err != nil {
+		return err
+	}
+	compressed := gzip.NewWriter(encrypted)
+	archive := tar.NewWriter(compressed)
+	if err := archive.WriteHeader(&tar.Header{Name: manifestEntry, Mode: 0600, Size: int64(len(manifestData))}); err != nil {
+		return err
+	}
+	if _, err := archive.Write(manifestData); err != nil {
+		return err
+	}
+	db, err := os.Open(dbFile)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	if err := archive.WriteHeader(&tar.Header{Name: dbEntry, Mode: 0600, Size: dbSize}); err != nil {
+		return err
+	}
+	if _, err := io.Copy(archive, db); err != nil {
+		return err
+	}
+	if err := archive.Close(); err != nil {
+		return err
+	}
+	if err := compressed.Close(); err != nil {
+		return err
+	}
+	return encrypted.Close()
+}
+
+// Read opens the archive with the passphrase, check decides from the manifest whether the database can be
+// restored before it is written to dbFile. The database is verified against the hash of the manifest, the
+// file is removed when it does not match.
+func Read(path, passphrase string, check func(manifest *Manifest) error, dbFile string) (*Manifest, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	decrypted, err := newDecryptReader(file, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	compressed, err := gzip.NewReader(decrypted)
+	if err != nil {
+		return nil, err
+	}
+	archive := tar.NewReader(compressed)
+
+	header, err := archive.Next()
+	if err != nil {
+		return nil, err
+	}
+	if header.Name != manifestEntry {
+		return nil, errors.New("backup archive without manifest")
+	}
+	var manifest Manifest
+	if err := json.NewDecoder(archive).Decode(&manifest); err != nil {
+		return nil, err
+	}
+	if err := check(&manifest); err != nil {
+		return nil, err
+	}
+
+	header, err = archive.Next()
+	if err != nil {
+		return nil, err
+	}
+	if header.Name != dbEntry {
+		return nil, errors.New("backup archive without database")
+	}
+	db, err := os.Create(dbFile)
+	if err != nil {
+		return nil, err
+	}
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(db, hash), archive)
+	if closeErr := db.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		// the rest of the archive is read for the checks of the compression and of the last chunk
+		_, err = io.Copy(io.Discard, compressed)
+	}
+	if err == nil && (size != manifest.DBSize || hex.EncodeToString(hash.Sum(nil)) != manifest.DBSHA256) {
+		err = errors.New("backup database does not match its manifest")
+	}
+	if err != nil {
+		_ = os.Remove(dbFile)
+		return nil, err
+	}
+	return &manifest, nil
+}
+
+func fileHash(path string) (int64, string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, "", err
+	}
+	defer file.Close()
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return 0, "", err
+	}
+	return size, hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,90 @@
+package backup
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestArchiveRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	dbFile := filepath.Join(dir, "src.db")
+	data := make([]byte, chunkSize+12345)
+	if _, err := rand.Read(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(dbFile, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	archive := filepath.Join(dir, "backup.oim")
+	if err := Write(archive, "secret", &Manifest{UserID: "u1", SchemaVersion: 3}, dbFile); err != nil {
+		t.Fatal(err)
+	}
+
+	restored := filepath.Join(dir, "dst.db")
+	manifest, err := Read(archive, "secret", func(m *Manifest) error { return nil }, restored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if manifest.UserID != "u1" || manifest.SchemaVersion != 3 {
+		t.Fatalf("unexpected manifest %+v", manifest)
+	}
+	got, err := os.ReadFile(restored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatal("restored database differs")
+	}
+
+	if _, err := Read(archive, "wrong", func(m *Manifest) error { return nil }, restored+"2"); !errors.Is(err, ErrWrongPassphrase) {
+		t.Fatalf("expected wrong passphrase, got %v", err)
+	}
+	incompatible := errors.New("incompatible")
+	if _, err := Read(archive, "secret", func(m *Manifest) error { return incompatible }, restored+"3"); !errors.Is(err, incompatible) {
+		t.Fatalf("expected the check error, got %v", err)
+	}
+	if _, err := os.Stat(restored + "3"); !os.IsNotExist(err) {
+		t.Fatal("database written although the check failed")
+	}
+}
+
+func TestArchiveTruncated(t *testing.T) {
+	dir := t.TempDir()
+	dbFile := filepath.Join(dir, "src.db")
+	if err := os.WriteFile(dbFile, bytes.Repeat([]byte("openim"), 1000), 0600); err != nil {
+		t.Fatal(err)
+	}
+	archive := filepath.Join(dir, "backup.oim")
+	if err := Write(archive, "secret", &Manifest{UserID: "u1"}, dbFile); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(archive, data[:len(data)-10], 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Read(archive, "secret", func(m *Manifest) error { return nil }, filepath.Join(dir, "dst.db")); err == nil {
+		t.Fatal("truncated archive restored")
+	}
+}
+
+func TestSealOpen(t *testing.T) {
+	data := []byte("identity keys")
+	sealed, err := Seal("secret", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := Open("secret", sealed)
+	if err != nil || !bytes.Equal(got, data) {
+		t.Fatal(string(got), err)
+	}
+	if _, err := Open("other", sealed); !errors.Is(err, ErrWrongPassphrase) {
+		t.Fatal("opened with a wrong passphrase", err)
+	}
+}
@@ -0,0 +1,255 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"hash"
+	"io"
+)
+
+// The archive is encrypted in chunks so that a large database never has to be held in memory. The header
+// holds the salt of the key and the nonce prefix, each chunk is sealed with AES-GCM under a nonce made of
+// the prefix, the index of the chunk and a flag set on the last chunk only, so that chunks can be neither
+// reordered nor dropped, nor the archive truncated, without the open failing.
+const (
+	magic          = "OIMBAK01"
+	saltSize       = 16
+	noncePrefixLen = 7
+	chunkSize      = 1 << 20
+	kdfIterations  = 200000
+	keySize        = 32
+)
+
+var ErrWrongPassphrase = errors.New("wrong passphrase or corrupted backup archive")
+
+// deriveKey is PBKDF2 with HMAC-SHA256.
+func deriveKey(passphrase, salt []byte, iterations int) []byte {
+	prf := hmac.New(sha256.New, passphrase)
+	var (
+		key   []byte
+		block uint32
+	)
+	for len(key) < keySize {
+		block++
+		key = append(key, pbkdf2Block(prf, salt, iterations, block)...)
+	}
+	return key[:keySize]
+}
+
+func pbkdf2Block(prf hash.Hash, salt []byte, iterations int, block uint32) []byte {
+	prf.Reset()
+	prf.Write(salt)
+	prf.Write(binary.BigEndian.AppendUint32(nil, block))
+	u := prf.Sum(nil)
+	t := append([]byte(nil), u...)
+	for i := 1; i < iterations; i++ {
+		prf.Reset()
+		prf.Write(u)
+		u = prf.Sum(u[:0])
+		for j := range t {
+			t[j] ^= u[j]
+		}
+	}
+	return t
+}
+
+func chunkNonce(prefix []byte, index uint32, last bool) []byte {
+	nonce := make([]byte, 0, noncePrefixLen+5)
+	nonce = append(nonce, prefix...)
+	nonce = binary.BigEndian.AppendUint32(nonce, index)
+	if last {
+		return append(nonce, 1)
+	}
+	return append(nonce, 0)
+}
+
+// encryptWriter seals what is written in chunks, Close seals the last one.
+type encryptWriter struct {
+	w      io.Writer
+	aead   cipher.AEAD
+	header []byte
+	prefix []byte
+	index  uint32
+	buf    []byte
+}
+
+func newEncryptWriter(w io.Writer, passphrase string) (*encryptWriter, error) {
+	salt := make([]byte, saltSize)
+	prefix := make([]byte, noncePrefixLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	if _, err := rand.Read(prefix); err != nil {
+		return nil, err
+	}
+	header := append([]byte(magic), salt...)
+	header = binary.BigEndian.AppendUint32(header, kdfIterations)
+	header = append(header, prefix...)
+	aead, err := newAEAD(deriveKey([]byte(passphrase), salt, kdfIterations))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(header); err != nil {
+		return nil, err
+	}
+	return &encryptWriter{w: w, aead: aead, header: header, prefix: prefix, buf: make([]byte, 0, chunkSize)}, nil
+}
+
+func (e *encryptWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		free := chunkSize - len(e.buf)
+		if free > len(p) {
+			free = len(p)
+		}
+		e.buf = append(e.buf, p[:free]...)
+		p = p[free:]
+		if len(e.buf) == chunkSize {
+			if err := e.seal(false); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return n, nil
+}
+
+func (e *encryptWriter) Close() error {
+	return e.seal(true)
+}
+
+func (e *encryptWriter) seal(last bool) error {
+	sealed := e.aead.Seal(nil, chunkNonce(e.prefix, e.index, last), e.buf, e.header)
+	if _, err := e.w.Write(binary.BigEndian.AppendUint32(nil, uint32(len(sealed)))); err != nil {
+		return err
+	}
+	if _, err := e.w.Write(sealed); err != nil {
+		return err
+	}
+	e.index++
+	e.buf = e.buf[:0]
+	return nil
+}
+
+// decryptReader opens the chunks written by encryptWriter, the end of the archive is only accepted after the
+// last chunk.
+type decryptReader struct {
+	r      *bufio.Reader
+	aead   cipher.AEAD
+	header []byte
+	prefix []byte
+	index  uint32
+	plain  []byte
+	done   bool
+}
+
+func newDecryptReader(r io.Reader, passphrase string) (*decryptReader, error) {
+	br := bufio.NewReader(r)
+	header := make([]byte, len(magic)+saltSize+4+noncePrefixLen)
+	if _, err := io.ReadFull(br, header); err != nil {
+		return nil, ErrWrongPassphrase
+	}
+	if string(header[:len(magic)]) != magic {
+		return nil, errors.New("not a backup archive")
+	}
+	salt := header[len(magic) : len(magic)+saltSize]
+	iterations := binary.BigEndian.Uint32(header[len(magic)+saltSize:])
+	aead, err := newAEAD(deriveKey([]byte(passphrase), salt, int(iterations)))
+	if err != nil {
+		return nil, err
+	}
+	return &decryptReader{r: br, aead: aead, header: header, prefix: header[len(header)-noncePrefixLen:]}, nil
+}
+
+func (d *decryptReader) Read(p []byte) (int, error) {
+	for len(d.plain) == 0 {
+		if d.done {
+			return 0, io.EOF
+		}
+		if err := d.open(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, d.plain)
+	d.plain = d.plain[n:]
+	return n, nil
+}
+
+func (d *decryptReader) open() error {
+	var size [4]byte
+	if _, err := io.ReadFull(d.r, size[:]); err != nil {
+		return ErrWrongPassphrase
+	}
+	n := binary.BigEndian.Uint32(size[:])
+	if n > chunkSize+uint32(d.aead.Overhead()) {
+		return ErrWrongPassphrase
+	}
+	sealed := make([]byte, n)
+	if _, err := io.ReadFull(d.r, sealed); err != nil {
+		return ErrWrongPassphrase
+	}
+	_, err := d.r.Peek(1)
+	last := errors.Is(err, io.EOF)
+	plain, err := d.aead.Open(sealed[:0], chunkNonce(d.prefix, d.index, last), sealed, d.header)
+	if err != nil {
+		return ErrWrongPassphrase
+	}
+	d.index++
+	d.plain = plain
+	d.done = last
+	return nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Seal encrypts data with the passphrase in the format of the archives, for the backups small enough to be
+// held in memory.
+func Seal(passphrase string, data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w, err := newEncryptWriter(&buf, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Open decrypts what Seal encrypted, ErrWrongPassphrase when the passphrase does not match.
+func Open(passphrase string, sealed []byte) ([]byte, error) {
+	r, err := newDecryptReader(bytes.NewReader(sealed), passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
@@ -2,22 +2,37 @@ package cache
 
 import "sync"
 
+// Store is the backend of a Cache, e.g. a cache shared by the processes of a desktop app. The values
+// stored are the ones loaded, a Store keeping them out of process decodes them again.
+type Store[K comparable, V any] interface {
+	Load(key K) (V, bool)
+	Store(key K, value V)
+	LoadOrStore(key K, value V) (V, bool)
+	Delete(key K)
+	// Range calls f for each key and value until f returns false.
+	Range(f func(key K, value V) bool)
+}
+
 func NewCache[K comparable, V any]() *Cache[K, V] {
-	return &Cache[K, V]{}
+	return &Cache[K, V]{m: &mapStore[K, V]{}}
+}
+
+// NewCacheWithStore returns a Cache keeping its values in the store, the same as NewCache when nil.
+func NewCacheWithStore[K comparable, V any](store Store[K, V]) *Cache[K, V] {
+	if store == nil {
+		return NewCache[K, V]()
+	}
+	return &Cache[K, V]{m: store}
 }
 
 // Cache is a Generic sync.Map structure.
 type Cache[K comparable, V any] struct {
-	m sync.Map
+	m Store[K, V]
 }
 
 // Load returns the value stored in the map for a key, or nil if no value is present.
 func (c *Cache[K, V]) Load(key K) (value V, ok bool) {
-	rawValue, ok := c.m.Load(key)
-	if !ok {
-		return
-	}
-	return rawValue.(V), ok
+	return c.m.Load(key)
 }
 
 // Store sets the value for a key.
@@ -41,8 +56,7 @@ func (c *Cache[K, V]) StoreAll(f func(value V) K, values []V) {
 
 // LoadOrStore returns the existing value for the key if present.
 func (c *Cache[K, V]) LoadOrStore(key K, value V) (actual V, loaded bool) {
-	rawValue, loaded := c.m.LoadOrStore(key, value)
-	return rawValue.(V), loaded
+	return c.m.LoadOrStore(key, value)
 }
 
 // Delete deletes the value for a key.
@@ -52,7 +66,7 @@ func (c *Cache[K, V]) Delete(key K) {
 
 // DeleteAll deletes all values.
 func (c *Cache[K, V]) DeleteAll() {
-	c.m.Range(func(key, value interface{}) bool {
+	c.m.Range(func(key K, value V) bool {
 		c.m.Delete(key)
 		return true
 	})
@@ -60,9 +74,9 @@ func (c *Cache[K, V]) DeleteAll() {
 
 // DeleteCon deletes the value for a key only if the provided condition function returns true.
 func (c *Cache[K, V]) DeleteCon(condition func(key K, value V) bool) {
-	c.m.Range(func(rawKey, rawValue interface{}) bool {
-		if condition(rawKey.(K), rawValue.(V)) {
-			c.m.Delete(rawKey)
+	c.m.Range(func(key K, value V) bool {
+		if condition(key, value) {
+			c.m.Delete(key)
 		}
 		return true // Continue iteration
 	})
@@ -70,8 +84,8 @@ func (c *Cache[K, V]) DeleteCon(condition func(key K, value V) bool) {
 
 // RangeAll returns all values in the map.
 func (c *Cache[K, V]) RangeAll() (values []V) {
-	c.m.Range(func(rawKey, rawValue interface{}) bool {
-		values = append(values, rawValue.(V))
+	c.m.Range(func(key K, value V) bool {
+		values = append(values, value)
 		return true
 	})
 	return values
@@ -79,11 +93,43 @@ func (c *Cache[K, V]) RangeAll() (values []V) {
 
 // RangeCon returns values in the map that satisfy condition f.
 func (c *Cache[K, V]) RangeCon(f func(key K, value V) bool) (values []V) {
-	c.m.Range(func(rawKey, rawValue interface{}) bool {
-		if f(rawKey.(K), rawValue.(V)) {
-			values = append(values, rawValue.(V))
+	c.m.Range(func(key K, value V) bool {
+		if f(key, value) {
+			values = append(values, value)
 		}
 		return true
 	})
 	return values
 }
+
+// mapStore is the Store of a Cache in memory.
+type mapStore[K comparable, V any] struct {
+	m sync.Map
+}
+
+func (s *mapStore[K, V]) Load(key K) (value V, ok bool) {
+	rawValue, ok := s.m.Load(key)
+	if !ok {
+		return
+	}
+	return rawValue.(V), ok
+}
+
+func (s *mapStore[K, V]) Store(key K, value V) {
+	s.m.Store(key, value)
+}
+
+func (s *mapStore[K, V]) LoadOrStore(key K, value V) (V, bool) {
+	rawValue, loaded := s.m.LoadOrStore(key, value)
+	return rawValue.(V), loaded
+}
+
+func (s *mapStore[K, V]) Delete(key K) {
+	s.m.Delete(key)
+}
+
+func (s *mapStore[K, V]) Range(f func(key K, value V) bool) {
+	s.m.Range(func(rawKey, rawValue any) bool {
+		return f(rawKey.(K), rawValue.(V))
+	})
+}
@@ -0,0 +1,54 @@
+package cache
+
+import (
+	"sync"
+
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+// meteredStore is a Store counting its hits, misses and evictions which can be trimmed, as the ones of
+// NewTTLStore.
+type meteredStore interface {
+	Stats() *sdk_struct.CacheStats
+	Trim(keep float64) int
+}
+
+var registry = struct {
+	lock   sync.Mutex
+	stores map[string]meteredStore
+}{stores: make(map[string]meteredStore)}
+
+// Register names the store of a cache for GetStats and Trim, it replaces the store registered with the name.
+// The stores which do not count their usage, e.g. the ones set by the app, are left out.
+func Register[K comparable, V any](name string, store Store[K, V]) {
+	registry.lock.Lock()
+	defer registry.lock.Unlock()
+	if metered, ok := store.(meteredStore); ok {
+		registry.stores[name] = metered
+	} else {
+		delete(registry.stores, name)
+	}
+}
+
+// GetStats returns the stats of the stores registered by their names.
+func GetStats() map[string]*sdk_struct.CacheStats {
+	registry.lock.Lock()
+	defer registry.lock.Unlock()
+	stats := make(map[string]*sdk_struct.CacheStats, len(registry.stores))
+	for name, store := range registry.stores {
+		stats[name] = store.Stats()
+	}
+	return stats
+}
+
+// Trim removes the expired entries of the stores registered, then their least recently used ones until at
+// most keep of the entries of each store are left. Returns the number of the entries removed.
+func Trim(keep float64) int {
+	registry.lock.Lock()
+	defer registry.lock.Unlock()
+	var n int
+	for _, store := range registry.stores {
+		n += store.Trim(keep)
+	}
+	return n
+}
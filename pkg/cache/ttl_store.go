@@ -0,0 +1,182 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+// The bounds of the in-memory caches of the user info and of the group members.
+const (
+	DefaultInfoTTL      = 10 * time.Minute
+	DefaultInfoCapacity = 10000
+)
+
+// NewTTLStore returns a Store in memory whose values expire ttl after they were stored, and which evicts the
+// least recently used values beyond capacity. 0 leaves the values without expiry or the store unbounded.
+func NewTTLStore[K comparable, V any](ttl time.Duration, capacity int) Store[K, V] {
+	return &ttlStore[K, V]{
+		ttl:      ttl,
+		capacity: capacity,
+		now:      time.Now,
+		items:    make(map[K]*list.Element),
+		lru:      list.New(),
+	}
+}
+
+type ttlEntry[K comparable, V any] struct {
+	key    K
+	value  V
+	expire time.Time
+}
+
+// ttlStore keeps the entries in a list, the most recently used first.
+type ttlStore[K comparable, V any] struct {
+	lock     sync.Mutex
+	ttl      time.Duration
+	capacity int
+	now      func() time.Time
+	items    map[K]*list.Element
+	lru      *list.List
+	// the counters of Stats
+	hits, misses, evictions, expired int64
+}
+
+// get returns the entry of the key unless it expired, in which case it is removed. Called with the lock.
+func (s *ttlStore[K, V]) get(key K) (*ttlEntry[K, V], bool) {
+	elem, ok := s.items[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*ttlEntry[K, V])
+	if s.ttl > 0 && !s.now().Before(entry.expire) {
+		s.remove(elem)
+		s.expired++
+		return nil, false
+	}
+	s.lru.MoveToFront(elem)
+	return entry, true
+}
+
+// set stores the value of the key and evicts the least recently used entries beyond the capacity. Called with
+// the lock.
+func (s *ttlStore[K, V]) set(key K, value V) {
+	var expire time.Time
+	if s.ttl > 0 {
+		expire = s.now().Add(s.ttl)
+	}
+	if elem, ok := s.items[key]; ok {
+		entry := elem.Value.(*ttlEntry[K, V])
+		entry.value, entry.expire = value, expire
+		s.lru.MoveToFront(elem)
+		return
+	}
+	s.items[key] = s.lru.PushFront(&ttlEntry[K, V]{key: key, value: value, expire: expire})
+	for s.capacity > 0 && s.lru.Len() > s.capacity {
+		s.remove(s.lru.Back())
+		s.evictions++
+	}
+}
+
+func (s *ttlStore[K, V]) remove(elem *list.Element) {
+	s.lru.Remove(elem)
+	delete(s.items, elem.Value.(*ttlEntry[K, V]).key)
+}
+
+func (s *ttlStore[K, V]) Load(key K) (value V, ok bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	entry, ok := s.get(key)
+	if !ok {
+		s.misses++
+		return value, false
+	}
+	s.hits++
+	return entry.value, true
+}
+
+func (s *ttlStore[K, V]) Store(key K, value V) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.set(key, value)
+}
+
+func (s *ttlStore[K, V]) LoadOrStore(key K, value V) (V, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if entry, ok := s.get(key); ok {
+		s.hits++
+		return entry.value, true
+	}
+	s.misses++
+	s.set(key, value)
+	return value, false
+}
+
+func (s *ttlStore[K, V]) Delete(key K) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if elem, ok := s.items[key]; ok {
+		s.remove(elem)
+	}
+}
+
+// Range calls f on a snapshot of the entries not expired, f may change the store.
+func (s *ttlStore[K, V]) Range(f func(key K, value V) bool) {
+	s.lock.Lock()
+	now := s.now()
+	entries := make([]ttlEntry[K, V], 0, s.lru.Len())
+	for elem := s.lru.Front(); elem != nil; elem = elem.Next() {
+		entry := elem.Value.(*ttlEntry[K, V])
+		if s.ttl > 0 && !now.Before(entry.expire) {
+			continue
+		}
+		entries = append(entries, *entry)
+	}
+	s.lock.Unlock()
+	for _, entry := range entries {
+		if !f(entry.key, entry.value) {
+			return
+		}
+	}
+}
+
+func (s *ttlStore[K, V]) Stats() *sdk_struct.CacheStats {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return &sdk_struct.CacheStats{
+		Size:      s.lru.Len(),
+		Capacity:  s.capacity,
+		Hits:      s.hits,
+		Misses:    s.misses,
+		Evictions: s.evictions,
+		Expired:   s.expired,
+	}
+}
+
+// Trim removes the expired entries, then the least recently used ones until at most keep of the entries are
+// left, 0 removes them all. Returns the number of the entries removed.
+func (s *ttlStore[K, V]) Trim(keep float64) int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	size := s.lru.Len()
+	if s.ttl > 0 {
+		now := s.now()
+		for elem := s.lru.Front(); elem != nil; {
+			next := elem.Next()
+			if !now.Before(elem.Value.(*ttlEntry[K, V]).expire) {
+				s.remove(elem)
+				s.expired++
+			}
+			elem = next
+		}
+	}
+	left := int(float64(size) * max(keep, 0))
+	for s.lru.Len() > left {
+		s.remove(s.lru.Back())
+		s.evictions++
+	}
+	return size - s.lru.Len()
+}
@@ -0,0 +1,97 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTTLStore(t *testing.T) {
+	now := time.Unix(0, 0)
+	s := NewTTLStore[string, int](time.Minute, 2).(*ttlStore[string, int])
+	s.now = func() time.Time { return now }
+	s.Store("a", 1)
+	s.Store("b", 2)
+	if _, ok := s.Load("a"); !ok {
+		t.Fatal("a is missing")
+	}
+	// b is the least recently used
+	s.Store("c", 3)
+	if _, ok := s.Load("b"); ok {
+		t.Fatal("b is not evicted")
+	}
+	if v, loaded := s.LoadOrStore("a", 10); !loaded || v != 1 {
+		t.Fatal(v, loaded)
+	}
+	now = now.Add(time.Minute)
+	if _, ok := s.Load("a"); ok {
+		t.Fatal("a is not expired")
+	}
+	var n int
+	s.Range(func(key string, value int) bool {
+		n++
+		return true
+	})
+	if n != 0 {
+		t.Fatal(n)
+	}
+	c := NewCacheWithStore[string, int](NewTTLStore[string, int](0, 0))
+	c.Store("x:1", 1)
+	c.Store("y:1", 2)
+	c.DeleteCon(func(key string, value int) bool { return key[0] == 'x' })
+	if _, ok := c.Load("x:1"); ok {
+		t.Fatal("x:1 is not deleted")
+	}
+}
+
+func TestTTLStoreStats(t *testing.T) {
+	now := time.Unix(0, 0)
+	s := NewTTLStore[string, int](time.Minute, 3).(*ttlStore[string, int])
+	s.now = func() time.Time { return now }
+	for i, key := range []string{"a", "b", "c", "d"} {
+		s.Store(key, i)
+	}
+	s.Load("a")
+	s.Load("b")
+	s.LoadOrStore("e", 4)
+	stats := s.Stats()
+	if stats.Size != 3 || stats.Hits != 1 || stats.Misses != 2 || stats.Evictions != 2 {
+		t.Fatalf("%+v", stats)
+	}
+	now = now.Add(30 * time.Second)
+	// f evicts d
+	s.Store("f", 5)
+	now = now.Add(30 * time.Second)
+	// b and e are expired
+	if n := s.Trim(0.5); n != 2 {
+		t.Fatal(n)
+	}
+	s.Store("g", 6)
+	s.Store("h", 7)
+	// f and g are the least recently used beyond the half kept
+	if n := s.Trim(0.5); n != 2 {
+		t.Fatal(n)
+	}
+	if _, ok := s.Load("h"); !ok {
+		t.Fatal("h is trimmed")
+	}
+	if stats = s.Stats(); stats.Expired != 2 || stats.Evictions != 5 {
+		t.Fatalf("%+v", stats)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	s := NewTTLStore[string, int](0, 0)
+	Register("test", s)
+	s.Store("a", 1)
+	s.Store("b", 2)
+	if stats := GetStats()["test"]; stats == nil || stats.Size != 2 {
+		t.Fatal(stats)
+	}
+	if n := Trim(0); n != 2 {
+		t.Fatal(n)
+	}
+	Register[string, int]("test", &mapStore[string, int]{})
+	if _, ok := GetStats()["test"]; ok {
+		t.Fatal("a store without stats is registered")
+	}
+}
@@ -17,9 +17,10 @@ func NewUserCache[K comparable, V any](
 	batchDBFunc func(ctx context.Context, keys []K) ([]V, error),
 	singleDBFunc func(ctx context.Context, keys K) (V, error),
 	queryFunc func(ctx context.Context, keys []K) ([]V, error),
+	store Store[K, V],
 ) *UserCache[K, V] {
 	return &UserCache[K, V]{
-		Cache:        NewCache[K, V](),
+		Cache:        NewCacheWithStore[K, V](store),
 		getKeyFunc:   getKeyFunc,
 		batchDBFunc:  batchDBFunc,
 		singleDBFunc: singleDBFunc,
@@ -45,7 +46,7 @@ func (m *UserCache[K, V]) BatchFetch(ctx context.Context, keys []K) (map[K]V, er
 		if data, ok := m.Load(key); ok {
 			res[key] = data
 		} else {
-			queryKeys = append(queryKeys, keys...)
+			queryKeys = append(queryKeys, key)
 		}
 	}
 
@@ -133,6 +134,14 @@ func (m *UserCache[K, V]) fetch(ctx context.Context, key K) (V, error) {
 	return writeData, nil
 }
 
+// Invalidate drops the values of the keys, both as users and as special users, for their change notifications.
+func (m *UserCache[K, V]) Invalidate(keys ...K) {
+	for _, key := range keys {
+		m.Delete(key)
+		m.Delete(m.specialUserKey(key))
+	}
+}
+
 func (m *UserCache[K, V]) specialUserKey(key K) K {
 	return any(fmt.Sprintf("%s%v", SpecialUserPrefix, key)).(K)
 }
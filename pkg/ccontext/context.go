@@ -16,6 +16,8 @@ package ccontext
 
 import (
 	"context"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
@@ -36,9 +38,40 @@ const (
 
 type GlobalConfig struct {
 	UserID string
-	Token  string
 
-	*sdk_struct.IMConfig
+	// the token is refreshed and the config is replaced by UpdateConfig while the requests read them
+	token  atomic.Pointer[string]
+	config atomic.Pointer[sdk_struct.IMConfig]
+	// states are the states the packages keep for the user context, by their key
+	states sync.Map
+}
+
+func NewGlobalConfig(userID, token string, config *sdk_struct.IMConfig) *GlobalConfig {
+	conf := &GlobalConfig{UserID: userID}
+	conf.SetToken(token)
+	conf.config.Store(config)
+	return conf
+}
+
+func (g *GlobalConfig) Token() string {
+	if token := g.token.Load(); token != nil {
+		return *token
+	}
+	return ""
+}
+
+func (g *GlobalConfig) SetToken(token string) {
+	g.token.Store(&token)
+}
+
+// Config returns the current config, nil before InitSDK. The config returned is never changed, a change
+// stores a copy by SetConfig.
+func (g *GlobalConfig) Config() *sdk_struct.IMConfig {
+	return g.config.Load()
+}
+
+func (g *GlobalConfig) SetConfig(config *sdk_struct.IMConfig) {
+	g.config.Store(config)
 }
 
 type ContextInfo interface {
@@ -47,8 +80,13 @@ type ContextInfo interface {
 	PlatformID() int32
 	ApiAddr() string
 	WsAddr() string
+	QuicAddr() string
+	Transport() string
+	LongPollingFallback() bool
+	Compression() string
 	DataDir() string
 	LogLevel() uint32
+	Proxy() *sdk_struct.ProxyConfig
 	OperationID() string
 }
 
@@ -60,6 +98,30 @@ func Info(ctx context.Context) ContextInfo {
 	}
 }
 
+// processStates are the states of the contexts of no user context.
+var processStates sync.Map
+
+// State returns the state a package keeps under the key for the user context of ctx, so that the user contexts
+// of a process don't share it, created by newState the first time. The contexts of no user context share the
+// state of the process.
+func State[T any](ctx context.Context, key any, newState func() *T) *T {
+	states := &processStates
+	if conf, ok := ctx.Value(GlobalConfigKey{}).(*GlobalConfig); ok {
+		states = &conf.states
+	}
+	if state, ok := states.Load(key); ok {
+		return state.(*T)
+	}
+	state, _ := states.LoadOrStore(key, newState())
+	return state.(*T)
+}
+
+// HasInfo reports whether the context is of a user context, Info panics otherwise.
+func HasInfo(ctx context.Context) bool {
+	_, ok := ctx.Value(GlobalConfigKey{}).(*GlobalConfig)
+	return ok
+}
+
 func WithInfo(ctx context.Context, conf *GlobalConfig) context.Context {
 	return context.WithValue(ctx, GlobalConfigKey{}, conf)
 }
@@ -95,27 +157,47 @@ func (i *info) UserID() string {
 }
 
 func (i *info) Token() string {
-	return i.conf.Token
+	return i.conf.Token()
 }
 
 func (i *info) PlatformID() int32 {
-	return i.conf.PlatformID
+	return i.conf.Config().PlatformID
 }
 
 func (i *info) ApiAddr() string {
-	return i.conf.ApiAddr
+	return i.conf.Config().ApiAddr
 }
 
 func (i *info) WsAddr() string {
-	return i.conf.WsAddr
+	return i.conf.Config().WsAddr
+}
+
+func (i *info) QuicAddr() string {
+	return i.conf.Config().QuicAddr
+}
+
+func (i *info) Transport() string {
+	return i.conf.Config().Transport
+}
+
+func (i *info) LongPollingFallback() bool {
+	return i.conf.Config().LongPollingFallback
+}
+
+func (i *info) Compression() string {
+	return i.conf.Config().Compression
 }
 
 func (i *info) DataDir() string {
-	return i.conf.DataDir
+	return i.conf.Config().DataDir
 }
 
 func (i *info) LogLevel() uint32 {
-	return i.conf.LogLevel
+	return i.conf.Config().LogLevel
+}
+
+func (i *info) Proxy() *sdk_struct.ProxyConfig {
+	return i.conf.Config().Proxy
 }
 
 func (i *info) OperationID() string {
@@ -159,3 +241,18 @@ func GetSendOrderInfo(ctx context.Context) (*SendOrderInfo, bool) {
 	info, ok := ctx.Value(sendOrderKey{}).(*SendOrderInfo)
 	return info, ok
 }
+
+type sendMsgOptionsKey struct{}
+
+// WithSendMsgOptions carries the options of one message sent to the send task.
+func WithSendMsgOptions(ctx context.Context, options *sdk_struct.SendMsgOptions) context.Context {
+	if options == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, sendMsgOptionsKey{}, options)
+}
+
+func GetSendMsgOptions(ctx context.Context) (*sdk_struct.SendMsgOptions, bool) {
+	options, ok := ctx.Value(sendMsgOptionsKey{}).(*sdk_struct.SendMsgOptions)
+	return options, ok
+}
@@ -17,7 +17,10 @@ package ccontext
 import (
 	"context"
 	"fmt"
+	"sync"
 	"testing"
+
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
 )
 
 func TestName(t *testing.T) {
@@ -39,3 +42,26 @@ func TestName(t *testing.T) {
 		t.Fatal("OperationID not match")
 	}
 }
+
+func TestSetConfig(t *testing.T) {
+	conf := NewGlobalConfig("uid123", "", &sdk_struct.IMConfig{ApiAddr: "http://a"})
+	ctx := WithInfo(context.Background(), conf)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			_ = Info(ctx).ApiAddr()
+			_ = Info(ctx).Token()
+		}
+	}()
+	conf.SetConfig(&sdk_struct.IMConfig{ApiAddr: "http://b"})
+	conf.SetToken("token2")
+	wg.Wait()
+	if addr := Info(ctx).ApiAddr(); addr != "http://b" {
+		t.Fatal("the contexts made before read the config set after", addr)
+	}
+	if token := Info(ctx).Token(); token != "token2" {
+		t.Fatal("the contexts made before read the token refreshed after", token)
+	}
+}
@@ -2,27 +2,72 @@ package cliconf
 
 import (
 	"context"
+	"maps"
+	"slices"
 	"strconv"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/api"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
 	"github.com/openimsdk/protocol/user"
-	"github.com/openimsdk/tools/log"
 )
 
 const (
 	ccConversationActiveNumKey = "CONVERSATION_ACTIVE_NUM"
+	ccRevokeWindowKey          = "MSG_REVOKE_WINDOW"
+	ccEnabledContentTypesKey   = "ENABLED_CONTENT_TYPES"
+	ccMaxImageSizeKey          = "MAX_IMAGE_SIZE"
+	ccMaxVideoSizeKey          = "MAX_VIDEO_SIZE"
+	ccMaxFileSizeKey           = "MAX_FILE_SIZE"
 )
 
 const (
 	ccConversationActiveNumDefault = 50
 )
 
+// ClientConfig is the config the server defines for the clients, it tunes the behavior of the sdk without a
+// release of the app. The flags the server does not set keep their defaults.
 type ClientConfig struct {
-	ConversationActiveNum int
-	RawConfig             map[string]string
+	ConversationActiveNum int `json:"conversationActiveNum"`
+	// RevokeWindow is the seconds a message can be revoked by its sender after it was sent, 0 for no limit
+	RevokeWindow int64 `json:"revokeWindow"`
+	// EnabledContentTypes are the content types that can be sent, all of them when empty
+	EnabledContentTypes []int32 `json:"enabledContentTypes"`
+	// MaxImageSize, MaxVideoSize and MaxFileSize are the bytes of the media that can be sent, 0 for no limit
+	MaxImageSize int64             `json:"maxImageSize"`
+	MaxVideoSize int64             `json:"maxVideoSize"`
+	MaxFileSize  int64             `json:"maxFileSize"`
+	RawConfig    map[string]string `json:"rawConfig"`
+}
+
+// ContentTypeEnabled reports whether messages of the content type can be sent.
+func (c *ClientConfig) ContentTypeEnabled(contentType int32) bool {
+	return len(c.EnabledContentTypes) == 0 || slices.Contains(c.EnabledContentTypes, contentType)
+}
+
+// MaxMediaSize is the bytes of the media of the content type that can be sent, 0 for no limit.
+func (c *ClientConfig) MaxMediaSize(contentType int32) int64 {
+	switch contentType {
+	case constant.Picture:
+		return c.MaxImageSize
+	case constant.Video:
+		return c.MaxVideoSize
+	case constant.File:
+		return c.MaxFileSize
+	default:
+		return 0
+	}
+}
+
+// Cache keeps the last config fetched, it is used while the server can't be reached.
+type Cache interface {
+	GetClientConfig(ctx context.Context) (map[string]string, error)
+	SetClientConfig(ctx context.Context, configs map[string]string) error
 }
 
 type currentClientConfig struct {
@@ -34,6 +79,12 @@ type currentClientConfig struct {
 type clientConfig struct {
 	userID string
 	config atomic.Pointer[currentClientConfig]
+	// last is the last config known, from the server or the cache
+	last atomic.Pointer[ClientConfig]
+
+	mu        sync.Mutex
+	cache     Cache
+	onChanged func(ctx context.Context, config *ClientConfig)
 }
 
 func (c *clientConfig) parseServerUserConfig(configs map[string]string) (*ClientConfig, error) {
@@ -42,10 +93,85 @@ func (c *clientConfig) parseServerUserConfig(configs map[string]string) (*Client
 	if config.ConversationActiveNum <= 0 {
 		config.ConversationActiveNum = ccConversationActiveNumDefault
 	}
+	config.RevokeWindow = parseNonNegative(configs[ccRevokeWindowKey])
+	for _, s := range strings.Split(configs[ccEnabledContentTypesKey], ",") {
+		if contentType, err := strconv.ParseInt(strings.TrimSpace(s), 10, 32); err == nil {
+			config.EnabledContentTypes = append(config.EnabledContentTypes, int32(contentType))
+		}
+	}
+	config.MaxImageSize = parseNonNegative(configs[ccMaxImageSizeKey])
+	config.MaxVideoSize = parseNonNegative(configs[ccMaxVideoSizeKey])
+	config.MaxFileSize = parseNonNegative(configs[ccMaxFileSizeKey])
 	config.RawConfig = configs
 	return &config, nil
 }
 
+// parseSize parses a non negative number of the config, the missing and invalid ones are 0.
+func parseNonNegative(s string) int64 {
+	n, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+func (c *clientConfig) setCache(ctx context.Context, cache Cache) {
+	c.mu.Lock()
+	c.cache = cache
+	c.mu.Unlock()
+	if cache == nil {
+		return
+	}
+	configs, err := cache.GetClientConfig(ctx)
+	if err != nil {
+		log.ZWarn(ctx, "get cached config failed", err)
+		return
+	}
+	if len(configs) == 0 {
+		return
+	}
+	config, _ := c.parseServerUserConfig(configs)
+	c.last.CompareAndSwap(nil, config)
+}
+
+func (c *clientConfig) setChangedListener(fn func(ctx context.Context, config *ClientConfig)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onChanged = fn
+}
+
+// update keeps the config fetched, it is cached and the listener is called when it is not the last one known.
+func (c *clientConfig) update(ctx context.Context, config *ClientConfig) {
+	var prev map[string]string
+	if last := c.last.Swap(config); last != nil {
+		prev = last.RawConfig
+	}
+	if maps.Equal(prev, config.RawConfig) {
+		return
+	}
+	c.mu.Lock()
+	cache, onChanged := c.cache, c.onChanged
+	c.mu.Unlock()
+	if cache != nil {
+		if err := cache.SetClientConfig(ctx, config.RawConfig); err != nil {
+			log.ZWarn(ctx, "cache config failed", err)
+		}
+	}
+	log.ZInfo(ctx, "client config changed", "config", config.RawConfig)
+	if onChanged != nil {
+		onChanged(ctx, config)
+	}
+}
+
+// latest is the last config known without waiting for the server, the defaults when there is none.
+func (c *clientConfig) latest() *ClientConfig {
+	if last := c.last.Load(); last != nil {
+		return last
+	}
+	config, _ := c.parseServerUserConfig(nil)
+	return config
+}
+
 func (c *clientConfig) getServerUserConfig(ctx context.Context) (*ClientConfig, error) {
 	configs, err := api.ExtractField(ctx, api.UserClientConfig.Invoke, &user.GetUserClientConfigReq{UserID: c.userID}, (*user.GetUserClientConfigResp).GetConfigs)
 	if err != nil {
@@ -57,23 +183,29 @@ func (c *clientConfig) getServerUserConfig(ctx context.Context) (*ClientConfig,
 func (c *clientConfig) asyncGetConfig(ctx context.Context, curr *currentClientConfig) {
 	ctx, cancel := context.WithTimeout(ctx, time.Second*10)
 	defer cancel()
-	for {
-		configs, err := c.getServerUserConfig(ctx)
-		if c.config.Load() != curr {
-			log.ZDebug(ctx, "get config end, but call clear config", "config", configs, "error", err)
-			continue
-		}
-		if err != nil {
-			curr.err = err
-			close(curr.wait)
+	configs, err := c.getServerUserConfig(ctx)
+	if c.config.Load() != curr {
+		// cleared while it was fetched, the waiters get it but it is not kept
+		log.ZDebug(ctx, "get config end, but call clear config", "config", configs, "error", err)
+		curr.config, curr.err = configs, err
+		close(curr.wait)
+		return
+	}
+	if err != nil {
+		if last := c.last.Load(); last != nil {
+			log.ZWarn(ctx, "get config failed, use the last one", err)
+			curr.config = last
+		} else {
 			log.ZWarn(ctx, "get config failed", err)
-			return
+			curr.err = err
 		}
-		curr.config = configs
 		close(curr.wait)
-		log.ZDebug(ctx, "get config success", "config", configs)
 		return
 	}
+	c.update(ctx, configs)
+	curr.config = configs
+	close(curr.wait)
+	log.ZDebug(ctx, "get config success", "config", configs)
 }
 
 func (c *clientConfig) getCurrConfig(ctx context.Context) (*currentClientConfig, error) {
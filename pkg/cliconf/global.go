@@ -1,18 +1,60 @@
 package cliconf
 
-import "context"
+import (
+	"context"
+	"sync/atomic"
 
-var globalClientConfig = &clientConfig{}
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/ccontext"
+)
 
-func SetLoginUserID(userID string) {
-	globalClientConfig.userID = userID
-	globalClientConfig.ClearConfig()
+type clientConfigKey struct{}
+
+// clientConfigOf holds the config of the login user of the user context of ctx, so user contexts do not share
+// it, even logged in with the same user.
+func clientConfigOf(ctx context.Context) *atomic.Pointer[clientConfig] {
+	return ccontext.State(ctx, clientConfigKey{}, func() *atomic.Pointer[clientConfig] {
+		var c atomic.Pointer[clientConfig]
+		c.Store(&clientConfig{})
+		return &c
+	})
+}
+
+func contextClientConfig(ctx context.Context) *clientConfig {
+	return clientConfigOf(ctx).Load()
 }
 
-func ClearConfig() {
-	globalClientConfig.ClearConfig()
+// SetLoginUserID starts the config of the user context of ctx over for the user about to log in.
+func SetLoginUserID(ctx context.Context, userID string) {
+	clientConfigOf(ctx).Store(&clientConfig{userID: userID})
+}
+
+func ClearConfig(ctx context.Context) {
+	contextClientConfig(ctx).ClearConfig()
 }
 
 func GetClientConfig(ctx context.Context) (*ClientConfig, error) {
-	return globalClientConfig.GetConfig(ctx)
+	return contextClientConfig(ctx).GetConfig(ctx)
+}
+
+// RefreshClientConfig fetches the config from the server again, when the server pushed that it changed.
+func RefreshClientConfig(ctx context.Context) (*ClientConfig, error) {
+	c := contextClientConfig(ctx)
+	c.ClearConfig()
+	return c.GetConfig(ctx)
+}
+
+// LatestClientConfig is the last config known of the login user without waiting for the server, the cached one
+// before it is fetched and the defaults when none was ever fetched.
+func LatestClientConfig(ctx context.Context) *ClientConfig {
+	return contextClientConfig(ctx).latest()
+}
+
+// SetCache sets the cache of the config of the login user and loads the config cached.
+func SetCache(ctx context.Context, cache Cache) {
+	contextClientConfig(ctx).setCache(ctx, cache)
+}
+
+// SetChangedListener sets the function called when the config fetched differs from the last one known.
+func SetChangedListener(ctx context.Context, fn func(ctx context.Context, config *ClientConfig)) {
+	contextClientConfig(ctx).setChangedListener(fn)
 }
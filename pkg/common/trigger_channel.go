@@ -19,13 +19,13 @@ import (
 	"errors"
 	"fmt"
 	"runtime"
-	"runtime/debug"
 	"time"
 
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/crash"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
 	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
 	"github.com/openimsdk/protocol/sdkws"
-	"github.com/openimsdk/tools/log"
 )
 
 const timeout = time.Second * 10
@@ -91,6 +91,18 @@ func DispatchWakeUp(ctx context.Context, queue chan Cmd2Value) error {
 	return DispatchCmd(ctx, constant.CmdWakeUpDataSync, nil, queue)
 }
 
+func DispatchCatchUpSync(ctx context.Context, queue chan Cmd2Value) error {
+	return DispatchCmd(ctx, constant.CmdCatchUpSync, nil, queue)
+}
+
+func DispatchResyncMsgs(ctx context.Context, queue chan Cmd2Value) error {
+	return DispatchCmd(ctx, constant.CmdResyncMsgs, nil, queue)
+}
+
+func DispatchBackfill(ctx context.Context, queue chan Cmd2Value) error {
+	return DispatchCmd(ctx, constant.CmdBackfill, nil, queue)
+}
+
 func DispatchIMSync(ctx context.Context, conversationIDs []string, queue chan Cmd2Value) error {
 	return DispatchCmd(ctx, constant.CmdIMMessageSync, conversationIDs, queue)
 }
@@ -172,12 +184,7 @@ type goroutine interface {
 }
 
 func DoListener(ctx context.Context, li goroutine) {
-	defer func() {
-		if r := recover(); r != nil {
-			err := fmt.Sprintf("panic: %+v\n%s", r, debug.Stack())
-			log.ZWarn(ctx, "DoListener panic", nil, "panic info", err)
-		}
-	}()
+	defer crash.Recover(ctx, fmt.Sprintf("DoListener %T", li))
 
 	for {
 		select {
@@ -27,6 +27,9 @@ const (
 	CmdConnSuccesss   = "connSuccess"
 	CmdWakeUpDataSync = "wakeUpDataSync"
 	CmdIMMessageSync  = "imMessageSync"
+	CmdCatchUpSync    = "catchUpSync"
+	CmdResyncMsgs     = "resyncMsgs"
+	CmdBackfill       = "backfill"
 	CmdLogOut         = "loginOut"
 )
 
@@ -49,6 +52,18 @@ const (
 	MarkdownText                    = 118
 	CustomMsgNotTriggerConversation = 119
 	CustomMsgOnlineOnly             = 120
+	// E2EEMessage is a message encrypted end to end, its content is the envelope of the message
+	E2EEMessage = 130
+	// Signaling is a signal of a call, sent online only and never stored
+	Signaling = 131
+	// Call is the message of a call over, inserted in the conversation by each side
+	Call = 132
+	// SignalingData is a payload of the custom signaling channel of the app, sent online only and never stored
+	SignalingData = 133
+	// InteractiveCard is a card of a bot with buttons and menus, the bot edits it in place after the actions
+	InteractiveCard = 134
+	// SatisfactionRating is the invitation of an agent to rate a customer-service session, or the rating
+	SatisfactionRating = 135
 
 	NotificationBegin = 1000
 
@@ -73,6 +88,8 @@ const (
 	UserCommandAddNotification    = 1305
 	UserCommandDeleteNotification = 1306
 	UserCommandUpdateNotification = 1307
+	// ClientConfigChangedNotification is sent to the users when the server changed the client config
+	ClientConfigChangedNotification = 1310
 
 	UserNotificationEnd = 1399
 
@@ -103,14 +120,39 @@ const (
 	ConversationPrivateChatNotification = 1701
 	ClearConversationNotification       = 1703
 
+	MomentNotificationBegin     = 1800
+	MomentPublishedNotification = 1801 // a friend published a moment
+	MomentDeletedNotification   = 1802
+	MomentLikedNotification     = 1803 // a moment of the login user or one the login user commented was liked
+	MomentCommentedNotification = 1804
+	MomentNotificationEnd       = 1899
+
+	OrganizationNotificationBegin = 1900
+	// OrganizationChangedNotification is sent to the members of the organization when its departments or
+	// their members changed, the changes are then synced incrementally
+	OrganizationChangedNotification = 1901
+	OrganizationNotificationEnd     = 1999
+
 	BusinessNotification = 2001
 
 	RevokeNotification = 2101
 
 	DeleteMsgsNotification = 2102
 
+	// InteractiveCardUpdatedNotification carries the new version of an interactive card edited by its bot
+	InteractiveCardUpdatedNotification = 2103
+
 	HasReadReceipt = 2200
 
+	CustomerServiceNotificationBegin = 2300
+	// CustomerServiceQueueNotification is sent to a customer waiting for an agent when the queue moved
+	CustomerServiceQueueNotification = 2301
+	// CustomerServiceAssignedNotification is sent to the customer and the agent a session was assigned to
+	CustomerServiceAssignedNotification    = 2302
+	CustomerServiceTransferredNotification = 2303
+	CustomerServiceClosedNotification      = 2304
+	CustomerServiceNotificationEnd         = 2399
+
 	NotificationEnd = 5000
 	////////////////////////////////////////
 
@@ -257,3 +299,292 @@ const (
 const (
 	Uninitialized = -1001
 )
+
+// user command types stored on the server for the login user
+const (
+	UserCommandTypePrivacy = 101
+
+	UserCommandPrivacyUUID = "privacy"
+)
+
+const (
+	// AddFriendPermission
+	AddFriendAllowAll      = 0
+	AddFriendNeedVerify    = 1
+	AddFriendRejectAll     = 2
+	AddFriendOnlyGroupMate = 3
+
+	// ProfileVisibility
+	ProfileVisibleToAll     = 0
+	ProfileVisibleToFriends = 1
+	ProfileVisibleToNone    = 2
+)
+
+// UserLastSeen status
+const (
+	LastSeenUnknown  = 0
+	LastSeenOnline   = 1
+	LastSeenRecently = 2
+	LastSeenLongAgo  = 3
+	LastSeenHidden   = 4
+)
+
+// QR code login
+const (
+	QRLoginPayloadPrefix = "openim-qrlogin:"
+
+	QRLoginStateWaiting   = 0
+	QRLoginStateScanned   = 1
+	QRLoginStateConfirmed = 2
+	QRLoginStateRejected  = 3
+	QRLoginStateExpired   = 4
+	QRLoginStateCanceled  = 5
+)
+
+// Long connection states
+const (
+	ConnStateConnecting     = "connecting"
+	ConnStateDNSResolving   = "dnsResolving"
+	ConnStateTLSHandshake   = "tlsHandshake"
+	ConnStateAuthenticating = "authenticating"
+	ConnStateSyncing        = "syncing"
+	ConnStateConnected      = "connected"
+	// ConnStateDegraded the connection is up but the round trip time is over the threshold
+	ConnStateDegraded           = "degraded"
+	ConnStateDisconnected       = "disconnected"
+	ConnStateReconnectScheduled = "reconnectScheduled"
+	// ConnStateFailed the reconnect attempts are exhausted, the SDK waits for ForceReconnect
+	ConnStateFailed = "failed"
+)
+
+// Compression of the long connection and the api responses
+const (
+	// CompressionGzip compresses every long connection message with gzip
+	CompressionGzip = "gzip"
+	// CompressionDeflate negotiates the websocket permessage-deflate extension
+	CompressionDeflate = "deflate"
+	CompressionNone    = "none"
+)
+
+// Bandwidth categories, the traffic of each one is limited by its own limit and the global one
+const (
+	// BandwidthSync is the traffic of the long connection and the api calls
+	BandwidthSync     = "sync"
+	BandwidthUpload   = "upload"
+	BandwidthDownload = "download"
+)
+
+// Transport of the api calls
+const (
+	ApiTransportHTTP = "http"
+	// ApiTransportGRPC calls the services over grpc when the server supports it, and over http otherwise
+	ApiTransportGRPC = "grpc"
+)
+
+// Phases of the initial sync reported to the sync progress listener
+const (
+	SyncPhaseConversations = "conversations"
+	SyncPhaseFriends       = "friends"
+	SyncPhaseGroups        = "groups"
+	SyncPhaseGroupMembers  = "groupMembers"
+	SyncPhaseMessages      = "messages"
+)
+
+// Scopes of the local data ForceResyncAll pulls again from the server
+const (
+	ResyncScopeConversations = "conversations"
+	ResyncScopeFriends       = "friends"
+	ResyncScopeGroups        = "groups"
+	ResyncScopeMessages      = "messages"
+)
+
+// Modes of LogoutWithMode, what is left of the local data of the user after the logout
+const (
+	// LogoutModeKeepData keeps all the local data for a fast login again, the mode of Logout
+	LogoutModeKeepData = "keepData"
+	// LogoutModeWipeMessages removes the messages and keeps the conversations, the friends and the groups
+	LogoutModeWipeMessages = "wipeMessages"
+	// LogoutModeSecureWipe overwrites and removes the local database and the media cache
+	LogoutModeSecureWipe = "secureWipe"
+)
+
+// The states of a customer-service session
+const (
+	// CustomerServiceWaiting is a session in the queue for an agent
+	CustomerServiceWaiting = "waiting"
+	CustomerServiceServing = "serving"
+	CustomerServiceClosed  = "closed"
+)
+
+// MaxSatisfactionRating is the best rating of a customer-service session, the worst is 1
+const MaxSatisfactionRating = 5
+
+// The elements of an interactive card
+const (
+	InteractiveElementButton = "button"
+	InteractiveElementMenu   = "menu"
+)
+
+// The media of a moment
+const (
+	MomentMediaImage = "image"
+	MomentMediaVideo = "video"
+)
+
+// Who sees a moment besides its publisher
+const (
+	MomentVisibleFriends = 0
+	MomentVisiblePrivate = 1
+	// MomentVisibleUsers is a moment seen by the users it lists only
+	MomentVisibleUsers = 2
+)
+
+// Types of the end-to-end encryption keys of the login user kept in the database
+const (
+	E2EEKeyIdentity      = 1
+	E2EEKeySignedPreKey  = 2
+	E2EEKeyOneTimePreKey = 3
+)
+
+// What is done with a message sent with a sensitive word
+const (
+	// SensitiveWordBlock fails the send
+	SensitiveWordBlock = "block"
+	// SensitiveWordReplace masks the words before the message is stored and sent
+	SensitiveWordReplace = "replace"
+	// SensitiveWordWarn sends the message as it is, the words are listed in its attached info
+	SensitiveWordWarn = "warn"
+)
+
+// The verdict of the moderator on a message
+const (
+	// ModerationPass sends or stores the message as it is
+	ModerationPass = "pass"
+	// ModerationBlock fails the send, a message received is stored without its text
+	ModerationBlock = "block"
+	// ModerationModify replaces the message with the one of the verdict
+	ModerationModify = "modify"
+	// ModerationFlag sends or stores the message as it is, the verdict is kept in its attached info
+	ModerationFlag = "flag"
+)
+
+// Verification of the signature of a received message
+const (
+	MsgSignatureNone  = 0 // the message is not signed
+	MsgSignatureValid = 1
+	// MsgSignatureInvalid is a message changed after it was signed, by the server or on the way
+	MsgSignatureInvalid = 2
+	// MsgSignatureUnknownDevice is a message signed by a device the sender does not list
+	MsgSignatureUnknownDevice = 3
+)
+
+// Classes of the api calls, each one has its own timeout and retries
+const (
+	RequestClassSend    = "send"
+	RequestClassQuery   = "query"
+	RequestClassHistory = "history"
+	// RequestClassUpload is the object storage calls, its timeout also applies to each uploaded part
+	RequestClassUpload = "upload"
+)
+
+// Classes of the network the device is on, hinted by the app
+const (
+	NetworkClassWifi     = "wifi"
+	NetworkClassCellular = "cellular"
+	// NetworkClassMetered is any other network charged by the data, e.g. a hotspot
+	NetworkClassMetered = "metered"
+)
+
+// Transfers deferred on a cellular or metered network until allowed
+const (
+	// MeteredBackfill is the messages of the seq gaps older than the latest page
+	MeteredBackfill = "backfill"
+	// MeteredMediaPrefetch is the media downloaded before the user opens it
+	MeteredMediaPrefetch = "mediaPrefetch"
+)
+
+// Levels of TrimMemory, the memory warnings of the platforms map to them
+const (
+	// TrimMemoryModerate removes the expired entries of the caches and half of the others
+	TrimMemoryModerate = 1
+	// TrimMemoryCritical removes all the entries of the caches and returns the memory freed to the system
+	TrimMemoryCritical = 2
+)
+
+// States of the downloads of the download manager
+const (
+	DownloadStateQueued      = "queued"
+	DownloadStateDownloading = "downloading"
+	DownloadStatePaused      = "paused"
+	DownloadStateCompleted   = "completed"
+	DownloadStateFailed      = "failed"
+	// DownloadStateCorrupted the file saved didn't match its md5, again once saved from the start
+	DownloadStateCorrupted = "corrupted"
+	DownloadStateCanceled  = "canceled"
+)
+
+// Reasons the media files are removed, reported to the media cache listener
+const (
+	// MediaEvictReasonLimit the least recently used files beyond MediaCacheLimit
+	MediaEvictReasonLimit = "limit"
+	// MediaEvictReasonQuota the oldest files beyond StorageQuota
+	MediaEvictReasonQuota = "quota"
+	// MediaEvictReasonClear the files removed by ClearMediaCache
+	MediaEvictReasonClear = "clear"
+)
+
+// Kinds of the conflicts between the local and the server state
+const (
+	// ConflictKindMessageRevoke the server revokes a message the app changed locally by its local ex
+	ConflictKindMessageRevoke = "messageRevoke"
+	// ConflictKindMessageSendState a message not sent locally, sending or failed, was sent on the server
+	ConflictKindMessageSendState = "messageSendState"
+)
+
+// Sides kept for a conflict
+const (
+	ConflictKeepServer = "server"
+	ConflictKeepLocal  = "local"
+)
+
+// Providers of the offline push tokens
+const (
+	PushProviderFCM   = "fcm"
+	PushProviderAPNs  = "apns"
+	PushProviderGeTui = "getui"
+	PushProviderJPush = "jpush"
+)
+
+// The actions of the call signaling
+const (
+	SignalingInvite  = "invite"
+	SignalingCancel  = "cancel"
+	SignalingAccept  = "accept"
+	SignalingReject  = "reject"
+	SignalingHungUp  = "hungUp"
+	SignalingTimeout = "timeout"
+	// SignalingJoin is a member joining a group call without answering an invitation
+	SignalingJoin = "join"
+	// SignalingRoster is the participants of a group call, sent to the group when someone joined
+	SignalingRoster = "roster"
+	// SignalingBusy is the answer of an invitee already in a call
+	SignalingBusy = "busy"
+)
+
+// The media of a call
+const (
+	CallMediaAudio = "audio"
+	CallMediaVideo = "video"
+)
+
+// How a call ended, the state of its message
+const (
+	// CallCompleted is a call answered then hung up
+	CallCompleted = "completed"
+	CallCancelled = "cancelled"
+	CallRejected  = "rejected"
+	// CallTimeout is a call not answered in time
+	CallTimeout = "timeout"
+	// CallBusy is a call the invitee was in another call for
+	CallBusy = "busy"
+)
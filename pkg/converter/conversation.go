@@ -5,11 +5,11 @@ import (
 
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
 	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
 	"github.com/openimsdk/protocol/sdkws"
 	"github.com/openimsdk/tools/errs"
-	"github.com/openimsdk/tools/log"
 
 	pbConversation "github.com/openimsdk/protocol/conversation"
 )
@@ -190,6 +190,18 @@ func PopulateMsgStructByContentType(msg *sdk_struct.MsgStruct) (err error) {
 		elem := sdk_struct.MarkdownTextElem{}
 		err = utils.JsonStringToStruct(msg.Content, &elem)
 		msg.MarkdownTextElem = &elem
+	case constant.Call:
+		elem := sdk_struct.CallElem{}
+		err = utils.JsonStringToStruct(msg.Content, &elem)
+		msg.CallElem = &elem
+	case constant.InteractiveCard:
+		elem := sdk_struct.InteractiveCardElem{}
+		err = utils.JsonStringToStruct(msg.Content, &elem)
+		msg.InteractiveCardElem = &elem
+	case constant.SatisfactionRating:
+		elem := sdk_struct.SatisfactionRatingElem{}
+		err = utils.JsonStringToStruct(msg.Content, &elem)
+		msg.SatisfactionRatingElem = &elem
 	default:
 		elem := sdk_struct.NotificationElem{}
 		err = utils.JsonStringToStruct(msg.Content, &elem)
@@ -260,6 +272,12 @@ func MsgStructToLocalChatLog(message *sdk_struct.MsgStruct) *model_struct.LocalC
 		local.Content = utils.StructToJsonString(message.AdvancedTextElem)
 	case constant.MarkdownText:
 		local.Content = utils.StructToJsonString(message.MarkdownTextElem)
+	case constant.Call:
+		local.Content = utils.StructToJsonString(message.CallElem)
+	case constant.InteractiveCard:
+		local.Content = utils.StructToJsonString(message.InteractiveCardElem)
+	case constant.SatisfactionRating:
+		local.Content = utils.StructToJsonString(message.SatisfactionRatingElem)
 	default:
 		local.Content = utils.StructToJsonString(message.NotificationElem)
 	}
@@ -0,0 +1,269 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package crash records the panics recovered in the goroutines of the SDK, with the stack, the tail of the
+// log and the state of the SDK, so that the app lists and uploads them on the next launch.
+package crash
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/version"
+)
+
+const (
+	// maxReports is the number of crash reports kept, the oldest are removed beyond it
+	maxReports = 20
+	// logTailLines is the number of the last lines of the log kept in a report
+	logTailLines = 200
+	// logTailBytes is the most bytes of the end of the log read for its tail
+	logTailBytes = 256 * 1024
+	// stateTimeout bounds the time the state of the SDK is collected in, a panic may hold the locks it needs
+	stateTimeout = time.Second
+	reportSuffix = ".crash.json"
+	logPrefix    = "open-im-sdk-core"
+)
+
+var (
+	lock   sync.Mutex
+	dir    string
+	logDir string
+	state  func() map[string]any
+)
+
+// SetDir sets the directory the reports are saved to, no report is saved while it is empty.
+func SetDir(reportDir string) {
+	lock.Lock()
+	defer lock.Unlock()
+	dir = reportDir
+}
+
+// SetLogDir sets the directory of the log files the tail is read from.
+func SetLogDir(logFilePath string) {
+	lock.Lock()
+	defer lock.Unlock()
+	logDir = logFilePath
+}
+
+// SetState sets the function collecting the state of the SDK kept in a report.
+func SetState(fn func() map[string]any) {
+	lock.Lock()
+	defer lock.Unlock()
+	state = fn
+}
+
+// Recover recovers the panic of the goroutine and records it, deferred at the top of the goroutine.
+func Recover(ctx context.Context, goroutine string) {
+	if r := recover(); r != nil {
+		Capture(ctx, goroutine, r, debug.Stack())
+	}
+}
+
+// Capture records a panic recovered by the caller, which handles it further.
+func Capture(ctx context.Context, goroutine string, r any, stack []byte) {
+	log.ZError(ctx, goroutine+" panic", nil, "panic", fmt.Sprint(r), "stack", string(stack))
+	lock.Lock()
+	reportDir, logFilePath, stateFn := dir, logDir, state
+	lock.Unlock()
+	if reportDir == "" {
+		return
+	}
+	now := time.Now()
+	report := &sdk_struct.CrashReport{
+		ID:        fmt.Sprintf("%d_%s", now.UnixMilli(), utils.Md5(fmt.Sprint(r, goroutine, now.UnixNano()))[:8]),
+		Time:      now.UnixMilli(),
+		Goroutine: goroutine,
+		Panic:     fmt.Sprint(r),
+		Stack:     string(stack),
+		Version:   version.Version,
+		LogTail:   logTail(logFilePath, logTailLines),
+		State:     collectState(stateFn),
+	}
+	if err := save(reportDir, report); err != nil {
+		log.ZWarn(ctx, "save crash report failed", err, "dir", reportDir)
+	}
+}
+
+func collectState(fn func() map[string]any) map[string]any {
+	if fn == nil {
+		return nil
+	}
+	ch := make(chan map[string]any, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				ch <- map[string]any{"error": fmt.Sprint("collect state panic: ", r)}
+			}
+		}()
+		ch <- fn()
+	}()
+	select {
+	case s := <-ch:
+		return s
+	case <-time.After(stateTimeout):
+		return map[string]any{"error": "collect state timeout"}
+	}
+}
+
+func save(reportDir string, report *sdk_struct.CrashReport) error {
+	if err := os.MkdirAll(reportDir, 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	tmp := filepath.Join(reportDir, report.ID+reportSuffix+".tmp")
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, filepath.Join(reportDir, report.ID+reportSuffix)); err != nil {
+		return err
+	}
+	paths := reportPaths(reportDir)
+	for len(paths) > maxReports {
+		_ = os.Remove(paths[0])
+		paths = paths[1:]
+	}
+	return nil
+}
+
+// reportPaths is the paths of the reports saved, the oldest first.
+func reportPaths(reportDir string) []string {
+	entries, err := os.ReadDir(reportDir)
+	if err != nil {
+		return nil
+	}
+	var paths []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), reportSuffix) {
+			paths = append(paths, filepath.Join(reportDir, entry.Name()))
+		}
+	}
+	// the ids start with the time of the report
+	sort.Strings(paths)
+	return paths
+}
+
+// List is the reports saved, the oldest first.
+func List() ([]*sdk_struct.CrashReport, error) {
+	lock.Lock()
+	reportDir := dir
+	lock.Unlock()
+	reports := make([]*sdk_struct.CrashReport, 0)
+	for _, path := range reportPaths(reportDir) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var report sdk_struct.CrashReport
+		if err := json.Unmarshal(data, &report); err != nil {
+			// a report half written is dropped
+			_ = os.Remove(path)
+			continue
+		}
+		reports = append(reports, &report)
+	}
+	return reports, nil
+}
+
+// Paths is the files of the reports saved, the oldest first.
+func Paths() []string {
+	lock.Lock()
+	reportDir := dir
+	lock.Unlock()
+	return reportPaths(reportDir)
+}
+
+// Delete removes the reports of the ids.
+func Delete(ids []string) error {
+	lock.Lock()
+	reportDir := dir
+	lock.Unlock()
+	for _, id := range ids {
+		if id == "" || filepath.Base(id) != id {
+			continue
+		}
+		if err := os.Remove(filepath.Join(reportDir, id+reportSuffix)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReportID is the id of the report of the file.
+func ReportID(path string) string {
+	return strings.TrimSuffix(filepath.Base(path), reportSuffix)
+}
+
+// logTail is the last lines of the newest log file.
+func logTail(logFilePath string, n int) []string {
+	if logFilePath == "" {
+		return nil
+	}
+	entries, err := os.ReadDir(logFilePath)
+	if err != nil {
+		return nil
+	}
+	var newest string
+	for _, entry := range entries {
+		// the file being written of a day is its part 0, the names end with the date, the newest is the greatest
+		if entry.IsDir() {
+			continue
+		}
+		if _, part, ok := log.ParseFileName(logPrefix, entry.Name()); ok && part == 0 && entry.Name() > newest {
+			newest = entry.Name()
+		}
+	}
+	if newest == "" {
+		return nil
+	}
+	file, err := os.Open(filepath.Join(logFilePath, newest))
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+	stat, err := file.Stat()
+	if err != nil {
+		return nil
+	}
+	offset := max(stat.Size()-logTailBytes, 0)
+	data := make([]byte, stat.Size()-offset)
+	if _, err := file.ReadAt(data, offset); err != nil && !errors.Is(err, io.EOF) {
+		return nil
+	}
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if offset > 0 {
+		// the first line is cut
+		lines = lines[1:]
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines
+}
@@ -0,0 +1,68 @@
+package crash
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestRecover(t *testing.T) {
+	dir := t.TempDir()
+	logFilePath := t.TempDir()
+	var lines []string
+	for i := 0; i < 300; i++ {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	if err := os.WriteFile(filepath.Join(logFilePath, "open-im-sdk-core.2024-05-06"), []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	SetDir(dir)
+	SetLogDir(logFilePath)
+	SetState(func() map[string]any { return map[string]any{"loginStatus": 3} })
+	defer SetDir("")
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer Recover(context.Background(), "worker")
+		var m map[string]int
+		m["x"] = 1
+	}()
+	wg.Wait()
+
+	reports, err := List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 1 {
+		t.Fatal("reports", len(reports))
+	}
+	report := reports[0]
+	if report.Goroutine != "worker" || !strings.Contains(report.Panic, "nil map") || !strings.Contains(report.Stack, "TestRecover") {
+		t.Fatal("report", report.Goroutine, report.Panic)
+	}
+	if len(report.LogTail) != logTailLines || report.LogTail[len(report.LogTail)-1] != "line 299" {
+		t.Fatal("log tail", len(report.LogTail))
+	}
+	if report.State["loginStatus"] != float64(3) {
+		t.Fatal("state", report.State)
+	}
+
+	for i := 0; i < maxReports+5; i++ {
+		Capture(context.Background(), "worker", i, nil)
+	}
+	if paths := Paths(); len(paths) != maxReports {
+		t.Fatal("reports kept", len(paths))
+	}
+	if err := Delete([]string{ReportID(Paths()[0])}); err != nil {
+		t.Fatal(err)
+	}
+	if paths := Paths(); len(paths) != maxReports-1 {
+		t.Fatal("reports after delete", len(paths))
+	}
+}
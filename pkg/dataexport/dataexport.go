@@ -0,0 +1,225 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package dataexport writes the archive of the personal data of a user, for the data portability requests.
+// The archive is a zip file of JSON documents:
+//
+//	manifest.json                    the format and its version, the user and the number of entries
+//	profile.json                     the user info and the privacy settings
+//	friends.json                     the friends
+//	blacks.json                      the users blocked
+//	groups.json                      the groups joined
+//	conversations.json               the conversations
+//	messages/<conversationID>.jsonl  the messages of the conversation, a message per line, the oldest first
+//	media.jsonl                      the media of the messages, an entry per line with its url, size and name
+//
+// The media files are not in the archive, they are downloaded from the urls of the media entries.
+package dataexport
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+const (
+	// Format names the archive in its manifest.
+	Format = "openim-personal-data"
+	// Version is the version of the format, increased when an entry changes incompatibly.
+	Version = 1
+
+	ManifestEntry      = "manifest.json"
+	ProfileEntry       = "profile.json"
+	FriendsEntry       = "friends.json"
+	BlacksEntry        = "blacks.json"
+	GroupsEntry        = "groups.json"
+	ConversationsEntry = "conversations.json"
+	MediaEntry         = "media.jsonl"
+	messagesDir        = "messages/"
+)
+
+// Manifest describes the archive, it is written once all the other entries are.
+type Manifest struct {
+	Format        string `json:"format"`
+	Version       int    `json:"version"`
+	UserID        string `json:"userID"`
+	SDKVersion    string `json:"sdkVersion"`
+	Conversations int    `json:"conversations"`
+	Messages      int64  `json:"messages"`
+	Media         int64  `json:"media"`
+	CreateTime    int64  `json:"createTime"`
+}
+
+// Media is a media file of a message.
+type Media struct {
+	ConversationID string `json:"conversationID"`
+	ClientMsgID    string `json:"clientMsgID"`
+	ContentType    int32  `json:"contentType"`
+	SendTime       int64  `json:"sendTime"`
+	URL            string `json:"url"`
+	Size           int64  `json:"size"`
+	Name           string `json:"name,omitempty"`
+}
+
+// MessagesEntry is the entry of the messages of the conversation.
+func MessagesEntry(conversationID string) string {
+	return messagesDir + conversationID + ".jsonl"
+}
+
+// MediaOf returns the media file of the message, nil for a message without one.
+func MediaOf(conversationID string, msg *sdk_struct.MsgStruct) *Media {
+	media := &Media{ConversationID: conversationID, ClientMsgID: msg.ClientMsgID, ContentType: msg.ContentType, SendTime: msg.SendTime}
+	switch {
+	case msg.ContentType == constant.Picture && msg.PictureElem != nil && msg.PictureElem.SourcePicture != nil:
+		media.URL, media.Size = msg.PictureElem.SourcePicture.Url, msg.PictureElem.SourcePicture.Size
+	case msg.ContentType == constant.Sound && msg.SoundElem != nil:
+		media.URL, media.Size = msg.SoundElem.SourceURL, msg.SoundElem.DataSize
+	case msg.ContentType == constant.Video && msg.VideoElem != nil:
+		media.URL, media.Size = msg.VideoElem.VideoURL, msg.VideoElem.VideoSize
+	case msg.ContentType == constant.File && msg.FileElem != nil:
+		media.URL, media.Size, media.Name = msg.FileElem.SourceURL, msg.FileElem.FileSize, msg.FileElem.FileName
+	}
+	if media.URL == "" {
+		return nil
+	}
+	return media
+}
+
+// Writer writes an archive, the entries are streamed to it one after the other. The archive replaces the file
+// at its path once closed, nothing is left behind when it is aborted.
+type Writer struct {
+	path  string
+	file  *os.File
+	zw    *zip.Writer
+	media *os.File
+	// mediaLines buffers the media entries written to the temporary media file
+	mediaLines *bufio.Writer
+	mediaCount int64
+}
+
+// Create starts the archive at path.
+func Create(path string) (*Writer, error) {
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return nil, err
+	}
+	media, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".media.*.tmp")
+	if err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return nil, err
+	}
+	return &Writer{path: path, file: file, zw: zip.NewWriter(file), media: media, mediaLines: bufio.NewWriter(media)}, nil
+}
+
+// WriteJSON writes the value as the entry.
+func (w *Writer) WriteJSON(name string, v any) error {
+	entry, err := w.zw.Create(name)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(entry)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// LineWriter writes the values of an entry, a JSON value per line.
+type LineWriter struct {
+	encoder *json.Encoder
+}
+
+func (l *LineWriter) Write(v any) error {
+	return l.encoder.Encode(v)
+}
+
+// CreateLines starts the entry of JSON lines, the LineWriter is valid until the next entry is started.
+func (w *Writer) CreateLines(name string) (*LineWriter, error) {
+	entry, err := w.zw.Create(name)
+	if err != nil {
+		return nil, err
+	}
+	return &LineWriter{encoder: json.NewEncoder(entry)}, nil
+}
+
+// AddMedia adds an entry to the media entry, written when the archive is closed.
+func (w *Writer) AddMedia(media *Media) error {
+	data, err := json.Marshal(media)
+	if err != nil {
+		return err
+	}
+	if _, err := w.mediaLines.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	w.mediaCount++
+	return nil
+}
+
+// Close writes the media entry and the manifest, and moves the archive to its path.
+func (w *Writer) Close(manifest *Manifest) error {
+	if err := w.closeEntries(manifest); err != nil {
+		w.Abort()
+		return err
+	}
+	w.removeMedia()
+	if err := os.Rename(w.file.Name(), w.path); err != nil {
+		os.Remove(w.file.Name())
+		return err
+	}
+	return nil
+}
+
+func (w *Writer) closeEntries(manifest *Manifest) error {
+	if err := w.mediaLines.Flush(); err != nil {
+		return err
+	}
+	if _, err := w.media.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	entry, err := w.zw.Create(MediaEntry)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(entry, w.media); err != nil {
+		return err
+	}
+	manifest.Format, manifest.Version = Format, Version
+	manifest.Media, manifest.CreateTime = w.mediaCount, time.Now().UnixMilli()
+	if err := w.WriteJSON(ManifestEntry, manifest); err != nil {
+		return err
+	}
+	if err := w.zw.Close(); err != nil {
+		return err
+	}
+	return w.file.Close()
+}
+
+// Abort drops the archive.
+func (w *Writer) Abort() {
+	w.zw.Close()
+	w.file.Close()
+	os.Remove(w.file.Name())
+	w.removeMedia()
+}
+
+func (w *Writer) removeMedia() {
+	w.media.Close()
+	os.Remove(w.media.Name())
+}
@@ -0,0 +1,106 @@
+package dataexport
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+func TestWriter(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "export.zip")
+	w, err := Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteJSON(FriendsEntry, []string{"u2"}); err != nil {
+		t.Fatal(err)
+	}
+	lines, err := w.CreateLines(MessagesEntry("si_u1_u2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	msgs := []*sdk_struct.MsgStruct{
+		{ClientMsgID: "m1", ContentType: constant.Text, TextElem: &sdk_struct.TextElem{Content: "hi"}},
+		{ClientMsgID: "m2", ContentType: constant.File, FileElem: &sdk_struct.FileElem{SourceURL: "https://x/f", FileName: "f.pdf", FileSize: 7}},
+	}
+	for _, msg := range msgs {
+		if err := lines.Write(msg); err != nil {
+			t.Fatal(err)
+		}
+		if media := MediaOf("si_u1_u2", msg); media != nil {
+			if err := w.AddMedia(media); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := w.Close(&Manifest{UserID: "u1", Messages: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Fatalf("temporary files left %v", entries)
+	}
+
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	entries := make(map[string]*zip.File)
+	for _, f := range r.File {
+		entries[f.Name] = f
+	}
+	var manifest Manifest
+	readJSON(t, entries[ManifestEntry], &manifest)
+	if manifest.Format != Format || manifest.Version != Version || manifest.Media != 1 || manifest.Messages != 2 {
+		t.Fatalf("unexpected manifest %+v", manifest)
+	}
+	if n := countLines(t, entries[MessagesEntry("si_u1_u2")]); n != 2 {
+		t.Fatalf("%d messages", n)
+	}
+	if n := countLines(t, entries[MediaEntry]); n != 1 {
+		t.Fatalf("%d media", n)
+	}
+}
+
+func TestWriterAbort(t *testing.T) {
+	dir := t.TempDir()
+	w, err := Create(filepath.Join(dir, "export.zip"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Abort()
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Fatalf("files left %v", entries)
+	}
+}
+
+func readJSON(t *testing.T, f *zip.File, v any) {
+	rc, err := f.Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+	if err := json.NewDecoder(rc).Decode(v); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func countLines(t *testing.T, f *zip.File) int {
+	rc, err := f.Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+	var n int
+	for scanner := bufio.NewScanner(rc); scanner.Scan(); {
+		n++
+	}
+	return n
+}
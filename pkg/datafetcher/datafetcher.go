@@ -214,3 +214,52 @@ func (ds *DataFetcher[T]) FetchMissingAndFillLocalV2(ctx context.Context, uids [
 
 	return localData, isEnd, nil
 }
+
+// PageAfter is a page read by FetchAfter.
+type PageAfter[T any] struct {
+	Data    []T
+	UIDs    []string // the UIDs read
+	Start   int      // the UID list index of the first UID read
+	HasMore bool
+}
+
+// After returns the UID list position following the entry of uid, the UID and the index to resume
+// a FetchAfter from to read past it. An empty uid stands for the last UID read.
+func (p *PageAfter[T]) After(uid string) (string, int) {
+	i := len(p.UIDs) - 1
+	if uid != "" {
+		i = datautil.IndexOf(uid, p.UIDs...)
+	}
+	if i < 0 {
+		return "", p.Start
+	}
+	return p.UIDs[i], p.Start + i + 1
+}
+
+// FetchAfter fetches up to limit entries following afterID in the UID list, starting at index when
+// afterID is empty or no longer listed.
+func (ds *DataFetcher[T]) FetchAfter(ctx context.Context, afterID string, index, limit int) (*PageAfter[T], error) {
+	versionInfo, err := ds.db.GetVersionSync(ctx, ds.TableName, ds.EntityID)
+	if err != nil {
+		return nil, err
+	}
+	start := index
+	if afterID != "" {
+		if i := datautil.IndexOf(afterID, versionInfo.UIDList...); i >= 0 {
+			start = i + 1
+		}
+	}
+	if start > len(versionInfo.UIDList) {
+		start = len(versionInfo.UIDList)
+	}
+	end := start + limit
+	if end > len(versionInfo.UIDList) {
+		end = len(versionInfo.UIDList)
+	}
+	page := &PageAfter[T]{UIDs: versionInfo.UIDList[start:end], Start: start, HasMore: end < len(versionInfo.UIDList)}
+	page.Data, err = ds.FetchMissingAndFillLocal(ctx, page.UIDs)
+	if err != nil {
+		return nil, err
+	}
+	return page, nil
+}
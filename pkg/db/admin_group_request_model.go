@@ -26,21 +26,18 @@ import (
 )
 
 func (d *DataBase) InsertAdminGroupRequest(ctx context.Context, groupRequest *model_struct.LocalAdminGroupRequest) error {
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
-	return errs.WrapMsg(d.conn.WithContext(ctx).Create(groupRequest).Error, "InsertAdminGroupRequest failed")
+	defer d.lock(ctx)()
+	return errs.WrapMsg(d.session(ctx).Create(groupRequest).Error, "InsertAdminGroupRequest failed")
 }
 
 func (d *DataBase) DeleteAdminGroupRequest(ctx context.Context, groupID, userID string) error {
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
-	return errs.WrapMsg(d.conn.WithContext(ctx).Where("group_id=? and user_id=?", groupID, userID).Delete(&model_struct.LocalAdminGroupRequest{}).Error, "DeleteAdminGroupRequest failed")
+	defer d.lock(ctx)()
+	return errs.WrapMsg(d.session(ctx).Where("group_id=? and user_id=?", groupID, userID).Delete(&model_struct.LocalAdminGroupRequest{}).Error, "DeleteAdminGroupRequest failed")
 }
 
 func (d *DataBase) UpdateAdminGroupRequest(ctx context.Context, groupRequest *model_struct.LocalAdminGroupRequest) error {
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
-	t := d.conn.WithContext(ctx).Model(groupRequest).Select("*").Updates(*groupRequest)
+	defer d.lock(ctx)()
+	t := d.session(ctx).Model(groupRequest).Select("*").Updates(*groupRequest)
 	if t.RowsAffected == 0 {
 		return errs.WrapMsg(errors.New("RowsAffected == 0"), "no update")
 	}
@@ -48,10 +45,9 @@ func (d *DataBase) UpdateAdminGroupRequest(ctx context.Context, groupRequest *mo
 }
 
 func (d *DataBase) GetAdminGroupApplication(ctx context.Context) ([]*model_struct.LocalAdminGroupRequest, error) {
-	d.mRWMutex.RLock()
-	defer d.mRWMutex.RUnlock()
+	defer d.rlock(ctx)()
 	var groupRequestList []*model_struct.LocalAdminGroupRequest
-	err := errs.Wrap(d.conn.WithContext(ctx).Order("create_time DESC").Find(&groupRequestList).Error)
+	err := errs.Wrap(d.session(ctx).Order("create_time DESC").Find(&groupRequestList).Error)
 	if err != nil {
 		return nil, errs.Wrap(err)
 	}
@@ -29,7 +29,7 @@ import (
 
 func (d *DataBase) GetAppSDKVersion(ctx context.Context) (*model_struct.LocalAppSDKVersion, error) {
 	var appVersion model_struct.LocalAppSDKVersion
-	err := d.conn.WithContext(ctx).Take(&appVersion).Error
+	err := d.session(ctx).Take(&appVersion).Error
 	if err == gorm.ErrRecordNotFound {
 		err = errs.ErrRecordNotFound
 	}
@@ -38,9 +38,9 @@ func (d *DataBase) GetAppSDKVersion(ctx context.Context) (*model_struct.LocalApp
 
 func (d *DataBase) SetAppSDKVersion(ctx context.Context, appVersion *model_struct.LocalAppSDKVersion) error {
 	var exist model_struct.LocalAppSDKVersion
-	err := d.conn.WithContext(ctx).First(&exist).Error
+	err := d.session(ctx).First(&exist).Error
 	if err == gorm.ErrRecordNotFound {
-		if createErr := d.conn.WithContext(ctx).Create(appVersion).Error; createErr != nil {
+		if createErr := d.session(ctx).Create(appVersion).Error; createErr != nil {
 			return errs.Wrap(createErr)
 		}
 		return nil
@@ -48,7 +48,7 @@ func (d *DataBase) SetAppSDKVersion(ctx context.Context, appVersion *model_struc
 		return errs.Wrap(err)
 	}
 
-	if updateErr := d.conn.WithContext(ctx).Model(&exist).Updates(appVersion).Error; updateErr != nil {
+	if updateErr := d.session(ctx).Model(&exist).Updates(appVersion).Error; updateErr != nil {
 		return errs.Wrap(updateErr)
 	}
 
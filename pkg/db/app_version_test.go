@@ -5,7 +5,7 @@ import (
 	"testing"
 
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
-	"github.com/openimsdk/tools/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
 )
 
 func Test_GetAppSDKVersion(t *testing.T) {
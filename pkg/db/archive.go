@@ -0,0 +1,269 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !js
+// +build !js
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+
+	"github.com/openimsdk/tools/errs"
+	"gorm.io/gorm"
+)
+
+// archiveSchema is the name the archive database is attached as.
+const archiveSchema = "archive"
+
+// archiveInfo caches what the archive database holds, it is read once on first use.
+type archiveInfo struct {
+	mu     sync.Mutex
+	loaded bool
+	// cutoff is the latest send time the messages were archived before
+	cutoff int64
+	// tables are the tables of the messages in the archive
+	tables map[string]bool
+}
+
+// archiveFileName is the file of the archive next to the database.
+func (d *DataBase) archiveFileName() string {
+	return d.dbFileName + ".archive"
+}
+
+// ArchiveMessages moves the messages sent before beforeTime to the archive database. The history queries
+// read the archive on demand, attached read only, and the live database stays small.
+func (d *DataBase) ArchiveMessages(ctx context.Context, beforeTime int64) (int64, error) {
+	if d.inMemory() {
+		return 0, errs.New("an in-memory database has no archive")
+	}
+	if beforeTime <= 0 {
+		return 0, errs.New("invalid archive time")
+	}
+	d.mRWMutex.Lock()
+	defer d.mRWMutex.Unlock()
+	d.archive.mu.Lock()
+	defer d.archive.mu.Unlock()
+	if err := d.loadArchive(ctx); err != nil {
+		return 0, err
+	}
+	var archived int64
+	err := d.attachArchive(ctx, true, func(tx *gorm.DB) error {
+		if err := tx.Exec("CREATE TABLE IF NOT EXISTS " + archiveSchema + ".local_archive_meta (cutoff INTEGER)").Error; err != nil {
+			return err
+		}
+		var tables []string
+		if err := tx.Raw("SELECT name FROM main.sqlite_master WHERE type = 'table' AND name LIKE ?",
+			constant.ChatLogsTableNamePre+"%").Scan(&tables).Error; err != nil {
+			return err
+		}
+		for _, table := range tables {
+			n, err := archiveTable(tx, table, beforeTime)
+			if err != nil {
+				return errs.WrapMsg(err, "archive table failed", "table", table)
+			}
+			d.archive.tables[table] = true
+			archived += n
+		}
+		cutoff := max(d.archive.cutoff, beforeTime)
+		if err := tx.Exec("DELETE FROM " + archiveSchema + ".local_archive_meta").Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("INSERT INTO "+archiveSchema+".local_archive_meta (cutoff) VALUES (?)", cutoff).Error; err != nil {
+			return err
+		}
+		d.archive.cutoff = cutoff
+		return nil
+	})
+	log.ZInfo(ctx, "messages archived", "beforeTime", beforeTime, "archived", archived)
+	return archived, err
+}
+
+// archiveTable moves the messages of the table sent before beforeTime to the archive, in one transaction.
+func archiveTable(tx *gorm.DB, table string, beforeTime int64) (int64, error) {
+	var count int
+	if err := tx.Raw("SELECT count(*) FROM "+archiveSchema+".sqlite_master WHERE type = 'table' AND name = ?", table).
+		Scan(&count).Error; err != nil {
+		return 0, err
+	}
+	if count == 0 {
+		conversationID := strings.TrimPrefix(table, constant.ChatLogsTableNamePre)
+		for _, statement := range []string{
+			fmt.Sprintf("CREATE TABLE %s.%s AS SELECT * FROM main.%s WHERE 0", archiveSchema, quoteIdent(table), quoteIdent(table)),
+			fmt.Sprintf("CREATE UNIQUE INDEX %s.%s ON %s (client_msg_id)", archiveSchema,
+				quoteIdent("index_client_msg_id_"+conversationID), quoteIdent(table)),
+			fmt.Sprintf("CREATE INDEX %s.%s ON %s (send_time, seq)", archiveSchema,
+				quoteIdent("index_send_time_seq_"+conversationID), quoteIdent(table)),
+		} {
+			if err := tx.Exec(statement).Error; err != nil {
+				return 0, err
+			}
+		}
+	}
+	// the columns added to the live table after the archive was created are not archived
+	columns, err := commonColumns(tx, table, archiveSchema)
+	if err != nil {
+		return 0, err
+	}
+	var archived int64
+	err = tx.Transaction(func(tx *gorm.DB) error {
+		insert := fmt.Sprintf("INSERT OR IGNORE INTO %s.%s (%s) SELECT %s FROM main.%s WHERE send_time < ?",
+			archiveSchema, quoteIdent(table), columns, columns, quoteIdent(table))
+		if err := tx.Exec(insert, beforeTime).Error; err != nil {
+			return err
+		}
+		result := tx.Exec(fmt.Sprintf("DELETE FROM main.%s WHERE send_time < ?", quoteIdent(table)), beforeTime)
+		archived = result.RowsAffected
+		return result.Error
+	})
+	return archived, err
+}
+
+// attachArchive runs fn on a connection the archive database is attached to, read only unless written.
+func (d *DataBase) attachArchive(ctx context.Context, write bool, fn func(tx *gorm.DB) error) error {
+	name := d.archiveFileName()
+	if !write {
+		name = (&url.URL{Scheme: "file", Path: name, RawQuery: "mode=ro"}).String()
+	}
+	return d.conn.WithContext(ctx).Connection(func(tx *gorm.DB) error {
+		if err := tx.Exec(attachStatement(name, archiveSchema, d.key)).Error; err != nil {
+			return errs.WrapMsg(err, "attach archive db failed "+d.archiveFileName())
+		}
+		// fn runs on its own statement, the transaction of a write would take the connection of tx otherwise
+		defer tx.Session(&gorm.Session{NewDB: true}).Exec("DETACH DATABASE " + archiveSchema)
+		return fn(tx.Session(&gorm.Session{NewDB: true}))
+	})
+}
+
+// loadArchive reads the cutoff and the tables of the archive once, with archive.mu held.
+func (d *DataBase) loadArchive(ctx context.Context) error {
+	if d.archive.loaded {
+		return nil
+	}
+	d.archive.tables = make(map[string]bool)
+	if !d.inMemory() {
+		if _, err := os.Stat(d.archiveFileName()); err == nil {
+			err := d.attachArchive(ctx, false, func(tx *gorm.DB) error {
+				var tables []string
+				if err := tx.Raw("SELECT name FROM "+archiveSchema+".sqlite_master WHERE type = 'table' AND name LIKE ?",
+					constant.ChatLogsTableNamePre+"%").Scan(&tables).Error; err != nil {
+					return err
+				}
+				for _, table := range tables {
+					d.archive.tables[table] = true
+				}
+				return tx.Raw("SELECT IFNULL(MAX(cutoff), 0) FROM " + archiveSchema + ".local_archive_meta").Scan(&d.archive.cutoff).Error
+			})
+			if err != nil {
+				return err
+			}
+		} else if !os.IsNotExist(err) {
+			return errs.Wrap(err)
+		}
+	}
+	d.archive.loaded = true
+	return nil
+}
+
+// archived tells whether the archive holds the table and the send time the messages were archived before.
+func (d *DataBase) archived(ctx context.Context, table string) (bool, int64, error) {
+	d.archive.mu.Lock()
+	defer d.archive.mu.Unlock()
+	if err := d.loadArchive(ctx); err != nil {
+		return false, 0, err
+	}
+	return d.archive.tables[table], d.archive.cutoff, nil
+}
+
+// withArchivedMessages merges the archived messages into a page of the history of the table when the page
+// reaches the archived time range. find queries the page on a table.
+func (d *DataBase) withArchivedMessages(ctx context.Context, table string, live []*model_struct.LocalChatLog, count int,
+	startTime int64, isReverse bool, find func(tx *gorm.DB, table string) ([]*model_struct.LocalChatLog, error)) []*model_struct.LocalChatLog {
+	ok, cutoff, err := d.archived(ctx, table)
+	if err != nil {
+		log.ZWarn(ctx, "read archive failed", err, "table", table)
+		return live
+	}
+	if !ok {
+		return live
+	}
+	if isReverse {
+		// newer messages from startTime, the archive only holds older ones than the cutoff
+		ok = startTime < cutoff
+	} else {
+		ok = len(live) < count || live[len(live)-1].SendTime < cutoff
+	}
+	if !ok {
+		return live
+	}
+	var older []*model_struct.LocalChatLog
+	err = d.attachArchive(ctx, false, func(tx *gorm.DB) error {
+		older, err = find(tx, archiveSchema+"."+table)
+		return err
+	})
+	if err != nil {
+		log.ZWarn(ctx, "read archived messages failed", err, "table", table)
+		return live
+	}
+	seen := make(map[string]struct{}, len(live))
+	for _, msg := range live {
+		seen[msg.ClientMsgID] = struct{}{}
+	}
+	for _, msg := range older {
+		if _, ok := seen[msg.ClientMsgID]; !ok {
+			live = append(live, msg)
+		}
+	}
+	return sortMessageList(live, count, isReverse)
+}
+
+// sortMessageList sorts the messages in the order of GetMessageList and keeps the first count of them.
+func sortMessageList(list []*model_struct.LocalChatLog, count int, isReverse bool) []*model_struct.LocalChatLog {
+	sort.SliceStable(list, func(i, j int) bool {
+		a, b := list[i], list[j]
+		if !isReverse {
+			a, b = b, a
+		}
+		if a.SendTime != b.SendTime {
+			return a.SendTime < b.SendTime
+		}
+		return a.Seq < b.Seq
+	})
+	if len(list) > count {
+		list = list[:count]
+	}
+	return list
+}
+
+// applyToArchive runs the change of the messages of the table on their archived copies too, the deleted
+// messages would come back from the archive otherwise.
+func (d *DataBase) applyToArchive(ctx context.Context, table string, fn func(tx *gorm.DB, table string) error) error {
+	ok, _, err := d.archived(ctx, table)
+	if err != nil || !ok {
+		return err
+	}
+	return d.attachArchive(ctx, true, func(tx *gorm.DB) error {
+		return fn(tx, archiveSchema+"."+table)
+	})
+}
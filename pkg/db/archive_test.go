@@ -0,0 +1,47 @@
+package db
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+)
+
+func TestArchiveMessages(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewDataBase(ctx, "1695766238", t.TempDir(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close(ctx)
+	const conversationID = "si_1695766238_4"
+	var msgs []*model_struct.LocalChatLog
+	for i := 1; i <= 10; i++ {
+		msgs = append(msgs, &model_struct.LocalChatLog{ClientMsgID: strconv.Itoa(i), Seq: int64(i), SendTime: int64(i)})
+	}
+	if err := db.BatchInsertMessageList(ctx, conversationID, msgs); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := db.ArchiveMessages(ctx, 6); err != nil || n != 5 {
+		t.Fatal(n, err)
+	}
+	// the page runs from the live messages into the archived ones
+	list, err := db.GetMessageList(ctx, conversationID, 4, 8, 8, "8", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 4 || list[0].Seq != 7 || list[3].Seq != 4 {
+		t.Fatal(list)
+	}
+	if err := db.DeleteConversationMsgs(ctx, conversationID, []string{"5"}); err != nil {
+		t.Fatal(err)
+	}
+	list, err = db.GetMessageList(ctx, conversationID, 3, 6, 6, "6", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 3 || list[0].Seq != 4 || list[2].Seq != 2 {
+		t.Fatal(list)
+	}
+}
@@ -0,0 +1,43 @@
+//go:build !js
+// +build !js
+
+package db
+
+import (
+	"context"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/tools/errs"
+)
+
+func (d *DataBase) InsertAuditLog(ctx context.Context, auditLog *model_struct.LocalAuditLog) error {
+	defer d.lock(ctx)()
+	return errs.WrapMsg(d.session(ctx).Create(auditLog).Error, "InsertAuditLog failed")
+}
+
+func (d *DataBase) GetAuditLogs(ctx context.Context, actions []string, startTime, endTime int64, offset, count int) ([]*model_struct.LocalAuditLog, error) {
+	defer d.rlock(ctx)()
+	query := d.session(ctx).Model(&model_struct.LocalAuditLog{}).Where("create_time >= ?", startTime)
+	if endTime > 0 {
+		query = query.Where("create_time < ?", endTime)
+	}
+	if len(actions) > 0 {
+		query = query.Where("action IN ?", actions)
+	}
+	var auditLogs []*model_struct.LocalAuditLog
+	return auditLogs, errs.WrapMsg(query.Order("create_time DESC, id DESC").Offset(offset).Limit(count).Find(&auditLogs).Error, "GetAuditLogs failed")
+}
+
+func (d *DataBase) GetAuditLogsToUpload(ctx context.Context, count int) ([]*model_struct.LocalAuditLog, error) {
+	defer d.rlock(ctx)()
+	var auditLogs []*model_struct.LocalAuditLog
+	return auditLogs, errs.WrapMsg(d.session(ctx).Where("uploaded = ?", false).Order("id").Limit(count).Find(&auditLogs).Error, "GetAuditLogsToUpload failed")
+}
+
+func (d *DataBase) SetAuditLogsUploaded(ctx context.Context, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	defer d.lock(ctx)()
+	return errs.WrapMsg(d.session(ctx).Model(&model_struct.LocalAuditLog{}).Where("id IN ?", ids).Update("uploaded", true).Error, "SetAuditLogsUploaded failed")
+}
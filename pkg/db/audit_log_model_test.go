@@ -0,0 +1,53 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+)
+
+func TestAuditLogModel(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewDataBase(ctx, "1695766238", t.TempDir(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close(ctx)
+	for i, action := range []string{"export", "bulk_delete", "admin_edit"} {
+		if err := db.InsertAuditLog(ctx, &model_struct.LocalAuditLog{Action: action, CreateTime: int64(i + 1)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	auditLogs, err := db.GetAuditLogs(ctx, nil, 0, 0, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(auditLogs) != 3 || auditLogs[0].Action != "admin_edit" {
+		t.Fatal(auditLogs)
+	}
+	auditLogs, err = db.GetAuditLogs(ctx, []string{"export", "admin_edit"}, 0, 3, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(auditLogs) != 1 || auditLogs[0].Action != "export" {
+		t.Fatal(auditLogs)
+	}
+	toUpload, err := db.GetAuditLogsToUpload(ctx, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(toUpload) != 2 || toUpload[0].Action != "export" {
+		t.Fatal(toUpload)
+	}
+	if err := db.SetAuditLogsUploaded(ctx, []int64{toUpload[0].ID, toUpload[1].ID}); err != nil {
+		t.Fatal(err)
+	}
+	toUpload, err = db.GetAuditLogsToUpload(ctx, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(toUpload) != 1 || toUpload[0].Action != "admin_edit" {
+		t.Fatal(toUpload)
+	}
+}
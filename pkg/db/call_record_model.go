@@ -0,0 +1,57 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !js
+// +build !js
+
+package db
+
+import (
+	"context"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/tools/errs"
+	"gorm.io/gorm/clause"
+)
+
+func (d *DataBase) SetCallRecord(ctx context.Context, record *model_struct.LocalCallRecord) error {
+	defer d.lock(ctx)()
+	return errs.WrapMsg(d.session(ctx).Clauses(clause.OnConflict{UpdateAll: true}).Create(record).Error, "SetCallRecord failed")
+}
+
+func (d *DataBase) GetCallRecordsAfter(ctx context.Context, peerUserID, groupID, mediaType string, states []string, missed bool,
+	initiateTime int64, roomID string, count int) ([]*model_struct.LocalCallRecord, error) {
+	defer d.rlock(ctx)()
+	query := d.session(ctx).Model(&model_struct.LocalCallRecord{})
+	if peerUserID != "" {
+		query = query.Where("peer_user_id = ?", peerUserID)
+	}
+	if groupID != "" {
+		query = query.Where("group_id = ?", groupID)
+	}
+	if mediaType != "" {
+		query = query.Where("media_type = ?", mediaType)
+	}
+	if len(states) > 0 {
+		query = query.Where("state IN ?", states)
+	}
+	if missed {
+		query = query.Where("missed = ?", true)
+	}
+	if roomID != "" {
+		query = query.Where("initiate_time < ? OR (initiate_time = ? AND room_id > ?)", initiateTime, initiateTime, roomID)
+	}
+	var records []*model_struct.LocalCallRecord
+	return records, errs.WrapMsg(query.Order("initiate_time DESC,room_id").Limit(count).Find(&records).Error, "GetCallRecordsAfter failed")
+}
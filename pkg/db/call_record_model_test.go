@@ -0,0 +1,52 @@
+package db
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+)
+
+func TestGetCallRecordsAfter(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewDataBase(ctx, "1695766238", t.TempDir(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close(ctx)
+	for i := 1; i <= 5; i++ {
+		record := &model_struct.LocalCallRecord{
+			RoomID:       strconv.Itoa(i),
+			PeerUserID:   "peer",
+			State:        "completed",
+			Missed:       i%2 == 0,
+			InitiateTime: int64(i / 2),
+		}
+		if err := db.SetCallRecord(ctx, record); err != nil {
+			t.Fatal(err)
+		}
+	}
+	records, err := db.GetCallRecordsAfter(ctx, "peer", "", "", nil, false, 0, "", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 || records[0].RoomID != "4" || records[1].RoomID != "5" || records[2].RoomID != "2" {
+		t.Fatal(records)
+	}
+	// the page goes on after the last record of the time the previous one ends in
+	records, err = db.GetCallRecordsAfter(ctx, "peer", "", "", nil, false, 1, "2", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[0].RoomID != "3" || records[1].RoomID != "1" {
+		t.Fatal(records)
+	}
+	records, err = db.GetCallRecordsAfter(ctx, "peer", "", "", nil, true, 2, "4", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].RoomID != "2" {
+		t.Fatal(records)
+	}
+}
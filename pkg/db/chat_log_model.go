@@ -27,16 +27,15 @@ import (
 
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
 	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
 
 	"github.com/openimsdk/tools/errs"
-	"github.com/openimsdk/tools/log"
 )
 
 func (d *DataBase) initChatLog(ctx context.Context, conversationID string) error {
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
+	defer d.lock(ctx)()
 	tableName := utils.GetTableName(conversationID)
 	if !d.tableChecker.HasTable(tableName) {
 		createTableSQL := fmt.Sprintf(`
@@ -66,16 +65,17 @@ func (d *DataBase) initChatLog(ctx context.Context, conversationID string) error
                 PRIMARY KEY (client_msg_id)
             );`, tableName)
 
-		if result := d.conn.Exec(createTableSQL); result.Error != nil {
+		if result := d.session(ctx).Exec(createTableSQL); result.Error != nil {
 			return errs.WrapMsg(result.Error, "Create table failed", "table", tableName)
 		}
-		result := d.conn.Exec(fmt.Sprintf("CREATE INDEX `%s` ON `%s` (seq)", "index_seq_"+conversationID, tableName))
+		result := d.session(ctx).Exec(fmt.Sprintf("CREATE INDEX `%s` ON `%s` (seq)", "index_seq_"+conversationID, tableName))
 		if result.Error != nil {
 			return errs.WrapMsg(result.Error, "Create index_seq failed", "table", tableName, "index", "index_seq_"+conversationID)
 		}
-		result = d.conn.Exec(fmt.Sprintf("CREATE INDEX `%s` ON `%s` (send_time)", "index_send_time_"+conversationID, tableName))
+		// the pages of the message list are ordered by send_time then seq
+		result = d.session(ctx).Exec(fmt.Sprintf("CREATE INDEX `%s` ON `%s` (send_time, seq)", "index_send_time_seq_"+conversationID, tableName))
 		if result.Error != nil {
-			return errs.WrapMsg(result.Error, "Create index_send_time failed", "table", tableName, "index", "index_send_time_"+conversationID)
+			return errs.WrapMsg(result.Error, "Create index_send_time_seq failed", "table", tableName, "index", "index_send_time_seq_"+conversationID)
 		}
 		d.tableChecker.UpdateTable(tableName)
 	}
@@ -87,9 +87,8 @@ func (d *DataBase) checkTable(ctx context.Context, tableName string) bool {
 }
 
 func (d *DataBase) UpdateMessage(ctx context.Context, conversationID string, c *model_struct.LocalChatLog) error {
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
-	t := d.conn.WithContext(ctx).Table(utils.GetTableName(conversationID)).Updates(c)
+	defer d.lock(ctx)()
+	t := d.session(ctx).Table(utils.GetTableName(conversationID)).Updates(c)
 	if t.RowsAffected == 0 {
 		return errs.WrapMsg(errors.New("RowsAffected == 0"), "no update ")
 	}
@@ -97,9 +96,8 @@ func (d *DataBase) UpdateMessage(ctx context.Context, conversationID string, c *
 }
 
 func (d *DataBase) UpdateMessageBySeq(ctx context.Context, conversationID string, c *model_struct.LocalChatLog) error {
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
-	return errs.WrapMsg(d.conn.WithContext(ctx).Table(utils.GetTableName(conversationID)).Where("seq=?", c.Seq).Updates(c).Error, "UpdateMessage failed")
+	defer d.lock(ctx)()
+	return errs.WrapMsg(d.session(ctx).Table(utils.GetTableName(conversationID)).Where("seq=?", c.Seq).Updates(c).Error, "UpdateMessage failed")
 }
 
 func (d *DataBase) BatchInsertMessageList(ctx context.Context, conversationID string, MessageList []*model_struct.LocalChatLog) error {
@@ -112,15 +110,13 @@ func (d *DataBase) BatchInsertMessageList(ctx context.Context, conversationID st
 	if MessageList == nil {
 		return nil
 	}
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
-	return errs.WrapMsg(d.conn.WithContext(ctx).Table(utils.GetTableName(conversationID)).Create(MessageList).Error, "BatchInsertMessageList failed")
+	defer d.lock(ctx)()
+	return errs.WrapMsg(d.session(ctx).Table(utils.GetTableName(conversationID)).Create(MessageList).Error, "BatchInsertMessageList failed")
 }
 
 func (d *DataBase) InsertMessage(ctx context.Context, conversationID string, Message *model_struct.LocalChatLog) error {
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
-	return errs.WrapMsg(d.conn.WithContext(ctx).Table(utils.GetTableName(conversationID)).Create(Message).Error, "InsertMessage failed")
+	defer d.lock(ctx)()
+	return errs.WrapMsg(d.session(ctx).Table(utils.GetTableName(conversationID)).Create(Message).Error, "InsertMessage failed")
 }
 func (d *DataBase) GetMessage(ctx context.Context, conversationID string, clientMsgID string) (*model_struct.LocalChatLog, error) {
 	err := d.initChatLog(ctx, conversationID)
@@ -128,10 +124,9 @@ func (d *DataBase) GetMessage(ctx context.Context, conversationID string, client
 		log.ZWarn(ctx, "initChatLog err", err)
 		return nil, err
 	}
-	d.mRWMutex.RLock()
-	defer d.mRWMutex.RUnlock()
+	defer d.rlock(ctx)()
 	var c model_struct.LocalChatLog
-	return &c, errs.WrapMsg(d.conn.WithContext(ctx).Table(utils.GetTableName(conversationID)).Where("client_msg_id = ?",
+	return &c, errs.WrapMsg(d.prepared(ctx).Table(utils.GetTableName(conversationID)).Where("client_msg_id = ?",
 		clientMsgID).Take(&c).Error, "GetMessage failed")
 }
 
@@ -141,17 +136,15 @@ func (d *DataBase) GetMessageBySeq(ctx context.Context, conversationID string, s
 		log.ZWarn(ctx, "initChatLog err", err)
 		return nil, err
 	}
-	d.mRWMutex.RLock()
-	defer d.mRWMutex.RUnlock()
+	defer d.rlock(ctx)()
 	var c model_struct.LocalChatLog
-	return &c, errs.WrapMsg(d.conn.WithContext(ctx).Table(utils.GetTableName(conversationID)).Where("seq = ?",
+	return &c, errs.WrapMsg(d.prepared(ctx).Table(utils.GetTableName(conversationID)).Where("seq = ?",
 		seq).Take(&c).Error, "GetMessage failed")
 }
 
 func (d *DataBase) UpdateMessageTimeAndStatus(ctx context.Context, conversationID, clientMsgID string, serverMsgID string, sendTime int64, status int32) error {
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
-	return errs.WrapMsg(d.conn.WithContext(ctx).Table(utils.GetTableName(conversationID)).Model(model_struct.LocalChatLog{}).Where("client_msg_id=? And seq=?", clientMsgID, 0).
+	defer d.lock(ctx)()
+	return errs.WrapMsg(d.session(ctx).Table(utils.GetTableName(conversationID)).Model(model_struct.LocalChatLog{}).Where("client_msg_id=? And seq=?", clientMsgID, 0).
 		Updates(model_struct.LocalChatLog{Status: status, SendTime: sendTime, ServerMsgID: serverMsgID}).Error, "UpdateMessageStatusBySourceID failed")
 }
 
@@ -160,8 +153,7 @@ func (d *DataBase) GetMessageList(ctx context.Context, conversationID string, co
 		log.ZWarn(ctx, "initChatLog err", err)
 		return nil, err
 	}
-	d.mRWMutex.RLock()
-	defer d.mRWMutex.RUnlock()
+	defer d.rlock(ctx)()
 	var condition, timeOrder, timeSymbol string
 	if isReverse {
 		timeOrder = "send_time ASC,seq ASC"
@@ -170,54 +162,154 @@ func (d *DataBase) GetMessageList(ctx context.Context, conversationID string, co
 		timeOrder = "send_time DESC,seq DESC"
 		timeSymbol = "<"
 	}
-	if startTime > 0 {
-		condition = "send_time " + timeSymbol + " ? " +
-			"OR (send_time = ? AND (seq " + timeSymbol + " ? OR (seq = 0 AND client_msg_id != ?)))"
-		err = errs.WrapMsg(d.conn.WithContext(ctx).Table(utils.GetTableName(conversationID)).
-			Where(condition, startTime, startTime, startSeq, startClientMsgID).
-			Order(timeOrder).Offset(0).Limit(count).Find(&result).Error, "GetMessageList failed")
-		if err != nil {
-			return nil, err
+	find := func(tx *gorm.DB, table string) (result []*model_struct.LocalChatLog, err error) {
+		if startTime > 0 {
+			condition = "send_time " + timeSymbol + " ? " +
+				"OR (send_time = ? AND (seq " + timeSymbol + " ? OR (seq = 0 AND client_msg_id != ?)))"
+			err = errs.WrapMsg(tx.Table(table).
+				Where(condition, startTime, startTime, startSeq, startClientMsgID).
+				Order(timeOrder).Offset(0).Limit(count).Find(&result).Error, "GetMessageList failed")
+		} else {
+			err = errs.WrapMsg(tx.Table(table).Order(timeOrder).
+				Offset(0).Limit(count).Find(&result).Error, "GetMessageList failed")
 		}
+		return result, err
+	}
+	table := utils.GetTableName(conversationID)
+	if startSeq > 0 || startTime == 0 {
+		result, err = seqWindow(d.prepared(ctx), table, count, startTime, startSeq, isReverse)
 	} else {
-		err = errs.WrapMsg(d.conn.WithContext(ctx).Table(utils.GetTableName(conversationID)).Order(timeOrder).
-			Offset(0).Limit(count).Find(&result).Error, "GetMessageList failed")
-		if err != nil {
-			return nil, err
-		}
+		// from a message without a seq, sent from here and not acknowledged
+		result, err = find(d.prepared(ctx), table)
 	}
-	return result, err
+	if err != nil {
+		return nil, err
+	}
+	return d.withArchivedMessages(ctx, table, result, count, startTime, isReverse, find), nil
+}
+
+// seqWindow gets the messages of GetMessageList next to a message with a seq, or from the end of the
+// conversation. The messages of the server are read as the window of the count seqs next to startSeq, an
+// indexed range whatever the size of the conversation, and merged with the messages without a seq sent in
+// the time of the window.
+func seqWindow(tx *gorm.DB, table string, count int, startTime, startSeq int64, isReverse bool) ([]*model_struct.LocalChatLog, error) {
+	seqOrder, timeOrder, symbol, farSymbol := "seq DESC", "send_time DESC", "<", ">="
+	if isReverse {
+		seqOrder, timeOrder, symbol, farSymbol = "seq ASC", "send_time ASC", ">", "<="
+	}
+	var window []*model_struct.LocalChatLog
+	query := tx.Table(table).Where("seq > 0")
+	if startSeq > 0 {
+		query = query.Where("seq "+symbol+" ?", startSeq)
+	}
+	if err := query.Order(seqOrder).Limit(count).Find(&window).Error; err != nil {
+		return nil, errs.WrapMsg(err, "GetMessageList failed")
+	}
+	var unsent []*model_struct.LocalChatLog
+	query = tx.Table(table).Where("seq = 0")
+	if startTime > 0 {
+		query = query.Where("send_time "+symbol+"= ?", startTime)
+	}
+	if len(window) == count {
+		// the ones beyond the window come with the next one
+		query = query.Where("send_time "+farSymbol+" ?", window[len(window)-1].SendTime)
+	}
+	if err := query.Order(timeOrder).Limit(count).Find(&unsent).Error; err != nil {
+		return nil, errs.WrapMsg(err, "GetMessageList failed")
+	}
+	return sortMessageList(append(window, unsent...), count, isReverse), nil
 }
 
 func (d *DataBase) DeleteConversationAllMessages(ctx context.Context, conversationID string) error {
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
-	return errs.WrapMsg(d.conn.WithContext(ctx).Table(utils.GetTableName(conversationID)).Where("1 = 1").Delete(model_struct.LocalChatLog{}).Error, "DeleteConversationAllMessages failed")
+	defer d.lock(ctx)()
+	deleteAll := func(tx *gorm.DB, table string) error {
+		return errs.WrapMsg(tx.Table(table).Where("1 = 1").Delete(model_struct.LocalChatLog{}).Error, "DeleteConversationAllMessages failed")
+	}
+	if err := deleteAll(d.session(ctx), utils.GetTableName(conversationID)); err != nil {
+		return err
+	}
+	return d.applyToArchive(ctx, utils.GetTableName(conversationID), deleteAll)
 }
 
 func (d *DataBase) MarkDeleteConversationAllMessages(ctx context.Context, conversationID string) error {
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
-	return errs.WrapMsg(d.conn.WithContext(ctx).Table(utils.GetTableName(conversationID)).Where("1 = 1").Updates(model_struct.LocalChatLog{Status: constant.MsgStatusHasDeleted}).Error, "DeleteConversationAllMessages failed")
+	defer d.lock(ctx)()
+	markDelete := func(tx *gorm.DB, table string) error {
+		return errs.WrapMsg(tx.Table(table).Where("1 = 1").Updates(model_struct.LocalChatLog{Status: constant.MsgStatusHasDeleted}).Error, "DeleteConversationAllMessages failed")
+	}
+	if err := markDelete(d.session(ctx), utils.GetTableName(conversationID)); err != nil {
+		return err
+	}
+	return d.applyToArchive(ctx, utils.GetTableName(conversationID), markDelete)
 }
 
 func (d *DataBase) DeleteConversationMsgs(ctx context.Context, conversationID string, msgIDs []string) error {
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
-	return errs.WrapMsg(d.conn.WithContext(ctx).Table(utils.GetTableName(conversationID)).Where("client_msg_id IN ?", msgIDs).Delete(model_struct.LocalChatLog{}).Error, "DeleteConversationMsgs failed")
+	defer d.lock(ctx)()
+	deleteMsgs := func(tx *gorm.DB, table string) error {
+		return errs.WrapMsg(tx.Table(table).Where("client_msg_id IN ?", msgIDs).Delete(model_struct.LocalChatLog{}).Error, "DeleteConversationMsgs failed")
+	}
+	if err := deleteMsgs(d.session(ctx), utils.GetTableName(conversationID)); err != nil {
+		return err
+	}
+	return d.applyToArchive(ctx, utils.GetTableName(conversationID), deleteMsgs)
+}
+
+func (d *DataBase) DeleteOldestMessages(ctx context.Context, conversationID string, count, keep int) (int64, error) {
+	defer d.lock(ctx)()
+	table := utils.GetTableName(conversationID)
+	if !d.tableChecker.HasTable(table) {
+		return 0, nil
+	}
+	pinned := d.session(ctx).Model(&model_struct.LocalMediaPin{}).Select("client_msg_id").Where("conversation_id = ?", conversationID)
+	oldest := d.session(ctx).Table(table).Select("client_msg_id").Where("client_msg_id NOT IN (?)", pinned).
+		Order("send_time ASC").Limit(count)
+	latest := d.session(ctx).Table(table).Select("client_msg_id").Order("send_time DESC").Limit(keep)
+	result := d.session(ctx).Table(table).Where("client_msg_id IN (?) AND client_msg_id NOT IN (?)", oldest, latest).
+		Delete(model_struct.LocalChatLog{})
+	return result.RowsAffected, errs.WrapMsg(result.Error, "DeleteOldestMessages failed")
+}
+
+func (d *DataBase) DeleteMessagesBeforeSendTime(ctx context.Context, conversationID string, sendTime int64) (int64, error) {
+	defer d.lock(ctx)()
+	if !d.tableChecker.HasTable(utils.GetTableName(conversationID)) {
+		return 0, nil
+	}
+	result := d.session(ctx).Table(utils.GetTableName(conversationID)).Where("send_time < ?", sendTime).
+		Delete(model_struct.LocalChatLog{})
+	return result.RowsAffected, errs.WrapMsg(result.Error, "DeleteMessagesBeforeSendTime failed")
+}
+
+// msgRowOverhead estimates the bytes of the numeric columns and the indexes of a message.
+const msgRowOverhead = 96
+
+func (d *DataBase) GetConversationMsgStorage(ctx context.Context, conversationID string) (count int64, size int64, err error) {
+	defer d.rlock(ctx)()
+	if !d.tableChecker.HasTable(utils.GetTableName(conversationID)) {
+		return 0, 0, nil
+	}
+	var columns []string
+	for _, column := range []string{"client_msg_id", "server_msg_id", "send_id", "recv_id", "sender_nick_name",
+		"sender_face_url", "content", "attached_info", "ex", "local_ex"} {
+		columns = append(columns, "IFNULL(LENGTH("+column+"),0)")
+	}
+	var result struct {
+		Count int64
+		Size  int64
+	}
+	err = d.session(ctx).Table(utils.GetTableName(conversationID)).
+		Select(fmt.Sprintf("COUNT(*) AS count, IFNULL(SUM(%s),0) + COUNT(*) * %d AS size", strings.Join(columns, "+"), msgRowOverhead)).
+		Scan(&result).Error
+	return result.Count, result.Size, errs.WrapMsg(err, "GetConversationMsgStorage failed")
 }
 
 func (d *DataBase) DeleteConversationMsgsBySeqs(ctx context.Context, conversationID string, seqs []int64) error {
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
-	return errs.WrapMsg(d.conn.WithContext(ctx).Table(utils.GetTableName(conversationID)).Where("seq IN ?", seqs).Delete(model_struct.LocalChatLog{}).Error, "DeleteConversationMsgs failed")
+	defer d.lock(ctx)()
+	return errs.WrapMsg(d.session(ctx).Table(utils.GetTableName(conversationID)).Where("seq IN ?", seqs).Delete(model_struct.LocalChatLog{}).Error, "DeleteConversationMsgs failed")
 }
 
 func (d *DataBase) SearchMessageByContentType(ctx context.Context, contentType []int, senderUserIDList []string, conversationID string, startTime, endTime int64, offset, count int) (result []*model_struct.LocalChatLog, err error) {
-	d.mRWMutex.RLock()
-	defer d.mRWMutex.RUnlock()
+	defer d.rlock(ctx)()
 
-	query := d.conn.WithContext(ctx).Table(utils.GetTableName(conversationID)).
+	query := d.session(ctx).Table(utils.GetTableName(conversationID)).
 		Where("send_time BETWEEN ? AND ?", startTime, endTime).
 		Where("status <= ?", constant.MsgStatusSendFailed).
 		Where("content_type IN ?", contentType)
@@ -235,10 +327,9 @@ func (d *DataBase) SearchMessageByContentType(ctx context.Context, contentType [
 }
 
 func (d *DataBase) SearchMessageByKeyword(ctx context.Context, contentType []int, senderUserIDList []string, keywordList []string, keywordListMatchType int, conversationID string, startTime, endTime int64, offset, count int) (result []*model_struct.LocalChatLog, err error) {
-	d.mRWMutex.RLock()
-	defer d.mRWMutex.RUnlock()
+	defer d.rlock(ctx)()
 
-	query := d.conn.WithContext(ctx).Table(utils.GetTableName(conversationID)).
+	query := d.session(ctx).Table(utils.GetTableName(conversationID)).
 		Where("send_time BETWEEN ? AND ?", startTime, endTime).
 		Where("status <= ?", constant.MsgStatusSendFailed).
 		Where("content_type IN ?", contentType)
@@ -275,10 +366,9 @@ func (d *DataBase) SearchMessageByKeyword(ctx context.Context, contentType []int
 
 // SearchMessageByContentTypeAndKeyword searches for messages in the database that match specified content types and keywords within a given time range.
 func (d *DataBase) SearchMessageByContentTypeAndKeyword(ctx context.Context, contentType []int, conversationID string, senderUserIDList []string, keywordList []string, keywordListMatchType int, startTime, endTime int64) (result []*model_struct.LocalChatLog, err error) {
-	d.mRWMutex.RLock()
-	defer d.mRWMutex.RUnlock()
+	defer d.rlock(ctx)()
 
-	query := d.conn.WithContext(ctx).Table(utils.GetTableName(conversationID)).
+	query := d.session(ctx).Table(utils.GetTableName(conversationID)).
 		Where("send_time BETWEEN ? AND ?", startTime, endTime).
 		Where("status <= ?", constant.MsgStatusSendFailed).
 		Where("content_type IN ?", contentType)
@@ -312,44 +402,42 @@ func (d *DataBase) SearchMessageByContentTypeAndKeyword(ctx context.Context, con
 }
 
 func (d *DataBase) UpdateMsgSenderFaceURLAndSenderNickname(ctx context.Context, conversationID, sendID, faceURL, nickname string) error {
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
-	return errs.WrapMsg(d.conn.WithContext(ctx).Table(utils.GetTableName(conversationID)).Model(model_struct.LocalChatLog{}).Where(
+	defer d.lock(ctx)()
+	return errs.WrapMsg(d.session(ctx).Table(utils.GetTableName(conversationID)).Model(model_struct.LocalChatLog{}).Where(
 		"send_id = ?", sendID).Updates(
 		map[string]any{"sender_face_url": faceURL, "sender_nick_name": nickname}).Error, utils.GetSelfFuncName()+" failed")
 }
 
 func (d *DataBase) UpdateColumnsMessage(ctx context.Context, conversationID, ClientMsgID string, args map[string]interface{}) error {
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
+	defer d.lock(ctx)()
 	c := model_struct.LocalChatLog{ClientMsgID: ClientMsgID}
-	t := d.conn.WithContext(ctx).Table(utils.GetConversationTableName(conversationID)).Model(&c).Updates(args)
+	t := d.session(ctx).Table(utils.GetConversationTableName(conversationID)).Model(&c).Updates(args)
 	if t.RowsAffected == 0 {
 		return errs.WrapMsg(errors.New("RowsAffected == 0"), "no update")
 	}
 	return errs.WrapMsg(t.Error, "UpdateColumnsConversation failed")
 }
 func (d *DataBase) SearchAllMessageByContentType(ctx context.Context, conversationID string, contentType int) (result []*model_struct.LocalChatLog, err error) {
-	d.mRWMutex.RLock()
-	defer d.mRWMutex.RUnlock()
+	defer d.rlock(ctx)()
+	if !d.tableChecker.HasTable(utils.GetTableName(conversationID)) {
+		return nil, nil
+	}
 
-	query := d.conn.WithContext(ctx).Table(utils.GetTableName(conversationID)).
+	query := d.session(ctx).Table(utils.GetTableName(conversationID)).
 		Where("content_type = ?", contentType)
 
 	err = query.Find(&result).Error
 	return result, err
 }
 func (d *DataBase) GetUnreadMessage(ctx context.Context, conversationID string) (msgs []*model_struct.LocalChatLog, err error) {
-	d.mRWMutex.RLock()
-	defer d.mRWMutex.RUnlock()
-	err = errs.WrapMsg(d.conn.WithContext(ctx).Table(utils.GetConversationTableName(conversationID)).Debug().Where("send_id != ? AND is_read = ?", d.loginUserID, constant.NotRead).Find(&msgs).Error, "GetMessageList failed")
+	defer d.rlock(ctx)()
+	err = errs.WrapMsg(d.session(ctx).Table(utils.GetConversationTableName(conversationID)).Debug().Where("send_id != ? AND is_read = ?", d.loginUserID, constant.NotRead).Find(&msgs).Error, "GetMessageList failed")
 	return msgs, err
 }
 
 func (d *DataBase) MarkConversationMessageAsReadBySeqs(ctx context.Context, conversationID string, seqs []int64) (rowsAffected int64, err error) {
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
-	t := d.conn.WithContext(ctx).Table(utils.GetConversationTableName(conversationID)).Where("seq in ? AND send_id != ?", seqs, d.loginUserID).Update("is_read", constant.HasRead)
+	defer d.lock(ctx)()
+	t := d.session(ctx).Table(utils.GetConversationTableName(conversationID)).Where("seq in ? AND send_id != ?", seqs, d.loginUserID).Update("is_read", constant.HasRead)
 	if t.RowsAffected == 0 {
 		return 0, errs.WrapMsg(errors.New("RowsAffected == 0"), "no update")
 	}
@@ -357,19 +445,18 @@ func (d *DataBase) MarkConversationMessageAsReadBySeqs(ctx context.Context, conv
 }
 
 func (d *DataBase) MarkConversationMessageAsReadDB(ctx context.Context, conversationID string, msgIDs []string) (rowsAffected int64, err error) {
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
+	defer d.lock(ctx)()
 	var msgs []*model_struct.LocalChatLog
-	if err := d.conn.WithContext(ctx).Table(utils.GetConversationTableName(conversationID)).Where("client_msg_id in ? AND send_id != ?", msgIDs, d.loginUserID).Find(&msgs).Error; err != nil {
+	if err := d.session(ctx).Table(utils.GetConversationTableName(conversationID)).Where("client_msg_id in ? AND send_id != ?", msgIDs, d.loginUserID).Find(&msgs).Error; err != nil {
 		return 0, errs.WrapMsg(err, "MarkConversationMessageAsReadDB failed")
 	}
 	for _, msg := range msgs {
 		var attachedInfo sdk_struct.AttachedInfoElem
 		utils.JsonStringToStruct(msg.AttachedInfo, &attachedInfo)
-		attachedInfo.HasReadTime = utils.GetCurrentTimestampByMill()
+		attachedInfo.HasReadTime = utils.GetServerTimestampByMill()
 		msg.IsRead = true
 		msg.AttachedInfo = utils.StructToJsonString(attachedInfo)
-		if err := d.conn.WithContext(ctx).Table(utils.GetConversationTableName(conversationID)).Where("client_msg_id = ?", msg.ClientMsgID).Updates(msg).Error; err != nil {
+		if err := d.session(ctx).Table(utils.GetConversationTableName(conversationID)).Where("client_msg_id = ?", msg.ClientMsgID).Updates(msg).Error; err != nil {
 			log.ZError(ctx, "MarkConversationMessageAsReadDB failed", err, "msg", msg)
 		} else {
 			rowsAffected++
@@ -379,9 +466,8 @@ func (d *DataBase) MarkConversationMessageAsReadDB(ctx context.Context, conversa
 }
 
 func (d *DataBase) MarkConversationAllMessageAsRead(ctx context.Context, conversationID string) (rowsAffected int64, err error) {
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
-	t := d.conn.WithContext(ctx).Table(utils.GetConversationTableName(conversationID)).Where("send_id != ? AND is_read == ?", d.loginUserID, false).Update("is_read", constant.HasRead)
+	defer d.lock(ctx)()
+	t := d.session(ctx).Table(utils.GetConversationTableName(conversationID)).Where("send_id != ? AND is_read == ?", d.loginUserID, false).Update("is_read", constant.HasRead)
 	if t.RowsAffected == 0 {
 		return 0, errs.WrapMsg(errors.New("RowsAffected == 0"), "no update")
 	}
@@ -394,16 +480,14 @@ func (d *DataBase) GetMessagesByClientMsgIDs(ctx context.Context, conversationID
 		log.ZWarn(ctx, "initChatLog err", err)
 		return nil, err
 	}
-	d.mRWMutex.RLock()
-	defer d.mRWMutex.RUnlock()
-	err = errs.WrapMsg(d.conn.WithContext(ctx).Table(utils.GetConversationTableName(conversationID)).Where("client_msg_id IN ?", msgIDs).Order("send_time DESC").Find(&msgs).Error, "GetMessagesByClientMsgIDs error")
+	defer d.rlock(ctx)()
+	err = errs.WrapMsg(d.session(ctx).Table(utils.GetConversationTableName(conversationID)).Where("client_msg_id IN ?", msgIDs).Order("send_time DESC").Find(&msgs).Error, "GetMessagesByClientMsgIDs error")
 	return msgs, err
 }
 
 func (d *DataBase) GetMessagesBySeqs(ctx context.Context, conversationID string, seqs []int64) (msgs []*model_struct.LocalChatLog, err error) {
-	d.mRWMutex.RLock()
-	defer d.mRWMutex.RUnlock()
-	err = errs.WrapMsg(d.conn.WithContext(ctx).Table(utils.GetConversationTableName(conversationID)).Where("seq IN ?", seqs).Order("send_time DESC").Find(&msgs).Error, "GetMessagesBySeqs error")
+	defer d.rlock(ctx)()
+	err = errs.WrapMsg(d.session(ctx).Table(utils.GetConversationTableName(conversationID)).Where("seq IN ?", seqs).Order("send_time DESC").Find(&msgs).Error, "GetMessagesBySeqs error")
 	return msgs, err
 }
 
@@ -413,36 +497,32 @@ func (d *DataBase) GetConversationNormalMsgSeq(ctx context.Context, conversation
 		log.ZWarn(ctx, "initChatLog err", err)
 		return 0, err
 	}
-	d.mRWMutex.RLock()
-	defer d.mRWMutex.RUnlock()
+	defer d.rlock(ctx)()
 	var seq int64
-	err = d.conn.WithContext(ctx).Table(utils.GetConversationTableName(conversationID)).Select("IFNULL(max(seq),0)").Find(&seq).Error
+	err = d.prepared(ctx).Table(utils.GetConversationTableName(conversationID)).Select("IFNULL(max(seq),0)").Find(&seq).Error
 	return seq, errs.WrapMsg(err, "GetConversationNormalMsgSeq")
 }
 
 func (d *DataBase) CheckConversationNormalMsgSeq(ctx context.Context, conversationID string) (int64, error) {
 	var seq int64
-	d.mRWMutex.RLock()
-	defer d.mRWMutex.RUnlock()
+	defer d.rlock(ctx)()
 	if d.tableChecker.HasTable(utils.GetConversationTableName(conversationID)) {
-		err := d.conn.WithContext(ctx).Table(utils.GetConversationTableName(conversationID)).Select("IFNULL(max(seq),0)").Find(&seq).Error
+		err := d.session(ctx).Table(utils.GetConversationTableName(conversationID)).Select("IFNULL(max(seq),0)").Find(&seq).Error
 		return seq, errs.Wrap(err)
 	}
 	return 0, nil
 }
 
 func (d *DataBase) GetConversationPeerNormalMsgSeq(ctx context.Context, conversationID string) (int64, error) {
-	d.mRWMutex.RLock()
-	defer d.mRWMutex.RUnlock()
+	defer d.rlock(ctx)()
 	var seq int64
-	err := d.conn.WithContext(ctx).Table(utils.GetConversationTableName(conversationID)).Select("IFNULL(max(seq),0)").Where("send_id != ?", d.loginUserID).Find(&seq).Error
+	err := d.session(ctx).Table(utils.GetConversationTableName(conversationID)).Select("IFNULL(max(seq),0)").Where("send_id != ?", d.loginUserID).Find(&seq).Error
 	return seq, errs.WrapMsg(err, "GetConversationPeerNormalMsgSeq")
 }
 
 func (d *DataBase) UpdateMsgSenderFaceURL(ctx context.Context, sendID, faceURL string, sType int) error {
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
-	return errs.WrapMsg(d.conn.WithContext(ctx).Model(model_struct.LocalChatLog{}).Where(
+	defer d.lock(ctx)()
+	return errs.WrapMsg(d.session(ctx).Model(model_struct.LocalChatLog{}).Where(
 		"send_id = ? and session_type = ? and sender_face_url != ? ", sendID, sType, faceURL).Updates(
 		map[string]any{"sender_face_url": faceURL}).Error, utils.GetSelfFuncName()+" failed")
 }
@@ -453,8 +533,7 @@ func (d *DataBase) GetLatestActiveMessage(ctx context.Context, conversationID st
 		return nil, err
 	}
 
-	d.mRWMutex.RLock()
-	defer d.mRWMutex.RUnlock()
+	defer d.rlock(ctx)()
 
 	var timeOrder string
 	if isReverse {
@@ -464,7 +543,7 @@ func (d *DataBase) GetLatestActiveMessage(ctx context.Context, conversationID st
 	}
 
 	// only get status < 4(NotHasDeleted) Msg
-	err = errs.WrapMsg(d.conn.WithContext(ctx).Table(utils.GetTableName(conversationID)).Where("status < ?", constant.MsgStatusHasDeleted).Order(timeOrder).Offset(0).Limit(1).Find(&result).Error, "GetLatestActiveMessage failed")
+	err = errs.WrapMsg(d.session(ctx).Table(utils.GetTableName(conversationID)).Where("status < ?", constant.MsgStatusHasDeleted).Order(timeOrder).Offset(0).Limit(1).Find(&result).Error, "GetLatestActiveMessage failed")
 	if err != nil {
 		return nil, err
 	}
@@ -472,8 +551,7 @@ func (d *DataBase) GetLatestActiveMessage(ctx context.Context, conversationID st
 	return result, err
 }
 func (d *DataBase) GetLatestValidServerMessage(ctx context.Context, conversationID string, startTime int64, isReverse bool) (*model_struct.LocalChatLog, error) {
-	d.mRWMutex.RLock()
-	defer d.mRWMutex.RUnlock()
+	defer d.rlock(ctx)()
 
 	var condition, timeOrder, timeSymbol string
 	var result model_struct.LocalChatLog
@@ -488,7 +566,7 @@ func (d *DataBase) GetLatestValidServerMessage(ctx context.Context, conversation
 
 	condition = "send_time " + timeSymbol + " ? AND seq != ?"
 
-	err := d.conn.WithContext(ctx).Table(utils.GetTableName(conversationID)).
+	err := d.session(ctx).Table(utils.GetTableName(conversationID)).
 		Where(condition, startTime, 0).
 		Order(timeOrder).
 		Limit(1).
@@ -2,7 +2,12 @@ package db
 
 import (
 	"context"
+	"slices"
+	"strconv"
+	"strings"
 	"testing"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
 )
 
 func TestGetLatestValidateServerMessage(t *testing.T) {
@@ -19,3 +24,156 @@ func TestGetLatestValidateServerMessage(t *testing.T) {
 	}
 	t.Log("message", message)
 }
+
+func TestDeleteOldestMessages(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewDataBase(ctx, "1695766238", MemoryDBDir, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close(ctx)
+	const conversationID = "si_1695766238_2"
+	var msgs []*model_struct.LocalChatLog
+	for i := 1; i <= 10; i++ {
+		msgs = append(msgs, &model_struct.LocalChatLog{ClientMsgID: strconv.Itoa(i), Seq: int64(i), SendTime: int64(i)})
+	}
+	if err := db.BatchInsertMessageList(ctx, conversationID, msgs); err != nil {
+		t.Fatal(err)
+	}
+	// the 3 oldest are deleted
+	if n, err := db.DeleteOldestMessages(ctx, conversationID, 3, 5); err != nil || n != 3 {
+		t.Fatal(n, err)
+	}
+	// only the 2 older than the latest 5 are left to delete
+	if n, err := db.DeleteOldestMessages(ctx, conversationID, 3, 5); err != nil || n != 2 {
+		t.Fatal(n, err)
+	}
+	if _, err := db.GetMessage(ctx, conversationID, "6"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// a message whose media are pinned, e.g. a favorited one, is not deleted
+func TestDeleteOldestMessagesPinned(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewDataBase(ctx, "1695766238", MemoryDBDir, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close(ctx)
+	const conversationID = "si_1695766238_2"
+	var msgs []*model_struct.LocalChatLog
+	for i := 1; i <= 10; i++ {
+		msgs = append(msgs, &model_struct.LocalChatLog{ClientMsgID: strconv.Itoa(i), Seq: int64(i), SendTime: int64(i)})
+	}
+	if err := db.BatchInsertMessageList(ctx, conversationID, msgs); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.InsertMediaPin(ctx, &model_struct.LocalMediaPin{ConversationID: conversationID, ClientMsgID: "2"}); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := db.DeleteOldestMessages(ctx, conversationID, 3, 5); err != nil || n != 3 {
+		t.Fatal(n, err)
+	}
+	if _, err := db.GetMessage(ctx, conversationID, "2"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.GetMessage(ctx, conversationID, "4"); err == nil {
+		t.Fatal("the oldest message not pinned is left")
+	}
+}
+
+// The hot reads of the messages run on prepared statements and the (send_time, seq) index. Compare with the
+// parent commit by go test -run NONE -bench 'GetMessage' -benchmem ./pkg/db/ on both.
+
+func benchmarkMessages(b *testing.B) (*DataBase, string) {
+	ctx := context.Background()
+	db, err := NewDataBase(ctx, "1695766238", MemoryDBDir, 0)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() { db.Close(ctx) })
+	const conversationID = "si_1695766238_3"
+	for start := 1; start <= 10000; start += 500 {
+		msgs := make([]*model_struct.LocalChatLog, 0, 500)
+		for i := start; i < start+500; i++ {
+			msgs = append(msgs, &model_struct.LocalChatLog{ClientMsgID: strconv.Itoa(i), Seq: int64(i), SendTime: int64(i / 3)})
+		}
+		if err := db.BatchInsertMessageList(ctx, conversationID, msgs); err != nil {
+			b.Fatal(err)
+		}
+	}
+	return db, conversationID
+}
+
+func BenchmarkGetMessageBySeq(b *testing.B) {
+	db, conversationID := benchmarkMessages(b)
+	ctx := context.Background()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := db.GetMessageBySeq(ctx, conversationID, int64(i%10000+1)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGetMessageList(b *testing.B) {
+	db, conversationID := benchmarkMessages(b)
+	ctx := context.Background()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		seq := int64(i%9000 + 1000)
+		if _, err := db.GetMessageList(ctx, conversationID, 20, seq/3, seq, strconv.FormatInt(seq, 10), false); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestGetMessageListSeqWindow(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewDataBase(ctx, "1695766238", MemoryDBDir, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close(ctx)
+	const conversationID = "si_1695766238_4"
+	var msgs []*model_struct.LocalChatLog
+	for i := 1; i <= 10; i++ {
+		msgs = append(msgs, &model_struct.LocalChatLog{ClientMsgID: strconv.Itoa(i), Seq: int64(i), SendTime: int64(i * 10)})
+	}
+	// failed to send between the 4th and the 5th, and after the last
+	msgs = append(msgs, &model_struct.LocalChatLog{ClientMsgID: "a", SendTime: 45}, &model_struct.LocalChatLog{ClientMsgID: "b", SendTime: 200})
+	if err := db.BatchInsertMessageList(ctx, conversationID, msgs); err != nil {
+		t.Fatal(err)
+	}
+	page := func(isReverse bool) []string {
+		var (
+			got                 []string
+			startTime, startSeq int64
+			startClientMsgID    string
+		)
+		for {
+			list, err := db.GetMessageList(ctx, conversationID, 3, startTime, startSeq, startClientMsgID, isReverse)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(list) == 0 {
+				return got
+			}
+			for _, msg := range list {
+				got = append(got, msg.ClientMsgID)
+			}
+			last := list[len(list)-1]
+			startTime, startSeq, startClientMsgID = last.SendTime, last.Seq, last.ClientMsgID
+		}
+	}
+	want := []string{"b", "10", "9", "8", "7", "6", "5", "a", "4", "3", "2", "1"}
+	if got := page(false); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	slices.Reverse(want)
+	// from the start of the conversation the other way
+	if got := page(true); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !js
+// +build !js
+
+package db
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// sqliteHeader starts every plaintext sqlite database file, an encrypted file starts with random bytes.
+var sqliteHeader = []byte("SQLite format 3\x00")
+
+func openSqlite(dbFileName, key string, pragmas *sdk_struct.DBPragmas) (gorm.Dialector, error) {
+	if key == "" {
+		return sqlite.Open(pragmaDSN(dbFileName, pragmas)), nil
+	}
+	// the pragmas of an encrypted database are run once its key is given
+	return cipherDialector(dbFileName, key, pragmas)
+}
+
+// pragmaDSN adds the pragmas to the parameters of the connections of the database file.
+func pragmaDSN(dbFileName string, pragmas *sdk_struct.DBPragmas) string {
+	values := pragmaValues(pragmas)
+	if len(values) == 0 {
+		return dbFileName
+	}
+	params := make([]string, 0, len(values))
+	for _, value := range values {
+		params = append(params, "_"+value[0]+"="+url.QueryEscape(value[1]))
+	}
+	sep := "?"
+	if strings.Contains(dbFileName, "?") {
+		sep = "&"
+	}
+	return dbFileName + sep + strings.Join(params, "&")
+}
+
+// isPlaintextDB tells whether the database file exists and is not encrypted.
+func isPlaintextDB(dbFileName string) (bool, error) {
+	file, err := os.Open(dbFileName)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	defer file.Close()
+	header := make([]byte, len(sqliteHeader))
+	if _, err := io.ReadFull(file, header); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return false, nil
+		}
+		return false, err
+	}
+	return bytes.Equal(header, sqliteHeader), nil
+}
+
+// attachStatement attaches the database file as the schema, an encrypted one with its key.
+func attachStatement(dbFileName, schema, key string) string {
+	attach := "ATTACH DATABASE " + quoteSqlString(dbFileName) + " AS " + schema
+	if key != "" {
+		attach += " KEY " + quoteSqlString(key)
+	}
+	return attach
+}
+
+// quoteIdent quotes a table or column name.
+func quoteIdent(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
+
+// quoteSqlString quotes a string literal of a statement that takes no parameters, like the pragmas.
+func quoteSqlString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
@@ -0,0 +1,39 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !js && !sqlcipher
+// +build !js,!sqlcipher
+
+package db
+
+import (
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+
+	"github.com/openimsdk/tools/errs"
+	"gorm.io/gorm"
+)
+
+var errNoSQLCipher = errs.New("database encryption needs the sdk built with the sqlcipher tag and linked with SQLCipher")
+
+func cipherDialector(string, string, *sdk_struct.DBPragmas) (gorm.Dialector, error) {
+	return nil, errNoSQLCipher
+}
+
+func encryptPlaintextDB(string, string) error {
+	return errNoSQLCipher
+}
+
+func rekeyDB(string, string, string) error {
+	return errNoSQLCipher
+}
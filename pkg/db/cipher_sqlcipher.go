@@ -0,0 +1,115 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !js && sqlcipher
+// +build !js,sqlcipher
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"os"
+
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+
+	"github.com/mattn/go-sqlite3"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// The sqlcipher build links go-sqlite3 with SQLCipher instead of sqlite, e.g.
+// CGO_CFLAGS="-DSQLITE_HAS_CODEC" CGO_LDFLAGS="-lsqlcipher" go build -tags "libsqlite3 sqlcipher".
+
+// cipherConnector opens the connections of an encrypted database, each new connection is given the key
+// before anything else is run on it.
+type cipherConnector struct {
+	driver *sqlite3.SQLiteDriver
+	dsn    string
+}
+
+func newCipherConnector(dsn, key string, pragmas *sdk_struct.DBPragmas) *cipherConnector {
+	return &cipherConnector{
+		driver: &sqlite3.SQLiteDriver{
+			ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+				if _, err := conn.Exec("PRAGMA key = "+quoteSqlString(key), nil); err != nil {
+					return err
+				}
+				// a wrong key only fails on the first read
+				if _, err := conn.Exec("SELECT count(*) FROM sqlite_master", nil); err != nil {
+					return err
+				}
+				for _, value := range pragmaValues(pragmas) {
+					if _, err := conn.Exec("PRAGMA "+value[0]+" = "+value[1], nil); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		dsn: dsn,
+	}
+}
+
+func (c *cipherConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+func (c *cipherConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+func cipherDialector(dbFileName, key string, pragmas *sdk_struct.DBPragmas) (gorm.Dialector, error) {
+	return &sqlite.Dialector{DSN: dbFileName, Conn: sql.OpenDB(newCipherConnector(dbFileName, key, pragmas))}, nil
+}
+
+// encryptPlaintextDB exports the plaintext database into an encrypted copy that then replaces it. The
+// database is left as it was when cut off, the copy is written again from scratch by the next attempt.
+func encryptPlaintextDB(dbFileName, key string) error {
+	encrypted := dbFileName + ".encrypting"
+	if err := os.Remove(encrypted); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	db, err := sql.Open("sqlite3", dbFileName)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	steps := []string{
+		"ATTACH DATABASE " + quoteSqlString(encrypted) + " AS encrypted KEY " + quoteSqlString(key),
+		"SELECT sqlcipher_export('encrypted')",
+		"DETACH DATABASE encrypted",
+	}
+	for _, step := range steps {
+		if _, err := db.Exec(step); err != nil {
+			_ = os.Remove(encrypted)
+			return err
+		}
+	}
+	if err := db.Close(); err != nil {
+		return err
+	}
+	return os.Rename(encrypted, dbFileName)
+}
+
+// rekeyDB encrypts the database again with the new key.
+func rekeyDB(dbFileName, oldKey, newKey string) error {
+	db := sql.OpenDB(newCipherConnector(dbFileName, oldKey, nil))
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	_, err := db.Exec("PRAGMA rekey = " + quoteSqlString(newKey))
+	return err
+}
@@ -0,0 +1,42 @@
+//go:build !js
+// +build !js
+
+package db
+
+import (
+	"context"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/tools/errs"
+	"gorm.io/gorm"
+)
+
+func (d *DataBase) GetClientConfig(ctx context.Context) (map[string]string, error) {
+	defer d.rlock(ctx)()
+	var configs []*model_struct.LocalClientConfig
+	if err := d.session(ctx).Find(&configs).Error; err != nil {
+		return nil, errs.WrapMsg(err, "GetClientConfig failed")
+	}
+	res := make(map[string]string, len(configs))
+	for _, config := range configs {
+		res[config.Key] = config.Value
+	}
+	return res, nil
+}
+
+func (d *DataBase) SetClientConfig(ctx context.Context, configs map[string]string) error {
+	defer d.lock(ctx)()
+	return errs.WrapMsg(d.session(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&model_struct.LocalClientConfig{}).Error; err != nil {
+			return err
+		}
+		if len(configs) == 0 {
+			return nil
+		}
+		rows := make([]*model_struct.LocalClientConfig, 0, len(configs))
+		for key, value := range configs {
+			rows = append(rows, &model_struct.LocalClientConfig{Key: key, Value: value})
+		}
+		return tx.Create(rows).Error
+	}), "SetClientConfig failed")
+}
@@ -0,0 +1,37 @@
+package db
+
+import (
+	"context"
+	"maps"
+	"testing"
+)
+
+func TestClientConfigModel(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewDataBase(ctx, "1695766238", t.TempDir(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close(ctx)
+	configs, err := db.GetClientConfig(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(configs) != 0 {
+		t.Fatal(configs)
+	}
+	if err := db.SetClientConfig(ctx, map[string]string{"MAX_FILE_SIZE": "1024", "MSG_REVOKE_WINDOW": "120"}); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"MAX_FILE_SIZE": "2048"}
+	if err := db.SetClientConfig(ctx, want); err != nil {
+		t.Fatal(err)
+	}
+	configs, err = db.GetClientConfig(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !maps.Equal(configs, want) {
+		t.Fatal(configs)
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !js
+// +build !js
+
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/tools/errs"
+	"gorm.io/gorm"
+)
+
+const (
+	autoVacuumIncremental = 2
+	// vacuumStepPages is the pages freed by a step of the incremental vacuum, the writes wait for a step only.
+	vacuumStepPages = 256
+)
+
+// FreePages is the number of unused pages of the database file and the number of its pages.
+func (d *DataBase) FreePages(ctx context.Context) (free int, total int, err error) {
+	d.mRWMutex.RLock()
+	defer d.mRWMutex.RUnlock()
+	return d.freePages(ctx)
+}
+
+func (d *DataBase) freePages(ctx context.Context) (free int, total int, err error) {
+	if err := d.conn.WithContext(ctx).Raw("PRAGMA freelist_count").Scan(&free).Error; err != nil {
+		return 0, 0, errs.Wrap(err)
+	}
+	if err := d.conn.WithContext(ctx).Raw("PRAGMA page_count").Scan(&total).Error; err != nil {
+		return 0, 0, errs.Wrap(err)
+	}
+	return free, total, nil
+}
+
+// Compact gives the unused pages of the database back to the file system, at most maxPages of them, all
+// when maxPages is 0. A database not yet in incremental auto vacuum is rebuilt by a full vacuum once, later
+// compactions free the pages in steps and stop between two steps when ctx is done. The progress is called
+// with the pages freed and the pages to free.
+func (d *DataBase) Compact(ctx context.Context, maxPages int, progress func(done, total int)) error {
+	if progress == nil {
+		progress = func(done, total int) {}
+	}
+	var mode int
+	if err := d.conn.WithContext(ctx).Raw("PRAGMA auto_vacuum").Scan(&mode).Error; err != nil {
+		return errs.Wrap(err)
+	}
+	if mode != autoVacuumIncremental {
+		return d.fullVacuum(ctx, progress)
+	}
+	free, _, err := d.FreePages(ctx)
+	if err != nil {
+		return err
+	}
+	total := free
+	if maxPages > 0 && maxPages < total {
+		total = maxPages
+	}
+	log.ZDebug(ctx, "incremental vacuum", "freePages", free, "pages", total)
+	for done := 0; done < total; {
+		if err := ctx.Err(); err != nil {
+			return errs.Wrap(err)
+		}
+		step := min(vacuumStepPages, total-done)
+		if err := d.incrementalVacuum(ctx, step); err != nil {
+			return err
+		}
+		done += step
+		progress(done, total)
+	}
+	return nil
+}
+
+// fullVacuum switches the database to incremental auto vacuum, which only a vacuum of the whole file applies.
+func (d *DataBase) fullVacuum(ctx context.Context, progress func(done, total int)) error {
+	d.mRWMutex.Lock()
+	defer d.mRWMutex.Unlock()
+	free, total, err := d.freePages(ctx)
+	if err != nil {
+		return err
+	}
+	log.ZInfo(ctx, "full vacuum", "freePages", free, "pageCount", total)
+	progress(0, free)
+	// the pragma and the vacuum applying it must run on the same connection
+	err = d.conn.WithContext(ctx).Connection(func(tx *gorm.DB) error {
+		if err := tx.Exec("PRAGMA auto_vacuum = INCREMENTAL").Error; err != nil {
+			return err
+		}
+		return tx.Exec("VACUUM").Error
+	})
+	if err != nil {
+		return errs.WrapMsg(err, "vacuum db failed "+d.dbFileName)
+	}
+	progress(free, free)
+	return nil
+}
+
+func (d *DataBase) incrementalVacuum(ctx context.Context, pages int) error {
+	d.mRWMutex.Lock()
+	defer d.mRWMutex.Unlock()
+	// a page is freed by each step of the statement, the rows are read until it is done
+	rows, err := d.conn.WithContext(ctx).Raw(fmt.Sprintf("PRAGMA incremental_vacuum(%d)", pages)).Rows()
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+	}
+	return errs.Wrap(rows.Err())
+}
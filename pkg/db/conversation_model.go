@@ -23,12 +23,12 @@ import (
 
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
 
 	"gorm.io/gorm"
 
 	"github.com/openimsdk/tools/errs"
-	"github.com/openimsdk/tools/log"
 )
 
 const (
@@ -36,18 +36,16 @@ const (
 )
 
 func (d *DataBase) GetConversationByUserID(ctx context.Context, userID string) (*model_struct.LocalConversation, error) {
-	d.mRWMutex.RLock()
-	defer d.mRWMutex.RUnlock()
+	defer d.rlock(ctx)()
 	var conversation model_struct.LocalConversation
-	err := errs.WrapMsg(d.conn.WithContext(ctx).Where("user_id=?", userID).Find(&conversation).Error, "GetConversationByUserID error")
+	err := errs.WrapMsg(d.session(ctx).Where("user_id=?", userID).Find(&conversation).Error, "GetConversationByUserID error")
 	return &conversation, err
 }
 
 func (d *DataBase) GetAllConversationListDB(ctx context.Context) ([]*model_struct.LocalConversation, error) {
-	d.mRWMutex.RLock()
-	defer d.mRWMutex.RUnlock()
+	defer d.rlock(ctx)()
 	var conversationList []*model_struct.LocalConversation
-	err := errs.WrapMsg(d.conn.WithContext(ctx).Where("latest_msg_send_time > ?", 0).Order("case when is_pinned=1 then 0 else 1 end,max(latest_msg_send_time,draft_text_time) DESC").Find(&conversationList).Error,
+	err := errs.WrapMsg(d.session(ctx).Where("latest_msg_send_time > ?", 0).Order("case when is_pinned=1 then 0 else 1 end,max(latest_msg_send_time,draft_text_time) DESC").Find(&conversationList).Error,
 		"GetAllConversationList failed")
 	if err != nil {
 		return nil, err
@@ -56,51 +54,62 @@ func (d *DataBase) GetAllConversationListDB(ctx context.Context) ([]*model_struc
 }
 
 func (d *DataBase) FindAllConversationConversationID(ctx context.Context) (conversationIDs []string, err error) {
-	d.mRWMutex.RLock()
-	defer d.mRWMutex.RUnlock()
-	return conversationIDs, errs.WrapMsg(d.conn.WithContext(ctx).Model(&model_struct.LocalConversation{}).Where("latest_msg_send_time > ?", 0).Pluck("conversation_id", &conversationIDs).Error, "")
+	defer d.rlock(ctx)()
+	return conversationIDs, errs.WrapMsg(d.session(ctx).Model(&model_struct.LocalConversation{}).Where("latest_msg_send_time > ?", 0).Pluck("conversation_id", &conversationIDs).Error, "")
 }
 
 func (d *DataBase) FindAllUnreadConversationConversationID(ctx context.Context) (conversationIDs []string, err error) {
-	d.mRWMutex.RLock()
-	defer d.mRWMutex.RUnlock()
-	return conversationIDs, errs.WrapMsg(d.conn.WithContext(ctx).Model(&model_struct.LocalConversation{}).Where("unread_count > ?", 0).Pluck("conversation_id", &conversationIDs).Error, "")
+	defer d.rlock(ctx)()
+	return conversationIDs, errs.WrapMsg(d.session(ctx).Model(&model_struct.LocalConversation{}).Where("unread_count > ?", 0).Pluck("conversation_id", &conversationIDs).Error, "")
 }
 
 func (d *DataBase) GetHiddenConversationList(ctx context.Context) ([]*model_struct.LocalConversation, error) {
-	d.mRWMutex.RLock()
-	defer d.mRWMutex.RUnlock()
+	defer d.rlock(ctx)()
 	var conversationList []*model_struct.LocalConversation
-	return conversationList, errs.WrapMsg(d.conn.WithContext(ctx).Where("latest_msg_send_time = ?", 0).Find(&conversationList).Error,
+	return conversationList, errs.WrapMsg(d.session(ctx).Where("latest_msg_send_time = ?", 0).Find(&conversationList).Error,
 		"GetHiddenConversationList failed")
 }
 
 func (d *DataBase) GetAllConversations(ctx context.Context) ([]*model_struct.LocalConversation, error) {
-	d.mRWMutex.RLock()
-	defer d.mRWMutex.RUnlock()
+	defer d.rlock(ctx)()
 	var conversationList []*model_struct.LocalConversation
-	return conversationList, errs.WrapMsg(d.conn.WithContext(ctx).Find(&conversationList).Error, "GetAllConversations failed")
+	return conversationList, errs.WrapMsg(d.session(ctx).Find(&conversationList).Error, "GetAllConversations failed")
 }
 
 func (d *DataBase) GetAllConversationIDList(ctx context.Context) (result []string, err error) {
-	d.mRWMutex.RLock()
-	defer d.mRWMutex.RUnlock()
+	defer d.rlock(ctx)()
 	var c model_struct.LocalConversation
-	return result, errs.WrapMsg(d.conn.WithContext(ctx).Model(&c).Pluck("conversation_id", &result).Error, "GetAllConversationIDList failed ")
+	return result, errs.WrapMsg(d.session(ctx).Model(&c).Pluck("conversation_id", &result).Error, "GetAllConversationIDList failed ")
 }
 
 func (d *DataBase) GetAllSingleConversationIDList(ctx context.Context) (result []string, err error) {
-	d.mRWMutex.RLock()
-	defer d.mRWMutex.RUnlock()
+	defer d.rlock(ctx)()
 	var c model_struct.LocalConversation
-	return result, errs.WrapMsg(d.conn.WithContext(ctx).Model(&c).Where("conversation_type = ?", constant.SingleChatType).Pluck("conversation_id", &result).Error, "GetAllSingleConversationIDList failed ")
+	return result, errs.WrapMsg(d.session(ctx).Model(&c).Where("conversation_type = ?", constant.SingleChatType).Pluck("conversation_id", &result).Error, "GetAllSingleConversationIDList failed ")
 }
 
 func (d *DataBase) GetConversationListSplitDB(ctx context.Context, offset, count int) ([]*model_struct.LocalConversation, error) {
-	d.mRWMutex.RLock()
-	defer d.mRWMutex.RUnlock()
+	defer d.rlock(ctx)()
 	var conversationList []*model_struct.LocalConversation
-	return conversationList, errs.Wrap(d.conn.WithContext(ctx).Where("latest_msg_send_time > ?", 0).Order("case when is_pinned=1 then 0 else 1 end,max(latest_msg_send_time,draft_text_time) DESC").Offset(offset).Limit(count).Find(&conversationList).Error)
+	return conversationList, errs.Wrap(d.session(ctx).Where("latest_msg_send_time > ?", 0).Order("case when is_pinned=1 then 0 else 1 end,max(latest_msg_send_time,draft_text_time) DESC").Offset(offset).Limit(count).Find(&conversationList).Error)
+}
+
+// GetConversationListAfterDB gets the conversations sorted after the given one in the order of
+// GetConversationListSplitDB, from the start when conversationID is empty.
+func (d *DataBase) GetConversationListAfterDB(ctx context.Context, pinned bool, sortTime int64, conversationID string, count int) ([]*model_struct.LocalConversation, error) {
+	defer d.rlock(ctx)()
+	const rank, sortTimeExpr = "case when is_pinned=1 then 0 else 1 end", "max(latest_msg_send_time,draft_text_time)"
+	var conversationList []*model_struct.LocalConversation
+	query := d.session(ctx).Where("latest_msg_send_time > ?", 0)
+	if conversationID != "" {
+		afterRank := 1
+		if pinned {
+			afterRank = 0
+		}
+		query = query.Where(rank+" > ? OR ("+rank+" = ? AND "+sortTimeExpr+" < ?) OR ("+rank+" = ? AND "+sortTimeExpr+" = ? AND conversation_id > ?)",
+			afterRank, afterRank, sortTime, afterRank, sortTime, conversationID)
+	}
+	return conversationList, errs.Wrap(query.Order(rank + "," + sortTimeExpr + " DESC,conversation_id").Limit(count).Find(&conversationList).Error)
 }
 
 func (d *DataBase) BatchInsertConversationList(ctx context.Context, conversationList []*model_struct.LocalConversation) error {
@@ -108,8 +117,7 @@ func (d *DataBase) BatchInsertConversationList(ctx context.Context, conversation
 		return nil
 	}
 
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
+	defer d.lock(ctx)()
 
 	for i := 0; i < len(conversationList); i += batchSize {
 		end := i + batchSize
@@ -118,7 +126,7 @@ func (d *DataBase) BatchInsertConversationList(ctx context.Context, conversation
 		}
 
 		batch := conversationList[i:end]
-		if err := d.conn.WithContext(ctx).Create(batch).Error; err != nil {
+		if err := d.session(ctx).Create(batch).Error; err != nil {
 			return errs.WrapMsg(err, "BatchInsertConversationList failed")
 		}
 	}
@@ -127,10 +135,9 @@ func (d *DataBase) BatchInsertConversationList(ctx context.Context, conversation
 }
 
 func (d *DataBase) UpdateOrCreateConversations(ctx context.Context, conversationList []*model_struct.LocalConversation) error {
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
+	defer d.lock(ctx)()
 	var conversationIDs []string
-	if err := d.conn.WithContext(ctx).Model(&model_struct.LocalConversation{}).Pluck("conversation_id", &conversationIDs).Error; err != nil {
+	if err := d.session(ctx).Model(&model_struct.LocalConversation{}).Pluck("conversation_id", &conversationIDs).Error; err != nil {
 		return err
 	}
 	var notExistConversations []*model_struct.LocalConversation
@@ -144,12 +151,12 @@ func (d *DataBase) UpdateOrCreateConversations(ctx context.Context, conversation
 		}
 	}
 	if len(notExistConversations) > 0 {
-		if err := d.conn.WithContext(ctx).Create(notExistConversations).Error; err != nil {
+		if err := d.session(ctx).Create(notExistConversations).Error; err != nil {
 			return err
 		}
 	}
 	for _, v := range existConversations {
-		if err := d.conn.WithContext(ctx).Model(&model_struct.LocalConversation{}).Where("conversation_id = ?", v.ConversationID).Updates(map[string]interface{}{"unread_count": v.UnreadCount}).Error; err != nil {
+		if err := d.session(ctx).Model(&model_struct.LocalConversation{}).Where("conversation_id = ?", v.ConversationID).Updates(map[string]interface{}{"unread_count": v.UnreadCount}).Error; err != nil {
 			return err
 		}
 	}
@@ -157,36 +164,31 @@ func (d *DataBase) UpdateOrCreateConversations(ctx context.Context, conversation
 }
 
 func (d *DataBase) InsertConversation(ctx context.Context, conversationList *model_struct.LocalConversation) error {
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
-	return errs.WrapMsg(d.conn.WithContext(ctx).Create(conversationList).Error, "InsertConversation failed")
+	defer d.lock(ctx)()
+	return errs.WrapMsg(d.session(ctx).Create(conversationList).Error, "InsertConversation failed")
 }
 
 func (d *DataBase) DeleteConversation(ctx context.Context, conversationID string) error {
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
-	return errs.WrapMsg(d.conn.WithContext(ctx).Where("conversation_id = ?", conversationID).Delete(&model_struct.LocalConversation{}).Error, "DeleteConversation failed")
+	defer d.lock(ctx)()
+	return errs.WrapMsg(d.session(ctx).Where("conversation_id = ?", conversationID).Delete(&model_struct.LocalConversation{}).Error, "DeleteConversation failed")
 }
 
 func (d *DataBase) DeleteAllConversation(ctx context.Context) error {
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
-	return errs.WrapMsg(d.conn.WithContext(ctx).Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&model_struct.LocalConversation{}).Error, "DeleteAllConversation failed")
+	defer d.lock(ctx)()
+	return errs.WrapMsg(d.session(ctx).Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&model_struct.LocalConversation{}).Error, "DeleteAllConversation failed")
 }
 
 func (d *DataBase) GetConversation(ctx context.Context, conversationID string) (*model_struct.LocalConversation, error) {
-	d.mRWMutex.RLock()
-	defer d.mRWMutex.RUnlock()
+	defer d.rlock(ctx)()
 	var c model_struct.LocalConversation
-	return &c, errs.WrapMsg(d.conn.WithContext(ctx).Where("conversation_id = ?",
+	return &c, errs.WrapMsg(d.session(ctx).Where("conversation_id = ?",
 		conversationID).Take(&c).Error, "GetConversation failed, conversationID: "+conversationID)
 }
 
 func (d *DataBase) UpdateConversation(ctx context.Context, c *model_struct.LocalConversation) error {
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
-	d.conn.WithContext(ctx).Logger.LogMode(6)
-	t := d.conn.WithContext(ctx).Updates(c)
+	defer d.lock(ctx)()
+	d.session(ctx).Logger.LogMode(6)
+	t := d.session(ctx).Updates(c)
 	if t.RowsAffected == 0 {
 		return errs.WrapMsg(errors.New("RowsAffected == 0"), "no update")
 	}
@@ -194,9 +196,8 @@ func (d *DataBase) UpdateConversation(ctx context.Context, c *model_struct.Local
 }
 
 func (d *DataBase) UpdateConversationForSync(ctx context.Context, c *model_struct.LocalConversation) error {
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
-	t := d.conn.WithContext(ctx).Model(&model_struct.LocalConversation{}).Where("conversation_id = ?", c.ConversationID).
+	defer d.lock(ctx)()
+	t := d.session(ctx).Model(&model_struct.LocalConversation{}).Where("conversation_id = ?", c.ConversationID).
 		Updates(map[string]interface{}{"recv_msg_opt": c.RecvMsgOpt, "is_pinned": c.IsPinned, "is_private_chat": c.IsPrivateChat,
 			"group_at_type": c.GroupAtType, "is_not_in_group": c.IsNotInGroup, "update_unread_count_time": c.UpdateUnreadCountTime, "ex": c.Ex, "attached_info": c.AttachedInfo,
 			"burn_duration": c.BurnDuration, "msg_destruct_time": c.MsgDestructTime, "is_msg_destruct": c.IsMsgDestruct})
@@ -218,10 +219,9 @@ func (d *DataBase) BatchUpdateConversationList(ctx context.Context, conversation
 }
 
 func (d *DataBase) ConversationIfExists(ctx context.Context, conversationID string) (bool, error) {
-	d.mRWMutex.RLock()
-	defer d.mRWMutex.RUnlock()
+	defer d.rlock(ctx)()
 	var count int64
-	t := d.conn.WithContext(ctx).Model(&model_struct.LocalConversation{}).Where("conversation_id = ?",
+	t := d.session(ctx).Model(&model_struct.LocalConversation{}).Where("conversation_id = ?",
 		conversationID).Count(&count)
 	if t.Error != nil {
 		return false, errs.WrapMsg(t.Error, "ConversationIfExists get failed")
@@ -236,10 +236,9 @@ func (d *DataBase) ConversationIfExists(ctx context.Context, conversationID stri
 // Reset the conversation is equivalent to deleting the conversation,
 // and the GetAllConversation or GetConversationListSplit interface will no longer be obtained.
 func (d *DataBase) ResetConversation(ctx context.Context, conversationID string) error {
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
+	defer d.lock(ctx)()
 	c := model_struct.LocalConversation{ConversationID: conversationID, UnreadCount: 0, LatestMsg: "", LatestMsgSendTime: 0, DraftText: "", DraftTextTime: 0}
-	t := d.conn.WithContext(ctx).Select("unread_count", "latest_msg", "latest_msg_send_time", "draft_text", "draft_text_time").Updates(c)
+	t := d.session(ctx).Select("unread_count", "latest_msg", "latest_msg_send_time", "draft_text", "draft_text_time").Updates(c)
 	if t.RowsAffected == 0 {
 		return errs.WrapMsg(errors.New("RowsAffected == 0"), "no update")
 	}
@@ -249,10 +248,9 @@ func (d *DataBase) ResetConversation(ctx context.Context, conversationID string)
 // ResetAllConversation Reset ALL conversation is equivalent to deleting the conversation,
 // and the GetAllConversation or GetConversationListSplit interface will no longer be obtained.
 func (d *DataBase) ResetAllConversation(ctx context.Context) error {
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
+	defer d.lock(ctx)()
 	c := model_struct.LocalConversation{UnreadCount: 0, LatestMsg: "", LatestMsgSendTime: 0, DraftText: "", DraftTextTime: 0}
-	t := d.conn.WithContext(ctx).Session(&gorm.Session{AllowGlobalUpdate: true}).Select("unread_count", "latest_msg", "latest_msg_send_time", "draft_text", "draft_text_time").Updates(c)
+	t := d.session(ctx).Session(&gorm.Session{AllowGlobalUpdate: true}).Select("unread_count", "latest_msg", "latest_msg_send_time", "draft_text", "draft_text_time").Updates(c)
 	if t.RowsAffected == 0 {
 		return errs.WrapMsg(errors.New("RowsAffected == 0"), "no update")
 	}
@@ -263,10 +261,9 @@ func (d *DataBase) ResetAllConversation(ctx context.Context) error {
 // The GetAllConversation or GetConversationListSplit interface can still be obtained,
 // but there is no latest message.
 func (d *DataBase) ClearConversation(ctx context.Context, conversationID string) error {
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
+	defer d.lock(ctx)()
 	c := model_struct.LocalConversation{ConversationID: conversationID, UnreadCount: 0, LatestMsg: "", DraftText: "", DraftTextTime: 0}
-	t := d.conn.WithContext(ctx).Select("unread_count", "latest_msg", "draft_text", "draft_text_time").Updates(c)
+	t := d.session(ctx).Select("unread_count", "latest_msg", "draft_text", "draft_text_time").Updates(c)
 	if t.RowsAffected == 0 {
 		return errs.WrapMsg(errors.New("RowsAffected == 0"), "no update")
 	}
@@ -274,10 +271,13 @@ func (d *DataBase) ClearConversation(ctx context.Context, conversationID string)
 }
 
 func (d *DataBase) SetConversationDraftDB(ctx context.Context, conversationID, draftText string) error {
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
-	nowTime := utils.GetCurrentTimestampByMill()
-	t := d.conn.WithContext(ctx).Exec("update local_conversations set draft_text=?,draft_text_time=?,latest_msg_send_time=case when latest_msg_send_time=? then ? else latest_msg_send_time  end where conversation_id=?",
+	defer d.lock(ctx)()
+	nowTime := utils.GetServerTimestampByMill()
+	draftText, err := encryptField(d.withFieldKey(ctx), draftText)
+	if err != nil {
+		return err
+	}
+	t := d.session(ctx).Exec("update local_conversations set draft_text=?,draft_text_time=?,latest_msg_send_time=case when latest_msg_send_time=? then ? else latest_msg_send_time  end where conversation_id=?",
 		draftText, nowTime, 0, nowTime, conversationID)
 	if t.RowsAffected == 0 {
 		return errs.WrapMsg(errors.New("RowsAffected == 0"), "no update")
@@ -286,10 +286,9 @@ func (d *DataBase) SetConversationDraftDB(ctx context.Context, conversationID, d
 }
 
 func (d *DataBase) RemoveConversationDraft(ctx context.Context, conversationID, draftText string) error {
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
+	defer d.lock(ctx)()
 	c := model_struct.LocalConversation{ConversationID: conversationID, DraftText: draftText, DraftTextTime: 0}
-	t := d.conn.WithContext(ctx).Select("draft_text", "draft_text_time").Updates(c)
+	t := d.session(ctx).Select("draft_text", "draft_text_time").Updates(c)
 	if t.RowsAffected == 0 {
 		return errs.WrapMsg(errors.New("RowsAffected == 0"), "no update")
 	}
@@ -297,9 +296,8 @@ func (d *DataBase) RemoveConversationDraft(ctx context.Context, conversationID,
 }
 
 func (d *DataBase) UnPinConversation(ctx context.Context, conversationID string, isPinned int) error {
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
-	t := d.conn.WithContext(ctx).Exec("update local_conversations set is_pinned=?,draft_text_time=case when draft_text=? then ? else draft_text_time  end where conversation_id=?",
+	defer d.lock(ctx)()
+	t := d.session(ctx).Exec("update local_conversations set is_pinned=?,draft_text_time=case when draft_text=? then ? else draft_text_time  end where conversation_id=?",
 		isPinned, "", 0, conversationID)
 	if t.RowsAffected == 0 {
 		return errs.WrapMsg(errors.New("RowsAffected == 0"), "no update")
@@ -308,9 +306,8 @@ func (d *DataBase) UnPinConversation(ctx context.Context, conversationID string,
 }
 
 func (d *DataBase) UpdateColumnsConversation(ctx context.Context, conversationID string, args map[string]interface{}) error {
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
-	t := d.conn.WithContext(ctx).Model(model_struct.LocalConversation{ConversationID: conversationID}).Updates(args)
+	defer d.lock(ctx)()
+	t := d.session(ctx).Model(model_struct.LocalConversation{ConversationID: conversationID}).Updates(args)
 	if t.RowsAffected == 0 {
 		return errs.WrapMsg(errs.ErrRecordNotFound, "no update")
 	}
@@ -318,12 +315,11 @@ func (d *DataBase) UpdateColumnsConversation(ctx context.Context, conversationID
 }
 
 func (d *DataBase) UpdateAllConversation(ctx context.Context, conversation *model_struct.LocalConversation) error {
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
+	defer d.lock(ctx)()
 	if conversation.ConversationID != "" {
 		return errs.WrapMsg(errors.New("not update all conversation"), "UpdateAllConversation failed")
 	}
-	t := d.conn.WithContext(ctx).Model(conversation).Updates(conversation)
+	t := d.session(ctx).Model(conversation).Updates(conversation)
 	if t.RowsAffected == 0 {
 		return errs.WrapMsg(errors.New("RowsAffected == 0"), "no update")
 	}
@@ -331,10 +327,9 @@ func (d *DataBase) UpdateAllConversation(ctx context.Context, conversation *mode
 }
 
 func (d *DataBase) IncrConversationUnreadCount(ctx context.Context, conversationID string) error {
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
+	defer d.lock(ctx)()
 	c := model_struct.LocalConversation{ConversationID: conversationID}
-	t := d.conn.WithContext(ctx).Model(&c).Update("unread_count", gorm.Expr("unread_count+?", 1))
+	t := d.session(ctx).Model(&c).Update("unread_count", gorm.Expr("unread_count+?", 1))
 	if t.RowsAffected == 0 {
 		return errs.WrapMsg(errors.New("RowsAffected == 0"), "no update")
 	}
@@ -342,10 +337,9 @@ func (d *DataBase) IncrConversationUnreadCount(ctx context.Context, conversation
 }
 
 func (d *DataBase) GetTotalUnreadMsgCountNewerDB(ctx context.Context) (totalUnreadCount int32, err error) {
-	d.mRWMutex.RLock()
-	defer d.mRWMutex.RUnlock()
+	defer d.rlock(ctx)()
 	var result []int64
-	err = d.conn.WithContext(ctx).Model(&model_struct.LocalConversation{}).Where("recv_msg_opt < ? ", constant.ReceiveNotNotifyMessage).Pluck("unread_count", &result).Error
+	err = d.session(ctx).Model(&model_struct.LocalConversation{}).Where("recv_msg_opt < ? ", constant.ReceiveNotNotifyMessage).Pluck("unread_count", &result).Error
 	if err != nil {
 		return totalUnreadCount, errs.WrapMsg(errors.New("GetTotalUnreadMsgCount err"), "GetTotalUnreadMsgCount err")
 	}
@@ -356,10 +350,9 @@ func (d *DataBase) GetTotalUnreadMsgCountNewerDB(ctx context.Context) (totalUnre
 }
 
 func (d *DataBase) GetTotalUnreadMsgCountDB(ctx context.Context) (totalUnreadCount int32, err error) {
-	d.mRWMutex.RLock()
-	defer d.mRWMutex.RUnlock()
+	defer d.rlock(ctx)()
 	var result []int64
-	err = d.conn.WithContext(ctx).Model(&model_struct.LocalConversation{}).Where("recv_msg_opt < ? and latest_msg_send_time > ?", constant.ReceiveNotNotifyMessage, 0).Pluck("unread_count", &result).Error
+	err = d.session(ctx).Model(&model_struct.LocalConversation{}).Where("recv_msg_opt < ? and latest_msg_send_time > ?", constant.ReceiveNotNotifyMessage, 0).Pluck("unread_count", &result).Error
 	if err != nil {
 		return totalUnreadCount, errs.WrapMsg(errors.New("GetTotalUnreadMsgCount err"), "GetTotalUnreadMsgCount err")
 	}
@@ -370,9 +363,8 @@ func (d *DataBase) GetTotalUnreadMsgCountDB(ctx context.Context) (totalUnreadCou
 }
 
 func (d *DataBase) SetMultipleConversationRecvMsgOpt(ctx context.Context, conversationIDList []string, opt int) (err error) {
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
-	t := d.conn.WithContext(ctx).Model(&model_struct.LocalConversation{}).Where("conversation_id IN ?", conversationIDList).Updates(map[string]interface{}{"recv_msg_opt": opt})
+	defer d.lock(ctx)()
+	t := d.session(ctx).Model(&model_struct.LocalConversation{}).Where("conversation_id IN ?", conversationIDList).Updates(map[string]interface{}{"recv_msg_opt": opt})
 	if t.RowsAffected == 0 {
 		return errs.WrapMsg(errors.New("RowsAffected == 0"), "no update")
 	}
@@ -380,10 +372,9 @@ func (d *DataBase) SetMultipleConversationRecvMsgOpt(ctx context.Context, conver
 }
 
 func (d *DataBase) GetMultipleConversationDB(ctx context.Context, conversationIDList []string) (result []*model_struct.LocalConversation, err error) {
-	d.mRWMutex.RLock()
-	defer d.mRWMutex.RUnlock()
+	defer d.rlock(ctx)()
 	var conversationList []model_struct.LocalConversation
-	err = errs.WrapMsg(d.conn.WithContext(ctx).Where("conversation_id IN ?", conversationIDList).Find(&conversationList).Error, "GetMultipleConversation failed")
+	err = errs.WrapMsg(d.session(ctx).Where("conversation_id IN ?", conversationIDList).Find(&conversationList).Error, "GetMultipleConversation failed")
 	for _, v := range conversationList {
 		v1 := v
 		result = append(result, &v1)
@@ -392,9 +383,8 @@ func (d *DataBase) GetMultipleConversationDB(ctx context.Context, conversationID
 }
 
 func (d *DataBase) DecrConversationUnreadCount(ctx context.Context, conversationID string, count int64) (err error) {
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
-	tx := d.conn.WithContext(ctx).Begin()
+	defer d.lock(ctx)()
+	tx := d.session(ctx).Begin()
 	c := model_struct.LocalConversation{ConversationID: conversationID}
 	t := tx.Model(&c).Update("unread_count", gorm.Expr("unread_count-?", count))
 	if t.Error != nil {
@@ -417,13 +407,12 @@ func (d *DataBase) DecrConversationUnreadCount(ctx context.Context, conversation
 }
 
 func (d *DataBase) SearchConversations(ctx context.Context, searchParam string) ([]*model_struct.LocalConversation, error) {
-	d.mRWMutex.RLock()
-	defer d.mRWMutex.RUnlock()
+	defer d.rlock(ctx)()
 
 	var conversationList []*model_struct.LocalConversation
 
 	// Define the search condition based on the searchParam
-	err := d.conn.WithContext(ctx).
+	err := d.session(ctx).
 		Where("show_name LIKE ?", "%"+searchParam+"%").
 		Order("latest_msg_send_time DESC").
 		Find(&conversationList).Error
@@ -0,0 +1,153 @@
+package db
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+)
+
+func TestGetConversationListAfterDB(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewDataBase(ctx, "1695766238", MemoryDBDir, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close(ctx)
+	conversations := []*model_struct.LocalConversation{
+		{ConversationID: "si_1", LatestMsgSendTime: 100},
+		{ConversationID: "si_2", LatestMsgSendTime: 300},
+		{ConversationID: "si_3", LatestMsgSendTime: 200, IsPinned: true},
+		{ConversationID: "si_4", LatestMsgSendTime: 300},
+		{ConversationID: "si_5", LatestMsgSendTime: 100, DraftTextTime: 400},
+	}
+	if err := db.BatchInsertConversationList(ctx, conversations); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	var pinned bool
+	var sortTime int64
+	var conversationID string
+	for i := 0; ; i++ {
+		page, err := db.GetConversationListAfterDB(ctx, pinned, sortTime, conversationID, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(page) == 0 {
+			break
+		}
+		for _, c := range page {
+			got = append(got, c.ConversationID)
+		}
+		last := page[len(page)-1]
+		pinned, sortTime, conversationID = last.IsPinned, max(last.LatestMsgSendTime, last.DraftTextTime), last.ConversationID
+		if i == 0 {
+			// a conversation moving ahead of the read position does not shift the next page
+			if err := db.BatchInsertConversationList(ctx, []*model_struct.LocalConversation{{ConversationID: "si_6", LatestMsgSendTime: 500}}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	want := []string{"si_3", "si_5", "si_2", "si_4", "si_1"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
+
+func TestEncryptedDraft(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewDataBase(ctx, "1695766238", MemoryDBDir, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close(ctx)
+	db.SetFieldKey("key")
+	if err := db.InsertConversation(ctx, &model_struct.LocalConversation{ConversationID: "si_1", DraftText: "first"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SetConversationDraftDB(ctx, "si_1", "secret"); err != nil {
+		t.Fatal(err)
+	}
+	var stored string
+	if err := db.conn.Raw("select draft_text from local_conversations where conversation_id = ?", "si_1").Scan(&stored).Error; err != nil {
+		t.Fatal(err)
+	}
+	if stored == "secret" || stored == "" {
+		t.Fatal("draft stored in plain", stored)
+	}
+	c, err := db.GetConversation(ctx, "si_1")
+	if err != nil || c.DraftText != "secret" {
+		t.Fatal(c, err)
+	}
+	// without the key it is not read
+	db.SetFieldKey("")
+	if c, err := db.GetConversation(ctx, "si_1"); err != nil || c.DraftText != "" {
+		t.Fatal(c, err)
+	}
+}
+
+// conversationListFirstPageTarget is the most time the first page of a list of 2000 conversations may take.
+const conversationListFirstPageTarget = 50 * time.Millisecond
+
+func benchmarkConversations(tb testing.TB) *DataBase {
+	ctx := context.Background()
+	db, err := NewDataBase(ctx, "1695766238", MemoryDBDir, 0)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(func() { _ = db.Close(ctx) })
+	latestMsg := strings.Repeat("x", 512)
+	conversations := make([]*model_struct.LocalConversation, 2000)
+	for i := range conversations {
+		conversations[i] = &model_struct.LocalConversation{ConversationID: "si_" + strconv.Itoa(i), ConversationType: 1,
+			UserID: strconv.Itoa(i), LatestMsg: latestMsg, LatestMsgSendTime: int64(i + 1), IsPinned: i%100 == 0}
+	}
+	if err := db.BatchInsertConversationList(ctx, conversations); err != nil {
+		tb.Fatal(err)
+	}
+	return db
+}
+
+func TestConversationListFirstPagePerf(t *testing.T) {
+	db := benchmarkConversations(t)
+	ctx := context.Background()
+	start := time.Now()
+	page, err := db.GetConversationListAfterDB(ctx, false, 0, "", 20)
+	if err != nil || len(page) != 20 {
+		t.Fatal(len(page), err)
+	}
+	if cost := time.Since(start); cost > conversationListFirstPageTarget {
+		t.Fatalf("the first page took %s, the target is %s", cost, conversationListFirstPageTarget)
+	}
+}
+
+func BenchmarkConversationListFirstPage(b *testing.B) {
+	db := benchmarkConversations(b)
+	ctx := context.Background()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := db.GetConversationListAfterDB(ctx, false, 0, "", 20); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkAllConversationList(b *testing.B) {
+	db := benchmarkConversations(b)
+	ctx := context.Background()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := db.GetAllConversationListDB(ctx); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -28,12 +28,10 @@ func (d *DataBase) BatchInsertConversationUnreadMessageList(ctx context.Context,
 	if messageList == nil {
 		return nil
 	}
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
-	return errs.WrapMsg(d.conn.WithContext(ctx).Create(messageList).Error, "BatchInsertConversationUnreadMessageList failed")
+	defer d.lock(ctx)()
+	return errs.WrapMsg(d.session(ctx).Create(messageList).Error, "BatchInsertConversationUnreadMessageList failed")
 }
 func (d *DataBase) DeleteConversationUnreadMessageList(ctx context.Context, conversationID string, sendTime int64) int64 {
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
-	return d.conn.WithContext(ctx).Where("conversation_id = ? and send_time <= ?", conversationID, sendTime).Delete(&model_struct.LocalConversationUnreadMessage{}).RowsAffected
+	defer d.lock(ctx)()
+	return d.session(ctx).Where("conversation_id = ? and send_time <= ?", conversationID, sendTime).Delete(&model_struct.LocalConversationUnreadMessage{}).RowsAffected
 }
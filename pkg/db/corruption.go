@@ -0,0 +1,39 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"context"
+
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+// CorruptionHandler receives the incident of a corrupted database recovered at open.
+type CorruptionHandler func(incident *sdk_struct.DBCorruptionIncident)
+
+type corruptionHandlerKey struct{}
+
+// WithCorruptionHandler makes the database opened with the context report the corruption it recovers from.
+func WithCorruptionHandler(ctx context.Context, handler CorruptionHandler) context.Context {
+	return context.WithValue(ctx, corruptionHandlerKey{}, handler)
+}
+
+func corruptionHandlerOf(ctx context.Context) CorruptionHandler {
+	handler, _ := ctx.Value(corruptionHandlerKey{}).(CorruptionHandler)
+	if handler == nil {
+		return func(*sdk_struct.DBCorruptionIncident) {}
+	}
+	return handler
+}
@@ -0,0 +1,36 @@
+//go:build !js
+// +build !js
+
+package db
+
+import (
+	"context"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/tools/errs"
+	"gorm.io/gorm/clause"
+)
+
+func (d *DataBase) SetCustomerServiceSessions(ctx context.Context, sessions []*model_struct.LocalCustomerServiceSession) error {
+	if len(sessions) == 0 {
+		return nil
+	}
+	defer d.lock(ctx)()
+	return errs.WrapMsg(d.session(ctx).Clauses(clause.OnConflict{UpdateAll: true}).Create(sessions).Error, "SetCustomerServiceSessions failed")
+}
+
+func (d *DataBase) GetCustomerServiceSession(ctx context.Context, sessionID string) (*model_struct.LocalCustomerServiceSession, error) {
+	defer d.rlock(ctx)()
+	var session model_struct.LocalCustomerServiceSession
+	return &session, errs.WrapMsg(d.session(ctx).Where("session_id = ?", sessionID).Take(&session).Error, "GetCustomerServiceSession failed")
+}
+
+func (d *DataBase) GetCustomerServiceSessions(ctx context.Context, states []string) ([]*model_struct.LocalCustomerServiceSession, error) {
+	defer d.rlock(ctx)()
+	query := d.session(ctx).Model(&model_struct.LocalCustomerServiceSession{})
+	if len(states) > 0 {
+		query = query.Where("state IN ?", states)
+	}
+	var sessions []*model_struct.LocalCustomerServiceSession
+	return sessions, errs.WrapMsg(query.Order("create_time DESC").Find(&sessions).Error, "GetCustomerServiceSessions failed")
+}
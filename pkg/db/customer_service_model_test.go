@@ -0,0 +1,51 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+)
+
+func TestCustomerServiceModel(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewDataBase(ctx, "1695766238", t.TempDir(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close(ctx)
+	if err := db.SetCustomerServiceSessions(ctx, []*model_struct.LocalCustomerServiceSession{
+		{SessionID: "s1", State: constant.CustomerServiceClosed, CreateTime: 1},
+		{SessionID: "s2", State: constant.CustomerServiceWaiting, QueuePosition: 3, CreateTime: 2},
+		{SessionID: "s3", State: constant.CustomerServiceServing, AgentUserID: "agent", CreateTime: 3},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	sessions, err := db.GetCustomerServiceSessions(ctx, []string{constant.CustomerServiceWaiting, constant.CustomerServiceServing})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions) != 2 || sessions[0].SessionID != "s3" || sessions[1].SessionID != "s2" {
+		t.Fatal(sessions)
+	}
+	if err := db.SetCustomerServiceSessions(ctx, []*model_struct.LocalCustomerServiceSession{
+		{SessionID: "s2", State: constant.CustomerServiceServing, AgentUserID: "agent", CreateTime: 2},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	session, err := db.GetCustomerServiceSession(ctx, "s2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if session.State != constant.CustomerServiceServing || session.QueuePosition != 0 {
+		t.Fatal(session)
+	}
+	sessions, err = db.GetCustomerServiceSessions(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions) != 3 {
+		t.Fatal(sessions)
+	}
+}
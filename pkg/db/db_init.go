@@ -20,20 +20,21 @@ package db
 import (
 	"context"
 	"errors"
+	"os"
 	"path/filepath"
 	"sync"
 	"time"
 
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
 	"github.com/openimsdk/openim-sdk-core/v3/version"
 
-	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 
 	"github.com/openimsdk/tools/errs"
-	"github.com/openimsdk/tools/log"
 )
 
 type TableChecker struct {
@@ -49,9 +50,11 @@ func NewTableChecker(tables []string) *TableChecker {
 	return tc
 }
 
+// InitTableCache replaces the tables of the cache.
 func (tc *TableChecker) InitTableCache(tables []string) {
 	tc.mu.Lock()
 	defer tc.mu.Unlock()
+	tc.tableCache = make(map[string]bool, len(tables))
 	for _, table := range tables {
 		tc.tableCache[table] = true
 	}
@@ -71,9 +74,15 @@ func (tc *TableChecker) UpdateTable(tableName string) {
 type DataBase struct {
 	loginUserID  string
 	dbDir        string
+	dbFileName   string
+	key          string // key of the SQLCipher encryption, empty for a plaintext database
+	pragmas      *sdk_struct.DBPragmas
+	fieldKey     []byte // key of the encrypted columns, nil to store them in plain
+	sqlLogLevel  logger.LogLevel
 	conn         *gorm.DB
 	tableChecker *TableChecker
 	mRWMutex     sync.RWMutex
+	archive      archiveInfo
 }
 
 func (d *DataBase) InitDB(ctx context.Context, userID string, dataDir string) error {
@@ -96,7 +105,13 @@ func (d *DataBase) Close(ctx context.Context) error {
 }
 
 func NewDataBase(ctx context.Context, loginUserID string, dbDir string, logLevel int) (*DataBase, error) {
-	dataBase := &DataBase{loginUserID: loginUserID, dbDir: dbDir}
+	return NewDataBaseWithKey(ctx, loginUserID, dbDir, logLevel, "")
+}
+
+// NewDataBaseWithKey opens the database encrypted with the key, an existing plaintext database is encrypted in
+// place first. An empty key opens a plaintext database.
+func NewDataBaseWithKey(ctx context.Context, loginUserID string, dbDir string, logLevel int, key string) (*DataBase, error) {
+	dataBase := &DataBase{loginUserID: loginUserID, dbDir: dbDir, key: key, pragmas: pragmasOf(ctx)}
 	err := dataBase.initDB(ctx, logLevel)
 	if err != nil {
 		return dataBase, errs.WrapMsg(err, "initDB failed "+dbDir)
@@ -118,12 +133,25 @@ func (d *DataBase) initDB(ctx context.Context, logLevel int) error {
 	d.mRWMutex.Lock()
 	defer d.mRWMutex.Unlock()
 
-	path := d.dbDir + "/OpenIM_" + constant.BigVersion + "_" + d.loginUserID + ".db"
-	dbFileName, err := filepath.Abs(path)
-	if err != nil {
-		return err
+	var (
+		dbFileName string
+		err        error
+	)
+	if d.inMemory() {
+		if d.key != "" {
+			return errs.New("an in-memory database is not encrypted")
+		}
+		// the connections of the pool share the database of the name
+		dbFileName = "file:OpenIM_" + constant.BigVersion + "_" + d.loginUserID + "?mode=memory&cache=shared"
+	} else {
+		if dbFileName, err = DBFileName(d.dbDir, d.loginUserID); err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(dbFileName), 0755); err != nil {
+			return errs.WrapMsg(err, "create db dir failed")
+		}
 	}
-	log.ZInfo(ctx, "sqlite", "path", dbFileName)
+	log.ZInfo(ctx, "sqlite", "path", dbFileName, "encrypted", d.key != "")
 	// slowThreshold := 500
 	// sqlLogger := log.NewSqlLogger(logger.LogLevel(sdk_struct.ServerConf.LogLevel), true, time.Duration(slowThreshold)*time.Millisecond)
 	if logLevel > 5 {
@@ -131,28 +159,38 @@ func (d *DataBase) initDB(ctx context.Context, logLevel int) error {
 	} else {
 		zLogLevel = logger.Silent
 	}
-	var (
-		db *gorm.DB
-	)
-	db, err = gorm.Open(sqlite.Open(dbFileName), &gorm.Config{Logger: log.NewSqlLogger(zLogLevel, false, time.Millisecond*200)})
-	if err != nil {
-		return errs.WrapMsg(err, "open db failed "+dbFileName)
+	d.dbFileName = dbFileName
+	d.sqlLogLevel = zLogLevel
+	if d.key != "" {
+		plaintext, err := isPlaintextDB(dbFileName)
+		if err != nil {
+			return err
+		}
+		if plaintext {
+			log.ZInfo(ctx, "encrypt plaintext db", "dbFileName", dbFileName)
+			if err := encryptPlaintextDB(dbFileName, d.key); err != nil {
+				return errs.WrapMsg(err, "encrypt db failed "+dbFileName)
+			}
+		}
 	}
-
-	log.ZDebug(ctx, "open db success", "dbFileName", dbFileName)
-	sqlDB, err := db.DB()
-	if err != nil {
-		return errs.WrapMsg(err, "get sql db failed")
+	if err := d.open(ctx); err != nil {
+		return err
+	}
+	var problems []string
+	if !d.inMemory() {
+		if problems, err = d.checkIntegrity(ctx); err != nil {
+			return err
+		}
+	}
+	var incident *sdk_struct.DBCorruptionIncident
+	if len(problems) > 0 {
+		if incident, err = d.quarantine(ctx, problems); err != nil {
+			return err
+		}
 	}
-
-	sqlDB.SetConnMaxLifetime(time.Hour * 1)
-	sqlDB.SetMaxOpenConns(3)
-	sqlDB.SetMaxIdleConns(2)
-	sqlDB.SetConnMaxIdleTime(time.Minute * 10)
-	d.conn = db
 
 	// base
-	if err = db.AutoMigrate(&model_struct.LocalAppSDKVersion{}); err != nil {
+	if err = d.conn.AutoMigrate(&model_struct.LocalAppSDKVersion{}); err != nil {
 		return err
 	}
 
@@ -160,6 +198,17 @@ func (d *DataBase) initDB(ctx context.Context, logLevel int) error {
 		return err
 	}
 
+	if err = d.migrateSchema(ctx); err != nil {
+		return err
+	}
+
+	if incident != nil {
+		if err := d.salvage(ctx, incident); err != nil {
+			return err
+		}
+		corruptionHandlerOf(ctx)(incident)
+	}
+
 	//if err := db.Table(constant.SuperGroupTableName).AutoMigrate(superGroup); err != nil {
 	//	return err
 	//}
@@ -167,6 +216,93 @@ func (d *DataBase) initDB(ctx context.Context, logLevel int) error {
 	return nil
 }
 
+func (d *DataBase) inMemory() bool {
+	return d.dbDir == MemoryDBDir
+}
+
+// DBFileName is the path of the database file of the user, see SetFileNameFormat.
+func DBFileName(dbDir, userID string) (string, error) {
+	return filepath.Abs(filepath.Join(dbDir, fileName(userID)))
+}
+
+// open opens the connections of the database file with the key of the database.
+func (d *DataBase) open(ctx context.Context) error {
+	dialector, err := openSqlite(d.dbFileName, d.key, d.pragmas)
+	if err != nil {
+		return err
+	}
+	db, err := gorm.Open(dialector, &gorm.Config{Logger: log.NewSqlLogger(d.sqlLogLevel, false, time.Millisecond*200)})
+	if err != nil {
+		return errs.WrapMsg(err, "open db failed "+d.dbFileName)
+	}
+	if err := registerInstrument(db); err != nil {
+		return err
+	}
+	if err := registerFault(db); err != nil {
+		return err
+	}
+
+	log.ZDebug(ctx, "open db success", "dbFileName", d.dbFileName)
+	sqlDB, err := db.DB()
+	if err != nil {
+		return errs.WrapMsg(err, "get sql db failed")
+	}
+
+	sqlDB.SetMaxOpenConns(3)
+	if d.inMemory() {
+		// the in-memory database is dropped with its last connection, the connections are kept open
+		sqlDB.SetMaxIdleConns(3)
+	} else {
+		sqlDB.SetConnMaxLifetime(time.Hour * 1)
+		sqlDB.SetMaxIdleConns(2)
+		sqlDB.SetConnMaxIdleTime(time.Minute * 10)
+	}
+	d.conn = db
+	return nil
+}
+
+// Rekey encrypts the database with a new key, a plaintext database is encrypted with it. The connections
+// are reopened with the new key once the database is rewritten.
+func (d *DataBase) Rekey(ctx context.Context, newKey string) error {
+	if newKey == "" {
+		return errs.New("empty database key")
+	}
+	if d.inMemory() {
+		return errs.New("an in-memory database is not encrypted")
+	}
+	d.mRWMutex.Lock()
+	defer d.mRWMutex.Unlock()
+	if err := d.Close(ctx); err != nil {
+		return err
+	}
+	var err error
+	if d.key == "" {
+		err = encryptPlaintextDB(d.dbFileName, newKey)
+	} else {
+		err = rekeyDB(d.dbFileName, d.key, newKey)
+	}
+	if err != nil {
+		// the database keeps its key, it is opened again as it was
+		if openErr := d.open(ctx); openErr != nil {
+			log.ZError(ctx, "reopen db failed", openErr, "dbFileName", d.dbFileName)
+		}
+		return errs.WrapMsg(err, "rekey db failed "+d.dbFileName)
+	}
+	if _, err := os.Stat(d.archiveFileName()); err == nil {
+		if d.key == "" {
+			err = encryptPlaintextDB(d.archiveFileName(), newKey)
+		} else {
+			err = rekeyDB(d.archiveFileName(), d.key, newKey)
+		}
+		if err != nil {
+			log.ZError(ctx, "rekey archive db failed", err, "archive", d.archiveFileName())
+		}
+	}
+	d.key = newKey
+	log.ZInfo(ctx, "db rekeyed", "dbFileName", d.dbFileName)
+	return d.open(ctx)
+}
+
 func (d *DataBase) versionDataMigrate(ctx context.Context) error {
 	verModel, err := d.GetAppSDKVersion(ctx)
 	if errs.Unwrap(err) == errs.ErrRecordNotFound {
@@ -185,6 +321,21 @@ func (d *DataBase) versionDataMigrate(ctx context.Context) error {
 			&model_struct.LocalStranger{},
 			&model_struct.LocalSendingMessages{},
 			&model_struct.LocalVersionSync{},
+			&model_struct.LocalPrivacySettings{},
+			&model_struct.LocalMediaPin{},
+			&model_struct.LocalUploadedFile{},
+			&model_struct.LocalE2EEKey{},
+			&model_struct.LocalE2EESession{},
+			&model_struct.LocalE2EEMessageKey{},
+			&model_struct.LocalE2EEDevice{},
+			&model_struct.LocalCallRecord{},
+			&model_struct.LocalMoment{},
+			&model_struct.LocalDepartment{},
+			&model_struct.LocalDepartmentMember{},
+			&model_struct.LocalCustomerServiceSession{},
+			&model_struct.LocalClientConfig{},
+			&model_struct.LocalAuditLog{},
+			&model_struct.LocalMessageTranslation{},
 		)
 		if err != nil {
 			return err
@@ -78,6 +78,13 @@ type MessageModel interface {
 	BatchInsertConversationUnreadMessageList(ctx context.Context, messageList []*model_struct.LocalConversationUnreadMessage) error
 	DeleteConversationUnreadMessageList(ctx context.Context, conversationID string, sendTime int64) int64
 	DeleteConversationMsgs(ctx context.Context, conversationID string, msgIDs []string) error
+	// DeleteOldestMessages deletes at most count of the oldest messages of the conversation, the latest keep
+	// messages and the messages whose media are pinned are never deleted.
+	DeleteOldestMessages(ctx context.Context, conversationID string, count, keep int) (int64, error)
+	// DeleteMessagesBeforeSendTime deletes the messages of the conversation sent before sendTime.
+	DeleteMessagesBeforeSendTime(ctx context.Context, conversationID string, sendTime int64) (int64, error)
+	// GetConversationMsgStorage is the number of messages of the conversation and an estimate of their bytes.
+	GetConversationMsgStorage(ctx context.Context, conversationID string) (count int64, size int64, err error)
 	SetNotificationSeq(ctx context.Context, conversationID string, seq int64) error
 	BatchInsertNotificationSeq(ctx context.Context, notificationSeqs []*model_struct.NotificationSeqs) error
 	GetNotificationAllSeqs(ctx context.Context) ([]*model_struct.NotificationSeqs, error)
@@ -92,6 +99,7 @@ type ConversationModel interface {
 	GetAllSingleConversationIDList(ctx context.Context) (result []string, err error)
 	GetAllConversationIDList(ctx context.Context) (result []string, err error)
 	GetConversationListSplitDB(ctx context.Context, offset, count int) ([]*model_struct.LocalConversation, error)
+	GetConversationListAfterDB(ctx context.Context, pinned bool, sortTime int64, conversationID string, count int) ([]*model_struct.LocalConversation, error)
 	BatchInsertConversationList(ctx context.Context, conversationList []*model_struct.LocalConversation) error
 	UpdateOrCreateConversations(ctx context.Context, conversationList []*model_struct.LocalConversation) error
 	InsertConversation(ctx context.Context, conversationList *model_struct.LocalConversation) error
@@ -153,6 +161,11 @@ type FriendModel interface {
 
 type S3Model interface {
 	GetUpload(ctx context.Context, partHash string) (*model_struct.LocalUpload, error)
+	// GetUploadByFingerprint gets the upload of the file with the fingerprint.
+	GetUploadByFingerprint(ctx context.Context, fingerprint string) (*model_struct.LocalUpload, error)
+	// GetUploadedFile gets the part hashes of the file with the fingerprint once uploaded.
+	GetUploadedFile(ctx context.Context, fingerprint string) (*model_struct.LocalUploadedFile, error)
+	SetUploadedFile(ctx context.Context, file *model_struct.LocalUploadedFile) error
 	InsertUpload(ctx context.Context, upload *model_struct.LocalUpload) error
 	DeleteUpload(ctx context.Context, partHash string) error
 	UpdateUpload(ctx context.Context, upload *model_struct.LocalUpload) error
@@ -174,12 +187,130 @@ type AppSDKVersion interface {
 	SetAppSDKVersion(ctx context.Context, version *model_struct.LocalAppSDKVersion) error
 }
 
+type PrivacyModel interface {
+	GetPrivacySettings(ctx context.Context, userID string) (*model_struct.LocalPrivacySettings, error)
+	SetPrivacySettings(ctx context.Context, settings *model_struct.LocalPrivacySettings) error
+}
+
+type MediaPinModel interface {
+	InsertMediaPin(ctx context.Context, pin *model_struct.LocalMediaPin) error
+	DeleteMediaPin(ctx context.Context, conversationID, clientMsgID string) error
+	GetAllMediaPins(ctx context.Context) ([]*model_struct.LocalMediaPin, error)
+}
+
+type E2EEModel interface {
+	InsertE2EEKeys(ctx context.Context, keys []*model_struct.LocalE2EEKey) error
+	GetE2EEKey(ctx context.Context, keyType int32, keyID string) (*model_struct.LocalE2EEKey, error)
+	GetE2EEKeysByType(ctx context.Context, keyType int32) ([]*model_struct.LocalE2EEKey, error)
+	DeleteE2EEKey(ctx context.Context, keyType int32, keyID string) error
+	GetE2EESession(ctx context.Context, userID, deviceID string) (*model_struct.LocalE2EESession, error)
+	GetE2EESessions(ctx context.Context, userID string) ([]*model_struct.LocalE2EESession, error)
+	GetAllE2EESessions(ctx context.Context) ([]*model_struct.LocalE2EESession, error)
+	SetE2EESession(ctx context.Context, session *model_struct.LocalE2EESession) error
+	DeleteE2EESession(ctx context.Context, userID, deviceID string) error
+	GetE2EEMessageKey(ctx context.Context, clientMsgID string) (*model_struct.LocalE2EEMessageKey, error)
+	SetE2EEMessageKey(ctx context.Context, key *model_struct.LocalE2EEMessageKey) error
+	GetE2EEDevices(ctx context.Context, userID string) ([]*model_struct.LocalE2EEDevice, error)
+	SetE2EEDevice(ctx context.Context, device *model_struct.LocalE2EEDevice) error
+	DeleteE2EEDevice(ctx context.Context, userID, deviceID string) error
+}
+
+type CallRecordModel interface {
+	SetCallRecord(ctx context.Context, record *model_struct.LocalCallRecord) error
+	// GetCallRecordsAfter gets the call records of the filter sorted after the given one by initiate time,
+	// the latest first, from the start when roomID is empty.
+	GetCallRecordsAfter(ctx context.Context, peerUserID, groupID, mediaType string, states []string, missed bool,
+		initiateTime int64, roomID string, count int) ([]*model_struct.LocalCallRecord, error)
+}
+
+type MomentModel interface {
+	SetMoments(ctx context.Context, moments []*model_struct.LocalMoment) error
+	GetMoment(ctx context.Context, momentID string) (*model_struct.LocalMoment, error)
+	// GetMomentsAfter gets the moments, of the user when userID is set, sorted after the given one by create
+	// time, the latest first, from the start when momentID is empty.
+	GetMomentsAfter(ctx context.Context, userID string, createTime int64, momentID string, count int) ([]*model_struct.LocalMoment, error)
+	DeleteMoment(ctx context.Context, momentID string) error
+}
+
+type OrganizationModel interface {
+	InsertDepartment(ctx context.Context, department *model_struct.LocalDepartment) error
+	BatchInsertDepartments(ctx context.Context, departments []*model_struct.LocalDepartment) error
+	UpdateDepartment(ctx context.Context, department *model_struct.LocalDepartment) error
+	DeleteDepartment(ctx context.Context, departmentID string) error
+	DeleteAllDepartments(ctx context.Context) error
+	GetDepartment(ctx context.Context, departmentID string) (*model_struct.LocalDepartment, error)
+	GetAllDepartments(ctx context.Context) ([]*model_struct.LocalDepartment, error)
+	// GetSubDepartments gets the departments of the parent by order, the top departments for an empty parentID.
+	GetSubDepartments(ctx context.Context, parentID string) ([]*model_struct.LocalDepartment, error)
+	InsertDepartmentMember(ctx context.Context, member *model_struct.LocalDepartmentMember) error
+	BatchInsertDepartmentMembers(ctx context.Context, members []*model_struct.LocalDepartmentMember) error
+	UpdateDepartmentMember(ctx context.Context, member *model_struct.LocalDepartmentMember) error
+	DeleteDepartmentMember(ctx context.Context, departmentID, userID string) error
+	DeleteAllDepartmentMembers(ctx context.Context) error
+	GetAllDepartmentMembers(ctx context.Context) ([]*model_struct.LocalDepartmentMember, error)
+	// GetDepartmentMembers gets the members of the departments by order.
+	GetDepartmentMembers(ctx context.Context, departmentIDs []string) ([]*model_struct.LocalDepartmentMember, error)
+	// GetUserDepartmentMembers gets the user in each of the departments the user is in.
+	GetUserDepartmentMembers(ctx context.Context, userID string) ([]*model_struct.LocalDepartmentMember, error)
+	// SearchDepartmentMembers gets the members whose nickname or position has the keyword, or who are in the
+	// given departments, count of them at most.
+	SearchDepartmentMembers(ctx context.Context, keyword string, isSearchNickname, isSearchPosition bool,
+		departmentIDs []string, count int) ([]*model_struct.LocalDepartmentMember, error)
+}
+
+type CustomerServiceModel interface {
+	SetCustomerServiceSessions(ctx context.Context, sessions []*model_struct.LocalCustomerServiceSession) error
+	GetCustomerServiceSession(ctx context.Context, sessionID string) (*model_struct.LocalCustomerServiceSession, error)
+	// GetCustomerServiceSessions gets the sessions in the states, all of them for no state, the latest first.
+	GetCustomerServiceSessions(ctx context.Context, states []string) ([]*model_struct.LocalCustomerServiceSession, error)
+}
+
+type ClientConfigModel interface {
+	GetClientConfig(ctx context.Context) (map[string]string, error)
+	// SetClientConfig replaces the stored config.
+	SetClientConfig(ctx context.Context, configs map[string]string) error
+}
+
+type AuditLogModel interface {
+	InsertAuditLog(ctx context.Context, auditLog *model_struct.LocalAuditLog) error
+	// GetAuditLogs gets the logs of the actions created in [startTime, endTime), any action for no action and
+	// no end for a 0 endTime, the latest first.
+	GetAuditLogs(ctx context.Context, actions []string, startTime, endTime int64, offset, count int) ([]*model_struct.LocalAuditLog, error)
+	// GetAuditLogsToUpload gets the logs not uploaded yet, the oldest first.
+	GetAuditLogsToUpload(ctx context.Context, count int) ([]*model_struct.LocalAuditLog, error)
+	SetAuditLogsUploaded(ctx context.Context, ids []int64) error
+}
+
+type MessageTranslationModel interface {
+	GetMessageTranslation(ctx context.Context, clientMsgID, lang string) (*model_struct.LocalMessageTranslation, error)
+	SetMessageTranslation(ctx context.Context, translation *model_struct.LocalMessageTranslation) error
+	// DeleteMessageTranslations deletes the translations of the messages to any language.
+	DeleteMessageTranslations(ctx context.Context, clientMsgIDs []string) error
+}
+
 type TableMaster interface {
 	GetExistTables(ctx context.Context) ([]string, error)
 }
 type DataBase interface {
 	Close(ctx context.Context) error
 	InitDB(ctx context.Context, userID string, dataDir string) error
+	// Rekey encrypts the database with a new key.
+	Rekey(ctx context.Context, newKey string) error
+	// SetFieldKey sets the key the drafts are encrypted with, independently of the key of the database.
+	SetFieldKey(key string)
+	// BackupTo writes a consistent copy of the database to the file.
+	BackupTo(ctx context.Context, path string) error
+	// SchemaVersion is the latest schema migration applied to the database.
+	SchemaVersion(ctx context.Context) (int, error)
+	// Transaction runs fn in one transaction, the calls with the ctx given to fn are part of it.
+	Transaction(ctx context.Context, fn func(ctx context.Context) error) error
+	// FreePages is the number of unused pages of the database and the number of its pages.
+	FreePages(ctx context.Context) (free int, total int, err error)
+	// Compact gives at most maxPages unused pages back to the file system, all of them when 0.
+	Compact(ctx context.Context, maxPages int, progress func(done, total int)) error
+	// ArchiveMessages moves the messages sent before beforeTime to the archive database, the number of
+	// them is returned.
+	ArchiveMessages(ctx context.Context, beforeTime int64) (int64, error)
 	GroupModel
 	MessageModel
 	ConversationModel
@@ -190,4 +321,14 @@ type DataBase interface {
 	VersionSyncModel
 	AppSDKVersion
 	TableMaster
+	PrivacyModel
+	MediaPinModel
+	E2EEModel
+	CallRecordModel
+	MomentModel
+	OrganizationModel
+	CustomerServiceModel
+	ClientConfigModel
+	AuditLogModel
+	MessageTranslationModel
 }
@@ -17,13 +17,25 @@ package db
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/openimsdk/openim-sdk-core/v3/wasm/exec"
 	"github.com/openimsdk/openim-sdk-core/v3/wasm/indexdb"
+	"github.com/openimsdk/tools/errs"
 )
 
 var ErrType = errors.New("from javascript data type err")
 
+func init() {
+	// the calls of the browser database are recorded by the name of the javascript function, their rows are unknown
+	exec.SetObserver(func(funcName string, cost time.Duration, err error) {
+		if errors.Is(err, errs.ErrRecordNotFound) {
+			err = nil
+		}
+		instrument.record(context.Background(), funcName, -1, cost, err)
+	})
+}
+
 type IndexDB struct {
 	*indexdb.LocalUsers
 	*indexdb.LocalConversations
@@ -39,6 +51,16 @@ type IndexDB struct {
 	*indexdb.LocalVersionSync
 	*indexdb.LocalAppSDKVersion
 	*indexdb.LocalTableMaster
+	*indexdb.LocalPrivacySettings
+	*indexdb.LocalMediaPins
+	*indexdb.LocalE2EE
+	*indexdb.LocalCallRecords
+	*indexdb.LocalMoments
+	*indexdb.LocalOrganization
+	*indexdb.LocalCustomerService
+	*indexdb.LocalClientConfig
+	*indexdb.LocalAuditLogs
+	*indexdb.LocalMessageTranslations
 	loginUserID string
 }
 
@@ -52,6 +74,67 @@ func (i IndexDB) InitDB(ctx context.Context, userID string, dataDir string) erro
 	return err
 }
 
+// Rekey is not supported, the database of the browser is not encrypted by the sdk.
+func (i IndexDB) Rekey(ctx context.Context, newKey string) error {
+	return errs.New("database encryption is not supported in the browser")
+}
+
+// SetFieldKey is not supported, the drafts are kept by the browser as they are.
+func (i IndexDB) SetFieldKey(key string) {}
+
+// BackupTo is not supported, the database of the browser is kept by the browser.
+func (i IndexDB) BackupTo(ctx context.Context, path string) error {
+	return errs.New("local data backup is not supported in the browser")
+}
+
+func (i IndexDB) SchemaVersion(ctx context.Context) (int, error) {
+	return 0, nil
+}
+
+// Transaction runs fn with the puts of its calls held back and sent to the browser in one bulk call once
+// fn returns nil, the other calls are committed on their own.
+func (i IndexDB) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if indexdb.HasBulkWrites(ctx) {
+		return fn(ctx)
+	}
+	ctx = indexdb.WithBulkWrites(ctx)
+	if err := fn(ctx); err != nil {
+		return err
+	}
+	return indexdb.FlushBulkWrites(ctx)
+}
+
+func (i IndexDB) FreePages(ctx context.Context) (free int, total int, err error) {
+	return 0, 0, nil
+}
+
+// Compact does nothing, the storage of the browser is compacted by the browser.
+func (i IndexDB) Compact(ctx context.Context, maxPages int, progress func(done, total int)) error {
+	return nil
+}
+
+// ArchiveMessages is not supported, the browser keeps the messages in one database.
+func (i IndexDB) ArchiveMessages(ctx context.Context, beforeTime int64) (int64, error) {
+	return 0, errs.New("message archive is not supported in the browser")
+}
+
+func LatestSchemaVersion() int {
+	return 0
+}
+
+func DBFileName(dbDir, userID string) (string, error) {
+	return "", errs.New("local data restore is not supported in the browser")
+}
+
+// NewDataBaseWithKey only opens the database without a key, the database of the browser is not encrypted
+// by the sdk.
+func NewDataBaseWithKey(ctx context.Context, loginUserID string, dbDir string, logLevel int, key string) (*IndexDB, error) {
+	if key != "" {
+		return nil, errs.New("database encryption is not supported in the browser")
+	}
+	return NewDataBase(ctx, loginUserID, dbDir, logLevel)
+}
+
 func NewDataBase(ctx context.Context, loginUserID string, dbDir string, logLevel int) (*IndexDB, error) {
 	i := &IndexDB{
 		LocalUsers:                      indexdb.NewLocalUsers(),
@@ -68,6 +151,16 @@ func NewDataBase(ctx context.Context, loginUserID string, dbDir string, logLevel
 		LocalVersionSync:                indexdb.NewLocalVersionSync(),
 		LocalAppSDKVersion:              indexdb.NewLocalAppSDKVersion(),
 		LocalTableMaster:                indexdb.NewLocalTableMaster(),
+		LocalPrivacySettings:            indexdb.NewLocalPrivacySettings(),
+		LocalMediaPins:                  indexdb.NewLocalMediaPins(),
+		LocalE2EE:                       indexdb.NewLocalE2EE(),
+		LocalCallRecords:                indexdb.NewLocalCallRecords(),
+		LocalMoments:                    indexdb.NewLocalMoments(),
+		LocalOrganization:               indexdb.NewLocalOrganization(),
+		LocalCustomerService:            indexdb.NewLocalCustomerService(),
+		LocalClientConfig:               indexdb.NewLocalClientConfig(),
+		LocalAuditLogs:                  indexdb.NewLocalAuditLogs(),
+		LocalMessageTranslations:        indexdb.NewLocalMessageTranslations(),
 		loginUserID:                     loginUserID,
 	}
 	err := i.InitDB(ctx, loginUserID, dbDir)
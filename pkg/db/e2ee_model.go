@@ -0,0 +1,119 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !js
+// +build !js
+
+package db
+
+import (
+	"context"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"gorm.io/gorm/clause"
+
+	"github.com/openimsdk/tools/errs"
+)
+
+func (d *DataBase) InsertE2EEKeys(ctx context.Context, keys []*model_struct.LocalE2EEKey) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	defer d.lock(ctx)()
+	return errs.WrapMsg(d.session(ctx).Clauses(clause.OnConflict{UpdateAll: true}).Create(keys).Error, "InsertE2EEKeys failed")
+}
+
+func (d *DataBase) GetE2EEKey(ctx context.Context, keyType int32, keyID string) (*model_struct.LocalE2EEKey, error) {
+	defer d.rlock(ctx)()
+	var key model_struct.LocalE2EEKey
+	err := d.session(ctx).Where("key_type = ? AND key_id = ?", keyType, keyID).Take(&key).Error
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return &key, nil
+}
+
+func (d *DataBase) GetE2EEKeysByType(ctx context.Context, keyType int32) ([]*model_struct.LocalE2EEKey, error) {
+	defer d.rlock(ctx)()
+	var keys []*model_struct.LocalE2EEKey
+	return keys, errs.WrapMsg(d.session(ctx).Where("key_type = ?", keyType).Order("create_time ASC").Find(&keys).Error, "GetE2EEKeysByType failed")
+}
+
+func (d *DataBase) DeleteE2EEKey(ctx context.Context, keyType int32, keyID string) error {
+	defer d.lock(ctx)()
+	return errs.WrapMsg(d.session(ctx).Where("key_type = ? AND key_id = ?", keyType, keyID).Delete(&model_struct.LocalE2EEKey{}).Error, "DeleteE2EEKey failed")
+}
+
+func (d *DataBase) GetE2EESession(ctx context.Context, userID, deviceID string) (*model_struct.LocalE2EESession, error) {
+	defer d.rlock(ctx)()
+	var session model_struct.LocalE2EESession
+	err := d.session(ctx).Where("user_id = ? AND device_id = ?", userID, deviceID).Take(&session).Error
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return &session, nil
+}
+
+func (d *DataBase) GetE2EESessions(ctx context.Context, userID string) ([]*model_struct.LocalE2EESession, error) {
+	defer d.rlock(ctx)()
+	var sessions []*model_struct.LocalE2EESession
+	return sessions, errs.WrapMsg(d.session(ctx).Where("user_id = ?", userID).Find(&sessions).Error, "GetE2EESessions failed")
+}
+
+func (d *DataBase) GetAllE2EESessions(ctx context.Context) ([]*model_struct.LocalE2EESession, error) {
+	defer d.rlock(ctx)()
+	var sessions []*model_struct.LocalE2EESession
+	return sessions, errs.WrapMsg(d.session(ctx).Find(&sessions).Error, "GetAllE2EESessions failed")
+}
+
+func (d *DataBase) SetE2EESession(ctx context.Context, session *model_struct.LocalE2EESession) error {
+	defer d.lock(ctx)()
+	return errs.WrapMsg(d.session(ctx).Clauses(clause.OnConflict{UpdateAll: true}).Create(session).Error, "SetE2EESession failed")
+}
+
+func (d *DataBase) DeleteE2EESession(ctx context.Context, userID, deviceID string) error {
+	defer d.lock(ctx)()
+	return errs.WrapMsg(d.session(ctx).Where("user_id = ? AND device_id = ?", userID, deviceID).Delete(&model_struct.LocalE2EESession{}).Error, "DeleteE2EESession failed")
+}
+
+func (d *DataBase) GetE2EEMessageKey(ctx context.Context, clientMsgID string) (*model_struct.LocalE2EEMessageKey, error) {
+	defer d.rlock(ctx)()
+	var key model_struct.LocalE2EEMessageKey
+	err := d.session(ctx).Where("client_msg_id = ?", clientMsgID).Take(&key).Error
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return &key, nil
+}
+
+func (d *DataBase) SetE2EEMessageKey(ctx context.Context, key *model_struct.LocalE2EEMessageKey) error {
+	defer d.lock(ctx)()
+	return errs.WrapMsg(d.session(ctx).Clauses(clause.OnConflict{UpdateAll: true}).Create(key).Error, "SetE2EEMessageKey failed")
+}
+
+func (d *DataBase) GetE2EEDevices(ctx context.Context, userID string) ([]*model_struct.LocalE2EEDevice, error) {
+	defer d.rlock(ctx)()
+	var devices []*model_struct.LocalE2EEDevice
+	return devices, errs.WrapMsg(d.session(ctx).Where("user_id = ?", userID).Order("create_time ASC").Find(&devices).Error, "GetE2EEDevices failed")
+}
+
+func (d *DataBase) SetE2EEDevice(ctx context.Context, device *model_struct.LocalE2EEDevice) error {
+	defer d.lock(ctx)()
+	return errs.WrapMsg(d.session(ctx).Clauses(clause.OnConflict{UpdateAll: true}).Create(device).Error, "SetE2EEDevice failed")
+}
+
+func (d *DataBase) DeleteE2EEDevice(ctx context.Context, userID, deviceID string) error {
+	defer d.lock(ctx)()
+	return errs.WrapMsg(d.session(ctx).Where("user_id = ? AND device_id = ?", userID, deviceID).Delete(&model_struct.LocalE2EEDevice{}).Error, "DeleteE2EEDevice failed")
+}
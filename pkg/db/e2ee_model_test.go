@@ -0,0 +1,56 @@
+//go:build !js
+// +build !js
+
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+)
+
+// the sessions kept per user before move to the device of their identity key
+func TestRekeyE2EESessions(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewDataBase(ctx, "1695766238", t.TempDir(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close(ctx)
+	if err := db.conn.Migrator().DropTable(&model_struct.LocalE2EESession{}, &model_struct.LocalE2EEMessageKey{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.conn.Exec("CREATE TABLE local_e2ee_sessions (user_id varchar(64) PRIMARY KEY, identity_key varchar(128), " +
+		"record text, update_time integer)").Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.conn.Exec("INSERT INTO local_e2ee_sessions VALUES ('a', 'aa', '{}', 1), ('b', 'bb', '{}', 2)").Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SetE2EEDevice(ctx, &model_struct.LocalE2EEDevice{UserID: "a", DeviceID: "a1", IdentityKey: "aa"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := rekeyE2EESessions(db.conn); err != nil {
+		t.Fatal(err)
+	}
+	sessions, err := db.GetAllE2EESessions(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions) != 1 || sessions[0].UserID != "a" || sessions[0].DeviceID != "a1" || sessions[0].Record != "{}" {
+		t.Fatal(sessions)
+	}
+	if err := db.SetE2EESession(ctx, &model_struct.LocalE2EESession{UserID: "a", DeviceID: "a2", Record: "{}"}); err != nil {
+		t.Fatal(err)
+	}
+	if sessions, err = db.GetE2EESessions(ctx, "a"); err != nil || len(sessions) != 2 {
+		t.Fatal(sessions, err)
+	}
+	if err := db.SetE2EEMessageKey(ctx, &model_struct.LocalE2EEMessageKey{ClientMsgID: "m", Key: "k"}); err != nil {
+		t.Fatal(err)
+	}
+	if key, err := db.GetE2EEMessageKey(ctx, "m"); err != nil || key.Key != "k" {
+		t.Fatal(key, err)
+	}
+}
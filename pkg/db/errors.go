@@ -0,0 +1,54 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !js && cgo
+// +build !js,cgo
+
+package db
+
+import (
+	"errors"
+
+	"github.com/mattn/go-sqlite3"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/tools/errs"
+	"gorm.io/gorm"
+)
+
+// The sqlite driver only exists in cgo builds, without it no database can be opened to fail.
+func init() {
+	sdkerrs.RegisterClassifier(classifyError)
+}
+
+// classifyError gives the errors of gorm and sqlite the storage code, except a missing record
+// which is the caller asking for something the database does not have.
+func classifyError(err error) errs.CodeError {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return errs.ErrRecordNotFound
+	}
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		return sdkerrs.ErrStorage
+	}
+	for _, gormErr := range []error{gorm.ErrInvalidTransaction, gorm.ErrNotImplemented, gorm.ErrMissingWhereClause,
+		gorm.ErrUnsupportedRelation, gorm.ErrPrimaryKeyRequired, gorm.ErrModelValueRequired, gorm.ErrInvalidData,
+		gorm.ErrUnsupportedDriver, gorm.ErrRegistered, gorm.ErrInvalidField, gorm.ErrEmptySlice, gorm.ErrDryRunModeUnsupported,
+		gorm.ErrInvalidDB, gorm.ErrInvalidValue, gorm.ErrInvalidValueOfLength, gorm.ErrPreloadNotAllowed, gorm.ErrDuplicatedKey,
+		gorm.ErrForeignKeyViolated} {
+		if errors.Is(err, gormErr) {
+			return sdkerrs.ErrStorage
+		}
+	}
+	return nil
+}
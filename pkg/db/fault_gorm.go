@@ -0,0 +1,45 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !js
+// +build !js
+
+package db
+
+import (
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/fault"
+	"github.com/openimsdk/tools/errs"
+	"gorm.io/gorm"
+)
+
+// registerFault fails the writes of the connection drawn by the fault injection, the statement is not run.
+// The callbacks do nothing while the injection is off.
+func registerFault(db *gorm.DB) error {
+	inject := func(tx *gorm.DB) {
+		if err := fault.DBWrite(); err != nil {
+			_ = tx.AddError(err)
+		}
+	}
+	c := db.Callback()
+	for _, err := range []error{
+		c.Create().Before("gorm:create").Register("openim:fault_create", inject),
+		c.Update().Before("gorm:update").Register("openim:fault_update", inject),
+		c.Delete().Before("gorm:delete").Register("openim:fault_delete", inject),
+	} {
+		if err != nil {
+			return errs.WrapMsg(err, "register db fault injection failed")
+		}
+	}
+	return nil
+}
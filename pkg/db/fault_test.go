@@ -0,0 +1,37 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/fault"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+func TestFaultInjection(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewDataBase(ctx, "1695766238", MemoryDBDir, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close(ctx)
+	if err := fault.Configure(&sdk_struct.FaultInjection{DBWriteFailRate: 1}); err != nil {
+		t.Fatal(err)
+	}
+	defer fault.Configure(nil)
+	conversation := &model_struct.LocalConversation{ConversationID: "si_1_2", ConversationType: 1, UserID: "2"}
+	if err := db.InsertConversation(ctx, conversation); !errors.Is(err, fault.ErrInjected) {
+		t.Fatalf("insert err %v", err)
+	}
+	if _, err := db.GetConversation(ctx, conversation.ConversationID); err == nil {
+		t.Fatal("the write failed was stored")
+	}
+	if err := fault.Configure(nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.InsertConversation(ctx, conversation); err != nil {
+		t.Fatal(err)
+	}
+}
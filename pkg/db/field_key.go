@@ -0,0 +1,130 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !js
+// +build !js
+
+package db
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"reflect"
+	"strings"
+
+	"github.com/openimsdk/tools/errs"
+	"gorm.io/gorm/schema"
+)
+
+// encryptedPrefix marks the values of the encrypted columns stored encrypted, the ones without it were
+// written before a field key was set and are read as they are.
+const encryptedPrefix = "enc1:"
+
+func init() {
+	schema.RegisterSerializer("encrypted", encryptedSerializer{})
+}
+
+type fieldKeyContextKey struct{}
+
+// SetFieldKey sets the key of the columns tagged serializer:encrypted, the drafts, independently of the key of
+// the database. An empty key stores them in plain, the ones stored encrypted before are then read empty.
+func (d *DataBase) SetFieldKey(key string) {
+	if key == "" {
+		d.fieldKey = nil
+		return
+	}
+	sum := sha256.Sum256([]byte("OpenIMFieldKey" + key))
+	d.fieldKey = sum[:]
+}
+
+// withFieldKey gives the field key to the serializer of the statements run with the context.
+func (d *DataBase) withFieldKey(ctx context.Context) context.Context {
+	if d.fieldKey == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, fieldKeyContextKey{}, d.fieldKey)
+}
+
+func fieldCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// encryptField encrypts the value with the field key of the context, the value itself without a key.
+func encryptField(ctx context.Context, value string) (string, error) {
+	key, _ := ctx.Value(fieldKeyContextKey{}).([]byte)
+	if key == nil || value == "" {
+		return value, nil
+	}
+	aead, err := fieldCipher(key)
+	if err != nil {
+		return "", errs.WrapMsg(err, "field cipher")
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", errs.WrapMsg(err, "field nonce")
+	}
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(aead.Seal(nonce, nonce, []byte(value), nil)), nil
+}
+
+// decryptField decrypts a value stored encrypted, empty when it can not be without the key it was stored with.
+func decryptField(ctx context.Context, value string) string {
+	if !strings.HasPrefix(value, encryptedPrefix) {
+		return value
+	}
+	key, _ := ctx.Value(fieldKeyContextKey{}).([]byte)
+	if key == nil {
+		return ""
+	}
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, encryptedPrefix))
+	if err != nil {
+		return ""
+	}
+	aead, err := fieldCipher(key)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return ""
+	}
+	plain, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil {
+		return ""
+	}
+	return string(plain)
+}
+
+// encryptedSerializer encrypts a string column with the field key of the database, AES-256-GCM.
+type encryptedSerializer struct{}
+
+func (encryptedSerializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue any) error {
+	var value string
+	switch v := dbValue.(type) {
+	case string:
+		value = v
+	case []byte:
+		value = string(v)
+	}
+	field.ReflectValueOf(ctx, dst).SetString(decryptField(ctx, value))
+	return nil
+}
+
+func (encryptedSerializer) Value(ctx context.Context, _ *schema.Field, _ reflect.Value, fieldValue any) (any, error) {
+	value, _ := fieldValue.(string)
+	return encryptField(ctx, value)
+}
@@ -29,29 +29,25 @@ import (
 )
 
 func (d *DataBase) InsertFriend(ctx context.Context, friend *model_struct.LocalFriend) error {
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
-	return errs.WrapMsg(d.conn.WithContext(ctx).Create(friend).Error, "InsertFriend failed")
+	defer d.lock(ctx)()
+	return errs.WrapMsg(d.session(ctx).Create(friend).Error, "InsertFriend failed")
 }
 
 func (d *DataBase) DeleteFriendDB(ctx context.Context, friendUserID string) error {
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
-	return errs.WrapMsg(d.conn.WithContext(ctx).Where("owner_user_id=? and friend_user_id=?", d.loginUserID, friendUserID).Delete(&model_struct.LocalFriend{}).Error, "DeleteFriend failed")
+	defer d.lock(ctx)()
+	return errs.WrapMsg(d.session(ctx).Where("owner_user_id=? and friend_user_id=?", d.loginUserID, friendUserID).Delete(&model_struct.LocalFriend{}).Error, "DeleteFriend failed")
 }
 
 func (d *DataBase) GetFriendListCount(ctx context.Context) (int64, error) {
-	d.mRWMutex.RLock()
-	defer d.mRWMutex.RUnlock()
+	defer d.rlock(ctx)()
 	var count int64
-	return count, errs.WrapMsg(d.conn.WithContext(ctx).Model(&model_struct.LocalFriend{}).Count(&count).Error, "GetFriendListCount failed")
+	return count, errs.WrapMsg(d.session(ctx).Model(&model_struct.LocalFriend{}).Count(&count).Error, "GetFriendListCount failed")
 }
 
 func (d *DataBase) UpdateFriend(ctx context.Context, friend *model_struct.LocalFriend) error {
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
+	defer d.lock(ctx)()
 
-	t := d.conn.WithContext(ctx).Model(friend).Select("*").Updates(*friend)
+	t := d.session(ctx).Model(friend).Select("*").Updates(*friend)
 	if t.RowsAffected == 0 {
 		return errs.WrapMsg(errors.New("RowsAffected == 0"), "no update")
 	}
@@ -59,43 +55,38 @@ func (d *DataBase) UpdateFriend(ctx context.Context, friend *model_struct.LocalF
 
 }
 func (d *DataBase) GetAllFriendList(ctx context.Context) ([]*model_struct.LocalFriend, error) {
-	d.mRWMutex.RLock()
-	defer d.mRWMutex.RUnlock()
+	defer d.rlock(ctx)()
 	var friendList []*model_struct.LocalFriend
-	return friendList, errs.WrapMsg(d.conn.WithContext(ctx).Where("owner_user_id = ?", d.loginUserID).Find(&friendList).Error,
+	return friendList, errs.WrapMsg(d.session(ctx).Where("owner_user_id = ?", d.loginUserID).Find(&friendList).Error,
 		"GetFriendList failed")
 }
 
 func (d *DataBase) GetPageFriendList(ctx context.Context, offset, count int) ([]*model_struct.LocalFriend, error) {
-	d.mRWMutex.RLock()
-	defer d.mRWMutex.RUnlock()
+	defer d.rlock(ctx)()
 	var friendList []*model_struct.LocalFriend
-	err := errs.WrapMsg(d.conn.WithContext(ctx).Where("owner_user_id = ?", d.loginUserID).Offset(offset).Limit(count).Order("name").Find(&friendList).Error,
+	err := errs.WrapMsg(d.session(ctx).Where("owner_user_id = ?", d.loginUserID).Offset(offset).Limit(count).Order("name").Find(&friendList).Error,
 		"GetFriendList failed")
 	return friendList, err
 }
 
 func (d *DataBase) BatchInsertFriend(ctx context.Context, friendList []*model_struct.LocalFriend) error {
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
+	defer d.lock(ctx)()
 	if friendList == nil {
 		return errs.New("nil").Wrap()
 	}
-	return errs.WrapMsg(d.conn.WithContext(ctx).Create(friendList).Error, "BatchInsertFriendList failed")
+	return errs.WrapMsg(d.session(ctx).Create(friendList).Error, "BatchInsertFriendList failed")
 }
 
 func (d *DataBase) DeleteAllFriend(ctx context.Context) error {
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
-	return errs.WrapMsg(d.conn.WithContext(ctx).Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&model_struct.LocalFriend{}).Error, "DeleteAllFriend failed")
+	defer d.lock(ctx)()
+	return errs.WrapMsg(d.session(ctx).Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&model_struct.LocalFriend{}).Error, "DeleteAllFriend failed")
 }
 
 func (d *DataBase) SearchFriendList(ctx context.Context, keyword string, isSearchUserID, isSearchNickname, isSearchRemark bool) ([]*model_struct.LocalFriend, error) {
-	d.mRWMutex.RLock()
-	defer d.mRWMutex.RUnlock()
+	defer d.rlock(ctx)()
 
 	var friendList []*model_struct.LocalFriend
-	query := d.conn.WithContext(ctx)
+	query := d.session(ctx)
 
 	var conditions []string
 	var args []any
@@ -127,22 +118,19 @@ func (d *DataBase) SearchFriendList(ctx context.Context, keyword string, isSearc
 }
 
 func (d *DataBase) GetFriendInfoByFriendUserID(ctx context.Context, FriendUserID string) (*model_struct.LocalFriend, error) {
-	d.mRWMutex.RLock()
-	defer d.mRWMutex.RUnlock()
+	defer d.rlock(ctx)()
 	var friend model_struct.LocalFriend
-	return &friend, errs.WrapMsg(d.conn.WithContext(ctx).Where("owner_user_id = ? AND friend_user_id = ?",
+	return &friend, errs.WrapMsg(d.session(ctx).Where("owner_user_id = ? AND friend_user_id = ?",
 		d.loginUserID, FriendUserID).Take(&friend).Error, "GetFriendInfoByFriendUserID failed")
 }
 
 func (d *DataBase) GetFriendInfoList(ctx context.Context, friendUserIDList []string) ([]*model_struct.LocalFriend, error) {
-	d.mRWMutex.RLock()
-	defer d.mRWMutex.RUnlock()
+	defer d.rlock(ctx)()
 	var friendList []*model_struct.LocalFriend
-	err := errs.WrapMsg(d.conn.WithContext(ctx).Where("friend_user_id IN ?", friendUserIDList).Find(&friendList).Error, "GetFriendInfoListByFriendUserID failed")
+	err := errs.WrapMsg(d.session(ctx).Where("friend_user_id IN ?", friendUserIDList).Find(&friendList).Error, "GetFriendInfoListByFriendUserID failed")
 	return friendList, err
 }
 func (d *DataBase) UpdateColumnsFriend(ctx context.Context, friendIDs []string, args map[string]any) error {
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
-	return errs.WrapMsg(d.conn.WithContext(ctx).Model(&model_struct.LocalFriend{}).Where("friend_user_id IN ?", friendIDs).Updates(args).Error, "UpdateColumnsFriend failed")
+	defer d.lock(ctx)()
+	return errs.WrapMsg(d.session(ctx).Model(&model_struct.LocalFriend{}).Where("friend_user_id IN ?", friendIDs).Updates(args).Error, "UpdateColumnsFriend failed")
 }
@@ -28,23 +28,20 @@ import (
 )
 
 func (d *DataBase) InsertGroup(ctx context.Context, groupInfo *model_struct.LocalGroup) error {
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
-	return errs.WrapMsg(d.conn.WithContext(ctx).Create(groupInfo).Error, "InsertGroup failed")
+	defer d.lock(ctx)()
+	return errs.WrapMsg(d.session(ctx).Create(groupInfo).Error, "InsertGroup failed")
 }
 
 func (d *DataBase) DeleteGroup(ctx context.Context, groupID string) error {
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
+	defer d.lock(ctx)()
 	localGroup := model_struct.LocalGroup{GroupID: groupID}
-	return errs.WrapMsg(d.conn.WithContext(ctx).Delete(&localGroup).Error, "DeleteGroup failed")
+	return errs.WrapMsg(d.session(ctx).Delete(&localGroup).Error, "DeleteGroup failed")
 }
 
 func (d *DataBase) UpdateGroup(ctx context.Context, groupInfo *model_struct.LocalGroup) error {
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
+	defer d.lock(ctx)()
 
-	t := d.conn.WithContext(ctx).Model(groupInfo).Select("*").Updates(*groupInfo)
+	t := d.session(ctx).Model(groupInfo).Select("*").Updates(*groupInfo)
 	if t.RowsAffected == 0 {
 		return errs.WrapMsg(errors.New("RowsAffected == 0"), "no update")
 	}
@@ -52,46 +49,40 @@ func (d *DataBase) UpdateGroup(ctx context.Context, groupInfo *model_struct.Loca
 }
 
 func (d *DataBase) BatchInsertGroup(ctx context.Context, groupList []*model_struct.LocalGroup) error {
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
-	return errs.WrapMsg(d.conn.WithContext(ctx).Create(groupList).Error, "BatchInsertGroup failed")
+	defer d.lock(ctx)()
+	return errs.WrapMsg(d.session(ctx).Create(groupList).Error, "BatchInsertGroup failed")
 }
 
 func (d *DataBase) DeleteAllGroup(ctx context.Context) error {
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
-	return errs.WrapMsg(d.conn.WithContext(ctx).Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&model_struct.LocalGroup{}).Error, "DeleteAllGroup failed")
+	defer d.lock(ctx)()
+	return errs.WrapMsg(d.session(ctx).Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&model_struct.LocalGroup{}).Error, "DeleteAllGroup failed")
 }
 
 func (d *DataBase) GetJoinedGroupListDB(ctx context.Context) ([]*model_struct.LocalGroup, error) {
-	d.mRWMutex.RLock()
-	defer d.mRWMutex.RUnlock()
+	defer d.rlock(ctx)()
 	var groupList []*model_struct.LocalGroup
-	err := d.conn.WithContext(ctx).Find(&groupList).Error
+	err := d.session(ctx).Find(&groupList).Error
 	return groupList, errs.WrapMsg(err, "GetJoinedGroupList failed ")
 }
 
 func (d *DataBase) GetGroups(ctx context.Context, groupIDs []string) ([]*model_struct.LocalGroup, error) {
-	d.mRWMutex.RLock()
-	defer d.mRWMutex.RUnlock()
+	defer d.rlock(ctx)()
 	var groupList []*model_struct.LocalGroup
-	err := d.conn.WithContext(ctx).Where("group_id in (?)", groupIDs).Find(&groupList).Error
+	err := d.session(ctx).Where("group_id in (?)", groupIDs).Find(&groupList).Error
 	return groupList, errs.WrapMsg(err, "GetGroups failed ")
 }
 
 func (d *DataBase) GetGroupInfoByGroupID(ctx context.Context, groupID string) (*model_struct.LocalGroup, error) {
-	d.mRWMutex.RLock()
-	defer d.mRWMutex.RUnlock()
+	defer d.rlock(ctx)()
 	var g model_struct.LocalGroup
-	return &g, errs.WrapMsg(d.conn.WithContext(ctx).Where("group_id = ?", groupID).Take(&g).Error, "GetGroupList failed")
+	return &g, errs.WrapMsg(d.session(ctx).Where("group_id = ?", groupID).Take(&g).Error, "GetGroupList failed")
 }
 
 func (d *DataBase) GetAllGroupInfoByGroupIDOrGroupName(ctx context.Context, keyword string, isSearchGroupID bool, isSearchGroupName bool) ([]*model_struct.LocalGroup, error) {
-	d.mRWMutex.RLock()
-	defer d.mRWMutex.RUnlock()
+	defer d.rlock(ctx)()
 
 	var groupList []*model_struct.LocalGroup
-	query := d.conn.WithContext(ctx)
+	query := d.session(ctx)
 
 	if isSearchGroupID {
 		if isSearchGroupName {
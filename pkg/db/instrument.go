@@ -0,0 +1,152 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/telemetry"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+const (
+	DefaultSlowQueryThreshold = 200 * time.Millisecond
+	// maxQueryShapes bounds the shapes kept, the queries of other shapes are counted as otherQueryShape
+	maxQueryShapes  = 256
+	otherQueryShape = "other"
+)
+
+// chatLogsTable matches the tables of the messages, one per conversation, which share a shape.
+var chatLogsTable = regexp.MustCompile(`chat_logs_\w+`)
+
+// placeholderList matches the lists of placeholders, which only differ by the length of the IN values.
+var placeholderList = regexp.MustCompile(`\?(\s*,\s*\?)+`)
+
+// instrument records the queries of the database by shape once enabled.
+var instrument = &queryRecorder{shapes: make(map[string]*queryShape)}
+
+type queryRecorder struct {
+	enabled atomic.Bool
+	slow    atomic.Int64
+	lock    sync.Mutex
+	shapes  map[string]*queryShape
+}
+
+type queryShape struct {
+	count, errors, slow, rows int64
+	total, max                time.Duration
+}
+
+// SetInstrumentation turns the recording of the queries on or off, the queries slower than slowThreshold
+// are logged, 0 is the default threshold. The stats recorded so far are kept.
+func SetInstrumentation(enabled bool, slowThreshold time.Duration) {
+	if slowThreshold <= 0 {
+		slowThreshold = DefaultSlowQueryThreshold
+	}
+	instrument.slow.Store(int64(slowThreshold))
+	instrument.enabled.Store(enabled)
+}
+
+// GetQueryStats returns the queries recorded since the instrumentation was turned on, by shape.
+func GetQueryStats() *sdk_struct.DBQueryStats {
+	r := instrument
+	stats := &sdk_struct.DBQueryStats{
+		Enabled:       r.enabled.Load(),
+		SlowThreshold: time.Duration(r.slow.Load()).Milliseconds(),
+	}
+	if stats.SlowThreshold == 0 {
+		stats.SlowThreshold = DefaultSlowQueryThreshold.Milliseconds()
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	for shape, s := range r.shapes {
+		stats.Queries = append(stats.Queries, &sdk_struct.DBQueryShapeStats{
+			Shape:     shape,
+			Count:     s.count,
+			Errors:    s.errors,
+			Slow:      s.slow,
+			Rows:      s.rows,
+			TotalTime: milliseconds(s.total),
+			MaxTime:   milliseconds(s.max),
+			AvgTime:   milliseconds(s.total / time.Duration(s.count)),
+		})
+	}
+	sort.Slice(stats.Queries, func(i, j int) bool {
+		return stats.Queries[i].TotalTime > stats.Queries[j].TotalTime
+	})
+	return stats
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// statementVerb is the kind of a statement, the attribute of its duration in the telemetry.
+func statementVerb(sql string) string {
+	verb, _, _ := strings.Cut(strings.TrimSpace(sql), " ")
+	switch verb = strings.ToUpper(verb); verb {
+	case "SELECT", "INSERT", "UPDATE", "DELETE":
+		return verb
+	default:
+		return "OTHER"
+	}
+}
+
+// record adds a query to the stats of its shape, rows is -1 when unknown.
+func (r *queryRecorder) record(ctx context.Context, sql string, rows int64, cost time.Duration, err error) {
+	if telemetry.Enabled() {
+		telemetry.Record(telemetry.DBQueryDuration, milliseconds(cost),
+			telemetry.String("operation", statementVerb(sql)), telemetry.Bool("error", err != nil))
+	}
+	if !r.enabled.Load() {
+		return
+	}
+	shape := placeholderList.ReplaceAllString(chatLogsTable.ReplaceAllString(sql, "chat_logs_*"), "?,...")
+	slow := cost > time.Duration(r.slow.Load())
+	if slow {
+		log.ZWarn(ctx, "slow db query", err, "shape", shape, "cost", cost, "rows", rows)
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	s, ok := r.shapes[shape]
+	if !ok {
+		if len(r.shapes) >= maxQueryShapes {
+			shape = otherQueryShape
+		}
+		if s, ok = r.shapes[shape]; !ok {
+			s = &queryShape{}
+			r.shapes[shape] = s
+		}
+	}
+	s.count++
+	if err != nil {
+		s.errors++
+	}
+	if slow {
+		s.slow++
+	}
+	if rows > 0 {
+		s.rows += rows
+	}
+	s.total += cost
+	s.max = max(s.max, cost)
+}
@@ -0,0 +1,74 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !js
+// +build !js
+
+package db
+
+import (
+	"errors"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/telemetry"
+	"github.com/openimsdk/tools/errs"
+	"gorm.io/gorm"
+)
+
+const instrumentStartKey = "openim:instrument_start"
+
+// registerInstrument records the statements of the connection, the callbacks do nothing while the
+// instrumentation is off.
+func registerInstrument(db *gorm.DB) error {
+	before := func(tx *gorm.DB) {
+		if instrument.enabled.Load() || telemetry.Enabled() {
+			tx.InstanceSet(instrumentStartKey, time.Now())
+		}
+	}
+	after := func(tx *gorm.DB) {
+		v, ok := tx.InstanceGet(instrumentStartKey)
+		if !ok {
+			return
+		}
+		start, ok := v.(time.Time)
+		if !ok {
+			return
+		}
+		err := tx.Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = nil
+		}
+		instrument.record(tx.Statement.Context, tx.Statement.SQL.String(), tx.RowsAffected, time.Since(start), err)
+	}
+	c := db.Callback()
+	for _, err := range []error{
+		c.Create().Before("gorm:create").Register("openim:instrument_before_create", before),
+		c.Create().After("gorm:create").Register("openim:instrument_after_create", after),
+		c.Query().Before("gorm:query").Register("openim:instrument_before_query", before),
+		c.Query().After("gorm:query").Register("openim:instrument_after_query", after),
+		c.Update().Before("gorm:update").Register("openim:instrument_before_update", before),
+		c.Update().After("gorm:update").Register("openim:instrument_after_update", after),
+		c.Delete().Before("gorm:delete").Register("openim:instrument_before_delete", before),
+		c.Delete().After("gorm:delete").Register("openim:instrument_after_delete", after),
+		c.Row().Before("gorm:row").Register("openim:instrument_before_row", before),
+		c.Row().After("gorm:row").Register("openim:instrument_after_row", after),
+		c.Raw().Before("gorm:raw").Register("openim:instrument_before_raw", before),
+		c.Raw().After("gorm:raw").Register("openim:instrument_after_raw", after),
+	} {
+		if err != nil {
+			return errs.WrapMsg(err, "register db instrumentation failed")
+		}
+	}
+	return nil
+}
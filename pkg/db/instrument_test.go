@@ -0,0 +1,29 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestQueryShape(t *testing.T) {
+	r := &queryRecorder{shapes: make(map[string]*queryShape)}
+	r.slow.Store(int64(time.Second))
+	r.enabled.Store(true)
+	ctx := context.Background()
+	r.record(ctx, "SELECT * FROM `chat_logs_si_1_2` WHERE seq IN (?,?,?)", 3, time.Millisecond, nil)
+	r.record(ctx, "SELECT * FROM `chat_logs_sg_3` WHERE seq IN (?, ?)", 2, 3*time.Millisecond, nil)
+	if len(r.shapes) != 1 {
+		t.Fatalf("want one shape, got %d", len(r.shapes))
+	}
+	s := r.shapes["SELECT * FROM `chat_logs_*` WHERE seq IN (?,...)"]
+	if s == nil || s.count != 2 || s.rows != 5 || s.max != 3*time.Millisecond {
+		t.Fatalf("unexpected stats %+v", s)
+	}
+
+	r.enabled.Store(false)
+	r.record(ctx, "DELETE FROM local_friends", 1, time.Millisecond, nil)
+	if len(r.shapes) != 1 {
+		t.Fatal("recorded while disabled")
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !js
+// +build !js
+
+package db
+
+import (
+	"context"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/tools/errs"
+	"gorm.io/gorm/clause"
+)
+
+func (d *DataBase) InsertMediaPin(ctx context.Context, pin *model_struct.LocalMediaPin) error {
+	defer d.lock(ctx)()
+	return errs.WrapMsg(d.session(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(pin).Error, "InsertMediaPin failed")
+}
+
+func (d *DataBase) DeleteMediaPin(ctx context.Context, conversationID, clientMsgID string) error {
+	defer d.lock(ctx)()
+	return errs.WrapMsg(d.session(ctx).Where("conversation_id = ? AND client_msg_id = ?", conversationID, clientMsgID).
+		Delete(&model_struct.LocalMediaPin{}).Error, "DeleteMediaPin failed")
+}
+
+func (d *DataBase) GetAllMediaPins(ctx context.Context) ([]*model_struct.LocalMediaPin, error) {
+	defer d.rlock(ctx)()
+	var pins []*model_struct.LocalMediaPin
+	return pins, errs.WrapMsg(d.session(ctx).Find(&pins).Error, "GetAllMediaPins failed")
+}
@@ -0,0 +1,20 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+// MemoryDBDir opens the database in memory instead of a file of the dir, for the tests and the sessions
+// that leave nothing on disk. The database is dropped when it is closed. The browser is told the same dir
+// and keeps the store of the session in memory.
+const MemoryDBDir = ":memory:"
@@ -0,0 +1,41 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+)
+
+func TestMemoryDataBase(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewDataBase(ctx, "1695766238", MemoryDBDir, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	versionSync := &model_struct.LocalVersionSync{
+		Table:     "local_group_entities_version",
+		EntityID:  "1076204769",
+		VersionID: "667aabe3417b67f0f0d3cdee",
+		Version:   1,
+	}
+	if err := db.SetVersionSync(ctx, versionSync); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.GetVersionSync(ctx, versionSync.Table, versionSync.EntityID); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// the database is dropped with its connections
+	db, err = NewDataBase(ctx, "1695766238", MemoryDBDir, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close(ctx)
+	if _, err := db.GetVersionSync(ctx, versionSync.Table, versionSync.EntityID); err == nil {
+		t.Fatal("the in-memory database outlived its close")
+	}
+}
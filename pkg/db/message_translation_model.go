@@ -0,0 +1,39 @@
+//go:build !js
+// +build !js
+
+package db
+
+import (
+	"context"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/tools/errs"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (d *DataBase) GetMessageTranslation(ctx context.Context, clientMsgID, lang string) (*model_struct.LocalMessageTranslation, error) {
+	defer d.rlock(ctx)()
+	var translation model_struct.LocalMessageTranslation
+	err := d.session(ctx).Where("client_msg_id = ? AND lang = ?", clientMsgID, lang).Take(&translation).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errs.ErrRecordNotFound.Wrap()
+		}
+		return nil, errs.WrapMsg(err, "GetMessageTranslation failed")
+	}
+	return &translation, nil
+}
+
+func (d *DataBase) SetMessageTranslation(ctx context.Context, translation *model_struct.LocalMessageTranslation) error {
+	defer d.lock(ctx)()
+	return errs.WrapMsg(d.session(ctx).Clauses(clause.OnConflict{UpdateAll: true}).Create(translation).Error, "SetMessageTranslation failed")
+}
+
+func (d *DataBase) DeleteMessageTranslations(ctx context.Context, clientMsgIDs []string) error {
+	if len(clientMsgIDs) == 0 {
+		return nil
+	}
+	defer d.lock(ctx)()
+	return errs.WrapMsg(d.session(ctx).Where("client_msg_id IN ?", clientMsgIDs).Delete(&model_struct.LocalMessageTranslation{}).Error, "DeleteMessageTranslations failed")
+}
@@ -0,0 +1,44 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/tools/errs"
+)
+
+func TestMessageTranslationModel(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewDataBase(ctx, "1695766238", t.TempDir(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close(ctx)
+	for _, translation := range []*model_struct.LocalMessageTranslation{
+		{ClientMsgID: "m1", Lang: "en", Text: "hello"},
+		{ClientMsgID: "m1", Lang: "fr", Text: "bonjour"},
+		{ClientMsgID: "m2", Lang: "en", Text: "bye"},
+		{ClientMsgID: "m1", Lang: "en", Text: "hello!"},
+	} {
+		if err := db.SetMessageTranslation(ctx, translation); err != nil {
+			t.Fatal(err)
+		}
+	}
+	translation, err := db.GetMessageTranslation(ctx, "m1", "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if translation.Text != "hello!" {
+		t.Fatal(translation)
+	}
+	if err := db.DeleteMessageTranslations(ctx, []string{"m1"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.GetMessageTranslation(ctx, "m1", "fr"); !errs.ErrRecordNotFound.Is(errs.Unwrap(err)) {
+		t.Fatal(err)
+	}
+	if _, err := db.GetMessageTranslation(ctx, "m2", "en"); err != nil {
+		t.Fatal(err)
+	}
+}
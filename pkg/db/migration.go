@@ -0,0 +1,215 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !js
+// +build !js
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/tools/errs"
+	"gorm.io/gorm"
+)
+
+// migration is a versioned step of the schema. up and down run in a transaction with the record of the
+// applied version, a step migrating a large table reports its progress with report.
+type migration struct {
+	version int
+	name    string
+	up      func(ctx context.Context, tx *gorm.DB, report func(done, total int)) error
+	// down undoes up, nil when it can not be undone and the backup is restored instead
+	down func(ctx context.Context, tx *gorm.DB, report func(done, total int)) error
+}
+
+// migrateSchema runs the migrations not applied yet in order after backing up the database. A failed
+// migration undoes the ones of the run with their down steps, or restores the backup when they can not
+// be undone, the backup is kept for inspection. The backup is removed once all the migrations succeed.
+func (d *DataBase) migrateSchema(ctx context.Context) error {
+	if err := d.conn.WithContext(ctx).AutoMigrate(&model_struct.LocalSchemaMigration{}); err != nil {
+		return errs.Wrap(err)
+	}
+	applied, err := d.appliedMigrations(ctx)
+	if err != nil {
+		return err
+	}
+	var pending []migration
+	for _, m := range migrations {
+		if _, ok := applied[m.version]; !ok {
+			pending = append(pending, m)
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+	var backup string
+	// an in-memory database is new at each open, its migrations only need undoing
+	if !d.inMemory() {
+		if backup, err = d.backupDB(ctx, pending[0].version); err != nil {
+			return err
+		}
+	}
+	done := make([]migration, 0, len(pending))
+	for _, m := range pending {
+		log.ZInfo(ctx, "schema migration", "version", m.version, "name", m.name)
+		if err := d.runMigration(ctx, m, true); err != nil {
+			log.ZError(ctx, "schema migration failed", err, "version", m.version, "name", m.name, "backup", backup)
+			if rollbackErr := d.rollback(ctx, done); rollbackErr != nil {
+				log.ZError(ctx, "undo schema migrations failed, restore the backup", rollbackErr, "backup", backup)
+				if backup == "" {
+					return errs.WrapMsg(rollbackErr, "undo schema migrations failed")
+				}
+				if restoreErr := d.restoreDB(ctx, backup); restoreErr != nil {
+					return errs.WrapMsg(restoreErr, "restore db backup failed "+backup)
+				}
+			}
+			return errs.WrapMsg(err, fmt.Sprintf("schema migration %d %s failed", m.version, m.name))
+		}
+		done = append(done, m)
+	}
+	if backup == "" {
+		return nil
+	}
+	if err := os.Remove(backup); err != nil && !os.IsNotExist(err) {
+		log.ZWarn(ctx, "remove db backup failed", err, "backup", backup)
+	}
+	return nil
+}
+
+// RollbackMigrations undoes the applied migrations after the version, latest first.
+func (d *DataBase) RollbackMigrations(ctx context.Context, version int) error {
+	d.mRWMutex.Lock()
+	defer d.mRWMutex.Unlock()
+	applied, err := d.appliedMigrations(ctx)
+	if err != nil {
+		return err
+	}
+	var undo []migration
+	for _, m := range migrations {
+		if _, ok := applied[m.version]; ok && m.version > version {
+			undo = append(undo, m)
+		}
+	}
+	return d.rollback(ctx, undo)
+}
+
+// rollback undoes the migrations in reverse order.
+func (d *DataBase) rollback(ctx context.Context, applied []migration) error {
+	for i := len(applied) - 1; i >= 0; i-- {
+		m := applied[i]
+		if m.down == nil {
+			return errs.New("schema migration can not be undone", "version", m.version, "name", m.name)
+		}
+		log.ZInfo(ctx, "undo schema migration", "version", m.version, "name", m.name)
+		if err := d.runMigration(ctx, m, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *DataBase) runMigration(ctx context.Context, m migration, up bool) error {
+	progress := migrationProgressOf(ctx)
+	report := func(done, total int) {
+		progress(m.version, m.name, done, total)
+	}
+	report(0, 0)
+	return d.conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if !up {
+			if err := m.down(ctx, tx, report); err != nil {
+				return err
+			}
+			return tx.Delete(&model_struct.LocalSchemaMigration{}, "version = ?", m.version).Error
+		}
+		if err := m.up(ctx, tx, report); err != nil {
+			return err
+		}
+		return tx.Create(&model_struct.LocalSchemaMigration{Version: m.version, Name: m.name, AppliedTime: time.Now().UnixMilli()}).Error
+	})
+}
+
+// LatestSchemaVersion is the version of the last migration known to the sdk.
+func LatestSchemaVersion() int {
+	if len(migrations) == 0 {
+		return 0
+	}
+	return migrations[len(migrations)-1].version
+}
+
+func (d *DataBase) SchemaVersion(ctx context.Context) (int, error) {
+	var version int
+	err := d.conn.WithContext(ctx).Model(&model_struct.LocalSchemaMigration{}).
+		Select("COALESCE(MAX(version), 0)").Scan(&version).Error
+	return version, errs.Wrap(err)
+}
+
+// BackupTo writes a consistent copy of the database, the copy of an encrypted database is encrypted with
+// the same key.
+func (d *DataBase) BackupTo(ctx context.Context, path string) error {
+	d.mRWMutex.RLock()
+	defer d.mRWMutex.RUnlock()
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return errs.Wrap(d.conn.WithContext(ctx).Exec("VACUUM INTO " + quoteSqlString(path)).Error)
+}
+
+func (d *DataBase) appliedMigrations(ctx context.Context) (map[int]struct{}, error) {
+	var records []*model_struct.LocalSchemaMigration
+	if err := d.conn.WithContext(ctx).Find(&records).Error; err != nil {
+		return nil, errs.Wrap(err)
+	}
+	applied := make(map[int]struct{}, len(records))
+	for _, record := range records {
+		applied[record.Version] = struct{}{}
+	}
+	return applied, nil
+}
+
+// backupDB copies the database before the migrations from the version, the copy of an encrypted database
+// is encrypted with the same key.
+func (d *DataBase) backupDB(ctx context.Context, version int) (string, error) {
+	backup := fmt.Sprintf("%s.v%d.bak", d.dbFileName, version)
+	if err := os.Remove(backup); err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	if err := d.conn.WithContext(ctx).Exec("VACUUM INTO " + quoteSqlString(backup)).Error; err != nil {
+		return "", errs.WrapMsg(err, "backup db failed "+backup)
+	}
+	return backup, nil
+}
+
+// restoreDB replaces the database with the backup and opens it again.
+func (d *DataBase) restoreDB(ctx context.Context, backup string) error {
+	if err := d.Close(ctx); err != nil {
+		return err
+	}
+	if err := os.Rename(backup, d.dbFileName); err != nil {
+		return err
+	}
+	return d.open(ctx)
+}
+
+func init() {
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].version < migrations[j].version
+	})
+}
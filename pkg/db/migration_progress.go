@@ -0,0 +1,36 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import "context"
+
+// MigrationProgress receives the progress of a schema migration, e.g. to show it while a large table is
+// migrated at login. total is 0 while unknown.
+type MigrationProgress func(version int, name string, done, total int)
+
+type migrationProgressKey struct{}
+
+// WithMigrationProgress makes the database opened with the context report the progress of its schema migrations.
+func WithMigrationProgress(ctx context.Context, progress MigrationProgress) context.Context {
+	return context.WithValue(ctx, migrationProgressKey{}, progress)
+}
+
+func migrationProgressOf(ctx context.Context) MigrationProgress {
+	progress, _ := ctx.Value(migrationProgressKey{}).(MigrationProgress)
+	if progress == nil {
+		return func(int, string, int, int) {}
+	}
+	return progress
+}
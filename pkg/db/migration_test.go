@@ -0,0 +1,22 @@
+//go:build !js
+// +build !js
+
+package db
+
+import "testing"
+
+func TestMigrationsVersions(t *testing.T) {
+	seen := make(map[int]struct{}, len(migrations))
+	for i, m := range migrations {
+		if m.version <= 0 || m.name == "" || m.up == nil {
+			t.Fatalf("invalid migration %d %q", m.version, m.name)
+		}
+		if _, ok := seen[m.version]; ok {
+			t.Fatalf("duplicate migration version %d", m.version)
+		}
+		seen[m.version] = struct{}{}
+		if i > 0 && migrations[i-1].version > m.version {
+			t.Fatalf("migrations out of order at version %d", m.version)
+		}
+	}
+}
@@ -0,0 +1,250 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !js
+// +build !js
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"gorm.io/gorm"
+)
+
+// migrations are the versioned steps of the schema. A new step takes the next version and never changes once
+// released, the tables created at install are still created by versionDataMigrate.
+var migrations = []migration{
+	{
+		version: 1,
+		name:    "create local_privacy_settings",
+		up: func(ctx context.Context, tx *gorm.DB, report func(done, total int)) error {
+			return tx.AutoMigrate(&model_struct.LocalPrivacySettings{})
+		},
+		down: func(ctx context.Context, tx *gorm.DB, report func(done, total int)) error {
+			return tx.Migrator().DropTable(&model_struct.LocalPrivacySettings{})
+		},
+	},
+	{
+		version: 2,
+		name:    "index chat_logs by send_time and seq",
+		up: func(ctx context.Context, tx *gorm.DB, report func(done, total int)) error {
+			// the composite index also serves the queries by send_time alone
+			return reindexChatLogs(tx, report, "index_send_time_seq_", "send_time, seq", "index_send_time_")
+		},
+		down: func(ctx context.Context, tx *gorm.DB, report func(done, total int)) error {
+			return reindexChatLogs(tx, report, "index_send_time_", "send_time", "index_send_time_seq_")
+		},
+	},
+	{
+		version: 3,
+		name:    "add fingerprint and part_info to local_uploads",
+		up: func(ctx context.Context, tx *gorm.DB, report func(done, total int)) error {
+			return tx.AutoMigrate(&model_struct.LocalUpload{})
+		},
+		down: func(ctx context.Context, tx *gorm.DB, report func(done, total int)) error {
+			migrator := tx.Migrator()
+			if err := migrator.DropIndex(&model_struct.LocalUpload{}, "Fingerprint"); err != nil {
+				return err
+			}
+			if err := migrator.DropColumn(&model_struct.LocalUpload{}, "Fingerprint"); err != nil {
+				return err
+			}
+			return migrator.DropColumn(&model_struct.LocalUpload{}, "PartInfo")
+		},
+	},
+	{
+		version: 4,
+		name:    "create local_media_pins",
+		up: func(ctx context.Context, tx *gorm.DB, report func(done, total int)) error {
+			return tx.AutoMigrate(&model_struct.LocalMediaPin{})
+		},
+		down: func(ctx context.Context, tx *gorm.DB, report func(done, total int)) error {
+			return tx.Migrator().DropTable(&model_struct.LocalMediaPin{})
+		},
+	},
+	{
+		version: 5,
+		name:    "create local_uploaded_files",
+		up: func(ctx context.Context, tx *gorm.DB, report func(done, total int)) error {
+			return tx.AutoMigrate(&model_struct.LocalUploadedFile{})
+		},
+		down: func(ctx context.Context, tx *gorm.DB, report func(done, total int)) error {
+			return tx.Migrator().DropTable(&model_struct.LocalUploadedFile{})
+		},
+	},
+	{
+		version: 6,
+		name:    "create local_e2ee_keys and local_e2ee_sessions",
+		up: func(ctx context.Context, tx *gorm.DB, report func(done, total int)) error {
+			return tx.AutoMigrate(&model_struct.LocalE2EEKey{}, &model_struct.LocalE2EESession{})
+		},
+		down: func(ctx context.Context, tx *gorm.DB, report func(done, total int)) error {
+			return tx.Migrator().DropTable(&model_struct.LocalE2EEKey{}, &model_struct.LocalE2EESession{})
+		},
+	},
+	{
+		version: 7,
+		name:    "create local_e2ee_devices",
+		up: func(ctx context.Context, tx *gorm.DB, report func(done, total int)) error {
+			return tx.AutoMigrate(&model_struct.LocalE2EEDevice{})
+		},
+		down: func(ctx context.Context, tx *gorm.DB, report func(done, total int)) error {
+			return tx.Migrator().DropTable(&model_struct.LocalE2EEDevice{})
+		},
+	},
+	{
+		version: 8,
+		name:    "create local_call_records",
+		up: func(ctx context.Context, tx *gorm.DB, report func(done, total int)) error {
+			return tx.AutoMigrate(&model_struct.LocalCallRecord{})
+		},
+		down: func(ctx context.Context, tx *gorm.DB, report func(done, total int)) error {
+			return tx.Migrator().DropTable(&model_struct.LocalCallRecord{})
+		},
+	},
+	{
+		version: 9,
+		name:    "create local_moments",
+		up: func(ctx context.Context, tx *gorm.DB, report func(done, total int)) error {
+			return tx.AutoMigrate(&model_struct.LocalMoment{})
+		},
+		down: func(ctx context.Context, tx *gorm.DB, report func(done, total int)) error {
+			return tx.Migrator().DropTable(&model_struct.LocalMoment{})
+		},
+	},
+	{
+		version: 10,
+		name:    "create local_departments and local_department_members",
+		up: func(ctx context.Context, tx *gorm.DB, report func(done, total int)) error {
+			return tx.AutoMigrate(&model_struct.LocalDepartment{}, &model_struct.LocalDepartmentMember{})
+		},
+		down: func(ctx context.Context, tx *gorm.DB, report func(done, total int)) error {
+			return tx.Migrator().DropTable(&model_struct.LocalDepartment{}, &model_struct.LocalDepartmentMember{})
+		},
+	},
+	{
+		version: 11,
+		name:    "create local_customer_service_sessions",
+		up: func(ctx context.Context, tx *gorm.DB, report func(done, total int)) error {
+			return tx.AutoMigrate(&model_struct.LocalCustomerServiceSession{})
+		},
+		down: func(ctx context.Context, tx *gorm.DB, report func(done, total int)) error {
+			return tx.Migrator().DropTable(&model_struct.LocalCustomerServiceSession{})
+		},
+	},
+	{
+		version: 12,
+		name:    "create local_client_configs",
+		up: func(ctx context.Context, tx *gorm.DB, report func(done, total int)) error {
+			return tx.AutoMigrate(&model_struct.LocalClientConfig{})
+		},
+		down: func(ctx context.Context, tx *gorm.DB, report func(done, total int)) error {
+			return tx.Migrator().DropTable(&model_struct.LocalClientConfig{})
+		},
+	},
+	{
+		version: 13,
+		name:    "create local_audit_logs",
+		up: func(ctx context.Context, tx *gorm.DB, report func(done, total int)) error {
+			return tx.AutoMigrate(&model_struct.LocalAuditLog{})
+		},
+		down: func(ctx context.Context, tx *gorm.DB, report func(done, total int)) error {
+			return tx.Migrator().DropTable(&model_struct.LocalAuditLog{})
+		},
+	},
+	{
+		version: 14,
+		name:    "create local_message_translations",
+		up: func(ctx context.Context, tx *gorm.DB, report func(done, total int)) error {
+			return tx.AutoMigrate(&model_struct.LocalMessageTranslation{})
+		},
+		down: func(ctx context.Context, tx *gorm.DB, report func(done, total int)) error {
+			return tx.Migrator().DropTable(&model_struct.LocalMessageTranslation{})
+		},
+	},
+	{
+		version: 15,
+		name:    "key local_e2ee_sessions by device and create local_e2ee_message_keys",
+		up: func(ctx context.Context, tx *gorm.DB, report func(done, total int)) error {
+			return rekeyE2EESessions(tx)
+		},
+		// the sessions of a user are merged back into one only by restoring the backup
+		down: nil,
+	},
+}
+
+// rekeyE2EESessions recreates the table of the sessions with their device, the device of a session is the
+// known one of its identity key. A session of no known device is dropped, the next message establishes one.
+func rekeyE2EESessions(tx *gorm.DB) error {
+	var sessions []*model_struct.LocalE2EESession
+	if err := tx.Raw("SELECT user_id, identity_key, record, update_time FROM local_e2ee_sessions").Scan(&sessions).Error; err != nil {
+		return err
+	}
+	var devices []*model_struct.LocalE2EEDevice
+	if err := tx.Find(&devices).Error; err != nil {
+		return err
+	}
+	deviceIDs := make(map[[2]string]string, len(devices))
+	for _, device := range devices {
+		deviceIDs[[2]string{device.UserID, device.IdentityKey}] = device.DeviceID
+	}
+	migrator := tx.Migrator()
+	if err := migrator.DropTable(&model_struct.LocalE2EESession{}); err != nil {
+		return err
+	}
+	if err := migrator.CreateTable(&model_struct.LocalE2EESession{}); err != nil {
+		return err
+	}
+	if err := tx.AutoMigrate(&model_struct.LocalE2EEMessageKey{}); err != nil {
+		return err
+	}
+	for _, session := range sessions {
+		deviceID, ok := deviceIDs[[2]string{session.UserID, session.IdentityKey}]
+		if !ok {
+			continue
+		}
+		session.DeviceID = deviceID
+		if err := tx.Create(session).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reindexChatLogs creates the index of the columns on each table of the messages and drops the index it
+// replaces, the indexes are named by their prefix and the conversation ID.
+func reindexChatLogs(tx *gorm.DB, report func(done, total int), prefix, columns, dropPrefix string) error {
+	var tables []string
+	if err := tx.Raw("SELECT name FROM sqlite_master WHERE type = 'table' AND name LIKE ?",
+		constant.ChatLogsTableNamePre+"%").Scan(&tables).Error; err != nil {
+		return err
+	}
+	for i, table := range tables {
+		conversationID := strings.TrimPrefix(table, constant.ChatLogsTableNamePre)
+		if err := tx.Exec(fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s (%s)",
+			quoteIdent(prefix+conversationID), quoteIdent(table), columns)).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DROP INDEX IF EXISTS " + quoteIdent(dropPrefix+conversationID)).Error; err != nil {
+			return err
+		}
+		report(i+1, len(tables))
+	}
+	return nil
+}
@@ -203,7 +203,7 @@ type LocalConversation struct {
 	GroupAtType           int32  `gorm:"column:group_at_type" json:"groupAtType"`
 	LatestMsg             string `gorm:"column:latest_msg;type:varchar(1000)" json:"latestMsg"`
 	LatestMsgSendTime     int64  `gorm:"column:latest_msg_send_time;index:index_latest_msg_send_time" json:"latestMsgSendTime"`
-	DraftText             string `gorm:"column:draft_text" json:"draftText"`
+	DraftText             string `gorm:"column:draft_text;serializer:encrypted" json:"draftText"`
 	DraftTextTime         int64  `gorm:"column:draft_text_time" json:"draftTextTime"`
 	IsPinned              bool   `gorm:"column:is_pinned" json:"isPinned"`
 	IsPrivateChat         bool   `gorm:"column:is_private_chat" json:"isPrivateChat"`
@@ -253,12 +253,28 @@ type LocalUpload struct {
 	UploadInfo string `gorm:"column:upload_info;type:varchar(2000)" json:"uploadInfo"`
 	ExpireTime int64  `gorm:"column:expire_time" json:"expireTime"`
 	CreateTime int64  `gorm:"column:create_time" json:"createTime"`
+	// Fingerprint tells the file did not change since its parts were hashed, PartInfo keeps the hashes
+	Fingerprint string `gorm:"column:fingerprint;type:varchar(64);index" json:"fingerprint"`
+	PartInfo    string `gorm:"column:part_info" json:"partInfo"`
 }
 
 func (LocalUpload) TableName() string {
 	return "local_uploads"
 }
 
+// LocalUploadedFile keeps the part hashes of a file uploaded, the server is asked for the object of the hash
+// when it is sent again, without hashing it again.
+type LocalUploadedFile struct {
+	Fingerprint string `gorm:"column:fingerprint;primary_key;type:varchar(64)" json:"fingerprint"`
+	PartHash    string `gorm:"column:part_hash;type:varchar(64)" json:"partHash"`
+	PartInfo    string `gorm:"column:part_info" json:"partInfo"`
+	CreateTime  int64  `gorm:"column:create_time;index" json:"createTime"`
+}
+
+func (LocalUploadedFile) TableName() string {
+	return "local_uploaded_files"
+}
+
 type LocalStranger struct {
 	UserID           string `gorm:"column:user_id;primary_key;type:varchar(64)" json:"userID"`
 	Nickname         string `gorm:"column:name;type:varchar(255)" json:"nickname"`
@@ -319,3 +335,256 @@ type LocalAppSDKVersion struct {
 func (LocalAppSDKVersion) TableName() string {
 	return "local_app_sdk_version"
 }
+
+// LocalSchemaMigration is a versioned schema migration applied to the database.
+type LocalSchemaMigration struct {
+	Version     int    `gorm:"column:version;primary_key" json:"version"`
+	Name        string `gorm:"column:name;type:varchar(255)" json:"name"`
+	AppliedTime int64  `gorm:"column:applied_time" json:"appliedTime"`
+}
+
+func (LocalSchemaMigration) TableName() string {
+	return "local_schema_migration"
+}
+
+type LocalPrivacySettings struct {
+	UserID              string `gorm:"column:user_id;primary_key;type:varchar(64)" json:"userID"`
+	AddFriendPermission int32  `gorm:"column:add_friend_permission" json:"addFriendPermission"`
+	ProfileVisibility   int32  `gorm:"column:profile_visibility" json:"profileVisibility"`
+	ReadReceiptDisabled bool   `gorm:"column:read_receipt_disabled" json:"readReceiptDisabled"`
+	LastSeenVisibility  int32  `gorm:"column:last_seen_visibility" json:"lastSeenVisibility"`
+	UpdateTime          int64  `gorm:"column:update_time" json:"updateTime"`
+}
+
+func (LocalPrivacySettings) TableName() string {
+	return "local_privacy_settings"
+}
+
+// LocalMediaPin is a message whose media files are kept by the media cache and the storage quota.
+type LocalMediaPin struct {
+	ConversationID string `gorm:"column:conversation_id;primary_key;type:varchar(128)" json:"conversationID"`
+	ClientMsgID    string `gorm:"column:client_msg_id;primary_key;type:varchar(64)" json:"clientMsgID"`
+	CreateTime     int64  `gorm:"column:create_time" json:"createTime"`
+}
+
+func (LocalMediaPin) TableName() string {
+	return "local_media_pins"
+}
+
+// LocalE2EEKey is a private key of the end-to-end encryption of the login user, by its type and ID.
+type LocalE2EEKey struct {
+	KeyType    int32  `gorm:"column:key_type;primary_key" json:"keyType"`
+	KeyID      string `gorm:"column:key_id;primary_key;type:varchar(64)" json:"keyID"`
+	KeyData    string `gorm:"column:key_data" json:"keyData"`
+	CreateTime int64  `gorm:"column:create_time" json:"createTime"`
+}
+
+func (LocalE2EEKey) TableName() string {
+	return "local_e2ee_keys"
+}
+
+// LocalE2EESession is the encryption sessions with a device of a user, Record is the e2ee.Record in json.
+type LocalE2EESession struct {
+	UserID      string `gorm:"column:user_id;primary_key;type:varchar(64)" json:"userID"`
+	DeviceID    string `gorm:"column:device_id;primary_key;type:varchar(64)" json:"deviceID"`
+	IdentityKey string `gorm:"column:identity_key;type:varchar(128)" json:"identityKey"`
+	Record      string `gorm:"column:record" json:"record"`
+	UpdateTime  int64  `gorm:"column:update_time" json:"updateTime"`
+}
+
+func (LocalE2EESession) TableName() string {
+	return "local_e2ee_sessions"
+}
+
+// LocalE2EEMessageKey is the key an encrypted message was decrypted with, Key is the e2ee.MessageKey in json.
+// It decrypts the message again when it is pulled again, the sessions are past it.
+type LocalE2EEMessageKey struct {
+	ClientMsgID string `gorm:"column:client_msg_id;primary_key;type:varchar(64)" json:"clientMsgID"`
+	Key         string `gorm:"column:key" json:"key"`
+	CreateTime  int64  `gorm:"column:create_time" json:"createTime"`
+}
+
+func (LocalE2EEMessageKey) TableName() string {
+	return "local_e2ee_message_keys"
+}
+
+// LocalE2EEDevice is a device of a user known by its identity, and whether the login user verified it.
+type LocalE2EEDevice struct {
+	UserID      string `gorm:"column:user_id;primary_key;type:varchar(64)" json:"userID"`
+	DeviceID    string `gorm:"column:device_id;primary_key;type:varchar(64)" json:"deviceID"`
+	IdentityKey string `gorm:"column:identity_key;type:varchar(128)" json:"identityKey"`
+	SigningKey  string `gorm:"column:signing_key;type:varchar(128)" json:"signingKey"`
+	Verified    bool   `gorm:"column:verified" json:"verified"`
+	CreateTime  int64  `gorm:"column:create_time" json:"createTime"`
+}
+
+func (LocalE2EEDevice) TableName() string {
+	return "local_e2ee_devices"
+}
+
+// LocalCallRecord is a call of the login user once it is over, as this device saw it.
+type LocalCallRecord struct {
+	RoomID         string   `gorm:"column:room_id;primary_key;type:varchar(64)" json:"roomID"`
+	InviterUserID  string   `gorm:"column:inviter_user_id;type:varchar(64)" json:"inviterUserID"`
+	InviteeUserIDs []string `gorm:"column:invitee_user_ids;serializer:json" json:"inviteeUserIDs"`
+	// PeerUserID is the other user of a single call, empty for a group call
+	PeerUserID  string `gorm:"column:peer_user_id;type:varchar(64);index:index_call_peer" json:"peerUserID"`
+	GroupID     string `gorm:"column:group_id;type:varchar(64);index:index_call_group" json:"groupID"`
+	SessionType int32  `gorm:"column:session_type" json:"sessionType"`
+	MediaType   string `gorm:"column:media_type;type:varchar(16)" json:"mediaType"`
+	State       string `gorm:"column:state;type:varchar(16)" json:"state"`
+	// Missed is a call the login user was invited to and did not answer
+	Missed       bool  `gorm:"column:missed" json:"missed"`
+	Duration     int64 `gorm:"column:duration" json:"duration"`
+	InitiateTime int64 `gorm:"column:initiate_time;index:index_call_initiate_time" json:"initiateTime"`
+	EndTime      int64 `gorm:"column:end_time" json:"endTime"`
+}
+
+func (LocalCallRecord) TableName() string {
+	return "local_call_records"
+}
+
+// MomentMedia is an image or a video of a moment, uploaded before the moment is published.
+type MomentMedia struct {
+	Type         string `json:"type"`
+	URL          string `json:"url"`
+	ThumbnailURL string `json:"thumbnailURL,omitempty"`
+	Width        int32  `json:"width,omitempty"`
+	Height       int32  `json:"height,omitempty"`
+	// Duration is the seconds of a video
+	Duration int64 `json:"duration,omitempty"`
+}
+
+type MomentComment struct {
+	CommentID string `json:"commentID"`
+	UserID    string `json:"userID"`
+	// ReplyToUserID is the user of the comment replied to, empty for a comment of the moment itself
+	ReplyToUserID string `json:"replyToUserID,omitempty"`
+	Content       string `json:"content"`
+	CreateTime    int64  `json:"createTime"`
+}
+
+// LocalMoment is a moment of the feed cached as last fetched or notified, with its likes and comments.
+type LocalMoment struct {
+	MomentID       string           `gorm:"column:moment_id;primary_key;type:varchar(64)" json:"momentID"`
+	UserID         string           `gorm:"column:user_id;type:varchar(64);index:index_moment_user" json:"userID"`
+	Content        string           `gorm:"column:content;type:text" json:"content"`
+	Medias         []*MomentMedia   `gorm:"column:medias;serializer:json" json:"medias"`
+	Visibility     int32            `gorm:"column:visibility" json:"visibility"`
+	VisibleUserIDs []string         `gorm:"column:visible_user_ids;serializer:json" json:"visibleUserIDs,omitempty"`
+	LikeUserIDs    []string         `gorm:"column:like_user_ids;serializer:json" json:"likeUserIDs"`
+	Comments       []*MomentComment `gorm:"column:comments;serializer:json" json:"comments"`
+	CreateTime     int64            `gorm:"column:create_time;index:index_moment_create_time" json:"createTime"`
+	Ex             string           `gorm:"column:ex;type:varchar(1024)" json:"ex"`
+}
+
+func (LocalMoment) TableName() string {
+	return "local_moments"
+}
+
+// LocalDepartment is a department of the organization, ParentID is empty for the top departments.
+type LocalDepartment struct {
+	DepartmentID string `gorm:"column:department_id;primary_key;type:varchar(64)" json:"departmentID"`
+	ParentID     string `gorm:"column:parent_id;type:varchar(64);index:index_department_parent" json:"parentID"`
+	Name         string `gorm:"column:name;type:varchar(255)" json:"name"`
+	FaceURL      string `gorm:"column:face_url;type:varchar(255)" json:"faceURL"`
+	// Order sorts the departments of a parent, the lower first
+	Order       int32  `gorm:"column:sort_order" json:"order"`
+	MemberCount int32  `gorm:"column:member_count" json:"memberCount"`
+	CreateTime  int64  `gorm:"column:create_time" json:"createTime"`
+	Ex          string `gorm:"column:ex;type:varchar(1024)" json:"ex"`
+}
+
+func (LocalDepartment) TableName() string {
+	return "local_departments"
+}
+
+// LocalDepartmentMember is a user in a department, a user can be in several departments.
+type LocalDepartmentMember struct {
+	DepartmentID string `gorm:"column:department_id;primary_key;type:varchar(64)" json:"departmentID"`
+	UserID       string `gorm:"column:user_id;primary_key;type:varchar(64);index:index_department_member_user" json:"userID"`
+	Nickname     string `gorm:"column:nickname;type:varchar(255)" json:"nickname"`
+	FaceURL      string `gorm:"column:face_url;type:varchar(255)" json:"faceURL"`
+	// Position is the title of the user in the department
+	Position  string `gorm:"column:position;type:varchar(255)" json:"position"`
+	Email     string `gorm:"column:email;type:varchar(255)" json:"email"`
+	Telephone string `gorm:"column:telephone;type:varchar(32)" json:"telephone"`
+	Order     int32  `gorm:"column:sort_order" json:"order"`
+	EntryTime int64  `gorm:"column:entry_time" json:"entryTime"`
+	Ex        string `gorm:"column:ex;type:varchar(1024)" json:"ex"`
+}
+
+func (LocalDepartmentMember) TableName() string {
+	return "local_department_members"
+}
+
+// LocalCustomerServiceSession is a customer-service session of the login user, as a customer or as an agent.
+// The customer and the agent talk in the conversation of the session.
+type LocalCustomerServiceSession struct {
+	SessionID      string `gorm:"column:session_id;primary_key;type:varchar(64)" json:"sessionID"`
+	ConversationID string `gorm:"column:conversation_id;type:varchar(128)" json:"conversationID"`
+	CustomerUserID string `gorm:"column:customer_user_id;type:varchar(64)" json:"customerUserID"`
+	AgentUserID    string `gorm:"column:agent_user_id;type:varchar(64)" json:"agentUserID"`
+	// Queue is the queue of agents the session was requested from, like a skill group
+	Queue string `gorm:"column:queue;type:varchar(64)" json:"queue"`
+	// State is waiting, serving or closed, see constant.CustomerService*
+	State string `gorm:"column:state;type:varchar(16);index:index_customer_service_state" json:"state"`
+	// QueuePosition is the sessions waiting before this one, while it is waiting
+	QueuePosition int32  `gorm:"column:queue_position" json:"queuePosition"`
+	Rating        int32  `gorm:"column:rating" json:"rating"`
+	RatingComment string `gorm:"column:rating_comment;type:varchar(1024)" json:"ratingComment"`
+	CreateTime    int64  `gorm:"column:create_time" json:"createTime"`
+	AssignTime    int64  `gorm:"column:assign_time" json:"assignTime"`
+	CloseTime     int64  `gorm:"column:close_time" json:"closeTime"`
+	CloseReason   string `gorm:"column:close_reason;type:varchar(255)" json:"closeReason"`
+	Ex            string `gorm:"column:ex;type:varchar(1024)" json:"ex"`
+}
+
+func (LocalCustomerServiceSession) TableName() string {
+	return "local_customer_service_sessions"
+}
+
+// LocalClientConfig is a flag of the client config defined by the server, the last one fetched, used while
+// the server can't be reached.
+type LocalClientConfig struct {
+	Key   string `gorm:"column:key;primary_key;type:varchar(128)" json:"key"`
+	Value string `gorm:"column:value;type:text" json:"value"`
+}
+
+func (LocalClientConfig) TableName() string {
+	return "local_client_configs"
+}
+
+// LocalAuditLog is a security-relevant action of the login user done through the sdk, see pkg/audit.
+type LocalAuditLog struct {
+	ID          int64  `gorm:"column:id;primary_key;autoIncrement" json:"id"`
+	UserID      string `gorm:"column:user_id;type:varchar(64)" json:"userID"`
+	OperationID string `gorm:"column:operation_id;type:varchar(128)" json:"operationID"`
+	Action      string `gorm:"column:action;type:varchar(32);index:index_audit_action" json:"action"`
+	// Operation is the function of the sdk the action was done by
+	Operation string `gorm:"column:operation;type:varchar(64)" json:"operation"`
+	// Target is the conversation, group or file the action was done to
+	Target string `gorm:"column:target;type:varchar(255)" json:"target"`
+	// Detail is the JSON of the arguments of the action
+	Detail     string `gorm:"column:detail;type:text" json:"detail"`
+	CreateTime int64  `gorm:"column:create_time;index:index_audit_create_time" json:"createTime"`
+	Uploaded   bool   `gorm:"column:uploaded" json:"uploaded"`
+}
+
+func (LocalAuditLog) TableName() string {
+	return "local_audit_logs"
+}
+
+// LocalMessageTranslation is the translation of the text of a message to a language, cached until the
+// message is edited or revoked.
+type LocalMessageTranslation struct {
+	ClientMsgID    string `gorm:"column:client_msg_id;primary_key;type:varchar(64)" json:"clientMsgID"`
+	Lang           string `gorm:"column:lang;primary_key;type:varchar(32)" json:"lang"`
+	ConversationID string `gorm:"column:conversation_id;type:varchar(128)" json:"conversationID"`
+	Text           string `gorm:"column:text;type:text" json:"text"`
+	CreateTime     int64  `gorm:"column:create_time" json:"createTime"`
+}
+
+func (LocalMessageTranslation) TableName() string {
+	return "local_message_translations"
+}
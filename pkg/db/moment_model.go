@@ -0,0 +1,44 @@
+//go:build !js
+// +build !js
+
+package db
+
+import (
+	"context"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/tools/errs"
+	"gorm.io/gorm/clause"
+)
+
+func (d *DataBase) SetMoments(ctx context.Context, moments []*model_struct.LocalMoment) error {
+	if len(moments) == 0 {
+		return nil
+	}
+	defer d.lock(ctx)()
+	return errs.WrapMsg(d.session(ctx).Clauses(clause.OnConflict{UpdateAll: true}).Create(moments).Error, "SetMoments failed")
+}
+
+func (d *DataBase) GetMoment(ctx context.Context, momentID string) (*model_struct.LocalMoment, error) {
+	defer d.rlock(ctx)()
+	var moment model_struct.LocalMoment
+	return &moment, errs.WrapMsg(d.session(ctx).Where("moment_id = ?", momentID).Take(&moment).Error, "GetMoment failed")
+}
+
+func (d *DataBase) GetMomentsAfter(ctx context.Context, userID string, createTime int64, momentID string, count int) ([]*model_struct.LocalMoment, error) {
+	defer d.rlock(ctx)()
+	query := d.session(ctx).Model(&model_struct.LocalMoment{})
+	if userID != "" {
+		query = query.Where("user_id = ?", userID)
+	}
+	if momentID != "" {
+		query = query.Where("create_time < ? OR (create_time = ? AND moment_id > ?)", createTime, createTime, momentID)
+	}
+	var moments []*model_struct.LocalMoment
+	return moments, errs.WrapMsg(query.Order("create_time DESC,moment_id").Limit(count).Find(&moments).Error, "GetMomentsAfter failed")
+}
+
+func (d *DataBase) DeleteMoment(ctx context.Context, momentID string) error {
+	defer d.lock(ctx)()
+	return errs.WrapMsg(d.session(ctx).Where("moment_id = ?", momentID).Delete(&model_struct.LocalMoment{}).Error, "DeleteMoment failed")
+}
@@ -0,0 +1,50 @@
+package db
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+)
+
+func TestGetMomentsAfter(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewDataBase(ctx, "1695766238", t.TempDir(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close(ctx)
+	var moments []*model_struct.LocalMoment
+	for i := 1; i <= 4; i++ {
+		moments = append(moments, &model_struct.LocalMoment{
+			MomentID:   strconv.Itoa(i),
+			UserID:     "user" + strconv.Itoa(i%2),
+			Comments:   []*model_struct.MomentComment{{CommentID: "c", Content: "comment"}},
+			CreateTime: int64(i),
+		})
+	}
+	if err := db.SetMoments(ctx, moments); err != nil {
+		t.Fatal(err)
+	}
+	list, err := db.GetMomentsAfter(ctx, "", 0, "", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[0].MomentID != "4" || list[1].MomentID != "3" || len(list[0].Comments) != 1 {
+		t.Fatal(list)
+	}
+	list, err = db.GetMomentsAfter(ctx, "user1", 3, "3", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].MomentID != "1" {
+		t.Fatal(list)
+	}
+	if err := db.DeleteMoment(ctx, "1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.GetMoment(ctx, "1"); err == nil {
+		t.Fatal("the moment is not deleted")
+	}
+}
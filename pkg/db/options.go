@@ -0,0 +1,115 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+
+	"github.com/openimsdk/tools/errs"
+)
+
+// UserIDPlaceholder is replaced by the user ID in the name of the database file.
+const UserIDPlaceholder = "{userID}"
+
+var defaultFileNameFormat = "OpenIM_" + constant.BigVersion + "_" + UserIDPlaceholder + ".db"
+
+var fileNameFormat atomic.Value // string
+
+// SetFileNameFormat sets the name of the database files, the format holds UserIDPlaceholder. The default name
+// is used when empty. Set before the databases are opened, the opened ones keep their file.
+func SetFileNameFormat(format string) error {
+	if err := CheckFileNameFormat(format); err != nil {
+		return err
+	}
+	fileNameFormat.Store(format)
+	return nil
+}
+
+// CheckFileNameFormat checks the name holds the user ID and no directory.
+func CheckFileNameFormat(format string) error {
+	if format == "" {
+		return nil
+	}
+	if !strings.Contains(format, UserIDPlaceholder) {
+		return errs.New("db file name must contain " + UserIDPlaceholder)
+	}
+	if strings.ContainsAny(format, `/\?`) {
+		return errs.New("db file name must not contain a directory or a query")
+	}
+	return nil
+}
+
+func fileName(userID string) string {
+	format, _ := fileNameFormat.Load().(string)
+	if format == "" {
+		format = defaultFileNameFormat
+	}
+	return strings.ReplaceAll(format, UserIDPlaceholder, userID)
+}
+
+type pragmasKey struct{}
+
+// WithPragmas makes the database opened with the context run the pragmas on each new connection, nil for the
+// sqlite defaults. The pragmas are checked by CheckPragmas before.
+func WithPragmas(ctx context.Context, p *sdk_struct.DBPragmas) context.Context {
+	return context.WithValue(ctx, pragmasKey{}, p)
+}
+
+func pragmasOf(ctx context.Context) *sdk_struct.DBPragmas {
+	p, _ := ctx.Value(pragmasKey{}).(*sdk_struct.DBPragmas)
+	return p
+}
+
+// CheckPragmas checks the values of the pragmas.
+func CheckPragmas(p *sdk_struct.DBPragmas) error {
+	if p == nil {
+		return nil
+	}
+	switch strings.ToUpper(p.JournalMode) {
+	case "", "DELETE", "TRUNCATE", "PERSIST", "MEMORY", "WAL", "OFF":
+	default:
+		return errs.New("invalid journal mode " + p.JournalMode)
+	}
+	switch strings.ToUpper(p.Synchronous) {
+	case "", "OFF", "NORMAL", "FULL", "EXTRA":
+	default:
+		return errs.New("invalid synchronous " + p.Synchronous)
+	}
+	return nil
+}
+
+// pragmaValues are the pragmas set, by their name.
+func pragmaValues(p *sdk_struct.DBPragmas) [][2]string {
+	if p == nil {
+		return nil
+	}
+	var values [][2]string
+	if p.JournalMode != "" {
+		values = append(values, [2]string{"journal_mode", strings.ToUpper(p.JournalMode)})
+	}
+	if p.Synchronous != "" {
+		values = append(values, [2]string{"synchronous", strings.ToUpper(p.Synchronous)})
+	}
+	if p.CacheSize != 0 {
+		values = append(values, [2]string{"cache_size", fmt.Sprint(p.CacheSize)})
+	}
+	return values
+}
@@ -0,0 +1,44 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+func TestPragmaDSN(t *testing.T) {
+	pragmas := &sdk_struct.DBPragmas{JournalMode: "wal", Synchronous: "normal", CacheSize: -4000}
+	if err := CheckPragmas(pragmas); err != nil {
+		t.Fatal(err)
+	}
+	if dsn := pragmaDSN("a.db", pragmas); dsn != "a.db?_journal_mode=WAL&_synchronous=NORMAL&_cache_size=-4000" {
+		t.Fatal(dsn)
+	}
+	if dsn := pragmaDSN("file:a?mode=memory", pragmas); dsn != "file:a?mode=memory&_journal_mode=WAL&_synchronous=NORMAL&_cache_size=-4000" {
+		t.Fatal(dsn)
+	}
+	// the pragmas are of the context the database is opened with
+	if p := pragmasOf(WithPragmas(context.Background(), pragmas)); p != pragmas {
+		t.Fatal(p)
+	}
+	if dsn := pragmaDSN("a.db", pragmasOf(context.Background())); dsn != "a.db" {
+		t.Fatal(dsn)
+	}
+	if err := CheckPragmas(&sdk_struct.DBPragmas{JournalMode: "fast"}); err == nil {
+		t.Fatal("invalid journal mode accepted")
+	}
+}
+
+func TestFileNameFormat(t *testing.T) {
+	defer SetFileNameFormat("")
+	if err := SetFileNameFormat("im.db"); err == nil {
+		t.Fatal("file name without the user ID accepted")
+	}
+	if err := SetFileNameFormat("im_{userID}.db"); err != nil {
+		t.Fatal(err)
+	}
+	if name := fileName("u1"); name != "im_u1.db" {
+		t.Fatal(name)
+	}
+}
@@ -0,0 +1,142 @@
+//go:build !js
+// +build !js
+
+package db
+
+import (
+	"context"
+	"strings"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/tools/errs"
+)
+
+func (d *DataBase) InsertDepartment(ctx context.Context, department *model_struct.LocalDepartment) error {
+	defer d.lock(ctx)()
+	return errs.WrapMsg(d.session(ctx).Create(department).Error, "InsertDepartment failed")
+}
+
+func (d *DataBase) BatchInsertDepartments(ctx context.Context, departments []*model_struct.LocalDepartment) error {
+	if len(departments) == 0 {
+		return nil
+	}
+	defer d.lock(ctx)()
+	return errs.WrapMsg(d.session(ctx).Create(departments).Error, "BatchInsertDepartments failed")
+}
+
+func (d *DataBase) UpdateDepartment(ctx context.Context, department *model_struct.LocalDepartment) error {
+	defer d.lock(ctx)()
+	t := d.session(ctx).Model(department).Select("*").Updates(*department)
+	if t.RowsAffected == 0 {
+		return errs.WrapMsg(errs.New("RowsAffected == 0"), "no update")
+	}
+	return errs.WrapMsg(t.Error, "UpdateDepartment failed")
+}
+
+func (d *DataBase) DeleteDepartment(ctx context.Context, departmentID string) error {
+	defer d.lock(ctx)()
+	return errs.WrapMsg(d.session(ctx).Where("department_id = ?", departmentID).Delete(&model_struct.LocalDepartment{}).Error, "DeleteDepartment failed")
+}
+
+func (d *DataBase) DeleteAllDepartments(ctx context.Context) error {
+	defer d.lock(ctx)()
+	return errs.WrapMsg(d.session(ctx).Where("1 = 1").Delete(&model_struct.LocalDepartment{}).Error, "DeleteAllDepartments failed")
+}
+
+func (d *DataBase) GetDepartment(ctx context.Context, departmentID string) (*model_struct.LocalDepartment, error) {
+	defer d.rlock(ctx)()
+	var department model_struct.LocalDepartment
+	return &department, errs.WrapMsg(d.session(ctx).Where("department_id = ?", departmentID).Take(&department).Error, "GetDepartment failed")
+}
+
+func (d *DataBase) GetAllDepartments(ctx context.Context) ([]*model_struct.LocalDepartment, error) {
+	defer d.rlock(ctx)()
+	var departments []*model_struct.LocalDepartment
+	return departments, errs.WrapMsg(d.session(ctx).Order("sort_order,department_id").Find(&departments).Error, "GetAllDepartments failed")
+}
+
+func (d *DataBase) GetSubDepartments(ctx context.Context, parentID string) ([]*model_struct.LocalDepartment, error) {
+	defer d.rlock(ctx)()
+	var departments []*model_struct.LocalDepartment
+	return departments, errs.WrapMsg(d.session(ctx).Where("parent_id = ?", parentID).Order("sort_order,department_id").Find(&departments).Error, "GetSubDepartments failed")
+}
+
+func (d *DataBase) InsertDepartmentMember(ctx context.Context, member *model_struct.LocalDepartmentMember) error {
+	defer d.lock(ctx)()
+	return errs.WrapMsg(d.session(ctx).Create(member).Error, "InsertDepartmentMember failed")
+}
+
+func (d *DataBase) BatchInsertDepartmentMembers(ctx context.Context, members []*model_struct.LocalDepartmentMember) error {
+	if len(members) == 0 {
+		return nil
+	}
+	defer d.lock(ctx)()
+	return errs.WrapMsg(d.session(ctx).Create(members).Error, "BatchInsertDepartmentMembers failed")
+}
+
+func (d *DataBase) UpdateDepartmentMember(ctx context.Context, member *model_struct.LocalDepartmentMember) error {
+	defer d.lock(ctx)()
+	t := d.session(ctx).Model(member).Select("*").Updates(*member)
+	if t.RowsAffected == 0 {
+		return errs.WrapMsg(errs.New("RowsAffected == 0"), "no update")
+	}
+	return errs.WrapMsg(t.Error, "UpdateDepartmentMember failed")
+}
+
+func (d *DataBase) DeleteDepartmentMember(ctx context.Context, departmentID, userID string) error {
+	defer d.lock(ctx)()
+	return errs.WrapMsg(d.session(ctx).Where("department_id = ? AND user_id = ?", departmentID, userID).Delete(&model_struct.LocalDepartmentMember{}).Error, "DeleteDepartmentMember failed")
+}
+
+func (d *DataBase) DeleteAllDepartmentMembers(ctx context.Context) error {
+	defer d.lock(ctx)()
+	return errs.WrapMsg(d.session(ctx).Where("1 = 1").Delete(&model_struct.LocalDepartmentMember{}).Error, "DeleteAllDepartmentMembers failed")
+}
+
+func (d *DataBase) GetAllDepartmentMembers(ctx context.Context) ([]*model_struct.LocalDepartmentMember, error) {
+	defer d.rlock(ctx)()
+	var members []*model_struct.LocalDepartmentMember
+	return members, errs.WrapMsg(d.session(ctx).Find(&members).Error, "GetAllDepartmentMembers failed")
+}
+
+func (d *DataBase) GetDepartmentMembers(ctx context.Context, departmentIDs []string) ([]*model_struct.LocalDepartmentMember, error) {
+	defer d.rlock(ctx)()
+	var members []*model_struct.LocalDepartmentMember
+	return members, errs.WrapMsg(d.session(ctx).Where("department_id IN ?", departmentIDs).Order("sort_order,user_id").Find(&members).Error, "GetDepartmentMembers failed")
+}
+
+func (d *DataBase) GetUserDepartmentMembers(ctx context.Context, userID string) ([]*model_struct.LocalDepartmentMember, error) {
+	defer d.rlock(ctx)()
+	var members []*model_struct.LocalDepartmentMember
+	return members, errs.WrapMsg(d.session(ctx).Where("user_id = ?", userID).Find(&members).Error, "GetUserDepartmentMembers failed")
+}
+
+func (d *DataBase) SearchDepartmentMembers(ctx context.Context, keyword string, isSearchNickname, isSearchPosition bool,
+	departmentIDs []string, count int) ([]*model_struct.LocalDepartmentMember, error) {
+	defer d.rlock(ctx)()
+	var (
+		conditions []string
+		args       []any
+	)
+	if isSearchNickname {
+		conditions = append(conditions, "nickname LIKE ?")
+		args = append(args, "%"+keyword+"%")
+	}
+	if isSearchPosition {
+		conditions = append(conditions, "position LIKE ?")
+		args = append(args, "%"+keyword+"%")
+	}
+	if len(departmentIDs) > 0 {
+		conditions = append(conditions, "department_id IN ?")
+		args = append(args, departmentIDs)
+	}
+	var members []*model_struct.LocalDepartmentMember
+	if len(conditions) == 0 {
+		return members, nil
+	}
+	query := d.session(ctx).Where("("+strings.Join(conditions, " OR ")+")", args...).Order("sort_order,user_id")
+	if count > 0 {
+		query = query.Limit(count)
+	}
+	return members, errs.WrapMsg(query.Find(&members).Error, "SearchDepartmentMembers failed")
+}
@@ -0,0 +1,69 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+)
+
+func TestOrganizationModel(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewDataBase(ctx, "1695766238", t.TempDir(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close(ctx)
+	if err := db.BatchInsertDepartments(ctx, []*model_struct.LocalDepartment{
+		{DepartmentID: "rd", Name: "R&D", Order: 1},
+		{DepartmentID: "sales", Name: "Sales", Order: 0},
+		{DepartmentID: "mobile", ParentID: "rd", Name: "Mobile"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	top, err := db.GetSubDepartments(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(top) != 2 || top[0].DepartmentID != "sales" || top[1].DepartmentID != "rd" {
+		t.Fatal(top)
+	}
+	if err := db.BatchInsertDepartmentMembers(ctx, []*model_struct.LocalDepartmentMember{
+		{DepartmentID: "mobile", UserID: "u1", Nickname: "Alice", Position: "Engineer"},
+		{DepartmentID: "sales", UserID: "u1", Nickname: "Alice", Position: "Advisor"},
+		{DepartmentID: "sales", UserID: "u2", Nickname: "Bob", Position: "Manager"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	members, err := db.GetUserDepartmentMembers(ctx, "u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(members) != 2 {
+		t.Fatal(members)
+	}
+	members, err = db.SearchDepartmentMembers(ctx, "manager", true, true, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(members) != 1 || members[0].UserID != "u2" {
+		t.Fatal(members)
+	}
+	members, err = db.SearchDepartmentMembers(ctx, "nobody", true, false, []string{"mobile"}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(members) != 1 || members[0].UserID != "u1" {
+		t.Fatal(members)
+	}
+	if err := db.DeleteDepartmentMember(ctx, "sales", "u1"); err != nil {
+		t.Fatal(err)
+	}
+	members, err = db.GetDepartmentMembers(ctx, []string{"sales"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(members) != 1 || members[0].UserID != "u2" {
+		t.Fatal(members)
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !js
+// +build !js
+
+package db
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// maxPreparedStmts bounds the statements kept prepared, each table of the messages has its own. The cache is
+// dropped beyond it and prepared again by the next calls.
+const maxPreparedStmts = 256
+
+// prepared is the session of the hot queries of fixed shape, their statements are prepared once per
+// connection and reused by the next calls instead of being compiled again. Queries with a list of values of
+// varying length, like IN, are not for it.
+func (d *DataBase) prepared(ctx context.Context) *gorm.DB {
+	tx := d.session(ctx).Session(&gorm.Session{PrepareStmt: true})
+	if stmts, ok := tx.Statement.ConnPool.(*gorm.PreparedStmtDB); ok {
+		stmts.Mux.RLock()
+		full := len(stmts.Stmts) > maxPreparedStmts
+		stmts.Mux.RUnlock()
+		if full {
+			// the map is shared by the sessions, Reset would only replace the one of this session
+			stmts.Mux.Lock()
+			for query, stmt := range stmts.Stmts {
+				delete(stmts.Stmts, query)
+				go stmt.Close()
+			}
+			stmts.Mux.Unlock()
+		}
+	}
+	return tx
+}
@@ -0,0 +1,32 @@
+//go:build !js
+// +build !js
+
+package db
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/tools/errs"
+)
+
+func (d *DataBase) GetPrivacySettings(ctx context.Context, userID string) (*model_struct.LocalPrivacySettings, error) {
+	defer d.rlock(ctx)()
+	var settings model_struct.LocalPrivacySettings
+	err := d.session(ctx).Where("user_id = ?", userID).Take(&settings).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errs.ErrRecordNotFound.Wrap()
+		}
+		return nil, errs.Wrap(err)
+	}
+	return &settings, nil
+}
+
+func (d *DataBase) SetPrivacySettings(ctx context.Context, settings *model_struct.LocalPrivacySettings) error {
+	defer d.lock(ctx)()
+	return errs.WrapMsg(d.session(ctx).Save(settings).Error, "SetPrivacySettings failed")
+}
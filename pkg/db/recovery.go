@@ -0,0 +1,212 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !js
+// +build !js
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+	"github.com/openimsdk/tools/errs"
+	"gorm.io/gorm"
+)
+
+const (
+	brokenSchema = "broken"
+	// salvageChunkRows is the rowid range copied at a time from a table that can not be copied whole, a
+	// corrupted page only loses the rows of its ranges.
+	salvageChunkRows = 1000
+)
+
+// checkIntegrity runs a quick check of the database, the problems found are returned. An error is only
+// returned when the database could not be checked for another reason than a corruption, e.g. a wrong key.
+func (d *DataBase) checkIntegrity(ctx context.Context) ([]string, error) {
+	var results []string
+	err := d.conn.WithContext(ctx).Raw("PRAGMA quick_check").Scan(&results).Error
+	if err != nil {
+		if isCorruptionErr(err, d.key) {
+			return []string{err.Error()}, nil
+		}
+		return nil, errs.Wrap(err)
+	}
+	if len(results) == 1 && results[0] == "ok" {
+		return nil, nil
+	}
+	return results, nil
+}
+
+// isCorruptionErr tells whether sqlite failed on a damaged file. An encrypted database opened with a wrong
+// key is not a database either, it is not taken for a corrupted one.
+func isCorruptionErr(err error, key string) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "malformed") || (key == "" && strings.Contains(msg, "not a database"))
+}
+
+// quarantine moves the corrupted database with its journal files aside and opens a new empty database.
+func (d *DataBase) quarantine(ctx context.Context, problems []string) (*sdk_struct.DBCorruptionIncident, error) {
+	incident := &sdk_struct.DBCorruptionIncident{
+		DBFile:     d.dbFileName,
+		Quarantine: fmt.Sprintf("%s.corrupt-%d", d.dbFileName, time.Now().UnixMilli()),
+		Problems:   problems,
+	}
+	log.ZError(ctx, "db corrupted, quarantine it", nil, "dbFileName", d.dbFileName, "quarantine", incident.Quarantine,
+		"problems", problems)
+	if err := d.Close(ctx); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(d.dbFileName, incident.Quarantine); err != nil {
+		return nil, errs.WrapMsg(err, "quarantine db failed "+d.dbFileName)
+	}
+	// the wal holds the last commits, it goes along with the database
+	for _, suffix := range []string{"-wal", "-shm"} {
+		if err := os.Rename(d.dbFileName+suffix, incident.Quarantine+suffix); err != nil && !os.IsNotExist(err) {
+			log.ZWarn(ctx, "quarantine db file failed", err, "file", d.dbFileName+suffix)
+		}
+	}
+	if err := d.open(ctx); err != nil {
+		return nil, err
+	}
+	return incident, nil
+}
+
+// salvage copies the rows still readable from the quarantined database into the new one, whose schema is
+// created already. The tables created on demand, like the ones of the messages, are created from the
+// schema of the quarantined database.
+func (d *DataBase) salvage(ctx context.Context, incident *sdk_struct.DBCorruptionIncident) error {
+	return d.conn.WithContext(ctx).Connection(func(tx *gorm.DB) error {
+		if err := tx.Exec(attachStatement(incident.Quarantine, brokenSchema, d.key)).Error; err != nil {
+			return errs.WrapMsg(err, "attach quarantined db failed "+incident.Quarantine)
+		}
+		defer tx.Exec("DETACH DATABASE " + brokenSchema)
+		var tables []struct {
+			Name string
+			Sql  string
+		}
+		err := tx.Raw("SELECT name, sql FROM " + brokenSchema + ".sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%'").
+			Scan(&tables).Error
+		if err != nil {
+			// not even the schema is readable, the scopes of all the tables are pulled again
+			incident.Problems = append(incident.Problems, err.Error())
+			incident.Lost = append(incident.Lost, model_struct.LocalVersionSync{}.TableName())
+			return nil
+		}
+		for _, table := range tables {
+			created, err := d.ensureSalvageTable(ctx, tx, table.Name, table.Sql)
+			if err != nil {
+				log.ZWarn(ctx, "create salvaged table failed", err, "table", table.Name)
+				incident.Lost = append(incident.Lost, table.Name)
+				continue
+			}
+			copied, failed := salvageTable(tx, table.Name)
+			log.ZInfo(ctx, "salvage table", "table", table.Name, "created", created, "copied", copied, "failed", failed)
+			switch {
+			case failed == 0:
+				incident.Salvaged = append(incident.Salvaged, table.Name)
+			case copied == 0:
+				incident.Lost = append(incident.Lost, table.Name)
+			default:
+				incident.Damaged = append(incident.Damaged, table.Name)
+			}
+		}
+		return nil
+	})
+}
+
+// ensureSalvageTable creates the table missing from the new database with the schema of the quarantined
+// one, with its indexes. The table checker loads the tables once the database is open.
+func (d *DataBase) ensureSalvageTable(ctx context.Context, tx *gorm.DB, table, createSql string) (bool, error) {
+	var count int
+	if err := tx.Raw("SELECT count(*) FROM main.sqlite_master WHERE type = 'table' AND name = ?", table).Scan(&count).Error; err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return false, nil
+	}
+	if err := tx.Exec(createSql).Error; err != nil {
+		return false, err
+	}
+	var indexes []string
+	_ = tx.Raw("SELECT sql FROM "+brokenSchema+".sqlite_master WHERE type = 'index' AND tbl_name = ? AND sql IS NOT NULL", table).
+		Scan(&indexes).Error
+	for _, index := range indexes {
+		if err := tx.Exec(index).Error; err != nil {
+			log.ZWarn(ctx, "create salvaged index failed", err, "table", table)
+		}
+	}
+	return true, nil
+}
+
+// salvageTable copies the rows of the columns both tables have, whole or else range by range. copied and
+// failed are the parts copied and not copied.
+func salvageTable(tx *gorm.DB, table string) (copied int, failed int) {
+	columns, err := commonColumns(tx, table, brokenSchema)
+	if err != nil || len(columns) == 0 {
+		return 0, 1
+	}
+	insert := fmt.Sprintf("INSERT OR IGNORE INTO main.%s (%s) SELECT %s FROM %s.%s", quoteIdent(table), columns, columns,
+		brokenSchema, quoteIdent(table))
+	if err := tx.Exec(insert).Error; err == nil {
+		return 1, 0
+	}
+	var maxRowID int64
+	if err := tx.Raw(fmt.Sprintf("SELECT COALESCE(MAX(rowid), 0) FROM %s.%s", brokenSchema, quoteIdent(table))).Scan(&maxRowID).Error; err != nil {
+		return 0, 1
+	}
+	for from := int64(0); from < maxRowID; from += salvageChunkRows {
+		if err := tx.Exec(insert+" WHERE rowid > ? AND rowid <= ?", from, from+salvageChunkRows).Error; err != nil {
+			failed++
+		} else {
+			copied++
+		}
+	}
+	return copied, failed
+}
+
+// commonColumns are the columns the table has both in the main database and in the schema.
+func commonColumns(tx *gorm.DB, table, schema string) (string, error) {
+	columnsOf := func(schema string) ([]string, error) {
+		var columns []string
+		err := tx.Raw(fmt.Sprintf("SELECT name FROM pragma_table_info(%s, %s)", quoteSqlString(table), quoteSqlString(schema))).
+			Scan(&columns).Error
+		return columns, err
+	}
+	mainColumns, err := columnsOf("main")
+	if err != nil {
+		return "", err
+	}
+	schemaColumns, err := columnsOf(schema)
+	if err != nil {
+		return "", err
+	}
+	other := make(map[string]struct{}, len(schemaColumns))
+	for _, column := range schemaColumns {
+		other[column] = struct{}{}
+	}
+	var common []string
+	for _, column := range mainColumns {
+		if _, ok := other[column]; ok {
+			common = append(common, quoteIdent(column))
+		}
+	}
+	return strings.Join(common, ", "), nil
+}
@@ -25,19 +25,16 @@ import (
 )
 
 func (d *DataBase) InsertSendingMessage(ctx context.Context, message *model_struct.LocalSendingMessages) error {
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
-	return errs.WrapMsg(d.conn.WithContext(ctx).Create(message).Error, "InsertSendingMessage failed")
+	defer d.lock(ctx)()
+	return errs.WrapMsg(d.session(ctx).Create(message).Error, "InsertSendingMessage failed")
 }
 
 func (d *DataBase) DeleteSendingMessage(ctx context.Context, conversationID, clientMsgID string) error {
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
+	defer d.lock(ctx)()
 	localSendingMessage := model_struct.LocalSendingMessages{ConversationID: conversationID, ClientMsgID: clientMsgID}
-	return errs.WrapMsg(d.conn.WithContext(ctx).Delete(&localSendingMessage).Error, "DeleteSendingMessage failed")
+	return errs.WrapMsg(d.session(ctx).Delete(&localSendingMessage).Error, "DeleteSendingMessage failed")
 }
 func (d *DataBase) GetAllSendingMessages(ctx context.Context) (friendRequests []*model_struct.LocalSendingMessages, err error) {
-	d.mRWMutex.RLock()
-	defer d.mRWMutex.RUnlock()
-	return friendRequests, errs.WrapMsg(d.conn.WithContext(ctx).Find(&friendRequests).Error, "GetAllSendingMessages failed")
+	defer d.rlock(ctx)()
+	return friendRequests, errs.WrapMsg(d.session(ctx).Find(&friendRequests).Error, "GetAllSendingMessages failed")
 }
@@ -25,19 +25,17 @@ import (
 )
 
 func (d *DataBase) GetMinSeq(ctx context.Context, ID string) (uint32, error) {
-	d.mRWMutex.RLock()
-	defer d.mRWMutex.RUnlock()
+	defer d.rlock(ctx)()
 	var seqData model_struct.LocalSeq
-	return seqData.MinSeq, errs.WrapMsg(d.conn.WithContext(ctx).First(&seqData).Error, "GetMinSeq failed")
+	return seqData.MinSeq, errs.WrapMsg(d.session(ctx).First(&seqData).Error, "GetMinSeq failed")
 }
 
 func (d *DataBase) SetMinSeq(ctx context.Context, ID string, minSeq uint32) error {
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
+	defer d.lock(ctx)()
 	seqData := model_struct.LocalSeq{ID: ID, MinSeq: minSeq}
-	t := d.conn.WithContext(ctx).Updates(&seqData)
+	t := d.session(ctx).Updates(&seqData)
 	if t.RowsAffected == 0 {
-		return errs.WrapMsg(d.conn.WithContext(ctx).Create(seqData).Error, "Updates failed")
+		return errs.WrapMsg(d.session(ctx).Create(seqData).Error, "Updates failed")
 	} else {
 		return errs.WrapMsg(t.Error, "SetMinSeq failed")
 	}
@@ -10,9 +10,8 @@ import (
 )
 
 func (d *DataBase) GetExistTables(ctx context.Context) ([]string, error) {
-	d.mRWMutex.RLock()
-	defer d.mRWMutex.RUnlock()
+	defer d.rlock(ctx)()
 	var tables []string
-	return tables, errs.Wrap(d.conn.WithContext(ctx).Raw("SELECT name FROM sqlite_master WHERE type='table'").Scan(&tables).Error)
+	return tables, errs.Wrap(d.session(ctx).Raw("SELECT name FROM sqlite_master WHERE type='table'").Scan(&tables).Error)
 
 }
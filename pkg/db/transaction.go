@@ -0,0 +1,94 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !js
+// +build !js
+
+package db
+
+import (
+	"context"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/tools/errs"
+	"gorm.io/gorm"
+)
+
+type txKey struct{}
+
+type dbTx struct {
+	db *DataBase
+	tx *gorm.DB
+}
+
+// Transaction runs fn in a single transaction, the calls of the database with the ctx given to fn are part
+// of it and are committed together when fn returns nil. A transaction started within fn joins this one. The
+// other calls wait for the transaction, keep it short.
+func (d *DataBase) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if d.txOf(ctx) != nil {
+		return fn(ctx)
+	}
+	err := func() error {
+		d.mRWMutex.Lock()
+		defer d.mRWMutex.Unlock()
+		return d.conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			return fn(context.WithValue(ctx, txKey{}, &dbTx{db: d, tx: tx}))
+		})
+	}()
+	if err != nil {
+		// the tables of the messages created in the transaction are gone with it
+		tables, tablesErr := d.GetExistTables(ctx)
+		if tablesErr != nil {
+			log.ZWarn(ctx, "reload tables failed", tablesErr)
+		} else {
+			d.tableChecker.InitTableCache(tables)
+		}
+	}
+	return errs.Wrap(err)
+}
+
+func (d *DataBase) txOf(ctx context.Context) *gorm.DB {
+	if t, ok := ctx.Value(txKey{}).(*dbTx); ok && t.db == d {
+		return t.tx
+	}
+	return nil
+}
+
+// session is the connection of the calls with the ctx, the transaction of the ctx if there is one.
+func (d *DataBase) session(ctx context.Context) *gorm.DB {
+	ctx = d.withFieldKey(ctx)
+	if tx := d.txOf(ctx); tx != nil {
+		return tx.WithContext(ctx)
+	}
+	return d.conn.WithContext(ctx)
+}
+
+// lock locks the database for a write and returns the unlock, the calls within a transaction are covered
+// by the lock of the transaction.
+func (d *DataBase) lock(ctx context.Context) (unlock func()) {
+	if d.txOf(ctx) != nil {
+		return func() {}
+	}
+	d.mRWMutex.Lock()
+	return d.mRWMutex.Unlock
+}
+
+// rlock locks the database for a read and returns the unlock.
+func (d *DataBase) rlock(ctx context.Context) (unlock func()) {
+	if d.txOf(ctx) != nil {
+		return func() {}
+	}
+	d.mRWMutex.RLock()
+	return d.mRWMutex.RUnlock
+}
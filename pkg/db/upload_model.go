@@ -22,48 +22,79 @@ import (
 	"time"
 
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/openimsdk/tools/errs"
 )
 
 func (d *DataBase) GetUpload(ctx context.Context, partHash string) (*model_struct.LocalUpload, error) {
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
+	defer d.lock(ctx)()
 	var upload model_struct.LocalUpload
-	err := d.conn.WithContext(ctx).Where("part_hash = ?", partHash).Take(&upload).Error
+	err := d.session(ctx).Where("part_hash = ?", partHash).Take(&upload).Error
 	if err != nil {
 		return nil, errs.Wrap(err)
 	}
 	return &upload, nil
 }
 
+func (d *DataBase) GetUploadByFingerprint(ctx context.Context, fingerprint string) (*model_struct.LocalUpload, error) {
+	defer d.lock(ctx)()
+	var upload model_struct.LocalUpload
+	err := d.session(ctx).Where("fingerprint = ?", fingerprint).Take(&upload).Error
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return &upload, nil
+}
+
+func (d *DataBase) GetUploadedFile(ctx context.Context, fingerprint string) (*model_struct.LocalUploadedFile, error) {
+	defer d.lock(ctx)()
+	var file model_struct.LocalUploadedFile
+	err := d.session(ctx).Where("fingerprint = ?", fingerprint).Take(&file).Error
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return &file, nil
+}
+
+// maxUploadedFiles is the uploaded files kept, the oldest are removed beyond it.
+const maxUploadedFiles = 2000
+
+func (d *DataBase) SetUploadedFile(ctx context.Context, file *model_struct.LocalUploadedFile) error {
+	defer d.lock(ctx)()
+	return errs.Wrap(d.session(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(file).Error; err != nil {
+			return err
+		}
+		return tx.Exec("DELETE FROM local_uploaded_files WHERE fingerprint NOT IN "+
+			"(SELECT fingerprint FROM local_uploaded_files ORDER BY create_time DESC LIMIT ?)", maxUploadedFiles).Error
+	}))
+}
+
 func (d *DataBase) InsertUpload(ctx context.Context, upload *model_struct.LocalUpload) error {
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
-	return errs.Wrap(d.conn.WithContext(ctx).Create(upload).Error)
+	defer d.lock(ctx)()
+	return errs.Wrap(d.session(ctx).Create(upload).Error)
 }
 
 func (d *DataBase) deleteUpload(ctx context.Context, partHash string) error {
-	return errs.Wrap(d.conn.WithContext(ctx).Where("part_hash = ?", partHash).Delete(&model_struct.LocalUpload{}).Error)
+	return errs.Wrap(d.session(ctx).Where("part_hash = ?", partHash).Delete(&model_struct.LocalUpload{}).Error)
 }
 
 func (d *DataBase) UpdateUpload(ctx context.Context, upload *model_struct.LocalUpload) error {
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
-	return errs.Wrap(d.conn.WithContext(ctx).Updates(upload).Error)
+	defer d.lock(ctx)()
+	return errs.Wrap(d.session(ctx).Updates(upload).Error)
 }
 
 func (d *DataBase) DeleteUpload(ctx context.Context, partHash string) error {
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
+	defer d.lock(ctx)()
 	return d.deleteUpload(ctx, partHash)
 }
 
 func (d *DataBase) DeleteExpireUpload(ctx context.Context) error {
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
+	defer d.lock(ctx)()
 	var uploads []*model_struct.LocalUpload
-	err := d.conn.WithContext(ctx).Where("expire_time <= ?", time.Now().UnixMilli()).Find(&uploads).Error
+	err := d.session(ctx).Where("expire_time <= ?", time.Now().UnixMilli()).Find(&uploads).Error
 	if err != nil {
 		return errs.Wrap(err)
 	}
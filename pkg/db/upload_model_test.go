@@ -0,0 +1,34 @@
+package db
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+)
+
+func TestSetUploadedFile(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewDataBase(ctx, "1695766238", t.TempDir(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close(ctx)
+	for i := 0; i < maxUploadedFiles+1; i++ {
+		file := &model_struct.LocalUploadedFile{Fingerprint: strconv.Itoa(i), PartHash: "hash", CreateTime: int64(i)}
+		if err := db.SetUploadedFile(ctx, file); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// the oldest is removed beyond the limit
+	if _, err := db.GetUploadedFile(ctx, "0"); err == nil {
+		t.Fatal("oldest uploaded file kept")
+	}
+	if err := db.SetUploadedFile(ctx, &model_struct.LocalUploadedFile{Fingerprint: "1", PartHash: "other", CreateTime: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if file, err := db.GetUploadedFile(ctx, "1"); err != nil || file.PartHash != "other" {
+		t.Fatal(file, err)
+	}
+}
@@ -28,10 +28,9 @@ import (
 )
 
 func (d *DataBase) GetVersionSync(ctx context.Context, tableName, entityID string) (*model_struct.LocalVersionSync, error) {
-	d.mRWMutex.RLock()
-	defer d.mRWMutex.RUnlock()
+	defer d.rlock(ctx)()
 	var res model_struct.LocalVersionSync
-	err := d.conn.WithContext(ctx).Where("`table_name` = ? and `entity_id` = ?", tableName, entityID).Take(&res).Error
+	err := d.session(ctx).Where("`table_name` = ? and `entity_id` = ?", tableName, entityID).Take(&res).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return &model_struct.LocalVersionSync{}, errs.ErrRecordNotFound.Wrap()
@@ -42,14 +41,13 @@ func (d *DataBase) GetVersionSync(ctx context.Context, tableName, entityID strin
 }
 
 func (d *DataBase) SetVersionSync(ctx context.Context, lv *model_struct.LocalVersionSync) error {
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
+	defer d.lock(ctx)()
 
 	var existing model_struct.LocalVersionSync
-	err := d.conn.WithContext(ctx).Where("`table_name` = ? AND `entity_id` = ?", lv.Table, lv.EntityID).First(&existing).Error
+	err := d.session(ctx).Where("`table_name` = ? AND `entity_id` = ?", lv.Table, lv.EntityID).First(&existing).Error
 
 	if err == gorm.ErrRecordNotFound {
-		if createErr := d.conn.WithContext(ctx).Create(lv).Error; createErr != nil {
+		if createErr := d.session(ctx).Create(lv).Error; createErr != nil {
 			return errs.Wrap(createErr)
 		}
 		return nil
@@ -57,7 +55,7 @@ func (d *DataBase) SetVersionSync(ctx context.Context, lv *model_struct.LocalVer
 		return errs.Wrap(err)
 	}
 
-	if updateErr := d.conn.WithContext(ctx).Model(&existing).Updates(lv).Error; updateErr != nil {
+	if updateErr := d.session(ctx).Model(&existing).Updates(lv).Error; updateErr != nil {
 		return errs.Wrap(updateErr)
 	}
 
@@ -65,8 +63,7 @@ func (d *DataBase) SetVersionSync(ctx context.Context, lv *model_struct.LocalVer
 }
 
 func (d *DataBase) DeleteVersionSync(ctx context.Context, tableName, entityID string) error {
-	d.mRWMutex.Lock()
-	defer d.mRWMutex.Unlock()
+	defer d.lock(ctx)()
 	localVersionSync := model_struct.LocalVersionSync{Table: tableName, EntityID: entityID}
-	return errs.WrapMsg(d.conn.WithContext(ctx).Delete(&localVersionSync).Error, "DeleteVersionSync failed")
+	return errs.WrapMsg(d.session(ctx).Delete(&localVersionSync).Error, "DeleteVersionSync failed")
 }
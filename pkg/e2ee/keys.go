@@ -0,0 +1,239 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package e2ee implements the end-to-end encryption of the messages between two users: the X3DH key
+// agreement establishes a session from the keys a user published, the double ratchet encrypts each message
+// of the session with a key of its own.
+package e2ee
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrInvalidSignature = errors.New("invalid signed pre key signature")
+	ErrInvalidKey       = errors.New("invalid e2ee key")
+)
+
+// KeyPair is an X25519 key pair.
+type KeyPair struct {
+	Private []byte `json:"private"`
+	Public  []byte `json:"public"`
+}
+
+// GenerateKeyPair returns a new X25519 key pair.
+func GenerateKeyPair() (*KeyPair, error) {
+	key, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return &KeyPair{Private: key.Bytes(), Public: key.PublicKey().Bytes()}, nil
+}
+
+// dh is the X25519 shared secret of the private key and the public key.
+func dh(private, public []byte) ([]byte, error) {
+	priv, err := ecdh.X25519().NewPrivateKey(private)
+	if err != nil {
+		return nil, ErrInvalidKey
+	}
+	pub, err := ecdh.X25519().NewPublicKey(public)
+	if err != nil {
+		return nil, ErrInvalidKey
+	}
+	return priv.ECDH(pub)
+}
+
+// Identity is the long term keys of a user: the X25519 key of the key agreements and the Ed25519 key
+// signing the pre keys.
+type Identity struct {
+	DHKey      *KeyPair `json:"dhKey"`
+	SigningKey []byte   `json:"signingKey"` // ed25519 private key
+}
+
+func GenerateIdentity() (*Identity, error) {
+	dhKey, err := GenerateKeyPair()
+	if err != nil {
+		return nil, err
+	}
+	_, signingKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return &Identity{DHKey: dhKey, SigningKey: signingKey}, nil
+}
+
+// SigningPublicKey is the public key of the signing key.
+func (i *Identity) SigningPublicKey() []byte {
+	return ed25519.PrivateKey(i.SigningKey).Public().(ed25519.PublicKey)
+}
+
+// SignPreKey signs the identity key and the signed pre key together, binding the two keys of the identity.
+func (i *Identity) SignPreKey(preKey []byte) []byte {
+	return ed25519.Sign(i.SigningKey, preKeyMessage(i.DHKey.Public, preKey))
+}
+
+// Sign signs a message with the signing key of the identity.
+func (i *Identity) Sign(message []byte) []byte {
+	return ed25519.Sign(i.SigningKey, append([]byte("OpenIMMessage"), message...))
+}
+
+// VerifyMessage checks the message was signed by the identity of the signing key.
+func VerifyMessage(signingKey, message, signature []byte) bool {
+	if len(signingKey) != ed25519.PublicKeySize {
+		return false
+	}
+	return ed25519.Verify(signingKey, append([]byte("OpenIMMessage"), message...), signature)
+}
+
+func preKeyMessage(identityKey, preKey []byte) []byte {
+	return append(append([]byte("OpenIMSignedPreKey"), identityKey...), preKey...)
+}
+
+// Bundle is the public keys of a user another one establishes a session with, the one time pre key is
+// handed out once by the server and may be missing.
+type Bundle struct {
+	IdentityKey           []byte `json:"identityKey"`
+	SigningKey            []byte `json:"signingKey"`
+	SignedPreKeyID        string `json:"signedPreKeyID"`
+	SignedPreKey          []byte `json:"signedPreKey"`
+	SignedPreKeySignature []byte `json:"signedPreKeySignature"`
+	OneTimePreKeyID       string `json:"oneTimePreKeyID,omitempty"`
+	OneTimePreKey         []byte `json:"oneTimePreKey,omitempty"`
+}
+
+// Verify checks the signed pre key is signed by the signing key of the identity.
+func (b *Bundle) Verify() error {
+	if len(b.SigningKey) != ed25519.PublicKeySize {
+		return ErrInvalidKey
+	}
+	if !ed25519.Verify(b.SigningKey, preKeyMessage(b.IdentityKey, b.SignedPreKey), b.SignedPreKeySignature) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// Fingerprint is the hash of the identity key and the signing key, what two users compare to verify they
+// talk to each other.
+func Fingerprint(identityKey, signingKey []byte) []byte {
+	h := sha256.New()
+	h.Write(identityKey)
+	h.Write(signingKey)
+	return h.Sum(nil)
+}
+
+// DeviceID is the short id of the device of an identity, the first bytes of its fingerprint.
+func DeviceID(identityKey, signingKey []byte) string {
+	return hex.EncodeToString(Fingerprint(identityKey, signingKey)[:8])
+}
+
+// SafetyNumber is the number two users compare to verify each other's device, the same on both sides: 60
+// digits in groups of 5, each of 5 bytes of the hash of both fingerprints.
+func SafetyNumber(fingerprint, otherFingerprint []byte) string {
+	a, b := fingerprint, otherFingerprint
+	if bytes.Compare(a, b) > 0 {
+		a, b = b, a
+	}
+	h := sha512.New()
+	h.Write([]byte("OpenIMSafetyNumber"))
+	h.Write(a)
+	h.Write(b)
+	sum := h.Sum(nil)
+	groups := make([]string, 0, 12)
+	for i := 0; i < 12; i++ {
+		var v uint64
+		for _, c := range sum[i*5 : i*5+5] {
+			v = v<<8 | uint64(c)
+		}
+		groups = append(groups, fmt.Sprintf("%05d", v%100000))
+	}
+	return strings.Join(groups, " ")
+}
+
+// x3dhInitiate returns the secret of a new session with the owner of the bundle, with the ephemeral key the
+// owner derives it again from.
+func x3dhInitiate(identity *Identity, bundle *Bundle) (secret []byte, ephemeral *KeyPair, err error) {
+	if err := bundle.Verify(); err != nil {
+		return nil, nil, err
+	}
+	ephemeral, err = GenerateKeyPair()
+	if err != nil {
+		return nil, nil, err
+	}
+	dhs := [][2][]byte{
+		{identity.DHKey.Private, bundle.SignedPreKey},
+		{ephemeral.Private, bundle.IdentityKey},
+		{ephemeral.Private, bundle.SignedPreKey},
+	}
+	if len(bundle.OneTimePreKey) > 0 {
+		dhs = append(dhs, [2][]byte{ephemeral.Private, bundle.OneTimePreKey})
+	}
+	secret, err = x3dhSecret(dhs)
+	return secret, ephemeral, err
+}
+
+// x3dhRespond returns the secret of the session a pre key message establishes, from the keys of the bundle
+// of the receiver the sender used.
+func x3dhRespond(identity *Identity, signedPreKey, oneTimePreKey *KeyPair, senderIdentityKey, ephemeralKey []byte) ([]byte, error) {
+	dhs := [][2][]byte{
+		{signedPreKey.Private, senderIdentityKey},
+		{identity.DHKey.Private, ephemeralKey},
+		{signedPreKey.Private, ephemeralKey},
+	}
+	if oneTimePreKey != nil {
+		dhs = append(dhs, [2][]byte{oneTimePreKey.Private, ephemeralKey})
+	}
+	return x3dhSecret(dhs)
+}
+
+func x3dhSecret(dhs [][2][]byte) ([]byte, error) {
+	ikm := bytes.Repeat([]byte{0xff}, 32)
+	for _, pair := range dhs {
+		out, err := dh(pair[0], pair[1])
+		if err != nil {
+			return nil, err
+		}
+		ikm = append(ikm, out...)
+	}
+	return hkdf(make([]byte, 32), ikm, []byte("OpenIMX3DH"), 32), nil
+}
+
+// hkdf is HKDF with HMAC-SHA256.
+func hkdf(salt, ikm, info []byte, length int) []byte {
+	extract := hmac.New(sha256.New, salt)
+	extract.Write(ikm)
+	prk := extract.Sum(nil)
+	var (
+		out  []byte
+		prev []byte
+	)
+	for i := byte(1); len(out) < length; i++ {
+		expand := hmac.New(sha256.New, prk)
+		expand.Write(prev)
+		expand.Write(info)
+		expand.Write([]byte{i})
+		prev = expand.Sum(nil)
+		out = append(out, prev...)
+	}
+	return out[:length]
+}
@@ -0,0 +1,218 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package e2ee
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// maxSkip is the most message keys of a chain kept for the messages received out of order.
+const maxSkip = 1000
+
+var (
+	ErrDecrypt     = errors.New("e2ee message can not be decrypted")
+	ErrTooManySkip = errors.New("too many e2ee messages skipped")
+)
+
+// Header is the ratchet key of the sender and the position of the message in the chains.
+type Header struct {
+	DH []byte `json:"dh"`
+	// PN is the number of messages of the previous sending chain
+	PN uint32 `json:"pn"`
+	N  uint32 `json:"n"`
+}
+
+// associatedData is the associated data of the session followed by the header, authenticated with the message.
+func (h *Header) associatedData(ad []byte) []byte {
+	b := make([]byte, 0, len(ad)+len(h.DH)+8)
+	b = append(append(b, ad...), h.DH...)
+	b = binary.BigEndian.AppendUint32(b, h.PN)
+	return binary.BigEndian.AppendUint32(b, h.N)
+}
+
+// ratchet is the double ratchet state of a session, see the signal double ratchet specification.
+type ratchet struct {
+	RootKey []byte   `json:"rootKey"`
+	DHs     *KeyPair `json:"dhs"`
+	DHr     []byte   `json:"dhr,omitempty"`
+	CKs     []byte   `json:"cks,omitempty"`
+	CKr     []byte   `json:"ckr,omitempty"`
+	Ns      uint32   `json:"ns"`
+	Nr      uint32   `json:"nr"`
+	PN      uint32   `json:"pn"`
+	// Skipped are the message keys of the messages not received yet, by ratchet key and number
+	Skipped map[string][]byte `json:"skipped,omitempty"`
+}
+
+// newSendingRatchet is the ratchet of the initiator of a session, the signed pre key of the responder is its
+// first ratchet key.
+func newSendingRatchet(secret, remoteKey []byte) (*ratchet, error) {
+	dhs, err := GenerateKeyPair()
+	if err != nil {
+		return nil, err
+	}
+	out, err := dh(dhs.Private, remoteKey)
+	if err != nil {
+		return nil, err
+	}
+	rootKey, cks := kdfRK(secret, out)
+	return &ratchet{RootKey: rootKey, DHs: dhs, DHr: remoteKey, CKs: cks}, nil
+}
+
+// newReceivingRatchet is the ratchet of the responder, it sends once it received.
+func newReceivingRatchet(secret []byte, signedPreKey *KeyPair) *ratchet {
+	return &ratchet{RootKey: secret, DHs: signedPreKey}
+}
+
+func kdfRK(rootKey, dhOut []byte) ([]byte, []byte) {
+	out := hkdf(rootKey, dhOut, []byte("OpenIMRatchet"), 64)
+	return out[:32], out[32:]
+}
+
+func kdfCK(chainKey []byte) (next, messageKey []byte) {
+	mac := hmac.New(sha256.New, chainKey)
+	mac.Write([]byte{1})
+	messageKey = mac.Sum(nil)
+	mac = hmac.New(sha256.New, chainKey)
+	mac.Write([]byte{2})
+	return mac.Sum(nil), messageKey
+}
+
+func (r *ratchet) encrypt(plaintext, ad []byte) (*Header, []byte, error) {
+	if r.CKs == nil {
+		return nil, nil, errors.New("the session can not send before it received")
+	}
+	var mk []byte
+	r.CKs, mk = kdfCK(r.CKs)
+	header := &Header{DH: r.DHs.Public, PN: r.PN, N: r.Ns}
+	r.Ns++
+	ciphertext, err := seal(mk, plaintext, header.associatedData(ad))
+	return header, ciphertext, err
+}
+
+// decrypt returns the plaintext and the message key it was encrypted with.
+func (r *ratchet) decrypt(header *Header, ciphertext, ad []byte) ([]byte, []byte, error) {
+	ad = header.associatedData(ad)
+	if mk, ok := r.Skipped[skippedKey(header.DH, header.N)]; ok {
+		plaintext, err := open(mk, ciphertext, ad)
+		if err != nil {
+			return nil, nil, err
+		}
+		delete(r.Skipped, skippedKey(header.DH, header.N))
+		return plaintext, mk, nil
+	}
+	if !bytes.Equal(header.DH, r.DHr) {
+		if err := r.skip(header.PN); err != nil {
+			return nil, nil, err
+		}
+		if err := r.step(header.DH); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := r.skip(header.N); err != nil {
+		return nil, nil, err
+	}
+	var mk []byte
+	r.CKr, mk = kdfCK(r.CKr)
+	r.Nr++
+	plaintext, err := open(mk, ciphertext, ad)
+	return plaintext, mk, err
+}
+
+// skip keeps the message keys of the receiving chain up to until.
+func (r *ratchet) skip(until uint32) error {
+	if r.CKr == nil || until <= r.Nr {
+		return nil
+	}
+	if int(until-r.Nr)+len(r.Skipped) > maxSkip {
+		return ErrTooManySkip
+	}
+	for r.Nr < until {
+		if r.Skipped == nil {
+			r.Skipped = make(map[string][]byte)
+		}
+		var mk []byte
+		r.CKr, mk = kdfCK(r.CKr)
+		r.Skipped[skippedKey(r.DHr, r.Nr)] = mk
+		r.Nr++
+	}
+	return nil
+}
+
+// step is the dh ratchet step on a new ratchet key of the peer.
+func (r *ratchet) step(remoteKey []byte) error {
+	r.PN, r.Ns, r.Nr = r.Ns, 0, 0
+	r.DHr = remoteKey
+	out, err := dh(r.DHs.Private, remoteKey)
+	if err != nil {
+		return err
+	}
+	r.RootKey, r.CKr = kdfRK(r.RootKey, out)
+	if r.DHs, err = GenerateKeyPair(); err != nil {
+		return err
+	}
+	if out, err = dh(r.DHs.Private, remoteKey); err != nil {
+		return err
+	}
+	r.RootKey, r.CKs = kdfRK(r.RootKey, out)
+	return nil
+}
+
+func skippedKey(dhKey []byte, n uint32) string {
+	return fmt.Sprintf("%s:%d", base64.RawStdEncoding.EncodeToString(dhKey), n)
+}
+
+// seal encrypts with AES-256-GCM under the key and nonce derived from the message key, each message key
+// encrypts one message only.
+func seal(mk, plaintext, ad []byte) ([]byte, error) {
+	aead, nonce, err := messageCipher(mk)
+	if err != nil {
+		return nil, err
+	}
+	return aead.Seal(nil, nonce, plaintext, ad), nil
+}
+
+func open(mk, ciphertext, ad []byte) ([]byte, error) {
+	aead, nonce, err := messageCipher(mk)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := aead.Open(nil, nonce, ciphertext, ad)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return plaintext, nil
+}
+
+func messageCipher(mk []byte) (cipher.AEAD, []byte, error) {
+	keys := hkdf(make([]byte, 32), mk, []byte("OpenIMMessageKeys"), 44)
+	block, err := aes.NewCipher(keys[:32])
+	if err != nil {
+		return nil, nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, nil, err
+	}
+	return aead, keys[32:], nil
+}
@@ -0,0 +1,213 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package e2ee
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// maxSessions is the most sessions a record keeps, the previous ones still decrypt the messages sent
+// before a new session replaced them, e.g. when both users established one at the same time.
+const maxSessions = 5
+
+// PreKeyHeader is sent with the messages of a session until the responder replied, it establishes the
+// session on the side of the responder.
+type PreKeyHeader struct {
+	IdentityKey     []byte `json:"identityKey"`
+	SigningKey      []byte `json:"signingKey"`
+	EphemeralKey    []byte `json:"ephemeralKey"`
+	SignedPreKeyID  string `json:"signedPreKeyID"`
+	OneTimePreKeyID string `json:"oneTimePreKeyID,omitempty"`
+}
+
+// Envelope is a message encrypted for one device.
+type Envelope struct {
+	Header     *Header       `json:"header"`
+	Ciphertext []byte        `json:"ciphertext"`
+	PreKey     *PreKeyHeader `json:"preKey,omitempty"`
+}
+
+// Message is an encrypted message as sent to the server, an envelope for each device of the recipient by
+// the device ID.
+type Message struct {
+	// SenderDevice is the device ID of the sender, it picks the session of an envelope without a pre key
+	SenderDevice string               `json:"senderDevice"`
+	Envelopes    map[string]*Envelope `json:"envelopes"`
+}
+
+// Session is a double ratchet session with the identity of a peer.
+type Session struct {
+	RemoteIdentityKey []byte `json:"remoteIdentityKey"`
+	RemoteSigningKey  []byte `json:"remoteSigningKey"`
+	// BaseKey is the ephemeral key of the initiator, it tells the sessions apart
+	BaseKey []byte `json:"baseKey"`
+	// AD is the identity keys of the initiator and the responder, bound to each message
+	AD      []byte        `json:"ad"`
+	PreKey  *PreKeyHeader `json:"preKey,omitempty"`
+	Ratchet *ratchet      `json:"ratchet"`
+}
+
+// Initiate establishes a session with the owner of the bundle.
+func Initiate(identity *Identity, bundle *Bundle) (*Session, error) {
+	secret, ephemeral, err := x3dhInitiate(identity, bundle)
+	if err != nil {
+		return nil, err
+	}
+	r, err := newSendingRatchet(secret, bundle.SignedPreKey)
+	if err != nil {
+		return nil, err
+	}
+	return &Session{
+		RemoteIdentityKey: bundle.IdentityKey,
+		RemoteSigningKey:  bundle.SigningKey,
+		BaseKey:           ephemeral.Public,
+		AD:                append(append([]byte{}, identity.DHKey.Public...), bundle.IdentityKey...),
+		PreKey: &PreKeyHeader{
+			IdentityKey:     identity.DHKey.Public,
+			SigningKey:      identity.SigningPublicKey(),
+			EphemeralKey:    ephemeral.Public,
+			SignedPreKeyID:  bundle.SignedPreKeyID,
+			OneTimePreKeyID: bundle.OneTimePreKeyID,
+		},
+		Ratchet: r,
+	}, nil
+}
+
+// Respond establishes the session of a pre key message with the pre keys it names, oneTimePreKey is nil
+// when the message names none.
+func Respond(identity *Identity, signedPreKey, oneTimePreKey *KeyPair, preKey *PreKeyHeader) (*Session, error) {
+	secret, err := x3dhRespond(identity, signedPreKey, oneTimePreKey, preKey.IdentityKey, preKey.EphemeralKey)
+	if err != nil {
+		return nil, err
+	}
+	return &Session{
+		RemoteIdentityKey: preKey.IdentityKey,
+		RemoteSigningKey:  preKey.SigningKey,
+		BaseKey:           preKey.EphemeralKey,
+		AD:                append(append([]byte{}, preKey.IdentityKey...), identity.DHKey.Public...),
+		Ratchet:           newReceivingRatchet(secret, signedPreKey),
+	}, nil
+}
+
+func (s *Session) Encrypt(plaintext []byte) (*Envelope, error) {
+	header, ciphertext, err := s.Ratchet.encrypt(plaintext, s.AD)
+	if err != nil {
+		return nil, err
+	}
+	return &Envelope{Header: header, Ciphertext: ciphertext, PreKey: s.PreKey}, nil
+}
+
+// Decrypt decrypts the envelope, the session is left as it was when it fails.
+func (s *Session) Decrypt(envelope *Envelope) ([]byte, error) {
+	plaintext, _, err := s.DecryptKey(envelope)
+	return plaintext, err
+}
+
+// DecryptKey decrypts the envelope and returns the key of the message as well, the one that decrypts it
+// again once the session moved past it.
+func (s *Session) DecryptKey(envelope *Envelope) ([]byte, *MessageKey, error) {
+	if envelope.Header == nil {
+		return nil, nil, ErrDecrypt
+	}
+	r, err := s.Ratchet.clone()
+	if err != nil {
+		return nil, nil, err
+	}
+	plaintext, mk, err := r.decrypt(envelope.Header, envelope.Ciphertext, s.AD)
+	if err != nil {
+		return nil, nil, err
+	}
+	s.Ratchet = r
+	// the peer replied, it has the session
+	s.PreKey = nil
+	return plaintext, &MessageKey{Key: mk, AD: s.AD}, nil
+}
+
+// MessageKey is the key of one message and the associated data of its session, a message key is used once
+// by the ratchet and is kept by the receiver to decrypt the message again when it is delivered again.
+type MessageKey struct {
+	Key []byte `json:"key"`
+	AD  []byte `json:"ad"`
+}
+
+// Open decrypts the envelope the key is of.
+func (k *MessageKey) Open(envelope *Envelope) ([]byte, error) {
+	if envelope.Header == nil {
+		return nil, ErrDecrypt
+	}
+	return open(k.Key, envelope.Ciphertext, envelope.Header.associatedData(k.AD))
+}
+
+func (r *ratchet) clone() (*ratchet, error) {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+	var c ratchet
+	return &c, json.Unmarshal(data, &c)
+}
+
+// Record is the sessions with a peer, the current one first.
+type Record struct {
+	Sessions []*Session `json:"sessions"`
+}
+
+// Current is the session the messages are encrypted with, nil when there is none.
+func (r *Record) Current() *Session {
+	if len(r.Sessions) == 0 {
+		return nil
+	}
+	return r.Sessions[0]
+}
+
+// Add makes the session the current one.
+func (r *Record) Add(s *Session) {
+	r.Sessions = append([]*Session{s}, r.Sessions...)
+	if len(r.Sessions) > maxSessions {
+		r.Sessions = r.Sessions[:maxSessions]
+	}
+}
+
+// Find is the session of the base key.
+func (r *Record) Find(baseKey []byte) *Session {
+	for _, s := range r.Sessions {
+		if bytes.Equal(s.BaseKey, baseKey) {
+			return s
+		}
+	}
+	return nil
+}
+
+// Decrypt decrypts the envelope with the first session that can, the session becomes the current one.
+func (r *Record) Decrypt(envelope *Envelope) ([]byte, error) {
+	plaintext, _, err := r.DecryptKey(envelope)
+	return plaintext, err
+}
+
+// DecryptKey is Decrypt returning the key of the message as well.
+func (r *Record) DecryptKey(envelope *Envelope) ([]byte, *MessageKey, error) {
+	for i, s := range r.Sessions {
+		plaintext, key, err := s.DecryptKey(envelope)
+		if err != nil {
+			continue
+		}
+		if i > 0 {
+			r.Sessions = append(append([]*Session{s}, r.Sessions[:i]...), r.Sessions[i+1:]...)
+		}
+		return plaintext, key, nil
+	}
+	return nil, nil, ErrDecrypt
+}
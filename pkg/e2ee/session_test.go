@@ -0,0 +1,219 @@
+package e2ee
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+type testUser struct {
+	identity     *Identity
+	signedPreKey *KeyPair
+	oneTimeKey   *KeyPair
+}
+
+func newTestUser(t *testing.T) *testUser {
+	identity, err := GenerateIdentity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signedPreKey, err := GenerateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	oneTimeKey, err := GenerateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testUser{identity: identity, signedPreKey: signedPreKey, oneTimeKey: oneTimeKey}
+}
+
+func (u *testUser) bundle() *Bundle {
+	return &Bundle{
+		IdentityKey:           u.identity.DHKey.Public,
+		SigningKey:            u.identity.SigningPublicKey(),
+		SignedPreKeyID:        "1",
+		SignedPreKey:          u.signedPreKey.Public,
+		SignedPreKeySignature: u.identity.SignPreKey(u.signedPreKey.Public),
+		OneTimePreKeyID:       "2",
+		OneTimePreKey:         u.oneTimeKey.Public,
+	}
+}
+
+// roundTrip passes the envelope through json as it is sent.
+func roundTrip(t *testing.T, envelope *Envelope) *Envelope {
+	data, err := json.Marshal(envelope)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var e Envelope
+	if err := json.Unmarshal(data, &e); err != nil {
+		t.Fatal(err)
+	}
+	return &e
+}
+
+func encrypt(t *testing.T, s *Session, text string) *Envelope {
+	envelope, err := s.Encrypt([]byte(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return roundTrip(t, envelope)
+}
+
+func expect(t *testing.T, plaintext []byte, err error, text string) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(plaintext) != text {
+		t.Fatalf("got %q, want %q", plaintext, text)
+	}
+}
+
+func TestSession(t *testing.T) {
+	alice, bob := newTestUser(t), newTestUser(t)
+	aliceSession, err := Initiate(alice.identity, bob.bundle())
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := encrypt(t, aliceSession, "hello")
+	second := encrypt(t, aliceSession, "again")
+	if first.PreKey == nil || first.PreKey.OneTimePreKeyID != "2" {
+		t.Fatal("the first message has no pre key header")
+	}
+	bobSession, err := Respond(bob.identity, bob.signedPreKey, bob.oneTimeKey, first.PreKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// out of order
+	plaintext, err := bobSession.Decrypt(second)
+	expect(t, plaintext, err, "again")
+	plaintext, err = bobSession.Decrypt(first)
+	expect(t, plaintext, err, "hello")
+	if _, err := bobSession.Decrypt(first); err == nil {
+		t.Fatal("a message is decrypted twice")
+	}
+
+	reply := encrypt(t, bobSession, "hi")
+	plaintext, err = aliceSession.Decrypt(reply)
+	expect(t, plaintext, err, "hi")
+	if aliceSession.PreKey != nil {
+		t.Fatal("the pre key header is still sent after the reply")
+	}
+	for i := 0; i < 3; i++ {
+		plaintext, err = bobSession.Decrypt(encrypt(t, aliceSession, "ping"))
+		expect(t, plaintext, err, "ping")
+		plaintext, err = aliceSession.Decrypt(encrypt(t, bobSession, "pong"))
+		expect(t, plaintext, err, "pong")
+	}
+
+	tampered := encrypt(t, aliceSession, "x")
+	tampered.Ciphertext[0] ^= 1
+	if _, err := bobSession.Decrypt(tampered); err == nil {
+		t.Fatal("a tampered message is decrypted")
+	}
+}
+
+// a message delivered again decrypts with the key kept, the session is past it
+func TestMessageKey(t *testing.T) {
+	alice, bob := newTestUser(t), newTestUser(t)
+	aliceSession, err := Initiate(alice.identity, bob.bundle())
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := encrypt(t, aliceSession, "hello")
+	bobSession, err := Respond(bob.identity, bob.signedPreKey, bob.oneTimeKey, first.PreKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plaintext, key, err := bobSession.DecryptKey(first)
+	expect(t, plaintext, err, "hello")
+	if _, err := bobSession.Decrypt(first); err == nil {
+		t.Fatal("a message is decrypted twice by the session")
+	}
+	plaintext, err = key.Open(roundTrip(t, first))
+	expect(t, plaintext, err, "hello")
+	if _, err := key.Open(encrypt(t, aliceSession, "other")); err == nil {
+		t.Fatal("the key of a message decrypts another one")
+	}
+}
+
+func TestBundleSignature(t *testing.T) {
+	alice, bob := newTestUser(t), newTestUser(t)
+	bundle := bob.bundle()
+	bundle.SignedPreKey = alice.signedPreKey.Public
+	if _, err := Initiate(alice.identity, bundle); err != ErrInvalidSignature {
+		t.Fatal(err)
+	}
+}
+
+// both users establish a session at the same time, each ends up decrypting the other with the record
+func TestRecordSimultaneousInitiate(t *testing.T) {
+	alice, bob := newTestUser(t), newTestUser(t)
+	var aliceRecord, bobRecord Record
+	aliceSession, err := Initiate(alice.identity, bob.bundle())
+	if err != nil {
+		t.Fatal(err)
+	}
+	aliceRecord.Add(aliceSession)
+	bobSession, err := Initiate(bob.identity, alice.bundle())
+	if err != nil {
+		t.Fatal(err)
+	}
+	bobRecord.Add(bobSession)
+
+	fromAlice := encrypt(t, aliceRecord.Current(), "a")
+	fromBob := encrypt(t, bobRecord.Current(), "b")
+	responder, err := Respond(bob.identity, bob.signedPreKey, bob.oneTimeKey, fromAlice.PreKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bobRecord.Add(responder)
+	responder, err = Respond(alice.identity, alice.signedPreKey, alice.oneTimeKey, fromBob.PreKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	aliceRecord.Add(responder)
+	plaintext, err := bobRecord.Decrypt(fromAlice)
+	expect(t, plaintext, err, "a")
+	plaintext, err = aliceRecord.Decrypt(fromBob)
+	expect(t, plaintext, err, "b")
+
+	plaintext, err = bobRecord.Decrypt(encrypt(t, aliceRecord.Current(), "c"))
+	expect(t, plaintext, err, "c")
+	plaintext, err = aliceRecord.Decrypt(encrypt(t, bobRecord.Current(), "d"))
+	expect(t, plaintext, err, "d")
+	if !bytes.Equal(aliceRecord.Current().BaseKey, bobRecord.Current().BaseKey) {
+		t.Fatal("the records did not settle on one session")
+	}
+}
+
+func TestSafetyNumber(t *testing.T) {
+	a, b := Fingerprint([]byte("a"), []byte("a")), Fingerprint([]byte("b"), []byte("b"))
+	n := SafetyNumber(a, b)
+	if n != SafetyNumber(b, a) {
+		t.Fatal("safety number depends on the side")
+	}
+	if len(strings.ReplaceAll(n, " ", "")) != 60 {
+		t.Fatal("unexpected safety number", n)
+	}
+	if n == SafetyNumber(a, Fingerprint([]byte("c"), []byte("c"))) {
+		t.Fatal("same safety number of other devices")
+	}
+}
+
+func TestMessageSignature(t *testing.T) {
+	identity, err := GenerateIdentity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature := identity.Sign([]byte("message"))
+	if !VerifyMessage(identity.SigningPublicKey(), []byte("message"), signature) {
+		t.Fatal("signature not verified")
+	}
+	if VerifyMessage(identity.SigningPublicKey(), []byte("changed"), signature) {
+		t.Fatal("signature of a changed message verified")
+	}
+}
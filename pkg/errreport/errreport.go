@@ -0,0 +1,63 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package errreport reports the errors of the background goroutines of the SDK, which are only logged
+// otherwise, so that the app learns that something went wrong and reports it with its telemetry.
+package errreport
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"sync"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+)
+
+// Sources name the part of the sdk a background error happened in.
+const (
+	SourceSync         = "sync"         // syncing the messages or the data with the server
+	SourceNotification = "notification" // handling a notification pushed by the server
+	SourceConversation = "conversation" // updating the local conversations
+)
+
+var (
+	lock     sync.Mutex
+	reporter func(ctx context.Context, source, operation string, err error, keysAndValues []any)
+)
+
+// SetReporter sets the function the background errors are reported to, besides the log.
+func SetReporter(fn func(ctx context.Context, source, operation string, err error, keysAndValues []any)) {
+	lock.Lock()
+	defer lock.Unlock()
+	reporter = fn
+}
+
+// Report logs an error of a background goroutine, which has no caller to return it to,
+// and reports it so the app learns that the sdk is not in sync.
+func Report(ctx context.Context, source, operation string, err error, keysAndValues ...any) {
+	kv := []any{"source", source}
+	// the log entry is written here, the caller is where the error happened
+	if _, file, line, ok := runtime.Caller(1); ok {
+		kv = append(kv, "caller", fmt.Sprintf("%s:%d", filepath.Base(file), line))
+	}
+	log.ZError(ctx, operation+" failed", err, append(kv, keysAndValues...)...)
+	lock.Lock()
+	fn := reporter
+	lock.Unlock()
+	if fn != nil {
+		fn(ctx, source, operation, err, keysAndValues)
+	}
+}
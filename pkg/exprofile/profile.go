@@ -0,0 +1,210 @@
+package exprofile
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+)
+
+const (
+	FieldTypeString = "string"
+	FieldTypeInt    = "int"
+	FieldTypeFloat  = "float"
+	FieldTypeBool   = "bool"
+	// FieldTypeDate is a calendar date such as a birthday, stored as "2006-01-02".
+	FieldTypeDate = "date"
+)
+
+const DateLayout = "2006-01-02"
+
+// Field describes one registered key of the user ex field.
+type Field struct {
+	Key  string `json:"key"`
+	Type string `json:"type"`
+}
+
+// Schema is the set of typed fields the app stores in the user ex field.
+// Keys that are not registered are kept as they are.
+type Schema struct {
+	fields map[string]*Field
+}
+
+func NewSchema(fields []*Field) (*Schema, error) {
+	s := &Schema{fields: make(map[string]*Field, len(fields))}
+	for _, field := range fields {
+		if field == nil || field.Key == "" {
+			return nil, sdkerrs.ErrArgs.WrapMsg("ex profile field key is empty")
+		}
+		switch field.Type {
+		case FieldTypeString, FieldTypeInt, FieldTypeFloat, FieldTypeBool, FieldTypeDate:
+		default:
+			return nil, sdkerrs.ErrArgs.WrapMsg("ex profile field " + field.Key + " has unknown type " + field.Type)
+		}
+		if _, ok := s.fields[field.Key]; ok {
+			return nil, sdkerrs.ErrArgs.WrapMsg("ex profile field " + field.Key + " is duplicated")
+		}
+		s.fields[field.Key] = field
+	}
+	return s, nil
+}
+
+// Fields returns the registered fields.
+func (s *Schema) Fields() []*Field {
+	fields := make([]*Field, 0, len(s.fields))
+	for _, field := range s.fields {
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// Profile is the decoded user ex field.
+type Profile map[string]any
+
+func (p Profile) String(key string) (string, bool) {
+	v, ok := p[key].(string)
+	return v, ok
+}
+
+func (p Profile) Int(key string) (int64, bool) {
+	v, ok := p[key].(int64)
+	return v, ok
+}
+
+func (p Profile) Float(key string) (float64, bool) {
+	v, ok := p[key].(float64)
+	return v, ok
+}
+
+func (p Profile) Bool(key string) (bool, bool) {
+	v, ok := p[key].(bool)
+	return v, ok
+}
+
+func (p Profile) Date(key string) (time.Time, bool) {
+	v, ok := p[key].(string)
+	if !ok {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(DateLayout, v)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// Decode parses the ex field into a profile. Registered fields whose value does not
+// match the schema are dropped, so a malformed value written by an old client cannot
+// break the readers.
+func (s *Schema) Decode(ex string) (Profile, error) {
+	raw, err := decodeObject(ex)
+	if err != nil {
+		return nil, err
+	}
+	profile := make(Profile, len(raw))
+	for key, value := range raw {
+		field, ok := s.fields[key]
+		if !ok {
+			profile[key] = value
+			continue
+		}
+		if v, err := convert(field, value); err == nil {
+			profile[key] = v
+		}
+	}
+	return profile, nil
+}
+
+// Merge applies a partial update to the ex field and returns the new ex field.
+// Only the keys in update are changed, a nil value removes the key.
+func (s *Schema) Merge(ex string, update map[string]any) (string, error) {
+	raw, err := decodeObject(ex)
+	if err != nil {
+		return "", err
+	}
+	for key, value := range update {
+		if value == nil {
+			delete(raw, key)
+			continue
+		}
+		if field, ok := s.fields[key]; ok {
+			if value, err = convert(field, value); err != nil {
+				return "", err
+			}
+		}
+		raw[key] = value
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return "", sdkerrs.ErrArgs.WrapMsg("ex profile json.Marshal failed " + err.Error())
+	}
+	return string(data), nil
+}
+
+func decodeObject(ex string) (map[string]any, error) {
+	raw := make(map[string]any)
+	if strings.TrimSpace(ex) == "" {
+		return raw, nil
+	}
+	decoder := json.NewDecoder(strings.NewReader(ex))
+	decoder.UseNumber()
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, sdkerrs.ErrArgs.WrapMsg("user ex is not a json object")
+	}
+	return raw, nil
+}
+
+func convert(field *Field, value any) (any, error) {
+	switch field.Type {
+	case FieldTypeString:
+		if v, ok := value.(string); ok {
+			return v, nil
+		}
+	case FieldTypeInt:
+		switch v := value.(type) {
+		case json.Number:
+			if i, err := v.Int64(); err == nil {
+				return i, nil
+			}
+		case float64:
+			if v == math.Trunc(v) {
+				return int64(v), nil
+			}
+		case int:
+			return int64(v), nil
+		case int32:
+			return int64(v), nil
+		case int64:
+			return v, nil
+		}
+	case FieldTypeFloat:
+		switch v := value.(type) {
+		case json.Number:
+			if f, err := v.Float64(); err == nil {
+				return f, nil
+			}
+		case float64:
+			return v, nil
+		case int:
+			return float64(v), nil
+		case int64:
+			return float64(v), nil
+		}
+	case FieldTypeBool:
+		if v, ok := value.(bool); ok {
+			return v, nil
+		}
+	case FieldTypeDate:
+		switch v := value.(type) {
+		case string:
+			if _, err := time.Parse(DateLayout, v); err == nil {
+				return v, nil
+			}
+		case time.Time:
+			return v.Format(DateLayout), nil
+		}
+	}
+	return nil, sdkerrs.ErrArgs.WrapMsg("ex profile field " + field.Key + " expects type " + field.Type)
+}
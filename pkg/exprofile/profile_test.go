@@ -0,0 +1,65 @@
+package exprofile
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func testSchema(t *testing.T) *Schema {
+	schema, err := NewSchema([]*Field{
+		{Key: "jobTitle", Type: FieldTypeString},
+		{Key: "level", Type: FieldTypeInt},
+		{Key: "birthday", Type: FieldTypeDate},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return schema
+}
+
+func TestSchemaDecode(t *testing.T) {
+	profile, err := testSchema(t).Decode(`{"jobTitle":"engineer","level":3,"birthday":"1990-02-30","custom":"x"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := profile.String("jobTitle"); !ok || v != "engineer" {
+		t.Errorf("jobTitle = %v", profile["jobTitle"])
+	}
+	if v, ok := profile.Int("level"); !ok || v != 3 {
+		t.Errorf("level = %v", profile["level"])
+	}
+	if _, ok := profile["birthday"]; ok {
+		t.Errorf("invalid birthday should be dropped")
+	}
+	if _, ok := profile["custom"]; !ok {
+		t.Errorf("unregistered key should be kept")
+	}
+}
+
+func TestSchemaMerge(t *testing.T) {
+	schema := testSchema(t)
+	ex, err := schema.Merge(`{"jobTitle":"engineer","level":3,"custom":"x"}`, map[string]any{
+		"level":    float64(4),
+		"birthday": "1990-01-02",
+		"custom":   nil,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal([]byte(ex), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got["jobTitle"] != "engineer" || got["level"] != float64(4) || got["birthday"] != "1990-01-02" {
+		t.Errorf("unexpected merge result %s", ex)
+	}
+	if _, ok := got["custom"]; ok {
+		t.Errorf("nil value should remove the key, got %s", ex)
+	}
+	if _, err := schema.Merge("", map[string]any{"level": "high"}); err == nil {
+		t.Errorf("expected a type error")
+	}
+	if _, err := schema.Merge("not json", map[string]any{"level": 1}); err == nil {
+		t.Errorf("expected an error for a non json ex")
+	}
+}
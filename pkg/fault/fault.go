@@ -0,0 +1,133 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fault injects the faults of the network and of the local database set by the FaultInjection config,
+// so that the recovery of the SDK can be tested. Nothing is injected until it is configured.
+package fault
+
+import (
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+	"github.com/openimsdk/tools/errs"
+)
+
+// ErrInjected is the error of the database writes failed on purpose.
+var ErrInjected = errs.New("fault injected")
+
+// Frame is the fault of a frame of the long connection, the zero value is none.
+type Frame struct {
+	Drop      bool
+	Duplicate bool
+	Delay     time.Duration
+}
+
+var injector struct {
+	enabled atomic.Bool
+	lock    sync.Mutex
+	conf    sdk_struct.FaultInjection
+	rand    *rand.Rand
+}
+
+// Check tells whether the rates of the config are between 0 and 1 and the durations not negative.
+func Check(conf *sdk_struct.FaultInjection) error {
+	if conf == nil {
+		return nil
+	}
+	for _, rate := range []float64{conf.FrameDropRate, conf.FrameDuplicateRate, conf.FrameDelayRate, conf.DBWriteFailRate} {
+		if rate < 0 || rate > 1 {
+			return sdkerrs.ErrArgs.WrapMsg("fault injection rates must be between 0 and 1")
+		}
+	}
+	if conf.FrameDelay < 0 || conf.ReconnectInterval < 0 {
+		return sdkerrs.ErrArgs.WrapMsg("fault injection durations must not be negative")
+	}
+	return nil
+}
+
+// Configure sets the faults injected from now on, nil turns the injection off. The faults are drawn from the
+// seed again.
+func Configure(conf *sdk_struct.FaultInjection) error {
+	if err := Check(conf); err != nil {
+		return err
+	}
+	injector.lock.Lock()
+	defer injector.lock.Unlock()
+	if conf == nil {
+		injector.enabled.Store(false)
+		injector.conf, injector.rand = sdk_struct.FaultInjection{}, nil
+		return nil
+	}
+	injector.conf = *conf
+	injector.rand = rand.New(rand.NewSource(conf.Seed))
+	injector.enabled.Store(true)
+	return nil
+}
+
+// Enabled tells whether the faults are injected.
+func Enabled() bool {
+	return injector.enabled.Load()
+}
+
+// hit draws whether a fault of the rate happens, called with the lock.
+func hit(rate float64) bool {
+	return rate > 0 && injector.rand.Float64() < rate
+}
+
+// NextFrame returns the fault of the next frame sent or received.
+func NextFrame() Frame {
+	if !injector.enabled.Load() {
+		return Frame{}
+	}
+	injector.lock.Lock()
+	defer injector.lock.Unlock()
+	if injector.rand == nil {
+		return Frame{}
+	}
+	conf := &injector.conf
+	var f Frame
+	f.Drop = hit(conf.FrameDropRate)
+	f.Duplicate = hit(conf.FrameDuplicateRate) && !f.Drop
+	if hit(conf.FrameDelayRate) {
+		f.Delay = time.Duration(conf.FrameDelay) * time.Millisecond
+	}
+	return f
+}
+
+// DBWrite returns ErrInjected when the next write of the local database fails.
+func DBWrite() error {
+	if !injector.enabled.Load() {
+		return nil
+	}
+	injector.lock.Lock()
+	defer injector.lock.Unlock()
+	if injector.rand == nil || !hit(injector.conf.DBWriteFailRate) {
+		return nil
+	}
+	return ErrInjected
+}
+
+// ReconnectInterval returns the time after which the long connection is closed, 0 for never.
+func ReconnectInterval() time.Duration {
+	if !injector.enabled.Load() {
+		return 0
+	}
+	injector.lock.Lock()
+	defer injector.lock.Unlock()
+	return time.Duration(injector.conf.ReconnectInterval) * time.Millisecond
+}
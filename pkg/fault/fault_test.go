@@ -0,0 +1,71 @@
+package fault
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+func TestFault(t *testing.T) {
+	defer Configure(nil)
+	if f := NextFrame(); f != (Frame{}) || DBWrite() != nil || ReconnectInterval() != 0 {
+		t.Fatal("faults injected while not configured")
+	}
+	if err := Configure(&sdk_struct.FaultInjection{FrameDropRate: 1.5}); err == nil {
+		t.Fatal("a rate over 1 was accepted")
+	}
+	conf := &sdk_struct.FaultInjection{
+		Seed:               42,
+		FrameDropRate:      0.2,
+		FrameDuplicateRate: 0.2,
+		FrameDelayRate:     0.5,
+		FrameDelay:         30,
+		DBWriteFailRate:    0.3,
+		ReconnectInterval:  1000,
+	}
+	draw := func() ([]Frame, []bool) {
+		if err := Configure(conf); err != nil {
+			t.Fatal(err)
+		}
+		frames := make([]Frame, 100)
+		writes := make([]bool, 100)
+		for i := range frames {
+			frames[i] = NextFrame()
+			writes[i] = DBWrite() != nil
+		}
+		return frames, writes
+	}
+	frames, writes := draw()
+	var dropped, delayed, failed int
+	for i, f := range frames {
+		if f.Drop {
+			dropped++
+			if f.Duplicate {
+				t.Fatal("a frame dropped was duplicated")
+			}
+		}
+		if f.Delay > 0 {
+			delayed++
+			if f.Delay != 30*time.Millisecond {
+				t.Fatalf("delay %s", f.Delay)
+			}
+		}
+		if writes[i] {
+			failed++
+		}
+	}
+	if dropped == 0 || dropped == 100 || delayed == 0 || delayed == 100 || failed == 0 || failed == 100 {
+		t.Fatalf("dropped %d delayed %d failed %d", dropped, delayed, failed)
+	}
+	// the same seed draws the same faults
+	again, writesAgain := draw()
+	for i := range frames {
+		if frames[i] != again[i] || writes[i] != writesAgain[i] {
+			t.Fatalf("fault %d differs with the same seed", i)
+		}
+	}
+	if ReconnectInterval() != time.Second {
+		t.Fatalf("reconnect interval %s", ReconnectInterval())
+	}
+}
@@ -0,0 +1,226 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package i18n renders the texts the sdk generates for display, e.g. the tips of the revokes and of the group
+// notifications, in the language of the locale set at init. The templates name their arguments in braces, e.g.
+// {user}, and the apps can override them for any locale. A locale missing a text falls back to its language, e.g.
+// zh for zh-TW, then to English.
+package i18n
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultLocale is used when no locale is set.
+const DefaultLocale = "en"
+
+// The keys of the texts.
+const (
+	UnknownUser = "unknownUser" // the name of a user who could not be found
+	You         = "you"         // the login user as the subject of a tip
+	Edited      = "edited"      // the label of an edited message
+	ListSep     = "listSep"     // the separator of the names of a list
+
+	DurationSeconds = "durationSeconds" // {n}
+	DurationMinutes = "durationMinutes" // {n}
+	DurationHours   = "durationHours"   // {n}
+	DurationDays    = "durationDays"    // {n}
+
+	MessageRevoked        = "messageRevoked"        // {user}
+	MessageRevokedByAdmin = "messageRevokedByAdmin" // {user} {sender}
+
+	GroupCreated            = "groupCreated"            // {user} {group}
+	GroupInfoSet            = "groupInfoSet"            // {user}
+	GroupNameSet            = "groupNameSet"            // {user} {group}
+	GroupAnnouncementSet    = "groupAnnouncementSet"    // {user}
+	GroupOwnerTransferred   = "groupOwnerTransferred"   // {user} {member}
+	GroupDismissed          = "groupDismissed"          // {user}
+	GroupMuted              = "groupMuted"              // {user}
+	GroupCancelMuted        = "groupCancelMuted"        // {user}
+	MemberInvited           = "memberInvited"           // {user} {members}
+	MemberKicked            = "memberKicked"            // {user} {members}
+	MemberQuit              = "memberQuit"              // {user}
+	MemberEnter             = "memberEnter"             // {user}
+	MemberMuted             = "memberMuted"             // {user} {member} {duration}
+	MemberCancelMuted       = "memberCancelMuted"       // {user} {member}
+	MemberSetToAdmin        = "memberSetToAdmin"        // {user} {member}
+	MemberSetToOrdinaryUser = "memberSetToOrdinaryUser" // {user} {member}
+)
+
+var builtin = map[string]map[string]string{
+	"en": {
+		UnknownUser:             "Unknown",
+		You:                     "You",
+		Edited:                  "(edited)",
+		ListSep:                 ", ",
+		DurationSeconds:         "{n} seconds",
+		DurationMinutes:         "{n} minutes",
+		DurationHours:           "{n} hours",
+		DurationDays:            "{n} days",
+		MessageRevoked:          "{user} recalled a message",
+		MessageRevokedByAdmin:   "{user} recalled a message of {sender}",
+		GroupCreated:            "{user} created the group {group}",
+		GroupInfoSet:            "{user} updated the group info",
+		GroupNameSet:            "{user} changed the group name to {group}",
+		GroupAnnouncementSet:    "{user} updated the group announcement",
+		GroupOwnerTransferred:   "{user} transferred the group to {member}",
+		GroupDismissed:          "{user} dismissed the group",
+		GroupMuted:              "{user} muted the group",
+		GroupCancelMuted:        "{user} unmuted the group",
+		MemberInvited:           "{user} invited {members} to the group",
+		MemberKicked:            "{user} removed {members} from the group",
+		MemberQuit:              "{user} left the group",
+		MemberEnter:             "{user} joined the group",
+		MemberMuted:             "{user} muted {member} for {duration}",
+		MemberCancelMuted:       "{user} unmuted {member}",
+		MemberSetToAdmin:        "{user} made {member} an admin",
+		MemberSetToOrdinaryUser: "{user} removed {member} from the admins",
+	},
+	"zh": {
+		UnknownUser:             "未知用户",
+		You:                     "你",
+		Edited:                  "(已编辑)",
+		ListSep:                 "、",
+		DurationSeconds:         "{n}秒",
+		DurationMinutes:         "{n}分钟",
+		DurationHours:           "{n}小时",
+		DurationDays:            "{n}天",
+		MessageRevoked:          "{user}撤回了一条消息",
+		MessageRevokedByAdmin:   "{user}撤回了{sender}的一条消息",
+		GroupCreated:            "{user}创建了群聊{group}",
+		GroupInfoSet:            "{user}修改了群资料",
+		GroupNameSet:            "{user}将群名称修改为{group}",
+		GroupAnnouncementSet:    "{user}更新了群公告",
+		GroupOwnerTransferred:   "{user}将群主转让给了{member}",
+		GroupDismissed:          "{user}解散了群聊",
+		GroupMuted:              "{user}开启了全员禁言",
+		GroupCancelMuted:        "{user}关闭了全员禁言",
+		MemberInvited:           "{user}邀请{members}加入了群聊",
+		MemberKicked:            "{user}将{members}移出了群聊",
+		MemberQuit:              "{user}退出了群聊",
+		MemberEnter:             "{user}加入了群聊",
+		MemberMuted:             "{user}将{member}禁言{duration}",
+		MemberCancelMuted:       "{user}取消了{member}的禁言",
+		MemberSetToAdmin:        "{user}将{member}设为管理员",
+		MemberSetToOrdinaryUser: "{user}取消了{member}的管理员",
+	},
+}
+
+var (
+	lock      sync.RWMutex
+	locale    = DefaultLocale
+	overrides = make(map[string]map[string]string)
+)
+
+// SetLocale sets the locale of the texts, e.g. en or zh-CN, the default one when empty.
+func SetLocale(l string) {
+	if l == "" {
+		l = DefaultLocale
+	}
+	lock.Lock()
+	defer lock.Unlock()
+	locale = normalize(l)
+}
+
+// Locale is the locale of the texts.
+func Locale() string {
+	lock.RLock()
+	defer lock.RUnlock()
+	return locale
+}
+
+// SetTemplates overrides the templates of the locale by key, an empty template restores the built-in one.
+func SetTemplates(l string, templates map[string]string) {
+	l = normalize(l)
+	lock.Lock()
+	defer lock.Unlock()
+	m := overrides[l]
+	if m == nil {
+		m = make(map[string]string, len(templates))
+		overrides[l] = m
+	}
+	for key, template := range templates {
+		if template == "" {
+			delete(m, key)
+		} else {
+			m[key] = template
+		}
+	}
+}
+
+// T renders the text of the key in the locale, args are the pairs of the names of the arguments and their
+// values. The key itself is returned when no locale has the text.
+func T(key string, args ...string) string {
+	text := template(key)
+	if len(args) == 0 {
+		return text
+	}
+	oldnew := make([]string, 0, len(args))
+	for i := 0; i+1 < len(args); i += 2 {
+		oldnew = append(oldnew, "{"+args[i]+"}", args[i+1])
+	}
+	return strings.NewReplacer(oldnew...).Replace(text)
+}
+
+// List joins the names with the separator of the locale.
+func List(names []string) string {
+	return strings.Join(names, template(ListSep))
+}
+
+// Duration renders the seconds in the largest unit they are a whole number of.
+func Duration(seconds int64) string {
+	switch {
+	case seconds > 0 && seconds%86400 == 0:
+		return T(DurationDays, "n", strconv.FormatInt(seconds/86400, 10))
+	case seconds > 0 && seconds%3600 == 0:
+		return T(DurationHours, "n", strconv.FormatInt(seconds/3600, 10))
+	case seconds > 0 && seconds%60 == 0:
+		return T(DurationMinutes, "n", strconv.FormatInt(seconds/60, 10))
+	default:
+		return T(DurationSeconds, "n", strconv.FormatInt(seconds, 10))
+	}
+}
+
+func template(key string) string {
+	lock.RLock()
+	defer lock.RUnlock()
+	for _, l := range fallbacks(locale) {
+		if text, ok := overrides[l][key]; ok {
+			return text
+		}
+		if text, ok := builtin[l][key]; ok {
+			return text
+		}
+	}
+	return key
+}
+
+// fallbacks are the locales searched for a text of the locale, in order.
+func fallbacks(l string) []string {
+	res := []string{l}
+	if i := strings.IndexByte(l, '-'); i > 0 {
+		res = append(res, l[:i])
+	}
+	if l != DefaultLocale {
+		res = append(res, DefaultLocale)
+	}
+	return res
+}
+
+// normalize lowers the locale and separates its parts with a hyphen, zh_CN being zh-cn.
+func normalize(l string) string {
+	return strings.ToLower(strings.ReplaceAll(l, "_", "-"))
+}
@@ -0,0 +1,29 @@
+package i18n
+
+import "testing"
+
+func TestT(t *testing.T) {
+	defer SetLocale("")
+	if text := T(MemberKicked, "user", "Ann", "members", List([]string{"Bob", "Cid"})); text != "Ann removed Bob, Cid from the group" {
+		t.Fatal(text)
+	}
+	SetLocale("zh_CN")
+	if text := T(MemberMuted, "user", "Ann", "member", "Bob", "duration", Duration(7200)); text != "Ann将Bob禁言2小时" {
+		t.Fatal(text)
+	}
+	SetTemplates("zh-CN", map[string]string{MemberQuit: "{user}走了"})
+	if text := T(MemberQuit, "user", "Ann"); text != "Ann走了" {
+		t.Fatal(text)
+	}
+	SetTemplates("zh-CN", map[string]string{MemberQuit: ""})
+	if text := T(MemberQuit, "user", "Ann"); text != "Ann退出了群聊" {
+		t.Fatal(text)
+	}
+	SetLocale("fr")
+	if text := T(You); text != "You" {
+		t.Fatal(text)
+	}
+	if text := T("missing"); text != "missing" {
+		t.Fatal(text)
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	tlog "github.com/openimsdk/tools/log"
+)
+
+// The modules a level can be set for, the entries of the other files follow the global level.
+const (
+	ModuleSync         = "sync"
+	ModuleDB           = "db"
+	ModuleWs           = "ws"
+	ModuleConversation = "conversation"
+)
+
+// LevelDebugWithSQL also writes the statements of gorm, it is the level of the entries of SDKLog for debug
+const LevelDebugWithSQL = tlog.LevelDebugWithSQL
+
+var modules = []string{ModuleSync, ModuleDB, ModuleWs, ModuleConversation}
+
+var (
+	level        atomic.Int32
+	moduleLevels atomic.Pointer[map[string]int]
+	// fileModules caches the module of the files of the callers
+	fileModules sync.Map
+)
+
+func init() {
+	level.Store(tlog.LevelDebugWithSQL)
+}
+
+// SetLevel sets the level of a module at runtime, or the global one when module is empty. A negative level
+// removes the level of the module, its entries then follow the global level again.
+func SetLevel(module string, logLevel int) error {
+	if module == "" {
+		if logLevel < 0 {
+			return sdkerrs.ErrArgs.WrapMsg("the global level can not be removed")
+		}
+		level.Store(int32(logLevel))
+		return nil
+	}
+	if !isModule(module) {
+		return sdkerrs.ErrArgs.WrapMsg("unknown log module " + module + ", expected one of " + strings.Join(modules, ", "))
+	}
+	levels := make(map[string]int)
+	if m := moduleLevels.Load(); m != nil {
+		for k, v := range *m {
+			levels[k] = v
+		}
+	}
+	if logLevel < 0 {
+		delete(levels, module)
+	} else {
+		levels[module] = logLevel
+	}
+	if len(levels) == 0 {
+		moduleLevels.Store(nil)
+		return nil
+	}
+	moduleLevels.Store(&levels)
+	return nil
+}
+
+// Levels returns the global level, under the empty key, and the levels set for the modules.
+func Levels() map[string]int {
+	levels := map[string]int{"": int(level.Load())}
+	if m := moduleLevels.Load(); m != nil {
+		for k, v := range *m {
+			levels[k] = v
+		}
+	}
+	return levels
+}
+
+// Modules returns the modules a level can be set for.
+func Modules() []string {
+	res := append([]string(nil), modules...)
+	sort.Strings(res)
+	return res
+}
+
+func isModule(module string) bool {
+	for _, m := range modules {
+		if m == module {
+			return true
+		}
+	}
+	return false
+}
+
+// moduleLevel returns the level of the module, the global one when it has none.
+func moduleLevel(module string) int {
+	if module != "" {
+		if m := moduleLevels.Load(); m != nil {
+			if l, ok := (*m)[module]; ok {
+				return l
+			}
+		}
+	}
+	return int(level.Load())
+}
+
+// enabled reports whether an entry of the level is written, the module is the one of the file of the caller,
+// skip frames above enabled.
+func enabled(entryLevel int, skip int) bool {
+	if moduleLevels.Load() == nil {
+		return entryLevel <= int(level.Load())
+	}
+	_, file, _, ok := runtime.Caller(skip + 1)
+	if !ok {
+		return entryLevel <= int(level.Load())
+	}
+	return entryLevel <= moduleLevel(moduleOf(file))
+}
+
+func moduleOf(file string) string {
+	if m, ok := fileModules.Load(file); ok {
+		return m.(string)
+	}
+	m := fileModule(file)
+	fileModules.Store(file, m)
+	return m
+}
+
+func fileModule(file string) string {
+	dir, name := file, ""
+	if i := strings.LastIndexByte(file, '/'); i >= 0 {
+		dir, name = file[:i+1], file[i+1:]
+	}
+	switch {
+	case strings.HasSuffix(dir, "/internal/interaction/"):
+		if strings.HasPrefix(name, "msg_sync") || strings.HasPrefix(name, "sync_") || strings.HasPrefix(name, "seq_gap") {
+			return ModuleSync
+		}
+		return ModuleWs
+	case strings.Contains(dir, "/pkg/syncer/"):
+		return ModuleSync
+	case strings.Contains(dir, "/pkg/db/"):
+		return ModuleDB
+	case strings.HasSuffix(dir, "/internal/conversation_msg/"):
+		switch name {
+		case "sync.go", "incremental_sync.go", "sync_progress.go":
+			return ModuleSync
+		}
+		return ModuleConversation
+	}
+	return ""
+}
@@ -0,0 +1,161 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package log is the logger of the SDK. It writes the entries to files rotated by day and size and compressed
+// after, the level can be set for the modules of the SDK at runtime. The values of the entries are redacted
+// first in the privacy mode.
+package log
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	tlog "github.com/openimsdk/tools/log"
+	"go.uber.org/zap/zapcore"
+)
+
+// callDepth skips the functions of the package for the caller of an entry
+const callDepth = 2
+
+var (
+	// logger writes to the standard output until InitLoggerFromConfig
+	logger  atomic.Pointer[zapLogger]
+	privacy atomic.Bool
+	// initLock serializes InitLoggerFromConfig, the writer of the previous logger is closed
+	initLock sync.Mutex
+)
+
+func init() {
+	format := &entryFormat{moduleName: "DefaultLoggerModule", moduleVersion: "undefined version"}
+	logger.Store(&zapLogger{zap: newZapLogger(format, true, false, nil)})
+}
+
+// InitLoggerFromConfig initializes the logger of the SDK. The files are named <loggerPrefixName>.yyyy-mm-dd in
+// logLocation, rotateCount is the number of the days they are kept for and rotationTime is not used anymore,
+// the files are rotated by day and by the size set with SetMaxFileSize. The logger of openimsdk/tools only
+// writes the entries of the libraries to the standard output.
+func InitLoggerFromConfig(
+	loggerPrefixName, moduleName string,
+	sdkType, platformName string,
+	logLevel int,
+	isStdout bool,
+	isJson bool,
+	logLocation string,
+	rotateCount uint,
+	rotationTime uint,
+	moduleVersion string,
+	isSimplify bool,
+) error {
+	initLock.Lock()
+	defer initLock.Unlock()
+	if err := tlog.InitLoggerFromConfig(loggerPrefixName, moduleName, sdkType, platformName, logLevel, isStdout,
+		isJson, "", rotateCount, rotationTime, moduleVersion, isSimplify); err != nil {
+		return err
+	}
+	var writer *rotateWriter
+	if logLocation != "" {
+		var maxSize int64
+		if prev := logger.Load(); prev.writer != nil {
+			maxSize = prev.writer.maxSize.Load()
+		}
+		var err error
+		writer, err = newRotateWriter(logLocation, loggerPrefixName, maxSize, int(rotateCount))
+		if err != nil {
+			return err
+		}
+	}
+	format := &entryFormat{
+		moduleName:    moduleName,
+		moduleVersion: moduleVersion,
+		sdkType:       sdkType,
+		platformName:  platformName,
+	}
+	l := &zapLogger{zap: newZapLogger(format, isStdout, isJson, writer), writer: writer, isSimplify: isSimplify}
+	if isJson {
+		l.zap = l.zap.Named(moduleName)
+	}
+	level.Store(int32(logLevel))
+	prev := logger.Swap(l)
+	prev.flush()
+	if prev.writer != nil {
+		if err := prev.writer.close(); err != nil {
+			_, _ = fmt.Fprintln(os.Stderr, "failed to close log file", err)
+		}
+	}
+	return nil
+}
+
+// SetMaxFileSize sets the size in bytes a log file is rotated at, DefaultMaxFileSize when it is not positive.
+func SetMaxFileSize(size int64) {
+	if l := logger.Load(); l.writer != nil {
+		l.writer.setMaxSize(size)
+	}
+}
+
+// Flush writes the entries buffered to the file.
+func Flush() {
+	logger.Load().flush()
+}
+
+// SetPrivacy turns the privacy mode on or off. In the privacy mode the user ids are hashed and the content of
+// the messages is hidden, so that the logs can be shared with support.
+func SetPrivacy(enabled bool) {
+	privacy.Store(enabled)
+}
+
+func ZDebug(ctx context.Context, msg string, keysAndValues ...any) {
+	if !enabled(tlog.LevelDebug, 1) {
+		return
+	}
+	logger.Load().write(ctx, zapcore.DebugLevel, msg, nil, keysAndValues)
+}
+
+func ZInfo(ctx context.Context, msg string, keysAndValues ...any) {
+	if !enabled(tlog.LevelInfo, 1) {
+		return
+	}
+	logger.Load().write(ctx, zapcore.InfoLevel, msg, nil, keysAndValues)
+}
+
+func ZWarn(ctx context.Context, msg string, err error, keysAndValues ...any) {
+	if !enabled(tlog.LevelWarn, 1) {
+		return
+	}
+	logger.Load().write(ctx, zapcore.WarnLevel, msg, err, keysAndValues)
+}
+
+func ZError(ctx context.Context, msg string, err error, keysAndValues ...any) {
+	if !enabled(tlog.LevelError, 1) {
+		return
+	}
+	logger.Load().write(ctx, zapcore.ErrorLevel, msg, err, keysAndValues)
+}
+
+// SDKLog writes an entry of the app, native_caller is the file and the line it was written at.
+func SDKLog(ctx context.Context, logLevel int, file string, line int, msg string, err error, keysAndValues []any) {
+	kv := append([]any{"native_caller", fmt.Sprintf("[%s:%d]", file, line)}, keysAndValues...)
+	switch logLevel {
+	case tlog.LevelDebugWithSQL:
+		ZDebug(ctx, msg, kv...)
+	case tlog.LevelInfo:
+		ZInfo(ctx, msg, kv...)
+	case tlog.LevelWarn:
+		ZWarn(ctx, msg, err, kv...)
+	case tlog.LevelError:
+		ZError(ctx, msg, err, kv...)
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// hiddenKeys are the fields of the content of the messages and of the profiles hidden in the privacy mode,
+// in lower case.
+var hiddenKeys = map[string]struct{}{
+	"content":        {},
+	"text":           {},
+	"ex":             {},
+	"attachedinfo":   {},
+	"nickname":       {},
+	"sendernickname": {},
+	"showname":       {},
+	"faceurl":        {},
+	"senderfaceurl":  {},
+	"phonenumber":    {},
+	"email":          {},
+	"remark":         {},
+	"notification":   {},
+	"introduction":   {},
+	"desc":           {},
+	"description":    {},
+	"title":          {},
+	"keyword":        {},
+	"keywordlist":    {},
+}
+
+// isIDKey tells whether the values of the key are user ids, or conversation ids which hold the user ids of
+// the single chats.
+func isIDKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, suffix := range []string{"userid", "userids", "useridlist", "conversationid", "conversationids"} {
+		if strings.HasSuffix(key, suffix) {
+			return true
+		}
+	}
+	return key == "sendid" || key == "recvid" || key == "uid"
+}
+
+func isHiddenKey(key string) bool {
+	_, ok := hiddenKeys[strings.ToLower(key)]
+	return ok
+}
+
+// HashID is the id written to the logs in the privacy mode, support gets the entries of a user from the hash
+// of its id.
+func HashID(id string) string {
+	if id == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(id))
+	return "#" + hex.EncodeToString(sum[:6])
+}
+
+// hide keeps the length of a hidden string only.
+func hide(s string) string {
+	if s == "" {
+		return ""
+	}
+	return fmt.Sprintf("<hidden %d bytes>", len(s))
+}
+
+// redact returns the values of the entry redacted in the privacy mode.
+func redact(keysAndValues []any) []any {
+	if !privacy.Load() || len(keysAndValues) == 0 {
+		return keysAndValues
+	}
+	redacted := make([]any, len(keysAndValues))
+	for i := 0; i < len(keysAndValues); i++ {
+		redacted[i] = keysAndValues[i]
+		if i%2 == 0 {
+			continue
+		}
+		key, _ := keysAndValues[i-1].(string)
+		redacted[i] = redactValue(key, keysAndValues[i])
+	}
+	return redacted
+}
+
+func redactValue(key string, value any) any {
+	switch v := value.(type) {
+	case nil, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return value
+	case error:
+		return redactString(key, v.Error())
+	case string:
+		return redactString(key, v)
+	case []string:
+		if !isIDKey(key) && !isHiddenKey(key) {
+			return value
+		}
+		s := make([]string, len(v))
+		for i := range v {
+			s[i] = redactString(key, v[i])
+		}
+		return s
+	case []byte:
+		if isHiddenKey(key) {
+			return hide(string(v))
+		}
+		return value
+	}
+	if isHiddenKey(key) {
+		return "<hidden>"
+	}
+	rv := reflect.ValueOf(value)
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return value
+		}
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+	default:
+		return redactString(key, fmt.Sprint(value))
+	}
+	// the structs are redacted by their json fields, the way they are written to the logs
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("<%T not redacted: %s>", value, err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var tree any
+	if err := decoder.Decode(&tree); err != nil {
+		return fmt.Sprintf("<%T not redacted: %s>", value, err)
+	}
+	return redactTree(key, tree)
+}
+
+func redactString(key, s string) string {
+	switch {
+	case isIDKey(key):
+		return HashID(s)
+	case isHiddenKey(key):
+		return hide(s)
+	default:
+		return s
+	}
+}
+
+func redactTree(key string, tree any) any {
+	switch v := tree.(type) {
+	case map[string]any:
+		for k, value := range v {
+			v[k] = redactTree(k, value)
+		}
+		return v
+	case []any:
+		for i, value := range v {
+			v[i] = redactTree(key, value)
+		}
+		return v
+	case string:
+		return redactString(key, v)
+	case json.Number:
+		if isIDKey(key) {
+			return HashID(v.String())
+		}
+		return v
+	default:
+		return v
+	}
+}
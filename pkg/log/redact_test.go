@@ -0,0 +1,59 @@
+package log
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type testMsg struct {
+	SendID   string   `json:"sendID"`
+	Content  string   `json:"content"`
+	Seq      int64    `json:"seq"`
+	AtUsers  []string `json:"atUserIDList"`
+	TextElem *struct {
+		Content string `json:"content"`
+	} `json:"textElem"`
+}
+
+func TestRedact(t *testing.T) {
+	kv := []any{"userID", "u1", "content", "hello", "seq", 3, "groupID", "g1"}
+	if got := redact(kv); !reflect.DeepEqual(got, kv) {
+		t.Fatalf("redacted without the privacy mode %v", got)
+	}
+	SetPrivacy(true)
+	defer SetPrivacy(false)
+
+	got := redact(kv)
+	want := []any{"userID", HashID("u1"), "content", "<hidden 5 bytes>", "seq", 3, "groupID", "g1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if kv[1] != "u1" {
+		t.Fatal("the values of the caller changed")
+	}
+
+	msg := &testMsg{SendID: "u1", Content: "secret", Seq: 7, AtUsers: []string{"u2"}}
+	msg.TextElem = &struct {
+		Content string `json:"content"`
+	}{Content: "secret"}
+	tree, ok := redact([]any{"msg", msg})[1].(map[string]any)
+	if !ok {
+		t.Fatalf("struct not redacted by its fields")
+	}
+	if tree["sendID"] != HashID("u1") || tree["content"] != "<hidden 6 bytes>" || tree["seq"].(interface{ String() string }).String() != "7" {
+		t.Fatalf("unexpected fields %v", tree)
+	}
+	if at := tree["atUserIDList"].([]any); at[0] != HashID("u2") {
+		t.Fatalf("unexpected at users %v", at)
+	}
+	if elem := tree["textElem"].(map[string]any); elem["content"] != "<hidden 6 bytes>" {
+		t.Fatalf("nested content not hidden %v", elem)
+	}
+	if ids := redact([]any{"conversationIDs", []string{"si_u1_u2"}})[1].([]string); ids[0] != HashID("si_u1_u2") {
+		t.Fatalf("conversation ids not hashed %v", ids)
+	}
+	if err := redact([]any{"content", errors.New("secret")})[1]; err != "<hidden 6 bytes>" {
+		t.Fatalf("error not hidden %v", err)
+	}
+}
@@ -0,0 +1,305 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	dayLayout = "2006-01-02"
+	// DefaultMaxFileSize is the size a log file is rotated at when no size is set
+	DefaultMaxFileSize = 20 << 20
+	compressedExt      = ".gz"
+)
+
+// rotateWriter writes the entries of a day to <prefix>.yyyy-mm-dd. The file is rotated when it reaches the
+// max size and when the day ends, it is then compressed to <prefix>.yyyy-mm-dd.<n>.gz. The files of the days
+// older than maxAge are removed.
+type rotateWriter struct {
+	dir     string
+	prefix  string
+	maxSize atomic.Int64
+	// maxAge is the number of the days the files are kept for, today included
+	maxAge int
+
+	lock        sync.Mutex
+	file        *os.File
+	day         string
+	size        int64
+	now         func() time.Time
+	compressing sync.WaitGroup
+}
+
+func newRotateWriter(dir, prefix string, maxSize int64, maxAge int) (*rotateWriter, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	w := &rotateWriter{dir: dir, prefix: prefix + ".", maxAge: maxAge, now: time.Now}
+	w.setMaxSize(maxSize)
+	// the files rotated by a previous process and not compressed before it ended
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(name, w.prefix) {
+			continue
+		}
+		if strings.HasSuffix(name, compressedExt+".tmp") {
+			_ = os.Remove(filepath.Join(dir, name))
+			continue
+		}
+		if _, part, gz, ok := parseName(w.prefix, name); ok && part > 0 && !gz {
+			w.compress(filepath.Join(dir, name))
+		}
+	}
+	w.removeExpired(w.now())
+	return w, nil
+}
+
+func (w *rotateWriter) setMaxSize(size int64) {
+	if size <= 0 {
+		size = DefaultMaxFileSize
+	}
+	w.maxSize.Store(size)
+}
+
+func (w *rotateWriter) Write(p []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	now := w.now()
+	day := now.Format(dayLayout)
+	if w.file != nil && (day != w.day || (w.size > 0 && w.size+int64(len(p)) > w.maxSize.Load())) {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+		if day != w.day {
+			w.removeExpired(now)
+		}
+	}
+	if w.file == nil {
+		if err := w.open(day); err != nil {
+			return 0, err
+		}
+	}
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+func (w *rotateWriter) Sync() error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.file == nil {
+		return nil
+	}
+	return w.file.Sync()
+}
+
+// close closes the file and waits for the rotated ones to be compressed.
+func (w *rotateWriter) close() error {
+	w.lock.Lock()
+	var err error
+	if w.file != nil {
+		err = w.file.Close()
+		w.file = nil
+	}
+	w.lock.Unlock()
+	w.compressing.Wait()
+	return err
+}
+
+func (w *rotateWriter) open(day string) error {
+	file, err := os.OpenFile(filepath.Join(w.dir, w.prefix+day), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	stat, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	w.file, w.day, w.size = file, day, stat.Size()
+	return nil
+}
+
+// rotate closes the file of the day and renames it to its part, which is compressed in the background.
+func (w *rotateWriter) rotate() error {
+	name := w.file.Name()
+	err := w.file.Close()
+	w.file = nil
+	if err != nil {
+		return err
+	}
+	part := w.nextPart(w.day)
+	rotated := name + "." + strconv.Itoa(part)
+	if err := os.Rename(name, rotated); err != nil {
+		return err
+	}
+	w.compress(rotated)
+	return nil
+}
+
+// nextPart is the part after the ones of the day rotated so far.
+func (w *rotateWriter) nextPart(day string) int {
+	entries, err := os.ReadDir(w.dir)
+	if err != nil {
+		return 1
+	}
+	var last int
+	for _, entry := range entries {
+		if d, part, _, ok := parseName(w.prefix, entry.Name()); ok && d.Format(dayLayout) == day {
+			last = max(last, part)
+		}
+	}
+	return last + 1
+}
+
+func (w *rotateWriter) compress(path string) {
+	w.compressing.Add(1)
+	go func() {
+		defer w.compressing.Done()
+		if err := compressFile(path); err != nil {
+			// the logger can not log its own failure
+			_, _ = fmt.Fprintln(os.Stderr, "compress rotated log failed", path, err)
+		}
+	}()
+}
+
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	tmp := path + compressedExt + ".tmp"
+	dst, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(dst)
+	if _, err := io.Copy(zw, src); err != nil {
+		_ = dst.Close()
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		_ = dst.Close()
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, path+compressedExt); err != nil {
+		return err
+	}
+	_ = src.Close()
+	return os.Remove(path)
+}
+
+// removeExpired removes the files of the days before the last maxAge days.
+func (w *rotateWriter) removeExpired(now time.Time) {
+	if w.maxAge <= 0 {
+		return
+	}
+	y, m, d := now.Date()
+	oldest := time.Date(y, m, d, 0, 0, 0, 0, time.Local).AddDate(0, 0, 1-w.maxAge)
+	entries, err := os.ReadDir(w.dir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		day, _, _, ok := parseName(w.prefix, entry.Name())
+		if ok && day.Before(oldest) {
+			_ = os.Remove(filepath.Join(w.dir, entry.Name()))
+		}
+	}
+}
+
+// parseName parses <prefix>yyyy-mm-dd, part 0, and the parts rotated <prefix>yyyy-mm-dd.<n>, gz when they are
+// compressed to <prefix>yyyy-mm-dd.<n>.gz.
+func parseName(prefix, name string) (day time.Time, part int, gz bool, ok bool) {
+	rest, found := strings.CutPrefix(name, prefix)
+	if !found || len(rest) < len(dayLayout) {
+		return time.Time{}, 0, false, false
+	}
+	day, err := time.ParseInLocation(dayLayout, rest[:len(dayLayout)], time.Local)
+	if err != nil {
+		return time.Time{}, 0, false, false
+	}
+	rest = rest[len(dayLayout):]
+	if rest == "" {
+		return day, 0, false, true
+	}
+	rest, gz = strings.CutSuffix(rest, compressedExt)
+	if !strings.HasPrefix(rest, ".") {
+		return time.Time{}, 0, false, false
+	}
+	part, err = strconv.Atoi(rest[1:])
+	if err != nil || part <= 0 {
+		return time.Time{}, 0, false, false
+	}
+	return day, part, gz, true
+}
+
+// ParseFileName parses the name of a log file of the prefix, the file of a day being written is part 0 and
+// the parts rotated and compressed follow. A part not compressed yet is not a log file.
+func ParseFileName(prefix, name string) (day time.Time, part int, ok bool) {
+	day, part, gz, ok := parseName(prefix+".", name)
+	if !ok || (part > 0 && !gz) {
+		return time.Time{}, 0, false
+	}
+	return day, part, true
+}
+
+// OpenFile opens a log file, the rotated ones are decompressed.
+func OpenFile(path string) (io.ReadCloser, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(path, compressedExt) {
+		return file, nil
+	}
+	zr, err := gzip.NewReader(file)
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return &gzipFile{Reader: zr, file: file}, nil
+}
+
+type gzipFile struct {
+	*gzip.Reader
+	file *os.File
+}
+
+func (f *gzipFile) Close() error {
+	_ = f.Reader.Close()
+	return f.file.Close()
+}
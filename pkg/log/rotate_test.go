@@ -0,0 +1,112 @@
+package log
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func dirNames(t *testing.T, dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestRotateWriter(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2024, 5, 6, 10, 0, 0, 0, time.Local)
+	w, err := newRotateWriter(dir, "sdk", 16, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.now = func() time.Time { return now }
+	for _, line := range []string{"0123456789\n", "abcdefghij\n", "klmnopqrst\n"} {
+		if _, err := w.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	now = now.AddDate(0, 0, 1)
+	if _, err := w.Write([]byte("next day\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.close(); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"sdk.2024-05-06.1.gz", "sdk.2024-05-06.2.gz", "sdk.2024-05-06.3.gz", "sdk.2024-05-07"}
+	if got := dirNames(t, dir); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("got files %v, want %v", got, want)
+	}
+	f, err := OpenFile(filepath.Join(dir, "sdk.2024-05-06.2.gz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(f)
+	_ = f.Close()
+	if err != nil || string(data) != "abcdefghij\n" {
+		t.Fatalf("decompressed %q, %v", data, err)
+	}
+
+	// 2 days are kept, the files of 2024-05-06 expire on 2024-05-08
+	w.removeExpired(now.AddDate(0, 0, 1))
+	if got := dirNames(t, dir); strings.Join(got, ",") != "sdk.2024-05-07" {
+		t.Fatalf("got files %v after expiry", got)
+	}
+}
+
+func TestParseFileName(t *testing.T) {
+	for name, want := range map[string]int{
+		"sdk.2024-05-06":        0,
+		"sdk.2024-05-06.3.gz":   3,
+		"sdk.2024-05-06.3":      -1,
+		"sdk.2024-05-06.0.gz":   -1,
+		"other.2024-05-06":      -1,
+		"sdk.2024-05-06.gz":     -1,
+		"sdk.2024-05-06.3.gz.x": -1,
+	} {
+		day, part, ok := ParseFileName("sdk", name)
+		if want < 0 {
+			if ok {
+				t.Errorf("%s parsed as part %d", name, part)
+			}
+			continue
+		}
+		if !ok || part != want || day.Format(dayLayout) != "2024-05-06" {
+			t.Errorf("%s parsed as %s part %d, %v", name, day, part, ok)
+		}
+	}
+}
+
+func TestModuleLevel(t *testing.T) {
+	defer moduleLevels.Store(nil)
+	defer level.Store(level.Load())
+	level.Store(3)
+	if err := SetLevel("unknown", 5); err == nil {
+		t.Fatal("unknown module accepted")
+	}
+	if err := SetLevel(ModuleDB, 6); err != nil {
+		t.Fatal(err)
+	}
+	if l := moduleLevel(fileModule("/src/pkg/db/group.go")); l != 6 {
+		t.Fatalf("db level %d", l)
+	}
+	if l := moduleLevel(fileModule("/src/internal/interaction/msg_sync.go")); l != 3 {
+		t.Fatalf("sync level %d", l)
+	}
+	if err := SetLevel(ModuleDB, -1); err != nil {
+		t.Fatal(err)
+	}
+	if levels := Levels(); len(levels) != 1 || levels[""] != 3 {
+		t.Fatalf("levels %v", levels)
+	}
+}
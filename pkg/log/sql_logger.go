@@ -0,0 +1,130 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	tlog "github.com/openimsdk/tools/log"
+	"go.uber.org/zap/zapcore"
+	"gorm.io/gorm"
+	gormLogger "gorm.io/gorm/logger"
+	gormUtils "gorm.io/gorm/utils"
+)
+
+const nanosecondsToMilliseconds = 1e6
+
+// SqlLogger is the logger of gorm, its entries follow the level of ModuleDB. The statements are written when
+// the level is LevelDebugWithSQL, whatever the level of gorm it was opened with, and never in the privacy mode
+// as they hold the values written.
+type SqlLogger struct {
+	LogLevel                  gormLogger.LogLevel
+	IgnoreRecordNotFoundError bool
+	SlowThreshold             time.Duration
+}
+
+func NewSqlLogger(logLevel gormLogger.LogLevel, ignoreRecordNotFoundError bool, slowThreshold time.Duration) *SqlLogger {
+	return &SqlLogger{
+		LogLevel:                  logLevel,
+		IgnoreRecordNotFoundError: ignoreRecordNotFoundError,
+		SlowThreshold:             slowThreshold,
+	}
+}
+
+func (l *SqlLogger) LogMode(logLevel gormLogger.LogLevel) gormLogger.Interface {
+	newLogger := *l
+	newLogger.LogLevel = logLevel
+	return &newLogger
+}
+
+func (SqlLogger) Info(ctx context.Context, msg string, args ...any) {
+	dbWrite(ctx, zapcore.InfoLevel, msg, nil, "args", args)
+}
+
+func (SqlLogger) Warn(ctx context.Context, msg string, args ...any) {
+	dbWrite(ctx, zapcore.WarnLevel, msg, nil, "args", args)
+}
+
+func (SqlLogger) Error(ctx context.Context, msg string, args ...any) {
+	var err error
+	kvList := make([]any, 0, len(args)*2)
+	for i, arg := range args {
+		if e, ok := arg.(error); ok && i == 0 {
+			err = e
+			continue
+		}
+		kvList = append(kvList, fmt.Sprintf("args[%v]", i), arg)
+	}
+	dbWrite(ctx, zapcore.ErrorLevel, msg, err, kvList...)
+}
+
+// logLevel is the level of gorm at the moment, the one of ModuleDB can be changed after the database is opened.
+func (l *SqlLogger) logLevel() gormLogger.LogLevel {
+	switch {
+	case privacy.Load():
+		return gormLogger.Silent
+	case moduleLevel(ModuleDB) >= LevelDebugWithSQL:
+		return gormLogger.Info
+	case l.LogLevel == gormLogger.Info:
+		return gormLogger.Warn
+	}
+	return l.LogLevel
+}
+
+func (l *SqlLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	logLevel := l.logLevel()
+	if logLevel <= gormLogger.Silent {
+		return
+	}
+	elapsed := time.Since(begin)
+	elapsedMs := fmt.Sprintf("%f(ms)", float64(elapsed.Nanoseconds())/nanosecondsToMilliseconds)
+	switch {
+	case err != nil && logLevel >= gormLogger.Error && (!errors.Is(err, gorm.ErrRecordNotFound) || !l.IgnoreRecordNotFoundError):
+		sql, rows := fc()
+		dbWrite(ctx, zapcore.ErrorLevel, "sql exec detail", err, traceValues(rows, sql, "gorm", gormUtils.FileWithLineNum(), "elapsed time", elapsedMs)...)
+	case elapsed > l.SlowThreshold && l.SlowThreshold != 0 && logLevel >= gormLogger.Warn:
+		sql, rows := fc()
+		slowLog := fmt.Sprintf("SLOW SQL >= %v", l.SlowThreshold)
+		dbWrite(ctx, zapcore.WarnLevel, "sql exec detail", nil, traceValues(rows, sql, "gorm", gormUtils.FileWithLineNum(), "slow sql", slowLog, "elapsed time", elapsedMs)...)
+	case logLevel == gormLogger.Info:
+		sql, rows := fc()
+		dbWrite(ctx, zapcore.DebugLevel, "sql exec detail", nil, traceValues(rows, sql, "gorm", gormUtils.FileWithLineNum(), "elapsed time", elapsedMs)...)
+	}
+}
+
+func traceValues(rows int64, sql string, keysAndValues ...any) []any {
+	if rows != -1 {
+		keysAndValues = append(keysAndValues, "rows", rows)
+	}
+	return append(keysAndValues, "sql", sql)
+}
+
+var zapLevels = map[zapcore.Level]int{
+	zapcore.DebugLevel: tlog.LevelDebug,
+	zapcore.InfoLevel:  tlog.LevelInfo,
+	zapcore.WarnLevel:  tlog.LevelWarn,
+	zapcore.ErrorLevel: tlog.LevelError,
+}
+
+// dbWrite writes an entry of ModuleDB, the caller is the function of SqlLogger.
+func dbWrite(ctx context.Context, level zapcore.Level, msg string, err error, keysAndValues ...any) {
+	if zapLevels[level] > moduleLevel(ModuleDB) {
+		return
+	}
+	logger.Load().write(ctx, level, msg, err, keysAndValues)
+}
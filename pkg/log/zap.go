@@ -0,0 +1,200 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/openimsdk/protocol/constant"
+	tlog "github.com/openimsdk/tools/log"
+	"github.com/openimsdk/tools/mcontext"
+	"github.com/openimsdk/tools/utils/stringutil"
+	"go.uber.org/zap"
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// The entries are written the way openimsdk/tools writes them, the time, the level, the pid, the module, the
+// version and the caller aligned in columns, so that the tools reading the logs keep working.
+const (
+	timeLayout    = "2006-01-02 15:04:05.000"
+	callerLength  = 50
+	messageLength = 50
+	pidLength     = 15
+	moduleLength  = 25
+	versionLength = 30
+	bufferedSize  = 512 * 1024
+	bufferedFlush = 2 * time.Second
+)
+
+var levelColors = map[zapcore.Level]int{
+	zapcore.DebugLevel:  37,
+	zapcore.InfoLevel:   34,
+	zapcore.WarnLevel:   33,
+	zapcore.ErrorLevel:  31,
+	zapcore.DPanicLevel: 31,
+	zapcore.PanicLevel:  31,
+	zapcore.FatalLevel:  31,
+}
+
+func color(level zapcore.Level, s string) string {
+	c, ok := levelColors[level]
+	if !ok {
+		c = levelColors[zapcore.ErrorLevel]
+	}
+	return fmt.Sprintf("\x1b[%dm%s\x1b[0m", c, s)
+}
+
+type zapLogger struct {
+	zap        *zap.SugaredLogger
+	writer     *rotateWriter
+	isSimplify bool
+}
+
+type entryFormat struct {
+	moduleName    string
+	moduleVersion string
+	sdkType       string
+	platformName  string
+}
+
+// alignEncoder pads the message of the entries to a column.
+type alignEncoder struct {
+	zapcore.Encoder
+}
+
+func (e *alignEncoder) Clone() zapcore.Encoder {
+	return &alignEncoder{Encoder: e.Encoder.Clone()}
+}
+
+func (e *alignEncoder) EncodeEntry(entry zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	entry.Message = fmt.Sprintf("%-*s", messageLength, entry.Message)
+	return e.Encoder.EncodeEntry(entry, fields)
+}
+
+func (f *entryFormat) encoder(isJson bool) zapcore.Encoder {
+	c := zap.NewProductionEncoderConfig()
+	c.EncodeTime = func(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
+		enc.AppendString(t.Format(timeLayout))
+	}
+	c.EncodeDuration = zapcore.StringDurationEncoder
+	c.MessageKey = "msg"
+	c.LevelKey = "level"
+	c.TimeKey = "time"
+	c.CallerKey = "caller"
+	c.NameKey = "logger"
+	var encoder zapcore.Encoder
+	if isJson {
+		c.EncodeLevel = zapcore.CapitalLevelEncoder
+		encoder = zapcore.NewJSONEncoder(c)
+		encoder.AddInt("PID", os.Getpid())
+		encoder.AddString("version", f.moduleVersion)
+	} else {
+		c.EncodeLevel = f.encodeLevel
+		c.EncodeCaller = f.encodeCaller
+		encoder = zapcore.NewConsoleEncoder(c)
+	}
+	return &alignEncoder{Encoder: encoder}
+}
+
+func (f *entryFormat) encodeLevel(level zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+	enc.AppendString(color(level, level.CapitalString()))
+	enc.AppendString(color(level, stringutil.FormatString(fmt.Sprintf("[PID:%d]", os.Getpid()), pidLength, true)))
+	if f.moduleName != "" {
+		enc.AppendString(color(level, stringutil.FormatString(f.moduleName, moduleLength, true)))
+	}
+	if f.moduleVersion != "" {
+		enc.AppendString(stringutil.FormatString(fmt.Sprintf("[%s]", f.moduleVersion), versionLength, true))
+	}
+}
+
+func (f *entryFormat) encodeCaller(caller zapcore.EntryCaller, enc zapcore.PrimitiveArrayEncoder) {
+	if f.sdkType != "" && f.platformName != "" {
+		enc.AppendString(stringutil.FormatString(fmt.Sprintf("[%s/%s]", f.sdkType, f.platformName), callerLength, true))
+	}
+	enc.AppendString(stringutil.FormatString("["+caller.TrimmedPath()+"]", callerLength, true))
+}
+
+// newZapLogger writes the entries to the rotated files of the directory and to the standard output, the levels
+// are checked before.
+func newZapLogger(format *entryFormat, isStdout, isJson bool, writer *rotateWriter) *zap.SugaredLogger {
+	encoder := format.encoder(isJson)
+	var cores []zapcore.Core
+	if writer != nil {
+		var ws zapcore.WriteSyncer = writer
+		if !isStdout {
+			ws = &zapcore.BufferedWriteSyncer{WS: writer, FlushInterval: bufferedFlush, Size: bufferedSize}
+		}
+		cores = append(cores, zapcore.NewCore(encoder, ws, zapcore.DebugLevel))
+	}
+	if isStdout {
+		cores = append(cores, zapcore.NewCore(encoder, zapcore.Lock(os.Stdout), zapcore.DebugLevel))
+	}
+	// write adds the frames of the package, the caller is the one of ZDebug, ZInfo, ZWarn or ZError
+	return zap.New(zapcore.NewTee(cores...), zap.AddCaller(), zap.AddCallerSkip(callDepth)).Sugar()
+}
+
+func (l *zapLogger) write(ctx context.Context, level zapcore.Level, msg string, err error, keysAndValues []any) {
+	keysAndValues = l.kvAppend(ctx, redact(keysAndValues))
+	if err != nil {
+		keysAndValues = append(keysAndValues, "error", err.Error())
+	}
+	switch level {
+	case zapcore.DebugLevel:
+		l.zap.Debugw(msg, keysAndValues...)
+	case zapcore.InfoLevel:
+		l.zap.Infow(msg, keysAndValues...)
+	case zapcore.WarnLevel:
+		l.zap.Warnw(msg, keysAndValues...)
+	default:
+		l.zap.Errorw(msg, keysAndValues...)
+	}
+}
+
+// kvAppend adds the ids of the context to the values of an entry.
+func (l *zapLogger) kvAppend(ctx context.Context, keysAndValues []any) []any {
+	if ctx == nil {
+		return keysAndValues
+	}
+	if l.isSimplify && len(keysAndValues)%2 == 0 {
+		for i := 1; i < len(keysAndValues); i += 2 {
+			if v, ok := keysAndValues[i].(tlog.LogFormatter); ok && v != nil {
+				keysAndValues[i] = v.Format()
+			}
+		}
+	}
+	for _, kv := range [][2]string{
+		{constant.OpUserID, mcontext.GetOpUserID(ctx)},
+		{constant.OperationID, mcontext.GetOperationID(ctx)},
+		{constant.ConnID, mcontext.GetConnID(ctx)},
+		{constant.TriggerID, mcontext.GetTriggerID(ctx)},
+		{constant.OpUserPlatform, mcontext.GetOpUserPlatform(ctx)},
+		{constant.RemoteAddr, mcontext.GetRemoteAddr(ctx)},
+	} {
+		if kv[1] != "" {
+			keysAndValues = append([]any{kv[0], kv[1]}, keysAndValues...)
+		}
+	}
+	return keysAndValues
+}
+
+func (l *zapLogger) flush() {
+	if err := l.zap.Sync(); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, "failed to flush zap logger", err)
+	}
+}
@@ -0,0 +1,238 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !js
+
+package network
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/ccontext"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/protocol/errinfo"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const grpcNegotiateTimeout = time.Second * 5
+
+// ErrGrpcUnimplemented is returned when the server does not serve the method over grpc, the call is then
+// made over http.
+var ErrGrpcUnimplemented = errors.New("grpc method unimplemented")
+
+type grpcClient struct {
+	conn *grpc.ClientConn
+	// unimplemented are the methods the server answered Unimplemented, they are called over http from now on
+	unimplemented sync.Map
+}
+
+// grpcActiveKey is the key of the grpc client of a user context once the server confirmed it serves grpc, nil
+// means the api calls use http.
+type grpcActiveKey struct{}
+
+func grpcActive(ctx context.Context) *atomic.Pointer[grpcClient] {
+	return ccontext.State(ctx, grpcActiveKey{}, func() *atomic.Pointer[grpcClient] { return new(atomic.Pointer[grpcClient]) })
+}
+
+// SetGrpc dials the grpc address, grpc://host:port or grpcs://host:port for tls, and negotiates with the
+// server in background through the standard health service. The api calls of the user context of ctx keep
+// using http until the server answers it is serving, and whenever the negotiation fails. An empty address
+// turns grpc off.
+func SetGrpc(ctx context.Context, addr string) error {
+	active := grpcActive(ctx)
+	if old := active.Swap(nil); old != nil {
+		_ = old.conn.Close()
+	}
+	if addr == "" {
+		return nil
+	}
+	u, err := url.Parse(addr)
+	if err != nil {
+		return sdkerrs.ErrArgs.WrapMsg("invalid grpc address " + err.Error())
+	}
+	var creds credentials.TransportCredentials
+	switch u.Scheme {
+	case "grpc":
+		creds = insecure.NewCredentials()
+	case "grpcs":
+		creds = credentials.NewTLS(TLSConfig(ctx, u.Hostname()))
+	default:
+		return sdkerrs.ErrArgs.WrapMsg("unsupported grpc address " + addr)
+	}
+	conn, err := grpc.NewClient(u.Host, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return sdkerrs.ErrArgs.WrapMsg("grpc client " + err.Error())
+	}
+	go negotiateGrpc(ctx, active, conn)
+	return nil
+}
+
+func negotiateGrpc(ctx context.Context, active *atomic.Pointer[grpcClient], conn *grpc.ClientConn) {
+	checkCtx, cancel := context.WithTimeout(ctx, grpcNegotiateTimeout)
+	defer cancel()
+	resp, err := grpc_health_v1.NewHealthClient(conn).Check(checkCtx, &grpc_health_v1.HealthCheckRequest{})
+	if err != nil || resp.GetStatus() != grpc_health_v1.HealthCheckResponse_SERVING {
+		log.ZWarn(ctx, "grpc not supported by the server, api calls use http", err, "target", conn.Target())
+		_ = conn.Close()
+		return
+	}
+	if !active.CompareAndSwap(nil, &grpcClient{conn: conn}) {
+		// SetGrpc was called again meanwhile
+		_ = conn.Close()
+		return
+	}
+	log.ZInfo(ctx, "api calls use grpc", "target", conn.Target())
+}
+
+// GrpcAvailable reports whether the method can be called over grpc.
+func GrpcAvailable(ctx context.Context, method string) bool {
+	client := grpcActive(ctx).Load()
+	if client == nil {
+		return false
+	}
+	_, unimplemented := client.unimplemented.Load(method)
+	return !unimplemented
+}
+
+// GrpcInvoke calls a unary method of the server, req and resp are protobuf messages.
+// It returns ErrGrpcUnimplemented when the method is not served over grpc.
+func GrpcInvoke(ctx context.Context, method string, req, resp any) (err error) {
+	client := grpcActive(ctx).Load()
+	if client == nil {
+		return ErrGrpcUnimplemented
+	}
+	rpcCtx, err := grpcContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer func(start time.Time) {
+		elapsed := time.Since(start).Milliseconds()
+		if err == nil {
+			log.ZDebug(ctx, "CallGrpc", "duration", fmt.Sprintf("%dms", elapsed), "method", method, "state", "success")
+		} else {
+			log.ZError(ctx, "CallGrpc", err, "duration", fmt.Sprintf("%dms", elapsed), "method", method, "state", "failed")
+		}
+	}(time.Now())
+	log.ZDebug(ctx, "GrpcRequest", "method", method, "req", req)
+	if err := client.conn.Invoke(rpcCtx, method, req, resp); err != nil {
+		return client.handleError(ctx, method, err)
+	}
+	countGrpcTraffic(req, resp)
+	log.ZDebug(ctx, "GrpcResponse", "method", method, "resp", resp)
+	return nil
+}
+
+// GrpcStream calls a server streaming method, newResp allocates each message and fn handles it.
+// It returns ErrGrpcUnimplemented when the method is not served over grpc and no message was received.
+func GrpcStream(ctx context.Context, method string, req any, newResp func() any, fn func(resp any) error) error {
+	client := grpcActive(ctx).Load()
+	if client == nil {
+		return ErrGrpcUnimplemented
+	}
+	rpcCtx, err := grpcContext(ctx)
+	if err != nil {
+		return err
+	}
+	rpcCtx, cancel := context.WithCancel(rpcCtx)
+	defer cancel()
+	stream, err := client.conn.NewStream(rpcCtx, &grpc.StreamDesc{ServerStreams: true}, method)
+	if err != nil {
+		return client.handleError(ctx, method, err)
+	}
+	if err := stream.SendMsg(req); err != nil && !errors.Is(err, io.EOF) {
+		return client.handleError(ctx, method, err)
+	}
+	if err := stream.CloseSend(); err != nil {
+		return client.handleError(ctx, method, err)
+	}
+	countGrpcTraffic(req, nil)
+	for {
+		resp := newResp()
+		if err := stream.RecvMsg(resp); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return client.handleError(ctx, method, err)
+		}
+		countGrpcTraffic(nil, resp)
+		if err := fn(resp); err != nil {
+			return err
+		}
+	}
+}
+
+func grpcContext(ctx context.Context) (context.Context, error) {
+	operationID, _ := ctx.Value("operationID").(string)
+	if operationID == "" {
+		err := sdkerrs.ErrArgs.WrapMsg("call api operationID is empty")
+		log.ZError(ctx, "GrpcRequest", err, "type", "ctx not set operationID")
+		return nil, err
+	}
+	return metadata.AppendToOutgoingContext(ctx, "operationID", operationID, "token", ccontext.Info(ctx).Token()), nil
+}
+
+// handleError turns the status into the error the http call would return. The server sends the error code
+// of the api as the status code, only Unavailable, DeadlineExceeded and Canceled are transport errors.
+func (c *grpcClient) handleError(ctx context.Context, method string, err error) error {
+	sta, ok := status.FromError(err)
+	if !ok {
+		return sdkerrs.ErrNetwork.WrapMsg("grpc call failed " + err.Error())
+	}
+	switch {
+	case sta.Code() == codes.Unimplemented:
+		c.unimplemented.Store(method, struct{}{})
+		log.ZWarn(ctx, "grpc method unimplemented, use http", err, "method", method)
+		return ErrGrpcUnimplemented
+	case sta.Code() == codes.Unavailable, sta.Code() == codes.DeadlineExceeded, sta.Code() == codes.Canceled:
+		return sdkerrs.ErrNetwork.WrapMsg("grpc call failed " + sta.Message())
+	}
+	var detail string
+	if details := sta.Details(); len(details) > 0 {
+		if errInfo, ok := details[0].(*errinfo.ErrorInfo); ok {
+			detail = strings.Join(errInfo.Warp, "->") + errInfo.Cause
+		}
+	}
+	codeErr := sdkerrs.New(int(sta.Code()), sta.Message(), detail)
+	ccontext.GetApiErrCodeCallback(ctx).OnError(ctx, codeErr)
+	return codeErr
+}
+
+// countGrpcTraffic counts the messages as api traffic, protobuf is not compressed so both sizes are the same.
+func countGrpcTraffic(req, resp any) {
+	var n int
+	if m, ok := req.(proto.Message); ok {
+		n += proto.Size(m)
+	}
+	if m, ok := resp.(proto.Message); ok {
+		n += proto.Size(m)
+	}
+	apiTraffic.raw.Add(int64(n))
+	apiTraffic.wire.Add(int64(n))
+}
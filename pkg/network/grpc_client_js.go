@@ -0,0 +1,48 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build js
+
+package network
+
+import (
+	"context"
+	"errors"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+)
+
+// ErrGrpcUnimplemented is returned when the server does not serve the method over grpc, the call is then
+// made over http.
+var ErrGrpcUnimplemented = errors.New("grpc method unimplemented")
+
+// SetGrpc is not supported in the browser, which can not speak grpc over http2, the api calls use http.
+func SetGrpc(ctx context.Context, addr string) error {
+	if addr != "" {
+		log.ZWarn(ctx, "grpc is not supported in wasm, api calls use http", nil, "addr", addr)
+	}
+	return nil
+}
+
+func GrpcAvailable(context.Context, string) bool {
+	return false
+}
+
+func GrpcInvoke(context.Context, string, any, any) error {
+	return ErrGrpcUnimplemented
+}
+
+func GrpcStream(context.Context, string, any, func() any, func(any) error) error {
+	return ErrGrpcUnimplemented
+}
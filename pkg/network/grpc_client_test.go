@@ -0,0 +1,32 @@
+//go:build !js
+
+package network
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/tools/errs"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGrpcHandleError(t *testing.T) {
+	var c grpcClient
+	for code, want := range map[codes.Code]int{
+		codes.Unavailable:      sdkerrs.NetworkError,
+		codes.DeadlineExceeded: sdkerrs.NetworkError,
+		codes.Canceled:         sdkerrs.NetworkError,
+		codes.PermissionDenied: int(codes.PermissionDenied),
+		codes.Unauthenticated:  int(codes.Unauthenticated),
+		codes.Code(1501):       1501,
+	} {
+		err := c.handleError(context.Background(), "/test", status.Error(code, "failed"))
+		var codeErr errs.CodeError
+		if !errors.As(err, &codeErr) || codeErr.Code() != want {
+			t.Fatal(code, err)
+		}
+	}
+}
@@ -26,17 +26,32 @@ import (
 	"time"
 
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/ccontext"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/page"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
 	"github.com/openimsdk/tools/errs"
 
 	"github.com/openimsdk/protocol/sdkws"
-	"github.com/openimsdk/tools/log"
 )
 
-// apiClient is a global HTTP client with a timeout of one minute.
-var apiClient = &http.Client{
-	Timeout: time.Second * 10,
+// apiClientKey is the key of the HTTP client of the api requests of a user context, its connections are checked
+// against the pins of the user context. The timeouts of the requests are the ones of their request policies.
+type apiClientKey struct{}
+
+func contextApiClient(ctx context.Context) *http.Client {
+	return ccontext.State(ctx, apiClientKey{}, func() *http.Client {
+		return &http.Client{Transport: newApiTransport(ctx)}
+	})
+}
+
+// newApiTransport returns the transport of the api requests of the user context of ctx, the proxy of a request
+// is the one of its user context.
+func newApiTransport(ctx context.Context) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = ApiProxy
+	transport.TLSClientConfig = TLSConfig(ctx, "")
+	return transport
 }
 
 // ApiResponse represents the standard structure of an API response.
@@ -98,10 +113,12 @@ func ApiPost(ctx context.Context, api string, req, resp any) (err error) {
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("operationID", operationID)
 	request.Header.Set("token", ctxInfo.Token())
-	request.Header.Set("Accept-Encoding", "gzip")
+	if ctxInfo.Compression() != constant.CompressionNone {
+		request.Header.Set("Accept-Encoding", "gzip")
+	}
 
 	// Send the request and receive the response.
-	response, err := apiClient.Do(request)
+	response, err := contextApiClient(ctx).Do(request)
 	if err != nil {
 		log.ZError(ctx, "ApiRequest", err, "type", "network error")
 		return sdkerrs.ErrNetwork.WrapMsg("ApiPost http.Client.Do failed " + err.Error())
@@ -109,24 +126,34 @@ func ApiPost(ctx context.Context, api string, req, resp any) (err error) {
 
 	// Ensure the response body is closed after processing.
 	defer response.Body.Close()
-	var body io.ReadCloser
+	wire := &countingReader{ReadCloser: ThrottleReadCloser(ctx, constant.BandwidthSync, response.Body)}
+	var (
+		body       io.ReadCloser
+		compressed bool
+	)
 	switch contentEncoding := response.Header.Get("Content-Encoding"); contentEncoding {
 	case "":
-		body = response.Body
+		body = wire
 	case "gzip":
-		body, err = gzip.NewReader(response.Body)
+		body, err = gzip.NewReader(wire)
 		if err != nil {
 			log.ZError(ctx, "http response gzip NewReader failed", err, "url", reqUrl)
 			return sdkerrs.ErrSdkInternal.WrapMsg("gzip NewReader failed " + err.Error())
 		}
+		compressed = true
 
 		defer body.Close()
 	default:
 		log.ZWarn(ctx, "http response content encoding not supported", nil, "url", reqUrl, "contentEncoding", contentEncoding)
-		body = response.Body
+		body = wire
 	}
 	// Read the response body.
 	respBody, err := io.ReadAll(body)
+	apiTraffic.raw.Add(int64(len(reqBody) + len(respBody)))
+	apiTraffic.wire.Add(int64(len(reqBody) + wire.n))
+	if compressed {
+		apiTraffic.saved.Add(int64(len(respBody) - wire.n))
+	}
 	if err != nil {
 		log.ZError(ctx, "ApiResponse", err, "type", "read body", "status", response.Status)
 		return sdkerrs.ErrSdkInternal.WrapMsg("io.ReadAll(ApiResponse) failed " + err.Error())
@@ -164,13 +191,18 @@ func ApiPost(ctx context.Context, api string, req, resp any) (err error) {
 	return nil
 }
 
-// CallApi wraps ApiPost to make an API call and unmarshal the response into a new instance of type T.
+// CallApi wraps ApiPost to make an API call and unmarshal the response into a new instance of type T,
+// with the request policy of the class of the api.
 func CallApi[T any](ctx context.Context, api string, req any) (*T, error) {
-	var resp T
-	if err := ApiPost(ctx, api, req, &resp); err != nil {
+	var resp *T
+	err := DoWithPolicy(ctx, RequestClassOf(api), func(ctx context.Context) error {
+		resp = new(T)
+		return ApiPost(ctx, api, req, resp)
+	})
+	if err != nil {
 		return nil, err
 	}
-	return &resp, nil
+	return resp, nil
 }
 
 // GetPageAll handles pagination for API requests. It iterates over pages of data until all data is retrieved.
@@ -227,13 +259,21 @@ func GetPageAllWithMaxNum[A interface {
 
 func FetchAndInsertPagedData[RESP, L any](ctx context.Context, api string, req page.PageReq, fn func(resp *RESP) []L, batchInsertFn func(ctx context.Context, items []L) error,
 	insertFn func(ctx context.Context, item L) error, maxItems int64) error {
+	return FetchAndInsertPagedDataFrom(ctx, api, req, 0, fn, batchInsertFn, insertFn, nil, maxItems)
+}
+
+// FetchAndInsertPagedDataFrom is FetchAndInsertPagedData starting after the skipped pages. pageInserted is
+// called with the items of each page once they are all inserted, it is no longer called after an item failed.
+func FetchAndInsertPagedDataFrom[RESP, L any](ctx context.Context, api string, req page.PageReq, skipPages int32, fn func(resp *RESP) []L,
+	batchInsertFn func(ctx context.Context, items []L) error, insertFn func(ctx context.Context, item L) error,
+	pageInserted func(ctx context.Context, items []L) error, maxItems int64) error {
 	if req.GetPagination().ShowNumber <= 0 {
 		req.GetPagination().ShowNumber = 50
 	}
 	var errSingle error
 	var errList []error
-	totalFetched := 0
-	for i := int32(0); ; i++ {
+	totalFetched := int(skipPages * req.GetPagination().ShowNumber)
+	for i := skipPages; ; i++ {
 		req.GetPagination().PageNumber = i + 1
 		memberResp, err := CallApi[RESP](ctx, api, req)
 		if err != nil {
@@ -248,6 +288,11 @@ func FetchAndInsertPagedData[RESP, L any](ctx context.Context, api string, req p
 				}
 			}
 		}
+		if pageInserted != nil && len(errList) == 0 {
+			if err := pageInserted(ctx, list); err != nil {
+				return err
+			}
+		}
 		totalFetched += len(list)
 		if len(list) < int(req.GetPagination().ShowNumber) || (maxItems > 0 && totalFetched >= int(maxItems)) {
 			break
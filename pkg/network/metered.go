@@ -0,0 +1,108 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package network
+
+import (
+	"sync"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+)
+
+// metered is the network class hinted by the app and the transfers allowed on a metered network.
+var metered = struct {
+	lock    sync.Mutex
+	class   string
+	allowed map[string]bool
+}{class: constant.NetworkClassWifi, allowed: make(map[string]bool)}
+
+func checkMeteredKind(kind string) error {
+	switch kind {
+	case constant.MeteredBackfill, constant.MeteredMediaPrefetch:
+		return nil
+	default:
+		return sdkerrs.ErrArgs.WrapMsg("unknown metered transfer " + kind)
+	}
+}
+
+// CheckAllowOnMetered checks the transfers of SetAllowOnMetered.
+func CheckAllowOnMetered(kinds []string) error {
+	for _, kind := range kinds {
+		if err := checkMeteredKind(kind); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetAllowOnMetered sets the transfers not deferred on a metered network, the others are deferred.
+func SetAllowOnMetered(kinds []string) error {
+	if err := CheckAllowOnMetered(kinds); err != nil {
+		return err
+	}
+	allowed := make(map[string]bool, len(kinds))
+	for _, kind := range kinds {
+		allowed[kind] = true
+	}
+	metered.lock.Lock()
+	metered.allowed = allowed
+	metered.lock.Unlock()
+	return nil
+}
+
+// SetNetworkClass sets the class of the network the device is on, returns true when the deferred transfers
+// may resume as it is no longer metered.
+func SetNetworkClass(class string) (bool, error) {
+	switch class {
+	case constant.NetworkClassWifi, constant.NetworkClassCellular, constant.NetworkClassMetered:
+	default:
+		return false, sdkerrs.ErrArgs.WrapMsg("unknown network class " + class)
+	}
+	metered.lock.Lock()
+	defer metered.lock.Unlock()
+	resumed := isMetered(metered.class) && !isMetered(class)
+	metered.class = class
+	return resumed, nil
+}
+
+func GetNetworkClass() string {
+	metered.lock.Lock()
+	defer metered.lock.Unlock()
+	return metered.class
+}
+
+// AllowMeteredTransfer allows or defers again a transfer on a metered network, returns true when the
+// transfer was deferred and may resume.
+func AllowMeteredTransfer(kind string, allow bool) (bool, error) {
+	if err := checkMeteredKind(kind); err != nil {
+		return false, err
+	}
+	metered.lock.Lock()
+	defer metered.lock.Unlock()
+	resumed := allow && !metered.allowed[kind] && isMetered(metered.class)
+	metered.allowed[kind] = allow
+	return resumed, nil
+}
+
+// Deferred reports whether the transfer must wait, as the network is metered and the transfer not allowed on it.
+func Deferred(kind string) bool {
+	metered.lock.Lock()
+	defer metered.lock.Unlock()
+	return isMetered(metered.class) && !metered.allowed[kind]
+}
+
+func isMetered(class string) bool {
+	return class == constant.NetworkClassCellular || class == constant.NetworkClassMetered
+}
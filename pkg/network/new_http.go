@@ -24,6 +24,7 @@ import (
 	"time"
 	"unsafe"
 
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
 	"github.com/openimsdk/tools/errs"
 )
 
@@ -132,7 +133,7 @@ func (c *HttpCli) ToBytes() (content []byte, err error) {
 	if resp.StatusCode != http.StatusOK {
 		return nil, errs.WrapMsg(errors.New(resp.Status), "status code failed ")
 	}
-	buf, err := ioutil.ReadAll(resp.Body)
+	buf, err := ioutil.ReadAll(ThrottleReader(c.httpRequest.Context(), constant.BandwidthDownload, resp.Body))
 	if err != nil {
 		return nil, errs.WrapMsg(err, "ioutil.ReadAll failed, url")
 	}
@@ -154,7 +155,7 @@ func (c *HttpCli) ToJson(obj interface{}) error {
 	if resp.StatusCode != http.StatusOK {
 		return errs.WrapMsg(errors.New(resp.Status), "status code failed ")
 	}
-	buf, err := ioutil.ReadAll(resp.Body)
+	buf, err := ioutil.ReadAll(ThrottleReader(c.httpRequest.Context(), constant.BandwidthDownload, resp.Body))
 	if err != nil {
 		return errs.WrapMsg(err, "ioutil.ReadAll failed, url")
 	}
@@ -0,0 +1,140 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package network
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/ccontext"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+// ErrPinMismatch is the handshake with a server whose certificate chain has none of the pinned keys.
+var ErrPinMismatch = errors.New("no pinned public key in the server certificate chain")
+
+type pinSet struct {
+	pins  map[[sha256.Size]byte]struct{}
+	hosts map[string]struct{}
+	// expire is when the pins stop being enforced, zero for never
+	expire time.Time
+}
+
+// pinsKey is the key of the pinned keys of the tls connections of a user context, nil pins nothing.
+type pinsKey struct{}
+
+func contextPins(ctx context.Context) *atomic.Pointer[pinSet] {
+	return ccontext.State(ctx, pinsKey{}, func() *atomic.Pointer[pinSet] { return new(atomic.Pointer[pinSet]) })
+}
+
+// SetCertificatePins sets the public keys the certificates of the servers of the user context of ctx must have
+// from the next tls handshake on, nil pins nothing. Idle api connections are closed so that the following
+// requests are checked.
+func SetCertificatePins(ctx context.Context, config *sdk_struct.CertificatePinning) error {
+	set, err := parsePins(config)
+	if err != nil {
+		return err
+	}
+	contextPins(ctx).Store(set)
+	CloseIdleConnections(ctx)
+	return nil
+}
+
+// CheckCertificatePins checks the pins of the config without setting them.
+func CheckCertificatePins(config *sdk_struct.CertificatePinning) error {
+	_, err := parsePins(config)
+	return err
+}
+
+func parsePins(config *sdk_struct.CertificatePinning) (*pinSet, error) {
+	if config == nil || (len(config.Pins) == 0 && len(config.BackupPins) == 0) {
+		return nil, nil
+	}
+	if len(config.Pins) == 0 {
+		return nil, sdkerrs.ErrArgs.WrapMsg("backup pins without pins")
+	}
+	set := &pinSet{pins: make(map[[sha256.Size]byte]struct{})}
+	for _, pin := range append(append([]string{}, config.Pins...), config.BackupPins...) {
+		sum, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(pin, "sha256/"))
+		if err != nil || len(sum) != sha256.Size {
+			return nil, sdkerrs.ErrArgs.WrapMsg("invalid pin " + pin + ", expected the base64 sha256 of a public key")
+		}
+		set.pins[[sha256.Size]byte(sum)] = struct{}{}
+	}
+	if len(config.Hosts) > 0 {
+		set.hosts = make(map[string]struct{}, len(config.Hosts))
+		for _, host := range config.Hosts {
+			set.hosts[strings.ToLower(host)] = struct{}{}
+		}
+	}
+	if config.ExpireTime > 0 {
+		set.expire = time.UnixMilli(config.ExpireTime)
+	}
+	return set, nil
+}
+
+// PublicKeyPin returns the pin of the public key of the certificate, the base64 sha256 of its subject public
+// key info.
+func PublicKeyPin(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return "sha256/" + base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// verifyPins is the VerifyConnection of the tls connections to the servers, it runs after the chain was
+// verified and fails the handshake when no certificate of the chain has a pinned key.
+func verifyPins(set *pinSet, cs tls.ConnectionState) error {
+	if set == nil {
+		return nil
+	}
+	if !set.expire.IsZero() && time.Now().After(set.expire) {
+		return nil
+	}
+	if set.hosts != nil {
+		if _, ok := set.hosts[strings.ToLower(cs.ServerName)]; !ok {
+			return nil
+		}
+	}
+	chains := cs.VerifiedChains
+	if len(chains) == 0 {
+		chains = [][]*x509.Certificate{cs.PeerCertificates}
+	}
+	for _, chain := range chains {
+		for _, cert := range chain {
+			if _, ok := set.pins[sha256.Sum256(cert.RawSubjectPublicKeyInfo)]; ok {
+				return nil
+			}
+		}
+	}
+	log.ZError(context.Background(), "certificate pin mismatch", ErrPinMismatch, "serverName", cs.ServerName)
+	return ErrPinMismatch
+}
+
+// TLSConfig returns the tls config of the connections of the user context of ctx to the servers, checking
+// its pinned keys.
+func TLSConfig(ctx context.Context, serverName string) *tls.Config {
+	pins := contextPins(ctx)
+	return &tls.Config{ServerName: serverName, VerifyConnection: func(cs tls.ConnectionState) error {
+		return verifyPins(pins.Load(), cs)
+	}}
+}
@@ -0,0 +1,56 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/ccontext"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+func TestCertificatePins(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	ctx := ccontext.WithInfo(context.Background(), ccontext.NewGlobalConfig("", "", &sdk_struct.IMConfig{}))
+	get := func(ctx context.Context) error {
+		transport := server.Client().Transport.(*http.Transport).Clone()
+		transport.TLSClientConfig.VerifyConnection = TLSConfig(ctx, "").VerifyConnection
+		resp, err := (&http.Client{Transport: transport}).Get(server.URL)
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+	other := "sha256/AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA="
+	if err := SetCertificatePins(ctx, &sdk_struct.CertificatePinning{Pins: []string{other}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := get(ctx); !errors.Is(err, ErrPinMismatch) {
+		t.Fatal("connected with no pinned key", err)
+	}
+	// the pins of a user context are not checked by the others
+	if err := get(ccontext.WithInfo(context.Background(), ccontext.NewGlobalConfig("", "", &sdk_struct.IMConfig{}))); err != nil {
+		t.Fatal(err)
+	}
+	// the server rotated to the backup key
+	pin := PublicKeyPin(server.Certificate())
+	if err := SetCertificatePins(ctx, &sdk_struct.CertificatePinning{Pins: []string{other}, BackupPins: []string{pin}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := get(ctx); err != nil {
+		t.Fatal(err)
+	}
+	// the pins of other hosts are not checked
+	if err := SetCertificatePins(ctx, &sdk_struct.CertificatePinning{Pins: []string{other}, Hosts: []string{"im.example.com"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := get(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckCertificatePins(&sdk_struct.CertificatePinning{Pins: []string{"sha256/short"}}); err == nil {
+		t.Fatal("invalid pin accepted")
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package network
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+	"github.com/openimsdk/tools/errs"
+)
+
+var defaultRequestPolicies = map[string]sdk_struct.RequestPolicy{
+	constant.RequestClassSend:    {Timeout: 10_000, RetryTimes: 2, RetryInterval: 500},
+	constant.RequestClassQuery:   {Timeout: 10_000, RetryTimes: 1, RetryInterval: 500},
+	constant.RequestClassHistory: {Timeout: 30_000, RetryTimes: 2, RetryInterval: 1000},
+	constant.RequestClassUpload:  {Timeout: 0, RetryTimes: 3, RetryInterval: 1000},
+}
+
+// requestClasses are the apis not in the query class, see DoWithPolicy.
+var requestClasses = map[string]string{
+	"/msg/send_msg":                               constant.RequestClassSend,
+	"/msg/pull_msg_by_seq":                        constant.RequestClassHistory,
+	"/friend/get_incremental_friends":             constant.RequestClassHistory,
+	"/friend/get_full_friend_user_ids":            constant.RequestClassHistory,
+	"/group/get_incremental_join_groups":          constant.RequestClassHistory,
+	"/group/get_incremental_group_members_batch":  constant.RequestClassHistory,
+	"/group/get_full_join_group_ids":              constant.RequestClassHistory,
+	"/group/get_full_group_member_user_ids":       constant.RequestClassHistory,
+	"/conversation/get_incremental_conversations": constant.RequestClassHistory,
+	"/conversation/get_full_conversation_ids":     constant.RequestClassHistory,
+	"/object/part_limit":                          constant.RequestClassUpload,
+	"/object/initiate_multipart_upload":           constant.RequestClassUpload,
+	"/object/auth_sign":                           constant.RequestClassUpload,
+	"/object/complete_multipart_upload":           constant.RequestClassUpload,
+}
+
+// RequestClassOf is the class of the api route.
+func RequestClassOf(api string) string {
+	if class, ok := requestClasses[api]; ok {
+		return class
+	}
+	return constant.RequestClassQuery
+}
+
+var requestPolicies = struct {
+	lock     sync.RWMutex
+	policies map[string]sdk_struct.RequestPolicy
+}{policies: defaultRequestPolicies}
+
+// CheckRequestPolicies checks the classes and the values of the policies of SetRequestPolicies.
+func CheckRequestPolicies(policies map[string]*sdk_struct.RequestPolicy) error {
+	for class, policy := range policies {
+		if _, ok := defaultRequestPolicies[class]; !ok {
+			return sdkerrs.ErrArgs.WrapMsg("unknown request class " + class)
+		}
+		if policy != nil && (policy.Timeout < 0 || policy.RetryTimes < 0 || policy.RetryInterval < 0) {
+			return sdkerrs.ErrArgs.WrapMsg("request policy of " + class + " must not be negative")
+		}
+	}
+	return nil
+}
+
+// SetRequestPolicies replaces the policies of the classes given, the others get their defaults back.
+func SetRequestPolicies(policies map[string]*sdk_struct.RequestPolicy) error {
+	if err := CheckRequestPolicies(policies); err != nil {
+		return err
+	}
+	merged := make(map[string]sdk_struct.RequestPolicy, len(defaultRequestPolicies))
+	for class, policy := range defaultRequestPolicies {
+		merged[class] = policy
+	}
+	for class, policy := range policies {
+		if policy != nil {
+			merged[class] = *policy
+		}
+	}
+	requestPolicies.lock.Lock()
+	requestPolicies.policies = merged
+	requestPolicies.lock.Unlock()
+	return nil
+}
+
+func GetRequestPolicy(class string) sdk_struct.RequestPolicy {
+	requestPolicies.lock.RLock()
+	defer requestPolicies.lock.RUnlock()
+	if policy, ok := requestPolicies.policies[class]; ok {
+		return policy
+	}
+	return requestPolicies.policies[constant.RequestClassQuery]
+}
+
+type requestPolicyKey struct{}
+
+type requestClassKey struct{}
+
+// WithRequestPolicy makes the api calls made with the context use the policy instead of the one of their class.
+func WithRequestPolicy(ctx context.Context, policy sdk_struct.RequestPolicy) context.Context {
+	return context.WithValue(ctx, requestPolicyKey{}, policy)
+}
+
+// WithRequestClass makes the api calls made with the context use the policy of the class instead of their own.
+func WithRequestClass(ctx context.Context, class string) context.Context {
+	return context.WithValue(ctx, requestClassKey{}, class)
+}
+
+func requestPolicy(ctx context.Context, class string) sdk_struct.RequestPolicy {
+	if policy, ok := ctx.Value(requestPolicyKey{}).(sdk_struct.RequestPolicy); ok {
+		return policy
+	}
+	if c, ok := ctx.Value(requestClassKey{}).(string); ok {
+		class = c
+	}
+	return GetRequestPolicy(class)
+}
+
+// RequestTimeout is the context of one attempt of a request of the class.
+func RequestTimeout(ctx context.Context, class string) (context.Context, context.CancelFunc) {
+	policy := requestPolicy(ctx, class)
+	if policy.Timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, time.Duration(policy.Timeout)*time.Millisecond)
+}
+
+// DoWithPolicy calls fn with the timeout of the policy of the class, and again after the network errors as
+// many times as the policy retries.
+func DoWithPolicy(ctx context.Context, class string, fn func(ctx context.Context) error) error {
+	policy := requestPolicy(ctx, class)
+	interval := time.Duration(policy.RetryInterval) * time.Millisecond
+	for attempt := 0; ; attempt++ {
+		err := func() error {
+			attemptCtx, cancel := RequestTimeout(ctx, class)
+			defer cancel()
+			return fn(attemptCtx)
+		}()
+		if err == nil || attempt >= policy.RetryTimes || !isNetworkError(err) || ctx.Err() != nil {
+			return err
+		}
+		if interval > 0 {
+			timer := time.NewTimer(interval)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return err
+			}
+			interval *= 2
+		}
+	}
+}
+
+func isNetworkError(err error) bool {
+	codeErr, ok := errs.Unwrap(err).(errs.CodeError)
+	return ok && codeErr.Code() == sdkerrs.NetworkError
+}
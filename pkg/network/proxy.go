@@ -0,0 +1,116 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package network
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/ccontext"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+// CloseIdleConnections closes the idle api connections of the user context of ctx, the following requests go
+// through its proxy and are checked against its pins once they changed.
+func CloseIdleConnections(ctx context.Context) {
+	contextApiClient(ctx).CloseIdleConnections()
+}
+
+// CheckProxy checks the URLs of the config without switching the proxy.
+func CheckProxy(config *sdk_struct.ProxyConfig) error {
+	if config == nil {
+		return nil
+	}
+	_, _, err := parseProxy(config)
+	return err
+}
+
+func parseProxy(config *sdk_struct.ProxyConfig) (api, ws *url.URL, err error) {
+	if api, err = parseProxyURL(config, config.ApiURL, "http", "https", "socks5"); err != nil {
+		return nil, nil, err
+	}
+	// the websocket dialer only supports http and socks5 proxies
+	if ws, err = parseProxyURL(config, config.WsURL, "http", "socks5"); err != nil {
+		return nil, nil, err
+	}
+	return api, ws, nil
+}
+
+func parseProxyURL(config *sdk_struct.ProxyConfig, schemeURL string, schemes ...string) (*url.URL, error) {
+	rawURL := schemeURL
+	if rawURL == "" {
+		rawURL = config.URL
+	}
+	if rawURL == "" {
+		return nil, nil
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, sdkerrs.ErrArgs.WrapMsg("invalid proxy url " + err.Error())
+	}
+	var supported bool
+	for _, scheme := range schemes {
+		if u.Scheme == scheme {
+			supported = true
+			break
+		}
+	}
+	if !supported || u.Host == "" {
+		return nil, sdkerrs.ErrArgs.WrapMsg("unsupported proxy url " + rawURL)
+	}
+	if config.Username != "" && u.User == nil {
+		u.User = url.UserPassword(config.Username, config.Password)
+	}
+	return u, nil
+}
+
+// ApiProxy returns the proxy for an api request, the one of the user context of the request.
+func ApiProxy(req *http.Request) (*url.URL, error) {
+	if api, _, ok := contextProxies(req.Context()); ok {
+		return api, nil
+	}
+	return http.ProxyFromEnvironment(req)
+}
+
+// WsProxy returns the function picking the proxy for the handshake request of the long connection of the
+// user context of ctx.
+func WsProxy(ctx context.Context) func(req *http.Request) (*url.URL, error) {
+	return func(req *http.Request) (*url.URL, error) {
+		if _, ws, ok := contextProxies(ctx); ok {
+			return ws, nil
+		}
+		return http.ProxyFromEnvironment(req)
+	}
+}
+
+// contextProxies returns the proxies of the user context of ctx, ok is false when the proxy is taken from
+// the environment: the context is of none, or it has no proxy URLs.
+func contextProxies(ctx context.Context) (api, ws *url.URL, ok bool) {
+	if !ccontext.HasInfo(ctx) {
+		return nil, nil, false
+	}
+	config := ccontext.Info(ctx).Proxy()
+	if config == nil {
+		return nil, nil, false
+	}
+	// the config was checked when it was set
+	api, ws, err := parseProxy(config)
+	if err != nil {
+		return nil, nil, false
+	}
+	return api, ws, api != nil || ws != nil
+}
@@ -0,0 +1,46 @@
+package network
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/ccontext"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+// each user context dials through its own proxy
+func TestContextProxy(t *testing.T) {
+	withProxy := func(proxy *sdk_struct.ProxyConfig) context.Context {
+		return ccontext.WithInfo(context.Background(), ccontext.NewGlobalConfig("", "", &sdk_struct.IMConfig{Proxy: proxy}))
+	}
+	a := withProxy(&sdk_struct.ProxyConfig{URL: "http://a:1"})
+	b := withProxy(&sdk_struct.ProxyConfig{ApiURL: "socks5://b:2", WsURL: "http://b:3"})
+	req := func(ctx context.Context) *http.Request {
+		r, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://im.example.com/api", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return r
+	}
+	for _, c := range []struct {
+		ctx     context.Context
+		api, ws string
+	}{
+		{a, "http://a:1", "http://a:1"},
+		{b, "socks5://b:2", "http://b:3"},
+	} {
+		api, err := ApiProxy(req(c.ctx))
+		if err != nil || api.String() != c.api {
+			t.Fatal(api, err)
+		}
+		ws, err := WsProxy(c.ctx)(req(context.Background()))
+		if err != nil || ws.String() != c.ws {
+			t.Fatal(ws, err)
+		}
+	}
+	t.Setenv("HTTPS_PROXY", "")
+	if api, err := ApiProxy(req(context.Background())); err != nil || api != nil {
+		t.Fatal("a request of no user context takes the proxy of the environment", api, err)
+	}
+}
@@ -0,0 +1,230 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package network
+
+import (
+	"context"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/ccontext"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+// rateLimiter is a token bucket of bytes holding at most one second of traffic, a rate of 0 is unlimited.
+type rateLimiter struct {
+	lock   sync.Mutex
+	rate   int64
+	tokens float64
+	last   time.Time
+}
+
+func (l *rateLimiter) setRate(rate int64) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if rate < 0 {
+		rate = 0
+	}
+	l.rate = rate
+	l.tokens = float64(rate)
+	l.last = time.Now()
+}
+
+func (l *rateLimiter) getRate() int64 {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return l.rate
+}
+
+// reserve takes n bytes from the bucket and returns how long to wait before they may be transferred.
+func (l *rateLimiter) reserve(n int) time.Duration {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.rate == 0 {
+		return 0
+	}
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * float64(l.rate)
+	if l.tokens > float64(l.rate) {
+		l.tokens = float64(l.rate)
+	}
+	l.last = now
+	l.tokens -= float64(n)
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / float64(l.rate) * float64(time.Second))
+}
+
+// bandwidthLimiters limit the traffic of a user context.
+type bandwidthLimiters struct {
+	global rateLimiter
+	// categories are the limiters of the categories, the traffic of a category is limited by both its limiter
+	// and the global one
+	categories map[string]*rateLimiter
+}
+
+type bandwidthLimitersKey struct{}
+
+func contextLimiters(ctx context.Context) *bandwidthLimiters {
+	return ccontext.State(ctx, bandwidthLimitersKey{}, func() *bandwidthLimiters {
+		return &bandwidthLimiters{categories: map[string]*rateLimiter{
+			constant.BandwidthSync:     {},
+			constant.BandwidthUpload:   {},
+			constant.BandwidthDownload: {},
+		}}
+	})
+}
+
+// CheckBandwidthLimit checks the limits of SetBandwidthLimit.
+func CheckBandwidthLimit(limit *sdk_struct.BandwidthLimit) error {
+	if limit != nil && (limit.Global < 0 || limit.Sync < 0 || limit.Upload < 0 || limit.Download < 0) {
+		return sdkerrs.ErrArgs.WrapMsg("bandwidth limit must not be negative")
+	}
+	return nil
+}
+
+// SetBandwidthLimit sets the bytes per second the user context of ctx may transfer, 0 means unlimited. The
+// limits apply right away to the transfers in progress.
+func SetBandwidthLimit(ctx context.Context, limit *sdk_struct.BandwidthLimit) error {
+	if err := CheckBandwidthLimit(limit); err != nil {
+		return err
+	}
+	if limit == nil {
+		limit = &sdk_struct.BandwidthLimit{}
+	}
+	limiters := contextLimiters(ctx)
+	limiters.global.setRate(limit.Global)
+	limiters.categories[constant.BandwidthSync].setRate(limit.Sync)
+	limiters.categories[constant.BandwidthUpload].setRate(limit.Upload)
+	limiters.categories[constant.BandwidthDownload].setRate(limit.Download)
+	return nil
+}
+
+func GetBandwidthLimit(ctx context.Context) *sdk_struct.BandwidthLimit {
+	limiters := contextLimiters(ctx)
+	return &sdk_struct.BandwidthLimit{
+		Global:   limiters.global.getRate(),
+		Sync:     limiters.categories[constant.BandwidthSync].getRate(),
+		Upload:   limiters.categories[constant.BandwidthUpload].getRate(),
+		Download: limiters.categories[constant.BandwidthDownload].getRate(),
+	}
+}
+
+// throttle waits until n bytes of the category may be transferred by the user context of ctx.
+func throttle(ctx context.Context, category string, n int) error {
+	limiters := contextLimiters(ctx)
+	wait := limiters.global.reserve(n)
+	if limiter := limiters.categories[category]; limiter != nil {
+		if w := limiter.reserve(n); w > wait {
+			wait = w
+		}
+	}
+	if wait <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	}
+}
+
+// throttleChunk is the most bytes a throttled reader or connection transfers at once, so that a limited
+// transfer progresses smoothly instead of in bursts of a whole buffer.
+const throttleChunk = 16 * 1024
+
+type throttledReader struct {
+	ctx      context.Context
+	category string
+	r        io.Reader
+}
+
+// ThrottleReader limits the speed the reader is read at to the bandwidth of the category.
+func ThrottleReader(ctx context.Context, category string, r io.Reader) io.Reader {
+	return &throttledReader{ctx: ctx, category: category, r: r}
+}
+
+func (t *throttledReader) Read(p []byte) (int, error) {
+	if len(p) > throttleChunk {
+		p = p[:throttleChunk]
+	}
+	n, err := t.r.Read(p)
+	if n > 0 {
+		if werr := throttle(t.ctx, t.category, n); werr != nil && err == nil {
+			err = werr
+		}
+	}
+	return n, err
+}
+
+type throttledReadCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// ThrottleReadCloser is ThrottleReader keeping the Close of the reader, for request and response bodies.
+func ThrottleReadCloser(ctx context.Context, category string, r io.ReadCloser) io.ReadCloser {
+	return throttledReadCloser{Reader: ThrottleReader(ctx, category, r), Closer: r}
+}
+
+type throttledConn struct {
+	net.Conn
+	// ctx is of the user context of the connection, it is not canceled with the connection
+	ctx      context.Context
+	category string
+}
+
+// ThrottleConn limits both directions of the connection to the bandwidth of the category of the user context
+// of ctx.
+func ThrottleConn(ctx context.Context, conn net.Conn, category string) net.Conn {
+	return &throttledConn{Conn: conn, ctx: context.WithoutCancel(ctx), category: category}
+}
+
+func (c *throttledConn) Read(b []byte) (int, error) {
+	if len(b) > throttleChunk {
+		b = b[:throttleChunk]
+	}
+	n, err := c.Conn.Read(b)
+	if n > 0 {
+		_ = throttle(c.ctx, c.category, n)
+	}
+	return n, err
+}
+
+func (c *throttledConn) Write(b []byte) (int, error) {
+	var written int
+	for len(b) > 0 {
+		chunk := b
+		if len(chunk) > throttleChunk {
+			chunk = chunk[:throttleChunk]
+		}
+		_ = throttle(c.ctx, c.category, len(chunk))
+		n, err := c.Conn.Write(chunk)
+		written += n
+		if err != nil {
+			return written, err
+		}
+		b = b[n:]
+	}
+	return written, nil
+}
@@ -0,0 +1,25 @@
+package network
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateLimiterReserve(t *testing.T) {
+	var l rateLimiter
+	if wait := l.reserve(1 << 20); wait != 0 {
+		t.Fatalf("unlimited limiter waits %s", wait)
+	}
+	l.setRate(1000)
+	if wait := l.reserve(1000); wait != 0 {
+		t.Fatalf("a full bucket waits %s", wait)
+	}
+	wait := l.reserve(500)
+	if wait < 400*time.Millisecond || wait > 500*time.Millisecond {
+		t.Fatalf("500 bytes over the bucket at 1000 B/s wait %s", wait)
+	}
+	l.setRate(0)
+	if wait := l.reserve(1 << 20); wait != 0 {
+		t.Fatalf("limiter waits %s after being unlimited", wait)
+	}
+}
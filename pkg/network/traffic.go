@@ -0,0 +1,76 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package network
+
+import (
+	"io"
+	"sync/atomic"
+
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+// trafficCounter counts the payload bytes before compression and the bytes actually transferred, and the
+// bytes compression saved on the payloads actually compressed.
+type trafficCounter struct {
+	raw   atomic.Int64
+	wire  atomic.Int64
+	saved atomic.Int64
+}
+
+var (
+	wsTraffic  trafficCounter
+	apiTraffic trafficCounter
+)
+
+// AddWsRawBytes counts long connection payload bytes before compression.
+func AddWsRawBytes(n int) {
+	wsTraffic.raw.Add(int64(n))
+}
+
+// AddWsWireBytes counts bytes the long connection transferred.
+func AddWsWireBytes(n int) {
+	wsTraffic.wire.Add(int64(n))
+}
+
+// AddWsCompressed counts a long connection payload compressed from raw to compressed bytes, the payloads sent
+// as they are do not count in the bytes saved.
+func AddWsCompressed(raw, compressed int) {
+	wsTraffic.saved.Add(int64(raw - compressed))
+}
+
+// GetTrafficStats returns the traffic of the process since start. The bytes saved only count the payloads
+// actually compressed, the framing, the encryption and the payloads sent as they are are left out.
+func GetTrafficStats() *sdk_struct.TrafficStats {
+	stats := &sdk_struct.TrafficStats{
+		WsRawBytes:   wsTraffic.raw.Load(),
+		WsWireBytes:  wsTraffic.wire.Load(),
+		ApiRawBytes:  apiTraffic.raw.Load(),
+		ApiWireBytes: apiTraffic.wire.Load(),
+	}
+	stats.BytesSaved = wsTraffic.saved.Load() + apiTraffic.saved.Load()
+	return stats
+}
+
+// countingReader counts the bytes read from the response body as it was sent by the server.
+type countingReader struct {
+	io.ReadCloser
+	n int
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.n += n
+	return n, err
+}
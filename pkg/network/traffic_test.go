@@ -0,0 +1,45 @@
+package network
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/ccontext"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+// only the responses actually compressed count in the bytes saved
+func TestBytesSaved(t *testing.T) {
+	body := []byte(`{"errCode":0,"errMsg":"","data":{"text":"` + string(bytes.Repeat([]byte("a"), 4096)) + `"}}`)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/gzip" {
+			w.Header().Set("Content-Encoding", "gzip")
+			zw := gzip.NewWriter(w)
+			_, _ = zw.Write(body)
+			_ = zw.Close()
+			return
+		}
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+	ctx := ccontext.WithInfo(context.Background(), ccontext.NewGlobalConfig("", "", &sdk_struct.IMConfig{ApiAddr: server.URL}))
+	ctx = ccontext.WithOperationID(ctx, "123456")
+	post := func(path string) int64 {
+		before := GetTrafficStats().BytesSaved
+		var resp any
+		if err := ApiPost(ctx, path, map[string]any{}, &resp); err != nil {
+			t.Fatal(err)
+		}
+		return GetTrafficStats().BytesSaved - before
+	}
+	if saved := post("/plain"); saved != 0 {
+		t.Fatal("a response not compressed saves", saved)
+	}
+	if saved := post("/gzip"); saved <= 0 || saved >= int64(len(body)) {
+		t.Fatal("a compressed response saves", saved)
+	}
+}
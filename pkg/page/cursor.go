@@ -0,0 +1,66 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package page
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+)
+
+// The lists a cursor may be handed back to, a cursor of one list is refused by the others.
+const (
+	CursorConversations = "c"
+	CursorMessages      = "m"
+	CursorFriends       = "f"
+	CursorGroupMembers  = "g"
+	CursorCallRecords   = "r"
+	CursorMoments       = "o"
+)
+
+// Cursor is the position after the last item of a page. It anchors on the item itself rather
+// than on an offset so that inserts and deletes ahead of it do not shift the next page, and
+// keeps the sort keys and the index of the item to fall back on once the item is gone.
+type Cursor struct {
+	List  string `json:"l"`
+	ID    string `json:"id,omitempty"`
+	Index int    `json:"i,omitempty"`
+	Time  int64  `json:"t,omitempty"`
+	Seq   int64  `json:"s,omitempty"`
+	Rank  int32  `json:"r,omitempty"`
+}
+
+// Encode returns the opaque token of the cursor.
+func (c *Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses a token of the list, the empty token being the start of the list.
+func DecodeCursor(list, token string) (*Cursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, sdkerrs.ErrArgs.WrapMsg("invalid cursor")
+	}
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.List != list {
+		return nil, sdkerrs.ErrArgs.WrapMsg("invalid cursor")
+	}
+	return &c, nil
+}
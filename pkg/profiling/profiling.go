@@ -0,0 +1,233 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package profiling writes the pprof profiles of the SDK to its data dir and samples the stats of the Go
+// runtime, so that the SDK can be profiled inside the apps without a special build.
+package profiling
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/tools/errs"
+	"github.com/openimsdk/tools/utils/datautil"
+)
+
+// The profiles Start takes. The cpu profile covers the time until Stop, the heap and the goroutine profiles
+// are taken when it stops.
+const (
+	ProfileCPU       = "cpu"
+	ProfileHeap      = "heap"
+	ProfileGoroutine = "goroutine"
+)
+
+const (
+	// MaxDuration stops a profiling left running, the cpu profile slows the SDK down
+	MaxDuration = 10 * time.Minute
+	// maxProfiles is the number of the profile files kept, the oldest are removed beyond it
+	maxProfiles   = 20
+	profileSuffix = ".pprof"
+)
+
+var (
+	lock    sync.Mutex
+	dir     string
+	current *session
+	// stopped is the session stopped by MaxDuration, Stop returns its files
+	stopped *session
+)
+
+type session struct {
+	profiles []string
+	start    time.Time
+	cpuFile  *os.File
+	timer    *time.Timer
+	files    []string
+	err      error
+}
+
+// SetDir sets the directory the profiles are written to, profiling fails while it is empty.
+func SetDir(profileDir string) {
+	lock.Lock()
+	defer lock.Unlock()
+	dir = profileDir
+}
+
+// Start starts to profile, all the profiles when none is given. Only one profiling runs at a time.
+func Start(ctx context.Context, profiles []string) error {
+	if len(profiles) == 0 {
+		profiles = []string{ProfileCPU, ProfileHeap, ProfileGoroutine}
+	}
+	profiles = datautil.Distinct(profiles)
+	for _, p := range profiles {
+		switch p {
+		case ProfileCPU, ProfileHeap, ProfileGoroutine:
+		default:
+			return sdkerrs.ErrArgs.WrapMsg("unknown profile " + p)
+		}
+	}
+	lock.Lock()
+	defer lock.Unlock()
+	if dir == "" {
+		return sdkerrs.ErrArgs.WrapMsg("the profile dir is not set, call InitSDK first")
+	}
+	if current != nil {
+		return sdkerrs.ErrArgs.WrapMsg("profiling is running")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return errs.WrapMsg(err, "create profile dir failed")
+	}
+	s := &session{profiles: profiles, start: time.Now()}
+	if datautil.Contain(ProfileCPU, profiles...) {
+		f, err := os.Create(s.path(ProfileCPU))
+		if err != nil {
+			return errs.WrapMsg(err, "create cpu profile failed")
+		}
+		if err := pprof.StartCPUProfile(f); err != nil {
+			_ = f.Close()
+			_ = os.Remove(f.Name())
+			return errs.WrapMsg(err, "start cpu profile failed")
+		}
+		s.cpuFile = f
+	}
+	s.timer = time.AfterFunc(MaxDuration, func() {
+		lock.Lock()
+		defer lock.Unlock()
+		if current != s {
+			return
+		}
+		log.ZWarn(ctx, "profiling stopped after the max duration", nil, "maxDuration", MaxDuration)
+		s.stop()
+		current, stopped = nil, s
+	})
+	current, stopped = s, nil
+	log.ZInfo(ctx, "profiling started", "profiles", profiles, "dir", dir)
+	return nil
+}
+
+// Stop stops the profiling and returns the files written, or the ones of the profiling stopped after
+// MaxDuration.
+func Stop(ctx context.Context) ([]string, error) {
+	lock.Lock()
+	defer lock.Unlock()
+	s := current
+	if s == nil {
+		s = stopped
+		if s == nil {
+			return nil, sdkerrs.ErrArgs.WrapMsg("profiling is not running")
+		}
+	} else {
+		s.timer.Stop()
+		s.stop()
+	}
+	current, stopped = nil, nil
+	log.ZInfo(ctx, "profiling stopped", "files", s.files, "duration", time.Since(s.start), "err", s.err)
+	return s.files, s.err
+}
+
+// Running returns the profiles being taken.
+func Running() []string {
+	lock.Lock()
+	defer lock.Unlock()
+	if current == nil {
+		return nil
+	}
+	return append([]string(nil), current.profiles...)
+}
+
+// stop writes the profiles, the lock is held.
+func (s *session) stop() {
+	if s.cpuFile != nil {
+		pprof.StopCPUProfile()
+		if err := s.cpuFile.Close(); err != nil {
+			s.err = errs.WrapMsg(err, "close cpu profile failed")
+		} else {
+			s.files = append(s.files, s.cpuFile.Name())
+		}
+	}
+	for _, p := range s.profiles {
+		if p == ProfileCPU {
+			continue
+		}
+		if p == ProfileHeap {
+			// the heap profile is as of the last gc
+			runtime.GC()
+		}
+		path := s.path(p)
+		if err := writeProfile(p, path); err != nil {
+			s.err = errs.WrapMsg(err, "write "+p+" profile failed")
+			continue
+		}
+		s.files = append(s.files, path)
+	}
+	removeOldProfiles()
+}
+
+func (s *session) path(profile string) string {
+	return filepath.Join(dir, profile+"_"+s.start.Format("20060102_150405")+profileSuffix)
+}
+
+func writeProfile(profile, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := pprof.Lookup(profile).WriteTo(f, 0); err != nil {
+		_ = f.Close()
+		_ = os.Remove(path)
+		return err
+	}
+	return f.Close()
+}
+
+// removeOldProfiles removes the oldest profiles beyond maxProfiles, the lock is held.
+func removeOldProfiles() {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	var files []os.DirEntry
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), profileSuffix) {
+			files = append(files, entry)
+		}
+	}
+	if len(files) <= maxProfiles {
+		return
+	}
+	// the names end with the time the profiling started, the kinds are before it
+	sort.Slice(files, func(i, j int) bool {
+		return profileTime(files[i].Name()) < profileTime(files[j].Name())
+	})
+	for _, entry := range files[:len(files)-maxProfiles] {
+		_ = os.Remove(filepath.Join(dir, entry.Name()))
+	}
+}
+
+func profileTime(name string) string {
+	name = strings.TrimSuffix(name, profileSuffix)
+	if i := strings.IndexByte(name, '_'); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
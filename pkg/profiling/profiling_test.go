@@ -0,0 +1,47 @@
+package profiling
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestProfiling(t *testing.T) {
+	ctx := context.Background()
+	if err := Start(ctx, nil); err == nil {
+		t.Fatal("started without a dir")
+	}
+	SetDir(t.TempDir())
+	defer SetDir("")
+	if err := Start(ctx, []string{"threadcreate"}); err == nil {
+		t.Fatal("unknown profile accepted")
+	}
+	if err := Start(ctx, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := Start(ctx, nil); err == nil {
+		t.Fatal("started twice")
+	}
+	if running := Running(); len(running) != 3 {
+		t.Fatalf("running %v", running)
+	}
+	files, err := Stop(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 3 {
+		t.Fatalf("files %v", files)
+	}
+	for _, file := range files {
+		if stat, err := os.Stat(file); err != nil || stat.Size() == 0 {
+			t.Fatalf("profile %s: %v", filepath.Base(file), err)
+		}
+	}
+	if _, err := Stop(ctx); err == nil {
+		t.Fatal("stopped twice")
+	}
+	if s := Stats(); s.Current == nil || s.Current.Goroutines == 0 || len(s.Profiling) != 0 {
+		t.Fatalf("stats %+v", s)
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package profiling
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+// maxSamples is the number of the samples of the runtime kept, the oldest are dropped beyond it
+const maxSamples = 60
+
+var (
+	statsLock   sync.Mutex
+	samples     []*sdk_struct.RuntimeStats
+	statsCancel context.CancelFunc
+)
+
+// StartStats samples the stats of the runtime every interval and stops the previous poller, a zero interval
+// only stops it. A sample stops the world for a moment, the interval should be seconds or more.
+func StartStats(interval time.Duration) {
+	statsLock.Lock()
+	defer statsLock.Unlock()
+	if statsCancel != nil {
+		statsCancel()
+		statsCancel = nil
+	}
+	samples = nil
+	if interval <= 0 {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	statsCancel = cancel
+	go poll(ctx, interval)
+}
+
+func poll(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s := Sample()
+			statsLock.Lock()
+			if ctx.Err() == nil {
+				samples = append(samples, s)
+				if len(samples) > maxSamples {
+					samples = samples[len(samples)-maxSamples:]
+				}
+			}
+			statsLock.Unlock()
+		}
+	}
+}
+
+// Sample reads the stats of the runtime now.
+func Sample() *sdk_struct.RuntimeStats {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	s := &sdk_struct.RuntimeStats{
+		Time:         time.Now().UnixMilli(),
+		Goroutines:   runtime.NumGoroutine(),
+		HeapAlloc:    m.HeapAlloc,
+		HeapInuse:    m.HeapInuse,
+		HeapObjects:  m.HeapObjects,
+		Sys:          m.Sys,
+		NumGC:        m.NumGC,
+		GCPauseTotal: time.Duration(m.PauseTotalNs).Milliseconds(),
+		CgoCalls:     runtime.NumCgoCall(),
+	}
+	if m.NumGC > 0 {
+		s.LastGCPause = time.Duration(m.PauseNs[(m.NumGC+255)%256]).Microseconds()
+	}
+	return s
+}
+
+// Stats returns a sample taken now, the samples of the poller and the profiles being taken.
+func Stats() *sdk_struct.RuntimeDiagnostics {
+	d := &sdk_struct.RuntimeDiagnostics{Current: Sample(), Profiling: Running()}
+	statsLock.Lock()
+	d.History = append([]*sdk_struct.RuntimeStats(nil), samples...)
+	statsLock.Unlock()
+	return d
+}
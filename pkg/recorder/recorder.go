@@ -0,0 +1,219 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package recorder records what the long connection receives and what the sync pipeline dispatches to the
+// conversations into a file, which the replay of open_im_sdk feeds back through the pipeline to reproduce the
+// bugs of the sync locally.
+package recorder
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/tools/errs"
+)
+
+// FormatVersion is the version of the format of the recordings, a replay reads its own version only
+const FormatVersion = 1
+
+// The kinds of the events.
+const (
+	// KindLogin is a login, UserID tells the user a replay logs in as
+	KindLogin = "login"
+	// KindConnected is a connection of the long connection
+	KindConnected = "connected"
+	// KindFrame is a frame pushed by the server, e.g. the new messages
+	KindFrame = "frame"
+	// KindExchange is a request of the SDK on the long connection and its response
+	KindExchange = "exchange"
+	// KindDispatch is the messages the sync dispatched to the conversations, Cmd tells the command
+	KindDispatch = "dispatch"
+)
+
+// Header is the first line of a recording.
+type Header struct {
+	Version    int    `json:"version"`
+	UserID     string `json:"userID"`
+	PlatformID int32  `json:"platformID"`
+	SDKVersion string `json:"sdkVersion"`
+	// StartTime is in milliseconds
+	StartTime int64 `json:"startTime"`
+}
+
+// Event is a line of a recording after the header.
+type Event struct {
+	// Time is the milliseconds since the recording started
+	Time          int64  `json:"time"`
+	Kind          string `json:"kind"`
+	UserID        string `json:"userID,omitempty"`
+	ReqIdentifier int    `json:"reqIdentifier,omitempty"`
+	OperationID   string `json:"operationID,omitempty"`
+	// ReqKey is the hash of the data of the request of an exchange, a replay answers the same request with the
+	// recorded response
+	ReqKey  string `json:"reqKey,omitempty"`
+	ErrCode int    `json:"errCode,omitempty"`
+	ErrMsg  string `json:"errMsg,omitempty"`
+	// Cmd is the command of a dispatch
+	Cmd string `json:"cmd,omitempty"`
+	// Data is the data of a frame, the response of an exchange or the sdkws.PushMessages of a dispatch
+	Data []byte `json:"data,omitempty"`
+}
+
+type Recorder struct {
+	path  string
+	start time.Time
+	lock  sync.Mutex
+	// file is not buffered, the events before a crash are kept
+	file  *os.File
+	count int
+	err   error
+}
+
+var current atomic.Pointer[Recorder]
+
+// Enabled reports whether a recording is running, the callers skip building the events when it is not.
+func Enabled() bool {
+	return current.Load() != nil
+}
+
+// Start starts to record to the file of the path, only one recording runs at a time.
+func Start(path string, header *Header) error {
+	if current.Load() != nil {
+		return sdkerrs.ErrArgs.WrapMsg("a recording is running")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return errs.WrapMsg(err, "create recording dir failed")
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return errs.WrapMsg(err, "create recording failed")
+	}
+	r := &Recorder{path: path, start: time.Now(), file: file}
+	h := *header
+	h.Version, h.StartTime = FormatVersion, r.start.UnixMilli()
+	if err := r.writeLine(&h); err != nil {
+		_ = file.Close()
+		return errs.WrapMsg(err, "write recording header failed")
+	}
+	if !current.CompareAndSwap(nil, r) {
+		_ = file.Close()
+		_ = os.Remove(path)
+		return sdkerrs.ErrArgs.WrapMsg("a recording is running")
+	}
+	return nil
+}
+
+// Stop stops the recording and returns its path and the number of the events recorded.
+func Stop() (string, int, error) {
+	r := current.Swap(nil)
+	if r == nil {
+		return "", 0, sdkerrs.ErrArgs.WrapMsg("no recording is running")
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	err := r.err
+	if e := r.file.Close(); err == nil && e != nil {
+		err = errs.WrapMsg(e, "close recording failed")
+	}
+	return r.path, r.count, err
+}
+
+// Record records an event, its time is set. The first error stops the recording of the events after it.
+func Record(e *Event) {
+	r := current.Load()
+	if r == nil {
+		return
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.err != nil {
+		return
+	}
+	e.Time = time.Since(r.start).Milliseconds()
+	if r.err = r.writeLine(e); r.err == nil {
+		r.count++
+	}
+}
+
+// RecordExchange records a request of the long connection and its response.
+func RecordExchange(reqIdentifier int, req []byte, operationID string, errCode int, errMsg string, resp []byte) {
+	if !Enabled() {
+		return
+	}
+	Record(&Event{Kind: KindExchange, ReqIdentifier: reqIdentifier, OperationID: operationID, ReqKey: ReqKey(req),
+		ErrCode: errCode, ErrMsg: errMsg, Data: resp})
+}
+
+// ReqKey is the key of the data of a request.
+func ReqKey(req []byte) string {
+	sum := sha256.Sum256(req)
+	return hex.EncodeToString(sum[:16])
+}
+
+func (r *Recorder) writeLine(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = r.file.Write(append(data, '\n'))
+	return err
+}
+
+// Read reads a recording.
+func Read(path string) (*Header, []*Event, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, errs.WrapMsg(err, "open recording failed")
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	// a frame holds a batch of messages
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	var (
+		header *Header
+		events []*Event
+	)
+	for scanner.Scan() {
+		if header == nil {
+			header = &Header{}
+			if err := json.Unmarshal(scanner.Bytes(), header); err != nil {
+				return nil, nil, errs.WrapMsg(err, "invalid recording header")
+			}
+			if header.Version != FormatVersion {
+				return nil, nil, sdkerrs.ErrArgs.WrapMsg("unsupported recording version")
+			}
+			continue
+		}
+		var e Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, nil, errs.WrapMsg(err, "invalid recording event")
+		}
+		events = append(events, &e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, errs.WrapMsg(err, "read recording failed")
+	}
+	if header == nil {
+		return nil, nil, sdkerrs.ErrArgs.WrapMsg("empty recording")
+	}
+	return header, events, nil
+}
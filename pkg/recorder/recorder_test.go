@@ -0,0 +1,44 @@
+package recorder
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestRecorder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "recording.jsonl")
+	Record(&Event{Kind: KindConnected})
+	if err := Start(path, &Header{UserID: "u1", PlatformID: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Start(path, &Header{}); err == nil {
+		t.Fatal("started twice")
+	}
+	Record(&Event{Kind: KindConnected})
+	RecordExchange(1001, []byte("req"), "op1", 0, "", []byte("resp"))
+	Record(&Event{Kind: KindFrame, ReqIdentifier: 2001, Data: []byte{0, 1, 2}})
+	got, count, err := Stop()
+	if err != nil || got != path || count != 3 {
+		t.Fatalf("stop %s %d %v", got, count, err)
+	}
+	if Enabled() {
+		t.Fatal("enabled after stop")
+	}
+
+	header, events, err := Read(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header.Version != FormatVersion || header.UserID != "u1" || header.PlatformID != 2 || header.StartTime == 0 {
+		t.Fatalf("header %+v", header)
+	}
+	if len(events) != 3 {
+		t.Fatalf("%d events", len(events))
+	}
+	if e := events[1]; e.Kind != KindExchange || e.ReqIdentifier != 1001 || e.ReqKey != ReqKey([]byte("req")) || string(e.Data) != "resp" {
+		t.Fatalf("exchange %+v", e)
+	}
+	if e := events[2]; e.Kind != KindFrame || string(e.Data) != string([]byte{0, 1, 2}) {
+		t.Fatalf("frame %+v", e)
+	}
+}
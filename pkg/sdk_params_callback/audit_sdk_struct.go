@@ -0,0 +1,25 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sdk_params_callback
+
+type GetAuditLogsParams struct {
+	// Actions are the actions of the logs, see the Action* of pkg/audit, any action when empty
+	Actions []string `json:"actions"`
+	// StartTime and EndTime are in milliseconds, no end for a 0 EndTime
+	StartTime int64 `json:"startTime"`
+	EndTime   int64 `json:"endTime"`
+	Offset    int   `json:"offset"`
+	Count     int   `json:"count"`
+}
@@ -0,0 +1,30 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sdk_params_callback
+
+import (
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+)
+
+// BatchFailure is an ID of a batched query which could not be answered, the others are still returned.
+type BatchFailure struct {
+	ID      string `json:"id"`
+	ErrCode int32  `json:"errCode"`
+	ErrMsg  string `json:"errMsg"`
+}
+
+func NewBatchFailure(id string, err error) *BatchFailure {
+	return &BatchFailure{ID: id, ErrCode: sdkerrs.Code(err), ErrMsg: err.Error()}
+}
@@ -15,6 +15,7 @@
 package sdk_params_callback
 
 import (
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
 	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
 )
 
@@ -28,6 +29,46 @@ type FindMessageListCallback struct {
 	FindResultItems []*SearchByConversationResult `json:"findResultItems"`
 }
 
+type GetConversationsCallback struct {
+	ConversationList []*model_struct.LocalConversation `json:"conversationList"`
+	FailedList       []*BatchFailure                   `json:"failedList"`
+}
+
+type GetMessagesCallback struct {
+	MessageList []*sdk_struct.MsgStruct `json:"messageList"`
+	FailedList  []*BatchFailure         `json:"failedList"`
+}
+
+// The cursor pages hand back NextCursor to fetch the page following them, HasMore being false on the last page.
+type GetConversationListByCursorCallback struct {
+	ConversationList []*model_struct.LocalConversation `json:"conversationList"`
+	NextCursor       string                            `json:"nextCursor"`
+	HasMore          bool                              `json:"hasMore"`
+}
+
+type GetMessageListByCursorCallback struct {
+	MessageList []*sdk_struct.MsgStruct `json:"messageList"`
+	NextCursor  string                  `json:"nextCursor"`
+	HasMore     bool                    `json:"hasMore"`
+}
+
+type GetCallRecordsCallback struct {
+	CallRecords []*model_struct.LocalCallRecord `json:"callRecords"`
+	NextCursor  string                          `json:"nextCursor"`
+	HasMore     bool                            `json:"hasMore"`
+}
+
+// CallRecordFilter selects the call records, an empty field selects them all.
+type CallRecordFilter struct {
+	// UserID is the other user of the single calls
+	UserID    string   `json:"userID"`
+	GroupID   string   `json:"groupID"`
+	MediaType string   `json:"mediaType"`
+	States    []string `json:"states"`
+	// Missed selects the missed calls only
+	Missed bool `json:"missed"`
+}
+
 type GetAdvancedHistoryMessageListParams struct {
 	ConversationID   string `json:"conversationID"`
 	StartClientMsgID string `json:"startClientMsgID"`
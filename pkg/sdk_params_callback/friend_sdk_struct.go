@@ -59,3 +59,9 @@ type GetFriendApplicationListAsApplicantReq struct {
 type GetSelfUnhandledApplyCountReq struct {
 	Time int64 `json:"time"`
 }
+
+type GetFriendListByCursorCallback struct {
+	FriendList []*model_struct.LocalFriend `json:"friendList"`
+	NextCursor string                      `json:"nextCursor"`
+	HasMore    bool                        `json:"hasMore"`
+}
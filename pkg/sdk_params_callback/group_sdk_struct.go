@@ -14,12 +14,21 @@
 
 package sdk_params_callback
 
+import (
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+)
+
 type SearchGroupsParam struct {
 	KeywordList       []string `json:"keywordList"`
 	IsSearchGroupID   bool     `json:"isSearchGroupID"`
 	IsSearchGroupName bool     `json:"isSearchGroupName"`
 }
 
+type GetGroupsInfoCallback struct {
+	GroupList  []*model_struct.LocalGroup `json:"groupList"`
+	FailedList []*BatchFailure            `json:"failedList"`
+}
+
 type SearchGroupMembersParam struct {
 	GroupID                string   `json:"groupID"`
 	KeywordList            []string `json:"keywordList"`
@@ -47,3 +56,9 @@ type GetGroupApplicationListAsApplicantReq struct {
 type GetGroupApplicationUnhandledCountReq struct {
 	Time int64 `json:"time"`
 }
+
+type GetGroupMemberListByCursorCallback struct {
+	GroupMemberList []*model_struct.LocalGroupMember `json:"groupMemberList"`
+	NextCursor      string                           `json:"nextCursor"`
+	HasMore         bool                             `json:"hasMore"`
+}
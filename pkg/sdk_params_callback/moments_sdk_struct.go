@@ -0,0 +1,43 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sdk_params_callback
+
+import "github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+
+// PublishMomentMedia is a media of the moment to publish, the local files are uploaded first.
+type PublishMomentMedia struct {
+	model_struct.MomentMedia
+	// FilePath is the local file of the media, the URL is taken as is when it is empty
+	FilePath string `json:"filePath"`
+	// ThumbnailPath is the local file of the thumbnail of a video, if any
+	ThumbnailPath string `json:"thumbnailPath"`
+}
+
+type PublishMomentParams struct {
+	Content string                `json:"content"`
+	Medias  []*PublishMomentMedia `json:"medias"`
+	// Visibility is who sees the moment besides the login user, the friends by default
+	Visibility     int32    `json:"visibility"`
+	VisibleUserIDs []string `json:"visibleUserIDs"`
+	Ex             string   `json:"ex"`
+}
+
+type GetMomentsCallback struct {
+	Moments    []*model_struct.LocalMoment `json:"moments"`
+	NextCursor string                      `json:"nextCursor"`
+	HasMore    bool                        `json:"hasMore"`
+	// Cached is a page of the local cache, the server could not be reached
+	Cached bool `json:"cached"`
+}
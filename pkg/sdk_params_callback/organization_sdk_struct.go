@@ -0,0 +1,52 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sdk_params_callback
+
+import "github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+
+// DepartmentNode is a department with its members and its sub departments, down to the depth asked.
+type DepartmentNode struct {
+	*model_struct.LocalDepartment
+	Members        []*model_struct.LocalDepartmentMember `json:"members"`
+	SubDepartments []*DepartmentNode                     `json:"subDepartments"`
+}
+
+type GetSubDepartmentsCallback struct {
+	Departments []*model_struct.LocalDepartment       `json:"departments"`
+	Members     []*model_struct.LocalDepartmentMember `json:"members"`
+}
+
+// UserInDepartment is the user in one of the departments the user is in, Path is the departments from the
+// top department down to it.
+type UserInDepartment struct {
+	Member     *model_struct.LocalDepartmentMember `json:"member"`
+	Department *model_struct.LocalDepartment       `json:"department"`
+	Path       []*model_struct.LocalDepartment     `json:"path"`
+}
+
+type SearchOrganizationParams struct {
+	Keyword          string `json:"keyword"`
+	IsSearchNickname bool   `json:"isSearchNickname"`
+	IsSearchPosition bool   `json:"isSearchPosition"`
+	// IsSearchDepartment searches the departments by name, their members are returned too
+	IsSearchDepartment bool `json:"isSearchDepartment"`
+	// Count is the members returned at most, 0 for all of them
+	Count int `json:"count"`
+}
+
+type SearchOrganizationCallback struct {
+	Departments []*model_struct.LocalDepartment       `json:"departments"`
+	Members     []*model_struct.LocalDepartmentMember `json:"members"`
+}
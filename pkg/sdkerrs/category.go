@@ -0,0 +1,140 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sdkerrs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"net"
+	"strconv"
+
+	"github.com/openimsdk/tools/errs"
+)
+
+// Categories group the error codes so the app can decide how to react
+// without knowing every code.
+const (
+	CategoryNetwork    = "network"    // the server could not be reached, retry later
+	CategoryAuth       = "auth"       // the token or the login state is not valid, login again
+	CategoryPermission = "permission" // the user is not allowed to do this
+	CategoryValidation = "validation" // the arguments or the local state do not allow the call
+	CategoryStorage    = "storage"    // the local database or file system failed
+	CategoryCanceled   = "canceled"   // the caller canceled the operation
+	CategoryServer     = "server"     // the server rejected or failed the request
+	CategoryInternal   = "internal"   // a bug or an unexpected state in the sdk
+)
+
+// Category returns the category of an error code.
+func Category(code int) string {
+	switch code {
+	case NetworkError, NetworkTimeoutError, CtxDeadlineExceededError,
+		MsgDeCompressionError, MsgDecodeBinaryWsError, MsgBinaryTypeNotSupportError:
+		return CategoryNetwork
+	case errs.TokenExpiredError, errs.TokenInvalidError, errs.TokenMalformedError, errs.TokenNotValidYetError,
+		errs.TokenUnknownError, errs.TokenKickedError, errs.TokenNotExistError,
+		SDKNotLoginError, LoginOutError, LoginRepeatError:
+		return CategoryAuth
+	case errs.NoPermissionError, errs.OrgUserNoPermissionError, GuestNotAllowedError, GuestSendLimitError:
+		return CategoryPermission
+	case errs.ArgsError, errs.DuplicateKeyError, errs.RecordNotFoundError:
+		return CategoryValidation
+	case StorageError:
+		return CategoryStorage
+	case CanceledError:
+		return CategoryCanceled
+	case SdkInternalError, UnknownCode:
+		return CategoryInternal
+	}
+	if code >= NetworkError {
+		return CategoryValidation
+	}
+	return CategoryServer
+}
+
+// Retryable reports whether the same call may succeed when it is made again later.
+func Retryable(code int) bool {
+	switch code {
+	case NetworkError, NetworkTimeoutError, CtxDeadlineExceededError, errs.ServerInternalError:
+		return true
+	}
+	return false
+}
+
+var classifiers []func(err error) errs.CodeError
+
+// RegisterClassifier adds a function mapping the errors of a package to a code error,
+// it returns nil for the errors it does not know. It must be called from an init function.
+func RegisterClassifier(fn func(err error) errs.CodeError) {
+	classifiers = append(classifiers, fn)
+}
+
+// Code returns the code of err, errors not carrying a code are classified by their type.
+func Code(err error) int32 {
+	if err == nil {
+		return 0
+	}
+	if code, ok := errs.Unwrap(err).(errs.CodeError); ok {
+		return int32(code.Code())
+	}
+	return int32(classify(err).Code())
+}
+
+// Normalize wraps an error not carrying a code with the code of its category,
+// so the callers of the sdk always get a code they can act on.
+func Normalize(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := errs.Unwrap(err).(errs.CodeError); ok {
+		return err
+	}
+	return classify(err).WrapMsg(err.Error())
+}
+
+func classify(err error) errs.CodeError {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return ErrCanceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrCtxDeadline
+	}
+	for _, fn := range classifiers {
+		if code := fn(err); code != nil {
+			return code
+		}
+	}
+	var (
+		netErr    net.Error
+		pathErr   *fs.PathError
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+		numErr    *strconv.NumError
+	)
+	// a path error is a net.Error too, it is checked first
+	switch {
+	case errors.As(err, &pathErr), errors.Is(err, fs.ErrNotExist), errors.Is(err, fs.ErrPermission):
+		return ErrStorage
+	case errors.As(err, &netErr):
+		if netErr.Timeout() {
+			return ErrNetworkTimeOut
+		}
+		return ErrNetwork
+	case errors.As(err, &syntaxErr), errors.As(err, &typeErr), errors.As(err, &numErr):
+		return ErrArgs
+	}
+	return ErrSdkInternal
+}
@@ -0,0 +1,51 @@
+package sdkerrs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/openimsdk/tools/errs"
+)
+
+func TestCode(t *testing.T) {
+	var syntaxErr error = json.Unmarshal([]byte("{"), &struct{}{})
+	_, pathErr := os.Open("/not/exist/file")
+	for _, c := range []struct {
+		err  error
+		code int32
+	}{
+		{nil, 0},
+		{ErrArgs.WrapMsg("bad"), ArgsError},
+		{errs.WrapMsg(errs.ErrNoPermission, "deny"), errs.NoPermissionError},
+		{fmt.Errorf("pull: %w", context.Canceled), CanceledError},
+		{context.DeadlineExceeded, CtxDeadlineExceededError},
+		{pathErr, StorageError},
+		{syntaxErr, ArgsError},
+		{errors.New("something"), SdkInternalError},
+	} {
+		if code := Code(c.err); code != c.code {
+			t.Errorf("Code(%v) = %d, want %d", c.err, code, c.code)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	err := Normalize(fmt.Errorf("stop: %w", context.Canceled))
+	code, ok := errs.Unwrap(err).(errs.CodeError)
+	if !ok || code.Code() != CanceledError {
+		t.Fatalf("Normalize = %v", err)
+	}
+	if Category(code.Code()) != CategoryCanceled {
+		t.Fatalf("Category(%d) = %s", code.Code(), Category(code.Code()))
+	}
+	if !Retryable(NetworkTimeoutError) || Retryable(ArgsError) {
+		t.Fatal("unexpected Retryable")
+	}
+	if c := Category(errs.TokenExpiredError); c != CategoryAuth {
+		t.Fatalf("Category(TokenExpiredError) = %s", c)
+	}
+}
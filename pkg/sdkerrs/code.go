@@ -27,10 +27,15 @@ const (
 	NoUpdateError    = 10007 // No updates available
 	SDKNotInitError  = 10008 // SDK not init
 	SDKNotLoginError = 10009 // SDK not login
+	StorageError     = 10010 // Local database or file system error
+	CanceledError    = 10011 // Operation canceled by the caller
 
-	UserIDNotFoundError = 10100 // UserID not found or not registered
-	LoginOutError       = 10101 // User has logged out
-	LoginRepeatError    = 10102 // User logged in repeatedly
+	UserIDNotFoundError    = 10100 // UserID not found or not registered
+	LoginOutError          = 10101 // User has logged out
+	LoginRepeatError       = 10102 // User logged in repeatedly
+	GuestNotAllowedError   = 10103 // Operation not allowed in a guest session
+	GuestSendLimitError    = 10104 // Guest send limit reached
+	PrivacyRestrictedError = 10105 // Operation not allowed by the privacy settings of the user
 
 	// Message-related errors
 	FileNotFoundError             = 10200 // Record not found
@@ -41,6 +46,11 @@ const (
 	MsgContentTypeNotSupportError = 10205 // Message content type not supported
 	MsgHasNoSeqError              = 10206 // Message does not have a sequence number
 	MsgHasDeletedError            = 10207 // Message has been deleted
+	MsgPluginRejectedError        = 10208 // Message rejected by a message plugin
+	MsgSensitiveWordsError        = 10209 // Message contains sensitive words
+	MsgRestrictedError            = 10210 // Message restricted by its sender
+	MsgTooLargeError              = 10211 // Message media larger than the server allows
+	MsgModerationBlockedError     = 10212 // Message blocked by the moderator
 
 	// Conversation-related errors
 	NotSupportOptError  = 10301 // Operation not supported
@@ -50,4 +60,8 @@ const (
 	// Group-related errors
 	GroupIDNotFoundError = 10400 // GroupID not found
 	GroupTypeErr         = 10401 // Invalid group type
+
+	// Call-related errors
+	CallNotFoundError = 10500 // Call not found or already over
+	CallStateError    = 10501 // Call state does not allow the operation
 )
@@ -23,11 +23,14 @@ var (
 	ErrSdkInternal    = errs.NewCodeError(SdkInternalError, "Internal SDK error")
 	ErrNetwork        = errs.NewCodeError(NetworkError, "Network error")
 	ErrNetworkTimeOut = errs.NewCodeError(NetworkTimeoutError, "Network timeout error")
+	ErrStorage        = errs.NewCodeError(StorageError, "Local storage error")
+	ErrCanceled       = errs.NewCodeError(CanceledError, "Operation canceled")
 
-	ErrGroupIDNotFound = errs.NewCodeError(GroupIDNotFoundError, "Group ID not found")
-	ErrUserIDNotFound  = errs.NewCodeError(UserIDNotFoundError, "User ID not found")
-	ErrSDKNotInit      = errs.NewCodeError(SDKNotInitError, "SDK not initialized. Please initialize first.")
-	ErrSDKNotLogin     = errs.NewCodeError(SDKNotLoginError, "SDK login incomplete. Please wait until login is complete before proceeding.")
+	ErrGroupIDNotFound   = errs.NewCodeError(GroupIDNotFoundError, "Group ID not found")
+	ErrUserIDNotFound    = errs.NewCodeError(UserIDNotFoundError, "User ID not found")
+	ErrSDKNotInit        = errs.NewCodeError(SDKNotInitError, "SDK not initialized. Please initialize first.")
+	ErrSDKNotLogin       = errs.NewCodeError(SDKNotLoginError, "SDK login incomplete. Please wait until login is complete before proceeding.")
+	ErrPrivacyRestricted = errs.NewCodeError(PrivacyRestrictedError, "Operation not allowed by the privacy settings of the user")
 
 	// Message-related errors
 	ErrFileNotFound             = errs.NewCodeError(FileNotFoundError, "File not found")
@@ -38,6 +41,11 @@ var (
 	ErrMsgContentTypeNotSupport = errs.NewCodeError(MsgContentTypeNotSupportError, "Message content type not supported")
 	ErrMsgHasNoSeq              = errs.NewCodeError(MsgHasNoSeqError, "Message has no sequence number")
 	ErrMsgHasDeleted            = errs.NewCodeError(MsgHasDeletedError, "Message has been deleted")
+	ErrMsgPluginRejected        = errs.NewCodeError(MsgPluginRejectedError, "Message rejected by a message plugin")
+	ErrMsgSensitiveWords        = errs.NewCodeError(MsgSensitiveWordsError, "Message contains sensitive words")
+	ErrMsgRestricted            = errs.NewCodeError(MsgRestrictedError, "Message restricted by its sender")
+	ErrMsgTooLarge              = errs.NewCodeError(MsgTooLargeError, "Message media larger than the server allows")
+	ErrMsgModerationBlocked     = errs.NewCodeError(MsgModerationBlockedError, "Message blocked by the moderator")
 
 	// Conversation-related errors
 	ErrNotSupportOpt  = errs.NewCodeError(NotSupportOptError, "Operation not supported for supergroup")
@@ -47,6 +55,13 @@ var (
 	// Group-related errors
 	ErrGroupType = errs.NewCodeError(GroupTypeErr, "Invalid group type")
 
+	// Call-related errors
+	ErrCallNotFound = errs.NewCodeError(CallNotFoundError, "Call not found or already over")
+	ErrCallState    = errs.NewCodeError(CallStateError, "Call state does not allow the operation")
+
 	ErrLoginOut    = errs.NewCodeError(LoginOutError, "User has logged out")
 	ErrLoginRepeat = errs.NewCodeError(LoginRepeatError, "User has logged in repeatedly")
+
+	ErrGuestNotAllowed = errs.NewCodeError(GuestNotAllowedError, "Operation not allowed in a guest session")
+	ErrGuestSendLimit  = errs.NewCodeError(GuestSendLimitError, "Guest send limit reached, please try again later")
 )
@@ -0,0 +1,62 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server_api_params
+
+type CreateQRLoginReq struct {
+	PlatformID int32 `json:"platformID"`
+}
+
+type CreateQRLoginResp struct {
+	QRID       string `json:"qrID"`
+	ExpireTime int64  `json:"expireTime"`
+}
+
+type GetQRLoginStatusReq struct {
+	QRID string `json:"qrID"`
+}
+
+type GetQRLoginStatusResp struct {
+	State  int32  `json:"state"`
+	UserID string `json:"userID"`
+	Token  string `json:"token"`
+}
+
+type ScanQRLoginReq struct {
+	QRID string `json:"qrID"`
+}
+
+type ScanQRLoginResp struct {
+	PlatformID int32 `json:"platformID"`
+}
+
+type ConfirmQRLoginReq struct {
+	QRID    string `json:"qrID"`
+	Approve bool   `json:"approve"`
+}
+
+type ConfirmQRLoginResp struct{}
+
+type GuestLoginReq struct {
+	PlatformID int32 `json:"platformID"`
+}
+
+type GuestLoginResp struct {
+	UserID string `json:"userID"`
+	Token  string `json:"token"`
+	// GroupIDs are the group conversations the guest may join and send to.
+	GroupIDs []string `json:"groupIDs"`
+	// SendLimit is the max number of messages per minute, the init config is used when it is 0.
+	SendLimit int32 `json:"sendLimit"`
+}
@@ -0,0 +1,41 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server_api_params
+
+import "github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+
+// SubmitInteractiveActionReq routes an action on an interactive card to the bot which sent it. Payload is the
+// value of the option chosen for a menu.
+type SubmitInteractiveActionReq struct {
+	ConversationID string `json:"conversationID"`
+	ClientMsgID    string `json:"clientMsgID"`
+	ServerMsgID    string `json:"serverMsgID"`
+	BotUserID      string `json:"botUserID"`
+	ActionID       string `json:"actionID"`
+	Payload        string `json:"payload"`
+}
+
+// SubmitInteractiveActionResp is the card as edited by the bot when it answered right away, nil otherwise,
+// the edit then comes with an interactive card notification.
+type SubmitInteractiveActionResp struct {
+	Card *sdk_struct.InteractiveCardElem `json:"card"`
+}
+
+// InteractiveCardUpdatedTips is the content of the interactive card notification.
+type InteractiveCardUpdatedTips struct {
+	ConversationID string                          `json:"conversationID"`
+	ClientMsgID    string                          `json:"clientMsgID"`
+	Card           *sdk_struct.InteractiveCardElem `json:"card"`
+}
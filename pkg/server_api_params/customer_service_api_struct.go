@@ -0,0 +1,63 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server_api_params
+
+import "github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+
+// RequestCustomerServiceAgentReq asks for an agent of the queue, the session is assigned right away when an
+// agent is free and waits in the queue otherwise.
+type RequestCustomerServiceAgentReq struct {
+	Queue string `json:"queue"`
+	Ex    string `json:"ex"`
+}
+
+type CustomerServiceSessionResp struct {
+	Session *model_struct.LocalCustomerServiceSession `json:"session"`
+}
+
+// TransferCustomerServiceSessionReq hands the session over to the agent, or to the next free agent of the
+// queue when toAgentUserID is empty.
+type TransferCustomerServiceSessionReq struct {
+	SessionID     string `json:"sessionID"`
+	ToAgentUserID string `json:"toAgentUserID"`
+	ToQueue       string `json:"toQueue"`
+	Reason        string `json:"reason"`
+}
+
+type CloseCustomerServiceSessionReq struct {
+	SessionID string `json:"sessionID"`
+	Reason    string `json:"reason"`
+}
+
+type RateCustomerServiceSessionReq struct {
+	SessionID string `json:"sessionID"`
+	Rating    int32  `json:"rating"`
+	Comment   string `json:"comment"`
+}
+
+// GetActiveCustomerServiceSessionsReq gets the sessions of the login user waiting or being served.
+type GetActiveCustomerServiceSessionsReq struct{}
+
+type GetActiveCustomerServiceSessionsResp struct {
+	Sessions []*model_struct.LocalCustomerServiceSession `json:"sessions"`
+}
+
+// CustomerServiceTips is the content of the customer-service notifications: the session as it is now, and the
+// agent it was transferred from with the reason for a transfer, or the reason of the close.
+type CustomerServiceTips struct {
+	Session         *model_struct.LocalCustomerServiceSession `json:"session"`
+	FromAgentUserID string                                    `json:"fromAgentUserID,omitempty"`
+	Reason          string                                    `json:"reason,omitempty"`
+}
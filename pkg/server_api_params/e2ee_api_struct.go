@@ -0,0 +1,80 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server_api_params
+
+import "github.com/openimsdk/openim-sdk-core/v3/pkg/e2ee"
+
+type E2EEPreKey struct {
+	KeyID     string `json:"keyID"`
+	PublicKey []byte `json:"publicKey"`
+}
+
+// UploadE2EEKeysReq replaces the identity and the signed pre key of the user and adds the one time pre keys.
+type UploadE2EEKeysReq struct {
+	IdentityKey           []byte        `json:"identityKey"`
+	SigningKey            []byte        `json:"signingKey"`
+	SignedPreKeyID        string        `json:"signedPreKeyID"`
+	SignedPreKey          []byte        `json:"signedPreKey"`
+	SignedPreKeySignature []byte        `json:"signedPreKeySignature"`
+	OneTimePreKeys        []*E2EEPreKey `json:"oneTimePreKeys"`
+}
+
+type UploadE2EEKeysResp struct{}
+
+// GetE2EEKeyBundleReq takes a one time pre key of each device of the user, it is handed out once.
+type GetE2EEKeyBundleReq struct {
+	UserID string `json:"userID"`
+	// DeviceIDs are the devices to take a bundle of, all the devices of the user when empty
+	DeviceIDs []string `json:"deviceIDs,omitempty"`
+}
+
+type GetE2EEKeyBundleResp struct {
+	// Bundles is one per device, empty when the user did not enable the encryption
+	Bundles []*e2ee.Bundle `json:"bundles"`
+}
+
+type GetE2EEPreKeyCountReq struct{}
+
+type GetE2EEPreKeyCountResp struct {
+	Count int32 `json:"count"`
+}
+
+// UploadE2EEBackupReq replaces the key backup of the user, encrypted with a passphrase the server never sees.
+type UploadE2EEBackupReq struct {
+	Backup []byte `json:"backup"`
+}
+
+type UploadE2EEBackupResp struct{}
+
+type GetE2EEBackupReq struct{}
+
+type GetE2EEBackupResp struct {
+	// Backup is empty when the user has none
+	Backup []byte `json:"backup"`
+}
+
+type E2EEDevice struct {
+	IdentityKey []byte `json:"identityKey"`
+	SigningKey  []byte `json:"signingKey"`
+}
+
+// GetE2EEDevicesReq lists the identities the user published, one per device.
+type GetE2EEDevicesReq struct {
+	UserID string `json:"userID"`
+}
+
+type GetE2EEDevicesResp struct {
+	Devices []*E2EEDevice `json:"devices"`
+}
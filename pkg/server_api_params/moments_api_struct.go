@@ -0,0 +1,79 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server_api_params
+
+import "github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+
+type PublishMomentReq struct {
+	Content        string                      `json:"content"`
+	Medias         []*model_struct.MomentMedia `json:"medias"`
+	Visibility     int32                       `json:"visibility"`
+	VisibleUserIDs []string                    `json:"visibleUserIDs"`
+	Ex             string                      `json:"ex"`
+}
+
+type PublishMomentResp struct {
+	Moment *model_struct.LocalMoment `json:"moment"`
+}
+
+type DeleteMomentReq struct {
+	MomentID string `json:"momentID"`
+}
+
+type DeleteMomentResp struct{}
+
+// GetMomentFeedReq gets the moments the login user sees after the given one, the latest first. The feed is
+// the moments of the friends and of the login user, or the moments of the user when it is set.
+type GetMomentFeedReq struct {
+	UserID     string `json:"userID"`
+	CreateTime int64  `json:"createTime"`
+	MomentID   string `json:"momentID"`
+	Count      int32  `json:"count"`
+}
+
+type GetMomentFeedResp struct {
+	Moments []*model_struct.LocalMoment `json:"moments"`
+}
+
+type LikeMomentReq struct {
+	MomentID string `json:"momentID"`
+	// Like is false to take the like back
+	Like bool `json:"like"`
+}
+
+type CommentMomentReq struct {
+	MomentID      string `json:"momentID"`
+	ReplyToUserID string `json:"replyToUserID"`
+	Content       string `json:"content"`
+}
+
+type DeleteMomentCommentReq struct {
+	MomentID  string `json:"momentID"`
+	CommentID string `json:"commentID"`
+}
+
+// MomentResp is the moment after a like or a comment.
+type MomentResp struct {
+	Moment *model_struct.LocalMoment `json:"moment"`
+}
+
+// MomentTips is the content of the moment notifications: the moment published, liked or commented, the user
+// who liked it and the comment added, or the ID of the moment deleted.
+type MomentTips struct {
+	Moment   *model_struct.LocalMoment   `json:"moment,omitempty"`
+	MomentID string                      `json:"momentID,omitempty"`
+	UserID   string                      `json:"userID,omitempty"`
+	Comment  *model_struct.MomentComment `json:"comment,omitempty"`
+}
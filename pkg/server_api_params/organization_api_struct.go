@@ -0,0 +1,84 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server_api_params
+
+import (
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/protocol/sdkws"
+)
+
+// GetPaginationDepartmentsReq gets a page of all the departments of the organization.
+type GetPaginationDepartmentsReq struct {
+	Pagination *sdkws.RequestPagination `json:"pagination"`
+}
+
+func (x *GetPaginationDepartmentsReq) GetPagination() *sdkws.RequestPagination {
+	return x.Pagination
+}
+
+type GetPaginationDepartmentsResp struct {
+	Total       int32                           `json:"total"`
+	Departments []*model_struct.LocalDepartment `json:"departments"`
+}
+
+// GetPaginationDepartmentMembersReq gets a page of the members of all the departments of the organization.
+type GetPaginationDepartmentMembersReq struct {
+	Pagination *sdkws.RequestPagination `json:"pagination"`
+}
+
+func (x *GetPaginationDepartmentMembersReq) GetPagination() *sdkws.RequestPagination {
+	return x.Pagination
+}
+
+type GetPaginationDepartmentMembersResp struct {
+	Total   int32                                 `json:"total"`
+	Members []*model_struct.LocalDepartmentMember `json:"members"`
+}
+
+// GetIncrementalDepartmentsReq gets the departments changed since the version, Full is set in the response
+// when the version is too old, or of another versionID, for the changes to be sent.
+type GetIncrementalDepartmentsReq struct {
+	VersionID string `json:"versionID"`
+	Version   uint64 `json:"version"`
+}
+
+type GetIncrementalDepartmentsResp struct {
+	VersionID string                          `json:"versionID"`
+	Version   uint64                          `json:"version"`
+	Full      bool                            `json:"full"`
+	Delete    []string                        `json:"delete"`
+	Insert    []*model_struct.LocalDepartment `json:"insert"`
+	Update    []*model_struct.LocalDepartment `json:"update"`
+}
+
+type GetIncrementalDepartmentMembersReq struct {
+	VersionID string `json:"versionID"`
+	Version   uint64 `json:"version"`
+}
+
+// DepartmentMemberID is a user removed from a department.
+type DepartmentMemberID struct {
+	DepartmentID string `json:"departmentID"`
+	UserID       string `json:"userID"`
+}
+
+type GetIncrementalDepartmentMembersResp struct {
+	VersionID string                                `json:"versionID"`
+	Version   uint64                                `json:"version"`
+	Full      bool                                  `json:"full"`
+	Delete    []*DepartmentMemberID                 `json:"delete"`
+	Insert    []*model_struct.LocalDepartmentMember `json:"insert"`
+	Update    []*model_struct.LocalDepartmentMember `json:"update"`
+}
@@ -0,0 +1,25 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server_api_params
+
+// SetOfflinePushTokenReq is the fcm token update with the provider of the token, the servers not knowing
+// the provider take the token as an fcm one.
+type SetOfflinePushTokenReq struct {
+	PlatformID int32  `json:"platformID"`
+	Token      string `json:"fcmToken"`
+	Provider   string `json:"provider"`
+	Account    string `json:"account"`
+	ExpireTime int64  `json:"expireTime"`
+}
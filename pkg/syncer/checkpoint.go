@@ -0,0 +1,108 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/db_interface"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/tools/errs"
+)
+
+// fullSyncCheckpointSuffix is appended to the table name of a version record to keep the checkpoint of its full sync.
+const fullSyncCheckpointSuffix = "_full_sync"
+
+// fullSyncCheckpoint keeps the keys of the items a full sync inserted so far. A full sync cut off, e.g. by the
+// app being killed, goes on from the page it reached on the next launch instead of pulling everything again.
+// It only applies to a full sync of the same server version, the pages of another version may differ.
+type fullSyncCheckpoint struct {
+	ctx    context.Context
+	db     db_interface.VersionSyncModel
+	record *model_struct.LocalVersionSync
+}
+
+type checkpointKey struct{}
+
+// loadFullSyncCheckpoint gets the checkpoint of the full sync of the server version, the checkpoint of another
+// version is dropped and the full sync starts from scratch.
+func loadFullSyncCheckpoint(ctx context.Context, db db_interface.VersionSyncModel, table, entityID, versionID string,
+	version uint64) (*fullSyncCheckpoint, error) {
+	table += fullSyncCheckpointSuffix
+	record, err := db.GetVersionSync(ctx, table, entityID)
+	if err != nil && errs.Unwrap(err) != errs.ErrRecordNotFound {
+		return nil, err
+	}
+	if record == nil || record.VersionID != versionID || record.Version != version {
+		if record != nil && len(record.UIDList) > 0 {
+			log.ZDebug(ctx, "full sync checkpoint of another version", "table", table, "entityID", entityID,
+				"versionID", record.VersionID, "version", record.Version)
+			if err := db.DeleteVersionSync(ctx, table, entityID); err != nil {
+				return nil, err
+			}
+		}
+		record = &model_struct.LocalVersionSync{Table: table, EntityID: entityID, VersionID: versionID, Version: version}
+	}
+	return &fullSyncCheckpoint{ctx: ctx, db: db, record: record}, nil
+}
+
+// resumed is the number of items inserted before the full sync was cut off.
+func (c *fullSyncCheckpoint) resumed() int {
+	return len(c.record.UIDList)
+}
+
+// save adds the keys of the items of an inserted page.
+func (c *fullSyncCheckpoint) save(keys []string) error {
+	c.record.UIDList = append(c.record.UIDList, keys...)
+	return c.db.SetVersionSync(c.ctx, c.record)
+}
+
+// clear drops the checkpoint once the full sync is over.
+func (c *fullSyncCheckpoint) clear() error {
+	if c.resumed() == 0 {
+		return nil
+	}
+	return c.db.DeleteVersionSync(c.ctx, c.record.Table, c.record.EntityID)
+}
+
+func withFullSyncCheckpoint(ctx context.Context, checkpoint *fullSyncCheckpoint) context.Context {
+	return context.WithValue(ctx, checkpointKey{}, checkpoint)
+}
+
+func fullSyncCheckpointOf(ctx context.Context) *fullSyncCheckpoint {
+	checkpoint, _ := ctx.Value(checkpointKey{}).(*fullSyncCheckpoint)
+	return checkpoint
+}
+
+// FullSyncResumed tells whether the full sync run with the context goes on from a checkpoint, the local items
+// are then the ones of the pages inserted before it was cut off.
+func FullSyncResumed(ctx context.Context) bool {
+	checkpoint := fullSyncCheckpointOf(ctx)
+	return checkpoint != nil && checkpoint.resumed() > 0
+}
+
+func checkpointKeys[T any, V comparable](values []T, uuid func(T) V) []string {
+	keys := make([]string, 0, len(values))
+	for i, value := range values {
+		if uuid == nil {
+			keys = append(keys, fmt.Sprint(i))
+			continue
+		}
+		keys = append(keys, fmt.Sprint(uuid(value)))
+	}
+	return keys
+}
@@ -0,0 +1,65 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncer
+
+import "context"
+
+// Progress receives the progress of the phases of a sync, e.g. to show a progress bar at login.
+type Progress interface {
+	// Update is told how many items of the phase are done out of total, total is 0 while unknown.
+	Update(phase string, done, total int)
+	Done(phase string)
+}
+
+type progressKey struct{}
+
+type phaseKey struct{}
+
+// WithProgress makes the syncs run with the context report their progress.
+func WithProgress(ctx context.Context, progress Progress) context.Context {
+	return context.WithValue(ctx, progressKey{}, progress)
+}
+
+// WithPhase sets the phase the syncs run with the context report their progress to, the syncs with an empty
+// phase report nothing, for the inner syncs of a phase counting its items itself.
+func WithPhase(ctx context.Context, phase string) context.Context {
+	return context.WithValue(ctx, phaseKey{}, phase)
+}
+
+func progressOf(ctx context.Context) (Progress, string) {
+	progress, _ := ctx.Value(progressKey{}).(Progress)
+	phase, _ := ctx.Value(phaseKey{}).(string)
+	if progress == nil || phase == "" {
+		return nil, ""
+	}
+	return progress, phase
+}
+
+// ReportProgress reports the items done of the phase of the context.
+func ReportProgress(ctx context.Context, done, total int) {
+	if progress, phase := progressOf(ctx); progress != nil {
+		progress.Update(phase, done, total)
+	}
+}
+
+// RunPhase runs fn as the phase of the sync and reports the phase done once fn returns.
+func RunPhase(ctx context.Context, phase string, fn func(ctx context.Context) error) error {
+	ctx = WithPhase(ctx, phase)
+	err := fn(ctx)
+	if progress, phase := progressOf(ctx); progress != nil {
+		progress.Done(phase)
+	}
+	return err
+}
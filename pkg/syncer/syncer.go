@@ -20,12 +20,11 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/network"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/page"
 	"github.com/openimsdk/tools/errs"
 	"github.com/openimsdk/tools/utils/datautil"
-
-	"github.com/openimsdk/tools/log"
 )
 
 // New creates a new Syncer instance with the provided synchronization functions.
@@ -273,6 +272,7 @@ func (s *Syncer[T, RESP, V]) Sync(ctx context.Context, serverData []T, localData
 
 	// Iterate through server data to sync with local data.
 	for i := range serverData {
+		ReportProgress(ctx, i, len(serverData))
 		server := serverData[i]
 		id := s.uuid(server)
 		local, ok := localMap[id]
@@ -321,6 +321,7 @@ func (s *Syncer[T, RESP, V]) Sync(ctx context.Context, serverData []T, localData
 		}
 	}
 
+	ReportProgress(ctx, len(serverData), len(serverData))
 	// Check the skipDeletion flag; if set, skip deletion.
 	if skipDeletion {
 		return nil
@@ -360,17 +361,44 @@ func (s *Syncer[T, RESP, V]) FullSync(ctx context.Context, entityID string) (err
 	//	return nil
 	//}
 
-	// Clear local table data
-	if err = s.deleteAll(ctx, entityID); err != nil {
-		return errs.New("full sync delete all failed", "err", err.Error(), "type", s.ts)
-	}
-
 	// Get batch req
 	batchReq := s.batchPageReq(entityID)
+	if batchReq.GetPagination().ShowNumber <= 0 {
+		batchReq.GetPagination().ShowNumber = 50
+	}
+
+	// Go on from the pages inserted before the last full sync was cut off, or clear local table data
+	var (
+		skipPages    int32
+		pageInserted func(ctx context.Context, values []T) error
+	)
+	if checkpoint := fullSyncCheckpointOf(ctx); checkpoint != nil {
+		resumed := checkpoint.resumed()
+		// a partial last page or the limit reached means the pages were all inserted before the cut off
+		if resumed%int(batchReq.GetPagination().ShowNumber) != 0 || (s.fullSyncLimit > 0 && int64(resumed) >= s.fullSyncLimit) {
+			log.ZInfo(ctx, "full sync already inserted all the pages", "type", s.ts, "resumed", resumed)
+			return nil
+		}
+		skipPages = int32(resumed) / batchReq.GetPagination().ShowNumber
+		pageInserted = func(ctx context.Context, values []T) error {
+			return checkpoint.save(checkpointKeys(values, s.uuid))
+		}
+	}
+	if skipPages > 0 {
+		log.ZInfo(ctx, "full sync resumed", "type", s.ts, "skipPages", skipPages)
+	} else if err = s.deleteAll(ctx, entityID); err != nil {
+		return errs.New("full sync delete all failed", "err", err.Error(), "type", s.ts)
+	}
 
-	// Batch page pull data and insert server data
-	if err = network.FetchAndInsertPagedData(ctx, s.reqApiRouter, batchReq, s.batchPageRespConvertFunc,
-		s.batchInsert, s.insert, s.fullSyncLimit); err != nil {
+	// Batch page pull data and insert server data, the number of items is only known at the end
+	fetched := int(skipPages * batchReq.GetPagination().ShowNumber)
+	batchInsert := func(ctx context.Context, values []T) error {
+		fetched += len(values)
+		ReportProgress(ctx, fetched, 0)
+		return s.batchInsert(ctx, values)
+	}
+	if err = network.FetchAndInsertPagedDataFrom(ctx, s.reqApiRouter, batchReq, skipPages, s.batchPageRespConvertFunc,
+		batchInsert, s.insert, pageInserted, s.fullSyncLimit); err != nil {
 		return errs.New("full sync batch insert failed", "err", err.Error(), "type", s.ts)
 	}
 
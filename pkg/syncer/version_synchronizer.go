@@ -6,8 +6,8 @@ import (
 
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/db_interface"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
 	"github.com/openimsdk/tools/errs"
-	"github.com/openimsdk/tools/log"
 	"github.com/openimsdk/tools/utils/datautil"
 )
 
@@ -92,14 +92,22 @@ func (o *VersionSynchronizer[V, R]) IncrementalSync() error {
 	}
 
 	if o.Full(resp) {
-		err := o.FullSyncer(o.Ctx)
+		versionID, version := o.Version(resp)
+		checkpoint, err := loadFullSyncCheckpoint(o.Ctx, o.DB, o.TableName, o.EntityID, versionID, version)
 		if err != nil {
 			return err
 		}
+		if err := o.FullSyncer(withFullSyncCheckpoint(o.Ctx, checkpoint)); err != nil {
+			return err
+		}
 		lvs.UIDList, err = o.FullID(o.Ctx)
 		if err != nil {
 			return err
 		}
+		if err := o.updateVersionInfo(lvs, resp); err != nil {
+			return err
+		}
+		return checkpoint.clear()
 	} else {
 		if len(delIDs) > 0 {
 			lvs.UIDList = datautil.DeleteElems(lvs.UIDList, delIDs...)
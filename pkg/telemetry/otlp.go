@@ -0,0 +1,292 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/version"
+	"github.com/openimsdk/tools/errs"
+)
+
+// The json encoding of OTLP, the 64 bits integers are strings and the ids hex.
+// See https://opentelemetry.io/docs/specs/otlp/#json-protobuf-encoding.
+const (
+	scopeName = "github.com/openimsdk/openim-sdk-core"
+	// temporalityDelta is AGGREGATION_TEMPORALITY_DELTA, every export holds what is recorded since the last one
+	temporalityDelta = 1
+	spanKindInternal = 1
+	statusOk         = 1
+	statusError      = 2
+	// maxResponseBody bounds the response of the collector read for an error
+	maxResponseBody = 4096
+)
+
+type anyValue struct {
+	StringValue *string `json:"stringValue,omitempty"`
+	IntValue    *string `json:"intValue,omitempty"`
+	BoolValue   *bool   `json:"boolValue,omitempty"`
+}
+
+type keyValue struct {
+	Key   string   `json:"key"`
+	Value anyValue `json:"value"`
+}
+
+type resource struct {
+	Attributes []keyValue `json:"attributes"`
+}
+
+type scope struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+type histogramDataPoint struct {
+	Attributes        []keyValue `json:"attributes,omitempty"`
+	StartTimeUnixNano string     `json:"startTimeUnixNano"`
+	TimeUnixNano      string     `json:"timeUnixNano"`
+	Count             string     `json:"count"`
+	Sum               float64    `json:"sum"`
+	Min               float64    `json:"min"`
+	Max               float64    `json:"max"`
+	BucketCounts      []string   `json:"bucketCounts"`
+	ExplicitBounds    []float64  `json:"explicitBounds"`
+}
+
+type numberDataPoint struct {
+	Attributes        []keyValue `json:"attributes,omitempty"`
+	StartTimeUnixNano string     `json:"startTimeUnixNano"`
+	TimeUnixNano      string     `json:"timeUnixNano"`
+	AsInt             string     `json:"asInt"`
+}
+
+type histogramData struct {
+	AggregationTemporality int                  `json:"aggregationTemporality"`
+	DataPoints             []histogramDataPoint `json:"dataPoints"`
+}
+
+type sumData struct {
+	AggregationTemporality int               `json:"aggregationTemporality"`
+	IsMonotonic            bool              `json:"isMonotonic"`
+	DataPoints             []numberDataPoint `json:"dataPoints"`
+}
+
+type metric struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Unit        string         `json:"unit"`
+	Histogram   *histogramData `json:"histogram,omitempty"`
+	Sum         *sumData       `json:"sum,omitempty"`
+}
+
+type scopeMetrics struct {
+	Scope   scope    `json:"scope"`
+	Metrics []metric `json:"metrics"`
+}
+
+type resourceMetrics struct {
+	Resource     resource       `json:"resource"`
+	ScopeMetrics []scopeMetrics `json:"scopeMetrics"`
+}
+
+type metricsRequest struct {
+	ResourceMetrics []resourceMetrics `json:"resourceMetrics"`
+}
+
+type spanStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type span struct {
+	TraceID           string     `json:"traceId"`
+	SpanID            string     `json:"spanId"`
+	ParentSpanID      string     `json:"parentSpanId,omitempty"`
+	Name              string     `json:"name"`
+	Kind              int        `json:"kind"`
+	StartTimeUnixNano string     `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string     `json:"endTimeUnixNano"`
+	Attributes        []keyValue `json:"attributes,omitempty"`
+	Status            spanStatus `json:"status"`
+}
+
+type scopeSpans struct {
+	Scope scope  `json:"scope"`
+	Spans []span `json:"spans"`
+}
+
+type resourceSpans struct {
+	Resource   resource     `json:"resource"`
+	ScopeSpans []scopeSpans `json:"scopeSpans"`
+}
+
+type tracesRequest struct {
+	ResourceSpans []resourceSpans `json:"resourceSpans"`
+}
+
+func keyValues(attrs []Attr) []keyValue {
+	kvs := make([]keyValue, 0, len(attrs))
+	for _, attr := range attrs {
+		kv := keyValue{Key: attr.Key}
+		switch v := attr.Value.(type) {
+		case string:
+			kv.Value.StringValue = &v
+		case int64:
+			s := strconv.FormatInt(v, 10)
+			kv.Value.IntValue = &s
+		case bool:
+			kv.Value.BoolValue = &v
+		default:
+			continue
+		}
+		kvs = append(kvs, kv)
+	}
+	return kvs
+}
+
+func unixNano(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+// collect takes what is recorded since the last export, a request is nil when there is nothing to export.
+func (e *exporter) collect(now time.Time) (*metricsRequest, *tracesRequest, int) {
+	e.lock.Lock()
+	start := e.start
+	histograms, counters, spans, dropped := e.histograms, e.counters, e.spans, e.dropped
+	e.start = now
+	e.histograms = make(map[string]map[string]*histogram)
+	e.counters = make(map[string]map[string]*counter)
+	e.spans = nil
+	e.dropped = 0
+	e.lock.Unlock()
+
+	res := resource{Attributes: keyValues(e.resource)}
+	sc := scope{Name: scopeName, Version: version.Version}
+	var metrics []metric
+	for _, name := range sortedKeys(histograms) {
+		data := &histogramData{AggregationTemporality: temporalityDelta}
+		for _, key := range sortedKeys(histograms[name]) {
+			h := histograms[name][key]
+			buckets := make([]string, len(h.buckets))
+			for i, n := range h.buckets {
+				buckets[i] = strconv.FormatUint(n, 10)
+			}
+			data.DataPoints = append(data.DataPoints, histogramDataPoint{
+				Attributes:        keyValues(h.attrs),
+				StartTimeUnixNano: unixNano(start),
+				TimeUnixNano:      unixNano(now),
+				Count:             strconv.FormatUint(h.count, 10),
+				Sum:               h.sum,
+				Min:               h.min,
+				Max:               h.max,
+				BucketCounts:      buckets,
+				ExplicitBounds:    bounds,
+			})
+		}
+		metrics = append(metrics, metric{Name: name, Description: descriptions[name], Unit: "ms", Histogram: data})
+	}
+	for _, name := range sortedKeys(counters) {
+		data := &sumData{AggregationTemporality: temporalityDelta, IsMonotonic: true}
+		for _, key := range sortedKeys(counters[name]) {
+			c := counters[name][key]
+			data.DataPoints = append(data.DataPoints, numberDataPoint{
+				Attributes:        keyValues(c.attrs),
+				StartTimeUnixNano: unixNano(start),
+				TimeUnixNano:      unixNano(now),
+				AsInt:             strconv.FormatInt(c.value, 10),
+			})
+		}
+		metrics = append(metrics, metric{Name: name, Description: descriptions[name], Unit: "1", Sum: data})
+	}
+	var metricsReq *metricsRequest
+	if len(metrics) > 0 {
+		metricsReq = &metricsRequest{ResourceMetrics: []resourceMetrics{{
+			Resource:     res,
+			ScopeMetrics: []scopeMetrics{{Scope: sc, Metrics: metrics}},
+		}}}
+	}
+	var tracesReq *tracesRequest
+	if len(spans) > 0 {
+		out := make([]span, 0, len(spans))
+		for _, s := range spans {
+			o := span{
+				TraceID:           hex.EncodeToString(s.traceID[:]),
+				SpanID:            hex.EncodeToString(s.spanID[:]),
+				Name:              s.name,
+				Kind:              spanKindInternal,
+				StartTimeUnixNano: unixNano(s.start),
+				EndTimeUnixNano:   unixNano(s.end),
+				Attributes:        keyValues(s.attrs),
+				Status:            spanStatus{Code: statusOk},
+			}
+			if s.parentID != [8]byte{} {
+				o.ParentSpanID = hex.EncodeToString(s.parentID[:])
+			}
+			if s.err != nil {
+				o.Status = spanStatus{Code: statusError, Message: s.err.Error()}
+			}
+			out = append(out, o)
+		}
+		tracesReq = &tracesRequest{ResourceSpans: []resourceSpans{{
+			Resource:   res,
+			ScopeSpans: []scopeSpans{{Scope: sc, Spans: out}},
+		}}}
+	}
+	return metricsReq, tracesReq, dropped
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (e *exporter) post(ctx context.Context, path string, body any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return errs.WrapMsg(err, "marshal telemetry failed")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint+path, bytes.NewReader(data))
+	if err != nil {
+		return errs.WrapMsg(err, "new telemetry request failed")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return errs.WrapMsg(err, "post telemetry failed")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+		return errs.New("telemetry collector responded "+resp.Status, "body", string(msg)).Wrap()
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
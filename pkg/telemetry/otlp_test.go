@@ -0,0 +1,96 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+func TestExport(t *testing.T) {
+	var (
+		lock     sync.Mutex
+		requests = make(map[string][]byte)
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Api-Key") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var body json.RawMessage
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		lock.Lock()
+		requests[r.URL.Path] = body
+		lock.Unlock()
+	}))
+	defer server.Close()
+
+	if err := Configure(&sdk_struct.TelemetryConfig{Endpoint: "ftp://collector"}); err == nil {
+		t.Fatal("unsupported endpoint accepted")
+	}
+	if err := Configure(&sdk_struct.TelemetryConfig{
+		Endpoint:         server.URL + "/",
+		Headers:          map[string]string{"Api-Key": "secret"},
+		ExportInterval:   int64(time.Hour / time.Millisecond),
+		TraceSampleRatio: 1,
+	}, String("openim.system_type", "test")); err != nil {
+		t.Fatal(err)
+	}
+	Record(MsgSendLatency, 3, Int("content_type", 101))
+	Record(MsgSendLatency, 700, Int("content_type", 101))
+	Add(WsReconnects, 2, String("result", "success"))
+	ctx, parent := StartSpan(context.Background(), "msg.sync")
+	_, child := StartSpan(ctx, "msg.send")
+	child.End(nil)
+	parent.End(context.DeadlineExceeded)
+	if err := Configure(nil); err != nil {
+		t.Fatal(err)
+	}
+	if Enabled() {
+		t.Fatal("telemetry enabled after it is stopped")
+	}
+	Record(MsgSendLatency, 1)
+
+	var metrics metricsRequest
+	if err := json.Unmarshal(requests["/v1/metrics"], &metrics); err != nil {
+		t.Fatal(err)
+	}
+	got := metrics.ResourceMetrics[0].ScopeMetrics[0].Metrics
+	if len(got) != 2 || got[0].Name != MsgSendLatency || got[1].Name != WsReconnects {
+		t.Fatalf("unexpected metrics %+v", got)
+	}
+	point := got[0].Histogram.DataPoints[0]
+	if point.Count != "2" || point.Sum != 703 || point.Min != 3 || point.Max != 700 {
+		t.Fatalf("unexpected histogram %+v", point)
+	}
+	// 3ms falls in the first bucket and 700ms in the one up to 1000ms
+	if point.BucketCounts[0] != "1" || point.BucketCounts[7] != "1" {
+		t.Fatalf("unexpected buckets %v", point.BucketCounts)
+	}
+	if got[1].Sum.DataPoints[0].AsInt != "2" || !got[1].Sum.IsMonotonic {
+		t.Fatalf("unexpected counter %+v", got[1].Sum)
+	}
+
+	var traces tracesRequest
+	if err := json.Unmarshal(requests["/v1/traces"], &traces); err != nil {
+		t.Fatal(err)
+	}
+	spans := traces.ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != 2 {
+		t.Fatalf("unexpected spans %+v", spans)
+	}
+	if spans[0].TraceID != spans[1].TraceID || spans[0].ParentSpanID != spans[1].SpanID || spans[1].ParentSpanID != "" {
+		t.Fatalf("spans not in one trace %+v", spans)
+	}
+	if spans[1].Status.Code != statusError || spans[0].Status.Code != statusOk {
+		t.Fatalf("unexpected status %+v", spans)
+	}
+}
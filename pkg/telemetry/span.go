@@ -0,0 +1,102 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"context"
+	"crypto/rand"
+	"math/big"
+	"time"
+)
+
+// maxSpans bounds the spans kept between two exports, the ones beyond it are dropped
+const maxSpans = 2048
+
+type spanKey struct{}
+
+// Span is an operation of a trace, a nil span is valid and records nothing.
+type Span struct {
+	name     string
+	traceID  [16]byte
+	spanID   [8]byte
+	parentID [8]byte
+	sampled  bool
+	start    time.Time
+	end      time.Time
+	attrs    []Attr
+	err      error
+}
+
+// StartSpan starts a span, the child of the span of ctx if any. The trace is sampled at its root with the
+// ratio of the config, the span is nil while the telemetry is not configured.
+func StartSpan(ctx context.Context, name string, attrs ...Attr) (context.Context, *Span) {
+	e := current.Load()
+	if e == nil {
+		return ctx, nil
+	}
+	s := &Span{name: name, start: time.Now(), attrs: attrs}
+	if parent, ok := ctx.Value(spanKey{}).(*Span); ok && parent != nil {
+		s.traceID = parent.traceID
+		s.parentID = parent.spanID
+		s.sampled = parent.sampled
+	} else {
+		_, _ = rand.Read(s.traceID[:])
+		s.sampled = sample(e.ratio)
+	}
+	_, _ = rand.Read(s.spanID[:])
+	return context.WithValue(ctx, spanKey{}, s), s
+}
+
+func sample(ratio float64) bool {
+	if ratio <= 0 {
+		return false
+	}
+	if ratio >= 1 {
+		return true
+	}
+	n, err := rand.Int(rand.Reader, big.NewInt(1<<53))
+	if err != nil {
+		return false
+	}
+	return float64(n.Int64())/(1<<53) < ratio
+}
+
+// SetAttributes adds attributes to the span, e.g. ones known once it ends.
+func (s *Span) SetAttributes(attrs ...Attr) {
+	if s == nil {
+		return
+	}
+	s.attrs = append(s.attrs, attrs...)
+}
+
+// End ends the span, failed with err if not nil, and queues it for the next export when its trace is sampled.
+func (s *Span) End(err error) {
+	if s == nil || !s.sampled {
+		return
+	}
+	s.end = time.Now()
+	s.err = err
+	e := current.Load()
+	if e == nil {
+		return
+	}
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if len(e.spans) >= maxSpans {
+		e.dropped++
+		return
+	}
+	e.spans = append(e.spans, s)
+}
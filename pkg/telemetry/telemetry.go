@@ -0,0 +1,306 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package telemetry records the latencies and the durations of the key paths of the SDK as metrics and spans,
+// and exports them with OTLP over http to the collector of the operator. Nothing is recorded until it is
+// configured.
+package telemetry
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/version"
+)
+
+// The instruments of the SDK, the latencies and the durations are histograms in milliseconds.
+const (
+	MsgSendLatency  = "openim.sdk.msg.send.latency"
+	WsAckLatency    = "openim.sdk.ws.ack.latency"
+	SyncDuration    = "openim.sdk.sync.duration"
+	DBQueryDuration = "openim.sdk.db.query.duration"
+	WsReconnects    = "openim.sdk.ws.reconnects"
+)
+
+const (
+	defaultServiceName    = "openim-sdk"
+	defaultExportInterval = time.Minute
+	// exportTimeout bounds an export, and the last one when the telemetry is stopped
+	exportTimeout = 10 * time.Second
+	// maxSeries bounds the series of the attributes of an instrument kept between two exports
+	maxSeries = 256
+)
+
+var (
+	descriptions = map[string]string{
+		MsgSendLatency:  "Time from sending a message to the response of the server",
+		WsAckLatency:    "Time from writing a request to the long connection to its response",
+		SyncDuration:    "Duration of a sync of the messages",
+		DBQueryDuration: "Duration of a statement of the local database",
+		WsReconnects:    "Reconnections of the long connection",
+	}
+	// bounds are the buckets of the histograms in milliseconds
+	bounds = []float64{5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000, 30000}
+)
+
+// Attr is an attribute of a metric or a span, its value is a string, an int, an int64 or a bool.
+type Attr struct {
+	Key   string
+	Value any
+}
+
+func String(key, value string) Attr { return Attr{Key: key, Value: value} }
+
+func Int(key string, value int) Attr { return Attr{Key: key, Value: int64(value)} }
+
+func Bool(key string, value bool) Attr { return Attr{Key: key, Value: value} }
+
+type histogram struct {
+	attrs   []Attr
+	count   uint64
+	sum     float64
+	min     float64
+	max     float64
+	buckets []uint64
+}
+
+type counter struct {
+	attrs []Attr
+	value int64
+}
+
+type exporter struct {
+	endpoint string
+	headers  map[string]string
+	ratio    float64
+	resource []Attr
+	client   *http.Client
+
+	lock       sync.Mutex
+	start      time.Time
+	histograms map[string]map[string]*histogram
+	counters   map[string]map[string]*counter
+	spans      []*Span
+	dropped    int
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+var (
+	// lock serializes the configurations
+	lock    sync.Mutex
+	current atomic.Pointer[exporter]
+)
+
+// Enabled tells whether the telemetry is configured, the callers skip measuring while it is not.
+func Enabled() bool {
+	return current.Load() != nil
+}
+
+// Check validates the config without applying it.
+func Check(config *sdk_struct.TelemetryConfig) error {
+	if config == nil {
+		return nil
+	}
+	u, err := url.Parse(config.Endpoint)
+	if err != nil {
+		return sdkerrs.ErrArgs.WrapMsg("invalid telemetry endpoint " + err.Error())
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return sdkerrs.ErrArgs.WrapMsg("unsupported telemetry endpoint " + config.Endpoint)
+	}
+	if config.ExportInterval < 0 {
+		return sdkerrs.ErrArgs.WrapMsg("invalid telemetry export interval " + strconv.FormatInt(config.ExportInterval, 10))
+	}
+	if config.TraceSampleRatio < 0 || config.TraceSampleRatio > 1 {
+		return sdkerrs.ErrArgs.WrapMsg("telemetry trace sample ratio out of 0 to 1")
+	}
+	return nil
+}
+
+// Configure starts exporting to the collector of the config, resource describes the client, e.g. its platform.
+// A nil config stops the telemetry, what is recorded and not exported yet is exported before.
+func Configure(config *sdk_struct.TelemetryConfig, resource ...Attr) error {
+	if err := Check(config); err != nil {
+		return err
+	}
+	lock.Lock()
+	defer lock.Unlock()
+	if e := current.Swap(nil); e != nil {
+		e.stop()
+	}
+	if config == nil {
+		return nil
+	}
+	serviceName := config.ServiceName
+	if serviceName == "" {
+		serviceName = defaultServiceName
+	}
+	interval := time.Duration(config.ExportInterval) * time.Millisecond
+	if interval == 0 {
+		interval = defaultExportInterval
+	}
+	e := newExporter(config, append([]Attr{String("service.name", serviceName), String("service.version", version.Version)}, resource...))
+	ctx, cancel := context.WithCancel(context.Background())
+	e.cancel = cancel
+	e.done = make(chan struct{})
+	go e.loop(ctx, interval)
+	current.Store(e)
+	return nil
+}
+
+func newExporter(config *sdk_struct.TelemetryConfig, resource []Attr) *exporter {
+	return &exporter{
+		endpoint:   strings.TrimSuffix(config.Endpoint, "/"),
+		headers:    config.Headers,
+		ratio:      config.TraceSampleRatio,
+		resource:   resource,
+		client:     &http.Client{Timeout: exportTimeout},
+		start:      time.Now(),
+		histograms: make(map[string]map[string]*histogram),
+		counters:   make(map[string]map[string]*counter),
+	}
+}
+
+func (e *exporter) loop(ctx context.Context, interval time.Duration) {
+	defer close(e.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			e.export(ctx)
+		}
+	}
+}
+
+// stop ends the loop and exports what is left.
+func (e *exporter) stop() {
+	e.cancel()
+	<-e.done
+	ctx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+	defer cancel()
+	e.export(ctx)
+}
+
+func (e *exporter) export(ctx context.Context) {
+	metrics, spans, dropped := e.collect(time.Now())
+	if dropped > 0 {
+		log.ZWarn(ctx, "telemetry spans dropped", nil, "dropped", dropped, "max", maxSpans)
+	}
+	if metrics != nil {
+		if err := e.post(ctx, "/v1/metrics", metrics); err != nil {
+			log.ZWarn(ctx, "export telemetry metrics failed", err, "endpoint", e.endpoint)
+		}
+	}
+	if spans != nil {
+		if err := e.post(ctx, "/v1/traces", spans); err != nil {
+			log.ZWarn(ctx, "export telemetry spans failed", err, "endpoint", e.endpoint)
+		}
+	}
+}
+
+// Record adds the milliseconds to the histogram of the instrument.
+func Record(name string, ms float64, attrs ...Attr) {
+	e := current.Load()
+	if e == nil {
+		return
+	}
+	key := attrsKey(attrs)
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	series, ok := e.histograms[name]
+	if !ok {
+		series = make(map[string]*histogram)
+		e.histograms[name] = series
+	}
+	h, ok := series[key]
+	if !ok {
+		if len(series) >= maxSeries {
+			return
+		}
+		h = &histogram{attrs: attrs, min: ms, max: ms, buckets: make([]uint64, len(bounds)+1)}
+		series[key] = h
+	}
+	h.count++
+	h.sum += ms
+	h.min = min(h.min, ms)
+	h.max = max(h.max, ms)
+	h.buckets[sort.SearchFloat64s(bounds, ms)]++
+}
+
+// Add adds the delta to the counter of the instrument.
+func Add(name string, delta int64, attrs ...Attr) {
+	e := current.Load()
+	if e == nil {
+		return
+	}
+	key := attrsKey(attrs)
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	series, ok := e.counters[name]
+	if !ok {
+		series = make(map[string]*counter)
+		e.counters[name] = series
+	}
+	c, ok := series[key]
+	if !ok {
+		if len(series) >= maxSeries {
+			return
+		}
+		c = &counter{attrs: attrs}
+		series[key] = c
+	}
+	c.value += delta
+}
+
+// Since is the milliseconds elapsed since start, the value the histograms record.
+func Since(start time.Time) float64 {
+	return float64(time.Since(start).Microseconds()) / 1000
+}
+
+func attrsKey(attrs []Attr) string {
+	keys := make([]string, 0, len(attrs))
+	for _, attr := range attrs {
+		keys = append(keys, attr.Key+"="+valueString(attr.Value))
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ",")
+}
+
+func valueString(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return ""
+	}
+}
@@ -16,6 +16,7 @@ package utils
 
 import (
 	"io"
+	"net/url"
 	"os"
 	"path"
 )
@@ -46,6 +47,16 @@ func FileTmpPath(fullPath, dbPrefix string) string {
 	return dbPrefix + Md5(fullPath) + suffix //a->b
 }
 
+// URLFileName is the name of the file the url is saved to when its download has no file path, the name of the
+// other copies of the media, the md5 of their source.
+func URLFileName(rawURL string) string {
+	ext := ""
+	if u, err := url.Parse(rawURL); err == nil {
+		ext = path.Ext(u.Path)
+	}
+	return Md5(rawURL) + ext
+}
+
 func FileExist(filename string) bool {
 	_, err := os.Stat(filename)
 	return err == nil || os.IsExist(err)
@@ -0,0 +1,38 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// serverTimeOffset is how many milliseconds the server clock is ahead of the local one.
+var serverTimeOffset atomic.Int64
+
+func SetServerTimeOffset(offset int64) {
+	serverTimeOffset.Store(offset)
+}
+
+func GetServerTimeOffset() int64 {
+	return serverTimeOffset.Load()
+}
+
+// GetServerTimestampByMill is the current time of the server clock, the local time corrected by the offset
+// sampled from the server, so that the timestamps of a device with a wrong clock are right. It is the local
+// time until the first sample.
+func GetServerTimestampByMill() int64 {
+	return time.Now().UnixMilli() + serverTimeOffset.Load()
+}
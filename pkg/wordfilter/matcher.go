@@ -0,0 +1,170 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package wordfilter finds the words of word lists in texts.
+package wordfilter
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Match is a word found in a text, Start and End are offsets in runes.
+type Match struct {
+	Word  string
+	Start int
+	End   int
+}
+
+type node struct {
+	next map[rune]int
+	fail int
+	// word is the index of the word ending at the node, -1 for none
+	word int
+	// output is the nearest node on the fail chain a word ends at, -1 for none
+	output int
+}
+
+// Matcher finds the words in a text in one pass whatever their number (Aho-Corasick), ignoring the case.
+// It is safe for concurrent use once built.
+type Matcher struct {
+	nodes []node
+	words []string
+	// lengths are the lengths of the words in runes
+	lengths []int
+}
+
+// New builds the matcher of the words, the empty ones and the repeated ones are ignored.
+func New(words []string) *Matcher {
+	m := &Matcher{nodes: []node{{word: -1, output: -1}}}
+	for _, word := range words {
+		m.add(word)
+	}
+	m.link()
+	return m
+}
+
+func (m *Matcher) add(word string) {
+	runes := []rune(strings.ToLower(strings.TrimSpace(word)))
+	if len(runes) == 0 {
+		return
+	}
+	cur := 0
+	for _, r := range runes {
+		next, ok := m.nodes[cur].next[r]
+		if !ok {
+			next = len(m.nodes)
+			m.nodes = append(m.nodes, node{word: -1, output: -1})
+			if m.nodes[cur].next == nil {
+				m.nodes[cur].next = make(map[rune]int)
+			}
+			m.nodes[cur].next[r] = next
+		}
+		cur = next
+	}
+	if m.nodes[cur].word >= 0 {
+		return
+	}
+	m.nodes[cur].word = len(m.words)
+	m.words = append(m.words, word)
+	m.lengths = append(m.lengths, len(runes))
+}
+
+// link sets the fail and output links breadth first, the links of a node go to shallower nodes.
+func (m *Matcher) link() {
+	queue := make([]int, 0, len(m.nodes))
+	for _, child := range m.nodes[0].next {
+		queue = append(queue, child)
+	}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for r, child := range m.nodes[cur].next {
+			fail := m.step(m.nodes[cur].fail, r)
+			m.nodes[child].fail = fail
+			if m.nodes[fail].word >= 0 {
+				m.nodes[child].output = fail
+			} else {
+				m.nodes[child].output = m.nodes[fail].output
+			}
+			queue = append(queue, child)
+		}
+	}
+}
+
+// step is the node reached from the node on the rune, following the fail links.
+func (m *Matcher) step(cur int, r rune) int {
+	for {
+		if next, ok := m.nodes[cur].next[r]; ok {
+			return next
+		}
+		if cur == 0 {
+			return 0
+		}
+		cur = m.nodes[cur].fail
+	}
+}
+
+// Empty reports whether the matcher has no word.
+func (m *Matcher) Empty() bool {
+	return m == nil || len(m.words) == 0
+}
+
+// Find returns the words in the text, overlapping ones included, in the order they end.
+func (m *Matcher) Find(text string) []Match {
+	if m.Empty() {
+		return nil
+	}
+	var matches []Match
+	cur, i := 0, 0
+	for _, r := range text {
+		cur = m.step(cur, unicode.ToLower(r))
+		i++
+		for out := cur; out >= 0; out = m.nodes[out].output {
+			if w := m.nodes[out].word; w >= 0 {
+				matches = append(matches, Match{Word: m.words[w], Start: i - m.lengths[w], End: i})
+			}
+		}
+	}
+	return matches
+}
+
+// Words returns the distinct words in the text.
+func (m *Matcher) Words(text string) []string {
+	var words []string
+	seen := make(map[string]struct{})
+	for _, match := range m.Find(text) {
+		if _, ok := seen[match.Word]; ok {
+			continue
+		}
+		seen[match.Word] = struct{}{}
+		words = append(words, match.Word)
+	}
+	return words
+}
+
+// Mask replaces each rune of the words in the text with the mask, it returns the text and whether it changed.
+func (m *Matcher) Mask(text string, mask rune) (string, bool) {
+	matches := m.Find(text)
+	if len(matches) == 0 {
+		return text, false
+	}
+	runes := []rune(text)
+	for _, match := range matches {
+		for i := match.Start; i < match.End; i++ {
+			runes[i] = mask
+		}
+	}
+	return string(runes), true
+}
@@ -0,0 +1,32 @@
+package wordfilter
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFind(t *testing.T) {
+	m := New([]string{"he", "she", "his", "hers", "", "she"})
+	matches := m.Find("uSHErs")
+	want := []Match{{Word: "she", Start: 1, End: 4}, {Word: "he", Start: 2, End: 4}, {Word: "hers", Start: 2, End: 6}}
+	if !reflect.DeepEqual(matches, want) {
+		t.Fatalf("Find = %v, want %v", matches, want)
+	}
+	if words := m.Words("he said she"); !reflect.DeepEqual(words, []string{"he", "she"}) {
+		t.Fatalf("Words = %v", words)
+	}
+}
+
+func TestMask(t *testing.T) {
+	m := New([]string{"坏蛋", "bad"})
+	masked, ok := m.Mask("你这个坏蛋, BAD!", '*')
+	if !ok || masked != "你这个**, ***!" {
+		t.Fatalf("Mask = %q, %v", masked, ok)
+	}
+	if _, ok := m.Mask("good", '*'); ok {
+		t.Fatal("masked a text without the words")
+	}
+	if New(nil).Find("bad") != nil {
+		t.Fatal("empty matcher found a word")
+	}
+}
@@ -66,6 +66,8 @@ type PictureBaseInfo struct {
 	Width  int32  `json:"width"`
 	Height int32  `json:"height"`
 	Url    string `json:"url,omitempty"`
+	// Md5 of the content, the downloads of the url are verified with it
+	Md5 string `json:"md5,omitempty"`
 }
 type SoundBaseInfo struct {
 	UUID      string `json:"uuid,omitempty"`
@@ -74,6 +76,7 @@ type SoundBaseInfo struct {
 	DataSize  int64  `json:"dataSize"`
 	Duration  int64  `json:"duration"`
 	SoundType string `json:"soundType,omitempty"`
+	Md5       string `json:"md5,omitempty"`
 }
 type VideoBaseInfo struct {
 	VideoPath      string `json:"videoPath,omitempty"`
@@ -89,6 +92,8 @@ type VideoBaseInfo struct {
 	SnapshotWidth  int32  `json:"snapshotWidth"`
 	SnapshotHeight int32  `json:"snapshotHeight"`
 	SnapshotType   string `json:"snapshotType,omitempty"`
+	VideoMd5       string `json:"videoMd5,omitempty"`
+	SnapshotMd5    string `json:"snapshotMd5,omitempty"`
 }
 type FileBaseInfo struct {
 	FilePath  string `json:"filePath,omitempty"`
@@ -97,6 +102,7 @@ type FileBaseInfo struct {
 	FileName  string `json:"fileName,omitempty"`
 	FileSize  int64  `json:"fileSize"`
 	FileType  string `json:"fileType,omitempty"`
+	FileMd5   string `json:"fileMd5,omitempty"`
 }
 
 type TextElem struct {
@@ -124,6 +130,7 @@ type SoundElem struct {
 	DataSize  int64  `json:"dataSize"`
 	Duration  int64  `json:"duration"`
 	SoundType string `json:"soundType,omitempty"`
+	Md5       string `json:"md5,omitempty"`
 }
 
 type VideoElem struct {
@@ -140,6 +147,8 @@ type VideoElem struct {
 	SnapshotWidth  int32  `json:"snapshotWidth"`
 	SnapshotHeight int32  `json:"snapshotHeight"`
 	SnapshotType   string `json:"snapshotType,omitempty"`
+	VideoMd5       string `json:"videoMd5,omitempty"`
+	SnapshotMd5    string `json:"snapshotMd5,omitempty"`
 }
 
 type FileElem struct {
@@ -149,6 +158,7 @@ type FileElem struct {
 	FileName  string `json:"fileName,omitempty"`
 	FileSize  int64  `json:"fileSize"`
 	FileType  string `json:"fileType,omitempty"`
+	FileMd5   string `json:"fileMd5,omitempty"`
 }
 
 type MergeElem struct {
@@ -191,6 +201,9 @@ type QuoteElem struct {
 
 type NotificationElem struct {
 	Detail string `json:"detail,omitempty"`
+	// Tips is the text shown for the notification in the locale of the config, for the revokes and the group
+	// notifications
+	Tips string `json:"tips,omitempty"`
 }
 
 type AdvancedTextElem struct {
@@ -203,44 +216,47 @@ type TypingElem struct {
 }
 
 type MsgStruct struct {
-	ClientMsgID      string                 `json:"clientMsgID,omitempty"`
-	ServerMsgID      string                 `json:"serverMsgID,omitempty"`
-	CreateTime       int64                  `json:"createTime"`
-	SendTime         int64                  `json:"sendTime"`
-	SessionType      int32                  `json:"sessionType"`
-	SendID           string                 `json:"sendID,omitempty"`
-	RecvID           string                 `json:"recvID,omitempty"`
-	MsgFrom          int32                  `json:"msgFrom"`
-	ContentType      int32                  `json:"contentType"`
-	SenderPlatformID int32                  `json:"senderPlatformID"`
-	SenderNickname   string                 `json:"senderNickname,omitempty"`
-	SenderFaceURL    string                 `json:"senderFaceUrl,omitempty"`
-	GroupID          string                 `json:"groupID,omitempty"`
-	Content          string                 `json:"content,omitempty"`
-	Seq              int64                  `json:"seq"`
-	IsRead           bool                   `json:"isRead"`
-	Status           int32                  `json:"status"`
-	OfflinePush      *sdkws.OfflinePushInfo `json:"offlinePush,omitempty"`
-	AttachedInfo     string                 `json:"attachedInfo,omitempty"`
-	Ex               string                 `json:"ex,omitempty"`
-	LocalEx          string                 `json:"localEx,omitempty"`
-	TextElem         *TextElem              `json:"textElem,omitempty"`
-	CardElem         *CardElem              `json:"cardElem,omitempty"`
-	PictureElem      *PictureElem           `json:"pictureElem,omitempty"`
-	SoundElem        *SoundElem             `json:"soundElem,omitempty"`
-	VideoElem        *VideoElem             `json:"videoElem,omitempty"`
-	FileElem         *FileElem              `json:"fileElem,omitempty"`
-	MergeElem        *MergeElem             `json:"mergeElem,omitempty"`
-	AtTextElem       *AtTextElem            `json:"atTextElem,omitempty"`
-	FaceElem         *FaceElem              `json:"faceElem,omitempty"`
-	LocationElem     *LocationElem          `json:"locationElem,omitempty"`
-	CustomElem       *CustomElem            `json:"customElem,omitempty"`
-	QuoteElem        *QuoteElem             `json:"quoteElem,omitempty"`
-	NotificationElem *NotificationElem      `json:"notificationElem,omitempty"`
-	AdvancedTextElem *AdvancedTextElem      `json:"advancedTextElem,omitempty"`
-	TypingElem       *TypingElem            `json:"typingElem,omitempty"`
-	AttachedInfoElem *AttachedInfoElem      `json:"attachedInfoElem,omitempty"`
-	MarkdownTextElem *MarkdownTextElem      `json:"markdownTextElem,omitempty"`
+	ClientMsgID            string                  `json:"clientMsgID,omitempty"`
+	ServerMsgID            string                  `json:"serverMsgID,omitempty"`
+	CreateTime             int64                   `json:"createTime"`
+	SendTime               int64                   `json:"sendTime"`
+	SessionType            int32                   `json:"sessionType"`
+	SendID                 string                  `json:"sendID,omitempty"`
+	RecvID                 string                  `json:"recvID,omitempty"`
+	MsgFrom                int32                   `json:"msgFrom"`
+	ContentType            int32                   `json:"contentType"`
+	SenderPlatformID       int32                   `json:"senderPlatformID"`
+	SenderNickname         string                  `json:"senderNickname,omitempty"`
+	SenderFaceURL          string                  `json:"senderFaceUrl,omitempty"`
+	GroupID                string                  `json:"groupID,omitempty"`
+	Content                string                  `json:"content,omitempty"`
+	Seq                    int64                   `json:"seq"`
+	IsRead                 bool                    `json:"isRead"`
+	Status                 int32                   `json:"status"`
+	OfflinePush            *sdkws.OfflinePushInfo  `json:"offlinePush,omitempty"`
+	AttachedInfo           string                  `json:"attachedInfo,omitempty"`
+	Ex                     string                  `json:"ex,omitempty"`
+	LocalEx                string                  `json:"localEx,omitempty"`
+	TextElem               *TextElem               `json:"textElem,omitempty"`
+	CardElem               *CardElem               `json:"cardElem,omitempty"`
+	PictureElem            *PictureElem            `json:"pictureElem,omitempty"`
+	SoundElem              *SoundElem              `json:"soundElem,omitempty"`
+	VideoElem              *VideoElem              `json:"videoElem,omitempty"`
+	FileElem               *FileElem               `json:"fileElem,omitempty"`
+	MergeElem              *MergeElem              `json:"mergeElem,omitempty"`
+	AtTextElem             *AtTextElem             `json:"atTextElem,omitempty"`
+	FaceElem               *FaceElem               `json:"faceElem,omitempty"`
+	LocationElem           *LocationElem           `json:"locationElem,omitempty"`
+	CustomElem             *CustomElem             `json:"customElem,omitempty"`
+	QuoteElem              *QuoteElem              `json:"quoteElem,omitempty"`
+	NotificationElem       *NotificationElem       `json:"notificationElem,omitempty"`
+	AdvancedTextElem       *AdvancedTextElem       `json:"advancedTextElem,omitempty"`
+	TypingElem             *TypingElem             `json:"typingElem,omitempty"`
+	AttachedInfoElem       *AttachedInfoElem       `json:"attachedInfoElem,omitempty"`
+	MarkdownTextElem       *MarkdownTextElem       `json:"markdownTextElem,omitempty"`
+	CallElem               *CallElem               `json:"callElem,omitempty"`
+	InteractiveCardElem    *InteractiveCardElem    `json:"interactiveCardElem,omitempty"`
+	SatisfactionRatingElem *SatisfactionRatingElem `json:"satisfactionRatingElem,omitempty"`
 }
 
 type AtInfo struct {
@@ -257,6 +273,49 @@ type AttachedInfoElem struct {
 	InEncryptStatus   bool             `json:"inEncryptStatus"`
 	//MessageReactionElem       []*ReactionElem  `json:"messageReactionElem,omitempty"`
 	Progress *UploadProgress `json:"uploadProgress,omitempty"`
+	// Signature is the signature of the message by the device of the sender, nil when it is not signed
+	Signature *MsgSignature `json:"signature,omitempty"`
+	// SignatureStatus is the verification of Signature on the receiving device, see constant.MsgSignature*
+	SignatureStatus int32 `json:"signatureStatus,omitempty"`
+	// SensitiveWords are the sensitive words of the message sent in the warn mode
+	SensitiveWords []string `json:"sensitiveWords,omitempty"`
+	// Restrictions are the restrictions the sender set on the message
+	Restrictions *MsgRestrictions `json:"restrictions,omitempty"`
+	// Viewed is a view-once message received that was opened, its media are gone
+	Viewed bool `json:"viewed,omitempty"`
+	// Moderation is the verdict of the moderator on the message when it did not pass it
+	Moderation *MsgModeration `json:"moderation,omitempty"`
+}
+
+// ModerationVerdict is what the moderator of the app decided for a message, see constant.Moderation*.
+type ModerationVerdict struct {
+	Action string `json:"action"`
+	// Message is the message replacing the one moderated for the modify action
+	Message *MsgStruct `json:"message,omitempty"`
+	Reason  string     `json:"reason,omitempty"`
+	Labels  []string   `json:"labels,omitempty"`
+}
+
+// MsgModeration is the verdict of the moderator kept on a message.
+type MsgModeration struct {
+	Action string   `json:"action"`
+	Reason string   `json:"reason,omitempty"`
+	Labels []string `json:"labels,omitempty"`
+}
+
+// MsgRestrictions are the restrictions of a message the clients enforce.
+type MsgRestrictions struct {
+	// NoForward fails the forward and the merge of the message
+	NoForward bool `json:"noForward,omitempty"`
+	// NoCopy asks the app not to let its content be copied
+	NoCopy bool `json:"noCopy,omitempty"`
+	// ViewOnce is a picture or a video the receivers open once with OpenViewOnceMessage, it can not be forwarded
+	ViewOnce bool `json:"viewOnce,omitempty"`
+}
+
+type MsgSignature struct {
+	DeviceID  string `json:"deviceID"`
+	Signature []byte `json:"signature"`
 }
 
 type UploadProgress struct {
@@ -264,6 +323,21 @@ type UploadProgress struct {
 	Save     int64  `json:"save"`
 	Current  int64  `json:"current"`
 	UploadID string `json:"uploadID"`
+	// Speed is the bytes per second the file is uploaded at, RemainingTime the milliseconds left, -1 if unknown
+	Speed         int64 `json:"speed"`
+	RemainingTime int64 `json:"remainingTime"`
+}
+
+// TransferSpeed is the speed of an upload or a download, measured the same for every client.
+type TransferSpeed struct {
+	Transferred int64 `json:"transferred"`
+	// Total is 0 while the size of the file is unknown
+	Total int64 `json:"total"`
+	// Speed is the bytes per second of the last seconds, AverageSpeed the one since the transfer started
+	Speed        int64 `json:"speed"`
+	AverageSpeed int64 `json:"averageSpeed"`
+	// RemainingTime is the milliseconds the transfer is estimated to take yet, -1 if unknown
+	RemainingTime int64 `json:"remainingTime"`
 }
 
 type ReactionElem struct {
@@ -317,9 +391,353 @@ type IMConfig struct {
 	IsLogStandardOutput bool   `json:"isLogStandardOutput"`
 	LogFilePath         string `json:"logFilePath"`
 	LogRemainCount      uint32 `json:"logRemainCount"`
+	// LogPrivacy
+	// Hash the user ids and hide the content of the messages and the profiles in the logs, so that they can be
+	// shared with support.
+	LogPrivacy bool `json:"logPrivacy"`
+	// LogMaxSize
+	// Megabytes a log file is rotated and compressed at, 20 by default. The files are also rotated every day.
+	LogMaxSize uint32 `json:"logMaxSize"`
+	// LogMaxAge
+	// Days the log files are kept for, LogRemainCount when 0.
+	LogMaxAge uint32 `json:"logMaxAge"`
+	// LogModuleLevels
+	// Levels of the modules sync, db, ws and conversation, which otherwise follow LogLevel. Can be changed at
+	// runtime by SetLogLevel.
+	LogModuleLevels map[string]uint32 `json:"logModuleLevels"`
 	// StopGoroutineOnBackground
 	// Whether to automatically stop goroutines in the background to prevent iOS watchdog issues
 	StopGoroutineOnBackground bool `json:"stopGoroutineOnBackground"`
+	// LastSeenPrecision
+	// Ascending buckets in seconds used to round the last seen time, e.g. [60, 300, 3600].
+	LastSeenPrecision []int64 `json:"lastSeenPrecision"`
+	// TokenRefreshAdvance
+	// Seconds before the token expires to call OnTokenWillExpire, 300 by default.
+	TokenRefreshAdvance int64 `json:"tokenRefreshAdvance"`
+	// GuestSendLimit
+	// Max number of messages a guest can send per minute when the server does not set one, 10 by default.
+	GuestSendLimit int32 `json:"guestSendLimit"`
+	// Proxy
+	// Proxy used by the api requests and the long connection, can be changed after login by SetProxy.
+	Proxy *ProxyConfig `json:"proxy"`
+	// CertificatePins
+	// Public keys the certificates of the servers must have, for the api requests and the long connection. The
+	// handshake with a server none of whose certificates has one fails. UpdateConfig rotates them.
+	CertificatePins *CertificatePinning `json:"certificatePins"`
+	// Transport
+	// Transport of the long connection, websocket by default, quic or longpolling. The quic transport reconnects with
+	// 0-RTT and keeps the connection across network changes, it needs a build with the quic tag and is not
+	// available in the browser.
+	Transport string `json:"transport"`
+	// QuicAddr
+	// Address of the quic endpoint, e.g. quic://127.0.0.1:10001/, the host of WsAddr is used when empty.
+	QuicAddr string `json:"quicAddr"`
+	// LongPollingFallback
+	// Fall back to http long polling on the ws address when websocket is blocked, and switch back to
+	// websocket when it works again. Transport longpolling always uses long polling. Both need a server with the
+	// long polling endpoints, websocket is used when the server answers it has none.
+	LongPollingFallback bool `json:"longPollingFallback"`
+	// Compression
+	// Compression of the long connection, gzip by default, deflate for websocket permessage-deflate, or none.
+	// The api responses are gzip compressed unless none.
+	Compression string `json:"compression"`
+	// ReconnectInitialDelay
+	// Milliseconds before the first reconnection attempt, 1000 by default.
+	ReconnectInitialDelay int64 `json:"reconnectInitialDelay"`
+	// ReconnectMultiplier
+	// Factor the delay grows by after each failed attempt, 2 by default.
+	ReconnectMultiplier float64 `json:"reconnectMultiplier"`
+	// ReconnectMaxDelay
+	// The longest delay between two attempts in milliseconds, 16000 by default.
+	ReconnectMaxDelay int64 `json:"reconnectMaxDelay"`
+	// ReconnectJitter
+	// Fraction of the delay randomly added or removed, from 0 to 1, 0 by default.
+	ReconnectJitter float64 `json:"reconnectJitter"`
+	// ReconnectMaxAttempts
+	// Failed attempts in a row after which the connection state is failed and the SDK stops reconnecting
+	// until ForceReconnect, 0 reconnects forever.
+	ReconnectMaxAttempts int `json:"reconnectMaxAttempts"`
+	// BandwidthLimit
+	// Bytes per second the SDK may transfer, can be changed at runtime by SetBandwidthLimit.
+	BandwidthLimit *BandwidthLimit `json:"bandwidthLimit"`
+	// AutoReportBadge
+	// Report the total unread count as the app badge of the offline pushes whenever it changes, instead of
+	// the app calling SetAppBadge.
+	AutoReportBadge bool `json:"autoReportBadge"`
+	// RequestPolicies
+	// Timeout and retries of the api calls by class: send, query, history and upload. The classes missing
+	// keep their defaults, 10 seconds for send and query, 30 seconds for history and none for upload.
+	RequestPolicies map[string]*RequestPolicy `json:"requestPolicies"`
+	// AllowOnMetered
+	// Transfers not deferred on a cellular or metered network: backfill and mediaPrefetch. The others wait
+	// for wifi or for AllowMeteredTransfer.
+	AllowOnMetered []string `json:"allowOnMetered"`
+	// ConflictPolicies
+	// Side kept by kind of conflict between the local and the server state, server or local. The kinds
+	// missing keep the server state, a conflict resolver set by SetConflictResolver decides first.
+	ConflictPolicies map[string]string `json:"conflictPolicies"`
+	// MsgSyncWorkers
+	// Number of message batches the backfill of the conversations pulls at the same time, 4 by default.
+	MsgSyncWorkers int `json:"msgSyncWorkers"`
+	// MsgWriteBatchSize
+	// Number of synced messages written to the local database per transaction, 500 by default.
+	MsgWriteBatchSize int `json:"msgWriteBatchSize"`
+	// DownloadConcurrency
+	// Number of downloads of the download manager at the same time, 3 by default.
+	DownloadConcurrency int `json:"downloadConcurrency"`
+	// UploadParallelism
+	// Number of parts of a file uploaded at the same time, 3 by default. A failed part is retried on its own.
+	UploadParallelism int `json:"uploadParallelism"`
+	// DBAutoCompactRatio
+	// Share of unused pages of the local database over which it is compacted while the app is in background,
+	// between 0 and 1. 0 disables the compaction in background, CompactDatabase still compacts it.
+	DBAutoCompactRatio float64 `json:"dbAutoCompactRatio"`
+	// ArchiveMessagesAfterDays
+	// Age in days over which the messages are moved to the archive database while the app is in background,
+	// ArchiveMessages still archives them when 0.
+	ArchiveMessagesAfterDays int `json:"archiveMessagesAfterDays"`
+	// InMemoryDB
+	// Keep the local database in memory instead of DataDir, for the tests and the sessions that leave no
+	// data on the device. Everything is synced again at each login and dropped at logout.
+	InMemoryDB bool `json:"inMemoryDB"`
+	// DBDir
+	// Directory of the local database, DataDir when empty. E.g. the shared container of an iOS app group.
+	DBDir string `json:"dbDir"`
+	// DBFileName
+	// Name of the database file of a user in DBDir, {userID} is replaced by the user ID.
+	// OpenIM_<version>_{userID}.db when empty.
+	DBFileName string `json:"dbFileName"`
+	// DBPragmas
+	// Pragmas set on each connection of the local database, the sqlite defaults are kept for the ones not set.
+	DBPragmas *DBPragmas `json:"dbPragmas"`
+	// StorageQuota
+	// Bytes the local database and the media files in DataDir may take, 0 for no quota. Beyond it the oldest
+	// media files are removed, except the ones of pinned conversations and pinned friends.
+	StorageQuota int64 `json:"storageQuota"`
+	// StorageEvictMessages
+	// Also remove the oldest messages of the muted conversations when removing the media is not enough to fit
+	// the quota. The messages stay on the server and are pulled again when scrolled to.
+	StorageEvictMessages bool `json:"storageEvictMessages"`
+	// MediaCacheLimit
+	// Bytes the media files in DataDir may take, 0 for no limit. Beyond it the least recently used files are
+	// removed, except the ones of pinned conversations, pinned friends and messages pinned by PinMessageMedia.
+	MediaCacheLimit int64 `json:"mediaCacheLimit"`
+	// StripImageMetadata
+	// Remove the exif, xmp and text metadata of the jpeg and png pictures sent, e.g. the GPS location, before
+	// uploading them. SendMessageWithOptions overrides it per message.
+	StripImageMetadata bool `json:"stripImageMetadata"`
+	// SignMessages
+	// Sign the messages sent with the e2ee identity of the device once EnableE2EE was called, the receivers
+	// verify the signatures and keep the result in the signatureStatus of the attached info.
+	SignMessages bool `json:"signMessages"`
+	// SensitiveWordMode
+	// What is done with a message sent with a word of the lists set by SetSensitiveWordList: block, replace or
+	// warn, see constant.SensitiveWord*. Empty to send the messages unchecked.
+	SensitiveWordMode string `json:"sensitiveWordMode"`
+	// SensitiveWordMask
+	// The character replacing each character of the sensitive words, * by default.
+	SensitiveWordMask string `json:"sensitiveWordMask"`
+	// FilterReceivedSensitiveWords
+	// Mask the sensitive words of the messages received too, before they are stored and shown.
+	FilterReceivedSensitiveWords bool `json:"filterReceivedSensitiveWords"`
+	// ModerateReceivedMessages
+	// Have the moderator set by SetModerator check the messages received from the other users too, before they
+	// are stored and shown.
+	ModerateReceivedMessages bool `json:"moderateReceivedMessages"`
+	// Locale
+	// The locale of the texts the sdk generates for display, e.g. the tips of the revokes and of the group
+	// notifications: en by default, zh is built in too. See SetLocalizedTemplates to override them.
+	Locale string `json:"locale"`
+	// ImageMaxSide
+	// Downscale the pictures sent with a longer side to it before uploading them, 0 to keep their size.
+	ImageMaxSide int `json:"imageMaxSide"`
+	// ImageQuality
+	// The jpeg quality, 1 to 100, of the pictures and thumbnails encoded by the SDK, 0 for 85.
+	ImageQuality int `json:"imageQuality"`
+	// ImageMaxBytes
+	// Lower the quality, then the size, of the pictures sent until they fit in it, 0 for no target.
+	ImageMaxBytes int64 `json:"imageMaxBytes"`
+	// ThumbnailMaxSide
+	// Generate and upload the snapshot of the pictures sent with this longer side, instead of letting the server
+	// resize them, 0 to leave it to the server.
+	ThumbnailMaxSide int `json:"thumbnailMaxSide"`
+	// VideoMaxSide
+	// The longer side of the videos sent asked to the video transcoder set by SetVideoTranscoder, 0 for any.
+	VideoMaxSide int `json:"videoMaxSide"`
+	// VideoMaxBitrate
+	// The bitrate in kbps of the videos sent asked to the video transcoder, 0 for any.
+	VideoMaxBitrate int `json:"videoMaxBitrate"`
+	// VideoMaxBytes
+	// The size of the videos sent asked to the video transcoder, 0 for any.
+	VideoMaxBytes int64 `json:"videoMaxBytes"`
+	// DBInstrumentation
+	// Record the duration, the rows and the shape of the queries of the local database, see GetDBQueryStats.
+	DBInstrumentation bool `json:"dbInstrumentation"`
+	// DBSlowQueryThreshold
+	// Milliseconds over which a query of the local database is logged as slow once instrumented, 200 by default.
+	DBSlowQueryThreshold int64 `json:"dbSlowQueryThreshold"`
+	// ApiTransport
+	// Transport of the api calls, http by default or grpc. With grpc the server is asked at init whether it
+	// serves grpc on GrpcAddr, the calls use http until it answers and for the methods it does not serve.
+	ApiTransport string `json:"apiTransport"`
+	// GrpcAddr
+	// Address of the grpc endpoint, grpc://host:port or grpcs://host:port for tls.
+	GrpcAddr string `json:"grpcAddr"`
+	// HeartbeatInterval
+	// Seconds between the pings of the long connection, 24 by default.
+	HeartbeatInterval int64 `json:"heartbeatInterval"`
+	// AdaptiveHeartbeat
+	// Lengthen the ping interval up to MaxHeartbeatInterval while the connection is stable, and use
+	// MaxHeartbeatInterval in background.
+	AdaptiveHeartbeat bool `json:"adaptiveHeartbeat"`
+	// MaxHeartbeatInterval
+	// The longest adaptive ping interval in seconds, 4 times HeartbeatInterval by default. The server must
+	// accept a connection silent for this long.
+	MaxHeartbeatInterval int64 `json:"maxHeartbeatInterval"`
+	// DegradedRTT
+	// Milliseconds of round trip time over which the connection is weak and reported degraded, 2000 by default.
+	DegradedRTT int64 `json:"degradedRTT"`
+	// DegradedLossRate
+	// Rate of pings and requests without a response over which the connection is weak, 0.2 by default.
+	DegradedLossRate float64 `json:"degradedLossRate"`
+	// Telemetry
+	// Export the send and ack latencies, the sync and db query durations and the reconnects of the long
+	// connection as metrics and spans to an OTLP collector, nothing is recorded while it is nil.
+	Telemetry *TelemetryConfig `json:"telemetry"`
+	// RuntimeStatsInterval
+	// Seconds between the samples of the Go runtime kept for CollectDiagnostics, 0 takes none.
+	RuntimeStatsInterval int64 `json:"runtimeStatsInterval"`
+	// InfoCacheTTL
+	// Seconds the nicknames and face urls of the users and of the group members are kept in memory after they
+	// were loaded, 600 by default. Their change notifications drop them before.
+	InfoCacheTTL int64 `json:"infoCacheTTL"`
+	// InfoCacheCapacity
+	// Most users and group members kept in memory each, the least recently used are dropped beyond it, 10000
+	// by default.
+	InfoCacheCapacity int `json:"infoCacheCapacity"`
+	// ConversationChangeWindow
+	// Milliseconds the changes of the conversations are held for to call OnConversationChanged once with each
+	// conversation changed, as last changed, e.g. during the sync after a reconnection. 0 calls it for each
+	// change. The other conversation callbacks call it with the changes held first.
+	ConversationChangeWindow int64 `json:"conversationChangeWindow"`
+	// EnableOrganization
+	// Sync the departments of the organization and their members, for the servers serving the organization
+	// directory. The organization functions fail while it is off. It is synced on the first of them called
+	// after the login rather than at the login.
+	EnableOrganization bool `json:"enableOrganization"`
+	// EnableCustomerService
+	// Keep the customer-service sessions of the login user, for the servers routing the customers to agents.
+	// The customer-service functions fail while it is off.
+	EnableCustomerService bool `json:"enableCustomerService"`
+	// EnableAuditLog
+	// Record the security-relevant actions of the login user in the local database: the exports of the local
+	// data, the bulk deletions of messages, the edits of the group admins and the kicks offline.
+	EnableAuditLog bool `json:"enableAuditLog"`
+	// UploadAuditLog
+	// Upload the audit log to the server as well, with EnableAuditLog.
+	UploadAuditLog bool `json:"uploadAuditLog"`
+	// FaultInjection
+	// For testing only: drop, duplicate and delay the frames of the long connection, fail the writes of the
+	// local database and reconnect on schedule, to check how the SDK recovers. Nothing is injected while it is
+	// nil.
+	FaultInjection *FaultInjection `json:"faultInjection"`
+}
+
+// ConfigUpdate The fields UpdateConfig changed, Reconnect the ones applied by opening the long connection again.
+type ConfigUpdate struct {
+	Applied   []string `json:"applied"`
+	Reconnect []string `json:"reconnect"`
+}
+
+// ConfigFieldError Field is the json name of the field of IMConfig.
+type ConfigFieldError struct {
+	Field  string `json:"field"`
+	Reason string `json:"reason"`
+}
+
+// ConfigValidation The config is valid when Errors is empty, UnknownFields are ignored by InitSDK.
+type ConfigValidation struct {
+	Errors        []*ConfigFieldError `json:"errors"`
+	UnknownFields []string            `json:"unknownFields"`
+}
+
+// EffectiveConfig Config holds the defaults of the fields left empty, the ones listed in Defaulted.
+type EffectiveConfig struct {
+	Config    *IMConfig `json:"config"`
+	Defaulted []string  `json:"defaulted"`
+}
+
+// TelemetryConfig Endpoint is the base url of an OTLP/HTTP collector, e.g. http://collector:4318, the metrics are
+// posted to /v1/metrics and the spans to /v1/traces in the json encoding.
+type TelemetryConfig struct {
+	Endpoint string `json:"endpoint"`
+	// Headers are added to the export requests, e.g. the api key of the collector.
+	Headers map[string]string `json:"headers"`
+	// ServiceName is the service.name of the resource, openim-sdk by default.
+	ServiceName string `json:"serviceName"`
+	// ExportInterval is the milliseconds between the exports, 60000 by default.
+	ExportInterval int64 `json:"exportInterval"`
+	// TraceSampleRatio is the share of the traces exported from 0 to 1, 0 exports the metrics only.
+	TraceSampleRatio float64 `json:"traceSampleRatio"`
+}
+
+// FaultInjection the rates are the share of the frames or the writes faulted, from 0 to 1. The faults are drawn
+// from Seed, so that a run of the same frames and writes gets the same faults.
+type FaultInjection struct {
+	Seed int64 `json:"seed"`
+	// FrameDropRate, FrameDuplicateRate and FrameDelayRate apply to the frames of the long connection both
+	// sent and received, a frame is dropped, duplicated or delayed by FrameDelay milliseconds.
+	FrameDropRate      float64 `json:"frameDropRate"`
+	FrameDuplicateRate float64 `json:"frameDuplicateRate"`
+	FrameDelayRate     float64 `json:"frameDelayRate"`
+	FrameDelay         int64   `json:"frameDelay"`
+	// DBWriteFailRate applies to the inserts, updates and deletes of the local database, not the one of the
+	// browser.
+	DBWriteFailRate float64 `json:"dbWriteFailRate"`
+	// ReconnectInterval closes the long connection every this many milliseconds, 0 never.
+	ReconnectInterval int64 `json:"reconnectInterval"`
+}
+
+// ProxyConfig URL is used by both the api requests and the long connection unless ApiURL or WsURL is set.
+// Supported schemes are http, https (api only) and socks5, e.g. socks5://127.0.0.1:1080.
+type ProxyConfig struct {
+	URL      string `json:"url"`
+	ApiURL   string `json:"apiURL"`
+	WsURL    string `json:"wsURL"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// CertificatePinning pins are the base64 sha256 of the subject public key info of a certificate of the chain of
+// the server, optionally prefixed with sha256/, e.g. the output of
+// openssl x509 -pubkey -noout | openssl pkey -pubin -outform der | openssl dgst -sha256 -binary | base64.
+type CertificatePinning struct {
+	Pins []string `json:"pins"`
+	// BackupPins are accepted too, the keys the servers rotate to, kept offline until then
+	BackupPins []string `json:"backupPins"`
+	// Hosts the pins apply to, all the hosts connected to when empty
+	Hosts []string `json:"hosts"`
+	// ExpireTime in milliseconds after which the pins are not checked, so that an app not updated in time keeps
+	// connecting once the servers rotated their keys. 0 for never.
+	ExpireTime int64 `json:"expireTime"`
+}
+
+// BandwidthLimit are bytes per second, 0 is unlimited. Global limits the sum of all the traffic, Sync the
+// long connection and the api calls, Upload and Download the file transfers.
+type BandwidthLimit struct {
+	Global   int64 `json:"global"`
+	Sync     int64 `json:"sync"`
+	Upload   int64 `json:"upload"`
+	Download int64 `json:"download"`
+}
+
+// RequestPolicy is how long an attempt of an api call may take and how often it is retried. Only the network
+// errors are retried, after RetryInterval and then twice as long as the previous wait each time.
+type RequestPolicy struct {
+	// Timeout of an attempt in milliseconds, 0 is no timeout
+	Timeout       int64 `json:"timeout"`
+	RetryTimes    int   `json:"retryTimes"`
+	RetryInterval int64 `json:"retryInterval"`
 }
 
 type CmdNewMsgComeToConversation struct {
@@ -338,6 +756,478 @@ type BasicInfo struct {
 	FaceURL  string
 }
 
+type SyncConflict struct {
+	Kind           string `json:"kind"`
+	ConversationID string `json:"conversationID"`
+	ClientMsgID    string `json:"clientMsgID"`
+	// Local and Server are the json of the two states
+	Local  string `json:"local"`
+	Server string `json:"server"`
+	// Resolution is the side kept, server or local, set once the conflict is resolved
+	Resolution string `json:"resolution"`
+}
+
+type SeqGap struct {
+	ConversationID string `json:"conversationID"`
+	BeginSeq       int64  `json:"beginSeq"`
+	EndSeq         int64  `json:"endSeq"`
+	// RepairTimes is how many times the messages of the gap were pulled again
+	RepairTimes int `json:"repairTimes"`
+}
+
+type SeqGapStats struct {
+	// Detected and Repaired count the gaps since login
+	Detected int64 `json:"detected"`
+	Repaired int64 `json:"repaired"`
+	// Pending are the gaps not repaired yet, or given up after too many pulls
+	Pending []*SeqGap `json:"pending"`
+}
+
+type MsgWriteStats struct {
+	// Messages of the sync written since login, into the local database of Conversations in Transactions
+	Messages      int64 `json:"messages"`
+	Conversations int64 `json:"conversations"`
+	Transactions  int64 `json:"transactions"`
+	// Duration is the time spent writing in milliseconds
+	Duration          int64   `json:"duration"`
+	MessagesPerSecond float64 `json:"messagesPerSecond"`
+}
+
+type ConnState struct {
+	State string `json:"state"`
+	// NextAttemptTime is when the next reconnection starts in milliseconds, set in reconnectScheduled
+	NextAttemptTime int64 `json:"nextAttemptTime,omitempty"`
+	// RTT is the last round trip time in milliseconds, set in connected and degraded
+	RTT     int64  `json:"rtt,omitempty"`
+	ErrCode int32  `json:"errCode,omitempty"`
+	ErrMsg  string `json:"errMsg,omitempty"`
+}
+
+// ConnStateChange is a change of the connection state, Time is in milliseconds.
+type ConnStateChange struct {
+	ConnState
+	Time int64 `json:"time"`
+}
+
+type SyncProgress struct {
+	Phase string `json:"phase"`
+	// Done and Total are the items of the phase, Total is 0 while unknown
+	Done  int `json:"done"`
+	Total int `json:"total"`
+	// PhasePercent is the progress of the phase and Percent the one of the whole sync, both from 0 to 100
+	PhasePercent int `json:"phasePercent"`
+	Percent      int `json:"percent"`
+}
+
+type DBMigrationProgress struct {
+	Version int    `json:"version"`
+	Name    string `json:"name"`
+	// Done and Total are the items of the migration, Total is 0 while unknown
+	Done  int `json:"done"`
+	Total int `json:"total"`
+}
+
+// DBCorruptionIncident is a corrupted local database found at login. The database is moved to Quarantine
+// and a new one is filled with the rows still readable, the scopes of the tables not fully salvaged are
+// pulled again from the server.
+type DBCorruptionIncident struct {
+	DBFile     string   `json:"dbFile"`
+	Quarantine string   `json:"quarantine"`
+	Problems   []string `json:"problems"`
+	// Salvaged tables are copied whole, Damaged ones in part and Lost ones not at all
+	Salvaged     []string `json:"salvaged"`
+	Damaged      []string `json:"damaged"`
+	Lost         []string `json:"lost"`
+	ResyncScopes []string `json:"resyncScopes"`
+}
+
+type NetworkQuality struct {
+	// RTT and Jitter are in milliseconds
+	RTT    int64 `json:"rtt"`
+	Jitter int64 `json:"jitter"`
+	// LossRate is the rate of the latest pings and requests without a response in time, from 0 to 1
+	LossRate float64 `json:"lossRate"`
+	// Throughput is the bytes per second transferred by the long connection between the latest pings
+	Throughput int64 `json:"throughput"`
+	Weak       bool  `json:"weak"`
+}
+
+type TrafficStats struct {
+	WsRawBytes   int64 `json:"wsRawBytes"`
+	WsWireBytes  int64 `json:"wsWireBytes"`
+	ApiRawBytes  int64 `json:"apiRawBytes"`
+	ApiWireBytes int64 `json:"apiWireBytes"`
+	BytesSaved   int64 `json:"bytesSaved"`
+}
+
+type ConversationStorageInfo struct {
+	ConversationID string `json:"conversationID"`
+	MessageCount   int64  `json:"messageCount"`
+	// Database is an estimate of the bytes of the messages in the local database
+	Database int64 `json:"database"`
+	// Media is the bytes of the media files of the messages in DataDir
+	Media      int64 `json:"media"`
+	MediaFiles int   `json:"mediaFiles"`
+}
+
+type PurgeStorageOptions struct {
+	// Media removes the media files of the messages, the messages are kept
+	Media bool `json:"media"`
+	// Messages removes the messages from the local database, they stay on the server
+	Messages bool `json:"messages"`
+	// BeforeTime only purges the messages sent before it in milliseconds, 0 for all of them
+	BeforeTime int64 `json:"beforeTime"`
+}
+
+type PurgeStorageResult struct {
+	RemovedMediaFiles int   `json:"removedMediaFiles"`
+	FreedMedia        int64 `json:"freedMedia"`
+	DeletedMessages   int64 `json:"deletedMessages"`
+}
+
+type DBPragmas struct {
+	// JournalMode is DELETE, TRUNCATE, PERSIST, MEMORY, WAL or OFF
+	JournalMode string `json:"journalMode"`
+	// Synchronous is OFF, NORMAL, FULL or EXTRA
+	Synchronous string `json:"synchronous"`
+	// CacheSize is in pages when positive and in KiB when negative, as the cache_size pragma
+	CacheSize int `json:"cacheSize"`
+}
+
+// DownloadReq is a media download queued in the download manager.
+type DownloadReq struct {
+	URL string `json:"url"`
+	// FilePath is where the file is saved, in DataDir named by the md5 of the url when empty
+	FilePath string `json:"filePath"`
+	// Priority orders the queue, the higher first and the same priority in the order queued
+	Priority int `json:"priority"`
+	// Prefetch is a download before the user opens the media, deferred on a metered network
+	Prefetch bool `json:"prefetch"`
+	// Md5 is the one of the elem of the media, the file is verified with it once saved and when already saved
+	Md5 string `json:"md5,omitempty"`
+}
+
+type DownloadInfo struct {
+	ID       string `json:"id"`
+	URL      string `json:"url"`
+	FilePath string `json:"filePath"`
+	Priority int    `json:"priority"`
+	Prefetch bool   `json:"prefetch"`
+	Md5      string `json:"md5,omitempty"`
+	// State is queued, downloading, paused, completed, failed, corrupted or canceled
+	State      string `json:"state"`
+	Downloaded int64  `json:"downloaded"`
+	// Total is 0 while the size of the file is unknown
+	Total int64 `json:"total"`
+	// Speed and AverageSpeed are in bytes per second, RemainingTime in milliseconds, -1 if unknown
+	Speed         int64  `json:"speed"`
+	AverageSpeed  int64  `json:"averageSpeed"`
+	RemainingTime int64  `json:"remainingTime"`
+	Error         string `json:"error,omitempty"`
+}
+
+// DownloadTotalProgress sums the downloads queued, running and paused.
+type DownloadTotalProgress struct {
+	Downloaded int64 `json:"downloaded"`
+	Total      int64 `json:"total"`
+	Active     int   `json:"active"`
+	Queued     int   `json:"queued"`
+	// Speed sums the speeds of the downloads running, RemainingTime is in milliseconds, -1 if unknown
+	Speed         int64 `json:"speed"`
+	RemainingTime int64 `json:"remainingTime"`
+}
+
+// SendMsgOptions are the options of one message sent, IMConfig applies to the ones not set.
+type SendMsgOptions struct {
+	StripImageMetadata *bool `json:"stripImageMetadata,omitempty"`
+	// CompressImage false sends the original picture, ImageMaxSide and ImageMaxBytes aren't applied
+	CompressImage *bool `json:"compressImage,omitempty"`
+	// TranscodeVideo false sends the original video, the video transcoder isn't called
+	TranscodeVideo *bool `json:"transcodeVideo,omitempty"`
+	// Storage puts the files of the message to this bucket instead of the storage of the server
+	Storage *ObjectStorage `json:"storage,omitempty"`
+	// Restrictions the receivers' clients enforce on the message
+	Restrictions *MsgRestrictions `json:"restrictions,omitempty"`
+}
+
+// ObjectStorage is an s3 compatible bucket the files of an upload or a message are put to, with its temporary
+// credentials, instead of the storage of the server.
+type ObjectStorage struct {
+	// Endpoint of the storage, e.g. https://s3.eu-central-1.amazonaws.com
+	Endpoint string `json:"endpoint"`
+	// Region signs the requests, us-east-1 when empty
+	Region          string `json:"region"`
+	Bucket          string `json:"bucket"`
+	AccessKeyID     string `json:"accessKeyID"`
+	SecretAccessKey string `json:"secretAccessKey"`
+	SessionToken    string `json:"sessionToken"`
+	// PathStyle puts the bucket in the path of the url rather than in its host
+	PathStyle bool `json:"pathStyle"`
+	// URL the objects are read from, a CDN in front of the bucket e.g., the endpoint when empty
+	URL string `json:"url"`
+}
+
+// VideoTranscodeConstraints is what the video transcoder is asked for, 0 for no constraint.
+type VideoTranscodeConstraints struct {
+	MaxSide    int   `json:"maxSide"`
+	MaxBitrate int   `json:"maxBitrate"`
+	MaxBytes   int64 `json:"maxBytes"`
+	// OutputPath is where the SDK would have the video written, it is removed once uploaded
+	OutputPath string `json:"outputPath"`
+}
+
+// VideoTranscodeResult is the video sent instead of the original, an empty path sends the original.
+type VideoTranscodeResult struct {
+	Path      string `json:"path"`
+	VideoType string `json:"videoType"`
+	// Duration in seconds, 0 keeps the one of the message
+	Duration int64 `json:"duration"`
+}
+
+// MediaEviction is the media files removed at once, by MediaCacheLimit, StorageQuota or ClearMediaCache.
+type MediaEviction struct {
+	// Reason is limit, quota or clear
+	Reason string          `json:"reason"`
+	Files  []*EvictedMedia `json:"files"`
+}
+
+type EvictedMedia struct {
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+}
+
+type MediaCacheResult struct {
+	RemovedMediaFiles int   `json:"removedMediaFiles"`
+	FreedMedia        int64 `json:"freedMedia"`
+}
+
+// CrashReport is a panic recovered in a goroutine of the SDK, kept until uploaded.
+type CrashReport struct {
+	ID string `json:"id"`
+	// Time is in milliseconds
+	Time      int64  `json:"time"`
+	Goroutine string `json:"goroutine"`
+	Panic     string `json:"panic"`
+	Stack     string `json:"stack"`
+	Version   string `json:"version"`
+	// LogTail is the last lines of the log before the panic
+	LogTail []string       `json:"logTail"`
+	State   map[string]any `json:"state"`
+}
+
+// SdkError is an error of a background task of the SDK, passed to OnSdkError.
+type SdkError struct {
+	// Source is the part of the SDK the error happened in, like sync, notification or conversation
+	Source    string `json:"source"`
+	Operation string `json:"operation"`
+	ErrCode   int32  `json:"errCode"`
+	ErrMsg    string `json:"errMsg"`
+	// Category is the category of ErrCode, like network or storage
+	Category    string `json:"category"`
+	OperationID string `json:"operationID"`
+	// Time is in milliseconds
+	Time    int64          `json:"time"`
+	Context map[string]any `json:"context,omitempty"`
+}
+
+// Diagnostics is the state of the SDK collected for a bug report, a part that could not be collected is
+// nil and its error is in Errors.
+type Diagnostics struct {
+	// Time is in milliseconds
+	Time        int64               `json:"time"`
+	Version     string              `json:"version"`
+	UserID      string              `json:"userID"`
+	LoginStatus int                 `json:"loginStatus"`
+	Connection  *ConnDiagnostics    `json:"connection"`
+	Sync        *SyncDiagnostics    `json:"sync"`
+	Database    *DBDiagnostics      `json:"database"`
+	Queues      *QueueDiagnostics   `json:"queues"`
+	Runtime     *RuntimeDiagnostics `json:"runtime"`
+	// Caches are the stats of the in-memory caches by their names
+	Caches map[string]*CacheStats `json:"caches"`
+	// Config is the config of InitSDK without the passwords and the headers of the telemetry
+	Config *IMConfig `json:"config"`
+	Errors []string  `json:"errors,omitempty"`
+}
+
+type ConnDiagnostics struct {
+	// Status is the status of the long connection and State the last state reported
+	Status  int                `json:"status"`
+	State   string             `json:"state"`
+	History []*ConnStateChange `json:"history"`
+	Quality *NetworkQuality    `json:"quality"`
+	Traffic *TrafficStats      `json:"traffic"`
+}
+
+type SyncDiagnostics struct {
+	// Checkpoints are the seqs the messages of the conversations are synced up to
+	Checkpoints map[string]int64 `json:"checkpoints"`
+	SeqGaps     *SeqGapStats     `json:"seqGaps"`
+	MsgWrite    *MsgWriteStats   `json:"msgWrite"`
+}
+
+type DBDiagnostics struct {
+	SchemaVersion int           `json:"schemaVersion"`
+	Storage       *StorageUsage `json:"storage"`
+	Queries       *DBQueryStats `json:"queries"`
+}
+
+type QueueDiagnostics struct {
+	// SendTasks are the messages waiting for the sender and SendingMessages the ones not acknowledged yet
+	SendTasks       int `json:"sendTasks"`
+	SendingMessages int `json:"sendingMessages"`
+	// WsRequests are the requests waiting to be written to the long connection
+	WsRequests int `json:"wsRequests"`
+	// SyncCommands and ConversationEvents wait for the syncer and the conversation handler
+	SyncCommands       int `json:"syncCommands"`
+	ConversationEvents int `json:"conversationEvents"`
+	// Downloads are the downloads by state
+	Downloads map[string]int `json:"downloads"`
+}
+
+type RuntimeDiagnostics struct {
+	Current *RuntimeStats `json:"current"`
+	// History are the samples of the poller of RuntimeStatsInterval, the oldest first
+	History []*RuntimeStats `json:"history"`
+	// Profiling are the profiles being taken
+	Profiling []string `json:"profiling"`
+}
+
+// RuntimeStats is a sample of the Go runtime, the sizes are in bytes.
+type RuntimeStats struct {
+	// Time is in milliseconds
+	Time        int64  `json:"time"`
+	Goroutines  int    `json:"goroutines"`
+	HeapAlloc   uint64 `json:"heapAlloc"`
+	HeapInuse   uint64 `json:"heapInuse"`
+	HeapObjects uint64 `json:"heapObjects"`
+	// Sys is the memory obtained from the system
+	Sys   uint64 `json:"sys"`
+	NumGC uint32 `json:"numGC"`
+	// GCPauseTotal is in milliseconds and LastGCPause in microseconds
+	GCPauseTotal int64 `json:"gcPauseTotal"`
+	LastGCPause  int64 `json:"lastGCPause"`
+	CgoCalls     int64 `json:"cgoCalls"`
+}
+
+// ReplayResult tells what a replay of a recording did, Mismatches are the dispatches to the conversations that
+// differ from the recorded ones.
+type ReplayResult struct {
+	// Path is the recording of the replay and DBDir the directory of its database
+	Path   string `json:"path"`
+	DBDir  string `json:"dbDir"`
+	Events int    `json:"events"`
+	// RecordedDispatches and ReplayedDispatches are the numbers of the dispatches of both
+	RecordedDispatches int      `json:"recordedDispatches"`
+	ReplayedDispatches int      `json:"replayedDispatches"`
+	Mismatches         []string `json:"mismatches"`
+	// MissedRequests are the requests of the replay without a recorded response
+	MissedRequests int `json:"missedRequests"`
+}
+
+// ErrorInfo describes an error code returned to a callback.
+type ErrorInfo struct {
+	Code int32 `json:"code"`
+	// Category is one of network, auth, permission, validation, storage, canceled, server and internal
+	Category string `json:"category"`
+	// Retryable tells if the same call may succeed when it is made again later
+	Retryable bool `json:"retryable"`
+}
+
+// LogFilter selects the log entries uploaded, the zero value uploads them all.
+type LogFilter struct {
+	// StartTime and EndTime bound the time of the entries in milliseconds, 0 leaves a bound open
+	StartTime int64 `json:"startTime"`
+	EndTime   int64 `json:"endTime"`
+	// Level is the least level of the entries uploaded: debug, info, warn or error
+	Level string `json:"level,omitempty"`
+	// Modules are the packages of the SDK that wrote the entries, e.g. conversation_msg or interaction
+	Modules []string `json:"modules,omitempty"`
+}
+
+// MediaFileChunk is the plain content of a downloaded file read from an offset.
+type MediaFileChunk struct {
+	Data []byte `json:"data"`
+	// Size is the size of the plain content of the file
+	Size int64 `json:"size"`
+	EOF  bool  `json:"eof"`
+}
+
+type StorageUsage struct {
+	// Database is the bytes of the files of the local database and its archive, DatabaseFree of them are
+	// unused pages of the database
+	Database     int64 `json:"database"`
+	DatabaseFree int64 `json:"databaseFree"`
+	// Media is the bytes of the media files in DataDir
+	Media      int64 `json:"media"`
+	MediaFiles int   `json:"mediaFiles"`
+	Total      int64 `json:"total"`
+	// Quota is StorageQuota, 0 for no quota
+	Quota int64 `json:"quota"`
+}
+
+// CacheStats are the usage of an in-memory cache since the login.
+type CacheStats struct {
+	Size int `json:"size"`
+	// Capacity is the most entries kept, 0 for no bound
+	Capacity int   `json:"capacity"`
+	Hits     int64 `json:"hits"`
+	Misses   int64 `json:"misses"`
+	// Evictions are the entries removed beyond the capacity or by TrimMemory, Expired the ones removed once
+	// expired
+	Evictions int64 `json:"evictions"`
+	Expired   int64 `json:"expired"`
+}
+
+type DBQueryStats struct {
+	Enabled bool `json:"enabled"`
+	// SlowThreshold is the milliseconds over which a query is logged as slow
+	SlowThreshold int64 `json:"slowThreshold"`
+	// Queries are the aggregates by the shape of the queries, the most time spent first
+	Queries []*DBQueryShapeStats `json:"queries"`
+}
+
+type DBQueryShapeStats struct {
+	// Shape is the sql of the query without its values, the tables of the messages are all chat_logs_*
+	Shape  string `json:"shape"`
+	Count  int64  `json:"count"`
+	Errors int64  `json:"errors"`
+	Slow   int64  `json:"slow"`
+	Rows   int64  `json:"rows"`
+	// TotalTime, MaxTime and AvgTime are in milliseconds
+	TotalTime float64 `json:"totalTime"`
+	MaxTime   float64 `json:"maxTime"`
+	AvgTime   float64 `json:"avgTime"`
+}
+
+type UserLastSeen struct {
+	UserID string `json:"userID"`
+	Status int32  `json:"status"`
+	// About is the elapsed time since the user was last seen, rounded up to a precision bucket in seconds.
+	About int64 `json:"about"`
+}
+
+type GuestLoginInfo struct {
+	UserID    string   `json:"userID"`
+	GroupIDs  []string `json:"groupIDs"`
+	SendLimit int32    `json:"sendLimit"`
+}
+
+type QRLoginCode struct {
+	QRID string `json:"qrID"`
+	// Payload is the content to encode in the QR code.
+	Payload    string `json:"payload"`
+	ExpireTime int64  `json:"expireTime"`
+}
+
+type QRLoginState struct {
+	QRID  string `json:"qrID"`
+	State int32  `json:"state"`
+	// UserID and Token are set when the login is confirmed, and are used to call Login.
+	UserID string `json:"userID,omitempty"`
+	Token  string `json:"token,omitempty"`
+}
+
 type PublicUser struct {
 	UserID     string `json:"userID"`
 	Nickname   string `json:"nickname"`
@@ -349,3 +1239,140 @@ type PublicUser struct {
 type MarkdownTextElem struct {
 	Content string `json:"content"`
 }
+
+// E2EEDevice is a device of a user with the end-to-end encryption enabled.
+type E2EEDevice struct {
+	UserID   string `json:"userID"`
+	DeviceID string `json:"deviceID"`
+	// Fingerprint is the hash of the identity of the device in hex
+	Fingerprint string `json:"fingerprint"`
+	// SafetyNumber is what the two users compare, empty for the devices of the login user
+	SafetyNumber string `json:"safetyNumber,omitempty"`
+	Verified     bool   `json:"verified"`
+	CreateTime   int64  `json:"createTime"`
+}
+
+// E2EEDevicesChange is the devices a user added and removed since they were last seen.
+type E2EEDevicesChange struct {
+	UserID  string        `json:"userID"`
+	Added   []*E2EEDevice `json:"added"`
+	Removed []*E2EEDevice `json:"removed"`
+}
+
+// SignalingInvitation is a call, what the inviter sends to the invitees.
+type SignalingInvitation struct {
+	// RoomID identifies the call, generated when it is empty
+	RoomID            string   `json:"roomID"`
+	InviterUserID     string   `json:"inviterUserID"`
+	InviteeUserIDList []string `json:"inviteeUserIDList"`
+	GroupID           string   `json:"groupID,omitempty"`
+	SessionType       int32    `json:"sessionType"`
+	// MediaType is audio or video
+	MediaType string `json:"mediaType"`
+	// Timeout is the seconds the invitees have to answer
+	Timeout      int32  `json:"timeout"`
+	InitiateTime int64  `json:"initiateTime"`
+	PlatformID   int32  `json:"platformID"`
+	CustomData   string `json:"customData,omitempty"`
+}
+
+// SignalingElem is the content of a signal, an action a user took on a call. It is what the signaling
+// listener is called with.
+type SignalingElem struct {
+	Action     string               `json:"action"`
+	Invitation *SignalingInvitation `json:"invitation"`
+	// UserID is the user who took the action
+	UserID     string `json:"userID"`
+	CustomData string `json:"customData,omitempty"`
+	// Participants is the roster of a group call
+	Participants []*SignalingParticipant `json:"participants,omitempty"`
+}
+
+// SignalingData is a payload of the custom signaling channel, what the signaling listener is called with.
+type SignalingData struct {
+	SendID     string `json:"sendID"`
+	PlatformID int32  `json:"platformID"`
+	Payload    string `json:"payload"`
+	SendTime   int64  `json:"sendTime"`
+}
+
+// SignalingParticipant is a user in a group call.
+type SignalingParticipant struct {
+	UserID   string `json:"userID"`
+	JoinTime int64  `json:"joinTime"`
+}
+
+// SignalingRoom is a group call with its roster, what the roster callbacks are called with.
+type SignalingRoom struct {
+	Invitation *SignalingInvitation `json:"invitation"`
+	// UserID is the user who joined or left
+	UserID       string                  `json:"userID,omitempty"`
+	Participants []*SignalingParticipant `json:"participants"`
+}
+
+// CallElem is the message of a call over.
+type CallElem struct {
+	RoomID        string `json:"roomID"`
+	InviterUserID string `json:"inviterUserID"`
+	MediaType     string `json:"mediaType"`
+	// State is how the call ended: completed, cancelled, rejected or timeout
+	State string `json:"state"`
+	// Duration is the milliseconds from the answer to the hang up, 0 for a call not answered
+	Duration int64 `json:"duration"`
+	// Missed is a call the login user was invited to and did not answer, a missed call message
+	Missed bool `json:"missed,omitempty"`
+}
+
+// InteractiveCardElem is a card sent by a bot, the actions on its elements are routed to the bot, which may
+// answer with a new version of the card.
+type InteractiveCardElem struct {
+	Title    string                `json:"title"`
+	Content  string                `json:"content"`
+	Elements []*InteractiveElement `json:"elements"`
+	// Version grows with each edit of the card, an older version received late is ignored
+	Version int64 `json:"version"`
+	// Closed is a card the bot no longer takes actions for
+	Closed bool   `json:"closed,omitempty"`
+	Ex     string `json:"ex,omitempty"`
+}
+
+// InteractiveElement is a button or a menu of an interactive card, see constant.InteractiveElement*.
+type InteractiveElement struct {
+	ActionID string `json:"actionID"`
+	Type     string `json:"type"`
+	Text     string `json:"text"`
+	// Style is a hint of the bot to the app, like primary or danger
+	Style string `json:"style,omitempty"`
+	// URL is opened by the app instead of submitting the action, for a button
+	URL      string               `json:"url,omitempty"`
+	Options  []*InteractiveOption `json:"options,omitempty"`
+	Disabled bool                 `json:"disabled,omitempty"`
+}
+
+// InteractiveOption is an option of a menu, its value is the payload of the action when it is chosen.
+type InteractiveOption struct {
+	Value string `json:"value"`
+	Text  string `json:"text"`
+}
+
+// SatisfactionRatingElem is an agent inviting the customer to rate the session when Rating is 0, the rating
+// of the customer from 1 to constant.MaxSatisfactionRating otherwise.
+type SatisfactionRatingElem struct {
+	SessionID string `json:"sessionID"`
+	Rating    int32  `json:"rating"`
+	Comment   string `json:"comment,omitempty"`
+}
+
+// EventEnvelope is an event of a listener with its sequence IDs. Seq grows with each event, and each of
+// ConversationSeqs with each event about the conversation: the messages received, deleted and edited, the
+// conversations new and changed, the input status. The envelopes are delivered in the order of Seq, and the
+// calls of the conversation and the message listeners in the same order, so an event of a conversation whose
+// sequence ID is below the last one seen for the conversation is stale.
+type EventEnvelope struct {
+	Seq              int64            `json:"seq"`
+	Listener         string           `json:"listener"`
+	Event            string           `json:"event"`
+	ConversationSeqs map[string]int64 `json:"conversationSeqs,omitempty"`
+	// Data is the argument of the call of the listener, the json array of them when there are several
+	Data string `json:"data"`
+}
@@ -93,10 +93,6 @@ func GetUserToken(ctx context.Context, userID string, platformID int32, secret s
 		UserID:     userID,
 		PlatformID: platformID,
 	}
-	ctx = ccontext.WithInfo(ctx, &ccontext.GlobalConfig{
-		UserID:   userID,
-		Token:    adminToken,
-		IMConfig: &imConf,
-	})
+	ctx = ccontext.WithInfo(ctx, ccontext.NewGlobalConfig(userID, adminToken, &imConf))
 	return api.ExtractField(ctx, api.GetUsersToken.Invoke, userReq, (*auth.GetUserTokenResp).GetToken)
 }
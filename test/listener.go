@@ -18,7 +18,7 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/openimsdk/tools/log"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
 )
 
 type OnConnListener struct{}
@@ -148,6 +148,10 @@ func (o *onAdvancedMsgListener) OnMsgDeleted(message string) {
 	log.ZInfo(o.ctx, "OnMsgDeleted", "message", message)
 }
 
+func (o *onAdvancedMsgListener) OnMsgEdited(message string) {
+	log.ZInfo(o.ctx, "OnMsgEdited", "message", message)
+}
+
 func (o *onAdvancedMsgListener) OnRecvOfflineNewMessages(messageList string) {
 	log.ZInfo(o.ctx, "OnRecvOfflineNewMessages", "messageList", messageList)
 }
@@ -0,0 +1,419 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"strconv"
+	"strings"
+)
+
+// callKind is how the wasm wrapper calls the exported function and what the js function returns.
+type callKind string
+
+const (
+	// kindCallback calls the function with a callback, the promise resolves with the json of the result.
+	kindCallback callKind = "AsyncCallWithCallback"
+	// kindWithoutCallback calls the function as is, the promise resolves with the array of its results.
+	kindWithoutCallback callKind = "AsyncCallWithOutCallback"
+	// kindSync calls the function as is and returns the array of its results.
+	kindSync callKind = "SyncCall"
+)
+
+type jsParam struct {
+	name     string
+	ts       string
+	json     bool // sent as the json of the value
+	optional bool
+}
+
+// jsFunc is a function registered by the wasm build.
+type jsFunc struct {
+	name     string
+	doc      string
+	kind     callKind
+	resolved bool // the go function behind is known, otherwise the params and the result are untyped
+	params   []jsParam
+	result   string
+	decode   bool // the result is the json of the value
+}
+
+type registration struct {
+	jsName  string
+	wrapper string
+	method  string
+}
+
+// registrations returns the functions the main of the wasm build sets on the js global object, in order.
+func registrations(file *ast.File) []registration {
+	wrappers := make(map[string]string)
+	var regs []registration
+	seen := make(map[string]bool)
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			if len(n.Lhs) != 1 || len(n.Rhs) != 1 {
+				return true
+			}
+			lhs, ok := n.Lhs[0].(*ast.Ident)
+			call, ok2 := n.Rhs[0].(*ast.CallExpr)
+			if !ok || !ok2 {
+				return true
+			}
+			if sel, ok := call.Fun.(*ast.SelectorExpr); ok && strings.HasPrefix(sel.Sel.Name, "New") {
+				wrappers[lhs.Name] = strings.TrimPrefix(sel.Sel.Name, "New")
+			}
+		case *ast.CallExpr:
+			lit, method, ok := registered(n)
+			if !ok {
+				return true
+			}
+			x, ok := method.X.(*ast.Ident)
+			if !ok {
+				return true
+			}
+			jsName, _ := strconv.Unquote(lit.Value)
+			if seen[jsName] {
+				return true
+			}
+			seen[jsName] = true
+			regs = append(regs, registration{jsName: jsName, wrapper: wrappers[x.Name], method: method.Sel.Name})
+		}
+		return true
+	})
+	return regs
+}
+
+// registered returns the js name and the wrapper method of a registration, either
+// js.Global().Set("name", js.FuncOf(w.Method)) or coordinator.Register("name", w.Method) of the tabs.
+func registered(call *ast.CallExpr) (*ast.BasicLit, *ast.SelectorExpr, bool) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || len(call.Args) != 2 {
+		return nil, nil, false
+	}
+	lit, ok := call.Args[0].(*ast.BasicLit)
+	if !ok {
+		return nil, nil, false
+	}
+	switch sel.Sel.Name {
+	case "Set":
+		funcOf, ok := call.Args[1].(*ast.CallExpr)
+		if !ok || len(funcOf.Args) != 1 {
+			return nil, nil, false
+		}
+		method, ok := funcOf.Args[0].(*ast.SelectorExpr)
+		return lit, method, ok
+	case "Register":
+		method, ok := call.Args[1].(*ast.SelectorExpr)
+		return lit, method, ok
+	}
+	return nil, nil, false
+}
+
+// api resolves the registered functions down to the methods of the sdk they call.
+type api struct {
+	l       *loader
+	ts      *tsTypes
+	sdk     *pkgInfo
+	wrapper *pkgInfo
+}
+
+// wrapped returns the exported function the wrapper method calls and how.
+func (a *api) wrapped(reg registration) (string, callKind) {
+	m, ok := a.wrapper.methods[reg.wrapper+"."+reg.method]
+	if !ok {
+		return "", ""
+	}
+	var name string
+	var kind callKind
+	ast.Inspect(m.decl.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || name != "" {
+			return name == ""
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		caller, ok := sel.X.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		newCaller, ok := caller.Fun.(*ast.SelectorExpr)
+		if !ok || newCaller.Sel.Name != "NewCaller" || len(caller.Args) == 0 {
+			return true
+		}
+		fn, ok := caller.Args[0].(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if x, ok := fn.X.(*ast.Ident); !ok || a.l.importPath(m.file, x.Name) != a.sdk.path {
+			return true
+		}
+		name, kind = fn.Sel.Name, callKind(sel.Sel.Name)
+		return false
+	})
+	return name, kind
+}
+
+// sdkCall is the call of a method of the sdk by an exported function, through call, messageCall or syncCall.
+type sdkCall struct {
+	method *funcDecl
+	pkg    *pkgInfo
+	args   map[string]int // the index in the params of the method of each param of the exported function
+}
+
+var callers = map[string]int{"call": 2, "messageCall": 2, "syncCall": 1}
+
+func (a *api) sdkCall(fn *funcDecl) *sdkCall {
+	var res *sdkCall
+	ast.Inspect(fn.decl.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || res != nil {
+			return res == nil
+		}
+		ident, ok := call.Fun.(*ast.Ident)
+		if !ok {
+			return true
+		}
+		index, ok := callers[ident.Name]
+		if !ok || len(call.Args) <= index {
+			return true
+		}
+		method, pkg := a.method(call.Args[index])
+		if method == nil {
+			return true
+		}
+		res = &sdkCall{method: method, pkg: pkg, args: make(map[string]int)}
+		for i, arg := range call.Args[index+1:] {
+			if ident, ok := arg.(*ast.Ident); ok {
+				res.args[ident.Name] = i + 1 // after the context
+			}
+		}
+		return false
+	})
+	return res
+}
+
+// method resolves IMUserContext.Method and IMUserContext.Accessor().Method.
+func (a *api) method(e ast.Expr) (*funcDecl, *pkgInfo) {
+	sel, ok := e.(*ast.SelectorExpr)
+	if !ok {
+		return nil, nil
+	}
+	if x, ok := sel.X.(*ast.Ident); ok && x.Name == "IMUserContext" {
+		return a.sdk.methods["UserContext."+sel.Sel.Name], a.sdk
+	}
+	call, ok := sel.X.(*ast.CallExpr)
+	if !ok {
+		return nil, nil
+	}
+	accessor, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return nil, nil
+	}
+	if x, ok := accessor.X.(*ast.Ident); !ok || x.Name != "IMUserContext" {
+		return nil, nil
+	}
+	getter, ok := a.sdk.methods["UserContext."+accessor.Sel.Name]
+	if !ok || getter.decl.Type.Results == nil || len(getter.decl.Type.Results.List) != 1 {
+		return nil, nil
+	}
+	t := getter.decl.Type.Results.List[0].Type
+	if star, ok := t.(*ast.StarExpr); ok {
+		t = star.X
+	}
+	typ, ok := t.(*ast.SelectorExpr)
+	if !ok {
+		return nil, nil
+	}
+	x, ok := typ.X.(*ast.Ident)
+	if !ok {
+		return nil, nil
+	}
+	pkg := a.l.load(a.l.importPath(getter.file, x.Name))
+	if pkg == nil {
+		return nil, nil
+	}
+	return pkg.methods[typ.Sel.Name+"."+sel.Sel.Name], pkg
+}
+
+type field struct {
+	name string
+	typ  ast.Expr
+}
+
+func flatten(list *ast.FieldList) []field {
+	if list == nil {
+		return nil
+	}
+	var fields []field
+	for _, f := range list.List {
+		if len(f.Names) == 0 {
+			fields = append(fields, field{typ: f.Type})
+			continue
+		}
+		for _, name := range f.Names {
+			fields = append(fields, field{name: name.Name, typ: f.Type})
+		}
+	}
+	return fields
+}
+
+// jsFunc types the registered function from the exported function it calls and the method of the sdk behind.
+// tabFuncs are the functions of the coordinator of the tabs, wasm/tabs, which wraps no function of the sdk.
+var tabFuncs = map[string]jsFunc{
+	"enableMultiTab": {
+		doc:    "Elect the tab owning the connection among the tabs sharing the channel, before initSDK.",
+		kind:   kindCallback,
+		params: []jsParam{{name: "channelName", ts: "string", optional: true}},
+		result: `"leader" | "follower"`,
+	},
+	"isLeaderTab": {
+		doc:    "Whether the tab owns the connection, true while the tabs are not coordinated.",
+		kind:   kindCallback,
+		result: "boolean",
+	},
+}
+
+func (a *api) jsFunc(reg registration) jsFunc {
+	if f, ok := tabFuncs[reg.jsName]; ok && reg.wrapper == "" {
+		f.name, f.resolved = reg.jsName, true
+		return f
+	}
+	f := jsFunc{name: reg.jsName, result: "unknown", decode: true}
+	name, kind := a.wrapped(reg)
+	fn, ok := a.sdk.funcs[name]
+	if !ok {
+		f.kind = kindCallback
+		return f
+	}
+	f.kind, f.resolved, f.doc = kind, true, docLine(fn.decl)
+	params := flatten(fn.decl.Type.Params)
+	if kind != kindWithoutCallback && len(params) > 0 {
+		params = params[1:] // the callback or the listener
+	}
+	call := a.sdkCall(fn)
+	var methodParams []field
+	if call != nil {
+		methodParams = flatten(call.method.decl.Type.Params)
+	}
+	for i, p := range params {
+		param := jsParam{name: paramName(p.name, i), ts: a.ts.expr(p.typ, a.sdk, fn.file)}
+		if _, ok := p.typ.(*ast.StarExpr); ok && kind == kindCallback {
+			param.ts = "ArrayBuffer"
+		}
+		if i, ok := call.arg(p.name); ok && i < len(methodParams) && param.ts == "string" {
+			if ts := a.ts.expr(methodParams[i].typ, call.pkg, call.method.file); ts != "string" {
+				param.ts, param.json = ts, true
+			}
+		}
+		f.params = append(f.params, param)
+	}
+	switch {
+	case kind == kindCallback && call != nil, call != nil && isSyncCall(fn):
+		f.result = a.results(call)
+	case kind == kindCallback:
+		f.result = "unknown"
+	default:
+		f.decode = false
+		if results := flatten(fn.decl.Type.Results); len(results) == 1 {
+			f.result = a.ts.expr(results[0].typ, a.sdk, fn.file)
+		} else {
+			f.result = "void"
+		}
+	}
+	return f
+}
+
+func (c *sdkCall) arg(name string) (int, bool) {
+	if c == nil {
+		return 0, false
+	}
+	i, ok := c.args[name]
+	return i, ok
+}
+
+// results returns the type of the json of the results of the method, the error excepted.
+func (a *api) results(call *sdkCall) string {
+	results := flatten(call.method.decl.Type.Results)
+	if n := len(results); n > 0 {
+		if ident, ok := results[n-1].typ.(*ast.Ident); ok && ident.Name == "error" {
+			results = results[:n-1]
+		}
+	}
+	switch len(results) {
+	case 0:
+		return "void"
+	case 1:
+		return a.ts.expr(results[0].typ, call.pkg, call.method.file)
+	}
+	var types []string
+	for _, r := range results {
+		types = append(types, a.ts.expr(r.typ, call.pkg, call.method.file))
+	}
+	return "[" + strings.Join(types, ", ") + "]"
+}
+
+func isSyncCall(fn *funcDecl) bool {
+	var found bool
+	ast.Inspect(fn.decl.Body, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok {
+			if ident, ok := call.Fun.(*ast.Ident); ok && ident.Name == "syncCall" {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+func docLine(decl *ast.FuncDecl) string {
+	if decl.Doc == nil {
+		return ""
+	}
+	doc := strings.Join(strings.Fields(decl.Doc.Text()), " ")
+	return strings.TrimPrefix(doc, decl.Name.Name+" ")
+}
+
+var reserved = map[string]bool{
+	"break": true, "case": true, "class": true, "const": true, "default": true, "delete": true, "function": true,
+	"in": true, "let": true, "new": true, "this": true, "typeof": true, "var": true, "void": true, "with": true,
+}
+
+// paramName names the parameters left blank in go, the leading one being the operation id.
+func paramName(name string, i int) string {
+	switch {
+	case name != "" && name != "_":
+		return jsIdentifier(name)
+	case i == 0:
+		return "operationID"
+	default:
+		return fmt.Sprintf("arg%d", i)
+	}
+}
+
+func jsIdentifier(name string) string {
+	if reserved[name] {
+		return name + "_"
+	}
+	return name
+}
+
+func parseFile(l *loader, filename string) (*ast.File, error) {
+	return parser.ParseFile(l.fset, filename, nil, parser.ParseComments)
+}
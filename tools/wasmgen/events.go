@@ -0,0 +1,155 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"go/ast"
+	"strconv"
+)
+
+// goType is a type of the sdk an event carries the json of.
+type goType struct {
+	path string
+	name string
+	list bool
+}
+
+const (
+	modelStruct      = "github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	sdkStruct        = "github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+	conversationMsg  = "github.com/openimsdk/openim-sdk-core/v3/internal/conversation_msg"
+	protocolUser     = "github.com/openimsdk/protocol/user"
+	listenerFileName = "wasm/event_listener/listener.go"
+)
+
+// eventPayloads are the events whose data is the json of a type of the sdk. The listeners take json strings,
+// so what they carry is only known by the code notifying them. The events left out carry the string as is.
+var eventPayloads = map[string]goType{
+	"OnNewConversation":                    {modelStruct, "LocalConversation", true},
+	"OnConversationChanged":                {modelStruct, "LocalConversation", true},
+	"OnConversationUserInputStatusChanged": {conversationMsg, "InputStatesChangedData", false},
+	"OnRecvNewMessage":                     {sdkStruct, "MsgStruct", false},
+	"OnRecvOfflineNewMessage":              {sdkStruct, "MsgStruct", false},
+	"OnRecvOnlineOnlyMessage":              {sdkStruct, "MsgStruct", false},
+	"OnRecvMessageModified":                {sdkStruct, "MsgStruct", false},
+	"OnMsgDeleted":                         {sdkStruct, "MsgStruct", false},
+	"OnMsgEdited":                          {sdkStruct, "MsgStruct", false},
+	"OnRecvC2CReadReceipt":                 {sdkStruct, "MessageReceipt", true},
+	"OnRecvGroupReadReceipt":               {sdkStruct, "MessageReceipt", true},
+	"OnNewRecvMessageRevoked":              {sdkStruct, "MessageRevoked", false},
+	"OnFriendApplicationAdded":             {modelStruct, "LocalFriendRequest", false},
+	"OnFriendApplicationDeleted":           {modelStruct, "LocalFriendRequest", false},
+	"OnFriendApplicationAccepted":          {modelStruct, "LocalFriendRequest", false},
+	"OnFriendApplicationRejected":          {modelStruct, "LocalFriendRequest", false},
+	"OnFriendAdded":                        {modelStruct, "LocalFriend", false},
+	"OnFriendDeleted":                      {modelStruct, "LocalFriend", false},
+	"OnFriendInfoChanged":                  {modelStruct, "LocalFriend", false},
+	"OnBlackAdded":                         {modelStruct, "LocalBlack", false},
+	"OnBlackDeleted":                       {modelStruct, "LocalBlack", false},
+	"OnJoinedGroupAdded":                   {modelStruct, "LocalGroup", false},
+	"OnJoinedGroupDeleted":                 {modelStruct, "LocalGroup", false},
+	"OnGroupInfoChanged":                   {modelStruct, "LocalGroup", false},
+	"OnGroupDismissed":                     {modelStruct, "LocalGroup", false},
+	"OnGroupMemberAdded":                   {modelStruct, "LocalGroupMember", false},
+	"OnGroupMemberDeleted":                 {modelStruct, "LocalGroupMember", false},
+	"OnGroupMemberInfoChanged":             {modelStruct, "LocalGroupMember", false},
+	"OnGroupApplicationAdded":              {modelStruct, "LocalGroupRequest", false},
+	"OnGroupApplicationDeleted":            {modelStruct, "LocalGroupRequest", false},
+	"OnGroupApplicationAccepted":           {modelStruct, "LocalGroupRequest", false},
+	"OnGroupApplicationRejected":           {modelStruct, "LocalGroupRequest", false},
+	"OnUserStatusChanged":                  {protocolUser, "OnlineStatus", false},
+	"OnSelfInfoUpdated":                    {modelStruct, "LocalUser", false},
+	"OnQRLoginStateChanged":                {sdkStruct, "QRLoginState", false},
+	"OnConnStateChanged":                   {sdkStruct, "ConnState", false},
+	"OnNetworkQualityChanged":              {sdkStruct, "NetworkQuality", false},
+	"OnSyncProgress":                       {sdkStruct, "SyncProgress", false},
+	"OnSyncConflict":                       {sdkStruct, "SyncConflict", false},
+}
+
+type jsEvent struct {
+	name   string
+	ts     string
+	decode bool // the data is the json of the value
+}
+
+// events returns the events the wasm listeners send to the common event function, in the order of the file.
+// The name of an event is the one of the listener method, its data what the method sets.
+func events(file *ast.File, ts *tsTypes) []jsEvent {
+	var res []jsEvent
+	seen := make(map[string]bool)
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv == nil || !sendsEvent(fn) || seen[fn.Name.Name] {
+			continue
+		}
+		seen[fn.Name.Name] = true
+		event := jsEvent{name: fn.Name.Name, ts: "undefined"}
+		if data := setData(fn); data != nil {
+			event.ts, event.decode = "unknown", false
+			switch data := data.(type) {
+			case *ast.Ident:
+				for _, p := range flatten(fn.Type.Params) {
+					if p.name == data.Name {
+						event.ts = ts.expr(p.typ, nil, file)
+					}
+				}
+			case *ast.CallExpr: // utils.StructToJsonString of a map
+				event.ts, event.decode = "Record<string, unknown>", true
+			}
+			if payload, ok := eventPayloads[event.name]; ok && event.ts != "boolean" && event.ts != "number" {
+				event.ts, event.decode = ts.named(ts.l.load(payload.path), payload.name), true
+				if payload.list {
+					event.ts += "[]"
+				}
+			}
+		}
+		res = append(res, event)
+	}
+	return res
+}
+
+func sendsEvent(fn *ast.FuncDecl) bool {
+	var found bool
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok {
+			if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "SetEvent" && len(call.Args) == 1 {
+				if inner, ok := call.Args[0].(*ast.CallExpr); ok {
+					if name, ok := inner.Fun.(*ast.SelectorExpr); ok && name.Sel.Name == "GetSelfFuncName" {
+						found = true
+					}
+				}
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+func setData(fn *ast.FuncDecl) ast.Expr {
+	var data ast.Expr
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok {
+			if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "SetData" && len(call.Args) == 1 {
+				data = call.Args[0]
+			}
+		}
+		return data == nil
+	})
+	return data
+}
+
+func quote(s string) string {
+	return strconv.Quote(s)
+}
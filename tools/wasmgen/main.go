@@ -0,0 +1,283 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// wasmgen generates, from the functions the wasm build registers, a promise based js module and its TypeScript
+// definitions. The json strings the functions take and return are typed with the structs of the sdk they are
+// the json of, so are the payloads of the events.
+//
+// Usage: go run ./tools/wasmgen [-root .] [-o wasm/cmd/static] [-check]
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const header = "// Code generated by tools/wasmgen. DO NOT EDIT.\n"
+
+func main() {
+	root := flag.String("root", ".", "the root of the repository")
+	out := flag.String("o", "wasm/cmd/static", "the directory the module and its definitions are written to")
+	check := flag.Bool("check", false, "fail when the files in the directory differ from the ones generated instead of writing them")
+	flag.Parse()
+
+	g, err := generate(*root)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *check {
+		if stale := g.stale(*out); len(stale) > 0 {
+			log.Fatalf("%s not up to date, run go run ./tools/wasmgen", strings.Join(stale, ", "))
+		}
+		return
+	}
+	for name, data := range g.files() {
+		if err := os.WriteFile(filepath.Join(*out, name), data, 0644); err != nil {
+			log.Fatal(err)
+		}
+	}
+	fmt.Println(g.summary)
+}
+
+// generated is the output of the generator.
+type generated struct {
+	js, dts []byte
+	summary string
+}
+
+func (g *generated) files() map[string][]byte {
+	return map[string][]byte{"openim.js": g.js, "openim.d.ts": g.dts}
+}
+
+// stale returns the files of the directory differing from the ones generated.
+func (g *generated) stale(dir string) []string {
+	var stale []string
+	for _, name := range []string{"openim.js", "openim.d.ts"} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil || !bytes.Equal(data, g.files()[name]) {
+			stale = append(stale, name)
+		}
+	}
+	return stale
+}
+
+// generate generates the module and its definitions from the sources of the repository at root.
+func generate(root string) (*generated, error) {
+	module, err := modulePath(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return nil, err
+	}
+	l := newLoader(root, module)
+	ts := newTSTypes(l)
+	a := &api{l: l, ts: ts, sdk: l.load(module + "/open_im_sdk"), wrapper: l.load(module + "/wasm/wasm_wrapper")}
+	if a.sdk == nil || a.wrapper == nil {
+		return nil, errors.New("the sdk or the wasm wrappers can not be parsed")
+	}
+	cmdFiles, err := cmdFiles(root)
+	if err != nil {
+		return nil, err
+	}
+	var funcs []jsFunc
+	for _, filename := range cmdFiles {
+		file, err := parseFile(l, filename)
+		if err != nil {
+			return nil, err
+		}
+		for _, reg := range registrations(file) {
+			funcs = append(funcs, a.jsFunc(reg))
+		}
+	}
+	listenerFile, err := parseFile(l, filepath.Join(root, listenerFileName))
+	if err != nil {
+		return nil, err
+	}
+	evs := events(listenerFile, ts)
+	var untyped int
+	for _, f := range funcs {
+		if !f.resolved {
+			untyped++
+		}
+	}
+	return &generated{
+		js:      jsModule(funcs, evs),
+		dts:     definitions(funcs, evs, ts),
+		summary: fmt.Sprintf("%d functions (%d untyped), %d events, %d types", len(funcs), untyped, len(evs), len(ts.decls)),
+	}, nil
+}
+
+// cmdFiles are the files of the main of the wasm build, main.go first and then the files of the optional
+// modules registering their functions, whatever their build tags.
+func cmdFiles(root string) ([]string, error) {
+	dir := filepath.Join(root, "wasm/cmd")
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	files := []string{filepath.Join(dir, "main.go")}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || name == "main.go" || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		files = append(files, filepath.Join(dir, name))
+	}
+	return files, nil
+}
+
+func modulePath(goMod string) (string, error) {
+	f, err := os.Open(goMod)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); strings.HasPrefix(line, "module ") {
+			return strings.TrimSpace(strings.TrimPrefix(line, "module ")), nil
+		}
+	}
+	return "", fmt.Errorf("no module in %s", goMod)
+}
+
+// jsModule writes the js module, each function awaits the registered one and decodes what it resolves with.
+func jsModule(funcs []jsFunc, evs []jsEvent) []byte {
+	var b bytes.Buffer
+	b.WriteString(header)
+	b.WriteString(`
+function decode(data) {
+  if (typeof data !== 'string') return data;
+  try {
+    return JSON.parse(data);
+  } catch {
+    return data;
+  }
+}
+
+function encode(value) {
+  return typeof value === 'string' ? value : JSON.stringify(value);
+}
+`)
+	for _, f := range funcs {
+		if !f.resolved {
+			fmt.Fprintf(&b, "\nexport async function %s(...args) {\n  return decode(await globalThis.%s(...args));\n}\n", f.name, f.name)
+			continue
+		}
+		var names, args []string
+		for _, p := range f.params {
+			names = append(names, p.name)
+			if p.json {
+				args = append(args, "encode("+p.name+")")
+			} else {
+				args = append(args, p.name)
+			}
+		}
+		invoke := fmt.Sprintf("globalThis.%s(%s)", f.name, strings.Join(args, ", "))
+		var res string
+		switch f.kind {
+		case kindCallback:
+			res = "await " + invoke
+		case kindWithoutCallback:
+			res = "(await " + invoke + ")[0]"
+		default:
+			res = invoke + "[0]"
+		}
+		if f.decode {
+			res = "decode(" + res + ")"
+		}
+		fmt.Fprintf(&b, "\nexport async function %s(%s) {\n  return %s;\n}\n", f.name, strings.Join(names, ", "), res)
+	}
+	var decoded []string
+	for _, e := range evs {
+		if e.decode {
+			decoded = append(decoded, "  "+quote(e.name)+",")
+		}
+	}
+	fmt.Fprintf(&b, `
+const jsonEvents = new Set([
+%s
+]);
+
+export function onEvent(handler) {
+  globalThis.commonEventFunc((data) => {
+    const event = JSON.parse(data);
+    if (jsonEvents.has(event.event)) event.data = decode(event.data);
+    handler(event);
+  });
+}
+`, strings.Join(decoded, "\n"))
+	return b.Bytes()
+}
+
+// definitions writes the TypeScript definitions of the module.
+func definitions(funcs []jsFunc, evs []jsEvent, ts *tsTypes) []byte {
+	var b bytes.Buffer
+	b.WriteString(header)
+	b.WriteString(`
+/** What the promises reject with. */
+export interface OpenIMError {
+  errCode: number;
+  errMsg: string;
+  operationID: string;
+}
+`)
+	for _, f := range funcs {
+		b.WriteString("\n")
+		if f.doc != "" {
+			b.WriteString("/** " + strings.ReplaceAll(f.doc, "*/", "* /") + " */\n")
+		}
+		if !f.resolved {
+			fmt.Fprintf(&b, "export function %s(operationID: string, ...args: unknown[]): Promise<unknown>;\n", f.name)
+			continue
+		}
+		var params []string
+		for _, p := range f.params {
+			if p.optional {
+				params = append(params, p.name+"?: "+p.ts)
+			} else {
+				params = append(params, p.name+": "+p.ts)
+			}
+		}
+		fmt.Fprintf(&b, "export function %s(%s): Promise<%s>;\n", f.name, strings.Join(params, ", "), f.result)
+	}
+	b.WriteString("\nexport interface OpenIMEventMap {\n")
+	for _, e := range evs {
+		fmt.Fprintf(&b, "  %s: %s;\n", e.name, e.ts)
+	}
+	b.WriteString(`}
+
+export type OpenIMEvent = {
+  [K in keyof OpenIMEventMap]: {
+    event: K;
+    errCode: number;
+    errMsg: string;
+    data: OpenIMEventMap[K];
+    operationID: string;
+  };
+}[keyof OpenIMEventMap];
+
+/** Receive the events of the listeners, the data decoded. */
+export function onEvent(handler: (event: OpenIMEvent) => void): void;
+`)
+	for _, decl := range ts.decls {
+		b.WriteString("\n" + decl)
+	}
+	return b.Bytes()
+}
@@ -0,0 +1,18 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// TestGeneratedUpToDate fails when the checked-in module or definitions differ from the ones of the api.
+func TestGeneratedUpToDate(t *testing.T) {
+	root := filepath.Join("..", "..")
+	g, err := generate(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stale := g.stale(filepath.Join(root, "wasm", "cmd", "static")); len(stale) > 0 {
+		t.Fatalf("%v not up to date, run go run ./tools/wasmgen", stale)
+	}
+}
@@ -0,0 +1,358 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// pkgInfo is a parsed package, only what the generator looks up.
+type pkgInfo struct {
+	path    string
+	name    string
+	types   map[string]*typeDecl
+	funcs   map[string]*funcDecl
+	methods map[string]*funcDecl // by receiver type name and method name, "Type.Method"
+}
+
+type typeDecl struct {
+	spec *ast.TypeSpec
+	file *ast.File
+}
+
+type funcDecl struct {
+	decl *ast.FuncDecl
+	file *ast.File
+}
+
+// loader parses the packages by their import path, the ones of the module from the tree and the others from
+// the module cache.
+type loader struct {
+	root   string
+	module string
+	fset   *token.FileSet
+	pkgs   map[string]*pkgInfo
+}
+
+func newLoader(root, module string) *loader {
+	return &loader{root: root, module: module, fset: token.NewFileSet(), pkgs: make(map[string]*pkgInfo)}
+}
+
+func (l *loader) dir(importPath string) (string, error) {
+	if importPath == l.module || strings.HasPrefix(importPath, l.module+"/") {
+		return filepath.Join(l.root, strings.TrimPrefix(importPath, l.module)), nil
+	}
+	cmd := exec.Command("go", "list", "-f", "{{.Dir}}", importPath)
+	cmd.Dir = l.root
+	cmd.Stderr = os.Stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("go list %s: %w", importPath, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// load returns the package, nil when it can not be found.
+func (l *loader) load(importPath string) *pkgInfo {
+	if p, ok := l.pkgs[importPath]; ok {
+		return p
+	}
+	l.pkgs[importPath] = nil
+	dir, err := l.dir(importPath)
+	if err != nil {
+		return nil
+	}
+	parsed, err := parser.ParseDir(l.fset, dir, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, parser.ParseComments)
+	if err != nil {
+		return nil
+	}
+	p := &pkgInfo{path: importPath, types: make(map[string]*typeDecl), funcs: make(map[string]*funcDecl), methods: make(map[string]*funcDecl)}
+	var names []string
+	for name := range parsed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if strings.HasSuffix(name, "_test") || name == "main" && len(names) > 1 {
+			continue
+		}
+		p.name = name
+		var files []string
+		for file := range parsed[name].Files {
+			files = append(files, file)
+		}
+		sort.Strings(files)
+		for _, filename := range files {
+			file := parsed[name].Files[filename]
+			for _, decl := range file.Decls {
+				switch d := decl.(type) {
+				case *ast.GenDecl:
+					for _, spec := range d.Specs {
+						if ts, ok := spec.(*ast.TypeSpec); ok {
+							if _, ok := p.types[ts.Name.Name]; !ok {
+								p.types[ts.Name.Name] = &typeDecl{spec: ts, file: file}
+							}
+						}
+					}
+				case *ast.FuncDecl:
+					if d.Recv == nil {
+						if _, ok := p.funcs[d.Name.Name]; !ok {
+							p.funcs[d.Name.Name] = &funcDecl{decl: d, file: file}
+						}
+						continue
+					}
+					key := receiverName(d) + "." + d.Name.Name
+					if _, ok := p.methods[key]; !ok {
+						p.methods[key] = &funcDecl{decl: d, file: file}
+					}
+				}
+			}
+		}
+	}
+	l.pkgs[importPath] = p
+	return p
+}
+
+func receiverName(d *ast.FuncDecl) string {
+	t := d.Recv.List[0].Type
+	if star, ok := t.(*ast.StarExpr); ok {
+		t = star.X
+	}
+	if ident, ok := t.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// importPath returns the path of the package imported by the file under the name.
+func (l *loader) importPath(file *ast.File, name string) string {
+	var unnamed []string
+	for _, spec := range file.Imports {
+		p, _ := strconv.Unquote(spec.Path.Value)
+		if spec.Name != nil {
+			if spec.Name.Name == name {
+				return p
+			}
+			continue
+		}
+		if path.Base(p) == name {
+			return p
+		}
+		unnamed = append(unnamed, p)
+	}
+	for _, p := range unnamed {
+		if pkg := l.load(p); pkg != nil && pkg.name == name {
+			return p
+		}
+	}
+	return ""
+}
+
+var basicTypes = map[string]string{
+	"string": "string", "bool": "boolean", "any": "unknown", "error": "string",
+	"int": "number", "int8": "number", "int16": "number", "int32": "number", "int64": "number",
+	"uint": "number", "uint8": "number", "uint16": "number", "uint32": "number", "uint64": "number",
+	"float32": "number", "float64": "number", "byte": "number", "rune": "number",
+}
+
+// wellKnownTypes are the types of the standard library marshaled otherwise than their fields.
+var wellKnownTypes = map[string]string{
+	"time.Time": "string", "time.Duration": "number", "encoding/json.RawMessage": "unknown",
+}
+
+// tsTypes converts the go types to TypeScript ones, the structs are declared as interfaces named after them.
+type tsTypes struct {
+	l     *loader
+	names map[string]string // by "importPath.Type"
+	taken map[string]string // the key of the type declared under the name
+	decls []string
+}
+
+func newTSTypes(l *loader) *tsTypes {
+	return &tsTypes{l: l, names: make(map[string]string), taken: make(map[string]string)}
+}
+
+func (t *tsTypes) expr(e ast.Expr, pkg *pkgInfo, file *ast.File) string {
+	switch e := e.(type) {
+	case *ast.Ident:
+		if ts, ok := basicTypes[e.Name]; ok {
+			return ts
+		}
+		return t.named(pkg, e.Name)
+	case *ast.StarExpr:
+		return t.expr(e.X, pkg, file)
+	case *ast.ArrayType:
+		if ident, ok := e.Elt.(*ast.Ident); ok && ident.Name == "byte" {
+			return "string" // base64 in json
+		}
+		elem := t.expr(e.Elt, pkg, file)
+		if strings.Contains(elem, " ") {
+			elem = "(" + elem + ")"
+		}
+		return elem + "[]"
+	case *ast.MapType:
+		return "Record<string, " + t.expr(e.Value, pkg, file) + ">"
+	case *ast.SelectorExpr:
+		x, ok := e.X.(*ast.Ident)
+		if !ok {
+			return "unknown"
+		}
+		importPath := t.l.importPath(file, x.Name)
+		if ts, ok := wellKnownTypes[importPath+"."+e.Sel.Name]; ok {
+			return ts
+		}
+		return t.named(t.l.load(importPath), e.Sel.Name)
+	case *ast.StructType:
+		return "{ " + strings.Join(t.fields(e, pkg, file), " ") + " }"
+	default:
+		return "unknown"
+	}
+}
+
+// named returns the TypeScript type of the declared type, declaring its interface the first time for a struct.
+func (t *tsTypes) named(pkg *pkgInfo, name string) string {
+	if pkg == nil {
+		return "unknown"
+	}
+	key := pkg.path + "." + name
+	if ts, ok := t.names[key]; ok {
+		return ts
+	}
+	if ts, ok := wellKnownTypes[key]; ok {
+		return ts
+	}
+	decl, ok := pkg.types[name]
+	if !ok || decl.spec.TypeParams != nil {
+		return "unknown"
+	}
+	st, ok := decl.spec.Type.(*ast.StructType)
+	if !ok {
+		t.names[key] = "unknown" // recursive definitions
+		ts := t.expr(decl.spec.Type, pkg, decl.file)
+		t.names[key] = ts
+		return ts
+	}
+	ts := name
+	if other, ok := t.taken[ts]; ok && other != key {
+		ts = exportedName(pkg.name) + name
+	}
+	t.taken[ts] = key
+	t.names[key] = ts
+	var extends []string
+	for _, field := range st.Fields.List {
+		if len(field.Names) != 0 || jsonName(field) != "" {
+			continue
+		}
+		if embedded := t.expr(field.Type, pkg, decl.file); embedded != "unknown" && isIdentifier(embedded) {
+			extends = append(extends, embedded)
+		}
+	}
+	var b strings.Builder
+	b.WriteString("export interface " + ts)
+	if len(extends) > 0 {
+		b.WriteString(" extends " + strings.Join(extends, ", "))
+	}
+	b.WriteString(" {\n")
+	for _, field := range t.fields(st, pkg, decl.file) {
+		b.WriteString("  " + field + "\n")
+	}
+	b.WriteString("}\n")
+	t.decls = append(t.decls, b.String())
+	return ts
+}
+
+// fields returns the members of the struct as marshaled by encoding/json, the embedded structs excepted.
+func (t *tsTypes) fields(st *ast.StructType, pkg *pkgInfo, file *ast.File) []string {
+	var members []string
+	for _, field := range st.Fields.List {
+		tag := jsonTag(field)
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		optional := ""
+		if strings.Contains(","+opts+",", ",omitempty,") {
+			optional = "?"
+		}
+		if len(field.Names) == 0 {
+			if name == "" {
+				continue
+			}
+			members = append(members, propertyName(name)+optional+": "+t.expr(field.Type, pkg, file)+";")
+			continue
+		}
+		for _, ident := range field.Names {
+			if !ident.IsExported() {
+				continue
+			}
+			fieldName := name
+			if fieldName == "" {
+				fieldName = ident.Name
+			}
+			members = append(members, propertyName(fieldName)+optional+": "+t.expr(field.Type, pkg, file)+";")
+		}
+	}
+	return members
+}
+
+func jsonTag(field *ast.Field) string {
+	if field.Tag == nil {
+		return ""
+	}
+	tag, _ := strconv.Unquote(field.Tag.Value)
+	return reflect.StructTag(tag).Get("json")
+}
+
+func jsonName(field *ast.Field) string {
+	name, _, _ := strings.Cut(jsonTag(field), ",")
+	return name
+}
+
+var identifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+func isIdentifier(s string) bool {
+	return identifier.MatchString(s)
+}
+
+func propertyName(name string) string {
+	if isIdentifier(name) {
+		return name
+	}
+	return strconv.Quote(name)
+}
+
+func exportedName(name string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(name, "_") {
+		if part != "" {
+			b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+		}
+	}
+	return b.String()
+}
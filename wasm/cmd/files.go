@@ -0,0 +1,30 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build js && wasm && !no_files
+// +build js,wasm,!no_files
+
+package main
+
+import (
+	"github.com/openimsdk/openim-sdk-core/v3/wasm/tabs"
+	"github.com/openimsdk/openim-sdk-core/v3/wasm/wasm_wrapper"
+)
+
+func init() {
+	moduleFuncs = append(moduleFuncs, func(coordinator *tabs.Coordinator, globalFuc *wasm_wrapper.WrapperCommon) {
+		wrapperThird := wasm_wrapper.NewWrapperThird(globalFuc)
+		coordinator.Register("uploadFile", wrapperThird.UploadFile)
+	})
+}
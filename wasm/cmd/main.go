@@ -23,6 +23,7 @@ import (
 	"runtime/debug"
 	"syscall/js"
 
+	"github.com/openimsdk/openim-sdk-core/v3/wasm/tabs"
 	"github.com/openimsdk/openim-sdk-core/v3/wasm/wasm_wrapper"
 )
 
@@ -37,145 +38,190 @@ func main() {
 	<-make(chan bool)
 }
 
+// moduleFuncs register the functions of the optional modules built, appended by the init of their files.
+var moduleFuncs []func(coordinator *tabs.Coordinator, globalFuc *wasm_wrapper.WrapperCommon)
+
 func registerFunc() {
 	//register global listener function
 	globalFuc := wasm_wrapper.NewWrapperCommon()
 	js.Global().Set(wasm_wrapper.COMMONEVENTFUNC, js.FuncOf(globalFuc.CommonEventFunc))
+	//register multi tab functions, the ones registered by the coordinator run in the leader tab once enabled
+	coordinator := tabs.New(globalFuc.EventFunc)
+	js.Global().Set("enableMultiTab", js.FuncOf(coordinator.Enable))
+	js.Global().Set("isLeaderTab", js.FuncOf(coordinator.IsLeader))
 	//register init login function
 	wrapperInitLogin := wasm_wrapper.NewWrapperInitLogin(globalFuc)
-	js.Global().Set("initSDK", js.FuncOf(wrapperInitLogin.InitSDK))
-	js.Global().Set("login", js.FuncOf(wrapperInitLogin.Login))
-	js.Global().Set("logout", js.FuncOf(wrapperInitLogin.Logout))
-	js.Global().Set("getLoginStatus", js.FuncOf(wrapperInitLogin.GetLoginStatus))
-	js.Global().Set("setAppBackgroundStatus", js.FuncOf(wrapperInitLogin.SetAppBackgroundStatus))
-	js.Global().Set("networkStatusChanged", js.FuncOf(wrapperInitLogin.NetworkStatusChanged))
+	coordinator.Register("initSDK", wrapperInitLogin.InitSDK)
+	coordinator.Register("login", wrapperInitLogin.Login)
+	coordinator.Register("guestLogin", wrapperInitLogin.GuestLogin)
+	coordinator.Register("logout", wrapperInitLogin.Logout)
+	coordinator.Register("getLoginStatus", wrapperInitLogin.GetLoginStatus)
+	coordinator.Register("getTrafficStats", wrapperInitLogin.GetTrafficStats)
+	coordinator.Register("getDBQueryStats", wrapperInitLogin.GetDBQueryStats)
+	coordinator.Register("getServerTime", wrapperInitLogin.GetServerTime)
+	coordinator.Register("getNetworkQuality", wrapperInitLogin.GetNetworkQuality)
+	coordinator.Register("forceReconnect", wrapperInitLogin.ForceReconnect)
+	coordinator.Register("setBandwidthLimit", wrapperInitLogin.SetBandwidthLimit)
+	coordinator.Register("getBandwidthLimit", wrapperInitLogin.GetBandwidthLimit)
+	coordinator.Register("setNetworkClass", wrapperInitLogin.SetNetworkClass)
+	coordinator.Register("trimMemory", wrapperInitLogin.TrimMemory)
+	coordinator.Register("setLocalizedTemplates", wrapperInitLogin.SetLocalizedTemplates)
+	coordinator.Register("getLocalizedText", wrapperInitLogin.GetLocalizedText)
+	coordinator.Register("allowMeteredTransfer", wrapperInitLogin.AllowMeteredTransfer)
+	coordinator.Register("setAppBackgroundStatus", wrapperInitLogin.SetAppBackgroundStatus)
+	coordinator.Register("enterBackground", wrapperInitLogin.EnterBackground)
+	coordinator.Register("enterForeground", wrapperInitLogin.EnterForeground)
+	coordinator.Register("switchAccount", wrapperInitLogin.SwitchAccount)
+	coordinator.Register("getStorageUsage", wrapperInitLogin.GetStorageUsage)
+	coordinator.Register("forceResyncAll", wrapperInitLogin.ForceResyncAll)
+	coordinator.Register("networkStatusChanged", wrapperInitLogin.NetworkStatusChanged)
+	coordinator.Register("refreshToken", wrapperInitLogin.RefreshToken)
+	coordinator.Register("createLoginQRCode", wrapperInitLogin.CreateLoginQRCode)
+	coordinator.Register("cancelLoginQRCode", wrapperInitLogin.CancelLoginQRCode)
+	coordinator.Register("scanLoginQRCode", wrapperInitLogin.ScanLoginQRCode)
+	coordinator.Register("approveLoginQRCode", wrapperInitLogin.ApproveLoginQRCode)
+	coordinator.Register("rejectLoginQRCode", wrapperInitLogin.RejectLoginQRCode)
 
 	//register conversation and message function
 	wrapperConMsg := wasm_wrapper.NewWrapperConMsg(globalFuc)
-	js.Global().Set("createTextMessage", js.FuncOf(wrapperConMsg.CreateTextMessage))
-	js.Global().Set("createImageMessage", js.FuncOf(wrapperConMsg.CreateImageMessage))
-	js.Global().Set("createImageMessageByURL", js.FuncOf(wrapperConMsg.CreateImageMessageByURL))
-	js.Global().Set("createSoundMessageByURL", js.FuncOf(wrapperConMsg.CreateSoundMessageByURL))
-	js.Global().Set("createVideoMessageByURL", js.FuncOf(wrapperConMsg.CreateVideoMessageByURL))
-	js.Global().Set("createFileMessageByURL", js.FuncOf(wrapperConMsg.CreateFileMessageByURL))
-	js.Global().Set("createCustomMessage", js.FuncOf(wrapperConMsg.CreateCustomMessage))
-	js.Global().Set("createQuoteMessage", js.FuncOf(wrapperConMsg.CreateQuoteMessage))
-	js.Global().Set("createAdvancedQuoteMessage", js.FuncOf(wrapperConMsg.CreateAdvancedQuoteMessage))
-	js.Global().Set("createAdvancedTextMessage", js.FuncOf(wrapperConMsg.CreateAdvancedTextMessage))
-	js.Global().Set("createCardMessage", js.FuncOf(wrapperConMsg.CreateCardMessage))
-	js.Global().Set("createTextAtMessage", js.FuncOf(wrapperConMsg.CreateTextAtMessage))
-	js.Global().Set("createVideoMessage", js.FuncOf(wrapperConMsg.CreateVideoMessage))
-	js.Global().Set("createFileMessage", js.FuncOf(wrapperConMsg.CreateFileMessage))
-	js.Global().Set("createMergerMessage", js.FuncOf(wrapperConMsg.CreateMergerMessage))
-	js.Global().Set("createFaceMessage", js.FuncOf(wrapperConMsg.CreateFaceMessage))
-	js.Global().Set("createForwardMessage", js.FuncOf(wrapperConMsg.CreateForwardMessage))
-	js.Global().Set("createLocationMessage", js.FuncOf(wrapperConMsg.CreateLocationMessage))
-	js.Global().Set("createVideoMessageFromFullPath", js.FuncOf(wrapperConMsg.CreateVideoMessageFromFullPath))
-	js.Global().Set("createImageMessageFromFullPath", js.FuncOf(wrapperConMsg.CreateImageMessageFromFullPath))
-
-	js.Global().Set("createSoundMessageFromFullPath", js.FuncOf(wrapperConMsg.CreateSoundMessageFromFullPath))
-	js.Global().Set("createFileMessageFromFullPath", js.FuncOf(wrapperConMsg.CreateFileMessageFromFullPath))
-	js.Global().Set("createSoundMessage", js.FuncOf(wrapperConMsg.CreateSoundMessage))
-	js.Global().Set("createForwardMessage", js.FuncOf(wrapperConMsg.CreateForwardMessage))
-	js.Global().Set("createLocationMessage", js.FuncOf(wrapperConMsg.CreateLocationMessage))
-	js.Global().Set("getAtAllTag", js.FuncOf(wrapperConMsg.GetAtAllTag))
-	js.Global().Set("markConversationMessageAsRead", js.FuncOf(wrapperConMsg.MarkConversationMessageAsRead))
-	js.Global().Set("markAllConversationMessageAsRead", js.FuncOf(wrapperConMsg.MarkAllConversationMessageAsRead))
-	js.Global().Set("markMessagesAsReadByMsgID", js.FuncOf(wrapperConMsg.MarkMessagesAsReadByMsgID))
-	js.Global().Set("sendMessage", js.FuncOf(wrapperConMsg.SendMessage))
-	js.Global().Set("sendMessageNotOss", js.FuncOf(wrapperConMsg.SendMessageNotOss))
-	js.Global().Set("getAllConversationList", js.FuncOf(wrapperConMsg.GetAllConversationList))
-	js.Global().Set("getConversationListSplit", js.FuncOf(wrapperConMsg.GetConversationListSplit))
-	js.Global().Set("getOneConversation", js.FuncOf(wrapperConMsg.GetOneConversation))
-	js.Global().Set("deleteConversationAndDeleteAllMsg", js.FuncOf(wrapperConMsg.DeleteConversationAndDeleteAllMsg))
-	js.Global().Set("getAdvancedHistoryMessageList", js.FuncOf(wrapperConMsg.GetAdvancedHistoryMessageList))
-	js.Global().Set("getAdvancedHistoryMessageListReverse", js.FuncOf(wrapperConMsg.GetAdvancedHistoryMessageListReverse))
-	js.Global().Set("getMultipleConversation", js.FuncOf(wrapperConMsg.GetMultipleConversation))
-	js.Global().Set("hideConversation", js.FuncOf(wrapperConMsg.HideConversation))
-	js.Global().Set("setConversationDraft", js.FuncOf(wrapperConMsg.SetConversationDraft))
-	js.Global().Set("setConversation", js.FuncOf(wrapperConMsg.SetConversation))
-
-	js.Global().Set("getTotalUnreadMsgCount", js.FuncOf(wrapperConMsg.GetTotalUnreadMsgCount))
-	js.Global().Set("findMessageList", js.FuncOf(wrapperConMsg.FindMessageList))
-
-	js.Global().Set("revokeMessage", js.FuncOf(wrapperConMsg.RevokeMessage))
-	js.Global().Set("typingStatusUpdate", js.FuncOf(wrapperConMsg.TypingStatusUpdate))
-	js.Global().Set("deleteMessageFromLocalStorage", js.FuncOf(wrapperConMsg.DeleteMessageFromLocalStorage))
-	js.Global().Set("deleteMessage", js.FuncOf(wrapperConMsg.DeleteMessage))
-	js.Global().Set("hideAllConversations", js.FuncOf(wrapperConMsg.HideAllConversations))
-	js.Global().Set("deleteAllMsgFromLocalAndSvr", js.FuncOf(wrapperConMsg.DeleteAllMsgFromLocalAndSvr))
-	js.Global().Set("deleteAllMsgFromLocal", js.FuncOf(wrapperConMsg.DeleteAllMsgFromLocal))
-	js.Global().Set("clearConversationAndDeleteAllMsg", js.FuncOf(wrapperConMsg.ClearConversationAndDeleteAllMsg))
-	js.Global().Set("insertSingleMessageToLocalStorage", js.FuncOf(wrapperConMsg.InsertSingleMessageToLocalStorage))
-	js.Global().Set("insertGroupMessageToLocalStorage", js.FuncOf(wrapperConMsg.InsertGroupMessageToLocalStorage))
-	js.Global().Set("searchLocalMessages", js.FuncOf(wrapperConMsg.SearchLocalMessages))
-	js.Global().Set("setMessageLocalEx", js.FuncOf(wrapperConMsg.SetMessageLocalEx))
-	js.Global().Set("searchConversation", js.FuncOf(wrapperConMsg.SearchConversation))
-
-	js.Global().Set("changeInputStates", js.FuncOf(wrapperConMsg.ChangeInputStates))
-	js.Global().Set("getInputStates", js.FuncOf(wrapperConMsg.GetInputStates))
+	coordinator.Register("createTextMessage", wrapperConMsg.CreateTextMessage)
+	coordinator.Register("createImageMessage", wrapperConMsg.CreateImageMessage)
+	coordinator.Register("createImageMessageByURL", wrapperConMsg.CreateImageMessageByURL)
+	coordinator.Register("createSoundMessageByURL", wrapperConMsg.CreateSoundMessageByURL)
+	coordinator.Register("createVideoMessageByURL", wrapperConMsg.CreateVideoMessageByURL)
+	coordinator.Register("createFileMessageByURL", wrapperConMsg.CreateFileMessageByURL)
+	coordinator.Register("createCustomMessage", wrapperConMsg.CreateCustomMessage)
+	coordinator.Register("createQuoteMessage", wrapperConMsg.CreateQuoteMessage)
+	coordinator.Register("createAdvancedQuoteMessage", wrapperConMsg.CreateAdvancedQuoteMessage)
+	coordinator.Register("createAdvancedTextMessage", wrapperConMsg.CreateAdvancedTextMessage)
+	coordinator.Register("createCardMessage", wrapperConMsg.CreateCardMessage)
+	coordinator.Register("createTextAtMessage", wrapperConMsg.CreateTextAtMessage)
+	coordinator.Register("createVideoMessage", wrapperConMsg.CreateVideoMessage)
+	coordinator.Register("createFileMessage", wrapperConMsg.CreateFileMessage)
+	coordinator.Register("createMergerMessage", wrapperConMsg.CreateMergerMessage)
+	coordinator.Register("createFaceMessage", wrapperConMsg.CreateFaceMessage)
+	coordinator.Register("createForwardMessage", wrapperConMsg.CreateForwardMessage)
+	coordinator.Register("createLocationMessage", wrapperConMsg.CreateLocationMessage)
+	coordinator.Register("createVideoMessageFromFullPath", wrapperConMsg.CreateVideoMessageFromFullPath)
+	coordinator.Register("createImageMessageFromFullPath", wrapperConMsg.CreateImageMessageFromFullPath)
+
+	coordinator.Register("createSoundMessageFromFullPath", wrapperConMsg.CreateSoundMessageFromFullPath)
+	coordinator.Register("createFileMessageFromFullPath", wrapperConMsg.CreateFileMessageFromFullPath)
+	coordinator.Register("createSoundMessage", wrapperConMsg.CreateSoundMessage)
+	coordinator.Register("createForwardMessage", wrapperConMsg.CreateForwardMessage)
+	coordinator.Register("createLocationMessage", wrapperConMsg.CreateLocationMessage)
+	coordinator.Register("getAtAllTag", wrapperConMsg.GetAtAllTag)
+	coordinator.Register("markConversationMessageAsRead", wrapperConMsg.MarkConversationMessageAsRead)
+	coordinator.Register("markAllConversationMessageAsRead", wrapperConMsg.MarkAllConversationMessageAsRead)
+	coordinator.Register("markMessagesAsReadByMsgID", wrapperConMsg.MarkMessagesAsReadByMsgID)
+	coordinator.Register("sendMessage", wrapperConMsg.SendMessage)
+	coordinator.Register("sendMessageNotOss", wrapperConMsg.SendMessageNotOss)
+	coordinator.Register("getAllConversationList", wrapperConMsg.GetAllConversationList)
+	coordinator.Register("getConversationListSplit", wrapperConMsg.GetConversationListSplit)
+	coordinator.Register("getOneConversation", wrapperConMsg.GetOneConversation)
+	coordinator.Register("deleteConversationAndDeleteAllMsg", wrapperConMsg.DeleteConversationAndDeleteAllMsg)
+	coordinator.Register("getAdvancedHistoryMessageList", wrapperConMsg.GetAdvancedHistoryMessageList)
+	coordinator.Register("getAdvancedHistoryMessageListReverse", wrapperConMsg.GetAdvancedHistoryMessageListReverse)
+	coordinator.Register("getMultipleConversation", wrapperConMsg.GetMultipleConversation)
+	coordinator.Register("hideConversation", wrapperConMsg.HideConversation)
+	coordinator.Register("setConversationDraft", wrapperConMsg.SetConversationDraft)
+	coordinator.Register("setConversation", wrapperConMsg.SetConversation)
+
+	coordinator.Register("getTotalUnreadMsgCount", wrapperConMsg.GetTotalUnreadMsgCount)
+	coordinator.Register("findMessageList", wrapperConMsg.FindMessageList)
+
+	coordinator.Register("revokeMessage", wrapperConMsg.RevokeMessage)
+	coordinator.Register("submitInteractiveAction", wrapperConMsg.SubmitInteractiveAction)
+	coordinator.Register("typingStatusUpdate", wrapperConMsg.TypingStatusUpdate)
+	coordinator.Register("deleteMessageFromLocalStorage", wrapperConMsg.DeleteMessageFromLocalStorage)
+	coordinator.Register("deleteMessage", wrapperConMsg.DeleteMessage)
+	coordinator.Register("hideAllConversations", wrapperConMsg.HideAllConversations)
+	coordinator.Register("deleteAllMsgFromLocalAndSvr", wrapperConMsg.DeleteAllMsgFromLocalAndSvr)
+	coordinator.Register("deleteAllMsgFromLocal", wrapperConMsg.DeleteAllMsgFromLocal)
+	coordinator.Register("clearConversationAndDeleteAllMsg", wrapperConMsg.ClearConversationAndDeleteAllMsg)
+	coordinator.Register("getConversationStorageInfo", wrapperConMsg.GetConversationStorageInfo)
+	coordinator.Register("purgeConversationStorage", wrapperConMsg.PurgeConversationStorage)
+	coordinator.Register("archiveMessages", wrapperConMsg.ArchiveMessages)
+	coordinator.Register("insertSingleMessageToLocalStorage", wrapperConMsg.InsertSingleMessageToLocalStorage)
+	coordinator.Register("insertGroupMessageToLocalStorage", wrapperConMsg.InsertGroupMessageToLocalStorage)
+	coordinator.Register("searchLocalMessages", wrapperConMsg.SearchLocalMessages)
+	coordinator.Register("setMessageLocalEx", wrapperConMsg.SetMessageLocalEx)
+	coordinator.Register("searchConversation", wrapperConMsg.SearchConversation)
+
+	coordinator.Register("changeInputStates", wrapperConMsg.ChangeInputStates)
+	coordinator.Register("getInputStates", wrapperConMsg.GetInputStates)
+	coordinator.Register("getSeqGapStats", wrapperConMsg.GetSeqGapStats)
 
 	//register group func
 	wrapperGroup := wasm_wrapper.NewWrapperGroup(globalFuc)
-	js.Global().Set("createGroup", js.FuncOf(wrapperGroup.CreateGroup))
-	js.Global().Set("getSpecifiedGroupsInfo", js.FuncOf(wrapperGroup.GetSpecifiedGroupsInfo))
-	js.Global().Set("joinGroup", js.FuncOf(wrapperGroup.JoinGroup))
-	js.Global().Set("quitGroup", js.FuncOf(wrapperGroup.QuitGroup))
-	js.Global().Set("dismissGroup", js.FuncOf(wrapperGroup.DismissGroup))
-	js.Global().Set("changeGroupMute", js.FuncOf(wrapperGroup.ChangeGroupMute))
-	js.Global().Set("changeGroupMemberMute", js.FuncOf(wrapperGroup.ChangeGroupMemberMute))
-	js.Global().Set("setGroupMemberInfo", js.FuncOf(wrapperGroup.SetGroupMemberInfo))
-	js.Global().Set("getJoinedGroupList", js.FuncOf(wrapperGroup.GetJoinedGroupList))
-	js.Global().Set("getJoinedGroupListPage", js.FuncOf(wrapperGroup.GetJoinedGroupListPage))
-	js.Global().Set("searchGroups", js.FuncOf(wrapperGroup.SearchGroups))
-	js.Global().Set("setGroupInfo", js.FuncOf(wrapperGroup.SetGroupInfo))
-	js.Global().Set("getGroupMemberList", js.FuncOf(wrapperGroup.GetGroupMemberList))
-	js.Global().Set("getGroupMemberOwnerAndAdmin", js.FuncOf(wrapperGroup.GetGroupMemberOwnerAndAdmin))
-	js.Global().Set("getGroupMemberListByJoinTimeFilter", js.FuncOf(wrapperGroup.GetGroupMemberListByJoinTimeFilter))
-	js.Global().Set("getSpecifiedGroupMembersInfo", js.FuncOf(wrapperGroup.GetSpecifiedGroupMembersInfo))
-	js.Global().Set("kickGroupMember", js.FuncOf(wrapperGroup.KickGroupMember))
-	js.Global().Set("transferGroupOwner", js.FuncOf(wrapperGroup.TransferGroupOwner))
-	js.Global().Set("inviteUserToGroup", js.FuncOf(wrapperGroup.InviteUserToGroup))
-	js.Global().Set("getGroupApplicationListAsRecipient", js.FuncOf(wrapperGroup.GetGroupApplicationListAsRecipient))
-	js.Global().Set("getGroupApplicationListAsApplicant", js.FuncOf(wrapperGroup.GetGroupApplicationListAsApplicant))
-	js.Global().Set("acceptGroupApplication", js.FuncOf(wrapperGroup.AcceptGroupApplication))
-	js.Global().Set("refuseGroupApplication", js.FuncOf(wrapperGroup.RefuseGroupApplication))
-	js.Global().Set("checkLocalGroupFullSync", js.FuncOf(wrapperGroup.CheckLocalGroupFullSync))
-	js.Global().Set("checkGroupMemberFullSync", js.FuncOf(wrapperGroup.CheckGroupMemberFullSync))
-	js.Global().Set("searchGroupMembers", js.FuncOf(wrapperGroup.SearchGroupMembers))
-	js.Global().Set("isJoinGroup", js.FuncOf(wrapperGroup.IsJoinGroup))
-	js.Global().Set("getUsersInGroup", js.FuncOf(wrapperGroup.GetUsersInGroup))
-	js.Global().Set("getGroupApplicationUnhandledCount", js.FuncOf(wrapperGroup.GetGroupApplicationUnhandledCount))
+	coordinator.Register("createGroup", wrapperGroup.CreateGroup)
+	coordinator.Register("getSpecifiedGroupsInfo", wrapperGroup.GetSpecifiedGroupsInfo)
+	coordinator.Register("joinGroup", wrapperGroup.JoinGroup)
+	coordinator.Register("quitGroup", wrapperGroup.QuitGroup)
+	coordinator.Register("dismissGroup", wrapperGroup.DismissGroup)
+	coordinator.Register("changeGroupMute", wrapperGroup.ChangeGroupMute)
+	coordinator.Register("changeGroupMemberMute", wrapperGroup.ChangeGroupMemberMute)
+	coordinator.Register("setGroupMemberInfo", wrapperGroup.SetGroupMemberInfo)
+	coordinator.Register("getJoinedGroupList", wrapperGroup.GetJoinedGroupList)
+	coordinator.Register("getJoinedGroupListPage", wrapperGroup.GetJoinedGroupListPage)
+	coordinator.Register("searchGroups", wrapperGroup.SearchGroups)
+	coordinator.Register("setGroupInfo", wrapperGroup.SetGroupInfo)
+	coordinator.Register("getGroupMemberList", wrapperGroup.GetGroupMemberList)
+	coordinator.Register("getGroupMemberOwnerAndAdmin", wrapperGroup.GetGroupMemberOwnerAndAdmin)
+	coordinator.Register("getGroupMemberListByJoinTimeFilter", wrapperGroup.GetGroupMemberListByJoinTimeFilter)
+	coordinator.Register("getSpecifiedGroupMembersInfo", wrapperGroup.GetSpecifiedGroupMembersInfo)
+	coordinator.Register("kickGroupMember", wrapperGroup.KickGroupMember)
+	coordinator.Register("transferGroupOwner", wrapperGroup.TransferGroupOwner)
+	coordinator.Register("inviteUserToGroup", wrapperGroup.InviteUserToGroup)
+	coordinator.Register("getGroupApplicationListAsRecipient", wrapperGroup.GetGroupApplicationListAsRecipient)
+	coordinator.Register("getGroupApplicationListAsApplicant", wrapperGroup.GetGroupApplicationListAsApplicant)
+	coordinator.Register("acceptGroupApplication", wrapperGroup.AcceptGroupApplication)
+	coordinator.Register("refuseGroupApplication", wrapperGroup.RefuseGroupApplication)
+	coordinator.Register("checkLocalGroupFullSync", wrapperGroup.CheckLocalGroupFullSync)
+	coordinator.Register("checkGroupMemberFullSync", wrapperGroup.CheckGroupMemberFullSync)
+	coordinator.Register("searchGroupMembers", wrapperGroup.SearchGroupMembers)
+	coordinator.Register("isJoinGroup", wrapperGroup.IsJoinGroup)
+	coordinator.Register("getUsersInGroup", wrapperGroup.GetUsersInGroup)
+	coordinator.Register("getGroupApplicationUnhandledCount", wrapperGroup.GetGroupApplicationUnhandledCount)
 
 	wrapperUser := wasm_wrapper.NewWrapperUser(globalFuc)
-	js.Global().Set("getSelfUserInfo", js.FuncOf(wrapperUser.GetSelfUserInfo))
-	js.Global().Set("setSelfInfo", js.FuncOf(wrapperUser.SetSelfInfo))
-	js.Global().Set("getUsersInfo", js.FuncOf(wrapperUser.GetUsersInfo))
-	js.Global().Set("subscribeUsersStatus", js.FuncOf(wrapperUser.SubscribeUsersStatus))
-	js.Global().Set("unsubscribeUsersStatus", js.FuncOf(wrapperUser.UnsubscribeUsersStatus))
-	js.Global().Set("getSubscribeUsersStatus", js.FuncOf(wrapperUser.GetSubscribeUsersStatus))
-	js.Global().Set("getUserStatus", js.FuncOf(wrapperUser.GetUserStatus))
+	coordinator.Register("getSelfUserInfo", wrapperUser.GetSelfUserInfo)
+	coordinator.Register("setSelfInfo", wrapperUser.SetSelfInfo)
+	coordinator.Register("getUsersInfo", wrapperUser.GetUsersInfo)
+	coordinator.Register("subscribeUsersStatus", wrapperUser.SubscribeUsersStatus)
+	coordinator.Register("unsubscribeUsersStatus", wrapperUser.UnsubscribeUsersStatus)
+	coordinator.Register("getSubscribeUsersStatus", wrapperUser.GetSubscribeUsersStatus)
+	coordinator.Register("getUserStatus", wrapperUser.GetUserStatus)
+	coordinator.Register("getUserLastSeen", wrapperUser.GetUserLastSeen)
+	coordinator.Register("getPrivacySettings", wrapperUser.GetPrivacySettings)
+	coordinator.Register("setPrivacySettings", wrapperUser.SetPrivacySettings)
+	coordinator.Register("registerUserExProfileSchema", wrapperUser.RegisterUserExProfileSchema)
+	coordinator.Register("getUsersExProfile", wrapperUser.GetUsersExProfile)
+	coordinator.Register("updateSelfExProfile", wrapperUser.UpdateSelfExProfile)
 
 	wrapperFriend := wasm_wrapper.NewWrapperFriend(globalFuc)
-	js.Global().Set("getSpecifiedFriendsInfo", js.FuncOf(wrapperFriend.GetSpecifiedFriendsInfo))
-	js.Global().Set("getFriendList", js.FuncOf(wrapperFriend.GetFriendList))
-	js.Global().Set("getFriendListPage", js.FuncOf(wrapperFriend.GetFriendListPage))
-	js.Global().Set("searchFriends", js.FuncOf(wrapperFriend.SearchFriends))
-	js.Global().Set("checkFriend", js.FuncOf(wrapperFriend.CheckFriend))
-	js.Global().Set("addFriend", js.FuncOf(wrapperFriend.AddFriend))
-	js.Global().Set("updateFriends", js.FuncOf(wrapperFriend.UpdateFriends))
-	js.Global().Set("deleteFriend", js.FuncOf(wrapperFriend.DeleteFriend))
-	js.Global().Set("getFriendApplicationListAsRecipient", js.FuncOf(wrapperFriend.GetFriendApplicationListAsRecipient))
-	js.Global().Set("getFriendApplicationListAsApplicant", js.FuncOf(wrapperFriend.GetFriendApplicationListAsApplicant))
-	js.Global().Set("acceptFriendApplication", js.FuncOf(wrapperFriend.AcceptFriendApplication))
-	js.Global().Set("refuseFriendApplication", js.FuncOf(wrapperFriend.RefuseFriendApplication))
-	js.Global().Set("getBlackList", js.FuncOf(wrapperFriend.GetBlackList))
-	js.Global().Set("removeBlack", js.FuncOf(wrapperFriend.RemoveBlack))
-	js.Global().Set("addBlack", js.FuncOf(wrapperFriend.AddBlack))
-	js.Global().Set("getFriendApplicationUnhandledCount", js.FuncOf(wrapperFriend.GetFriendApplicationUnhandledCount))
+	coordinator.Register("getSpecifiedFriendsInfo", wrapperFriend.GetSpecifiedFriendsInfo)
+	coordinator.Register("getFriendList", wrapperFriend.GetFriendList)
+	coordinator.Register("getFriendListPage", wrapperFriend.GetFriendListPage)
+	coordinator.Register("searchFriends", wrapperFriend.SearchFriends)
+	coordinator.Register("checkFriend", wrapperFriend.CheckFriend)
+	coordinator.Register("addFriend", wrapperFriend.AddFriend)
+	coordinator.Register("updateFriends", wrapperFriend.UpdateFriends)
+	coordinator.Register("deleteFriend", wrapperFriend.DeleteFriend)
+	coordinator.Register("getFriendApplicationListAsRecipient", wrapperFriend.GetFriendApplicationListAsRecipient)
+	coordinator.Register("getFriendApplicationListAsApplicant", wrapperFriend.GetFriendApplicationListAsApplicant)
+	coordinator.Register("acceptFriendApplication", wrapperFriend.AcceptFriendApplication)
+	coordinator.Register("refuseFriendApplication", wrapperFriend.RefuseFriendApplication)
+	coordinator.Register("getBlackList", wrapperFriend.GetBlackList)
+	coordinator.Register("removeBlack", wrapperFriend.RemoveBlack)
+	coordinator.Register("addBlack", wrapperFriend.AddBlack)
+	coordinator.Register("getFriendApplicationUnhandledCount", wrapperFriend.GetFriendApplicationUnhandledCount)
 
 	wrapperThird := wasm_wrapper.NewWrapperThird(globalFuc)
-	js.Global().Set("updateFcmToken", js.FuncOf(wrapperThird.UpdateFcmToken))
-	js.Global().Set("uploadFile", js.FuncOf(wrapperThird.UploadFile))
+	coordinator.Register("updateFcmToken", wrapperThird.UpdateFcmToken)
+	coordinator.Register("setOfflinePushToken", wrapperThird.SetOfflinePushToken)
 
+	for _, register := range moduleFuncs {
+		register(coordinator, globalFuc)
+	}
 }
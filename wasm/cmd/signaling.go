@@ -0,0 +1,39 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build js && wasm && !no_signaling
+// +build js,wasm,!no_signaling
+
+package main
+
+import (
+	"github.com/openimsdk/openim-sdk-core/v3/wasm/tabs"
+	"github.com/openimsdk/openim-sdk-core/v3/wasm/wasm_wrapper"
+)
+
+func init() {
+	moduleFuncs = append(moduleFuncs, func(coordinator *tabs.Coordinator, globalFuc *wasm_wrapper.WrapperCommon) {
+		wrapperSignaling := wasm_wrapper.NewWrapperSignaling(globalFuc)
+		coordinator.Register("signalingInvite", wrapperSignaling.SignalingInvite)
+		coordinator.Register("signalingInviteInGroup", wrapperSignaling.SignalingInviteInGroup)
+		coordinator.Register("signalingJoin", wrapperSignaling.SignalingJoin)
+		coordinator.Register("signalingGetRoomByGroupID", wrapperSignaling.SignalingGetRoomByGroupID)
+		coordinator.Register("signalingAccept", wrapperSignaling.SignalingAccept)
+		coordinator.Register("signalingReject", wrapperSignaling.SignalingReject)
+		coordinator.Register("signalingCancel", wrapperSignaling.SignalingCancel)
+		coordinator.Register("signalingHungUp", wrapperSignaling.SignalingHungUp)
+		coordinator.Register("sendSignalingData", wrapperSignaling.SendSignalingData)
+		coordinator.Register("getCallRecords", wrapperSignaling.GetCallRecords)
+	})
+}
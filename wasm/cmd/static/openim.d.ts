@@ -0,0 +1,1411 @@
+// Code generated by tools/wasmgen. DO NOT EDIT.
+
+/** What the promises reject with. */
+export interface OpenIMError {
+  errCode: number;
+  errMsg: string;
+  operationID: string;
+}
+
+/** Elect the tab owning the connection among the tabs sharing the channel, before initSDK. */
+export function enableMultiTab(channelName?: string): Promise<"leader" | "follower">;
+
+/** Whether the tab owns the connection, true while the tabs are not coordinated. */
+export function isLeaderTab(): Promise<boolean>;
+
+export function initSDK(operationID: string, config: string): Promise<boolean>;
+
+export function login(operationID: string, userID: string, token: string): Promise<void>;
+
+/** Log in with a temporary identity issued by the server. The guest can only join and send to the designated group conversations, with a limited number of messages per minute. The SDK only refuses the calls a guest is not allowed to make early, the server must enforce the same restrictions on the guest token. */
+export function guestLogin(operationID: string): Promise<GuestLoginInfo>;
+
+export function logout(operationID: string): Promise<void>;
+
+export function getLoginStatus(operationID: string): Promise<number>;
+
+/** Get the bytes of the long connection and the api requests before and after compression. */
+export function getTrafficStats(operationID: string): Promise<string>;
+
+/** Get the count, rows and durations of the queries of the local database by their shape, the most time spent first. Only recorded when DBInstrumentation is set in the config. */
+export function getDBQueryStats(operationID: string): Promise<string>;
+
+/** Get the current time of the server clock in milliseconds, the SDK corrects the local clock with the offset sampled from the server after connecting and periodically. */
+export function getServerTime(operationID: string): Promise<number>;
+
+/** Get the round trip time, jitter, loss rate and throughput of the long connection. */
+export function getNetworkQuality(operationID: string): Promise<NetworkQuality>;
+
+/** Reconnect right away when the app knows the network is back, instead of waiting for the next attempt. Also restarts reconnecting after the connection state became failed. */
+export function forceReconnect(operationID: string): Promise<void>;
+
+/** Set the bytes per second the SDK may transfer in total and per category, 0 is unlimited. Applies right away to the transfers in progress. Can be called before login. */
+export function setBandwidthLimit(operationID: string, bandwidthLimit: BandwidthLimit): Promise<void>;
+
+/** Get the bandwidth limits set by the config or SetBandwidthLimit. */
+export function getBandwidthLimit(operationID: string): Promise<BandwidthLimit>;
+
+/** Tell the SDK the class of the network: wifi, cellular or metered. On cellular and metered networks the backfill of old messages and the media prefetch wait until wifi or AllowMeteredTransfer. Can be called before login. */
+export function setNetworkClass(operationID: string, class_: string): Promise<void>;
+
+/** Free the memory of the in-memory caches on a memory warning of the platform: TrimMemoryModerate removes the expired entries and half of the others, TrimMemoryCritical removes them all. The entries removed are loaded again from the local database when used. Can be called before login. */
+export function trimMemory(operationID: string, level: number): Promise<void>;
+
+/** Override the templates of the texts the SDK generates in the locale, a json object of the templates by key, see pkg/i18n for the keys and their arguments. An empty template restores the built-in one. Can be called before login. */
+export function setLocalizedTemplates(operationID: string, locale: string, templates: Record<string, string>): Promise<void>;
+
+/** Get the text of the key in the locale of the config, e.g. the label of the edited messages, args is a json object of its arguments. */
+export function getLocalizedText(operationID: string, key: string, args: string): Promise<string>;
+
+/** Allow or defer again the backfill or the media prefetch on a metered network. Can be called before login. */
+export function allowMeteredTransfer(operationID: string, kind: string, allow: boolean): Promise<void>;
+
+export function setAppBackgroundStatus(operationID: string, isBackground: boolean): Promise<void>;
+
+/** Call when the app goes to background instead of SetAppBackgroundStatus. Besides telling the server, the SDK slows the heartbeat down, stops filling the gaps of the pushed messages and writes the buffered ones right away, as the app may be suspended at any time. */
+export function enterBackground(operationID: string): Promise<void>;
+
+/** Call when the app comes back to foreground, the SDK syncs what was missed in background and calls OnSyncCaughtUp of the app lifecycle listener once done. */
+export function enterForeground(operationID: string): Promise<void>;
+
+/** Log out the current account and log in userID without the cold start of a login, the database of an account switched away from is kept open. The token may be empty for an account switched away from before, its last token is used. Can be called logged out. */
+export function switchAccount(operationID: string, userID: string, token: string): Promise<void>;
+
+/** Get the bytes taken by the local database and the media files, and the storage quota. */
+export function getStorageUsage(operationID: string): Promise<StorageUsage>;
+
+/** Drop the sync versions of the scopes, a json array of conversations, friends, groups and messages, all of them when empty, and pull them again from the server. For when the local data went wrong. */
+export function forceResyncAll(operationID: string, scopes: string[]): Promise<void>;
+
+export function networkStatusChanged(operationID: string): Promise<void>;
+
+/** Replace the token of the logged in user without logging in again. Requests and reconnections use the new token from now on. */
+export function refreshToken(operationID: string, token: string): Promise<void>;
+
+/** Create a QR code for logging in this device by scanning it with a logged-in device, can be called before login. */
+export function createLoginQRCode(operationID: string): Promise<QRLoginCode>;
+
+/** Stop waiting for the QR code created by this device to be scanned. */
+export function cancelLoginQRCode(operationID: string): Promise<void>;
+
+/** Notify the device showing the QR code that it was scanned. */
+export function scanLoginQRCode(operationID: string, payload: string): Promise<number>;
+
+/** Approve the login of the device showing the QR code. */
+export function approveLoginQRCode(operationID: string, payload: string): Promise<void>;
+
+/** Reject the login of the device showing the QR code. */
+export function rejectLoginQRCode(operationID: string, payload: string): Promise<void>;
+
+export function createTextMessage(operationID: string, text: string): Promise<MsgStruct>;
+
+export function createImageMessage(operationID: string, imagePath: string): Promise<MsgStruct>;
+
+export function createImageMessageByURL(operationID: string, sourcePath: string, sourcePicture: PictureBaseInfo, bigPicture: PictureBaseInfo, snapshotPicture: PictureBaseInfo): Promise<MsgStruct>;
+
+export function createSoundMessageByURL(operationID: string, soundBaseInfo: SoundBaseInfo): Promise<MsgStruct>;
+
+export function createVideoMessageByURL(operationID: string, videoBaseInfo: VideoBaseInfo): Promise<MsgStruct>;
+
+export function createFileMessageByURL(operationID: string, fileBaseInfo: FileBaseInfo): Promise<MsgStruct>;
+
+export function createCustomMessage(operationID: string, data: string, extension: string, description: string): Promise<MsgStruct>;
+
+export function createQuoteMessage(operationID: string, text: string, message: MsgStruct): Promise<MsgStruct>;
+
+export function createAdvancedQuoteMessage(operationID: string, text: string, message: MsgStruct, messageEntityList: MessageEntity[]): Promise<MsgStruct>;
+
+export function createAdvancedTextMessage(operationID: string, text: string, messageEntityList: MessageEntity[]): Promise<MsgStruct>;
+
+export function createCardMessage(operationID: string, cardInfo: CardElem): Promise<MsgStruct>;
+
+export function createTextAtMessage(operationID: string, text: string, atUserList: string[], atUsersInfo: AtInfo[], message: MsgStruct): Promise<MsgStruct>;
+
+export function createVideoMessage(operationID: string, videoPath: string, videoType: string, duration: number, snapshotPath: string): Promise<MsgStruct>;
+
+export function createFileMessage(operationID: string, filePath: string, fileName: string): Promise<MsgStruct>;
+
+export function createMergerMessage(operationID: string, messageList: MsgStruct[], title: string, summaryList: string[]): Promise<MsgStruct>;
+
+export function createFaceMessage(operationID: string, index: number, data: string): Promise<MsgStruct>;
+
+export function createForwardMessage(operationID: string, m: MsgStruct): Promise<MsgStruct>;
+
+export function createLocationMessage(operationID: string, description: string, longitude: number, latitude: number): Promise<MsgStruct>;
+
+export function createVideoMessageFromFullPath(operationID: string, videoFullPath: string, videoType: string, duration: number, snapshotFullPath: string): Promise<MsgStruct>;
+
+export function createImageMessageFromFullPath(operationID: string, imageFullPath: string): Promise<MsgStruct>;
+
+export function createSoundMessageFromFullPath(operationID: string, soundPath: string, duration: number): Promise<MsgStruct>;
+
+export function createFileMessageFromFullPath(operationID: string, fileFullPath: string, fileName: string): Promise<MsgStruct>;
+
+export function createSoundMessage(operationID: string, soundPath: string, duration: number): Promise<MsgStruct>;
+
+export function getAtAllTag(operationID: string): Promise<string>;
+
+/** mark as read */
+export function markConversationMessageAsRead(operationID: string, conversationID: string): Promise<void>;
+
+export function markAllConversationMessageAsRead(operationID: string): Promise<void>;
+
+export function markMessagesAsReadByMsgID(operationID: string, conversationID: string, clientMsgIDs: string[]): Promise<void>;
+
+export function sendMessage(operationID: string, message: MsgStruct, recvID: string, groupID: string, offlinePushInfo: OfflinePushInfo, isOnlineOnly: boolean): Promise<MsgStruct>;
+
+export function sendMessageNotOss(operationID: string, message: MsgStruct, recvID: string, groupID: string, offlinePushInfo: OfflinePushInfo, isOnlineOnly: boolean): Promise<MsgStruct>;
+
+export function getAllConversationList(operationID: string): Promise<LocalConversation[]>;
+
+export function getConversationListSplit(operationID: string, offset: number, count: number): Promise<LocalConversation[]>;
+
+export function getOneConversation(operationID: string, sessionType: number, sourceID: string): Promise<LocalConversation>;
+
+export function deleteConversationAndDeleteAllMsg(operationID: string, conversationID: string): Promise<void>;
+
+export function getAdvancedHistoryMessageList(operationID: string, getMessageOptions: GetAdvancedHistoryMessageListParams): Promise<GetAdvancedHistoryMessageListCallback>;
+
+export function getAdvancedHistoryMessageListReverse(operationID: string, getMessageOptions: GetAdvancedHistoryMessageListParams): Promise<GetAdvancedHistoryMessageListCallback>;
+
+export function getMultipleConversation(operationID: string, conversationIDList: string[]): Promise<LocalConversation[]>;
+
+export function hideConversation(operationID: string, conversationID: string): Promise<void>;
+
+export function setConversationDraft(operationID: string, conversationID: string, draftText: string): Promise<void>;
+
+export function setConversation(operationID: string, conversationID: string, req: ConversationReq): Promise<void>;
+
+export function getTotalUnreadMsgCount(operationID: string): Promise<number>;
+
+export function findMessageList(operationID: string, findMessageOptions: ConversationArgs[]): Promise<FindMessageListCallback>;
+
+export function revokeMessage(operationID: string, conversationID: string, clientMsgID: string): Promise<void>;
+
+/** Route the action of an element of the interactive card to its bot, the payload of a menu is the value of the option chosen. The card edited by the bot comes with OnMsgEdited. */
+export function submitInteractiveAction(operationID: string, conversationID: string, clientMsgID: string, actionID: string, payload: string): Promise<void>;
+
+export function typingStatusUpdate(operationID: string, recvID: string, msgTip: string): Promise<void>;
+
+export function deleteMessageFromLocalStorage(operationID: string, conversationID: string, clientMsgID: string): Promise<void>;
+
+export function deleteMessage(operationID: string, conversationID: string, clientMsgID: string): Promise<void>;
+
+export function hideAllConversations(operationID: string): Promise<void>;
+
+export function deleteAllMsgFromLocalAndSvr(operationID: string): Promise<void>;
+
+export function deleteAllMsgFromLocal(operationID: string): Promise<void>;
+
+export function clearConversationAndDeleteAllMsg(operationID: string, conversationID: string): Promise<void>;
+
+/** Get the number of messages of the conversation in the local database, an estimate of their bytes and the bytes of their media files. */
+export function getConversationStorageInfo(operationID: string, conversationID: string): Promise<ConversationStorageInfo>;
+
+/** Remove the media files and, if asked, the messages of the conversation from the local storage, the messages stay on the server. The media of the removed messages are removed with them. */
+export function purgeConversationStorage(operationID: string, conversationID: string, options: PurgeStorageOptions): Promise<PurgeStorageResult>;
+
+/** Move the messages sent before beforeTime, in milliseconds, to the archive database next to the local database. The history still reads them, the archive is attached on demand, and the local database stays small. Returns the number of messages archived. */
+export function archiveMessages(operationID: string, beforeTime: number): Promise<number>;
+
+export function insertSingleMessageToLocalStorage(operationID: string, message: MsgStruct, recvID: string, sendID: string): Promise<MsgStruct>;
+
+export function insertGroupMessageToLocalStorage(operationID: string, message: MsgStruct, groupID: string, sendID: string): Promise<MsgStruct>;
+
+export function searchLocalMessages(operationID: string, searchParam: SearchLocalMessagesParams): Promise<SearchLocalMessagesCallback>;
+
+export function setMessageLocalEx(operationID: string, conversationID: string, clientMsgID: string, localEx: string): Promise<void>;
+
+export function searchConversation(operationID: string, searchParam: string): Promise<Conversation[]>;
+
+export function changeInputStates(operationID: string, conversationID: string, focus: boolean): Promise<void>;
+
+export function getInputStates(operationID: string, conversationID: string, userID: string): Promise<number[]>;
+
+/** Get the gaps found in the messages of the conversations since login, the repaired and the pending ones. */
+export function getSeqGapStats(operationID: string): Promise<SeqGapStats>;
+
+export function createGroup(operationID: string, groupReqInfo: CreateGroupReq): Promise<GroupInfo>;
+
+export function getSpecifiedGroupsInfo(operationID: string, groupIDList: string[]): Promise<LocalGroup[]>;
+
+export function joinGroup(operationID: string, groupID: string, reqMsg: string, joinSource: number, ex: string): Promise<void>;
+
+export function quitGroup(operationID: string, groupID: string): Promise<void>;
+
+export function dismissGroup(operationID: string, groupID: string): Promise<void>;
+
+export function changeGroupMute(operationID: string, groupID: string, isMute: boolean): Promise<void>;
+
+export function changeGroupMemberMute(operationID: string, groupID: string, userID: string, mutedSeconds: number): Promise<void>;
+
+export function setGroupMemberInfo(operationID: string, groupMemberInfo: SetGroupMemberInfo): Promise<void>;
+
+export function getJoinedGroupList(operationID: string): Promise<LocalGroup[]>;
+
+export function getJoinedGroupListPage(operationID: string, offset: number, count: number): Promise<LocalGroup[]>;
+
+export function searchGroups(operationID: string, searchParam: SearchGroupsParam): Promise<LocalGroup[]>;
+
+export function setGroupInfo(operationID: string, groupInfo: SetGroupInfoExReq): Promise<void>;
+
+export function getGroupMemberList(operationID: string, groupID: string, filter: number, offset: number, count: number): Promise<LocalGroupMember[]>;
+
+export function getGroupMemberOwnerAndAdmin(operationID: string, groupID: string): Promise<LocalGroupMember[]>;
+
+export function getGroupMemberListByJoinTimeFilter(operationID: string, groupID: string, offset: number, count: number, joinTimeBegin: number, joinTimeEnd: number, filterUserIDList: string[]): Promise<LocalGroupMember[]>;
+
+export function getSpecifiedGroupMembersInfo(operationID: string, groupID: string, userIDList: string[]): Promise<LocalGroupMember[]>;
+
+export function kickGroupMember(operationID: string, groupID: string, reason: string, userIDList: string[]): Promise<void>;
+
+export function transferGroupOwner(operationID: string, groupID: string, newOwnerUserID: string): Promise<void>;
+
+export function inviteUserToGroup(operationID: string, groupID: string, reason: string, userIDList: string[]): Promise<void>;
+
+export function getGroupApplicationListAsRecipient(operationID: string, req: GetGroupApplicationListAsRecipientReq): Promise<LocalGroupRequest[]>;
+
+export function getGroupApplicationListAsApplicant(operationID: string, req: GetGroupApplicationListAsApplicantReq): Promise<LocalGroupRequest[]>;
+
+export function acceptGroupApplication(operationID: string, groupID: string, fromUserID: string, handleMsg: string): Promise<void>;
+
+export function refuseGroupApplication(operationID: string, groupID: string, fromUserID: string, handleMsg: string): Promise<void>;
+
+export function checkLocalGroupFullSync(operationID: string): Promise<boolean>;
+
+export function checkGroupMemberFullSync(operationID: string, groupID: string): Promise<boolean>;
+
+export function searchGroupMembers(operationID: string, searchParam: SearchGroupMembersParam): Promise<LocalGroupMember[]>;
+
+export function isJoinGroup(operationID: string, groupID: string): Promise<boolean>;
+
+export function getUsersInGroup(operationID: string, groupID: string, userIDList: string[]): Promise<string[]>;
+
+export function getGroupApplicationUnhandledCount(operationID: string, req: GetGroupApplicationUnhandledCountReq): Promise<number>;
+
+/** obtains the user's own information. */
+export function getSelfUserInfo(operationID: string): Promise<LocalUser>;
+
+/** sets the user's own information. */
+export function setSelfInfo(operationID: string, userInfo: UserInfoWithEx): Promise<void>;
+
+export function getUsersInfo(operationID: string, userIDs: string[]): Promise<PublicUser[]>;
+
+/** Presence status of subscribed users. */
+export function subscribeUsersStatus(operationID: string, userIDs: string[]): Promise<OnlineStatus[]>;
+
+/** Unsubscribe a user's presence. */
+export function unsubscribeUsersStatus(operationID: string, userIDs: string[]): Promise<void>;
+
+/** Get the online status of subscribers. */
+export function getSubscribeUsersStatus(operationID: string): Promise<OnlineStatus[]>;
+
+/** Get the online status of users. */
+export function getUserStatus(operationID: string, userIDs: string[]): Promise<OnlineStatus[]>;
+
+/** Get when users were last seen, rounded to the precision configured at init. */
+export function getUserLastSeen(operationID: string, userIDs: string[]): Promise<UserLastSeen[]>;
+
+/** obtains the login user's privacy settings. */
+export function getPrivacySettings(operationID: string): Promise<LocalPrivacySettings>;
+
+/** sets the login user's privacy settings and syncs them to the other devices. The add friend permission and the visibilities are published in the user ex field for the other users to respect. */
+export function setPrivacySettings(operationID: string, settings: LocalPrivacySettings): Promise<void>;
+
+/** registers the typed fields stored in the user ex field. */
+export function registerUserExProfileSchema(operationID: string, fields: Field[]): Promise<void>;
+
+/** obtains the typed ex profile of the specified users. */
+export function getUsersExProfile(operationID: string, userIDs: string[]): Promise<Record<string, Record<string, unknown>>>;
+
+/** partially updates the user's own ex profile. */
+export function updateSelfExProfile(operationID: string, fields: Record<string, unknown>): Promise<void>;
+
+export function getSpecifiedFriendsInfo(operationID: string, userIDList: string[], filterBlack: boolean): Promise<LocalFriend[]>;
+
+export function getFriendList(operationID: string, filterBlack: boolean): Promise<LocalFriend[]>;
+
+export function getFriendListPage(operationID: string, offset: number, count: number, filterBlack: boolean): Promise<LocalFriend[]>;
+
+export function searchFriends(operationID: string, searchParam: SearchFriendsParam): Promise<SearchFriendItem[]>;
+
+export function checkFriend(operationID: string, userIDList: string[]): Promise<UserIDResult[]>;
+
+export function addFriend(operationID: string, userIDReqMsg: ApplyToAddFriendReq): Promise<void>;
+
+export function updateFriends(operationID: string, req: UpdateFriendsReq): Promise<void>;
+
+export function deleteFriend(operationID: string, friendUserID: string): Promise<void>;
+
+export function getFriendApplicationListAsRecipient(operationID: string, req: GetFriendApplicationListAsRecipientReq): Promise<LocalFriendRequest[]>;
+
+export function getFriendApplicationListAsApplicant(operationID: string, req: GetFriendApplicationListAsApplicantReq): Promise<LocalFriendRequest[]>;
+
+export function acceptFriendApplication(operationID: string, userIDHandleMsg: ProcessFriendApplicationParams): Promise<void>;
+
+export function refuseFriendApplication(operationID: string, userIDHandleMsg: ProcessFriendApplicationParams): Promise<void>;
+
+export function getBlackList(operationID: string): Promise<LocalBlack[]>;
+
+export function removeBlack(operationID: string, removeUserID: string): Promise<void>;
+
+export function addBlack(operationID: string, blackUserID: string, ex: string): Promise<void>;
+
+export function getFriendApplicationUnhandledCount(operationID: string, req: GetSelfUnhandledApplyCountReq): Promise<number>;
+
+export function updateFcmToken(operationID: string, fcmToken: string, expireTime: number): Promise<void>;
+
+export function setOfflinePushToken(operationID: string, platform: number, token: string, provider: string): Promise<void>;
+
+export function uploadFile(operationID: string, ...args: unknown[]): Promise<unknown>;
+
+/** Call a user, the invitation is returned with its room ID which the other calls take. The SignalingListener tells the answer, and the call message is inserted in the conversation once it is over. */
+export function signalingInvite(operationID: string, invitation: SignalingInvitation): Promise<SignalingInvitation>;
+
+/** Start a call in the group, the invitees are rung and the other members can join it. No invitees rings all the members of a small group. */
+export function signalingInviteInGroup(operationID: string, invitation: SignalingInvitation): Promise<SignalingInvitation>;
+
+/** Join the call of a group, the roster changes are told by the SignalingListener. */
+export function signalingJoin(operationID: string, roomID: string, customData: string): Promise<void>;
+
+/** Get the call of the group with the users in it. */
+export function signalingGetRoomByGroupID(operationID: string, groupID: string): Promise<SignalingRoom>;
+
+/** Answer the call the user is invited to, the app then joins the room with its media engine. */
+export function signalingAccept(operationID: string, roomID: string, customData: string): Promise<void>;
+
+/** Decline the call the user is invited to. */
+export function signalingReject(operationID: string, roomID: string, customData: string): Promise<void>;
+
+/** Call off the call of the user before it is answered. */
+export function signalingCancel(operationID: string, roomID: string, customData: string): Promise<void>;
+
+/** End the call in progress, or leave the call of a group. */
+export function signalingHungUp(operationID: string, roomID: string, customData: string): Promise<void>;
+
+/** Send the payload to the online devices of the user, it is neither stored nor pushed offline. */
+export function sendSignalingData(operationID: string, toUserID: string, payload: string): Promise<void>;
+
+/** Get the page of the local call records of the filter following the opaque cursor, the latest calls first. */
+export function getCallRecords(operationID: string, filter: CallRecordFilter, cursor: string, count: number): Promise<GetCallRecordsCallback>;
+
+export interface OpenIMEventMap {
+  OnConnecting: undefined;
+  OnConnectSuccess: undefined;
+  OnConnectFailed: undefined;
+  OnKickedOffline: undefined;
+  OnUserTokenExpired: undefined;
+  OnUserTokenInvalid: string;
+  OnUserCommandAdd: string;
+  OnUserCommandDelete: string;
+  OnUserCommandUpdate: string;
+  OnSyncServerStart: boolean;
+  OnSyncServerFinish: boolean;
+  OnSyncServerProgress: number;
+  OnSyncServerFailed: boolean;
+  OnNewConversation: LocalConversation[];
+  OnConversationChanged: LocalConversation[];
+  OnTotalUnreadMessageCountChanged: number;
+  OnConversationUserInputStatusChanged: InputStatesChangedData;
+  OnRecvNewMessage: MsgStruct;
+  OnRecvC2CReadReceipt: MessageReceipt[];
+  OnRecvGroupReadReceipt: MessageReceipt[];
+  OnRecvMessageRevoked: string;
+  OnNewRecvMessageRevoked: MessageRevoked;
+  OnRecvMessageModified: MsgStruct;
+  OnRecvMessageExtensionsChanged: Record<string, unknown>;
+  OnRecvMessageExtensionsDeleted: Record<string, unknown>;
+  OnRecvMessageExtensionsAdded: Record<string, unknown>;
+  OnRecvOfflineNewMessage: MsgStruct;
+  OnMsgDeleted: MsgStruct;
+  OnRecvOnlineOnlyMessage: MsgStruct;
+  OnMsgEdited: MsgStruct;
+  OnProgress: Record<string, unknown>;
+  OnUploadSpeed: Record<string, unknown>;
+  Open: Record<string, unknown>;
+  PartSize: Record<string, unknown>;
+  HashPartProgress: Record<string, unknown>;
+  HashPartComplete: Record<string, unknown>;
+  UploadID: Record<string, unknown>;
+  UploadPartComplete: Record<string, unknown>;
+  UploadComplete: Record<string, unknown>;
+  Complete: Record<string, unknown>;
+  OnFriendApplicationAdded: LocalFriendRequest;
+  OnFriendApplicationDeleted: LocalFriendRequest;
+  OnFriendApplicationAccepted: LocalFriendRequest;
+  OnFriendApplicationRejected: LocalFriendRequest;
+  OnFriendAdded: LocalFriend;
+  OnFriendDeleted: LocalFriend;
+  OnFriendInfoChanged: LocalFriend;
+  OnBlackAdded: LocalBlack;
+  OnBlackDeleted: LocalBlack;
+  OnJoinedGroupAdded: LocalGroup;
+  OnJoinedGroupDeleted: LocalGroup;
+  OnGroupMemberAdded: LocalGroupMember;
+  OnGroupMemberDeleted: LocalGroupMember;
+  OnGroupApplicationAdded: LocalGroupRequest;
+  OnGroupApplicationDeleted: LocalGroupRequest;
+  OnGroupInfoChanged: LocalGroup;
+  OnGroupMemberInfoChanged: LocalGroupMember;
+  OnGroupApplicationAccepted: LocalGroupRequest;
+  OnGroupApplicationRejected: LocalGroupRequest;
+  OnGroupDismissed: LocalGroup;
+  OnUserStatusChanged: OnlineStatus;
+  OnUserInputStatusChanged: string;
+  OnSelfInfoUpdated: LocalUser;
+  OnRecvCustomBusinessMessage: string;
+  OnQRLoginStateChanged: QRLoginState;
+  OnTokenWillExpire: number;
+  OnConnStateChanged: ConnState;
+  OnNetworkQualityChanged: NetworkQuality;
+  OnSyncCaughtUp: undefined;
+  OnSyncProgress: SyncProgress;
+  OnSyncConflict: SyncConflict;
+  OnRoomParticipantConnected: string;
+  OnRoomParticipantDisconnected: string;
+  OnReceiveSignalingData: string;
+  OnReceiveNewInvitation: string;
+  OnInviteeAccepted: string;
+  OnInviteeAcceptedByOtherDevice: string;
+  OnInviteeRejected: string;
+  OnInviteeRejectedByOtherDevice: string;
+  OnInviteeBusy: string;
+  OnInvitationCancelled: string;
+  OnInvitationTimeout: string;
+  OnHangUp: string;
+}
+
+export type OpenIMEvent = {
+  [K in keyof OpenIMEventMap]: {
+    event: K;
+    errCode: number;
+    errMsg: string;
+    data: OpenIMEventMap[K];
+    operationID: string;
+  };
+}[keyof OpenIMEventMap];
+
+/** Receive the events of the listeners, the data decoded. */
+export function onEvent(handler: (event: OpenIMEvent) => void): void;
+
+export interface GuestLoginInfo {
+  userID: string;
+  groupIDs: string[];
+  sendLimit: number;
+}
+
+export interface NetworkQuality {
+  rtt: number;
+  jitter: number;
+  lossRate: number;
+  throughput: number;
+  weak: boolean;
+}
+
+export interface BandwidthLimit {
+  global: number;
+  sync: number;
+  upload: number;
+  download: number;
+}
+
+export interface StorageUsage {
+  database: number;
+  databaseFree: number;
+  media: number;
+  mediaFiles: number;
+  total: number;
+  quota: number;
+}
+
+export interface QRLoginCode {
+  qrID: string;
+  payload: string;
+  expireTime: number;
+}
+
+export interface OfflinePushInfo {
+  title: string;
+  desc: string;
+  ex: string;
+  iOSPushSound: string;
+  iOSBadgeCount: boolean;
+  signalInfo: string;
+}
+
+export interface TextElem {
+  content: string;
+}
+
+export interface CardElem {
+  userID: string;
+  nickname: string;
+  faceURL: string;
+  ex: string;
+}
+
+export interface PictureBaseInfo {
+  uuid?: string;
+  type?: string;
+  size: number;
+  width: number;
+  height: number;
+  url?: string;
+  md5?: string;
+}
+
+export interface PictureElem {
+  sourcePath?: string;
+  sourcePicture?: PictureBaseInfo;
+  bigPicture?: PictureBaseInfo;
+  snapshotPicture?: PictureBaseInfo;
+}
+
+export interface SoundElem {
+  uuid?: string;
+  soundPath?: string;
+  sourceUrl?: string;
+  dataSize: number;
+  duration: number;
+  soundType?: string;
+  md5?: string;
+}
+
+export interface VideoElem {
+  videoPath?: string;
+  videoUUID?: string;
+  videoUrl?: string;
+  videoType?: string;
+  videoSize: number;
+  duration: number;
+  snapshotPath?: string;
+  snapshotUUID?: string;
+  snapshotSize: number;
+  snapshotUrl?: string;
+  snapshotWidth: number;
+  snapshotHeight: number;
+  snapshotType?: string;
+  videoMd5?: string;
+  snapshotMd5?: string;
+}
+
+export interface FileElem {
+  filePath?: string;
+  uuid?: string;
+  sourceUrl?: string;
+  fileName?: string;
+  fileSize: number;
+  fileType?: string;
+  fileMd5?: string;
+}
+
+export interface MessageEntity {
+  type?: string;
+  offset: number;
+  length: number;
+  url?: string;
+  ex?: string;
+}
+
+export interface MergeElem {
+  title?: string;
+  abstractList?: string[];
+  multiMessage?: MsgStruct[];
+  messageEntityList?: MessageEntity[];
+}
+
+export interface AtInfo {
+  atUserID?: string;
+  groupNickname?: string;
+}
+
+export interface AtTextElem {
+  text?: string;
+  atUserList?: string[];
+  atUsersInfo?: AtInfo[];
+  quoteMessage?: MsgStruct;
+  isAtSelf: boolean;
+}
+
+export interface FaceElem {
+  index: number;
+  data?: string;
+}
+
+export interface LocationElem {
+  description?: string;
+  longitude: number;
+  latitude: number;
+}
+
+export interface CustomElem {
+  data?: string;
+  description?: string;
+  extension?: string;
+}
+
+export interface QuoteElem {
+  text?: string;
+  quoteMessage?: MsgStruct;
+  messageEntityList?: MessageEntity[];
+}
+
+export interface NotificationElem {
+  detail?: string;
+  tips?: string;
+}
+
+export interface AdvancedTextElem {
+  text?: string;
+  messageEntityList?: MessageEntity[];
+}
+
+export interface TypingElem {
+  msgTips?: string;
+}
+
+export interface GroupHasReadInfo {
+  hasReadUserIDList?: string[];
+  hasReadCount: number;
+  groupMemberCount: number;
+}
+
+export interface UploadProgress {
+  total: number;
+  save: number;
+  current: number;
+  uploadID: string;
+  speed: number;
+  remainingTime: number;
+}
+
+export interface MsgSignature {
+  deviceID: string;
+  signature: string;
+}
+
+export interface MsgRestrictions {
+  noForward?: boolean;
+  noCopy?: boolean;
+  viewOnce?: boolean;
+}
+
+export interface MsgModeration {
+  action: string;
+  reason?: string;
+  labels?: string[];
+}
+
+export interface AttachedInfoElem {
+  groupHasReadInfo?: GroupHasReadInfo;
+  isPrivateChat: boolean;
+  burnDuration: number;
+  hasReadTime: number;
+  messageEntityList?: MessageEntity[];
+  isEncryption: boolean;
+  inEncryptStatus: boolean;
+  uploadProgress?: UploadProgress;
+  signature?: MsgSignature;
+  signatureStatus?: number;
+  sensitiveWords?: string[];
+  restrictions?: MsgRestrictions;
+  viewed?: boolean;
+  moderation?: MsgModeration;
+}
+
+export interface MarkdownTextElem {
+  content: string;
+}
+
+export interface CallElem {
+  roomID: string;
+  inviterUserID: string;
+  mediaType: string;
+  state: string;
+  duration: number;
+  missed?: boolean;
+}
+
+export interface InteractiveOption {
+  value: string;
+  text: string;
+}
+
+export interface InteractiveElement {
+  actionID: string;
+  type: string;
+  text: string;
+  style?: string;
+  url?: string;
+  options?: InteractiveOption[];
+  disabled?: boolean;
+}
+
+export interface InteractiveCardElem {
+  title: string;
+  content: string;
+  elements: InteractiveElement[];
+  version: number;
+  closed?: boolean;
+  ex?: string;
+}
+
+export interface SatisfactionRatingElem {
+  sessionID: string;
+  rating: number;
+  comment?: string;
+}
+
+export interface MsgStruct {
+  clientMsgID?: string;
+  serverMsgID?: string;
+  createTime: number;
+  sendTime: number;
+  sessionType: number;
+  sendID?: string;
+  recvID?: string;
+  msgFrom: number;
+  contentType: number;
+  senderPlatformID: number;
+  senderNickname?: string;
+  senderFaceUrl?: string;
+  groupID?: string;
+  content?: string;
+  seq: number;
+  isRead: boolean;
+  status: number;
+  offlinePush?: OfflinePushInfo;
+  attachedInfo?: string;
+  ex?: string;
+  localEx?: string;
+  textElem?: TextElem;
+  cardElem?: CardElem;
+  pictureElem?: PictureElem;
+  soundElem?: SoundElem;
+  videoElem?: VideoElem;
+  fileElem?: FileElem;
+  mergeElem?: MergeElem;
+  atTextElem?: AtTextElem;
+  faceElem?: FaceElem;
+  locationElem?: LocationElem;
+  customElem?: CustomElem;
+  quoteElem?: QuoteElem;
+  notificationElem?: NotificationElem;
+  advancedTextElem?: AdvancedTextElem;
+  typingElem?: TypingElem;
+  attachedInfoElem?: AttachedInfoElem;
+  markdownTextElem?: MarkdownTextElem;
+  callElem?: CallElem;
+  interactiveCardElem?: InteractiveCardElem;
+  satisfactionRatingElem?: SatisfactionRatingElem;
+}
+
+export interface SoundBaseInfo {
+  uuid?: string;
+  soundPath?: string;
+  sourceUrl?: string;
+  dataSize: number;
+  duration: number;
+  soundType?: string;
+  md5?: string;
+}
+
+export interface VideoBaseInfo {
+  videoPath?: string;
+  videoUUID?: string;
+  videoUrl?: string;
+  videoType?: string;
+  videoSize: number;
+  duration: number;
+  snapshotPath?: string;
+  snapshotUUID?: string;
+  snapshotSize: number;
+  snapshotUrl?: string;
+  snapshotWidth: number;
+  snapshotHeight: number;
+  snapshotType?: string;
+  videoMd5?: string;
+  snapshotMd5?: string;
+}
+
+export interface FileBaseInfo {
+  filePath?: string;
+  uuid?: string;
+  sourceUrl?: string;
+  fileName?: string;
+  fileSize: number;
+  fileType?: string;
+  fileMd5?: string;
+}
+
+export interface LocalConversation {
+  conversationID: string;
+  conversationType: number;
+  userID: string;
+  groupID: string;
+  showName: string;
+  faceURL: string;
+  recvMsgOpt: number;
+  unreadCount: number;
+  groupAtType: number;
+  latestMsg: string;
+  latestMsgSendTime: number;
+  draftText: string;
+  draftTextTime: number;
+  isPinned: boolean;
+  isPrivateChat: boolean;
+  burnDuration: number;
+  isNotInGroup: boolean;
+  updateUnreadCountTime: number;
+  attachedInfo: string;
+  ex: string;
+  maxSeq: number;
+  minSeq: number;
+  msgDestructTime: number;
+  isMsgDestruct: boolean;
+}
+
+export interface GetAdvancedHistoryMessageListParams {
+  conversationID: string;
+  startClientMsgID: string;
+  count: number;
+  viewType: number;
+}
+
+export interface GetAdvancedHistoryMessageListCallback {
+  messageList: MsgStruct[];
+  isEnd: boolean;
+  errCode: number;
+  errMsg: string;
+}
+
+export interface Int32Value {
+  value: number;
+}
+
+export interface BoolValue {
+  value: boolean;
+}
+
+export interface StringValue {
+  value: string;
+}
+
+export interface Int64Value {
+  value: number;
+}
+
+export interface ConversationReq {
+  conversationID: string;
+  conversationType: number;
+  userID: string;
+  groupID: string;
+  recvMsgOpt: Int32Value;
+  isPinned: BoolValue;
+  attachedInfo: StringValue;
+  isPrivateChat: BoolValue;
+  ex: StringValue;
+  burnDuration: Int32Value;
+  minSeq: Int64Value;
+  maxSeq: Int64Value;
+  groupAtType: Int32Value;
+  msgDestructTime: Int64Value;
+  isMsgDestruct: BoolValue;
+}
+
+export interface ConversationArgs {
+  conversationID: string;
+  clientMsgIDList: string[];
+}
+
+export interface SearchByConversationResult {
+  conversationID: string;
+  conversationType: number;
+  showName: string;
+  faceURL: string;
+  latestMsgSendTime?: number;
+  messageCount: number;
+  messageList: MsgStruct[];
+}
+
+export interface FindMessageListCallback {
+  totalCount: number;
+  findResultItems: SearchByConversationResult[];
+}
+
+export interface ConversationStorageInfo {
+  conversationID: string;
+  messageCount: number;
+  database: number;
+  media: number;
+  mediaFiles: number;
+}
+
+export interface PurgeStorageOptions {
+  media: boolean;
+  messages: boolean;
+  beforeTime: number;
+}
+
+export interface PurgeStorageResult {
+  removedMediaFiles: number;
+  freedMedia: number;
+  deletedMessages: number;
+}
+
+export interface SearchLocalMessagesParams {
+  conversationID: string;
+  keywordList: string[];
+  keywordListMatchType: number;
+  senderUserIDList: string[];
+  messageTypeList: number[];
+  searchTimePosition: number;
+  searchTimePeriod: number;
+  pageIndex: number;
+  count: number;
+}
+
+export interface SearchLocalMessagesCallback {
+  totalCount: number;
+  searchResultItems: SearchByConversationResult[];
+}
+
+export interface Conversation {
+  ownerUserID: string;
+  conversationID: string;
+  conversationType: number;
+  userID: string;
+  groupID: string;
+  recvMsgOpt: number;
+  unreadCount: number;
+  draftTextTime: number;
+  isPinned: boolean;
+  isPrivateChat: boolean;
+  burnDuration: number;
+  groupAtType: number;
+  isNotInGroup: boolean;
+  updateUnreadCountTime: number;
+  attachedInfo: string;
+  ex: string;
+}
+
+export interface SeqGap {
+  conversationID: string;
+  beginSeq: number;
+  endSeq: number;
+  repairTimes: number;
+}
+
+export interface SeqGapStats {
+  detected: number;
+  repaired: number;
+  pending: SeqGap[];
+}
+
+export interface GroupInfo {
+  groupID: string;
+  groupName: string;
+  notification: string;
+  introduction: string;
+  faceURL: string;
+  ownerUserID: string;
+  createTime: number;
+  memberCount: number;
+  ex: string;
+  status: number;
+  creatorUserID: string;
+  groupType: number;
+  needVerification: number;
+  lookMemberInfo: number;
+  applyMemberFriend: number;
+  notificationUpdateTime: number;
+  notificationUserID: string;
+}
+
+export interface CreateGroupReq {
+  memberUserIDs: string[];
+  groupInfo: GroupInfo;
+  adminUserIDs: string[];
+  ownerUserID: string;
+  sendMessage: boolean;
+}
+
+export interface LocalGroup {
+  groupID: string;
+  groupName: string;
+  notification: string;
+  introduction: string;
+  faceURL: string;
+  createTime: number;
+  status: number;
+  creatorUserID: string;
+  groupType: number;
+  ownerUserID: string;
+  memberCount: number;
+  ex: string;
+  attachedInfo: string;
+  needVerification: number;
+  lookMemberInfo: number;
+  applyMemberFriend: number;
+  notificationUpdateTime: number;
+  notificationUserID: string;
+}
+
+export interface SetGroupMemberInfo {
+  groupID: string;
+  userID: string;
+  nickname: StringValue;
+  faceURL: StringValue;
+  roleLevel: Int32Value;
+  ex: StringValue;
+}
+
+export interface SearchGroupsParam {
+  keywordList: string[];
+  isSearchGroupID: boolean;
+  isSearchGroupName: boolean;
+}
+
+export interface SetGroupInfoExReq {
+  groupID: string;
+  groupName: StringValue;
+  notification: StringValue;
+  introduction: StringValue;
+  faceURL: StringValue;
+  ex: StringValue;
+  needVerification: Int32Value;
+  lookMemberInfo: Int32Value;
+  applyMemberFriend: Int32Value;
+}
+
+export interface LocalGroupMember {
+  groupID: string;
+  userID: string;
+  nickname: string;
+  faceURL: string;
+  roleLevel: number;
+  joinTime: number;
+  joinSource: number;
+  inviterUserID: string;
+  muteEndTime: number;
+  operatorUserID: string;
+  ex: string;
+  attachedInfo: string;
+}
+
+export interface GetGroupApplicationListAsRecipientReq {
+  groupIDs: string[];
+  handleResults: number[];
+  offset: number;
+  count: number;
+}
+
+export interface LocalGroupRequest {
+  groupID: string;
+  groupName: string;
+  notification: string;
+  introduction: string;
+  groupFaceURL: string;
+  createTime: number;
+  status: number;
+  creatorUserID: string;
+  groupType: number;
+  ownerUserID: string;
+  memberCount: number;
+  userID: string;
+  nickname: string;
+  userFaceURL: string;
+  handleResult: number;
+  reqMsg: string;
+  handledMsg: string;
+  reqTime: number;
+  handleUserID: string;
+  handledTime: number;
+  ex: string;
+  attachedInfo: string;
+  joinSource: number;
+  inviterUserID: string;
+}
+
+export interface GetGroupApplicationListAsApplicantReq {
+  groupIDs: string[];
+  handleResults: number[];
+  offset: number;
+  count: number;
+}
+
+export interface SearchGroupMembersParam {
+  groupID: string;
+  keywordList: string[];
+  isSearchUserID: boolean;
+  isSearchMemberNickname: boolean;
+  offset: number;
+  count: number;
+  pageNumber: number;
+}
+
+export interface GetGroupApplicationUnhandledCountReq {
+  time: number;
+}
+
+export interface LocalUser {
+  userID: string;
+  nickname: string;
+  faceURL: string;
+  createTime: number;
+  ex: string;
+  attachedInfo: string;
+  globalRecvMsgOpt: number;
+}
+
+export interface UserInfoWithEx {
+  userID: string;
+  nickname: StringValue;
+  faceURL: StringValue;
+  ex: StringValue;
+  globalRecvMsgOpt: Int32Value;
+}
+
+export interface PublicUser {
+  userID: string;
+  nickname: string;
+  faceURL: string;
+  ex: string;
+  createTime: number;
+}
+
+export interface OnlineStatus {
+  userID: string;
+  status: number;
+  platformIDs: number[];
+}
+
+export interface UserLastSeen {
+  userID: string;
+  status: number;
+  about: number;
+}
+
+export interface LocalPrivacySettings {
+  userID: string;
+  addFriendPermission: number;
+  profileVisibility: number;
+  readReceiptDisabled: boolean;
+  lastSeenVisibility: number;
+  updateTime: number;
+}
+
+export interface Field {
+  key: string;
+  type: string;
+}
+
+export interface LocalFriend {
+  ownerUserID: string;
+  userID: string;
+  remark: string;
+  createTime: number;
+  addSource: number;
+  operatorUserID: string;
+  nickname: string;
+  faceURL: string;
+  ex: string;
+  attachedInfo: string;
+  isPinned: boolean;
+}
+
+export interface SearchFriendsParam {
+  keywordList: string[];
+  isSearchUserID: boolean;
+  isSearchNickname: boolean;
+  isSearchRemark: boolean;
+}
+
+export interface SearchFriendItem extends LocalFriend {
+  relationship: number;
+}
+
+export interface UserIDResult {
+  userID: string;
+  result: number;
+}
+
+export interface ApplyToAddFriendReq {
+  fromUserID: string;
+  toUserID: string;
+  reqMsg: string;
+  ex: string;
+}
+
+export interface UpdateFriendsReq {
+  ownerUserID: string;
+  friendUserIDs: string[];
+  isPinned: BoolValue;
+  remark: StringValue;
+  ex: StringValue;
+}
+
+export interface GetFriendApplicationListAsRecipientReq {
+  handleResults: number[];
+  offset: number;
+  count: number;
+}
+
+export interface LocalFriendRequest {
+  fromUserID: string;
+  fromNickname: string;
+  fromFaceURL: string;
+  toUserID: string;
+  toNickname: string;
+  toFaceURL: string;
+  handleResult: number;
+  reqMsg: string;
+  createTime: number;
+  handlerUserID: string;
+  handleMsg: string;
+  handleTime: number;
+  ex: string;
+  attachedInfo: string;
+}
+
+export interface GetFriendApplicationListAsApplicantReq {
+  offset: number;
+  count: number;
+}
+
+export interface ProcessFriendApplicationParams {
+  toUserID: string;
+  handleMsg: string;
+}
+
+export interface LocalBlack {
+  ownerUserID: string;
+  userID: string;
+  nickname: string;
+  faceURL: string;
+  createTime: number;
+  addSource: number;
+  operatorUserID: string;
+  ex: string;
+  attachedInfo: string;
+}
+
+export interface GetSelfUnhandledApplyCountReq {
+  time: number;
+}
+
+export interface SignalingInvitation {
+  roomID: string;
+  inviterUserID: string;
+  inviteeUserIDList: string[];
+  groupID?: string;
+  sessionType: number;
+  mediaType: string;
+  timeout: number;
+  initiateTime: number;
+  platformID: number;
+  customData?: string;
+}
+
+export interface SignalingParticipant {
+  userID: string;
+  joinTime: number;
+}
+
+export interface SignalingRoom {
+  invitation: SignalingInvitation;
+  userID?: string;
+  participants: SignalingParticipant[];
+}
+
+export interface CallRecordFilter {
+  userID: string;
+  groupID: string;
+  mediaType: string;
+  states: string[];
+  missed: boolean;
+}
+
+export interface LocalCallRecord {
+  roomID: string;
+  inviterUserID: string;
+  inviteeUserIDs: string[];
+  peerUserID: string;
+  groupID: string;
+  sessionType: number;
+  mediaType: string;
+  state: string;
+  missed: boolean;
+  duration: number;
+  initiateTime: number;
+  endTime: number;
+}
+
+export interface GetCallRecordsCallback {
+  callRecords: LocalCallRecord[];
+  nextCursor: string;
+  hasMore: boolean;
+}
+
+export interface InputStatesChangedData {
+  conversationID: string;
+  userID: string;
+  platformIDs: number[];
+}
+
+export interface MessageReceipt {
+  groupID: string;
+  userID: string;
+  msgIDList: string[];
+  readTime: number;
+  msgFrom: number;
+  contentType: number;
+  sessionType: number;
+}
+
+export interface MessageRevoked {
+  revokerID: string;
+  revokerRole: number;
+  clientMsgID: string;
+  revokerNickname: string;
+  revokeTime: number;
+  sourceMessageSendTime: number;
+  sourceMessageSendID: string;
+  sourceMessageSenderNickname: string;
+  sessionType: number;
+  seq: number;
+  ex: string;
+  isAdminRevoke: boolean;
+}
+
+export interface QRLoginState {
+  qrID: string;
+  state: number;
+  userID?: string;
+  token?: string;
+}
+
+export interface ConnState {
+  state: string;
+  nextAttemptTime?: number;
+  rtt?: number;
+  errCode?: number;
+  errMsg?: string;
+}
+
+export interface SyncProgress {
+  phase: string;
+  done: number;
+  total: number;
+  phasePercent: number;
+  percent: number;
+}
+
+export interface SyncConflict {
+  kind: string;
+  conversationID: string;
+  clientMsgID: string;
+  local: string;
+  server: string;
+  resolution: string;
+}
@@ -0,0 +1,733 @@
+// Code generated by tools/wasmgen. DO NOT EDIT.
+
+function decode(data) {
+  if (typeof data !== 'string') return data;
+  try {
+    return JSON.parse(data);
+  } catch {
+    return data;
+  }
+}
+
+function encode(value) {
+  return typeof value === 'string' ? value : JSON.stringify(value);
+}
+
+export async function enableMultiTab(channelName) {
+  return await globalThis.enableMultiTab(channelName);
+}
+
+export async function isLeaderTab() {
+  return await globalThis.isLeaderTab();
+}
+
+export async function initSDK(operationID, config) {
+  return globalThis.initSDK(operationID, config)[0];
+}
+
+export async function login(operationID, userID, token) {
+  return decode(await globalThis.login(operationID, userID, token));
+}
+
+export async function guestLogin(operationID) {
+  return decode(await globalThis.guestLogin(operationID));
+}
+
+export async function logout(operationID) {
+  return decode(await globalThis.logout(operationID));
+}
+
+export async function getLoginStatus(operationID) {
+  return (await globalThis.getLoginStatus(operationID))[0];
+}
+
+export async function getTrafficStats(operationID) {
+  return (await globalThis.getTrafficStats(operationID))[0];
+}
+
+export async function getDBQueryStats(operationID) {
+  return (await globalThis.getDBQueryStats(operationID))[0];
+}
+
+export async function getServerTime(operationID) {
+  return (await globalThis.getServerTime(operationID))[0];
+}
+
+export async function getNetworkQuality(operationID) {
+  return decode(await globalThis.getNetworkQuality(operationID));
+}
+
+export async function forceReconnect(operationID) {
+  return decode(await globalThis.forceReconnect(operationID));
+}
+
+export async function setBandwidthLimit(operationID, bandwidthLimit) {
+  return decode(await globalThis.setBandwidthLimit(operationID, encode(bandwidthLimit)));
+}
+
+export async function getBandwidthLimit(operationID) {
+  return decode(await globalThis.getBandwidthLimit(operationID));
+}
+
+export async function setNetworkClass(operationID, class_) {
+  return decode(await globalThis.setNetworkClass(operationID, class_));
+}
+
+export async function trimMemory(operationID, level) {
+  return decode(await globalThis.trimMemory(operationID, level));
+}
+
+export async function setLocalizedTemplates(operationID, locale, templates) {
+  return decode(await globalThis.setLocalizedTemplates(operationID, locale, encode(templates)));
+}
+
+export async function getLocalizedText(operationID, key, args) {
+  return (await globalThis.getLocalizedText(operationID, key, args))[0];
+}
+
+export async function allowMeteredTransfer(operationID, kind, allow) {
+  return decode(await globalThis.allowMeteredTransfer(operationID, kind, allow));
+}
+
+export async function setAppBackgroundStatus(operationID, isBackground) {
+  return decode(await globalThis.setAppBackgroundStatus(operationID, isBackground));
+}
+
+export async function enterBackground(operationID) {
+  return decode(await globalThis.enterBackground(operationID));
+}
+
+export async function enterForeground(operationID) {
+  return decode(await globalThis.enterForeground(operationID));
+}
+
+export async function switchAccount(operationID, userID, token) {
+  return decode(await globalThis.switchAccount(operationID, userID, token));
+}
+
+export async function getStorageUsage(operationID) {
+  return decode(await globalThis.getStorageUsage(operationID));
+}
+
+export async function forceResyncAll(operationID, scopes) {
+  return decode(await globalThis.forceResyncAll(operationID, encode(scopes)));
+}
+
+export async function networkStatusChanged(operationID) {
+  return decode(await globalThis.networkStatusChanged(operationID));
+}
+
+export async function refreshToken(operationID, token) {
+  return decode(await globalThis.refreshToken(operationID, token));
+}
+
+export async function createLoginQRCode(operationID) {
+  return decode(await globalThis.createLoginQRCode(operationID));
+}
+
+export async function cancelLoginQRCode(operationID) {
+  return decode(await globalThis.cancelLoginQRCode(operationID));
+}
+
+export async function scanLoginQRCode(operationID, payload) {
+  return decode(await globalThis.scanLoginQRCode(operationID, payload));
+}
+
+export async function approveLoginQRCode(operationID, payload) {
+  return decode(await globalThis.approveLoginQRCode(operationID, payload));
+}
+
+export async function rejectLoginQRCode(operationID, payload) {
+  return decode(await globalThis.rejectLoginQRCode(operationID, payload));
+}
+
+export async function createTextMessage(operationID, text) {
+  return decode((await globalThis.createTextMessage(operationID, text))[0]);
+}
+
+export async function createImageMessage(operationID, imagePath) {
+  return decode((await globalThis.createImageMessage(operationID, imagePath))[0]);
+}
+
+export async function createImageMessageByURL(operationID, sourcePath, sourcePicture, bigPicture, snapshotPicture) {
+  return decode((await globalThis.createImageMessageByURL(operationID, sourcePath, encode(sourcePicture), encode(bigPicture), encode(snapshotPicture)))[0]);
+}
+
+export async function createSoundMessageByURL(operationID, soundBaseInfo) {
+  return decode((await globalThis.createSoundMessageByURL(operationID, encode(soundBaseInfo)))[0]);
+}
+
+export async function createVideoMessageByURL(operationID, videoBaseInfo) {
+  return decode((await globalThis.createVideoMessageByURL(operationID, encode(videoBaseInfo)))[0]);
+}
+
+export async function createFileMessageByURL(operationID, fileBaseInfo) {
+  return decode((await globalThis.createFileMessageByURL(operationID, encode(fileBaseInfo)))[0]);
+}
+
+export async function createCustomMessage(operationID, data, extension, description) {
+  return decode((await globalThis.createCustomMessage(operationID, data, extension, description))[0]);
+}
+
+export async function createQuoteMessage(operationID, text, message) {
+  return decode((await globalThis.createQuoteMessage(operationID, text, encode(message)))[0]);
+}
+
+export async function createAdvancedQuoteMessage(operationID, text, message, messageEntityList) {
+  return decode((await globalThis.createAdvancedQuoteMessage(operationID, text, encode(message), encode(messageEntityList)))[0]);
+}
+
+export async function createAdvancedTextMessage(operationID, text, messageEntityList) {
+  return decode((await globalThis.createAdvancedTextMessage(operationID, text, encode(messageEntityList)))[0]);
+}
+
+export async function createCardMessage(operationID, cardInfo) {
+  return decode((await globalThis.createCardMessage(operationID, encode(cardInfo)))[0]);
+}
+
+export async function createTextAtMessage(operationID, text, atUserList, atUsersInfo, message) {
+  return decode((await globalThis.createTextAtMessage(operationID, text, encode(atUserList), encode(atUsersInfo), encode(message)))[0]);
+}
+
+export async function createVideoMessage(operationID, videoPath, videoType, duration, snapshotPath) {
+  return decode((await globalThis.createVideoMessage(operationID, videoPath, videoType, duration, snapshotPath))[0]);
+}
+
+export async function createFileMessage(operationID, filePath, fileName) {
+  return decode((await globalThis.createFileMessage(operationID, filePath, fileName))[0]);
+}
+
+export async function createMergerMessage(operationID, messageList, title, summaryList) {
+  return decode((await globalThis.createMergerMessage(operationID, encode(messageList), title, encode(summaryList)))[0]);
+}
+
+export async function createFaceMessage(operationID, index, data) {
+  return decode((await globalThis.createFaceMessage(operationID, index, data))[0]);
+}
+
+export async function createForwardMessage(operationID, m) {
+  return decode((await globalThis.createForwardMessage(operationID, encode(m)))[0]);
+}
+
+export async function createLocationMessage(operationID, description, longitude, latitude) {
+  return decode((await globalThis.createLocationMessage(operationID, description, longitude, latitude))[0]);
+}
+
+export async function createVideoMessageFromFullPath(operationID, videoFullPath, videoType, duration, snapshotFullPath) {
+  return decode((await globalThis.createVideoMessageFromFullPath(operationID, videoFullPath, videoType, duration, snapshotFullPath))[0]);
+}
+
+export async function createImageMessageFromFullPath(operationID, imageFullPath) {
+  return decode((await globalThis.createImageMessageFromFullPath(operationID, imageFullPath))[0]);
+}
+
+export async function createSoundMessageFromFullPath(operationID, soundPath, duration) {
+  return decode((await globalThis.createSoundMessageFromFullPath(operationID, soundPath, duration))[0]);
+}
+
+export async function createFileMessageFromFullPath(operationID, fileFullPath, fileName) {
+  return decode((await globalThis.createFileMessageFromFullPath(operationID, fileFullPath, fileName))[0]);
+}
+
+export async function createSoundMessage(operationID, soundPath, duration) {
+  return decode((await globalThis.createSoundMessage(operationID, soundPath, duration))[0]);
+}
+
+export async function getAtAllTag(operationID) {
+  return decode((await globalThis.getAtAllTag(operationID))[0]);
+}
+
+export async function markConversationMessageAsRead(operationID, conversationID) {
+  return decode(await globalThis.markConversationMessageAsRead(operationID, conversationID));
+}
+
+export async function markAllConversationMessageAsRead(operationID) {
+  return decode(await globalThis.markAllConversationMessageAsRead(operationID));
+}
+
+export async function markMessagesAsReadByMsgID(operationID, conversationID, clientMsgIDs) {
+  return decode(await globalThis.markMessagesAsReadByMsgID(operationID, conversationID, encode(clientMsgIDs)));
+}
+
+export async function sendMessage(operationID, message, recvID, groupID, offlinePushInfo, isOnlineOnly) {
+  return decode(await globalThis.sendMessage(operationID, encode(message), recvID, groupID, encode(offlinePushInfo), isOnlineOnly));
+}
+
+export async function sendMessageNotOss(operationID, message, recvID, groupID, offlinePushInfo, isOnlineOnly) {
+  return decode(await globalThis.sendMessageNotOss(operationID, encode(message), recvID, groupID, encode(offlinePushInfo), isOnlineOnly));
+}
+
+export async function getAllConversationList(operationID) {
+  return decode(await globalThis.getAllConversationList(operationID));
+}
+
+export async function getConversationListSplit(operationID, offset, count) {
+  return decode(await globalThis.getConversationListSplit(operationID, offset, count));
+}
+
+export async function getOneConversation(operationID, sessionType, sourceID) {
+  return decode(await globalThis.getOneConversation(operationID, sessionType, sourceID));
+}
+
+export async function deleteConversationAndDeleteAllMsg(operationID, conversationID) {
+  return decode(await globalThis.deleteConversationAndDeleteAllMsg(operationID, conversationID));
+}
+
+export async function getAdvancedHistoryMessageList(operationID, getMessageOptions) {
+  return decode(await globalThis.getAdvancedHistoryMessageList(operationID, encode(getMessageOptions)));
+}
+
+export async function getAdvancedHistoryMessageListReverse(operationID, getMessageOptions) {
+  return decode(await globalThis.getAdvancedHistoryMessageListReverse(operationID, encode(getMessageOptions)));
+}
+
+export async function getMultipleConversation(operationID, conversationIDList) {
+  return decode(await globalThis.getMultipleConversation(operationID, encode(conversationIDList)));
+}
+
+export async function hideConversation(operationID, conversationID) {
+  return decode(await globalThis.hideConversation(operationID, conversationID));
+}
+
+export async function setConversationDraft(operationID, conversationID, draftText) {
+  return decode(await globalThis.setConversationDraft(operationID, conversationID, draftText));
+}
+
+export async function setConversation(operationID, conversationID, req) {
+  return decode(await globalThis.setConversation(operationID, conversationID, encode(req)));
+}
+
+export async function getTotalUnreadMsgCount(operationID) {
+  return decode(await globalThis.getTotalUnreadMsgCount(operationID));
+}
+
+export async function findMessageList(operationID, findMessageOptions) {
+  return decode(await globalThis.findMessageList(operationID, encode(findMessageOptions)));
+}
+
+export async function revokeMessage(operationID, conversationID, clientMsgID) {
+  return decode(await globalThis.revokeMessage(operationID, conversationID, clientMsgID));
+}
+
+export async function submitInteractiveAction(operationID, conversationID, clientMsgID, actionID, payload) {
+  return decode(await globalThis.submitInteractiveAction(operationID, conversationID, clientMsgID, actionID, payload));
+}
+
+export async function typingStatusUpdate(operationID, recvID, msgTip) {
+  return decode(await globalThis.typingStatusUpdate(operationID, recvID, msgTip));
+}
+
+export async function deleteMessageFromLocalStorage(operationID, conversationID, clientMsgID) {
+  return decode(await globalThis.deleteMessageFromLocalStorage(operationID, conversationID, clientMsgID));
+}
+
+export async function deleteMessage(operationID, conversationID, clientMsgID) {
+  return decode(await globalThis.deleteMessage(operationID, conversationID, clientMsgID));
+}
+
+export async function hideAllConversations(operationID) {
+  return decode(await globalThis.hideAllConversations(operationID));
+}
+
+export async function deleteAllMsgFromLocalAndSvr(operationID) {
+  return decode(await globalThis.deleteAllMsgFromLocalAndSvr(operationID));
+}
+
+export async function deleteAllMsgFromLocal(operationID) {
+  return decode(await globalThis.deleteAllMsgFromLocal(operationID));
+}
+
+export async function clearConversationAndDeleteAllMsg(operationID, conversationID) {
+  return decode(await globalThis.clearConversationAndDeleteAllMsg(operationID, conversationID));
+}
+
+export async function getConversationStorageInfo(operationID, conversationID) {
+  return decode(await globalThis.getConversationStorageInfo(operationID, conversationID));
+}
+
+export async function purgeConversationStorage(operationID, conversationID, options) {
+  return decode(await globalThis.purgeConversationStorage(operationID, conversationID, encode(options)));
+}
+
+export async function archiveMessages(operationID, beforeTime) {
+  return decode(await globalThis.archiveMessages(operationID, beforeTime));
+}
+
+export async function insertSingleMessageToLocalStorage(operationID, message, recvID, sendID) {
+  return decode(await globalThis.insertSingleMessageToLocalStorage(operationID, encode(message), recvID, sendID));
+}
+
+export async function insertGroupMessageToLocalStorage(operationID, message, groupID, sendID) {
+  return decode(await globalThis.insertGroupMessageToLocalStorage(operationID, encode(message), groupID, sendID));
+}
+
+export async function searchLocalMessages(operationID, searchParam) {
+  return decode(await globalThis.searchLocalMessages(operationID, encode(searchParam)));
+}
+
+export async function setMessageLocalEx(operationID, conversationID, clientMsgID, localEx) {
+  return decode(await globalThis.setMessageLocalEx(operationID, conversationID, clientMsgID, localEx));
+}
+
+export async function searchConversation(operationID, searchParam) {
+  return decode(await globalThis.searchConversation(operationID, searchParam));
+}
+
+export async function changeInputStates(operationID, conversationID, focus) {
+  return decode(await globalThis.changeInputStates(operationID, conversationID, focus));
+}
+
+export async function getInputStates(operationID, conversationID, userID) {
+  return decode(await globalThis.getInputStates(operationID, conversationID, userID));
+}
+
+export async function getSeqGapStats(operationID) {
+  return decode(await globalThis.getSeqGapStats(operationID));
+}
+
+export async function createGroup(operationID, groupReqInfo) {
+  return decode(await globalThis.createGroup(operationID, encode(groupReqInfo)));
+}
+
+export async function getSpecifiedGroupsInfo(operationID, groupIDList) {
+  return decode(await globalThis.getSpecifiedGroupsInfo(operationID, encode(groupIDList)));
+}
+
+export async function joinGroup(operationID, groupID, reqMsg, joinSource, ex) {
+  return decode(await globalThis.joinGroup(operationID, groupID, reqMsg, joinSource, ex));
+}
+
+export async function quitGroup(operationID, groupID) {
+  return decode(await globalThis.quitGroup(operationID, groupID));
+}
+
+export async function dismissGroup(operationID, groupID) {
+  return decode(await globalThis.dismissGroup(operationID, groupID));
+}
+
+export async function changeGroupMute(operationID, groupID, isMute) {
+  return decode(await globalThis.changeGroupMute(operationID, groupID, isMute));
+}
+
+export async function changeGroupMemberMute(operationID, groupID, userID, mutedSeconds) {
+  return decode(await globalThis.changeGroupMemberMute(operationID, groupID, userID, mutedSeconds));
+}
+
+export async function setGroupMemberInfo(operationID, groupMemberInfo) {
+  return decode(await globalThis.setGroupMemberInfo(operationID, encode(groupMemberInfo)));
+}
+
+export async function getJoinedGroupList(operationID) {
+  return decode(await globalThis.getJoinedGroupList(operationID));
+}
+
+export async function getJoinedGroupListPage(operationID, offset, count) {
+  return decode(await globalThis.getJoinedGroupListPage(operationID, offset, count));
+}
+
+export async function searchGroups(operationID, searchParam) {
+  return decode(await globalThis.searchGroups(operationID, encode(searchParam)));
+}
+
+export async function setGroupInfo(operationID, groupInfo) {
+  return decode(await globalThis.setGroupInfo(operationID, encode(groupInfo)));
+}
+
+export async function getGroupMemberList(operationID, groupID, filter, offset, count) {
+  return decode(await globalThis.getGroupMemberList(operationID, groupID, filter, offset, count));
+}
+
+export async function getGroupMemberOwnerAndAdmin(operationID, groupID) {
+  return decode(await globalThis.getGroupMemberOwnerAndAdmin(operationID, groupID));
+}
+
+export async function getGroupMemberListByJoinTimeFilter(operationID, groupID, offset, count, joinTimeBegin, joinTimeEnd, filterUserIDList) {
+  return decode(await globalThis.getGroupMemberListByJoinTimeFilter(operationID, groupID, offset, count, joinTimeBegin, joinTimeEnd, encode(filterUserIDList)));
+}
+
+export async function getSpecifiedGroupMembersInfo(operationID, groupID, userIDList) {
+  return decode(await globalThis.getSpecifiedGroupMembersInfo(operationID, groupID, encode(userIDList)));
+}
+
+export async function kickGroupMember(operationID, groupID, reason, userIDList) {
+  return decode(await globalThis.kickGroupMember(operationID, groupID, reason, encode(userIDList)));
+}
+
+export async function transferGroupOwner(operationID, groupID, newOwnerUserID) {
+  return decode(await globalThis.transferGroupOwner(operationID, groupID, newOwnerUserID));
+}
+
+export async function inviteUserToGroup(operationID, groupID, reason, userIDList) {
+  return decode(await globalThis.inviteUserToGroup(operationID, groupID, reason, encode(userIDList)));
+}
+
+export async function getGroupApplicationListAsRecipient(operationID, req) {
+  return decode(await globalThis.getGroupApplicationListAsRecipient(operationID, encode(req)));
+}
+
+export async function getGroupApplicationListAsApplicant(operationID, req) {
+  return decode(await globalThis.getGroupApplicationListAsApplicant(operationID, encode(req)));
+}
+
+export async function acceptGroupApplication(operationID, groupID, fromUserID, handleMsg) {
+  return decode(await globalThis.acceptGroupApplication(operationID, groupID, fromUserID, handleMsg));
+}
+
+export async function refuseGroupApplication(operationID, groupID, fromUserID, handleMsg) {
+  return decode(await globalThis.refuseGroupApplication(operationID, groupID, fromUserID, handleMsg));
+}
+
+export async function checkLocalGroupFullSync(operationID) {
+  return decode(await globalThis.checkLocalGroupFullSync(operationID));
+}
+
+export async function checkGroupMemberFullSync(operationID, groupID) {
+  return decode(await globalThis.checkGroupMemberFullSync(operationID, groupID));
+}
+
+export async function searchGroupMembers(operationID, searchParam) {
+  return decode(await globalThis.searchGroupMembers(operationID, encode(searchParam)));
+}
+
+export async function isJoinGroup(operationID, groupID) {
+  return decode(await globalThis.isJoinGroup(operationID, groupID));
+}
+
+export async function getUsersInGroup(operationID, groupID, userIDList) {
+  return decode(await globalThis.getUsersInGroup(operationID, groupID, encode(userIDList)));
+}
+
+export async function getGroupApplicationUnhandledCount(operationID, req) {
+  return decode(await globalThis.getGroupApplicationUnhandledCount(operationID, encode(req)));
+}
+
+export async function getSelfUserInfo(operationID) {
+  return decode(await globalThis.getSelfUserInfo(operationID));
+}
+
+export async function setSelfInfo(operationID, userInfo) {
+  return decode(await globalThis.setSelfInfo(operationID, encode(userInfo)));
+}
+
+export async function getUsersInfo(operationID, userIDs) {
+  return decode(await globalThis.getUsersInfo(operationID, encode(userIDs)));
+}
+
+export async function subscribeUsersStatus(operationID, userIDs) {
+  return decode(await globalThis.subscribeUsersStatus(operationID, encode(userIDs)));
+}
+
+export async function unsubscribeUsersStatus(operationID, userIDs) {
+  return decode(await globalThis.unsubscribeUsersStatus(operationID, encode(userIDs)));
+}
+
+export async function getSubscribeUsersStatus(operationID) {
+  return decode(await globalThis.getSubscribeUsersStatus(operationID));
+}
+
+export async function getUserStatus(operationID, userIDs) {
+  return decode(await globalThis.getUserStatus(operationID, encode(userIDs)));
+}
+
+export async function getUserLastSeen(operationID, userIDs) {
+  return decode(await globalThis.getUserLastSeen(operationID, encode(userIDs)));
+}
+
+export async function getPrivacySettings(operationID) {
+  return decode(await globalThis.getPrivacySettings(operationID));
+}
+
+export async function setPrivacySettings(operationID, settings) {
+  return decode(await globalThis.setPrivacySettings(operationID, encode(settings)));
+}
+
+export async function registerUserExProfileSchema(operationID, fields) {
+  return decode(await globalThis.registerUserExProfileSchema(operationID, encode(fields)));
+}
+
+export async function getUsersExProfile(operationID, userIDs) {
+  return decode(await globalThis.getUsersExProfile(operationID, encode(userIDs)));
+}
+
+export async function updateSelfExProfile(operationID, fields) {
+  return decode(await globalThis.updateSelfExProfile(operationID, encode(fields)));
+}
+
+export async function getSpecifiedFriendsInfo(operationID, userIDList, filterBlack) {
+  return decode(await globalThis.getSpecifiedFriendsInfo(operationID, encode(userIDList), filterBlack));
+}
+
+export async function getFriendList(operationID, filterBlack) {
+  return decode(await globalThis.getFriendList(operationID, filterBlack));
+}
+
+export async function getFriendListPage(operationID, offset, count, filterBlack) {
+  return decode(await globalThis.getFriendListPage(operationID, offset, count, filterBlack));
+}
+
+export async function searchFriends(operationID, searchParam) {
+  return decode(await globalThis.searchFriends(operationID, encode(searchParam)));
+}
+
+export async function checkFriend(operationID, userIDList) {
+  return decode(await globalThis.checkFriend(operationID, encode(userIDList)));
+}
+
+export async function addFriend(operationID, userIDReqMsg) {
+  return decode(await globalThis.addFriend(operationID, encode(userIDReqMsg)));
+}
+
+export async function updateFriends(operationID, req) {
+  return decode(await globalThis.updateFriends(operationID, encode(req)));
+}
+
+export async function deleteFriend(operationID, friendUserID) {
+  return decode(await globalThis.deleteFriend(operationID, friendUserID));
+}
+
+export async function getFriendApplicationListAsRecipient(operationID, req) {
+  return decode(await globalThis.getFriendApplicationListAsRecipient(operationID, encode(req)));
+}
+
+export async function getFriendApplicationListAsApplicant(operationID, req) {
+  return decode(await globalThis.getFriendApplicationListAsApplicant(operationID, encode(req)));
+}
+
+export async function acceptFriendApplication(operationID, userIDHandleMsg) {
+  return decode(await globalThis.acceptFriendApplication(operationID, encode(userIDHandleMsg)));
+}
+
+export async function refuseFriendApplication(operationID, userIDHandleMsg) {
+  return decode(await globalThis.refuseFriendApplication(operationID, encode(userIDHandleMsg)));
+}
+
+export async function getBlackList(operationID) {
+  return decode(await globalThis.getBlackList(operationID));
+}
+
+export async function removeBlack(operationID, removeUserID) {
+  return decode(await globalThis.removeBlack(operationID, removeUserID));
+}
+
+export async function addBlack(operationID, blackUserID, ex) {
+  return decode(await globalThis.addBlack(operationID, blackUserID, ex));
+}
+
+export async function getFriendApplicationUnhandledCount(operationID, req) {
+  return decode(await globalThis.getFriendApplicationUnhandledCount(operationID, encode(req)));
+}
+
+export async function updateFcmToken(operationID, fcmToken, expireTime) {
+  return decode(await globalThis.updateFcmToken(operationID, fcmToken, expireTime));
+}
+
+export async function setOfflinePushToken(operationID, platform, token, provider) {
+  return decode(await globalThis.setOfflinePushToken(operationID, platform, token, provider));
+}
+
+export async function uploadFile(...args) {
+  return decode(await globalThis.uploadFile(...args));
+}
+
+export async function signalingInvite(operationID, invitation) {
+  return decode(await globalThis.signalingInvite(operationID, encode(invitation)));
+}
+
+export async function signalingInviteInGroup(operationID, invitation) {
+  return decode(await globalThis.signalingInviteInGroup(operationID, encode(invitation)));
+}
+
+export async function signalingJoin(operationID, roomID, customData) {
+  return decode(await globalThis.signalingJoin(operationID, roomID, customData));
+}
+
+export async function signalingGetRoomByGroupID(operationID, groupID) {
+  return decode(await globalThis.signalingGetRoomByGroupID(operationID, groupID));
+}
+
+export async function signalingAccept(operationID, roomID, customData) {
+  return decode(await globalThis.signalingAccept(operationID, roomID, customData));
+}
+
+export async function signalingReject(operationID, roomID, customData) {
+  return decode(await globalThis.signalingReject(operationID, roomID, customData));
+}
+
+export async function signalingCancel(operationID, roomID, customData) {
+  return decode(await globalThis.signalingCancel(operationID, roomID, customData));
+}
+
+export async function signalingHungUp(operationID, roomID, customData) {
+  return decode(await globalThis.signalingHungUp(operationID, roomID, customData));
+}
+
+export async function sendSignalingData(operationID, toUserID, payload) {
+  return decode(await globalThis.sendSignalingData(operationID, toUserID, payload));
+}
+
+export async function getCallRecords(operationID, filter, cursor, count) {
+  return decode(await globalThis.getCallRecords(operationID, encode(filter), cursor, count));
+}
+
+const jsonEvents = new Set([
+  "OnNewConversation",
+  "OnConversationChanged",
+  "OnConversationUserInputStatusChanged",
+  "OnRecvNewMessage",
+  "OnRecvC2CReadReceipt",
+  "OnRecvGroupReadReceipt",
+  "OnNewRecvMessageRevoked",
+  "OnRecvMessageModified",
+  "OnRecvMessageExtensionsChanged",
+  "OnRecvMessageExtensionsDeleted",
+  "OnRecvMessageExtensionsAdded",
+  "OnRecvOfflineNewMessage",
+  "OnMsgDeleted",
+  "OnRecvOnlineOnlyMessage",
+  "OnMsgEdited",
+  "OnProgress",
+  "OnUploadSpeed",
+  "Open",
+  "PartSize",
+  "HashPartProgress",
+  "HashPartComplete",
+  "UploadID",
+  "UploadPartComplete",
+  "UploadComplete",
+  "Complete",
+  "OnFriendApplicationAdded",
+  "OnFriendApplicationDeleted",
+  "OnFriendApplicationAccepted",
+  "OnFriendApplicationRejected",
+  "OnFriendAdded",
+  "OnFriendDeleted",
+  "OnFriendInfoChanged",
+  "OnBlackAdded",
+  "OnBlackDeleted",
+  "OnJoinedGroupAdded",
+  "OnJoinedGroupDeleted",
+  "OnGroupMemberAdded",
+  "OnGroupMemberDeleted",
+  "OnGroupApplicationAdded",
+  "OnGroupApplicationDeleted",
+  "OnGroupInfoChanged",
+  "OnGroupMemberInfoChanged",
+  "OnGroupApplicationAccepted",
+  "OnGroupApplicationRejected",
+  "OnGroupDismissed",
+  "OnUserStatusChanged",
+  "OnSelfInfoUpdated",
+  "OnQRLoginStateChanged",
+  "OnConnStateChanged",
+  "OnNetworkQualityChanged",
+  "OnSyncProgress",
+  "OnSyncConflict",
+]);
+
+export function onEvent(handler) {
+  globalThis.commonEventFunc((data) => {
+    const event = JSON.parse(data);
+    if (jsonEvents.has(event.event)) event.data = decode(event.data);
+    handler(event);
+  });
+}
@@ -54,8 +54,21 @@ func (e *EventData) GetOperationID() string {
 func NewEventData(callback *js.Value) *EventData {
 	return &EventData{callback: callback}
 }
+
+// eventRelay is given the events sent too, e.g. to forward them to the other tabs, see SetEventRelay.
+var eventRelay func(event string)
+
+// SetEventRelay has the events sent given to relay as well, nil stops it.
+func SetEventRelay(relay func(event string)) {
+	eventRelay = relay
+}
+
 func (e *EventData) SendMessage() {
-	e.callback.Invoke(utils.StructToJsonString(e))
+	event := utils.StructToJsonString(e)
+	e.callback.Invoke(event)
+	if eventRelay != nil {
+		eventRelay(event)
+	}
 }
 func (e *EventData) SetEvent(event string) CallbackWriter {
 	e.Event = event
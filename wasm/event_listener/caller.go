@@ -28,9 +28,9 @@ import (
 
 	"github.com/openimsdk/tools/errs"
 
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
 	"github.com/openimsdk/openim-sdk-core/v3/wasm/exec"
-	"github.com/openimsdk/tools/log"
 )
 
 type Caller interface {
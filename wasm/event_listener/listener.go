@@ -216,6 +216,13 @@ func (s *SendMessageCallback) OnProgress(progress int) {
 	s.globalEvent.SetEvent(utils.GetSelfFuncName()).SetData(utils.StructToJsonString(mReply)).SendMessage()
 }
 
+func (s *SendMessageCallback) OnUploadSpeed(speed string) {
+	mReply := make(map[string]interface{})
+	_ = utils.JsonStringToStruct(speed, &mReply)
+	mReply["clientMsgID"] = s.clientMsgID
+	s.globalEvent.SetEvent(utils.GetSelfFuncName()).SetData(utils.StructToJsonString(mReply)).SendMessage()
+}
+
 type UploadInterface interface {
 	open_im_sdk_callback.Base
 	open_im_sdk_callback.UploadFileCallback
@@ -295,6 +302,13 @@ func (u *UploadFileCallback) UploadComplete(fileSize int64, streamSize int64, st
 	u.globalEvent.SetEvent(utils.GetSelfFuncName()).SetData(utils.StructToJsonString(mReply)).SendMessage()
 }
 
+func (u *UploadFileCallback) OnUploadSpeed(speed string) {
+	mReply := make(map[string]interface{})
+	_ = utils.JsonStringToStruct(speed, &mReply)
+	mReply["uuid"] = u.Uuid
+	u.globalEvent.SetEvent(utils.GetSelfFuncName()).SetData(utils.StructToJsonString(mReply)).SendMessage()
+}
+
 func (u *UploadFileCallback) Complete(size int64, url string, typ int) {
 	mReply := make(map[string]interface{})
 	mReply["size"] = size
@@ -420,6 +434,90 @@ func (c CustomBusinessCallback) OnRecvCustomBusinessMessage(businessMessage stri
 
 }
 
+type QRLoginCallback struct {
+	CallbackWriter
+}
+
+func NewQRLoginCallback(callback *js.Value) *QRLoginCallback {
+	return &QRLoginCallback{CallbackWriter: NewEventData(callback)}
+}
+
+func (q QRLoginCallback) OnQRLoginStateChanged(state string) {
+	q.CallbackWriter.SetEvent(utils.GetSelfFuncName()).SetData(state).SendMessage()
+}
+
+type TokenCallback struct {
+	CallbackWriter
+}
+
+func NewTokenCallback(callback *js.Value) *TokenCallback {
+	return &TokenCallback{CallbackWriter: NewEventData(callback)}
+}
+
+func (t TokenCallback) OnTokenWillExpire(expireTime int64) {
+	t.CallbackWriter.SetEvent(utils.GetSelfFuncName()).SetData(expireTime).SendMessage()
+}
+
+type ConnStateCallback struct {
+	CallbackWriter
+}
+
+func NewConnStateCallback(callback *js.Value) *ConnStateCallback {
+	return &ConnStateCallback{CallbackWriter: NewEventData(callback)}
+}
+
+func (c ConnStateCallback) OnConnStateChanged(state string) {
+	c.CallbackWriter.SetEvent(utils.GetSelfFuncName()).SetData(state).SendMessage()
+}
+
+type NetworkQualityCallback struct {
+	CallbackWriter
+}
+
+func NewNetworkQualityCallback(callback *js.Value) *NetworkQualityCallback {
+	return &NetworkQualityCallback{CallbackWriter: NewEventData(callback)}
+}
+
+func (n NetworkQualityCallback) OnNetworkQualityChanged(quality string) {
+	n.CallbackWriter.SetEvent(utils.GetSelfFuncName()).SetData(quality).SendMessage()
+}
+
+type AppLifecycleCallback struct {
+	CallbackWriter
+}
+
+func NewAppLifecycleCallback(callback *js.Value) *AppLifecycleCallback {
+	return &AppLifecycleCallback{CallbackWriter: NewEventData(callback)}
+}
+
+func (a AppLifecycleCallback) OnSyncCaughtUp() {
+	a.CallbackWriter.SetEvent(utils.GetSelfFuncName()).SendMessage()
+}
+
+type SyncProgressCallback struct {
+	CallbackWriter
+}
+
+func NewSyncProgressCallback(callback *js.Value) *SyncProgressCallback {
+	return &SyncProgressCallback{CallbackWriter: NewEventData(callback)}
+}
+
+func (s SyncProgressCallback) OnSyncProgress(progress string) {
+	s.CallbackWriter.SetEvent(utils.GetSelfFuncName()).SetData(progress).SendMessage()
+}
+
+type SyncConflictCallback struct {
+	CallbackWriter
+}
+
+func NewSyncConflictCallback(callback *js.Value) *SyncConflictCallback {
+	return &SyncConflictCallback{CallbackWriter: NewEventData(callback)}
+}
+
+func (s SyncConflictCallback) OnSyncConflict(conflict string) {
+	s.CallbackWriter.SetEvent(utils.GetSelfFuncName()).SetData(conflict).SendMessage()
+}
+
 type SignalingCallback struct {
 	CallbackWriter
 }
@@ -432,6 +530,10 @@ func (s SignalingCallback) OnRoomParticipantDisconnected(onRoomParticipantDiscon
 	s.CallbackWriter.SetEvent(utils.GetSelfFuncName()).SetData(onRoomParticipantDisconnectedCallback).SendMessage()
 }
 
+func (s SignalingCallback) OnReceiveSignalingData(signalingData string) {
+	s.CallbackWriter.SetEvent(utils.GetSelfFuncName()).SetData(signalingData).SendMessage()
+}
+
 func (s SignalingCallback) OnReceiveNewInvitation(receiveNewInvitationCallback string) {
 	s.CallbackWriter.SetEvent(utils.GetSelfFuncName()).SetData(receiveNewInvitationCallback).SendMessage()
 }
@@ -452,6 +554,10 @@ func (s SignalingCallback) OnInviteeRejectedByOtherDevice(inviteeRejectedCallbac
 	s.CallbackWriter.SetEvent(utils.GetSelfFuncName()).SetData(inviteeRejectedCallback).SendMessage()
 }
 
+func (s SignalingCallback) OnInviteeBusy(inviteeBusyCallback string) {
+	s.CallbackWriter.SetEvent(utils.GetSelfFuncName()).SetData(inviteeBusyCallback).SendMessage()
+}
+
 func (s SignalingCallback) OnInvitationCancelled(invitationCancelledCallback string) {
 	s.CallbackWriter.SetEvent(utils.GetSelfFuncName()).SetData(invitationCancelledCallback).SendMessage()
 }
@@ -24,9 +24,9 @@ import (
 	"syscall/js"
 	"time"
 
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/log"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
 	"github.com/openimsdk/tools/errs"
-	"github.com/openimsdk/tools/log"
 )
 
 type CallbackData struct {
@@ -44,8 +44,22 @@ var PrimaryKeyNull = errors.New("primary key is null err")
 var ErrTimoutFromJavaScript = errors.New("invoke javascript timeout, maybe should check  function from javascript")
 var jsErr = js.Global().Get("Error")
 
+var observer func(funcName string, cost time.Duration, err error)
+
+// SetObserver sets the function called after each call to javascript with its name, duration and error.
+func SetObserver(fn func(funcName string, cost time.Duration, err error)) {
+	observer = fn
+}
+
 func Exec(args ...interface{}) (output interface{}, err error) {
 	ctx := context.Background()
+	var funcName string
+	if observe := observer; observe != nil {
+		start := time.Now()
+		defer func() {
+			observe(funcName, time.Since(start), err)
+		}()
+	}
 	defer func() {
 		if r := recover(); r != nil {
 			switch x := r.(type) {
@@ -63,7 +77,7 @@ func Exec(args ...interface{}) (output interface{}, err error) {
 	catchChannel := make(chan []js.Value)
 	defer close(catchChannel)
 	pc, _, _, _ := runtime.Caller(1)
-	funcName := utils.CleanUpfuncName(runtime.FuncForPC(pc).Name())
+	funcName = utils.CleanUpfuncName(runtime.FuncForPC(pc).Name())
 	data := CallbackData{}
 	thenFunc := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
 		defer func() {
@@ -0,0 +1,71 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build js && wasm
+// +build js,wasm
+
+package indexdb
+
+import (
+	"context"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/wasm/exec"
+)
+
+type LocalAuditLogs struct {
+}
+
+func NewLocalAuditLogs() *LocalAuditLogs {
+	return &LocalAuditLogs{}
+}
+
+func (i *LocalAuditLogs) InsertAuditLog(ctx context.Context, auditLog *model_struct.LocalAuditLog) error {
+	_, err := exec.Exec(utils.StructToJsonString(auditLog))
+	return err
+}
+
+func (i *LocalAuditLogs) GetAuditLogs(ctx context.Context, actions []string, startTime, endTime int64, offset, count int) ([]*model_struct.LocalAuditLog, error) {
+	result, err := exec.Exec(utils.StructToJsonString(actions), startTime, endTime, offset, count)
+	if err != nil {
+		return nil, err
+	}
+	return auditLogs(result)
+}
+
+func (i *LocalAuditLogs) GetAuditLogsToUpload(ctx context.Context, count int) ([]*model_struct.LocalAuditLog, error) {
+	result, err := exec.Exec(count)
+	if err != nil {
+		return nil, err
+	}
+	return auditLogs(result)
+}
+
+func (i *LocalAuditLogs) SetAuditLogsUploaded(ctx context.Context, ids []int64) error {
+	_, err := exec.Exec(utils.StructToJsonString(ids))
+	return err
+}
+
+func auditLogs(result any) ([]*model_struct.LocalAuditLog, error) {
+	v, ok := result.(string)
+	if !ok {
+		return nil, exec.ErrType
+	}
+	var auditLogs []*model_struct.LocalAuditLog
+	if err := utils.JsonStringToStruct(v, &auditLogs); err != nil {
+		return nil, err
+	}
+	return auditLogs, nil
+}
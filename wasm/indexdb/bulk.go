@@ -0,0 +1,124 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build js && wasm
+// +build js,wasm
+
+package indexdb
+
+import (
+	"context"
+	"reflect"
+	"sync"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/wasm/exec"
+	"github.com/openimsdk/tools/errs"
+)
+
+// The bulk calls cross the js bridge once for the rows of many tables, the rows are keyed by the table
+// names of model_struct and are in the json of model_struct, the js side writes each call in one
+// transaction of IndexedDB.
+
+// BulkPut inserts or replaces the rows of the tables.
+func BulkPut(ctx context.Context, rows map[string][]any) error {
+	_, err := exec.Exec(utils.StructToJsonString(rows))
+	return err
+}
+
+// BulkGet gets the rows of the tables by primary key into result, a map of the tables to their rows. The
+// keys not found are left out.
+func BulkGet(ctx context.Context, keys map[string][]string, result any) error {
+	rows, err := exec.Exec(utils.StructToJsonString(keys))
+	if err != nil {
+		return err
+	}
+	v, ok := rows.(string)
+	if !ok {
+		return exec.ErrType
+	}
+	return utils.JsonStringToStruct(v, result)
+}
+
+// BulkDelete deletes the rows of the tables by primary key.
+func BulkDelete(ctx context.Context, keys map[string][]string) error {
+	_, err := exec.Exec(utils.StructToJsonString(keys))
+	return err
+}
+
+type bulkWritesKey struct{}
+
+// bulkWrites are the rows put within a transaction of the database, put by one BulkPut once it ends.
+type bulkWrites struct {
+	lock sync.Mutex
+	rows map[string][]any
+}
+
+// WithBulkWrites makes the puts of the calls with the context wait for FlushBulkWrites.
+func WithBulkWrites(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bulkWritesKey{}, &bulkWrites{rows: make(map[string][]any)})
+}
+
+// HasBulkWrites tells whether the puts of the calls with the context wait for FlushBulkWrites.
+func HasBulkWrites(ctx context.Context) bool {
+	_, ok := ctx.Value(bulkWritesKey{}).(*bulkWrites)
+	return ok
+}
+
+// FlushBulkWrites puts the rows of the context put since WithBulkWrites.
+func FlushBulkWrites(ctx context.Context) error {
+	b, ok := ctx.Value(bulkWritesKey{}).(*bulkWrites)
+	if !ok {
+		return nil
+	}
+	b.lock.Lock()
+	rows := b.rows
+	b.rows = make(map[string][]any)
+	b.lock.Unlock()
+	if len(rows) == 0 {
+		return nil
+	}
+	return BulkPut(ctx, rows)
+}
+
+// putRows puts the rows of the table now, or with the other writes of the transaction of the context.
+func putRows[T any](ctx context.Context, table string, rows []T) error {
+	values := make([]any, 0, len(rows))
+	for _, row := range rows {
+		values = append(values, row)
+	}
+	if b, ok := ctx.Value(bulkWritesKey{}).(*bulkWrites); ok {
+		b.lock.Lock()
+		defer b.lock.Unlock()
+		b.rows[table] = append(b.rows[table], values...)
+		return nil
+	}
+	return BulkPut(ctx, map[string][]any{table: values})
+}
+
+// mergeNonZero sets the fields of src that are not zero on dst, as an update of the sqlite database with a
+// struct does.
+func mergeNonZero(dst, src any) error {
+	d, s := reflect.ValueOf(dst), reflect.ValueOf(src)
+	if d.Kind() != reflect.Pointer || s.Kind() != reflect.Pointer || d.Type() != s.Type() {
+		return errs.New("merge of different types")
+	}
+	d, s = d.Elem(), s.Elem()
+	for i := 0; i < s.NumField(); i++ {
+		if field := s.Field(i); !field.IsZero() && d.Field(i).CanSet() {
+			d.Field(i).Set(field)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build js && wasm
+// +build js,wasm
+
+package indexdb
+
+import (
+	"context"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/wasm/exec"
+)
+
+type LocalCallRecords struct {
+}
+
+func NewLocalCallRecords() *LocalCallRecords {
+	return &LocalCallRecords{}
+}
+
+func (i *LocalCallRecords) SetCallRecord(ctx context.Context, record *model_struct.LocalCallRecord) error {
+	_, err := exec.Exec(utils.StructToJsonString(record))
+	return err
+}
+
+func (i *LocalCallRecords) GetCallRecordsAfter(ctx context.Context, peerUserID, groupID, mediaType string, states []string, missed bool,
+	initiateTime int64, roomID string, count int) ([]*model_struct.LocalCallRecord, error) {
+	result, err := exec.Exec(peerUserID, groupID, mediaType, utils.StructToJsonString(states), missed, initiateTime, roomID, count)
+	if err != nil {
+		return nil, err
+	}
+	v, ok := result.(string)
+	if !ok {
+		return nil, exec.ErrType
+	}
+	var records []*model_struct.LocalCallRecord
+	if err := utils.JsonStringToStruct(v, &records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
@@ -700,6 +700,51 @@ func (i *LocalChatLogs) DeleteConversationMsgs(ctx context.Context, conversation
 	return err
 }
 
+// DeleteOldestMessages deletes at most count of the oldest messages of the session, the latest keep and the
+// ones whose media are pinned are kept
+func (i *LocalChatLogs) DeleteOldestMessages(ctx context.Context, conversationID string, count, keep int) (int64, error) {
+	rows, err := exec.Exec(conversationID, count, keep)
+	if err != nil {
+		return 0, err
+	}
+	if v, ok := rows.(float64); ok {
+		return int64(v), nil
+	}
+	return 0, exec.ErrType
+}
+
+// DeleteMessagesBeforeSendTime deletes the messages of the session sent before sendTime
+func (i *LocalChatLogs) DeleteMessagesBeforeSendTime(ctx context.Context, conversationID string, sendTime int64) (int64, error) {
+	rows, err := exec.Exec(conversationID, sendTime)
+	if err != nil {
+		return 0, err
+	}
+	if v, ok := rows.(float64); ok {
+		return int64(v), nil
+	}
+	return 0, exec.ErrType
+}
+
+// GetConversationMsgStorage gets the number of messages of the session and an estimate of their bytes
+func (i *LocalChatLogs) GetConversationMsgStorage(ctx context.Context, conversationID string) (count int64, size int64, err error) {
+	result, err := exec.Exec(conversationID)
+	if err != nil {
+		return 0, 0, err
+	}
+	v, ok := result.(string)
+	if !ok {
+		return 0, 0, exec.ErrType
+	}
+	var storage struct {
+		Count int64 `json:"count"`
+		Size  int64 `json:"size"`
+	}
+	if err := utils.JsonStringToStruct(v, &storage); err != nil {
+		return 0, 0, err
+	}
+	return storage.Count, storage.Size, nil
+}
+
 // DeleteConversationMsgsBySeqs deletes messages of the session
 func (i *LocalChatLogs) DeleteConversationMsgsBySeqs(ctx context.Context, conversationID string, seqs []int64) error {
 	_, err := exec.Exec(conversationID, utils.StructToJsonString(seqs))
@@ -0,0 +1,53 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build js && wasm
+// +build js,wasm
+
+package indexdb
+
+import (
+	"context"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/wasm/exec"
+)
+
+type LocalClientConfig struct {
+}
+
+func NewLocalClientConfig() *LocalClientConfig {
+	return &LocalClientConfig{}
+}
+
+func (i *LocalClientConfig) GetClientConfig(ctx context.Context) (map[string]string, error) {
+	result, err := exec.Exec()
+	if err != nil {
+		return nil, err
+	}
+	v, ok := result.(string)
+	if !ok {
+		return nil, exec.ErrType
+	}
+	configs := make(map[string]string)
+	if err := utils.JsonStringToStruct(v, &configs); err != nil {
+		return nil, err
+	}
+	return configs, nil
+}
+
+func (i *LocalClientConfig) SetClientConfig(ctx context.Context, configs map[string]string) error {
+	_, err := exec.Exec(utils.StructToJsonString(configs))
+	return err
+}
@@ -177,6 +177,27 @@ func (i *LocalConversations) GetConversationListSplitDB(ctx context.Context, off
 	}
 }
 
+// GetConversationListAfterDB gets the conversations sorted after the given one, from the start when conversationID is empty
+func (i *LocalConversations) GetConversationListAfterDB(ctx context.Context, pinned bool, sortTime int64, conversationID string, count int) (result []*model_struct.LocalConversation, err error) {
+	cList, err := exec.Exec(pinned, sortTime, conversationID, count)
+	if err != nil {
+		return nil, err
+	}
+	v, ok := cList.(string)
+	if !ok {
+		return nil, exec.ErrType
+	}
+	var temp []model_struct.LocalConversation
+	if err := utils.JsonStringToStruct(v, &temp); err != nil {
+		return nil, err
+	}
+	for _, v := range temp {
+		v1 := v
+		result = append(result, &v1)
+	}
+	return result, nil
+}
+
 func (i *LocalConversations) BatchInsertConversationList(ctx context.Context, conversationList []*model_struct.LocalConversation) error {
 	_, err := exec.Exec(utils.StructToJsonString(conversationList))
 	return err
@@ -245,13 +266,46 @@ func (i *LocalConversations) UpdateConversationForSync(ctx context.Context, c *m
 	return err
 }
 
+// BatchUpdateConversationList gets the conversations and puts them back updated in one call each, instead
+// of a call per conversation.
 func (i *LocalConversations) BatchUpdateConversationList(ctx context.Context, conversationList []*model_struct.LocalConversation) error {
+	if len(conversationList) == 0 {
+		return nil
+	}
+	table := model_struct.LocalConversation{}.TableName()
+	conversationIDs := make([]string, 0, len(conversationList))
 	for _, v := range conversationList {
-		err := i.UpdateConversation(ctx, v)
-		if err != nil {
-			return errs.WrapMsg(err, "BatchUpdateConversationList failed")
+		if v.ConversationID == "" {
+			return exec.PrimaryKeyNull
 		}
-
+		conversationIDs = append(conversationIDs, v.ConversationID)
+	}
+	var rows map[string][]*model_struct.LocalConversation
+	if err := BulkGet(ctx, map[string][]string{table: conversationIDs}, &rows); err != nil {
+		return errs.WrapMsg(err, "BatchUpdateConversationList failed")
+	}
+	local := make(map[string]*model_struct.LocalConversation, len(rows[table]))
+	for _, v := range rows[table] {
+		local[v.ConversationID] = v
+	}
+	updated := make([]*model_struct.LocalConversation, 0, len(conversationList))
+	var missing []string
+	for _, v := range conversationList {
+		conversation, ok := local[v.ConversationID]
+		if !ok {
+			missing = append(missing, v.ConversationID)
+			continue
+		}
+		if err := mergeNonZero(conversation, v); err != nil {
+			return err
+		}
+		updated = append(updated, conversation)
+	}
+	if err := putRows(ctx, table, updated); err != nil {
+		return errs.WrapMsg(err, "BatchUpdateConversationList failed")
+	}
+	if len(missing) > 0 {
+		return errs.New("BatchUpdateConversationList failed, no update", "conversationIDs", missing)
 	}
 	return nil
 }
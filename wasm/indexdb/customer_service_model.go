@@ -0,0 +1,70 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build js && wasm
+// +build js,wasm
+
+package indexdb
+
+import (
+	"context"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/wasm/exec"
+)
+
+type LocalCustomerService struct {
+}
+
+func NewLocalCustomerService() *LocalCustomerService {
+	return &LocalCustomerService{}
+}
+
+func (i *LocalCustomerService) SetCustomerServiceSessions(ctx context.Context, sessions []*model_struct.LocalCustomerServiceSession) error {
+	_, err := exec.Exec(utils.StructToJsonString(sessions))
+	return err
+}
+
+func (i *LocalCustomerService) GetCustomerServiceSession(ctx context.Context, sessionID string) (*model_struct.LocalCustomerServiceSession, error) {
+	result, err := exec.Exec(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	v, ok := result.(string)
+	if !ok {
+		return nil, exec.ErrType
+	}
+	var session model_struct.LocalCustomerServiceSession
+	if err := utils.JsonStringToStruct(v, &session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (i *LocalCustomerService) GetCustomerServiceSessions(ctx context.Context, states []string) ([]*model_struct.LocalCustomerServiceSession, error) {
+	result, err := exec.Exec(utils.StructToJsonString(states))
+	if err != nil {
+		return nil, err
+	}
+	v, ok := result.(string)
+	if !ok {
+		return nil, exec.ErrType
+	}
+	var sessions []*model_struct.LocalCustomerServiceSession
+	if err := utils.JsonStringToStruct(v, &sessions); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
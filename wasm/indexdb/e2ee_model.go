@@ -0,0 +1,183 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build js && wasm
+// +build js,wasm
+
+package indexdb
+
+import (
+	"context"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/wasm/exec"
+)
+
+type LocalE2EE struct {
+}
+
+func NewLocalE2EE() *LocalE2EE {
+	return &LocalE2EE{}
+}
+
+func (i *LocalE2EE) InsertE2EEKeys(ctx context.Context, keys []*model_struct.LocalE2EEKey) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	_, err := exec.Exec(utils.StructToJsonString(keys))
+	return err
+}
+
+func (i *LocalE2EE) GetE2EEKey(ctx context.Context, keyType int32, keyID string) (*model_struct.LocalE2EEKey, error) {
+	result, err := exec.Exec(keyType, keyID)
+	if err != nil {
+		return nil, err
+	}
+	v, ok := result.(string)
+	if !ok {
+		return nil, exec.ErrType
+	}
+	var key model_struct.LocalE2EEKey
+	if err := utils.JsonStringToStruct(v, &key); err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (i *LocalE2EE) GetE2EEKeysByType(ctx context.Context, keyType int32) ([]*model_struct.LocalE2EEKey, error) {
+	result, err := exec.Exec(keyType)
+	if err != nil {
+		return nil, err
+	}
+	v, ok := result.(string)
+	if !ok {
+		return nil, exec.ErrType
+	}
+	var keys []*model_struct.LocalE2EEKey
+	if err := utils.JsonStringToStruct(v, &keys); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (i *LocalE2EE) DeleteE2EEKey(ctx context.Context, keyType int32, keyID string) error {
+	_, err := exec.Exec(keyType, keyID)
+	return err
+}
+
+func (i *LocalE2EE) GetE2EESession(ctx context.Context, userID, deviceID string) (*model_struct.LocalE2EESession, error) {
+	result, err := exec.Exec(userID, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	v, ok := result.(string)
+	if !ok {
+		return nil, exec.ErrType
+	}
+	var session model_struct.LocalE2EESession
+	if err := utils.JsonStringToStruct(v, &session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (i *LocalE2EE) GetE2EESessions(ctx context.Context, userID string) ([]*model_struct.LocalE2EESession, error) {
+	result, err := exec.Exec(userID)
+	if err != nil {
+		return nil, err
+	}
+	v, ok := result.(string)
+	if !ok {
+		return nil, exec.ErrType
+	}
+	var sessions []*model_struct.LocalE2EESession
+	if err := utils.JsonStringToStruct(v, &sessions); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (i *LocalE2EE) GetAllE2EESessions(ctx context.Context) ([]*model_struct.LocalE2EESession, error) {
+	result, err := exec.Exec()
+	if err != nil {
+		return nil, err
+	}
+	v, ok := result.(string)
+	if !ok {
+		return nil, exec.ErrType
+	}
+	var sessions []*model_struct.LocalE2EESession
+	if err := utils.JsonStringToStruct(v, &sessions); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (i *LocalE2EE) SetE2EESession(ctx context.Context, session *model_struct.LocalE2EESession) error {
+	_, err := exec.Exec(utils.StructToJsonString(session))
+	return err
+}
+
+func (i *LocalE2EE) DeleteE2EESession(ctx context.Context, userID, deviceID string) error {
+	_, err := exec.Exec(userID, deviceID)
+	return err
+}
+
+func (i *LocalE2EE) GetE2EEMessageKey(ctx context.Context, clientMsgID string) (*model_struct.LocalE2EEMessageKey, error) {
+	result, err := exec.Exec(clientMsgID)
+	if err != nil {
+		return nil, err
+	}
+	v, ok := result.(string)
+	if !ok {
+		return nil, exec.ErrType
+	}
+	var key model_struct.LocalE2EEMessageKey
+	if err := utils.JsonStringToStruct(v, &key); err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (i *LocalE2EE) SetE2EEMessageKey(ctx context.Context, key *model_struct.LocalE2EEMessageKey) error {
+	_, err := exec.Exec(utils.StructToJsonString(key))
+	return err
+}
+
+func (i *LocalE2EE) GetE2EEDevices(ctx context.Context, userID string) ([]*model_struct.LocalE2EEDevice, error) {
+	result, err := exec.Exec(userID)
+	if err != nil {
+		return nil, err
+	}
+	v, ok := result.(string)
+	if !ok {
+		return nil, exec.ErrType
+	}
+	var devices []*model_struct.LocalE2EEDevice
+	if err := utils.JsonStringToStruct(v, &devices); err != nil {
+		return nil, err
+	}
+	return devices, nil
+}
+
+func (i *LocalE2EE) SetE2EEDevice(ctx context.Context, device *model_struct.LocalE2EEDevice) error {
+	_, err := exec.Exec(utils.StructToJsonString(device))
+	return err
+}
+
+func (i *LocalE2EE) DeleteE2EEDevice(ctx context.Context, userID, deviceID string) error {
+	_, err := exec.Exec(userID, deviceID)
+	return err
+}
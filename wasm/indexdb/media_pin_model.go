@@ -0,0 +1,59 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build js && wasm
+// +build js,wasm
+
+package indexdb
+
+import (
+	"context"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/wasm/exec"
+)
+
+type LocalMediaPins struct {
+}
+
+func NewLocalMediaPins() *LocalMediaPins {
+	return &LocalMediaPins{}
+}
+
+func (i *LocalMediaPins) InsertMediaPin(ctx context.Context, pin *model_struct.LocalMediaPin) error {
+	_, err := exec.Exec(utils.StructToJsonString(pin))
+	return err
+}
+
+func (i *LocalMediaPins) DeleteMediaPin(ctx context.Context, conversationID, clientMsgID string) error {
+	_, err := exec.Exec(conversationID, clientMsgID)
+	return err
+}
+
+func (i *LocalMediaPins) GetAllMediaPins(ctx context.Context) ([]*model_struct.LocalMediaPin, error) {
+	result, err := exec.Exec()
+	if err != nil {
+		return nil, err
+	}
+	v, ok := result.(string)
+	if !ok {
+		return nil, exec.ErrType
+	}
+	var pins []*model_struct.LocalMediaPin
+	if err := utils.JsonStringToStruct(v, &pins); err != nil {
+		return nil, err
+	}
+	return pins, nil
+}
@@ -0,0 +1,58 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//go:build js && wasm
+// +build js,wasm
+
+package indexdb
+
+import (
+	"context"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/wasm/exec"
+)
+
+type LocalMessageTranslations struct {
+}
+
+func NewLocalMessageTranslations() *LocalMessageTranslations {
+	return &LocalMessageTranslations{}
+}
+
+func (i *LocalMessageTranslations) GetMessageTranslation(ctx context.Context, clientMsgID, lang string) (*model_struct.LocalMessageTranslation, error) {
+	result, err := exec.Exec(clientMsgID, lang)
+	if err != nil {
+		return nil, err
+	}
+	v, ok := result.(string)
+	if !ok {
+		return nil, exec.ErrType
+	}
+	var translation model_struct.LocalMessageTranslation
+	if err := utils.JsonStringToStruct(v, &translation); err != nil {
+		return nil, err
+	}
+	return &translation, nil
+}
+
+func (i *LocalMessageTranslations) SetMessageTranslation(ctx context.Context, translation *model_struct.LocalMessageTranslation) error {
+	_, err := exec.Exec(utils.StructToJsonString(translation))
+	return err
+}
+
+func (i *LocalMessageTranslations) DeleteMessageTranslations(ctx context.Context, clientMsgIDs []string) error {
+	_, err := exec.Exec(utils.StructToJsonString(clientMsgIDs))
+	return err
+}
@@ -0,0 +1,75 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build js && wasm
+// +build js,wasm
+
+package indexdb
+
+import (
+	"context"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/wasm/exec"
+)
+
+type LocalMoments struct {
+}
+
+func NewLocalMoments() *LocalMoments {
+	return &LocalMoments{}
+}
+
+func (i *LocalMoments) SetMoments(ctx context.Context, moments []*model_struct.LocalMoment) error {
+	_, err := exec.Exec(utils.StructToJsonString(moments))
+	return err
+}
+
+func (i *LocalMoments) GetMoment(ctx context.Context, momentID string) (*model_struct.LocalMoment, error) {
+	result, err := exec.Exec(momentID)
+	if err != nil {
+		return nil, err
+	}
+	v, ok := result.(string)
+	if !ok {
+		return nil, exec.ErrType
+	}
+	var moment model_struct.LocalMoment
+	if err := utils.JsonStringToStruct(v, &moment); err != nil {
+		return nil, err
+	}
+	return &moment, nil
+}
+
+func (i *LocalMoments) GetMomentsAfter(ctx context.Context, userID string, createTime int64, momentID string, count int) ([]*model_struct.LocalMoment, error) {
+	result, err := exec.Exec(userID, createTime, momentID, count)
+	if err != nil {
+		return nil, err
+	}
+	v, ok := result.(string)
+	if !ok {
+		return nil, exec.ErrType
+	}
+	var moments []*model_struct.LocalMoment
+	if err := utils.JsonStringToStruct(v, &moments); err != nil {
+		return nil, err
+	}
+	return moments, nil
+}
+
+func (i *LocalMoments) DeleteMoment(ctx context.Context, momentID string) error {
+	_, err := exec.Exec(momentID)
+	return err
+}
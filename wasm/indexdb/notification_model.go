@@ -32,7 +32,12 @@ func NewNotificationSeqs() *NotificationSeqs {
 	return &NotificationSeqs{}
 }
 
+// SetNotificationSeq puts the seq with the other writes of the transaction of the context, if there is one.
 func (i *NotificationSeqs) SetNotificationSeq(ctx context.Context, conversationID string, seq int64) error {
+	if HasBulkWrites(ctx) {
+		return putRows(ctx, model_struct.NotificationSeqs{}.TableName(),
+			[]*model_struct.NotificationSeqs{{ConversationID: conversationID, Seq: seq}})
+	}
 	_, err := exec.Exec(conversationID, seq)
 	return err
 }
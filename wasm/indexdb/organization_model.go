@@ -0,0 +1,196 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build js && wasm
+// +build js,wasm
+
+package indexdb
+
+import (
+	"context"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/wasm/exec"
+)
+
+type LocalOrganization struct {
+}
+
+func NewLocalOrganization() *LocalOrganization {
+	return &LocalOrganization{}
+}
+
+func (i *LocalOrganization) InsertDepartment(ctx context.Context, department *model_struct.LocalDepartment) error {
+	_, err := exec.Exec(utils.StructToJsonString(department))
+	return err
+}
+
+func (i *LocalOrganization) BatchInsertDepartments(ctx context.Context, departments []*model_struct.LocalDepartment) error {
+	_, err := exec.Exec(utils.StructToJsonString(departments))
+	return err
+}
+
+func (i *LocalOrganization) UpdateDepartment(ctx context.Context, department *model_struct.LocalDepartment) error {
+	_, err := exec.Exec(utils.StructToJsonString(department))
+	return err
+}
+
+func (i *LocalOrganization) DeleteDepartment(ctx context.Context, departmentID string) error {
+	_, err := exec.Exec(departmentID)
+	return err
+}
+
+func (i *LocalOrganization) DeleteAllDepartments(ctx context.Context) error {
+	_, err := exec.Exec()
+	return err
+}
+
+func (i *LocalOrganization) GetDepartment(ctx context.Context, departmentID string) (*model_struct.LocalDepartment, error) {
+	result, err := exec.Exec(departmentID)
+	if err != nil {
+		return nil, err
+	}
+	v, ok := result.(string)
+	if !ok {
+		return nil, exec.ErrType
+	}
+	var department model_struct.LocalDepartment
+	if err := utils.JsonStringToStruct(v, &department); err != nil {
+		return nil, err
+	}
+	return &department, nil
+}
+
+func (i *LocalOrganization) GetAllDepartments(ctx context.Context) ([]*model_struct.LocalDepartment, error) {
+	result, err := exec.Exec()
+	if err != nil {
+		return nil, err
+	}
+	v, ok := result.(string)
+	if !ok {
+		return nil, exec.ErrType
+	}
+	var departments []*model_struct.LocalDepartment
+	if err := utils.JsonStringToStruct(v, &departments); err != nil {
+		return nil, err
+	}
+	return departments, nil
+}
+
+func (i *LocalOrganization) GetSubDepartments(ctx context.Context, parentID string) ([]*model_struct.LocalDepartment, error) {
+	result, err := exec.Exec(parentID)
+	if err != nil {
+		return nil, err
+	}
+	v, ok := result.(string)
+	if !ok {
+		return nil, exec.ErrType
+	}
+	var departments []*model_struct.LocalDepartment
+	if err := utils.JsonStringToStruct(v, &departments); err != nil {
+		return nil, err
+	}
+	return departments, nil
+}
+
+func (i *LocalOrganization) InsertDepartmentMember(ctx context.Context, member *model_struct.LocalDepartmentMember) error {
+	_, err := exec.Exec(utils.StructToJsonString(member))
+	return err
+}
+
+func (i *LocalOrganization) BatchInsertDepartmentMembers(ctx context.Context, members []*model_struct.LocalDepartmentMember) error {
+	_, err := exec.Exec(utils.StructToJsonString(members))
+	return err
+}
+
+func (i *LocalOrganization) UpdateDepartmentMember(ctx context.Context, member *model_struct.LocalDepartmentMember) error {
+	_, err := exec.Exec(utils.StructToJsonString(member))
+	return err
+}
+
+func (i *LocalOrganization) DeleteDepartmentMember(ctx context.Context, departmentID, userID string) error {
+	_, err := exec.Exec(departmentID, userID)
+	return err
+}
+
+func (i *LocalOrganization) DeleteAllDepartmentMembers(ctx context.Context) error {
+	_, err := exec.Exec()
+	return err
+}
+
+func (i *LocalOrganization) GetAllDepartmentMembers(ctx context.Context) ([]*model_struct.LocalDepartmentMember, error) {
+	result, err := exec.Exec()
+	if err != nil {
+		return nil, err
+	}
+	v, ok := result.(string)
+	if !ok {
+		return nil, exec.ErrType
+	}
+	var members []*model_struct.LocalDepartmentMember
+	if err := utils.JsonStringToStruct(v, &members); err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+func (i *LocalOrganization) GetDepartmentMembers(ctx context.Context, departmentIDs []string) ([]*model_struct.LocalDepartmentMember, error) {
+	result, err := exec.Exec(utils.StructToJsonString(departmentIDs))
+	if err != nil {
+		return nil, err
+	}
+	v, ok := result.(string)
+	if !ok {
+		return nil, exec.ErrType
+	}
+	var members []*model_struct.LocalDepartmentMember
+	if err := utils.JsonStringToStruct(v, &members); err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+func (i *LocalOrganization) GetUserDepartmentMembers(ctx context.Context, userID string) ([]*model_struct.LocalDepartmentMember, error) {
+	result, err := exec.Exec(userID)
+	if err != nil {
+		return nil, err
+	}
+	v, ok := result.(string)
+	if !ok {
+		return nil, exec.ErrType
+	}
+	var members []*model_struct.LocalDepartmentMember
+	if err := utils.JsonStringToStruct(v, &members); err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+func (i *LocalOrganization) SearchDepartmentMembers(ctx context.Context, keyword string, isSearchNickname, isSearchPosition bool,
+	departmentIDs []string, count int) ([]*model_struct.LocalDepartmentMember, error) {
+	result, err := exec.Exec(keyword, isSearchNickname, isSearchPosition, utils.StructToJsonString(departmentIDs), count)
+	if err != nil {
+		return nil, err
+	}
+	v, ok := result.(string)
+	if !ok {
+		return nil, exec.ErrType
+	}
+	var members []*model_struct.LocalDepartmentMember
+	if err := utils.JsonStringToStruct(v, &members); err != nil {
+		return nil, err
+	}
+	return members, nil
+}
@@ -0,0 +1,42 @@
+//go:build js && wasm
+// +build js,wasm
+
+package indexdb
+
+import (
+	"context"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/wasm/exec"
+)
+
+type LocalPrivacySettings struct {
+}
+
+func NewLocalPrivacySettings() *LocalPrivacySettings {
+	return &LocalPrivacySettings{}
+}
+
+func (i *LocalPrivacySettings) GetPrivacySettings(ctx context.Context, userID string) (*model_struct.LocalPrivacySettings, error) {
+	settings, err := exec.Exec(userID)
+	if err != nil {
+		return nil, err
+	} else {
+		if v, ok := settings.(string); ok {
+			result := model_struct.LocalPrivacySettings{}
+			err := utils.JsonStringToStruct(v, &result)
+			if err != nil {
+				return nil, err
+			}
+			return &result, err
+		} else {
+			return nil, exec.ErrType
+		}
+	}
+}
+
+func (i *LocalPrivacySettings) SetPrivacySettings(ctx context.Context, settings *model_struct.LocalPrivacySettings) error {
+	_, err := exec.Exec(utils.StructToJsonString(settings))
+	return err
+}
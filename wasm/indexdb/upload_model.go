@@ -48,6 +48,43 @@ func (i *LocalUpload) GetUpload(ctx context.Context, partHash string) (*model_st
 	}
 }
 
+func (i *LocalUpload) GetUploadByFingerprint(ctx context.Context, fingerprint string) (*model_struct.LocalUpload, error) {
+	c, err := exec.Exec(fingerprint)
+	if err != nil {
+		return nil, err
+	}
+	v, ok := c.(string)
+	if !ok {
+		return nil, exec.ErrType
+	}
+	result := model_struct.LocalUpload{}
+	if err := utils.JsonStringToStruct(v, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (i *LocalUpload) GetUploadedFile(ctx context.Context, fingerprint string) (*model_struct.LocalUploadedFile, error) {
+	c, err := exec.Exec(fingerprint)
+	if err != nil {
+		return nil, err
+	}
+	v, ok := c.(string)
+	if !ok {
+		return nil, exec.ErrType
+	}
+	result := model_struct.LocalUploadedFile{}
+	if err := utils.JsonStringToStruct(v, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (i *LocalUpload) SetUploadedFile(ctx context.Context, file *model_struct.LocalUploadedFile) error {
+	_, err := exec.Exec(utils.StructToJsonString(file))
+	return err
+}
+
 func (i *LocalUpload) InsertUpload(ctx context.Context, upload *model_struct.LocalUpload) error {
 	_, err := exec.Exec(utils.StructToJsonString(upload))
 	return err
@@ -0,0 +1,467 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build js && wasm
+// +build js,wasm
+
+// Package tabs coordinates the tabs of a browser running the SDK, once enabled by enableMultiTab. One tab, the
+// leader, owns the long connection and the database; the others proxy their calls to it and receive its
+// events. The leader is elected with the Web Locks API and the tabs talk over a BroadcastChannel. When the
+// leader closes, the next tab waiting for the lock takes over: it initializes and logs in with the arguments
+// the app gave it, and the calls pending on the former leader fail with a network error.
+package tabs
+
+import (
+	"sync"
+	"syscall/js"
+
+	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/wasm/event_listener"
+)
+
+const (
+	RoleLeader   = "leader"
+	RoleFollower = "follower"
+
+	// defaultChannel names the lock and the channel when the app gives none
+	defaultChannel = "openim-sdk"
+	// roleChangedEvent tells the app the role of its tab, the data is RoleLeader or RoleFollower
+	roleChangedEvent = "OnTabRoleChanged"
+)
+
+// The kinds of the messages of the channel.
+const (
+	kindCall   = "call"
+	kindResult = "result"
+	kindEvent  = "event"
+	kindLeader = "leader"
+)
+
+// followerNoop are the calls telling the state of the tab itself, a follower does not pass them to the leader
+// which has its own.
+var followerNoop = map[string]struct{}{
+	"setAppBackgroundStatus": {},
+	"enterBackground":        {},
+	"enterForeground":        {},
+	"networkStatusChanged":   {},
+}
+
+var (
+	jsPromise = js.Global().Get("Promise")
+	// pendingForever is the executor of the promise holding the lock of the leader until the tab closes
+	pendingForever = js.FuncOf(func(js.Value, []js.Value) any { return nil })
+)
+
+type call struct {
+	resolve, reject js.Value
+}
+
+// Coordinator passes the calls of the functions registered to the leader tab once enabled, they are called
+// in the tab itself until then.
+type Coordinator struct {
+	eventFunc func() *js.Value
+	tabID     string
+
+	lock    sync.Mutex
+	funcs   map[string]func(js.Value, []js.Value) any
+	enabled bool
+	role    string
+	decided chan struct{}
+	channel js.Value
+	pending map[string]*call
+	// initArgs and loginArgs are the ones of the app, to take over as the leader
+	initArgs    []js.Value
+	initialized bool
+	loginFunc   string
+	loginArgs   []js.Value
+	onMessage   js.Func
+	// initLock is held while the initSDK of the app runs late, see ensureInit
+	initLock sync.Mutex
+}
+
+// New returns a Coordinator giving the events of the leader to the function of the app returned by eventFunc.
+func New(eventFunc func() *js.Value) *Coordinator {
+	return &Coordinator{
+		eventFunc: eventFunc,
+		tabID:     utils.OperationIDGenerator(),
+		funcs:     make(map[string]func(js.Value, []js.Value) any),
+		decided:   make(chan struct{}),
+		pending:   make(map[string]*call),
+	}
+}
+
+// Register sets the global function of the name, called by the leader tab.
+func (c *Coordinator) Register(name string, fn func(js.Value, []js.Value) any) {
+	c.lock.Lock()
+	c.funcs[name] = fn
+	c.lock.Unlock()
+	js.Global().Set(name, js.FuncOf(func(this js.Value, args []js.Value) any {
+		return c.call(name, fn, this, args)
+	}))
+}
+
+// Enable starts the election of the tabs sharing the channel, before initSDK. It returns a promise of the role of
+// the tab, RoleLeader or RoleFollower. A tab stays leader without the Web Locks API.
+func (c *Coordinator) Enable(_ js.Value, args []js.Value) any {
+	name := defaultChannel
+	if len(args) > 0 && args[0].Type() == js.TypeString && args[0].String() != "" {
+		name = args[0].String()
+	}
+	c.lock.Lock()
+	if c.enabled {
+		c.lock.Unlock()
+		return c.rolePromise()
+	}
+	c.enabled = true
+	if js.Global().Get("BroadcastChannel").IsUndefined() || js.Global().Get("navigator").Get("locks").IsUndefined() {
+		c.lock.Unlock()
+		c.decide(RoleLeader)
+		return c.rolePromise()
+	}
+	c.channel = js.Global().Get("BroadcastChannel").New(name)
+	c.onMessage = js.FuncOf(func(_ js.Value, args []js.Value) any {
+		c.receive(args[0].Get("data"))
+		return nil
+	})
+	c.channel.Call("addEventListener", "message", c.onMessage)
+	c.lock.Unlock()
+	c.elect(name)
+	return c.rolePromise()
+}
+
+// IsLeader tells whether the tab owns the connection, true while the coordination is not enabled.
+func (c *Coordinator) IsLeader(js.Value, []js.Value) any {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return !c.enabled || c.role == RoleLeader
+}
+
+// elect tries the lock, a tab finding it held waits for it as a follower.
+func (c *Coordinator) elect(name string) {
+	var leader js.Func
+	leader = js.FuncOf(func(_ js.Value, args []js.Value) any {
+		defer leader.Release()
+		if len(args) == 0 || args[0].IsNull() {
+			c.decide(RoleFollower)
+			c.waitLock(name)
+			return nil
+		}
+		c.decide(RoleLeader)
+		return jsPromise.New(pendingForever)
+	})
+	js.Global().Get("navigator").Get("locks").Call("request", name, map[string]any{"ifAvailable": true}, leader)
+}
+
+// waitLock waits for the lock of the leader, the tab takes over once it has it.
+func (c *Coordinator) waitLock(name string) {
+	var takeOver js.Func
+	takeOver = js.FuncOf(func(js.Value, []js.Value) any {
+		defer takeOver.Release()
+		go c.takeOver()
+		return jsPromise.New(pendingForever)
+	})
+	js.Global().Get("navigator").Get("locks").Call("request", name, takeOver)
+}
+
+func (c *Coordinator) decide(role string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.role = role
+	select {
+	case <-c.decided:
+	default:
+		close(c.decided)
+	}
+	if role == RoleLeader {
+		event_listener.SetEventRelay(c.relay)
+		c.post(map[string]any{"kind": kindLeader, "tab": c.tabID})
+	}
+}
+
+// takeOver makes the follower the leader, it initializes and logs in with the arguments of the app.
+func (c *Coordinator) takeOver() {
+	c.decide(RoleLeader)
+	c.rejectPending("the leader tab closed")
+	if c.ensureInit("") {
+		c.lock.Lock()
+		loginArgs, login := c.loginArgs, c.funcs[c.loginFunc]
+		c.lock.Unlock()
+		if loginArgs != nil && login != nil {
+			login(js.Undefined(), loginArgs)
+		}
+	}
+	c.notifyRole(RoleLeader)
+}
+
+// ensureInit runs the initSDK of the app once the tab leads, if it was called before. Returns whether the
+// SDK is initialized.
+func (c *Coordinator) ensureInit(name string) bool {
+	c.initLock.Lock()
+	defer c.initLock.Unlock()
+	c.lock.Lock()
+	initialized, initArgs, initSDK := c.initialized, c.initArgs, c.funcs["initSDK"]
+	if name == "initSDK" || (!initialized && initArgs != nil && initSDK != nil) {
+		c.initialized = true
+	}
+	c.lock.Unlock()
+	if name == "initSDK" || initialized {
+		return true
+	}
+	if initArgs == nil || initSDK == nil {
+		return false
+	}
+	initSDK(js.Undefined(), initArgs)
+	return true
+}
+
+func (c *Coordinator) rolePromise() js.Value {
+	var executor js.Func
+	executor = js.FuncOf(func(_ js.Value, args []js.Value) any {
+		resolve := args[0]
+		go func() {
+			<-c.decided
+			c.lock.Lock()
+			role := c.role
+			c.lock.Unlock()
+			resolve.Invoke(role)
+		}()
+		return nil
+	})
+	defer executor.Release()
+	return jsPromise.New(executor)
+}
+
+// call runs the function in the leader, and passes it to the leader from a follower. The functions of a
+// follower return a promise of the result of the leader.
+func (c *Coordinator) call(name string, fn func(js.Value, []js.Value) any, this js.Value, args []js.Value) any {
+	c.lock.Lock()
+	enabled, role := c.enabled, c.role
+	switch name {
+	case "initSDK":
+		c.initArgs = args
+	case "login", "guestLogin":
+		c.loginFunc, c.loginArgs = name, args
+	}
+	c.lock.Unlock()
+	if !enabled {
+		return fn(this, args)
+	}
+	if role == RoleLeader {
+		c.ensureInit(name)
+		return fn(this, args)
+	}
+	if name == "initSDK" {
+		// run when the tab takes over
+		return js.ValueOf([]any{true})
+	}
+	if _, ok := followerNoop[name]; ok && role == RoleFollower {
+		return jsPromise.Call("resolve", "")
+	}
+	var executor js.Func
+	executor = js.FuncOf(func(_ js.Value, promFn []js.Value) any {
+		resolve, reject := promFn[0], promFn[1]
+		go func() {
+			<-c.decided
+			if c.isLeader() {
+				c.ensureInit(name)
+				settle(fn(js.Undefined(), args), resolve, reject)
+				return
+			}
+			if _, ok := followerNoop[name]; ok {
+				resolve.Invoke("")
+				return
+			}
+			c.forward(name, args, resolve, reject)
+		}()
+		return nil
+	})
+	defer executor.Release()
+	return jsPromise.New(executor)
+}
+
+// forward passes the call to the leader, the promise is settled by its result.
+func (c *Coordinator) forward(name string, args []js.Value, resolve, reject js.Value) {
+	id := utils.OperationIDGenerator()
+	values := make([]any, len(args))
+	for i, arg := range args {
+		values[i] = arg
+	}
+	c.lock.Lock()
+	c.pending[id] = &call{resolve: resolve, reject: reject}
+	c.post(map[string]any{"kind": kindCall, "id": id, "from": c.tabID, "name": name, "args": values})
+	c.lock.Unlock()
+}
+
+// receive handles a message of another tab.
+func (c *Coordinator) receive(msg js.Value) {
+	if msg.Type() != js.TypeObject {
+		return
+	}
+	switch msg.Get("kind").String() {
+	case kindCall:
+		if c.isLeader() {
+			c.serve(msg)
+		}
+	case kindResult:
+		if msg.Get("to").String() != c.tabID {
+			return
+		}
+		c.lock.Lock()
+		p := c.pending[msg.Get("id").String()]
+		delete(c.pending, msg.Get("id").String())
+		c.lock.Unlock()
+		if p == nil {
+			return
+		}
+		if msg.Get("ok").Bool() {
+			p.resolve.Invoke(msg.Get("value"))
+		} else {
+			p.reject.Invoke(msg.Get("value"))
+		}
+	case kindEvent:
+		if c.isLeader() {
+			return
+		}
+		if fn := c.eventFunc(); fn != nil {
+			fn.Invoke(msg.Get("payload"))
+		}
+	case kindLeader:
+		if msg.Get("tab").String() == c.tabID {
+			return
+		}
+		// the calls sent to the former leader are lost
+		c.rejectPending("the leader tab changed")
+		c.notifyRole(RoleFollower)
+	}
+}
+
+// serve runs the call of a follower and answers it.
+func (c *Coordinator) serve(msg js.Value) {
+	id, from, name := msg.Get("id").String(), msg.Get("from").String(), msg.Get("name").String()
+	c.lock.Lock()
+	fn := c.funcs[name]
+	c.lock.Unlock()
+	answer := func(ok bool, value any) {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+		c.post(map[string]any{"kind": kindResult, "id": id, "to": from, "ok": ok, "value": value})
+	}
+	if fn == nil {
+		answer(false, errInfo(sdkerrs.ArgsError, "unknown function "+name))
+		return
+	}
+	jsArgs := msg.Get("args")
+	args := make([]js.Value, jsArgs.Length())
+	for i := range args {
+		args[i] = jsArgs.Index(i)
+	}
+	if (name == "login" || name == "guestLogin") && len(args) > 0 && open_im_sdk.IMUserContext != nil {
+		status := open_im_sdk.GetLoginStatus(args[0].String())
+		if status == open_im_sdk.Logging || status == open_im_sdk.Logged {
+			// the connection is shared, a follower can not switch it to another user, a guest login being a new user
+			if name == "guestLogin" || len(args) < 2 || open_im_sdk.GetLoginUserID() != args[1].String() {
+				answer(false, errInfo(sdkerrs.LoginRepeatError, "the leader tab is logged in as another user"))
+				return
+			}
+			if status == open_im_sdk.Logged {
+				// the tab of the same user shares the session of the leader
+				answer(true, "")
+				return
+			}
+		}
+	}
+	result := js.ValueOf(fn(js.Undefined(), args))
+	if !result.InstanceOf(jsPromise) {
+		answer(true, result)
+		return
+	}
+	var fulfilled, rejected js.Func
+	fulfilled = js.FuncOf(func(_ js.Value, v []js.Value) any {
+		defer fulfilled.Release()
+		defer rejected.Release()
+		answer(true, first(v))
+		return nil
+	})
+	rejected = js.FuncOf(func(_ js.Value, v []js.Value) any {
+		defer fulfilled.Release()
+		defer rejected.Release()
+		answer(false, first(v))
+		return nil
+	})
+	result.Call("then", fulfilled, rejected)
+}
+
+// relay forwards the events of the leader to the followers.
+func (c *Coordinator) relay(event string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.role == RoleLeader {
+		c.post(map[string]any{"kind": kindEvent, "payload": event})
+	}
+}
+
+// post is called with the lock.
+func (c *Coordinator) post(msg map[string]any) {
+	if c.channel.Truthy() {
+		c.channel.Call("postMessage", msg)
+	}
+}
+
+func (c *Coordinator) isLeader() bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.role == RoleLeader
+}
+
+func (c *Coordinator) rejectPending(reason string) {
+	c.lock.Lock()
+	pending := c.pending
+	c.pending = make(map[string]*call)
+	c.lock.Unlock()
+	for _, p := range pending {
+		p.reject.Invoke(errInfo(sdkerrs.NetworkError, reason))
+	}
+}
+
+// notifyRole tells the app of the tab its role, the event is not relayed to the other tabs.
+func (c *Coordinator) notifyRole(role string) {
+	fn := c.eventFunc()
+	if fn == nil {
+		return
+	}
+	fn.Invoke(utils.StructToJsonString(&event_listener.EventData{Event: roleChangedEvent, Data: role}))
+}
+
+// settle settles the promise of the call with the result of the function, itself a promise or not.
+func settle(result any, resolve, reject js.Value) {
+	v := js.ValueOf(result)
+	if v.InstanceOf(jsPromise) {
+		v.Call("then", resolve, reject)
+		return
+	}
+	resolve.Invoke(v)
+}
+
+func errInfo(errCode int, errMsg string) map[string]any {
+	return map[string]any{"errCode": errCode, "errMsg": errMsg, "operationID": ""}
+}
+
+func first(v []js.Value) any {
+	if len(v) == 0 {
+		return js.Undefined()
+	}
+	return v[0]
+}
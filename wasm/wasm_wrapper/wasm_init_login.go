@@ -63,15 +63,46 @@ func (s *SetListener) setUserListener() {
 	open_im_sdk.SetUserListener(callback)
 }
 
-func (s *SetListener) setSignalingListener() {
-	//callback := event_listener.NewSignalingCallback(s.commonFunc)
-	//open_im_sdk.SetSignalingListener(callback)
-}
 func (s *SetListener) setCustomBusinessListener() {
 	callback := event_listener.NewCustomBusinessCallback(s.commonFunc)
 	open_im_sdk.SetCustomBusinessListener(callback)
 }
 
+func (s *SetListener) setQRLoginListener() {
+	callback := event_listener.NewQRLoginCallback(s.commonFunc)
+	open_im_sdk.SetQRLoginListener(callback)
+}
+
+func (s *SetListener) setTokenListener() {
+	callback := event_listener.NewTokenCallback(s.commonFunc)
+	open_im_sdk.SetTokenListener(callback)
+}
+
+func (s *SetListener) setConnStateListener() {
+	callback := event_listener.NewConnStateCallback(s.commonFunc)
+	open_im_sdk.SetConnStateListener(callback)
+}
+
+func (s *SetListener) setNetworkQualityListener() {
+	callback := event_listener.NewNetworkQualityCallback(s.commonFunc)
+	open_im_sdk.SetNetworkQualityListener(callback)
+}
+
+func (s *SetListener) setAppLifecycleListener() {
+	callback := event_listener.NewAppLifecycleCallback(s.commonFunc)
+	open_im_sdk.SetAppLifecycleListener(callback)
+}
+
+func (s *SetListener) setSyncProgressListener() {
+	callback := event_listener.NewSyncProgressCallback(s.commonFunc)
+	open_im_sdk.SetSyncProgressListener(callback)
+}
+
+func (s *SetListener) setSyncConflictListener() {
+	callback := event_listener.NewSyncConflictCallback(s.commonFunc)
+	open_im_sdk.SetSyncConflictListener(callback)
+}
+
 func (s *SetListener) SetAllListener() {
 	s.setConversationListener()
 	s.setAdvancedMsgListener()
@@ -80,6 +111,13 @@ func (s *SetListener) SetAllListener() {
 	s.setUserListener()
 	s.setSignalingListener()
 	s.setCustomBusinessListener()
+	s.setQRLoginListener()
+	s.setTokenListener()
+	s.setConnStateListener()
+	s.setNetworkQualityListener()
+	s.setAppLifecycleListener()
+	s.setSyncProgressListener()
+	s.setSyncConflictListener()
 }
 
 type WrapperCommon struct {
@@ -98,6 +136,11 @@ func (w *WrapperCommon) CommonEventFunc(_ js.Value, args []js.Value) interface{}
 	}
 }
 
+// EventFunc returns the function of the app given the events, nil until it is set by commonEventFunc.
+func (w *WrapperCommon) EventFunc() *js.Value {
+	return w.commonFunc
+}
+
 type WrapperInitLogin struct {
 	*WrapperCommon
 }
@@ -127,7 +170,110 @@ func (w *WrapperInitLogin) NetworkStatusChanged(_ js.Value, args []js.Value) int
 func (w *WrapperInitLogin) GetLoginStatus(_ js.Value, args []js.Value) interface{} {
 	return event_listener.NewCaller(open_im_sdk.GetLoginStatus, nil, &args).AsyncCallWithOutCallback()
 }
+func (w *WrapperInitLogin) ForceReconnect(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.ForceReconnect, callback, &args).AsyncCallWithCallback()
+}
+func (w *WrapperInitLogin) GetNetworkQuality(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.GetNetworkQuality, callback, &args).AsyncCallWithCallback()
+}
+func (w *WrapperInitLogin) SetBandwidthLimit(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.SetBandwidthLimit, callback, &args).AsyncCallWithCallback()
+}
+func (w *WrapperInitLogin) SetNetworkClass(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.SetNetworkClass, callback, &args).AsyncCallWithCallback()
+}
+func (w *WrapperInitLogin) AllowMeteredTransfer(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.AllowMeteredTransfer, callback, &args).AsyncCallWithCallback()
+}
+func (w *WrapperInitLogin) TrimMemory(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.TrimMemory, callback, &args).AsyncCallWithCallback()
+}
+func (w *WrapperInitLogin) GetBandwidthLimit(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.GetBandwidthLimit, callback, &args).AsyncCallWithCallback()
+}
+func (w *WrapperInitLogin) SetLocalizedTemplates(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.SetLocalizedTemplates, callback, &args).AsyncCallWithCallback()
+}
+func (w *WrapperInitLogin) GetLocalizedText(_ js.Value, args []js.Value) interface{} {
+	return event_listener.NewCaller(open_im_sdk.GetLocalizedText, nil, &args).AsyncCallWithOutCallback()
+}
+func (w *WrapperInitLogin) GetTrafficStats(_ js.Value, args []js.Value) interface{} {
+	return event_listener.NewCaller(open_im_sdk.GetTrafficStats, nil, &args).AsyncCallWithOutCallback()
+}
+func (w *WrapperInitLogin) GetDBQueryStats(_ js.Value, args []js.Value) interface{} {
+	return event_listener.NewCaller(open_im_sdk.GetDBQueryStats, nil, &args).AsyncCallWithOutCallback()
+}
+func (w *WrapperInitLogin) GetServerTime(_ js.Value, args []js.Value) interface{} {
+	return event_listener.NewCaller(open_im_sdk.GetServerTime, nil, &args).AsyncCallWithOutCallback()
+}
 func (w *WrapperInitLogin) SetAppBackgroundStatus(_ js.Value, args []js.Value) interface{} {
 	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
 	return event_listener.NewCaller(open_im_sdk.SetAppBackgroundStatus, callback, &args).AsyncCallWithCallback()
 }
+func (w *WrapperInitLogin) EnterBackground(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.EnterBackground, callback, &args).AsyncCallWithCallback()
+}
+func (w *WrapperInitLogin) EnterForeground(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.EnterForeground, callback, &args).AsyncCallWithCallback()
+}
+
+func (w *WrapperInitLogin) SwitchAccount(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.SwitchAccount, callback, &args).AsyncCallWithCallback()
+}
+func (w *WrapperInitLogin) GetStorageUsage(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.GetStorageUsage, callback, &args).AsyncCallWithCallback()
+}
+func (w *WrapperInitLogin) ForceResyncAll(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.ForceResyncAll, callback, &args).AsyncCallWithCallback()
+}
+
+func (w *WrapperInitLogin) CreateLoginQRCode(_ js.Value, args []js.Value) interface{} {
+	NewSetListener(w.WrapperCommon).setQRLoginListener()
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.CreateLoginQRCode, callback, &args).AsyncCallWithCallback()
+}
+
+func (w *WrapperInitLogin) CancelLoginQRCode(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.CancelLoginQRCode, callback, &args).AsyncCallWithCallback()
+}
+
+func (w *WrapperInitLogin) ScanLoginQRCode(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.ScanLoginQRCode, callback, &args).AsyncCallWithCallback()
+}
+
+func (w *WrapperInitLogin) ApproveLoginQRCode(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.ApproveLoginQRCode, callback, &args).AsyncCallWithCallback()
+}
+
+func (w *WrapperInitLogin) RejectLoginQRCode(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.RejectLoginQRCode, callback, &args).AsyncCallWithCallback()
+}
+
+func (w *WrapperInitLogin) RefreshToken(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.RefreshToken, callback, &args).AsyncCallWithCallback()
+}
+
+func (w *WrapperInitLogin) GuestLogin(_ js.Value, args []js.Value) interface{} {
+	listener := NewSetListener(w.WrapperCommon)
+	listener.SetAllListener()
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.GuestLogin, callback, &args).AsyncCallWithCallback()
+}
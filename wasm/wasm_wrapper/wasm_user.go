@@ -68,3 +68,33 @@ func (w *WrapperUser) GetUserStatus(_ js.Value, args []js.Value) interface{} {
 	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
 	return event_listener.NewCaller(open_im_sdk.GetUserStatus, callback, &args).AsyncCallWithCallback()
 }
+
+func (w *WrapperUser) GetPrivacySettings(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.GetPrivacySettings, callback, &args).AsyncCallWithCallback()
+}
+
+func (w *WrapperUser) SetPrivacySettings(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.SetPrivacySettings, callback, &args).AsyncCallWithCallback()
+}
+
+func (w *WrapperUser) RegisterUserExProfileSchema(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.RegisterUserExProfileSchema, callback, &args).AsyncCallWithCallback()
+}
+
+func (w *WrapperUser) GetUsersExProfile(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.GetUsersExProfile, callback, &args).AsyncCallWithCallback()
+}
+
+func (w *WrapperUser) UpdateSelfExProfile(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.UpdateSelfExProfile, callback, &args).AsyncCallWithCallback()
+}
+
+func (w *WrapperUser) GetUserLastSeen(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.GetUserLastSeen, callback, &args).AsyncCallWithCallback()
+}